@@ -0,0 +1,267 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/tenant"
+)
+
+// EventType 审计事件类型
+type EventType string
+
+// 标准安全事件类型
+const (
+	EventLoginSuccess        EventType = "auth.login.success"
+	EventLoginFailure        EventType = "auth.login.failure"
+	EventLogout              EventType = "auth.logout"
+	EventTokenRefresh        EventType = "auth.token.refresh"
+	EventTokenRefreshFailure EventType = "auth.token.refresh_failure"
+	EventUnauthenticated     EventType = "auth.unauthenticated"
+	EventPermissionDenied    EventType = "auth.permission_denied"
+	// EventMutation 数据变更（Mutation 或 MutationMiddleware / MutationUnaryServerInterceptor 记录）
+	EventMutation EventType = "data.mutation"
+)
+
+// 事件结果
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Event 审计事件
+type Event struct {
+	Type    EventType `json:"type" bson:"type"`
+	Outcome string    `json:"outcome" bson:"outcome"`
+	Time    time.Time `json:"time" bson:"time"`
+	// 操作者（登录失败时可能只有用户名，未指定时取自 context，见 WithActor）
+	ActorID   string `json:"actorId,omitempty" bson:"actorId,omitempty"`
+	ActorName string `json:"actorName,omitempty" bson:"actorName,omitempty"`
+	// 租户 ID（未指定时取自 tenant.FromContext）
+	TenantID string `json:"tenantId,omitempty" bson:"tenantId,omitempty"`
+	// 请求来源
+	ClientIP  string `json:"clientIp,omitempty" bson:"clientIp,omitempty"`
+	UserAgent string `json:"userAgent,omitempty" bson:"userAgent,omitempty"`
+	// HTTP 路由或 gRPC 方法
+	Method  string `json:"method,omitempty" bson:"method,omitempty"`
+	TraceID string `json:"traceId,omitempty" bson:"traceId,omitempty"`
+	// 变更操作，如 user.update、PUT /api/users/:id
+	Action string `json:"action,omitempty" bson:"action,omitempty"`
+	// 访问或变更的资源，如权限拒绝时的请求路径、user:42
+	Resource string `json:"resource,omitempty" bson:"resource,omitempty"`
+	// 字段变更（敏感字段已脱敏）
+	Changes []Change `json:"changes,omitempty" bson:"changes,omitempty"`
+	// 失败原因
+	Reason string `json:"reason,omitempty" bson:"reason,omitempty"`
+	// 附加信息
+	Metadata map[string]string `json:"metadata,omitempty" bson:"metadata,omitempty"`
+}
+
+// Sink 审计事件输出目标
+type Sink interface {
+	Write(ctx context.Context, event *Event) error
+}
+
+// SinkFunc 函数形式的 Sink
+type SinkFunc func(ctx context.Context, event *Event) error
+
+// Write 实现 Sink
+func (f SinkFunc) Write(ctx context.Context, event *Event) error {
+	return f(ctx, event)
+}
+
+// LogSink 将审计事件输出到框架日志（带 action/user/client_ip 等结构化字段）
+type LogSink struct{}
+
+// Write 实现 Sink
+func (LogSink) Write(ctx context.Context, event *Event) error {
+	l := logger.GetDefault()
+	if l == nil {
+		return errors.New("logger is not initialized")
+	}
+	fields := logger.NewFields().WithUser(event.ActorID, event.ActorName)
+	fields[logger.FieldAction] = string(event.Type)
+	fields["audit"] = true
+	fields["outcome"] = event.Outcome
+	if event.ClientIP != "" {
+		fields[logger.FieldClientIP] = event.ClientIP
+	}
+	if event.UserAgent != "" {
+		fields[logger.FieldUserAgent] = event.UserAgent
+	}
+	if event.Method != "" {
+		fields[logger.FieldMethod] = event.Method
+	}
+	if event.Resource != "" {
+		fields[logger.FieldResource] = event.Resource
+	}
+	if event.TenantID != "" {
+		fields["tenant_id"] = event.TenantID
+	}
+	if event.Action != "" {
+		fields["audit_action"] = event.Action
+	}
+	if len(event.Changes) > 0 {
+		fields["changes"] = event.Changes
+	}
+	for key, value := range event.Metadata {
+		fields[key] = value
+	}
+
+	if event.Outcome == OutcomeFailure {
+		l.WithFields(fields).Warn(ctx, "Audit event: type=%s, outcome=%s, reason=%s", event.Type, event.Outcome, event.Reason)
+	} else {
+		l.WithFields(fields).Info(ctx, "Audit event: type=%s, outcome=%s", event.Type, event.Outcome)
+	}
+	return nil
+}
+
+// Auditor 审计记录器，将事件分发到所有 Sink
+type Auditor struct {
+	mu    sync.RWMutex
+	sinks []Sink
+	// 从 context 解析操作者（如读取鉴权中间件写入的 JWT claims）
+	actorResolver func(ctx context.Context) (Actor, bool)
+}
+
+// NewAuditor 创建审计记录器，未指定 Sink 时输出到日志
+func NewAuditor(sinks ...Sink) *Auditor {
+	if len(sinks) == 0 {
+		sinks = []Sink{LogSink{}}
+	}
+	return &Auditor{sinks: sinks}
+}
+
+// AddSink 添加输出目标
+func (a *Auditor) AddSink(sink Sink) {
+	if sink == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sinks = append(a.sinks, sink)
+}
+
+// SetActorResolver 设置操作者解析函数，事件未指定操作者且 context 中没有 WithActor 写入的操作者时调用
+func (a *Auditor) SetActorResolver(resolver func(ctx context.Context) (Actor, bool)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.actorResolver = resolver
+}
+
+// Record 记录审计事件，自动补全时间、trace ID、操作者、租户与请求来源（来自 context）
+// 某个 Sink 失败不影响其他 Sink，错误会被记录日志并合并返回
+func (a *Auditor) Record(ctx context.Context, event Event) error {
+	if a == nil {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.Outcome == "" {
+		event.Outcome = OutcomeSuccess
+	}
+	if event.TraceID == "" {
+		event.TraceID = logger.GetTraceID(ctx)
+	}
+	if info, ok := RequestInfoFromContext(ctx); ok {
+		if event.ClientIP == "" {
+			event.ClientIP = info.ClientIP
+		}
+		if event.UserAgent == "" {
+			event.UserAgent = info.UserAgent
+		}
+		if event.Method == "" {
+			event.Method = info.Method
+		}
+	}
+
+	a.mu.RLock()
+	sinks := append([]Sink(nil), a.sinks...)
+	resolver := a.actorResolver
+	a.mu.RUnlock()
+
+	if event.ActorID == "" && event.ActorName == "" {
+		actor, ok := ActorFromContext(ctx)
+		if !ok && resolver != nil {
+			actor, ok = resolver(ctx)
+		}
+		if ok {
+			event.ActorID, event.ActorName = actor.ID, actor.Name
+		}
+	}
+	if event.TenantID == "" {
+		event.TenantID, _ = tenant.FromContext(ctx)
+	}
+	if event.Type == EventMutation {
+		markMutationRecorded(ctx)
+	}
+
+	var errs []error
+	for _, sink := range sinks {
+		if err := sink.Write(ctx, &event); err != nil {
+			logger.Error(ctx, "Failed to write audit event: type=%s, sink=%T, error=%v", event.Type, sink, err)
+			errs = append(errs, fmt.Errorf("%T: %w", sink, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+var (
+	defaultMu      sync.RWMutex
+	defaultAuditor = NewAuditor()
+)
+
+// SetDefault 设置全局审计记录器（nil 时恢复为仅输出日志）
+func SetDefault(a *Auditor) {
+	if a == nil {
+		a = NewAuditor()
+	}
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultAuditor = a
+}
+
+// Default 获取全局审计记录器
+func Default() *Auditor {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultAuditor
+}
+
+// Record 使用全局审计记录器记录事件
+func Record(ctx context.Context, event Event) error {
+	return Default().Record(ctx, event)
+}
+
+// LoginSucceeded 记录登录成功
+func LoginSucceeded(ctx context.Context, userID, username string) {
+	_ = Record(ctx, Event{Type: EventLoginSuccess, ActorID: userID, ActorName: username})
+}
+
+// LoginFailed 记录登录失败（reason 不应包含密码等敏感信息）
+func LoginFailed(ctx context.Context, username, reason string) {
+	_ = Record(ctx, Event{Type: EventLoginFailure, Outcome: OutcomeFailure, ActorName: username, Reason: reason})
+}
+
+// TokenRefreshed 记录令牌刷新
+func TokenRefreshed(ctx context.Context, userID string) {
+	_ = Record(ctx, Event{Type: EventTokenRefresh, ActorID: userID})
+}
+
+// TokenRefreshFailed 记录令牌刷新失败
+func TokenRefreshFailed(ctx context.Context, userID, reason string) {
+	_ = Record(ctx, Event{Type: EventTokenRefreshFailure, Outcome: OutcomeFailure, ActorID: userID, Reason: reason})
+}
+
+// PermissionDenied 记录权限拒绝
+func PermissionDenied(ctx context.Context, userID, resource, reason string) {
+	_ = Record(ctx, Event{Type: EventPermissionDenied, Outcome: OutcomeFailure, ActorID: userID, Resource: resource, Reason: reason})
+}
@@ -0,0 +1,126 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+type recordingSink struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (s *recordingSink) Write(ctx context.Context, event *Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, *event)
+	return nil
+}
+
+func (s *recordingSink) snapshot() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Event(nil), s.events...)
+}
+
+func TestRecordFillsContextFields(t *testing.T) {
+	sink := &recordingSink{}
+	failing := SinkFunc(func(context.Context, *Event) error { return errors.New("down") })
+	auditor := NewAuditor(failing, sink)
+
+	ctx := logger.WithTraceID(context.Background(), "trace-1")
+	ctx = WithRequestInfo(ctx, RequestInfo{ClientIP: "10.0.0.1", UserAgent: "curl/8", Method: "/auth.AuthService/Login"})
+	if err := auditor.Record(ctx, Event{Type: EventLoginFailure, Outcome: OutcomeFailure, ActorName: "alice"}); err == nil {
+		t.Fatalf("expected failing sink error to be returned")
+	}
+
+	events := sink.snapshot()
+	if len(events) != 1 {
+		t.Fatalf("expected other sinks to still receive the event, got %d", len(events))
+	}
+	event := events[0]
+	if event.TraceID != "trace-1" || event.ClientIP != "10.0.0.1" || event.UserAgent != "curl/8" || event.Method != "/auth.AuthService/Login" {
+		t.Fatalf("context fields not filled: %+v", event)
+	}
+	if event.Time.IsZero() {
+		t.Fatalf("expected event time to be set")
+	}
+}
+
+func TestUnaryServerInterceptorRecordsDenied(t *testing.T) {
+	sink := &recordingSink{}
+	interceptor := UnaryServerInterceptor(NewAuditor(sink))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		MetadataClientIP, "203.0.113.9",
+		"x-forwarded-for", "198.51.100.1",
+		MetadataUserAgent, "Mozilla/5.0",
+	))
+	info := &grpc.UnaryServerInfo{FullMethod: "/admin.Service/Delete"}
+
+	var seen RequestInfo
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		seen, _ = RequestInfoFromContext(ctx)
+		return nil, status.Error(codes.PermissionDenied, "admin role required")
+	})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected handler error to be returned, got %v", err)
+	}
+	if seen.ClientIP != "203.0.113.9" || seen.UserAgent != "Mozilla/5.0" {
+		t.Fatalf("expected forwarded client info in handler context, got %+v", seen)
+	}
+
+	events := sink.snapshot()
+	if len(events) != 1 || events[0].Type != EventPermissionDenied || events[0].Reason != "admin role required" {
+		t.Fatalf("expected permission denied event, got %+v", events)
+	}
+
+	_, _ = interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "missing")
+	})
+	if got := len(sink.snapshot()); got != 1 {
+		t.Fatalf("expected non-auth errors not to be audited, got %d events", got)
+	}
+}
+
+func TestFiberMiddlewareForwardsClientInfoAndRecordsForbidden(t *testing.T) {
+	sink := &recordingSink{}
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(FiberMiddleware(NewAuditor(sink)))
+
+	var forwardedIP, forwardedUA interface{}
+	app.Get("/admin", func(c *fiber.Ctx) error {
+		forwardedIP = c.Context().UserValue(MetadataClientIP)
+		forwardedUA = c.Context().UserValue(MetadataUserAgent)
+		return fiber.ErrForbidden
+	})
+	app.Get("/ok", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	req := httptest.NewRequest("GET", "/admin", nil)
+	req.Header.Set("User-Agent", "test-agent")
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if _, err := app.Test(httptest.NewRequest("GET", "/ok", nil)); err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+
+	if forwardedIP == nil || forwardedUA != "test-agent" {
+		t.Fatalf("expected client info in user values, got ip=%v ua=%v", forwardedIP, forwardedUA)
+	}
+	events := sink.snapshot()
+	if len(events) != 1 || events[0].Type != EventPermissionDenied || events[0].Resource != "/admin" || events[0].UserAgent != "test-agent" {
+		t.Fatalf("expected one permission denied event, got %+v", events)
+	}
+}
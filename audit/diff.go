@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// changeMask 敏感字段变更的占位值
+const changeMask = "***"
+
+// secretKeys 计算变更时需要脱敏的字段（小写包含匹配）
+var secretKeys = []string{"password", "passwd", "secret", "token", "apikey", "api_key", "credential", "privatekey", "private_key"}
+
+// Change 字段变更，嵌套字段以 . 连接（如 profile.email），数组整体比较
+type Change struct {
+	Field  string      `json:"field" bson:"field"`
+	Before interface{} `json:"before,omitempty" bson:"before,omitempty"`
+	After  interface{} `json:"after,omitempty" bson:"after,omitempty"`
+}
+
+// Diff 计算变更前后对象的字段差异（按 JSON 编码后的字段比较，结果按字段名排序）
+// 创建时 before 为 nil，删除时 after 为 nil；密码、令牌等字段只记录发生了变更，值替换为 ***
+func Diff(before, after interface{}) ([]Change, error) {
+	b, err := normalize(before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode before value: %w", err)
+	}
+	a, err := normalize(after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode after value: %w", err)
+	}
+	var changes []Change
+	diffValue("", b, a, &changes)
+	return changes, nil
+}
+
+// normalize 将对象编码为 JSON 结构，数字保留为 json.Number 以免大整数丢失精度
+func normalize(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var result interface{}
+	if err := decoder.Decode(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func diffValue(field string, before, after interface{}, changes *[]Change) {
+	beforeMap, beforeIsMap := before.(map[string]interface{})
+	afterMap, afterIsMap := after.(map[string]interface{})
+	if (beforeIsMap || before == nil) && (afterIsMap || after == nil) && (beforeIsMap || afterIsMap) {
+		keys := make([]string, 0, len(beforeMap)+len(afterMap))
+		for key := range beforeMap {
+			keys = append(keys, key)
+		}
+		for key := range afterMap {
+			if _, ok := beforeMap[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			path := key
+			if field != "" {
+				path = field + "." + key
+			}
+			diffValue(path, beforeMap[key], afterMap[key], changes)
+		}
+		return
+	}
+	if reflect.DeepEqual(before, after) {
+		return
+	}
+	if isSecretField(field) {
+		before, after = maskChange(before), maskChange(after)
+	}
+	*changes = append(*changes, Change{Field: field, Before: before, After: after})
+}
+
+func isSecretField(field string) bool {
+	if i := strings.LastIndex(field, "."); i >= 0 {
+		field = field[i+1:]
+	}
+	field = strings.ToLower(field)
+	for _, secret := range secretKeys {
+		if strings.Contains(field, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+func maskChange(value interface{}) interface{} {
+	if s, ok := value.(string); value == nil || ok && s == "" {
+		return value
+	}
+	return changeMask
+}
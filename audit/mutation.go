@@ -0,0 +1,192 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/team-dandelion/quickgo/http"
+)
+
+// mutationPrefixes 默认视为变更操作的 gRPC 方法名前缀
+var mutationPrefixes = []string{"Create", "Update", "Delete", "Remove", "Set", "Add", "Patch", "Put", "Insert", "Upsert",
+	"Modify", "Save", "Assign", "Revoke", "Grant", "Enable", "Disable", "Reset", "Change"}
+
+// Actor 操作者
+type Actor struct {
+	ID   string
+	Name string
+}
+
+type actorKey struct{}
+
+// WithActor 将操作者存入 context，通常由鉴权中间件/拦截器在验证令牌后调用
+// 外层的 MutationMiddleware / MutationUnaryServerInterceptor 也能读取到在内层写入的操作者
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	if tracker := mutationTrackerFromContext(ctx); tracker != nil {
+		tracker.mu.Lock()
+		tracker.actor = &actor
+		tracker.mu.Unlock()
+	}
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext 获取操作者
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	if ctx == nil {
+		return Actor{}, false
+	}
+	if actor, ok := ctx.Value(actorKey{}).(Actor); ok {
+		return actor, true
+	}
+	if tracker := mutationTrackerFromContext(ctx); tracker != nil {
+		tracker.mu.Lock()
+		defer tracker.mu.Unlock()
+		if tracker.actor != nil {
+			return *tracker.actor, true
+		}
+	}
+	return Actor{}, false
+}
+
+// mutationTracker 单次请求的变更审计状态：处理函数已通过 Mutation 记录时，自动审计不再重复记录
+type mutationTracker struct {
+	mu       sync.Mutex
+	recorded bool
+	actor    *Actor
+}
+
+type mutationTrackerKey struct{}
+
+func withMutationTracker(ctx context.Context) (context.Context, *mutationTracker) {
+	tracker := &mutationTracker{}
+	return context.WithValue(ctx, mutationTrackerKey{}, tracker), tracker
+}
+
+func mutationTrackerFromContext(ctx context.Context) *mutationTracker {
+	tracker, _ := ctx.Value(mutationTrackerKey{}).(*mutationTracker)
+	return tracker
+}
+
+func markMutationRecorded(ctx context.Context) {
+	if tracker := mutationTrackerFromContext(ctx); tracker != nil {
+		tracker.mu.Lock()
+		tracker.recorded = true
+		tracker.mu.Unlock()
+	}
+}
+
+func (t *mutationTracker) isRecorded() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.recorded
+}
+
+// Mutation 记录数据变更：action 如 user.update，resource 如 user:42，
+// before / after 为变更前后的对象（创建时 before 为 nil，删除时 after 为 nil），字段差异由 Diff 计算
+func (a *Auditor) Mutation(ctx context.Context, action, resource string, before, after interface{}) error {
+	changes, err := Diff(before, after)
+	if err != nil {
+		return err
+	}
+	return a.Record(ctx, Event{Type: EventMutation, Action: action, Resource: resource, Changes: changes})
+}
+
+// Mutation 使用全局审计记录器记录数据变更
+func Mutation(ctx context.Context, action, resource string, before, after interface{}) error {
+	return Default().Mutation(ctx, action, resource, before, after)
+}
+
+// MutationMiddleware HTTP 变更审计中间件，对 POST / PUT / PATCH / DELETE 请求在处理完成后记录 EventMutation
+// （Action 为 "方法 路由"，Resource 为请求路径，4xx / 5xx 记为失败）；处理函数已调用 Mutation 记录更详细的变更时不再重复记录。
+// 操作者取自鉴权中间件调用的 WithActor 或 Auditor.SetActorResolver，auditor 为 nil 时使用全局审计记录器
+func MutationMiddleware(auditor *Auditor) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		default:
+			return c.Next()
+		}
+		path := utils.CopyString(c.Path())
+		ctx, tracker := withMutationTracker(http.RequestContext(c))
+		http.SetRequestContext(c, ctx)
+
+		err := c.Next()
+		if tracker.isRecorded() {
+			return err
+		}
+
+		statusCode := c.Response().StatusCode()
+		if err != nil {
+			statusCode = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				statusCode = fiberErr.Code
+			}
+		}
+		a := auditor
+		if a == nil {
+			a = Default()
+		}
+		event := Event{
+			Type:     EventMutation,
+			Action:   c.Method() + " " + c.Route().Path,
+			Resource: path,
+			TraceID:  http.GetTraceID(c),
+			Metadata: map[string]string{"status": strconv.Itoa(statusCode)},
+		}
+		if statusCode >= fiber.StatusBadRequest {
+			event.Outcome, event.Reason = OutcomeFailure, "http "+strconv.Itoa(statusCode)
+		}
+		_ = a.Record(c.UserContext(), event)
+		return err
+	}
+}
+
+// IsMutationMethod 按方法名前缀（Create、Update、Delete、Set 等）判断 gRPC 方法是否为变更操作
+func IsMutationMethod(fullMethod string) bool {
+	name := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	for _, prefix := range mutationPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MutationUnaryServerInterceptor gRPC 变更审计拦截器，isMutation 判断方法是否需要审计（nil 时使用 IsMutationMethod）
+// 调用完成后记录 EventMutation（Action 为方法全名，返回错误时记为失败）；处理函数已调用 Mutation 时不再重复记录
+func MutationUnaryServerInterceptor(auditor *Auditor, isMutation func(fullMethod string) bool) grpc.UnaryServerInterceptor {
+	if isMutation == nil {
+		isMutation = IsMutationMethod
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !isMutation(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, tracker := withMutationTracker(ctx)
+		resp, err := handler(ctx, req)
+		if tracker.isRecorded() {
+			return resp, err
+		}
+
+		a := auditor
+		if a == nil {
+			a = Default()
+		}
+		st := status.Convert(err)
+		event := Event{Type: EventMutation, Action: info.FullMethod, Metadata: map[string]string{"code": st.Code().String()}}
+		if err != nil {
+			event.Outcome, event.Reason = OutcomeFailure, st.Message()
+		}
+		_ = a.Record(ctx, event)
+		return resp, err
+	}
+}
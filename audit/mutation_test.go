@@ -0,0 +1,139 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/team-dandelion/quickgo/tenant"
+)
+
+func TestDiff(t *testing.T) {
+	type profile struct {
+		Email string `json:"email"`
+	}
+	type user struct {
+		ID       int64   `json:"id"`
+		Name     string  `json:"name"`
+		Password string  `json:"password"`
+		Profile  profile `json:"profile"`
+		Tags     []string
+	}
+	before := user{ID: 9007199254740993, Name: "alice", Password: "old", Profile: profile{Email: "a@x.io"}, Tags: []string{"a"}}
+	after := before
+	after.Name, after.Password, after.Profile.Email, after.Tags = "alicia", "new", "a@y.io", []string{"a", "b"}
+
+	changes, err := Diff(before, after)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	want := []string{
+		"Tags: [a] -> [a b]",
+		"name: alice -> alicia",
+		"password: *** -> ***",
+		"profile.email: a@x.io -> a@y.io",
+	}
+	if got := formatChanges(changes); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected changes: %q", got)
+	}
+
+	created, err := Diff(nil, user{ID: 9007199254740993, Name: "bob"})
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	want = []string{
+		"id: <nil> -> 9007199254740993",
+		"name: <nil> -> bob",
+		"password: <nil> -> ",
+		"profile.email: <nil> -> ",
+	}
+	if got := formatChanges(created); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected create changes: %q", got)
+	}
+}
+
+func formatChanges(changes []Change) []string {
+	result := make([]string, 0, len(changes))
+	for _, change := range changes {
+		result = append(result, fmt.Sprintf("%s: %v -> %v", change.Field, change.Before, change.After))
+	}
+	return result
+}
+
+func TestMutationMiddlewareRecordsMutations(t *testing.T) {
+	sink := &recordingSink{}
+	auditor := NewAuditor(sink)
+	app := fiber.New()
+	app.Use(MutationMiddleware(auditor))
+	// 模拟鉴权中间件（位于审计中间件之后）写入操作者
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(WithActor(tenant.WithTenant(c.UserContext(), "acme"), Actor{ID: "42", Name: "alice"}))
+		return c.Next()
+	})
+	app.Get("/users/:id", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	app.Put("/users/:id", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	app.Delete("/users/:id", func(c *fiber.Ctx) error { return fiber.ErrForbidden })
+	app.Post("/users", func(c *fiber.Ctx) error {
+		if err := auditor.Mutation(c.UserContext(), "user.create", "user:7", nil, map[string]string{"name": "bob"}); err != nil {
+			return err
+		}
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	for _, req := range []struct{ method, path string }{{"GET", "/users/1"}, {"PUT", "/users/1"}, {"DELETE", "/users/2"}, {"POST", "/users"}} {
+		if _, err := app.Test(httptest.NewRequest(req.method, req.path, nil)); err != nil {
+			t.Fatalf("%s %s failed: %v", req.method, req.path, err)
+		}
+	}
+
+	events := sink.snapshot()
+	if len(events) != 3 {
+		t.Fatalf("expected 3 mutation events, got %+v", events)
+	}
+	put, del, create := events[0], events[1], events[2]
+	if put.Type != EventMutation || put.Action != "PUT /users/:id" || put.Resource != "/users/1" || put.Outcome != OutcomeSuccess {
+		t.Fatalf("unexpected put event: %+v", put)
+	}
+	if put.ActorID != "42" || put.ActorName != "alice" || put.TenantID != "acme" {
+		t.Fatalf("expected actor and tenant from context: %+v", put)
+	}
+	if del.Outcome != OutcomeFailure || del.Metadata["status"] != "403" {
+		t.Fatalf("unexpected delete event: %+v", del)
+	}
+	if create.Action != "user.create" || create.Resource != "user:7" || len(create.Changes) != 1 || create.ActorID != "42" {
+		t.Fatalf("expected handler-recorded mutation without duplicate: %+v", create)
+	}
+}
+
+func TestMutationUnaryServerInterceptor(t *testing.T) {
+	sink := &recordingSink{}
+	auditor := NewAuditor(sink)
+	auditor.SetActorResolver(func(ctx context.Context) (Actor, bool) { return Actor{ID: "svc"}, true })
+	interceptor := MutationUnaryServerInterceptor(auditor, nil)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+
+	for _, method := range []string{"/user.UserService/GetUser", "/user.UserService/UpdateUser"} {
+		_, _ = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	}
+	events := sink.snapshot()
+	if len(events) != 1 {
+		t.Fatalf("expected only the mutating method to be audited, got %+v", events)
+	}
+	event := events[0]
+	if event.Action != "/user.UserService/UpdateUser" || event.Outcome != OutcomeFailure || event.Reason != "user not found" ||
+		event.Metadata["code"] != "NotFound" || event.ActorID != "svc" {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+	if IsMutationMethod("/user.UserService/ListUsers") || !IsMutationMethod("DeleteUser") {
+		t.Fatal("unexpected IsMutationMethod result")
+	}
+}
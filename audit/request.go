@@ -0,0 +1,175 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/team-dandelion/quickgo/http"
+)
+
+// 网关转发客户端信息使用的 gRPC metadata 键（由 FiberMiddleware 写入 UserValues，经 grpcep.RPCCtx 透传）
+const (
+	MetadataClientIP  = "x-client-ip"
+	MetadataUserAgent = "x-client-user-agent"
+)
+
+// RequestInfo 请求来源信息
+type RequestInfo struct {
+	ClientIP  string
+	UserAgent string
+	Method    string
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo 将请求来源信息存入 context
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext 获取请求来源信息
+func RequestInfoFromContext(ctx context.Context) (RequestInfo, bool) {
+	if ctx == nil {
+		return RequestInfo{}, false
+	}
+	info, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info, ok
+}
+
+// UnaryServerInterceptor gRPC 一元审计拦截器
+// 提取客户端 IP / User-Agent 存入 context，并在返回 Unauthenticated / PermissionDenied 时记录安全事件
+// auditor 为 nil 时使用全局审计记录器
+func UnaryServerInterceptor(auditor *Auditor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = WithRequestInfo(ctx, grpcRequestInfo(ctx, info.FullMethod))
+		resp, err := handler(ctx, req)
+		recordDenied(ctx, auditor, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor gRPC 流式审计拦截器
+func StreamServerInterceptor(auditor *Auditor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := WithRequestInfo(ss.Context(), grpcRequestInfo(ss.Context(), info.FullMethod))
+		err := handler(srv, &auditServerStream{ServerStream: ss, ctx: ctx})
+		recordDenied(ctx, auditor, err)
+		return err
+	}
+}
+
+type auditServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *auditServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func recordDenied(ctx context.Context, auditor *Auditor, err error) {
+	if err == nil {
+		return
+	}
+	if auditor == nil {
+		auditor = Default()
+	}
+	st := status.Convert(err)
+	switch st.Code() {
+	case codes.Unauthenticated:
+		_ = auditor.Record(ctx, Event{Type: EventUnauthenticated, Outcome: OutcomeFailure, Reason: st.Message()})
+	case codes.PermissionDenied:
+		_ = auditor.Record(ctx, Event{Type: EventPermissionDenied, Outcome: OutcomeFailure, Reason: st.Message()})
+	}
+}
+
+// grpcRequestInfo 从 metadata 与 peer 提取请求来源，优先使用网关透传的客户端信息
+func grpcRequestInfo(ctx context.Context, method string) RequestInfo {
+	info := RequestInfo{Method: method}
+	md, _ := metadata.FromIncomingContext(ctx)
+	info.ClientIP = firstMetadata(md, MetadataClientIP)
+	if info.ClientIP == "" {
+		if forwarded := firstMetadata(md, "x-forwarded-for"); forwarded != "" {
+			info.ClientIP = strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+	}
+	if info.ClientIP == "" {
+		info.ClientIP = firstMetadata(md, "x-real-ip")
+	}
+	if info.ClientIP == "" {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			info.ClientIP = p.Addr.String()
+			if host, _, err := net.SplitHostPort(info.ClientIP); err == nil {
+				info.ClientIP = host
+			}
+		}
+	}
+	info.UserAgent = firstMetadata(md, MetadataUserAgent)
+	if info.UserAgent == "" {
+		info.UserAgent = firstMetadata(md, "user-agent")
+	}
+	return info
+}
+
+func firstMetadata(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// FiberMiddleware HTTP 审计中间件
+// 将客户端 IP / User-Agent 存入 UserContext，并写入 UserValues 以便经 grpcep.RPCCtx 透传给后端 gRPC 服务；
+// 响应 401 / 403 时记录安全事件。auditor 为 nil 时使用全局审计记录器
+// 客户端 IP 取自 c.IP()，部署在代理之后时需配置 fiber.Config.ProxyHeader 与可信代理
+func FiberMiddleware(auditor *Auditor) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// fiber 返回的字符串引用请求缓冲区，事件可能在请求结束后才被 Sink 处理，需拷贝
+		path := utils.CopyString(c.Path())
+		info := RequestInfo{
+			ClientIP:  utils.CopyString(c.IP()),
+			UserAgent: utils.CopyString(c.Get(fiber.HeaderUserAgent)),
+			Method:    c.Method() + " " + path,
+		}
+		c.Context().SetUserValue(MetadataClientIP, info.ClientIP)
+		if info.UserAgent != "" {
+			c.Context().SetUserValue(MetadataUserAgent, info.UserAgent)
+		}
+		ctx := WithRequestInfo(http.RequestContext(c), info)
+		http.SetRequestContext(c, ctx)
+
+		err := c.Next()
+
+		statusCode := c.Response().StatusCode()
+		var fiberErr *fiber.Error
+		if err != nil && errors.As(err, &fiberErr) {
+			statusCode = fiberErr.Code
+		}
+		if statusCode != fiber.StatusUnauthorized && statusCode != fiber.StatusForbidden {
+			return err
+		}
+
+		a := auditor
+		if a == nil {
+			a = Default()
+		}
+		event := Event{Outcome: OutcomeFailure, TraceID: http.GetTraceID(c), Resource: path}
+		if statusCode == fiber.StatusUnauthorized {
+			event.Type, event.Reason = EventUnauthenticated, "http 401"
+		} else {
+			event.Type, event.Reason = EventPermissionDenied, "http 403"
+		}
+		_ = a.Record(ctx, event)
+		return err
+	}
+}
@@ -0,0 +1,172 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/team-dandelion/quickgo/db/gorm"
+	"github.com/team-dandelion/quickgo/db/mongodb"
+	"github.com/team-dandelion/quickgo/mq"
+)
+
+const (
+	// DefaultTable GormSink 默认表名
+	DefaultTable = "audit_logs"
+	// DefaultCollection MongoSink 默认集合名
+	DefaultCollection = "audit_logs"
+	// EventTypeHeader MQSink 消息头中的事件类型
+	EventTypeHeader = "x-audit-type"
+)
+
+// Entry GormSink 的审计表结构，Changes 与 Metadata 以 JSON 文本存储
+type Entry struct {
+	ID        uint64    `gorm:"primaryKey;autoIncrement"`
+	Time      time.Time `gorm:"index"`
+	Type      string    `gorm:"size:64;index"`
+	Outcome   string    `gorm:"size:16"`
+	ActorID   string    `gorm:"size:128;index"`
+	ActorName string    `gorm:"size:128"`
+	TenantID  string    `gorm:"size:128;index"`
+	ClientIP  string    `gorm:"size:64"`
+	UserAgent string    `gorm:"size:512"`
+	Method    string    `gorm:"size:255"`
+	TraceID   string    `gorm:"size:64;index"`
+	Action    string    `gorm:"size:255"`
+	Resource  string    `gorm:"size:255;index"`
+	Reason    string    `gorm:"type:text"`
+	Changes   string    `gorm:"type:text"`
+	Metadata  string    `gorm:"type:text"`
+}
+
+// newEntry 将事件转换为审计表记录行
+func newEntry(event *Event) (*Entry, error) {
+	entry := &Entry{
+		Time: event.Time, Type: string(event.Type), Outcome: event.Outcome,
+		ActorID: event.ActorID, ActorName: event.ActorName, TenantID: event.TenantID,
+		ClientIP: event.ClientIP, UserAgent: event.UserAgent, Method: event.Method, TraceID: event.TraceID,
+		Action: event.Action, Resource: event.Resource, Reason: event.Reason,
+	}
+	if len(event.Changes) > 0 {
+		data, err := json.Marshal(event.Changes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode audit changes: %w", err)
+		}
+		entry.Changes = string(data)
+	}
+	if len(event.Metadata) > 0 {
+		data, err := json.Marshal(event.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode audit metadata: %w", err)
+		}
+		entry.Metadata = string(data)
+	}
+	return entry, nil
+}
+
+// GormSink 将审计事件写入关系型数据库表
+// 写入不加入 context 中的事务（业务事务回滚时审计记录仍保留），也不受行级租户隔离影响
+type GormSink struct {
+	manager  *gorm.Manager
+	database string
+	table    string
+}
+
+// NewGormSink 创建数据库 Sink，database 为 GormManager 中的数据库名称，table 为空时使用 DefaultTable
+func NewGormSink(manager *gorm.Manager, database, table string) (*GormSink, error) {
+	if manager == nil {
+		return nil, errors.New("gorm manager is nil")
+	}
+	if table == "" {
+		table = DefaultTable
+	}
+	return &GormSink{manager: manager, database: database, table: table}, nil
+}
+
+// Migrate 创建或更新审计表
+func (s *GormSink) Migrate(ctx context.Context) error {
+	db, err := s.manager.GetDB(s.database)
+	if err != nil {
+		return err
+	}
+	return db.WithContext(ctx).Table(s.table).AutoMigrate(&Entry{})
+}
+
+// Write 实现 Sink
+func (s *GormSink) Write(ctx context.Context, event *Event) error {
+	entry, err := newEntry(event)
+	if err != nil {
+		return err
+	}
+	db, err := s.manager.GetDB(s.database)
+	if err != nil {
+		return err
+	}
+	return db.WithContext(gorm.WithoutTenantScope(ctx)).Table(s.table).Create(entry).Error
+}
+
+// MongoSink 将审计事件写入 MongoDB 集合
+type MongoSink struct {
+	manager    *mongodb.Manager
+	database   string
+	collection string
+}
+
+// NewMongoSink 创建 MongoDB Sink，database 为 MongoManager 中的客户端名称，collection 为空时使用 DefaultCollection
+func NewMongoSink(manager *mongodb.Manager, database, collection string) (*MongoSink, error) {
+	if manager == nil {
+		return nil, errors.New("mongodb manager is nil")
+	}
+	if collection == "" {
+		collection = DefaultCollection
+	}
+	return &MongoSink{manager: manager, database: database, collection: collection}, nil
+}
+
+// Write 实现 Sink
+func (s *MongoSink) Write(ctx context.Context, event *Event) error {
+	db, err := s.manager.GetDB(s.database)
+	if err != nil {
+		return err
+	}
+	_, err = db.Collection(s.collection).InsertOne(ctx, event)
+	return err
+}
+
+// MQSink 将审计事件以 JSON 发送到消息队列（如 Kafka），消息 key 为资源（为空时为操作者），同一资源的事件保持有序
+type MQSink struct {
+	manager *mq.Manager
+	topic   string
+}
+
+// NewMQSink 创建消息队列 Sink
+func NewMQSink(manager *mq.Manager, topic string) (*MQSink, error) {
+	if manager == nil {
+		return nil, errors.New("mq manager is nil")
+	}
+	if topic == "" {
+		return nil, errors.New("audit topic is required")
+	}
+	return &MQSink{manager: manager, topic: topic}, nil
+}
+
+// Write 实现 Sink
+func (s *MQSink) Write(ctx context.Context, event *Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %w", err)
+	}
+	key := event.Resource
+	if key == "" {
+		key = event.ActorID
+	}
+	return s.manager.Publish(ctx, &mq.Message{
+		Topic:     s.topic,
+		Key:       []byte(key),
+		Value:     data,
+		Headers:   map[string]string{EventTypeHeader: string(event.Type)},
+		Timestamp: event.Time,
+	})
+}
@@ -0,0 +1,106 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/team-dandelion/quickgo/db/gorm"
+	"github.com/team-dandelion/quickgo/mq"
+	"github.com/team-dandelion/quickgo/tenant"
+)
+
+func TestGormSinkWritesEntries(t *testing.T) {
+	manager, err := gorm.NewManager(&gorm.GormManagerConfig{Databases: []gorm.GormConfig{{
+		Name:        "audit",
+		Master:      gorm.MasterConfig{Type: gorm.DatabaseTypeSQLite, Database: filepath.Join(t.TempDir(), "audit.db")},
+		TenantScope: &gorm.TenantScopeConfig{Enabled: true, Required: true},
+	}}})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	t.Cleanup(func() { _ = manager.Close() })
+	sink, err := NewGormSink(manager, "audit", "")
+	if err != nil {
+		t.Fatalf("NewGormSink failed: %v", err)
+	}
+	if err := sink.Migrate(context.Background()); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	auditor := NewAuditor(sink)
+	ctx := WithActor(context.Background(), Actor{ID: "42"})
+	if err := auditor.Mutation(tenant.WithTenant(ctx, "acme"), "user.update", "user:7", map[string]string{"name": "a"}, map[string]string{"name": "b"}); err != nil {
+		t.Fatalf("Mutation failed: %v", err)
+	}
+	// 未携带租户的事件不受行级租户隔离的 Required 限制
+	if err := auditor.Record(ctx, Event{Type: EventLogout}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	db, _ := manager.GetDB("audit")
+	var entries []Entry
+	if err := db.WithContext(gorm.WithoutTenantScope(context.Background())).Table(DefaultTable).Order("id").Find(&entries).Error; err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	entry := entries[0]
+	if entry.Type != string(EventMutation) || entry.ActorID != "42" || entry.TenantID != "acme" || entry.Resource != "user:7" ||
+		entry.Changes != `[{"field":"name","before":"a","after":"b"}]` {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+}
+
+type publishedDriver struct {
+	mu       sync.Mutex
+	messages []*mq.Message
+}
+
+func (d *publishedDriver) Name() string { return "memory" }
+
+func (d *publishedDriver) Publish(ctx context.Context, msg *mq.Message) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.messages = append(d.messages, msg)
+	return nil
+}
+
+func (d *publishedDriver) Consume(ctx context.Context, sub mq.Subscription, handler mq.Handler) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (d *publishedDriver) Close() error { return nil }
+
+func TestMQSinkPublishesEvents(t *testing.T) {
+	driver := &publishedDriver{}
+	manager, err := mq.NewManagerWithDriver(&mq.Config{}, driver)
+	if err != nil {
+		t.Fatalf("NewManagerWithDriver failed: %v", err)
+	}
+	if _, err := NewMQSink(manager, ""); err == nil {
+		t.Fatal("expected empty topic to be rejected")
+	}
+	sink, _ := NewMQSink(manager, "audit-events")
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := NewAuditor(sink).Record(context.Background(), Event{Type: EventMutation, Time: at, Action: "user.delete", Resource: "user:7"}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	if len(driver.messages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(driver.messages))
+	}
+	msg := driver.messages[0]
+	if msg.Topic != "audit-events" || string(msg.Key) != "user:7" || msg.Headers[EventTypeHeader] != string(EventMutation) || !msg.Timestamp.Equal(at) {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+	var event Event
+	if err := json.Unmarshal(msg.Value, &event); err != nil || event.Action != "user.delete" {
+		t.Fatalf("unexpected payload: %s (%v)", msg.Value, err)
+	}
+}
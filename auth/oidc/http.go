@@ -0,0 +1,209 @@
+package oidc
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/team-dandelion/quickgo/http"
+	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/session"
+)
+
+const (
+	// SessionUserKey 会话中保存登录用户的键
+	SessionUserKey = "oidc_user"
+	// sessionLoginKey 会话中保存登录中状态（state、nonce、code_verifier）的键
+	sessionLoginKey = "oidc_login"
+	// loginStateTTL 登录中状态的有效期
+	loginStateTTL = 10 * time.Minute
+)
+
+// User 登录用户（保存在会话中）
+type User struct {
+	Subject  string                 `json:"sub"`
+	Email    string                 `json:"email,omitempty"`
+	Name     string                 `json:"name,omitempty"`
+	Username string                 `json:"username,omitempty"`
+	Claims   map[string]interface{} `json:"claims,omitempty"`
+	// 令牌（用于调用下游 API、刷新与退出登录）
+	AccessToken  string    `json:"accessToken,omitempty"`
+	RefreshToken string    `json:"refreshToken,omitempty"`
+	IDToken      string    `json:"idToken,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+}
+
+// loginState 登录中状态
+type loginState struct {
+	State        string    `json:"state"`
+	Nonce        string    `json:"nonce"`
+	CodeVerifier string    `json:"codeVerifier"`
+	ReturnTo     string    `json:"returnTo,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// Register 在路由上挂载 /login、/callback、/logout，需先安装 session 中间件
+//
+//	GET  /login?return_to=/orders   跳转到身份提供方登录（return_to 仅允许站内相对路径）
+//	GET  /callback                  校验 state、换取令牌、校验 ID Token 后写入会话并跳转
+//	GET|POST /logout                销毁会话，身份提供方支持时跳转到 end_session_endpoint
+func (p *Provider) Register(router fiber.Router) {
+	router.Get("/login", p.LoginHandler())
+	router.Get("/callback", p.CallbackHandler())
+	router.Get("/logout", p.LogoutHandler())
+	router.Post("/logout", p.LogoutHandler())
+}
+
+// LoginHandler 发起授权码登录
+func (p *Provider) LoginHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		s := session.FromCtx(c)
+		if s == nil {
+			return session.ErrNoSession
+		}
+		state, err := randomString()
+		if err != nil {
+			return err
+		}
+		nonce, err := randomString()
+		if err != nil {
+			return err
+		}
+		verifier, challenge, err := NewPKCE()
+		if err != nil {
+			return err
+		}
+		login := loginState{State: state, Nonce: nonce, CodeVerifier: verifier, CreatedAt: time.Now()}
+		if returnTo := c.Query("return_to"); isLocalPath(returnTo) {
+			login.ReturnTo = returnTo
+		}
+		if err := s.Set(sessionLoginKey, login); err != nil {
+			return err
+		}
+		return c.Redirect(p.AuthCodeURL(state, nonce, challenge), fiber.StatusFound)
+	}
+}
+
+// CallbackHandler 处理身份提供方回调
+// state 不一致或登录已过期返回 400，身份提供方返回错误或令牌校验失败返回 401
+func (p *Provider) CallbackHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		s := session.FromCtx(c)
+		if s == nil {
+			return session.ErrNoSession
+		}
+		var login loginState
+		ok, err := s.Get(sessionLoginKey, &login)
+		s.Delete(sessionLoginKey)
+		if err != nil || !ok || login.State == "" || c.Query("state") != login.State || time.Since(login.CreatedAt) > loginStateTTL {
+			return fiber.NewError(fiber.StatusBadRequest, ErrInvalidState.Error())
+		}
+		if errCode := c.Query("error"); errCode != "" {
+			logger.Warn(c.UserContext(), "OIDC login failed: error=%s, description=%s", errCode, c.Query("error_description"))
+			return fiber.NewError(fiber.StatusUnauthorized, "login failed: "+errCode)
+		}
+		code := c.Query("code")
+		if code == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "missing authorization code")
+		}
+
+		ctx := http.RequestContext(c)
+		token, err := p.Exchange(ctx, code, login.CodeVerifier)
+		if err != nil {
+			logger.Error(ctx, "OIDC token exchange failed: error=%v", err)
+			return fiber.NewError(fiber.StatusUnauthorized, "token exchange failed")
+		}
+		if token.IDToken == "" {
+			return fiber.NewError(fiber.StatusUnauthorized, "token response is missing id_token")
+		}
+		idToken, err := p.VerifyIDToken(ctx, token.IDToken, login.Nonce)
+		if err != nil {
+			logger.Warn(ctx, "OIDC id token rejected: error=%v", err)
+			return fiber.NewError(fiber.StatusUnauthorized, ErrInvalidToken.Error())
+		}
+
+		user := User{
+			Subject:      idToken.Subject,
+			Claims:       idToken.Claims,
+			AccessToken:  token.AccessToken,
+			RefreshToken: token.RefreshToken,
+			IDToken:      token.IDToken,
+			Expiry:       token.Expiry,
+		}
+		user.Email, _ = idToken.Claims["email"].(string)
+		user.Name, _ = idToken.Claims["name"].(string)
+		user.Username, _ = idToken.Claims["preferred_username"].(string)
+		// 登录后更换会话 ID，防止会话固定攻击
+		s.Regenerate()
+		if err := s.Set(SessionUserKey, user); err != nil {
+			return err
+		}
+		returnTo := login.ReturnTo
+		if returnTo == "" {
+			returnTo = p.config.PostLoginRedirect
+		}
+		return c.Redirect(returnTo, fiber.StatusFound)
+	}
+}
+
+// LogoutHandler 退出登录
+func (p *Provider) LogoutHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		s := session.FromCtx(c)
+		if s == nil {
+			return session.ErrNoSession
+		}
+		var user User
+		_, _ = s.Get(SessionUserKey, &user)
+		s.Destroy()
+
+		if p.discovery.EndSessionEndpoint == "" {
+			return c.Redirect(p.config.PostLogoutRedirect, fiber.StatusFound)
+		}
+		query := url.Values{"client_id": {p.config.ClientID}}
+		if user.IDToken != "" {
+			query.Set("id_token_hint", user.IDToken)
+		}
+		if target, err := url.Parse(p.config.PostLogoutRedirect); err == nil && target.IsAbs() {
+			query.Set("post_logout_redirect_uri", p.config.PostLogoutRedirect)
+		}
+		separator := "?"
+		if strings.Contains(p.discovery.EndSessionEndpoint, "?") {
+			separator = "&"
+		}
+		return c.Redirect(p.discovery.EndSessionEndpoint+separator+query.Encode(), fiber.StatusFound)
+	}
+}
+
+// RequireLogin 要求已登录的中间件：未登录的 GET 页面请求跳转到 loginPath（默认 /auth/login）并带上 return_to，其他请求返回 401
+func (p *Provider) RequireLogin(loginPath ...string) fiber.Handler {
+	path := "/auth/login"
+	if len(loginPath) > 0 && loginPath[0] != "" {
+		path = loginPath[0]
+	}
+	return func(c *fiber.Ctx) error {
+		if _, ok := UserFromCtx(c); ok {
+			return c.Next()
+		}
+		if c.Method() == fiber.MethodGet && strings.Contains(c.Get(fiber.HeaderAccept), fiber.MIMETextHTML) {
+			return c.Redirect(path+"?"+url.Values{"return_to": {c.OriginalURL()}}.Encode(), fiber.StatusFound)
+		}
+		return fiber.NewError(fiber.StatusUnauthorized, "login required")
+	}
+}
+
+// UserFromCtx 获取会话中的登录用户
+func UserFromCtx(c *fiber.Ctx) (*User, bool) {
+	user, ok := session.Get[User](c, SessionUserKey)
+	if !ok || user.Subject == "" {
+		return nil, false
+	}
+	return &user, true
+}
+
+// isLocalPath 判断是否为站内相对路径，避免登录后跳转到外部站点（开放重定向）
+func isLocalPath(path string) bool {
+	return strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "//") && !strings.HasPrefix(path, "/\\")
+}
@@ -0,0 +1,196 @@
+// Package oidc 提供 OAuth2 / OpenID Connect 授权码模式（PKCE）客户端
+//
+// Provider 读取发现文档，完成授权码换取令牌、JWKS 缓存与 ID Token 校验；Register 在 HTTP 路由上挂载
+// /login、/callback、/logout，登录状态保存在会话（session 包）中，网关可直接对接 Keycloak、Auth0 等身份提供方：
+//
+//	provider, _ := oidc.New(ctx, &oidc.Config{Issuer: issuer, ClientID: id, ClientSecret: secret,
+//		RedirectURL: "https://gateway.example.com/auth/callback"})
+//	app.Use(sessions.FiberMiddleware())
+//	provider.Register(app.Group("/auth"))
+//	app.Get("/api/profile", provider.RequireLogin(), handler)
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/team-dandelion/quickgo/types"
+)
+
+const (
+	defaultTimeout      = 10 * time.Second
+	defaultJWKSCacheTTL = time.Hour
+	// jwksMinRefresh 遇到未知 kid 时刷新 JWKS 的最小间隔，避免伪造 kid 的请求放大为对身份提供方的请求
+	jwksMinRefresh = time.Minute
+	// maxResponseBytes 身份提供方响应体上限
+	maxResponseBytes = 1 << 20
+)
+
+var (
+	// ErrInvalidState 回调的 state 与登录时不一致（或登录已过期）
+	ErrInvalidState = errors.New("oidc: invalid state")
+	// ErrInvalidToken ID Token 校验失败
+	ErrInvalidToken = errors.New("oidc: invalid id token")
+)
+
+// Config OIDC 客户端配置
+type Config struct {
+	// 身份提供方 Issuer 示例：https://keycloak.example.com/realms/demo
+	Issuer string `json:"issuer" yaml:"issuer" toml:"issuer"`
+	// 客户端 ID
+	ClientID string `json:"clientId" yaml:"clientId" toml:"clientId"`
+	// 客户端密钥（公开客户端可为空，仅使用 PKCE）
+	ClientSecret string `json:"clientSecret" yaml:"clientSecret" toml:"clientSecret"`
+	// 回调地址，需与身份提供方中登记的一致 示例：https://gateway.example.com/auth/callback
+	RedirectURL string `json:"redirectUrl" yaml:"redirectUrl" toml:"redirectUrl"`
+	// 申请的 scope（默认 openid profile email，始终包含 openid）
+	Scopes []string `json:"scopes" yaml:"scopes" toml:"scopes"`
+	// 登录成功后的默认跳转地址（默认 /）
+	PostLoginRedirect string `json:"postLoginRedirect" yaml:"postLoginRedirect" toml:"postLoginRedirect"`
+	// 退出登录后的跳转地址（默认 /），身份提供方支持 end_session_endpoint 时作为 post_logout_redirect_uri，需为绝对地址
+	PostLogoutRedirect string `json:"postLogoutRedirect" yaml:"postLogoutRedirect" toml:"postLogoutRedirect"`
+	// 请求身份提供方的超时时间 示例：10s（默认 10s）
+	Timeout types.Duration `json:"timeout" yaml:"timeout" toml:"timeout"`
+	// JWKS 缓存时间 示例：1h（默认 1h），遇到未知 kid 时提前刷新
+	JWKSCacheTTL types.Duration `json:"jwksCacheTTL" yaml:"jwksCacheTTL" toml:"jwksCacheTTL"`
+	// 校验令牌时间时允许的时钟偏差 示例：30s（默认 0）
+	ClockSkew types.Duration `json:"clockSkew" yaml:"clockSkew" toml:"clockSkew"`
+	// 自定义 HTTP 客户端（可选，如配置代理或自定义 CA）
+	HTTPClient *http.Client `json:"-" yaml:"-" toml:"-"`
+}
+
+// Discovery OIDC 发现文档（/.well-known/openid-configuration）
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	EndSessionEndpoint    string `json:"end_session_endpoint"`
+}
+
+// Provider OIDC 身份提供方客户端
+type Provider struct {
+	config       Config
+	discovery    Discovery
+	client       *http.Client
+	jwksCacheTTL time.Duration
+	clockSkew    time.Duration
+
+	mu          sync.Mutex
+	keys        map[string]interface{}
+	keysFetched time.Time
+}
+
+// New 创建 OIDC 客户端并读取发现文档
+func New(ctx context.Context, config *Config) (*Provider, error) {
+	if config == nil {
+		return nil, errors.New("oidc config is nil")
+	}
+	p := &Provider{
+		config:       *config,
+		jwksCacheTTL: config.JWKSCacheTTL.OrDefault(defaultJWKSCacheTTL),
+		clockSkew:    config.ClockSkew.OrDefault(0),
+	}
+	if p.config.Issuer == "" || p.config.ClientID == "" || p.config.RedirectURL == "" {
+		return nil, errors.New("oidc issuer, clientId and redirectUrl are required")
+	}
+	if len(p.config.Scopes) == 0 {
+		p.config.Scopes = []string{"openid", "profile", "email"}
+	} else if !containsString(p.config.Scopes, "openid") {
+		p.config.Scopes = append([]string{"openid"}, p.config.Scopes...)
+	}
+	if p.config.PostLoginRedirect == "" {
+		p.config.PostLoginRedirect = "/"
+	}
+	if p.config.PostLogoutRedirect == "" {
+		p.config.PostLogoutRedirect = "/"
+	}
+	p.client = p.config.HTTPClient
+	if p.client == nil {
+		p.client = &http.Client{Timeout: p.config.Timeout.OrDefault(defaultTimeout)}
+	}
+
+	issuer := strings.TrimSuffix(p.config.Issuer, "/")
+	if err := p.getJSON(ctx, issuer+"/.well-known/openid-configuration", &p.discovery); err != nil {
+		return nil, fmt.Errorf("failed to fetch oidc discovery document: %w", err)
+	}
+	if strings.TrimSuffix(p.discovery.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc issuer mismatch: configured %s, discovered %s", p.config.Issuer, p.discovery.Issuer)
+	}
+	if p.discovery.AuthorizationEndpoint == "" || p.discovery.TokenEndpoint == "" || p.discovery.JWKSURI == "" {
+		return nil, errors.New("oidc discovery document is missing authorization, token or jwks endpoint")
+	}
+	return p, nil
+}
+
+// Discovery 返回发现文档
+func (p *Provider) Discovery() Discovery {
+	return p.discovery
+}
+
+// AuthCodeURL 生成授权地址，codeChallenge 为 PKCE code_verifier 的 S256 摘要（见 NewPKCE）
+func (p *Provider) AuthCodeURL(state, nonce, codeChallenge string) string {
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {strings.Join(p.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(p.discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return p.discovery.AuthorizationEndpoint + separator + query.Encode()
+}
+
+// getJSON 请求身份提供方并解码 JSON 响应
+func (p *Provider) getJSON(ctx context.Context, endpoint string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	return p.do(req, out)
+}
+
+// do 发送请求，非 2xx 响应返回包含响应体的错误
+func (p *Provider) do(req *http.Request, out interface{}) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: status %d: %s", req.Method, req.URL.Redacted(), resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", req.URL.Redacted(), err)
+	}
+	return nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
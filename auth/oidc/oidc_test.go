@@ -0,0 +1,270 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	redisClient "github.com/redis/go-redis/v9"
+
+	"github.com/team-dandelion/quickgo/session"
+)
+
+// fakeIdP 测试用身份提供方：签发授权码并在令牌端点校验 PKCE
+type fakeIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu       sync.Mutex
+	codes    map[string]url.Values
+	jwksHits int
+	audience string
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	idp := &fakeIdP{key: key, codes: make(map[string]url.Values), audience: "gateway"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(Discovery{
+			Issuer:                idp.server.URL,
+			AuthorizationEndpoint: idp.server.URL + "/authorize",
+			TokenEndpoint:         idp.server.URL + "/token",
+			JWKSURI:               idp.server.URL + "/jwks",
+			EndSessionEndpoint:    idp.server.URL + "/logout",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		idp.mu.Lock()
+		idp.jwksHits++
+		idp.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kid": "k1", "kty": "RSA", "use": "sig",
+			"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		idp.mu.Lock()
+		auth := idp.codes[r.PostForm.Get("code")]
+		delete(idp.codes, r.PostForm.Get("code"))
+		idp.mu.Unlock()
+		if user, pass, _ := r.BasicAuth(); user != "gateway" || pass != "secret" {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+		if auth == nil || pkceChallenge(r.PostForm.Get("code_verifier")) != auth.Get("code_challenge") ||
+			r.PostForm.Get("redirect_uri") != auth.Get("redirect_uri") {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(Token{
+			AccessToken: "access", TokenType: "Bearer", RefreshToken: "refresh", ExpiresIn: 300,
+			IDToken: idp.sign(t, jwt.MapClaims{"sub": "user-1", "nonce": auth.Get("nonce"), "email": "alice@example.com"}),
+		})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+// authorize 模拟用户在身份提供方完成登录，返回回调地址
+func (idp *fakeIdP) authorize(t *testing.T, location string) string {
+	t.Helper()
+	authURL, err := url.Parse(location)
+	if err != nil || !strings.HasPrefix(location, idp.server.URL+"/authorize?") {
+		t.Fatalf("unexpected authorization redirect: %s", location)
+	}
+	query := authURL.Query()
+	if query.Get("code_challenge_method") != "S256" || query.Get("scope") != "openid profile email" {
+		t.Fatalf("unexpected authorization request: %s", location)
+	}
+	idp.mu.Lock()
+	idp.codes["code-1"] = query
+	idp.mu.Unlock()
+	return "/auth/callback?" + url.Values{"code": {"code-1"}, "state": {query.Get("state")}}.Encode()
+}
+
+func (idp *fakeIdP) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	now := time.Now()
+	base := jwt.MapClaims{"iss": idp.server.URL, "aud": idp.audience, "iat": now.Unix(), "exp": now.Add(time.Minute).Unix()}
+	for k, v := range claims {
+		base[k] = v
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, base)
+	token.Header["kid"] = "k1"
+	signed, err := token.SignedString(idp.key)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return signed
+}
+
+func newTestProvider(t *testing.T, idp *fakeIdP) *Provider {
+	t.Helper()
+	provider, err := New(context.Background(), &Config{
+		Issuer:             idp.server.URL,
+		ClientID:           "gateway",
+		ClientSecret:       "secret",
+		RedirectURL:        "https://gateway.example.com/auth/callback",
+		PostLogoutRedirect: "https://gateway.example.com/",
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return provider
+}
+
+func TestLoginCallbackLogout(t *testing.T) {
+	idp := newFakeIdP(t)
+	provider := newTestProvider(t, idp)
+
+	redis := miniredis.RunT(t)
+	client := redisClient.NewClient(&redisClient.Options{Addr: redis.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	sessions, err := session.New(session.NewRedisStore(client), &session.Config{Secret: "0123456789abcdef0123456789abcdef"})
+	if err != nil {
+		t.Fatalf("session.New failed: %v", err)
+	}
+	app := fiber.New()
+	app.Use(sessions.FiberMiddleware())
+	provider.Register(app.Group("/auth"))
+	app.Get("/orders", provider.RequireLogin(), func(c *fiber.Ctx) error {
+		user, _ := UserFromCtx(c)
+		return c.SendString(user.Subject + " " + user.Email)
+	})
+
+	var cookie *http.Cookie
+	send := func(method, target string, header map[string]string) (*http.Response, string) {
+		t.Helper()
+		req := httptest.NewRequest(method, target, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", method, target, err)
+		}
+		for _, c := range resp.Cookies() {
+			if c.Name == session.DefaultCookieName {
+				cookie = c
+			}
+		}
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	if resp, _ := send("GET", "/orders", nil); resp.StatusCode != fiber.StatusUnauthorized {
+		t.Fatalf("expected 401 for API request, got %d", resp.StatusCode)
+	}
+	resp, _ := send("GET", "/orders?page=2", map[string]string{"Accept": "text/html"})
+	if resp.StatusCode != fiber.StatusFound || resp.Header.Get("Location") != "/auth/login?return_to=%2Forders%3Fpage%3D2" {
+		t.Fatalf("expected redirect to login, got %d %s", resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	resp, _ = send("GET", "/auth/login?return_to=/orders", nil)
+	callback := idp.authorize(t, resp.Header.Get("Location"))
+	preLogin := cookie.Value
+
+	// state 不一致的回调被拒绝，且登录状态只能使用一次
+	if resp, _ := send("GET", "/auth/callback?code=code-1&state=forged", nil); resp.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for forged state, got %d", resp.StatusCode)
+	}
+	resp, _ = send("GET", "/auth/login?return_to=//evil.example.com", nil)
+	callback = idp.authorize(t, resp.Header.Get("Location"))
+
+	resp, body := send("GET", callback, nil)
+	if resp.StatusCode != fiber.StatusFound || resp.Header.Get("Location") != "/" {
+		t.Fatalf("expected redirect after login (external return_to ignored), got %d %s %s", resp.StatusCode, resp.Header.Get("Location"), body)
+	}
+	if cookie.Value == preLogin {
+		t.Fatal("expected session id to be regenerated after login")
+	}
+	if _, body = send("GET", "/orders", nil); body != "user-1 alice@example.com" {
+		t.Fatalf("expected logged in user, got %s", body)
+	}
+
+	resp, _ = send("POST", "/auth/logout", nil)
+	location, _ := url.Parse(resp.Header.Get("Location"))
+	if resp.StatusCode != fiber.StatusFound || !strings.HasPrefix(location.String(), idp.server.URL+"/logout?") ||
+		location.Query().Get("id_token_hint") == "" || location.Query().Get("post_logout_redirect_uri") != "https://gateway.example.com/" {
+		t.Fatalf("unexpected logout redirect: %d %s", resp.StatusCode, location)
+	}
+	if resp, _ := send("GET", "/orders", nil); resp.StatusCode != fiber.StatusUnauthorized {
+		t.Fatalf("expected logout to clear the session, got %d", resp.StatusCode)
+	}
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	idp := newFakeIdP(t)
+	provider := newTestProvider(t, idp)
+	ctx := context.Background()
+
+	token, err := provider.VerifyIDToken(ctx, idp.sign(t, jwt.MapClaims{"sub": "user-1", "nonce": "n1", "name": "Alice"}), "n1")
+	if err != nil {
+		t.Fatalf("VerifyIDToken failed: %v", err)
+	}
+	if token.Subject != "user-1" || token.Claims["name"] != "Alice" || token.Expiry.IsZero() {
+		t.Fatalf("unexpected token: %+v", token)
+	}
+
+	cases := map[string]string{
+		"nonce mismatch": idp.sign(t, jwt.MapClaims{"sub": "user-1", "nonce": "other"}),
+		"expired":        idp.sign(t, jwt.MapClaims{"sub": "user-1", "nonce": "n1", "exp": time.Now().Add(-time.Minute).Unix()}),
+		"wrong issuer":   idp.sign(t, jwt.MapClaims{"sub": "user-1", "nonce": "n1", "iss": "https://evil.example.com"}),
+		"wrong audience": idp.sign(t, jwt.MapClaims{"sub": "user-1", "nonce": "n1", "aud": "other-client"}),
+		"missing sub":    idp.sign(t, jwt.MapClaims{"nonce": "n1"}),
+		"hmac":           mustHMAC(t, idp),
+	}
+	for name, raw := range cases {
+		if _, err := provider.VerifyIDToken(ctx, raw, "n1"); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: expected ErrInvalidToken, got %v", name, err)
+		}
+	}
+	if idp.jwksHits != 1 {
+		t.Fatalf("expected jwks to be cached, fetched %d times", idp.jwksHits)
+	}
+}
+
+func mustHMAC(t *testing.T, idp *fakeIdP) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"iss": idp.server.URL, "aud": "gateway", "sub": "user-1",
+		"exp": time.Now().Add(time.Minute).Unix()})
+	token.Header["kid"] = "k1"
+	signed, _ := token.SignedString([]byte("guessable"))
+	return signed
+}
+
+func TestNewRejectsIssuerMismatch(t *testing.T) {
+	idp := newFakeIdP(t)
+	if _, err := New(context.Background(), &Config{Issuer: idp.server.URL + "/other", ClientID: "gateway", RedirectURL: "https://x/cb"}); err == nil {
+		t.Fatal("expected discovery failure for unknown issuer path")
+	}
+	if _, err := New(context.Background(), &Config{ClientID: "gateway"}); err == nil {
+		t.Fatal("expected missing issuer to be rejected")
+	}
+}
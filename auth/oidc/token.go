@@ -0,0 +1,287 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signingMethods 允许的 ID Token 签名算法（不接受 none 与 HMAC）
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// Token 令牌端点返回的令牌
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+	// 访问令牌过期时间（由 ExpiresIn 计算）
+	Expiry time.Time `json:"expiry,omitempty"`
+}
+
+// IDToken 校验通过的 ID Token
+type IDToken struct {
+	Subject  string
+	Issuer   string
+	Audience []string
+	Expiry   time.Time
+	IssuedAt time.Time
+	Nonce    string
+	// 全部 claims（含 email、name、preferred_username 及身份提供方自定义的角色等）
+	Claims map[string]interface{}
+	// 原始令牌
+	Raw string
+}
+
+// NewPKCE 生成 PKCE code_verifier 与对应的 S256 code_challenge
+func NewPKCE() (verifier, challenge string, err error) {
+	verifier, err = randomString()
+	if err != nil {
+		return "", "", err
+	}
+	return verifier, pkceChallenge(verifier), nil
+}
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// randomString 生成随机字符串（state、nonce、code_verifier）
+func randomString() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Exchange 使用授权码与 PKCE code_verifier 换取令牌
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (*Token, error) {
+	return p.requestToken(ctx, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {codeVerifier},
+	})
+}
+
+// Refresh 使用刷新令牌换取新的令牌
+func (p *Provider) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	return p.requestToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+}
+
+// requestToken 请求令牌端点，配置了 ClientSecret 时使用 client_secret_basic 认证
+func (p *Provider) requestToken(ctx context.Context, form url.Values) (*Token, error) {
+	if p.config.ClientSecret == "" {
+		form.Set("client_id", p.config.ClientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+	var token Token
+	if err := p.do(req, &token); err != nil {
+		return nil, fmt.Errorf("oidc token request failed: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("oidc token response is missing access_token")
+	}
+	if token.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return &token, nil
+}
+
+// VerifyIDToken 校验 ID Token 的签名（JWKS）、issuer、audience、有效期与 nonce（nonce 为空时不校验）
+func (p *Provider) VerifyIDToken(ctx context.Context, raw, nonce string) (*IDToken, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, kid)
+	},
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithIssuer(p.discovery.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(p.clockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	token := &IDToken{Claims: claims, Raw: raw}
+	token.Subject, _ = claims.GetSubject()
+	token.Issuer, _ = claims.GetIssuer()
+	token.Audience, _ = claims.GetAudience()
+	if exp, _ := claims.GetExpirationTime(); exp != nil {
+		token.Expiry = exp.Time
+	}
+	if iat, _ := claims.GetIssuedAt(); iat != nil {
+		token.IssuedAt = iat.Time
+	}
+	token.Nonce, _ = claims["nonce"].(string)
+	if token.Subject == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrInvalidToken)
+	}
+	// 多个 audience 时 azp 必须为当前客户端
+	if azp, ok := claims["azp"].(string); ok && azp != p.config.ClientID || !ok && len(token.Audience) > 1 {
+		return nil, fmt.Errorf("%w: authorized party mismatch", ErrInvalidToken)
+	}
+	if nonce != "" && token.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidToken)
+	}
+	return token, nil
+}
+
+// jwks JWKS 文档
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// key 返回 kid 对应的公钥，缓存过期或 kid 未知时刷新 JWKS（未知 kid 的刷新受 jwksMinRefresh 限制）
+func (p *Provider) key(ctx context.Context, kid string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	if key, ok := p.lookupKey(kid); ok && now.Sub(p.keysFetched) < p.jwksCacheTTL {
+		return key, nil
+	}
+	if p.keys == nil || now.Sub(p.keysFetched) >= jwksMinRefresh || now.Sub(p.keysFetched) >= p.jwksCacheTTL {
+		keys, err := p.fetchKeys(ctx)
+		if err != nil {
+			// 身份提供方暂时不可用时继续使用已缓存的公钥
+			if key, ok := p.lookupKey(kid); ok {
+				return key, nil
+			}
+			return nil, err
+		}
+		p.keys, p.keysFetched = keys, now
+	}
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("signing key %q not found in jwks", kid)
+}
+
+// lookupKey 查找公钥，kid 为空且只有一个公钥时使用该公钥
+func (p *Provider) lookupKey(kid string) (interface{}, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+// fetchKeys 读取并解析 JWKS（忽略不支持的密钥类型与加密用途的密钥）
+func (p *Provider) fetchKeys(ctx context.Context) (map[string]interface{}, error) {
+	var set jwks
+	if err := p.getJSON(ctx, p.discovery.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwks contains no usable signing keys")
+	}
+	return keys, nil
+}
+
+// publicKey 将 JWK 转换为 RSA / ECDSA 公钥
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, errors.New("rsa exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("ec point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil || len(data) == 0 {
+		return nil, errors.New("invalid jwk parameter")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
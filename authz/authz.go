@@ -0,0 +1,308 @@
+// Package authz 提供基于角色的访问控制（RBAC）
+//
+// 角色拥有权限（如 user:read、user:*、*），并可继承其他角色的权限；策略来自配置（StaticSource）
+// 或数据库（gormsource 子包），编译后缓存在内存中，可定时或手动 Reload 热更新。
+// 网关通过 FiberIdentity 写入身份并透传给后端，HTTP 使用 http.RequirePermission，gRPC 使用 UnaryServerInterceptor：
+//
+//	authorizer, _ := authz.New(authz.StaticSource(roles...), &authz.Config{ReloadInterval: types.Duration(time.Minute)})
+//	authz.SetDefault(authorizer)
+//	app.Get("/api/users", http.RequirePermission("user:read"), handler)
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/types"
+)
+
+var (
+	// ErrUnauthenticated 请求未携带身份
+	ErrUnauthenticated = errors.New("authz: unauthenticated")
+	// ErrForbidden 身份不具备所需权限
+	ErrForbidden = errors.New("authz: permission denied")
+)
+
+// Role 角色定义
+type Role struct {
+	// 角色名称 示例：admin
+	Name string `json:"name" yaml:"name" toml:"name"`
+	// 权限列表，支持通配符 示例：user:read、user:*、*
+	Permissions []string `json:"permissions" yaml:"permissions" toml:"permissions"`
+	// 继承的角色
+	Inherits []string `json:"inherits" yaml:"inherits" toml:"inherits"`
+}
+
+// Config 授权配置
+type Config struct {
+	// 策略热更新间隔 示例：1m（默认 0，不定时更新，可调用 Reload）
+	ReloadInterval types.Duration `json:"reloadInterval" yaml:"reloadInterval" toml:"reloadInterval"`
+	// 从 context 解析身份（如读取鉴权中间件写入的 JWT claims），context 中没有 WithIdentity 写入的身份时调用
+	IdentityResolver func(ctx context.Context) (Identity, bool) `json:"-" yaml:"-" toml:"-"`
+}
+
+// PolicySource 策略来源
+type PolicySource interface {
+	// Load 读取全部角色定义
+	Load(ctx context.Context) ([]Role, error)
+}
+
+// Identity 请求身份
+type Identity struct {
+	Subject string
+	Roles   []string
+}
+
+type identityKey struct{}
+
+// WithIdentity 将身份存入 context
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext 获取 WithIdentity 写入的身份
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	if ctx == nil {
+		return Identity{}, false
+	}
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
+// policy 编译后的策略：角色 -> 权限（已展开继承）
+type policy struct {
+	permissions map[string][]string
+	// 判定缓存：角色 + "\x00" + 权限 -> 是否允许，策略更新时整体替换
+	decisions sync.Map
+}
+
+// Authorizer RBAC 授权器
+type Authorizer struct {
+	source PolicySource
+	config Config
+
+	mu     sync.RWMutex
+	policy *policy
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New 创建授权器并加载策略，配置了 ReloadInterval 时在后台定时重新加载（Close 停止）
+func New(source PolicySource, config *Config) (*Authorizer, error) {
+	if source == nil {
+		return nil, errors.New("authz policy source is nil")
+	}
+	a := &Authorizer{source: source}
+	if config != nil {
+		a.config = *config
+	}
+	if err := a.Reload(context.Background()); err != nil {
+		return nil, err
+	}
+	if interval := a.config.ReloadInterval.Std(); interval > 0 {
+		a.stop, a.done = make(chan struct{}), make(chan struct{})
+		go a.reloadLoop(interval)
+	}
+	return a, nil
+}
+
+// Reload 重新加载策略，失败时保留当前策略
+func (a *Authorizer) Reload(ctx context.Context) error {
+	roles, err := a.source.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load authz policy: %w", err)
+	}
+	compiled, err := compile(roles)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.policy = compiled
+	a.mu.Unlock()
+	return nil
+}
+
+func (a *Authorizer) reloadLoop(interval time.Duration) {
+	defer close(a.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.stop:
+			return
+		case <-ticker.C:
+			if err := a.Reload(context.Background()); err != nil {
+				logger.Error(context.Background(), "Failed to reload authz policy: %v", err)
+			}
+		}
+	}
+}
+
+// Close 停止定时重新加载
+func (a *Authorizer) Close() {
+	if a.stop == nil {
+		return
+	}
+	select {
+	case <-a.stop:
+	default:
+		close(a.stop)
+	}
+	<-a.done
+}
+
+// Permissions 返回角色拥有的全部权限（含继承，已排序）
+func (a *Authorizer) Permissions(role string) []string {
+	a.mu.RLock()
+	p := a.policy
+	a.mu.RUnlock()
+	return append([]string(nil), p.permissions[role]...)
+}
+
+// Allowed 判断任一角色是否拥有权限
+func (a *Authorizer) Allowed(roles []string, permission string) bool {
+	a.mu.RLock()
+	p := a.policy
+	a.mu.RUnlock()
+	for _, role := range roles {
+		key := role + "\x00" + permission
+		if allowed, ok := p.decisions.Load(key); ok {
+			if allowed.(bool) {
+				return true
+			}
+			continue
+		}
+		allowed := false
+		for _, granted := range p.permissions[role] {
+			if matchPermission(granted, permission) {
+				allowed = true
+				break
+			}
+		}
+		p.decisions.Store(key, allowed)
+		if allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// Identity 获取请求身份：WithIdentity 写入的身份优先，其次为 Config.IdentityResolver
+func (a *Authorizer) Identity(ctx context.Context) (Identity, bool) {
+	if identity, ok := IdentityFromContext(ctx); ok {
+		return identity, true
+	}
+	if a.config.IdentityResolver != nil {
+		return a.config.IdentityResolver(ctx)
+	}
+	return Identity{}, false
+}
+
+// Check 校验请求身份是否拥有全部权限，未携带身份返回 ErrUnauthenticated，缺少权限返回 ErrForbidden
+func (a *Authorizer) Check(ctx context.Context, permissions ...string) error {
+	identity, ok := a.Identity(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	for _, permission := range permissions {
+		if !a.Allowed(identity.Roles, permission) {
+			return fmt.Errorf("%w: %s requires %s", ErrForbidden, identity.Subject, permission)
+		}
+	}
+	return nil
+}
+
+// matchPermission 权限匹配：* 匹配全部，以 :* 结尾时匹配该前缀下的全部权限（user:* 匹配 user:read、user:profile:write）
+func matchPermission(granted, required string) bool {
+	if granted == "*" || granted == required {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(granted, "*"); ok && strings.HasSuffix(prefix, ":") {
+		return strings.HasPrefix(required, prefix)
+	}
+	return false
+}
+
+// compile 展开角色继承，继承了未定义的角色或存在循环继承时返回错误
+func compile(roles []Role) (*policy, error) {
+	defined := make(map[string]Role, len(roles))
+	for _, role := range roles {
+		if role.Name == "" {
+			return nil, errors.New("authz role name is required")
+		}
+		existing := defined[role.Name]
+		existing.Name = role.Name
+		existing.Permissions = append(existing.Permissions, role.Permissions...)
+		existing.Inherits = append(existing.Inherits, role.Inherits...)
+		defined[role.Name] = existing
+	}
+
+	p := &policy{permissions: make(map[string][]string, len(defined))}
+	var expand func(name string, visiting map[string]bool) (map[string]bool, error)
+	expand = func(name string, visiting map[string]bool) (map[string]bool, error) {
+		role, ok := defined[name]
+		if !ok {
+			return nil, fmt.Errorf("authz role %s is not defined", name)
+		}
+		if visiting[name] {
+			return nil, fmt.Errorf("authz role %s has circular inheritance", name)
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+		result := make(map[string]bool)
+		for _, permission := range role.Permissions {
+			result[strings.TrimSpace(permission)] = true
+		}
+		for _, parent := range role.Inherits {
+			inherited, err := expand(parent, visiting)
+			if err != nil {
+				return nil, err
+			}
+			for permission := range inherited {
+				result[permission] = true
+			}
+		}
+		return result, nil
+	}
+	for name := range defined {
+		permissions, err := expand(name, make(map[string]bool))
+		if err != nil {
+			return nil, err
+		}
+		list := make([]string, 0, len(permissions))
+		for permission := range permissions {
+			if permission != "" {
+				list = append(list, permission)
+			}
+		}
+		sort.Strings(list)
+		p.permissions[name] = list
+	}
+	return p, nil
+}
+
+var (
+	defaultMu         sync.RWMutex
+	defaultAuthorizer *Authorizer
+)
+
+// SetDefault 设置全局授权器（http.RequirePermission 等使用）
+func SetDefault(a *Authorizer) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultAuthorizer = a
+}
+
+// Default 获取全局授权器，未设置时返回 nil
+func Default() *Authorizer {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultAuthorizer
+}
@@ -0,0 +1,105 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var testRoles = []Role{
+	{Name: "viewer", Permissions: []string{"user:read", "order:read"}},
+	{Name: "editor", Permissions: []string{"user:*"}, Inherits: []string{"viewer"}},
+	{Name: "admin", Permissions: []string{"*"}},
+}
+
+func TestAuthorizerPermissions(t *testing.T) {
+	a, err := New(StaticSource(testRoles...), nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if got := a.Permissions("editor"); !reflect.DeepEqual(got, []string{"order:read", "user:*", "user:read"}) {
+		t.Fatalf("unexpected editor permissions: %v", got)
+	}
+
+	cases := []struct {
+		roles      []string
+		permission string
+		want       bool
+	}{
+		{[]string{"viewer"}, "user:read", true},
+		{[]string{"viewer"}, "user:write", false},
+		{[]string{"editor"}, "user:profile:write", true},
+		{[]string{"editor"}, "order:read", true},
+		{[]string{"editor"}, "order:write", false},
+		{[]string{"editor"}, "users:read", false},
+		{[]string{"viewer", "admin"}, "billing:refund", true},
+		{[]string{"unknown"}, "user:read", false},
+	}
+	for _, c := range cases {
+		// 第二次命中判定缓存
+		for i := 0; i < 2; i++ {
+			if got := a.Allowed(c.roles, c.permission); got != c.want {
+				t.Errorf("Allowed(%v, %s) = %v, want %v", c.roles, c.permission, got, c.want)
+			}
+		}
+	}
+
+	ctx := WithIdentity(context.Background(), Identity{Subject: "u1", Roles: []string{"viewer"}})
+	if err := a.Check(ctx, "user:read", "order:read"); err != nil {
+		t.Fatalf("expected viewer to be allowed: %v", err)
+	}
+	if err := a.Check(ctx, "user:read", "user:write"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+	if err := a.Check(context.Background(), "user:read"); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("expected ErrUnauthenticated, got %v", err)
+	}
+}
+
+func TestCompileRejectsInvalidInheritance(t *testing.T) {
+	if _, err := New(StaticSource(Role{Name: "a", Inherits: []string{"missing"}}), nil); err == nil {
+		t.Fatal("expected undefined parent role to be rejected")
+	}
+	if _, err := New(StaticSource(Role{Name: "a", Inherits: []string{"b"}}, Role{Name: "b", Inherits: []string{"a"}}), nil); err == nil {
+		t.Fatal("expected circular inheritance to be rejected")
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	a, _ := New(StaticSource(testRoles...), nil)
+	interceptor := a.UnaryServerInterceptor(map[string][]string{
+		"/user.UserService/DeleteUser": {"user:delete"},
+		"/admin.AdminService/*":        {"admin:access"},
+	})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		identity, _ := IdentityFromContext(ctx)
+		return identity.Subject, nil
+	}
+	call := func(ctx context.Context, method string) (interface{}, error) {
+		return interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	}
+	asEditor := metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataSubject, "u1", MetadataRoles, "viewer, editor"))
+
+	if resp, err := call(asEditor, "/user.UserService/DeleteUser"); err != nil || resp != "u1" {
+		t.Fatalf("expected editor to delete users, got %v %v", resp, err)
+	}
+	if _, err := call(asEditor, "/admin.AdminService/Purge"); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", err)
+	}
+	if _, err := call(context.Background(), "/user.UserService/DeleteUser"); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+	if resp, err := call(context.Background(), "/user.UserService/GetUser"); err != nil || resp != "" {
+		t.Fatalf("expected unruled method to pass, got %v %v", resp, err)
+	}
+	injected := metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataSubject, "u1", MetadataRoles, "viewer", MetadataRoles, "admin"))
+	if _, err := call(injected, "/admin.AdminService/Purge"); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected duplicated roles to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+// Package gormsource 提供从数据库表读取 authz 策略的 PolicySource，
+// 独立成包避免只使用 authz 中间件的服务引入 GORM 依赖。
+//
+//	source, _ := gormsource.New(gormManager, "main", "", authz.Role{Name: "admin", Permissions: []string{"*"}})
+//	authorizer, _ := authz.New(source, &authz.Config{ReloadInterval: types.Duration(time.Minute)})
+package gormsource
+
+import (
+	"context"
+	"errors"
+
+	"github.com/team-dandelion/quickgo/authz"
+	"github.com/team-dandelion/quickgo/db/gorm"
+)
+
+// DefaultTable 默认表名
+const DefaultTable = "authz_role_permissions"
+
+var _ authz.PolicySource = (*Source)(nil)
+
+// RolePermission Source 的策略表结构，每行授予角色一个权限；Inherits 非空时表示角色继承另一个角色
+type RolePermission struct {
+	ID         uint64 `gorm:"primaryKey;autoIncrement"`
+	Role       string `gorm:"size:64;not null;index"`
+	Permission string `gorm:"size:128"`
+	Inherits   string `gorm:"size:64"`
+}
+
+// Source 从数据库表读取策略，可与配置中的固定角色合并（如内置的 admin 角色）
+type Source struct {
+	manager  *gorm.Manager
+	database string
+	table    string
+	static   []authz.Role
+}
+
+// New 创建数据库策略来源，database 为 GormManager 中的数据库名称，table 为空时使用 DefaultTable
+func New(manager *gorm.Manager, database, table string, static ...authz.Role) (*Source, error) {
+	if manager == nil {
+		return nil, errors.New("gorm manager is nil")
+	}
+	if table == "" {
+		table = DefaultTable
+	}
+	return &Source{manager: manager, database: database, table: table, static: static}, nil
+}
+
+// Migrate 创建或更新策略表
+func (s *Source) Migrate(ctx context.Context) error {
+	db, err := s.manager.GetDB(s.database)
+	if err != nil {
+		return err
+	}
+	return db.WithContext(ctx).Table(s.table).AutoMigrate(&RolePermission{})
+}
+
+// Load 实现 authz.PolicySource
+func (s *Source) Load(ctx context.Context) ([]authz.Role, error) {
+	db, err := s.manager.GetDB(s.database)
+	if err != nil {
+		return nil, err
+	}
+	var rows []RolePermission
+	if err := db.WithContext(gorm.WithoutTenantScope(ctx)).Table(s.table).Order("id").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	roles := append([]authz.Role(nil), s.static...)
+	for _, row := range rows {
+		role := authz.Role{Name: row.Role}
+		if row.Permission != "" {
+			role.Permissions = []string{row.Permission}
+		}
+		if row.Inherits != "" {
+			role.Inherits = []string{row.Inherits}
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
@@ -0,0 +1,64 @@
+package gormsource
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/team-dandelion/quickgo/authz"
+	"github.com/team-dandelion/quickgo/db/gorm"
+)
+
+func TestSourceReload(t *testing.T) {
+	manager, err := gorm.NewManager(&gorm.GormManagerConfig{Databases: []gorm.GormConfig{{
+		Name:   "authz",
+		Master: gorm.MasterConfig{Type: gorm.DatabaseTypeSQLite, Database: filepath.Join(t.TempDir(), "authz.db")},
+	}}})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	t.Cleanup(func() { _ = manager.Close() })
+	source, err := New(manager, "authz", "", authz.Role{Name: "admin", Permissions: []string{"*"}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := source.Migrate(context.Background()); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	db, _ := manager.GetDB("authz")
+	if err := db.Table(DefaultTable).Create(&[]RolePermission{
+		{Role: "viewer", Permission: "user:read"},
+		{Role: "support", Inherits: "viewer"},
+	}).Error; err != nil {
+		t.Fatalf("seed failed: %v", err)
+	}
+
+	a, err := authz.New(source, nil)
+	if err != nil {
+		t.Fatalf("authz.New failed: %v", err)
+	}
+	if !a.Allowed([]string{"support"}, "user:read") || a.Allowed([]string{"support"}, "ticket:close") {
+		t.Fatal("unexpected support permissions before reload")
+	}
+
+	if err := db.Table(DefaultTable).Create(&RolePermission{Role: "support", Permission: "ticket:close"}).Error; err != nil {
+		t.Fatalf("insert failed: %v", err)
+	}
+	if err := a.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if !a.Allowed([]string{"support"}, "ticket:close") || !a.Allowed([]string{"admin"}, "anything") {
+		t.Fatal("expected reloaded policy to be applied")
+	}
+
+	// 策略无效时保留当前策略
+	if err := db.Table(DefaultTable).Create(&RolePermission{Role: "broken", Inherits: "missing"}).Error; err != nil {
+		t.Fatalf("insert failed: %v", err)
+	}
+	if err := a.Reload(context.Background()); err == nil {
+		t.Fatal("expected invalid policy to fail reload")
+	}
+	if !a.Allowed([]string{"support"}, "ticket:close") {
+		t.Fatal("expected previous policy to be kept")
+	}
+}
@@ -0,0 +1,107 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// 网关透传身份使用的 gRPC metadata 键（由 FiberIdentity 写入 UserValues，经 grpcep.RPCCtx 透传）
+const (
+	MetadataSubject = "x-user-id"
+	MetadataRoles   = "x-user-roles"
+)
+
+// IdentityFromMetadata 从 incoming metadata 读取网关透传的身份（角色以逗号分隔）
+// 网关对每个键只写入一个值（grpcep 隧道丢弃客户端携带的 x-user-* 键），出现多个值时视为伪造，不返回身份；
+// metadata 可由直连调用方任意设置，后端服务只应在仅接受网关访问的内网中信任该身份
+func IdentityFromMetadata(ctx context.Context) (Identity, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return Identity{}, false
+	}
+	subjects, roles := md.Get(MetadataSubject), md.Get(MetadataRoles)
+	if len(subjects) != 1 || subjects[0] == "" || len(roles) > 1 {
+		return Identity{}, false
+	}
+	identity := Identity{Subject: subjects[0]}
+	if len(roles) == 1 {
+		identity.Roles = splitRoles(roles[0])
+	}
+	return identity, true
+}
+
+func splitRoles(value string) []string {
+	var roles []string
+	for _, role := range strings.Split(value, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// UnaryServerInterceptor gRPC 一元调用授权拦截器，rules 为方法全名（或 /pkg.Service/* 匹配整个服务）到所需权限的映射，
+// 未配置规则的方法不做校验。身份取自 context（WithIdentity / Config.IdentityResolver），其次为网关透传的 metadata；
+// 未携带身份返回 Unauthenticated，缺少权限返回 PermissionDenied
+func (a *Authorizer) UnaryServerInterceptor(rules map[string][]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authorizeMethod(ctx, rules, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor gRPC 流式调用授权拦截器
+func (a *Authorizer) StreamServerInterceptor(rules map[string][]string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorizeMethod(ss.Context(), rules, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authzServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authorizeMethod 校验方法所需权限，并将身份写入 context 供业务代码使用
+func (a *Authorizer) authorizeMethod(ctx context.Context, rules map[string][]string, fullMethod string) (context.Context, error) {
+	identity, ok := a.Identity(ctx)
+	if !ok {
+		identity, ok = IdentityFromMetadata(ctx)
+	}
+	if ok {
+		ctx = WithIdentity(ctx, identity)
+	}
+	permissions, ruled := rules[fullMethod]
+	if !ruled {
+		if i := strings.LastIndex(fullMethod, "/"); i > 0 {
+			permissions, ruled = rules[fullMethod[:i]+"/*"]
+		}
+	}
+	if !ruled {
+		return ctx, nil
+	}
+	switch err := a.Check(ctx, permissions...); {
+	case errors.Is(err, ErrUnauthenticated):
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	return ctx, nil
+}
+
+type authzServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authzServerStream) Context() context.Context {
+	return s.ctx
+}
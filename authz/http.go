@@ -0,0 +1,24 @@
+package authz
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FiberIdentity HTTP 身份中间件，resolve 从请求中解析已验证的身份（如 JWT、OIDC 会话）
+// 身份写入 UserContext 供 http.RequirePermission 校验，并写入 UserValues 经 grpcep.RPCCtx 透传给后端 gRPC 服务
+func FiberIdentity(resolve func(c *fiber.Ctx) (Identity, bool)) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		identity, ok := resolve(c)
+		if !ok || identity.Subject == "" {
+			return c.Next()
+		}
+		c.SetUserContext(WithIdentity(c.UserContext(), identity))
+		c.Context().SetUserValue(MetadataSubject, identity.Subject)
+		if len(identity.Roles) > 0 {
+			c.Context().SetUserValue(MetadataRoles, strings.Join(identity.Roles, ","))
+		}
+		return c.Next()
+	}
+}
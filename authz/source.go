@@ -0,0 +1,15 @@
+package authz
+
+import "context"
+
+type staticSource []Role
+
+// StaticSource 配置中的固定策略
+func StaticSource(roles ...Role) PolicySource {
+	return staticSource(roles)
+}
+
+// Load 实现 PolicySource
+func (s staticSource) Load(ctx context.Context) ([]Role, error) {
+	return []Role(s), nil
+}
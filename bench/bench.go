@@ -0,0 +1,242 @@
+// Package bench 提供 gRPC 客户端压测工具
+//
+// Run 以固定并发（可选限定 QPS）在指定时长或请求数内反复执行调用，统计成功率、吞吐与延迟分位数，
+// 用于评估拦截器开销与服务容量。调用可以是生成的客户端方法，也可以通过 GRPCCall / ReflectionCall
+// 直接驱动已注册的服务：
+//
+//	call := bench.GRPCCall(conn, "/auth.Auth/Login", func(i int) proto.Message { return &pb.LoginReq{} }, func() proto.Message { return &pb.LoginResp{} })
+//	report, err := bench.Run(ctx, &bench.Config{Concurrency: 50, QPS: 2000, Duration: "30s"}, call)
+//	fmt.Println(report)
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultConcurrency = 10
+	defaultDuration    = 10 * time.Second
+)
+
+// Call 一次压测调用，i 为请求序号（从 0 开始，可用于生成不同的请求内容）
+type Call func(ctx context.Context, i int) error
+
+// Config 压测配置
+type Config struct {
+	// 并发数（默认 10）
+	Concurrency int `json:"concurrency" yaml:"concurrency" toml:"concurrency"`
+	// 目标 QPS（0 表示不限速，每个并发连续调用）
+	QPS float64 `json:"qps" yaml:"qps" toml:"qps"`
+	// 压测时长 示例：30s（未设置 Requests 时默认 10s）
+	Duration string `json:"duration" yaml:"duration" toml:"duration"`
+	// 总请求数（0 表示按时长），与 Duration 同时设置时先达到者结束
+	Requests int `json:"requests" yaml:"requests" toml:"requests"`
+	// 单次调用超时 示例：1s（默认不设置）
+	Timeout string `json:"timeout" yaml:"timeout" toml:"timeout"`
+}
+
+// Latency 延迟统计
+type Latency struct {
+	Min  time.Duration `json:"min"`
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P90  time.Duration `json:"p90"`
+	P99  time.Duration `json:"p99"`
+	P999 time.Duration `json:"p999"`
+	Max  time.Duration `json:"max"`
+}
+
+// Report 压测结果
+type Report struct {
+	// 完成的请求数
+	Total   int `json:"total"`
+	Success int `json:"success"`
+	Failure int `json:"failure"`
+	// 失败按 gRPC 状态码分组（非 status 错误计为 Unknown）
+	Errors map[string]int `json:"errors,omitempty"`
+	// 实际压测耗时
+	Elapsed time.Duration `json:"elapsed"`
+	// 实际吞吐（请求数 / 秒）
+	Throughput float64 `json:"throughput"`
+	// 所有请求（含失败）的延迟
+	Latency Latency `json:"latency"`
+}
+
+// String 返回可读的压测结果
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "requests: %d (success %d, failure %d) in %s, %.1f req/s\n",
+		r.Total, r.Success, r.Failure, r.Elapsed.Round(time.Millisecond), r.Throughput)
+	fmt.Fprintf(&b, "latency: min %s, mean %s, p50 %s, p90 %s, p99 %s, p99.9 %s, max %s\n",
+		r.Latency.Min, r.Latency.Mean, r.Latency.P50, r.Latency.P90, r.Latency.P99, r.Latency.P999, r.Latency.Max)
+	codes := make([]string, 0, len(r.Errors))
+	for code := range r.Errors {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		fmt.Fprintf(&b, "error %s: %d\n", code, r.Errors[code])
+	}
+	return b.String()
+}
+
+// worker 单个并发的统计结果
+type worker struct {
+	latencies []time.Duration
+	errors    map[string]int
+}
+
+// Run 执行压测，ctx 取消时提前结束并返回已完成请求的统计
+func Run(ctx context.Context, config *Config, call Call) (*Report, error) {
+	if call == nil {
+		return nil, errors.New("bench call is nil")
+	}
+	if config == nil {
+		config = &Config{}
+	}
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	if config.QPS < 0 || config.Requests < 0 {
+		return nil, errors.New("bench qps and requests must not be negative")
+	}
+	var duration, timeout time.Duration
+	if config.Duration != "" {
+		d, err := time.ParseDuration(config.Duration)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid bench duration %q", config.Duration)
+		}
+		duration = d
+	} else if config.Requests == 0 {
+		duration = defaultDuration
+	}
+	if config.Timeout != "" {
+		d, err := time.ParseDuration(config.Timeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid bench timeout %q", config.Timeout)
+		}
+		timeout = d
+	}
+
+	var (
+		next    atomic.Int64
+		start   = time.Now()
+		workers = make([]*worker, concurrency)
+		wg      sync.WaitGroup
+	)
+	var deadline time.Time
+	if duration > 0 {
+		deadline = start.Add(duration)
+	}
+	// take 分配下一个请求序号；限速时按 start + i/QPS 排定发送时间（开环，避免慢响应降低发压）
+	take := func() (int, bool) {
+		i := int(next.Add(1) - 1)
+		if config.Requests > 0 && i >= config.Requests {
+			return 0, false
+		}
+		due := time.Now()
+		if config.QPS > 0 {
+			due = start.Add(time.Duration(float64(i) / config.QPS * float64(time.Second)))
+		}
+		if !deadline.IsZero() && !due.Before(deadline) {
+			return 0, false
+		}
+		if wait := time.Until(due); wait > 0 {
+			timer := time.NewTimer(wait)
+			defer timer.Stop()
+			select {
+			case <-ctx.Done():
+				return 0, false
+			case <-timer.C:
+			}
+		}
+		return i, ctx.Err() == nil
+	}
+
+	for w := range workers {
+		workers[w] = &worker{errors: make(map[string]int)}
+		wg.Add(1)
+		go func(stats *worker) {
+			defer wg.Done()
+			for {
+				i, ok := take()
+				if !ok {
+					return
+				}
+				latency, err := invoke(ctx, call, i, timeout)
+				stats.latencies = append(stats.latencies, latency)
+				if err != nil {
+					stats.errors[status.Code(err).String()]++
+				}
+			}
+		}(workers[w])
+	}
+	wg.Wait()
+	return buildReport(workers, time.Since(start)), nil
+}
+
+// invoke 执行一次调用并返回耗时
+func invoke(ctx context.Context, call Call, i int, timeout time.Duration) (time.Duration, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	begin := time.Now()
+	err := call(ctx, i)
+	return time.Since(begin), err
+}
+
+// buildReport 合并各并发的统计结果
+func buildReport(workers []*worker, elapsed time.Duration) *Report {
+	report := &Report{Elapsed: elapsed, Errors: make(map[string]int)}
+	var latencies []time.Duration
+	for _, w := range workers {
+		latencies = append(latencies, w.latencies...)
+		for code, n := range w.errors {
+			report.Errors[code] += n
+			report.Failure += n
+		}
+	}
+	report.Total = len(latencies)
+	report.Success = report.Total - report.Failure
+	if elapsed > 0 {
+		report.Throughput = float64(report.Total) / elapsed.Seconds()
+	}
+	if len(latencies) == 0 {
+		return report
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	var sum time.Duration
+	for _, l := range latencies {
+		sum += l
+	}
+	report.Latency = Latency{
+		Min:  latencies[0],
+		Mean: sum / time.Duration(len(latencies)),
+		P50:  percentile(latencies, 0.5),
+		P90:  percentile(latencies, 0.9),
+		P99:  percentile(latencies, 0.99),
+		P999: percentile(latencies, 0.999),
+		Max:  latencies[len(latencies)-1],
+	}
+	return report
+}
+
+// percentile 返回已排序延迟的分位数（nearest-rank）
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	rank = max(0, min(rank, len(sorted)-1))
+	return sorted[rank]
+}
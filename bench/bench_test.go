@@ -0,0 +1,79 @@
+package bench
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRunStopsAfterRequestsAndGroupsErrors(t *testing.T) {
+	var calls atomic.Int32
+	report, err := Run(context.Background(), &Config{Concurrency: 4, Requests: 100}, func(ctx context.Context, i int) error {
+		calls.Add(1)
+		if i%10 == 0 {
+			return status.Error(codes.Unavailable, "down")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if calls.Load() != 100 || report.Total != 100 || report.Success != 90 || report.Failure != 10 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if report.Errors[codes.Unavailable.String()] != 10 {
+		t.Fatalf("expected errors grouped by code, got %v", report.Errors)
+	}
+	if report.Latency.Min > report.Latency.P50 || report.Latency.P50 > report.Latency.Max {
+		t.Fatalf("unexpected latency %+v", report.Latency)
+	}
+}
+
+func TestRunPacesToQPS(t *testing.T) {
+	report, err := Run(context.Background(), &Config{Concurrency: 8, QPS: 200, Duration: "200ms"}, func(ctx context.Context, i int) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	// 200ms 内按 200 QPS 排定 40 个请求
+	if report.Total != 40 {
+		t.Fatalf("expected 40 paced requests, got %d", report.Total)
+	}
+}
+
+func TestRunAppliesTimeout(t *testing.T) {
+	report, err := Run(context.Background(), &Config{Concurrency: 2, Requests: 4, Timeout: "10ms"}, func(ctx context.Context, i int) error {
+		<-ctx.Done()
+		return status.FromContextError(ctx.Err()).Err()
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if report.Errors[codes.DeadlineExceeded.String()] != 4 {
+		t.Fatalf("expected deadline errors, got %v", report.Errors)
+	}
+	if _, err := Run(context.Background(), &Config{Duration: "soon"}, func(context.Context, int) error { return nil }); err == nil {
+		t.Fatal("expected invalid duration to be rejected")
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+	if p := percentile(sorted, 0.5); p != 50*time.Millisecond {
+		t.Fatalf("p50 = %s", p)
+	}
+	if p := percentile(sorted, 0.99); p != 99*time.Millisecond {
+		t.Fatalf("p99 = %s", p)
+	}
+	if p := percentile(sorted, 0.999); p != 100*time.Millisecond {
+		t.Fatalf("p99.9 = %s", p)
+	}
+}
@@ -0,0 +1,178 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// IndexPlaceholder JSON 请求模板中的请求序号占位符
+const IndexPlaceholder = "{{i}}"
+
+// GRPCCall 创建一元 gRPC 调用，newRequest 按请求序号生成请求，newResponse 创建响应消息
+// fullMethod 格式为 /package.Service/Method
+func GRPCCall(conn grpc.ClientConnInterface, fullMethod string, newRequest func(i int) proto.Message, newResponse func() proto.Message, opts ...grpc.CallOption) Call {
+	return func(ctx context.Context, i int) error {
+		return conn.Invoke(ctx, fullMethod, newRequest(i), newResponse(), opts...)
+	}
+}
+
+// Method 通过 server reflection 解析的一元方法（服务端需开启 GrpcServerConfig.Reflection）
+type Method struct {
+	// 完整方法名 /package.Service/Method
+	FullMethod string
+	Input      protoreflect.MessageDescriptor
+	Output     protoreflect.MessageDescriptor
+}
+
+// ResolveMethod 通过 server reflection 解析方法的请求与响应类型，method 格式为 package.Service/Method
+func ResolveMethod(ctx context.Context, conn grpc.ClientConnInterface, method string) (*Method, error) {
+	method = strings.TrimPrefix(method, "/")
+	service, name, ok := strings.Cut(method, "/")
+	if !ok || service == "" || name == "" {
+		return nil, fmt.Errorf("invalid method %q, expected package.Service/Method", method)
+	}
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open reflection stream: %w", err)
+	}
+	defer func() { _ = stream.CloseSend() }()
+
+	files, err := reflectFiles(stream, service)
+	if err != nil {
+		return nil, err
+	}
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, fmt.Errorf("service %s not found: %w", service, err)
+	}
+	serviceDesc, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", service)
+	}
+	methodDesc := serviceDesc.Methods().ByName(protoreflect.Name(name))
+	if methodDesc == nil {
+		return nil, fmt.Errorf("method %s not found in service %s", name, service)
+	}
+	if methodDesc.IsStreamingClient() || methodDesc.IsStreamingServer() {
+		return nil, fmt.Errorf("method %s is streaming, only unary methods are supported", method)
+	}
+	return &Method{FullMethod: "/" + method, Input: methodDesc.Input(), Output: methodDesc.Output()}, nil
+}
+
+// reflectFiles 获取包含 symbol 的文件及其依赖，构建文件描述集合
+func reflectFiles(stream reflectionpb.ServerReflection_ServerReflectionInfoClient, symbol string) (*protoregistry.Files, error) {
+	protos := make(map[string]*descriptorpb.FileDescriptorProto)
+	request := &reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: symbol},
+	}
+	for request != nil {
+		if err := stream.Send(request); err != nil {
+			return nil, fmt.Errorf("failed to send reflection request: %w", err)
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, fmt.Errorf("failed to receive reflection response: %w", err)
+		}
+		if errResp := resp.GetErrorResponse(); errResp != nil {
+			return nil, fmt.Errorf("reflection error: %s", errResp.GetErrorMessage())
+		}
+		for _, raw := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			file := &descriptorpb.FileDescriptorProto{}
+			if err := proto.Unmarshal(raw, file); err != nil {
+				return nil, fmt.Errorf("failed to decode file descriptor: %w", err)
+			}
+			protos[file.GetName()] = file
+		}
+
+		// 服务端可能省略已发送过的依赖，缺失时按文件名补充请求
+		request = nil
+		for _, file := range protos {
+			for _, dep := range file.GetDependency() {
+				if _, ok := protos[dep]; !ok {
+					request = &reflectionpb.ServerReflectionRequest{
+						MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: dep},
+					}
+					break
+				}
+			}
+			if request != nil {
+				break
+			}
+		}
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, file := range protos {
+		set.File = append(set.File, file)
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build file descriptors: %w", err)
+	}
+	return files, nil
+}
+
+// NewResponse 创建响应消息
+func (m *Method) NewResponse() proto.Message {
+	return dynamicpb.NewMessage(m.Output)
+}
+
+// jsonRequests 根据 JSON 模板生成请求，模板中的 {{i}} 替换为请求序号
+// 模板不含占位符时只解析一次并复用同一请求
+func (m *Method) jsonRequests(template string) (func(i int) (proto.Message, error), error) {
+	if template == "" {
+		template = "{}"
+	}
+	parse := func(data string) (proto.Message, error) {
+		msg := dynamicpb.NewMessage(m.Input)
+		if err := protojson.Unmarshal([]byte(data), msg); err != nil {
+			return nil, fmt.Errorf("invalid request for %s: %w", m.Input.FullName(), err)
+		}
+		return msg, nil
+	}
+
+	first, err := parse(strings.ReplaceAll(template, IndexPlaceholder, "0"))
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(template, IndexPlaceholder) {
+		return func(int) (proto.Message, error) { return first, nil }, nil
+	}
+	return func(i int) (proto.Message, error) {
+		return parse(strings.ReplaceAll(template, IndexPlaceholder, strconv.Itoa(i)))
+	}, nil
+}
+
+// ReflectionCall 创建通过 server reflection 解析的一元调用，请求由 JSON 模板生成（{{i}} 替换为请求序号）
+func ReflectionCall(conn grpc.ClientConnInterface, method *Method, template string, opts ...grpc.CallOption) (Call, error) {
+	if method == nil {
+		return nil, errors.New("bench method is nil")
+	}
+	newRequest, err := method.jsonRequests(template)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, i int) error {
+		req, err := newRequest(i)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		return conn.Invoke(ctx, method.FullMethod, req, method.NewResponse(), opts...)
+	}, nil
+}
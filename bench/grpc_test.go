@@ -0,0 +1,72 @@
+package bench
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newReflectionConn(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("svc0", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestReflectionCallDrivesRegisteredService(t *testing.T) {
+	conn := newReflectionConn(t)
+	ctx := context.Background()
+
+	method, err := ResolveMethod(ctx, conn, "grpc.health.v1.Health/Check")
+	if err != nil {
+		t.Fatalf("ResolveMethod failed: %v", err)
+	}
+	if method.FullMethod != "/grpc.health.v1.Health/Check" || method.Input.FullName() != "grpc.health.v1.HealthCheckRequest" {
+		t.Fatalf("unexpected method %+v", method)
+	}
+
+	// 只有 svc0 已注册，其余序号返回 NotFound
+	call, err := ReflectionCall(conn, method, `{"service":"svc{{i}}"}`)
+	if err != nil {
+		t.Fatalf("ReflectionCall failed: %v", err)
+	}
+	report, err := Run(ctx, &Config{Concurrency: 1, Requests: 3}, call)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if report.Success != 1 || report.Errors[codes.NotFound.String()] != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	if _, err := ReflectionCall(conn, method, `{"unknown":1}`); err == nil {
+		t.Fatal("expected invalid template to be rejected")
+	}
+	if _, err := ResolveMethod(ctx, conn, "grpc.health.v1.Health/Watch"); err == nil {
+		t.Fatal("expected streaming method to be rejected")
+	}
+	if _, err := ResolveMethod(ctx, conn, "grpc.health.v1.Health/Missing"); err == nil {
+		t.Fatal("expected unknown method to be rejected")
+	}
+}
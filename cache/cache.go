@@ -0,0 +1,435 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	redisClient "github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/team-dandelion/quickgo/db/redis"
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+var (
+	// ErrCacheMiss 缓存不存在
+	ErrCacheMiss = errors.New("cache miss")
+	// ErrNotFound 数据不存在；loader 返回该错误时会写入负缓存，后续读取直接返回该错误
+	ErrNotFound = errors.New("cache: not found")
+)
+
+// negativeValue 负缓存占位值
+var negativeValue = []byte("\x00quickgo:cache:nil")
+
+const (
+	defaultJitter      = 0.1
+	defaultNegativeTTL = time.Minute
+	defaultTTL         = 10 * time.Minute
+)
+
+// Config 缓存配置
+type Config struct {
+	// key 前缀，示例：gateway:auth:
+	Prefix string `json:"prefix" yaml:"prefix" toml:"prefix"`
+	// 序列化方式：json（默认）、msgpack，或 serializer 包中注册的名称（如 cbor）
+	Codec string `json:"codec" yaml:"codec" toml:"codec"`
+	// 命名空间配置（key 为命名空间名称），通过 Cache.Namespace 获取命名空间缓存
+	Namespaces map[string]NamespaceConfig `json:"namespaces" yaml:"namespaces" toml:"namespaces"`
+	// 默认过期时间（调用时 ttl<=0 使用），示例：10m（默认 10m）
+	DefaultTTL string `json:"defaultTTL" yaml:"defaultTTL" toml:"defaultTTL"`
+	// 过期时间随机抖动比例（0~1），防止大量 key 同时过期导致缓存雪崩（默认 0.1，负数表示关闭）
+	Jitter float64 `json:"jitter" yaml:"jitter" toml:"jitter"`
+	// 负缓存过期时间，示例：1m（默认 1m，"0s" 表示关闭负缓存）
+	NegativeTTL string `json:"negativeTTL" yaml:"negativeTTL" toml:"negativeTTL"`
+	// 进程内本地缓存（可选），位于 Redis 之前，减少热点 key 的网络往返
+	Local *LocalConfig `json:"local" yaml:"local" toml:"local"`
+}
+
+// NamespaceConfig 缓存命名空间配置
+type NamespaceConfig struct {
+	// 序列化方式（为空时使用 Config.Codec），内部二进制客户端读取的命名空间可使用 msgpack / cbor
+	Codec string `json:"codec" yaml:"codec" toml:"codec"`
+}
+
+// LocalConfig 进程内本地缓存配置
+// 本地缓存不会感知其他实例的更新/删除，TTL 应设置得较短
+type LocalConfig struct {
+	// 最大条目数（默认 10000）
+	MaxEntries int `json:"maxEntries" yaml:"maxEntries" toml:"maxEntries"`
+	// 最大占用字节数（0 表示不限制）
+	MaxBytes int64 `json:"maxBytes" yaml:"maxBytes" toml:"maxBytes"`
+	// 本地过期时间，不超过写入时的 ttl，示例：30s（默认 30s）
+	TTL string `json:"ttl" yaml:"ttl" toml:"ttl"`
+}
+
+// Hooks 缓存指标钩子（均为可选）
+type Hooks struct {
+	// OnHit 缓存命中（negative 为 true 表示命中负缓存）
+	OnHit func(key string, negative bool)
+	// OnMiss 缓存未命中
+	OnMiss func(key string)
+	// OnLoad loader 执行完成
+	OnLoad func(key string, duration time.Duration, err error)
+	// OnError Redis 读写或序列化出错（不影响主流程）
+	OnError func(key string, err error)
+}
+
+// Cache 基于 Redis 的缓存，支持回源加载、singleflight 合并、TTL 抖动和负缓存
+type Cache struct {
+	client      redisClient.Cmdable
+	prefix      string
+	codec       Codec
+	defaultTTL  time.Duration
+	jitter      float64
+	negativeTTL time.Duration
+	hooks       Hooks
+	group       *singleflight.Group
+	local       *LRU[string, []byte]
+	localTTL    time.Duration
+	// 命名空间 key 前缀（根缓存为空），命名空间缓存与根缓存共享 Redis 连接、本地缓存与 singleflight 分组
+	ns         string
+	namespaces map[string]Codec
+}
+
+// LoaderFunc 回源加载函数
+type LoaderFunc func(ctx context.Context) (interface{}, error)
+
+// New 创建缓存
+func New(client redisClient.Cmdable, config *Config) (*Cache, error) {
+	if client == nil {
+		return nil, errors.New("redis client is nil")
+	}
+	if config == nil {
+		config = &Config{}
+	}
+
+	codec, err := codecByName(config.Codec)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cache{
+		client:      client,
+		prefix:      config.Prefix,
+		codec:       codec,
+		defaultTTL:  defaultTTL,
+		jitter:      defaultJitter,
+		negativeTTL: defaultNegativeTTL,
+		group:       &singleflight.Group{},
+		namespaces:  make(map[string]Codec, len(config.Namespaces)),
+	}
+	for name, ns := range config.Namespaces {
+		if name == "" {
+			return nil, errors.New("cache namespace name is empty")
+		}
+		nsCodec := codec
+		if ns.Codec != "" {
+			if nsCodec, err = codecByName(ns.Codec); err != nil {
+				return nil, fmt.Errorf("namespace %s: %w", name, err)
+			}
+		}
+		c.namespaces[name] = nsCodec
+	}
+	if config.DefaultTTL != "" {
+		ttl, err := time.ParseDuration(config.DefaultTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse DefaultTTL %s: %w", config.DefaultTTL, err)
+		}
+		c.defaultTTL = ttl
+	}
+	if config.NegativeTTL != "" {
+		ttl, err := time.ParseDuration(config.NegativeTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse NegativeTTL %s: %w", config.NegativeTTL, err)
+		}
+		c.negativeTTL = ttl
+	}
+	switch {
+	case config.Jitter < 0:
+		c.jitter = 0
+	case config.Jitter > 1:
+		return nil, fmt.Errorf("invalid cache jitter: %v", config.Jitter)
+	case config.Jitter > 0:
+		c.jitter = config.Jitter
+	}
+	if config.Local != nil {
+		if err := c.initLocal(config.Local); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *Cache) initLocal(config *LocalConfig) error {
+	c.localTTL = 30 * time.Second
+	if config.TTL != "" {
+		ttl, err := time.ParseDuration(config.TTL)
+		if err != nil {
+			return fmt.Errorf("failed to parse Local.TTL %s: %w", config.TTL, err)
+		}
+		c.localTTL = ttl
+	}
+	maxEntries := config.MaxEntries
+	if maxEntries == 0 {
+		maxEntries = 10000
+	}
+	local, err := NewLRU(LRUOptions[string, []byte]{
+		MaxEntries: maxEntries,
+		MaxBytes:   config.MaxBytes,
+		TTL:        c.localTTL,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create local cache: %w", err)
+	}
+	c.local = local
+	return nil
+}
+
+// LocalStats 返回本地缓存统计，未启用本地缓存时返回零值
+func (c *Cache) LocalStats() LRUStats {
+	if c.local == nil {
+		return LRUStats{}
+	}
+	return c.local.Stats()
+}
+
+// NewFromClient 基于框架 Redis 客户端创建缓存
+func NewFromClient(client *redis.Client, config *Config) (*Cache, error) {
+	if client == nil || client.GetClient() == nil {
+		return nil, errors.New("redis client is nil")
+	}
+	return New(client.GetClient(), config)
+}
+
+// Namespace 返回命名空间缓存：key 增加 "name:" 前缀，使用命名空间配置的序列化方式（未配置时沿用当前缓存的序列化方式）
+// 命名空间缓存与当前缓存共享 Redis 连接、本地缓存、singleflight 分组、过期策略与钩子，可嵌套（嵌套命名空间的配置名为 "parent:child"）
+func (c *Cache) Namespace(name string) *Cache {
+	ns := c.ns + name + ":"
+	codec, ok := c.namespaces[strings.TrimSuffix(ns, ":")]
+	if !ok {
+		codec = c.codec
+	}
+	return &Cache{
+		client:      c.client,
+		prefix:      c.prefix,
+		codec:       codec,
+		defaultTTL:  c.defaultTTL,
+		jitter:      c.jitter,
+		negativeTTL: c.negativeTTL,
+		hooks:       c.hooks,
+		group:       c.group,
+		local:       c.local,
+		localTTL:    c.localTTL,
+		ns:          ns,
+		namespaces:  c.namespaces,
+	}
+}
+
+// Codec 返回当前使用的序列化方式
+func (c *Cache) Codec() Codec {
+	return c.codec
+}
+
+// SetCodec 设置自定义序列化方式
+func (c *Cache) SetCodec(codec Codec) {
+	if codec != nil {
+		c.codec = codec
+	}
+}
+
+// SetHooks 设置指标钩子
+func (c *Cache) SetHooks(hooks Hooks) {
+	c.hooks = hooks
+}
+
+// Get 读取缓存到 dst，不存在返回 ErrCacheMiss，命中负缓存返回 ErrNotFound
+func (c *Cache) Get(ctx context.Context, key string, dst interface{}) error {
+	data, ok := c.getLocal(key)
+	if !ok {
+		var err error
+		data, err = c.client.Get(ctx, c.redisKey(key)).Bytes()
+		if errors.Is(err, redisClient.Nil) {
+			return ErrCacheMiss
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get cache %s: %w", key, err)
+		}
+		c.setLocal(key, data, 0)
+	}
+	if bytes.Equal(data, negativeValue) {
+		return ErrNotFound
+	}
+	if err := c.codec.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("failed to decode cache %s: %w", key, err)
+	}
+	return nil
+}
+
+// Set 写入缓存，ttl<=0 时使用默认过期时间，实际过期时间带随机抖动
+func (c *Cache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache %s: %w", key, err)
+	}
+	return c.setRaw(ctx, key, data, c.withJitter(ttl))
+}
+
+// Delete 删除缓存（包括负缓存）
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	fullKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		fullKeys = append(fullKeys, c.redisKey(key))
+		if c.local != nil {
+			c.local.Delete(c.ns + key)
+		}
+	}
+	if err := c.client.Del(ctx, fullKeys...).Err(); err != nil {
+		return fmt.Errorf("failed to delete cache: %w", err)
+	}
+	return nil
+}
+
+// GetOrLoad 读取缓存到 dst，未命中时调用 loader 回源并写入缓存
+// 同一进程内相同 key 的并发回源会被合并为一次；loader 返回 ErrNotFound 时写入负缓存
+// Redis 不可用时降级为直接回源
+func (c *Cache) GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader LoaderFunc, dst interface{}) error {
+	err := c.Get(ctx, key, dst)
+	switch {
+	case err == nil:
+		c.onHit(key, false)
+		return nil
+	case errors.Is(err, ErrNotFound):
+		c.onHit(key, true)
+		return ErrNotFound
+	case errors.Is(err, ErrCacheMiss):
+		c.onMiss(key)
+	default:
+		c.onError(ctx, key, err)
+		c.onMiss(key)
+	}
+
+	// 按带命名空间的 key 合并，避免不同命名空间的同名 key 共享回源结果
+	result, err, _ := c.group.Do(c.ns+key, func() (interface{}, error) {
+		return c.load(ctx, key, ttl, loader)
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.codec.Unmarshal(result.([]byte), dst); err != nil {
+		return fmt.Errorf("failed to decode cache %s: %w", key, err)
+	}
+	return nil
+}
+
+// load 执行回源并写入缓存，返回序列化后的数据
+func (c *Cache) load(ctx context.Context, key string, ttl time.Duration, loader LoaderFunc) ([]byte, error) {
+	start := time.Now()
+	value, err := loader(ctx)
+	if c.hooks.OnLoad != nil {
+		c.hooks.OnLoad(key, time.Since(start), err)
+	}
+
+	if errors.Is(err, ErrNotFound) {
+		if c.negativeTTL > 0 {
+			if setErr := c.setRaw(ctx, key, negativeValue, c.negativeTTL); setErr != nil {
+				c.onError(ctx, key, setErr)
+			}
+		}
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cache %s: %w", key, err)
+	}
+	if err := c.setRaw(ctx, key, data, c.withJitter(ttl)); err != nil {
+		c.onError(ctx, key, err)
+	}
+	return data, nil
+}
+
+func (c *Cache) setRaw(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	c.setLocal(key, data, ttl)
+	if err := c.client.Set(ctx, c.redisKey(key), data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set cache %s: %w", key, err)
+	}
+	return nil
+}
+
+// redisKey 返回 Redis 中的完整 key
+func (c *Cache) redisKey(key string) string {
+	return c.prefix + c.ns + key
+}
+
+func (c *Cache) getLocal(key string) ([]byte, bool) {
+	if c.local == nil {
+		return nil, false
+	}
+	return c.local.Get(c.ns + key)
+}
+
+// setLocal 写入本地缓存，本地过期时间不超过 ttl
+func (c *Cache) setLocal(key string, data []byte, ttl time.Duration) {
+	if c.local == nil {
+		return
+	}
+	localTTL := c.localTTL
+	if ttl > 0 && (localTTL <= 0 || ttl < localTTL) {
+		localTTL = ttl
+	}
+	c.local.SetWithTTL(c.ns+key, data, localTTL)
+}
+
+// withJitter 在 ttl 基础上增加 [0, ttl*jitter) 的随机时长
+func (c *Cache) withJitter(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		ttl = c.defaultTTL
+	}
+	if c.jitter <= 0 || ttl <= 0 {
+		return ttl
+	}
+	maxJitter := int64(float64(ttl) * c.jitter)
+	if maxJitter <= 0 {
+		return ttl
+	}
+	return ttl + time.Duration(rand.Int64N(maxJitter))
+}
+
+func (c *Cache) onHit(key string, negative bool) {
+	if c.hooks.OnHit != nil {
+		c.hooks.OnHit(key, negative)
+	}
+}
+
+func (c *Cache) onMiss(key string) {
+	if c.hooks.OnMiss != nil {
+		c.hooks.OnMiss(key)
+	}
+}
+
+func (c *Cache) onError(ctx context.Context, key string, err error) {
+	logger.Warn(ctx, "Cache operation failed: key=%s, error=%v", key, err)
+	if c.hooks.OnError != nil {
+		c.hooks.OnError(key, err)
+	}
+}
+
+// Load 类型化的 GetOrLoad，直接返回 T 类型结果
+func Load[T any](ctx context.Context, c *Cache, key string, ttl time.Duration, loader func(ctx context.Context) (T, error)) (T, error) {
+	var result T
+	err := c.GetOrLoad(ctx, key, ttl, func(ctx context.Context) (interface{}, error) {
+		return loader(ctx)
+	}, &result)
+	return result, err
+}
@@ -0,0 +1,316 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	redisClient "github.com/redis/go-redis/v9"
+
+	"github.com/team-dandelion/quickgo/serializer"
+)
+
+type user struct {
+	ID   string `json:"id" msgpack:"id"`
+	Name string `json:"name" msgpack:"name"`
+}
+
+func newTestCache(t *testing.T, config *Config) (*miniredis.Miniredis, *Cache) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redisClient.NewClient(&redisClient.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	c, err := New(client, config)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return server, c
+}
+
+func TestGetOrLoadCachesAndDeduplicates(t *testing.T) {
+	for _, codec := range []string{CodecJSON, CodecMsgpack} {
+		t.Run(codec, func(t *testing.T) {
+			server, c := newTestCache(t, &Config{Prefix: "test:", Codec: codec})
+
+			var loads atomic.Int32
+			release := make(chan struct{})
+			loader := func(ctx context.Context) (*user, error) {
+				loads.Add(1)
+				<-release
+				return &user{ID: "1", Name: "alice"}, nil
+			}
+
+			var wg sync.WaitGroup
+			results := make([]*user, 10)
+			errs := make([]error, 10)
+			for i := range results {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					results[i], errs[i] = Load(context.Background(), c, "user:1", time.Minute, loader)
+				}(i)
+			}
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+			wg.Wait()
+
+			for i := range results {
+				if errs[i] != nil || results[i] == nil || results[i].Name != "alice" {
+					t.Fatalf("unexpected result %d: %+v, %v", i, results[i], errs[i])
+				}
+			}
+			if loads.Load() != 1 {
+				t.Fatalf("expected loader to run once, got %d", loads.Load())
+			}
+			if !server.Exists("test:user:1") {
+				t.Fatal("expected value to be cached with prefix")
+			}
+
+			got, err := Load(context.Background(), c, "user:1", time.Minute, loader)
+			if err != nil || got.Name != "alice" || loads.Load() != 1 {
+				t.Fatalf("expected cache hit, got %+v, %v, loads=%d", got, err, loads.Load())
+			}
+		})
+	}
+}
+
+func TestGetOrLoadNegativeCaching(t *testing.T) {
+	server, c := newTestCache(t, &Config{NegativeTTL: "30s"})
+
+	var hits, negativeHits, misses int
+	c.SetHooks(Hooks{
+		OnHit: func(key string, negative bool) {
+			hits++
+			if negative {
+				negativeHits++
+			}
+		},
+		OnMiss: func(key string) { misses++ },
+	})
+
+	var loads int
+	loader := func(ctx context.Context) (interface{}, error) {
+		loads++
+		return nil, ErrNotFound
+	}
+
+	var dst user
+	for i := 0; i < 3; i++ {
+		if err := c.GetOrLoad(context.Background(), "missing", time.Minute, loader, &dst); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+	}
+	if loads != 1 || misses != 1 || negativeHits != 2 || hits != 2 {
+		t.Fatalf("unexpected counters: loads=%d misses=%d hits=%d negativeHits=%d", loads, misses, hits, negativeHits)
+	}
+	if ttl := server.TTL("missing"); ttl != 30*time.Second {
+		t.Fatalf("expected negative ttl 30s, got %s", ttl)
+	}
+
+	if err := c.Delete(context.Background(), "missing"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := c.Get(context.Background(), "missing", &dst); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("expected ErrCacheMiss after delete, got %v", err)
+	}
+}
+
+func TestGetOrLoadPropagatesLoaderError(t *testing.T) {
+	server, c := newTestCache(t, nil)
+
+	loadErr := errors.New("backend down")
+	var dst user
+	err := c.GetOrLoad(context.Background(), "k", time.Minute, func(ctx context.Context) (interface{}, error) {
+		return nil, loadErr
+	}, &dst)
+	if !errors.Is(err, loadErr) {
+		t.Fatalf("expected loader error, got %v", err)
+	}
+	if server.Exists("k") {
+		t.Fatal("loader errors must not be cached")
+	}
+}
+
+func TestGetOrLoadFallsBackWhenRedisUnavailable(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redisClient.NewClient(&redisClient.Options{Addr: server.Addr(), MaxRetries: -1})
+	defer client.Close()
+	server.Close()
+
+	c, err := New(client, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	var errorsSeen int
+	c.SetHooks(Hooks{OnError: func(key string, err error) { errorsSeen++ }})
+
+	got, err := Load(context.Background(), c, "u", time.Minute, func(ctx context.Context) (user, error) {
+		return user{ID: "2"}, nil
+	})
+	if err != nil || got.ID != "2" {
+		t.Fatalf("expected loader result when redis is down, got %+v, %v", got, err)
+	}
+	if errorsSeen != 2 {
+		t.Fatalf("expected get and set errors reported, got %d", errorsSeen)
+	}
+}
+
+func TestTTLJitter(t *testing.T) {
+	_, c := newTestCache(t, &Config{Jitter: 0.5})
+	for i := 0; i < 100; i++ {
+		ttl := c.withJitter(time.Minute)
+		if ttl < time.Minute || ttl >= 90*time.Second {
+			t.Fatalf("ttl out of jitter range: %s", ttl)
+		}
+	}
+
+	_, noJitter := newTestCache(t, &Config{Jitter: -1, DefaultTTL: "2m"})
+	if ttl := noJitter.withJitter(0); ttl != 2*time.Minute {
+		t.Fatalf("expected default ttl without jitter, got %s", ttl)
+	}
+
+	if _, err := New(redisClient.NewClient(&redisClient.Options{}), &Config{Codec: "xml"}); err == nil {
+		t.Fatal("expected unsupported codec error")
+	}
+}
+
+func TestLocalTierServesHotKeys(t *testing.T) {
+	server, c := newTestCache(t, &Config{Local: &LocalConfig{MaxEntries: 100, TTL: "1m"}})
+
+	loads := 0
+	loader := func(ctx context.Context) (user, error) {
+		loads++
+		return user{ID: "1", Name: "alice"}, nil
+	}
+	if _, err := Load(context.Background(), c, "hot", time.Minute, loader); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Redis 中的数据被删除后，本地缓存仍可命中
+	server.Del("hot")
+	got, err := Load(context.Background(), c, "hot", time.Minute, loader)
+	if err != nil || got.Name != "alice" || loads != 1 {
+		t.Fatalf("expected local hit, got %+v, %v, loads=%d", got, err, loads)
+	}
+	if stats := c.LocalStats(); stats.Hits != 1 || stats.Entries != 1 {
+		t.Fatalf("unexpected local stats: %+v", stats)
+	}
+
+	// Delete 同时清除本地缓存
+	if err := c.Delete(context.Background(), "hot"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := Load(context.Background(), c, "hot", time.Minute, loader); err != nil || loads != 2 {
+		t.Fatalf("expected reload after delete, err=%v loads=%d", err, loads)
+	}
+}
+
+func TestNamespaceUsesConfiguredCodecAndPrefix(t *testing.T) {
+	server, c := newTestCache(t, &Config{
+		Prefix:     "test:",
+		Namespaces: map[string]NamespaceConfig{"internal": {Codec: serializer.CBOR}},
+		Local:      &LocalConfig{},
+	})
+	ctx := context.Background()
+
+	internal := c.Namespace("internal")
+	if internal.Codec().Name() != serializer.CBOR {
+		t.Fatalf("expected cbor codec for namespace, got %s", internal.Codec().Name())
+	}
+	if c.Namespace("public").Codec() != JSONCodec {
+		t.Fatal("expected unconfigured namespace to inherit the root codec")
+	}
+
+	if err := internal.Set(ctx, "user:1", &user{ID: "1", Name: "alice"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := c.Set(ctx, "user:1", &user{ID: "1", Name: "root"}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	raw, err := server.Get("test:internal:user:1")
+	if err != nil {
+		t.Fatalf("expected namespaced redis key: %v", err)
+	}
+	var decoded user
+	if err := serializer.Must(serializer.CBOR).Unmarshal([]byte(raw), &decoded); err != nil || decoded.Name != "alice" {
+		t.Fatalf("expected cbor encoded value, got %q (%v)", raw, err)
+	}
+
+	// 命名空间与根缓存共享本地缓存但 key 互不冲突
+	var got user
+	if err := internal.Get(ctx, "user:1", &got); err != nil || got.Name != "alice" {
+		t.Fatalf("namespace Get = %+v, %v", got, err)
+	}
+	if err := c.Get(ctx, "user:1", &got); err != nil || got.Name != "root" {
+		t.Fatalf("root Get = %+v, %v", got, err)
+	}
+	if err := internal.Delete(ctx, "user:1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := internal.Get(ctx, "user:1", &got); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("expected namespace miss after delete, got %v", err)
+	}
+	if err := c.Get(ctx, "user:1", &got); err != nil {
+		t.Fatalf("expected root key to survive namespace delete: %v", err)
+	}
+}
+
+func TestNamespaceSharesSingleflightByNamespacedKey(t *testing.T) {
+	_, c := newTestCache(t, &Config{Prefix: "test:"})
+
+	var nsLoads, rootLoads atomic.Int32
+	release := make(chan struct{})
+	loader := func(loads *atomic.Int32, name string) func(ctx context.Context) (*user, error) {
+		return func(ctx context.Context) (*user, error) {
+			loads.Add(1)
+			<-release
+			return &user{ID: "1", Name: name}, nil
+		}
+	}
+
+	var wg sync.WaitGroup
+	results := make([]*user, 10)
+	errs := make([]error, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// 每次调用 Namespace 都返回新实例，合并仍需生效；同名 key 不能与根缓存合并
+			if i%2 == 0 {
+				results[i], errs[i] = Load(context.Background(), c.Namespace("users"), "1", time.Minute, loader(&nsLoads, "alice"))
+			} else {
+				results[i], errs[i] = Load(context.Background(), c, "1", time.Minute, loader(&rootLoads, "root"))
+			}
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i := range results {
+		want := "alice"
+		if i%2 == 1 {
+			want = "root"
+		}
+		if errs[i] != nil || results[i] == nil || results[i].Name != want {
+			t.Fatalf("unexpected result %d: %+v, %v", i, results[i], errs[i])
+		}
+	}
+	if nsLoads.Load() != 1 || rootLoads.Load() != 1 {
+		t.Fatalf("expected one load per namespace, got namespace=%d root=%d", nsLoads.Load(), rootLoads.Load())
+	}
+}
+
+func TestNewRejectsUnknownNamespaceCodec(t *testing.T) {
+	client := redisClient.NewClient(&redisClient.Options{Addr: miniredis.RunT(t).Addr()})
+	defer client.Close()
+	if _, err := New(client, &Config{Namespaces: map[string]NamespaceConfig{"x": {Codec: "yaml"}}}); err == nil {
+		t.Fatal("expected unknown namespace codec to be rejected")
+	}
+}
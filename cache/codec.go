@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/team-dandelion/quickgo/serializer"
+)
+
+// 内置序列化方式
+const (
+	CodecJSON    = "json"
+	CodecMsgpack = "msgpack"
+)
+
+// Codec 缓存值序列化接口
+type Codec interface {
+	// Name 返回序列化方式名称
+	Name() string
+	// Marshal 序列化
+	Marshal(v interface{}) ([]byte, error)
+	// Unmarshal 反序列化
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string                               { return CodecJSON }
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string                               { return CodecMsgpack }
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error)      { return msgpack.Marshal(v) }
+func (msgpackCodec) Unmarshal(data []byte, v interface{}) error { return msgpack.Unmarshal(data, v) }
+
+// JSONCodec JSON 序列化
+var JSONCodec Codec = jsonCodec{}
+
+// MsgpackCodec msgpack 序列化（体积更小、编解码更快）
+var MsgpackCodec Codec = msgpackCodec{}
+
+// codecByName 根据名称获取序列化方式：json、msgpack 使用缓存内置实现（保持已有数据格式不变），
+// 其他名称从 serializer 注册表查找（如 cbor、protojson 或自定义注册的序列化方式）
+func codecByName(name string) (Codec, error) {
+	switch name {
+	case "", CodecJSON:
+		return JSONCodec, nil
+	case CodecMsgpack:
+		return MsgpackCodec, nil
+	}
+	s, err := serializer.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported cache codec: %s", name)
+	}
+	return s, nil
+}
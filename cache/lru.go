@@ -0,0 +1,221 @@
+package cache
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+// LRUOptions 进程内 LRU 缓存配置
+type LRUOptions[K comparable, V any] struct {
+	// 最大条目数（0 表示不限制）
+	MaxEntries int
+	// 最大占用字节数（0 表示不限制），按 SizeFunc 计算
+	MaxBytes int64
+	// 默认过期时间（0 表示不过期）
+	TTL time.Duration
+	// 计算条目大小（字节），未设置时 []byte/string 按长度计算，其余类型为 0
+	SizeFunc func(key K, value V) int64
+	// 条目被淘汰、过期或删除时回调（在锁外调用）
+	OnEvict func(key K, value V)
+}
+
+// LRUStats LRU 缓存统计
+type LRUStats struct {
+	Hits        uint64 `json:"hits"`
+	Misses      uint64 `json:"misses"`
+	Evictions   uint64 `json:"evictions"`
+	Expirations uint64 `json:"expirations"`
+	Entries     int    `json:"entries"`
+	Bytes       int64  `json:"bytes"`
+}
+
+type lruEntry[K comparable, V any] struct {
+	key      K
+	value    V
+	size     int64
+	expireAt time.Time
+}
+
+// LRU 并发安全的进程内 LRU 缓存，支持条目数/字节数上限与 TTL
+type LRU[K comparable, V any] struct {
+	mu    sync.Mutex
+	opts  LRUOptions[K, V]
+	ll    *list.List
+	items map[K]*list.Element
+	bytes int64
+	stats LRUStats
+	now   func() time.Time
+}
+
+// NewLRU 创建 LRU 缓存
+func NewLRU[K comparable, V any](opts LRUOptions[K, V]) (*LRU[K, V], error) {
+	if opts.MaxEntries < 0 || opts.MaxBytes < 0 || opts.TTL < 0 {
+		return nil, errors.New("lru limits must be non-negative")
+	}
+	if opts.MaxEntries == 0 && opts.MaxBytes == 0 {
+		return nil, errors.New("lru requires MaxEntries or MaxBytes")
+	}
+	if opts.SizeFunc == nil {
+		opts.SizeFunc = defaultSize[K, V]
+	}
+	return &LRU[K, V]{
+		opts:  opts,
+		ll:    list.New(),
+		items: make(map[K]*list.Element),
+		now:   time.Now,
+	}, nil
+}
+
+// Get 获取缓存，命中时移动到最近使用位置
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	var zero V
+	c.mu.Lock()
+	elem, ok := c.items[key]
+	if !ok {
+		c.stats.Misses++
+		c.mu.Unlock()
+		return zero, false
+	}
+	entry := elem.Value.(*lruEntry[K, V])
+	if !entry.expireAt.IsZero() && !c.now().Before(entry.expireAt) {
+		c.removeElement(elem)
+		c.stats.Expirations++
+		c.stats.Misses++
+		c.mu.Unlock()
+		c.evicted(entry)
+		return zero, false
+	}
+	c.ll.MoveToFront(elem)
+	c.stats.Hits++
+	c.mu.Unlock()
+	return entry.value, true
+}
+
+// Set 写入缓存，使用默认 TTL
+func (c *LRU[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.opts.TTL)
+}
+
+// SetWithTTL 写入缓存并指定过期时间（0 表示不过期）
+// 单个条目超过 MaxBytes 时不会写入
+func (c *LRU[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	size := c.opts.SizeFunc(key, value)
+	var expireAt time.Time
+	if ttl > 0 {
+		expireAt = c.now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	var removed []*lruEntry[K, V]
+	if elem, ok := c.items[key]; ok {
+		removed = append(removed, c.removeElement(elem))
+	}
+	if c.opts.MaxBytes > 0 && size > c.opts.MaxBytes {
+		c.mu.Unlock()
+		c.evicted(removed...)
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&lruEntry[K, V]{key: key, value: value, size: size, expireAt: expireAt})
+	c.bytes += size
+	for c.overLimit() {
+		oldest := c.ll.Back()
+		if oldest == nil {
+			break
+		}
+		removed = append(removed, c.removeElement(oldest))
+		c.stats.Evictions++
+	}
+	c.mu.Unlock()
+	c.evicted(removed...)
+}
+
+// Delete 删除缓存
+func (c *LRU[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	elem, ok := c.items[key]
+	if !ok {
+		c.mu.Unlock()
+		return false
+	}
+	entry := c.removeElement(elem)
+	c.mu.Unlock()
+	c.evicted(entry)
+	return true
+}
+
+// Purge 清空缓存
+func (c *LRU[K, V]) Purge() {
+	c.mu.Lock()
+	removed := make([]*lruEntry[K, V], 0, len(c.items))
+	for elem := c.ll.Front(); elem != nil; elem = elem.Next() {
+		removed = append(removed, elem.Value.(*lruEntry[K, V]))
+	}
+	c.ll.Init()
+	c.items = make(map[K]*list.Element)
+	c.bytes = 0
+	c.mu.Unlock()
+	c.evicted(removed...)
+}
+
+// Len 返回条目数（可能包含尚未清理的过期条目）
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// Bytes 返回当前占用字节数
+func (c *LRU[K, V]) Bytes() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.bytes
+}
+
+// Stats 返回统计信息快照
+func (c *LRU[K, V]) Stats() LRUStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = c.ll.Len()
+	stats.Bytes = c.bytes
+	return stats
+}
+
+func (c *LRU[K, V]) overLimit() bool {
+	if c.opts.MaxEntries > 0 && c.ll.Len() > c.opts.MaxEntries {
+		return true
+	}
+	return c.opts.MaxBytes > 0 && c.bytes > c.opts.MaxBytes
+}
+
+// removeElement 移除条目（调用方需持有 c.mu）
+func (c *LRU[K, V]) removeElement(elem *list.Element) *lruEntry[K, V] {
+	entry := elem.Value.(*lruEntry[K, V])
+	c.ll.Remove(elem)
+	delete(c.items, entry.key)
+	c.bytes -= entry.size
+	return entry
+}
+
+func (c *LRU[K, V]) evicted(entries ...*lruEntry[K, V]) {
+	if c.opts.OnEvict == nil {
+		return
+	}
+	for _, entry := range entries {
+		c.opts.OnEvict(entry.key, entry.value)
+	}
+}
+
+func defaultSize[K comparable, V any](key K, value V) int64 {
+	switch v := any(value).(type) {
+	case []byte:
+		return int64(len(v))
+	case string:
+		return int64(len(v))
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,145 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	var evicted []string
+	lru, err := NewLRU(LRUOptions[string, int]{
+		MaxEntries: 2,
+		OnEvict:    func(key string, value int) { evicted = append(evicted, key) },
+	})
+	if err != nil {
+		t.Fatalf("NewLRU failed: %v", err)
+	}
+
+	lru.Set("a", 1)
+	lru.Set("b", 2)
+	if _, ok := lru.Get("a"); !ok {
+		t.Fatal("expected a to be cached")
+	}
+	lru.Set("c", 3)
+
+	if _, ok := lru.Get("b"); ok {
+		t.Fatal("expected b to be evicted as least recently used")
+	}
+	if v, ok := lru.Get("a"); !ok || v != 1 {
+		t.Fatalf("expected a=1, got %v, %v", v, ok)
+	}
+	if len(evicted) != 1 || evicted[0] != "b" {
+		t.Fatalf("unexpected evictions: %v", evicted)
+	}
+
+	stats := lru.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Evictions != 1 || stats.Entries != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestLRUByteAccounting(t *testing.T) {
+	lru, err := NewLRU(LRUOptions[string, []byte]{MaxBytes: 10})
+	if err != nil {
+		t.Fatalf("NewLRU failed: %v", err)
+	}
+
+	lru.Set("a", make([]byte, 4))
+	lru.Set("b", make([]byte, 4))
+	if lru.Bytes() != 8 {
+		t.Fatalf("expected 8 bytes, got %d", lru.Bytes())
+	}
+	lru.Set("c", make([]byte, 4))
+	if lru.Bytes() != 8 || lru.Len() != 2 {
+		t.Fatalf("expected oldest entry evicted, bytes=%d len=%d", lru.Bytes(), lru.Len())
+	}
+	if _, ok := lru.Get("a"); ok {
+		t.Fatal("expected a to be evicted")
+	}
+
+	// 替换条目时重新计算大小
+	lru.Set("b", make([]byte, 1))
+	if lru.Bytes() != 5 {
+		t.Fatalf("expected 5 bytes after replace, got %d", lru.Bytes())
+	}
+
+	// 超过上限的单个条目不写入
+	lru.Set("huge", make([]byte, 11))
+	if _, ok := lru.Get("huge"); ok {
+		t.Fatal("expected oversized entry to be rejected")
+	}
+
+	lru.Purge()
+	if lru.Len() != 0 || lru.Bytes() != 0 {
+		t.Fatalf("expected empty cache after purge, len=%d bytes=%d", lru.Len(), lru.Bytes())
+	}
+}
+
+func TestLRUExpiresEntries(t *testing.T) {
+	lru, err := NewLRU(LRUOptions[string, string]{MaxEntries: 10, TTL: time.Minute})
+	if err != nil {
+		t.Fatalf("NewLRU failed: %v", err)
+	}
+	now := time.Now()
+	lru.now = func() time.Time { return now }
+
+	lru.Set("default", "v")
+	lru.SetWithTTL("short", "v", time.Second)
+	lru.SetWithTTL("forever", "v", 0)
+
+	now = now.Add(2 * time.Second)
+	if _, ok := lru.Get("short"); ok {
+		t.Fatal("expected short entry to expire")
+	}
+	if _, ok := lru.Get("default"); !ok {
+		t.Fatal("expected default ttl entry to remain")
+	}
+
+	now = now.Add(time.Hour)
+	if _, ok := lru.Get("default"); ok {
+		t.Fatal("expected default ttl entry to expire")
+	}
+	if _, ok := lru.Get("forever"); !ok {
+		t.Fatal("expected entry without ttl to remain")
+	}
+	if stats := lru.Stats(); stats.Expirations != 2 {
+		t.Fatalf("expected 2 expirations, got %+v", stats)
+	}
+}
+
+func TestLRUConcurrentAccess(t *testing.T) {
+	lru, err := NewLRU(LRUOptions[int, int]{MaxEntries: 64})
+	if err != nil {
+		t.Fatalf("NewLRU failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				lru.Set(i%128, g)
+				lru.Get((i + g) % 128)
+				if i%100 == 0 {
+					lru.Delete(i % 128)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if lru.Len() > 64 {
+		t.Fatalf("expected at most 64 entries, got %d", lru.Len())
+	}
+}
+
+func TestNewLRUValidatesOptions(t *testing.T) {
+	if _, err := NewLRU(LRUOptions[string, int]{}); err == nil {
+		t.Fatal("expected error without limits")
+	}
+	if _, err := NewLRU(LRUOptions[string, int]{MaxEntries: -1}); err == nil {
+		t.Fatal("expected error for negative limit")
+	}
+}
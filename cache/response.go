@@ -0,0 +1,193 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	redisClient "github.com/redis/go-redis/v9"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// defaultResponsePrefix HTTP 响应缓存的默认 Redis key 前缀
+const defaultResponsePrefix = "quickgo:httpcache:"
+
+// ResponseEntry 缓存的 HTTP 响应
+type ResponseEntry struct {
+	// 状态码
+	Status int `msgpack:"s"`
+	// 响应头（按写入顺序，同名头可出现多次）
+	Header [][2]string `msgpack:"h"`
+	// 响应体
+	Body []byte `msgpack:"b"`
+	// 写入时间，用于计算 Age
+	StoredAt time.Time `msgpack:"t"`
+	// 响应 Vary 列出的请求头及写入时的请求值，读取时请求值不同视为未命中
+	Vary [][2]string `msgpack:"v,omitempty"`
+}
+
+// ResponseStore HTTP 响应缓存存储，后端为 Redis 或进程内 LRU
+// 缓存 key 的格式为 "路由#摘要"，同一路由的全部 key 记录在路由索引中，InvalidateRoute 按路由批量删除
+type ResponseStore struct {
+	// Redis 后端（进程内存储时为 nil）
+	client redisClient.Cmdable
+	prefix string
+
+	// 进程内后端
+	local  *LRU[string, []byte]
+	mu     sync.Mutex
+	routes map[string]map[string]struct{}
+}
+
+// NewRedisResponseStore 创建基于 Redis 的响应缓存存储，多个网关实例共享缓存与失效
+// prefix 为空时使用 quickgo:httpcache:
+func NewRedisResponseStore(client redisClient.Cmdable, prefix string) (*ResponseStore, error) {
+	if client == nil {
+		return nil, errors.New("redis client is nil")
+	}
+	if prefix == "" {
+		prefix = defaultResponsePrefix
+	}
+	return &ResponseStore{client: client, prefix: prefix}, nil
+}
+
+// NewMemoryResponseStore 创建进程内响应缓存存储（多实例部署时各实例独立缓存与失效）
+// maxEntries 与 maxBytes 至少设置一项
+func NewMemoryResponseStore(maxEntries int, maxBytes int64) (*ResponseStore, error) {
+	s := &ResponseStore{routes: make(map[string]map[string]struct{})}
+	local, err := NewLRU(LRUOptions[string, []byte]{
+		MaxEntries: maxEntries,
+		MaxBytes:   maxBytes,
+		OnEvict: func(key string, _ []byte) {
+			s.unindex(key)
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create response cache: %w", err)
+	}
+	s.local = local
+	return s, nil
+}
+
+// ResponseKey 返回路由下的缓存 key
+func ResponseKey(route, digest string) string {
+	return route + "#" + digest
+}
+
+// Get 读取缓存的响应，不存在返回 ErrCacheMiss
+func (s *ResponseStore) Get(ctx context.Context, key string) (*ResponseEntry, error) {
+	var data []byte
+	if s.client == nil {
+		var ok bool
+		if data, ok = s.local.Get(key); !ok {
+			return nil, ErrCacheMiss
+		}
+	} else {
+		var err error
+		data, err = s.client.Get(ctx, s.prefix+"resp:"+key).Bytes()
+		if errors.Is(err, redisClient.Nil) {
+			return nil, ErrCacheMiss
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cached response %s: %w", key, err)
+		}
+	}
+	var entry ResponseEntry
+	if err := msgpack.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to decode cached response %s: %w", key, err)
+	}
+	return &entry, nil
+}
+
+// Set 写入响应缓存并记录到路由索引
+// Redis 路由索引的过期时间随每次写入刷新为 ttl，同一路由应使用相同的 TTL 配置以保证索引不早于条目过期
+func (s *ResponseStore) Set(ctx context.Context, key string, entry *ResponseEntry, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	data, err := msgpack.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode response %s: %w", key, err)
+	}
+	route := responseRoute(key)
+
+	if s.client == nil {
+		// 先写入再登记索引：覆盖已有 key 时旧条目的淘汰回调会移除索引
+		s.local.SetWithTTL(key, data, ttl)
+		s.mu.Lock()
+		keys, ok := s.routes[route]
+		if !ok {
+			keys = make(map[string]struct{})
+			s.routes[route] = keys
+		}
+		keys[key] = struct{}{}
+		s.mu.Unlock()
+		return nil
+	}
+
+	index := s.prefix + "route:" + route
+	_, err = s.client.TxPipelined(ctx, func(pipe redisClient.Pipeliner) error {
+		pipe.Set(ctx, s.prefix+"resp:"+key, data, ttl)
+		pipe.SAdd(ctx, index, key)
+		pipe.Expire(ctx, index, ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set cached response %s: %w", key, err)
+	}
+	return nil
+}
+
+// InvalidateRoute 删除路由下的全部缓存响应（route 为缓存中间件使用的路由，如 /api/users/:id）
+func (s *ResponseStore) InvalidateRoute(ctx context.Context, route string) error {
+	if s.client == nil {
+		s.mu.Lock()
+		keys := s.routes[route]
+		delete(s.routes, route)
+		s.mu.Unlock()
+		// 在锁外删除：淘汰回调会再次获取锁
+		for key := range keys {
+			s.local.Delete(key)
+		}
+		return nil
+	}
+
+	index := s.prefix + "route:" + route
+	keys, err := s.client.SMembers(ctx, index).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list cached responses of route %s: %w", route, err)
+	}
+	fullKeys := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		fullKeys = append(fullKeys, s.prefix+"resp:"+key)
+	}
+	fullKeys = append(fullKeys, index)
+	if err := s.client.Del(ctx, fullKeys...).Err(); err != nil {
+		return fmt.Errorf("failed to invalidate route %s: %w", route, err)
+	}
+	return nil
+}
+
+// unindex 从路由索引中移除被淘汰的 key
+func (s *ResponseStore) unindex(key string) {
+	route := responseRoute(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if keys, ok := s.routes[route]; ok {
+		delete(keys, key)
+		if len(keys) == 0 {
+			delete(s.routes, route)
+		}
+	}
+}
+
+// responseRoute 从缓存 key 中解析路由
+func responseRoute(key string) string {
+	if i := strings.LastIndexByte(key, '#'); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	redisClient "github.com/redis/go-redis/v9"
+)
+
+func TestResponseStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	redisStore, err := NewRedisResponseStore(redisClient.NewClient(&redisClient.Options{Addr: server.Addr()}), "")
+	if err != nil {
+		t.Fatalf("NewRedisResponseStore failed: %v", err)
+	}
+	memoryStore, err := NewMemoryResponseStore(100, 0)
+	if err != nil {
+		t.Fatalf("NewMemoryResponseStore failed: %v", err)
+	}
+
+	for name, store := range map[string]*ResponseStore{"redis": redisStore, "memory": memoryStore} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			entry := &ResponseEntry{
+				Status:   200,
+				Header:   [][2]string{{"Content-Type", "application/json"}},
+				Body:     []byte(`{"id":1}`),
+				StoredAt: time.Now(),
+			}
+			first := ResponseKey("/users/:id", "a")
+			second := ResponseKey("/users/:id", "b")
+			other := ResponseKey("/orders", "a")
+			for _, key := range []string{first, second, other} {
+				if err := store.Set(ctx, key, entry, time.Minute); err != nil {
+					t.Fatalf("Set %s failed: %v", key, err)
+				}
+			}
+			// 覆盖写入不影响路由索引
+			if err := store.Set(ctx, first, entry, time.Minute); err != nil {
+				t.Fatalf("Set failed: %v", err)
+			}
+
+			got, err := store.Get(ctx, first)
+			if err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+			if got.Status != 200 || string(got.Body) != `{"id":1}` || got.Header[0][1] != "application/json" {
+				t.Fatalf("unexpected entry: %+v", got)
+			}
+
+			if err := store.InvalidateRoute(ctx, "/users/:id"); err != nil {
+				t.Fatalf("InvalidateRoute failed: %v", err)
+			}
+			for _, key := range []string{first, second} {
+				if _, err := store.Get(ctx, key); !errors.Is(err, ErrCacheMiss) {
+					t.Fatalf("expected miss for %s after invalidation, got %v", key, err)
+				}
+			}
+			if _, err := store.Get(ctx, other); err != nil {
+				t.Fatalf("other route should remain cached: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// Package callinfo 提供 gRPC 服务端调用信息（对端地址、客户端 IP、User-Agent、截止时间与选定的 metadata）
+//
+// 服务端注册 UnaryServerInterceptor / StreamServerInterceptor 后，业务代码通过 From 读取调用信息，
+// 调用信息同时作为结构化字段附加到经该 context 输出的日志：
+//
+//	info, ok := callinfo.From(ctx)
+//	if ok {
+//		logger.Info(ctx, "caller ip=%s", info.ClientIP)
+//	}
+package callinfo
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Config 调用信息提取配置
+type Config struct {
+	// 额外提取的 metadata 键（不区分大小写） 示例：[x-canary, x-app-version]
+	Metadata []string `json:"metadata" yaml:"metadata" toml:"metadata"`
+	// 信任的客户端 IP metadata 键，按顺序取第一个非空值（x-forwarded-for 取首个地址） 示例：[x-real-ip]
+	// 仅在调用方均为可信网关时配置，否则客户端可伪造 IP；为空时客户端 IP 取对端地址
+	ClientIPKeys []string `json:"clientIPKeys" yaml:"clientIPKeys" toml:"clientIPKeys"`
+	// 是否不将调用信息附加到日志字段
+	DisableLogFields bool `json:"disableLogFields" yaml:"disableLogFields" toml:"disableLogFields"`
+}
+
+// Info 服务端调用信息
+type Info struct {
+	// 完整方法名 示例：/user.UserService/GetUser
+	Method string
+	// 对端地址 示例：10.0.0.8:52341
+	PeerAddr string
+	// 客户端 IP（配置 ClientIPKeys 且 metadata 中存在时取其值，否则为对端 IP）
+	ClientIP string
+	// 调用方 User-Agent
+	UserAgent string
+	// 调用截止时间，零值表示调用方未设置截止时间
+	Deadline time.Time
+	// Config.Metadata 中选定且存在的 metadata（键为小写）
+	Metadata map[string]string
+}
+
+// HasDeadline 调用方是否设置了截止时间
+func (i *Info) HasDeadline() bool {
+	return !i.Deadline.IsZero()
+}
+
+// Fields 返回调用信息的结构化日志字段
+func (i *Info) Fields() map[string]interface{} {
+	fields := make(map[string]interface{}, 4+len(i.Metadata))
+	if i.PeerAddr != "" {
+		fields[logger.FieldRemoteAddr] = i.PeerAddr
+	}
+	if i.ClientIP != "" {
+		fields[logger.FieldClientIP] = i.ClientIP
+	}
+	if i.UserAgent != "" {
+		fields[logger.FieldUserAgent] = i.UserAgent
+	}
+	if i.HasDeadline() {
+		fields["deadline"] = i.Deadline.Format(time.RFC3339Nano)
+	}
+	for key, value := range i.Metadata {
+		fields["md_"+strings.ReplaceAll(key, "-", "_")] = value
+	}
+	return fields
+}
+
+type infoKey struct{}
+
+// NewContext 将调用信息写入 context
+func NewContext(ctx context.Context, info *Info) context.Context {
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+// From 从 context 中获取调用信息
+func From(ctx context.Context) (*Info, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	info, ok := ctx.Value(infoKey{}).(*Info)
+	return info, ok && info != nil
+}
+
+// Extract 从 gRPC 服务端 context（对端信息、incoming metadata、截止时间）提取调用信息
+func Extract(ctx context.Context, method string, config Config) *Info {
+	info := &Info{Method: method}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		info.PeerAddr = p.Addr.String()
+		info.ClientIP = hostIP(info.PeerAddr)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		info.Deadline = deadline
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return info
+	}
+	info.UserAgent = first(md, "user-agent")
+	for _, key := range config.ClientIPKeys {
+		if value := first(md, key); value != "" {
+			if ip, _, _ := strings.Cut(value, ","); strings.TrimSpace(ip) != "" {
+				info.ClientIP = strings.TrimSpace(ip)
+				break
+			}
+		}
+	}
+	for _, key := range config.Metadata {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if value := first(md, key); value != "" {
+			if info.Metadata == nil {
+				info.Metadata = make(map[string]string, len(config.Metadata))
+			}
+			info.Metadata[key] = value
+		}
+	}
+	return info
+}
+
+// UnaryServerInterceptor gRPC 一元调用信息拦截器
+func UnaryServerInterceptor(config Config) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(serverContext(ctx, info.FullMethod, config), req)
+	}
+}
+
+// StreamServerInterceptor gRPC 流式调用信息拦截器
+func StreamServerInterceptor(config Config) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := serverContext(ss.Context(), info.FullMethod, config)
+		return handler(srv, &infoServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// serverContext 提取调用信息写入 context，并按配置附加日志字段
+func serverContext(ctx context.Context, method string, config Config) context.Context {
+	info := Extract(ctx, method, config)
+	ctx = NewContext(ctx, info)
+	if !config.DisableLogFields {
+		ctx = logger.WithContextFields(ctx, info.Fields())
+	}
+	return ctx
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// hostIP 去掉地址中的端口（非 host:port 格式时原样返回）
+func hostIP(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// infoServerStream 替换 context 的 ServerStream
+type infoServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *infoServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,83 @@
+package callinfo
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func incomingContext(pairs ...string) context.Context {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.8"), Port: 52341}})
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(pairs...))
+}
+
+func TestUnaryServerInterceptorExtractsCallInfo(t *testing.T) {
+	ctx, cancel := context.WithTimeout(incomingContext("user-agent", "grpc-go/1.60", "x-canary", "v2", "x-secret", "s"), time.Minute)
+	defer cancel()
+
+	interceptor := UnaryServerInterceptor(Config{Metadata: []string{"X-Canary"}})
+	var got *Info
+	var fields map[string]interface{}
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/user.UserService/GetUser"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		got, _ = From(ctx)
+		fields = logger.GetContextFields(ctx)
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("interceptor failed: %v", err)
+	}
+	if got == nil {
+		t.Fatal("expected call info in context")
+	}
+	if got.Method != "/user.UserService/GetUser" || got.PeerAddr != "10.0.0.8:52341" || got.ClientIP != "10.0.0.8" {
+		t.Fatalf("unexpected peer info %+v", got)
+	}
+	if got.UserAgent != "grpc-go/1.60" || !got.HasDeadline() {
+		t.Fatalf("unexpected user agent or deadline %+v", got)
+	}
+	if len(got.Metadata) != 1 || got.Metadata["x-canary"] != "v2" {
+		t.Fatalf("expected only selected metadata, got %v", got.Metadata)
+	}
+	if fields[logger.FieldClientIP] != "10.0.0.8" || fields["md_x_canary"] != "v2" || fields["deadline"] == nil {
+		t.Fatalf("unexpected log fields %v", fields)
+	}
+}
+
+func TestExtractTrustedClientIP(t *testing.T) {
+	ctx := incomingContext("x-forwarded-for", "203.0.113.9, 10.0.0.1")
+	if info := Extract(ctx, "/m", Config{}); info.ClientIP != "10.0.0.8" || info.HasDeadline() {
+		t.Fatalf("expected peer ip without trusted keys, got %+v", info)
+	}
+	info := Extract(ctx, "/m", Config{ClientIPKeys: []string{"x-real-ip", "x-forwarded-for"}})
+	if info.ClientIP != "203.0.113.9" {
+		t.Fatalf("expected forwarded client ip, got %q", info.ClientIP)
+	}
+}
+
+func TestStreamServerInterceptorWithoutLogFields(t *testing.T) {
+	interceptor := StreamServerInterceptor(Config{DisableLogFields: true})
+	stream := &infoServerStream{ctx: incomingContext()}
+	err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/svc/Watch"}, func(srv interface{}, ss grpc.ServerStream) error {
+		info, ok := From(ss.Context())
+		if !ok || info.Method != "/svc/Watch" || info.ClientIP != "10.0.0.8" {
+			t.Fatalf("unexpected stream call info %+v", info)
+		}
+		if logger.GetContextFields(ss.Context()) != nil {
+			t.Fatal("expected log fields to be disabled")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("interceptor failed: %v", err)
+	}
+	if _, ok := From(context.Background()); ok {
+		t.Fatal("expected no call info in plain context")
+	}
+}
@@ -0,0 +1,51 @@
+// depmap 合并各服务导出的依赖图快照（JSON），输出完整的服务依赖图
+//
+// 用法：
+//
+//	depmap gateway.json user-service.json order-service.json > deps.json
+//	depmap -format dot exports/*.json | dot -Tsvg -o deps.svg
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/team-dandelion/quickgo/depmap"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	format := flag.String("format", "json", "输出格式：json、dot")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: depmap [-format json|dot] <snapshot.json>...")
+		return 2
+	}
+
+	snapshots := make([]depmap.Snapshot, 0, flag.NArg())
+	for _, path := range flag.Args() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "read snapshot: %v\n", err)
+			return 2
+		}
+		var snapshot depmap.Snapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			fmt.Fprintf(os.Stderr, "parse snapshot %s: %v\n", path, err)
+			return 2
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if err := depmap.Merge(snapshots...).Encode(os.Stdout, depmap.Format(*format)); err != nil {
+		fmt.Fprintf(os.Stderr, "write dependency map: %v\n", err)
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strconv"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const (
+	contextPackage   = protogen.GoImportPath("context")
+	grpcPackage      = protogen.GoImportPath("google.golang.org/grpc")
+	rpcclientPackage = protogen.GoImportPath("github.com/team-dandelion/quickgo/rpcclient")
+)
+
+// generateFile 为文件中的服务生成类型化客户端，文件不含服务时不生成
+func generateFile(gen *protogen.Plugin, file *protogen.File) *protogen.GeneratedFile {
+	if len(file.Services) == 0 {
+		return nil
+	}
+
+	filename := file.GeneratedFilenamePrefix + "_quickgo_client.pb.go"
+	g := gen.NewGeneratedFile(filename, file.GoImportPath)
+	g.P("// Code generated by protoc-gen-quickgo-client. DO NOT EDIT.")
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("package ", file.GoPackageName)
+	g.P()
+	for _, service := range file.Services {
+		generateService(g, service)
+	}
+	return g
+}
+
+func generateService(g *protogen.GeneratedFile, service *protogen.Service) {
+	clientName := service.GoName + "QuickClient"
+
+	g.P("// ", clientName, " ", service.GoName, " 的类型化客户端（内置超时、重试、熔断与 gerr 错误转换）")
+	g.P("type ", clientName, " struct {")
+	g.P("client *", rpcclientPackage.Ident("Client"))
+	g.P("}")
+	g.P()
+
+	methods, quoted := unaryMethods(service)
+
+	g.P("// New", clientName, " 创建 ", service.GoName, " 类型化客户端")
+	g.P("// serviceName 为 GrpcClientManager 中注册的服务名，opts 覆盖默认调用策略")
+	g.P("// 仅 proto 中声明 idempotency_level 或通过 rpcclient.WithIdempotency 分类为幂等的方法会自动重试")
+	g.P("func New", clientName, "(provider ", rpcclientPackage.Ident("ConnProvider"), ", serviceName string, opts ...", rpcclientPackage.Ident("Option"), ") *", clientName, " {")
+	g.P("opts = append([]", rpcclientPackage.Ident("Option"), "{", rpcclientPackage.Ident("WithMethods"), "(", quoted, ")}, opts...)")
+	g.P("return &", clientName, "{client: ", rpcclientPackage.Ident("New"), "(provider, serviceName, opts...)}")
+	g.P("}")
+	g.P()
+
+	for _, method := range methods {
+		fullMethod := fullMethodName(service, method)
+		if method.Comments.Leading != "" {
+			g.P(method.Comments.Leading, "//")
+		} else {
+			g.P("// ", method.GoName, " 调用 ", fullMethod)
+			g.P("//")
+		}
+		g.P("// 错误均为 *gerr.GErr；CommonResp 业务失败时同时返回响应与业务错误")
+		g.P("func (c *", clientName, ") ", method.GoName, "(ctx ", contextPackage.Ident("Context"), ", req *", method.Input.GoIdent, ", opts ...", grpcPackage.Ident("CallOption"), ") (*", method.Output.GoIdent, ", error) {")
+		g.P("return ", rpcclientPackage.Ident("Call"), "(ctx, c.client, ", strconv.Quote(fullMethod), ", func(ctx ", contextPackage.Ident("Context"), ", conn ", grpcPackage.Ident("ClientConnInterface"), ") (*", method.Output.GoIdent, ", error) {")
+		g.P("return New", service.GoName, "Client(conn).", method.GoName, "(ctx, req, opts...)")
+		g.P("})")
+		g.P("}")
+		g.P()
+	}
+}
+
+// unaryMethods 返回服务的一元方法及其带引号、逗号分隔的完整方法名列表（流式方法请使用原始存根）
+func unaryMethods(service *protogen.Service) ([]*protogen.Method, string) {
+	methods := make([]*protogen.Method, 0, len(service.Methods))
+	quoted := ""
+	for _, method := range service.Methods {
+		if method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer() {
+			continue
+		}
+		if len(methods) > 0 {
+			quoted += ", "
+		}
+		quoted += strconv.Quote(fullMethodName(service, method))
+		methods = append(methods, method)
+	}
+	return methods, quoted
+}
+
+// fullMethodName 返回 gRPC 完整方法名（如 /auth.AuthService/Login）
+func fullMethodName(service *protogen.Service, method *protogen.Method) string {
+	return "/" + string(service.Desc.FullName()) + "/" + string(method.Desc.Name())
+}
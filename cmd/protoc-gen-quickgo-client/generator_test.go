@@ -0,0 +1,130 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestGenerateFileEmitsTypedUnaryMethods(t *testing.T) {
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("auth.proto"),
+		Package: proto.String("auth"),
+		Syntax:  proto.String("proto3"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/gen/auth;auth")},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("LoginRequest")},
+			{Name: proto.String("LoginResponse")},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("AuthService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("Login"), InputType: proto.String(".auth.LoginRequest"), OutputType: proto.String(".auth.LoginResponse")},
+				{Name: proto.String("Watch"), InputType: proto.String(".auth.LoginRequest"), OutputType: proto.String(".auth.LoginResponse"), ServerStreaming: proto.Bool(true)},
+			},
+		}},
+	}
+	gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"auth.proto"},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{file},
+	})
+	if err != nil {
+		t.Fatalf("protogen.New failed: %v", err)
+	}
+	for _, f := range gen.Files {
+		if f.Generate {
+			generateFile(gen, f)
+		}
+	}
+
+	resp := gen.Response()
+	if resp.Error != nil {
+		t.Fatalf("generation failed: %s", resp.GetError())
+	}
+	if len(resp.File) != 1 || resp.File[0].GetName() != "example.com/gen/auth/auth_quickgo_client.pb.go" {
+		t.Fatalf("unexpected generated files: %v", resp.File)
+	}
+	content := resp.File[0].GetContent()
+	if _, err := parser.ParseFile(token.NewFileSet(), "auth_quickgo_client.pb.go", content, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, content)
+	}
+	for _, want := range []string{
+		"func NewAuthServiceQuickClient(provider rpcclient.ConnProvider, serviceName string, opts ...rpcclient.Option) *AuthServiceQuickClient",
+		"func (c *AuthServiceQuickClient) Login(ctx context.Context, req *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)",
+		`rpcclient.Call(ctx, c.client, "/auth.AuthService/Login"`,
+		`rpcclient.WithMethods("/auth.AuthService/Login")`,
+		"return NewAuthServiceClient(conn).Login(ctx, req, opts...)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("generated code missing %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "Watch(") {
+		t.Fatalf("expected streaming methods to be skipped:\n%s", content)
+	}
+}
+
+func TestGenerateSDKWrapsStubsInSeparatePackage(t *testing.T) {
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("auth.proto"),
+		Package: proto.String("auth"),
+		Syntax:  proto.String("proto3"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/gen/auth;auth")},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("LoginRequest")},
+			{Name: proto.String("LoginResponse")},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("AuthService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("Login"), InputType: proto.String(".auth.LoginRequest"), OutputType: proto.String(".auth.LoginResponse")},
+			},
+		}},
+	}
+	gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"auth.proto"},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{file},
+	})
+	if err != nil {
+		t.Fatalf("protogen.New failed: %v", err)
+	}
+	var files []*protogen.File
+	for _, f := range gen.Files {
+		if f.Generate {
+			files = append(files, f)
+		}
+	}
+	generateSDK(gen, files, parseSDKPackage("example.com/gen/authsdk", "auth-server"))
+
+	resp := gen.Response()
+	if resp.Error != nil {
+		t.Fatalf("generation failed: %s", resp.GetError())
+	}
+	if len(resp.File) != 1 || resp.File[0].GetName() != "example.com/gen/authsdk/auth_quickgo_sdk.pb.go" {
+		t.Fatalf("unexpected generated files: %v", resp.File)
+	}
+	content := resp.File[0].GetContent()
+	if _, err := parser.ParseFile(token.NewFileSet(), "auth_quickgo_sdk.pb.go", content, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, content)
+	}
+	for _, want := range []string{
+		"package authsdk",
+		`auth "example.com/gen/auth"`,
+		"func NewAuthServiceClient(provider rpcclient.ConnProvider, opts ...rpcclient.Option) *AuthServiceClient",
+		`rpcclient.New(provider, "auth-server", opts...)`,
+		"func (c *AuthServiceClient) Login(ctx context.Context, req *auth.LoginRequest, opts ...grpc.CallOption) (*auth.LoginResponse, error)",
+		"return auth.NewAuthServiceClient(conn).Login(ctx, req, opts...)",
+		"type Client = AuthServiceClient",
+		"func NewClient(provider rpcclient.ConnProvider, opts ...rpcclient.Option) *Client",
+	} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("generated code missing %q:\n%s", want, content)
+		}
+	}
+}
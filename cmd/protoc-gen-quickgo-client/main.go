@@ -0,0 +1,47 @@
+// protoc-gen-quickgo-client 为 proto 中的服务生成类型化客户端（*_quickgo_client.pb.go）
+//
+// 生成的客户端与 protoc-gen-go-grpc 的存根位于同一 Go 包，内置服务连接查找、默认超时、
+// 重试与熔断，并将错误统一转换为 gerr（见 rpcclient 包）。仅生成一元方法，流式方法请使用原始存根。
+//
+// 用法：
+//
+//	go install github.com/team-dandelion/quickgo/cmd/protoc-gen-quickgo-client
+//	protoc --go_out=. --go-grpc_out=. --quickgo-client_out=. auth.proto
+//
+// 设置 sdk_package 参数时额外在独立的 SDK 包中生成客户端（*_quickgo_sdk.pb.go，按导入路径输出），
+// 内置 GrpcClientManager 中的服务名（service_name 参数，默认为 proto 包名），调用方无需了解存根与服务名：
+//
+//	//go:generate protoc --go_out=. --go-grpc_out=. --quickgo-client_out=. --quickgo-client_opt=module=example.com/gen,sdk_package=example.com/gen/authsdk,service_name=auth-server auth.proto
+//
+//	client := authsdk.NewClient(app.GrpcClientManager(), rpcclient.WithBearerToken(tokens))
+//	resp, err := client.Login(ctx, &auth.LoginRequest{Username: "alice"})
+package main
+
+import (
+	"flag"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func main() {
+	var flags flag.FlagSet
+	sdkPackage := flags.String("sdk_package", "", "import path of the generated SDK package (path or path;name)")
+	serviceName := flags.String("service_name", "", "service name registered in GrpcClientManager (default: proto package)")
+
+	protogen.Options{ParamFunc: flags.Set}.Run(func(gen *protogen.Plugin) error {
+		gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+		var files []*protogen.File
+		for _, file := range gen.Files {
+			if !file.Generate {
+				continue
+			}
+			generateFile(gen, file)
+			files = append(files, file)
+		}
+		if config := parseSDKPackage(*sdkPackage, *serviceName); config != nil {
+			generateSDK(gen, files, config)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"path"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// sdkConfig 独立 SDK 包的生成参数
+type sdkConfig struct {
+	// SDK 包导入路径
+	importPath protogen.GoImportPath
+	// SDK 包名
+	packageName protogen.GoPackageName
+	// GrpcClientManager 中注册的服务名（为空时使用 proto 包名）
+	serviceName string
+}
+
+// parseSDKPackage 解析 sdk_package 参数（"导入路径" 或 "导入路径;包名"）
+func parseSDKPackage(value, serviceName string) *sdkConfig {
+	if value == "" {
+		return nil
+	}
+	importPath, name, ok := strings.Cut(value, ";")
+	if !ok {
+		name = path.Base(importPath)
+	}
+	return &sdkConfig{
+		importPath:  protogen.GoImportPath(importPath),
+		packageName: protogen.GoPackageName(name),
+		serviceName: serviceName,
+	}
+}
+
+// generateSDK 在独立 SDK 包中为文件中的服务生成客户端，文件按 SDK 导入路径输出（可配合 module= 参数）
+// SDK 包只包含一个服务时额外生成 Client 与 NewClient 别名
+func generateSDK(gen *protogen.Plugin, files []*protogen.File, config *sdkConfig) {
+	var services int
+	for _, file := range files {
+		services += len(file.Services)
+	}
+
+	for _, file := range files {
+		if len(file.Services) == 0 {
+			continue
+		}
+		filename := path.Join(string(config.importPath), path.Base(file.GeneratedFilenamePrefix)) + "_quickgo_sdk.pb.go"
+		g := gen.NewGeneratedFile(filename, config.importPath)
+		g.P("// Code generated by protoc-gen-quickgo-client. DO NOT EDIT.")
+		g.P("// source: ", file.Desc.Path())
+		g.P()
+		g.P("package ", config.packageName)
+		g.P()
+
+		serviceName := config.serviceName
+		if serviceName == "" {
+			serviceName = string(file.Desc.Package())
+		}
+		for _, service := range file.Services {
+			generateSDKService(g, file, service, serviceName)
+			if services == 1 {
+				generateSDKAlias(g, service)
+			}
+		}
+	}
+}
+
+func generateSDKService(g *protogen.GeneratedFile, file *protogen.File, service *protogen.Service, serviceName string) {
+	clientName := service.GoName + "Client"
+	fullName := string(service.Desc.FullName())
+
+	g.P("// ", clientName, " ", fullName, " 的 SDK 客户端（服务发现、超时、重试、熔断与 gerr 错误转换由 rpcclient 提供）")
+	g.P("type ", clientName, " struct {")
+	g.P("client *", rpcclientPackage.Ident("Client"))
+	g.P("}")
+	g.P()
+
+	methods, quoted := unaryMethods(service)
+	g.P("// New", clientName, " 创建 ", fullName, " 客户端，provider 通常为 app.GrpcClientManager()")
+	g.P("// 默认调用 GrpcClientManager 中的 ", strconv.Quote(serviceName), " 服务（rpcclient.WithServiceName 覆盖），")
+	g.P("// 认证令牌等调用元数据通过 rpcclient.WithBearerToken / rpcclient.WithMetadata 注入")
+	g.P("func New", clientName, "(provider ", rpcclientPackage.Ident("ConnProvider"), ", opts ...", rpcclientPackage.Ident("Option"), ") *", clientName, " {")
+	g.P("opts = append([]", rpcclientPackage.Ident("Option"), "{", rpcclientPackage.Ident("WithMethods"), "(", quoted, ")}, opts...)")
+	g.P("return &", clientName, "{client: ", rpcclientPackage.Ident("New"), "(provider, ", strconv.Quote(serviceName), ", opts...)}")
+	g.P("}")
+	g.P()
+
+	stub := file.GoImportPath.Ident("New" + service.GoName + "Client")
+	for _, method := range methods {
+		fullMethod := fullMethodName(service, method)
+		if method.Comments.Leading != "" {
+			g.P(method.Comments.Leading, "//")
+		} else {
+			g.P("// ", method.GoName, " 调用 ", fullMethod)
+			g.P("//")
+		}
+		g.P("// 错误均为 *gerr.GErr；CommonResp 业务失败时同时返回响应与业务错误")
+		g.P("func (c *", clientName, ") ", method.GoName, "(ctx ", contextPackage.Ident("Context"), ", req *", method.Input.GoIdent, ", opts ...", grpcPackage.Ident("CallOption"), ") (*", method.Output.GoIdent, ", error) {")
+		g.P("return ", rpcclientPackage.Ident("Call"), "(ctx, c.client, ", strconv.Quote(fullMethod), ", func(ctx ", contextPackage.Ident("Context"), ", conn ", grpcPackage.Ident("ClientConnInterface"), ") (*", method.Output.GoIdent, ", error) {")
+		g.P("return ", stub, "(conn).", method.GoName, "(ctx, req, opts...)")
+		g.P("})")
+		g.P("}")
+		g.P()
+	}
+}
+
+// generateSDKAlias 为单服务 SDK 生成 Client / NewClient 别名
+func generateSDKAlias(g *protogen.GeneratedFile, service *protogen.Service) {
+	clientName := service.GoName + "Client"
+	g.P("// Client SDK 包唯一服务的客户端")
+	g.P("type Client = ", clientName)
+	g.P()
+	g.P("// NewClient 创建 ", clientName, "（见 New", clientName, "）")
+	g.P("func NewClient(provider ", rpcclientPackage.Ident("ConnProvider"), ", opts ...", rpcclientPackage.Ident("Option"), ") *Client {")
+	g.P("return New", clientName, "(provider, opts...)")
+	g.P("}")
+	g.P()
+}
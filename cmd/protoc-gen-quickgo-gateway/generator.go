@@ -0,0 +1,94 @@
+package main
+
+import (
+	"strconv"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const (
+	contextPackage = protogen.GoImportPath("context")
+	fiberPackage   = protogen.GoImportPath("github.com/gofiber/fiber/v2")
+	grpcepPackage  = protogen.GoImportPath("github.com/team-dandelion/quickgo/grpcep")
+)
+
+// generateFile 为文件中的服务生成类型化网关处理器，文件不含服务时不生成
+func generateFile(gen *protogen.Plugin, file *protogen.File) *protogen.GeneratedFile {
+	if len(file.Services) == 0 {
+		return nil
+	}
+
+	filename := file.GeneratedFilenamePrefix + "_quickgo_gateway.pb.go"
+	g := gen.NewGeneratedFile(filename, file.GoImportPath)
+	g.P("// Code generated by protoc-gen-quickgo-gateway. DO NOT EDIT.")
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("package ", file.GoPackageName)
+	g.P()
+	for _, service := range file.Services {
+		generateService(g, service)
+	}
+	return g
+}
+
+func generateService(g *protogen.GeneratedFile, service *protogen.Service) {
+	gatewayName := service.GoName + "Gateway"
+
+	methods := make([]*protogen.Method, 0, len(service.Methods))
+	for _, method := range service.Methods {
+		if method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer() {
+			continue
+		}
+		methods = append(methods, method)
+	}
+
+	g.P("// ", gatewayName, " ", service.GoName, " 的 HTTP 网关处理器，每个一元方法对应一个类型化 fiber 处理器")
+	g.P("type ", gatewayName, " struct {")
+	g.P(grpcepPackage.Ident("BaseHandler"))
+	g.P("resolve ", grpcepPackage.Ident("TunnelResolver"))
+	g.P("serviceName string")
+	g.P("}")
+	g.P()
+
+	g.P("// New", gatewayName, " 创建 ", service.GoName, " 网关处理器")
+	g.P("// resolve 按服务名获取后端连接（如 GrpcClientManager.TunnelResolver()），serviceName 为 GrpcClientManager 中注册的服务名")
+	g.P("func New", gatewayName, "(resolve ", grpcepPackage.Ident("TunnelResolver"), ", serviceName string) *", gatewayName, " {")
+	g.P("return &", gatewayName, "{resolve: resolve, serviceName: serviceName}")
+	g.P("}")
+	g.P()
+
+	g.P("// Register 以 POST /", service.Desc.FullName(), "/{Method} 注册全部一元方法")
+	g.P("// 需要鉴权等中间件时传入已挂载中间件的路由组，自定义路径时直接注册单个方法处理器")
+	g.P("func (g *", gatewayName, ") Register(router ", fiberPackage.Ident("Router"), ") {")
+	for _, method := range methods {
+		g.P("router.Post(", strconv.Quote(fullMethodName(service, method)), ", g.", method.GoName, ")")
+	}
+	g.P("}")
+	g.P()
+
+	for _, method := range methods {
+		fullMethod := fullMethodName(service, method)
+		if method.Comments.Leading != "" {
+			g.P(method.Comments.Leading, "//")
+		} else {
+			g.P("// ", method.GoName, " 调用 ", fullMethod)
+			g.P("//")
+		}
+		g.P("// 请求体绑定为 ", method.Input.GoIdent.GoName, " 并校验，响应按 ResponseDecorator 输出")
+		g.P("func (g *", gatewayName, ") ", method.GoName, "(c *", fiberPackage.Ident("Ctx"), ") error {")
+		g.P("return ", grpcepPackage.Ident("Invoke"), "(&g.BaseHandler, c, new(", method.Input.GoIdent, "), func(ctx ", contextPackage.Ident("Context"), ", req *", method.Input.GoIdent, ") (*", method.Output.GoIdent, ", error) {")
+		g.P("conn, err := g.resolve(ctx, g.serviceName)")
+		g.P("if err != nil {")
+		g.P("return nil, err")
+		g.P("}")
+		g.P("return New", service.GoName, "Client(conn).", method.GoName, "(ctx, req)")
+		g.P("})")
+		g.P("}")
+		g.P()
+	}
+}
+
+// fullMethodName 返回 gRPC 完整方法名（如 /auth.AuthService/Login）
+func fullMethodName(service *protogen.Service, method *protogen.Method) string {
+	return "/" + string(service.Desc.FullName()) + "/" + string(method.Desc.Name())
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestGenerateFileEmitsTypedGatewayHandlers(t *testing.T) {
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("auth.proto"),
+		Package: proto.String("auth"),
+		Syntax:  proto.String("proto3"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/gen/auth;auth")},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("LoginRequest")},
+			{Name: proto.String("LoginResponse")},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("AuthService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("Login"), InputType: proto.String(".auth.LoginRequest"), OutputType: proto.String(".auth.LoginResponse")},
+				{Name: proto.String("Watch"), InputType: proto.String(".auth.LoginRequest"), OutputType: proto.String(".auth.LoginResponse"), ServerStreaming: proto.Bool(true)},
+			},
+		}},
+	}
+	gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"auth.proto"},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{file},
+	})
+	if err != nil {
+		t.Fatalf("protogen.New failed: %v", err)
+	}
+	for _, f := range gen.Files {
+		if f.Generate {
+			generateFile(gen, f)
+		}
+	}
+
+	resp := gen.Response()
+	if resp.Error != nil {
+		t.Fatalf("generation failed: %s", resp.GetError())
+	}
+	if len(resp.File) != 1 || resp.File[0].GetName() != "example.com/gen/auth/auth_quickgo_gateway.pb.go" {
+		t.Fatalf("unexpected generated files: %v", resp.File)
+	}
+	content := resp.File[0].GetContent()
+	if _, err := parser.ParseFile(token.NewFileSet(), "auth_quickgo_gateway.pb.go", content, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, content)
+	}
+	for _, want := range []string{
+		"func NewAuthServiceGateway(resolve grpcep.TunnelResolver, serviceName string) *AuthServiceGateway",
+		"func (g *AuthServiceGateway) Register(router v2.Router)",
+		`router.Post("/auth.AuthService/Login", g.Login)`,
+		"func (g *AuthServiceGateway) Login(c *v2.Ctx) error",
+		"grpcep.Invoke(&g.BaseHandler, c, new(LoginRequest), func(ctx context.Context, req *LoginRequest) (*LoginResponse, error)",
+		"return NewAuthServiceClient(conn).Login(ctx, req)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("generated code missing %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "Watch") {
+		t.Fatalf("expected streaming methods to be skipped:\n%s", content)
+	}
+	if strings.Contains(content, "reflect") {
+		t.Fatalf("expected generated handlers to avoid reflection:\n%s", content)
+	}
+}
@@ -0,0 +1,29 @@
+// protoc-gen-quickgo-gateway 为 proto 中的服务生成类型化 HTTP 网关处理器（*_quickgo_gateway.pb.go）
+//
+// 生成的处理器与 protoc-gen-go-grpc 的存根位于同一 Go 包，每个一元方法对应一个 fiber 处理器，
+// 请求绑定与校验、错误转换与 ResponseDecorator 响应格式与 BaseHandler.GRPCCall 一致，
+// 但直接调用类型化存根，调用路径上没有反射（见 grpcep.Invoke）。仅生成一元方法，流式方法请使用 RPCStream。
+//
+// 用法：
+//
+//	go install github.com/team-dandelion/quickgo/cmd/protoc-gen-quickgo-gateway
+//	protoc --go_out=. --go-grpc_out=. --quickgo-gateway_out=. auth.proto
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func main() {
+	protogen.Options{}.Run(func(gen *protogen.Plugin) error {
+		gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+		for _, file := range gen.Files {
+			if !file.Generate {
+				continue
+			}
+			generateFile(gen, file)
+		}
+		return nil
+	})
+}
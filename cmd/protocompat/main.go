@@ -0,0 +1,116 @@
+// protocompat 在 CI 中检查 protobuf 描述符是否与已发布基线兼容
+//
+// 用法：
+//
+//	buf build -o current.binpb   # 或 protoc --include_imports --descriptor_set_out=current.binpb ...
+//	protocompat -current current.binpb -baseline api/baseline.binpb
+//	protocompat -current current.binpb -etcd 127.0.0.1:2379 -name auth-server
+//	protocompat -current current.binpb -etcd 127.0.0.1:2379 -name auth-server -update   # 发布成功后更新基线
+//
+// 存在破坏性变更时以退出码 1 结束
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/team-dandelion/quickgo/protocompat"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	var (
+		current      = flag.String("current", "", "当前描述符集文件（必填）")
+		baseline     = flag.String("baseline", "", "基线描述符集文件")
+		etcd         = flag.String("etcd", "", "etcd 端点，逗号分隔（与 -baseline 二选一）")
+		etcdPrefix   = flag.String("etcd-prefix", protocompat.DefaultEtcdPrefix, "etcd 键前缀")
+		etcdUser     = flag.String("etcd-user", "", "etcd 用户名")
+		etcdPassword = flag.String("etcd-password", "", "etcd 密码")
+		name         = flag.String("name", "default", "基线名称（etcd 模式下作为键名）")
+		update       = flag.Bool("update", false, "检查通过后将当前描述符集写入基线")
+		force        = flag.Bool("force", false, "忽略破坏性变更，强制更新基线（需配合 -update）")
+		timeout      = flag.Duration("timeout", 10*time.Second, "etcd 操作超时")
+	)
+	flag.Parse()
+
+	if *current == "" || (*baseline == "") == (*etcd == "") {
+		fmt.Fprintln(os.Stderr, "usage: protocompat -current <file> (-baseline <file> | -etcd <endpoints> -name <name>) [-update]")
+		return 2
+	}
+
+	set, err := protocompat.LoadFile(*current)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load current descriptor set: %v\n", err)
+		return 2
+	}
+
+	var store protocompat.Store
+	key := *name
+	if *baseline != "" {
+		store = &singleFileStore{path: *baseline}
+	} else {
+		etcdStore, err := protocompat.NewEtcdStore(protocompat.EtcdConfig{
+			Endpoints: strings.Split(*etcd, ","),
+			Prefix:    *etcdPrefix,
+			Username:  *etcdUser,
+			Password:  *etcdPassword,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "connect etcd: %v\n", err)
+			return 2
+		}
+		defer etcdStore.Close()
+		store = etcdStore
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	report, err := protocompat.Check(ctx, store, key, set)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "check compatibility: %v\n", err)
+		return 2
+	}
+	fmt.Println(report.String())
+
+	breaking := report.Breaking()
+	if breaking && !(*update && *force) {
+		fmt.Fprintf(os.Stderr, "found %d breaking change(s)\n", len(report.BreakingChanges()))
+		return 1
+	}
+	if *update {
+		if err := store.Save(ctx, key, set); err != nil {
+			fmt.Fprintf(os.Stderr, "update baseline: %v\n", err)
+			return 2
+		}
+		fmt.Println("baseline updated")
+	}
+	return 0
+}
+
+// singleFileStore 直接读写 -baseline 指定的文件
+type singleFileStore struct {
+	path string
+}
+
+func (s *singleFileStore) Load(ctx context.Context, name string) (*descriptorpb.FileDescriptorSet, error) {
+	set, err := protocompat.LoadFile(s.path)
+	if errors.Is(err, protocompat.ErrBaselineNotFound) {
+		fmt.Fprintf(os.Stderr, "baseline %s not found, skipping check\n", s.path)
+	}
+	return set, err
+}
+
+func (s *singleFileStore) Save(ctx context.Context, name string, set *descriptorpb.FileDescriptorSet) error {
+	return protocompat.SaveFile(s.path, set)
+}
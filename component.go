@@ -0,0 +1,206 @@
+package quickgo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 内置组件名称，自定义组件可以声明依赖这些名称
+// 内置组件总是先于自定义组件初始化、启动，并在其之后停止，依赖仅校验对应配置已启用
+const (
+	ComponentTracing    = "tracing"
+	ComponentLogger     = "logger"
+	ComponentMetrics    = "metrics"
+	ComponentGrpcServer = "grpc-server"
+	ComponentGrpcClient = "grpc-client"
+	ComponentHTTPServer = "http-server"
+	ComponentGorm       = "gorm"
+	ComponentMongoDB    = "mongodb"
+	ComponentRedis      = "redis"
+	ComponentMQ         = "mq"
+)
+
+// ComponentDependencies 可选接口：组件实现 DependsOn 声明依赖的组件名称
+// 依赖的组件先于该组件 Init/Start，并在其之后 Stop
+type ComponentDependencies interface {
+	DependsOn() []string
+}
+
+// ComponentOption 组件注册选项
+type ComponentOption func(*componentOptions)
+
+type componentOptions struct {
+	dependsOn []string
+}
+
+// WithDependsOn 声明组件依赖（与组件实现的 DependsOn 合并）
+func WithDependsOn(names ...string) ComponentOption {
+	return func(o *componentOptions) {
+		o.dependsOn = append(o.dependsOn, names...)
+	}
+}
+
+// builtinComponentEnabled 返回内置组件是否已配置，name 不是内置组件时 ok 为 false
+func (c *FrameworkConfig) builtinComponentEnabled(name string) (enabled bool, ok bool) {
+	switch name {
+	case ComponentTracing:
+		return c.Tracing != nil, true
+	case ComponentLogger:
+		return true, true
+	case ComponentMetrics:
+		return c.Metrics != nil, true
+	case ComponentGrpcServer:
+		return c.GrpcServer != nil, true
+	case ComponentGrpcClient:
+		return c.GrpcClient != nil, true
+	case ComponentHTTPServer:
+		return c.HTTPServer != nil || len(c.HTTPServers) > 0, true
+	case ComponentGorm:
+		return c.Gorm != nil, true
+	case ComponentMongoDB:
+		return c.MongoDB != nil, true
+	case ComponentRedis:
+		return c.Redis != nil, true
+	case ComponentMQ:
+		return c.MQ != nil, true
+	}
+	return false, false
+}
+
+// componentDependencies 返回组件声明的依赖（注册选项与 DependsOn 合并去重）
+func (f *Framework) componentDependencies(name string, component Component) []string {
+	var deps []string
+	seen := make(map[string]bool)
+	add := func(names []string) {
+		for _, dep := range names {
+			if dep = strings.TrimSpace(dep); dep != "" && !seen[dep] {
+				seen[dep] = true
+				deps = append(deps, dep)
+			}
+		}
+	}
+	add(f.componentDeps[name])
+	if declared, ok := component.(ComponentDependencies); ok {
+		add(declared.DependsOn())
+	}
+	return deps
+}
+
+// orderComponents 按依赖对已启用的自定义组件拓扑排序，无依赖关系的组件保持注册顺序
+// 依赖不存在、未启用或存在循环依赖时返回错误
+func (f *Framework) orderComponents(entries []componentEntry) ([]componentEntry, error) {
+	index := make(map[string]int, len(entries))
+	for i, entry := range entries {
+		index[entry.name] = i
+	}
+
+	deps := make([][]int, len(entries))
+	var errs []error
+	for i, entry := range entries {
+		if !entry.component.IsEnabled() {
+			continue
+		}
+		for _, dep := range f.componentDependencies(entry.name, entry.component) {
+			if dep == entry.name {
+				errs = append(errs, fmt.Errorf("component %s depends on itself", entry.name))
+				continue
+			}
+			if j, ok := index[dep]; ok {
+				if !entries[j].component.IsEnabled() {
+					errs = append(errs, fmt.Errorf("component %s depends on disabled component %s", entry.name, dep))
+					continue
+				}
+				deps[i] = append(deps[i], j)
+				continue
+			}
+			if enabled, ok := f.config.builtinComponentEnabled(dep); ok {
+				if !enabled {
+					errs = append(errs, fmt.Errorf("component %s depends on %s which is not configured", entry.name, dep))
+				}
+				continue
+			}
+			errs = append(errs, fmt.Errorf("component %s depends on unknown component %s", entry.name, dep))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	// 每轮选择注册顺序最靠前的、依赖均已排好的组件
+	ordered := make([]componentEntry, 0, len(entries))
+	placed := make([]bool, len(entries))
+	for len(ordered) < len(entries) {
+		progressed := false
+		for i, entry := range entries {
+			if placed[i] || !dependenciesPlaced(deps[i], placed) {
+				continue
+			}
+			placed[i] = true
+			ordered = append(ordered, entry)
+			progressed = true
+			break
+		}
+		if !progressed {
+			return nil, fmt.Errorf("component dependency cycle: %s", componentCycle(entries, deps, placed))
+		}
+	}
+	return ordered, nil
+}
+
+func dependenciesPlaced(deps []int, placed []bool) bool {
+	for _, dep := range deps {
+		if !placed[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// componentCycle 在未排好的组件中查找一个依赖环，返回形如 a -> b -> a 的描述
+func componentCycle(entries []componentEntry, deps [][]int, placed []bool) string {
+	start := -1
+	for i := range entries {
+		if !placed[i] {
+			start = i
+			break
+		}
+	}
+	// 未排好的组件都至少有一个未排好的依赖，沿依赖前进必然回到已访问的组件
+	visited := make(map[int]int)
+	var path []int
+	for current := start; ; {
+		if pos, ok := visited[current]; ok {
+			path = append(path[pos:], current)
+			break
+		}
+		visited[current] = len(path)
+		path = append(path, current)
+		for _, dep := range deps[current] {
+			if !placed[dep] {
+				current = dep
+				break
+			}
+		}
+	}
+	names := make([]string, len(path))
+	for i, idx := range path {
+		names[i] = entries[idx].name
+	}
+	return strings.Join(names, " -> ")
+}
+
+// ComponentOrder 返回自定义组件按依赖排序后的初始化顺序（已启用的组件），依赖无效时返回错误
+func (f *Framework) ComponentOrder() ([]string, error) {
+	ordered, err := f.orderComponents(f.componentsSnapshot())
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(ordered))
+	for _, entry := range ordered {
+		if entry.component.IsEnabled() {
+			names = append(names, entry.name)
+		}
+	}
+	return names, nil
+}
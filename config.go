@@ -1,6 +1,7 @@
 package quickgo
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
@@ -8,8 +9,11 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
+
+	"github.com/team-dandelion/quickgo/secrets"
 )
 
 // 支持的配置后缀
@@ -43,6 +47,13 @@ type ConfigLoader struct {
 	configName   string
 	configFormat string
 	viper        *viper.Viper
+
+	watchMu  sync.Mutex
+	watchers []func()
+	watching bool
+
+	secretMu       sync.RWMutex
+	secretProvider secrets.Provider
 }
 
 // NewConfigLoader 创建配置加载器
@@ -99,8 +110,32 @@ func NewConfigLoader(env string, configPath ...string) (*ConfigLoader, error) {
 	return loader, nil
 }
 
+// NewConfigLoaderFromBytes 从内存内容创建配置加载器（用于测试与嵌入式配置，不读取配置文件，Watch 不会触发）
+// format: 配置格式（json, yaml, toml, ini）
+func NewConfigLoaderFromBytes(env, format string, content []byte) (*ConfigLoader, error) {
+	if !isValidEnv(env) {
+		return nil, fmt.Errorf("unsupported environment: %s, supported: %v", env, []string{EnvLocal, EnvDevelop, EnvRelease, EnvProduction})
+	}
+	if !contains(supportedFormats, format) {
+		return nil, fmt.Errorf("unsupported config format: %s, supported: %v", format, supportedFormats)
+	}
+
+	loader := &ConfigLoader{
+		env:          env,
+		configName:   fmt.Sprintf("configs_%s", env),
+		configFormat: format,
+		viper:        viper.New(),
+	}
+	loader.viper.SetConfigType(format)
+	if err := loader.viper.ReadConfig(bytes.NewReader(content)); err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	return loader, nil
+}
+
 // Load 加载配置到指定的结构体
 // configs: 配置结构体指针，可以传入多个
+// 配置值中的 ${ENV_VAR} / ${ENV_VAR:default} 会替换为环境变量，secret://path#key 会通过 SetSecretProvider 设置的提供者读取
 // 注意：会根据配置文件格式自动选择对应的标签（yaml/toml/json）
 // 例如：如果配置文件是 YAML，会使用 yaml 标签；如果是 TOML，会使用 toml 标签
 func (l *ConfigLoader) Load(configs ...interface{}) error {
@@ -111,6 +146,12 @@ func (l *ConfigLoader) Load(configs ...interface{}) error {
 	// 根据配置文件格式确定使用的标签名
 	tagName := l.getTagNameForFormat()
 
+	// 将 viper 的所有配置转换为 map，并展开环境变量与密钥引用
+	configMap, err := l.newExpander().expand("", l.viper.AllSettings())
+	if err != nil {
+		return fmt.Errorf("failed to expand config: %w", err)
+	}
+
 	for i, cfg := range configs {
 		if cfg == nil {
 			return fmt.Errorf("config[%d] is nil", i)
@@ -123,6 +164,8 @@ func (l *ConfigLoader) Load(configs ...interface{}) error {
 			Result:           cfg,
 			WeaklyTypedInput: true,
 			TagName:          tagName, // 根据配置文件格式选择标签
+			// 字符串按 encoding.TextUnmarshaler 解析（如 Duration 的 "5s"）
+			DecodeHook: mapstructure.TextUnmarshallerHookFunc(),
 		}
 
 		decoder, err := mapstructure.NewDecoder(decoderConfig)
@@ -130,8 +173,6 @@ func (l *ConfigLoader) Load(configs ...interface{}) error {
 			return fmt.Errorf("failed to create decoder for config[%d]: %w", i, err)
 		}
 
-		// 将 viper 的所有配置转换为 map
-		configMap := l.viper.AllSettings()
 		if err := decoder.Decode(configMap); err != nil {
 			return fmt.Errorf("failed to unmarshal config[%d]: %w", i, err)
 		}
@@ -175,6 +216,7 @@ func (l *ConfigLoader) LoadKey(key string, cfg interface{}) error {
 		Result:           cfg,
 		WeaklyTypedInput: true,
 		TagName:          tagName,
+		DecodeHook:       mapstructure.TextUnmarshallerHookFunc(),
 	}
 
 	decoder, err := mapstructure.NewDecoder(decoderConfig)
@@ -188,7 +230,11 @@ func (l *ConfigLoader) LoadKey(key string, cfg interface{}) error {
 		return fmt.Errorf("config key %s not found", key)
 	}
 
-	// 直接解码配置值
+	// 展开环境变量与密钥引用后解码
+	configValue, err = l.newExpander().expand(key, configValue)
+	if err != nil {
+		return fmt.Errorf("failed to expand key %s: %w", key, err)
+	}
 	if err := decoder.Decode(configValue); err != nil {
 		return fmt.Errorf("failed to unmarshal key %s: %w", key, err)
 	}
@@ -196,6 +242,34 @@ func (l *ConfigLoader) LoadKey(key string, cfg interface{}) error {
 	return nil
 }
 
+// Watch 监听配置文件变化，文件变化后自动重新读取并依次调用已注册的回调
+// 回调在 viper 的监听协程中执行，耗时操作应自行异步处理
+func (l *ConfigLoader) Watch(onChange func()) {
+	if onChange == nil {
+		return
+	}
+	l.watchMu.Lock()
+	defer l.watchMu.Unlock()
+	l.watchers = append(l.watchers, onChange)
+	if l.watching {
+		return
+	}
+	l.watching = true
+	// 内存配置没有可监听的文件
+	if l.configPath == "" {
+		return
+	}
+	l.viper.OnConfigChange(func(event fsnotify.Event) {
+		l.watchMu.Lock()
+		watchers := append([]func(){}, l.watchers...)
+		l.watchMu.Unlock()
+		for _, watcher := range watchers {
+			watcher()
+		}
+	})
+	l.viper.WatchConfig()
+}
+
 // GetViper 获取底层 viper 实例（用于高级用法）
 func (l *ConfigLoader) GetViper() *viper.Viper {
 	return l.viper
@@ -252,6 +326,19 @@ func InitConfigE(env string, configPath ...string) error {
 	return nil
 }
 
+// InitConfigFromBytes 使用内存配置内容初始化全局配置加载器（用于测试，之后 LoadCustomConfig 等函数读取该内容）
+func InitConfigFromBytes(env, format string, content []byte) error {
+	loader, err := NewConfigLoaderFromBytes(env, format, content)
+	if err != nil {
+		return err
+	}
+	globalMu.Lock()
+	globalLoader = loader
+	globalEnv = env
+	globalMu.Unlock()
+	return nil
+}
+
 // LoadCustomConfig 使用全局配置加载器加载配置（向后兼容）
 // configs: 配置结构体指针，可以传入多个
 // 注意：如果返回错误，会 panic（保持向后兼容）
@@ -299,6 +386,18 @@ func LoadCustomConfigKeyE(key string, cfg interface{}) error {
 	return loader.LoadKey(key, cfg)
 }
 
+// WatchConfig 使用全局配置加载器监听配置文件变化
+func WatchConfig(onChange func()) error {
+	globalMu.RLock()
+	loader := globalLoader
+	globalMu.RUnlock()
+	if loader == nil {
+		return errors.New("config not initialized, call InitConfig first")
+	}
+	loader.Watch(onChange)
+	return nil
+}
+
 // GetEnv 获取全局环境（向后兼容）
 func GetEnv() string {
 	globalMu.RLock()
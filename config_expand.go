@@ -0,0 +1,165 @@
+package quickgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/team-dandelion/quickgo/secrets"
+)
+
+// SetSecretProvider 设置解析 secret:// 配置值的密钥提供者
+func (l *ConfigLoader) SetSecretProvider(provider secrets.Provider) {
+	l.secretMu.Lock()
+	defer l.secretMu.Unlock()
+	l.secretProvider = provider
+}
+
+// SetSecretProvider 为全局配置加载器设置密钥提供者
+func SetSecretProvider(provider secrets.Provider) error {
+	globalMu.RLock()
+	loader := globalLoader
+	globalMu.RUnlock()
+	if loader == nil {
+		return errors.New("config not initialized, call InitConfig first")
+	}
+	loader.SetSecretProvider(provider)
+	return nil
+}
+
+// configExpander 展开一次加载中的配置值，同一密钥引用只读取一次
+type configExpander struct {
+	provider secrets.Provider
+	cache    map[string]string
+}
+
+func (l *ConfigLoader) newExpander() *configExpander {
+	l.secretMu.RLock()
+	defer l.secretMu.RUnlock()
+	return &configExpander{provider: l.secretProvider, cache: make(map[string]string)}
+}
+
+// expand 递归展开配置值中的 ${ENV_VAR} / ${ENV_VAR:default} 与 secret:// 引用，返回新的值（不修改原值）
+func (e *configExpander) expand(path string, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return e.expandString(path, v)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			expanded, err := e.expand(joinConfigPath(path, key), item)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = expanded
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			expanded, err := e.expand(fmt.Sprintf("%s[%d]", path, i), item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = expanded
+		}
+		return out, nil
+	case []string:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			expanded, err := e.expandString(fmt.Sprintf("%s[%d]", path, i), item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = expanded
+		}
+		return out, nil
+	default:
+		return value, nil
+	}
+}
+
+// expandString 展开环境变量后，整个值为 secret:// 引用时替换为密钥值
+func (e *configExpander) expandString(path, value string) (string, error) {
+	expanded, err := expandEnv(value)
+	if err != nil {
+		return "", fmt.Errorf("config key %s: %w", path, err)
+	}
+	if !secrets.IsRef(expanded) {
+		return expanded, nil
+	}
+	if secret, ok := e.cache[expanded]; ok {
+		return secret, nil
+	}
+	secret, err := secrets.Resolve(context.Background(), e.provider, expanded)
+	if err != nil {
+		return "", fmt.Errorf("config key %s: %w", path, err)
+	}
+	e.cache[expanded] = secret
+	return secret, nil
+}
+
+// expandEnv 展开 ${NAME} 与 ${NAME:default}：变量未设置或为空时使用默认值，没有默认值时返回错误；
+// $${ 转义为字面量 ${
+func expandEnv(value string) (string, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+	var b strings.Builder
+	for {
+		start := strings.Index(value, "${")
+		if start < 0 {
+			b.WriteString(value)
+			return b.String(), nil
+		}
+		if start > 0 && value[start-1] == '$' {
+			b.WriteString(value[:start-1])
+			b.WriteString("${")
+			value = value[start+2:]
+			continue
+		}
+		end := strings.IndexByte(value[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated variable reference in %q", value)
+		}
+		b.WriteString(value[:start])
+		expr := value[start+2 : start+end]
+		name, fallback, hasDefault := strings.Cut(expr, ":")
+		if !isEnvName(name) {
+			return "", fmt.Errorf("invalid environment variable name %q", name)
+		}
+		if env := os.Getenv(name); env != "" {
+			b.WriteString(env)
+		} else if hasDefault {
+			b.WriteString(fallback)
+		} else {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		value = value[start+end+1:]
+	}
+}
+
+// isEnvName 校验环境变量名（字母、数字与下划线，不以数字开头）
+func isEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func joinConfigPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
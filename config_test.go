@@ -0,0 +1,187 @@
+package quickgo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/team-dandelion/quickgo/secrets"
+	"github.com/team-dandelion/quickgo/validation"
+)
+
+func newTestConfigLoader(t *testing.T, content string) *ConfigLoader {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "configs_local.yaml"), []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	t.Setenv(EnvVarName, "")
+	loader, err := NewConfigLoader(EnvLocal, dir)
+	if err != nil {
+		t.Fatalf("NewConfigLoader failed: %v", err)
+	}
+	return loader
+}
+
+func TestConfigLoaderExpandsEnvAndSecrets(t *testing.T) {
+	loader := newTestConfigLoader(t, `
+app:
+  name: ${APP_NAME}
+  version: ${APP_VERSION:1.0.0}
+  env: literal-$${HOME}
+redis:
+  password: secret://redis#password
+  addrs:
+    - ${REDIS_HOST:localhost}:6379
+`)
+	t.Setenv("APP_NAME", "orders")
+	var lookups int
+	loader.SetSecretProvider(secrets.ProviderFunc(func(ctx context.Context, ref secrets.Ref) (string, error) {
+		lookups++
+		if ref.Path != "redis" || ref.Key != "password" {
+			return "", secrets.ErrNotFound
+		}
+		return "s3cret", nil
+	}))
+
+	var config struct {
+		App   AppConfig `yaml:"app"`
+		Redis struct {
+			Password string   `yaml:"password"`
+			Addrs    []string `yaml:"addrs"`
+		} `yaml:"redis"`
+	}
+	if err := loader.Load(&config); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if config.App.Name != "orders" || config.App.Version != "1.0.0" || config.App.Env != "literal-${HOME}" {
+		t.Fatalf("unexpected app config %+v", config.App)
+	}
+	if config.Redis.Password != "s3cret" || len(config.Redis.Addrs) != 1 || config.Redis.Addrs[0] != "localhost:6379" {
+		t.Fatalf("unexpected redis config %+v", config.Redis)
+	}
+
+	var app AppConfig
+	if err := loader.LoadKey("app", &app); err != nil || app.Name != "orders" {
+		t.Fatalf("LoadKey failed: %+v, %v", app, err)
+	}
+	if lookups != 1 {
+		t.Fatalf("expected one secret lookup, got %d", lookups)
+	}
+}
+
+func TestConfigLoaderReportsUnresolvedValues(t *testing.T) {
+	loader := newTestConfigLoader(t, `
+app:
+  name: ${QUICKGO_TEST_UNSET_VAR}
+db:
+  password: secret://db#password
+`)
+	var app AppConfig
+	err := loader.LoadKey("app", &app)
+	if err == nil || !strings.Contains(err.Error(), "app.name: environment variable QUICKGO_TEST_UNSET_VAR is not set") {
+		t.Fatalf("expected unset variable error, got %v", err)
+	}
+	var db struct {
+		Password string `yaml:"password"`
+	}
+	err = loader.LoadKey("db", &db)
+	if err == nil || !strings.Contains(err.Error(), "no provider configured") {
+		t.Fatalf("expected missing provider error, got %v", err)
+	}
+}
+
+func TestValidateFrameworkConfigReportsAllProblems(t *testing.T) {
+	cfg := &FrameworkConfig{
+		GrpcServer: &GrpcServerConfig{Port: 70000, Etcd: &EtcdConfig{}},
+		HTTPServer: &HTTPServerConfig{Port: 8080, EnableTrace: true, DisableTrace: true, Limits: &HTTPLimitsConfig{ReadTimeout: "10x"}},
+	}
+	err := ValidateConfig(cfg)
+	var errs validation.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected validation errors, got %v", err)
+	}
+	fields := make(map[string]bool)
+	for _, e := range errs {
+		fields[e.Field] = true
+	}
+	for _, field := range []string{"httpServer.limits.readTimeout", "grpcServer.port", "grpcServer.serviceName", "grpcServer.etcd.endpoints", "httpServer.enableTrace"} {
+		if !fields[field] {
+			t.Errorf("expected error for %s, got %v", field, err)
+		}
+	}
+
+	framework, err := NewFramework(ConfigOptionWithLogger(LoggerConfig{Enabled: false}), ConfigOptionWithHTTPServer(&HTTPServerConfig{Port: 8080, Limits: &HTTPLimitsConfig{ReadTimeout: "soon"}}))
+	if err != nil {
+		t.Fatalf("NewFramework: %v", err)
+	}
+	if err := framework.Init(); err == nil || !strings.Contains(err.Error(), "httpServer.limits.readTimeout") {
+		t.Fatalf("expected Init to report httpServer.limits.readTimeout, got %v", err)
+	}
+}
+
+func TestConfigLoaderDecodesDurations(t *testing.T) {
+	loader := newTestConfigLoader(t, `
+grpcClient:
+  timeout: ${CLIENT_TIMEOUT:1m30s}
+  keepAliveTime: 10s
+  healthCheckInterval: -1s
+redis:
+  databases:
+    - name: cache
+      dialTimeout: 250ms
+`)
+	var config FrameworkConfig
+	if err := loader.Load(&config); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := config.GrpcClient.Timeout.Std(); got != 90*time.Second {
+		t.Fatalf("expected timeout 1m30s, got %s", got)
+	}
+	if got := config.GrpcClient.KeepAliveTime.Std(); got != 10*time.Second {
+		t.Fatalf("expected keepAliveTime 10s, got %s", got)
+	}
+	if config.GrpcClient.HealthCheckInterval >= 0 {
+		t.Fatalf("expected negative healthCheckInterval, got %s", config.GrpcClient.HealthCheckInterval)
+	}
+	if got := config.Redis.Databases[0].DialTimeout.Std(); got != 250*time.Millisecond {
+		t.Fatalf("expected redis dialTimeout 250ms, got %s", got)
+	}
+
+	var client GrpcClientConfig
+	if err := loader.LoadKey("grpcClient", &client); err != nil || client.Timeout.Std() != 90*time.Second {
+		t.Fatalf("LoadKey: timeout=%s err=%v", client.Timeout, err)
+	}
+
+	invalid := newTestConfigLoader(t, "grpcClient:\n  timeout: soon\n")
+	if err := invalid.Load(&config); err == nil || !strings.Contains(err.Error(), "soon") {
+		t.Fatalf("expected invalid duration error, got %v", err)
+	}
+}
+
+func TestConfigLoaderFromBytes(t *testing.T) {
+	t.Setenv("APP_NAME", "from-env")
+	loader, err := NewConfigLoaderFromBytes(EnvLocal, ConfigFormatJSON, []byte(`{"app": {"name": "${APP_NAME}"}, "grpcClient": {"timeout": "2s"}}`))
+	if err != nil {
+		t.Fatalf("NewConfigLoaderFromBytes failed: %v", err)
+	}
+	var config FrameworkConfig
+	if err := loader.Load(&config); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if config.App.Name != "from-env" || config.GrpcClient.Timeout.Std() != 2*time.Second {
+		t.Fatalf("unexpected config: app=%+v grpcClient=%+v", config.App, config.GrpcClient)
+	}
+	loader.Watch(func() {})
+
+	if _, err := NewConfigLoaderFromBytes(EnvLocal, "xml", nil); err == nil {
+		t.Fatal("expected unsupported format error")
+	}
+	if _, err := NewConfigLoaderFromBytes(EnvLocal, ConfigFormatYAML, []byte("app: [")); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
@@ -0,0 +1,12 @@
+package quickgo
+
+import (
+	"github.com/team-dandelion/quickgo/validation"
+)
+
+// ValidateConfig 校验配置结构体（如 FrameworkConfig）：执行各组件配置的 validate 标签
+// （端口范围、时长字符串、互斥字段等）与 Validate 方法，一次返回所有问题；
+// 错误为 validation.ValidationErrors，字段路径使用配置文件中的键名（如 grpcServer.keepAliveTime）
+func ValidateConfig(cfg interface{}) error {
+	return validation.NewValidator(validation.WithNameTag("json")).Validate(cfg)
+}
@@ -10,6 +10,7 @@ import (
 	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/team-dandelion/quickgo/logger"
 
+	"gorm.io/driver/clickhouse"
 	gormmysql "gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
@@ -23,6 +24,10 @@ type Client struct {
 	name   string
 	db     *gorm.DB
 	config *GormConfig
+	// 写后粘滞主库（未配置从库或 StickyMasterAfterWrite 时为 nil）
+	sticky *stickyTracker
+	// 慢查询回调
+	slowHooks *slowQueryHooks
 }
 
 // NewClient 创建 GORM 客户端
@@ -44,13 +49,25 @@ func NewClient(config *GormConfig) (*Client, error) {
 		return nil, fmt.Errorf("failed to build master DSN: %w", err)
 	}
 
-	dialector, err := newDialector(config.Master.Type, masterDSN)
+	// 会话变量与初始化 SQL（TiDB / OceanBase 等兼容库的行为开关通常通过会话变量设置）
+	initStatements, err := sessionStatements(config.Master.Type, config.SessionVars, config.InitSQL)
+	if err != nil {
+		return nil, err
+	}
+
+	dialector, err := openSessionDialector(config.Master.Type, masterDSN, initStatements)
+	if err != nil {
+		return nil, err
+	}
+
+	sanitizer, err := newSQLSanitizer(config.LogSanitize, config.Master.Type)
 	if err != nil {
 		return nil, err
 	}
 
 	// 打开主库连接
-	db, err := gorm.Open(dialector, newGormConfig(config))
+	slowHooks := &slowQueryHooks{}
+	db, err := gorm.Open(dialector, newGormConfig(config, slowHooks, sanitizer))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database connection (check database is running and accessible): %w", err)
 	}
@@ -68,28 +85,12 @@ func NewClient(config *GormConfig) (*Client, error) {
 		sqlDB.SetMaxOpenConns(config.MaxOpenConn)
 	}
 
-	// 解析并设置连接最大生存时间
-	if config.ConnMaxLifetime != "" {
-		connMaxLifetime, err := time.ParseDuration(config.ConnMaxLifetime)
-		if err != nil {
-			sqlDB.Close()
-			return nil, fmt.Errorf("failed to parse ConnMaxLifetime %s: %w", config.ConnMaxLifetime, err)
-		}
-		if connMaxLifetime > 0 {
-			sqlDB.SetConnMaxLifetime(connMaxLifetime)
-		}
+	// 连接最大生存时间与最大空闲时间
+	if config.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime.Std())
 	}
-
-	// 解析并设置连接最大空闲时间
-	if config.ConnMaxIdleTime != "" {
-		connMaxIdleTime, err := time.ParseDuration(config.ConnMaxIdleTime)
-		if err != nil {
-			sqlDB.Close()
-			return nil, fmt.Errorf("failed to parse ConnMaxIdleTime %s: %w", config.ConnMaxIdleTime, err)
-		}
-		if connMaxIdleTime > 0 {
-			sqlDB.SetConnMaxIdleTime(connMaxIdleTime)
-		}
+	if config.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(config.ConnMaxIdleTime.Std())
 	}
 
 	// 测试连接（使用带超时的 context，确保不会无限等待）
@@ -102,9 +103,32 @@ func NewClient(config *GormConfig) (*Client, error) {
 		return nil, fmt.Errorf("failed to ping database (connection test failed): %w", err)
 	}
 
+	client := &Client{
+		name:      config.Name,
+		db:        db,
+		config:    config,
+		slowHooks: slowHooks,
+	}
+
+	if err := registerTracingCallbacks(db, config.Master.Database, config.Tracing, sanitizer); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to register tracing callbacks: %w", err)
+	}
+
+	if config.TenantScope != nil && config.TenantScope.Enabled {
+		if err := registerTenantCallbacks(db, config.TenantScope); err != nil {
+			sqlDB.Close()
+			return nil, fmt.Errorf("failed to register tenant callbacks: %w", err)
+		}
+	}
+
 	// 如果配置了从库，设置读写分离
 	// 注意：从库连接失败也会导致服务无法启动
 	if len(config.Slaves) > 0 {
+		if config.StickyMasterAfterWrite > 0 {
+			client.sticky = newStickyTracker(config.StickyMasterAfterWrite.Std())
+		}
+
 		logger.Info(ctx, "Configuring read replicas: name=%s, count=%d", config.Name, len(config.Slaves))
 
 		var slaveDialectors []gorm.Dialector
@@ -115,14 +139,14 @@ func NewClient(config *GormConfig) (*Client, error) {
 				return nil, fmt.Errorf("failed to build slave[%d] DSN: %w", i, err)
 			}
 
-			probeDialector, err := newDialector(config.Master.Type, slaveDSN)
+			probeDialector, err := openSessionDialector(config.Master.Type, slaveDSN, initStatements)
 			if err != nil {
 				sqlDB.Close()
 				return nil, err
 			}
 
 			// 测试从库连接（确保从库可用）
-			slaveDB, err := gorm.Open(probeDialector, newGormConfig(config))
+			slaveDB, err := gorm.Open(probeDialector, newGormConfig(config, nil, sanitizer))
 			if err != nil {
 				sqlDB.Close()
 				return nil, fmt.Errorf("failed to connect to slave[%d] (read replica connection failed): %w", i, err)
@@ -148,7 +172,7 @@ func NewClient(config *GormConfig) (*Client, error) {
 				return nil, fmt.Errorf("failed to close slave[%d] probe connection: %w", i, closeErr)
 			}
 
-			slaveDialector, err := newDialector(config.Master.Type, slaveDSN)
+			slaveDialector, err := openSessionDialector(config.Master.Type, slaveDSN, initStatements)
 			if err != nil {
 				sqlDB.Close()
 				return nil, err
@@ -169,28 +193,28 @@ func NewClient(config *GormConfig) (*Client, error) {
 			sqlDB.Close()
 			return nil, fmt.Errorf("failed to register db resolver: %w", err)
 		}
+		if err := client.registerRoutingCallbacks(db); err != nil {
+			sqlDB.Close()
+			return nil, fmt.Errorf("failed to register routing callbacks: %w", err)
+		}
 
 		logger.Info(ctx, "Read replicas configured successfully: name=%s, count=%d", config.Name, len(slaveDialectors))
 	}
 
 	logger.Info(ctx, "GORM client initialized successfully: name=%s", config.Name)
 
-	return &Client{
-		name:   config.Name,
-		db:     db,
-		config: config,
-	}, nil
+	return client, nil
 }
 
-func newGormConfig(config *GormConfig) *gorm.Config {
+func newGormConfig(config *GormConfig, slowHooks *slowQueryHooks, sanitizer *sqlSanitizer) *gorm.Config {
 	return &gorm.Config{
-		Logger: newLogger(config),
+		Logger: newLogger(config, slowHooks, sanitizer),
 	}
 }
 
 func newDialector(dbType DatabaseType, dsn string) (gorm.Dialector, error) {
 	switch dbType {
-	case DatabaseTypeMySQL:
+	case DatabaseTypeMySQL, DatabaseTypeTiDB, DatabaseTypeOceanBase:
 		return gormmysql.Open(dsn), nil
 	case DatabaseTypePostgreSQL:
 		return postgres.Open(dsn), nil
@@ -198,6 +222,8 @@ func newDialector(dbType DatabaseType, dsn string) (gorm.Dialector, error) {
 		return sqlite.Open(dsn), nil
 	case DatabaseTypeSQLServer:
 		return sqlserver.Open(dsn), nil
+	case DatabaseTypeClickHouse:
+		return clickhouse.Open(dsn), nil
 	default:
 		return nil, fmt.Errorf("unsupported database type: %s", dbType)
 	}
@@ -257,7 +283,7 @@ func buildDSN(master MasterConfig) (string, error) {
 
 	// 根据数据库类型构建 DSN
 	switch master.Type {
-	case DatabaseTypeMySQL:
+	case DatabaseTypeMySQL, DatabaseTypeTiDB, DatabaseTypeOceanBase:
 		return buildMySQLDSN(master), nil
 	case DatabaseTypePostgreSQL:
 		return buildPostgreSQLDSN(master), nil
@@ -268,6 +294,8 @@ func buildDSN(master MasterConfig) (string, error) {
 		return master.Database, nil
 	case DatabaseTypeSQLServer:
 		return buildSQLServerDSN(master), nil
+	case DatabaseTypeClickHouse:
+		return buildClickHouseDSN(master.Host, master.Port, master.User, master.Password, master.Database, master.Params), nil
 	default:
 		return "", fmt.Errorf("unsupported database type: %s", master.Type)
 	}
@@ -299,7 +327,7 @@ func buildMySQLDSN(master MasterConfig) string {
 	}
 
 	cfg := mysqldriver.NewConfig()
-	cfg.User = master.User
+	cfg.User = mysqlUser(master.User, master.Tenant, master.Cluster)
 	cfg.Passwd = master.Password
 	cfg.Net = "tcp"
 	cfg.Addr = net.JoinHostPort(master.Host, fmt.Sprintf("%d", master.Port))
@@ -309,6 +337,17 @@ func buildMySQLDSN(master MasterConfig) string {
 	return cfg.FormatDSN()
 }
 
+// mysqlUser 构建 MySQL 协议用户名，OceanBase 通过 user@tenant#cluster 指定租户与集群
+func mysqlUser(user, tenant, cluster string) string {
+	if tenant != "" {
+		user += "@" + tenant
+	}
+	if cluster != "" {
+		user += "#" + cluster
+	}
+	return user
+}
+
 func userInfo(user, password string) *url.Userinfo {
 	if user == "" && password == "" {
 		return nil
@@ -357,10 +396,27 @@ func buildSQLServerDSN(master MasterConfig) string {
 	return u.String()
 }
 
+// buildClickHouseDSN 构建 ClickHouse DSN（clickhouse-go v2 原生协议）
+// Params 透传为查询参数，如 dial_timeout、read_timeout、compress、secure 等
+func buildClickHouseDSN(host string, port int, user, password, database string, params map[string]string) string {
+	values := make(url.Values)
+	for k, v := range params {
+		values.Set(k, v)
+	}
+	u := url.URL{
+		Scheme:   "clickhouse",
+		User:     userInfo(user, password),
+		Host:     net.JoinHostPort(host, fmt.Sprintf("%d", port)),
+		Path:     "/" + database,
+		RawQuery: values.Encode(),
+	}
+	return u.String()
+}
+
 // buildSlaveDSN 构建从库 DSN
 func buildSlaveDSN(dbType DatabaseType, slave SlaveConfig) (string, error) {
 	switch dbType {
-	case DatabaseTypeMySQL:
+	case DatabaseTypeMySQL, DatabaseTypeTiDB, DatabaseTypeOceanBase:
 		return buildMySQLSlaveDSN(slave), nil
 	case DatabaseTypePostgreSQL:
 		return buildPostgreSQLSlaveDSN(slave), nil
@@ -368,6 +424,8 @@ func buildSlaveDSN(dbType DatabaseType, slave SlaveConfig) (string, error) {
 		return slave.Database, nil
 	case DatabaseTypeSQLServer:
 		return buildSQLServerSlaveDSN(slave), nil
+	case DatabaseTypeClickHouse:
+		return buildClickHouseDSN(slave.Host, slave.Port, slave.User, slave.Password, slave.Database, slave.Params), nil
 	default:
 		return "", fmt.Errorf("unsupported database type: %s", dbType)
 	}
@@ -399,7 +457,7 @@ func buildMySQLSlaveDSN(slave SlaveConfig) string {
 	}
 
 	cfg := mysqldriver.NewConfig()
-	cfg.User = slave.User
+	cfg.User = mysqlUser(slave.User, slave.Tenant, slave.Cluster)
 	cfg.Passwd = slave.Password
 	cfg.Net = "tcp"
 	cfg.Addr = net.JoinHostPort(slave.Host, fmt.Sprintf("%d", slave.Port))
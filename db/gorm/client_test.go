@@ -4,8 +4,11 @@ import (
 	"net/url"
 	"path/filepath"
 	"testing"
+	"time"
 
+	chdriver "github.com/ClickHouse/clickhouse-go/v2"
 	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
 )
 
 func TestBuildMySQLDSNUsesDriverEscaping(t *testing.T) {
@@ -153,3 +156,138 @@ func TestNewClientWithReadReplicaKeepsSourceOpen(t *testing.T) {
 		t.Fatalf("expected source connection to remain usable after replica setup, got %v", err)
 	}
 }
+
+func TestBuildClickHouseDSNParsesWithDriver(t *testing.T) {
+	dsn, err := buildDSN(MasterConfig{
+		Type:     DatabaseTypeClickHouse,
+		Host:     "ch.local",
+		Port:     9000,
+		User:     "analytics",
+		Password: "p@ss/word",
+		Database: "events",
+		Params: map[string]string{
+			"dial_timeout": "3s",
+			"compress":     "lz4",
+		},
+	})
+	if err != nil {
+		t.Fatalf("buildDSN failed: %v", err)
+	}
+
+	opts, err := chdriver.ParseDSN(dsn)
+	if err != nil {
+		t.Fatalf("ParseDSN failed: %v", err)
+	}
+	if len(opts.Addr) != 1 || opts.Addr[0] != "ch.local:9000" {
+		t.Fatalf("unexpected addr: %v", opts.Addr)
+	}
+	if opts.Auth.Username != "analytics" || opts.Auth.Password != "p@ss/word" || opts.Auth.Database != "events" {
+		t.Fatalf("unexpected auth: %+v", opts.Auth)
+	}
+	if opts.DialTimeout != 3*time.Second {
+		t.Fatalf("unexpected dial timeout: %s", opts.DialTimeout)
+	}
+
+	slaveDSN, err := buildSlaveDSN(DatabaseTypeClickHouse, SlaveConfig{Host: "ch-replica", Port: 9000, Database: "events"})
+	if err != nil {
+		t.Fatalf("buildSlaveDSN failed: %v", err)
+	}
+	if slaveOpts, err := chdriver.ParseDSN(slaveDSN); err != nil || slaveOpts.Addr[0] != "ch-replica:9000" {
+		t.Fatalf("unexpected slave dsn %q: %v", slaveDSN, err)
+	}
+	if _, err := newDialector(DatabaseTypeClickHouse, dsn); err != nil {
+		t.Fatalf("newDialector failed: %v", err)
+	}
+}
+
+func TestBuildOceanBaseDSNIncludesTenantAndCluster(t *testing.T) {
+	dsn, err := buildDSN(MasterConfig{
+		Type:     DatabaseTypeOceanBase,
+		Host:     "ob.local",
+		Port:     2881,
+		User:     "app",
+		Password: "secret",
+		Database: "orders",
+		Tenant:   "mysql_tenant",
+		Cluster:  "obcluster",
+	})
+	if err != nil {
+		t.Fatalf("buildDSN failed: %v", err)
+	}
+	cfg, err := mysqldriver.ParseDSN(dsn)
+	if err != nil {
+		t.Fatalf("ParseDSN failed: %v", err)
+	}
+	if cfg.User != "app@mysql_tenant#obcluster" || cfg.Passwd != "secret" || cfg.Addr != "ob.local:2881" {
+		t.Fatalf("unexpected oceanbase config: user=%q password=%q addr=%q", cfg.User, cfg.Passwd, cfg.Addr)
+	}
+
+	if _, err := buildSlaveDSN(DatabaseTypeTiDB, SlaveConfig{Host: "tidb", Port: 4000, User: "root"}); err != nil {
+		t.Fatalf("buildSlaveDSN for tidb failed: %v", err)
+	}
+}
+
+func TestSessionStatements(t *testing.T) {
+	statements, err := sessionStatements(DatabaseTypeTiDB, map[string]string{
+		"tidb_txn_mode":               "'pessimistic'",
+		"tidb_isolation_read_engines": "'tikv,tidb'",
+	}, []string{"SET NAMES utf8mb4", ""})
+	if err != nil {
+		t.Fatalf("sessionStatements failed: %v", err)
+	}
+	expected := []string{
+		"SET SESSION tidb_isolation_read_engines = 'tikv,tidb'",
+		"SET SESSION tidb_txn_mode = 'pessimistic'",
+		"SET NAMES utf8mb4",
+	}
+	if len(statements) != len(expected) {
+		t.Fatalf("unexpected statements: %v", statements)
+	}
+	for i := range expected {
+		if statements[i] != expected[i] {
+			t.Fatalf("statement[%d]: expected %q, got %q", i, expected[i], statements[i])
+		}
+	}
+
+	if _, err := sessionStatements(DatabaseTypeMySQL, map[string]string{"sql_mode = ''; DROP TABLE x; --": "1"}, nil); err == nil {
+		t.Fatal("expected invalid session variable name to be rejected")
+	}
+	if _, err := sessionStatements(DatabaseTypeSQLite, map[string]string{"foreign_keys": "1"}, nil); err == nil {
+		t.Fatal("expected session variables to be rejected for sqlite")
+	}
+}
+
+func TestNewClientRunsInitSQLOnEveryConnection(t *testing.T) {
+	dir := t.TempDir()
+	client, err := NewClient(&GormConfig{
+		Name: "test",
+		Master: MasterConfig{
+			Type:     DatabaseTypeSQLite,
+			Database: filepath.Join(dir, "master.db"),
+		},
+		Slaves: []SlaveConfig{
+			{Database: filepath.Join(dir, "replica.db")},
+		},
+		MaxOpenConn: 2,
+		InitSQL:     []string{"PRAGMA foreign_keys = ON", "CREATE TEMP TABLE session_marker (id integer)"},
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	// 占用一个连接，迫使第二个查询建立新连接
+	tx := client.GetDB().Begin()
+	defer tx.Rollback()
+
+	for _, db := range []*gorm.DB{tx, client.GetDB()} {
+		var enabled int
+		if err := db.Raw("PRAGMA foreign_keys").Scan(&enabled).Error; err != nil || enabled != 1 {
+			t.Fatalf("expected foreign_keys enabled on connection, got %d, %v", enabled, err)
+		}
+		var count int64
+		if err := db.Raw("SELECT COUNT(*) FROM session_marker").Scan(&count).Error; err != nil {
+			t.Fatalf("expected init SQL to run on connection: %v", err)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package gorm
 
+import "github.com/team-dandelion/quickgo/types"
+
 // DatabaseType 数据库类型
 type DatabaseType string
 
@@ -8,11 +10,20 @@ const (
 	DatabaseTypePostgreSQL DatabaseType = "postgres"
 	DatabaseTypeSQLite     DatabaseType = "sqlite"
 	DatabaseTypeSQLServer  DatabaseType = "sqlserver"
+	DatabaseTypeClickHouse DatabaseType = "clickhouse"
+	// TiDB 与 OceanBase（MySQL 模式）兼容 MySQL 协议，使用 MySQL 驱动与 DSN 构建逻辑
+	DatabaseTypeTiDB      DatabaseType = "tidb"
+	DatabaseTypeOceanBase DatabaseType = "oceanbase"
 )
 
+// IsMySQLCompatible 是否为 MySQL 协议兼容的数据库
+func (t DatabaseType) IsMySQLCompatible() bool {
+	return t == DatabaseTypeMySQL || t == DatabaseTypeTiDB || t == DatabaseTypeOceanBase
+}
+
 // MasterConfig 主库配置
 type MasterConfig struct {
-	// 数据库类型：mysql, postgres, sqlite, sqlserver
+	// 数据库类型：mysql, postgres, sqlite, sqlserver, clickhouse, tidb, oceanbase
 	Type DatabaseType `json:"type" yaml:"type" toml:"type"`
 	// DSN 连接字符串（如果提供，则忽略其他连接参数）
 	DSN string `json:"dsn" yaml:"dsn" toml:"dsn"`
@@ -32,6 +43,9 @@ type MasterConfig struct {
 	Timezone string `json:"timezone" yaml:"timezone" toml:"timezone"`
 	// SSL 模式（PostgreSQL 使用）
 	SSLMode string `json:"sslMode" yaml:"sslMode" toml:"sslMode"`
+	// 租户与集群名（OceanBase 使用，用户名拼接为 user@tenant#cluster）
+	Tenant  string `json:"tenant" yaml:"tenant" toml:"tenant"`
+	Cluster string `json:"cluster" yaml:"cluster" toml:"cluster"`
 	// 其他连接参数
 	Params map[string]string `json:"params" yaml:"params" toml:"params"`
 }
@@ -54,6 +68,9 @@ type SlaveConfig struct {
 	Timezone string `json:"timezone" yaml:"timezone" toml:"timezone"`
 	// SSL 模式（PostgreSQL 使用）
 	SSLMode string `json:"sslMode" yaml:"sslMode" toml:"sslMode"`
+	// 租户与集群名（OceanBase 使用，用户名拼接为 user@tenant#cluster）
+	Tenant  string `json:"tenant" yaml:"tenant" toml:"tenant"`
+	Cluster string `json:"cluster" yaml:"cluster" toml:"cluster"`
 	// 其他连接参数
 	Params map[string]string `json:"params" yaml:"params" toml:"params"`
 }
@@ -67,19 +84,39 @@ type GormConfig struct {
 	// 从库配置列表（可选，用于读写分离）
 	Slaves []SlaveConfig `json:"slaves" yaml:"slaves" toml:"slaves"`
 	// 连接池配置
-	MaxIdleConn     int    `json:"maxIdleConn" yaml:"maxIdleConn" toml:"maxIdleConn"`             // 最大空闲连接数
-	MaxOpenConn     int    `json:"maxOpenConn" yaml:"maxOpenConn" toml:"maxOpenConn"`             // 最大打开连接数
-	ConnMaxLifetime string `json:"connMaxLifetime" yaml:"connMaxLifetime" toml:"connMaxLifetime"` // 连接最大生存时间（如：30m、1h）
-	ConnMaxIdleTime string `json:"connMaxIdleTime" yaml:"connMaxIdleTime" toml:"connMaxIdleTime"` // 连接最大空闲时间（如：10m、30m）
+	MaxIdleConn     int            `json:"maxIdleConn" yaml:"maxIdleConn" toml:"maxIdleConn"`             // 最大空闲连接数
+	MaxOpenConn     int            `json:"maxOpenConn" yaml:"maxOpenConn" toml:"maxOpenConn"`             // 最大打开连接数
+	ConnMaxLifetime types.Duration `json:"connMaxLifetime" yaml:"connMaxLifetime" toml:"connMaxLifetime"` // 连接最大生存时间（如：30m、1h）
+	ConnMaxIdleTime types.Duration `json:"connMaxIdleTime" yaml:"connMaxIdleTime" toml:"connMaxIdleTime"` // 连接最大空闲时间（如：10m、30m）
 	// GORM 配置
-	LogLevel      string `json:"logLevel" yaml:"logLevel" toml:"logLevel"`                // 日志级别：silent, error, warn, info
-	SlowThreshold int    `json:"slowThreshold" yaml:"slowThreshold" toml:"slowThreshold"` // 慢查询阈值（毫秒）
+	LogLevel      string          `json:"logLevel" yaml:"logLevel" toml:"logLevel"`                // 日志级别：silent, error, warn, info
+	SlowThreshold *types.Duration `json:"slowThreshold" yaml:"slowThreshold" toml:"slowThreshold"` // 慢查询阈值（如：200ms、1s），未设置时默认 200ms，显式设置为 0 关闭
 	// 是否启用日志
 	EnableLog bool `json:"enableLog" yaml:"enableLog" toml:"enableLog"`
+	// 写后粘滞主库时间（如：500ms、2s），仅在配置从库时生效
+	// 同一粘滞键（WithStickyKey）写入后的该时间内，读请求路由到主库
+	StickyMasterAfterWrite types.Duration `json:"stickyMasterAfterWrite" yaml:"stickyMasterAfterWrite" toml:"stickyMasterAfterWrite"`
+	// 会话变量，每个新连接建立时执行（主库与从库均生效）
+	// 值为原样的 SQL 字面量，字符串需自带引号，如：
+	//   TiDB:      tidb_txn_mode: "'pessimistic'", tidb_isolation_read_engines: "'tikv,tidb'"
+	//   OceanBase: ob_query_timeout: "10000000", ob_trx_timeout: "100000000"
+	//   MySQL:     sql_mode: "'STRICT_TRANS_TABLES'"
+	// MySQL 兼容库执行 SET SESSION name = value，PostgreSQL 执行 SET name = value，其他类型不支持
+	SessionVars map[string]string `json:"sessionVars" yaml:"sessionVars" toml:"sessionVars"`
+	// 初始化 SQL，每个新连接建立时在会话变量之后按顺序执行
+	InitSQL []string `json:"initSQL" yaml:"initSQL" toml:"initSQL"`
+	// 行级租户隔离（可选），按租户列自动追加查询条件、填充租户列
+	TenantScope *TenantScopeConfig `json:"tenantScope" yaml:"tenantScope" toml:"tenantScope"`
+	// 数据库 span 配置（可选，默认启用 tracing 时按操作创建 span，db.statement 不含参数值）
+	Tracing *TracingConfig `json:"tracing" yaml:"tracing" toml:"tracing"`
+	// SQL 日志脱敏（可选），屏蔽字面量、邮箱、电话号码与指定列的值，避免日志包含用户个人信息
+	LogSanitize *LogSanitizeConfig `json:"logSanitize" yaml:"logSanitize" toml:"logSanitize"`
 }
 
 // GormManagerConfig GORM 管理器配置（支持多个数据库实例）
 type GormManagerConfig struct {
 	// 数据库配置列表
 	Databases []GormConfig `json:"databases" yaml:"databases" toml:"databases"`
+	// 库级租户隔离（可选），按租户选择数据库，见 Manager.TenantDB
+	TenantRouting *TenantRoutingConfig `json:"tenantRouting" yaml:"tenantRouting" toml:"tenantRouting"`
 }
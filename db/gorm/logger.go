@@ -2,28 +2,32 @@ package gorm
 
 import (
 	"context"
+	"errors"
 	"regexp"
 	"strings"
 	"time"
 
 	frameworkLogger "github.com/team-dandelion/quickgo/logger"
-	"github.com/team-dandelion/quickgo/tracing"
-
-	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/trace"
 
 	"gorm.io/gorm/logger"
 )
 
 // newLogger 创建 GORM 日志适配器
-func newLogger(config *GormConfig) logger.Interface {
-	if !config.EnableLog {
-		return logger.Default.LogMode(logger.Silent)
+// 未启用日志时仍使用静默级别的适配器，保证慢查询回调生效
+func newLogger(config *GormConfig, slowHooks *slowQueryHooks, sanitizer *sqlSanitizer) logger.Interface {
+	slowThreshold := 200 * time.Millisecond // 默认 200ms
+	if config.SlowThreshold != nil {
+		slowThreshold = config.SlowThreshold.Std()
 	}
 
-	slowThreshold := time.Duration(config.SlowThreshold) * time.Millisecond
-	if slowThreshold == 0 {
-		slowThreshold = 200 * time.Millisecond // 默认 200ms
+	if !config.EnableLog {
+		return &gormLogger{
+			config:        config,
+			slowThreshold: slowThreshold,
+			logLevel:      logger.Silent,
+			slowHooks:     slowHooks,
+			sanitizer:     sanitizer,
+		}
 	}
 
 	// 根据配置的日志级别设置
@@ -46,6 +50,8 @@ func newLogger(config *GormConfig) logger.Interface {
 		config:        config,
 		slowThreshold: slowThreshold,
 		logLevel:      logLevel,
+		slowHooks:     slowHooks,
+		sanitizer:     sanitizer,
 	}
 }
 
@@ -54,6 +60,9 @@ type gormLogger struct {
 	config        *GormConfig
 	slowThreshold time.Duration
 	logLevel      logger.LogLevel
+	slowHooks     *slowQueryHooks
+	// SQL 脱敏器（nil 表示不脱敏）
+	sanitizer *sqlSanitizer
 }
 
 // LogMode 设置日志级别
@@ -87,51 +96,29 @@ func (l *gormLogger) Error(ctx context.Context, msg string, data ...interface{})
 // Trace 实现 logger.Interface.Trace
 // 这是最重要的方法，GORM 的 SQL 查询日志通过这里输出
 func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	slow := elapsed > l.slowThreshold && l.slowThreshold != 0
+
 	if l.logLevel <= logger.Silent {
+		if slow && l.slowHooks.enabled() {
+			sql, rows := fc()
+			l.fireSlowQuery(ctx, l.sanitizer.Sanitize(removeFilePath(sql)), rows, elapsed, err)
+		}
 		return
 	}
 
-	elapsed := time.Since(begin)
 	sql, rows := fc()
 
 	// 去除日志消息中的文件路径（格式：[/path/to/file.go:123]）
 	// GORM 默认会在日志末尾添加文件路径，我们需要去除它
-	sql = removeFilePath(sql)
-
-	// 如果启用了 OpenTelemetry tracing，创建数据库操作的 span
-	var span trace.Span
-	if tracing.IsEnabled() {
-		ctx, span = tracing.StartSpan(ctx, "gorm.query")
-		defer span.End()
-
-		// 设置 span 属性
-		span.SetAttributes(
-			attribute.String("db.system", "gorm"),
-			attribute.String("db.statement", sql),
-			attribute.Int64("db.rows_affected", rows),
-			attribute.Float64("db.duration_ms", float64(elapsed.Nanoseconds())/1e6),
-		)
-
-		// 添加 trace_id 到 span attributes（方便在 Jaeger 中查询）
-		tracing.AddTraceIDToSpan(span, ctx)
-
-		// 检测是否是慢查询
-		if elapsed > l.slowThreshold && l.slowThreshold != 0 {
-			span.SetAttributes(attribute.Bool("db.slow_query", true))
-		}
-
-		// 设置错误状态
-		if err != nil {
-			tracing.SetSpanError(span, err)
-		}
-	}
+	sql = l.sanitizer.Sanitize(removeFilePath(sql))
 
 	switch {
 	case err != nil && l.logLevel >= logger.Error:
-		// 错误日志
+		// 错误日志（错误信息可能包含冲突的字段值，如 Duplicate entry，同样脱敏）
 		frameworkLogger.Error(ctx, "[GORM] [%.3fms] [rows:%d] %s",
-			float64(elapsed.Nanoseconds())/1e6, rows, sql, err)
-	case elapsed > l.slowThreshold && l.slowThreshold != 0 && l.logLevel >= logger.Warn:
+			float64(elapsed.Nanoseconds())/1e6, rows, sql, l.sanitizeError(err))
+	case slow && l.logLevel >= logger.Warn:
 		// 慢查询日志
 		frameworkLogger.Warn(ctx, "[GORM] [%.3fms] [rows:%d] %s | slow query",
 			float64(elapsed.Nanoseconds())/1e6, rows, sql)
@@ -140,6 +127,33 @@ func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (stri
 		frameworkLogger.Info(ctx, "[GORM] [%.3fms] [rows:%d] %s",
 			float64(elapsed.Nanoseconds())/1e6, rows, sql)
 	}
+
+	if slow {
+		l.fireSlowQuery(ctx, sql, rows, elapsed, err)
+	}
+}
+
+// fireSlowQuery 触发慢查询回调
+func (l *gormLogger) fireSlowQuery(ctx context.Context, sql string, rows int64, elapsed time.Duration, err error) {
+	l.slowHooks.fire(ctx, SlowQuery{
+		Name:      l.config.Name,
+		SQL:       sql,
+		Rows:      rows,
+		Duration:  elapsed,
+		Threshold: l.slowThreshold,
+		Err:       err,
+	})
+}
+
+// sanitizeError 返回用于日志输出的脱敏错误
+func (l *gormLogger) sanitizeError(err error) error {
+	if l.sanitizer == nil {
+		return err
+	}
+	if msg := l.sanitizer.Sanitize(err.Error()); msg != err.Error() {
+		return errors.New(msg)
+	}
+	return err
 }
 
 // removeFilePath 去除日志消息中的文件路径
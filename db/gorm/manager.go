@@ -15,6 +15,11 @@ import (
 type Manager struct {
 	clients map[string]*Client
 	mu      sync.RWMutex
+	// 慢查询回调（对之后注册的客户端同样生效）
+	slowHooks []SlowQueryHook
+	// 租户 -> 数据库名称（库级租户隔离）
+	tenantDatabases       map[string]string
+	defaultTenantDatabase string
 }
 
 // NewManager 创建 GORM 管理器
@@ -60,6 +65,26 @@ func NewManager(config *GormManagerConfig) (*Manager, error) {
 		return nil, fmt.Errorf("no databases configured or all database connections failed")
 	}
 
+	if routing := config.TenantRouting; routing != nil {
+		for tenantID, name := range routing.Databases {
+			if _, exists := manager.clients[name]; !exists {
+				_ = manager.Close()
+				return nil, fmt.Errorf("tenant %s routes to unknown database: %s", tenantID, name)
+			}
+		}
+		if routing.Default != "" {
+			if _, exists := manager.clients[routing.Default]; !exists {
+				_ = manager.Close()
+				return nil, fmt.Errorf("default tenant database not found: %s", routing.Default)
+			}
+		}
+		manager.tenantDatabases = make(map[string]string, len(routing.Databases))
+		for tenantID, name := range routing.Databases {
+			manager.tenantDatabases[tenantID] = name
+		}
+		manager.defaultTenantDatabase = routing.Default
+	}
+
 	logger.Info(ctx, "GORM Manager initialized successfully: total_clients=%d", len(manager.clients))
 
 	return manager, nil
@@ -118,6 +143,9 @@ func (m *Manager) RegisterClient(config *GormConfig) error {
 		_ = client.Close()
 		return fmt.Errorf("gorm client already exists: name=%s", config.Name)
 	}
+	for _, hook := range m.slowHooks {
+		client.OnSlowQuery(hook)
+	}
 	m.clients[config.Name] = client
 	logger.Info(ctx, "GORM client registered successfully: name=%s", config.Name)
 
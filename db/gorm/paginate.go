@@ -0,0 +1,136 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/team-dandelion/quickgo/pagination"
+)
+
+// Paginate 按排序键进行游标分页查询（keyset pagination），db 可携带 Model/Where 等查询条件
+// 与 OFFSET 分页不同，查询耗时不随翻页深度增长，排序列应有对应的联合索引
+func Paginate[T any, K any](ctx context.Context, db *gorm.DB, keyset *pagination.Keyset[T, K], req pagination.Request) (*pagination.Page[T], error) {
+	if err := keyset.Validate(); err != nil {
+		return nil, err
+	}
+	after, err := keyset.After(req)
+	if err != nil {
+		return nil, err
+	}
+
+	query := db.WithContext(ctx)
+	if after != nil {
+		condition, err := keysetCondition(keyset.Sort, after)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(condition)
+	}
+	for _, key := range keyset.Sort {
+		query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: key.Column}, Desc: key.Desc})
+	}
+
+	limit := req.PageLimit()
+	var items []T
+	if err := query.Limit(limit + 1).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return keyset.BuildPage(items, limit)
+}
+
+// keysetCondition 将游标条件转换为 GORM 表达式
+func keysetCondition(sort []pagination.SortKey, after []interface{}) (clause.Expression, error) {
+	groups, err := pagination.Conditions(sort, after)
+	if err != nil {
+		return nil, err
+	}
+	ors := make([]clause.Expression, 0, len(groups))
+	for _, group := range groups {
+		ands := make([]clause.Expression, 0, len(group))
+		for _, cmp := range group {
+			column := clause.Column{Name: cmp.Column}
+			switch cmp.Op {
+			case ">":
+				ands = append(ands, clause.Gt{Column: column, Value: cmp.Value})
+			case "<":
+				ands = append(ands, clause.Lt{Column: column, Value: cmp.Value})
+			default:
+				ands = append(ands, clause.Eq{Column: column, Value: cmp.Value})
+			}
+		}
+		ors = append(ors, clause.And(ands...))
+	}
+	return clause.Or(ors...), nil
+}
+
+// PageScope 页码分页查询作用域：应用过滤条件、排序与 LIMIT/OFFSET
+// 列名来自 pagination.QuerySpec 白名单并以标识符方式引用，用法：db.Scopes(PageScope(req)).Find(&items)
+func PageScope(req *pagination.PageRequest) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Scopes(FilterScope(req.Filters))
+		for _, key := range req.Sort {
+			db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: key.Column}, Desc: key.Desc})
+		}
+		return db.Limit(req.PageSize).Offset(req.Offset())
+	}
+}
+
+// FilterScope 过滤条件作用域（不含排序与分页，可用于统计总数）
+func FilterScope(filters []pagination.Filter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, filter := range filters {
+			db = db.Where(filterExpression(filter))
+		}
+		return db
+	}
+}
+
+// FindPage 按页码分页查询并统计总数，db 可携带 Model/Where 等查询条件
+func FindPage[T any](ctx context.Context, db *gorm.DB, req *pagination.PageRequest) (*pagination.PageResponse[T], error) {
+	query := db.WithContext(ctx).Scopes(FilterScope(req.Filters))
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+	var items []T
+	if total > int64(req.Offset()) {
+		if err := db.WithContext(ctx).Scopes(PageScope(req)).Find(&items).Error; err != nil {
+			return nil, err
+		}
+	}
+	return pagination.NewPageResponse(req, items, total), nil
+}
+
+// filterExpression 将过滤条件转换为 GORM 表达式
+func filterExpression(filter pagination.Filter) clause.Expression {
+	column := clause.Column{Name: filter.Column}
+	var value interface{}
+	if len(filter.Values) > 0 {
+		value = filter.Values[0]
+	}
+	switch filter.Op {
+	case pagination.OpNe:
+		return clause.Neq{Column: column, Value: value}
+	case pagination.OpGt:
+		return clause.Gt{Column: column, Value: value}
+	case pagination.OpGte:
+		return clause.Gte{Column: column, Value: value}
+	case pagination.OpLt:
+		return clause.Lt{Column: column, Value: value}
+	case pagination.OpLte:
+		return clause.Lte{Column: column, Value: value}
+	case pagination.OpLike:
+		return clause.Like{Column: column, Value: "%" + fmt.Sprint(value) + "%"}
+	case pagination.OpIn:
+		values := make([]interface{}, len(filter.Values))
+		for i, v := range filter.Values {
+			values[i] = v
+		}
+		return clause.IN{Column: column, Values: values}
+	default:
+		return clause.Eq{Column: column, Value: value}
+	}
+}
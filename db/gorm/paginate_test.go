@@ -0,0 +1,111 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/team-dandelion/quickgo/pagination"
+)
+
+type messageKey struct {
+	Body string
+	ID   uint
+}
+
+func TestPaginateWalksAllPagesWithCursor(t *testing.T) {
+	manager := newTxTestManager(t)
+	db, _ := manager.GetDB("main")
+	// 重复的 body 验证多列排序键的稳定性
+	for i := 0; i < 7; i++ {
+		if err := db.Create(&txMessage{Body: fmt.Sprintf("b%d", i%3)}).Error; err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	codec, err := pagination.NewCodec(&pagination.Config{Secret: "test"})
+	if err != nil {
+		t.Fatalf("NewCodec failed: %v", err)
+	}
+	keyset := &pagination.Keyset[txMessage, messageKey]{
+		Scope: "messages",
+		Codec: codec,
+		Sort:  []pagination.SortKey{{Column: "body", Desc: true}, {Column: "id"}},
+		Key:   func(m txMessage) messageKey { return messageKey{Body: m.Body, ID: m.ID} },
+	}
+
+	ctx := context.Background()
+	var (
+		got    []string
+		req    = pagination.Request{Limit: 3}
+		pages  int
+		lastID = map[string]uint{}
+	)
+	for {
+		page, err := Paginate(ctx, db.Model(&txMessage{}), keyset, req)
+		if err != nil {
+			t.Fatalf("Paginate failed: %v", err)
+		}
+		pages++
+		for _, m := range page.Items {
+			if m.ID <= lastID[m.Body] {
+				t.Fatalf("ids within same body should ascend: %v", page.Items)
+			}
+			lastID[m.Body] = m.ID
+			got = append(got, m.Body)
+		}
+		if !page.HasMore {
+			if page.NextCursor != "" {
+				t.Fatal("last page should not have a cursor")
+			}
+			break
+		}
+		req.Cursor = page.NextCursor
+	}
+	if pages != 3 || len(got) != 7 {
+		t.Fatalf("expected 7 rows over 3 pages, got %d rows over %d pages", len(got), pages)
+	}
+	if got[0] != "b2" || got[6] != "b0" {
+		t.Fatalf("unexpected order %v", got)
+	}
+
+	_, err = Paginate(ctx, db.Model(&txMessage{}), keyset, pagination.Request{Cursor: "forged.cursor"})
+	if !errors.Is(err, pagination.ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestFindPageAppliesFiltersSortAndOffset(t *testing.T) {
+	manager := newTxTestManager(t)
+	db, _ := manager.GetDB("main")
+	for i := 0; i < 7; i++ {
+		if err := db.Create(&txMessage{Body: fmt.Sprintf("b%d", i%3)}).Error; err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	values, _ := url.ParseQuery("page=2&page_size=2&sort=-id&filter=body:in:b0|b1")
+	req, err := pagination.ParsePageRequest(values, &pagination.QuerySpec{
+		Sortable:   map[string]string{"id": "id"},
+		Filterable: map[string]string{"body": "body"},
+	})
+	if err != nil {
+		t.Fatalf("ParsePageRequest failed: %v", err)
+	}
+	page, err := FindPage[txMessage](context.Background(), db.Model(&txMessage{}), req)
+	if err != nil {
+		t.Fatalf("FindPage failed: %v", err)
+	}
+	// b0/b1 对应 id 1,2,4,5,7，降序第二页为 4,2
+	if page.Total != 5 || page.TotalPages != 3 || len(page.Items) != 2 || page.Items[0].ID != 4 || page.Items[1].ID != 2 {
+		t.Fatalf("unexpected page %+v", page)
+	}
+
+	req.Page = 4
+	page, err = FindPage[txMessage](context.Background(), db.Model(&txMessage{}), req)
+	if err != nil || len(page.Items) != 0 || page.Total != 5 {
+		t.Fatalf("expected empty page past the end, got %+v, err=%v", page, err)
+	}
+}
@@ -0,0 +1,186 @@
+package gorm
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// route 读写路由偏好
+type route int
+
+const (
+	routeDefault route = iota
+	routeMaster
+	routeReplica
+)
+
+// dbresolver 在 Statement.Settings 中记录显式读写模式使用的键
+const (
+	resolverWriteSetting = "gorm:db_resolver:write"
+	resolverReadSetting  = "gorm:db_resolver:read"
+)
+
+type routeKey struct{}
+
+type stickyKey struct{}
+
+// WithMaster 返回强制路由到主库的 context（读己之写）
+// 通过 Client.DB(ctx) / db.WithContext(ctx) 执行的查询均使用主库
+func WithMaster(ctx context.Context) context.Context {
+	return context.WithValue(ctx, routeKey{}, routeMaster)
+}
+
+// WithReplica 返回强制路由到从库的 context（优先级高于写后粘滞）
+func WithReplica(ctx context.Context) context.Context {
+	return context.WithValue(ctx, routeKey{}, routeReplica)
+}
+
+// WithStickyKey 为 context 设置粘滞键（如用户 ID、会话 ID）
+// 配置 StickyMasterAfterWrite 后，同一粘滞键写入后的一段时间内读请求路由到主库，避免主从延迟读到旧数据
+func WithStickyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, stickyKey{}, key)
+}
+
+func routeFromContext(ctx context.Context) route {
+	if ctx == nil {
+		return routeDefault
+	}
+	if r, ok := ctx.Value(routeKey{}).(route); ok {
+		return r
+	}
+	return routeDefault
+}
+
+func stickyKeyFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	key, _ := ctx.Value(stickyKey{}).(string)
+	return key
+}
+
+// UseMaster 路由到主库的 Scope：db.Scopes(gorm.UseMaster).Find(&users)
+func UseMaster(db *gorm.DB) *gorm.DB {
+	return db.Clauses(dbresolver.Write)
+}
+
+// UseReplica 路由到从库的 Scope：db.Scopes(gorm.UseReplica).Find(&users)
+func UseReplica(db *gorm.DB) *gorm.DB {
+	return db.Clauses(dbresolver.Read)
+}
+
+// stickyTracker 记录粘滞键最近一次写入时间
+type stickyTracker struct {
+	window time.Duration
+	mu     sync.Mutex
+	writes map[string]time.Time
+}
+
+// stickyCleanupThreshold 记录数超过该值时在写入路径清理过期记录
+const stickyCleanupThreshold = 4096
+
+func newStickyTracker(window time.Duration) *stickyTracker {
+	return &stickyTracker{window: window, writes: make(map[string]time.Time)}
+}
+
+func (s *stickyTracker) markWrite(key string) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.writes) >= stickyCleanupThreshold {
+		for k, at := range s.writes {
+			if now.Sub(at) >= s.window {
+				delete(s.writes, k)
+			}
+		}
+	}
+	s.writes[key] = now
+}
+
+func (s *stickyTracker) active(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	at, ok := s.writes[key]
+	if !ok {
+		return false
+	}
+	if time.Since(at) >= s.window {
+		delete(s.writes, key)
+		return false
+	}
+	return true
+}
+
+// registerRoutingCallbacks 注册读写路由回调（仅在配置从库时调用）
+// 读操作根据 context 中的路由偏好与粘滞状态设置 dbresolver 的读写模式；写操作记录粘滞键的写入时间
+func (c *Client) registerRoutingCallbacks(db *gorm.DB) error {
+	applyRoute := func(db *gorm.DB) {
+		if db.Statement == nil {
+			return
+		}
+		// 显式的 Clauses(dbresolver.Write/Read) 或 UseMaster/UseReplica Scope 优先
+		if _, ok := db.Statement.Settings.Load(resolverWriteSetting); ok {
+			return
+		}
+		if _, ok := db.Statement.Settings.Load(resolverReadSetting); ok {
+			return
+		}
+		switch routeFromContext(db.Statement.Context) {
+		case routeMaster:
+			dbresolver.Write.ModifyStatement(db.Statement)
+		case routeReplica:
+			dbresolver.Read.ModifyStatement(db.Statement)
+		default:
+			if c.sticky != nil {
+				if key := stickyKeyFromContext(db.Statement.Context); key != "" && c.sticky.active(key) {
+					dbresolver.Write.ModifyStatement(db.Statement)
+				}
+			}
+		}
+	}
+	// dbresolver 的回调同样注册为 Before("*")，gorm 将后注册的 Before("*") 回调排在前面，
+	// 因此需在 dbresolver 注册之后调用，保证路由偏好先于 gorm:db_resolver 生效
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("*").Register("quickgo:route_query", applyRoute); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("*").Register("quickgo:route_row", applyRoute); err != nil {
+		return err
+	}
+	if err := callbacks.Raw().Before("*").Register("quickgo:route_raw", applyRoute); err != nil {
+		return err
+	}
+
+	if c.sticky == nil {
+		return nil
+	}
+	markWrite := func(db *gorm.DB) {
+		if db.Error != nil || db.Statement == nil {
+			return
+		}
+		if key := stickyKeyFromContext(db.Statement.Context); key != "" {
+			c.sticky.markWrite(key)
+		}
+	}
+	markRawWrite := func(db *gorm.DB) {
+		if sql := strings.TrimSpace(db.Statement.SQL.String()); len(sql) >= 6 && strings.EqualFold(sql[:6], "select") {
+			return
+		}
+		markWrite(db)
+	}
+	if err := callbacks.Create().After("gorm:create").Register("quickgo:sticky_create", markWrite); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Register("quickgo:sticky_update", markWrite); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("gorm:delete").Register("quickgo:sticky_delete", markWrite); err != nil {
+		return err
+	}
+	return callbacks.Raw().After("gorm:raw").Register("quickgo:sticky_raw", markRawWrite)
+}
@@ -0,0 +1,138 @@
+package gorm
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/team-dandelion/quickgo/json"
+	"github.com/team-dandelion/quickgo/types"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type routeRecord struct {
+	ID   uint `gorm:"primaryKey"`
+	Body string
+}
+
+// newRoutingTestClient 主库与从库为两个独立的 SQLite 文件，通过读到的数据判断路由目标
+func newRoutingTestClient(t *testing.T, sticky types.Duration) *Client {
+	t.Helper()
+	dir := t.TempDir()
+	replicaPath := filepath.Join(dir, "replica.db")
+	replica, err := gorm.Open(sqlite.Open(replicaPath), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open replica failed: %v", err)
+	}
+	if err := replica.AutoMigrate(&routeRecord{}); err != nil {
+		t.Fatalf("migrate replica failed: %v", err)
+	}
+	replica.Create(&routeRecord{ID: 1, Body: "replica"})
+	if sqlDB, err := replica.DB(); err == nil {
+		_ = sqlDB.Close()
+	}
+
+	client, err := NewClient(&GormConfig{
+		Name:                   "routing",
+		Master:                 MasterConfig{Type: DatabaseTypeSQLite, Database: filepath.Join(dir, "master.db")},
+		Slaves:                 []SlaveConfig{{Database: replicaPath}},
+		StickyMasterAfterWrite: sticky,
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	if err := client.GetDB().Exec("CREATE TABLE route_records (id integer primary key, body text)").Error; err != nil {
+		t.Fatalf("create master table failed: %v", err)
+	}
+	return client
+}
+
+func readBody(t *testing.T, db *gorm.DB) string {
+	t.Helper()
+	var record routeRecord
+	if err := db.Order("id desc").First(&record).Error; err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	return record.Body
+}
+
+func TestRoutingHints(t *testing.T) {
+	client := newRoutingTestClient(t, 0)
+	ctx := context.Background()
+	if err := client.DB(ctx).Create(&routeRecord{ID: 2, Body: "master"}).Error; err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	if got := readBody(t, client.DB(ctx)); got != "replica" {
+		t.Fatalf("expected default read from replica, got %s", got)
+	}
+	if got := readBody(t, client.DB(WithMaster(ctx))); got != "master" {
+		t.Fatalf("expected WithMaster read from master, got %s", got)
+	}
+	if got := readBody(t, client.GetDB().WithContext(WithMaster(ctx))); got != "master" {
+		t.Fatalf("expected WithMaster to apply through WithContext, got %s", got)
+	}
+	if got := readBody(t, client.DB(ctx).Scopes(UseMaster)); got != "master" {
+		t.Fatalf("expected UseMaster scope read from master, got %s", got)
+	}
+	if got := readBody(t, client.DB(WithMaster(ctx)).Scopes(UseReplica)); got != "replica" {
+		t.Fatalf("expected UseReplica scope read from replica, got %s", got)
+	}
+
+	var count int64
+	if err := client.DB(WithMaster(ctx)).Raw("SELECT COUNT(*) FROM route_records WHERE body = 'master'").Scan(&count).Error; err != nil || count != 1 {
+		t.Fatalf("expected raw select routed to master, got %d, %v", count, err)
+	}
+}
+
+func TestStickyMasterAfterWrite(t *testing.T) {
+	client := newRoutingTestClient(t, types.Duration(200*time.Millisecond))
+	ctx := WithStickyKey(context.Background(), "user-1")
+	other := WithStickyKey(context.Background(), "user-2")
+
+	if got := readBody(t, client.DB(ctx)); got != "replica" {
+		t.Fatalf("expected read from replica before write, got %s", got)
+	}
+	if err := client.DB(ctx).Create(&routeRecord{ID: 2, Body: "master"}).Error; err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if got := readBody(t, client.DB(ctx)); got != "master" {
+		t.Fatalf("expected sticky read from master after write, got %s", got)
+	}
+	if got := readBody(t, client.DB(other)); got != "replica" {
+		t.Fatalf("expected other sticky key to read from replica, got %s", got)
+	}
+	if got := readBody(t, client.DB(WithReplica(ctx))); got != "replica" {
+		t.Fatalf("expected WithReplica to override stickiness, got %s", got)
+	}
+
+	time.Sleep(250 * time.Millisecond)
+	if got := readBody(t, client.DB(ctx)); got != "replica" {
+		t.Fatalf("expected read from replica after sticky window, got %s", got)
+	}
+
+	if err := client.DB(ctx).Exec("UPDATE route_records SET body = 'updated' WHERE id = 2").Error; err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+	if got := readBody(t, client.DB(ctx)); got != "updated" {
+		t.Fatalf("expected raw exec to start sticky window, got %s", got)
+	}
+}
+
+func TestInvalidStickyMasterAfterWrite(t *testing.T) {
+	var config GormConfig
+	err := json.Unmarshal([]byte(`{"stickyMasterAfterWrite":"soon"}`), &config)
+	if err == nil {
+		t.Fatal("expected invalid StickyMasterAfterWrite to be rejected")
+	}
+	if err := json.Unmarshal([]byte(`{"stickyMasterAfterWrite":"500ms"}`), &config); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if config.StickyMasterAfterWrite.Std() != 500*time.Millisecond {
+		t.Fatalf("expected 500ms, got %s", config.StickyMasterAfterWrite)
+	}
+}
@@ -0,0 +1,43 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+)
+
+// RowsIterator 将 GORM 查询结果 (*sql.Rows) 适配为逐条读取的迭代器，
+// 接口与 *mongo.Cursor 一致，可直接用于 http.StreamJSONArray 流式导出大量记录
+type RowsIterator struct {
+	db   *gorm.DB
+	rows *sql.Rows
+}
+
+// NewRowsIterator 创建迭代器，rows 通常来自 db.Model(...).Rows()，Decode 时按 db 的模型映射列
+func NewRowsIterator(db *gorm.DB, rows *sql.Rows) *RowsIterator {
+	return &RowsIterator{db: db, rows: rows}
+}
+
+// Next 移动到下一行，ctx 已取消或没有更多数据时返回 false
+func (it *RowsIterator) Next(ctx context.Context) bool {
+	if ctx != nil && ctx.Err() != nil {
+		return false
+	}
+	return it.rows.Next()
+}
+
+// Decode 将当前行扫描到 v（结构体指针或 map）
+func (it *RowsIterator) Decode(v interface{}) error {
+	return it.db.ScanRows(it.rows, v)
+}
+
+// Err 返回迭代过程中的错误
+func (it *RowsIterator) Err() error {
+	return it.rows.Err()
+}
+
+// Close 关闭结果集，释放数据库连接
+func (it *RowsIterator) Close(context.Context) error {
+	return it.rows.Close()
+}
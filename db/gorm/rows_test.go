@@ -0,0 +1,38 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRowsIteratorDecodesEachRow(t *testing.T) {
+	manager := newTxTestManager(t)
+	db, _ := manager.GetDB("main")
+	for _, body := range []string{"a", "b", "c"} {
+		if err := db.Create(&txMessage{Body: body}).Error; err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	rows, err := db.Model(&txMessage{}).Order("id").Rows()
+	if err != nil {
+		t.Fatalf("Rows failed: %v", err)
+	}
+	it := NewRowsIterator(db, rows)
+	defer it.Close(context.Background())
+
+	var bodies []string
+	for it.Next(context.Background()) {
+		var msg txMessage
+		if err := it.Decode(&msg); err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+		bodies = append(bodies, msg.Body)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	if len(bodies) != 3 || bodies[0] != "a" || bodies[2] != "c" {
+		t.Fatalf("unexpected rows %v", bodies)
+	}
+}
@@ -0,0 +1,326 @@
+package gorm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// defaultSanitizeMask 默认脱敏替换值
+const defaultSanitizeMask = "***"
+
+var (
+	// emailPattern 邮箱地址
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	// phonePattern 电话号码（至少 10 位数字，可带国家码与 空格 / - 分隔，如 13800138000、+86 138-0013-8000、555-123-4567）
+	// 不匹配日期时间（2024-01-01 10:00:00）
+	phonePattern = regexp.MustCompile(`(?:\+\d{1,3}[\s-]?)?\b\d{3,4}[\s-]?\d{3,4}[\s-]?\d{4}\b`)
+)
+
+// LogSanitizeConfig SQL 日志脱敏配置，作用于 SQL 日志与慢查询回调的 SQL（TracingConfig.Statement 为 full 时也作用于 db.statement）
+// 配置后默认屏蔽全部字面量、邮箱与电话号码；关闭 Literals 可保留普通参数值，只屏蔽个人信息与指定列
+type LogSanitizeConfig struct {
+	// 是否屏蔽全部字面量（字符串与数字，LIMIT / OFFSET 后的数字除外），默认 true
+	Literals *bool `json:"literals" yaml:"literals" toml:"literals"`
+	// 是否屏蔽字符串中的邮箱地址，默认 true
+	Emails *bool `json:"emails" yaml:"emails" toml:"emails"`
+	// 是否屏蔽字符串中的电话号码，默认 true
+	Phones *bool `json:"phones" yaml:"phones" toml:"phones"`
+	// 需要屏蔽值的列名（大小写不敏感，不区分表，如 id_card、bank_card）
+	// 作用于比较条件（col = ?、col LIKE ?）、IN 列表、UPDATE SET 与 INSERT VALUES 中对应列的值
+	Columns []string `json:"columns" yaml:"columns" toml:"columns"`
+	// 额外的正则表达式，匹配字符串字面量中的内容并屏蔽
+	Patterns []string `json:"patterns" yaml:"patterns" toml:"patterns"`
+	// 脱敏替换值，默认 ***
+	Mask string `json:"mask" yaml:"mask" toml:"mask"`
+}
+
+// sqlSanitizer SQL 脱敏器，nil 表示不脱敏
+type sqlSanitizer struct {
+	literals bool
+	columns  map[string]struct{}
+	// 字符串字面量中需要屏蔽的内容（邮箱、电话号码、自定义正则）
+	patterns []*regexp.Regexp
+	mask     string
+	// 字符串字面量的引号（SQLite 的 Explain 使用双引号，其他数据库双引号为标识符）
+	stringQuotes string
+}
+
+// newSQLSanitizer 创建 SQL 脱敏器，config 为 nil 时返回 nil
+func newSQLSanitizer(config *LogSanitizeConfig, dbType DatabaseType) (*sqlSanitizer, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	s := &sqlSanitizer{
+		literals:     config.Literals == nil || *config.Literals,
+		columns:      make(map[string]struct{}, len(config.Columns)),
+		mask:         config.Mask,
+		stringQuotes: "'",
+	}
+	if s.mask == "" {
+		s.mask = defaultSanitizeMask
+	}
+	if dbType == DatabaseTypeSQLite {
+		s.stringQuotes = `'"`
+	}
+	for _, column := range config.Columns {
+		if i := strings.LastIndex(column, "."); i >= 0 {
+			column = column[i+1:]
+		}
+		if column = strings.ToLower(strings.TrimSpace(column)); column != "" {
+			s.columns[column] = struct{}{}
+		}
+	}
+	if config.Emails == nil || *config.Emails {
+		s.patterns = append(s.patterns, emailPattern)
+	}
+	if config.Phones == nil || *config.Phones {
+		s.patterns = append(s.patterns, phonePattern)
+	}
+	for _, pattern := range config.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid logSanitize pattern %q: %w", pattern, err)
+		}
+		s.patterns = append(s.patterns, re)
+	}
+	return s, nil
+}
+
+// sqlToken SQL 词法单元
+type sqlToken struct {
+	kind tokenKind
+	text string
+}
+
+type tokenKind int
+
+const (
+	tokenSpace tokenKind = iota
+	tokenString
+	tokenNumber
+	// tokenIdent 标识符或关键字（含带引号的标识符）
+	tokenIdent
+	tokenPunct
+)
+
+// sanitizeFrame 括号层级状态
+type sanitizeFrame struct {
+	// 屏蔽括号内全部字面量（指定列的 IN 列表）
+	maskAll bool
+	// INSERT 列名列表
+	columnList bool
+	// VALUES 元组，pos 为当前值的位置
+	tuple bool
+	pos   int
+}
+
+// Sanitize 返回脱敏后的 SQL
+func (s *sqlSanitizer) Sanitize(sql string) string {
+	if s == nil || sql == "" {
+		return sql
+	}
+
+	tokens := s.tokenize(sql)
+	var (
+		out strings.Builder
+		// 最近两个非空白词法单元
+		prev, prev2 *sqlToken
+		stack       []sanitizeFrame
+		insertCols  []string
+		// 0: 非 INSERT；1: 等待列名列表；2: 已读取列名列表
+		insertState int
+		afterValues bool
+	)
+	out.Grow(len(sql))
+
+	for i := range tokens {
+		token := &tokens[i]
+		switch token.kind {
+		case tokenSpace:
+			out.WriteString(token.text)
+			continue
+		case tokenString, tokenNumber:
+			if s.maskLiteral(token, prev, prev2, stack, insertCols) {
+				if token.kind == tokenString {
+					quote := token.text[:1]
+					out.WriteString(quote + s.mask + quote)
+				} else {
+					out.WriteString(s.mask)
+				}
+			} else if token.kind == tokenString {
+				out.WriteString(s.scrub(token.text))
+			} else {
+				out.WriteString(token.text)
+			}
+		case tokenIdent:
+			switch {
+			case isKeyword(token, "INSERT"):
+				insertState, insertCols = 1, nil
+			case isKeyword(token, "VALUES"):
+				afterValues = true
+			case isKeyword(token, "ON"), isKeyword(token, "RETURNING"), isKeyword(token, "SELECT"):
+				afterValues = false
+			}
+			if n := len(stack); n > 0 && stack[n-1].columnList {
+				insertCols = append(insertCols, identName(token.text))
+			}
+			out.WriteString(token.text)
+		case tokenPunct:
+			switch token.text {
+			case "(":
+				frame := sanitizeFrame{}
+				switch {
+				case isKeyword(prev, "IN") && s.isMaskedColumn(prev2):
+					frame.maskAll = true
+				case len(stack) == 0 && afterValues:
+					frame.tuple = true
+				case len(stack) == 0 && insertState == 1:
+					frame.columnList = true
+					insertState = 2
+				}
+				stack = append(stack, frame)
+			case ")":
+				if len(stack) > 0 {
+					stack = stack[:len(stack)-1]
+				}
+			case ",":
+				if n := len(stack); n > 0 && stack[n-1].tuple {
+					stack[n-1].pos++
+				}
+			}
+			out.WriteString(token.text)
+		}
+		prev2, prev = prev, token
+	}
+	return out.String()
+}
+
+// maskLiteral 判断字面量是否整体屏蔽
+func (s *sqlSanitizer) maskLiteral(token, prev, prev2 *sqlToken, stack []sanitizeFrame, insertCols []string) bool {
+	if s.literals {
+		return token.kind == tokenString || !(isKeyword(prev, "LIMIT") || isKeyword(prev, "OFFSET"))
+	}
+	if len(s.columns) == 0 {
+		return false
+	}
+	if isComparison(prev) && s.isMaskedColumn(prev2) {
+		return true
+	}
+	if n := len(stack); n > 0 {
+		frame := stack[n-1]
+		if frame.maskAll {
+			return true
+		}
+		if frame.tuple && frame.pos < len(insertCols) {
+			_, ok := s.columns[insertCols[frame.pos]]
+			return ok
+		}
+	}
+	return false
+}
+
+// scrub 屏蔽字符串字面量中的邮箱、电话号码与自定义正则匹配的内容
+func (s *sqlSanitizer) scrub(text string) string {
+	for _, re := range s.patterns {
+		text = re.ReplaceAllString(text, s.mask)
+	}
+	return text
+}
+
+// isMaskedColumn 判断词法单元是否为需要屏蔽的列
+func (s *sqlSanitizer) isMaskedColumn(token *sqlToken) bool {
+	if token == nil || token.kind != tokenIdent || len(s.columns) == 0 {
+		return false
+	}
+	_, ok := s.columns[identName(token.text)]
+	return ok
+}
+
+// tokenize 将 SQL 拆分为词法单元
+func (s *sqlSanitizer) tokenize(sql string) []sqlToken {
+	var tokens []sqlToken
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		start := i
+		var kind tokenKind
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			for i < len(sql) && (sql[i] == ' ' || sql[i] == '\t' || sql[i] == '\n' || sql[i] == '\r') {
+				i++
+			}
+			kind = tokenSpace
+		case strings.IndexByte(s.stringQuotes, c) >= 0:
+			i = quotedEnd(sql, i)
+			kind = tokenString
+		case c == '`' || c == '"':
+			i = quotedEnd(sql, i)
+			kind = tokenIdent
+		case c >= '0' && c <= '9':
+			for i < len(sql) && (isWordByte(sql[i]) || sql[i] == '.') {
+				i++
+			}
+			kind = tokenNumber
+		case isWordByte(c):
+			for i < len(sql) && isWordByte(sql[i]) {
+				i++
+			}
+			kind = tokenIdent
+		case strings.IndexByte("=<>!", c) >= 0:
+			for i < len(sql) && strings.IndexByte("=<>!", sql[i]) >= 0 {
+				i++
+			}
+			kind = tokenPunct
+		default:
+			i++
+			kind = tokenPunct
+		}
+		tokens = append(tokens, sqlToken{kind: kind, text: sql[start:i]})
+	}
+	return tokens
+}
+
+// quotedEnd 返回引号内容的结束位置（两个连续引号视为转义）
+func quotedEnd(sql string, start int) int {
+	quote := sql[start]
+	for i := start + 1; i < len(sql); i++ {
+		if sql[i] != quote {
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(sql)
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// identName 返回去除引号的小写标识符
+func identName(text string) string {
+	return strings.ToLower(strings.Trim(text, "`\""))
+}
+
+func isKeyword(token *sqlToken, keyword string) bool {
+	return token != nil && token.kind == tokenIdent && strings.EqualFold(token.text, keyword)
+}
+
+// isComparison 判断词法单元是否为比较或赋值运算符
+func isComparison(token *sqlToken) bool {
+	if token == nil {
+		return false
+	}
+	switch token.kind {
+	case tokenPunct:
+		return strings.IndexByte("=<>!", token.text[0]) >= 0
+	case tokenIdent:
+		return isKeyword(token, "LIKE") || isKeyword(token, "ILIKE")
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package gorm
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSQLSanitizerMasksLiteralsByDefault(t *testing.T) {
+	s, err := newSQLSanitizer(&LogSanitizeConfig{}, DatabaseTypeMySQL)
+	if err != nil {
+		t.Fatalf("newSQLSanitizer failed: %v", err)
+	}
+
+	got := s.Sanitize("SELECT * FROM `users` WHERE `email` = 'a@b.com' AND age > 18 AND `deleted_at` IS NULL ORDER BY `id` LIMIT 10 OFFSET 20")
+	want := "SELECT * FROM `users` WHERE `email` = '***' AND age > *** AND `deleted_at` IS NULL ORDER BY `id` LIMIT 10 OFFSET 20"
+	if got != want {
+		t.Fatalf("unexpected sanitized sql:\n got: %s\nwant: %s", got, want)
+	}
+
+	// 转义引号不截断字面量
+	if got := s.Sanitize("UPDATE `users` SET `name`='O''Brien' WHERE `id` = 7"); got != "UPDATE `users` SET `name`='***' WHERE `id` = ***" {
+		t.Fatalf("unexpected sanitized sql: %s", got)
+	}
+
+	var nilSanitizer *sqlSanitizer
+	if got := nilSanitizer.Sanitize("SELECT 1"); got != "SELECT 1" {
+		t.Fatalf("nil sanitizer should keep sql, got %s", got)
+	}
+}
+
+func TestSQLSanitizerScrubsPIIAndColumns(t *testing.T) {
+	literals := false
+	s, err := newSQLSanitizer(&LogSanitizeConfig{
+		Literals: &literals,
+		Columns:  []string{"users.ID_Card", "bank_card"},
+		Patterns: []string{`VIP-\d+`},
+		Mask:     "?",
+	}, DatabaseTypePostgreSQL)
+	if err != nil {
+		t.Fatalf("newSQLSanitizer failed: %v", err)
+	}
+
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{
+			sql:  `SELECT * FROM "users" WHERE "note" = 'call 138-0013-8000 or mail bob@example.com' AND "created_at" > '2024-01-01 10:00:00'`,
+			want: `SELECT * FROM "users" WHERE "note" = 'call ? or mail ?' AND "created_at" > '2024-01-01 10:00:00'`,
+		},
+		{
+			sql:  `SELECT * FROM "users" WHERE "users"."id_card" = '110101199001011234' AND "bank_card" IN ('6222', '6228') AND "level" IN (1, 2) AND "code" LIKE 'VIP-42%'`,
+			want: `SELECT * FROM "users" WHERE "users"."id_card" = '?' AND "bank_card" IN ('?', '?') AND "level" IN (1, 2) AND "code" LIKE '?%'`,
+		},
+		{
+			sql:  `INSERT INTO "users" ("name","id_card","age") VALUES ('bob','1101',30),('amy','1102',(SELECT 1)) RETURNING "id"`,
+			want: `INSERT INTO "users" ("name","id_card","age") VALUES ('bob','?',30),('amy','?',(SELECT 1)) RETURNING "id"`,
+		},
+		{
+			sql:  `UPDATE "users" SET "bank_card"='6222',"age"=31 WHERE "id" = 5`,
+			want: `UPDATE "users" SET "bank_card"='?',"age"=31 WHERE "id" = 5`,
+		},
+	}
+	for _, tt := range tests {
+		if got := s.Sanitize(tt.sql); got != tt.want {
+			t.Fatalf("unexpected sanitized sql:\n got: %s\nwant: %s", got, tt.want)
+		}
+	}
+}
+
+func TestSQLSanitizerSQLiteDoubleQuotedStrings(t *testing.T) {
+	literals := false
+	s, err := newSQLSanitizer(&LogSanitizeConfig{Literals: &literals, Columns: []string{"phone"}}, DatabaseTypeSQLite)
+	if err != nil {
+		t.Fatalf("newSQLSanitizer failed: %v", err)
+	}
+	got := s.Sanitize("SELECT * FROM `users` WHERE `phone` = \"12345\" AND `name` = \"bob@example.com\"")
+	want := "SELECT * FROM `users` WHERE `phone` = \"***\" AND `name` = \"***\""
+	if got != want {
+		t.Fatalf("unexpected sanitized sql:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestNewSQLSanitizerRejectsInvalidPattern(t *testing.T) {
+	if _, err := newSQLSanitizer(&LogSanitizeConfig{Patterns: []string{"("}}, DatabaseTypeMySQL); err == nil {
+		t.Fatal("expected invalid pattern error")
+	}
+	if s, err := newSQLSanitizer(nil, DatabaseTypeMySQL); s != nil || err != nil {
+		t.Fatalf("nil config should disable sanitizer, got %v, %v", s, err)
+	}
+}
+
+func TestLogSanitizeAppliesToSlowQueryHooks(t *testing.T) {
+	client, err := NewClient(&GormConfig{
+		Name:        "sanitize",
+		Master:      MasterConfig{Type: DatabaseTypeSQLite, Database: filepath.Join(t.TempDir(), "sanitize.db")},
+		LogSanitize: &LogSanitizeConfig{},
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	var queries []SlowQuery
+	client.OnSlowQuery(func(ctx context.Context, q SlowQuery) {
+		queries = append(queries, q)
+	})
+	client.GetDB().Config.Logger.Trace(context.Background(), time.Now().Add(-time.Second), func() (string, int64) {
+		return "SELECT * FROM `users` WHERE `email` = \"bob@example.com\"", 1
+	}, nil)
+
+	if len(queries) != 1 || queries[0].SQL != "SELECT * FROM `users` WHERE `email` = \"***\"" {
+		t.Fatalf("unexpected slow queries: %+v", queries)
+	}
+}
@@ -0,0 +1,187 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"gorm.io/driver/clickhouse"
+	gormmysql "gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/driver/sqlserver"
+	"gorm.io/gorm"
+
+	// 注册 pgx database/sql 驱动（gorm postgres 驱动默认不经过 sql.Open）
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+var sessionVarNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// driverName 返回各数据库类型在 database/sql 中注册的驱动名
+func driverName(dbType DatabaseType) (string, error) {
+	switch {
+	case dbType.IsMySQLCompatible():
+		return gormmysql.DefaultDriverName, nil
+	case dbType == DatabaseTypePostgreSQL:
+		return "pgx", nil
+	case dbType == DatabaseTypeSQLite:
+		return sqlite.DriverName, nil
+	case dbType == DatabaseTypeSQLServer:
+		return "sqlserver", nil
+	case dbType == DatabaseTypeClickHouse:
+		return "clickhouse", nil
+	default:
+		return "", fmt.Errorf("unsupported database type: %s", dbType)
+	}
+}
+
+// sessionStatements 将会话变量与初始化 SQL 转换为连接建立时执行的语句（变量按名称排序，保证顺序稳定）
+func sessionStatements(dbType DatabaseType, vars map[string]string, initSQL []string) ([]string, error) {
+	statements := make([]string, 0, len(vars)+len(initSQL))
+	if len(vars) > 0 {
+		var format string
+		switch {
+		case dbType.IsMySQLCompatible():
+			format = "SET SESSION %s = %s"
+		case dbType == DatabaseTypePostgreSQL:
+			format = "SET %s = %s"
+		default:
+			return nil, fmt.Errorf("session variables are not supported for database type %s, use initSQL or connection params instead", dbType)
+		}
+		names := make([]string, 0, len(vars))
+		for name := range vars {
+			if !sessionVarNamePattern.MatchString(name) {
+				return nil, fmt.Errorf("invalid session variable name: %q", name)
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			statements = append(statements, fmt.Sprintf(format, name, vars[name]))
+		}
+	}
+	for _, stmt := range initSQL {
+		if stmt != "" {
+			statements = append(statements, stmt)
+		}
+	}
+	return statements, nil
+}
+
+// openSessionDialector 创建在每个新连接上执行初始化语句的方言
+// 无初始化语句时与 newDialector 行为一致
+func openSessionDialector(dbType DatabaseType, dsn string, statements []string) (gorm.Dialector, error) {
+	if len(statements) == 0 {
+		return newDialector(dbType, dsn)
+	}
+	name, err := driverName(dbType)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := openInitConnPool(name, dsn, statements)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case dbType.IsMySQLCompatible():
+		return gormmysql.New(gormmysql.Config{Conn: pool}), nil
+	case dbType == DatabaseTypePostgreSQL:
+		return postgres.New(postgres.Config{Conn: pool}), nil
+	case dbType == DatabaseTypeSQLite:
+		return sqlite.New(sqlite.Config{Conn: pool}), nil
+	case dbType == DatabaseTypeSQLServer:
+		return sqlserver.New(sqlserver.Config{Conn: pool}), nil
+	default:
+		return clickhouse.New(clickhouse.Config{Conn: pool}), nil
+	}
+}
+
+// openInitConnPool 基于驱动的 Connector 创建连接池，每个新建立的物理连接都会先执行初始化语句
+func openInitConnPool(driverName, dsn string, statements []string) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s driver: %w", driverName, err)
+	}
+	drv := db.Driver()
+	_ = db.Close()
+
+	var base driver.Connector
+	if dc, ok := drv.(driver.DriverContext); ok {
+		if base, err = dc.OpenConnector(dsn); err != nil {
+			return nil, fmt.Errorf("failed to create %s connector: %w", driverName, err)
+		}
+	} else {
+		base = dsnConnector{dsn: dsn, driver: drv}
+	}
+	return sql.OpenDB(&initConnector{Connector: base, statements: statements}), nil
+}
+
+// dsnConnector 适配未实现 driver.DriverContext 的驱动
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// initConnector 在连接建立后执行会话初始化语句，失败时关闭连接并返回错误
+type initConnector struct {
+	driver.Connector
+	statements []string
+}
+
+func (c *initConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, stmt := range c.statements {
+		if err := execOnConn(ctx, conn, stmt); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("failed to execute session init statement %q: %w", stmt, err)
+		}
+	}
+	return conn, nil
+}
+
+func execOnConn(ctx context.Context, conn driver.Conn, query string) error {
+	if execer, ok := conn.(driver.ExecerContext); ok {
+		_, err := execer.ExecContext(ctx, query, nil)
+		if !errors.Is(err, driver.ErrSkip) {
+			return err
+		}
+	}
+
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+	if preparer, ok := conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = conn.Prepare(query)
+	}
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	if execer, ok := stmt.(driver.StmtExecContext); ok {
+		_, err = execer.ExecContext(ctx, nil)
+		return err
+	}
+	_, err = stmt.Exec(nil)
+	return err
+}
@@ -0,0 +1,148 @@
+package gorm
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// PoolStats 主库连接池统计
+type PoolStats struct {
+	// 客户端名称
+	Name string `json:"name"`
+	// 最大打开连接数（0 表示不限制）
+	MaxOpen int `json:"maxOpen"`
+	// 当前打开的连接数（使用中 + 空闲）
+	Open int `json:"open"`
+	// 使用中的连接数
+	InUse int `json:"inUse"`
+	// 空闲连接数
+	Idle int `json:"idle"`
+	// 累计等待连接的次数
+	WaitCount int64 `json:"waitCount"`
+	// 累计等待连接的时间
+	WaitDuration time.Duration `json:"waitDuration"`
+	// 因超过最大空闲数、最大空闲时间、最大生存时间而关闭的连接数
+	MaxIdleClosed     int64 `json:"maxIdleClosed"`
+	MaxIdleTimeClosed int64 `json:"maxIdleTimeClosed"`
+	MaxLifetimeClosed int64 `json:"maxLifetimeClosed"`
+}
+
+// SlowQuery 慢查询事件
+type SlowQuery struct {
+	// 客户端名称
+	Name string `json:"name"`
+	// SQL 语句
+	SQL string `json:"sql"`
+	// 影响行数（-1 表示未知）
+	Rows int64 `json:"rows"`
+	// 执行耗时
+	Duration time.Duration `json:"duration"`
+	// 慢查询阈值
+	Threshold time.Duration `json:"threshold"`
+	// 执行错误（可能为 nil）
+	Err error `json:"-"`
+}
+
+// SlowQueryHook 慢查询回调，在查询所在协程中同步执行，应避免阻塞
+type SlowQueryHook func(ctx context.Context, query SlowQuery)
+
+// slowQueryHooks 慢查询回调列表（并发安全）
+type slowQueryHooks struct {
+	mu    sync.RWMutex
+	hooks []SlowQueryHook
+}
+
+func (h *slowQueryHooks) add(hook SlowQueryHook) {
+	if h == nil || hook == nil {
+		return
+	}
+	h.mu.Lock()
+	h.hooks = append(h.hooks, hook)
+	h.mu.Unlock()
+}
+
+func (h *slowQueryHooks) enabled() bool {
+	if h == nil {
+		return false
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.hooks) > 0
+}
+
+// fire 依次执行回调，回调 panic 时记录错误日志，不影响查询
+func (h *slowQueryHooks) fire(ctx context.Context, query SlowQuery) {
+	if h == nil {
+		return
+	}
+	h.mu.RLock()
+	hooks := h.hooks
+	h.mu.RUnlock()
+	for _, hook := range hooks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Error(ctx, "GORM slow query hook panic: name=%s, panic=%v", query.Name, r)
+				}
+			}()
+			hook(ctx, query)
+		}()
+	}
+}
+
+// Stats 返回主库连接池统计
+func (c *Client) Stats() PoolStats {
+	stats := PoolStats{Name: c.name}
+	if c.db == nil {
+		return stats
+	}
+	sqlDB, err := c.db.DB()
+	if err != nil {
+		return stats
+	}
+	s := sqlDB.Stats()
+	stats.MaxOpen = s.MaxOpenConnections
+	stats.Open = s.OpenConnections
+	stats.InUse = s.InUse
+	stats.Idle = s.Idle
+	stats.WaitCount = s.WaitCount
+	stats.WaitDuration = s.WaitDuration
+	stats.MaxIdleClosed = s.MaxIdleClosed
+	stats.MaxIdleTimeClosed = s.MaxIdleTimeClosed
+	stats.MaxLifetimeClosed = s.MaxLifetimeClosed
+	return stats
+}
+
+// OnSlowQuery 注册慢查询回调，执行耗时超过 SlowThreshold 的 SQL 会触发回调（不受 EnableLog 影响）
+func (c *Client) OnSlowQuery(hook SlowQueryHook) {
+	c.slowHooks.add(hook)
+}
+
+// Stats 返回所有客户端的连接池统计（key 为客户端名称）
+func (m *Manager) Stats() map[string]PoolStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stats := make(map[string]PoolStats, len(m.clients))
+	for name, client := range m.clients {
+		stats[name] = client.Stats()
+	}
+	return stats
+}
+
+// OnSlowQuery 为所有客户端注册慢查询回调（包括之后通过 RegisterClient 添加的客户端）
+func (m *Manager) OnSlowQuery(hook SlowQueryHook) {
+	if hook == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.slowHooks = append(m.slowHooks, hook)
+	for _, client := range m.clients {
+		client.OnSlowQuery(hook)
+	}
+}
@@ -0,0 +1,88 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newStatsTestManager(t *testing.T) *Manager {
+	t.Helper()
+	dir := t.TempDir()
+	manager, err := NewManager(&GormManagerConfig{Databases: []GormConfig{{
+		Name:        "main",
+		Master:      MasterConfig{Type: DatabaseTypeSQLite, Database: filepath.Join(dir, "main.db")},
+		MaxOpenConn: 4,
+	}}})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	t.Cleanup(func() { _ = manager.Close() })
+	return manager
+}
+
+func TestManagerStats(t *testing.T) {
+	manager := newStatsTestManager(t)
+	client, _ := manager.GetClient("main")
+	sqlDB, _ := client.GetDB().DB()
+	conn, err := sqlDB.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("acquire conn failed: %v", err)
+	}
+	defer conn.Close()
+
+	stats := manager.Stats()
+	got, ok := stats["main"]
+	if !ok {
+		t.Fatalf("expected stats for main, got %v", stats)
+	}
+	if got.Name != "main" || got.MaxOpen != 4 || got.InUse != 1 || got.Open != got.InUse+got.Idle {
+		t.Fatalf("unexpected pool stats: %+v", got)
+	}
+}
+
+func TestSlowQueryHookFiresWithLoggingDisabled(t *testing.T) {
+	manager := newStatsTestManager(t)
+
+	var queries []SlowQuery
+	manager.OnSlowQuery(func(ctx context.Context, q SlowQuery) {
+		queries = append(queries, q)
+	})
+	manager.OnSlowQuery(func(ctx context.Context, q SlowQuery) {
+		panic("hook panic must not break the query")
+	})
+
+	dir := t.TempDir()
+	if err := manager.RegisterClient(&GormConfig{
+		Name:   "late",
+		Master: MasterConfig{Type: DatabaseTypeSQLite, Database: filepath.Join(dir, "late.db")},
+	}); err != nil {
+		t.Fatalf("RegisterClient failed: %v", err)
+	}
+
+	queryErr := errors.New("boom")
+	for _, name := range []string{"main", "late"} {
+		client, _ := manager.GetClient(name)
+		l := client.GetDB().Config.Logger
+		// 未超过阈值不触发
+		l.Trace(context.Background(), time.Now(), func() (string, int64) { return "SELECT 1", 1 }, nil)
+		l.Trace(context.Background(), time.Now().Add(-time.Second), func() (string, int64) {
+			return "SELECT * FROM users", 3
+		}, queryErr)
+	}
+
+	if len(queries) != 2 {
+		t.Fatalf("expected 2 slow queries, got %d: %+v", len(queries), queries)
+	}
+	for i, name := range []string{"main", "late"} {
+		q := queries[i]
+		if q.Name != name || q.SQL != "SELECT * FROM users" || q.Rows != 3 || !errors.Is(q.Err, queryErr) {
+			t.Fatalf("unexpected slow query: %+v", q)
+		}
+		if q.Threshold != 200*time.Millisecond || q.Duration < time.Second {
+			t.Fatalf("unexpected slow query timing: %+v", q)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"github.com/team-dandelion/quickgo/tenant"
+)
+
+const defaultTenantColumn = "tenant_id"
+
+var (
+	// ErrTenantRequired 访问含租户列的表时 context 未携带租户 ID（TenantScopeConfig.Required 为 true 时）
+	ErrTenantRequired = errors.New("gorm: tenant id is required for tenant scoped tables")
+	// ErrTenantDatabaseNotFound 租户未映射到任何数据库且未配置默认数据库
+	ErrTenantDatabaseNotFound = errors.New("gorm: no database configured for tenant")
+)
+
+// TenantScopeConfig 行级租户隔离配置
+// 模型包含租户列时，查询、更新、删除自动追加 tenant_id = ? 条件，创建时自动填充租户列；
+// 租户 ID 取自 context（tenant.WithTenant / tenant 中间件），需通过 Client.DB(ctx) 或 db.WithContext(ctx) 执行；
+// 原生 SQL（Raw / Exec）不做改写
+type TenantScopeConfig struct {
+	// 是否启用
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled"`
+	// 租户列名 示例：tenant_id（默认）
+	Column string `json:"column" yaml:"column" toml:"column"`
+	// 是否要求访问含租户列的表时 context 必须携带租户 ID（默认不携带时不追加条件）
+	Required bool `json:"required" yaml:"required" toml:"required"`
+}
+
+// TenantRoutingConfig 库级租户隔离配置：按租户选择数据库（Manager.TenantDB）
+type TenantRoutingConfig struct {
+	// 租户 ID -> 数据库名称（GormManagerConfig.Databases 中的 Name）
+	Databases map[string]string `json:"databases" yaml:"databases" toml:"databases"`
+	// 未映射的租户使用的数据库名称，为空时返回 ErrTenantDatabaseNotFound
+	Default string `json:"default" yaml:"default" toml:"default"`
+}
+
+type skipTenantScopeKey struct{}
+
+// WithoutTenantScope 返回跳过行级租户隔离的 context（如跨租户的运维任务、数据迁移）
+func WithoutTenantScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipTenantScopeKey{}, true)
+}
+
+func tenantScopeSkipped(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	skip, _ := ctx.Value(skipTenantScopeKey{}).(bool)
+	return skip
+}
+
+// registerTenantCallbacks 注册行级租户隔离回调
+func registerTenantCallbacks(db *gorm.DB, config *TenantScopeConfig) error {
+	column := config.Column
+	if column == "" {
+		column = defaultTenantColumn
+	}
+
+	// tenantField 返回当前语句的租户字段与租户 ID，不需要处理时 field 为 nil
+	tenantField := func(db *gorm.DB) (*schema.Field, string) {
+		if db.Error != nil || db.Statement == nil || db.Statement.Schema == nil {
+			return nil, ""
+		}
+		field := db.Statement.Schema.LookUpField(column)
+		if field == nil || tenantScopeSkipped(db.Statement.Context) {
+			return nil, ""
+		}
+		id, ok := tenant.FromContext(db.Statement.Context)
+		if !ok {
+			if config.Required {
+				_ = db.AddError(fmt.Errorf("%w: table=%s", ErrTenantRequired, db.Statement.Schema.Table))
+			}
+			return nil, ""
+		}
+		return field, id
+	}
+	addCondition := func(db *gorm.DB) {
+		field, id := tenantField(db)
+		if field == nil {
+			return
+		}
+		db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: id},
+		}})
+	}
+	// 更新、删除没有其他条件时保留 gorm 的全表操作保护，不因租户条件而放行
+	addMutationCondition := func(db *gorm.DB) {
+		if db.Statement != nil && !db.AllowGlobalUpdate && !hasWhereOrPrimaryKey(db.Statement) {
+			return
+		}
+		addCondition(db)
+	}
+	fillTenant := func(db *gorm.DB) {
+		field, id := tenantField(db)
+		if field == nil {
+			return
+		}
+		ctx := db.Statement.Context
+		value := db.Statement.ReflectValue
+		switch value.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < value.Len(); i++ {
+				if err := field.Set(ctx, reflect.Indirect(value.Index(i)), id); err != nil {
+					_ = db.AddError(err)
+					return
+				}
+			}
+		case reflect.Struct:
+			if err := field.Set(ctx, value, id); err != nil {
+				_ = db.AddError(err)
+			}
+		}
+	}
+
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("gorm:query").Register("quickgo:tenant_query", addCondition); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("quickgo:tenant_row", addCondition); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("quickgo:tenant_update", addMutationCondition); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("quickgo:tenant_delete", addMutationCondition); err != nil {
+		return err
+	}
+	return callbacks.Create().Before("gorm:create").Register("quickgo:tenant_create", fillTenant)
+}
+
+// hasWhereOrPrimaryKey 判断语句是否已有条件或可按主键定位
+func hasWhereOrPrimaryKey(stmt *gorm.Statement) bool {
+	if _, ok := stmt.Clauses["WHERE"]; ok {
+		return true
+	}
+	value := stmt.ReflectValue
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		return value.Len() > 0
+	case reflect.Struct:
+		for _, field := range stmt.Schema.PrimaryFields {
+			if _, zero := field.ValueOf(stmt.Context, value); !zero {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TenantDB 按 context 中的租户选择数据库（TenantRouting 配置），返回绑定该 context 的 DB（context 中有进行中的事务时返回事务）
+func (m *Manager) TenantDB(ctx context.Context) (*gorm.DB, error) {
+	id, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, ErrTenantRequired
+	}
+	m.mu.RLock()
+	name, mapped := m.tenantDatabases[id]
+	if !mapped {
+		name = m.defaultTenantDatabase
+	}
+	m.mu.RUnlock()
+	if name == "" {
+		return nil, fmt.Errorf("%w: tenant=%s", ErrTenantDatabaseNotFound, id)
+	}
+	client, err := m.GetClient(name)
+	if err != nil {
+		return nil, err
+	}
+	return client.DB(ctx), nil
+}
+
+// SetTenantDatabase 设置租户使用的数据库（如新租户开通时配合 RegisterClient 使用）
+func (m *Manager) SetTenantDatabase(tenantID, name string) error {
+	if tenantID == "" {
+		return errors.New("tenant id is required")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.clients[name]; !exists {
+		return fmt.Errorf("gorm client not found: name=%s", name)
+	}
+	if m.tenantDatabases == nil {
+		m.tenantDatabases = make(map[string]string)
+	}
+	m.tenantDatabases[tenantID] = name
+	return nil
+}
@@ -0,0 +1,143 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/team-dandelion/quickgo/tenant"
+)
+
+type tenantRecord struct {
+	ID       uint `gorm:"primaryKey"`
+	TenantID string
+	Body     string
+}
+
+func newTenantTestClient(t *testing.T, required bool) *Client {
+	t.Helper()
+	client, err := NewClient(&GormConfig{
+		Name:        "tenant",
+		Master:      MasterConfig{Type: DatabaseTypeSQLite, Database: filepath.Join(t.TempDir(), "tenant.db")},
+		TenantScope: &TenantScopeConfig{Enabled: true, Required: required},
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	if err := client.GetDB().AutoMigrate(&tenantRecord{}); err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+	return client
+}
+
+func TestTenantScopeIsolatesRows(t *testing.T) {
+	client := newTenantTestClient(t, true)
+	acme := tenant.WithTenant(context.Background(), "acme")
+	globex := tenant.WithTenant(context.Background(), "globex")
+
+	records := []tenantRecord{{Body: "a1"}, {Body: "a2"}}
+	if err := client.DB(acme).Create(&records).Error; err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if records[0].TenantID != "acme" || records[1].TenantID != "acme" {
+		t.Fatalf("expected tenant column to be filled, got %+v", records)
+	}
+	other := tenantRecord{Body: "g1", TenantID: "acme"}
+	if err := client.DB(globex).Create(&other).Error; err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if other.TenantID != "globex" {
+		t.Fatalf("expected tenant column to be overwritten by context tenant, got %q", other.TenantID)
+	}
+
+	var found []tenantRecord
+	if err := client.DB(acme).Order("id").Find(&found).Error; err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(found) != 2 || found[0].Body != "a1" {
+		t.Fatalf("expected only acme rows, got %+v", found)
+	}
+	var count int64
+	if err := client.DB(globex).Model(&tenantRecord{}).Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("expected globex to see one row, got %d (%v)", count, err)
+	}
+
+	// 跨租户更新、删除不生效
+	if err := client.DB(globex).Model(&tenantRecord{}).Where("body = ?", "a1").Update("body", "hacked").Error; err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if err := client.DB(globex).Delete(&tenantRecord{}, records[1].ID).Error; err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if err := client.DB(WithoutTenantScope(context.Background())).Model(&tenantRecord{}).Count(&count).Error; err != nil || count != 3 {
+		t.Fatalf("expected all rows to survive cross-tenant writes, got %d (%v)", count, err)
+	}
+	var first tenantRecord
+	if err := client.DB(acme).First(&first, records[0].ID).Error; err != nil || first.Body != "a1" {
+		t.Fatalf("expected acme row to be unchanged, got %+v (%v)", first, err)
+	}
+
+	// 没有条件的更新仍受全表更新保护
+	if err := client.DB(acme).Model(&tenantRecord{}).Update("body", "all").Error; !errors.Is(err, gorm.ErrMissingWhereClause) {
+		t.Fatalf("expected missing where clause error, got %v", err)
+	}
+
+	if err := client.DB(context.Background()).Find(&found).Error; !errors.Is(err, ErrTenantRequired) {
+		t.Fatalf("expected tenant required error, got %v", err)
+	}
+}
+
+func TestManagerTenantDBRoutesByTenant(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := NewManager(&GormManagerConfig{
+		Databases: []GormConfig{
+			{Name: "shared", Master: MasterConfig{Type: DatabaseTypeSQLite, Database: filepath.Join(dir, "shared.db")}},
+			{Name: "vip", Master: MasterConfig{Type: DatabaseTypeSQLite, Database: filepath.Join(dir, "vip.db")}},
+		},
+		TenantRouting: &TenantRoutingConfig{Databases: map[string]string{"acme": "vip"}, Default: "shared"},
+	})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	t.Cleanup(func() { _ = manager.Close() })
+
+	name := func(id string) string {
+		t.Helper()
+		db, err := manager.TenantDB(tenant.WithTenant(context.Background(), id))
+		if err != nil {
+			t.Fatalf("TenantDB(%s) failed: %v", id, err)
+		}
+		var file string
+		if err := db.Raw("SELECT file FROM pragma_database_list WHERE name = 'main'").Scan(&file).Error; err != nil {
+			t.Fatalf("query database file failed: %v", err)
+		}
+		return filepath.Base(file)
+	}
+	if got := name("acme"); got != "vip.db" {
+		t.Fatalf("expected acme to use vip database, got %s", got)
+	}
+	if got := name("globex"); got != "shared.db" {
+		t.Fatalf("expected unmapped tenant to use default database, got %s", got)
+	}
+	if err := manager.SetTenantDatabase("globex", "vip"); err != nil {
+		t.Fatalf("SetTenantDatabase failed: %v", err)
+	}
+	if got := name("globex"); got != "vip.db" {
+		t.Fatalf("expected globex to be re-routed, got %s", got)
+	}
+	if _, err := manager.TenantDB(context.Background()); !errors.Is(err, ErrTenantRequired) {
+		t.Fatalf("expected tenant required error, got %v", err)
+	}
+
+	_, err = NewManager(&GormManagerConfig{
+		Databases:     []GormConfig{{Name: "shared", Master: MasterConfig{Type: DatabaseTypeSQLite, Database: filepath.Join(dir, "other.db")}}},
+		TenantRouting: &TenantRoutingConfig{Databases: map[string]string{"acme": "missing"}},
+	})
+	if err == nil {
+		t.Fatal("expected unknown tenant database to be rejected")
+	}
+}
@@ -0,0 +1,207 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	"github.com/team-dandelion/quickgo/tracing"
+)
+
+// db.statement 记录方式
+const (
+	// StatementSanitized 仅记录带占位符的 SQL，不含参数值（默认）
+	StatementSanitized = "sanitized"
+	// StatementFull 记录代入参数值后的 SQL，可能包含敏感数据
+	StatementFull = "full"
+	// StatementNone 不记录 SQL
+	StatementNone = "none"
+)
+
+// ErrContextRequired 语句未通过 WithContext（或 Client.DB(ctx)）传入调用方 context（TracingConfig.RequireContext 为 true 时）
+var ErrContextRequired = errors.New("gorm: caller context is required, use db.WithContext(ctx) or Client.DB(ctx)")
+
+// TracingConfig 数据库 span 配置，启用 OpenTelemetry tracing 时按操作创建 span
+// span 名称为 "操作 表名"（如 SELECT users），父 span 取自 db.WithContext(ctx) 传入的调用方 context
+type TracingConfig struct {
+	// 是否关闭数据库 span
+	Disabled bool `json:"disabled" yaml:"disabled" toml:"disabled"`
+	// db.statement 记录方式：sanitized（默认，不含参数值）、full（含参数值，配置 GormConfig.LogSanitize 时同样脱敏）、none（不记录）
+	Statement string `json:"statement" yaml:"statement" toml:"statement"`
+	// 是否要求模型操作（Create、Find、Update、Delete 等）携带调用方 context，为 true 时未调用 WithContext 的语句返回 ErrContextRequired
+	// 原生 SQL（Raw / Exec）与迁移不做检查
+	RequireContext bool `json:"requireContext" yaml:"requireContext" toml:"requireContext"`
+}
+
+const (
+	traceSpanKey = "quickgo:trace_span"
+	traceCtxKey  = "quickgo:trace_parent_ctx"
+)
+
+// registerTracingCallbacks 注册数据库 span 回调（create、query、update、delete、row、raw）
+func registerTracingCallbacks(db *gorm.DB, database string, config *TracingConfig, sanitizer *sqlSanitizer) error {
+	if config == nil {
+		config = &TracingConfig{}
+	}
+	if config.Disabled {
+		return nil
+	}
+	system := dbSystem(db.Dialector.Name())
+
+	before := func(operation string) func(*gorm.DB) {
+		return func(db *gorm.DB) {
+			ctx := db.Statement.Context
+			// 原生 SQL（Raw / Exec，含 AutoMigrate 内部语句）不做检查
+			if config.RequireContext && operation != "row" && operation != "raw" && (ctx == nil || ctx == context.Background()) {
+				_ = db.AddError(ErrContextRequired)
+				return
+			}
+			if !tracing.IsEnabled() {
+				return
+			}
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			spanCtx, span := tracing.StartSpan(ctx, "gorm."+operation, trace.WithSpanKind(trace.SpanKindClient))
+			db.InstanceSet(traceSpanKey, span)
+			db.InstanceSet(traceCtxKey, ctx)
+			// 后续回调与 SQL 日志使用数据库 span 的 context
+			db.Statement.Context = spanCtx
+		}
+	}
+	after := func(operation string) func(*gorm.DB) {
+		return func(db *gorm.DB) {
+			value, _ := db.InstanceGet(traceSpanKey)
+			span, ok := value.(trace.Span)
+			if !ok {
+				return
+			}
+			db.InstanceSet(traceSpanKey, nil)
+			spanCtx := db.Statement.Context
+			// 恢复调用方 context，避免同一 Statement 上的后续操作挂到已结束的 span 下
+			if parent, ok := db.InstanceGet(traceCtxKey); ok {
+				db.Statement.Context = parent.(context.Context)
+			}
+			finishSpan(spanCtx, span, db, operation, system, database, config.Statement, sanitizer)
+		}
+	}
+
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("*").Register("quickgo:trace_before_create", before("create")); err != nil {
+		return err
+	}
+	if err := callbacks.Create().After("*").Register("quickgo:trace_after_create", after("create")); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("*").Register("quickgo:trace_before_query", before("query")); err != nil {
+		return err
+	}
+	if err := callbacks.Query().After("*").Register("quickgo:trace_after_query", after("query")); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("*").Register("quickgo:trace_before_update", before("update")); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("*").Register("quickgo:trace_after_update", after("update")); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("*").Register("quickgo:trace_before_delete", before("delete")); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("*").Register("quickgo:trace_after_delete", after("delete")); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("*").Register("quickgo:trace_before_row", before("row")); err != nil {
+		return err
+	}
+	if err := callbacks.Row().After("*").Register("quickgo:trace_after_row", after("row")); err != nil {
+		return err
+	}
+	if err := callbacks.Raw().Before("*").Register("quickgo:trace_before_raw", before("raw")); err != nil {
+		return err
+	}
+	return callbacks.Raw().After("*").Register("quickgo:trace_after_raw", after("raw"))
+}
+
+// finishSpan 设置 span 名称与属性并结束 span
+func finishSpan(ctx context.Context, span trace.Span, db *gorm.DB, operation, system, database, mode string, sanitizer *sqlSanitizer) {
+	defer span.End()
+
+	sql := db.Statement.SQL.String()
+	op := sqlOperation(operation, sql)
+	table := db.Statement.Table
+	if table != "" {
+		span.SetName(op + " " + table)
+	} else {
+		span.SetName(op)
+	}
+
+	span.SetAttributes(
+		attribute.String("db.system", system),
+		attribute.String("db.operation", op),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if database != "" {
+		span.SetAttributes(attribute.String("db.name", database))
+	}
+	if table != "" {
+		span.SetAttributes(attribute.String("db.sql.table", table))
+	}
+	if statement := spanStatement(db, sql, mode, sanitizer); statement != "" {
+		span.SetAttributes(attribute.String("db.statement", statement))
+	}
+	tracing.AddTraceIDToSpan(span, ctx)
+
+	if err := db.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		tracing.SetSpanError(span, err)
+	}
+}
+
+// spanStatement 按记录方式返回 db.statement
+func spanStatement(db *gorm.DB, sql, mode string, sanitizer *sqlSanitizer) string {
+	switch mode {
+	case StatementNone:
+		return ""
+	case StatementFull:
+		if sql == "" {
+			return ""
+		}
+		return sanitizer.Sanitize(db.Dialector.Explain(sql, db.Statement.Vars...))
+	default:
+		return sql
+	}
+}
+
+// sqlOperation 返回操作名：create、query、update、delete 回调使用固定操作名，row、raw 取 SQL 首个关键字
+func sqlOperation(operation, sql string) string {
+	switch operation {
+	case "create":
+		return "INSERT"
+	case "query":
+		return "SELECT"
+	case "update":
+		return "UPDATE"
+	case "delete":
+		return "DELETE"
+	}
+	if fields := strings.Fields(sql); len(fields) > 0 {
+		return strings.ToUpper(fields[0])
+	}
+	return strings.ToUpper(operation)
+}
+
+// dbSystem 将 GORM 方言名转换为 OpenTelemetry db.system 取值
+func dbSystem(dialect string) string {
+	switch dialect {
+	case "postgres":
+		return "postgresql"
+	case "sqlserver":
+		return "mssql"
+	default:
+		return dialect
+	}
+}
@@ -0,0 +1,135 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	gormDB "gorm.io/gorm"
+
+	"github.com/team-dandelion/quickgo/tracing"
+)
+
+type traceRecord struct {
+	ID   uint
+	Name string
+}
+
+func newTracingTestClient(t *testing.T, config *TracingConfig) (*Client, *[]tracesdk.ReadOnlySpan) {
+	t.Helper()
+	client, err := NewClient(&GormConfig{
+		Name:    "trace",
+		Master:  MasterConfig{Type: DatabaseTypeSQLite, Database: filepath.Join(t.TempDir(), "trace.db")},
+		Tracing: config,
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	if err := client.GetDB().AutoMigrate(&traceRecord{}); err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+
+	// 在 span 结束前取出 span，便于检查名称与属性
+	spans := &[]tracesdk.ReadOnlySpan{}
+	capture := func(db *gormDB.DB) {
+		if value, ok := db.InstanceGet(traceSpanKey); ok {
+			if span, ok := value.(tracesdk.ReadOnlySpan); ok {
+				*spans = append(*spans, span)
+			}
+		}
+	}
+	callbacks := client.GetDB().Callback()
+	_ = callbacks.Create().Before("quickgo:trace_after_create").Register("test:capture_create", capture)
+	_ = callbacks.Query().Before("quickgo:trace_after_query").Register("test:capture_query", capture)
+	return client, spans
+}
+
+func spanAttribute(span tracesdk.ReadOnlySpan, key attribute.Key) string {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestTracingCallbacksCreateOperationSpans(t *testing.T) {
+	config := tracing.DefaultConfig()
+	config.Enabled = true
+	if err := tracing.Init(&config); err != nil {
+		t.Fatalf("tracing init failed: %v", err)
+	}
+	defer tracing.Shutdown(context.Background())
+
+	client, spans := newTracingTestClient(t, nil)
+	ctx, parent := tracing.StartSpan(context.Background(), "handler")
+	defer parent.End()
+
+	if err := client.DB(ctx).Create(&traceRecord{Name: "secret-value"}).Error; err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	var found traceRecord
+	if err := client.DB(ctx).Where("name = ?", "secret-value").First(&found).Error; err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+
+	if len(*spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(*spans))
+	}
+	insert, query := (*spans)[0], (*spans)[1]
+	if insert.Name() != "INSERT trace_records" || query.Name() != "SELECT trace_records" {
+		t.Fatalf("unexpected span names %q, %q", insert.Name(), query.Name())
+	}
+	for _, span := range *spans {
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Fatalf("expected span %q to be a child of the caller span", span.Name())
+		}
+		if span.SpanKind() != trace.SpanKindClient || spanAttribute(span, "db.system") != "sqlite" {
+			t.Fatalf("unexpected span kind or db.system for %q", span.Name())
+		}
+	}
+	if got := spanAttribute(query, "db.statement"); got == "" || spanAttribute(query, "db.sql.table") != "trace_records" {
+		t.Fatalf("expected statement and table attributes, got %q", got)
+	}
+	if got := spanAttribute(query, "db.statement"); strings.Contains(got, "secret-value") {
+		t.Fatalf("expected sanitized statement without values, got %q", got)
+	}
+}
+
+func TestTracingCallbacksFullStatementAndRequireContext(t *testing.T) {
+	config := tracing.DefaultConfig()
+	config.Enabled = true
+	if err := tracing.Init(&config); err != nil {
+		t.Fatalf("tracing init failed: %v", err)
+	}
+	defer tracing.Shutdown(context.Background())
+
+	client, spans := newTracingTestClient(t, &TracingConfig{Statement: StatementFull, RequireContext: true})
+	if err := client.GetDB().Create(&traceRecord{Name: "a"}).Error; !errors.Is(err, ErrContextRequired) {
+		t.Fatalf("expected ErrContextRequired without WithContext, got %v", err)
+	}
+	if err := client.DB(context.TODO()).Create(&traceRecord{Name: "visible-value"}).Error; err != nil {
+		t.Fatalf("create with context failed: %v", err)
+	}
+	if len(*spans) != 1 || !strings.Contains(spanAttribute((*spans)[0], "db.statement"), "visible-value") {
+		t.Fatalf("expected full statement with values, got %d spans", len(*spans))
+	}
+}
+
+func TestSQLOperationAndDBSystem(t *testing.T) {
+	if got := sqlOperation("raw", "  update users set name = ?"); got != "UPDATE" {
+		t.Fatalf("unexpected raw operation %q", got)
+	}
+	if got := sqlOperation("row", ""); got != "ROW" {
+		t.Fatalf("unexpected empty operation %q", got)
+	}
+	if dbSystem("postgres") != "postgresql" || dbSystem("mysql") != "mysql" || dbSystem("sqlserver") != "mssql" {
+		t.Fatal("unexpected db.system mapping")
+	}
+}
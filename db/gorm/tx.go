@@ -0,0 +1,113 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// txContextKey 按数据库名称存储事务
+type txContextKey struct {
+	name string
+}
+
+// currentTxKey 存储最近一次开启的事务（不区分数据库）
+type currentTxKey struct{}
+
+// ContextWithTx 将事务绑定到 context，后续通过 FromContext / Manager.DB 获取的 DB 自动加入该事务
+func ContextWithTx(ctx context.Context, name string, tx *gorm.DB) context.Context {
+	ctx = context.WithValue(ctx, txContextKey{name: name}, tx)
+	return context.WithValue(ctx, currentTxKey{}, tx)
+}
+
+// FromContext 获取 context 中最近一次开启的事务，不存在时返回 nil
+func FromContext(ctx context.Context) *gorm.DB {
+	if ctx == nil {
+		return nil
+	}
+	if tx, ok := ctx.Value(currentTxKey{}).(*gorm.DB); ok && tx != nil {
+		return tx.WithContext(ctx)
+	}
+	return nil
+}
+
+// TxFromContext 获取 context 中指定数据库的事务，不存在时返回 nil
+func TxFromContext(ctx context.Context, name string) *gorm.DB {
+	if ctx == nil {
+		return nil
+	}
+	if tx, ok := ctx.Value(txContextKey{name: name}).(*gorm.DB); ok && tx != nil {
+		return tx.WithContext(ctx)
+	}
+	return nil
+}
+
+// DB 获取当前客户端的 DB：context 中存在该库的事务时加入事务，否则返回普通连接
+func (c *Client) DB(ctx context.Context) *gorm.DB {
+	if tx := TxFromContext(ctx, c.name); tx != nil {
+		return tx
+	}
+	return c.db.WithContext(ctx)
+}
+
+// Transaction 在事务中执行 fn
+// 如果 context 中已存在该库的事务，则以保存点（SAVEPOINT）方式嵌套执行；fn 返回错误或 panic 时回滚
+// fn 中 tx.Statement.Context 已绑定事务，可继续传给仓储方法以加入同一事务
+func (c *Client) Transaction(ctx context.Context, fn func(tx *gorm.DB) error, opts ...*sql.TxOptions) error {
+	if fn == nil {
+		return errors.New("transaction func is nil")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	parent := TxFromContext(ctx, c.name)
+	if parent == nil {
+		parent = c.db.WithContext(ctx)
+	}
+
+	return parent.Transaction(func(tx *gorm.DB) error {
+		txCtx := ContextWithTx(ctx, c.name, tx)
+		return fn(tx.WithContext(txCtx))
+	}, opts...)
+}
+
+// WithinTransaction 以 Unit-of-Work 方式在事务中执行 fn，fn 收到的 context 携带事务
+// 仓储方法通过 Client.DB(ctx) / Manager.DB(ctx, name) 获取连接即可自动加入事务
+func (c *Client) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error, opts ...*sql.TxOptions) error {
+	if fn == nil {
+		return errors.New("transaction func is nil")
+	}
+	return c.Transaction(ctx, func(tx *gorm.DB) error {
+		return fn(tx.Statement.Context)
+	}, opts...)
+}
+
+// DB 获取指定数据库的 DB：context 中存在该库的事务时加入事务，否则返回普通连接
+func (m *Manager) DB(ctx context.Context, name string) (*gorm.DB, error) {
+	client, err := m.GetClient(name)
+	if err != nil {
+		return nil, err
+	}
+	return client.DB(ctx), nil
+}
+
+// Transaction 在指定数据库的事务中执行 fn，支持嵌套（保存点）
+func (m *Manager) Transaction(ctx context.Context, name string, fn func(tx *gorm.DB) error, opts ...*sql.TxOptions) error {
+	client, err := m.GetClient(name)
+	if err != nil {
+		return err
+	}
+	return client.Transaction(ctx, fn, opts...)
+}
+
+// WithinTransaction 以 Unit-of-Work 方式在指定数据库的事务中执行 fn
+func (m *Manager) WithinTransaction(ctx context.Context, name string, fn func(ctx context.Context) error, opts ...*sql.TxOptions) error {
+	client, err := m.GetClient(name)
+	if err != nil {
+		return err
+	}
+	return client.WithinTransaction(ctx, fn, opts...)
+}
@@ -0,0 +1,129 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type txMessage struct {
+	ID   uint `gorm:"primaryKey"`
+	Body string
+}
+
+func newTxTestManager(t *testing.T) *Manager {
+	t.Helper()
+	manager, err := NewManager(&GormManagerConfig{
+		Databases: []GormConfig{{
+			Name:   "main",
+			Master: MasterConfig{Type: DatabaseTypeSQLite, Database: filepath.Join(t.TempDir(), "tx.db")},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	t.Cleanup(func() { _ = manager.Close() })
+
+	db, _ := manager.GetDB("main")
+	if err := db.AutoMigrate(&txMessage{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	return manager
+}
+
+// createMessage 模拟仓储方法：通过 Manager.DB(ctx) 自动加入环境事务
+func createMessage(ctx context.Context, manager *Manager, body string) error {
+	db, err := manager.DB(ctx, "main")
+	if err != nil {
+		return err
+	}
+	return db.Create(&txMessage{Body: body}).Error
+}
+
+func countMessages(t *testing.T, manager *Manager) int64 {
+	t.Helper()
+	db, _ := manager.GetDB("main")
+	var count int64
+	if err := db.Model(&txMessage{}).Count(&count).Error; err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	return count
+}
+
+func TestWithinTransactionRollsBackRepositoryWrites(t *testing.T) {
+	manager := newTxTestManager(t)
+	errBoom := errors.New("boom")
+
+	err := manager.WithinTransaction(context.Background(), "main", func(ctx context.Context) error {
+		if FromContext(ctx) == nil {
+			t.Fatal("expected ambient transaction in context")
+		}
+		if err := createMessage(ctx, manager, "first"); err != nil {
+			return err
+		}
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("expected errBoom, got %v", err)
+	}
+	if got := countMessages(t, manager); got != 0 {
+		t.Fatalf("expected rollback, got %d rows", got)
+	}
+
+	if err := manager.WithinTransaction(context.Background(), "main", func(ctx context.Context) error {
+		return createMessage(ctx, manager, "second")
+	}); err != nil {
+		t.Fatalf("WithinTransaction failed: %v", err)
+	}
+	if got := countMessages(t, manager); got != 1 {
+		t.Fatalf("expected commit, got %d rows", got)
+	}
+}
+
+func TestNestedTransactionUsesSavepoint(t *testing.T) {
+	manager := newTxTestManager(t)
+
+	err := manager.Transaction(context.Background(), "main", func(tx *gorm.DB) error {
+		ctx := tx.Statement.Context
+		if err := createMessage(ctx, manager, "outer"); err != nil {
+			return err
+		}
+
+		// 内层事务失败只回滚到保存点，不影响外层
+		innerErr := manager.Transaction(ctx, "main", func(inner *gorm.DB) error {
+			if err := inner.Create(&txMessage{Body: "inner"}).Error; err != nil {
+				return err
+			}
+			return errors.New("inner failed")
+		})
+		if innerErr == nil {
+			t.Fatal("expected inner transaction error")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction failed: %v", err)
+	}
+
+	db, _ := manager.GetDB("main")
+	var bodies []string
+	if err := db.Model(&txMessage{}).Order("id").Pluck("body", &bodies).Error; err != nil {
+		t.Fatalf("Pluck failed: %v", err)
+	}
+	if len(bodies) != 1 || bodies[0] != "outer" {
+		t.Fatalf("expected only outer row, got %v", bodies)
+	}
+}
+
+func TestTransactionUnknownDatabase(t *testing.T) {
+	manager := newTxTestManager(t)
+	if err := manager.Transaction(context.Background(), "missing", func(tx *gorm.DB) error { return nil }); err == nil {
+		t.Fatal("expected unknown database error")
+	}
+	if FromContext(context.Background()) != nil {
+		t.Fatal("expected no ambient transaction")
+	}
+}
@@ -0,0 +1,73 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+// Command 执行迁移命令，供应用实现 `<app> migrate <up|down|status>` 命令行模式
+//
+//	up [version]  执行待执行迁移（指定 version 时只执行到该版本）
+//	down [steps]  回滚最近的 steps 个迁移（默认 1）
+//	status        输出迁移状态
+func Command(ctx context.Context, m *Migrator, args []string, out io.Writer) error {
+	if m == nil {
+		return errors.New("migrator is nil")
+	}
+	if len(args) == 0 {
+		return errors.New("migrate command is required: up, down, status")
+	}
+
+	switch args[0] {
+	case "up":
+		var target int64
+		if len(args) > 1 {
+			version, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid target version %q: %w", args[1], err)
+			}
+			target = version
+		}
+		done, err := m.UpTo(ctx, target)
+		fmt.Fprintf(out, "[%s] applied %d migration(s) %v\n", m.Name(), len(done), done)
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid steps %q", args[1])
+			}
+			steps = n
+		}
+		done, err := m.Down(ctx, steps)
+		fmt.Fprintf(out, "[%s] rolled back %d migration(s) %v\n", m.Name(), len(done), done)
+		return err
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "[%s]\n", m.Name())
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, status := range statuses {
+			state, appliedAt := "pending", "-"
+			if status.Applied {
+				state = "applied"
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if status.Missing {
+				state = "applied (missing)"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command %q: expected up, down, status", args[0])
+	}
+}
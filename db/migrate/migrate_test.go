@@ -0,0 +1,238 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "migrate.db")), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func testMigrations(t *testing.T) []*Migration {
+	t.Helper()
+	migrations, err := LoadFS(fstest.MapFS{
+		"sql/1_create_users.up.sql":   {Data: []byte("-- users\nCREATE TABLE users (\n  id INTEGER PRIMARY KEY,\n  name TEXT\n);\nCREATE INDEX idx_users_name ON users(name);\n")},
+		"sql/1_create_users.down.sql": {Data: []byte("DROP TABLE users;\n")},
+		"sql/README.md":               {Data: []byte("ignored")},
+	}, "sql")
+	if err != nil {
+		t.Fatalf("LoadFS failed: %v", err)
+	}
+	return append(migrations, &Migration{
+		Version: 2,
+		Name:    "seed_admin",
+		Up: func(ctx context.Context, tx *gorm.DB) error {
+			return tx.Exec("INSERT INTO users (id, name) VALUES (1, 'admin')").Error
+		},
+		Down: func(ctx context.Context, tx *gorm.DB) error {
+			return tx.Exec("DELETE FROM users WHERE id = 1").Error
+		},
+	})
+}
+
+func TestMigratorUpDownStatus(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	m, err := New("main", db, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := m.Add(testMigrations(t)...); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	done, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+	if len(done) != 2 || done[0] != 1 || done[1] != 2 {
+		t.Fatalf("expected versions [1 2] applied, got %v", done)
+	}
+	var count int64
+	db.Table("users").Count(&count)
+	if count != 1 {
+		t.Fatalf("expected seeded user, got %d rows", count)
+	}
+
+	// 再次执行为幂等
+	if done, err := m.Up(ctx); err != nil || len(done) != 0 {
+		t.Fatalf("expected no pending migrations, got %v, %v", done, err)
+	}
+
+	if done, err := m.Down(ctx, 1); err != nil || len(done) != 1 || done[0] != 2 {
+		t.Fatalf("expected version 2 rolled back, got %v, %v", done, err)
+	}
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if len(statuses) != 2 || !statuses[0].Applied || statuses[1].Applied {
+		t.Fatalf("unexpected statuses: %+v", statuses)
+	}
+
+	var out bytes.Buffer
+	if err := Command(ctx, m, []string{"status"}, &out); err != nil {
+		t.Fatalf("status command failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "seed_admin") || !strings.Contains(out.String(), "pending") {
+		t.Fatalf("unexpected status output: %s", out.String())
+	}
+}
+
+func TestMigratorRollsBackFailedMigration(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	m, _ := New("main", db, nil)
+	_ = m.Add(&Migration{
+		Version: 1,
+		Name:    "broken",
+		UpSQL:   "CREATE TABLE half (id INTEGER);\nINSERT INTO missing_table VALUES (1);\n",
+	})
+
+	if _, err := m.Up(ctx); err == nil {
+		t.Fatalf("expected migration to fail")
+	}
+	if db.Migrator().HasTable("half") {
+		t.Fatalf("expected failed migration to be rolled back")
+	}
+	pending, err := m.Pending(ctx)
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("expected failed migration to remain pending, got %v, %v", pending, err)
+	}
+}
+
+func TestMigratorRejectsDuplicateVersions(t *testing.T) {
+	m, _ := New("main", newTestDB(t), nil)
+	err := m.Add(
+		&Migration{Version: 1, Name: "a", UpSQL: "SELECT 1;"},
+		&Migration{Version: 1, Name: "b", UpSQL: "SELECT 1;"},
+	)
+	if err == nil {
+		t.Fatalf("expected duplicate version error")
+	}
+}
+
+func TestMigratorLockAllowsSingleRunner(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	var (
+		mu   sync.Mutex
+		runs int
+	)
+	migration := &Migration{
+		Version: 1,
+		Name:    "slow",
+		Up: func(ctx context.Context, tx *gorm.DB) error {
+			mu.Lock()
+			runs++
+			mu.Unlock()
+			time.Sleep(100 * time.Millisecond)
+			return nil
+		},
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	for i := range errs {
+		m, _ := New("main", db, &Options{RetryInterval: 20 * time.Millisecond, LockTimeout: 5 * time.Second})
+		_ = m.Add(migration)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = m.Up(ctx)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("Up failed: %v", err)
+		}
+	}
+	if runs != 1 {
+		t.Fatalf("expected migration to run once across instances, ran %d times", runs)
+	}
+}
+
+func TestMigratorLockTimeoutAndStaleLock(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	m, _ := New("main", db, &Options{RetryInterval: 10 * time.Millisecond, LockTimeout: 50 * time.Millisecond, LockTTL: time.Hour})
+	_ = m.Add(&Migration{Version: 1, Name: "noop", UpSQL: "SELECT 1;"})
+	if err := m.ensureTables(ctx); err != nil {
+		t.Fatalf("ensureTables failed: %v", err)
+	}
+	db.Table(m.opts.LockTable).Create(&migrationLock{ID: 1, Owner: "other", LockedAt: time.Now().Add(-time.Minute)})
+
+	if _, err := m.Up(ctx); !errors.Is(err, ErrLockTimeout) {
+		t.Fatalf("expected ErrLockTimeout, got %v", err)
+	}
+
+	m.opts.LockTTL = time.Second
+	if done, err := m.Up(ctx); err != nil || len(done) != 1 {
+		t.Fatalf("expected stale lock to be taken over, got %v, %v", done, err)
+	}
+}
+
+func TestMigratorLockHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	m, _ := New("main", db, &Options{LockTTL: 150 * time.Millisecond})
+
+	// 耗时超过 LockTTL 的迁移期间 locked_at 持续刷新，锁不会被当作过期锁抢占
+	var lockedAt time.Time
+	err := m.withLock(ctx, func(ctx context.Context) error {
+		time.Sleep(300 * time.Millisecond)
+		var lock migrationLock
+		if err := db.Table(m.opts.LockTable).Where("id = ?", 1).First(&lock).Error; err != nil {
+			return err
+		}
+		lockedAt = lock.LockedAt
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("withLock failed: %v", err)
+	}
+	if age := time.Since(lockedAt); age > 150*time.Millisecond {
+		t.Fatalf("expected locked_at to be refreshed, last refresh %s ago", age)
+	}
+
+	// 锁被其他实例抢占时取消 fn 的 context 并返回 ErrLockLost
+	err = m.withLock(ctx, func(ctx context.Context) error {
+		db.Table(m.opts.LockTable).Where("id = ?", 1).Update("owner", "other")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+			return errors.New("context not cancelled")
+		}
+	})
+	if !errors.Is(err, ErrLockLost) {
+		t.Fatalf("expected ErrLockLost, got %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// MigrationFunc Go 迁移函数，tx 为当前迁移使用的连接（NoTransaction 时为普通连接）
+type MigrationFunc func(ctx context.Context, tx *gorm.DB) error
+
+// Migration 单个版本的迁移
+// Up/Down 与 UpSQL/DownSQL 二选一，同时设置时优先执行 Go 函数
+type Migration struct {
+	// 版本号（唯一、递增，推荐使用时间戳如 20240601120000）
+	Version int64
+	// 迁移名称
+	Name string
+	// Go 迁移
+	Up   MigrationFunc
+	Down MigrationFunc
+	// SQL 迁移（多条语句以行尾分号分隔）
+	UpSQL   string
+	DownSQL string
+	// 不在事务中执行（如 PostgreSQL 的 CREATE INDEX CONCURRENTLY）
+	NoTransaction bool
+}
+
+// HasDown 是否支持回滚
+func (m *Migration) HasDown() bool {
+	return m.Down != nil || strings.TrimSpace(m.DownSQL) != ""
+}
+
+func (m *Migration) validate() error {
+	if m.Version <= 0 {
+		return fmt.Errorf("migration %q: version must be positive", m.Name)
+	}
+	if m.Up == nil && strings.TrimSpace(m.UpSQL) == "" {
+		return fmt.Errorf("migration %d: up is required", m.Version)
+	}
+	return nil
+}
+
+func (m *Migration) runUp(ctx context.Context, db *gorm.DB) error {
+	if m.Up != nil {
+		return m.Up(ctx, db)
+	}
+	return execSQL(db, m.UpSQL)
+}
+
+func (m *Migration) runDown(ctx context.Context, db *gorm.DB) error {
+	if m.Down != nil {
+		return m.Down(ctx, db)
+	}
+	return execSQL(db, m.DownSQL)
+}
+
+// execSQL 逐条执行 SQL（部分驱动不支持单次执行多条语句）
+func execSQL(db *gorm.DB, script string) error {
+	for _, statement := range splitStatements(script) {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("exec %q: %w", abbreviate(statement), err)
+		}
+	}
+	return nil
+}
+
+// splitStatements 按行尾分号拆分 SQL，忽略空行与整行注释
+func splitStatements(script string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+	)
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			if statement := strings.TrimSpace(current.String()); statement != ";" {
+				statements = append(statements, statement)
+			}
+			current.Reset()
+		}
+	}
+	if statement := strings.TrimSpace(current.String()); statement != "" {
+		statements = append(statements, statement)
+	}
+	return statements
+}
+
+func abbreviate(statement string) string {
+	statement = strings.Join(strings.Fields(statement), " ")
+	if len(statement) > 80 {
+		return statement[:77] + "..."
+	}
+	return statement
+}
+
+// sqlFilePattern SQL 迁移文件名：{version}_{name}.up.sql / {version}_{name}.down.sql
+var sqlFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// LoadFS 从文件系统目录加载 SQL 迁移
+// 文件命名：{version}_{name}.up.sql 与可选的 {version}_{name}.down.sql，其他文件会被忽略
+func LoadFS(fsys fs.FS, dir string) ([]*Migration, error) {
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration dir %s: %w", dir, err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		matches := sqlFilePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		} else if migration.Name != matches[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names: %s, %s", version, migration.Name, matches[2])
+		}
+		if matches[3] == "up" {
+			migration.UpSQL = string(content)
+		} else {
+			migration.DownSQL = string(content)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if strings.TrimSpace(migration.UpSQL) == "" {
+			return nil, fmt.Errorf("migration %d_%s: missing up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, migration)
+	}
+	sortMigrations(migrations)
+	return migrations, nil
+}
+
+func sortMigrations(migrations []*Migration) {
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string][]*Migration)
+)
+
+// Register 为指定数据库注册 Go 迁移（通常在 init 中调用），database 对应 GormConfig.Name
+func Register(database string, migrations ...*Migration) {
+	if database == "" {
+		panic(errors.New("migrate: database name is required"))
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, migration := range migrations {
+		if migration == nil {
+			continue
+		}
+		if err := migration.validate(); err != nil {
+			panic(fmt.Errorf("migrate: %w", err))
+		}
+		registry[database] = append(registry[database], migration)
+	}
+}
+
+// Registered 返回指定数据库已注册的 Go 迁移（按版本排序）
+func Registered(database string) []*Migration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	migrations := append([]*Migration(nil), registry[database]...)
+	sortMigrations(migrations)
+	return migrations
+}
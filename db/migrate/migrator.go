@@ -0,0 +1,526 @@
+package migrate
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+
+	"gorm.io/gorm"
+)
+
+// ErrLockTimeout 等待迁移锁超时（其他实例正在迁移）
+var ErrLockTimeout = errors.New("timed out waiting for migration lock")
+
+// ErrLockLost 迁移执行期间迁移锁丢失（被其他实例抢占或心跳持续刷新失败），正在执行的迁移已中止
+var ErrLockLost = errors.New("migration lock lost")
+
+// Config 数据库迁移配置
+type Config struct {
+	// 是否在框架 Init 时自动执行待执行迁移
+	AutoRun bool `json:"autoRun" yaml:"autoRun" toml:"autoRun"`
+	// SQL 迁移文件目录，每个数据库使用 {dir}/{数据库名称} 子目录（可选）
+	Dir string `json:"dir" yaml:"dir" toml:"dir"`
+	// 参与迁移的数据库名称（默认所有存在迁移的数据库）
+	Databases []string `json:"databases" yaml:"databases" toml:"databases"`
+	// 版本记录表名（默认 schema_migrations）
+	Table string `json:"table" yaml:"table" toml:"table"`
+	// 等待迁移锁的最长时间 示例：1m（默认 1m）
+	LockTimeout string `json:"lockTimeout" yaml:"lockTimeout" toml:"lockTimeout" validate:"duration"`
+	// 迁移锁过期时间，持有实例崩溃后超过该时间可被抢占 示例：10m（默认 10m）
+	LockTTL string `json:"lockTTL" yaml:"lockTTL" toml:"lockTTL" validate:"duration"`
+}
+
+// Options 迁移器选项
+type Options struct {
+	// 版本记录表名（默认 schema_migrations）
+	Table string
+	// 迁移锁表名（默认 {Table}_lock）
+	LockTable string
+	// 等待迁移锁的最长时间（默认 1m）
+	LockTimeout time.Duration
+	// 迁移锁过期时间（默认 10m），持有期间每 LockTTL/3 刷新一次 locked_at
+	LockTTL time.Duration
+	// 获取锁失败后的重试间隔（默认 1s）
+	RetryInterval time.Duration
+}
+
+// Status 迁移状态
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+	// 已执行但当前代码中不存在该迁移
+	Missing bool `json:"missing,omitempty"`
+}
+
+// schemaMigration 版本记录
+type schemaMigration struct {
+	Version   int64  `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"size:255"`
+	AppliedAt time.Time
+}
+
+// migrationLock 迁移锁（单行记录，通过主键冲突实现互斥，兼容所有数据库）
+type migrationLock struct {
+	ID       int    `gorm:"primaryKey;autoIncrement:false"`
+	Owner    string `gorm:"size:64"`
+	LockedAt time.Time
+}
+
+// Migrator 单个数据库的迁移器
+type Migrator struct {
+	name       string
+	db         *gorm.DB
+	opts       Options
+	migrations []*Migration
+	versions   map[int64]*Migration
+}
+
+// New 创建迁移器，name 为数据库名称（用于日志）
+func New(name string, db *gorm.DB, opts *Options) (*Migrator, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	var options Options
+	if opts != nil {
+		options = *opts
+	}
+	if options.Table == "" {
+		options.Table = "schema_migrations"
+	}
+	if options.LockTable == "" {
+		options.LockTable = options.Table + "_lock"
+	}
+	if options.LockTimeout <= 0 {
+		options.LockTimeout = time.Minute
+	}
+	if options.LockTTL <= 0 {
+		options.LockTTL = 10 * time.Minute
+	}
+	if options.RetryInterval <= 0 {
+		options.RetryInterval = time.Second
+	}
+	return &Migrator{
+		name:     name,
+		db:       db,
+		opts:     options,
+		versions: make(map[int64]*Migration),
+	}, nil
+}
+
+// NewFromConfig 根据配置创建迁移器，加载 Register 注册的 Go 迁移与 {Dir}/{name} 下的 SQL 迁移
+func NewFromConfig(name string, db *gorm.DB, config *Config) (*Migrator, error) {
+	if config == nil {
+		return nil, errors.New("config is nil")
+	}
+	opts := &Options{Table: config.Table}
+	var err error
+	if config.LockTimeout != "" {
+		if opts.LockTimeout, err = time.ParseDuration(config.LockTimeout); err != nil {
+			return nil, fmt.Errorf("failed to parse lockTimeout: %w", err)
+		}
+	}
+	if config.LockTTL != "" {
+		if opts.LockTTL, err = time.ParseDuration(config.LockTTL); err != nil {
+			return nil, fmt.Errorf("failed to parse lockTTL: %w", err)
+		}
+	}
+
+	migrator, err := New(name, db, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrator.Add(Registered(name)...); err != nil {
+		return nil, err
+	}
+	if config.Dir != "" {
+		dir := filepath.Join(config.Dir, name)
+		if info, statErr := os.Stat(dir); statErr == nil && info.IsDir() {
+			migrations, err := LoadFS(os.DirFS(dir), ".")
+			if err != nil {
+				return nil, err
+			}
+			if err := migrator.Add(migrations...); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return migrator, nil
+}
+
+// Name 返回数据库名称
+func (m *Migrator) Name() string {
+	return m.name
+}
+
+// Add 添加迁移，版本号重复时返回错误
+func (m *Migrator) Add(migrations ...*Migration) error {
+	for _, migration := range migrations {
+		if migration == nil {
+			continue
+		}
+		if err := migration.validate(); err != nil {
+			return err
+		}
+		if existing, ok := m.versions[migration.Version]; ok {
+			return fmt.Errorf("duplicate migration version %d: %s, %s", migration.Version, existing.Name, migration.Name)
+		}
+		m.versions[migration.Version] = migration
+		m.migrations = append(m.migrations, migration)
+	}
+	sortMigrations(m.migrations)
+	return nil
+}
+
+// Migrations 返回已添加的迁移（按版本排序）
+func (m *Migrator) Migrations() []*Migration {
+	return append([]*Migration(nil), m.migrations...)
+}
+
+// Status 返回所有迁移的执行状态（包含已执行但代码中缺失的版本）
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.ensureTables(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations)+len(applied))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	for version, record := range applied {
+		if _, ok := m.versions[version]; ok {
+			continue
+		}
+		appliedAt := record.AppliedAt
+		statuses = append(statuses, Status{Version: version, Name: record.Name, Applied: true, AppliedAt: &appliedAt, Missing: true})
+	}
+	sortStatuses(statuses)
+	return statuses, nil
+}
+
+// Pending 返回待执行的迁移
+func (m *Migrator) Pending(ctx context.Context) ([]*Migration, error) {
+	if err := m.ensureTables(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return m.pending(applied), nil
+}
+
+// Up 执行所有待执行迁移，返回本次执行的版本
+func (m *Migrator) Up(ctx context.Context) ([]int64, error) {
+	return m.UpTo(ctx, 0)
+}
+
+// UpTo 执行版本号不大于 target 的待执行迁移（target <= 0 表示全部）
+func (m *Migrator) UpTo(ctx context.Context, target int64) ([]int64, error) {
+	var done []int64
+	err := m.withLock(ctx, func(ctx context.Context) error {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+		var maxApplied int64
+		for version := range applied {
+			if version > maxApplied {
+				maxApplied = version
+			}
+		}
+		for _, migration := range m.pending(applied) {
+			if target > 0 && migration.Version > target {
+				break
+			}
+			if migration.Version < maxApplied {
+				logger.Warn(ctx, "Applying out-of-order migration: database=%s, version=%d, latest_applied=%d", m.name, migration.Version, maxApplied)
+			}
+			if err := m.apply(ctx, migration, true); err != nil {
+				return err
+			}
+			done = append(done, migration.Version)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down 回滚最近执行的 steps 个迁移（steps <= 0 时回滚 1 个），返回本次回滚的版本
+func (m *Migrator) Down(ctx context.Context, steps int) ([]int64, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+	var done []int64
+	err := m.withLock(ctx, func(ctx context.Context) error {
+		records, err := m.appliedDesc(ctx)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < steps && i < len(records); i++ {
+			migration, ok := m.versions[records[i].Version]
+			if !ok {
+				return fmt.Errorf("cannot roll back migration %d_%s: not found in code", records[i].Version, records[i].Name)
+			}
+			if !migration.HasDown() {
+				return fmt.Errorf("cannot roll back migration %d_%s: down is not defined", migration.Version, migration.Name)
+			}
+			if err := m.apply(ctx, migration, false); err != nil {
+				return err
+			}
+			done = append(done, migration.Version)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// apply 执行单个迁移并更新版本记录
+func (m *Migrator) apply(ctx context.Context, migration *Migration, up bool) error {
+	direction := "up"
+	if !up {
+		direction = "down"
+	}
+	start := time.Now()
+	logger.Info(ctx, "Running migration: database=%s, version=%d, name=%s, direction=%s", m.name, migration.Version, migration.Name, direction)
+
+	run := func(tx *gorm.DB) error {
+		var err error
+		if up {
+			err = migration.runUp(ctx, tx)
+		} else {
+			err = migration.runDown(ctx, tx)
+		}
+		if err != nil {
+			return err
+		}
+		if up {
+			return tx.Table(m.opts.Table).Create(&schemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		}
+		return tx.Table(m.opts.Table).Where("version = ?", migration.Version).Delete(&schemaMigration{}).Error
+	}
+
+	var err error
+	if migration.NoTransaction {
+		err = run(m.db.WithContext(ctx))
+	} else {
+		err = m.db.WithContext(ctx).Transaction(run)
+	}
+	if err != nil {
+		logger.Error(ctx, "Migration failed: database=%s, version=%d, name=%s, direction=%s, error=%v", m.name, migration.Version, migration.Name, direction, err)
+		return fmt.Errorf("migration %d_%s %s failed: %w", migration.Version, migration.Name, direction, err)
+	}
+	logger.Info(ctx, "Migration completed: database=%s, version=%d, name=%s, direction=%s, duration=%v", m.name, migration.Version, migration.Name, direction, time.Since(start))
+	return nil
+}
+
+func (m *Migrator) pending(applied map[int64]schemaMigration) []*Migration {
+	pending := make([]*Migration, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int64]schemaMigration, error) {
+	records, err := m.appliedDesc(ctx)
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]schemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+func (m *Migrator) appliedDesc(ctx context.Context) ([]schemaMigration, error) {
+	var records []schemaMigration
+	if err := m.db.WithContext(ctx).Table(m.opts.Table).Order("version DESC").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to load applied migrations: %w", err)
+	}
+	return records, nil
+}
+
+// ensureTables 创建版本记录表与锁表
+func (m *Migrator) ensureTables(ctx context.Context) error {
+	if err := m.ensureTable(ctx, m.opts.Table, &schemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create migration table %s: %w", m.opts.Table, err)
+	}
+	if err := m.ensureTable(ctx, m.opts.LockTable, &migrationLock{}); err != nil {
+		return fmt.Errorf("failed to create migration lock table %s: %w", m.opts.LockTable, err)
+	}
+	return nil
+}
+
+// ensureTable 建表在加锁之前执行，多实例同时建表时以表已存在为成功
+func (m *Migrator) ensureTable(ctx context.Context, table string, model interface{}) error {
+	db := m.db.WithContext(ctx)
+	if db.Migrator().HasTable(table) {
+		return nil
+	}
+	if err := db.Table(table).Migrator().CreateTable(model); err != nil {
+		if db.Migrator().HasTable(table) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// withLock 持有迁移锁执行 fn，保证多实例同时启动时只有一个实例执行迁移；
+// 执行期间定期刷新 locked_at，避免耗时迁移的锁被当作过期锁抢占，锁丢失时取消传给 fn 的 context
+func (m *Migrator) withLock(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := m.ensureTables(ctx); err != nil {
+		return err
+	}
+	owner, err := newLockOwner()
+	if err != nil {
+		return err
+	}
+	if err := m.acquireLock(ctx, owner); err != nil {
+		return err
+	}
+	defer func() {
+		// 使用独立 context，避免调用方 context 取消导致锁无法释放
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := m.db.WithContext(releaseCtx).Table(m.opts.LockTable).
+			Where("id = ? AND owner = ?", 1, owner).Delete(&migrationLock{}).Error; err != nil {
+			logger.Error(ctx, "Failed to release migration lock: database=%s, error=%v", m.name, err)
+		}
+	}()
+
+	lockCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		m.heartbeat(lockCtx, owner, cancel)
+	}()
+	err = fn(lockCtx)
+	cancel(nil)
+	<-stopped
+	if cause := context.Cause(lockCtx); err != nil && errors.Is(cause, ErrLockLost) {
+		return fmt.Errorf("database %s: %w: %w", m.name, cause, err)
+	}
+	return err
+}
+
+// heartbeat 每 LockTTL/3 刷新一次锁的 locked_at；锁已被其他实例抢占，或刷新持续失败导致锁在下次刷新前可能过期时，
+// 以 ErrLockLost 取消 ctx
+func (m *Migrator) heartbeat(ctx context.Context, owner string, cancel context.CancelCauseFunc) {
+	interval := m.opts.LockTTL / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	refreshed := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		now := time.Now()
+		db := m.db.WithContext(ctx).Table(m.opts.LockTable).Where("id = ? AND owner = ?", 1, owner)
+		result := db.Session(&gorm.Session{}).Update("locked_at", now)
+		held := result.RowsAffected
+		if result.Error == nil && held == 0 {
+			// 部分数据库（如 MySQL）值未变化时影响行数为 0，需确认锁是否仍由自己持有
+			result = db.Session(&gorm.Session{}).Count(&held)
+		}
+		switch {
+		case ctx.Err() != nil:
+			return
+		case result.Error == nil && held > 0:
+			refreshed = now
+		case result.Error == nil:
+			logger.Error(ctx, "Migration lock taken over by another instance: database=%s", m.name)
+			cancel(ErrLockLost)
+			return
+		case now.Sub(refreshed)+interval >= m.opts.LockTTL:
+			logger.Error(ctx, "Failed to refresh migration lock, aborting: database=%s, error=%v", m.name, result.Error)
+			cancel(fmt.Errorf("%w: %w", ErrLockLost, result.Error))
+			return
+		default:
+			logger.Warn(ctx, "Failed to refresh migration lock: database=%s, error=%v", m.name, result.Error)
+		}
+	}
+}
+
+func (m *Migrator) acquireLock(ctx context.Context, owner string) error {
+	deadline := time.Now().Add(m.opts.LockTimeout)
+	db := m.db.WithContext(ctx).Table(m.opts.LockTable)
+	waiting := false
+	for {
+		// 先检查再插入，避免锁被占用时每次重试都产生主键冲突错误日志；并发插入仍由主键保证互斥
+		var held int64
+		if err := db.Session(&gorm.Session{}).Where("id = ?", 1).Count(&held).Error; err != nil {
+			return fmt.Errorf("failed to check migration lock: %w", err)
+		}
+		if held == 0 {
+			if err := db.Session(&gorm.Session{}).Create(&migrationLock{ID: 1, Owner: owner, LockedAt: time.Now()}).Error; err == nil {
+				return nil
+			}
+		}
+
+		// 清理持有实例崩溃遗留的过期锁
+		stale := db.Session(&gorm.Session{}).Where("id = ? AND locked_at < ?", 1, time.Now().Add(-m.opts.LockTTL)).Delete(&migrationLock{})
+		if stale.Error == nil && stale.RowsAffected > 0 {
+			logger.Warn(ctx, "Removed stale migration lock: database=%s", m.name)
+			continue
+		}
+
+		if !waiting {
+			logger.Info(ctx, "Waiting for migration lock held by another instance: database=%s", m.name)
+			waiting = true
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("database %s: %w", m.name, ErrLockTimeout)
+		}
+		timer := time.NewTimer(m.opts.RetryInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func newLockOwner() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate lock owner: %w", err)
+	}
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%.40s-%s", hostname, hex.EncodeToString(buf)), nil
+}
+
+func sortStatuses(statuses []Status) {
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+}
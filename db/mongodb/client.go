@@ -5,11 +5,16 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/team-dandelion/quickgo/logger"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 // Client MongoDB 客户端封装
@@ -18,6 +23,10 @@ type Client struct {
 	client *mongo.Client
 	db     *mongo.Database
 	config *MongoConfig
+	// 连接池统计与慢命令检测
+	pool        *poolTracker
+	slow        *slowQueryTracker
+	maxPoolSize int
 }
 
 // NewClient 创建 MongoDB 客户端
@@ -45,6 +54,12 @@ func NewClient(config *MongoConfig) (*Client, error) {
 
 	// 配置客户端选项
 	clientOptions := options.Client().ApplyURI(uri)
+	clientOptions.SetLoggerOptions(newMongoLoggerOptions())
+
+	// 副本集、读偏好、写关注与可重试写（同时适用于 URI 方式）
+	if err := applyReplicaOptions(clientOptions, config); err != nil {
+		return nil, err
+	}
 
 	// 连接池配置
 	if config.MaxPoolSize > 0 {
@@ -54,37 +69,34 @@ func NewClient(config *MongoConfig) (*Client, error) {
 		clientOptions.SetMinPoolSize(config.MinPoolSize)
 	}
 
-	// 解析并设置连接最大空闲时间
-	if config.MaxConnIdleTime != "" {
-		maxConnIdleTime, err := time.ParseDuration(config.MaxConnIdleTime)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse MaxConnIdleTime %s: %w", config.MaxConnIdleTime, err)
-		}
-		if maxConnIdleTime > 0 {
-			clientOptions.SetMaxConnIdleTime(maxConnIdleTime)
-		}
+	// 连接最大空闲时间与超时时间
+	if config.MaxConnIdleTime > 0 {
+		clientOptions.SetMaxConnIdleTime(config.MaxConnIdleTime.Std())
+	}
+	if config.ConnectTimeout > 0 {
+		clientOptions.SetConnectTimeout(config.ConnectTimeout.Std())
+	}
+	if config.SocketTimeout > 0 {
+		clientOptions.SetSocketTimeout(config.SocketTimeout.Std())
 	}
 
-	// 解析并设置连接超时时间
-	if config.ConnectTimeout != "" {
-		connectTimeout, err := time.ParseDuration(config.ConnectTimeout)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse ConnectTimeout %s: %w", config.ConnectTimeout, err)
-		}
-		if connectTimeout > 0 {
-			clientOptions.SetConnectTimeout(connectTimeout)
-		}
+	// 解析慢命令阈值
+	slowThreshold := defaultSlowThreshold
+	if config.SlowThreshold != nil {
+		slowThreshold = config.SlowThreshold.Std()
 	}
 
-	// 解析并设置 Socket 超时时间
-	if config.SocketTimeout != "" {
-		socketTimeout, err := time.ParseDuration(config.SocketTimeout)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse SocketTimeout %s: %w", config.SocketTimeout, err)
-		}
-		if socketTimeout > 0 {
-			clientOptions.SetSocketTimeout(socketTimeout)
-		}
+	// 连接池事件与命令事件监控（用于 Stats、慢命令回调与 tracing span）
+	pool := &poolTracker{}
+	clientOptions.SetPoolMonitor(&event.PoolMonitor{Event: pool.handle})
+	var slow *slowQueryTracker
+	if slowThreshold > 0 {
+		slow = &slowQueryTracker{name: config.Name, threshold: slowThreshold}
+	}
+	clientOptions.SetMonitor((&commandTracker{slow: slow}).monitor())
+	maxPoolSize := defaultMaxPoolSize
+	if clientOptions.MaxPoolSize != nil {
+		maxPoolSize = int(*clientOptions.MaxPoolSize)
 	}
 
 	// 创建客户端
@@ -113,10 +125,13 @@ func NewClient(config *MongoConfig) (*Client, error) {
 	logger.Info(ctx, "MongoDB client initialized successfully: name=%s, database=%s", config.Name, dbName)
 
 	return &Client{
-		name:   config.Name,
-		client: client,
-		db:     db,
-		config: config,
+		name:        config.Name,
+		client:      client,
+		db:          db,
+		config:      config,
+		pool:        pool,
+		slow:        slow,
+		maxPoolSize: maxPoolSize,
 	}, nil
 }
 
@@ -167,20 +182,74 @@ func (c *Client) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
+// applyReplicaOptions 应用副本集相关配置
+func applyReplicaOptions(clientOptions *options.ClientOptions, config *MongoConfig) error {
+	if config.ReplicaSet != "" {
+		clientOptions.SetReplicaSet(config.ReplicaSet)
+	}
+	if config.ReadPreference != "" {
+		mode, err := readpref.ModeFromString(config.ReadPreference)
+		if err != nil {
+			return fmt.Errorf("invalid readPreference %q: %w", config.ReadPreference, err)
+		}
+		pref, err := readpref.New(mode)
+		if err != nil {
+			return fmt.Errorf("invalid readPreference %q: %w", config.ReadPreference, err)
+		}
+		clientOptions.SetReadPreference(pref)
+	}
+	if config.WriteConcern != "" {
+		wc, err := parseWriteConcern(config.WriteConcern)
+		if err != nil {
+			return err
+		}
+		clientOptions.SetWriteConcern(wc)
+	}
+	if config.RetryWrites != nil {
+		clientOptions.SetRetryWrites(*config.RetryWrites)
+	}
+	return nil
+}
+
+// parseWriteConcern 解析写关注：majority 或确认节点数
+func parseWriteConcern(value string) (*writeconcern.WriteConcern, error) {
+	if strings.EqualFold(value, "majority") {
+		return writeconcern.Majority(), nil
+	}
+	w, err := strconv.Atoi(value)
+	if err != nil || w < 0 {
+		return nil, fmt.Errorf("invalid writeConcern %q: expected majority or a non-negative number", value)
+	}
+	return &writeconcern.WriteConcern{W: w}, nil
+}
+
 // buildURI 构建 MongoDB URI
 func buildURI(config *MongoConfig) (string, error) {
-	if config.Host == "" {
-		return "", fmt.Errorf("host is required")
+	hosts := make([]string, 0, len(config.Hosts))
+	for _, host := range config.Hosts {
+		if host = strings.TrimSpace(host); host == "" {
+			continue
+		}
+		// 未指定端口时使用默认端口
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			host = net.JoinHostPort(host, "27017")
+		}
+		hosts = append(hosts, host)
 	}
-
-	port := config.Port
-	if port == 0 {
-		port = 27017
+	if len(hosts) == 0 {
+		if config.Host == "" {
+			return "", fmt.Errorf("host is required")
+		}
+		port := config.Port
+		if port == 0 {
+			port = 27017
+		}
+		hosts = append(hosts, net.JoinHostPort(config.Host, fmt.Sprintf("%d", port)))
 	}
 
 	u := url.URL{
 		Scheme: "mongodb",
-		Host:   net.JoinHostPort(config.Host, fmt.Sprintf("%d", port)),
+		Host:   strings.Join(hosts, ","),
 	}
 	if config.Username != "" && config.Password != "" {
 		u.User = url.UserPassword(config.Username, config.Password)
@@ -3,6 +3,9 @@ package mongodb
 import (
 	"net/url"
 	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 func TestBuildURIEncodesCredentialsDatabaseAndOptions(t *testing.T) {
@@ -46,6 +49,51 @@ func TestBuildURIEncodesCredentialsDatabaseAndOptions(t *testing.T) {
 	}
 }
 
+func TestBuildURIReplicaSetHosts(t *testing.T) {
+	uri, err := buildURI(&MongoConfig{
+		Host:     "ignored",
+		Hosts:    []string{"mongo-0:27017", " mongo-1 ", ""},
+		Database: "app",
+	})
+	if err != nil {
+		t.Fatalf("buildURI failed: %v", err)
+	}
+	if uri != "mongodb://mongo-0:27017,mongo-1:27017/app" {
+		t.Fatalf("unexpected replica set uri: %q", uri)
+	}
+}
+
+func TestApplyReplicaOptions(t *testing.T) {
+	retryWrites := false
+	clientOptions := options.Client()
+	err := applyReplicaOptions(clientOptions, &MongoConfig{
+		ReplicaSet:     "rs0",
+		ReadPreference: "secondaryPreferred",
+		WriteConcern:   "majority",
+		RetryWrites:    &retryWrites,
+	})
+	if err != nil {
+		t.Fatalf("applyReplicaOptions failed: %v", err)
+	}
+	if *clientOptions.ReplicaSet != "rs0" || clientOptions.ReadPreference.Mode() != readpref.SecondaryPreferredMode {
+		t.Fatalf("unexpected replica set options: %+v", clientOptions)
+	}
+	if clientOptions.WriteConcern.W != "majority" || *clientOptions.RetryWrites {
+		t.Fatalf("unexpected write options: w=%v retryWrites=%v", clientOptions.WriteConcern.W, *clientOptions.RetryWrites)
+	}
+
+	clientOptions = options.Client()
+	if err := applyReplicaOptions(clientOptions, &MongoConfig{WriteConcern: "2"}); err != nil || clientOptions.WriteConcern.W != 2 {
+		t.Fatalf("expected numeric write concern, got %v err=%v", clientOptions.WriteConcern, err)
+	}
+	if err := applyReplicaOptions(options.Client(), &MongoConfig{ReadPreference: "fastest"}); err == nil {
+		t.Fatal("expected invalid read preference error")
+	}
+	if err := applyReplicaOptions(options.Client(), &MongoConfig{WriteConcern: "all"}); err == nil {
+		t.Fatal("expected invalid write concern error")
+	}
+}
+
 func TestBuildURIRequiresHost(t *testing.T) {
 	if _, err := buildURI(&MongoConfig{}); err == nil {
 		t.Fatal("expected missing host to return an error")
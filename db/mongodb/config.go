@@ -1,5 +1,7 @@
 package mongodb
 
+import "github.com/team-dandelion/quickgo/types"
+
 // MongoConfig MongoDB 配置
 type MongoConfig struct {
 	// 数据库名称（用于多实例管理）
@@ -10,6 +12,18 @@ type MongoConfig struct {
 	Host string `json:"host" yaml:"host" toml:"host"`
 	// 端口（不使用 URI 时）
 	Port int `json:"port" yaml:"port" toml:"port"`
+	// 副本集节点地址列表（不使用 URI 时，配置后忽略 Host、Port） 示例：[mongo-0:27017, mongo-1:27017]
+	Hosts []string `json:"hosts" yaml:"hosts" toml:"hosts"`
+	// 副本集名称 示例：rs0
+	ReplicaSet string `json:"replicaSet" yaml:"replicaSet" toml:"replicaSet"`
+	// 读偏好：primary（默认）、primaryPreferred、secondary、secondaryPreferred、nearest
+	ReadPreference string `json:"readPreference" yaml:"readPreference" toml:"readPreference"`
+	// 写关注：majority 或确认节点数（如 1），为空使用 driver 默认值
+	WriteConcern string `json:"writeConcern" yaml:"writeConcern" toml:"writeConcern"`
+	// 是否启用可重试写（driver 默认启用），为空使用默认值
+	RetryWrites *bool `json:"retryWrites" yaml:"retryWrites" toml:"retryWrites"`
+	// WithTransaction 遇到临时错误（TransientTransactionError、UnknownTransactionCommitResult）的最大重试次数，默认 3
+	TransactionRetries int `json:"transactionRetries" yaml:"transactionRetries" toml:"transactionRetries"`
 	// 用户名（不使用 URI 时）
 	Username string `json:"username" yaml:"username" toml:"username"`
 	// 密码（不使用 URI 时）
@@ -19,11 +33,13 @@ type MongoConfig struct {
 	// 认证数据库（不使用 URI 时）
 	AuthSource string `json:"authSource" yaml:"authSource" toml:"authSource"`
 	// 连接池配置
-	MaxPoolSize     uint64 `json:"maxPoolSize" yaml:"maxPoolSize" toml:"maxPoolSize"`             // 最大连接池大小
-	MinPoolSize     uint64 `json:"minPoolSize" yaml:"minPoolSize" toml:"minPoolSize"`             // 最小连接池大小
-	MaxConnIdleTime string `json:"maxConnIdleTime" yaml:"maxConnIdleTime" toml:"maxConnIdleTime"` // 连接最大空闲时间（如：30m、1h）
-	ConnectTimeout  string `json:"connectTimeout" yaml:"connectTimeout" toml:"connectTimeout"`    // 连接超时时间（如：10s、30s）
-	SocketTimeout   string `json:"socketTimeout" yaml:"socketTimeout" toml:"socketTimeout"`       // Socket 超时时间（如：30s、1m）
+	MaxPoolSize     uint64         `json:"maxPoolSize" yaml:"maxPoolSize" toml:"maxPoolSize"`             // 最大连接池大小
+	MinPoolSize     uint64         `json:"minPoolSize" yaml:"minPoolSize" toml:"minPoolSize"`             // 最小连接池大小
+	MaxConnIdleTime types.Duration `json:"maxConnIdleTime" yaml:"maxConnIdleTime" toml:"maxConnIdleTime"` // 连接最大空闲时间（如：30m、1h）
+	ConnectTimeout  types.Duration `json:"connectTimeout" yaml:"connectTimeout" toml:"connectTimeout"`    // 连接超时时间（如：10s、30s）
+	SocketTimeout   types.Duration `json:"socketTimeout" yaml:"socketTimeout" toml:"socketTimeout"`       // Socket 超时时间（如：30s、1m）
+	// 慢命令阈值（如：50ms、100ms），未设置时默认 100ms，显式设置为 0 关闭；超过后输出告警日志并触发 OnSlowQuery 回调
+	SlowThreshold *types.Duration `json:"slowThreshold" yaml:"slowThreshold" toml:"slowThreshold"`
 	// 其他选项
 	Options map[string]string `json:"options" yaml:"options" toml:"options"`
 }
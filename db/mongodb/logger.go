@@ -0,0 +1,52 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// mongoLogSink 将 mongo driver 日志转发到框架 logger
+// 日志级别由 logger.SetLibraryLevel(logger.LibraryMongo, ...) 控制
+type mongoLogSink struct{}
+
+// Info 输出普通日志（level 0 为 info，1 为 debug）
+func (s *mongoLogSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	logLevel := logger.LevelInfo
+	if level > 0 {
+		logLevel = logger.LevelDebug
+	}
+	logger.LogLibrary(context.Background(), logger.LibraryMongo, logLevel, msg, keyValuesToFields(keysAndValues))
+}
+
+// Error 输出错误日志
+func (s *mongoLogSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	fields := keyValuesToFields(keysAndValues)
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	logger.LogLibrary(context.Background(), logger.LibraryMongo, logger.LevelError, msg, fields)
+}
+
+// newMongoLoggerOptions 根据 mongo 库日志级别创建 driver 日志选项
+// driver 仅区分 info 与 debug，库级别高于 info 时只保留错误日志
+func newMongoLoggerOptions() *options.LoggerOptions {
+	level := options.LogLevelInfo
+	if logger.GetLibraryLevel(logger.LibraryMongo) <= logger.LevelDebug {
+		level = options.LogLevelDebug
+	}
+	return options.Logger().
+		SetSink(&mongoLogSink{}).
+		SetComponentLevel(options.LogComponentAll, level)
+}
+
+func keyValuesToFields(keysAndValues []interface{}) map[string]interface{} {
+	fields := make(map[string]interface{}, len(keysAndValues)/2+1)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		fields[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+	}
+	return fields
+}
@@ -0,0 +1,98 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/team-dandelion/quickgo/tracing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// commandTracker 基于 driver 命令事件输出慢命令日志与 OpenTelemetry span（与 gormLogger 对 SQL 的处理一致）
+type commandTracker struct {
+	// 慢命令检测（nil 表示关闭）
+	slow *slowQueryTracker
+	// 执行中的命令（key 为 RequestID），用于在完成事件中取回集合名与 span
+	inflight sync.Map
+}
+
+// inflightCommand 执行中的命令
+type inflightCommand struct {
+	collection string
+	span       trace.Span
+}
+
+// monitor 返回 driver 命令监控器
+func (t *commandTracker) monitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: t.started,
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			t.finished(ctx, &e.CommandFinishedEvent, nil)
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			t.finished(ctx, &e.CommandFinishedEvent, errors.New(e.Failure))
+		},
+	}
+}
+
+func (t *commandTracker) started(ctx context.Context, e *event.CommandStartedEvent) {
+	command := &inflightCommand{collection: commandCollection(e.CommandName, e.Command)}
+	if tracing.IsEnabled() {
+		_, span := tracing.StartSpan(ctx, "mongodb."+e.CommandName, trace.WithSpanKind(trace.SpanKindClient))
+		span.SetAttributes(
+			attribute.String("db.system", "mongodb"),
+			attribute.String("db.name", e.DatabaseName),
+			attribute.String("db.operation", e.CommandName),
+			attribute.String("net.peer.name", e.ConnectionID),
+		)
+		if command.collection != "" {
+			span.SetAttributes(attribute.String("db.mongodb.collection", command.collection))
+		}
+		tracing.AddTraceIDToSpan(span, ctx)
+		command.span = span
+	}
+	if t.slow == nil && command.span == nil {
+		return
+	}
+	t.inflight.Store(e.RequestID, command)
+}
+
+func (t *commandTracker) finished(ctx context.Context, e *event.CommandFinishedEvent, err error) {
+	var collection string
+	if value, ok := t.inflight.LoadAndDelete(e.RequestID); ok {
+		command := value.(*inflightCommand)
+		collection = command.collection
+		if span := command.span; span != nil {
+			span.SetAttributes(attribute.Float64("db.duration_ms", float64(e.Duration.Nanoseconds())/1e6))
+			if t.slow != nil && e.Duration > t.slow.threshold {
+				span.SetAttributes(attribute.Bool("db.slow_query", true))
+			}
+			if err != nil {
+				tracing.SetSpanError(span, err)
+			}
+			span.End()
+		}
+	}
+	if t.slow != nil {
+		t.slow.finished(ctx, e, collection, err)
+	}
+}
+
+// commandCollection 从命令文档中解析集合名（find、insert、update、aggregate 等命令的首个字段值，getMore 的 collection 字段）
+func commandCollection(commandName string, command bson.Raw) string {
+	if len(command) == 0 {
+		return ""
+	}
+	if collection, ok := command.Lookup(commandName).StringValueOK(); ok {
+		return collection
+	}
+	if collection, ok := command.Lookup("collection").StringValueOK(); ok {
+		return collection
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/team-dandelion/quickgo/tracing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func rawCommand(t *testing.T, doc bson.D) bson.Raw {
+	t.Helper()
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		t.Fatalf("marshal command: %v", err)
+	}
+	return raw
+}
+
+func TestCommandCollection(t *testing.T) {
+	if got := commandCollection("find", rawCommand(t, bson.D{{Key: "find", Value: "users"}, {Key: "filter", Value: bson.D{}}})); got != "users" {
+		t.Fatalf("expected users, got %q", got)
+	}
+	if got := commandCollection("getMore", rawCommand(t, bson.D{{Key: "getMore", Value: int64(42)}, {Key: "collection", Value: "orders"}})); got != "orders" {
+		t.Fatalf("expected orders, got %q", got)
+	}
+	if got := commandCollection("ping", rawCommand(t, bson.D{{Key: "ping", Value: 1}})); got != "" {
+		t.Fatalf("expected empty collection, got %q", got)
+	}
+	if got := commandCollection("saslStart", nil); got != "" {
+		t.Fatalf("expected empty collection for redacted command, got %q", got)
+	}
+}
+
+func TestCommandTrackerSlowQueryCollection(t *testing.T) {
+	slow := &slowQueryTracker{name: "main", threshold: 10 * time.Millisecond}
+	var queries []SlowQuery
+	slow.add(func(ctx context.Context, q SlowQuery) { queries = append(queries, q) })
+	tracker := &commandTracker{slow: slow}
+	monitor := tracker.monitor()
+
+	ctx := context.Background()
+	monitor.Started(ctx, &event.CommandStartedEvent{
+		Command: rawCommand(t, bson.D{{Key: "aggregate", Value: "orders"}}), DatabaseName: "app", CommandName: "aggregate", RequestID: 7,
+	})
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: event.CommandFinishedEvent{
+		CommandName: "aggregate", DatabaseName: "app", RequestID: 7, Duration: 20 * time.Millisecond,
+	}})
+
+	if len(queries) != 1 || queries[0].Collection != "orders" || queries[0].Command != "aggregate" {
+		t.Fatalf("expected slow aggregate on orders, got %+v", queries)
+	}
+	if _, ok := tracker.inflight.Load(int64(7)); ok {
+		t.Fatal("expected finished command to be removed")
+	}
+}
+
+func TestCommandTrackerCreatesSpans(t *testing.T) {
+	config := tracing.DefaultConfig()
+	config.Enabled = true
+	if err := tracing.Init(&config); err != nil {
+		t.Fatalf("tracing init failed: %v", err)
+	}
+	defer tracing.Shutdown(context.Background())
+
+	tracker := &commandTracker{}
+	monitor := tracker.monitor()
+	ctx := context.Background()
+	monitor.Started(ctx, &event.CommandStartedEvent{
+		Command: rawCommand(t, bson.D{{Key: "insert", Value: "users"}}), DatabaseName: "app", CommandName: "insert", RequestID: 9,
+	})
+	value, ok := tracker.inflight.Load(int64(9))
+	if !ok {
+		t.Fatal("expected in-flight command with span")
+	}
+	span := value.(*inflightCommand).span
+	if span == nil || !span.IsRecording() {
+		t.Fatal("expected recording span for command")
+	}
+	monitor.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: event.CommandFinishedEvent{
+		CommandName: "insert", DatabaseName: "app", RequestID: 9, Duration: time.Millisecond,
+	}, Failure: "duplicate key"})
+	if span.IsRecording() {
+		t.Fatal("expected span to be ended after command finished")
+	}
+	if _, ok := tracker.inflight.Load(int64(9)); ok {
+		t.Fatal("expected finished command to be removed")
+	}
+}
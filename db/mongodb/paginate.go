@@ -0,0 +1,77 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/team-dandelion/quickgo/pagination"
+)
+
+// Paginate 按排序键进行游标分页查询（keyset pagination），filter 为业务过滤条件（可为 nil）
+// 排序字段应有对应的复合索引
+func Paginate[T any, K any](ctx context.Context, coll *mongo.Collection, filter interface{}, keyset *pagination.Keyset[T, K], req pagination.Request) (*pagination.Page[T], error) {
+	if err := keyset.Validate(); err != nil {
+		return nil, err
+	}
+	after, err := keyset.After(req)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := keysetFilter(filter, keyset.Sort, after)
+	if err != nil {
+		return nil, err
+	}
+	sort := make(bson.D, 0, len(keyset.Sort))
+	for _, key := range keyset.Sort {
+		direction := 1
+		if key.Desc {
+			direction = -1
+		}
+		sort = append(sort, bson.E{Key: key.Column, Value: direction})
+	}
+
+	limit := req.PageLimit()
+	cursor, err := coll.Find(ctx, query, options.Find().SetSort(sort).SetLimit(int64(limit+1)))
+	if err != nil {
+		return nil, err
+	}
+	var items []T
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return keyset.BuildPage(items, limit)
+}
+
+// keysetFilter 合并业务过滤条件与游标条件
+func keysetFilter(filter interface{}, sort []pagination.SortKey, after []interface{}) (interface{}, error) {
+	if filter == nil {
+		filter = bson.D{}
+	}
+	if after == nil {
+		return filter, nil
+	}
+	groups, err := pagination.Conditions(sort, after)
+	if err != nil {
+		return nil, err
+	}
+	ors := make(bson.A, 0, len(groups))
+	for _, group := range groups {
+		and := make(bson.D, 0, len(group))
+		for _, cmp := range group {
+			switch cmp.Op {
+			case ">":
+				and = append(and, bson.E{Key: cmp.Column, Value: bson.D{{Key: "$gt", Value: cmp.Value}}})
+			case "<":
+				and = append(and, bson.E{Key: cmp.Column, Value: bson.D{{Key: "$lt", Value: cmp.Value}}})
+			default:
+				and = append(and, bson.E{Key: cmp.Column, Value: cmp.Value})
+			}
+		}
+		ors = append(ors, and)
+	}
+	return bson.D{{Key: "$and", Value: bson.A{filter, bson.D{{Key: "$or", Value: ors}}}}}, nil
+}
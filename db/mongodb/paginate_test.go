@@ -0,0 +1,29 @@
+package mongodb
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/team-dandelion/quickgo/pagination"
+)
+
+func TestKeysetFilterCombinesBusinessFilter(t *testing.T) {
+	sort := []pagination.SortKey{{Column: "createdAt", Desc: true}, {Column: "_id"}}
+	filter, err := keysetFilter(bson.M{"status": "paid"}, sort, []interface{}{int64(100), "abc"})
+	if err != nil {
+		t.Fatalf("keysetFilter failed: %v", err)
+	}
+	data, err := bson.MarshalExtJSON(filter, false, false)
+	if err != nil {
+		t.Fatalf("MarshalExtJSON failed: %v", err)
+	}
+	want := `{"$and":[{"status":"paid"},{"$or":[{"createdAt":{"$lt":100}},{"createdAt":100,"_id":{"$gt":"abc"}}]}]}`
+	if string(data) != want {
+		t.Fatalf("unexpected filter\n got: %s\nwant: %s", data, want)
+	}
+
+	if first, err := keysetFilter(nil, sort, nil); err != nil || len(first.(bson.D)) != 0 {
+		t.Fatalf("first page should use an empty filter, got %v (%v)", first, err)
+	}
+}
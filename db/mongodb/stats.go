@@ -0,0 +1,210 @@
+package mongodb
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+const (
+	// defaultSlowThreshold 默认慢命令阈值
+	defaultSlowThreshold = 100 * time.Millisecond
+	// defaultMaxPoolSize driver 默认最大连接池大小
+	defaultMaxPoolSize = 100
+)
+
+// PoolStats 连接池统计（所有节点汇总）
+type PoolStats struct {
+	// 客户端名称
+	Name string `json:"name"`
+	// 每个节点的最大连接池大小
+	MaxOpen int `json:"maxOpen"`
+	// 当前打开的连接数（使用中 + 空闲）
+	Open int `json:"open"`
+	// 使用中的连接数
+	InUse int `json:"inUse"`
+	// 空闲连接数
+	Idle int `json:"idle"`
+	// 累计获取连接的次数
+	WaitCount int64 `json:"waitCount"`
+	// 累计获取连接的等待时间
+	WaitDuration time.Duration `json:"waitDuration"`
+	// 累计获取连接超时次数（连接池耗尽）
+	Timeouts int64 `json:"timeouts"`
+	// 累计获取连接失败次数（含超时）
+	Failures int64 `json:"failures"`
+}
+
+// SlowQuery 慢命令事件
+type SlowQuery struct {
+	// 客户端名称
+	Name string `json:"name"`
+	// 数据库名称
+	Database string `json:"database"`
+	// 集合名称（无法从命令中解析时为空）
+	Collection string `json:"collection,omitempty"`
+	// 命令名称（如：find、insert、aggregate）
+	Command string `json:"command"`
+	// 执行耗时
+	Duration time.Duration `json:"duration"`
+	// 慢命令阈值
+	Threshold time.Duration `json:"threshold"`
+	// 执行错误（可能为 nil）
+	Err error `json:"-"`
+}
+
+// SlowQueryHook 慢命令回调，在 driver 的监控协程中同步执行，应避免阻塞
+type SlowQueryHook func(ctx context.Context, query SlowQuery)
+
+// poolTracker 基于 driver 连接池事件统计连接数
+type poolTracker struct {
+	open         atomic.Int64
+	inUse        atomic.Int64
+	waitCount    atomic.Int64
+	waitDuration atomic.Int64
+	timeouts     atomic.Int64
+	failures     atomic.Int64
+}
+
+// handle 处理连接池事件（event.PoolMonitor.Event）
+func (p *poolTracker) handle(e *event.PoolEvent) {
+	switch e.Type {
+	case event.ConnectionCreated:
+		p.open.Add(1)
+	case event.ConnectionClosed:
+		p.open.Add(-1)
+	case event.GetSucceeded:
+		p.inUse.Add(1)
+		p.waitCount.Add(1)
+		p.waitDuration.Add(int64(e.Duration))
+	case event.GetFailed:
+		p.waitCount.Add(1)
+		p.waitDuration.Add(int64(e.Duration))
+		p.failures.Add(1)
+		if e.Reason == event.ReasonTimedOut {
+			p.timeouts.Add(1)
+		}
+	case event.ConnectionReturned:
+		p.inUse.Add(-1)
+	}
+}
+
+func (p *poolTracker) snapshot() PoolStats {
+	open := int(max(p.open.Load(), 0))
+	inUse := int(max(p.inUse.Load(), 0))
+	return PoolStats{
+		Open:         open,
+		InUse:        inUse,
+		Idle:         max(open-inUse, 0),
+		WaitCount:    p.waitCount.Load(),
+		WaitDuration: time.Duration(p.waitDuration.Load()),
+		Timeouts:     p.timeouts.Load(),
+		Failures:     p.failures.Load(),
+	}
+}
+
+// slowQueryTracker 基于 driver 命令事件检测慢命令
+type slowQueryTracker struct {
+	name      string
+	threshold time.Duration
+	mu        sync.RWMutex
+	hooks     []SlowQueryHook
+}
+
+func (s *slowQueryTracker) add(hook SlowQueryHook) {
+	if hook == nil {
+		return
+	}
+	s.mu.Lock()
+	s.hooks = append(s.hooks, hook)
+	s.mu.Unlock()
+}
+
+// monitor 返回仅检测慢命令的 driver 命令监控器
+func (s *slowQueryTracker) monitor() *event.CommandMonitor {
+	return (&commandTracker{slow: s}).monitor()
+}
+
+func (s *slowQueryTracker) finished(ctx context.Context, e *event.CommandFinishedEvent, collection string, err error) {
+	if e.Duration <= s.threshold {
+		return
+	}
+	target := e.DatabaseName
+	if collection != "" {
+		target += "." + collection
+	}
+	logger.Warn(ctx, "[MongoDB] [%.3fms] %s %s | slow command: name=%s",
+		float64(e.Duration.Nanoseconds())/1e6, target, e.CommandName, s.name)
+
+	query := SlowQuery{
+		Name:       s.name,
+		Database:   e.DatabaseName,
+		Collection: collection,
+		Command:    e.CommandName,
+		Duration:   e.Duration,
+		Threshold:  s.threshold,
+		Err:        err,
+	}
+	s.mu.RLock()
+	hooks := s.hooks
+	s.mu.RUnlock()
+	for _, hook := range hooks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Error(ctx, "MongoDB slow query hook panic: name=%s, panic=%v", s.name, r)
+				}
+			}()
+			hook(ctx, query)
+		}()
+	}
+}
+
+// Stats 返回连接池统计
+func (c *Client) Stats() PoolStats {
+	var stats PoolStats
+	if c.pool != nil {
+		stats = c.pool.snapshot()
+	}
+	stats.Name = c.name
+	stats.MaxOpen = c.maxPoolSize
+	return stats
+}
+
+// OnSlowQuery 注册慢命令回调，执行耗时超过 SlowThreshold 的命令会触发回调
+func (c *Client) OnSlowQuery(hook SlowQueryHook) {
+	if c.slow != nil {
+		c.slow.add(hook)
+	}
+}
+
+// Stats 返回所有客户端的连接池统计（key 为客户端名称）
+func (m *Manager) Stats() map[string]PoolStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stats := make(map[string]PoolStats, len(m.clients))
+	for name, client := range m.clients {
+		stats[name] = client.Stats()
+	}
+	return stats
+}
+
+// OnSlowQuery 为所有客户端注册慢命令回调（包括之后通过 RegisterClient 添加的客户端）
+func (m *Manager) OnSlowQuery(hook SlowQueryHook) {
+	if hook == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.slowHooks = append(m.slowHooks, hook)
+	for _, client := range m.clients {
+		client.OnSlowQuery(hook)
+	}
+}
@@ -0,0 +1,80 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func TestPoolTrackerStats(t *testing.T) {
+	pool := &poolTracker{}
+	for _, e := range []*event.PoolEvent{
+		{Type: event.ConnectionCreated},
+		{Type: event.ConnectionCreated},
+		{Type: event.GetSucceeded, Duration: 2 * time.Millisecond},
+		{Type: event.GetSucceeded, Duration: 3 * time.Millisecond},
+		{Type: event.ConnectionReturned},
+		{Type: event.GetFailed, Reason: event.ReasonTimedOut, Duration: 5 * time.Millisecond},
+		{Type: event.GetFailed, Reason: "connectionError"},
+	} {
+		pool.handle(e)
+	}
+
+	client := &Client{name: "main", pool: pool, maxPoolSize: 10}
+	stats := client.Stats()
+	want := PoolStats{
+		Name: "main", MaxOpen: 10, Open: 2, InUse: 1, Idle: 1,
+		WaitCount: 4, WaitDuration: 10 * time.Millisecond, Timeouts: 1, Failures: 2,
+	}
+	if stats != want {
+		t.Fatalf("unexpected stats: got %+v, want %+v", stats, want)
+	}
+}
+
+func TestSlowQueryTracker(t *testing.T) {
+	slow := &slowQueryTracker{name: "main", threshold: 10 * time.Millisecond}
+	var queries []SlowQuery
+	slow.add(func(ctx context.Context, q SlowQuery) { queries = append(queries, q) })
+	slow.add(func(ctx context.Context, q SlowQuery) { panic("hook panic must be recovered") })
+
+	monitor := slow.monitor()
+	ctx := context.Background()
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: event.CommandFinishedEvent{
+		CommandName: "find", DatabaseName: "app", Duration: time.Millisecond,
+	}})
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: event.CommandFinishedEvent{
+		CommandName: "aggregate", DatabaseName: "app", Duration: 20 * time.Millisecond,
+	}})
+	monitor.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: event.CommandFinishedEvent{
+		CommandName: "insert", DatabaseName: "app", Duration: 30 * time.Millisecond,
+	}, Failure: "duplicate key"})
+
+	if len(queries) != 2 {
+		t.Fatalf("expected 2 slow commands, got %+v", queries)
+	}
+	if q := queries[0]; q.Command != "aggregate" || q.Database != "app" || q.Name != "main" || q.Err != nil {
+		t.Fatalf("unexpected slow command: %+v", q)
+	}
+	if q := queries[1]; q.Command != "insert" || q.Err == nil || q.Err.Error() != "duplicate key" {
+		t.Fatalf("unexpected failed slow command: %+v", q)
+	}
+}
+
+func TestManagerOnSlowQueryAppliesToClients(t *testing.T) {
+	slow := &slowQueryTracker{name: "main", threshold: time.Millisecond}
+	manager := &Manager{clients: map[string]*Client{"main": {name: "main", slow: slow}}}
+
+	fired := 0
+	manager.OnSlowQuery(func(ctx context.Context, q SlowQuery) { fired++ })
+	slow.monitor().Succeeded(context.Background(), &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", Duration: time.Second},
+	})
+	if fired != 1 || len(manager.slowHooks) != 1 {
+		t.Fatalf("expected manager hook to fire once, got %d", fired)
+	}
+	if _, ok := manager.Stats()["main"]; !ok {
+		t.Fatal("expected stats for main")
+	}
+}
@@ -0,0 +1,123 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/tracing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// defaultTransactionRetries 默认事务临时错误重试次数
+	defaultTransactionRetries = 3
+	// transactionRetryBackoff 事务重试的基础退避间隔（按重试次数线性增加）
+	transactionRetryBackoff = 20 * time.Millisecond
+
+	// 服务端返回的事务错误标签
+	transientTransactionLabel = "TransientTransactionError"
+	unknownCommitResultLabel  = "UnknownTransactionCommitResult"
+)
+
+// WithTransaction 在新会话中执行事务：fn 返回 nil 时提交，返回错误时回滚
+// 遇到 TransientTransactionError 时重试整个事务，提交结果未知（UnknownTransactionCommitResult）时重试提交，
+// 最大重试次数由 MongoConfig.TransactionRetries 控制；fn 可能被多次执行，应保证幂等且只通过 sc 访问数据库
+// 需要副本集或分片集群（单节点部署不支持事务）
+func (c *Client) WithTransaction(ctx context.Context, fn func(sc mongo.SessionContext) error, opts ...*options.TransactionOptions) error {
+	if c.client == nil {
+		return fmt.Errorf("mongodb client is nil")
+	}
+
+	var span trace.Span
+	if tracing.IsEnabled() {
+		ctx, span = tracing.StartSpan(ctx, "mongodb.transaction")
+		defer span.End()
+		span.SetAttributes(
+			attribute.String("db.system", "mongodb"),
+			attribute.String("db.name", c.db.Name()),
+		)
+		tracing.AddTraceIDToSpan(span, ctx)
+	}
+
+	session, err := c.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start mongodb session: %w", err)
+	}
+	defer session.EndSession(context.WithoutCancel(ctx))
+
+	retries := c.transactionRetries()
+	attempts := 0
+	err = retryTransaction(ctx, retries, func() error {
+		attempts++
+		return mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
+			if err := session.StartTransaction(opts...); err != nil {
+				return err
+			}
+			if err := fn(sc); err != nil {
+				if abortErr := session.AbortTransaction(context.WithoutCancel(sc)); abortErr != nil {
+					logger.Warn(sc, "MongoDB transaction abort failed: name=%s, error=%v", c.name, abortErr)
+				}
+				return err
+			}
+			return commitTransaction(sc, retries, session.CommitTransaction)
+		})
+	})
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("db.transaction.attempts", attempts))
+		if err != nil {
+			tracing.SetSpanError(span, err)
+		}
+	}
+	return err
+}
+
+// transactionRetries 返回事务临时错误重试次数
+func (c *Client) transactionRetries() int {
+	if c.config != nil && c.config.TransactionRetries > 0 {
+		return c.config.TransactionRetries
+	}
+	return defaultTransactionRetries
+}
+
+// retryTransaction 执行事务，遇到 TransientTransactionError 时按退避间隔重试，最多重试 retries 次
+func retryTransaction(ctx context.Context, retries int, run func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := run()
+		if err == nil || attempt >= retries || !hasErrorLabel(err, transientTransactionLabel) {
+			return err
+		}
+		logger.Warn(ctx, "MongoDB transaction transient error, retrying: attempt=%d, error=%v", attempt+1, err)
+		timer := time.NewTimer(time.Duration(attempt+1) * transactionRetryBackoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// commitTransaction 提交事务，提交结果未知（UnknownTransactionCommitResult）时重试提交，最多重试 retries 次
+func commitTransaction(ctx context.Context, retries int, commit func(context.Context) error) error {
+	for attempt := 0; ; attempt++ {
+		err := commit(ctx)
+		if err == nil || attempt >= retries || ctx.Err() != nil || !hasErrorLabel(err, unknownCommitResultLabel) {
+			return err
+		}
+		logger.Warn(ctx, "MongoDB transaction commit result unknown, retrying: attempt=%d, error=%v", attempt+1, err)
+	}
+}
+
+// hasErrorLabel 判断错误是否带有指定的错误标签
+func hasErrorLabel(err error, label string) bool {
+	var labeled interface{ HasErrorLabel(string) bool }
+	return errors.As(err, &labeled) && labeled.HasErrorLabel(label)
+}
@@ -0,0 +1,68 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestRetryTransactionRetriesTransientErrors(t *testing.T) {
+	transient := mongo.CommandError{Code: 112, Message: "write conflict", Labels: []string{transientTransactionLabel}}
+
+	calls := 0
+	err := retryTransaction(context.Background(), 3, func() error {
+		calls++
+		if calls < 3 {
+			return transient
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("expected success after 3 attempts, got err=%v calls=%d", err, calls)
+	}
+
+	calls = 0
+	err = retryTransaction(context.Background(), 2, func() error {
+		calls++
+		return transient
+	})
+	if !hasErrorLabel(err, transientTransactionLabel) || calls != 3 {
+		t.Fatalf("expected transient error after retries exhausted, got err=%v calls=%d", err, calls)
+	}
+
+	calls = 0
+	business := errors.New("insufficient balance")
+	if err := retryTransaction(context.Background(), 3, func() error {
+		calls++
+		return business
+	}); !errors.Is(err, business) || calls != 1 {
+		t.Fatalf("expected non-transient error without retry, got err=%v calls=%d", err, calls)
+	}
+}
+
+func TestCommitTransactionRetriesUnknownCommitResult(t *testing.T) {
+	unknown := mongo.CommandError{Code: 91, Message: "shutdown in progress", Labels: []string{unknownCommitResultLabel}}
+	calls := 0
+	err := commitTransaction(context.Background(), 3, func(context.Context) error {
+		calls++
+		if calls == 1 {
+			return unknown
+		}
+		return nil
+	})
+	if err != nil || calls != 2 {
+		t.Fatalf("expected commit retry to succeed, got err=%v calls=%d", err, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	if err := commitTransaction(ctx, 3, func(context.Context) error {
+		calls++
+		return unknown
+	}); err == nil || calls != 1 {
+		t.Fatalf("expected canceled context to stop commit retries, got err=%v calls=%d", err, calls)
+	}
+}
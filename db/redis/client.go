@@ -15,6 +15,10 @@ type Client struct {
 	name   string
 	client *redisClient.Client
 	config *RedisConfig
+	// 慢命令回调
+	slowHooks *slowQueryHooks
+	// 命令计数
+	commands *commandCounters
 }
 
 // NewClient 创建 Redis 客户端
@@ -63,78 +67,24 @@ func NewClient(config *RedisConfig) (*Client, error) {
 		options.MinIdleConns = config.MinIdleConns
 	}
 
-	// 解析并设置连接最大生存时间
-	if config.MaxConnAge != "" {
-		maxConnAge, err := time.ParseDuration(config.MaxConnAge)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse MaxConnAge %s: %w", config.MaxConnAge, err)
-		}
-		if maxConnAge > 0 {
-			options.ConnMaxLifetime = maxConnAge
-		}
-	}
-
-	// 解析并设置连接池超时时间
-	if config.PoolTimeout != "" {
-		poolTimeout, err := time.ParseDuration(config.PoolTimeout)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse PoolTimeout %s: %w", config.PoolTimeout, err)
-		}
-		if poolTimeout > 0 {
-			options.PoolTimeout = poolTimeout
-		}
-	} else {
-		options.PoolTimeout = 4 * time.Second // 默认值
-	}
-
-	// 解析并设置空闲连接超时时间
-	if config.IdleTimeout != "" {
-		idleTimeout, err := time.ParseDuration(config.IdleTimeout)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse IdleTimeout %s: %w", config.IdleTimeout, err)
-		}
-		if idleTimeout > 0 {
-			options.ConnMaxIdleTime = idleTimeout
-		}
+	// 连接最大生存时间与空闲连接超时时间
+	if config.MaxConnAge > 0 {
+		options.ConnMaxLifetime = config.MaxConnAge.Std()
 	}
-
-	// 解析并设置连接超时时间
-	if config.DialTimeout != "" {
-		dialTimeout, err := time.ParseDuration(config.DialTimeout)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse DialTimeout %s: %w", config.DialTimeout, err)
-		}
-		if dialTimeout > 0 {
-			options.DialTimeout = dialTimeout
-		}
-	} else {
-		options.DialTimeout = 5 * time.Second // 默认值
+	if config.IdleTimeout > 0 {
+		options.ConnMaxIdleTime = config.IdleTimeout.Std()
 	}
 
-	// 解析并设置读取超时时间
-	if config.ReadTimeout != "" {
-		readTimeout, err := time.ParseDuration(config.ReadTimeout)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse ReadTimeout %s: %w", config.ReadTimeout, err)
-		}
-		if readTimeout > 0 {
-			options.ReadTimeout = readTimeout
-		}
-	} else {
-		options.ReadTimeout = 3 * time.Second // 默认值
-	}
+	// 超时时间（未设置时使用默认值）
+	options.PoolTimeout = config.PoolTimeout.OrDefault(4 * time.Second)
+	options.DialTimeout = config.DialTimeout.OrDefault(5 * time.Second)
+	options.ReadTimeout = config.ReadTimeout.OrDefault(3 * time.Second)
+	options.WriteTimeout = config.WriteTimeout.OrDefault(3 * time.Second)
 
-	// 解析并设置写入超时时间
-	if config.WriteTimeout != "" {
-		writeTimeout, err := time.ParseDuration(config.WriteTimeout)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse WriteTimeout %s: %w", config.WriteTimeout, err)
-		}
-		if writeTimeout > 0 {
-			options.WriteTimeout = writeTimeout
-		}
-	} else {
-		options.WriteTimeout = 3 * time.Second // 默认值
+	// 解析慢命令阈值
+	slowThreshold := defaultSlowThreshold
+	if config.SlowThreshold != nil {
+		slowThreshold = config.SlowThreshold.Std()
 	}
 
 	// TLS 配置（如果需要，可以在这里添加 TLS 配置）
@@ -144,6 +94,17 @@ func NewClient(config *RedisConfig) (*Client, error) {
 
 	// 创建客户端
 	client := redisClient.NewClient(options)
+	slowHooks := &slowQueryHooks{}
+	commands := &commandCounters{}
+	client.AddHook(&commandHook{
+		name:      config.Name,
+		addr:      addr,
+		db:        config.DB,
+		threshold: slowThreshold,
+		enableLog: config.EnableLog,
+		hooks:     slowHooks,
+		counters:  commands,
+	})
 
 	// 测试连接（使用带超时的 context，确保不会无限等待）
 	pingCtx, pingCancel := context.WithTimeout(ctx, 5*time.Second)
@@ -158,9 +119,11 @@ func NewClient(config *RedisConfig) (*Client, error) {
 	logger.Info(ctx, "Redis client initialized successfully: name=%s, addr=%s, db=%d", config.Name, addr, config.DB)
 
 	return &Client{
-		name:   config.Name,
-		client: client,
-		config: config,
+		name:      config.Name,
+		client:    client,
+		config:    config,
+		slowHooks: slowHooks,
+		commands:  commands,
 	}, nil
 }
 
@@ -1,5 +1,7 @@
 package redis
 
+import "github.com/team-dandelion/quickgo/types"
+
 // RedisConfig Redis 配置
 type RedisConfig struct {
 	// 数据库名称（用于多实例管理）
@@ -17,16 +19,20 @@ type RedisConfig struct {
 	// 用户名（Redis 6.0+）
 	Username string `json:"username" yaml:"username" toml:"username"`
 	// 连接池配置
-	PoolSize     int    `json:"poolSize" yaml:"poolSize" toml:"poolSize"`             // 连接池大小
-	MinIdleConns int    `json:"minIdleConns" yaml:"minIdleConns" toml:"minIdleConns"` // 最小空闲连接数
-	MaxConnAge   string `json:"maxConnAge" yaml:"maxConnAge" toml:"maxConnAge"`       // 连接最大生存时间（如：1h、30m）
-	PoolTimeout  string `json:"poolTimeout" yaml:"poolTimeout" toml:"poolTimeout"`    // 获取连接超时时间（如：4s、5s）
-	IdleTimeout  string `json:"idleTimeout" yaml:"idleTimeout" toml:"idleTimeout"`    // 空闲连接超时时间（如：5m、10m）
-	DialTimeout  string `json:"dialTimeout" yaml:"dialTimeout" toml:"dialTimeout"`    // 连接超时时间（如：5s、10s）
-	ReadTimeout  string `json:"readTimeout" yaml:"readTimeout" toml:"readTimeout"`    // 读取超时时间（如：3s、5s）
-	WriteTimeout string `json:"writeTimeout" yaml:"writeTimeout" toml:"writeTimeout"` // 写入超时时间（如：3s、5s）
+	PoolSize     int            `json:"poolSize" yaml:"poolSize" toml:"poolSize"`             // 连接池大小
+	MinIdleConns int            `json:"minIdleConns" yaml:"minIdleConns" toml:"minIdleConns"` // 最小空闲连接数
+	MaxConnAge   types.Duration `json:"maxConnAge" yaml:"maxConnAge" toml:"maxConnAge"`       // 连接最大生存时间（如：1h、30m）
+	PoolTimeout  types.Duration `json:"poolTimeout" yaml:"poolTimeout" toml:"poolTimeout"`    // 获取连接超时时间（如：4s、5s）
+	IdleTimeout  types.Duration `json:"idleTimeout" yaml:"idleTimeout" toml:"idleTimeout"`    // 空闲连接超时时间（如：5m、10m）
+	DialTimeout  types.Duration `json:"dialTimeout" yaml:"dialTimeout" toml:"dialTimeout"`    // 连接超时时间（如：5s、10s）
+	ReadTimeout  types.Duration `json:"readTimeout" yaml:"readTimeout" toml:"readTimeout"`    // 读取超时时间（如：3s、5s）
+	WriteTimeout types.Duration `json:"writeTimeout" yaml:"writeTimeout" toml:"writeTimeout"` // 写入超时时间（如：3s、5s）
 	// 是否启用 TLS
 	TLS bool `json:"tls" yaml:"tls" toml:"tls"`
+	// 慢命令阈值（如：50ms、100ms），未设置时默认 100ms，显式设置为 0 关闭；超过后输出告警日志并触发 OnSlowQuery 回调
+	SlowThreshold *types.Duration `json:"slowThreshold" yaml:"slowThreshold" toml:"slowThreshold"`
+	// 是否输出命令日志（命令名与耗时，不含参数；失败命令输出错误日志），慢命令告警不受影响
+	EnableLog bool `json:"enableLog" yaml:"enableLog" toml:"enableLog"`
 }
 
 // RedisManagerConfig Redis 管理器配置（支持多个数据库实例）
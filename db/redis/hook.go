@@ -0,0 +1,154 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	redisClient "github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/tracing"
+)
+
+// commandCounters 命令计数
+type commandCounters struct {
+	commands atomic.Int64
+	errors   atomic.Int64
+}
+
+// commandHook go-redis 钩子：命令计数、tracing span、命令日志与慢命令检测（与 gormLogger 对 SQL 的处理一致）
+// 日志与 span 只记录命令名，不包含参数，避免泄露数据
+type commandHook struct {
+	name string
+	addr string
+	db   int
+	// 慢命令阈值，0 表示关闭慢命令检测
+	threshold time.Duration
+	// 是否输出命令日志（普通命令 Info，失败命令 Error）
+	enableLog bool
+	hooks     *slowQueryHooks
+	counters  *commandCounters
+}
+
+// DialHook 实现 redis.Hook
+func (h *commandHook) DialHook(next redisClient.DialHook) redisClient.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+// ProcessHook 实现 redis.Hook
+func (h *commandHook) ProcessHook(next redisClient.ProcessHook) redisClient.ProcessHook {
+	return func(ctx context.Context, cmd redisClient.Cmder) error {
+		ctx, span := h.startSpan(ctx, "redis."+cmd.Name(), cmd.Name(), 1)
+		start := time.Now()
+		err := next(ctx, cmd)
+		h.finish(ctx, span, cmd.Name(), 1, time.Since(start), err)
+		return err
+	}
+}
+
+// ProcessPipelineHook 实现 redis.Hook
+func (h *commandHook) ProcessPipelineHook(next redisClient.ProcessPipelineHook) redisClient.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redisClient.Cmder) error {
+		names := make([]string, len(cmds))
+		for i, cmd := range cmds {
+			names[i] = cmd.Name()
+		}
+		command := "pipeline(" + strings.Join(names, ",") + ")"
+		ctx, span := h.startSpan(ctx, "redis.pipeline", command, len(cmds))
+		start := time.Now()
+		err := next(ctx, cmds)
+		// pipeline 返回首个失败命令的错误，错误数按失败命令计数
+		failed := 0
+		for _, cmd := range cmds {
+			if commandFailed(cmd.Err()) {
+				failed++
+			}
+		}
+		if failed == 0 && commandFailed(err) {
+			failed = 1
+		}
+		h.counters.errors.Add(int64(failed))
+		h.counters.commands.Add(int64(len(cmds)))
+		h.observe(ctx, span, command, time.Since(start), err)
+		return err
+	}
+}
+
+// startSpan 启用 tracing 时创建命令 span
+func (h *commandHook) startSpan(ctx context.Context, spanName, command string, count int) (context.Context, trace.Span) {
+	if !tracing.IsEnabled() {
+		return ctx, nil
+	}
+	ctx, span := tracing.StartSpan(ctx, spanName, trace.WithSpanKind(trace.SpanKindClient))
+	span.SetAttributes(
+		attribute.String("db.system", "redis"),
+		attribute.String("db.operation", command),
+		attribute.Int("db.redis.database_index", h.db),
+		attribute.String("net.peer.name", h.addr),
+	)
+	if count > 1 {
+		span.SetAttributes(attribute.Int("db.redis.pipeline_length", count))
+	}
+	tracing.AddTraceIDToSpan(span, ctx)
+	return ctx, span
+}
+
+// finish 记录单条命令的计数、span 与日志
+func (h *commandHook) finish(ctx context.Context, span trace.Span, command string, count int, elapsed time.Duration, err error) {
+	h.counters.commands.Add(int64(count))
+	if commandFailed(err) {
+		h.counters.errors.Add(1)
+	}
+	h.observe(ctx, span, command, elapsed, err)
+}
+
+// observe 结束 span、输出命令日志并检测慢命令
+func (h *commandHook) observe(ctx context.Context, span trace.Span, command string, elapsed time.Duration, err error) {
+	if errors.Is(err, redisClient.Nil) {
+		err = nil
+	}
+	slow := h.threshold > 0 && elapsed > h.threshold
+
+	if span != nil {
+		span.SetAttributes(attribute.Float64("db.duration_ms", float64(elapsed.Nanoseconds())/1e6))
+		if slow {
+			span.SetAttributes(attribute.Bool("db.slow_query", true))
+		}
+		if err != nil {
+			tracing.SetSpanError(span, err)
+		}
+		span.End()
+	}
+
+	switch {
+	case err != nil && h.enableLog:
+		logger.Error(ctx, "[Redis] [%.3fms] %s | name=%s", float64(elapsed.Nanoseconds())/1e6, command, h.name, err)
+	case slow:
+		logger.Warn(ctx, "[Redis] [%.3fms] %s | slow command: name=%s", float64(elapsed.Nanoseconds())/1e6, command, h.name)
+	case h.enableLog:
+		logger.Info(ctx, "[Redis] [%.3fms] %s | name=%s", float64(elapsed.Nanoseconds())/1e6, command, h.name)
+	}
+
+	if slow {
+		h.hooks.fire(ctx, SlowQuery{
+			Name:      h.name,
+			Command:   command,
+			Duration:  elapsed,
+			Threshold: h.threshold,
+			Err:       err,
+		})
+	}
+}
+
+// commandFailed 判断命令是否失败（redis.Nil 表示键不存在，不视为错误）
+func commandFailed(err error) bool {
+	return err != nil && !errors.Is(err, redisClient.Nil)
+}
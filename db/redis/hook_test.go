@@ -0,0 +1,92 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	redisClient "github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/team-dandelion/quickgo/tracing"
+)
+
+func TestCommandHookCountsCommandsAndErrors(t *testing.T) {
+	server := miniredis.RunT(t)
+	client, err := NewClient(&RedisConfig{Name: "cache", Addr: server.Addr(), EnableLog: true, SlowThreshold: durationPtr(0)})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	before := client.Stats()
+	rdb := client.GetClient()
+	ctx := context.Background()
+	rdb.Set(ctx, "k", "v", 0)
+	rdb.Get(ctx, "missing")
+	if err := rdb.Incr(ctx, "k").Err(); err == nil {
+		t.Fatal("expected incr on string value to fail")
+	}
+	pipe := rdb.Pipeline()
+	pipe.Get(ctx, "k")
+	pipe.Incr(ctx, "k")
+	_, _ = pipe.Exec(ctx)
+
+	stats := client.Stats()
+	if got := stats.Commands - before.Commands; got != 5 {
+		t.Fatalf("expected 5 commands, got %d", got)
+	}
+	if got := stats.Errors - before.Errors; got != 2 {
+		t.Fatalf("expected 2 errors (redis.Nil excluded), got %d", got)
+	}
+}
+
+func TestCommandHookCreatesSpans(t *testing.T) {
+	config := tracing.DefaultConfig()
+	config.Enabled = true
+	if err := tracing.Init(&config); err != nil {
+		t.Fatalf("tracing init failed: %v", err)
+	}
+	defer tracing.Shutdown(context.Background())
+
+	server := miniredis.RunT(t)
+	client, err := NewClient(&RedisConfig{Name: "cache", Addr: server.Addr()})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, parent := tracing.StartSpan(context.Background(), "parent")
+	defer parent.End()
+
+	var spans []trace.SpanContext
+	client.GetClient().AddHook(&spanRecorder{spans: &spans})
+	client.GetClient().Set(ctx, "k", "v", 0)
+
+	if len(spans) != 1 || !spans[0].IsValid() || spans[0].SpanID() == parent.SpanContext().SpanID() {
+		t.Fatalf("expected command span as child of parent, got %+v", spans)
+	}
+	if spans[0].TraceID() != parent.SpanContext().TraceID() {
+		t.Fatal("expected command span to share parent trace")
+	}
+}
+
+// spanRecorder 记录命令执行时 context 中的 span（位于 commandHook 之内）
+type spanRecorder struct {
+	spans *[]trace.SpanContext
+}
+
+func (r *spanRecorder) DialHook(next redisClient.DialHook) redisClient.DialHook {
+	return next
+}
+
+func (r *spanRecorder) ProcessPipelineHook(next redisClient.ProcessPipelineHook) redisClient.ProcessPipelineHook {
+	return next
+}
+
+func (r *spanRecorder) ProcessHook(next redisClient.ProcessHook) redisClient.ProcessHook {
+	return func(ctx context.Context, cmd redisClient.Cmder) error {
+		*r.spans = append(*r.spans, trace.SpanContextFromContext(ctx))
+		return next(ctx, cmd)
+	}
+}
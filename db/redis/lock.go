@@ -0,0 +1,255 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	redisClient "github.com/redis/go-redis/v9"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+var (
+	// ErrLockNotHeld 锁未被当前持有者持有（已过期或被他人获取）
+	ErrLockNotHeld = errors.New("redis lock not held")
+	// ErrLockAlreadyHeld 当前 Lock 实例已持有锁
+	ErrLockAlreadyHeld = errors.New("redis lock already held")
+)
+
+// unlockScript 仅当 token 匹配时删除锁
+var unlockScript = redisClient.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// refreshScript 仅当 token 匹配时续期锁
+var refreshScript = redisClient.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// LockOptions 分布式锁配置
+type LockOptions struct {
+	// 锁过期时间（默认 30s）
+	TTL time.Duration
+	// Lock 阻塞获取时的重试间隔（默认 100ms）
+	RetryInterval time.Duration
+	// 是否开启自动续期（watchdog），持有期间定期续期，防止业务执行超过 TTL 导致锁提前释放
+	AutoRenew bool
+	// 自动续期间隔（默认 TTL/3）
+	RenewInterval time.Duration
+}
+
+// Lock 基于 Redis 的分布式锁（SET NX PX + token 校验释放）
+// 同一个 Lock 实例不可重入，不同实例之间互斥
+type Lock struct {
+	client redisClient.Cmdable
+	key    string
+	opts   LockOptions
+
+	mu        sync.Mutex
+	token     string
+	stopRenew chan struct{}
+	renewDone chan struct{}
+}
+
+// NewLock 创建分布式锁
+func NewLock(client redisClient.Cmdable, key string, opts *LockOptions) (*Lock, error) {
+	if client == nil {
+		return nil, errors.New("redis client is nil")
+	}
+	if key == "" {
+		return nil, errors.New("lock key is required")
+	}
+
+	var options LockOptions
+	if opts != nil {
+		options = *opts
+	}
+	if options.TTL <= 0 {
+		options.TTL = 30 * time.Second
+	}
+	if options.RetryInterval <= 0 {
+		options.RetryInterval = 100 * time.Millisecond
+	}
+	if options.RenewInterval <= 0 {
+		options.RenewInterval = options.TTL / 3
+	}
+	if options.RenewInterval >= options.TTL {
+		return nil, fmt.Errorf("renew interval %s must be less than ttl %s", options.RenewInterval, options.TTL)
+	}
+
+	return &Lock{
+		client: client,
+		key:    key,
+		opts:   options,
+	}, nil
+}
+
+// NewLock 基于当前客户端创建分布式锁
+func (c *Client) NewLock(key string, opts *LockOptions) (*Lock, error) {
+	if c == nil || c.client == nil {
+		return nil, errors.New("redis client is nil")
+	}
+	return NewLock(c.client, key, opts)
+}
+
+// Key 返回锁的 key
+func (l *Lock) Key() string {
+	return l.key
+}
+
+// Token 返回当前持有的 token，未持有时为空
+func (l *Lock) Token() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.token
+}
+
+// TryLock 尝试获取锁，不阻塞
+func (l *Lock) TryLock(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.token != "" {
+		return false, ErrLockAlreadyHeld
+	}
+
+	token, err := newLockToken()
+	if err != nil {
+		return false, err
+	}
+	ok, err := l.client.SetNX(ctx, l.key, token, l.opts.TTL).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lock %s: %w", l.key, err)
+	}
+	if !ok {
+		return false, nil
+	}
+
+	l.token = token
+	if l.opts.AutoRenew {
+		l.startWatchdog(token)
+	}
+	return true, nil
+}
+
+// Lock 阻塞获取锁，直到成功或 ctx 结束
+func (l *Lock) Lock(ctx context.Context) error {
+	ticker := time.NewTicker(l.opts.RetryInterval)
+	defer ticker.Stop()
+
+	for {
+		ok, err := l.TryLock(ctx)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to acquire lock %s: %w", l.key, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh 手动续期锁
+func (l *Lock) Refresh(ctx context.Context, ttl time.Duration) error {
+	token := l.Token()
+	if token == "" {
+		return ErrLockNotHeld
+	}
+	if ttl <= 0 {
+		ttl = l.opts.TTL
+	}
+	return l.refresh(ctx, token, ttl)
+}
+
+func (l *Lock) refresh(ctx context.Context, token string, ttl time.Duration) error {
+	result, err := refreshScript.Run(ctx, l.client, []string{l.key}, token, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to refresh lock %s: %w", l.key, err)
+	}
+	if result == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// Unlock 释放锁，仅当锁仍由当前实例持有时才会删除
+func (l *Lock) Unlock(ctx context.Context) error {
+	l.mu.Lock()
+	token := l.token
+	l.token = ""
+	stopRenew, renewDone := l.stopRenew, l.renewDone
+	l.stopRenew, l.renewDone = nil, nil
+	l.mu.Unlock()
+
+	if stopRenew != nil {
+		close(stopRenew)
+		<-renewDone
+	}
+	if token == "" {
+		return ErrLockNotHeld
+	}
+
+	result, err := unlockScript.Run(ctx, l.client, []string{l.key}, token).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", l.key, err)
+	}
+	if result == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// startWatchdog 启动自动续期协程（调用方需持有 l.mu）
+func (l *Lock) startWatchdog(token string) {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	l.stopRenew, l.renewDone = stop, done
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(l.opts.RenewInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), l.opts.RenewInterval)
+				err := l.refresh(ctx, token, l.opts.TTL)
+				cancel()
+				if errors.Is(err, ErrLockNotHeld) {
+					logger.Warn(context.Background(), "Redis lock lost, stop renewing: key=%s", l.key)
+					return
+				}
+				if err != nil {
+					logger.Warn(context.Background(), "Redis lock renew failed: key=%s, error=%v", l.key, err)
+				}
+			}
+		}
+	}()
+}
+
+func newLockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,128 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	redisClient "github.com/redis/go-redis/v9"
+)
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redisClient.Client) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redisClient.NewClient(&redisClient.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return server, client
+}
+
+func TestLockMutualExclusion(t *testing.T) {
+	_, client := newTestRedis(t)
+	ctx := context.Background()
+
+	first, err := NewLock(client, "lock:order", &LockOptions{TTL: time.Second})
+	if err != nil {
+		t.Fatalf("NewLock failed: %v", err)
+	}
+	second, _ := NewLock(client, "lock:order", &LockOptions{TTL: time.Second})
+
+	if ok, err := first.TryLock(ctx); err != nil || !ok {
+		t.Fatalf("expected first TryLock to succeed, ok=%v, err=%v", ok, err)
+	}
+	if ok, err := second.TryLock(ctx); err != nil || ok {
+		t.Fatalf("expected second TryLock to fail, ok=%v, err=%v", ok, err)
+	}
+	if _, err := first.TryLock(ctx); !errors.Is(err, ErrLockAlreadyHeld) {
+		t.Fatalf("expected ErrLockAlreadyHeld, got %v", err)
+	}
+
+	// 非持有者释放不能删除他人的锁
+	if err := second.Unlock(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Fatalf("expected ErrLockNotHeld, got %v", err)
+	}
+	if err := first.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if ok, err := second.TryLock(ctx); err != nil || !ok {
+		t.Fatalf("expected TryLock after unlock to succeed, ok=%v, err=%v", ok, err)
+	}
+}
+
+func TestLockUnlockAfterExpiryDoesNotReleaseOthers(t *testing.T) {
+	server, client := newTestRedis(t)
+	ctx := context.Background()
+
+	first, _ := NewLock(client, "lock:expire", &LockOptions{TTL: time.Second})
+	second, _ := NewLock(client, "lock:expire", &LockOptions{TTL: time.Second})
+
+	if ok, _ := first.TryLock(ctx); !ok {
+		t.Fatal("expected first TryLock to succeed")
+	}
+	server.FastForward(2 * time.Second)
+	if ok, _ := second.TryLock(ctx); !ok {
+		t.Fatal("expected second TryLock to succeed after expiry")
+	}
+	if err := first.Unlock(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Fatalf("expected ErrLockNotHeld for expired holder, got %v", err)
+	}
+	if !server.Exists("lock:expire") {
+		t.Fatal("expected second holder's lock to remain")
+	}
+}
+
+func TestLockBlocksUntilContextDone(t *testing.T) {
+	_, client := newTestRedis(t)
+
+	holder, _ := NewLock(client, "lock:block", nil)
+	waiter, _ := NewLock(client, "lock:block", &LockOptions{RetryInterval: 10 * time.Millisecond})
+	if ok, _ := holder.TryLock(context.Background()); !ok {
+		t.Fatal("expected holder TryLock to succeed")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := waiter.Lock(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = holder.Unlock(context.Background())
+	}()
+	ctx2, cancel2 := context.WithTimeout(context.Background(), time.Second)
+	defer cancel2()
+	if err := waiter.Lock(ctx2); err != nil {
+		t.Fatalf("expected Lock to succeed after release, got %v", err)
+	}
+}
+
+func TestLockAutoRenew(t *testing.T) {
+	server, client := newTestRedis(t)
+
+	lock, err := NewLock(client, "lock:renew", &LockOptions{
+		TTL:           time.Second,
+		AutoRenew:     true,
+		RenewInterval: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewLock failed: %v", err)
+	}
+	if ok, _ := lock.TryLock(context.Background()); !ok {
+		t.Fatal("expected TryLock to succeed")
+	}
+
+	server.SetTTL("lock:renew", 100*time.Millisecond)
+	time.Sleep(60 * time.Millisecond)
+	if ttl := server.TTL("lock:renew"); ttl < 500*time.Millisecond {
+		t.Fatalf("expected watchdog to renew ttl, got %s", ttl)
+	}
+
+	if err := lock.Unlock(context.Background()); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if server.Exists("lock:renew") {
+		t.Fatal("expected lock to be released")
+	}
+}
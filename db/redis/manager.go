@@ -14,6 +14,8 @@ import (
 type Manager struct {
 	clients map[string]*Client
 	mu      sync.RWMutex
+	// 慢命令回调（对之后注册的客户端同样生效）
+	slowHooks []SlowQueryHook
 }
 
 // NewManager 创建 Redis 管理器
@@ -117,6 +119,9 @@ func (m *Manager) RegisterClient(config *RedisConfig) error {
 		_ = client.Close()
 		return fmt.Errorf("redis client already exists: name=%s", config.Name)
 	}
+	for _, hook := range m.slowHooks {
+		client.OnSlowQuery(hook)
+	}
 	m.clients[config.Name] = client
 	logger.Info(ctx, "Redis client registered successfully: name=%s", config.Name)
 
@@ -0,0 +1,145 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// defaultSlowThreshold 默认慢命令阈值
+const defaultSlowThreshold = 100 * time.Millisecond
+
+// PoolStats 连接池统计
+type PoolStats struct {
+	// 客户端名称
+	Name string `json:"name"`
+	// 连接池大小
+	MaxOpen int `json:"maxOpen"`
+	// 当前打开的连接数（使用中 + 空闲）
+	Open int `json:"open"`
+	// 使用中的连接数
+	InUse int `json:"inUse"`
+	// 空闲连接数
+	Idle int `json:"idle"`
+	// 累计等待连接的次数
+	WaitCount int64 `json:"waitCount"`
+	// 累计等待连接的时间
+	WaitDuration time.Duration `json:"waitDuration"`
+	// 累计获取连接超时次数（连接池耗尽）
+	Timeouts uint32 `json:"timeouts"`
+	// 累计命中 / 未命中空闲连接次数
+	Hits   uint32 `json:"hits"`
+	Misses uint32 `json:"misses"`
+	// 已关闭的过期连接数
+	StaleConns uint32 `json:"staleConns"`
+	// 累计执行的命令数（pipeline 按命令计数）
+	Commands int64 `json:"commands"`
+	// 累计失败的命令数（redis.Nil 不视为错误）
+	Errors int64 `json:"errors"`
+}
+
+// SlowQuery 慢命令事件
+type SlowQuery struct {
+	// 客户端名称
+	Name string `json:"name"`
+	// 命令名称，pipeline 为 "pipeline(get,set)" 形式（不包含参数，避免泄露数据）
+	Command string `json:"command"`
+	// 执行耗时
+	Duration time.Duration `json:"duration"`
+	// 慢命令阈值
+	Threshold time.Duration `json:"threshold"`
+	// 执行错误（redis.Nil 不视为错误）
+	Err error `json:"-"`
+}
+
+// SlowQueryHook 慢命令回调，在命令所在协程中同步执行，应避免阻塞
+type SlowQueryHook func(ctx context.Context, query SlowQuery)
+
+// slowQueryHooks 慢命令回调列表（并发安全）
+type slowQueryHooks struct {
+	mu    sync.RWMutex
+	hooks []SlowQueryHook
+}
+
+func (h *slowQueryHooks) add(hook SlowQueryHook) {
+	if h == nil || hook == nil {
+		return
+	}
+	h.mu.Lock()
+	h.hooks = append(h.hooks, hook)
+	h.mu.Unlock()
+}
+
+// fire 依次执行回调，回调 panic 时记录错误日志，不影响命令结果
+func (h *slowQueryHooks) fire(ctx context.Context, query SlowQuery) {
+	h.mu.RLock()
+	hooks := h.hooks
+	h.mu.RUnlock()
+	for _, hook := range hooks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Error(ctx, "Redis slow query hook panic: name=%s, panic=%v", query.Name, r)
+				}
+			}()
+			hook(ctx, query)
+		}()
+	}
+}
+
+// Stats 返回连接池统计
+func (c *Client) Stats() PoolStats {
+	stats := PoolStats{Name: c.name}
+	if c.commands != nil {
+		stats.Commands = c.commands.commands.Load()
+		stats.Errors = c.commands.errors.Load()
+	}
+	if c.client == nil {
+		return stats
+	}
+	s := c.client.PoolStats()
+	stats.MaxOpen = c.client.Options().PoolSize
+	stats.Open = int(s.TotalConns)
+	stats.Idle = int(s.IdleConns)
+	stats.InUse = max(stats.Open-stats.Idle, 0)
+	stats.WaitCount = int64(s.WaitCount)
+	stats.WaitDuration = time.Duration(s.WaitDurationNs)
+	stats.Timeouts = s.Timeouts
+	stats.Hits = s.Hits
+	stats.Misses = s.Misses
+	stats.StaleConns = s.StaleConns
+	return stats
+}
+
+// OnSlowQuery 注册慢命令回调，执行耗时超过 SlowThreshold 的命令会触发回调
+func (c *Client) OnSlowQuery(hook SlowQueryHook) {
+	c.slowHooks.add(hook)
+}
+
+// Stats 返回所有客户端的连接池统计（key 为客户端名称）
+func (m *Manager) Stats() map[string]PoolStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stats := make(map[string]PoolStats, len(m.clients))
+	for name, client := range m.clients {
+		stats[name] = client.Stats()
+	}
+	return stats
+}
+
+// OnSlowQuery 为所有客户端注册慢命令回调（包括之后通过 RegisterClient 添加的客户端）
+func (m *Manager) OnSlowQuery(hook SlowQueryHook) {
+	if hook == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.slowHooks = append(m.slowHooks, hook)
+	for _, client := range m.clients {
+		client.OnSlowQuery(hook)
+	}
+}
@@ -0,0 +1,98 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/team-dandelion/quickgo/json"
+	"github.com/team-dandelion/quickgo/types"
+)
+
+func TestManagerStatsAndSlowQueryHook(t *testing.T) {
+	server := miniredis.RunT(t)
+	manager, err := NewManager(&RedisManagerConfig{Databases: []RedisConfig{{
+		Name:          "cache",
+		Addr:          server.Addr(),
+		PoolSize:      5,
+		SlowThreshold: durationPtr(1),
+	}}})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer manager.Close()
+
+	var queries []SlowQuery
+	manager.OnSlowQuery(func(ctx context.Context, q SlowQuery) {
+		queries = append(queries, q)
+	})
+
+	client, _ := manager.GetRedisClient("cache")
+	ctx := context.Background()
+	client.Set(ctx, "k", "v", 0)
+	client.Get(ctx, "missing")
+	pipe := client.Pipeline()
+	pipe.Get(ctx, "k")
+	pipe.Incr(ctx, "n")
+	if _, err := pipe.Exec(ctx); err != nil {
+		t.Fatalf("pipeline failed: %v", err)
+	}
+
+	if len(queries) != 3 {
+		t.Fatalf("expected 3 slow commands, got %d: %+v", len(queries), queries)
+	}
+	if queries[0].Command != "set" || queries[0].Name != "cache" {
+		t.Fatalf("unexpected slow command: %+v", queries[0])
+	}
+	if queries[1].Command != "get" || queries[1].Err != nil {
+		t.Fatalf("expected redis.Nil not reported as error: %+v", queries[1])
+	}
+	if !strings.HasPrefix(queries[2].Command, "pipeline(get,incr") {
+		t.Fatalf("unexpected pipeline command: %+v", queries[2])
+	}
+
+	stats := manager.Stats()["cache"]
+	if stats.Name != "cache" || stats.MaxOpen != 5 || stats.Open < 1 || stats.Open != stats.InUse+stats.Idle {
+		t.Fatalf("unexpected pool stats: %+v", stats)
+	}
+}
+
+func TestSlowQueryHookDisabled(t *testing.T) {
+	server := miniredis.RunT(t)
+	client, err := NewClient(&RedisConfig{Name: "cache", Addr: server.Addr(), SlowThreshold: durationPtr(0)})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	fired := false
+	client.OnSlowQuery(func(ctx context.Context, q SlowQuery) { fired = true })
+	client.GetClient().Set(context.Background(), "k", "v", 0)
+	if fired {
+		t.Fatal("expected no slow query hook when threshold is 0")
+	}
+}
+
+func TestSlowThresholdDistinguishesZeroFromUnset(t *testing.T) {
+	var unset, zero RedisConfig
+	if err := json.Unmarshal([]byte(`{"name":"cache"}`), &unset); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"name":"cache","slowThreshold":"0s"}`), &zero); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if unset.SlowThreshold != nil {
+		t.Fatalf("expected unset SlowThreshold to stay nil, got %v", *unset.SlowThreshold)
+	}
+	if zero.SlowThreshold == nil || *zero.SlowThreshold != 0 {
+		t.Fatalf("expected explicit 0 SlowThreshold, got %v", zero.SlowThreshold)
+	}
+}
+
+func durationPtr(d time.Duration) *types.Duration {
+	v := types.Duration(d)
+	return &v
+}
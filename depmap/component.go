@@ -0,0 +1,193 @@
+package depmap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// Config 依赖图组件配置
+type Config struct {
+	// 是否启用
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled"`
+	// 组件名称（默认 depmap）
+	Name string `json:"name" yaml:"name" toml:"name"`
+	// 当前服务名称（调用方），必填
+	Service string `json:"service" yaml:"service" toml:"service"`
+	// 导出文件路径（为空时不导出文件，仅通过 Handler / Snapshot 获取）
+	ExportPath string `json:"exportPath" yaml:"exportPath" toml:"exportPath"`
+	// 导出格式：json（默认）、dot
+	Format Format `json:"format" yaml:"format" toml:"format"`
+	// 导出间隔（如 1m，默认 1m），停止时会再导出一次
+	ExportInterval string `json:"exportInterval" yaml:"exportInterval" toml:"exportInterval"`
+}
+
+// Component 依赖图组件，实现 quickgo.Component 接口
+// Init 时设置全局依赖图，框架创建的 gRPC 客户端调用会记录到该依赖图
+type Component struct {
+	name     string
+	enabled  bool
+	graph    *Graph
+	path     string
+	format   Format
+	interval time.Duration
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewComponent 创建依赖图组件
+func NewComponent(config *Config) (*Component, error) {
+	if config == nil {
+		return nil, errors.New("config is nil")
+	}
+	if config.Enabled && config.Service == "" {
+		return nil, errors.New("dependency map service name is required")
+	}
+
+	name := config.Name
+	if name == "" {
+		name = "depmap"
+	}
+
+	format := config.Format
+	if format == "" {
+		format = FormatJSON
+	}
+	if format != FormatJSON && format != FormatDOT {
+		return nil, fmt.Errorf("unsupported dependency map format: %s", format)
+	}
+
+	interval := time.Minute
+	if config.ExportInterval != "" {
+		d, err := time.ParseDuration(config.ExportInterval)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ExportInterval %s: %w", config.ExportInterval, err)
+		}
+		if d > 0 {
+			interval = d
+		}
+	}
+
+	return &Component{
+		name:     name,
+		enabled:  config.Enabled,
+		graph:    New(config.Service),
+		path:     config.ExportPath,
+		format:   format,
+		interval: interval,
+	}, nil
+}
+
+// Graph 返回组件的依赖图
+func (c *Component) Graph() *Graph {
+	return c.graph
+}
+
+// Export 将当前快照写入导出文件（先写临时文件再重命名，避免读到不完整内容）
+func (c *Component) Export() error {
+	if c.path == "" {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := c.graph.Snapshot().Encode(&buf, c.format); err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(c.path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(c.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create dependency map file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write dependency map file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write dependency map file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to write dependency map file: %w", err)
+	}
+	return nil
+}
+
+// ==================== Component 接口实现 ====================
+
+// Name 返回组件名称
+func (c *Component) Name() string {
+	return c.name
+}
+
+// IsEnabled 是否启用
+func (c *Component) IsEnabled() bool {
+	return c.enabled
+}
+
+// Init 设置全局依赖图
+func (c *Component) Init(ctx context.Context) error {
+	SetGlobal(c.graph)
+	logger.Info(ctx, "Dependency map enabled: service=%s", c.graph.Service())
+	return nil
+}
+
+// Start 启动定时导出
+func (c *Component) Start(ctx context.Context) error {
+	if c.path == "" {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancel != nil {
+		return errors.New("dependency map already started")
+	}
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.done = make(chan struct{})
+	go c.exportLoop(loopCtx, c.done)
+	logger.Info(ctx, "Dependency map export started: path=%s, format=%s, interval=%v", c.path, c.format, c.interval)
+	return nil
+}
+
+// Stop 停止定时导出并导出最终快照，取消全局依赖图
+func (c *Component) Stop(ctx context.Context) error {
+	c.mu.Lock()
+	cancel, done := c.cancel, c.done
+	c.cancel, c.done = nil, nil
+	c.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+	if Global() == c.graph {
+		SetGlobal(nil)
+	}
+	return c.Export()
+}
+
+func (c *Component) exportLoop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Export(); err != nil {
+				logger.Error(ctx, "Failed to export dependency map: path=%s, error=%v", c.path, err)
+			}
+		}
+	}
+}
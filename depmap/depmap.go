@@ -0,0 +1,293 @@
+// Package depmap 根据运行时的 gRPC 客户端调用数据聚合服务依赖关系
+// 每个服务记录自己调用了哪些下游服务/方法，导出为 JSON 或 DOT，多个服务的快照合并后即得到完整的服务依赖图
+package depmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Format 导出格式
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatDOT  Format = "dot"
+)
+
+// Edge 一条依赖边：Caller 调用 Target 的 Method
+type Edge struct {
+	// 调用方服务
+	Caller string `json:"caller"`
+	// 被调用方服务
+	Target string `json:"target"`
+	// 完整方法名（如 /user.UserService/GetUser）
+	Method string `json:"method"`
+	// 累计调用次数
+	Calls uint64 `json:"calls"`
+	// 累计失败次数
+	Errors uint64 `json:"errors"`
+	// 平均耗时（毫秒）
+	AvgLatencyMs float64 `json:"avgLatencyMs"`
+	// 最大耗时（毫秒）
+	MaxLatencyMs float64 `json:"maxLatencyMs"`
+	// 首次 / 最近一次调用时间
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// Snapshot 依赖图快照
+type Snapshot struct {
+	// 快照生成时间
+	GeneratedAt time.Time `json:"generatedAt"`
+	// 调用方服务（合并多个服务的快照后为空）
+	Service string `json:"service,omitempty"`
+	// 依赖边（按 Caller、Target、Method 排序）
+	Edges []Edge `json:"edges"`
+}
+
+type edgeKey struct {
+	target string
+	method string
+}
+
+type edgeStats struct {
+	calls      uint64
+	errors     uint64
+	totalNanos int64
+	maxNanos   int64
+	firstSeen  time.Time
+	lastSeen   time.Time
+}
+
+// Graph 单个服务的下游依赖统计（并发安全）
+type Graph struct {
+	service string
+	mu      sync.Mutex
+	edges   map[edgeKey]*edgeStats
+}
+
+// New 创建依赖图，service 为当前服务名称（调用方）
+func New(service string) *Graph {
+	return &Graph{service: service, edges: make(map[edgeKey]*edgeStats)}
+}
+
+// Service 返回当前服务名称
+func (g *Graph) Service() string {
+	return g.service
+}
+
+// Record 记录一次对下游服务的调用
+func (g *Graph) Record(target, method string, duration time.Duration, err error) {
+	if target == "" {
+		target = "unknown"
+	}
+	now := time.Now()
+	key := edgeKey{target: target, method: method}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	stats, ok := g.edges[key]
+	if !ok {
+		stats = &edgeStats{firstSeen: now}
+		g.edges[key] = stats
+	}
+	stats.calls++
+	if err != nil {
+		stats.errors++
+	}
+	stats.totalNanos += int64(duration)
+	stats.maxNanos = max(stats.maxNanos, int64(duration))
+	stats.lastSeen = now
+}
+
+// Reset 清空统计
+func (g *Graph) Reset() {
+	g.mu.Lock()
+	g.edges = make(map[edgeKey]*edgeStats)
+	g.mu.Unlock()
+}
+
+// Snapshot 返回当前依赖图快照
+func (g *Graph) Snapshot() Snapshot {
+	g.mu.Lock()
+	edges := make([]Edge, 0, len(g.edges))
+	for key, stats := range g.edges {
+		edge := Edge{
+			Caller:       g.service,
+			Target:       key.target,
+			Method:       key.method,
+			Calls:        stats.calls,
+			Errors:       stats.errors,
+			MaxLatencyMs: float64(stats.maxNanos) / 1e6,
+			FirstSeen:    stats.firstSeen,
+			LastSeen:     stats.lastSeen,
+		}
+		if stats.calls > 0 {
+			edge.AvgLatencyMs = float64(stats.totalNanos) / float64(stats.calls) / 1e6
+		}
+		edges = append(edges, edge)
+	}
+	g.mu.Unlock()
+
+	sortEdges(edges)
+	return Snapshot{GeneratedAt: time.Now(), Service: g.service, Edges: edges}
+}
+
+// Merge 合并多个服务的快照，相同的 Caller/Target/Method 累加调用次数
+func Merge(snapshots ...Snapshot) Snapshot {
+	type key struct{ caller, target, method string }
+	merged := make(map[key]*Edge)
+	for _, snapshot := range snapshots {
+		for _, edge := range snapshot.Edges {
+			k := key{edge.Caller, edge.Target, edge.Method}
+			existing, ok := merged[k]
+			if !ok {
+				e := edge
+				merged[k] = &e
+				continue
+			}
+			total := existing.AvgLatencyMs*float64(existing.Calls) + edge.AvgLatencyMs*float64(edge.Calls)
+			existing.Calls += edge.Calls
+			existing.Errors += edge.Errors
+			if existing.Calls > 0 {
+				existing.AvgLatencyMs = total / float64(existing.Calls)
+			}
+			existing.MaxLatencyMs = max(existing.MaxLatencyMs, edge.MaxLatencyMs)
+			if edge.FirstSeen.Before(existing.FirstSeen) {
+				existing.FirstSeen = edge.FirstSeen
+			}
+			if edge.LastSeen.After(existing.LastSeen) {
+				existing.LastSeen = edge.LastSeen
+			}
+		}
+	}
+
+	edges := make([]Edge, 0, len(merged))
+	for _, edge := range merged {
+		edges = append(edges, *edge)
+	}
+	sortEdges(edges)
+	return Snapshot{GeneratedAt: time.Now(), Edges: edges}
+}
+
+func sortEdges(edges []Edge) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Caller != edges[j].Caller {
+			return edges[i].Caller < edges[j].Caller
+		}
+		if edges[i].Target != edges[j].Target {
+			return edges[i].Target < edges[j].Target
+		}
+		return edges[i].Method < edges[j].Method
+	})
+}
+
+// JSON 以 JSON 格式输出快照
+func (s Snapshot) JSON() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// DOT 以 Graphviz DOT 格式输出服务级依赖图
+// 同一对服务之间的多个方法合并为一条边，标签为方法数与调用次数，有失败调用时边标红
+func (s Snapshot) DOT() string {
+	type pair struct{ caller, target string }
+	type pairStats struct {
+		methods int
+		calls   uint64
+		errors  uint64
+	}
+	pairs := make(map[pair]*pairStats)
+	order := make([]pair, 0)
+	for _, edge := range s.Edges {
+		p := pair{edge.Caller, edge.Target}
+		stats, ok := pairs[p]
+		if !ok {
+			stats = &pairStats{}
+			pairs[p] = stats
+			order = append(order, p)
+		}
+		stats.methods++
+		stats.calls += edge.Calls
+		stats.errors += edge.Errors
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, p := range order {
+		stats := pairs[p]
+		fmt.Fprintf(&b, "  %q -> %q [label=%q", p.caller, p.target,
+			fmt.Sprintf("%d methods, %d calls", stats.methods, stats.calls))
+		if stats.errors > 0 {
+			b.WriteString(", color=red")
+		}
+		b.WriteString("];\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Encode 按指定格式输出快照
+func (s Snapshot) Encode(w io.Writer, format Format) error {
+	switch format {
+	case FormatDOT:
+		_, err := io.WriteString(w, s.DOT())
+		return err
+	case FormatJSON, "":
+		data, err := s.JSON()
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	default:
+		return fmt.Errorf("unsupported dependency map format: %s", format)
+	}
+}
+
+// Handler 返回输出依赖图的 HTTP handler，通过 ?format=dot 输出 DOT，默认 JSON
+// g 为 nil 时使用全局依赖图
+func Handler(g *Graph) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		graph := g
+		if graph == nil {
+			graph = Global()
+		}
+		if graph == nil {
+			http.Error(w, "dependency map is not enabled", http.StatusServiceUnavailable)
+			return
+		}
+		format := Format(r.URL.Query().Get("format"))
+		switch format {
+		case FormatDOT:
+			w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		case FormatJSON, "":
+			w.Header().Set("Content-Type", "application/json")
+		default:
+			http.Error(w, fmt.Sprintf("unsupported format: %s", format), http.StatusBadRequest)
+			return
+		}
+		_ = graph.Snapshot().Encode(w, format)
+	})
+}
+
+var global atomic.Pointer[Graph]
+
+// SetGlobal 设置全局依赖图（客户端拦截器默认记录到全局依赖图），传入 nil 关闭记录
+func SetGlobal(g *Graph) {
+	global.Store(g)
+}
+
+// Global 返回全局依赖图，未启用时为 nil
+func Global() *Graph {
+	return global.Load()
+}
@@ -0,0 +1,170 @@
+package depmap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+func TestUnaryClientInterceptorRecordsToGlobalGraph(t *testing.T) {
+	interceptor := UnaryClientInterceptor(nil, "user-service")
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		if method == "/user.UserService/Fail" {
+			return errors.New("unavailable")
+		}
+		return nil
+	}
+
+	// 未启用时不记录
+	if err := interceptor(context.Background(), "/user.UserService/GetUser", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	graph := New("gateway")
+	SetGlobal(graph)
+	defer SetGlobal(nil)
+	for i := 0; i < 3; i++ {
+		_ = interceptor(context.Background(), "/user.UserService/GetUser", nil, nil, nil, invoker)
+	}
+	if err := interceptor(context.Background(), "/user.UserService/Fail", nil, nil, nil, invoker); err == nil {
+		t.Fatal("expected invoker error to be returned")
+	}
+
+	snapshot := graph.Snapshot()
+	if snapshot.Service != "gateway" || len(snapshot.Edges) != 2 {
+		t.Fatalf("unexpected snapshot: %+v", snapshot)
+	}
+	fail, get := snapshot.Edges[0], snapshot.Edges[1]
+	if get.Caller != "gateway" || get.Target != "user-service" || get.Method != "/user.UserService/GetUser" || get.Calls != 3 || get.Errors != 0 {
+		t.Fatalf("unexpected edge: %+v", get)
+	}
+	if fail.Calls != 1 || fail.Errors != 1 {
+		t.Fatalf("unexpected failed edge: %+v", fail)
+	}
+}
+
+func TestMergeAndDOT(t *testing.T) {
+	gateway := New("gateway")
+	gateway.Record("user-service", "/user.UserService/GetUser", 10*time.Millisecond, nil)
+	gateway.Record("order-service", "/order.OrderService/List", 20*time.Millisecond, errors.New("boom"))
+	user := New("user-service")
+	user.Record("auth-service", "/auth.AuthService/Verify", 2*time.Millisecond, nil)
+	again := New("gateway")
+	again.Record("user-service", "/user.UserService/GetUser", 30*time.Millisecond, nil)
+
+	merged := Merge(gateway.Snapshot(), user.Snapshot(), again.Snapshot())
+	if merged.Service != "" || len(merged.Edges) != 3 {
+		t.Fatalf("unexpected merged snapshot: %+v", merged)
+	}
+	edge := merged.Edges[1]
+	if edge.Target != "user-service" || edge.Calls != 2 || edge.AvgLatencyMs != 20 || edge.MaxLatencyMs != 30 {
+		t.Fatalf("unexpected merged edge: %+v", edge)
+	}
+
+	dot := merged.DOT()
+	for _, want := range []string{
+		`"gateway" -> "order-service" [label="1 methods, 1 calls", color=red];`,
+		`"gateway" -> "user-service" [label="1 methods, 2 calls"];`,
+		`"user-service" -> "auth-service"`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Fatalf("expected DOT to contain %s, got:\n%s", want, dot)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	graph := New("gateway")
+	graph.Record("user-service", "/user.UserService/GetUser", time.Millisecond, nil)
+	handler := Handler(graph)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/deps", nil))
+	var snapshot Snapshot
+	if err := json.Unmarshal(rec.Body.Bytes(), &snapshot); err != nil || len(snapshot.Edges) != 1 {
+		t.Fatalf("unexpected JSON response: %v, %s", err, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/deps?format=dot", nil))
+	if !strings.HasPrefix(rec.Body.String(), "digraph dependencies {") {
+		t.Fatalf("unexpected DOT response: %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/deps?format=xml", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unsupported format, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	Handler(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/deps", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without global graph, got %d", rec.Code)
+	}
+}
+
+func TestComponentExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deps.dot")
+	component, err := NewComponent(&Config{
+		Enabled:        true,
+		Service:        "gateway",
+		ExportPath:     path,
+		Format:         FormatDOT,
+		ExportInterval: "10ms",
+	})
+	if err != nil {
+		t.Fatalf("NewComponent failed: %v", err)
+	}
+	ctx := context.Background()
+	if err := component.Init(ctx); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if Global() != component.Graph() {
+		t.Fatal("expected Init to set global graph")
+	}
+	if err := component.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	Global().Record("user-service", "/user.UserService/GetUser", time.Millisecond, nil)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		data, _ := os.ReadFile(path)
+		if strings.Contains(string(data), `"gateway" -> "user-service"`) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected periodic export, got %q", data)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := component.Stop(ctx); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if Global() != nil {
+		t.Fatal("expected Stop to clear global graph")
+	}
+}
+
+func TestNewComponentValidation(t *testing.T) {
+	if _, err := NewComponent(&Config{Enabled: true}); err == nil {
+		t.Fatal("expected missing service to be rejected")
+	}
+	if _, err := NewComponent(&Config{Enabled: true, Service: "a", Format: "svg"}); err == nil {
+		t.Fatal("expected unsupported format to be rejected")
+	}
+	if _, err := NewComponent(&Config{Enabled: true, Service: "a", ExportInterval: "often"}); err == nil {
+		t.Fatal("expected invalid interval to be rejected")
+	}
+}
@@ -0,0 +1,54 @@
+package depmap
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// UnaryClientInterceptor 记录对 target 服务的一元调用
+// g 为 nil 时在每次调用时使用全局依赖图，未启用时不做任何记录
+func UnaryClientInterceptor(g *Graph, target string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		graph := g
+		if graph == nil {
+			graph = Global()
+		}
+		if graph == nil {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		graph.Record(target, method, time.Since(start), err)
+		return err
+	}
+}
+
+// StreamClientInterceptor 记录对 target 服务的流式调用
+// 流式调用在建立时记录一次，耗时为建立流的耗时
+func StreamClientInterceptor(g *Graph, target string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		graph := g
+		if graph == nil {
+			graph = Global()
+		}
+		if graph == nil {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		graph.Record(target, method, time.Since(start), err)
+		return stream, err
+	}
+}
+
+// DialOptions 返回记录对 target 服务调用的 DialOption（使用全局依赖图）
+func DialOptions(target string) []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(UnaryClientInterceptor(nil, target)),
+		grpc.WithChainStreamInterceptor(StreamClientInterceptor(nil, target)),
+	}
+}
@@ -0,0 +1,6 @@
+package quickgo
+
+import "github.com/team-dandelion/quickgo/types"
+
+// Duration 配置中的时长类型（如 "5s"、"1m30s"），兼容原有的字符串配置值
+type Duration = types.Duration
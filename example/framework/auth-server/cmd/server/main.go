@@ -1,14 +1,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
 
 	"github.com/team-dandelion/quickgo"
+	"github.com/team-dandelion/quickgo/audit"
 	"github.com/team-dandelion/quickgo/db/gorm"
+	"github.com/team-dandelion/quickgo/db/migrate"
 	"github.com/team-dandelion/quickgo/db/redis"
 	gen "github.com/team-dandelion/quickgo/example/framework/auth-server/api/proto/gen"
 	"github.com/team-dandelion/quickgo/example/framework/auth-server/internal/handler"
+	"github.com/team-dandelion/quickgo/example/framework/auth-server/internal/migrations"
 	"github.com/team-dandelion/quickgo/example/framework/auth-server/internal/service"
+	"github.com/team-dandelion/quickgo/grpc"
 	"github.com/team-dandelion/quickgo/tracing"
 
 	rpc "google.golang.org/grpc"
@@ -30,12 +36,28 @@ func main() {
 	}{}
 	quickgo.LoadCustomConfig(&config)
 
+	// 审计拦截器：提取网关透传的客户端信息，供登录/刷新令牌等安全事件使用
+	config.GrpcServerConfig.Interceptors = grpc.NewInterceptorChain()
+	if err := config.GrpcServerConfig.Interceptors.Register(grpc.InterceptorSpec{
+		Name:   "audit",
+		Class:  grpc.ClassAuth,
+		Unary:  audit.UnaryServerInterceptor(nil),
+		Stream: audit.StreamServerInterceptor(nil),
+	}); err != nil {
+		panic(err)
+	}
+
+	// 命令行迁移模式：server migrate <up|down|status> [参数]
+	migrateMode := len(os.Args) > 1 && os.Args[1] == "migrate"
+
 	// 创建框架实例，使用 Option 模式显式指定需要初始化的组件
 	app, err := quickgo.NewFramework(
 		quickgo.ConfigOptionWithApp(config.AppConfig),
 		quickgo.ConfigOptionWithLogger(config.LoggerConfig),
 		quickgo.ConfigOptionWithGrpcServer(&config.GrpcServerConfig),
 		quickgo.ConfigOptionWithGorm(&config.GormConfig),
+		// 服务启动时自动执行待执行迁移（多实例同时启动时只有一个实例执行）
+		quickgo.ConfigOptionWithMigrate(&migrate.Config{AutoRun: !migrateMode}),
 		quickgo.ConfigOptionWithRedis(&config.RedisConfig),
 		quickgo.ConfigOptionWithTracing(&config.TracingConfig),
 		// 如果需要其他组件，可以继续添加：
@@ -51,6 +73,16 @@ func main() {
 		panic(err)
 	}
 
+	if migrateMode {
+		err := app.RunMigrateCommand(context.Background(), migrations.Database, os.Args[2:], os.Stdout)
+		_ = app.Stop()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// 注册 gRPC 服务
 	if app.GrpcServer() != nil {
 		// 获取数据库连接（如果配置了，必须成功获取，否则服务无法启动）
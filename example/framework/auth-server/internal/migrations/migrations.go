@@ -0,0 +1,52 @@
+// Package migrations 认证服务数据库迁移
+// 每个版本一旦发布不可修改，结构变更请新增版本
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/team-dandelion/quickgo/db/migrate"
+
+	"gorm.io/gorm"
+)
+
+// Database 迁移所属的数据库名称（对应 gorm 配置中的 name）
+const Database = "go-admin"
+
+// usersV1 users 表初始结构快照（与 model.UserModel 解耦，模型后续变更不影响已发布的迁移）
+type usersV1 struct {
+	ID        uint   `gorm:"primarykey"`
+	UserID    string `gorm:"uniqueIndex;not null;size:64"`
+	Username  string `gorm:"uniqueIndex;not null;size:64"`
+	Password  string `gorm:"not null;size:255"`
+	Email     string `gorm:"size:128"`
+	Nickname  string `gorm:"size:64"`
+	Avatar    string `gorm:"size:255"`
+	Roles     string `gorm:"size:255"`
+	Status    int    `gorm:"default:1"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (usersV1) TableName() string {
+	return "users"
+}
+
+func init() {
+	migrate.Register(Database,
+		&migrate.Migration{
+			Version: 20240601000000,
+			Name:    "create_users",
+			// 基线版本：引入迁移前已通过 AutoMigrate 建表的库中 users 表已存在，
+			// 使用 AutoMigrate 补齐缺失的列与索引而不是重复建表
+			Up: func(ctx context.Context, tx *gorm.DB) error {
+				return tx.Migrator().AutoMigrate(&usersV1{})
+			},
+			Down: func(ctx context.Context, tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&usersV1{})
+			},
+		},
+	)
+}
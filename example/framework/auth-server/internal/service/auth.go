@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/team-dandelion/quickgo/audit"
 	"github.com/team-dandelion/quickgo/db/redis"
 	gen "github.com/team-dandelion/quickgo/example/framework/auth-server/api/proto/gen"
 	"github.com/team-dandelion/quickgo/example/framework/auth-server/internal/model"
@@ -80,15 +81,8 @@ func NewAuthService(db *gormDB.DB, redisClient *redis.Client) *AuthService {
 		tokens: make(map[string]*TokenInfo),
 	}
 
-	// 如果配置了数据库，初始化表结构并插入初始数据
+	// 如果配置了数据库，插入初始数据（表结构由 internal/migrations 在框架 Init 时迁移）
 	if db != nil {
-		// 自动迁移表结构
-		if err := db.AutoMigrate(&model.UserModel{}); err != nil {
-			logger.Error(context.Background(), "Failed to migrate user table: %v", err)
-		} else {
-			logger.Info(context.Background(), "User table migrated successfully")
-		}
-
 		// 插入初始用户数据（如果不存在）
 		service.initDefaultUsers(context.Background(), db)
 		logger.Info(context.Background(), "AuthService initialized with database")
@@ -153,8 +147,6 @@ func (s *AuthService) initDefaultUsers(ctx context.Context, db *gormDB.DB) {
 
 // Login 用户登录
 func (s *AuthService) Login(ctx context.Context, username, password string) (*gen.LoginResponse, error) {
-	logger.Info(ctx, "Login attempt: username=%s", username)
-
 	var userModel *model.UserModel
 	var err error
 
@@ -163,7 +155,7 @@ func (s *AuthService) Login(ctx context.Context, username, password string) (*ge
 		userModel = &model.UserModel{}
 		if err := s.db.WithContext(ctx).Where("username = ? AND status = ?", username, 1).First(userModel).Error; err != nil {
 			if err == gormDB.ErrRecordNotFound {
-				logger.Warn(ctx, "User not found: username=%s", username)
+				audit.LoginFailed(ctx, username, "user not found")
 				resp := newLoginResponse()
 				resp.CommonResp.Code = 401
 				resp.CommonResp.Msg = "用户名或密码错误"
@@ -178,7 +170,7 @@ func (s *AuthService) Login(ctx context.Context, username, password string) (*ge
 
 		// 验证密码（实际应该使用 bcrypt 等哈希比较）
 		if userModel.Password != password {
-			logger.Warn(ctx, "Invalid password: username=%s", username)
+			audit.LoginFailed(ctx, username, "invalid password")
 			resp := newLoginResponse()
 			resp.CommonResp.Code = 401
 			resp.CommonResp.Msg = "用户名或密码错误"
@@ -188,12 +180,14 @@ func (s *AuthService) Login(ctx context.Context, username, password string) (*ge
 		// 使用内存存储（向后兼容）
 		user, exists := s.users[username]
 		if !exists {
+			audit.LoginFailed(ctx, username, "user not found")
 			resp := newLoginResponse()
 			resp.CommonResp.Code = 401
 			resp.CommonResp.Msg = "用户名或密码错误"
 			return resp, nil
 		}
 		if user.Password != password {
+			audit.LoginFailed(ctx, username, "invalid password")
 			resp := newLoginResponse()
 			resp.CommonResp.Code = 401
 			resp.CommonResp.Msg = "用户名或密码错误"
@@ -246,7 +240,7 @@ func (s *AuthService) Login(ctx context.Context, username, password string) (*ge
 		s.tokens[token] = tokenInfo
 	}
 
-	logger.Info(ctx, "Login success: username=%s, user_id=%s", username, userModel.UserID)
+	audit.LoginSucceeded(ctx, userModel.UserID, username)
 
 	resp := newLoginResponse()
 	resp.CommonResp.Code = grpcep.SuccessCode
@@ -368,8 +362,6 @@ func (s *AuthService) VerifyToken(ctx context.Context, token string) (*gen.Verif
 
 // RefreshToken 刷新令牌
 func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*gen.RefreshTokenResponse, error) {
-	logger.Info(ctx, "Refreshing token")
-
 	var tokenInfo *TokenInfo
 	var token string
 	var err error
@@ -379,7 +371,7 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*g
 		// 从 Redis 获取 refresh token 对应的 access token
 		token, err = s.getTokenByRefreshTokenFromRedis(ctx, refreshToken)
 		if err != nil {
-			logger.Warn(ctx, "Refresh token not found in Redis: %v", err)
+			audit.TokenRefreshFailed(ctx, "", "refresh token not found")
 			resp := newRefreshTokenResponse()
 			resp.CommonResp.Code = 401
 			resp.CommonResp.Msg = "刷新令牌无效"
@@ -388,7 +380,7 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*g
 		// 获取 token 信息
 		tokenInfo, err = s.getTokenFromRedis(ctx, token)
 		if err != nil {
-			logger.Warn(ctx, "Token not found in Redis: %v", err)
+			audit.TokenRefreshFailed(ctx, "", "access token not found")
 			resp := newRefreshTokenResponse()
 			resp.CommonResp.Code = 401
 			resp.CommonResp.Msg = "刷新令牌无效"
@@ -406,6 +398,7 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*g
 			}
 		}
 		if !found {
+			audit.TokenRefreshFailed(ctx, "", "refresh token not found")
 			resp := newRefreshTokenResponse()
 			resp.CommonResp.Code = 401
 			resp.CommonResp.Msg = "刷新令牌无效"
@@ -489,6 +482,8 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*g
 		s.tokens[newToken] = newTokenInfo
 	}
 
+	audit.TokenRefreshed(ctx, userModel.UserID)
+
 	resp := newRefreshTokenResponse()
 	resp.CommonResp.Code = 200
 	resp.CommonResp.Msg = "刷新成功"
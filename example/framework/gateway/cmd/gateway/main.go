@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"github.com/team-dandelion/quickgo"
+	"github.com/team-dandelion/quickgo/audit"
 	"github.com/team-dandelion/quickgo/db/mongodb"
 	"github.com/team-dandelion/quickgo/db/redis"
 	"github.com/team-dandelion/quickgo/example/framework/gateway/internal/handler"
@@ -18,8 +19,8 @@ type grpcClientManagerAdapter struct {
 	manager *quickgo.GrpcClientManager
 }
 
-func (a *grpcClientManagerAdapter) GetConn(ctx context.Context, serviceName string) (*grpc.ClientConn, error) {
-	return a.manager.GetConn(ctx, serviceName)
+func (a *grpcClientManagerAdapter) Conn(ctx context.Context, serviceName string) (grpc.ClientConnInterface, error) {
+	return a.manager.Conn(ctx, serviceName)
 }
 
 func main() {
@@ -95,8 +96,8 @@ func main() {
 				})
 			})
 
-			// API 路由组
-			api := fiberApp.Group("/api/v1")
+			// API 路由组（审计中间件：透传客户端 IP / User-Agent 给后端服务，记录 401/403 安全事件）
+			api := fiberApp.Group("/api/v1", audit.FiberMiddleware(nil))
 			{
 				// 认证相关路由
 				auth := api.Group("/auth")
@@ -4,7 +4,9 @@ import (
 	"context"
 	"time"
 
+	"github.com/team-dandelion/quickgo/cache"
 	"github.com/team-dandelion/quickgo/db/redis"
+	gen "github.com/team-dandelion/quickgo/example/framework/auth-server/api/proto/gen"
 	"github.com/team-dandelion/quickgo/example/framework/gateway/internal/service"
 	"github.com/team-dandelion/quickgo/grpcep"
 	"github.com/team-dandelion/quickgo/logger"
@@ -13,28 +15,39 @@ import (
 	"google.golang.org/grpc"
 )
 
+// userInfoCacheTTL 用户信息缓存时间
+const userInfoCacheTTL = 5 * time.Minute
+
 // AuthHandler HTTP 认证处理器
 type AuthHandler struct {
 	baseHandler *grpcep.BaseHandler
 	authClient  *service.AuthClient
 	clientMgr   ClientManager
-	cacheRedis  *redis.Client // Redis 缓存客户端（可选）
+	cache       *cache.Cache // Redis 缓存（可选）
 }
 
 // ClientManager gRPC 客户端管理器接口
 type ClientManager interface {
-	GetConn(ctx context.Context, serviceName string) (*grpc.ClientConn, error)
+	Conn(ctx context.Context, serviceName string) (grpc.ClientConnInterface, error)
 }
 
 // NewAuthHandler 创建认证处理器
 // clientMgr: gRPC 客户端管理器
 // cacheRedis: Redis 缓存客户端（可选，如果为 nil 则不使用缓存）
 func NewAuthHandler(clientMgr ClientManager, cacheRedis *redis.Client) *AuthHandler {
-	return &AuthHandler{
+	h := &AuthHandler{
 		baseHandler: &grpcep.BaseHandler{},
 		clientMgr:   clientMgr,
-		cacheRedis:  cacheRedis,
 	}
+	if cacheRedis != nil {
+		authCache, err := cache.NewFromClient(cacheRedis, &cache.Config{Prefix: "gateway:auth:"})
+		if err != nil {
+			logger.Warn(context.Background(), "Failed to create auth cache, running without cache: %v", err)
+		} else {
+			h.cache = authCache
+		}
+	}
+	return h
 }
 
 // getAuthClient 获取认证客户端
@@ -44,7 +57,7 @@ func (h *AuthHandler) getAuthClient(ctx context.Context) (*service.AuthClient, e
 	}
 
 	// 获取 gRPC 连接
-	conn, err := h.clientMgr.GetConn(ctx, "auth-service")
+	conn, err := h.clientMgr.Conn(ctx, "auth-service")
 	if err != nil {
 		return nil, err
 	}
@@ -54,35 +67,6 @@ func (h *AuthHandler) getAuthClient(ctx context.Context) (*service.AuthClient, e
 	return h.authClient, nil
 }
 
-// getCacheKey 生成缓存键
-func (h *AuthHandler) getCacheKey(key string) string {
-	return "gateway:auth:" + key
-}
-
-// getFromCache 从缓存获取数据
-func (h *AuthHandler) getFromCache(ctx context.Context, key string) (string, error) {
-	if h.cacheRedis == nil {
-		return "", nil // 未配置 Redis，返回空
-	}
-
-	cacheKey := h.getCacheKey(key)
-	val, err := h.cacheRedis.GetClient().Get(ctx, cacheKey).Result()
-	if err != nil {
-		return "", err
-	}
-	return val, nil
-}
-
-// setCache 设置缓存
-func (h *AuthHandler) setCache(ctx context.Context, key string, value string, ttl time.Duration) error {
-	if h.cacheRedis == nil {
-		return nil // 未配置 Redis，忽略
-	}
-
-	cacheKey := h.getCacheKey(key)
-	return h.cacheRedis.GetClient().Set(ctx, cacheKey, value, ttl).Err()
-}
-
 // LoginRequest 登录请求参数
 type LoginRequest struct {
 	Username string `json:"username" validate:"required"`
@@ -211,8 +195,14 @@ func (h *AuthHandler) GetUserInfo(c *fiber.Ctx) error {
 			return nil, err
 		}
 
-		// 调用 gRPC 服务
-		return authClient.GetUserInfo(ctx, req.UserId)
+		if h.cache == nil {
+			return authClient.GetUserInfo(ctx, req.UserId)
+		}
+
+		// 用户信息读多写少，使用缓存（未命中时回源 gRPC 服务，并发请求合并为一次）
+		return cache.Load(ctx, h.cache, "user:"+req.UserId, userInfoCacheTTL, func(ctx context.Context) (*gen.GetUserInfoResponse, error) {
+			return authClient.GetUserInfo(ctx, req.UserId)
+		})
 	}
 
 	// 使用 grpcep 的 GRPCCall 方法
@@ -16,7 +16,7 @@ type AuthClient struct {
 }
 
 // NewAuthClient 创建认证客户端
-func NewAuthClient(conn *grpc.ClientConn) *AuthClient {
+func NewAuthClient(conn grpc.ClientConnInterface) *AuthClient {
 	return &AuthClient{
 		client: gen.NewAuthServiceClient(conn),
 	}
@@ -0,0 +1,357 @@
+// Package featureflag 提供功能开关
+//
+// 开关在代码中以 Define 声明默认值，由 Provider（配置文件、etcd、Unleash）下发规则覆盖；
+// 每次请求按规则评估：指定用户、指定租户、按用户（或租户）稳定哈希的百分比灰度。
+// 用户默认取自 authz 身份，租户取自 tenant 包，也可用 WithTarget 显式指定：
+//
+//	flags, _ := featureflag.New(featureflag.NewFileProvider("flags.yaml"), &featureflag.Config{RefreshInterval: types.Duration(30 * time.Second)})
+//	flags.Define(featureflag.Flag{Name: "new-checkout", Description: "新版结算页"})
+//	featureflag.SetDefault(flags)
+//	if featureflag.Enabled(ctx, "new-checkout") { ... }
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/team-dandelion/quickgo/authz"
+	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/tenant"
+	"github.com/team-dandelion/quickgo/types"
+)
+
+// Flag 功能开关定义
+type Flag struct {
+	// 开关名称 示例：new-checkout
+	Name string `json:"name" yaml:"name" toml:"name"`
+	// 描述
+	Description string `json:"description,omitempty" yaml:"description" toml:"description"`
+	// 没有规则命中时的取值
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled"`
+	// 定向规则，按顺序匹配，首个命中的规则决定取值
+	Rules []Rule `json:"rules,omitempty" yaml:"rules" toml:"rules"`
+}
+
+// Rule 定向规则，配置的条件全部满足时命中（未配置任何条件的规则对所有请求命中）
+type Rule struct {
+	// 用户 ID 列表
+	Users []string `json:"users,omitempty" yaml:"users" toml:"users"`
+	// 租户 ID 列表
+	Tenants []string `json:"tenants,omitempty" yaml:"tenants" toml:"tenants"`
+	// 灰度百分比（0-100），按用户 ID（无用户时按租户 ID）稳定哈希分桶，0 表示不限制
+	Percentage float64 `json:"percentage,omitempty" yaml:"percentage" toml:"percentage"`
+	// 命中时的取值
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled"`
+}
+
+// Config 功能开关配置
+type Config struct {
+	// 定时从 Provider 刷新的间隔 示例：30s（默认 0，不定时刷新；支持 Watch 的 Provider 实时更新）
+	RefreshInterval types.Duration `json:"refreshInterval" yaml:"refreshInterval" toml:"refreshInterval"`
+	// 从 context 解析评估对象，context 中没有 WithTarget 写入的对象时调用（默认读取 authz 身份与 tenant）
+	TargetResolver func(ctx context.Context) Target `json:"-" yaml:"-" toml:"-"`
+}
+
+// Provider 开关规则来源
+type Provider interface {
+	// Load 读取全部开关
+	Load(ctx context.Context) ([]Flag, error)
+}
+
+// Watcher 支持变更通知的 Provider，Watch 阻塞直到 ctx 结束，开关变化时调用 onChange
+type Watcher interface {
+	Watch(ctx context.Context, onChange func()) error
+}
+
+// Target 评估对象
+type Target struct {
+	UserID   string `json:"userId,omitempty"`
+	TenantID string `json:"tenantId,omitempty"`
+}
+
+type targetKey struct{}
+
+// WithTarget 将评估对象存入 context
+func WithTarget(ctx context.Context, target Target) context.Context {
+	return context.WithValue(ctx, targetKey{}, target)
+}
+
+// Evaluation 评估结果
+type Evaluation struct {
+	Flag    string `json:"flag"`
+	Enabled bool   `json:"enabled"`
+	// 结果来源：rule（规则命中）、default（开关默认值）、undefined（开关未定义，返回 false）
+	Reason string `json:"reason"`
+	// 命中的规则序号（Reason 为 rule 时有效）
+	Rule int `json:"rule"`
+}
+
+// 评估结果来源
+const (
+	ReasonRule      = "rule"
+	ReasonDefault   = "default"
+	ReasonUndefined = "undefined"
+)
+
+// State 开关当前状态（运维接口展示）
+type State struct {
+	Flag
+	// 来源：provider（Provider 下发）或 default（代码声明的默认值）
+	Source string `json:"source"`
+}
+
+// Manager 功能开关管理器
+type Manager struct {
+	provider Provider
+	config   Config
+
+	mu       sync.RWMutex
+	defined  map[string]Flag
+	remote   map[string]Flag
+	loadedAt time.Time
+	loadErr  error
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New 创建功能开关管理器并加载开关，配置了 RefreshInterval 或 Provider 支持 Watch 时在后台更新（Close 停止）
+// provider 为 nil 时仅使用 Define 声明的默认值
+func New(provider Provider, config *Config) (*Manager, error) {
+	m := &Manager{provider: provider, defined: make(map[string]Flag), remote: make(map[string]Flag)}
+	if config != nil {
+		m.config = *config
+	}
+	if provider == nil {
+		return m, nil
+	}
+	if err := m.Refresh(context.Background()); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	if interval := m.config.RefreshInterval.Std(); interval > 0 {
+		m.wg.Add(1)
+		go m.refreshLoop(ctx, interval)
+	}
+	if watcher, ok := provider.(Watcher); ok {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			if err := watcher.Watch(ctx, func() { m.refreshLogged(ctx) }); err != nil && ctx.Err() == nil {
+				logger.Error(ctx, "Feature flag watch stopped: %v", err)
+			}
+		}()
+	}
+	return m, nil
+}
+
+// Define 声明开关及其默认值，Provider 下发同名开关时以 Provider 为准
+func (m *Manager) Define(flags ...Flag) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, flag := range flags {
+		m.defined[flag.Name] = flag
+	}
+}
+
+// Refresh 从 Provider 重新加载开关，失败时保留当前开关
+func (m *Manager) Refresh(ctx context.Context) error {
+	if m.provider == nil {
+		return nil
+	}
+	flags, err := m.provider.Load(ctx)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		m.loadErr = err
+		return fmt.Errorf("failed to load feature flags: %w", err)
+	}
+	remote := make(map[string]Flag, len(flags))
+	for _, flag := range flags {
+		if flag.Name == "" {
+			continue
+		}
+		remote[flag.Name] = flag
+	}
+	m.remote, m.loadedAt, m.loadErr = remote, time.Now(), nil
+	return nil
+}
+
+func (m *Manager) refreshLogged(ctx context.Context) {
+	if err := m.Refresh(ctx); err != nil && ctx.Err() == nil {
+		logger.Error(ctx, "Failed to refresh feature flags: %v", err)
+	}
+}
+
+func (m *Manager) refreshLoop(ctx context.Context, interval time.Duration) {
+	defer m.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.refreshLogged(ctx)
+		}
+	}
+}
+
+// Close 停止后台更新
+func (m *Manager) Close() {
+	if m.cancel == nil {
+		return
+	}
+	m.cancel()
+	m.wg.Wait()
+}
+
+// lookup 获取开关，Provider 下发的优先
+func (m *Manager) lookup(name string) (Flag, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if flag, ok := m.remote[name]; ok {
+		return flag, true
+	}
+	flag, ok := m.defined[name]
+	return flag, ok
+}
+
+// Target 解析 context 中的评估对象
+func (m *Manager) Target(ctx context.Context) Target {
+	if ctx == nil {
+		return Target{}
+	}
+	if target, ok := ctx.Value(targetKey{}).(Target); ok {
+		return target
+	}
+	if m.config.TargetResolver != nil {
+		return m.config.TargetResolver(ctx)
+	}
+	var target Target
+	if identity, ok := authz.IdentityFromContext(ctx); ok {
+		target.UserID = identity.Subject
+	}
+	target.TenantID, _ = tenant.FromContext(ctx)
+	return target
+}
+
+// Enabled 判断开关对当前请求是否开启，未定义的开关返回 false
+func (m *Manager) Enabled(ctx context.Context, name string) bool {
+	return m.Evaluate(ctx, name).Enabled
+}
+
+// Evaluate 评估开关对当前请求的取值
+func (m *Manager) Evaluate(ctx context.Context, name string) Evaluation {
+	return m.EvaluateFor(name, m.Target(ctx))
+}
+
+// EvaluateFor 评估开关对指定对象的取值
+func (m *Manager) EvaluateFor(name string, target Target) Evaluation {
+	flag, ok := m.lookup(name)
+	if !ok {
+		return Evaluation{Flag: name, Reason: ReasonUndefined, Rule: -1}
+	}
+	for i, rule := range flag.Rules {
+		if rule.matches(name, target) {
+			return Evaluation{Flag: name, Enabled: rule.Enabled, Reason: ReasonRule, Rule: i}
+		}
+	}
+	return Evaluation{Flag: name, Enabled: flag.Enabled, Reason: ReasonDefault, Rule: -1}
+}
+
+// States 返回全部开关的当前状态（按名称排序）
+func (m *Manager) States() []State {
+	m.mu.RLock()
+	states := make([]State, 0, len(m.defined)+len(m.remote))
+	for name, flag := range m.remote {
+		states = append(states, State{Flag: flag, Source: "provider"})
+		if _, ok := m.defined[name]; ok && flag.Description == "" {
+			states[len(states)-1].Description = m.defined[name].Description
+		}
+	}
+	for name, flag := range m.defined {
+		if _, ok := m.remote[name]; !ok {
+			states = append(states, State{Flag: flag, Source: "default"})
+		}
+	}
+	m.mu.RUnlock()
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	return states
+}
+
+// LastRefresh 返回最近一次成功加载的时间与最近一次加载错误
+func (m *Manager) LastRefresh() (time.Time, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.loadedAt, m.loadErr
+}
+
+// matches 判断规则是否命中
+func (r Rule) matches(flag string, target Target) bool {
+	if len(r.Users) > 0 && (target.UserID == "" || !slices.Contains(r.Users, target.UserID)) {
+		return false
+	}
+	if len(r.Tenants) > 0 && (target.TenantID == "" || !slices.Contains(r.Tenants, target.TenantID)) {
+		return false
+	}
+	if r.Percentage > 0 && r.Percentage < 100 {
+		key := target.UserID
+		if key == "" {
+			key = target.TenantID
+		}
+		// 没有分桶依据时无法稳定灰度，不命中
+		if key == "" || bucket(flag, key) >= r.Percentage {
+			return false
+		}
+	}
+	return true
+}
+
+// bucket 按开关名称与对象稳定哈希到 [0, 100)
+func bucket(flag, key string) float64 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(flag))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(key))
+	return float64(h.Sum32()%10000) / 100
+}
+
+var (
+	defaultMu      sync.RWMutex
+	defaultManager *Manager
+)
+
+// ErrNotConfigured 未设置全局功能开关管理器
+var ErrNotConfigured = errors.New("featureflag: default manager is not configured")
+
+// SetDefault 设置全局功能开关管理器（Enabled、AdminHandler 使用）
+func SetDefault(m *Manager) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultManager = m
+}
+
+// Default 获取全局功能开关管理器，未设置时返回 nil
+func Default() *Manager {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultManager
+}
+
+// Enabled 使用全局管理器判断开关是否开启，未设置全局管理器时返回 false
+func Enabled(ctx context.Context, name string) bool {
+	m := Default()
+	if m == nil {
+		return false
+	}
+	return m.Enabled(ctx, name)
+}
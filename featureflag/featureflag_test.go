@@ -0,0 +1,202 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/team-dandelion/quickgo/authz"
+	"github.com/team-dandelion/quickgo/tenant"
+	"github.com/team-dandelion/quickgo/types"
+)
+
+func TestEvaluateRules(t *testing.T) {
+	m, err := New(StaticProvider(Flag{
+		Name: "new-checkout",
+		Rules: []Rule{
+			{Users: []string{"blocked"}, Enabled: false},
+			{Tenants: []string{"acme"}, Enabled: true},
+			{Percentage: 30, Enabled: true},
+		},
+	}), nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	m.Define(Flag{Name: "new-checkout", Description: "新版结算页"}, Flag{Name: "dark-mode", Enabled: true})
+
+	ctx := tenant.WithTenant(authz.WithIdentity(context.Background(), authz.Identity{Subject: "blocked"}), "acme")
+	if got := m.Evaluate(ctx, "new-checkout"); got.Enabled || got.Reason != ReasonRule || got.Rule != 0 {
+		t.Fatalf("expected user rule to win, got %+v", got)
+	}
+	if got := m.Evaluate(tenant.WithTenant(context.Background(), "acme"), "new-checkout"); !got.Enabled || got.Rule != 1 {
+		t.Fatalf("expected tenant rule, got %+v", got)
+	}
+	if !m.Enabled(context.Background(), "dark-mode") || m.Enabled(context.Background(), "missing") {
+		t.Fatal("expected defined default and undefined flag to evaluate to their defaults")
+	}
+	// 没有用户或租户时百分比规则不命中
+	if got := m.Evaluate(context.Background(), "new-checkout"); got.Enabled || got.Reason != ReasonDefault {
+		t.Fatalf("expected default without target, got %+v", got)
+	}
+
+	enabled := 0
+	for i := 0; i < 1000; i++ {
+		target := Target{UserID: "user-" + string(rune('a'+i%26)) + string(rune('a'+i/26))}
+		first := m.EvaluateFor("new-checkout", target)
+		if first != m.EvaluateFor("new-checkout", target) {
+			t.Fatal("expected percentage rollout to be sticky")
+		}
+		if first.Enabled {
+			enabled++
+		}
+	}
+	if enabled < 220 || enabled > 380 {
+		t.Fatalf("expected about 30%% of users enabled, got %d/1000", enabled)
+	}
+
+	states := m.States()
+	if len(states) != 2 || states[0].Name != "dark-mode" || states[0].Source != "default" ||
+		states[1].Source != "provider" || states[1].Description != "新版结算页" {
+		t.Fatalf("unexpected states: %+v", states)
+	}
+}
+
+func TestFileProviderRefresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.yaml")
+	write := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+	write("flags:\n  - name: beta\n    enabled: false\n")
+	m, err := New(NewFileProvider(path), &Config{RefreshInterval: types.Duration(10 * time.Millisecond)})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer m.Close()
+	ctx := WithTarget(context.Background(), Target{UserID: "u1"})
+	if m.Enabled(ctx, "beta") {
+		t.Fatal("expected beta to be disabled")
+	}
+
+	write(`[{"name": "beta", "rules": [{"users": ["u1"], "enabled": true}]}]`)
+	deadline := time.Now().Add(2 * time.Second)
+	for !m.Enabled(ctx, "beta") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !m.Enabled(ctx, "beta") {
+		t.Fatal("expected refreshed file to enable beta for u1")
+	}
+}
+
+type fakeKV struct {
+	clientv3.KV
+	kvs []*mvccpb.KeyValue
+}
+
+func (f *fakeKV) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	return &clientv3.GetResponse{Kvs: f.kvs}, nil
+}
+
+func TestEtcdProviderLoad(t *testing.T) {
+	provider := newEtcdProvider(&fakeKV{kvs: []*mvccpb.KeyValue{
+		{Key: []byte(DefaultEtcdPrefix + "beta"), Value: []byte(`{"enabled": true}`)},
+		{Key: []byte(DefaultEtcdPrefix + "broken"), Value: []byte(`[`)},
+		{Key: []byte(DefaultEtcdPrefix + "x"), Value: []byte("name: gamma\nrules:\n  - tenants: [acme]\n    enabled: true\n")},
+	}}, nil, "")
+	flags, err := provider.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(flags) != 2 || flags[0].Name != "beta" || !flags[0].Enabled || flags[1].Name != "gamma" || flags[1].Rules[0].Tenants[0] != "acme" {
+		t.Fatalf("unexpected flags: %+v", flags)
+	}
+}
+
+func TestUnleashProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/client/features" || r.Header.Get("Authorization") != "token" || r.Header.Get("UNLEASH-APPNAME") != "orders" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		_, _ = io.WriteString(w, `{"version": 2, "features": [
+			{"name": "off", "enabled": false, "strategies": [{"name": "default"}]},
+			{"name": "on", "enabled": true, "strategies": []},
+			{"name": "vip", "enabled": true, "strategies": [
+				{"name": "userWithId", "parameters": {"userIds": "u1, u2"}},
+				{"name": "remoteAddress", "parameters": {"IPs": "10.0.0.1"}},
+				{"name": "flexibleRollout", "parameters": {"rollout": "100", "stickiness": "default"},
+				 "constraints": [{"contextName": "tenantId", "operator": "IN", "values": ["acme"]}]}
+			]}
+		]}`)
+	}))
+	defer server.Close()
+
+	provider, err := NewUnleashProvider(&UnleashConfig{URL: server.URL + "/api/", APIToken: "token", AppName: "orders"})
+	if err != nil {
+		t.Fatalf("NewUnleashProvider failed: %v", err)
+	}
+	m, err := New(provider, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	cases := []struct {
+		flag   string
+		target Target
+		want   bool
+	}{
+		{"off", Target{UserID: "u1"}, false},
+		{"on", Target{}, true},
+		{"vip", Target{UserID: "u2"}, true},
+		{"vip", Target{UserID: "u3", TenantID: "acme"}, true},
+		{"vip", Target{UserID: "u3", TenantID: "other"}, false},
+	}
+	for _, tc := range cases {
+		if got := m.EvaluateFor(tc.flag, tc.target).Enabled; got != tc.want {
+			t.Errorf("%s for %+v: expected %v, got %v", tc.flag, tc.target, tc.want, got)
+		}
+	}
+}
+
+func TestAdminHandler(t *testing.T) {
+	app := fiber.New()
+	app.Get("/feature-flags", AdminHandler())
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/feature-flags", nil))
+	if err != nil || resp.StatusCode != fiber.StatusNotFound {
+		t.Fatalf("expected 404 without default manager, got %v (%v)", resp.StatusCode, err)
+	}
+
+	m, _ := New(StaticProvider(Flag{Name: "beta", Rules: []Rule{{Tenants: []string{"acme"}, Enabled: true}}}), nil)
+	SetDefault(m)
+	defer SetDefault(nil)
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/feature-flags?tenant=acme", nil))
+	if err != nil || resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %v (%v)", resp.StatusCode, err)
+	}
+	var body struct {
+		Flags []struct {
+			Name       string      `json:"name"`
+			Source     string      `json:"source"`
+			Evaluation *Evaluation `json:"evaluation"`
+		} `json:"flags"`
+		LastRefresh string `json:"lastRefresh"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if len(body.Flags) != 1 || body.Flags[0].Name != "beta" || body.Flags[0].Source != "provider" ||
+		body.Flags[0].Evaluation == nil || !body.Flags[0].Evaluation.Enabled || body.LastRefresh == "" {
+		t.Fatalf("unexpected response: %+v", body)
+	}
+}
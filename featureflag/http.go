@@ -0,0 +1,55 @@
+package featureflag
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// adminFlag 运维接口中的开关状态
+type adminFlag struct {
+	State
+	// 按查询参数 user / tenant 评估的结果
+	Evaluation *Evaluation `json:"evaluation,omitempty"`
+}
+
+// AdminHandler 运维接口：返回全局管理器中全部开关的当前状态，
+// 携带查询参数 user、tenant 时同时返回对该对象的评估结果
+func AdminHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		m := Default()
+		if m == nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": ErrNotConfigured.Error()})
+		}
+		return Handler(m)(c)
+	}
+}
+
+// Handler 指定管理器的运维接口，见 AdminHandler
+func Handler(m *Manager) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		target := Target{UserID: c.Query("user"), TenantID: c.Query("tenant")}
+		evaluate := target.UserID != "" || target.TenantID != ""
+
+		states := m.States()
+		flags := make([]adminFlag, 0, len(states))
+		for _, state := range states {
+			flag := adminFlag{State: state}
+			if evaluate {
+				evaluation := m.EvaluateFor(state.Name, target)
+				flag.Evaluation = &evaluation
+			}
+			flags = append(flags, flag)
+		}
+
+		response := fiber.Map{"flags": flags}
+		loadedAt, err := m.LastRefresh()
+		if !loadedAt.IsZero() {
+			response["lastRefresh"] = loadedAt.Format(time.RFC3339)
+		}
+		if err != nil {
+			response["refreshError"] = err.Error()
+		}
+		return c.JSON(response)
+	}
+}
@@ -0,0 +1,190 @@
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"gopkg.in/yaml.v3"
+
+	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/types"
+)
+
+// DefaultEtcdPrefix EtcdProvider 默认 key 前缀
+const DefaultEtcdPrefix = "/quickgo/featureflags/"
+
+type staticProvider []Flag
+
+// StaticProvider 固定的开关列表（如配置文件中 featureFlags.flags 解析出的列表）
+func StaticProvider(flags ...Flag) Provider {
+	return staticProvider(flags)
+}
+
+// Load 实现 Provider
+func (p staticProvider) Load(ctx context.Context) ([]Flag, error) {
+	return []Flag(p), nil
+}
+
+// FileProvider 从 YAML 或 JSON 文件读取开关，文件内容为开关列表或 {flags: [...]}
+// 配合 Config.RefreshInterval 定时重新读取
+type FileProvider struct {
+	path string
+}
+
+// NewFileProvider 创建文件 Provider
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path}
+}
+
+// Load 实现 Provider
+func (p *FileProvider) Load(ctx context.Context) ([]Flag, error) {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, err
+	}
+	flags, err := parseFlags(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse feature flag file %s: %w", p.path, err)
+	}
+	return flags, nil
+}
+
+// parseFlags 解析开关列表或 {flags: [...]}（YAML 兼容 JSON）
+func parseFlags(data []byte) ([]Flag, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	if len(node.Content) == 0 {
+		return nil, nil
+	}
+	var flags []Flag
+	if node.Content[0].Kind == yaml.SequenceNode {
+		err := node.Decode(&flags)
+		return flags, err
+	}
+	var doc struct {
+		Flags []Flag `yaml:"flags"`
+	}
+	err := node.Decode(&doc)
+	return doc.Flags, err
+}
+
+// EtcdConfig etcd Provider 配置
+type EtcdConfig struct {
+	// etcd 端点列表
+	Endpoints []string `json:"endpoints" yaml:"endpoints" toml:"endpoints"`
+	// 连接超时 示例：5s（默认 5s）
+	DialTimeout types.Duration `json:"dialTimeout" yaml:"dialTimeout" toml:"dialTimeout"`
+	// key 前缀（默认 /quickgo/featureflags/），每个 key 存储一个开关（YAML 或 JSON），缺省 name 时取 key 的后缀
+	Prefix   string `json:"prefix" yaml:"prefix" toml:"prefix"`
+	Username string `json:"username" yaml:"username" toml:"username"`
+	Password string `json:"password" yaml:"password" toml:"password"`
+}
+
+// EtcdProvider 从 etcd 前缀下读取开关，并监听前缀变化实时更新
+type EtcdProvider struct {
+	kv      clientv3.KV
+	watcher clientv3.Watcher
+	client  *clientv3.Client
+	prefix  string
+}
+
+// NewEtcdProvider 创建 etcd Provider
+func NewEtcdProvider(config *EtcdConfig) (*EtcdProvider, error) {
+	if config == nil {
+		return nil, errors.New("config is nil")
+	}
+	if len(config.Endpoints) == 0 {
+		return nil, errors.New("etcd endpoints are required")
+	}
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   config.Endpoints,
+		DialTimeout: config.DialTimeout.OrDefault(5 * time.Second),
+		Username:    config.Username,
+		Password:    config.Password,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create etcd client: %w", err)
+	}
+	provider := NewEtcdProviderWithClient(client, config.Prefix)
+	provider.client = client
+	return provider, nil
+}
+
+// NewEtcdProviderWithClient 使用已有的 etcd 客户端创建 Provider（客户端由调用方负责关闭）
+func NewEtcdProviderWithClient(client *clientv3.Client, prefix string) *EtcdProvider {
+	return newEtcdProvider(client, client, prefix)
+}
+
+func newEtcdProvider(kv clientv3.KV, watcher clientv3.Watcher, prefix string) *EtcdProvider {
+	if prefix == "" {
+		prefix = DefaultEtcdPrefix
+	}
+	return &EtcdProvider{kv: kv, watcher: watcher, prefix: prefix}
+}
+
+// Load 实现 Provider，解析失败的开关被跳过并记录日志
+func (p *EtcdProvider) Load(ctx context.Context) ([]Flag, error) {
+	resp, err := p.kv.Get(ctx, p.prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	flags := make([]Flag, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var flag Flag
+		if err := yaml.Unmarshal(kv.Value, &flag); err != nil {
+			logger.Error(ctx, "Failed to parse feature flag from etcd: key=%s, error=%v", kv.Key, err)
+			continue
+		}
+		if flag.Name == "" {
+			flag.Name = strings.TrimPrefix(string(kv.Key), p.prefix)
+		}
+		flags = append(flags, flag)
+	}
+	return flags, nil
+}
+
+// Watch 实现 Watcher；连接中断时按 5s 间隔重试
+func (p *EtcdProvider) Watch(ctx context.Context, onChange func()) error {
+	if p.watcher == nil {
+		<-ctx.Done()
+		return nil
+	}
+	for {
+		watchCtx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+		for resp := range p.watcher.Watch(watchCtx, p.prefix, clientv3.WithPrefix()) {
+			if err := resp.Err(); err != nil {
+				logger.Warn(ctx, "Feature flag etcd watch error: prefix=%s, error=%v", p.prefix, err)
+				break
+			}
+			if len(resp.Events) > 0 {
+				onChange()
+			}
+		}
+		cancel()
+		if ctx.Err() != nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(5 * time.Second):
+		}
+		// 重连期间可能错过变更，重新加载一次
+		onChange()
+	}
+}
+
+// Close 关闭由 NewEtcdProvider 创建的 etcd 客户端
+func (p *EtcdProvider) Close() error {
+	if p.client == nil {
+		return nil
+	}
+	return p.client.Close()
+}
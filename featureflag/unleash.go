@@ -0,0 +1,227 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/types"
+)
+
+// UnleashConfig Unleash Provider 配置
+type UnleashConfig struct {
+	// Unleash API 地址 示例：https://unleash.example.com/api
+	URL string `json:"url" yaml:"url" toml:"url"`
+	// 客户端 API Token
+	APIToken string `json:"apiToken" yaml:"apiToken" toml:"apiToken"`
+	// 应用名称（UNLEASH-APPNAME 请求头）
+	AppName string `json:"appName" yaml:"appName" toml:"appName"`
+	// 实例 ID（UNLEASH-INSTANCEID 请求头）
+	InstanceID string `json:"instanceId" yaml:"instanceId" toml:"instanceId"`
+	// 请求超时 示例：5s（默认 5s）
+	Timeout types.Duration `json:"timeout" yaml:"timeout" toml:"timeout"`
+	// 自定义 HTTP 客户端（设置后忽略 Timeout）
+	HTTPClient *http.Client `json:"-" yaml:"-" toml:"-"`
+}
+
+// UnleashProvider 从 Unleash Client API（/client/features）拉取开关，配合 Config.RefreshInterval 定时刷新
+// 支持 default、userWithId、gradualRolloutUserId、flexibleRollout 策略，以及 userId / tenantId 的 IN 约束；
+// 其他策略或约束无法在本地评估，视为不命中
+type UnleashProvider struct {
+	url        string
+	config     UnleashConfig
+	httpClient *http.Client
+}
+
+// NewUnleashProvider 创建 Unleash Provider
+func NewUnleashProvider(config *UnleashConfig) (*UnleashProvider, error) {
+	if config == nil {
+		return nil, errors.New("config is nil")
+	}
+	if config.URL == "" {
+		return nil, errors.New("unleash url is required")
+	}
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: config.Timeout.OrDefault(5 * time.Second)}
+	}
+	return &UnleashProvider{
+		url:        strings.TrimRight(config.URL, "/") + "/client/features",
+		config:     *config,
+		httpClient: httpClient,
+	}, nil
+}
+
+type unleashFeatures struct {
+	Features []unleashFeature `json:"features"`
+}
+
+type unleashFeature struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Enabled     bool              `json:"enabled"`
+	Strategies  []unleashStrategy `json:"strategies"`
+}
+
+type unleashStrategy struct {
+	Name        string              `json:"name"`
+	Parameters  map[string]any      `json:"parameters"`
+	Constraints []unleashConstraint `json:"constraints"`
+}
+
+type unleashConstraint struct {
+	ContextName string   `json:"contextName"`
+	Operator    string   `json:"operator"`
+	Values      []string `json:"values"`
+	Inverted    bool     `json:"inverted"`
+}
+
+// Load 实现 Provider
+func (p *UnleashProvider) Load(ctx context.Context) ([]Flag, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.config.APIToken != "" {
+		req.Header.Set("Authorization", p.config.APIToken)
+	}
+	if p.config.AppName != "" {
+		req.Header.Set("UNLEASH-APPNAME", p.config.AppName)
+	}
+	if p.config.InstanceID != "" {
+		req.Header.Set("UNLEASH-INSTANCEID", p.config.InstanceID)
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch unleash features: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("unleash features request failed: status=%d, body=%s", resp.StatusCode, body)
+	}
+	var features unleashFeatures
+	if err := json.NewDecoder(resp.Body).Decode(&features); err != nil {
+		return nil, fmt.Errorf("failed to decode unleash features: %w", err)
+	}
+
+	flags := make([]Flag, 0, len(features.Features))
+	for _, feature := range features.Features {
+		flags = append(flags, convertUnleashFeature(ctx, feature))
+	}
+	return flags, nil
+}
+
+// convertUnleashFeature 将 Unleash 开关转换为规则：开关启用且任一策略命中时开启
+func convertUnleashFeature(ctx context.Context, feature unleashFeature) Flag {
+	flag := Flag{Name: feature.Name, Description: feature.Description}
+	if !feature.Enabled {
+		return flag
+	}
+	if len(feature.Strategies) == 0 {
+		flag.Enabled = true
+		return flag
+	}
+	for _, strategy := range feature.Strategies {
+		rule, ok := convertUnleashStrategy(strategy)
+		if !ok {
+			logger.Debug(ctx, "Unsupported unleash strategy ignored: flag=%s, strategy=%s", feature.Name, strategy.Name)
+			continue
+		}
+		flag.Rules = append(flag.Rules, rule)
+	}
+	return flag
+}
+
+// convertUnleashStrategy 转换单个策略，无法本地评估时返回 false
+func convertUnleashStrategy(strategy unleashStrategy) (Rule, bool) {
+	rule := Rule{Enabled: true}
+	switch strategy.Name {
+	case "default":
+	case "userWithId":
+		rule.Users = splitList(unleashParameter(strategy.Parameters, "userIds"))
+		if len(rule.Users) == 0 {
+			return Rule{}, false
+		}
+	case "gradualRolloutUserId", "flexibleRollout":
+		key := "percentage"
+		if strategy.Name == "flexibleRollout" {
+			key = "rollout"
+			if stickiness := unleashParameter(strategy.Parameters, "stickiness"); stickiness != "" && stickiness != "default" && stickiness != "userId" {
+				return Rule{}, false
+			}
+		}
+		percentage, err := strconv.ParseFloat(unleashParameter(strategy.Parameters, key), 64)
+		if err != nil || percentage <= 0 {
+			return Rule{}, false
+		}
+		rule.Percentage = percentage
+	default:
+		return Rule{}, false
+	}
+
+	for _, constraint := range strategy.Constraints {
+		if constraint.Operator != "IN" || constraint.Inverted {
+			return Rule{}, false
+		}
+		var list *[]string
+		switch constraint.ContextName {
+		case "userId":
+			list = &rule.Users
+		case "tenantId":
+			list = &rule.Tenants
+		default:
+			return Rule{}, false
+		}
+		*list = intersect(*list, constraint.Values)
+		// 约束之间交集为空时规则不会命中任何对象
+		if len(*list) == 0 {
+			return Rule{}, false
+		}
+	}
+	return rule, true
+}
+
+// unleashParameter 读取策略参数（Unleash 参数值可能为字符串或数字）
+func unleashParameter(parameters map[string]any, key string) string {
+	switch v := parameters[key].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// intersect 合并约束：已有列表时取交集
+func intersect(current, values []string) []string {
+	if len(current) == 0 {
+		return values
+	}
+	var result []string
+	for _, v := range current {
+		if slices.Contains(values, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -4,16 +4,27 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"sort"
 	"sync"
 	"syscall"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
 
 	"github.com/team-dandelion/quickgo/db/gorm"
+	"github.com/team-dandelion/quickgo/db/migrate"
 	"github.com/team-dandelion/quickgo/db/mongodb"
 	"github.com/team-dandelion/quickgo/db/redis"
+	"github.com/team-dandelion/quickgo/grpcep"
+	"github.com/team-dandelion/quickgo/handover"
+	"github.com/team-dandelion/quickgo/http"
 	"github.com/team-dandelion/quickgo/logger"
 	"github.com/team-dandelion/quickgo/metrics"
+	"github.com/team-dandelion/quickgo/mq"
+	"github.com/team-dandelion/quickgo/recovery"
 	"github.com/team-dandelion/quickgo/tracing"
 )
 
@@ -30,17 +41,42 @@ type Framework struct {
 	grpcServer    *GrpcServer
 	grpcClientMgr *GrpcClientManager
 	httpServer    *HTTPServer
+	httpServers   map[string]*HTTPServer // 具名 HTTP 服务器（如 internal）
 
 	// 数据库组件
 	gormManager    *gorm.Manager
 	mongodbManager *mongodb.Manager
 	redisManager   *redis.Manager
+	migrators      map[string]*migrate.Migrator
+
+	// 消息队列组件
+	mqManager *mq.Manager
+
+	// 声明式路由表及动态来源监听
+	routeTable       *grpcep.RouteTable
+	routeWatchCancel context.CancelFunc
+	routeWatchDone   chan struct{}
+
+	// 停止日志级别信号监听
+	stopLevelSignals func()
 
 	// 组件注册表（用于扩展）
 	components                map[string]Component
 	componentOrder            []string
+	componentDeps             map[string][]string
 	initializedComponentOrder []string
 
+	// 依赖提供者注册表
+	providers      map[string]*provider
+	providerOrder  []string
+	builtProviders []string
+
+	// 生命周期钩子
+	hooks map[hookStage][]*lifecycleHook
+
+	// 最近一次停止的报告
+	shutdownReport *ShutdownReport
+
 	// 生命周期管理
 	mu           sync.RWMutex
 	lifecycleMu  sync.Mutex
@@ -54,30 +90,51 @@ type Framework struct {
 // FrameworkConfig 框架配置（内部使用）
 type FrameworkConfig struct {
 	// 应用配置
-	App AppConfig
+	App AppConfig `json:"app" yaml:"app" toml:"app"`
 
 	// Logger 配置
-	Logger *LoggerConfig
+	Logger *LoggerConfig `json:"logger" yaml:"logger" toml:"logger"`
 
 	// gRPC Server 配置（可选）
-	GrpcServer *GrpcServerConfig
+	GrpcServer *GrpcServerConfig `json:"grpcServer" yaml:"grpcServer" toml:"grpcServer"`
 
 	// gRPC Client 配置（可选，网关场景使用）
-	GrpcClient *GrpcClientConfig
+	GrpcClient *GrpcClientConfig `json:"grpcClient" yaml:"grpcClient" toml:"grpcClient"`
 
 	// HTTP Server 配置（可选）
-	HTTPServer *HTTPServerConfig
+	HTTPServer *HTTPServerConfig `json:"httpServer" yaml:"httpServer" toml:"httpServer"`
+
+	// 具名 HTTP Server 配置（可选，名称 -> 配置，如内部运维接口）
+	HTTPServers map[string]*HTTPServerConfig `json:"httpServers" yaml:"httpServers" toml:"httpServers"`
+
+	// 声明式路由鉴权策略（名称 -> 中间件，可选）
+	RouteAuth map[string]fiber.Handler `json:"-" yaml:"-" toml:"-"`
+
+	// 声明式路由动态来源（可选，配置后路由变化时热更新）
+	RouteSource RouteSource `json:"-" yaml:"-" toml:"-"`
 
 	// 数据库配置（可选）
-	Gorm    *gorm.GormManagerConfig
-	MongoDB *mongodb.MongoManagerConfig
-	Redis   *redis.RedisManagerConfig
+	Gorm    *gorm.GormManagerConfig     `json:"gorm" yaml:"gorm" toml:"gorm"`
+	MongoDB *mongodb.MongoManagerConfig `json:"mongodb" yaml:"mongodb" toml:"mongodb"`
+	Redis   *redis.RedisManagerConfig   `json:"redis" yaml:"redis" toml:"redis"`
+
+	// 数据库迁移配置（可选，依赖 Gorm）
+	Migrate *migrate.Config `json:"migrate" yaml:"migrate" toml:"migrate"`
+
+	// 消息队列配置（可选）
+	MQ *mq.Config `json:"mq" yaml:"mq" toml:"mq"`
 
 	// 链路追踪配置（可选）
-	Tracing *tracing.Config
+	Tracing *tracing.Config `json:"tracing" yaml:"tracing" toml:"tracing"`
 
 	// 指标配置（可选）
-	Metrics *metrics.Config
+	Metrics *metrics.Config `json:"metrics" yaml:"metrics" toml:"metrics"`
+
+	// panic / 错误突增上报配置（可选）
+	Recovery *recovery.Config `json:"recovery" yaml:"recovery" toml:"recovery"`
+
+	// 监听套接字交接配置（可选，用于不经负载均衡的原地升级）
+	Handover *handover.Config `json:"handover" yaml:"handover" toml:"handover"`
 }
 
 // FrameworkOption 框架配置选项
@@ -96,8 +153,14 @@ type LoggerConfig struct {
 	Level   string `json:"level" yaml:"level" toml:"level"`       // 日志级别：debug, info, warn, error
 	Output  string `json:"output" yaml:"output" toml:"output"`    // 输出方式：console, file
 	File    string `json:"file" yaml:"file" toml:"file"`          // 文件路径（output=file 时）
-	Service string `json:"service" yaml:"service" toml:"service"` // 服务名称
-	Version string `json:"version" yaml:"version" toml:"version"` // 服务版本
+	Service string `json:"service" yaml:"service" toml:"service"` // 服务名称，为空时使用 app.name
+	Version string `json:"version" yaml:"version" toml:"version"` // 服务版本，为空时使用 app.version
+	// 第三方库日志级别（如 etcd: warn、mongo: error、fiber: info），未配置的库默认为 warn
+	Libraries map[string]string `json:"libraries" yaml:"libraries" toml:"libraries"`
+	// 错误率触发的日志级别自动提升（按 gRPC 方法统计，可选）
+	Boost *logger.BoostConfig `json:"boost" yaml:"boost" toml:"boost"`
+	// 运行时级别调整（管理接口的默认有效期与 SIGUSR1/SIGHUP 信号，可选）
+	Dynamic *logger.DynamicLevelConfig `json:"dynamic" yaml:"dynamic" toml:"dynamic"`
 }
 
 // Component 组件接口（用于扩展）
@@ -145,6 +208,9 @@ func NewFramework(opts ...FrameworkOption) (*Framework, error) {
 		config:         config,
 		components:     make(map[string]Component),
 		componentOrder: make([]string, 0),
+		componentDeps:  make(map[string][]string),
+		hooks:          make(map[hookStage][]*lifecycleHook),
+		providers:      make(map[string]*provider),
 	}
 
 	return f, nil
@@ -187,6 +253,41 @@ func ConfigOptionWithHTTPServer(server *HTTPServerConfig) FrameworkOption {
 	}
 }
 
+// ConfigOptionWithNamedHTTPServer 配置具名 HTTP Server（如 "internal"），与默认 HTTP Server 同时运行
+// 各服务器拥有独立的监听地址、中间件与 TLS 配置，通过 Framework.HTTPServerNamed(name) 获取；
+// name 为 DefaultHTTPServerName 时等同于 ConfigOptionWithHTTPServer
+func ConfigOptionWithNamedHTTPServer(name string, server *HTTPServerConfig) FrameworkOption {
+	return func(c *FrameworkConfig) {
+		if name == DefaultHTTPServerName {
+			c.HTTPServer = server
+			return
+		}
+		if c.HTTPServers == nil {
+			c.HTTPServers = make(map[string]*HTTPServerConfig)
+		}
+		c.HTTPServers[name] = server
+	}
+}
+
+// ConfigOptionWithRouteAuthPolicy 注册声明式路由使用的鉴权策略
+// 路由配置中 auth 字段引用该名称，handler 鉴权失败时直接返回响应，成功时调用 c.Next()
+func ConfigOptionWithRouteAuthPolicy(name string, handler fiber.Handler) FrameworkOption {
+	return func(c *FrameworkConfig) {
+		if c.RouteAuth == nil {
+			c.RouteAuth = make(map[string]fiber.Handler)
+		}
+		c.RouteAuth[name] = handler
+	}
+}
+
+// ConfigOptionWithRouteSource 配置声明式路由的动态来源（如 NewConfigRouteSource、NewEtcdRouteSource）
+// 启动后监听来源变化，校验通过的路由表原子替换当前路由，校验失败时保留旧路由
+func ConfigOptionWithRouteSource(source RouteSource) FrameworkOption {
+	return func(c *FrameworkConfig) {
+		c.RouteSource = source
+	}
+}
+
 // ConfigOptionWithGorm 配置 GORM 数据库管理器
 func ConfigOptionWithGorm(config *gorm.GormManagerConfig) FrameworkOption {
 	return func(c *FrameworkConfig) {
@@ -194,6 +295,13 @@ func ConfigOptionWithGorm(config *gorm.GormManagerConfig) FrameworkOption {
 	}
 }
 
+// ConfigOptionWithMigrate 配置数据库迁移（需同时配置 Gorm）
+func ConfigOptionWithMigrate(config *migrate.Config) FrameworkOption {
+	return func(c *FrameworkConfig) {
+		c.Migrate = config
+	}
+}
+
 // ConfigOptionWithMongoDB 配置 MongoDB 数据库管理器
 func ConfigOptionWithMongoDB(config *mongodb.MongoManagerConfig) FrameworkOption {
 	return func(c *FrameworkConfig) {
@@ -208,6 +316,30 @@ func ConfigOptionWithRedis(config *redis.RedisManagerConfig) FrameworkOption {
 	}
 }
 
+// ConfigOptionWithMQ 配置消息队列
+func ConfigOptionWithMQ(config *mq.Config) FrameworkOption {
+	return func(c *FrameworkConfig) {
+		c.MQ = cloneMQConfig(config)
+	}
+}
+
+func cloneMQConfig(config *mq.Config) *mq.Config {
+	if config == nil {
+		return nil
+	}
+	cloned := *config
+	if config.Kafka != nil {
+		kafka := *config.Kafka
+		kafka.Brokers = append([]string(nil), config.Kafka.Brokers...)
+		cloned.Kafka = &kafka
+	}
+	if config.RabbitMQ != nil {
+		rabbitmq := *config.RabbitMQ
+		cloned.RabbitMQ = &rabbitmq
+	}
+	return &cloned
+}
+
 // ConfigOptionWithTracing 配置链路追踪
 func ConfigOptionWithTracing(config *tracing.Config) FrameworkOption {
 	return func(c *FrameworkConfig) {
@@ -216,10 +348,58 @@ func ConfigOptionWithTracing(config *tracing.Config) FrameworkOption {
 			return
 		}
 		cloned := *config
+		if config.Metrics != nil {
+			metricsConfig := *config.Metrics
+			cloned.Metrics = &metricsConfig
+		}
+		if config.Logs != nil {
+			logsConfig := *config.Logs
+			cloned.Logs = &logsConfig
+		}
+		if config.Payload != nil {
+			payloadConfig := *config.Payload
+			cloned.Payload = &payloadConfig
+		}
+		if config.AttributeFilter != nil {
+			filterConfig := *config.AttributeFilter
+			cloned.AttributeFilter = &filterConfig
+		}
 		c.Tracing = &cloned
 	}
 }
 
+// ConfigOptionWithRecovery 配置 panic 与错误突增上报（如 Sentry）
+func ConfigOptionWithRecovery(config *recovery.Config) FrameworkOption {
+	return func(c *FrameworkConfig) {
+		if config == nil {
+			c.Recovery = nil
+			return
+		}
+		cloned := *config
+		if config.Sentry != nil {
+			sentryConfig := *config.Sentry
+			cloned.Sentry = &sentryConfig
+		}
+		if config.ErrorBurst != nil {
+			burstConfig := *config.ErrorBurst
+			cloned.ErrorBurst = &burstConfig
+		}
+		c.Recovery = &cloned
+	}
+}
+
+// ConfigOptionWithHandover 配置监听套接字交接（SO_REUSEPORT / 信号触发升级）
+func ConfigOptionWithHandover(config *handover.Config) FrameworkOption {
+	return func(c *FrameworkConfig) {
+		if config == nil {
+			c.Handover = nil
+			return
+		}
+		cloned := *config
+		c.Handover = &cloned
+	}
+}
+
 // ConfigOptionWithMetrics 配置指标采集
 func ConfigOptionWithMetrics(config *metrics.Config) FrameworkOption {
 	return func(c *FrameworkConfig) {
@@ -266,6 +446,17 @@ func (f *Framework) Init() error {
 		}
 	}()
 
+	// 0. 校验配置，一次报告所有问题，避免在初始化中途才因单个配置项失败
+	if err := ValidateConfig(f.config); err != nil {
+		return fmt.Errorf("invalid framework config: %w", err)
+	}
+
+	// 按依赖排序自定义组件（在初始化任何组件之前校验依赖）
+	components, err := f.orderComponents(f.componentsSnapshot())
+	if err != nil {
+		return fmt.Errorf("invalid component dependencies: %w", err)
+	}
+
 	// 1. 初始化链路追踪（最优先，其他组件可能需要追踪）
 	if f.config.Tracing != nil {
 		if err := f.initTracing(ctx); err != nil {
@@ -280,15 +471,34 @@ func (f *Framework) Init() error {
 		}
 	} else {
 		// 即使未启用，也创建一个默认 logger
-		logger.Init(logger.Config{
-			Level: logger.LevelInfo,
-		})
+		logger.Init(f.baseLoggerConfig(logger.LevelInfo))
 		f.setLogger(logger.GetDefault())
 	}
+	if err := f.initLibraryLoggers(ctx); err != nil {
+		return fmt.Errorf("failed to init library loggers: %w", err)
+	}
+	handover.Configure(f.config.Handover)
+	if f.config.Recovery != nil {
+		if err := recovery.Init(f.config.Recovery, f.config.App.Name, f.config.App.Version, f.config.App.Env); err != nil {
+			return fmt.Errorf("failed to init recovery reporter: %w", err)
+		}
+		if f.config.Recovery.Enabled {
+			logger.Info(ctx, "Recovery reporter initialized")
+		}
+	}
 
 	// 3. 初始化指标收集器（如果配置）
 	if f.config.Metrics != nil {
 		f.setMetrics(metrics.New(*f.config.Metrics))
+		if f.config.Tracing != nil && f.config.Tracing.Enabled {
+			namespace := f.config.Metrics.Namespace
+			if namespace == "" {
+				namespace = metrics.DefaultConfig().Namespace
+			}
+			if err := tracing.RegisterMetrics(f.metrics.Registry(), namespace); err != nil {
+				return fmt.Errorf("failed to register tracing metrics: %w", err)
+			}
+		}
 	}
 
 	// 4. 初始化 gRPC Server（仅当通过 Option 配置时）
@@ -317,20 +527,20 @@ func (f *Framework) Init() error {
 
 	// 6. 初始化 HTTP Server（仅当通过 Option 配置时）
 	if f.config.HTTPServer != nil && f.config.HTTPServer.Enabled {
-		if f.config.Metrics != nil && f.config.HTTPServer.Metrics == nil {
-			config := *f.config.HTTPServer
-			config.Metrics = cloneMetricsConfig(f.config.Metrics)
-			f.config.HTTPServer = &config
-		}
-		if f.metrics != nil {
-			config := *f.config.HTTPServer
-			config.metrics = f.metrics
-			f.config.HTTPServer = &config
-		}
+		f.config.HTTPServer = f.prepareHTTPServerConfig(f.config.HTTPServer)
 		if err := f.initHTTPServer(ctx); err != nil {
 			return fmt.Errorf("failed to init http server: %w", err)
 		}
 	}
+	for _, name := range sortedHTTPServerNames(f.config.HTTPServers) {
+		config := f.config.HTTPServers[name]
+		if config == nil || !config.Enabled {
+			continue
+		}
+		if err := f.initNamedHTTPServer(name, f.prepareHTTPServerConfig(config)); err != nil {
+			return fmt.Errorf("failed to init http server %s: %w", name, err)
+		}
+	}
 
 	// 7. 初始化 GORM 数据库管理器（仅当通过 Option 配置时）
 	if f.config.Gorm != nil {
@@ -338,6 +548,11 @@ func (f *Framework) Init() error {
 			return fmt.Errorf("failed to init gorm manager: %w", err)
 		}
 	}
+	if f.config.Migrate != nil {
+		if err := f.initMigrations(ctx); err != nil {
+			return fmt.Errorf("failed to init migrations: %w", err)
+		}
+	}
 
 	// 8. 初始化 MongoDB 数据库管理器（仅当通过 Option 配置时）
 	if f.config.MongoDB != nil {
@@ -353,8 +568,20 @@ func (f *Framework) Init() error {
 		}
 	}
 
-	// 10. 初始化自定义组件
-	for _, entry := range f.componentsSnapshot() {
+	// 10. 初始化消息队列管理器（仅当通过 Option 配置时）
+	if f.config.MQ != nil {
+		if err := f.initMQManager(ctx); err != nil {
+			return fmt.Errorf("failed to init mq manager: %w", err)
+		}
+	}
+
+	// 11. 构造依赖提供者（自定义组件初始化时可通过 Resolve 获取）
+	if err := f.initProviders(ctx); err != nil {
+		return err
+	}
+
+	// 12. 初始化自定义组件（依赖的组件先初始化）
+	for _, entry := range components {
 		component := entry.component
 		if component != nil && component.IsEnabled() {
 			if err := component.Init(ctx); err != nil {
@@ -392,11 +619,19 @@ func (f *Framework) Start() error {
 	}
 	grpcServer := f.grpcServer
 	httpServer := f.httpServer
+	namedHTTPServers := f.namedHTTPServersLocked()
 	grpcClientMgr := f.grpcClientMgr
+	mqManager := f.mqManager
 	components := f.initializedComponentsLocked()
 	f.mu.Unlock()
 
 	ctx := context.Background()
+
+	// 执行启动前钩子（此时尚未启动任何服务）
+	if err := runHooks(ctx, f.hooksFor(hookBeforeStart)); err != nil {
+		return fmt.Errorf("before start hooks failed: %w", err)
+	}
+
 	var cleanup []func()
 	startedComponents := make([]Component, 0, len(components))
 	startFailed := func(format string, args ...interface{}) error {
@@ -412,6 +647,16 @@ func (f *Framework) Start() error {
 		return fmt.Errorf(format, args...)
 	}
 
+	// 预热 gRPC 客户端连接（依赖暂时不可用时降级为懒连接，不阻止启动）
+	if grpcClientMgr != nil && f.config.GrpcClient != nil && f.config.GrpcClient.WarmUp {
+		timeout := f.config.GrpcClient.Timeout.OrDefault(defaultGrpcClientWarmUpTimeout)
+		warmUpCtx, cancel := context.WithTimeout(ctx, timeout)
+		if failed := grpcClientMgr.WarmUp(warmUpCtx); len(failed) > 0 {
+			logger.Warn(ctx, "Some gRPC dependencies are unavailable at startup: %d service(s)", len(failed))
+		}
+		cancel()
+	}
+
 	// 1. 启动 gRPC Server
 	if grpcServer != nil {
 		if err := grpcServer.Start(); err != nil {
@@ -436,6 +681,20 @@ func (f *Framework) Start() error {
 			}
 		})
 		logger.Info(ctx, "HTTP server started")
+		f.startRouteWatch(ctx)
+		cleanup = append(cleanup, f.stopRouteWatch)
+	}
+	for _, named := range namedHTTPServers {
+		server := named.server
+		if err := server.StartAsync(); err != nil {
+			return startFailed("failed to start http server %s: %w", named.name, err)
+		}
+		cleanup = append(cleanup, func() {
+			if err := server.Stop(); err != nil {
+				logger.Error(ctx, "Failed to rollback http server %s after start failure: %v", named.name, err)
+			}
+		})
+		logger.Info(ctx, "HTTP server %s started", named.name)
 	}
 
 	// 3. 启动自定义组件
@@ -448,6 +707,13 @@ func (f *Framework) Start() error {
 		}
 	}
 
+	// 4. 启动消息队列消费（在组件之后启动，保证消费处理依赖的组件已就绪）
+	if mqManager != nil {
+		if err := mqManager.Start(ctx); err != nil {
+			return startFailed("failed to start mq manager: %w", err)
+		}
+	}
+
 	f.mu.Lock()
 	if f.started {
 		f.mu.Unlock()
@@ -458,7 +724,17 @@ func (f *Framework) Start() error {
 	if grpcClientMgr != nil {
 		grpcClientMgr.StartHealthCheck()
 	}
+	// 新版本不再监听的继承地址由旧进程排空后关闭
+	if unused := handover.Default().CloseUnused(); len(unused) > 0 {
+		logger.Warn(ctx, "Closed unused inherited listeners: %v", unused)
+	}
+	f.startLevelSignals(ctx)
 	logger.Info(ctx, "Framework started successfully")
+
+	// 执行启动后钩子（失败时框架保持运行状态）
+	if err := runHooks(ctx, f.hooksFor(hookAfterStart)); err != nil {
+		return fmt.Errorf("after start hooks failed: %w", err)
+	}
 	return nil
 }
 
@@ -489,21 +765,29 @@ func (f *Framework) stop(ctx context.Context, logStopped bool) error {
 		return nil
 	}
 	components := f.initializedComponentsLocked()
+	wasStarted := f.started
+	providerClosers := f.closeProvidersLocked()
 	httpServer := f.httpServer
+	namedHTTPServers := f.namedHTTPServersLocked()
 	grpcServer := f.grpcServer
 	grpcClientMgr := f.grpcClientMgr
 	redisManager := f.redisManager
 	mongodbManager := f.mongodbManager
 	gormManager := f.gormManager
+	mqManager := f.mqManager
 	frameworkLogger := f.logger
 	traceEnabled := f.config.Tracing != nil && f.config.Tracing.Enabled
+	recoveryEnabled := f.config.Recovery != nil && f.config.Recovery.Enabled
 
 	f.httpServer = nil
+	f.httpServers = nil
 	f.grpcServer = nil
 	f.grpcClientMgr = nil
 	f.redisManager = nil
 	f.mongodbManager = nil
 	f.gormManager = nil
+	f.migrators = nil
+	f.mqManager = nil
 	f.logger = nil
 	f.metrics = nil
 	f.started = false
@@ -520,76 +804,123 @@ func (f *Framework) stop(ctx context.Context, logStopped bool) error {
 	}()
 
 	var errs []error
+	report := newShutdownReport()
+	// step 执行单个组件的停止，记录耗时与错误
+	step := func(name string, stop func() error) {
+		start := time.Now()
+		err := stop()
+		report.record(name, time.Since(start), err)
+		if err != nil {
+			logger.Error(ctx, "Failed to stop %s: %v", name, err)
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	// 执行停止前钩子（按注册的逆序，失败不影响后续停止）
+	if wasStarted && logStopped {
+		hooks := f.hooksFor(hookBeforeStop)
+		for i := len(hooks) - 1; i >= 0; i-- {
+			hook := hooks[i]
+			step(hook.name, func() error { return hook.run(ctx) })
+		}
+	}
 
 	// 按相反顺序停止组件
 
+	// 停止声明式路由监听
+	f.stopRouteWatch()
+	f.mu.Lock()
+	f.routeTable = nil
+	f.mu.Unlock()
+
+	// 0. 停止消息队列消费，等待处理中的消息完成后关闭连接
+	if mqManager != nil {
+		step("mq manager", func() error { return mqManager.Close(ctx) })
+	}
+
 	// 1. 停止自定义组件
 	for i := len(components) - 1; i >= 0; i-- {
 		component := components[i]
 		if component != nil {
-			if err := component.Stop(ctx); err != nil {
-				logger.Error(ctx, "Failed to stop component %s: %v", component.Name(), err)
-				errs = append(errs, fmt.Errorf("component %s: %w", component.Name(), err))
-			}
+			step("component "+component.Name(), func() error { return component.Stop(ctx) })
 		}
 	}
 
-	// 2. 停止 HTTP Server
-	if httpServer != nil {
-		if err := httpServer.Stop(); err != nil {
-			logger.Error(ctx, "Failed to stop http server: %v", err)
-			errs = append(errs, fmt.Errorf("http server: %w", err))
+	// 2. 停止 HTTP Server（记录停止前处理中的请求与打开的连接，优雅关闭会等待其完成）
+	stopHTTPServer := func(name string, server *HTTPServer) {
+		if inner := server.GetServer(); inner != nil {
+			report.InFlightDrained += inner.InFlight()
+			report.ConnectionsClosed += inner.OpenConnections()
 		}
+		step(name, server.Stop)
+	}
+	for i := len(namedHTTPServers) - 1; i >= 0; i-- {
+		named := namedHTTPServers[i]
+		stopHTTPServer("http server "+named.name, named.server)
+	}
+	if httpServer != nil {
+		stopHTTPServer("http server", httpServer)
 	}
 
 	// 3. 停止 gRPC Server
 	if grpcServer != nil {
-		if err := grpcServer.Stop(); err != nil {
-			logger.Error(ctx, "Failed to stop grpc server: %v", err)
-			errs = append(errs, fmt.Errorf("grpc server: %w", err))
-		}
+		step("grpc server", grpcServer.Stop)
+	}
+
+	// 关闭依赖提供者实例（服务已停止，不再有请求使用）
+	for _, p := range providerClosers {
+		step("provider "+p.name, p.closer.Close)
 	}
 
 	// 4. 关闭 gRPC Client Manager
 	if grpcClientMgr != nil {
-		if err := grpcClientMgr.CloseAll(); err != nil {
-			logger.Error(ctx, "Failed to close grpc client manager: %v", err)
-			errs = append(errs, fmt.Errorf("grpc client manager: %w", err))
+		for _, pool := range grpcClientMgr.GetPoolStatus() {
+			report.ConnectionsClosed += pool.Total
 		}
+		step("grpc client manager", grpcClientMgr.CloseAll)
 	}
 
 	// 5. 关闭数据库连接
 	if redisManager != nil {
-		if err := redisManager.Close(); err != nil {
-			logger.Error(ctx, "Failed to close redis manager: %v", err)
-			errs = append(errs, fmt.Errorf("redis manager: %w", err))
-		}
+		step("redis manager", redisManager.Close)
 	}
-
 	if mongodbManager != nil {
-		if err := mongodbManager.Close(); err != nil {
-			logger.Error(ctx, "Failed to close mongodb manager: %v", err)
-			errs = append(errs, fmt.Errorf("mongodb manager: %w", err))
-		}
+		step("mongodb manager", mongodbManager.Close)
 	}
-
 	if gormManager != nil {
-		if err := gormManager.Close(); err != nil {
-			logger.Error(ctx, "Failed to close gorm manager: %v", err)
-			errs = append(errs, fmt.Errorf("gorm manager: %w", err))
-		}
+		step("gorm manager", gormManager.Close)
 	}
 
-	// 关闭链路追踪
+	// 发送剩余的错误上报事件
+	if recoveryEnabled {
+		step("recovery reporter", func() error { return recovery.Shutdown(ctx) })
+	}
+
+	// 关闭链路追踪（导出剩余 span）
 	if traceEnabled {
-		if err := tracing.Shutdown(ctx); err != nil {
-			logger.Error(ctx, "Failed to shutdown tracing: %v", err)
-			errs = append(errs, fmt.Errorf("tracing: %w", err))
-		} else {
-			logger.Info(ctx, "Tracing shutdown successfully")
-		}
+		before := tracing.Stats()
+		step("tracing", func() error { return tracing.Shutdown(ctx) })
+		after := tracing.Stats()
+		report.SpansFlushed = after.Exported - before.Exported
+		report.SpansDropped = after.Dropped - before.Dropped
 	}
 
+	// 停止日志级别信号监听并恢复临时调整的日志级别
+	f.stopLevelSignalHandling()
+	logger.RevertLevelOverrides()
+
+	// 恢复被错误率提升的模块日志级别
+	if booster := logger.DefaultBooster(); booster != nil {
+		booster.Close()
+		logger.SetDefaultBooster(nil)
+	}
+
+	f.mu.Lock()
+	f.shutdownReport = report
+	f.mu.Unlock()
+	if logStopped {
+		report.finish(ctx)
+	}
 	if logStopped {
 		logger.Info(ctx, "Framework stopped")
 	}
@@ -604,11 +935,37 @@ func (f *Framework) stop(ctx context.Context, logStopped bool) error {
 	return nil
 }
 
+// ShutdownReport 获取最近一次停止的报告（尚未停止过时为 nil）
+func (f *Framework) ShutdownReport() *ShutdownReport {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.shutdownReport
+}
+
 // Wait 等待中断信号（优雅关闭）
+// 启用 Handover.UpgradeOnSignal 时，收到升级信号会启动新版本进程并传递监听，随后当前进程优雅关闭
 func (f *Framework) Wait() {
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	<-sigChan
+	signals := []os.Signal{os.Interrupt, syscall.SIGTERM}
+	upgrade := f.config.Handover != nil && f.config.Handover.UpgradeOnSignal && handover.UpgradeSignal != nil
+	if upgrade {
+		signals = append(signals, handover.UpgradeSignal)
+	}
+	signal.Notify(sigChan, signals...)
+	defer signal.Stop(sigChan)
+
+	for sig := range sigChan {
+		if upgrade && sig == handover.UpgradeSignal {
+			process, err := handover.StartProcess("", nil)
+			if err != nil {
+				logger.Error(context.Background(), "Failed to start new process for upgrade: %v", err)
+				continue
+			}
+			logger.Info(context.Background(), "Upgrade started, new process pid=%d, draining current process...", process.Pid)
+			_ = process.Release()
+		}
+		break
+	}
 
 	logger.Info(context.Background(), "Received shutdown signal, stopping framework...")
 	if err := f.Stop(); err != nil {
@@ -617,7 +974,8 @@ func (f *Framework) Wait() {
 }
 
 // RegisterComponent 注册自定义组件
-func (f *Framework) RegisterComponent(component Component) error {
+// 组件按依赖顺序（WithDependsOn 或实现 ComponentDependencies）初始化与启动，逆序停止；无依赖关系的组件保持注册顺序
+func (f *Framework) RegisterComponent(component Component, opts ...ComponentOption) error {
 	if component == nil {
 		return errors.New("component is nil")
 	}
@@ -637,8 +995,15 @@ func (f *Framework) RegisterComponent(component Component) error {
 		return errors.New("cannot register component after framework initialization has started")
 	}
 
+	options := &componentOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
 	f.components[name] = component
 	f.componentOrder = append(f.componentOrder, name)
+	if len(options.dependsOn) > 0 {
+		f.componentDeps[name] = options.dependsOn
+	}
 	logger.Info(context.Background(), "Component registered: %s", name)
 	return nil
 }
@@ -707,6 +1072,38 @@ func (f *Framework) setHTTPServer(value *HTTPServer) {
 	f.httpServer = value
 }
 
+func (f *Framework) setNamedHTTPServer(name string, value *HTTPServer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.httpServers == nil {
+		f.httpServers = make(map[string]*HTTPServer)
+	}
+	f.httpServers[name] = value
+}
+
+type namedHTTPServer struct {
+	name   string
+	server *HTTPServer
+}
+
+// namedHTTPServersLocked 按名称排序返回具名 HTTP 服务器（调用方需持有 f.mu）
+func (f *Framework) namedHTTPServersLocked() []namedHTTPServer {
+	servers := make([]namedHTTPServer, 0, len(f.httpServers))
+	for _, name := range sortedHTTPServerNames(f.httpServers) {
+		servers = append(servers, namedHTTPServer{name: name, server: f.httpServers[name]})
+	}
+	return servers
+}
+
+func sortedHTTPServerNames[V any](servers map[string]V) []string {
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (f *Framework) setGormManager(value *gorm.Manager) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
@@ -725,6 +1122,12 @@ func (f *Framework) setRedisManager(value *redis.Manager) {
 	f.redisManager = value
 }
 
+func (f *Framework) setMQManager(value *mq.Manager) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.mqManager = value
+}
+
 // GetComponent 获取自定义组件
 func (f *Framework) GetComponent(name string) (Component, error) {
 	f.mu.RLock()
@@ -768,6 +1171,16 @@ func (f *Framework) HTTPServer() *HTTPServer {
 	return f.httpServer
 }
 
+// HTTPServerNamed 按名称获取 HTTP 服务器实例（DefaultHTTPServerName 返回默认服务器，未配置时为 nil）
+func (f *Framework) HTTPServerNamed(name string) *HTTPServer {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if name == DefaultHTTPServerName {
+		return f.httpServer
+	}
+	return f.httpServers[name]
+}
+
 // GormManager 获取 GORM 数据库管理器实例
 func (f *Framework) GormManager() *gorm.Manager {
 	f.mu.RLock()
@@ -775,6 +1188,48 @@ func (f *Framework) GormManager() *gorm.Manager {
 	return f.gormManager
 }
 
+// Migrator 获取指定数据库的迁移器（需配置 ConfigOptionWithMigrate 并完成 Init）
+func (f *Framework) Migrator(name string) (*migrate.Migrator, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	migrator, ok := f.migrators[name]
+	if !ok {
+		return nil, fmt.Errorf("migrator for database %s not found", name)
+	}
+	return migrator, nil
+}
+
+// RunMigrateCommand 执行迁移命令（up/down/status），用于实现 `<app> migrate ...` 命令行模式
+// database 为空时依次对所有数据库执行；命令行模式下应关闭 AutoRun
+func (f *Framework) RunMigrateCommand(ctx context.Context, database string, args []string, out io.Writer) error {
+	f.mu.RLock()
+	var migrators []*migrate.Migrator
+	if database != "" {
+		if migrator, ok := f.migrators[database]; ok {
+			migrators = append(migrators, migrator)
+		}
+	} else {
+		for _, migrator := range f.migrators {
+			migrators = append(migrators, migrator)
+		}
+	}
+	f.mu.RUnlock()
+
+	if len(migrators) == 0 {
+		if database != "" {
+			return fmt.Errorf("migrator for database %s not found", database)
+		}
+		return errors.New("no migrations configured")
+	}
+	sort.Slice(migrators, func(i, j int) bool { return migrators[i].Name() < migrators[j].Name() })
+	for _, migrator := range migrators {
+		if err := migrate.Command(ctx, migrator, args, out); err != nil {
+			return fmt.Errorf("database %s: %w", migrator.Name(), err)
+		}
+	}
+	return nil
+}
+
 // MongoManager 获取 MongoDB 数据库管理器实例
 func (f *Framework) MongoManager() *mongodb.Manager {
 	f.mu.RLock()
@@ -789,6 +1244,13 @@ func (f *Framework) RedisManager() *redis.Manager {
 	return f.redisManager
 }
 
+// MQManager 获取消息队列管理器实例
+func (f *Framework) MQManager() *mq.Manager {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.mqManager
+}
+
 // Metrics 获取框架共享的指标收集器。
 func (f *Framework) Metrics() *metrics.Metrics {
 	f.mu.RLock()
@@ -818,10 +1280,12 @@ func (f *Framework) initLogger(ctx context.Context) error {
 	}
 
 	// 构建 logger 配置
-	loggerConfig := logger.Config{
-		Level:   level,
-		Service: cfg.Service,
-		Version: cfg.Version,
+	loggerConfig := f.baseLoggerConfig(level)
+	if cfg.Service != "" {
+		loggerConfig.Service = cfg.Service
+	}
+	if cfg.Version != "" {
+		loggerConfig.Version = cfg.Version
 	}
 
 	// 设置输出方式
@@ -833,10 +1297,85 @@ func (f *Framework) initLogger(ctx context.Context) error {
 		return err
 	}
 
+	if cfg.Boost != nil && cfg.Boost.Enabled {
+		booster, err := logger.NewErrorRateBooster(cfg.Boost)
+		if err != nil {
+			return fmt.Errorf("logger boost: %w", err)
+		}
+		logger.SetDefaultBooster(booster)
+	}
+
 	f.setLogger(logger.GetDefault())
 	return nil
 }
 
+// baseLoggerConfig 返回带应用信息的 logger 配置：服务名称与版本取自 app 配置，
+// 每条日志附加 environment 与运行环境字段（hostname、pid、Kubernetes pod_name/namespace）
+func (f *Framework) baseLoggerConfig(level logger.Level) logger.Config {
+	app := f.config.App
+	fields := logger.RuntimeFields()
+	if app.Env != "" {
+		fields[logger.FieldEnvironment] = app.Env
+	}
+	return logger.Config{
+		Level:   level,
+		Service: app.Name,
+		Version: app.Version,
+		Fields:  fields,
+	}
+}
+
+// dynamicLevelTTL 临时日志级别调整的默认有效期（未配置 logger.dynamic 时为 0，即永久生效）
+func (f *Framework) dynamicLevelTTL() time.Duration {
+	if f.config.Logger == nil || f.config.Logger.Dynamic == nil {
+		return 0
+	}
+	return f.config.Logger.Dynamic.TTL.OrDefault(logger.DefaultDynamicLevelTTL)
+}
+
+// startLevelSignals 启用 logger.dynamic.signals 时监听日志级别信号
+func (f *Framework) startLevelSignals(ctx context.Context) {
+	if f.config.Logger == nil || f.config.Logger.Dynamic == nil || !f.config.Logger.Dynamic.Signals {
+		return
+	}
+	if logger.CycleLevelSignal == nil && logger.ReloadLevelSignal == nil {
+		logger.Warn(ctx, "Log level signals are not supported on this platform")
+		return
+	}
+	ttl := f.dynamicLevelTTL()
+	stop := logger.HandleLevelSignals(ttl)
+	f.mu.Lock()
+	f.stopLevelSignals = stop
+	f.mu.Unlock()
+	logger.Info(ctx, "Log level signal handling started: cycle=%v, reload=%v, ttl=%s", logger.CycleLevelSignal, logger.ReloadLevelSignal, ttl)
+}
+
+// stopLevelSignalHandling 停止日志级别信号监听
+func (f *Framework) stopLevelSignalHandling() {
+	f.mu.Lock()
+	stop := f.stopLevelSignals
+	f.stopLevelSignals = nil
+	f.mu.Unlock()
+	if stop != nil {
+		stop()
+	}
+}
+
+// initLibraryLoggers 配置第三方库（etcd、mongo、fiber）日志级别，并将 fiber 日志转发到框架 logger
+func (f *Framework) initLibraryLoggers(ctx context.Context) error {
+	if f.config.Logger != nil {
+		for library, levelStr := range f.config.Logger.Libraries {
+			level, err := logger.ParseLevel(levelStr)
+			if err != nil {
+				return fmt.Errorf("library %s: %w", library, err)
+			}
+			logger.SetLibraryLevel(library, level)
+		}
+	}
+	http.InstallFiberLogger()
+	return nil
+}
+
 // initGrpcServer 初始化 gRPC 服务器
 func (f *Framework) initGrpcServer(ctx context.Context) error {
 	server, err := NewGrpcServer(f.config.GrpcServer)
@@ -866,10 +1405,164 @@ func (f *Framework) initHTTPServer(ctx context.Context) error {
 		return err
 	}
 
+	// 运维接口先于声明式路由挂载，避免被路由表拦截
+	if err := f.registerAdminRoutes(server); err != nil {
+		return err
+	}
+	if err := f.registerDeclarativeRoutes(ctx, server); err != nil {
+		return err
+	}
+
 	f.setHTTPServer(server)
 	return nil
 }
 
+// initNamedHTTPServer 初始化具名 HTTP 服务器（声明式路由仅挂载在默认服务器上）
+func (f *Framework) initNamedHTTPServer(name string, config *HTTPServerConfig) error {
+	if len(config.Routes) > 0 {
+		return errors.New("declarative routes are only supported on the default http server")
+	}
+	server, err := NewHTTPServer(config)
+	if err != nil {
+		return err
+	}
+	if err := f.registerAdminRoutes(server); err != nil {
+		return err
+	}
+
+	f.setNamedHTTPServer(name, server)
+	return nil
+}
+
+// prepareHTTPServerConfig 为 HTTP 服务器配置注入当前环境、框架共享的指标配置与收集器
+func (f *Framework) prepareHTTPServerConfig(config *HTTPServerConfig) *HTTPServerConfig {
+	if config.env == "" && f.config.App.Env != "" {
+		cloned := *config
+		cloned.env = f.config.App.Env
+		config = &cloned
+	}
+	if f.config.Metrics != nil && config.Metrics == nil {
+		cloned := *config
+		cloned.Metrics = cloneMetricsConfig(f.config.Metrics)
+		config = &cloned
+	}
+	if f.metrics != nil {
+		cloned := *config
+		cloned.metrics = f.metrics
+		config = &cloned
+	}
+	return config
+}
+
+// registerDeclarativeRoutes 为 HTTP 服务器挂载声明式路由表并加载配置中的路由，后端服务自动注册到 gRPC Client Manager
+func (f *Framework) registerDeclarativeRoutes(ctx context.Context, server *HTTPServer) error {
+	routes := f.config.HTTPServer.Routes
+	if len(routes) == 0 && f.config.RouteSource == nil {
+		return nil
+	}
+	manager := f.GrpcClientManager()
+	if manager == nil {
+		return errors.New("declarative routes require grpc client to be configured")
+	}
+
+	table, err := (&grpcep.BaseHandler{}).NewRouteTable(server.GetApp(), grpcep.RouteOptions{
+		Resolver:        manager.TunnelResolver(),
+		AuthPolicies:    f.config.RouteAuth,
+		ValidateService: manager.ValidateService,
+	})
+	if err != nil {
+		return err
+	}
+	server.GetApp().Use(table.Handler())
+	if len(routes) > 0 {
+		if err := applyRoutes(manager, table, routes); err != nil {
+			return fmt.Errorf("failed to register declarative routes: %w", err)
+		}
+	}
+
+	f.mu.Lock()
+	f.routeTable = table
+	f.mu.Unlock()
+	logger.Info(ctx, "Declarative routes registered: count=%d", len(routes))
+	return nil
+}
+
+// ReloadRoutes 校验并原子替换声明式路由，校验失败时保留当前路由
+func (f *Framework) ReloadRoutes(routes []grpcep.RouteConfig) error {
+	f.mu.RLock()
+	table := f.routeTable
+	manager := f.grpcClientMgr
+	f.mu.RUnlock()
+	if table == nil || manager == nil {
+		return errors.New("declarative routes are not enabled")
+	}
+	return applyRoutes(manager, table, routes)
+}
+
+// RouteTable 获取声明式路由表（未启用时为 nil）
+func (f *Framework) RouteTable() *grpcep.RouteTable {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.routeTable
+}
+
+// applyRoutes 校验并注册路由引用的后端服务后替换路由表
+func applyRoutes(manager *GrpcClientManager, table *grpcep.RouteTable, routes []grpcep.RouteConfig) error {
+	for i, route := range routes {
+		if err := manager.ValidateService(route.Service); err != nil {
+			return fmt.Errorf("route[%d] %s %s: %w", i, route.Method, route.Path, err)
+		}
+	}
+	for _, route := range routes {
+		if err := manager.RegisterService(route.Service); err != nil {
+			return err
+		}
+	}
+	return table.Update(routes)
+}
+
+// startRouteWatch 启动声明式路由动态来源监听
+func (f *Framework) startRouteWatch(ctx context.Context) {
+	f.mu.Lock()
+	source := f.config.RouteSource
+	if source == nil || f.routeTable == nil || f.routeWatchCancel != nil {
+		f.mu.Unlock()
+		return
+	}
+	watchCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	f.routeWatchCancel = cancel
+	f.routeWatchDone = done
+	f.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		err := source.Watch(watchCtx, func(routes []grpcep.RouteConfig) {
+			if err := f.ReloadRoutes(routes); err != nil {
+				logger.Error(watchCtx, "Failed to reload declarative routes, keeping current routes: %v", err)
+				return
+			}
+			logger.Info(watchCtx, "Declarative routes reloaded: count=%d", len(routes))
+		})
+		if err != nil {
+			logger.Error(watchCtx, "Declarative route source stopped: %v", err)
+		}
+	}()
+	logger.Info(ctx, "Declarative route source watching started")
+}
+
+// stopRouteWatch 停止声明式路由动态来源监听并等待监听协程退出
+func (f *Framework) stopRouteWatch() {
+	f.mu.Lock()
+	cancel, done := f.routeWatchCancel, f.routeWatchDone
+	f.routeWatchCancel, f.routeWatchDone = nil, nil
+	f.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
 // initGormManager 初始化 GORM 数据库管理器
 func (f *Framework) initGormManager(ctx context.Context) error {
 	manager, err := gorm.NewManager(f.config.Gorm)
@@ -881,6 +1574,59 @@ func (f *Framework) initGormManager(ctx context.Context) error {
 	return nil
 }
 
+// initMigrations 创建各数据库的迁移器，AutoRun 时执行待执行迁移
+func (f *Framework) initMigrations(ctx context.Context) error {
+	manager := f.GormManager()
+	if manager == nil {
+		return errors.New("migrate requires gorm to be configured")
+	}
+
+	config := f.config.Migrate
+	names := config.Databases
+	explicit := len(names) > 0
+	if !explicit {
+		names = manager.ListClients()
+		sort.Strings(names)
+	}
+
+	migrators := make(map[string]*migrate.Migrator, len(names))
+	for _, name := range names {
+		db, err := manager.GetDB(name)
+		if err != nil {
+			return err
+		}
+		migrator, err := migrate.NewFromConfig(name, db, config)
+		if err != nil {
+			return fmt.Errorf("database %s: %w", name, err)
+		}
+		if len(migrator.Migrations()) == 0 && !explicit {
+			continue
+		}
+		migrators[name] = migrator
+	}
+
+	f.mu.Lock()
+	f.migrators = migrators
+	f.mu.Unlock()
+
+	if !config.AutoRun {
+		logger.Info(ctx, "Migrations loaded (auto run disabled): databases=%d", len(migrators))
+		return nil
+	}
+	for _, name := range names {
+		migrator, ok := migrators[name]
+		if !ok {
+			continue
+		}
+		done, err := migrator.Up(ctx)
+		if err != nil {
+			return fmt.Errorf("database %s: %w", name, err)
+		}
+		logger.Info(ctx, "Migrations applied: database=%s, count=%d", name, len(done))
+	}
+	return nil
+}
+
 // initMongoDBManager 初始化 MongoDB 数据库管理器
 func (f *Framework) initMongoDBManager(ctx context.Context) error {
 	manager, err := mongodb.NewManager(f.config.MongoDB)
@@ -903,6 +1649,17 @@ func (f *Framework) initRedisManager(ctx context.Context) error {
 	return nil
 }
 
+// initMQManager 初始化消息队列管理器
+func (f *Framework) initMQManager(ctx context.Context) error {
+	manager, err := mq.NewManager(f.config.MQ)
+	if err != nil {
+		return err
+	}
+	f.setMQManager(manager)
+	logger.Info(ctx, "MQ manager initialized: driver=%s", f.config.MQ.Driver)
+	return nil
+}
+
 // initTracing 初始化链路追踪
 func (f *Framework) initTracing(ctx context.Context) error {
 	if f.config.Tracing == nil {
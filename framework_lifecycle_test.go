@@ -4,12 +4,19 @@ import (
 	"context"
 	"errors"
 	"io"
+	"net"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/gofiber/fiber/v2"
+	"github.com/team-dandelion/quickgo/db/gorm"
+	"github.com/team-dandelion/quickgo/db/migrate"
+	"github.com/team-dandelion/quickgo/logger"
 	"github.com/team-dandelion/quickgo/metrics"
 )
 
@@ -48,6 +55,41 @@ func TestFrameworkLoggerFileOutput(t *testing.T) {
 	}
 }
 
+func TestFrameworkLoggerStampsAppAndRuntimeFields(t *testing.T) {
+	t.Setenv("POD_NAME", "orders-7d9f")
+	t.Setenv("POD_NAMESPACE", "shop")
+	sink := &logger.TestSink{}
+	defer logger.AddEntryHook(sink.Record)()
+
+	f, err := NewFramework(
+		ConfigOptionWithApp(AppConfig{Name: "orders", Version: "2.3.1", Env: "production"}),
+		ConfigOptionWithLogger(LoggerConfig{Enabled: true, Level: "info", Output: "console"}),
+	)
+	if err != nil {
+		t.Fatalf("NewFramework failed: %v", err)
+	}
+	if err := f.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	f.Logger().Info(context.Background(), "stamped entry")
+
+	found := sink.Find(logger.MatchMessage("stamped entry"),
+		logger.MatchField(logger.FieldEnvironment, "production"),
+		logger.MatchField(logger.FieldPID, os.Getpid()),
+		logger.MatchField(logger.FieldPodName, "orders-7d9f"),
+		logger.MatchField(logger.FieldNamespace, "shop"))
+	if len(found) != 1 {
+		t.Fatalf("expected stamped entry, got %+v", sink.Entries())
+	}
+	if found[0].Service != "orders" || found[0].Version != "2.3.1" {
+		t.Fatalf("expected service/version from app config, got %q/%q", found[0].Service, found[0].Version)
+	}
+	if hostname, _ := os.Hostname(); hostname != "" && found[0].Fields[logger.FieldHostname] != hostname {
+		t.Fatalf("expected hostname %q, got %v", hostname, found[0].Fields[logger.FieldHostname])
+	}
+}
+
 type lifecycleTestComponent struct {
 	name       string
 	enabled    bool
@@ -144,6 +186,62 @@ func TestFrameworkInitFailureRollsBackInitializedComponents(t *testing.T) {
 	}
 }
 
+func TestFrameworkStopRecordsShutdownReport(t *testing.T) {
+	var (
+		events []string
+		mu     sync.Mutex
+	)
+
+	f, err := NewFramework(
+		ConfigOptionWithLogger(LoggerConfig{Enabled: false}),
+		ConfigOptionWithHTTPServer(&HTTPServerConfig{Enabled: true, Address: "127.0.0.1", Port: reserveTCPPort(t)}),
+	)
+	if err != nil {
+		t.Fatalf("NewFramework failed: %v", err)
+	}
+	if err := f.RegisterComponent(&lifecycleTestComponent{name: "ok", enabled: true, events: &events, eventsLock: &mu}); err != nil {
+		t.Fatalf("RegisterComponent(ok) failed: %v", err)
+	}
+	if err := f.RegisterComponent(&lifecycleTestComponent{name: "broken", enabled: true, stopErr: errors.New("boom"), events: &events, eventsLock: &mu}); err != nil {
+		t.Fatalf("RegisterComponent(broken) failed: %v", err)
+	}
+	if f.ShutdownReport() != nil {
+		t.Fatal("expected no shutdown report before Stop")
+	}
+
+	if err := f.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if err := f.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := f.Stop(); err == nil {
+		t.Fatal("expected Stop to report component error")
+	}
+
+	report := f.ShutdownReport()
+	if report == nil {
+		t.Fatal("expected shutdown report after Stop")
+	}
+	var names []string
+	for _, component := range report.Components {
+		names = append(names, component.Name)
+	}
+	want := []string{"component broken", "component ok", "http server"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected report components: got %v want %v", names, want)
+	}
+	if report.Components[0].Error != "boom" || report.Components[1].Error != "" {
+		t.Fatalf("unexpected component errors: %+v", report.Components)
+	}
+	if len(report.Errors) != 1 || report.Errors[0] != "component broken: boom" {
+		t.Fatalf("unexpected report errors: %v", report.Errors)
+	}
+	if report.DurationMs < 0 || report.StartedAt.IsZero() {
+		t.Fatalf("unexpected report timing: %+v", report)
+	}
+}
+
 func TestFrameworkComponentStartStopOrderIsStable(t *testing.T) {
 	var (
 		events []string
@@ -250,6 +348,63 @@ func TestFrameworkHTTPMetricsEndpointExposesSharedGRPCMetrics(t *testing.T) {
 	}
 }
 
+func TestFrameworkRunsNamedHTTPServersWithIndependentMiddlewares(t *testing.T) {
+	tagged := func(tag string) fiber.Handler {
+		return func(c *fiber.Ctx) error {
+			c.Set("X-Server", tag)
+			return c.Next()
+		}
+	}
+	f, err := NewFramework(
+		ConfigOptionWithLogger(LoggerConfig{Enabled: false}),
+		ConfigOptionWithMetrics(&metrics.Config{Namespace: "suite"}),
+		ConfigOptionWithHTTPServer(&HTTPServerConfig{Enabled: true, Middlewares: []fiber.Handler{tagged("public")}}),
+		ConfigOptionWithNamedHTTPServer("internal", &HTTPServerConfig{
+			Enabled:     true,
+			Address:     "127.0.0.1",
+			Port:        8081,
+			Middlewares: []fiber.Handler{tagged("internal")},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewFramework failed: %v", err)
+	}
+	if err := f.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer f.Stop()
+
+	internal := f.HTTPServerNamed("internal")
+	if internal == nil || internal == f.HTTPServer() {
+		t.Fatal("expected a separate internal http server")
+	}
+	if f.HTTPServerNamed(DefaultHTTPServerName) != f.HTTPServer() {
+		t.Fatal("expected default name to resolve to the default http server")
+	}
+	if f.HTTPServerNamed("missing") != nil {
+		t.Fatal("expected unknown server name to return nil")
+	}
+	if internal.GetServer().GetAddress() != "127.0.0.1:8081" {
+		t.Fatalf("unexpected internal address: %s", internal.GetServer().GetAddress())
+	}
+	if internal.Metrics() != f.Metrics() {
+		t.Fatal("expected named http server to share framework metrics")
+	}
+
+	for server, want := range map[*HTTPServer]string{f.HTTPServer(): "public", internal: "internal"} {
+		server.GetApp().Get("/whoami", func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+		resp, err := server.GetApp().Test(httptest.NewRequest("GET", "/whoami", nil))
+		if err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+		if got := resp.Header.Get("X-Server"); got != want {
+			t.Fatalf("expected middleware %s, got %q", want, got)
+		}
+	}
+}
+
 type frameworkAccessComponent struct {
 	name      string
 	enabled   bool
@@ -294,3 +449,245 @@ func TestFrameworkLifecycleAllowsComponentCallbacksToAccessFramework(t *testing.
 		t.Fatalf("Stop failed: %v", err)
 	}
 }
+
+func TestFrameworkRunsMigrationsOnInit(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "migrations", "main"), 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "migrations", "main", "1_create_notes.up.sql"), []byte("CREATE TABLE notes (id INTEGER PRIMARY KEY);\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	f, err := NewFramework(
+		ConfigOptionWithGorm(&gorm.GormManagerConfig{Databases: []gorm.GormConfig{{
+			Name:   "main",
+			Master: gorm.MasterConfig{Type: gorm.DatabaseTypeSQLite, Database: filepath.Join(dir, "app.db")},
+		}}}),
+		ConfigOptionWithMigrate(&migrate.Config{AutoRun: true, Dir: filepath.Join(dir, "migrations")}),
+	)
+	if err != nil {
+		t.Fatalf("NewFramework failed: %v", err)
+	}
+	if err := f.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer f.Stop()
+
+	db, _ := f.GormManager().GetDB("main")
+	if !db.Migrator().HasTable("notes") {
+		t.Fatalf("expected migration to create notes table on Init")
+	}
+
+	var out strings.Builder
+	if err := f.RunMigrateCommand(context.Background(), "", []string{"status"}, &out); err != nil {
+		t.Fatalf("RunMigrateCommand failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "create_notes") || !strings.Contains(out.String(), "applied") {
+		t.Fatalf("unexpected status output: %s", out.String())
+	}
+}
+
+func reserveTCPPort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to reserve tcp port: %v", err)
+	}
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+type dependentTestComponent struct {
+	*lifecycleTestComponent
+	deps []string
+}
+
+func (c *dependentTestComponent) DependsOn() []string { return c.deps }
+
+func TestFrameworkOrdersComponentsByDependencies(t *testing.T) {
+	var (
+		events []string
+		mu     sync.Mutex
+	)
+	f, err := NewFramework(ConfigOptionWithLogger(LoggerConfig{Enabled: false}))
+	if err != nil {
+		t.Fatalf("NewFramework failed: %v", err)
+	}
+	newComponent := func(name string) *lifecycleTestComponent {
+		return &lifecycleTestComponent{name: name, enabled: true, events: &events, eventsLock: &mu}
+	}
+	// 注册顺序与依赖顺序相反：warmer -> store -> client
+	if err := f.RegisterComponent(&dependentTestComponent{lifecycleTestComponent: newComponent("warmer"), deps: []string{"store"}}); err != nil {
+		t.Fatalf("RegisterComponent(warmer) failed: %v", err)
+	}
+	if err := f.RegisterComponent(newComponent("audit")); err != nil {
+		t.Fatalf("RegisterComponent(audit) failed: %v", err)
+	}
+	if err := f.RegisterComponent(newComponent("store"), WithDependsOn("client", ComponentLogger)); err != nil {
+		t.Fatalf("RegisterComponent(store) failed: %v", err)
+	}
+	if err := f.RegisterComponent(newComponent("client")); err != nil {
+		t.Fatalf("RegisterComponent(client) failed: %v", err)
+	}
+
+	order, err := f.ComponentOrder()
+	if err != nil || strings.Join(order, ",") != "audit,client,store,warmer" {
+		t.Fatalf("unexpected component order %v, err=%v", order, err)
+	}
+	if err := f.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if err := f.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := f.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	want := []string{
+		"init:audit", "init:client", "init:store", "init:warmer",
+		"start:audit", "start:client", "start:store", "start:warmer",
+		"stop:warmer", "stop:store", "stop:client", "stop:audit",
+	}
+	if strings.Join(events, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected lifecycle order: got %v want %v", events, want)
+	}
+}
+
+func TestFrameworkRejectsInvalidComponentDependencies(t *testing.T) {
+	cases := []struct {
+		name     string
+		register func(f *Framework, events *[]string)
+		want     string
+	}{
+		{
+			name: "cycle",
+			register: func(f *Framework, events *[]string) {
+				_ = f.RegisterComponent(&lifecycleTestComponent{name: "a", enabled: true, events: events}, WithDependsOn("b"))
+				_ = f.RegisterComponent(&lifecycleTestComponent{name: "b", enabled: true, events: events}, WithDependsOn("c"))
+				_ = f.RegisterComponent(&lifecycleTestComponent{name: "c", enabled: true, events: events}, WithDependsOn("a"))
+			},
+			want: "component dependency cycle: a -> b -> c -> a",
+		},
+		{
+			name: "unknown",
+			register: func(f *Framework, events *[]string) {
+				_ = f.RegisterComponent(&lifecycleTestComponent{name: "a", enabled: true, events: events}, WithDependsOn("missing"))
+			},
+			want: "component a depends on unknown component missing",
+		},
+		{
+			name: "builtin not configured",
+			register: func(f *Framework, events *[]string) {
+				_ = f.RegisterComponent(&lifecycleTestComponent{name: "cache-warmer", enabled: true, events: events}, WithDependsOn(ComponentRedis))
+			},
+			want: "component cache-warmer depends on redis which is not configured",
+		},
+		{
+			name: "disabled",
+			register: func(f *Framework, events *[]string) {
+				_ = f.RegisterComponent(&lifecycleTestComponent{name: "a", enabled: true, events: events}, WithDependsOn("b"))
+				_ = f.RegisterComponent(&lifecycleTestComponent{name: "b", enabled: false, events: events})
+			},
+			want: "component a depends on disabled component b",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var events []string
+			f, err := NewFramework(ConfigOptionWithLogger(LoggerConfig{Enabled: false}))
+			if err != nil {
+				t.Fatalf("NewFramework failed: %v", err)
+			}
+			tc.register(f, &events)
+			err = f.Init()
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+			if len(events) != 0 {
+				t.Fatalf("expected no component to be initialized, got %v", events)
+			}
+		})
+	}
+}
+
+func TestFrameworkLifecycleHooks(t *testing.T) {
+	var (
+		events []string
+		mu     sync.Mutex
+	)
+	record := func(event string) HookFunc {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, event)
+			return nil
+		}
+	}
+	f, err := NewFramework(ConfigOptionWithLogger(LoggerConfig{Enabled: false}))
+	if err != nil {
+		t.Fatalf("NewFramework failed: %v", err)
+	}
+	if err := f.RegisterComponent(&lifecycleTestComponent{name: "store", enabled: true, events: &events, eventsLock: &mu}); err != nil {
+		t.Fatalf("RegisterComponent failed: %v", err)
+	}
+	f.OnBeforeStart(record("before-start"))
+	f.OnAfterStart(record("after-start"))
+	f.OnBeforeStop(record("before-stop:1"))
+	f.OnBeforeStop(record("before-stop:2"), WithHookName("flush"))
+
+	if err := f.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if err := f.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := f.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	want := []string{"init:store", "before-start", "start:store", "after-start", "before-stop:2", "before-stop:1", "stop:store"}
+	if strings.Join(events, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected lifecycle order: got %v want %v", events, want)
+	}
+	if report := f.ShutdownReport(); report == nil || report.Components[0].Name != "flush" {
+		t.Fatalf("expected before stop hooks in shutdown report, got %+v", report)
+	}
+}
+
+func TestFrameworkHookErrorsAreAggregated(t *testing.T) {
+	var events []string
+	f, err := NewFramework(ConfigOptionWithLogger(LoggerConfig{Enabled: false}))
+	if err != nil {
+		t.Fatalf("NewFramework failed: %v", err)
+	}
+	if err := f.RegisterComponent(&lifecycleTestComponent{name: "store", enabled: true, events: &events}); err != nil {
+		t.Fatalf("RegisterComponent failed: %v", err)
+	}
+	f.OnBeforeStart(func(ctx context.Context) error { return errors.New("config missing") }, WithHookName("check"))
+	f.OnBeforeStart(func(ctx context.Context) error {
+		<-time.After(time.Second)
+		return nil
+	}, WithHookName("slow"), WithHookTimeout(20*time.Millisecond))
+	f.OnBeforeStart(func(ctx context.Context) error { panic("boom") }, WithHookName("panicky"))
+
+	if err := f.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	err = f.Start()
+	if err == nil {
+		t.Fatal("expected Start to fail")
+	}
+	for _, want := range []string{"check: config missing", "slow: timed out", "panicky: panic: boom"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error to contain %q, got %v", want, err)
+		}
+	}
+	if strings.Join(events, ",") != "init:store" {
+		t.Fatalf("expected no component to start, got %v", events)
+	}
+	if err := f.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package gerr
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+)
+
+// ErrorCode 错误码定义，服务在初始化时通过 Register / MustRegister 统一声明
+type ErrorCode struct {
+	// 错误码（不能为 0）
+	Code int32
+	// 错误类型
+	Type ErrorType
+	// 默认错误消息
+	Msg string
+	// 对应的 gRPC code（为 OK 时按 Type 推导）
+	GRPCCode codes.Code
+	// 对应的 HTTP 状态码（为 0 时按 Type 推导）
+	HTTPStatus int
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[int32]*ErrorCode)
+)
+
+// Register 注册错误码，错误码为 0 或已注册时返回错误
+func Register(def ErrorCode) (*ErrorCode, error) {
+	if def.Code == 0 {
+		return nil, fmt.Errorf("gerr: error code must not be 0")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if existing, ok := registry[def.Code]; ok {
+		return nil, fmt.Errorf("gerr: error code %d already registered (%s)", def.Code, existing.Msg)
+	}
+	code := def
+	registry[def.Code] = &code
+	return &code, nil
+}
+
+// MustRegister 注册错误码，失败时 panic，适用于包级变量声明：
+//
+//	var ErrUserNotFound = gerr.MustRegister(gerr.ErrorCode{Code: 40401, Type: gerr.TypeNotFound, Msg: "user not found"})
+func MustRegister(def ErrorCode) *ErrorCode {
+	code, err := Register(def)
+	if err != nil {
+		panic(err)
+	}
+	return code
+}
+
+// Lookup 查找已注册的错误码
+func Lookup(code int32) (*ErrorCode, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	def, ok := registry[code]
+	return def, ok
+}
+
+// IsRegistered 判断错误的错误码是否已注册
+func IsRegistered(err error) bool {
+	gErr := asGErr(err)
+	if gErr == nil {
+		return false
+	}
+	_, ok := Lookup(gErr.Code)
+	return ok
+}
+
+// RegisteredCodes 返回全部已注册的错误码（按错误码排序），可用于生成错误码文档
+func RegisteredCodes() []ErrorCode {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	defs := make([]ErrorCode, 0, len(registry))
+	for _, def := range registry {
+		defs = append(defs, *def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Code < defs[j].Code })
+	return defs
+}
+
+// New 使用默认消息创建错误
+func (c *ErrorCode) New() *GErr {
+	return &GErr{Code: c.Code, Msg: c.Msg, Type: c.Type, Stack: captureStack(2)}
+}
+
+// Newf 使用格式化消息创建错误
+func (c *ErrorCode) Newf(format string, args ...interface{}) *GErr {
+	return &GErr{Code: c.Code, Msg: fmt.Sprintf(format, args...), Type: c.Type, Stack: captureStack(2)}
+}
+
+// Wrap 包装已有错误（使用默认消息）
+func (c *ErrorCode) Wrap(err error) *GErr {
+	if err == nil {
+		return nil
+	}
+	return &GErr{Code: c.Code, Msg: c.Msg, Type: c.Type, Cause: err, Stack: captureStack(2)}
+}
@@ -0,0 +1,236 @@
+package gerr
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// ErrorDomain ToGRPCStatus 附加的 google.rpc.ErrorInfo 的 domain，FromGRPCStatus 据此还原错误
+	ErrorDomain = "quickgo.gerr"
+	// MetadataKeyCode ErrorInfo 中记录错误码的元数据 key
+	MetadataKeyCode = "code"
+	// MetadataKeyType ErrorInfo 中记录错误类型的元数据 key
+	MetadataKeyType = "type"
+)
+
+// asGErr 从错误链中查找 GErr，不存在时返回 nil（不会像 Parse 一样包装普通错误）
+func asGErr(err error) *GErr {
+	var gErr *GErr
+	if errors.As(err, &gErr) {
+		return gErr
+	}
+	return nil
+}
+
+// ToGRPCStatus 将错误转换为 gRPC status
+// GErr 的 gRPC code 取注册的 GRPCCode（未注册或未指定时按错误类型推导），错误码、类型与元数据
+// 以 google.rpc.ErrorInfo 详情携带，对端通过 FromGRPCStatus 还原；已是 status 的错误原样返回
+func ToGRPCStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+	gErr := asGErr(err)
+	if gErr == nil {
+		if st, ok := status.FromError(err); ok {
+			return st
+		}
+		return status.FromContextError(err)
+	}
+
+	metadata := make(map[string]string, len(gErr.Metadata)+2)
+	for k, v := range gErr.Metadata {
+		metadata[k] = v
+	}
+	metadata[MetadataKeyCode] = strconv.FormatInt(int64(gErr.Code), 10)
+	metadata[MetadataKeyType] = gErr.Type.String()
+
+	st := status.New(GRPCCode(gErr), gErr.Msg)
+	withDetails, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   gErr.Type.String(),
+		Domain:   ErrorDomain,
+		Metadata: metadata,
+	})
+	if detailErr != nil {
+		return st
+	}
+	return withDetails
+}
+
+// FromGRPCStatus 将 gRPC status 还原为 GErr，status 为 nil 或 OK 时返回 nil
+// 由 ToGRPCStatus 生成的 status 还原错误码、类型与元数据；其他 status 按 gRPC code 推导错误类型，错误码为 0
+func FromGRPCStatus(st *status.Status) *GErr {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+	gErr := &GErr{
+		Msg:   st.Message(),
+		Type:  typeFromGRPCCode(st.Code()),
+		Cause: st.Err(),
+	}
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != ErrorDomain {
+			continue
+		}
+		for k, v := range info.GetMetadata() {
+			switch k {
+			case MetadataKeyCode:
+				if code, err := strconv.ParseInt(v, 10, 32); err == nil {
+					gErr.Code = int32(code)
+				}
+			case MetadataKeyType:
+				gErr.Type = parseErrorType(v)
+			default:
+				gErr.WithMetadata(k, v)
+			}
+		}
+		break
+	}
+	return gErr
+}
+
+// GRPCCode 获取错误对应的 gRPC code
+func GRPCCode(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+	gErr := asGErr(err)
+	if gErr == nil {
+		return ToGRPCStatus(err).Code()
+	}
+	if def, ok := Lookup(gErr.Code); ok && def.GRPCCode != codes.OK {
+		return def.GRPCCode
+	}
+	return grpcCodeForType(gErr.Type)
+}
+
+// HTTPStatus 获取错误对应的 HTTP 状态码
+// GErr 取注册的 HTTPStatus（未注册或未指定时按错误类型推导），gRPC status 错误按 gRPC code 映射，其余错误为 500
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	if gErr := asGErr(err); gErr != nil {
+		if def, ok := Lookup(gErr.Code); ok && def.HTTPStatus != 0 {
+			return def.HTTPStatus
+		}
+		return httpStatusForType(gErr.Type)
+	}
+	if st, ok := status.FromError(err); ok {
+		return httpStatusForGRPCCode(st.Code())
+	}
+	return http.StatusInternalServerError
+}
+
+func grpcCodeForType(t ErrorType) codes.Code {
+	switch t {
+	case TypeBusiness:
+		return codes.FailedPrecondition
+	case TypeValidation:
+		return codes.InvalidArgument
+	case TypeNotFound:
+		return codes.NotFound
+	case TypeUnauthorized:
+		return codes.Unauthenticated
+	case TypeForbidden:
+		return codes.PermissionDenied
+	case TypeInternal, TypeDatabase:
+		return codes.Internal
+	case TypeNetwork, TypeThirdParty:
+		return codes.Unavailable
+	case TypeTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Unknown
+	}
+}
+
+func typeFromGRPCCode(code codes.Code) ErrorType {
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return TypeValidation
+	case codes.NotFound:
+		return TypeNotFound
+	case codes.Unauthenticated:
+		return TypeUnauthorized
+	case codes.PermissionDenied:
+		return TypeForbidden
+	case codes.AlreadyExists, codes.FailedPrecondition, codes.Aborted:
+		return TypeBusiness
+	case codes.DeadlineExceeded, codes.Canceled:
+		return TypeTimeout
+	case codes.Unavailable, codes.ResourceExhausted:
+		return TypeNetwork
+	case codes.Unknown:
+		return TypeUnknown
+	default:
+		return TypeInternal
+	}
+}
+
+func httpStatusForType(t ErrorType) int {
+	switch t {
+	case TypeBusiness, TypeValidation:
+		return http.StatusBadRequest
+	case TypeNotFound:
+		return http.StatusNotFound
+	case TypeUnauthorized:
+		return http.StatusUnauthorized
+	case TypeForbidden:
+		return http.StatusForbidden
+	case TypeNetwork:
+		return http.StatusServiceUnavailable
+	case TypeTimeout:
+		return http.StatusGatewayTimeout
+	case TypeThirdParty:
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// httpStatusForGRPCCode gRPC code 与 HTTP 状态码的对应关系（与 grpc-gateway 一致）
+func httpStatusForGRPCCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseErrorType 解析 ErrorType.String() 的结果
+func parseErrorType(s string) ErrorType {
+	for t := TypeUnknown; t <= TypeThirdParty; t++ {
+		if t.String() == s {
+			return t
+		}
+	}
+	return TypeUnknown
+}
@@ -0,0 +1,75 @@
+package gerr
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRegisterRejectsDuplicateAndZeroCodes(t *testing.T) {
+	if _, err := Register(ErrorCode{Code: 0, Msg: "zero"}); err == nil {
+		t.Fatal("expected zero code to be rejected")
+	}
+	if _, err := Register(ErrorCode{Code: 91001, Type: TypeBusiness, Msg: "first"}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if _, err := Register(ErrorCode{Code: 91001, Msg: "second"}); err == nil {
+		t.Fatal("expected duplicate code to be rejected")
+	}
+	if def, ok := Lookup(91001); !ok || def.Msg != "first" {
+		t.Fatalf("unexpected lookup result %+v, %v", def, ok)
+	}
+}
+
+func TestGRPCStatusRoundTrip(t *testing.T) {
+	def := MustRegister(ErrorCode{Code: 91002, Type: TypeNotFound, Msg: "user not found", HTTPStatus: http.StatusGone})
+	original := def.New().WithMetadata("user_id", "42")
+
+	st := ToGRPCStatus(original)
+	if st.Code() != codes.NotFound || st.Message() != "user not found" {
+		t.Fatalf("unexpected status %v", st)
+	}
+
+	restored := FromGRPCStatus(status.Convert(st.Err()))
+	if restored.Code != 91002 || restored.Type != TypeNotFound || restored.Msg != "user not found" {
+		t.Fatalf("unexpected restored error %+v", restored)
+	}
+	if restored.GetMetadataValue("user_id") != "42" {
+		t.Fatalf("expected metadata to round-trip, got %v", restored.Metadata)
+	}
+	if !errors.Is(restored, original) {
+		t.Fatal("expected restored error to match original code")
+	}
+	if got := HTTPStatus(restored); got != http.StatusGone {
+		t.Fatalf("expected registered HTTP status, got %d", got)
+	}
+}
+
+func TestStatusMappingForUnregisteredErrors(t *testing.T) {
+	if got := GRPCCode(NewValidation(91003, "bad")); got != codes.InvalidArgument {
+		t.Fatalf("unexpected gRPC code %v", got)
+	}
+	if got := HTTPStatus(NewUnauthorized(91004, "login")); got != http.StatusUnauthorized {
+		t.Fatalf("unexpected HTTP status %d", got)
+	}
+	if got := HTTPStatus(status.Error(codes.ResourceExhausted, "slow down")); got != http.StatusTooManyRequests {
+		t.Fatalf("unexpected HTTP status for status error %d", got)
+	}
+	if got := HTTPStatus(errors.New("boom")); got != http.StatusInternalServerError {
+		t.Fatalf("unexpected HTTP status for plain error %d", got)
+	}
+
+	plain := FromGRPCStatus(status.New(codes.PermissionDenied, "denied"))
+	if plain.Code != 0 || plain.Type != TypeForbidden || plain.Msg != "denied" {
+		t.Fatalf("unexpected error from plain status %+v", plain)
+	}
+	if FromGRPCStatus(status.New(codes.OK, "")) != nil {
+		t.Fatal("expected nil for OK status")
+	}
+	if st := ToGRPCStatus(status.Error(codes.Aborted, "conflict")); st.Code() != codes.Aborted {
+		t.Fatalf("expected status errors to pass through, got %v", st.Code())
+	}
+}
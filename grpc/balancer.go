@@ -3,6 +3,7 @@ package grpc
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 
 	"google.golang.org/grpc"
@@ -39,13 +40,12 @@ type WeightedAddress struct {
 	Weight  int // 权重，默认为 1
 }
 
-// weightedRoundRobinBuilder 加权轮询构建器（简化实现，使用轮询策略）
+// weightedRoundRobinBuilder 加权轮询构建器（权重来自地址上的实例元数据，见 InstanceFromAddress）
 type weightedRoundRobinBuilder struct{}
 
 // Build 构建负载均衡器
 func (b *weightedRoundRobinBuilder) Build(cc balancer.ClientConn, opts balancer.BuildOptions) balancer.Balancer {
-	// 使用 base 包构建轮询负载均衡器
-	return base.NewBalancerBuilder(WeightedRoundRobinBalancer, &roundRobinPickerBuilder{}, base.Config{
+	return base.NewBalancerBuilder(WeightedRoundRobinBalancer, &weightedPickerBuilder{}, base.Config{
 		HealthCheck: true,
 	}).Build(cc, opts)
 }
@@ -55,38 +55,49 @@ func (b *weightedRoundRobinBuilder) Name() string {
 	return WeightedRoundRobinBalancer
 }
 
-// roundRobinPickerBuilder 轮询选择器构建器
-type roundRobinPickerBuilder struct{}
+// weightedPickerBuilder 加权轮询选择器构建器
+type weightedPickerBuilder struct{}
 
 // Build 构建选择器
-func (b *roundRobinPickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+func (b *weightedPickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
 	if len(info.ReadySCs) == 0 {
 		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
 	}
+	return newWeightedPicker(info.ReadySCs)
+}
 
-	// 构建轮询选择器
-	scs := make([]balancer.SubConn, 0, len(info.ReadySCs))
-	for sc := range info.ReadySCs {
-		scs = append(scs, sc)
+// newWeightedPicker 使用就绪子连接创建加权轮询选择器
+func newWeightedPicker(readySCs map[balancer.SubConn]base.SubConnInfo) *weightedPicker {
+	conns := make([]*weightedSubConn, 0, len(readySCs))
+	for sc, scInfo := range readySCs {
+		conns = append(conns, &weightedSubConn{
+			subConn: sc,
+			addr:    scInfo.Address.Addr,
+			weight:  addressWeight(scInfo.Address),
+		})
 	}
+	// ReadySCs 为 map，按地址排序保证选择顺序稳定
+	sort.Slice(conns, func(i, j int) bool { return conns[i].addr < conns[j].addr })
 
-	// 使用简单的轮询选择器
-	return &roundRobinPicker{
-		subConns: scs,
-		next:     0,
-		mu:       sync.Mutex{},
-	}
+	return &weightedPicker{subConns: conns}
 }
 
-// roundRobinPicker 轮询选择器
-type roundRobinPicker struct {
-	subConns []balancer.SubConn
-	next     int
+// weightedSubConn 带权重的子连接
+type weightedSubConn struct {
+	subConn balancer.SubConn
+	addr    string
+	weight  int
+	current int
+}
+
+// weightedPicker 平滑加权轮询选择器（与 nginx 相同的算法，权重相同时退化为轮询）
+type weightedPicker struct {
+	subConns []*weightedSubConn
 	mu       sync.Mutex
 }
 
 // Pick 选择连接
-func (p *roundRobinPicker) Pick(info balancer.PickInfo) (balancer.PickResult, error) {
+func (p *weightedPicker) Pick(info balancer.PickInfo) (balancer.PickResult, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -94,11 +105,19 @@ func (p *roundRobinPicker) Pick(info balancer.PickInfo) (balancer.PickResult, er
 		return balancer.PickResult{}, fmt.Errorf("no subconnections available")
 	}
 
-	sc := p.subConns[p.next]
-	p.next = (p.next + 1) % len(p.subConns)
+	total := 0
+	var best *weightedSubConn
+	for _, sc := range p.subConns {
+		sc.current += sc.weight
+		total += sc.weight
+		if best == nil || sc.current > best.current {
+			best = sc
+		}
+	}
+	best.current -= total
 
 	return balancer.PickResult{
-		SubConn: sc,
+		SubConn: best.subConn,
 	}, nil
 }
 
@@ -0,0 +1,197 @@
+package grpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/serviceconfig"
+)
+
+// CanaryBalancer 灰度（按版本路由）负载均衡器
+const CanaryBalancer = "quickgo_canary"
+
+// CanaryRule 灰度路由规则：将部分流量路由到注册元数据 version 匹配的实例
+// 请求 metadata 命中 Header 时始终路由到灰度实例，否则按 Percent 随机路由；
+// 灰度实例不可用时回退到稳定实例，稳定实例不可用时使用灰度实例
+type CanaryRule struct {
+	// 灰度实例版本（匹配注册元数据 version），示例：2.0.0
+	Version string `json:"version"`
+	// 路由到灰度实例的流量百分比（0-100）
+	Percent float64 `json:"percent"`
+	// 请求 metadata 键（可选），示例：x-canary
+	Header string `json:"header,omitempty"`
+	// 请求 metadata 值（可选），为空时只要携带 Header 即路由到灰度实例
+	HeaderValue string `json:"headerValue,omitempty"`
+}
+
+// Validate 校验灰度规则
+func (r CanaryRule) Validate() error {
+	if r.Version == "" {
+		return errors.New("canary version is required")
+	}
+	if r.Percent < 0 || r.Percent > 100 {
+		return fmt.Errorf("canary percent must be between 0 and 100, got %v", r.Percent)
+	}
+	if r.Percent == 0 && r.Header == "" {
+		return errors.New("canary rule requires percent or header")
+	}
+	return nil
+}
+
+// matchHeader 判断请求 metadata 是否命中灰度规则
+func (r *CanaryRule) matchHeader(md metadata.MD) bool {
+	if r.Header == "" {
+		return false
+	}
+	values := md.Get(r.Header)
+	if len(values) == 0 {
+		return false
+	}
+	if r.HeaderValue == "" {
+		return true
+	}
+	for _, value := range values {
+		if value == r.HeaderValue {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCanaryOption 获取灰度路由负载均衡选项
+func GetCanaryOption(rule CanaryRule) (grpc.DialOption, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	config, err := json.Marshal(map[string][]map[string]CanaryRule{
+		"loadBalancingConfig": {{CanaryBalancer: rule}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal canary config: %w", err)
+	}
+	return grpc.WithDefaultServiceConfig(string(config)), nil
+}
+
+func init() {
+	balancer.Register(&canaryBuilder{})
+}
+
+// canaryConfig 灰度负载均衡器配置（来自 service config）
+type canaryConfig struct {
+	serviceconfig.LoadBalancingConfig
+	CanaryRule
+}
+
+// canaryBuilder 灰度负载均衡器构建器
+type canaryBuilder struct{}
+
+// Build 构建负载均衡器
+func (b *canaryBuilder) Build(cc balancer.ClientConn, opts balancer.BuildOptions) balancer.Balancer {
+	pickerBuilder := &canaryPickerBuilder{}
+	return &canaryBalancer{
+		Balancer: base.NewBalancerBuilder(CanaryBalancer, pickerBuilder, base.Config{
+			HealthCheck: true,
+		}).Build(cc, opts),
+		pickerBuilder: pickerBuilder,
+	}
+}
+
+// Name 返回名称
+func (b *canaryBuilder) Name() string {
+	return CanaryBalancer
+}
+
+// ParseConfig 解析 service config 中的灰度规则
+func (b *canaryBuilder) ParseConfig(raw json.RawMessage) (serviceconfig.LoadBalancingConfig, error) {
+	config := &canaryConfig{}
+	if err := json.Unmarshal(raw, &config.CanaryRule); err != nil {
+		return nil, fmt.Errorf("invalid canary config: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	config.Header = strings.ToLower(config.Header)
+	return config, nil
+}
+
+// canaryBalancer 在 base 负载均衡器的基础上记录灰度规则
+type canaryBalancer struct {
+	balancer.Balancer
+	pickerBuilder *canaryPickerBuilder
+}
+
+// UpdateClientConnState 更新灰度规则与地址（规则在下一次子连接状态变化重建选择器时生效）
+func (b *canaryBalancer) UpdateClientConnState(state balancer.ClientConnState) error {
+	if config, ok := state.BalancerConfig.(*canaryConfig); ok {
+		rule := config.CanaryRule
+		b.pickerBuilder.rule.Store(&rule)
+	}
+	return b.Balancer.UpdateClientConnState(state)
+}
+
+// canaryPickerBuilder 灰度选择器构建器
+type canaryPickerBuilder struct {
+	rule atomic.Pointer[CanaryRule]
+}
+
+// Build 构建选择器：按实例版本拆分为灰度与稳定两组，组内加权轮询
+func (b *canaryPickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+	rule := b.rule.Load()
+	if rule == nil {
+		return newWeightedPicker(info.ReadySCs)
+	}
+
+	canary := make(map[balancer.SubConn]base.SubConnInfo)
+	stable := make(map[balancer.SubConn]base.SubConnInfo)
+	for sc, scInfo := range info.ReadySCs {
+		if inst, ok := InstanceFromAddress(scInfo.Address); ok && inst.Version == rule.Version {
+			canary[sc] = scInfo
+		} else {
+			stable[sc] = scInfo
+		}
+	}
+	if len(canary) == 0 || len(stable) == 0 {
+		return newWeightedPicker(info.ReadySCs)
+	}
+	return &canaryPicker{
+		rule:   rule,
+		canary: newWeightedPicker(canary),
+		stable: newWeightedPicker(stable),
+	}
+}
+
+// canaryPicker 灰度选择器
+type canaryPicker struct {
+	rule   *CanaryRule
+	canary balancer.Picker
+	stable balancer.Picker
+}
+
+// Pick 选择连接
+func (p *canaryPicker) Pick(info balancer.PickInfo) (balancer.PickResult, error) {
+	if p.routeToCanary(info) {
+		return p.canary.Pick(info)
+	}
+	return p.stable.Pick(info)
+}
+
+// routeToCanary 判断本次调用是否路由到灰度实例
+func (p *canaryPicker) routeToCanary(info balancer.PickInfo) bool {
+	if info.Ctx != nil {
+		if md, ok := metadata.FromOutgoingContext(info.Ctx); ok && p.rule.matchHeader(md) {
+			return true
+		}
+	}
+	return p.rule.Percent > 0 && rand.Float64()*100 < p.rule.Percent
+}
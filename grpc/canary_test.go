@@ -0,0 +1,107 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+func canaryReadySCs() (map[balancer.SubConn]base.SubConnInfo, *fakeSubConn, *fakeSubConn) {
+	stable, canary := &fakeSubConn{name: "stable"}, &fakeSubConn{name: "canary"}
+	addrs := instanceAddresses([]ServiceInfo{
+		{Address: "10.0.0.1:9000", Metadata: map[string]string{MetadataVersion: "1.0.0"}},
+		{Address: "10.0.0.2:9000", Metadata: map[string]string{MetadataVersion: "2.0.0"}},
+	})
+	return map[balancer.SubConn]base.SubConnInfo{
+		stable: {Address: addrs[0]},
+		canary: {Address: addrs[1]},
+	}, stable, canary
+}
+
+func buildCanaryPicker(t *testing.T, raw string, readySCs map[balancer.SubConn]base.SubConnInfo) balancer.Picker {
+	t.Helper()
+	config, err := (&canaryBuilder{}).ParseConfig([]byte(raw))
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	builder := &canaryPickerBuilder{}
+	rule := config.(*canaryConfig).CanaryRule
+	builder.rule.Store(&rule)
+	return builder.Build(base.PickerBuildInfo{ReadySCs: readySCs})
+}
+
+func pickName(t *testing.T, picker balancer.Picker, ctx context.Context) string {
+	t.Helper()
+	result, err := picker.Pick(balancer.PickInfo{Ctx: ctx})
+	if err != nil {
+		t.Fatalf("Pick failed: %v", err)
+	}
+	return result.SubConn.(*fakeSubConn).name
+}
+
+func TestCanaryParseConfigValidates(t *testing.T) {
+	for _, raw := range []string{`{"percent":10}`, `{"version":"2.0.0","percent":120}`, `{"version":"2.0.0"}`, `{`} {
+		if _, err := (&canaryBuilder{}).ParseConfig([]byte(raw)); err == nil {
+			t.Fatalf("expected %s to be rejected", raw)
+		}
+	}
+	option, err := GetCanaryOption(CanaryRule{Version: "2.0.0", Percent: 5})
+	if err != nil {
+		t.Fatalf("GetCanaryOption failed: %v", err)
+	}
+	conn, err := grpc.NewClient("passthrough:///127.0.0.1:1", option, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("expected canary service config to be accepted: %v", err)
+	}
+	conn.Close()
+}
+
+func TestCanaryPickerRoutesByHeader(t *testing.T) {
+	readySCs, _, _ := canaryReadySCs()
+	picker := buildCanaryPicker(t, `{"version":"2.0.0","header":"X-Canary","headerValue":"on"}`, readySCs)
+
+	for i := 0; i < 5; i++ {
+		if name := pickName(t, picker, context.Background()); name != "stable" {
+			t.Fatalf("expected requests without header to use stable instances, got %s", name)
+		}
+	}
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-canary", "on")
+	if name := pickName(t, picker, ctx); name != "canary" {
+		t.Fatalf("expected header to route to canary, got %s", name)
+	}
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "x-canary", "off")
+	if name := pickName(t, picker, ctx); name != "stable" {
+		t.Fatalf("expected mismatched header value to use stable instances, got %s", name)
+	}
+}
+
+func TestCanaryPickerRoutesByPercent(t *testing.T) {
+	readySCs, _, _ := canaryReadySCs()
+	picker := buildCanaryPicker(t, `{"version":"2.0.0","percent":100}`, readySCs)
+	if name := pickName(t, picker, context.Background()); name != "canary" {
+		t.Fatalf("expected 100%% canary traffic, got %s", name)
+	}
+
+	picker = buildCanaryPicker(t, `{"version":"2.0.0","percent":30}`, readySCs)
+	counts := make(map[string]int)
+	for i := 0; i < 2000; i++ {
+		counts[pickName(t, picker, context.Background())]++
+	}
+	if counts["canary"] < 400 || counts["canary"] > 800 {
+		t.Fatalf("expected roughly 30%% canary traffic, got %v", counts)
+	}
+}
+
+func TestCanaryPickerFallsBackWithoutCanaryInstances(t *testing.T) {
+	readySCs, _, canary := canaryReadySCs()
+	delete(readySCs, canary)
+	picker := buildCanaryPicker(t, `{"version":"2.0.0","percent":100}`, readySCs)
+	if name := pickName(t, picker, context.Background()); name != "stable" {
+		t.Fatalf("expected fallback to stable instances, got %s", name)
+	}
+}
@@ -0,0 +1,328 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// InterceptorClass 拦截器优先级分类，数值越小越靠外层（越先执行）
+type InterceptorClass int
+
+const (
+	// ClassObservability 可观测性（tracing、日志、指标、panic 恢复）
+	ClassObservability InterceptorClass = iota
+	// ClassAuth 认证鉴权
+	ClassAuth
+	// ClassTraffic 流量治理（限流、熔断、超时）
+	ClassTraffic
+	// ClassBusiness 业务拦截器
+	ClassBusiness
+)
+
+// String 返回分类名称
+func (c InterceptorClass) String() string {
+	switch c {
+	case ClassObservability:
+		return "observability"
+	case ClassAuth:
+		return "auth"
+	case ClassTraffic:
+		return "traffic"
+	case ClassBusiness:
+		return "business"
+	default:
+		return fmt.Sprintf("class(%d)", int(c))
+	}
+}
+
+// InterceptorSpec 命名拦截器定义
+type InterceptorSpec struct {
+	// 拦截器名称（链内唯一）
+	Name string
+	// 优先级分类
+	Class InterceptorClass
+	// 同一分类内的顺序，数值越小越靠外层；相同时按注册顺序
+	Order int
+	// 一元拦截器（可选）
+	Unary grpc.UnaryServerInterceptor
+	// 流拦截器（可选）
+	Stream grpc.StreamServerInterceptor
+}
+
+// InterceptorInfo 生效拦截器链中单个拦截器的描述
+type InterceptorInfo struct {
+	Position int    `json:"position"`
+	Name     string `json:"name"`
+	Class    string `json:"class"`
+	Order    int    `json:"order"`
+	Unary    bool   `json:"unary"`
+	Stream   bool   `json:"stream"`
+}
+
+type chainEntry struct {
+	spec InterceptorSpec
+	seq  int
+}
+
+// InterceptorChain 命名拦截器注册表，按 分类 -> Order -> 注册顺序 确定性组装拦截器链
+type InterceptorChain struct {
+	mu      sync.RWMutex
+	entries map[string]chainEntry
+	seq     int
+	// 显式顺序（Select 生成的链按配置顺序排列，忽略分类）
+	explicit map[string]int
+}
+
+// NewInterceptorChain 创建拦截器注册表
+func NewInterceptorChain() *InterceptorChain {
+	return &InterceptorChain{
+		entries: make(map[string]chainEntry),
+	}
+}
+
+// Register 注册命名拦截器，名称重复时返回错误
+func (c *InterceptorChain) Register(spec InterceptorSpec) error {
+	if spec.Name == "" {
+		return fmt.Errorf("interceptor name is required")
+	}
+	if spec.Unary == nil && spec.Stream == nil {
+		return fmt.Errorf("interceptor %s has neither unary nor stream handler", spec.Name)
+	}
+	if spec.Class < ClassObservability || spec.Class > ClassBusiness {
+		return fmt.Errorf("interceptor %s has invalid class %d", spec.Name, int(spec.Class))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.entries[spec.Name]; exists {
+		return fmt.Errorf("interceptor %s already registered", spec.Name)
+	}
+	c.seq++
+	c.entries[spec.Name] = chainEntry{spec: spec, seq: c.seq}
+	return nil
+}
+
+// RegisterUnary 注册命名一元拦截器
+func (c *InterceptorChain) RegisterUnary(name string, class InterceptorClass, order int, interceptor grpc.UnaryServerInterceptor) error {
+	return c.Register(InterceptorSpec{Name: name, Class: class, Order: order, Unary: interceptor})
+}
+
+// RegisterStream 注册命名流拦截器
+func (c *InterceptorChain) RegisterStream(name string, class InterceptorClass, order int, interceptor grpc.StreamServerInterceptor) error {
+	return c.Register(InterceptorSpec{Name: name, Class: class, Order: order, Stream: interceptor})
+}
+
+// Remove 移除命名拦截器
+func (c *InterceptorChain) Remove(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.entries[name]; !exists {
+		return false
+	}
+	delete(c.entries, name)
+	return true
+}
+
+// Merge 将另一个注册表中的拦截器合并进来（保持其内部注册顺序）
+func (c *InterceptorChain) Merge(other *InterceptorChain) error {
+	if other == nil {
+		return nil
+	}
+	for _, spec := range other.sorted() {
+		if err := c.Register(spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Select 按 names 的顺序（由外到内）选择拦截器，返回新的拦截器链；未列出的拦截器不启用
+// 不存在的名称会被忽略（可用 Missing 检查），名称为空或重复时返回错误
+func (c *InterceptorChain) Select(names []string) (*InterceptorChain, error) {
+	if err := validateInterceptorNames(names); err != nil {
+		return nil, err
+	}
+	selected := NewInterceptorChain()
+	selected.explicit = make(map[string]int, len(names))
+	for i, name := range names {
+		selected.explicit[name] = i
+	}
+	for _, spec := range c.sorted() {
+		if _, ok := selected.explicit[spec.Name]; ok {
+			if err := selected.Register(spec); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return selected, nil
+}
+
+// Missing 返回 names 中未注册的拦截器名称
+func (c *InterceptorChain) Missing(names []string) []string {
+	var missing []string
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, name := range names {
+		if _, ok := c.entries[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// validateInterceptorNames 校验配置的拦截器名称列表
+func validateInterceptorNames(names []string) error {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name == "" {
+			return fmt.Errorf("interceptor name is required")
+		}
+		if seen[name] {
+			return fmt.Errorf("interceptor %s listed more than once", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// UnaryInterceptor 将拦截器链组合为单个一元拦截器（链为空时直接调用 handler）
+func (c *InterceptorChain) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	interceptors := c.UnaryInterceptors()
+	if len(interceptors) == 0 {
+		return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			return handler(ctx, req)
+		}
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return interceptors[0](ctx, req, info, chainedUnaryHandler(interceptors, 0, info, handler))
+	}
+}
+
+func chainedUnaryHandler(interceptors []grpc.UnaryServerInterceptor, curr int, info *grpc.UnaryServerInfo, final grpc.UnaryHandler) grpc.UnaryHandler {
+	if curr == len(interceptors)-1 {
+		return final
+	}
+	return func(ctx context.Context, req any) (any, error) {
+		return interceptors[curr+1](ctx, req, info, chainedUnaryHandler(interceptors, curr+1, info, final))
+	}
+}
+
+// StreamInterceptor 将拦截器链组合为单个流拦截器（链为空时直接调用 handler）
+func (c *InterceptorChain) StreamInterceptor() grpc.StreamServerInterceptor {
+	interceptors := c.StreamInterceptors()
+	if len(interceptors) == 0 {
+		return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			return handler(srv, ss)
+		}
+	}
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return interceptors[0](srv, ss, info, chainedStreamHandler(interceptors, 0, info, handler))
+	}
+}
+
+func chainedStreamHandler(interceptors []grpc.StreamServerInterceptor, curr int, info *grpc.StreamServerInfo, final grpc.StreamHandler) grpc.StreamHandler {
+	if curr == len(interceptors)-1 {
+		return final
+	}
+	return func(srv any, ss grpc.ServerStream) error {
+		return interceptors[curr+1](srv, ss, info, chainedStreamHandler(interceptors, curr+1, info, final))
+	}
+}
+
+// UnaryInterceptors 返回按顺序排列的一元拦截器
+func (c *InterceptorChain) UnaryInterceptors() []grpc.UnaryServerInterceptor {
+	specs := c.sorted()
+	result := make([]grpc.UnaryServerInterceptor, 0, len(specs))
+	for _, spec := range specs {
+		if spec.Unary != nil {
+			result = append(result, spec.Unary)
+		}
+	}
+	return result
+}
+
+// StreamInterceptors 返回按顺序排列的流拦截器
+func (c *InterceptorChain) StreamInterceptors() []grpc.StreamServerInterceptor {
+	specs := c.sorted()
+	result := make([]grpc.StreamServerInterceptor, 0, len(specs))
+	for _, spec := range specs {
+		if spec.Stream != nil {
+			result = append(result, spec.Stream)
+		}
+	}
+	return result
+}
+
+// ServerOptions 返回组装好的拦截器链 ServerOption
+func (c *InterceptorChain) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(c.UnaryInterceptors()...),
+		grpc.ChainStreamInterceptor(c.StreamInterceptors()...),
+	}
+}
+
+// Describe 返回生效的拦截器链（按执行顺序，由外到内）
+func (c *InterceptorChain) Describe() []InterceptorInfo {
+	specs := c.sorted()
+	result := make([]InterceptorInfo, 0, len(specs))
+	for i, spec := range specs {
+		result = append(result, InterceptorInfo{
+			Position: i,
+			Name:     spec.Name,
+			Class:    spec.Class.String(),
+			Order:    spec.Order,
+			Unary:    spec.Unary != nil,
+			Stream:   spec.Stream != nil,
+		})
+	}
+	return result
+}
+
+// String 返回拦截器链的可读描述，示例：tracing(observability) -> logging(observability) -> auth(auth)
+func (c *InterceptorChain) String() string {
+	infos := c.Describe()
+	parts := make([]string, 0, len(infos))
+	for _, info := range infos {
+		parts = append(parts, fmt.Sprintf("%s(%s)", info.Name, info.Class))
+	}
+	return strings.Join(parts, " -> ")
+}
+
+func (c *InterceptorChain) sorted() []InterceptorSpec {
+	if c == nil {
+		return nil
+	}
+	c.mu.RLock()
+	entries := make([]chainEntry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		entries = append(entries, entry)
+	}
+	explicit := c.explicit
+	c.mu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i].spec, entries[j].spec
+		if explicit != nil {
+			return explicit[a.Name] < explicit[b.Name]
+		}
+		if a.Class != b.Class {
+			return a.Class < b.Class
+		}
+		if a.Order != b.Order {
+			return a.Order < b.Order
+		}
+		return entries[i].seq < entries[j].seq
+	})
+
+	specs := make([]InterceptorSpec, 0, len(entries))
+	for _, entry := range entries {
+		specs = append(specs, entry.spec)
+	}
+	return specs
+}
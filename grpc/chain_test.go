@@ -0,0 +1,146 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+)
+
+func recordingUnary(name string, calls *[]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		*calls = append(*calls, name)
+		return handler(ctx, req)
+	}
+}
+
+func TestInterceptorChainOrdersByClassThenOrder(t *testing.T) {
+	var calls []string
+	chain := NewInterceptorChain()
+	specs := []InterceptorSpec{
+		{Name: "business", Class: ClassBusiness, Unary: recordingUnary("business", &calls)},
+		{Name: "ratelimit", Class: ClassTraffic, Unary: recordingUnary("ratelimit", &calls)},
+		{Name: "auth", Class: ClassAuth, Unary: recordingUnary("auth", &calls)},
+		{Name: "logging", Class: ClassObservability, Order: 10, Unary: recordingUnary("logging", &calls)},
+		{Name: "tracing", Class: ClassObservability, Order: 0, Unary: recordingUnary("tracing", &calls)},
+		{Name: "timeout", Class: ClassTraffic, Unary: recordingUnary("timeout", &calls)},
+	}
+	for _, spec := range specs {
+		if err := chain.Register(spec); err != nil {
+			t.Fatalf("Register(%s) failed: %v", spec.Name, err)
+		}
+	}
+
+	want := "tracing(observability) -> logging(observability) -> auth(auth) -> ratelimit(traffic) -> timeout(traffic) -> business(business)"
+	if got := chain.String(); got != want {
+		t.Fatalf("unexpected chain:\n got: %s\nwant: %s", got, want)
+	}
+
+	// 通过 grpc 的链式组合执行，验证实际调用顺序与描述一致
+	interceptors := chain.UnaryInterceptors()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return req, nil }
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		next, interceptor := handler, interceptors[i]
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return interceptor(ctx, req, &grpc.UnaryServerInfo{}, next)
+		}
+	}
+	if _, err := handler(context.Background(), "req"); err != nil {
+		t.Fatalf("handler failed: %v", err)
+	}
+	if got := strings.Join(calls, ","); got != "tracing,logging,auth,ratelimit,timeout,business" {
+		t.Fatalf("unexpected call order: %s", got)
+	}
+}
+
+func TestInterceptorChainRejectsInvalidRegistrations(t *testing.T) {
+	var calls []string
+	chain := NewInterceptorChain()
+	if err := chain.RegisterUnary("auth", ClassAuth, 0, recordingUnary("auth", &calls)); err != nil {
+		t.Fatalf("RegisterUnary failed: %v", err)
+	}
+	if err := chain.RegisterUnary("auth", ClassAuth, 0, recordingUnary("auth", &calls)); err == nil {
+		t.Fatal("expected duplicate name error")
+	}
+	if err := chain.Register(InterceptorSpec{Name: "empty", Class: ClassAuth}); err == nil {
+		t.Fatal("expected missing handler error")
+	}
+	if err := chain.RegisterUnary("bad", InterceptorClass(99), 0, recordingUnary("bad", &calls)); err == nil {
+		t.Fatal("expected invalid class error")
+	}
+
+	other := NewInterceptorChain()
+	_ = other.RegisterUnary("auth", ClassBusiness, 0, recordingUnary("auth", &calls))
+	if err := chain.Merge(other); err == nil {
+		t.Fatal("expected merge conflict error")
+	}
+
+	infos := chain.Describe()
+	if len(infos) != 1 || infos[0].Name != "auth" || !infos[0].Unary || infos[0].Stream {
+		t.Fatalf("unexpected describe result: %+v", infos)
+	}
+	if !chain.Remove("auth") || chain.Remove("auth") {
+		t.Fatal("expected Remove to succeed once")
+	}
+}
+
+func TestInterceptorChainSelectUsesConfiguredOrder(t *testing.T) {
+	var calls []string
+	chain := NewInterceptorChain()
+	_ = chain.RegisterUnary("tracing", ClassObservability, 0, recordingUnary("tracing", &calls))
+	_ = chain.RegisterUnary("logging", ClassObservability, 10, recordingUnary("logging", &calls))
+	_ = chain.RegisterUnary("auth", ClassAuth, 0, recordingUnary("auth", &calls))
+	_ = chain.RegisterUnary("ratelimit", ClassTraffic, 0, recordingUnary("ratelimit", &calls))
+
+	selected, err := chain.Select([]string{"auth", "tracing", "missing"})
+	if err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if got := selected.String(); got != "auth(auth) -> tracing(observability)" {
+		t.Fatalf("unexpected selected chain: %s", got)
+	}
+	if missing := chain.Missing([]string{"auth", "missing"}); len(missing) != 1 || missing[0] != "missing" {
+		t.Fatalf("unexpected missing interceptors: %v", missing)
+	}
+	if _, err := chain.Select([]string{"auth", "auth"}); err == nil {
+		t.Fatal("expected duplicate names to be rejected")
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return req, nil }
+	if _, err := selected.UnaryInterceptor()(context.Background(), "req", &grpc.UnaryServerInfo{}, handler); err != nil {
+		t.Fatalf("composed interceptor failed: %v", err)
+	}
+	if got := strings.Join(calls, ","); got != "auth,tracing" {
+		t.Fatalf("unexpected call order: %s", got)
+	}
+
+	// 空链直接调用 handler
+	if resp, err := NewInterceptorChain().UnaryInterceptor()(context.Background(), "req", &grpc.UnaryServerInfo{}, handler); err != nil || resp != "req" {
+		t.Fatalf("expected empty chain to call handler, got %v, %v", resp, err)
+	}
+}
+
+func TestBuildClientInterceptors(t *testing.T) {
+	noop := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	custom := []ClientInterceptorSpec{{Name: "auth", Unary: noop}}
+
+	specs, err := BuildClientInterceptors(custom, nil)
+	if err != nil || specs[len(specs)-1].Name != "auth" || specs[len(specs)-2].Name != "logging" {
+		t.Fatalf("expected custom interceptors inside defaults, got %+v, %v", specs, err)
+	}
+
+	specs, err = BuildClientInterceptors(custom, []string{"auth", "recovery"})
+	if err != nil || len(specs) != 2 || specs[0].Name != "auth" || specs[1].Name != "recovery" {
+		t.Fatalf("expected configured order, got %+v, %v", specs, err)
+	}
+
+	if _, err := BuildClientInterceptors(custom, []string{"missing"}); err == nil {
+		t.Fatal("expected unknown interceptor to be rejected")
+	}
+	if _, err := BuildClientInterceptors([]ClientInterceptorSpec{{Name: "logging", Unary: noop}}, nil); err == nil {
+		t.Fatal("expected builtin name conflict to be rejected")
+	}
+}
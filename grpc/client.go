@@ -5,9 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
 	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
@@ -15,13 +17,16 @@ import (
 	"google.golang.org/grpc/keepalive"
 
 	"github.com/team-dandelion/quickgo/logger"
-	"github.com/team-dandelion/quickgo/tracing"
 )
 
 // Client gRPC客户端封装
 type Client struct {
-	mu             sync.RWMutex
-	conn           *grpc.ClientConn
+	mu sync.RWMutex
+	// 连接池（PoolSize<=1 时仅一个连接）
+	conns          []*grpc.ClientConn
+	next           atomic.Uint64
+	poolSize       int
+	poolInterval   time.Duration
 	address        string
 	options        []grpc.DialOption
 	timeout        time.Duration
@@ -29,18 +34,37 @@ type Client struct {
 	cancel         context.CancelFunc
 	resolverScheme string
 	resolverSD     ServiceDiscovery
+	fallback       *HTTPFallbackConfig
+	tunnel         *HTTPTunnelConn
 }
 
 // ClientConfig 客户端配置
 type ClientConfig struct {
-	Address          string              // 服务器地址，格式：host:port 或 scheme://service-name（使用服务发现时）
-	Timeout          time.Duration       // 连接超时时间
-	Insecure         bool                // 是否使用非安全连接（不加密）
-	TLS              *TLSConfig          // TLS配置（如果 Insecure=false）
-	Options          []grpc.DialOption   // 自定义 DialOption
-	KeepAlive        *KeepAliveConfig    // KeepAlive配置
-	ServiceDiscovery ServiceDiscovery    // 服务发现（可选）
-	LoadBalancing    LoadBalancingPolicy // 负载均衡策略
+	Address          string                  // 服务器地址，格式：host:port 或 scheme://service-name（使用服务发现时）
+	Timeout          time.Duration           // 连接超时时间
+	Insecure         bool                    // 是否使用非安全连接（不加密）
+	TLS              *TLSConfig              // TLS配置（如果 Insecure=false）
+	Options          []grpc.DialOption       // 自定义 DialOption
+	KeepAlive        *KeepAliveConfig        // KeepAlive配置
+	ServiceDiscovery ServiceDiscovery        // 服务发现（可选）
+	LoadBalancing    LoadBalancingPolicy     // 负载均衡策略
+	Interceptors     []ClientInterceptorSpec // 拦截器链（由外到内），为 nil 时使用 DefaultClientInterceptors
+	Canary           *CanaryRule             // 灰度路由规则（可选，需要提供实例元数据的服务发现，设置后忽略 LoadBalancing）
+	HTTPFallback     *HTTPFallbackConfig     // 直连失败时通过 HTTP 网关隧道转发（可选，仅支持一元调用）
+	// 连接池大小：>1 时对同一目标建立多个连接（各自独立的 HTTP/2 连接），GetConn 轮询选取就绪连接，默认 1
+	PoolSize int
+	// 连接池健康检查间隔：连续两次处于 TransientFailure 的连接会被淘汰并重建，默认 10s
+	PoolHealthCheckInterval time.Duration
+	// 调用默认使用 WaitForReady：连接未就绪时等待（受调用 context 超时约束）而不是立即失败
+	WaitForReady bool
+	// 断线重连退避配置（可选，默认使用 gRPC 内置的指数退避：1s 起，最大 120s）
+	Backoff *BackoffConfig
+}
+
+// BackoffConfig 重连指数退避配置
+type BackoffConfig struct {
+	BaseDelay time.Duration // 首次重连等待时间
+	MaxDelay  time.Duration // 最大等待时间
 }
 
 // TLSConfig TLS配置
@@ -67,6 +91,12 @@ func NewClient(config ClientConfig) (*Client, error) {
 	if config.Timeout == 0 {
 		config.Timeout = 10 * time.Second
 	}
+	if config.PoolSize <= 0 {
+		config.PoolSize = 1
+	}
+	if config.PoolHealthCheckInterval <= 0 {
+		config.PoolHealthCheckInterval = defaultPoolHealthCheckInterval
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -80,6 +110,8 @@ func NewClient(config ClientConfig) (*Client, error) {
 			scheme = EtcdScheme
 		case *StaticResolver:
 			scheme = StaticScheme
+		case *MemoryRegistry:
+			scheme = MemoryScheme
 		}
 
 		// 如果地址不包含 scheme，添加 scheme
@@ -105,10 +137,13 @@ func NewClient(config ClientConfig) (*Client, error) {
 	}
 
 	client := &Client{
-		address: address,
-		timeout: config.Timeout,
-		ctx:     ctx,
-		cancel:  cancel,
+		address:      address,
+		timeout:      config.Timeout,
+		poolSize:     config.PoolSize,
+		poolInterval: config.PoolHealthCheckInterval,
+		ctx:          ctx,
+		cancel:       cancel,
+		fallback:     config.HTTPFallback,
 	}
 	if config.ServiceDiscovery != nil {
 		client.resolverScheme = extractScheme(address)
@@ -167,26 +202,27 @@ func NewClient(config ClientConfig) (*Client, error) {
 	}
 
 	// 构建拦截器链
-	unaryInterceptors := []grpc.UnaryClientInterceptor{
-		ClientLoggingInterceptor(),
-	}
-	streamInterceptors := []grpc.StreamClientInterceptor{
-		ClientStreamLoggingInterceptor(),
-	}
-
-	// 如果启用了 OpenTelemetry tracing，添加 tracing 拦截器
-	if tracing.IsEnabled() {
-		unaryInterceptors = append([]grpc.UnaryClientInterceptor{tracing.UnaryClientInterceptor()}, unaryInterceptors...)
-		streamInterceptors = append([]grpc.StreamClientInterceptor{tracing.StreamClientInterceptor()}, streamInterceptors...)
+	interceptorSpecs := config.Interceptors
+	if interceptorSpecs == nil {
+		interceptorSpecs = DefaultClientInterceptors()
 	}
+	unaryInterceptors, streamInterceptors := clientInterceptors(interceptorSpecs)
 
-	// 添加默认拦截器（日志、链路追踪）
+	// 添加拦截器链（默认为日志、链路追踪）
 	options = append(options, grpc.WithChainUnaryInterceptor(unaryInterceptors...))
 	// 添加流式拦截器
 	options = append(options, grpc.WithChainStreamInterceptor(streamInterceptors...))
 
 	// 添加负载均衡策略
-	if config.LoadBalancing != "" {
+	if config.Canary != nil {
+		canaryOption, err := GetCanaryOption(*config.Canary)
+		if err != nil {
+			_ = client.Close()
+			cancel()
+			return nil, fmt.Errorf("invalid canary rule: %w", err)
+		}
+		options = append(options, canaryOption)
+	} else if config.LoadBalancing != "" {
 		options = append(options, GetLoadBalancingOption(config.LoadBalancing))
 	} else {
 		// 如果使用服务发现，默认使用轮询策略
@@ -195,6 +231,26 @@ func NewClient(config ClientConfig) (*Client, error) {
 		}
 	}
 
+	// 调用等待连接就绪
+	if config.WaitForReady {
+		options = append(options, grpc.WithDefaultCallOptions(grpc.WaitForReady(true)))
+	}
+
+	// 重连退避
+	if config.Backoff != nil {
+		backoffConfig := backoff.DefaultConfig
+		if config.Backoff.BaseDelay > 0 {
+			backoffConfig.BaseDelay = config.Backoff.BaseDelay
+		}
+		if config.Backoff.MaxDelay > 0 {
+			backoffConfig.MaxDelay = config.Backoff.MaxDelay
+		}
+		options = append(options, grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           backoffConfig,
+			MinConnectTimeout: config.Timeout,
+		}))
+	}
+
 	// 添加自定义选项
 	options = append(options, config.Options...)
 
@@ -206,60 +262,220 @@ func NewClient(config ClientConfig) (*Client, error) {
 // Connect 连接到gRPC服务器
 func (c *Client) Connect(ctx context.Context) error {
 	c.mu.RLock()
-	connected := c.conn != nil
+	connected := len(c.conns) > 0 || c.tunnel != nil
 	c.mu.RUnlock()
 	if connected {
 		return fmt.Errorf("client already connected")
 	}
 
-	// 创建带超时的context
+	conns, err := c.dialDirect(ctx)
+	if err != nil {
+		if c.fallback != nil {
+			return c.connectHTTPFallback(ctx, err)
+		}
+		logger.Error(ctx, "Failed to connect to gRPC server: address=%s, error=%v", c.address, err)
+		return err
+	}
+
+	c.mu.Lock()
+	if len(c.conns) > 0 {
+		c.mu.Unlock()
+		for _, cc := range conns {
+			_ = cc.Close()
+		}
+		return fmt.Errorf("client already connected")
+	}
+	c.conns = conns
+	c.mu.Unlock()
+	if c.poolSize > 1 {
+		go c.maintainPool()
+		logger.Info(ctx, "gRPC client connected: address=%s, poolSize=%d", c.address, c.poolSize)
+	} else {
+		logger.Info(ctx, "gRPC client connected: address=%s", c.address)
+	}
+
+	return nil
+}
+
+// dialDirect 阻塞建立首个直连（受连接超时约束），连接池的其余连接异步建立，未就绪前 GetConn 不会选取
+func (c *Client) dialDirect(ctx context.Context) ([]*grpc.ClientConn, error) {
 	connectCtx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
-	// 连接服务器
 	options := append([]grpc.DialOption{}, c.options...)
 	options = append(options, grpc.WithBlock())
 	conn, err := grpc.DialContext(connectCtx, c.address, options...)
 	if err != nil {
-		logger.Error(ctx, "Failed to connect to gRPC server: address=%s, error=%v", c.address, err)
-		return fmt.Errorf("failed to connect to %s: %w", c.address, err)
+		return nil, fmt.Errorf("failed to connect to %s: %w", c.address, err)
+	}
+
+	conns := []*grpc.ClientConn{conn}
+	for i := 1; i < c.poolSize; i++ {
+		extra, err := c.dialPoolConn(ctx)
+		if err != nil {
+			for _, cc := range conns {
+				_ = cc.Close()
+			}
+			return nil, fmt.Errorf("failed to connect to %s: %w", c.address, err)
+		}
+		conns = append(conns, extra)
+	}
+	return conns, nil
+}
+
+// ConnectLazy 以懒连接方式连接：不等待连接建立，立即返回
+// 连接在后台建立，断开后按退避策略自动重连；配合 WaitForReady 时调用会等待连接就绪
+// 懒连接不会感知拨号失败，配置了 HTTPFallback 时返回错误
+func (c *Client) ConnectLazy(ctx context.Context) error {
+	if c.fallback != nil {
+		return errors.New("lazy connection does not support http fallback")
+	}
+	c.mu.RLock()
+	connected := len(c.conns) > 0 || c.tunnel != nil
+	c.mu.RUnlock()
+	if connected {
+		return fmt.Errorf("client already connected")
+	}
+
+	conns := make([]*grpc.ClientConn, 0, c.poolSize)
+	for i := 0; i < c.poolSize; i++ {
+		conn, err := c.dialPoolConn(ctx)
+		if err != nil {
+			for _, cc := range conns {
+				_ = cc.Close()
+			}
+			return fmt.Errorf("failed to dial %s: %w", c.address, err)
+		}
+		conns = append(conns, conn)
 	}
 
 	c.mu.Lock()
-	if c.conn != nil {
+	if len(c.conns) > 0 || c.tunnel != nil {
 		c.mu.Unlock()
-		_ = conn.Close()
+		for _, cc := range conns {
+			_ = cc.Close()
+		}
 		return fmt.Errorf("client already connected")
 	}
-	c.conn = conn
+	c.conns = conns
 	c.mu.Unlock()
-	logger.Info(ctx, "gRPC client connected: address=%s", c.address)
+	if c.poolSize > 1 {
+		go c.maintainPool()
+	}
+	logger.Info(ctx, "gRPC client dialing lazily: address=%s, poolSize=%d", c.address, c.poolSize)
+	return nil
+}
+
+// connectHTTPFallback 直连失败时切换到 HTTP 网关隧道
+func (c *Client) connectHTTPFallback(ctx context.Context, dialErr error) error {
+	tunnel, err := NewHTTPTunnelConn(*c.fallback)
+	if err != nil {
+		return errors.Join(dialErr, fmt.Errorf("http fallback: %w", err))
+	}
 
+	c.mu.Lock()
+	if len(c.conns) > 0 || c.tunnel != nil {
+		c.mu.Unlock()
+		return fmt.Errorf("client already connected")
+	}
+	c.tunnel = tunnel
+	c.mu.Unlock()
+	logger.Warn(ctx, "gRPC direct connection failed, falling back to HTTP tunnel: address=%s, tunnel=%s, error=%v", c.address, c.fallback.URL, dialErr)
+	go c.probeDirect()
 	return nil
 }
 
+// probeDirect 隧道模式下定期重新建立直连，成功后切回直连
+func (c *Client) probeDirect() {
+	interval := c.fallback.ProbeInterval
+	if interval <= 0 {
+		interval = defaultFallbackProbeInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		conns, err := c.dialDirect(c.ctx)
+		if err != nil {
+			logger.Debug(c.ctx, "gRPC direct connection still unavailable, keep using HTTP tunnel: address=%s, error=%v", c.address, err)
+			continue
+		}
+
+		c.mu.Lock()
+		// 客户端已关闭
+		if c.tunnel == nil || len(c.conns) > 0 {
+			c.mu.Unlock()
+			for _, cc := range conns {
+				_ = cc.Close()
+			}
+			return
+		}
+		c.conns = conns
+		c.tunnel = nil
+		c.mu.Unlock()
+		if c.poolSize > 1 {
+			go c.maintainPool()
+		}
+		logger.Info(c.ctx, "gRPC direct connection recovered, leaving HTTP tunnel: address=%s", c.address)
+		return
+	}
+}
+
 // ConnectWithContext 使用context连接到gRPC服务器
 func (c *Client) ConnectWithContext(ctx context.Context) error {
 	return c.Connect(ctx)
 }
 
-// GetConn 获取底层连接
+// GetConn 获取底层连接（启用连接池时轮询选取就绪连接）
+//
+// Deprecated: 通过 HTTP 隧道连接时没有底层 gRPC 连接，GetConn 返回 nil；请使用 Conn，直连与隧道均可用于生成客户端存根
 func (c *Client) GetConn() *grpc.ClientConn {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.conn
+	return c.pickConnLocked()
+}
+
+// Conn 获取可用于生成客户端存根的连接：直连可用时返回 gRPC 连接，否则返回 HTTP 隧道
+func (c *Client) Conn() grpc.ClientConnInterface {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if conn := c.pickConnLocked(); conn != nil {
+		return conn
+	}
+	if c.tunnel != nil {
+		return c.tunnel
+	}
+	return nil
+}
+
+// UsingHTTPFallback 是否正在使用 HTTP 隧道
+func (c *Client) UsingHTTPFallback() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.conns) == 0 && c.tunnel != nil
 }
 
-// IsConnected 检查是否已连接
+// IsConnected 检查是否已连接（连接池中任一连接就绪即视为已连接）
 func (c *Client) IsConnected() bool {
 	c.mu.RLock()
-	conn := c.conn
+	conns := c.conns
+	tunnel := c.tunnel
 	c.mu.RUnlock()
-	if conn == nil {
-		return false
+	if len(conns) == 0 {
+		return tunnel != nil
+	}
+	for _, conn := range conns {
+		if conn.GetState() == connectivity.Ready {
+			return true
+		}
 	}
-	state := conn.GetState()
-	return state == connectivity.Ready
+	return false
 }
 
 // Close 关闭连接
@@ -267,17 +483,19 @@ func (c *Client) Close() error {
 	ctx := context.Background()
 	var errs []error
 	c.mu.Lock()
-	conn := c.conn
-	c.conn = nil
+	conns := c.conns
+	c.conns = nil
+	c.tunnel = nil
 	c.mu.Unlock()
-	if conn != nil {
+	for _, conn := range conns {
 		if err := conn.Close(); err != nil {
 			logger.Error(ctx, "Failed to close gRPC client connection: address=%s, error=%v", c.address, err)
 			errs = append(errs, err)
-		} else {
-			logger.Info(ctx, "gRPC client connection closed: address=%s", c.address)
 		}
 	}
+	if len(conns) > 0 && len(errs) == 0 {
+		logger.Info(ctx, "gRPC client connection closed: address=%s", c.address)
+	}
 
 	c.mu.Lock()
 	cancel := c.cancel
@@ -305,9 +523,7 @@ func (c *Client) Close() error {
 
 // HealthCheck 健康检查
 func (c *Client) HealthCheck(ctx context.Context, service string) (*grpc_health_v1.HealthCheckResponse, error) {
-	c.mu.RLock()
-	conn := c.conn
-	c.mu.RUnlock()
+	conn := c.Conn()
 	if conn == nil {
 		return nil, fmt.Errorf("client not connected")
 	}
@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc"
+
+	"github.com/team-dandelion/quickgo/tracing"
+)
+
+// ClientInterceptorSpec 命名客户端拦截器定义
+type ClientInterceptorSpec struct {
+	// 拦截器名称（链内唯一）
+	Name string
+	// 一元拦截器（可选）
+	Unary grpc.UnaryClientInterceptor
+	// 流拦截器（可选）
+	Stream grpc.StreamClientInterceptor
+}
+
+// DefaultClientInterceptors 返回默认的客户端拦截器链（由外到内）：tracing（启用时）、logging
+func DefaultClientInterceptors() []ClientInterceptorSpec {
+	specs := make([]ClientInterceptorSpec, 0, 2)
+	if tracing.IsEnabled() {
+		specs = append(specs, ClientInterceptorSpec{Name: "tracing", Unary: tracing.UnaryClientInterceptor(), Stream: tracing.StreamClientInterceptor()})
+	}
+	return append(specs, ClientInterceptorSpec{Name: "logging", Unary: ClientLoggingInterceptor(), Stream: ClientStreamLoggingInterceptor()})
+}
+
+// optionalClientInterceptors 默认不启用、可通过名称选择的内置客户端拦截器
+func optionalClientInterceptors() []ClientInterceptorSpec {
+	return []ClientInterceptorSpec{
+		{Name: "recovery", Unary: ClientRecoveryInterceptor()},
+	}
+}
+
+// BuildClientInterceptors 组装客户端拦截器链
+// names 为空时使用默认链并在其内侧追加 custom；否则按 names 的顺序（由外到内）从内置与 custom 中选择，名称不存在时返回错误
+func BuildClientInterceptors(custom []ClientInterceptorSpec, names []string) ([]ClientInterceptorSpec, error) {
+	if err := validateInterceptorNames(names); err != nil {
+		return nil, err
+	}
+	defaults := DefaultClientInterceptors()
+	available := make(map[string]ClientInterceptorSpec)
+	for _, spec := range append(defaults, optionalClientInterceptors()...) {
+		available[spec.Name] = spec
+	}
+	for _, spec := range custom {
+		if spec.Name == "" {
+			return nil, fmt.Errorf("interceptor name is required")
+		}
+		if spec.Unary == nil && spec.Stream == nil {
+			return nil, fmt.Errorf("interceptor %s has neither unary nor stream handler", spec.Name)
+		}
+		if _, exists := available[spec.Name]; exists {
+			return nil, fmt.Errorf("interceptor %s already registered", spec.Name)
+		}
+		available[spec.Name] = spec
+	}
+
+	if len(names) == 0 {
+		return append(defaults, custom...), nil
+	}
+	specs := make([]ClientInterceptorSpec, 0, len(names))
+	for _, name := range names {
+		spec, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown client interceptor: %s", name)
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// clientInterceptors 拆分为一元与流拦截器
+func clientInterceptors(specs []ClientInterceptorSpec) ([]grpc.UnaryClientInterceptor, []grpc.StreamClientInterceptor) {
+	unary := make([]grpc.UnaryClientInterceptor, 0, len(specs))
+	stream := make([]grpc.StreamClientInterceptor, 0, len(specs))
+	for _, spec := range specs {
+		if spec.Unary != nil {
+			unary = append(unary, spec.Unary)
+		}
+		if spec.Stream != nil {
+			stream = append(stream, spec.Stream)
+		}
+	}
+	return unary, stream
+}
@@ -0,0 +1,313 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/team-dandelion/quickgo/types"
+)
+
+// ConcurrencyLimitConfig 服务端并发限制配置：限制同时处理的请求数，超出时进入有界队列等待，队列已满或等待超时返回 ResourceExhausted
+// 请求先获取方法级名额，再获取全局名额；流式调用在整个流的生命周期内占用名额
+type ConcurrencyLimitConfig struct {
+	// 全局最大并发数，0 表示不限制
+	MaxInFlight int `json:"maxInFlight" yaml:"maxInFlight" toml:"maxInFlight"`
+	// 全局等待队列长度，0 表示不排队（超出并发数立即拒绝）
+	MaxQueue int `json:"maxQueue" yaml:"maxQueue" toml:"maxQueue"`
+	// 排队最长等待时间 示例：100ms，0 表示等待至请求 context 结束
+	QueueTimeout types.Duration `json:"queueTimeout" yaml:"queueTimeout" toml:"queueTimeout"`
+	// 方法级限制（key 为 gRPC 方法全名，支持 * 通配符如 /report.ReportService/*），匹配的每个方法独立计数
+	// 同时匹配多个规则时使用精确匹配，其次使用最长的通配规则
+	Methods map[string]MethodConcurrencyLimit `json:"methods" yaml:"methods" toml:"methods"`
+}
+
+// MethodConcurrencyLimit 方法级并发限制
+type MethodConcurrencyLimit struct {
+	// 最大并发数，0 表示不限制
+	MaxInFlight int `json:"maxInFlight" yaml:"maxInFlight" toml:"maxInFlight"`
+	// 等待队列长度，0 表示不排队
+	MaxQueue int `json:"maxQueue" yaml:"maxQueue" toml:"maxQueue"`
+	// 排队最长等待时间，0 表示等待至请求 context 结束
+	QueueTimeout types.Duration `json:"queueTimeout" yaml:"queueTimeout" toml:"queueTimeout"`
+}
+
+// Validate 校验并发限制配置
+func (c ConcurrencyLimitConfig) Validate() error {
+	if err := validateConcurrencyLimit(c.MaxInFlight, c.MaxQueue, c.QueueTimeout); err != nil {
+		return err
+	}
+	for method, limit := range c.Methods {
+		if _, err := path.Match(method, ""); err != nil {
+			return fmt.Errorf("invalid concurrency limit method pattern %q: %w", method, err)
+		}
+		if err := validateConcurrencyLimit(limit.MaxInFlight, limit.MaxQueue, limit.QueueTimeout); err != nil {
+			return fmt.Errorf("concurrency limit for %s: %w", method, err)
+		}
+	}
+	return nil
+}
+
+func validateConcurrencyLimit(maxInFlight, maxQueue int, queueTimeout types.Duration) error {
+	if maxInFlight < 0 {
+		return fmt.Errorf("maxInFlight must be non-negative: %d", maxInFlight)
+	}
+	if maxQueue < 0 {
+		return fmt.Errorf("maxQueue must be non-negative: %d", maxQueue)
+	}
+	if queueTimeout < 0 {
+		return fmt.Errorf("queueTimeout must be non-negative: %s", queueTimeout)
+	}
+	return nil
+}
+
+var (
+	errConcurrencyQueueFull    = errors.New("queue full")
+	errConcurrencyQueueTimeout = errors.New("queue timeout")
+)
+
+// semaphore 带有界等待队列的信号量
+type semaphore struct {
+	slots        chan struct{}
+	waiting      atomic.Int64
+	maxQueue     int64
+	queueTimeout time.Duration
+}
+
+func newSemaphore(maxInFlight, maxQueue int, queueTimeout types.Duration) *semaphore {
+	if maxInFlight <= 0 {
+		return nil
+	}
+	return &semaphore{
+		slots:        make(chan struct{}, maxInFlight),
+		maxQueue:     int64(maxQueue),
+		queueTimeout: queueTimeout.Std(),
+	}
+}
+
+// acquire 获取名额，无空闲名额时排队等待
+func (s *semaphore) acquire(ctx context.Context) error {
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	default:
+	}
+	if s.waiting.Add(1) > s.maxQueue {
+		s.waiting.Add(-1)
+		return errConcurrencyQueueFull
+	}
+	defer s.waiting.Add(-1)
+
+	var timeout <-chan time.Time
+	if s.queueTimeout > 0 {
+		timer := time.NewTimer(s.queueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timeout:
+		return errConcurrencyQueueTimeout
+	}
+}
+
+func (s *semaphore) release() {
+	<-s.slots
+}
+
+// ConcurrencyStats 并发限制统计
+type ConcurrencyStats struct {
+	// 处理中的请求数（全局）
+	InFlight int
+	// 排队中的请求数（全局）
+	Queued int
+	// 被拒绝的请求数（队列已满或排队超时）
+	Rejected int64
+}
+
+// ConcurrencyLimiter 服务端并发限制器，一元与流式拦截器共享同一组名额
+type ConcurrencyLimiter struct {
+	global *semaphore
+	// 方法规则（精确匹配）
+	exact map[string]MethodConcurrencyLimit
+	// 通配规则，按长度降序
+	patterns []string
+	rules    map[string]MethodConcurrencyLimit
+	// 方法级信号量（key 为方法全名，值为 *semaphore，未匹配规则的方法值为 nil）
+	methods  sync.Map
+	rejected atomic.Int64
+}
+
+// NewConcurrencyLimiter 创建并发限制器
+func NewConcurrencyLimiter(config ConcurrencyLimitConfig) (*ConcurrencyLimiter, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	l := &ConcurrencyLimiter{
+		global: newSemaphore(config.MaxInFlight, config.MaxQueue, config.QueueTimeout),
+		exact:  make(map[string]MethodConcurrencyLimit),
+		rules:  make(map[string]MethodConcurrencyLimit),
+	}
+	for method, limit := range config.Methods {
+		if containsWildcard(method) {
+			l.patterns = append(l.patterns, method)
+			l.rules[method] = limit
+		} else {
+			l.exact[method] = limit
+		}
+	}
+	sort.Slice(l.patterns, func(i, j int) bool {
+		if len(l.patterns[i]) != len(l.patterns[j]) {
+			return len(l.patterns[i]) > len(l.patterns[j])
+		}
+		return l.patterns[i] < l.patterns[j]
+	})
+	return l, nil
+}
+
+// ConcurrencyLimitInterceptor 一元并发限制拦截器；需要同时限制流式调用时使用 NewConcurrencyLimiter 共享名额
+// 配置无效时 panic（可先调用 ConcurrencyLimitConfig.Validate 校验）
+func ConcurrencyLimitInterceptor(config ConcurrencyLimitConfig) grpc.UnaryServerInterceptor {
+	return mustConcurrencyLimiter(config).UnaryInterceptor()
+}
+
+// StreamConcurrencyLimitInterceptor 流式并发限制拦截器
+// 配置无效时 panic（可先调用 ConcurrencyLimitConfig.Validate 校验）
+func StreamConcurrencyLimitInterceptor(config ConcurrencyLimitConfig) grpc.StreamServerInterceptor {
+	return mustConcurrencyLimiter(config).StreamInterceptor()
+}
+
+func mustConcurrencyLimiter(config ConcurrencyLimitConfig) *ConcurrencyLimiter {
+	limiter, err := NewConcurrencyLimiter(config)
+	if err != nil {
+		panic(err)
+	}
+	return limiter
+}
+
+// UnaryInterceptor 返回一元拦截器
+func (l *ConcurrencyLimiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		release, err := l.Acquire(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor 返回流式拦截器
+func (l *ConcurrencyLimiter) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		release, err := l.Acquire(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		defer release()
+		return handler(srv, ss)
+	}
+}
+
+// Acquire 为方法获取方法级与全局名额，返回释放函数
+// 队列已满或排队超时返回 ResourceExhausted，排队期间 context 结束返回对应的 Canceled / DeadlineExceeded
+func (l *ConcurrencyLimiter) Acquire(ctx context.Context, method string) (func(), error) {
+	methodSem := l.methodSemaphore(method)
+	if methodSem != nil {
+		if err := methodSem.acquire(ctx); err != nil {
+			return nil, l.rejectError(method, "method", err)
+		}
+	}
+	if l.global != nil {
+		if err := l.global.acquire(ctx); err != nil {
+			if methodSem != nil {
+				methodSem.release()
+			}
+			return nil, l.rejectError(method, "server", err)
+		}
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			if l.global != nil {
+				l.global.release()
+			}
+			if methodSem != nil {
+				methodSem.release()
+			}
+		})
+	}, nil
+}
+
+// Stats 返回全局并发统计
+func (l *ConcurrencyLimiter) Stats() ConcurrencyStats {
+	stats := ConcurrencyStats{Rejected: l.rejected.Load()}
+	if l.global != nil {
+		stats.InFlight = len(l.global.slots)
+		stats.Queued = int(l.global.waiting.Load())
+	}
+	return stats
+}
+
+// methodSemaphore 返回方法级信号量（未配置限制时返回 nil）
+func (l *ConcurrencyLimiter) methodSemaphore(method string) *semaphore {
+	if len(l.exact) == 0 && len(l.patterns) == 0 {
+		return nil
+	}
+	if value, ok := l.methods.Load(method); ok {
+		return value.(*semaphore)
+	}
+	var sem *semaphore
+	if limit, ok := l.matchMethod(method); ok {
+		sem = newSemaphore(limit.MaxInFlight, limit.MaxQueue, limit.QueueTimeout)
+	}
+	value, _ := l.methods.LoadOrStore(method, sem)
+	return value.(*semaphore)
+}
+
+// matchMethod 查找方法对应的限制规则
+func (l *ConcurrencyLimiter) matchMethod(method string) (MethodConcurrencyLimit, bool) {
+	if limit, ok := l.exact[method]; ok {
+		return limit, true
+	}
+	for _, pattern := range l.patterns {
+		if matched, _ := path.Match(pattern, method); matched {
+			return l.rules[pattern], true
+		}
+	}
+	return MethodConcurrencyLimit{}, false
+}
+
+// rejectError 将获取名额失败转换为 gRPC status 错误
+func (l *ConcurrencyLimiter) rejectError(method, scope string, err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	}
+	l.rejected.Add(1)
+	return status.Errorf(codes.ResourceExhausted, "%s concurrency limit exceeded (%v): method=%s", scope, err, method)
+}
+
+func containsWildcard(pattern string) bool {
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '*', '?', '[':
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/team-dandelion/quickgo/types"
+)
+
+func TestConcurrencyLimiterQueuesAndRejects(t *testing.T) {
+	limiter, err := NewConcurrencyLimiter(ConcurrencyLimitConfig{MaxInFlight: 1, MaxQueue: 1})
+	if err != nil {
+		t.Fatalf("NewConcurrencyLimiter failed: %v", err)
+	}
+	interceptor := limiter.UnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc.Svc/Call"}
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	blocking := func(ctx context.Context, req interface{}) (interface{}, error) {
+		close(started)
+		<-unblock
+		return "first", nil
+	}
+	firstDone := make(chan error, 1)
+	go func() {
+		_, err := interceptor(context.Background(), nil, info, blocking)
+		firstDone <- err
+	}()
+	<-started
+
+	// 第二个请求进入队列
+	queuedDone := make(chan interface{}, 1)
+	go func() {
+		resp, _ := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "queued", nil
+		})
+		queuedDone <- resp
+	}()
+	waitFor(t, func() bool { return limiter.Stats().Queued == 1 })
+
+	// 队列已满，第三个请求立即拒绝
+	_, err = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		t.Fatal("handler should not run when queue is full")
+		return nil, nil
+	})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+
+	close(unblock)
+	if err := <-firstDone; err != nil {
+		t.Fatalf("first call failed: %v", err)
+	}
+	if resp := <-queuedDone; resp != "queued" {
+		t.Fatalf("queued call should run after release, got %v", resp)
+	}
+	if stats := limiter.Stats(); stats.InFlight != 0 || stats.Queued != 0 || stats.Rejected != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestConcurrencyLimiterPerMethodLimits(t *testing.T) {
+	limiter, err := NewConcurrencyLimiter(ConcurrencyLimitConfig{
+		Methods: map[string]MethodConcurrencyLimit{
+			"/report.Report/*":      {MaxInFlight: 1},
+			"/report.Report/Export": {MaxInFlight: 1, MaxQueue: 1, QueueTimeout: types.Duration(20 * time.Millisecond)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewConcurrencyLimiter failed: %v", err)
+	}
+
+	releaseHeavy, err := limiter.Acquire(context.Background(), "/report.Report/Build")
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	// 通配规则下各方法独立计数，其他服务不受限制
+	if _, err := limiter.Acquire(context.Background(), "/report.Report/Build"); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted for saturated method, got %v", err)
+	}
+	releaseList, err := limiter.Acquire(context.Background(), "/report.Report/List")
+	if err != nil {
+		t.Fatalf("other method should not be limited by Build: %v", err)
+	}
+	releaseUser, err := limiter.Acquire(context.Background(), "/user.User/Get")
+	if err != nil {
+		t.Fatalf("unmatched method should not be limited: %v", err)
+	}
+	releaseHeavy()
+	releaseHeavy() // 重复释放无副作用
+	releaseList()
+	releaseUser()
+
+	// 精确规则优先：排队超时返回 ResourceExhausted
+	releaseExport, err := limiter.Acquire(context.Background(), "/report.Report/Export")
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer releaseExport()
+	if _, err := limiter.Acquire(context.Background(), "/report.Report/Export"); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected queue timeout ResourceExhausted, got %v", err)
+	}
+
+	// 排队期间 context 结束返回对应状态码
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.Acquire(ctx, "/report.Report/Export"); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestConcurrencyLimitConfigValidate(t *testing.T) {
+	invalid := []ConcurrencyLimitConfig{
+		{MaxInFlight: -1},
+		{QueueTimeout: types.Duration(-time.Second)},
+		{Methods: map[string]MethodConcurrencyLimit{"[": {MaxInFlight: 1}}},
+		{Methods: map[string]MethodConcurrencyLimit{"/svc.Svc/Call": {MaxQueue: -1}}},
+	}
+	for _, config := range invalid {
+		if err := config.Validate(); err == nil {
+			t.Fatalf("expected validation error for %+v", config)
+		}
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -11,8 +11,6 @@ import (
 	"time"
 
 	clientv3 "go.etcd.io/etcd/client/v3"
-
-	"github.com/team-dandelion/quickgo/logger"
 )
 
 const (
@@ -30,6 +28,9 @@ type EtcdConfig struct {
 	TTL         int64         // 租约 TTL（秒），默认为 30
 	Username    string        // 用户名（可选）
 	Password    string        // 密码（可选）
+
+	ReregisterBackoff    time.Duration // 租约丢失后重新注册的初始退避间隔，默认为 1s
+	ReregisterMaxBackoff time.Duration // 重新注册的最大退避间隔，默认为 30s
 }
 
 // EtcdResolver etcd 服务发现实现
@@ -65,6 +66,7 @@ func NewEtcdResolver(config EtcdConfig) (*EtcdResolver, error) {
 	etcdConfig := clientv3.Config{
 		Endpoints:   config.Endpoints,
 		DialTimeout: config.DialTimeout,
+		Logger:      newEtcdLogger(),
 	}
 
 	if config.Username != "" && config.Password != "" {
@@ -92,6 +94,15 @@ func (r *EtcdResolver) DiscoveryKey() string {
 
 // Resolve 解析服务地址
 func (r *EtcdResolver) Resolve(ctx context.Context, serviceName string) ([]string, error) {
+	instances, err := r.ResolveInstances(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	return instanceAddressList(instances), nil
+}
+
+// ResolveInstances 解析服务实例（包含注册时写入的元数据与权重）
+func (r *EtcdResolver) ResolveInstances(ctx context.Context, serviceName string) ([]ServiceInfo, error) {
 	key := path.Join(r.prefix, serviceName)
 
 	resp, err := r.client.Get(ctx, key, clientv3.WithPrefix())
@@ -99,31 +110,59 @@ func (r *EtcdResolver) Resolve(ctx context.Context, serviceName string) ([]strin
 		return nil, fmt.Errorf("failed to get service from etcd: %w", err)
 	}
 
-	addresses := make([]string, 0, len(resp.Kvs))
+	instances := make([]ServiceInfo, 0, len(resp.Kvs))
 	seen := make(map[string]bool)
 
 	for _, kv := range resp.Kvs {
-		// 从 key 中提取地址，格式：/prefix/service-name/address
-		keyStr := string(kv.Key)
-		parts := strings.Split(keyStr, "/")
-		if len(parts) > 0 {
-			addr := parts[len(parts)-1]
-			if !seen[addr] {
-				addresses = append(addresses, addr)
-				seen[addr] = true
-			}
+		info, ok := parseEtcdInstance(serviceName, string(kv.Key), kv.Value)
+		if ok && !seen[info.Address] {
+			instances = append(instances, info)
+			seen[info.Address] = true
 		}
 	}
 
-	if len(addresses) == 0 {
+	if len(instances) == 0 {
 		return nil, fmt.Errorf("no addresses found for service: %s", serviceName)
 	}
 
-	return addresses, nil
+	return instances, nil
+}
+
+// parseEtcdInstance 解析注册记录：key 格式为 /prefix/service-name/address，value 为元数据 JSON 或地址
+func parseEtcdInstance(serviceName, key string, value []byte) (ServiceInfo, bool) {
+	parts := strings.Split(key, "/")
+	addr := parts[len(parts)-1]
+	if addr == "" {
+		return ServiceInfo{}, false
+	}
+	info := ServiceInfo{Name: serviceName, Address: addr}
+	if len(value) > 0 && value[0] == '{' {
+		var metadata map[string]string
+		if err := json.Unmarshal(value, &metadata); err == nil {
+			info.Metadata = metadata
+		}
+	}
+	info.Weight = metadataWeight(info.Metadata)
+	return info, true
+}
+
+func instanceAddressList(instances []ServiceInfo) []string {
+	addresses := make([]string, 0, len(instances))
+	for _, info := range instances {
+		addresses = append(addresses, info.Address)
+	}
+	return addresses
 }
 
 // Watch 监听服务变化
 func (r *EtcdResolver) Watch(ctx context.Context, serviceName string, callback func([]string)) error {
+	return r.WatchInstances(ctx, serviceName, func(instances []ServiceInfo) {
+		callback(instanceAddressList(instances))
+	})
+}
+
+// WatchInstances 监听服务实例变化（实例增减或元数据变化时回调）
+func (r *EtcdResolver) WatchInstances(ctx context.Context, serviceName string, callback func([]ServiceInfo)) error {
 	key := path.Join(r.prefix, serviceName)
 
 	r.mu.Lock()
@@ -139,9 +178,9 @@ func (r *EtcdResolver) Watch(ctx context.Context, serviceName string, callback f
 	r.mu.Unlock()
 
 	// 首次获取
-	addresses, err := r.Resolve(watchCtx, serviceName)
+	instances, err := r.ResolveInstances(watchCtx, serviceName)
 	if err == nil {
-		callback(addresses)
+		callback(instances)
 	}
 
 	// 监听变化
@@ -167,10 +206,10 @@ func (r *EtcdResolver) Watch(ctx context.Context, serviceName string, callback f
 					return
 				}
 
-				// 重新解析服务地址
-				addresses, err := r.Resolve(watchCtx, serviceName)
+				// 重新解析服务实例
+				instances, err := r.ResolveInstances(watchCtx, serviceName)
 				if err == nil {
-					callback(addresses)
+					callback(instances)
 				}
 			}
 		}
@@ -202,174 +241,6 @@ func (r *EtcdResolver) Close() error {
 	return nil
 }
 
-// EtcdRegistry etcd 服务注册实现
-type EtcdRegistry struct {
-	client    *clientv3.Client
-	prefix    string
-	ttl       int64
-	leaseID   clientv3.LeaseID
-	leaseKeep <-chan *clientv3.LeaseKeepAliveResponse
-	mu        sync.RWMutex
-}
-
-// NewEtcdRegistry 创建 etcd 服务注册
-func NewEtcdRegistry(config EtcdConfig) (*EtcdRegistry, error) {
-	if len(config.Endpoints) == 0 {
-		return nil, fmt.Errorf("etcd endpoints are required")
-	}
-
-	if config.DialTimeout == 0 {
-		config.DialTimeout = 5 * time.Second
-	}
-
-	if config.Prefix == "" {
-		config.Prefix = DefaultEtcdPrefix
-	}
-
-	if config.TTL == 0 {
-		config.TTL = DefaultEtcdTTL
-	}
-
-	etcdConfig := clientv3.Config{
-		Endpoints:   config.Endpoints,
-		DialTimeout: config.DialTimeout,
-	}
-
-	if config.Username != "" && config.Password != "" {
-		etcdConfig.Username = config.Username
-		etcdConfig.Password = config.Password
-	}
-
-	client, err := clientv3.New(etcdConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create etcd client: %w", err)
-	}
-
-	return &EtcdRegistry{
-		client: client,
-		prefix: config.Prefix,
-		ttl:    config.TTL,
-	}, nil
-}
-
-// Register 注册服务
-func (r *EtcdRegistry) Register(ctx context.Context, serviceName, address string, metadata map[string]string) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	// 创建租约
-	leaseResp, err := r.client.Grant(ctx, r.ttl)
-	if err != nil {
-		return fmt.Errorf("failed to create lease: %w", err)
-	}
-	r.leaseID = leaseResp.ID
-
-	// 构建 key，格式：/prefix/service-name/address
-	key := path.Join(r.prefix, serviceName, address)
-
-	// 构建 value（包含元数据）
-	value := address
-	if len(metadata) > 0 {
-		metadataJSON, err := json.Marshal(metadata)
-		if err == nil {
-			value = string(metadataJSON)
-		}
-	}
-
-	// 注册服务
-	_, err = r.client.Put(ctx, key, value, clientv3.WithLease(r.leaseID))
-	if err != nil {
-		_, _ = r.client.Revoke(ctx, r.leaseID)
-		r.leaseID = 0
-		return fmt.Errorf("failed to register service: %w", err)
-	}
-
-	// 启动心跳保持（使用独立的 context，因为心跳需要持续运行）
-	keepAliveCtx := context.Background()
-	r.leaseKeep, err = r.client.KeepAlive(keepAliveCtx, r.leaseID)
-	if err != nil {
-		_, _ = r.client.Revoke(ctx, r.leaseID)
-		r.leaseID = 0
-		return fmt.Errorf("failed to start keepalive: %w", err)
-	}
-
-	// 处理心跳响应
-	go func() {
-		for ka := range r.leaseKeep {
-			if ka == nil {
-				logger.Warn(keepAliveCtx, "KeepAlive channel closed: service=%s, address=%s", serviceName, address)
-				return
-			}
-		}
-	}()
-
-	logger.Info(ctx, "Service registered to etcd: service=%s, address=%s, key=%s", serviceName, address, key)
-	return nil
-}
-
-// Deregister 注销服务
-func (r *EtcdRegistry) Deregister(ctx context.Context, serviceName, address string) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	// 撤销租约（会自动停止心跳）
-	if r.leaseID != 0 {
-		_, err := r.client.Revoke(ctx, r.leaseID)
-		if err != nil {
-			logger.Error(ctx, "Failed to revoke lease: leaseID=%d, error=%v", r.leaseID, err)
-		}
-		r.leaseID = 0
-		r.leaseKeep = nil
-	}
-
-	// 删除 key
-	key := path.Join(r.prefix, serviceName, address)
-	_, err := r.client.Delete(ctx, key)
-	if err != nil {
-		return fmt.Errorf("failed to deregister service: %w", err)
-	}
-
-	logger.Info(ctx, "Service deregistered from etcd: service=%s, address=%s", serviceName, address)
-	return nil
-}
-
-// KeepAlive 保持服务活跃（心跳）
-func (r *EtcdRegistry) KeepAlive(ctx context.Context, serviceName, address string) error {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	if r.leaseID == 0 {
-		return fmt.Errorf("service not registered")
-	}
-
-	// 续约
-	_, err := r.client.KeepAliveOnce(ctx, r.leaseID)
-	if err != nil {
-		return fmt.Errorf("failed to keepalive: %w", err)
-	}
-
-	return nil
-}
-
-// Close 关闭注册中心连接
-func (r *EtcdRegistry) Close() error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	// 撤销租约（会自动停止心跳）
-	if r.leaseID != 0 {
-		ctx := context.Background()
-		_, _ = r.client.Revoke(ctx, r.leaseID)
-		r.leaseID = 0
-		r.leaseKeep = nil
-	}
-
-	if r.client != nil {
-		return r.client.Close()
-	}
-	return nil
-}
-
 // RegisterEtcdResolver 注册 etcd resolver
 func RegisterEtcdResolver(config EtcdConfig) error {
 	resolver, err := NewEtcdResolver(config)
@@ -0,0 +1,71 @@
+package grpc
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// etcdLogCore 将 etcd clientv3 的 zap 日志转发到框架 logger
+// 日志级别由 logger.SetLibraryLevel(logger.LibraryEtcd, ...) 控制
+type etcdLogCore struct {
+	fields []zapcore.Field
+}
+
+// newEtcdLogger 创建转发到框架 logger 的 zap.Logger
+func newEtcdLogger() *zap.Logger {
+	return zap.New(&etcdLogCore{})
+}
+
+func (c *etcdLogCore) Enabled(level zapcore.Level) bool {
+	return logger.LibraryEnabled(logger.LibraryEtcd, zapLevelToLogger(level))
+}
+
+func (c *etcdLogCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &etcdLogCore{fields: merged}
+}
+
+func (c *etcdLogCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *etcdLogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range c.fields {
+		field.AddTo(enc)
+	}
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	if entry.LoggerName != "" {
+		enc.Fields["logger"] = entry.LoggerName
+	}
+	logger.LogLibrary(context.Background(), logger.LibraryEtcd, zapLevelToLogger(entry.Level), entry.Message, enc.Fields)
+	return nil
+}
+
+func (c *etcdLogCore) Sync() error {
+	return nil
+}
+
+func zapLevelToLogger(level zapcore.Level) logger.Level {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return logger.LevelDebug
+	case level == zapcore.InfoLevel:
+		return logger.LevelInfo
+	case level == zapcore.WarnLevel:
+		return logger.LevelWarn
+	default:
+		return logger.LevelError
+	}
+}
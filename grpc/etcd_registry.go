@@ -0,0 +1,397 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"path"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+const (
+	defaultReregisterBackoff    = time.Second
+	defaultReregisterMaxBackoff = 30 * time.Second
+	// etcdRevokeTimeout 撤销租约的超时时间（etcd 不可用时避免关闭流程阻塞）
+	etcdRevokeTimeout = 5 * time.Second
+)
+
+// RegistrationEventType 服务注册事件类型
+type RegistrationEventType string
+
+const (
+	// RegistrationRegistered 实例已注册
+	RegistrationRegistered RegistrationEventType = "registered"
+	// RegistrationLeaseLost 租约丢失（过期或心跳通道关闭），实例已从注册中心消失
+	RegistrationLeaseLost RegistrationEventType = "lease_lost"
+	// RegistrationReregistered 租约丢失后重新注册成功
+	RegistrationReregistered RegistrationEventType = "reregistered"
+	// RegistrationReregisterFailed 重新注册失败，将按退避间隔继续重试
+	RegistrationReregisterFailed RegistrationEventType = "reregister_failed"
+	// RegistrationDeregistered 实例已注销
+	RegistrationDeregistered RegistrationEventType = "deregistered"
+)
+
+// RegistrationEvent 服务注册事件
+type RegistrationEvent struct {
+	Type        RegistrationEventType
+	ServiceName string
+	Address     string
+	LeaseID     clientv3.LeaseID
+	// 重新注册的尝试次数（仅 RegistrationReregistered / RegistrationReregisterFailed）
+	Attempt int
+	Err     error
+}
+
+// etcdRegistryClient EtcdRegistry 使用的 etcd 操作（*clientv3.Client 已实现）
+type etcdRegistryClient interface {
+	Grant(ctx context.Context, ttl int64) (*clientv3.LeaseGrantResponse, error)
+	Revoke(ctx context.Context, id clientv3.LeaseID) (*clientv3.LeaseRevokeResponse, error)
+	KeepAlive(ctx context.Context, id clientv3.LeaseID) (<-chan *clientv3.LeaseKeepAliveResponse, error)
+	KeepAliveOnce(ctx context.Context, id clientv3.LeaseID) (*clientv3.LeaseKeepAliveResponse, error)
+	Put(ctx context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error)
+	Delete(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error)
+	Close() error
+}
+
+// EtcdRegistry etcd 服务注册实现
+// 每个注册的实例持有独立的租约，租约丢失（如 etcd 重启、网络分区导致过期）时自动重新申请租约并写入，
+// 通过 OnEvent 可观测注册状态
+type EtcdRegistry struct {
+	client        etcdRegistryClient
+	prefix        string
+	ttl           int64
+	backoff       time.Duration
+	maxBackoff    time.Duration
+	instances     map[string]*etcdInstance
+	mu            sync.Mutex
+	handlers      map[int]func(RegistrationEvent)
+	nextHandlerID int
+	handlersMu    sync.RWMutex
+}
+
+// etcdInstance 单个注册实例
+type etcdInstance struct {
+	serviceName string
+	address     string
+	key         string
+	value       string
+	leaseID     atomic.Int64
+	healthy     atomic.Bool
+	ctx         context.Context
+	cancel      context.CancelFunc
+	done        chan struct{}
+}
+
+func (inst *etcdInstance) lease() clientv3.LeaseID {
+	return clientv3.LeaseID(inst.leaseID.Load())
+}
+
+// NewEtcdRegistry 创建 etcd 服务注册
+func NewEtcdRegistry(config EtcdConfig) (*EtcdRegistry, error) {
+	if len(config.Endpoints) == 0 {
+		return nil, fmt.Errorf("etcd endpoints are required")
+	}
+
+	if config.DialTimeout == 0 {
+		config.DialTimeout = 5 * time.Second
+	}
+
+	etcdConfig := clientv3.Config{
+		Endpoints:   config.Endpoints,
+		DialTimeout: config.DialTimeout,
+		Logger:      newEtcdLogger(),
+	}
+
+	if config.Username != "" && config.Password != "" {
+		etcdConfig.Username = config.Username
+		etcdConfig.Password = config.Password
+	}
+
+	client, err := clientv3.New(etcdConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create etcd client: %w", err)
+	}
+
+	return newEtcdRegistry(client, config), nil
+}
+
+func newEtcdRegistry(client etcdRegistryClient, config EtcdConfig) *EtcdRegistry {
+	if config.Prefix == "" {
+		config.Prefix = DefaultEtcdPrefix
+	}
+	if config.TTL == 0 {
+		config.TTL = DefaultEtcdTTL
+	}
+	if config.ReregisterBackoff <= 0 {
+		config.ReregisterBackoff = defaultReregisterBackoff
+	}
+	if config.ReregisterMaxBackoff <= 0 {
+		config.ReregisterMaxBackoff = defaultReregisterMaxBackoff
+	}
+	if config.ReregisterMaxBackoff < config.ReregisterBackoff {
+		config.ReregisterMaxBackoff = config.ReregisterBackoff
+	}
+	return &EtcdRegistry{
+		client:     client,
+		prefix:     config.Prefix,
+		ttl:        config.TTL,
+		backoff:    config.ReregisterBackoff,
+		maxBackoff: config.ReregisterMaxBackoff,
+		instances:  make(map[string]*etcdInstance),
+		handlers:   make(map[int]func(RegistrationEvent)),
+	}
+}
+
+// OnEvent 订阅注册事件，返回取消订阅函数；回调在注册或心跳协程中同步执行，不应阻塞
+func (r *EtcdRegistry) OnEvent(fn func(RegistrationEvent)) func() {
+	r.handlersMu.Lock()
+	id := r.nextHandlerID
+	r.nextHandlerID++
+	r.handlers[id] = fn
+	r.handlersMu.Unlock()
+	return func() {
+		r.handlersMu.Lock()
+		delete(r.handlers, id)
+		r.handlersMu.Unlock()
+	}
+}
+
+func (r *EtcdRegistry) emit(event RegistrationEvent) {
+	r.handlersMu.RLock()
+	handlers := make([]func(RegistrationEvent), 0, len(r.handlers))
+	for _, fn := range r.handlers {
+		handlers = append(handlers, fn)
+	}
+	r.handlersMu.RUnlock()
+	for _, fn := range handlers {
+		fn(event)
+	}
+}
+
+// IsRegistered 实例是否已注册且租约有效
+func (r *EtcdRegistry) IsRegistered(serviceName, address string) bool {
+	r.mu.Lock()
+	inst := r.instances[path.Join(r.prefix, serviceName, address)]
+	r.mu.Unlock()
+	return inst != nil && inst.healthy.Load()
+}
+
+// Register 注册服务
+func (r *EtcdRegistry) Register(ctx context.Context, serviceName, address string, metadata map[string]string) error {
+	// 构建 key，格式：/prefix/service-name/address
+	key := path.Join(r.prefix, serviceName, address)
+
+	// 构建 value（包含元数据）
+	value := address
+	if len(metadata) > 0 {
+		metadataJSON, err := json.Marshal(metadata)
+		if err == nil {
+			value = string(metadataJSON)
+		}
+	}
+
+	r.mu.Lock()
+	// 重复注册同一实例时替换旧注册（旧租约随之撤销）
+	if old := r.instances[key]; old != nil {
+		delete(r.instances, key)
+		r.stopInstance(old)
+	}
+
+	leaseID, err := r.grantAndPut(ctx, key, value)
+	if err != nil {
+		r.mu.Unlock()
+		return err
+	}
+
+	// 心跳使用独立的 context，随实例注销而取消
+	instCtx, cancel := context.WithCancel(context.Background())
+	inst := &etcdInstance{
+		serviceName: serviceName,
+		address:     address,
+		key:         key,
+		value:       value,
+		ctx:         instCtx,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+	inst.leaseID.Store(int64(leaseID))
+	inst.healthy.Store(true)
+	r.instances[key] = inst
+	go r.maintain(inst)
+	r.mu.Unlock()
+
+	logger.Info(ctx, "Service registered to etcd: service=%s, address=%s, key=%s, lease=%x", serviceName, address, key, leaseID)
+	r.emit(RegistrationEvent{Type: RegistrationRegistered, ServiceName: serviceName, Address: address, LeaseID: leaseID})
+	return nil
+}
+
+// grantAndPut 申请新租约并写入实例 key
+func (r *EtcdRegistry) grantAndPut(ctx context.Context, key, value string) (clientv3.LeaseID, error) {
+	leaseResp, err := r.client.Grant(ctx, r.ttl)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create lease: %w", err)
+	}
+	if _, err := r.client.Put(ctx, key, value, clientv3.WithLease(leaseResp.ID)); err != nil {
+		r.revoke(leaseResp.ID)
+		return 0, fmt.Errorf("failed to register service: %w", err)
+	}
+	return leaseResp.ID, nil
+}
+
+// revoke 尽力撤销租约（会同时删除绑定的 key 并停止心跳）
+func (r *EtcdRegistry) revoke(leaseID clientv3.LeaseID) {
+	if leaseID == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), etcdRevokeTimeout)
+	defer cancel()
+	if _, err := r.client.Revoke(ctx, leaseID); err != nil {
+		logger.Warn(ctx, "Failed to revoke lease: lease=%x, error=%v", leaseID, err)
+	}
+}
+
+// maintain 持续为实例续约，心跳通道关闭时视为租约丢失并重新注册
+func (r *EtcdRegistry) maintain(inst *etcdInstance) {
+	defer close(inst.done)
+	for {
+		keepAlive, err := r.client.KeepAlive(inst.ctx, inst.lease())
+		if err == nil {
+			// 通道在租约过期、续约失败或 ctx 取消时关闭
+			for range keepAlive {
+			}
+		}
+		if inst.ctx.Err() != nil {
+			return
+		}
+
+		inst.healthy.Store(false)
+		if err == nil {
+			err = fmt.Errorf("keepalive channel closed")
+		}
+		logger.Warn(inst.ctx, "Etcd lease lost, re-registering: service=%s, address=%s, lease=%x, error=%v", inst.serviceName, inst.address, inst.lease(), err)
+		r.emit(RegistrationEvent{Type: RegistrationLeaseLost, ServiceName: inst.serviceName, Address: inst.address, LeaseID: inst.lease(), Err: err})
+		if !r.reregister(inst) {
+			return
+		}
+	}
+}
+
+// reregister 按指数退避重新申请租约并写入，实例注销时返回 false
+func (r *EtcdRegistry) reregister(inst *etcdInstance) bool {
+	delay := r.backoff
+	for attempt := 1; ; attempt++ {
+		leaseID, err := r.grantAndPut(inst.ctx, inst.key, inst.value)
+		if inst.ctx.Err() != nil {
+			if err == nil {
+				r.revoke(leaseID)
+			}
+			return false
+		}
+		if err == nil {
+			// 旧租约可能仍然存活（如仅心跳流中断），key 已绑定到新租约，撤销旧租约不影响注册
+			old := clientv3.LeaseID(inst.leaseID.Swap(int64(leaseID)))
+			if old != leaseID {
+				r.revoke(old)
+			}
+			inst.healthy.Store(true)
+			logger.Info(inst.ctx, "Service re-registered to etcd: service=%s, address=%s, lease=%x, attempt=%d", inst.serviceName, inst.address, leaseID, attempt)
+			r.emit(RegistrationEvent{Type: RegistrationReregistered, ServiceName: inst.serviceName, Address: inst.address, LeaseID: leaseID, Attempt: attempt})
+			return true
+		}
+
+		wait := jitterBackoff(delay)
+		logger.Error(inst.ctx, "Failed to re-register service to etcd: service=%s, address=%s, attempt=%d, retry_in=%v, error=%v", inst.serviceName, inst.address, attempt, wait, err)
+		r.emit(RegistrationEvent{Type: RegistrationReregisterFailed, ServiceName: inst.serviceName, Address: inst.address, Attempt: attempt, Err: err})
+		timer := time.NewTimer(wait)
+		select {
+		case <-inst.ctx.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+		delay = min(delay*2, r.maxBackoff)
+	}
+}
+
+// jitterBackoff 为退避间隔增加 ±20% 抖动，避免大量实例同时重连 etcd
+func jitterBackoff(delay time.Duration) time.Duration {
+	return time.Duration(float64(delay) * (0.8 + 0.4*rand.Float64()))
+}
+
+// stopInstance 停止实例的心跳协程并撤销租约
+func (r *EtcdRegistry) stopInstance(inst *etcdInstance) {
+	inst.cancel()
+	<-inst.done
+	inst.healthy.Store(false)
+	r.revoke(inst.lease())
+}
+
+// Deregister 注销服务
+func (r *EtcdRegistry) Deregister(ctx context.Context, serviceName, address string) error {
+	key := path.Join(r.prefix, serviceName, address)
+
+	r.mu.Lock()
+	inst := r.instances[key]
+	delete(r.instances, key)
+	r.mu.Unlock()
+
+	var leaseID clientv3.LeaseID
+	if inst != nil {
+		leaseID = inst.lease()
+		r.stopInstance(inst)
+	}
+
+	// 删除 key
+	if _, err := r.client.Delete(ctx, key); err != nil {
+		return fmt.Errorf("failed to deregister service: %w", err)
+	}
+
+	logger.Info(ctx, "Service deregistered from etcd: service=%s, address=%s", serviceName, address)
+	if inst != nil {
+		r.emit(RegistrationEvent{Type: RegistrationDeregistered, ServiceName: serviceName, Address: address, LeaseID: leaseID})
+	}
+	return nil
+}
+
+// KeepAlive 保持服务活跃（立即续约一次，常规续约由注册时启动的心跳协程负责）
+func (r *EtcdRegistry) KeepAlive(ctx context.Context, serviceName, address string) error {
+	r.mu.Lock()
+	inst := r.instances[path.Join(r.prefix, serviceName, address)]
+	r.mu.Unlock()
+
+	if inst == nil {
+		return fmt.Errorf("service not registered")
+	}
+
+	// 续约
+	if _, err := r.client.KeepAliveOnce(ctx, inst.lease()); err != nil {
+		return fmt.Errorf("failed to keepalive: %w", err)
+	}
+
+	return nil
+}
+
+// Close 关闭注册中心连接
+func (r *EtcdRegistry) Close() error {
+	r.mu.Lock()
+	instances := r.instances
+	r.instances = make(map[string]*etcdInstance)
+	r.mu.Unlock()
+
+	// 撤销各实例租约（会自动删除 key 并停止心跳）
+	for _, inst := range instances {
+		r.stopInstance(inst)
+	}
+
+	if r.client != nil {
+		return r.client.Close()
+	}
+	return nil
+}
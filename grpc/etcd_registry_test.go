@@ -0,0 +1,220 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// fakeEtcdClient 内存实现的 etcd 租约与 KV 操作
+type fakeEtcdClient struct {
+	mu         sync.Mutex
+	nextLease  clientv3.LeaseID
+	leases     map[clientv3.LeaseID]chan struct{}
+	keys       map[string]clientv3.LeaseID
+	grantFails int
+	closed     bool
+}
+
+func newFakeEtcdClient() *fakeEtcdClient {
+	return &fakeEtcdClient{
+		leases: make(map[clientv3.LeaseID]chan struct{}),
+		keys:   make(map[string]clientv3.LeaseID),
+	}
+}
+
+func (c *fakeEtcdClient) Grant(ctx context.Context, ttl int64) (*clientv3.LeaseGrantResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.grantFails > 0 {
+		c.grantFails--
+		return nil, errors.New("etcd unavailable")
+	}
+	c.nextLease++
+	c.leases[c.nextLease] = make(chan struct{})
+	return &clientv3.LeaseGrantResponse{ID: c.nextLease, TTL: ttl}, nil
+}
+
+func (c *fakeEtcdClient) Revoke(ctx context.Context, id clientv3.LeaseID) (*clientv3.LeaseRevokeResponse, error) {
+	c.expire(id)
+	return &clientv3.LeaseRevokeResponse{}, nil
+}
+
+// expire 模拟租约过期：删除绑定的 key 并关闭心跳通道
+func (c *fakeEtcdClient) expire(id clientv3.LeaseID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if lost, ok := c.leases[id]; ok {
+		close(lost)
+		delete(c.leases, id)
+	}
+	for key, lease := range c.keys {
+		if lease == id {
+			delete(c.keys, key)
+		}
+	}
+}
+
+func (c *fakeEtcdClient) KeepAlive(ctx context.Context, id clientv3.LeaseID) (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	c.mu.Lock()
+	lost, ok := c.leases[id]
+	c.mu.Unlock()
+	ch := make(chan *clientv3.LeaseKeepAliveResponse)
+	if !ok {
+		close(ch)
+		return ch, nil
+	}
+	go func() {
+		defer close(ch)
+		select {
+		case <-ctx.Done():
+		case <-lost:
+		}
+	}()
+	return ch, nil
+}
+
+func (c *fakeEtcdClient) KeepAliveOnce(ctx context.Context, id clientv3.LeaseID) (*clientv3.LeaseKeepAliveResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.leases[id]; !ok {
+		return nil, errors.New("lease not found")
+	}
+	return &clientv3.LeaseKeepAliveResponse{ID: id}, nil
+}
+
+func (c *fakeEtcdClient) Put(ctx context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	// clientv3.Op 未导出租约 ID，通过反射读取 WithLease 设置的值
+	op := clientv3.OpPut(key, val, opts...)
+	lease := clientv3.LeaseID(reflect.ValueOf(op).FieldByName("leaseID").Int())
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.keys[key] = lease
+	return &clientv3.PutResponse{}, nil
+}
+
+func (c *fakeEtcdClient) Delete(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.keys, key)
+	return &clientv3.DeleteResponse{}, nil
+}
+
+func (c *fakeEtcdClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+func (c *fakeEtcdClient) leaseOf(key string) (clientv3.LeaseID, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	lease, ok := c.keys[key]
+	return lease, ok
+}
+
+func collectRegistrationEvents(registry *EtcdRegistry) (<-chan RegistrationEvent, func()) {
+	events := make(chan RegistrationEvent, 32)
+	return events, registry.OnEvent(func(event RegistrationEvent) {
+		events <- event
+	})
+}
+
+func waitRegistrationEvent(t *testing.T, events <-chan RegistrationEvent, want RegistrationEventType) RegistrationEvent {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case event := <-events:
+			if event.Type == want {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s event", want)
+		}
+	}
+}
+
+func TestEtcdRegistryIsolatesLeasesPerInstance(t *testing.T) {
+	client := newFakeEtcdClient()
+	registry := newEtcdRegistry(client, EtcdConfig{Prefix: "/svc"})
+	ctx := context.Background()
+
+	if err := registry.Register(ctx, "orders", "10.0.0.1:9000", nil); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := registry.Register(ctx, "orders", "10.0.0.2:9000", map[string]string{"weight": "5"}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	first, _ := client.leaseOf("/svc/orders/10.0.0.1:9000")
+	second, _ := client.leaseOf("/svc/orders/10.0.0.2:9000")
+	if first == 0 || second == 0 || first == second {
+		t.Fatalf("expected distinct leases per instance, got %x and %x", first, second)
+	}
+
+	if err := registry.Deregister(ctx, "orders", "10.0.0.1:9000"); err != nil {
+		t.Fatalf("Deregister failed: %v", err)
+	}
+	if _, ok := client.leaseOf("/svc/orders/10.0.0.1:9000"); ok {
+		t.Fatal("expected deregistered key to be removed")
+	}
+	if lease, ok := client.leaseOf("/svc/orders/10.0.0.2:9000"); !ok || lease != second {
+		t.Fatal("expected other instance to keep its registration and lease")
+	}
+	if err := registry.KeepAlive(ctx, "orders", "10.0.0.2:9000"); err != nil {
+		t.Fatalf("KeepAlive failed: %v", err)
+	}
+
+	if err := registry.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, ok := client.leaseOf("/svc/orders/10.0.0.2:9000"); ok || !client.closed {
+		t.Fatal("expected Close to revoke remaining leases and close the client")
+	}
+}
+
+func TestEtcdRegistryReregistersAfterLeaseLoss(t *testing.T) {
+	client := newFakeEtcdClient()
+	registry := newEtcdRegistry(client, EtcdConfig{Prefix: "/svc", ReregisterBackoff: time.Millisecond, ReregisterMaxBackoff: 5 * time.Millisecond})
+	defer registry.Close()
+	events, unsubscribe := collectRegistrationEvents(registry)
+	defer unsubscribe()
+
+	if err := registry.Register(context.Background(), "orders", "10.0.0.1:9000", nil); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	waitRegistrationEvent(t, events, RegistrationRegistered)
+	lease, _ := client.leaseOf("/svc/orders/10.0.0.1:9000")
+
+	// 模拟 etcd 重启：租约过期且前两次重新申请失败
+	client.mu.Lock()
+	client.grantFails = 2
+	client.mu.Unlock()
+	client.expire(lease)
+
+	lost := waitRegistrationEvent(t, events, RegistrationLeaseLost)
+	if lost.LeaseID != lease {
+		t.Fatalf("expected lost lease %x, got %x", lease, lost.LeaseID)
+	}
+	for attempt := 1; attempt <= 2; attempt++ {
+		if failed := waitRegistrationEvent(t, events, RegistrationReregisterFailed); failed.Attempt != attempt || failed.Err == nil {
+			t.Fatalf("unexpected failure event: %+v", failed)
+		}
+	}
+	reregistered := waitRegistrationEvent(t, events, RegistrationReregistered)
+	if reregistered.Attempt != 3 || reregistered.LeaseID == lease {
+		t.Fatalf("unexpected reregistered event: %+v", reregistered)
+	}
+	if current, ok := client.leaseOf("/svc/orders/10.0.0.1:9000"); !ok || current != reregistered.LeaseID {
+		t.Fatalf("expected key to be bound to new lease %x, got %x", reregistered.LeaseID, current)
+	}
+	if !registry.IsRegistered("orders", "10.0.0.1:9000") {
+		t.Fatal("expected instance to be healthy after re-registration")
+	}
+}
@@ -150,7 +150,7 @@ func ExampleClientUsage() {
 	}
 
 	// 使用客户端连接调用服务
-	// conn := client.GetConn()
+	// conn := client.Conn()
 	// serviceClient := pb.NewYourServiceClient(conn)
 	// resp, err := serviceClient.YourMethod(ctx, &pb.YourRequest{...})
 }
@@ -247,7 +247,7 @@ func ExampleClientWithTimeout() {
 	defer cancel()
 
 	// 使用超时context调用服务
-	// serviceClient := pb.NewYourServiceClient(client.GetConn())
+	// serviceClient := pb.NewYourServiceClient(client.Conn())
 	// resp, err := serviceClient.YourMethod(timeoutCtx, &pb.YourRequest{...})
 	_ = timeoutCtx
 }
@@ -366,7 +366,7 @@ func ExampleLoadBalancing() {
 	}
 
 	// 多次调用会自动在多个服务实例间负载均衡
-	// serviceClient := pb.NewYourServiceClient(client.GetConn())
+	// serviceClient := pb.NewYourServiceClient(client.Conn())
 	// for i := 0; i < 10; i++ {
 	//     resp, err := serviceClient.YourMethod(ctx, &pb.YourRequest{...})
 	//     // 请求会自动分发到不同的服务实例
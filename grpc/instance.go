@@ -0,0 +1,106 @@
+package grpc
+
+import (
+	"context"
+	"maps"
+	"strconv"
+
+	"google.golang.org/grpc/resolver"
+)
+
+// 注册元数据中的约定字段
+const (
+	// MetadataVersion 服务版本，示例：2.0.0
+	MetadataVersion = "version"
+	// MetadataZone 可用区，缺省时使用 region
+	MetadataZone = "zone"
+	// MetadataRegion 地域
+	MetadataRegion = "region"
+	// MetadataWeight 负载均衡权重（正整数，默认 1）
+	MetadataWeight = "weight"
+)
+
+// InstanceDiscovery 可返回实例注册元数据的服务发现（EtcdResolver 已实现）
+// resolver 优先使用该接口，并将元数据以 Instance 的形式附加到 resolver.Address 上供负载均衡器使用
+type InstanceDiscovery interface {
+	// ResolveInstances 解析服务实例
+	ResolveInstances(ctx context.Context, serviceName string) ([]ServiceInfo, error)
+	// WatchInstances 监听服务实例变化
+	WatchInstances(ctx context.Context, serviceName string, callback func([]ServiceInfo)) error
+}
+
+// Instance 附加在 resolver.Address 上的实例元数据
+type Instance struct {
+	Address  string
+	Version  string
+	Zone     string
+	Weight   int
+	Metadata map[string]string
+}
+
+// NewInstance 根据注册信息创建实例元数据
+func NewInstance(info ServiceInfo) *Instance {
+	inst := &Instance{
+		Address:  info.Address,
+		Version:  info.Metadata[MetadataVersion],
+		Zone:     info.Metadata[MetadataZone],
+		Weight:   info.Weight,
+		Metadata: info.Metadata,
+	}
+	if inst.Zone == "" {
+		inst.Zone = info.Metadata[MetadataRegion]
+	}
+	if inst.Weight <= 0 {
+		inst.Weight = metadataWeight(info.Metadata)
+	}
+	return inst
+}
+
+// Equal 实现 attributes 比较，元数据变化时 gRPC 视为新地址并重建子连接
+func (i *Instance) Equal(o any) bool {
+	other, ok := o.(*Instance)
+	if !ok || i == nil || other == nil {
+		return ok && i == other
+	}
+	return i.Address == other.Address && i.Version == other.Version && i.Zone == other.Zone &&
+		i.Weight == other.Weight && maps.Equal(i.Metadata, other.Metadata)
+}
+
+type instanceKey struct{}
+
+// WithInstance 将实例元数据附加到地址上
+func WithInstance(addr resolver.Address, inst *Instance) resolver.Address {
+	addr.Attributes = addr.Attributes.WithValue(instanceKey{}, inst)
+	return addr
+}
+
+// InstanceFromAddress 获取地址上的实例元数据（服务发现不提供元数据时返回 false）
+func InstanceFromAddress(addr resolver.Address) (*Instance, bool) {
+	inst, ok := addr.Attributes.Value(instanceKey{}).(*Instance)
+	return inst, ok && inst != nil
+}
+
+// addressWeight 返回地址的负载均衡权重（无元数据时为 1）
+func addressWeight(addr resolver.Address) int {
+	if inst, ok := InstanceFromAddress(addr); ok && inst.Weight > 0 {
+		return inst.Weight
+	}
+	return 1
+}
+
+// metadataWeight 解析元数据中的权重，缺省或非法时为 1
+func metadataWeight(metadata map[string]string) int {
+	if weight, err := strconv.Atoi(metadata[MetadataWeight]); err == nil && weight > 0 {
+		return weight
+	}
+	return 1
+}
+
+// instanceAddresses 将服务实例转换为带元数据的 resolver 地址
+func instanceAddresses(instances []ServiceInfo) []resolver.Address {
+	addrs := make([]resolver.Address, 0, len(instances))
+	for _, info := range instances {
+		addrs = append(addrs, WithInstance(resolver.Address{Addr: info.Address}, NewInstance(info)))
+	}
+	return addrs
+}
@@ -0,0 +1,122 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/resolver"
+)
+
+type instanceDiscovery struct {
+	closeCountingDiscovery
+	instances []ServiceInfo
+}
+
+func (d *instanceDiscovery) ResolveInstances(ctx context.Context, serviceName string) ([]ServiceInfo, error) {
+	return d.instances, nil
+}
+
+func (d *instanceDiscovery) WatchInstances(ctx context.Context, serviceName string, callback func([]ServiceInfo)) error {
+	callback(d.instances)
+	return nil
+}
+
+type fakeSubConn struct {
+	balancer.SubConn
+	name string
+}
+
+func TestParseEtcdInstanceReadsMetadata(t *testing.T) {
+	info, ok := parseEtcdInstance("svc", "/quickgo/services/svc/10.0.0.1:9000",
+		[]byte(`{"version":"2.0.0","weight":"30","region":"sh","zone":"sh-a"}`))
+	if !ok || info.Address != "10.0.0.1:9000" || info.Weight != 30 {
+		t.Fatalf("unexpected instance: %+v", info)
+	}
+	inst := NewInstance(info)
+	if inst.Version != "2.0.0" || inst.Zone != "sh-a" || inst.Weight != 30 {
+		t.Fatalf("unexpected instance metadata: %+v", inst)
+	}
+
+	// 旧格式的 value 为地址本身
+	info, ok = parseEtcdInstance("svc", "/quickgo/services/svc/10.0.0.2:9000", []byte("10.0.0.2:9000"))
+	if !ok || info.Weight != 1 || NewInstance(info).Zone != "" {
+		t.Fatalf("expected legacy value to default weight 1, got %+v", info)
+	}
+}
+
+func TestInstanceAddressesAttachMetadata(t *testing.T) {
+	addrs := instanceAddresses([]ServiceInfo{
+		{Address: "10.0.0.1:9000", Metadata: map[string]string{MetadataVersion: "1.0.0", MetadataRegion: "bj", MetadataWeight: "5"}},
+	})
+	inst, ok := InstanceFromAddress(addrs[0])
+	if !ok || inst.Version != "1.0.0" || inst.Zone != "bj" || addressWeight(addrs[0]) != 5 {
+		t.Fatalf("unexpected address instance: %+v", inst)
+	}
+	if _, ok := InstanceFromAddress(resolver.Address{Addr: "10.0.0.1:9000"}); ok {
+		t.Fatal("expected plain address to carry no instance")
+	}
+
+	same := instanceAddresses([]ServiceInfo{
+		{Address: "10.0.0.1:9000", Metadata: map[string]string{MetadataVersion: "1.0.0", MetadataRegion: "bj", MetadataWeight: "5"}},
+	})
+	if !addrs[0].Equal(same[0]) {
+		t.Fatal("expected identical metadata to compare equal")
+	}
+	changed := instanceAddresses([]ServiceInfo{
+		{Address: "10.0.0.1:9000", Metadata: map[string]string{MetadataVersion: "1.0.0", MetadataRegion: "bj", MetadataWeight: "6"}},
+	})
+	if addrs[0].Equal(changed[0]) {
+		t.Fatal("expected weight change to produce a different address")
+	}
+}
+
+func TestServiceResolverPrefersInstanceDiscovery(t *testing.T) {
+	r := &serviceResolver{
+		sd: &instanceDiscovery{instances: []ServiceInfo{
+			{Address: "10.0.0.1:9000", Metadata: map[string]string{MetadataWeight: "3"}},
+		}},
+		ctx:         context.Background(),
+		serviceName: "svc",
+	}
+	addrs, err := r.resolveAddresses(context.Background(), "svc")
+	if err != nil || len(addrs) != 1 || addressWeight(addrs[0]) != 3 {
+		t.Fatalf("unexpected addresses: %+v, %v", addrs, err)
+	}
+
+	r.sd = &closeCountingDiscovery{}
+	addrs, err = r.resolveAddresses(context.Background(), "svc")
+	if err != nil || len(addrs) != 1 || addressWeight(addrs[0]) != 1 {
+		t.Fatalf("unexpected plain addresses: %+v, %v", addrs, err)
+	}
+}
+
+func TestWeightedPickerDistributesByWeight(t *testing.T) {
+	heavy, light := &fakeSubConn{name: "heavy"}, &fakeSubConn{name: "light"}
+	picker := (&weightedPickerBuilder{}).Build(base.PickerBuildInfo{
+		ReadySCs: map[balancer.SubConn]base.SubConnInfo{
+			heavy: {Address: instanceAddresses([]ServiceInfo{{Address: "10.0.0.1:9000", Weight: 3}})[0]},
+			light: {Address: resolver.Address{Addr: "10.0.0.2:9000"}},
+		},
+	})
+
+	counts := make(map[string]int)
+	var sequence []string
+	for i := 0; i < 8; i++ {
+		result, err := picker.Pick(balancer.PickInfo{})
+		if err != nil {
+			t.Fatalf("Pick failed: %v", err)
+		}
+		name := result.SubConn.(*fakeSubConn).name
+		counts[name]++
+		sequence = append(sequence, name)
+	}
+	if counts["heavy"] != 6 || counts["light"] != 2 {
+		t.Fatalf("expected 3:1 distribution, got %v", counts)
+	}
+	// 平滑加权：轻量实例不会被连续跳过整个周期
+	if sequence[0] != "heavy" || sequence[1] != "heavy" || sequence[2] != "light" {
+		t.Fatalf("expected smooth interleaving, got %v", sequence)
+	}
+}
@@ -3,15 +3,22 @@ package grpc
 import (
 	"context"
 	"fmt"
+	"runtime/debug"
 	"time"
 
+	"github.com/team-dandelion/quickgo/gerr"
 	"github.com/team-dandelion/quickgo/grpcep"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/recovery"
+	"github.com/team-dandelion/quickgo/tracing"
 )
 
 // UnaryInterceptor 一元拦截器类型
@@ -26,73 +33,233 @@ const TraceIDMetadataKey = "x-trace-id"
 // SpanIDMetadataKey span ID 在 metadata 中的 key
 const SpanIDMetadataKey = "x-span-id"
 
-// LoggingInterceptor 日志拦截器
+// TraceparentMetadataKey W3C traceparent 在 metadata 中的 key
+const TraceparentMetadataKey = tracing.TraceparentHeader
+
+// traceFromIncoming 从 metadata 中提取链路信息（优先 W3C traceparent，其次 x-trace-id / x-span-id）
+// 启用 OpenTelemetry 时 logger.GetTraceID 直接返回 span 的 trace ID，这里的值仅作兜底
+func traceFromIncoming(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	if values := md.Get(TraceparentMetadataKey); len(values) > 0 {
+		if traceID, spanID, ok := tracing.ParseTraceparent(values[0]); ok {
+			return logger.WithTrace(ctx, traceID, spanID)
+		}
+	}
+	if traceIDs := md.Get(TraceIDMetadataKey); len(traceIDs) > 0 && traceIDs[0] != "" {
+		ctx = logger.WithTraceID(ctx, traceIDs[0])
+	}
+	if spanIDs := md.Get(SpanIDMetadataKey); len(spanIDs) > 0 && spanIDs[0] != "" {
+		ctx = logger.WithSpanID(ctx, spanIDs[0])
+	}
+	return ctx
+}
+
+// traceToOutgoing 将链路信息写入 metadata：保留 x-trace-id / x-span-id，并携带 W3C traceparent
+// 已存在 traceparent（如 otelgrpc 已注入）时不覆盖
+func traceToOutgoing(ctx context.Context) context.Context {
+	traceID := logger.GetTraceID(ctx)
+	if traceID == "" {
+		return ctx
+	}
+	spanID := logger.GetSpanID(ctx)
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok {
+		md = metadata.New(nil)
+	}
+	md.Set(TraceIDMetadataKey, traceID)
+	if spanID != "" {
+		md.Set(SpanIDMetadataKey, spanID)
+	}
+	ctx = metadata.NewOutgoingContext(ctx, md)
+	if len(md.Get(TraceparentMetadataKey)) > 0 {
+		return ctx
+	}
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return tracing.InjectTraceContext(ctx)
+	}
+	if traceparent, ok := tracing.FormatTraceparent(traceID, spanID, false); ok {
+		md.Set(TraceparentMetadataKey, traceparent)
+	}
+	return ctx
+}
+
+// AccessLogConfig 服务端访问日志配置
+type AccessLogConfig struct {
+	// 是否使用两行模式：调用开始（gRPC call）与完成（gRPC call success/failed）各输出一行
+	// 默认每次调用完成时输出一行，包含方法、状态码、耗时、对端地址、请求/响应大小与截止时间
+	Split bool `json:"split" yaml:"split" toml:"split"`
+}
+
+// LoggingInterceptor 日志拦截器（每次调用完成时输出一行访问日志）
 func LoggingInterceptor() grpc.UnaryServerInterceptor {
+	return LoggingInterceptorWithConfig(AccessLogConfig{})
+}
+
+// LoggingInterceptorWithConfig 按配置创建日志拦截器
+func LoggingInterceptorWithConfig(config AccessLogConfig) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		start := time.Now()
 
 		// 从 metadata 中提取 trace ID 和 span ID
-		md, ok := metadata.FromIncomingContext(ctx)
-		if ok {
-			// 提取 trace ID
-			if traceIDs := md.Get(TraceIDMetadataKey); len(traceIDs) > 0 && traceIDs[0] != "" {
-				ctx = logger.WithTraceID(ctx, traceIDs[0])
-			}
-			// 提取 span ID（如果有）
-			if spanIDs := md.Get(SpanIDMetadataKey); len(spanIDs) > 0 && spanIDs[0] != "" {
-				ctx = logger.WithSpanID(ctx, spanIDs[0])
-			}
-		}
+		ctx = traceFromIncoming(ctx)
 
 		// 从 context 中提取或创建链路信息（如果没有从 metadata 获取到，则创建新的）
 		ctx = logger.StartSpan(ctx)
-
-		// 记录请求信息
-		logger.Info(ctx, "gRPC call: method=%s", info.FullMethod)
+		// 以方法名作为日志模块，支持按方法调整日志级别（含错误率自动提升）
+		ctx = logger.WithModule(ctx, info.FullMethod)
+
+		// 启用载荷采集或运行时开启了该方法的载荷日志时附带脱敏后的请求与响应
+		debugPayload := logger.PayloadLoggingEnabled(info.FullMethod)
+		requestPayload, hasRequestPayload := capturePayload(info.FullMethod, req, true, debugPayload)
+		if config.Split {
+			if hasRequestPayload {
+				logger.Info(ctx, "gRPC call: method=%s, request=%s", info.FullMethod, requestPayload)
+			} else {
+				logger.Info(ctx, "gRPC call: method=%s", info.FullMethod)
+			}
+		}
 
 		// 执行处理
 		resp, err := handler(ctx, req)
+		logger.DefaultBooster().Record(info.FullMethod, err != nil)
 
 		// 记录响应信息
 		duration := time.Since(start)
-		if err != nil {
-			logger.Error(ctx, "gRPC call failed: method=%s, duration=%v, error=%v", info.FullMethod, duration, err)
+		if config.Split {
+			if err != nil {
+				logger.Error(ctx, "gRPC call failed: method=%s, duration=%v, error=%v", info.FullMethod, duration, err)
+			} else if payload, ok := capturePayload(info.FullMethod, resp, false, debugPayload); ok {
+				logger.Info(ctx, "gRPC call success: method=%s, duration=%v, response=%s", info.FullMethod, duration, payload)
+			} else {
+				logger.Info(ctx, "gRPC call success: method=%s, duration=%v", info.FullMethod, duration)
+			}
 		} else {
-			logger.Info(ctx, "gRPC call success: method=%s, duration=%v", info.FullMethod, duration)
+			fields := accessLogFields(ctx, info.FullMethod, err, start, duration)
+			if size, ok := messageSize(req); ok {
+				fields[logger.FieldRequestSize] = size
+			}
+			if size, ok := messageSize(resp); ok && err == nil {
+				fields[logger.FieldResponseSize] = size
+			}
+			if hasRequestPayload {
+				fields[logger.FieldRequestPayload] = requestPayload
+			}
+			if payload, ok := capturePayload(info.FullMethod, resp, false, debugPayload); ok && err == nil {
+				fields[logger.FieldResponsePayload] = payload
+			}
+			logAccess(ctx, "gRPC call", fields, err)
 		}
 
-		if err != nil {
-			if ok = grpcep.WithError(resp, err); ok {
+		// 携带 status 详情的错误直接返回，避免详情在 CommonResp 转换中丢失
+		if err != nil && !grpcep.HasErrorDetails(err) {
+			if grpcep.WithError(resp, err) {
 				err = nil
 			}
 		}
+		// 未写入 CommonResp 的 GErr 转换为携带错误码的 gRPC status，网关据此还原错误码
+		if err != nil && gerr.IsGErr(err) {
+			err = gerr.ToGRPCStatus(err).Err()
+		}
 
 		return resp, err
 	}
 }
 
+// accessLogFields 访问日志的公共字段：方法、状态码、耗时、对端地址与调用开始时距截止时间的剩余时间
+func accessLogFields(ctx context.Context, method string, err error, start time.Time, duration time.Duration) logger.Fields {
+	fields := logger.Fields{
+		logger.FieldGRPCMethod: method,
+		logger.FieldGRPCCode:   errorCode(err).String(),
+		logger.FieldDurationMs: float64(duration.Microseconds()) / 1000,
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields[logger.FieldRemoteAddr] = p.Addr.String()
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		fields[logger.FieldDeadlineMs] = float64(deadline.Sub(start).Microseconds()) / 1000
+	}
+	return fields
+}
+
+// logAccess 输出单行访问日志，调用失败时使用 error 级别并附带错误
+func logAccess(ctx context.Context, kind string, fields logger.Fields, err error) {
+	msg := fmt.Sprintf("%s completed: method=%s, code=%s, duration=%.3fms", kind, fields[logger.FieldGRPCMethod], fields[logger.FieldGRPCCode], fields[logger.FieldDurationMs])
+	if addr, ok := fields[logger.FieldRemoteAddr]; ok {
+		msg += fmt.Sprintf(", peer=%s", addr)
+	}
+	log := logger.WithFields(fields)
+	if err != nil {
+		log.Error(ctx, "%s, error=%v", msg, err)
+		return
+	}
+	log.Info(ctx, "%s", msg)
+}
+
+// errorCode 返回错误对应的 gRPC 状态码（GErr 按其映射的状态码）
+func errorCode(err error) codes.Code {
+	if err != nil && gerr.IsGErr(err) {
+		return gerr.ToGRPCStatus(err).Code()
+	}
+	return status.Code(err)
+}
+
+// messageSize 返回 protobuf 消息的编码大小，非 protobuf 消息返回 false
+func messageSize(msg interface{}) (int, bool) {
+	m, ok := msg.(proto.Message)
+	if !ok || m == nil {
+		return 0, false
+	}
+	return proto.Size(m), true
+}
+
 // RecoveryInterceptor 恢复拦截器（防止panic）
 func RecoveryInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 		defer func() {
 			if r := recover(); r != nil {
 				// 从 metadata 中提取 trace ID（如果存在）
-				md, ok := metadata.FromIncomingContext(ctx)
-				if ok {
-					if traceIDs := md.Get(TraceIDMetadataKey); len(traceIDs) > 0 && traceIDs[0] != "" {
-						ctx = logger.WithTraceID(ctx, traceIDs[0])
-					}
-				}
+				ctx = traceFromIncoming(ctx)
 				// 从 context 中提取或创建链路信息
 				ctx = logger.StartSpan(ctx)
-				logger.Error(ctx, "panic recovered: method=%s, panic=%v", info.FullMethod, r)
+				stack := debug.Stack()
+				logger.Error(ctx, "panic recovered: method=%s, panic=%v\n%s", info.FullMethod, r, stack)
+				recovery.ReportPanic(ctx, r, stack, grpcReport(ctx, "grpc", info.FullMethod, codes.Internal))
 				err = status.Error(codes.Internal, fmt.Sprintf("internal server error: %v", r))
 			}
+			if code := status.Code(err); isServerErrorCode(code) {
+				recovery.RecordServerError(ctx, grpcReport(ctx, "grpc", info.FullMethod, code))
+			}
 		}()
 		return handler(ctx, req)
 	}
 }
 
+// isServerErrorCode 判断 gRPC code 是否表示服务端错误（计入错误突增上报）
+func isServerErrorCode(code codes.Code) bool {
+	return code == codes.Internal || code == codes.Unknown || code == codes.DataLoss
+}
+
+// grpcReport 提取调用信息用于错误上报
+func grpcReport(ctx context.Context, transport, method string, code codes.Code) recovery.Report {
+	report := recovery.Report{Transport: transport, Method: method, Status: int(code)}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		report.Metadata = map[string]string{"peer": p.Addr.String()}
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ua := md.Get("user-agent"); len(ua) > 0 {
+			if report.Metadata == nil {
+				report.Metadata = make(map[string]string)
+			}
+			report.Metadata["user_agent"] = ua[0]
+		}
+	}
+	return report
+}
+
 // AuthInterceptor 认证拦截器示例
 func AuthInterceptor(token string) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
@@ -133,6 +300,23 @@ func ChainUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.Se
 	return grpc.ChainUnaryInterceptor(interceptors...)
 }
 
+// capturePayload 采集请求或响应载荷：优先使用配置的载荷采集，debug 为 true 时使用运行时载荷日志的默认规则
+func capturePayload(method string, msg interface{}, request, debug bool) (string, bool) {
+	var (
+		payload string
+		ok      bool
+	)
+	if request {
+		payload, ok = tracing.CaptureRequestPayload(method, msg)
+	} else {
+		payload, ok = tracing.CaptureResponsePayload(method, msg)
+	}
+	if ok || !debug {
+		return payload, ok
+	}
+	return tracing.FormatPayload(method, msg)
+}
+
 // wrappedServerStream 包装 ServerStream 以传递包含 trace ID 的 context
 type wrappedServerStream struct {
 	grpc.ServerStream
@@ -143,30 +327,28 @@ func (w *wrappedServerStream) Context() context.Context {
 	return w.ctx
 }
 
-// StreamLoggingInterceptor 流式日志拦截器
+// StreamLoggingInterceptor 流式日志拦截器（流结束时输出一行访问日志）
 func StreamLoggingInterceptor() grpc.StreamServerInterceptor {
+	return StreamLoggingInterceptorWithConfig(AccessLogConfig{})
+}
+
+// StreamLoggingInterceptorWithConfig 按配置创建流式日志拦截器
+func StreamLoggingInterceptorWithConfig(config AccessLogConfig) grpc.StreamServerInterceptor {
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		ctx := ss.Context()
 		start := time.Now()
 
 		// 从 metadata 中提取 trace ID 和 span ID
-		md, ok := metadata.FromIncomingContext(ctx)
-		if ok {
-			// 提取 trace ID
-			if traceIDs := md.Get(TraceIDMetadataKey); len(traceIDs) > 0 && traceIDs[0] != "" {
-				ctx = logger.WithTraceID(ctx, traceIDs[0])
-			}
-			// 提取 span ID（如果有）
-			if spanIDs := md.Get(SpanIDMetadataKey); len(spanIDs) > 0 && spanIDs[0] != "" {
-				ctx = logger.WithSpanID(ctx, spanIDs[0])
-			}
-		}
+		ctx = traceFromIncoming(ctx)
 
 		// 从 context 中提取或创建链路信息
 		ctx = logger.StartSpan(ctx)
+		ctx = logger.WithModule(ctx, info.FullMethod)
 
 		// 记录请求信息
-		logger.Info(ctx, "gRPC stream call: method=%s", info.FullMethod)
+		if config.Split {
+			logger.Info(ctx, "gRPC stream call: method=%s", info.FullMethod)
+		}
 
 		// 创建包装的 stream，将包含 trace ID 的 context 传递给 handler
 		wrappedStream := &wrappedServerStream{
@@ -176,14 +358,21 @@ func StreamLoggingInterceptor() grpc.StreamServerInterceptor {
 
 		// 执行处理
 		err := handler(srv, wrappedStream)
+		logger.DefaultBooster().Record(info.FullMethod, err != nil)
 
 		// 记录响应信息
 		duration := time.Since(start)
-		if err != nil {
+		switch {
+		case !config.Split:
+			logAccess(ctx, "gRPC stream call", accessLogFields(ctx, info.FullMethod, err, start, duration), err)
+		case err != nil:
 			logger.Error(ctx, "gRPC stream call failed: method=%s, duration=%v, error=%v", info.FullMethod, duration, err)
-		} else {
+		default:
 			logger.Info(ctx, "gRPC stream call success: method=%s, duration=%v", info.FullMethod, duration)
 		}
+		if err != nil && gerr.IsGErr(err) {
+			err = gerr.ToGRPCStatus(err).Err()
+		}
 
 		return err
 	}
@@ -197,23 +386,8 @@ func ClientStreamLoggingInterceptor() grpc.StreamClientInterceptor {
 		// 从 context 中提取或创建链路信息
 		ctx = logger.StartSpan(ctx)
 
-		// 获取 trace ID 和 span ID
-		traceID := logger.GetTraceID(ctx)
-		spanID := logger.GetSpanID(ctx)
-
-		// 将 trace ID 和 span ID 添加到 metadata 中传递给服务端
-		if traceID != "" {
-			md, ok := metadata.FromOutgoingContext(ctx)
-			if !ok {
-				md = metadata.New(nil)
-			}
-			md = md.Copy()
-			md.Set(TraceIDMetadataKey, traceID)
-			if spanID != "" {
-				md.Set(SpanIDMetadataKey, spanID)
-			}
-			ctx = metadata.NewOutgoingContext(ctx, md)
-		}
+		// 将 trace ID、span ID 及 W3C traceparent 添加到 metadata 中传递给服务端
+		ctx = traceToOutgoing(ctx)
 
 		// 记录请求信息
 		logger.Info(ctx, "gRPC client stream call: method=%s", method)
@@ -249,23 +423,8 @@ func ClientLoggingInterceptor() grpc.UnaryClientInterceptor {
 		// 从 context 中提取或创建链路信息
 		ctx = logger.StartSpan(ctx)
 
-		// 获取 trace ID 和 span ID
-		traceID := logger.GetTraceID(ctx)
-		spanID := logger.GetSpanID(ctx)
-
-		// 将 trace ID 和 span ID 添加到 metadata 中传递给服务端
-		if traceID != "" {
-			md, ok := metadata.FromOutgoingContext(ctx)
-			if !ok {
-				md = metadata.New(nil)
-			}
-			md = md.Copy()
-			md.Set(TraceIDMetadataKey, traceID)
-			if spanID != "" {
-				md.Set(SpanIDMetadataKey, spanID)
-			}
-			ctx = metadata.NewOutgoingContext(ctx, md)
-		}
+		// 将 trace ID、span ID 及 W3C traceparent 添加到 metadata 中传递给服务端
+		ctx = traceToOutgoing(ctx)
 
 		// 记录请求信息
 		logger.Info(ctx, "gRPC client call: method=%s", method)
@@ -307,13 +466,43 @@ func ClientTimeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor
 	}
 }
 
+// ClientDeadlineInterceptor 客户端截止时间拦截器
+// 调用 context 的截止时间（如网关 HTTP 请求的超时）随调用以 gRPC deadline 传递给下游；
+// context 没有截止时间且 defaultTimeout > 0 时使用 defaultTimeout，context 已取消或超时时不再发起调用
+func ClientDeadlineInterceptor(defaultTimeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		if _, ok := ctx.Deadline(); !ok && defaultTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// ClientStreamDeadlineInterceptor 客户端流截止时间拦截器：context 已取消或超时时不再建立流
+// 流通常是长连接，不设置默认超时
+func ClientStreamDeadlineInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
 // ClientRecoveryInterceptor 客户端恢复拦截器（防止panic）
 func ClientRecoveryInterceptor() grpc.UnaryClientInterceptor {
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) (err error) {
 		defer func() {
 			if r := recover(); r != nil {
 				ctx = logger.StartSpan(ctx)
-				logger.Error(ctx, "panic recovered in client: method=%s, panic=%v", method, r)
+				stack := debug.Stack()
+				logger.Error(ctx, "panic recovered in client: method=%s, panic=%v\n%s", method, r, stack)
+				recovery.ReportPanic(ctx, r, stack, recovery.Report{Transport: "grpc-client", Method: method, Status: int(codes.Internal)})
 				err = status.Error(codes.Internal, fmt.Sprintf("internal client error: %v", r))
 			}
 		}()
@@ -0,0 +1,195 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/team-dandelion/quickgo/gerr"
+	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/recovery"
+	"github.com/team-dandelion/quickgo/tracing"
+)
+
+func TestTraceMetadataCarriesTraceparent(t *testing.T) {
+	traceID := logger.GenerateTraceID()
+	spanID := logger.GenerateSpanID()
+	ctx := traceToOutgoing(logger.WithTrace(context.Background(), traceID, spanID))
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if got := md.Get(TraceIDMetadataKey); len(got) != 1 || got[0] != traceID {
+		t.Fatalf("expected %s metadata %q, got %v", TraceIDMetadataKey, traceID, got)
+	}
+	want, _ := tracing.FormatTraceparent(traceID, spanID, false)
+	if got := md.Get(TraceparentMetadataKey); len(got) != 1 || got[0] != want {
+		t.Fatalf("expected traceparent %q, got %v", want, got)
+	}
+
+	incoming := traceFromIncoming(metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		TraceparentMetadataKey, want,
+		TraceIDMetadataKey, "ignored",
+	)))
+	if got := logger.GetTraceID(incoming); got != traceID {
+		t.Fatalf("expected traceparent to take precedence, got %q", got)
+	}
+}
+
+func TestLoggingInterceptorEncodesGErrAsStatus(t *testing.T) {
+	interceptor := LoggingInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Get"}
+	_, err := interceptor(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, gerr.NewNotFound(40401, "user not found")
+	})
+
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.NotFound {
+		t.Fatalf("expected NotFound status, got %v", err)
+	}
+	restored := gerr.FromGRPCStatus(st)
+	if restored.Code != 40401 || restored.Msg != "user not found" {
+		t.Fatalf("unexpected restored error %+v", restored)
+	}
+}
+
+func TestLoggingInterceptorWritesSingleAccessLog(t *testing.T) {
+	log, sink := logger.NewTestLogger()
+	defer logger.ReplaceDefault(log)()
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.7"), Port: 5123}})
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Echo"}
+	req := wrapperspb.String("hello")
+	resp := wrapperspb.String("hello, world")
+	if _, err := LoggingInterceptor()(ctx, req, info, func(context.Context, interface{}) (interface{}, error) {
+		return resp, nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := len(sink.Entries()); got != 1 {
+		t.Fatalf("expected a single access log line, got %d", got)
+	}
+	sink.AssertLogged(t, logger.MatchLevel(logger.LevelInfo), logger.MatchMessage("gRPC call completed: method=/test.Service/Echo, code=OK"),
+		logger.MatchField(logger.FieldGRPCCode, "OK"),
+		logger.MatchField(logger.FieldRemoteAddr, "10.0.0.7:5123"),
+		logger.MatchField(logger.FieldRequestSize, proto.Size(req)),
+		logger.MatchField(logger.FieldResponseSize, proto.Size(resp)))
+	deadline, ok := sink.Entries()[0].Fields[logger.FieldDeadlineMs].(float64)
+	if !ok || deadline <= 59000 || deadline > 60000 {
+		t.Fatalf("unexpected deadline field: %v", sink.Entries()[0].Fields[logger.FieldDeadlineMs])
+	}
+
+	sink.Reset()
+	_, _ = LoggingInterceptor()(context.Background(), req, info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, gerr.NewNotFound(40401, "user not found")
+	})
+	sink.AssertLogged(t, logger.MatchLevel(logger.LevelError), logger.MatchField(logger.FieldGRPCCode, "NotFound"), logger.MatchError("user not found"))
+	sink.AssertNotLogged(t, logger.MatchField(logger.FieldResponseSize, 0))
+}
+
+func TestLoggingInterceptorSplitMode(t *testing.T) {
+	log, sink := logger.NewTestLogger()
+	defer logger.ReplaceDefault(log)()
+
+	interceptor := LoggingInterceptorWithConfig(AccessLogConfig{Split: true})
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Echo"}
+	_, _ = interceptor(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, nil
+	})
+
+	if got := len(sink.Entries()); got != 2 {
+		t.Fatalf("expected two log lines in split mode, got %d", got)
+	}
+	sink.AssertLogged(t, logger.MatchMessage("gRPC call: method=/test.Service/Echo"))
+	sink.AssertLogged(t, logger.MatchMessage("gRPC call success: method=/test.Service/Echo"))
+}
+
+func TestCapturePayloadUsesRuntimeToggle(t *testing.T) {
+	const method = "/auth.AuthService/Login"
+	req := map[string]string{"user": "alice", "password": "hunter2"}
+	if _, ok := capturePayload(method, req, true, false); ok {
+		t.Fatal("expected no payload without capture config or runtime toggle")
+	}
+	payload, ok := capturePayload(method, req, true, true)
+	if !ok || !strings.Contains(payload, "alice") || strings.Contains(payload, "hunter2") {
+		t.Fatalf("expected redacted payload, got %q (%v)", payload, ok)
+	}
+}
+
+type recordingReporter struct {
+	reports []*recovery.Report
+}
+
+func (r *recordingReporter) Report(_ context.Context, report *recovery.Report) {
+	r.reports = append(r.reports, report)
+}
+
+func TestRecoveryInterceptorReportsPanic(t *testing.T) {
+	reporter := &recordingReporter{}
+	recovery.SetReporter(reporter)
+	t.Cleanup(func() { recovery.SetReporter(nil) })
+
+	interceptor := RecoveryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Get"}
+	_, err := interceptor(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		panic("boom")
+	})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal status, got %v", err)
+	}
+	if len(reporter.reports) != 1 {
+		t.Fatalf("expected 1 report, got %d", len(reporter.reports))
+	}
+	report := reporter.reports[0]
+	if report.Kind != recovery.ReportKindPanic || report.Message != "boom" || report.Method != "/test.Service/Get" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if report.Transport != "grpc" || report.Status != int(codes.Internal) || report.Stack == "" || report.TraceID == "" {
+		t.Fatalf("unexpected report metadata %+v", report)
+	}
+}
+
+func TestClientDeadlineInterceptor(t *testing.T) {
+	interceptor := ClientDeadlineInterceptor(time.Second)
+	var got time.Duration
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			return errors.New("missing deadline")
+		}
+		got = time.Until(deadline)
+		return nil
+	}
+
+	// 没有截止时间时使用默认超时
+	if err := interceptor(context.Background(), "/svc/M", nil, nil, nil, invoker); err != nil || got <= 900*time.Millisecond || got > time.Second {
+		t.Fatalf("expected default timeout, got %v (%v)", got, err)
+	}
+	// 上游（HTTP 请求）的截止时间原样传递
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := interceptor(ctx, "/svc/M", nil, nil, nil, invoker); err != nil || got > 100*time.Millisecond {
+		t.Fatalf("expected caller deadline, got %v (%v)", got, err)
+	}
+	// 已超时的调用不再发起
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	if err := interceptor(expired, "/svc/M", nil, nil, nil, func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+		t.Fatal("invoker should not be called after the deadline")
+		return nil
+	}); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,317 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// MemoryScheme 进程内注册中心（MemoryRegistry）的 resolver scheme
+const MemoryScheme = "memory"
+
+// MemoryRegistry 进程内注册中心，同时实现 ServiceRegistry、ServiceDiscovery 与 InstanceDiscovery，
+// 用于在没有 etcd 的情况下测试服务注册、resolver 与负载均衡行为：
+//   - Register / Deregister / SetInstances 动态增减实例并通知 Watch 回调
+//   - SetResolveLatency / SetResolveError / FailNextResolve 注入解析延迟与错误
+//   - Outage / Restore / Flap 模拟注册中心抖动（实例整体消失后恢复）
+//
+// 作为客户端 ServiceDiscovery 使用时注册到 memory scheme，同一时间只能有一个 MemoryRegistry 被客户端引用
+type MemoryRegistry struct {
+	mu       sync.Mutex
+	services map[string][]ServiceInfo
+	// 模拟故障中的服务（实例对解析与监听不可见）
+	down     map[string]bool
+	watchers map[string]map[int]func([]ServiceInfo)
+	nextID   int
+
+	resolveLatency time.Duration
+	resolveErr     error
+	failNext       []error
+	registryErr    error
+
+	// 串行化回调，保证监听方按变更顺序收到实例列表
+	notifyMu sync.Mutex
+}
+
+// NewMemoryRegistry 创建进程内注册中心
+func NewMemoryRegistry() *MemoryRegistry {
+	return &MemoryRegistry{
+		services: make(map[string][]ServiceInfo),
+		down:     make(map[string]bool),
+		watchers: make(map[string]map[int]func([]ServiceInfo)),
+	}
+}
+
+// Register 注册实例（同一地址重复注册时更新元数据），SetRegistryError 设置的错误优先返回
+func (r *MemoryRegistry) Register(ctx context.Context, serviceName, address string, metadata map[string]string) error {
+	info := ServiceInfo{Name: serviceName, Address: address, Metadata: metadata, Weight: 1}
+	if weight, ok := metadata[MetadataWeight]; ok {
+		if w, err := parseInt(weight); err == nil {
+			info.Weight = w
+		}
+	}
+
+	r.mu.Lock()
+	if r.registryErr != nil {
+		r.mu.Unlock()
+		return r.registryErr
+	}
+	instances := r.services[serviceName]
+	replaced := false
+	for i := range instances {
+		if instances[i].Address == address {
+			instances[i] = info
+			replaced = true
+		}
+	}
+	if !replaced {
+		instances = append(instances, info)
+	}
+	r.services[serviceName] = instances
+	r.mu.Unlock()
+
+	logger.Debug(ctx, "Memory registry instance registered: service=%s, address=%s", serviceName, address)
+	r.notify(serviceName)
+	return nil
+}
+
+// Deregister 注销实例
+func (r *MemoryRegistry) Deregister(ctx context.Context, serviceName, address string) error {
+	r.mu.Lock()
+	if r.registryErr != nil {
+		r.mu.Unlock()
+		return r.registryErr
+	}
+	instances := r.services[serviceName]
+	kept := make([]ServiceInfo, 0, len(instances))
+	for _, info := range instances {
+		if info.Address != address {
+			kept = append(kept, info)
+		}
+	}
+	changed := len(kept) != len(instances)
+	r.services[serviceName] = kept
+	r.mu.Unlock()
+
+	if changed {
+		r.notify(serviceName)
+	}
+	return nil
+}
+
+// KeepAlive 心跳（SetRegistryError 设置错误时返回该错误，用于模拟租约续期失败）
+func (r *MemoryRegistry) KeepAlive(ctx context.Context, serviceName, address string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.registryErr
+}
+
+// Close 停止所有监听（已注册的实例保留，同一个 MemoryRegistry 可在多个客户端间复用）
+func (r *MemoryRegistry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.watchers = make(map[string]map[int]func([]ServiceInfo))
+	return nil
+}
+
+// SetInstances 整体替换服务的实例列表并通知监听方
+func (r *MemoryRegistry) SetInstances(serviceName string, instances []ServiceInfo) {
+	r.mu.Lock()
+	r.services[serviceName] = append([]ServiceInfo(nil), instances...)
+	r.mu.Unlock()
+	r.notify(serviceName)
+}
+
+// Instances 返回服务当前注册的实例（不受 Outage 影响）
+func (r *MemoryRegistry) Instances(serviceName string) []ServiceInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ServiceInfo(nil), r.services[serviceName]...)
+}
+
+// WatcherCount 返回服务当前的监听数
+func (r *MemoryRegistry) WatcherCount(serviceName string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.watchers[serviceName])
+}
+
+// Resolve 解析服务地址
+func (r *MemoryRegistry) Resolve(ctx context.Context, serviceName string) ([]string, error) {
+	instances, err := r.ResolveInstances(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	return instanceAddressList(instances), nil
+}
+
+// ResolveInstances 解析服务实例，依次应用注入的延迟与错误；没有可见实例时返回错误
+func (r *MemoryRegistry) ResolveInstances(ctx context.Context, serviceName string) ([]ServiceInfo, error) {
+	r.mu.Lock()
+	latency := r.resolveLatency
+	r.mu.Unlock()
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.failNext) > 0 {
+		err := r.failNext[0]
+		r.failNext = r.failNext[1:]
+		return nil, err
+	}
+	if r.resolveErr != nil {
+		return nil, r.resolveErr
+	}
+	instances := r.visibleLocked(serviceName)
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("no instances available for service %s", serviceName)
+	}
+	return instances, nil
+}
+
+// Watch 监听服务地址变化（立即回调一次当前地址，之后在实例变化时回调，ctx 结束时停止）
+func (r *MemoryRegistry) Watch(ctx context.Context, serviceName string, callback func([]string)) error {
+	return r.WatchInstances(ctx, serviceName, func(instances []ServiceInfo) {
+		callback(instanceAddressList(instances))
+	})
+}
+
+// WatchInstances 监听服务实例变化（立即回调一次当前实例，之后在实例变化时回调，ctx 结束时停止）
+// 与 etcd 实现一致，方法立即返回；Outage 期间回调收到空列表
+func (r *MemoryRegistry) WatchInstances(ctx context.Context, serviceName string, callback func([]ServiceInfo)) error {
+	r.notifyMu.Lock()
+	defer r.notifyMu.Unlock()
+
+	r.mu.Lock()
+	r.nextID++
+	id := r.nextID
+	if r.watchers[serviceName] == nil {
+		r.watchers[serviceName] = make(map[int]func([]ServiceInfo))
+	}
+	r.watchers[serviceName][id] = callback
+	instances := r.visibleLocked(serviceName)
+	r.mu.Unlock()
+
+	if len(instances) > 0 {
+		callback(instances)
+	}
+	go func() {
+		<-ctx.Done()
+		r.mu.Lock()
+		delete(r.watchers[serviceName], id)
+		r.mu.Unlock()
+	}()
+	return nil
+}
+
+// SetResolveLatency 为每次解析增加延迟（0 清除）
+func (r *MemoryRegistry) SetResolveLatency(latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resolveLatency = latency
+}
+
+// SetResolveError 使每次解析都返回 err（nil 清除）
+func (r *MemoryRegistry) SetResolveError(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resolveErr = err
+}
+
+// FailNextResolve 使下一次解析返回 err（多次调用按顺序依次生效）
+func (r *MemoryRegistry) FailNextResolve(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failNext = append(r.failNext, err)
+}
+
+// SetRegistryError 使 Register、Deregister 与 KeepAlive 返回 err（nil 清除），模拟注册中心不可用
+func (r *MemoryRegistry) SetRegistryError(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.registryErr = err
+}
+
+// Outage 模拟服务在注册中心中整体消失：解析返回错误，监听方收到空列表
+func (r *MemoryRegistry) Outage(serviceName string) {
+	r.setDown(serviceName, true)
+}
+
+// Restore 结束 Outage，监听方重新收到当前实例
+func (r *MemoryRegistry) Restore(serviceName string) {
+	r.setDown(serviceName, false)
+}
+
+// Flap 模拟注册中心抖动：交替执行 Outage 与 Restore times 次，每个阶段持续 interval，结束时服务处于恢复状态
+// 阻塞直到完成或 ctx 结束（ctx 结束时同样恢复服务并返回 ctx 错误）
+func (r *MemoryRegistry) Flap(ctx context.Context, serviceName string, interval time.Duration, times int) error {
+	defer r.Restore(serviceName)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for i := 0; i < times; i++ {
+		r.Outage(serviceName)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		r.Restore(serviceName)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRegistry) setDown(serviceName string, down bool) {
+	r.mu.Lock()
+	changed := r.down[serviceName] != down
+	if down {
+		r.down[serviceName] = true
+	} else {
+		delete(r.down, serviceName)
+	}
+	r.mu.Unlock()
+	if changed {
+		r.notify(serviceName)
+	}
+}
+
+// visibleLocked 返回对解析与监听可见的实例（调用方需持有 r.mu）
+func (r *MemoryRegistry) visibleLocked(serviceName string) []ServiceInfo {
+	if r.down[serviceName] {
+		return nil
+	}
+	return append([]ServiceInfo(nil), r.services[serviceName]...)
+}
+
+// notify 向服务的监听方推送当前可见实例
+func (r *MemoryRegistry) notify(serviceName string) {
+	r.notifyMu.Lock()
+	defer r.notifyMu.Unlock()
+
+	r.mu.Lock()
+	instances := r.visibleLocked(serviceName)
+	callbacks := make([]func([]ServiceInfo), 0, len(r.watchers[serviceName]))
+	for _, callback := range r.watchers[serviceName] {
+		callbacks = append(callbacks, callback)
+	}
+	r.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(append([]ServiceInfo(nil), instances...))
+	}
+}
@@ -0,0 +1,231 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/resolver"
+)
+
+// recordingClientConn 记录 resolver 推送的地址
+type recordingClientConn struct {
+	resolver.ClientConn
+	mu     sync.Mutex
+	states [][]string
+}
+
+func (c *recordingClientConn) UpdateState(state resolver.State) error {
+	addresses := make([]string, 0, len(state.Addresses))
+	for _, addr := range state.Addresses {
+		addresses = append(addresses, addr.Addr)
+	}
+	sort.Strings(addresses)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.states = append(c.states, addresses)
+	return nil
+}
+
+func (c *recordingClientConn) last() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.states) == 0 {
+		return ""
+	}
+	return strings.Join(c.states[len(c.states)-1], ",")
+}
+
+func (c *recordingClientConn) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.states)
+}
+
+func TestMemoryRegistryWatchReceivesDynamicChanges(t *testing.T) {
+	reg := NewMemoryRegistry()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var mu sync.Mutex
+	var updates [][]ServiceInfo
+	if err := reg.WatchInstances(ctx, "svc", func(instances []ServiceInfo) {
+		mu.Lock()
+		defer mu.Unlock()
+		updates = append(updates, instances)
+	}); err != nil {
+		t.Fatalf("WatchInstances failed: %v", err)
+	}
+
+	_ = reg.Register(ctx, "svc", "10.0.0.1:9000", map[string]string{MetadataWeight: "5"})
+	_ = reg.Register(ctx, "svc", "10.0.0.2:9000", nil)
+	_ = reg.Register(ctx, "other", "10.0.0.3:9000", nil)
+	_ = reg.Deregister(ctx, "svc", "10.0.0.1:9000")
+
+	mu.Lock()
+	if len(updates) != 3 {
+		mu.Unlock()
+		t.Fatalf("expected 3 updates for svc, got %d", len(updates))
+	}
+	if len(updates[1]) != 2 || updates[1][0].Weight != 5 {
+		t.Fatalf("unexpected second update: %+v", updates[1])
+	}
+	if len(updates[2]) != 1 || updates[2][0].Address != "10.0.0.2:9000" {
+		t.Fatalf("unexpected third update: %+v", updates[2])
+	}
+	mu.Unlock()
+
+	cancel()
+	waitFor(t, func() bool { return reg.WatcherCount("svc") == 0 })
+}
+
+func TestMemoryRegistryResolveInjection(t *testing.T) {
+	reg := NewMemoryRegistry()
+	ctx := context.Background()
+	if _, err := reg.Resolve(ctx, "svc"); err == nil {
+		t.Fatal("expected error for service without instances")
+	}
+	_ = reg.Register(ctx, "svc", "10.0.0.1:9000", nil)
+
+	injected := errors.New("registry unavailable")
+	reg.FailNextResolve(injected)
+	if _, err := reg.Resolve(ctx, "svc"); !errors.Is(err, injected) {
+		t.Fatalf("expected injected error, got %v", err)
+	}
+	if addrs, err := reg.Resolve(ctx, "svc"); err != nil || len(addrs) != 1 {
+		t.Fatalf("expected recovery after FailNextResolve, got %v, %v", addrs, err)
+	}
+
+	reg.SetResolveError(injected)
+	if _, err := reg.Resolve(ctx, "svc"); !errors.Is(err, injected) {
+		t.Fatalf("expected persistent error, got %v", err)
+	}
+	reg.SetResolveError(nil)
+
+	reg.SetResolveLatency(time.Second)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := reg.Resolve(timeoutCtx, "svc"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	reg.SetRegistryError(injected)
+	if err := reg.Register(ctx, "svc", "10.0.0.2:9000", nil); !errors.Is(err, injected) {
+		t.Fatalf("expected register error, got %v", err)
+	}
+	if err := reg.KeepAlive(ctx, "svc", "10.0.0.1:9000"); !errors.Is(err, injected) {
+		t.Fatalf("expected keepalive error, got %v", err)
+	}
+}
+
+func TestMemoryRegistryOutageAndFlap(t *testing.T) {
+	reg := NewMemoryRegistry()
+	ctx := context.Background()
+	_ = reg.Register(ctx, "svc", "10.0.0.1:9000", nil)
+
+	var mu sync.Mutex
+	var sizes []int
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	_ = reg.Watch(watchCtx, "svc", func(addresses []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		sizes = append(sizes, len(addresses))
+	})
+
+	reg.Outage("svc")
+	if _, err := reg.Resolve(ctx, "svc"); err == nil {
+		t.Fatal("expected resolve error during outage")
+	}
+	if len(reg.Instances("svc")) != 1 {
+		t.Fatal("outage must not drop registered instances")
+	}
+	reg.Restore("svc")
+
+	if err := reg.Flap(ctx, "svc", time.Millisecond, 2); err != nil {
+		t.Fatalf("Flap failed: %v", err)
+	}
+	if _, err := reg.Resolve(ctx, "svc"); err != nil {
+		t.Fatalf("expected service restored after flap: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []int{1, 0, 1, 0, 1, 0, 1}
+	if len(sizes) != len(want) {
+		t.Fatalf("unexpected watch updates: %v", sizes)
+	}
+	for i := range want {
+		if sizes[i] != want[i] {
+			t.Fatalf("unexpected watch updates: %v", sizes)
+		}
+	}
+}
+
+func TestServiceResolverFollowsMemoryRegistry(t *testing.T) {
+	reg := NewMemoryRegistry()
+	ctx := context.Background()
+	_ = reg.Register(ctx, "svc", "10.0.0.1:9000", nil)
+
+	cc := &recordingClientConn{}
+	r := &serviceResolver{cc: cc, sd: reg, serviceName: "svc"}
+	r.start()
+	defer r.Close()
+
+	waitFor(t, func() bool { return reg.WatcherCount("svc") == 1 })
+	_ = reg.Register(ctx, "svc", "10.0.0.2:9000", nil)
+	waitFor(t, func() bool { return cc.last() == "10.0.0.1:9000,10.0.0.2:9000" })
+
+	// 注册中心抖动期间 resolver 保留最后一次可用地址
+	updates := cc.count()
+	reg.Outage("svc")
+	reg.FailNextResolve(errors.New("registry unavailable"))
+	r.ResolveNow(resolver.ResolveNowOptions{})
+	if cc.count() != updates {
+		t.Fatalf("resolver pushed state during outage: %d -> %d", updates, cc.count())
+	}
+	reg.Restore("svc")
+	waitFor(t, func() bool { return cc.count() == updates+1 })
+
+	_ = reg.Deregister(ctx, "svc", "10.0.0.1:9000")
+	waitFor(t, func() bool { return cc.last() == "10.0.0.2:9000" })
+}
+
+func TestStaticResolverWatchReceivesUpdates(t *testing.T) {
+	sd := NewStaticResolver([]string{"10.0.0.1:9000"})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var mu sync.Mutex
+	var updates [][]string
+	if err := sd.Watch(ctx, "svc", func(addresses []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		updates = append(updates, addresses)
+	}); err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	sd.UpdateAddresses([]string{"10.0.0.1:9000", "10.0.0.2:9000"})
+
+	mu.Lock()
+	if len(updates) != 2 || len(updates[1]) != 2 {
+		mu.Unlock()
+		t.Fatalf("unexpected updates: %v", updates)
+	}
+	mu.Unlock()
+
+	cancel()
+	waitFor(t, func() bool {
+		sd.mu.RLock()
+		defer sd.mu.RUnlock()
+		return len(sd.watchers) == 0
+	})
+	sd.UpdateAddresses([]string{"10.0.0.3:9000"})
+	mu.Lock()
+	defer mu.Unlock()
+	if len(updates) != 2 {
+		t.Fatalf("watcher notified after ctx done: %v", updates)
+	}
+}
@@ -0,0 +1,113 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+const defaultPoolHealthCheckInterval = 10 * time.Second
+
+// dialPoolConn 非阻塞地建立连接池中的额外连接，并立即触发连接
+func (c *Client) dialPoolConn(ctx context.Context) (*grpc.ClientConn, error) {
+	conn, err := grpc.DialContext(ctx, c.address, c.options...)
+	if err != nil {
+		return nil, err
+	}
+	conn.Connect()
+	return conn, nil
+}
+
+// pickConnLocked 轮询选取就绪连接，均未就绪时按轮询顺序返回（由 gRPC 负责等待或报错）
+// 调用方需持有 c.mu 读锁
+func (c *Client) pickConnLocked() *grpc.ClientConn {
+	n := len(c.conns)
+	switch n {
+	case 0:
+		return nil
+	case 1:
+		return c.conns[0]
+	}
+	start := int(c.next.Add(1) % uint64(n))
+	for i := 0; i < n; i++ {
+		conn := c.conns[(start+i)%n]
+		if conn.GetState() == connectivity.Ready {
+			return conn
+		}
+	}
+	return c.conns[start]
+}
+
+// PoolStates 返回连接池中各连接的状态
+func (c *Client) PoolStates() []connectivity.State {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	states := make([]connectivity.State, len(c.conns))
+	for i, conn := range c.conns {
+		states[i] = conn.GetState()
+	}
+	return states
+}
+
+// maintainPool 定期检查连接池，连续两次处于 TransientFailure 或已关闭的连接被淘汰并重建
+func (c *Client) maintainPool() {
+	ticker := time.NewTicker(c.poolInterval)
+	defer ticker.Stop()
+	failures := make(map[*grpc.ClientConn]int)
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		c.mu.RLock()
+		conns := append([]*grpc.ClientConn(nil), c.conns...)
+		c.mu.RUnlock()
+
+		seen := make(map[*grpc.ClientConn]int, len(conns))
+		for i, conn := range conns {
+			switch conn.GetState() {
+			case connectivity.TransientFailure, connectivity.Shutdown:
+				seen[conn] = failures[conn] + 1
+				if seen[conn] >= 2 {
+					c.evictPoolConn(i, conn)
+					delete(seen, conn)
+				}
+			case connectivity.Idle:
+				conn.Connect()
+			}
+		}
+		failures = seen
+	}
+}
+
+// evictPoolConn 用新连接替换不健康的连接
+func (c *Client) evictPoolConn(idx int, old *grpc.ClientConn) {
+	ctx := c.ctx
+	replacement, err := c.dialPoolConn(ctx)
+	if err != nil {
+		logger.Warn(ctx, "Failed to redial pooled gRPC connection: address=%s, index=%d, error=%v", c.address, idx, err)
+		return
+	}
+
+	c.mu.Lock()
+	if idx >= len(c.conns) || c.conns[idx] != old {
+		// 连接池已关闭或该连接已被替换
+		c.mu.Unlock()
+		_ = replacement.Close()
+		return
+	}
+	// 写时复制，避免与持有旧切片的读取方竞争
+	conns := append([]*grpc.ClientConn(nil), c.conns...)
+	conns[idx] = replacement
+	c.conns = conns
+	c.mu.Unlock()
+
+	_ = old.Close()
+	logger.Warn(ctx, "Evicted unhealthy pooled gRPC connection: address=%s, index=%d", c.address, idx)
+}
@@ -0,0 +1,136 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+func startPoolTestServer(t *testing.T, port int) *Server {
+	t.Helper()
+	server, err := NewServer(Config{Address: "127.0.0.1", Port: port})
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	if err := server.StartAsync(); err != nil {
+		t.Fatalf("StartAsync failed: %v", err)
+	}
+	return server
+}
+
+func waitPoolStates(t *testing.T, client *Client, want func([]connectivity.State) bool) []connectivity.State {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		states := client.PoolStates()
+		if want(states) {
+			return states
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("pool did not reach expected state, got %v", states)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func allReady(states []connectivity.State) bool {
+	for _, state := range states {
+		if state != connectivity.Ready {
+			return false
+		}
+	}
+	return len(states) > 0
+}
+
+func TestClientPoolRoundRobinsReadyConns(t *testing.T) {
+	port := reserveTCPPort(t)
+	server := startPoolTestServer(t, port)
+	defer server.Stop()
+
+	client, err := NewClient(ClientConfig{Address: fmt.Sprintf("127.0.0.1:%d", port), Insecure: true, PoolSize: 3})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+
+	if states := waitPoolStates(t, client, allReady); len(states) != 3 {
+		t.Fatalf("expected 3 pooled conns, got %d", len(states))
+	}
+	picked := make(map[*grpc.ClientConn]bool)
+	for i := 0; i < 6; i++ {
+		picked[client.GetConn()] = true
+	}
+	if len(picked) != 3 {
+		t.Fatalf("expected round-robin across 3 conns, got %d", len(picked))
+	}
+	if _, err := client.HealthCheck(context.Background(), ""); err != nil {
+		t.Fatalf("HealthCheck through pool failed: %v", err)
+	}
+}
+
+func TestClientPoolEvictsFailedConns(t *testing.T) {
+	port := reserveTCPPort(t)
+	server := startPoolTestServer(t, port)
+
+	client, err := NewClient(ClientConfig{
+		Address:                 fmt.Sprintf("127.0.0.1:%d", port),
+		Insecure:                true,
+		PoolSize:                2,
+		PoolHealthCheckInterval: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	waitPoolStates(t, client, allReady)
+
+	client.mu.RLock()
+	before := append([]*grpc.ClientConn(nil), client.conns...)
+	client.mu.RUnlock()
+
+	// 服务端下线后连接进入 TransientFailure，被淘汰重建
+	_ = server.Stop()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		client.mu.RLock()
+		replaced := client.conns[0] != before[0] && client.conns[1] != before[1]
+		client.mu.RUnlock()
+		if replaced {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected failed conns to be evicted, states=%v", client.PoolStates())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for _, conn := range before {
+		if conn.GetState() != connectivity.Shutdown {
+			t.Fatalf("expected evicted conn to be closed, got %v", conn.GetState())
+		}
+	}
+
+	// 服务端恢复后新连接重新就绪
+	restarted := startPoolTestServer(t, port)
+	defer restarted.Stop()
+	waitPoolStates(t, client, func(states []connectivity.State) bool {
+		for _, state := range states {
+			if state == connectivity.Ready {
+				return true
+			}
+		}
+		return false
+	})
+	if !client.IsConnected() {
+		t.Fatal("expected client to be connected after server restart")
+	}
+}
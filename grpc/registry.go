@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -29,7 +30,7 @@ type ServiceInfo struct {
 	Weight   int // 权重，用于负载均衡
 }
 
-// StaticRegistry 静态服务注册（用于测试，实际不注册到注册中心）
+// StaticRegistry 静态服务注册（用于测试，实际不注册到注册中心；需要被服务发现看到实例时使用 MemoryRegistry）
 type StaticRegistry struct {
 	services map[string][]ServiceInfo
 	mu       sync.RWMutex
@@ -122,9 +123,28 @@ func parseInt(v interface{}) (int, error) {
 	}
 }
 
+// DefaultRegistryName NewServiceRegistrar 使用的注册中心名称
+const DefaultRegistryName = "default"
+
+// NamedRegistry 命名的注册中心（名称用于日志与状态报告）
+type NamedRegistry struct {
+	Name     string
+	Registry ServiceRegistry
+}
+
+// RegistryStatus 单个注册中心的注册状态
+type RegistryStatus struct {
+	Name       string    // 注册中心名称
+	Registered bool      // 当前是否已注册
+	Err        error     // 最近一次操作（注册、心跳、注销）的错误
+	UpdatedAt  time.Time // 状态更新时间
+}
+
 // ServiceRegistrar 服务注册器（用于服务端自动注册）
+// 可同时注册到多个注册中心（如迁移期间同时注册到 etcd 与 Consul），注册、注销与心跳作用于所有注册中心
 type ServiceRegistrar struct {
-	registry        ServiceRegistry
+	registries      []NamedRegistry
+	status          map[string]*RegistryStatus
 	serviceName     string
 	address         string
 	metadata        map[string]string
@@ -136,27 +156,67 @@ type ServiceRegistrar struct {
 
 // NewServiceRegistrar 创建服务注册器
 func NewServiceRegistrar(registry ServiceRegistry, serviceName, address string, metadata map[string]string) *ServiceRegistrar {
+	registrar, _ := NewMultiServiceRegistrar([]NamedRegistry{{Name: DefaultRegistryName, Registry: registry}}, serviceName, address, metadata)
+	return registrar
+}
+
+// NewMultiServiceRegistrar 创建注册到多个注册中心的服务注册器，注册中心名称不能为空或重复
+func NewMultiServiceRegistrar(registries []NamedRegistry, serviceName, address string, metadata map[string]string) (*ServiceRegistrar, error) {
+	if len(registries) == 0 {
+		return nil, fmt.Errorf("at least one registry is required")
+	}
+	status := make(map[string]*RegistryStatus, len(registries))
+	for _, named := range registries {
+		if named.Name == "" || named.Registry == nil {
+			return nil, fmt.Errorf("registry name and implementation are required")
+		}
+		if _, ok := status[named.Name]; ok {
+			return nil, fmt.Errorf("duplicate registry name: %s", named.Name)
+		}
+		status[named.Name] = &RegistryStatus{Name: named.Name}
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 	return &ServiceRegistrar{
-		registry:    registry,
+		registries:  append([]NamedRegistry(nil), registries...),
+		status:      status,
 		serviceName: serviceName,
 		address:     address,
 		metadata:    metadata,
 		ctx:         ctx,
 		cancel:      cancel,
-	}
+	}, nil
 }
 
-// Register 注册服务
+// Register 注册服务到所有注册中心
+// 任一注册中心失败时撤销已成功的注册并返回错误，保证服务在所有注册中心中可见或都不可见
 func (sr *ServiceRegistrar) Register(ctx context.Context) error {
 	sr.mu.Lock()
 	defer sr.mu.Unlock()
 
-	if err := sr.registry.Register(ctx, sr.serviceName, sr.address, sr.metadata); err != nil {
-		return fmt.Errorf("failed to register service: %w", err)
+	var errs []error
+	registered := make([]NamedRegistry, 0, len(sr.registries))
+	for _, named := range sr.registries {
+		err := named.Registry.Register(ctx, sr.serviceName, sr.address, sr.metadata)
+		sr.recordLocked(named.Name, err == nil, err)
+		if err != nil {
+			logger.Error(ctx, "Failed to register service: registry=%s, service=%s, address=%s, error=%v", named.Name, sr.serviceName, sr.address, err)
+			errs = append(errs, fmt.Errorf("%s: %w", named.Name, err))
+			continue
+		}
+		registered = append(registered, named)
+	}
+	if len(errs) > 0 {
+		for _, named := range registered {
+			err := named.Registry.Deregister(ctx, sr.serviceName, sr.address)
+			if err != nil {
+				logger.Error(ctx, "Failed to roll back registration: registry=%s, service=%s, error=%v", named.Name, sr.serviceName, err)
+			}
+			sr.recordLocked(named.Name, err != nil, err)
+		}
+		return fmt.Errorf("failed to register service: %w", errors.Join(errs...))
 	}
 
-	logger.Info(ctx, "Service registered successfully: service=%s, address=%s", sr.serviceName, sr.address)
+	logger.Info(ctx, "Service registered successfully: service=%s, address=%s, registries=%v", sr.serviceName, sr.address, sr.registryNames())
 	return nil
 }
 
@@ -169,6 +229,7 @@ func (sr *ServiceRegistrar) StartKeepAlive(interval time.Duration) {
 
 	sr.mu.Lock()
 	sr.keepAliveTicker = time.NewTicker(interval)
+	ticker := sr.keepAliveTicker
 	sr.mu.Unlock()
 
 	go func() {
@@ -176,16 +237,27 @@ func (sr *ServiceRegistrar) StartKeepAlive(interval time.Duration) {
 			select {
 			case <-sr.ctx.Done():
 				return
-			case <-sr.keepAliveTicker.C:
-				if err := sr.registry.KeepAlive(sr.ctx, sr.serviceName, sr.address); err != nil {
-					logger.Error(sr.ctx, "KeepAlive failed: service=%s, address=%s, error=%v", sr.serviceName, sr.address, err)
-				}
+			case <-ticker.C:
+				sr.keepAlive(sr.ctx)
 			}
 		}
 	}()
 }
 
-// Deregister 注销服务
+// keepAlive 向所有注册中心发送心跳并记录状态
+func (sr *ServiceRegistrar) keepAlive(ctx context.Context) {
+	for _, named := range sr.registries {
+		err := named.Registry.KeepAlive(ctx, sr.serviceName, sr.address)
+		if err != nil {
+			logger.Error(ctx, "KeepAlive failed: registry=%s, service=%s, address=%s, error=%v", named.Name, sr.serviceName, sr.address, err)
+		}
+		sr.mu.Lock()
+		sr.recordLocked(named.Name, sr.status[named.Name].Registered, err)
+		sr.mu.Unlock()
+	}
+}
+
+// Deregister 从所有注册中心注销服务（某个注册中心失败时继续注销其他注册中心）
 func (sr *ServiceRegistrar) Deregister(ctx context.Context) error {
 	sr.mu.Lock()
 	defer sr.mu.Unlock()
@@ -196,16 +268,58 @@ func (sr *ServiceRegistrar) Deregister(ctx context.Context) error {
 		sr.keepAliveTicker = nil
 	}
 
-	if err := sr.registry.Deregister(ctx, sr.serviceName, sr.address); err != nil {
-		return fmt.Errorf("failed to deregister service: %w", err)
+	var errs []error
+	for _, named := range sr.registries {
+		err := named.Registry.Deregister(ctx, sr.serviceName, sr.address)
+		sr.recordLocked(named.Name, err != nil && sr.status[named.Name].Registered, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", named.Name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to deregister service: %w", errors.Join(errs...))
 	}
 
 	logger.Info(ctx, "Service deregistered: service=%s, address=%s", sr.serviceName, sr.address)
 	return nil
 }
 
-// Close 关闭注册器
+// Status 返回各注册中心的注册状态（按注册中心顺序）
+func (sr *ServiceRegistrar) Status() []RegistryStatus {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	status := make([]RegistryStatus, 0, len(sr.registries))
+	for _, named := range sr.registries {
+		status = append(status, *sr.status[named.Name])
+	}
+	return status
+}
+
+// recordLocked 记录注册中心状态（调用方需持有 sr.mu）
+func (sr *ServiceRegistrar) recordLocked(name string, registered bool, err error) {
+	status := sr.status[name]
+	status.Registered = registered
+	status.Err = err
+	status.UpdatedAt = time.Now()
+}
+
+func (sr *ServiceRegistrar) registryNames() []string {
+	names := make([]string, 0, len(sr.registries))
+	for _, named := range sr.registries {
+		names = append(names, named.Name)
+	}
+	return names
+}
+
+// Close 关闭注册器及所有注册中心连接
 func (sr *ServiceRegistrar) Close() error {
 	sr.cancel()
-	return sr.registry.Close()
+	var errs []error
+	for _, named := range sr.registries {
+		if err := named.Registry.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", named.Name, err))
+		}
+	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,117 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type failingRegistry struct {
+	StaticRegistry
+	registerErr   error
+	deregisterErr error
+	deregistered  int
+	closed        int
+}
+
+func newFailingRegistry() *failingRegistry {
+	return &failingRegistry{StaticRegistry: *NewStaticRegistry()}
+}
+
+func (r *failingRegistry) Register(ctx context.Context, serviceName, address string, metadata map[string]string) error {
+	if r.registerErr != nil {
+		return r.registerErr
+	}
+	return r.StaticRegistry.Register(ctx, serviceName, address, metadata)
+}
+
+func (r *failingRegistry) Deregister(ctx context.Context, serviceName, address string) error {
+	r.deregistered++
+	if r.deregisterErr != nil {
+		return r.deregisterErr
+	}
+	return r.StaticRegistry.Deregister(ctx, serviceName, address)
+}
+
+func (r *failingRegistry) Close() error {
+	r.closed++
+	return nil
+}
+
+func TestMultiServiceRegistrarRegistersAllRegistries(t *testing.T) {
+	etcd, consul := newFailingRegistry(), newFailingRegistry()
+	consul.deregisterErr = errors.New("consul unavailable")
+	registrar, err := NewMultiServiceRegistrar([]NamedRegistry{
+		{Name: "etcd", Registry: etcd},
+		{Name: "consul", Registry: consul},
+	}, "svc", "10.0.0.1:9000", nil)
+	if err != nil {
+		t.Fatalf("NewMultiServiceRegistrar failed: %v", err)
+	}
+
+	if err := registrar.Register(context.Background()); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if len(etcd.GetServices("svc")) != 1 || len(consul.GetServices("svc")) != 1 {
+		t.Fatal("expected service to be registered in every registry")
+	}
+	for _, status := range registrar.Status() {
+		if !status.Registered || status.Err != nil {
+			t.Fatalf("unexpected status after register: %+v", status)
+		}
+	}
+
+	// 某个注册中心注销失败时仍注销其他注册中心，并报告失败的注册中心
+	err = registrar.Deregister(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "consul") {
+		t.Fatalf("expected consul deregister error, got %v", err)
+	}
+	if len(etcd.GetServices("svc")) != 0 {
+		t.Fatal("expected etcd deregistration despite consul failure")
+	}
+	status := registrar.Status()
+	if status[0].Name != "etcd" || status[0].Registered || status[1].Name != "consul" || !status[1].Registered || status[1].Err == nil {
+		t.Fatalf("unexpected status after deregister: %+v", status)
+	}
+
+	if err := registrar.Close(); err != nil || etcd.closed != 1 || consul.closed != 1 {
+		t.Fatalf("expected every registry to be closed, err=%v", err)
+	}
+}
+
+func TestMultiServiceRegistrarRollsBackPartialRegistration(t *testing.T) {
+	etcd, consul := newFailingRegistry(), newFailingRegistry()
+	consul.registerErr = errors.New("consul unavailable")
+	registrar, err := NewMultiServiceRegistrar([]NamedRegistry{
+		{Name: "etcd", Registry: etcd},
+		{Name: "consul", Registry: consul},
+	}, "svc", "10.0.0.1:9000", nil)
+	if err != nil {
+		t.Fatalf("NewMultiServiceRegistrar failed: %v", err)
+	}
+
+	err = registrar.Register(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "consul") {
+		t.Fatalf("expected consul register error, got %v", err)
+	}
+	if len(etcd.GetServices("svc")) != 0 || etcd.deregistered != 1 {
+		t.Fatal("expected successful etcd registration to be rolled back")
+	}
+	if status := registrar.Status(); status[0].Registered || status[1].Registered || status[1].Err == nil {
+		t.Fatalf("unexpected status after failed register: %+v", status)
+	}
+}
+
+func TestNewMultiServiceRegistrarValidatesRegistries(t *testing.T) {
+	cases := [][]NamedRegistry{
+		nil,
+		{{Name: "", Registry: NewStaticRegistry()}},
+		{{Name: "etcd", Registry: NewStaticRegistry()}, {Name: "etcd", Registry: NewStaticRegistry()}},
+	}
+	for _, registries := range cases {
+		if _, err := NewMultiServiceRegistrar(registries, "svc", "10.0.0.1:9000", nil); err == nil {
+			t.Fatalf("expected registries %+v to be rejected", registries)
+		}
+	}
+}
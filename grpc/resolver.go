@@ -38,8 +38,11 @@ type discoveryKeyer interface {
 }
 
 // StaticResolver 静态服务发现（直接指定地址列表）
+// 地址通过 UpdateAddresses 更新时通知 Watch 回调；需要按服务增减实例或注入故障时使用 MemoryRegistry
 type StaticResolver struct {
 	addresses []string
+	watchers  map[int]func([]string)
+	nextID    int
 	mu        sync.RWMutex
 }
 
@@ -65,14 +68,29 @@ func (r *StaticResolver) Resolve(ctx context.Context, serviceName string) ([]str
 	return result, nil
 }
 
-// Watch 监听服务变化（静态服务发现不需要监听）
+// Watch 监听服务变化（立即回调一次当前地址，之后在 UpdateAddresses 时回调，ctx 结束时停止）
 func (r *StaticResolver) Watch(ctx context.Context, serviceName string, callback func([]string)) error {
-	// 静态服务发现不需要监听，直接调用一次回调
 	addresses, err := r.Resolve(ctx, serviceName)
 	if err != nil {
 		return err
 	}
 	callback(addresses)
+
+	r.mu.Lock()
+	if r.watchers == nil {
+		r.watchers = make(map[int]func([]string))
+	}
+	r.nextID++
+	id := r.nextID
+	r.watchers[id] = callback
+	r.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		r.mu.Lock()
+		delete(r.watchers, id)
+		r.mu.Unlock()
+	}()
 	return nil
 }
 
@@ -81,11 +99,22 @@ func (r *StaticResolver) Close() error {
 	return nil
 }
 
-// UpdateAddresses 更新地址列表
+// UpdateAddresses 更新地址列表并通知监听方（列表为空时只更新，不通知）
 func (r *StaticResolver) UpdateAddresses(addresses []string) {
 	r.mu.Lock()
-	defer r.mu.Unlock()
 	r.addresses = addresses
+	callbacks := make([]func([]string), 0, len(r.watchers))
+	for _, callback := range r.watchers {
+		callbacks = append(callbacks, callback)
+	}
+	r.mu.Unlock()
+
+	if len(addresses) == 0 {
+		return
+	}
+	for _, callback := range callbacks {
+		callback(append([]string(nil), addresses...))
+	}
 }
 
 // DiscoveryKey returns a stable key for enforcing one config per resolver scheme.
@@ -197,39 +226,65 @@ func (r *serviceResolver) start() {
 	logger.Info(r.ctx, "Resolver starting for service: %s", serviceName)
 
 	// 首次解析
-	addresses, err := r.sd.Resolve(r.ctx, serviceName)
+	addrs, err := r.resolveAddresses(r.ctx, serviceName)
 	if err != nil {
 		logger.Error(r.ctx, "Failed to resolve service: service=%s, error=%v", serviceName, err)
 		return
 	}
 
-	r.updateState(addresses)
+	r.updateState(addrs)
 
 	// 监听服务变化
 	go func() {
-		err := r.sd.Watch(r.ctx, serviceName, func(addrs []string) {
-			r.updateState(addrs)
-		})
+		var err error
+		if discovery, ok := r.sd.(InstanceDiscovery); ok {
+			err = discovery.WatchInstances(r.ctx, serviceName, func(instances []ServiceInfo) {
+				r.updateState(instanceAddresses(instances))
+			})
+		} else {
+			err = r.sd.Watch(r.ctx, serviceName, func(addresses []string) {
+				r.updateState(plainAddresses(addresses))
+			})
+		}
 		if err != nil {
 			logger.Error(r.ctx, "Service discovery watch failed: service=%s, error=%v", serviceName, err)
 		}
 	}()
 }
 
-// updateState 更新连接状态
-func (r *serviceResolver) updateState(addresses []string) {
-	serviceName := r.getServiceName()
-	if len(addresses) == 0 {
-		logger.Warn(r.ctx, "No addresses available for service: service=%s", serviceName)
-		return
+// resolveAddresses 解析服务地址，服务发现实现 InstanceDiscovery 时附加实例元数据
+func (r *serviceResolver) resolveAddresses(ctx context.Context, serviceName string) ([]resolver.Address, error) {
+	if discovery, ok := r.sd.(InstanceDiscovery); ok {
+		instances, err := discovery.ResolveInstances(ctx, serviceName)
+		if err != nil {
+			return nil, err
+		}
+		return instanceAddresses(instances), nil
+	}
+	addresses, err := r.sd.Resolve(ctx, serviceName)
+	if err != nil {
+		return nil, err
 	}
+	return plainAddresses(addresses), nil
+}
 
+func plainAddresses(addresses []string) []resolver.Address {
 	addrs := make([]resolver.Address, 0, len(addresses))
 	for _, addr := range addresses {
 		addrs = append(addrs, resolver.Address{
 			Addr: addr,
 		})
 	}
+	return addrs
+}
+
+// updateState 更新连接状态
+func (r *serviceResolver) updateState(addrs []resolver.Address) {
+	serviceName := r.getServiceName()
+	if len(addrs) == 0 {
+		logger.Warn(r.ctx, "No addresses available for service: service=%s", serviceName)
+		return
+	}
 
 	state := resolver.State{
 		Addresses: addrs,
@@ -240,6 +295,10 @@ func (r *serviceResolver) updateState(addresses []string) {
 		return
 	}
 
+	addresses := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		addresses = append(addresses, addr.Addr)
+	}
 	logger.Info(r.ctx, "Resolver state updated: service=%s, addresses=%v", serviceName, addresses)
 }
 
@@ -249,12 +308,12 @@ func (r *serviceResolver) ResolveNow(resolver.ResolveNowOptions) {
 	if serviceName == "" {
 		return
 	}
-	addresses, err := r.sd.Resolve(r.ctx, serviceName)
+	addrs, err := r.resolveAddresses(r.ctx, serviceName)
 	if err != nil {
 		logger.Error(r.ctx, "Failed to resolve service: service=%s, error=%v", serviceName, err)
 		return
 	}
-	r.updateState(addresses)
+	r.updateState(addrs)
 }
 
 // Close 关闭 resolver
@@ -9,10 +9,13 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/admin"
+	channelzservice "google.golang.org/grpc/channelz/service"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/team-dandelion/quickgo/handover"
 	"github.com/team-dandelion/quickgo/logger"
 )
 
@@ -26,9 +29,11 @@ type Server struct {
 	options    []grpc.ServerOption
 	services   []ServiceRegister
 	reflection bool
-	mu         sync.RWMutex
-	running    bool
-	stopped    bool
+	// admin 服务的清理函数（启用 Admin 时）
+	adminCleanup func()
+	mu           sync.RWMutex
+	running      bool
+	stopped      bool
 }
 
 // ServiceRegister 服务注册接口
@@ -40,6 +45,8 @@ type Config struct {
 	Port       int
 	Options    []grpc.ServerOption
 	Reflection bool // 是否启用反射（用于调试）
+	Channelz   bool // 是否注册 channelz 服务（用于 grpcdebug 等工具查看连接状态）
+	Admin      bool // 是否注册 gRPC admin 服务（包含 channelz，以及引入 xds 时的 CSDS）
 }
 
 // NewServer 创建新的gRPC服务器实例
@@ -73,6 +80,17 @@ func NewServer(config Config) (*Server, error) {
 		reflection.Register(s.server)
 	}
 
+	// admin 服务已包含 channelz，两者同时启用时只注册 admin，避免重复注册
+	if config.Admin {
+		cleanup, err := admin.Register(s.server)
+		if err != nil {
+			return nil, fmt.Errorf("failed to register grpc admin services: %w", err)
+		}
+		s.adminCleanup = cleanup
+	} else if config.Channelz {
+		channelzservice.RegisterChannelzServiceToServer(s.server)
+	}
+
 	return s, nil
 }
 
@@ -137,7 +155,7 @@ func (s *Server) Listen() error {
 	}
 
 	addr := fmt.Sprintf("%s:%d", s.address, s.port)
-	listener, err := net.Listen("tcp", addr)
+	listener, err := handover.Listen("tcp", addr)
 	if err != nil {
 		return fmt.Errorf("failed to listen on %s: %v", addr, err)
 	}
@@ -227,11 +245,7 @@ func (s *Server) Stop() error {
 	if err := s.closeListener(); err != nil {
 		return err
 	}
-	s.mu.Lock()
-	s.running = false
-	s.listener = nil
-	s.stopped = true
-	s.mu.Unlock()
+	s.markStopped()
 	return nil
 }
 
@@ -262,22 +276,14 @@ func (s *Server) StopWithContext(ctx context.Context) error {
 		if err := s.closeListener(); err != nil {
 			return err
 		}
-		s.mu.Lock()
-		s.running = false
-		s.listener = nil
-		s.stopped = true
-		s.mu.Unlock()
+		s.markStopped()
 		return ctx.Err()
 	case <-stopped:
 		logger.Info(ctx, "gRPC server gracefully stopped")
 		if err := s.closeListener(); err != nil {
 			return err
 		}
-		s.mu.Lock()
-		s.running = false
-		s.listener = nil
-		s.stopped = true
-		s.mu.Unlock()
+		s.markStopped()
 		return nil
 	}
 }
@@ -320,6 +326,20 @@ func (s *Server) markRunning() error {
 	return nil
 }
 
+// markStopped 标记服务器已停止并释放 admin 服务资源
+func (s *Server) markStopped() {
+	s.mu.Lock()
+	s.running = false
+	s.listener = nil
+	s.stopped = true
+	cleanup := s.adminCleanup
+	s.adminCleanup = nil
+	s.mu.Unlock()
+	if cleanup != nil {
+		cleanup()
+	}
+}
+
 func (s *Server) getListener() net.Listener {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -0,0 +1,192 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	grpcstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/team-dandelion/quickgo/grpcep"
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// HTTPFallbackConfig gRPC 直连不可用时，通过 HTTP/JSON 网关隧道转发调用的配置
+type HTTPFallbackConfig struct {
+	// 网关隧道地址，示例：https://api.example.com/grpc-tunnel
+	URL string
+	// 目标服务名称（网关用于选择后端，如 user-service）
+	Target string
+	// 单次请求超时（context 无 deadline 时使用，默认 30s）
+	Timeout time.Duration
+	// 自定义 HTTP 客户端（可选）
+	HTTPClient *http.Client
+	// 附加请求头（如网关鉴权）
+	Headers map[string]string
+	// 使用隧道期间重新尝试直连的间隔（默认 30s），直连恢复后自动切回
+	ProbeInterval time.Duration
+}
+
+// defaultFallbackProbeInterval 使用隧道期间重新尝试直连的默认间隔
+const defaultFallbackProbeInterval = 30 * time.Second
+
+// tunnelResponse 隧道响应体（与 grpcep.JsonResponse 兼容，协议见 grpcep.GRPCTunnel）
+type tunnelResponse struct {
+	Code int32           `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+// HTTPTunnelConn 通过 HTTP/JSON 网关转发一元调用的连接，实现 grpc.ClientConnInterface
+// 生成的 gRPC 客户端存根可直接使用，流式调用不支持
+type HTTPTunnelConn struct {
+	url     string
+	target  string
+	timeout time.Duration
+	client  *http.Client
+	headers map[string]string
+}
+
+var _ grpc.ClientConnInterface = (*HTTPTunnelConn)(nil)
+
+// NewHTTPTunnelConn 创建 HTTP 隧道连接
+func NewHTTPTunnelConn(config HTTPFallbackConfig) (*HTTPTunnelConn, error) {
+	if config.URL == "" {
+		return nil, errors.New("http fallback url is required")
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &HTTPTunnelConn{
+		url:     strings.TrimSuffix(config.URL, "/"),
+		target:  config.Target,
+		timeout: config.Timeout,
+		client:  client,
+		headers: config.Headers,
+	}, nil
+}
+
+// Invoke 通过 HTTP 隧道执行一元调用
+func (t *HTTPTunnelConn) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	in, ok := args.(proto.Message)
+	if !ok {
+		return grpcstatus.Errorf(codes.Internal, "http tunnel: request is not a proto message: %T", args)
+	}
+	out, ok := reply.(proto.Message)
+	if !ok {
+		return grpcstatus.Errorf(codes.Internal, "http tunnel: reply is not a proto message: %T", reply)
+	}
+
+	body, err := protojson.Marshal(in)
+	if err != nil {
+		return grpcstatus.Errorf(codes.Internal, "http tunnel: failed to marshal request: %v", err)
+	}
+
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url+"/"+strings.TrimPrefix(method, "/"), bytes.NewReader(body))
+	if err != nil {
+		return grpcstatus.Errorf(codes.Internal, "http tunnel: failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if t.target != "" {
+		req.Header.Set(grpcep.TunnelTargetHeader, t.target)
+	}
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+	if traceID := logger.GetTraceID(ctx); traceID != "" {
+		req.Header.Set(TraceIDMetadataKey, traceID)
+	}
+	// 剩余时间传给网关，网关对后端的调用使用相同的截止时间
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Header.Set(grpcep.TunnelTimeoutHeader, strconv.FormatInt(max(time.Until(deadline).Milliseconds(), 1), 10))
+	}
+	if md, ok := metadata.FromOutgoingContext(ctx); ok {
+		for key, values := range md {
+			for _, value := range values {
+				req.Header.Add(grpcep.TunnelMetadataPrefix+key, value)
+			}
+		}
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return grpcstatus.FromContextError(ctxErr).Err()
+		}
+		return grpcstatus.Errorf(codes.Unavailable, "http tunnel: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return grpcstatus.Errorf(codes.Unavailable, "http tunnel: failed to read response: %v", err)
+	}
+
+	if err := grpcep.DecodeTunnelStatus(resp.Header.Get); err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return grpcstatus.Errorf(httpStatusToCode(resp.StatusCode), "http tunnel: unexpected http status %d", resp.StatusCode)
+	}
+
+	var envelope tunnelResponse
+	if err := json.Unmarshal(respBody, &envelope); err != nil {
+		return grpcstatus.Errorf(codes.Internal, "http tunnel: failed to decode response: %v", err)
+	}
+	if len(envelope.Data) == 0 || string(envelope.Data) == "null" {
+		return nil
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(envelope.Data, out); err != nil {
+		return grpcstatus.Errorf(codes.Internal, "http tunnel: failed to unmarshal reply: %v", err)
+	}
+	return nil
+}
+
+// NewStream HTTP 隧道不支持流式调用
+func (t *HTTPTunnelConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, grpcstatus.Errorf(codes.Unimplemented, "http tunnel: streaming method %s is not supported", method)
+}
+
+func httpStatusToCode(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.Unimplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return codes.Unavailable
+	default:
+		return codes.Unknown
+	}
+}
+
+// String 返回隧道描述
+func (t *HTTPTunnelConn) String() string {
+	return fmt.Sprintf("http-tunnel(%s, target=%s)", t.url, t.target)
+}
@@ -0,0 +1,269 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/team-dandelion/quickgo/grpcep"
+)
+
+// startTunnelGateway 启动后端 gRPC 服务与挂载隧道的 HTTP 网关（handlers 在隧道之前执行），返回隧道地址与后端收到的 metadata
+func startTunnelGateway(t *testing.T, handlers ...fiber.Handler) (string, <-chan metadata.MD) {
+	t.Helper()
+
+	received := make(chan metadata.MD, 8)
+	backend := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		received <- md
+		return handler(ctx, req)
+	}))
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("user-service", grpc_health_v1.HealthCheckResponse_SERVING)
+	grpc_health_v1.RegisterHealthServer(backend, healthServer)
+
+	grpcListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen grpc: %v", err)
+	}
+	go func() { _ = backend.Serve(grpcListener) }()
+	t.Cleanup(backend.Stop)
+
+	backendConn, err := grpc.NewClient(grpcListener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial backend: %v", err)
+	}
+	t.Cleanup(func() { _ = backendConn.Close() })
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	handlers = append(handlers, (&grpcep.BaseHandler{}).GRPCTunnel(func(ctx context.Context, target string) (grpc.ClientConnInterface, error) {
+		if target != "user-service" {
+			return nil, fmt.Errorf("unknown target %s", target)
+		}
+		return backendConn, nil
+	}))
+	app.Post("/grpc-tunnel/*", handlers...)
+	httpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen http: %v", err)
+	}
+	go func() { _ = app.Listener(httpListener) }()
+	t.Cleanup(func() { _ = app.Shutdown() })
+
+	return "http://" + httpListener.Addr().String() + "/grpc-tunnel", received
+}
+
+func TestClientFallsBackToHTTPTunnel(t *testing.T) {
+	tunnelURL, received := startTunnelGateway(t)
+
+	client, err := NewClient(ClientConfig{
+		Address:  fmt.Sprintf("127.0.0.1:%d", reserveTCPPort(t)),
+		Timeout:  200 * time.Millisecond,
+		Insecure: true,
+		HTTPFallback: &HTTPFallbackConfig{
+			URL:    tunnelURL,
+			Target: "user-service",
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("expected fallback connect to succeed, got %v", err)
+	}
+	if !client.UsingHTTPFallback() || !client.IsConnected() {
+		t.Fatalf("expected client to be connected via http tunnel")
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-tenant-id", "t1")
+	healthClient := grpc_health_v1.NewHealthClient(client.Conn())
+	resp, err := healthClient.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "user-service"})
+	if err != nil {
+		t.Fatalf("Check over tunnel failed: %v", err)
+	}
+	if resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Fatalf("expected SERVING, got %v", resp.GetStatus())
+	}
+	select {
+	case md := <-received:
+		if got := md.Get("x-tenant-id"); len(got) != 1 || got[0] != "t1" {
+			t.Fatalf("expected metadata to be forwarded, got %v", md)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("backend did not receive the call")
+	}
+
+	_, err = healthClient.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "missing service"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound to round-trip through tunnel, got %v", err)
+	}
+	if st, _ := status.FromError(err); st.Message() != "unknown service" {
+		t.Fatalf("expected original status message, got %q", st.Message())
+	}
+
+	stream, err := healthClient.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "user-service"})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unimplemented {
+		t.Fatalf("expected streaming to be unimplemented over tunnel, got %v", err)
+	}
+}
+
+func TestHTTPTunnelDropsReservedMetadata(t *testing.T) {
+	tunnelURL, received := startTunnelGateway(t, func(c *fiber.Ctx) error {
+		// 网关鉴权后写入的身份
+		c.Context().SetUserValue("x-user-id", "u1")
+		return c.Next()
+	})
+
+	conn, err := NewHTTPTunnelConn(HTTPFallbackConfig{URL: tunnelURL, Target: "user-service"})
+	if err != nil {
+		t.Fatalf("NewHTTPTunnelConn failed: %v", err)
+	}
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		"x-user-id", "admin", "x-user-roles", "admin", "x-tenant-id", "t1")
+	if _, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "user-service"}); err != nil {
+		t.Fatalf("Check over tunnel failed: %v", err)
+	}
+	select {
+	case md := <-received:
+		if got := md.Get("x-user-id"); len(got) != 1 || got[0] != "u1" {
+			t.Fatalf("expected only the gateway identity, got %v", got)
+		}
+		if got := md.Get("x-user-roles"); len(got) != 0 {
+			t.Fatalf("expected client roles to be dropped, got %v", got)
+		}
+		if got := md.Get("x-tenant-id"); len(got) != 1 || got[0] != "t1" {
+			t.Fatalf("expected other metadata to be forwarded, got %v", md)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("backend did not receive the call")
+	}
+}
+
+func TestHTTPTunnelAppliesMetadataPolicy(t *testing.T) {
+	tunnelURL, received := startTunnelGateway(t, grpcep.MetadataPolicy(grpcep.MetadataConfig{
+		Deny:          []string{"x-debug-*"},
+		MaxValueBytes: 8,
+	}))
+
+	conn, err := NewHTTPTunnelConn(HTTPFallbackConfig{URL: tunnelURL, Target: "user-service"})
+	if err != nil {
+		t.Fatalf("NewHTTPTunnelConn failed: %v", err)
+	}
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		"x-debug-force", "1", "x-tenant-id", "t1", "x-note", "longer than eight bytes")
+	if _, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "user-service"}); err != nil {
+		t.Fatalf("Check over tunnel failed: %v", err)
+	}
+	select {
+	case md := <-received:
+		if got := md.Get("x-debug-force"); len(got) != 0 {
+			t.Fatalf("expected denied header to be dropped, got %v", got)
+		}
+		if got := md.Get("x-note"); len(got) != 0 {
+			t.Fatalf("expected oversized value to be dropped, got %v", got)
+		}
+		if got := md.Get("x-tenant-id"); len(got) != 1 || got[0] != "t1" {
+			t.Fatalf("expected allowed metadata to be forwarded, got %v", md)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("backend did not receive the call")
+	}
+}
+
+func TestHTTPTunnelRejectsUnknownTarget(t *testing.T) {
+	tunnelURL, _ := startTunnelGateway(t)
+
+	conn, err := NewHTTPTunnelConn(HTTPFallbackConfig{URL: tunnelURL, Target: "order-service"})
+	if err != nil {
+		t.Fatalf("NewHTTPTunnelConn failed: %v", err)
+	}
+	_, err = grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable for unknown target, got %v", err)
+	}
+}
+
+func TestClientSwitchesBackToDirectConnection(t *testing.T) {
+	tunnelURL, _ := startTunnelGateway(t)
+	address := fmt.Sprintf("127.0.0.1:%d", reserveTCPPort(t))
+
+	client, err := NewClient(ClientConfig{
+		Address:  address,
+		Timeout:  200 * time.Millisecond,
+		Insecure: true,
+		HTTPFallback: &HTTPFallbackConfig{
+			URL:           tunnelURL,
+			Target:        "user-service",
+			ProbeInterval: 20 * time.Millisecond,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("expected fallback connect to succeed, got %v", err)
+	}
+	if !client.UsingHTTPFallback() {
+		t.Fatalf("expected client to start on the http tunnel")
+	}
+
+	// 直连恢复
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("user-service", grpc_health_v1.HealthCheckResponse_SERVING)
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for client.UsingHTTPFallback() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if client.UsingHTTPFallback() || client.GetConn() == nil {
+		t.Fatalf("expected client to switch back to the direct connection")
+	}
+	if _, ok := client.Conn().(*grpc.ClientConn); !ok {
+		t.Fatalf("expected Conn to return the direct connection, got %T", client.Conn())
+	}
+}
+
+func TestClientWithoutFallbackReportsDialError(t *testing.T) {
+	client, err := NewClient(ClientConfig{
+		Address:  fmt.Sprintf("127.0.0.1:%d", reserveTCPPort(t)),
+		Timeout:  200 * time.Millisecond,
+		Insecure: true,
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Connect(context.Background()); err == nil {
+		t.Fatalf("expected connect to fail without fallback")
+	}
+	if client.Conn() != nil || client.UsingHTTPFallback() {
+		t.Fatalf("expected no connection without fallback")
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand/v2"
 
+	"github.com/team-dandelion/quickgo/depmap"
 	"github.com/team-dandelion/quickgo/grpc"
+	"github.com/team-dandelion/quickgo/grpcep"
 	"github.com/team-dandelion/quickgo/logger"
 	"sync"
 	"sync/atomic"
@@ -14,6 +17,9 @@ import (
 	rpc "google.golang.org/grpc"
 )
 
+// defaultGrpcClientWarmUpTimeout 启动预热连接的默认等待时间
+const defaultGrpcClientWarmUpTimeout = 10 * time.Second
+
 // GrpcClientConfig gRPC 客户端配置（全局配置，所有服务共享）
 type GrpcClientConfig struct {
 	// 服务发现模式：static（静态地址），etcd（etcd 服务发现）
@@ -22,40 +28,133 @@ type GrpcClientConfig struct {
 	// 格式：服务名 -> 地址（如 "user-service": "127.0.0.1:9001"）
 	StaticAddresses map[string]string `json:"staticAddresses" yaml:"staticAddresses" toml:"staticAddresses"`
 	// 连接超时时间 示例：10s
-	Timeout string `json:"timeout" yaml:"timeout" toml:"timeout"`
+	Timeout Duration `json:"timeout" yaml:"timeout" toml:"timeout"`
 	// 是否使用非安全连接（不加密）
 	Insecure bool `json:"insecure" yaml:"insecure" toml:"insecure"`
 	// 心跳时间 示例：10s
-	KeepAliveTime string `json:"keepAliveTime" yaml:"keepAliveTime" toml:"keepAliveTime"`
+	KeepAliveTime Duration `json:"keepAliveTime" yaml:"keepAliveTime" toml:"keepAliveTime"`
 	// 心跳超时时间 示例：3s
-	KeepAliveTimeout string `json:"keepAliveTimeout" yaml:"keepAliveTimeout" toml:"keepAliveTimeout"`
+	KeepAliveTimeout Duration `json:"keepAliveTimeout" yaml:"keepAliveTimeout" toml:"keepAliveTimeout"`
 	// 是否允许在没有活跃流时发送心跳
 	PermitWithoutStream bool `json:"permitWithoutStream" yaml:"permitWithoutStream" toml:"permitWithoutStream"`
 	// 负载均衡策略：round_robin, pick_first, weighted_round_robin
 	LoadBalancing string `json:"loadBalancing" yaml:"loadBalancing" toml:"loadBalancing"`
 	// 连接池大小（每个服务的连接数，默认为 1，建议设置为 2-4 以避免 HTTP/2 HPACK 并发问题）
 	PoolSize int `json:"poolSize" yaml:"poolSize" toml:"poolSize"`
-	// 健康检查间隔 示例：30s（默认 30s，设置为空或 0 则禁用）
-	HealthCheckInterval string `json:"healthCheckInterval" yaml:"healthCheckInterval" toml:"healthCheckInterval"`
-	// 连接失败后重试间隔 示例：5s（默认 5s）
-	ReconnectInterval string `json:"reconnectInterval" yaml:"reconnectInterval" toml:"reconnectInterval"`
+	// 健康检查间隔 示例：30s（默认 30s，设置为负值如 -1s 则禁用）
+	HealthCheckInterval Duration `json:"healthCheckInterval" yaml:"healthCheckInterval" toml:"healthCheckInterval"`
+	// 连接失败后重试间隔 示例：5s（默认 5s），连续失败时按指数退避增长
+	ReconnectInterval Duration `json:"reconnectInterval" yaml:"reconnectInterval" toml:"reconnectInterval"`
+	// 重试间隔上限 示例：2m（默认 2m）
+	ReconnectMaxInterval Duration `json:"reconnectMaxInterval" yaml:"reconnectMaxInterval" toml:"reconnectMaxInterval"`
+	// 懒连接：GetClient 不等待连接建立，连接在后台建立并自动重连（依赖暂时不可用时不会导致调用方立即失败）
+	// 懒连接不会探测直连是否可用，不能与 HTTPFallback 同时使用
+	Lazy bool `json:"lazy" yaml:"lazy" toml:"lazy"`
+	// 调用默认超时 示例：5s（调用 context 没有截止时间时使用；HTTP 请求的截止时间会随调用传递给下游，见 http.RequestTimeoutHeader）
+	CallTimeout Duration `json:"callTimeout" yaml:"callTimeout" toml:"callTimeout"`
+	// 调用默认等待连接就绪（受调用 context 超时约束），而不是连接不可用时立即失败
+	WaitForReady bool `json:"waitForReady" yaml:"waitForReady" toml:"waitForReady"`
+	// 框架启动时预先建立所有已注册服务的连接（WarmUp），连接失败的服务降级为懒连接
+	WarmUp bool `json:"warmUp" yaml:"warmUp" toml:"warmUp"`
 	// Etcd 配置（使用 etcd 服务发现时必需，全局共享）
 	Etcd *EtcdConfig `json:"etcd" yaml:"etcd" toml:"etcd"`
+	// HTTP 隧道备用通道（直连 gRPC 端口不可达时通过网关转发一元调用，可选）
+	HTTPFallback *GrpcHTTPFallbackConfig `json:"httpFallback" yaml:"httpFallback" toml:"httpFallback"`
+	// 启用的客户端拦截器及顺序（由外到内），示例：[tracing, logging, recovery]
+	// 可选名称：内置的 tracing、logging、recovery、deadline 以及通过 GrpcClientManager.Use 注册的拦截器；为空时使用 tracing、logging、deadline 与自定义拦截器
+	InterceptorOrder []string `json:"interceptors" yaml:"interceptors" toml:"interceptors"`
+	// 灰度路由规则（可选，需要 etcd 服务发现）
+	// 格式：服务名 -> 规则，将部分流量路由到注册元数据 version 匹配的实例
+	Canary map[string]*GrpcCanaryConfig `json:"canary" yaml:"canary" toml:"canary"`
+	// 按服务名追加的 DialOption（可选，仅代码配置），如测试中通过 bufconn 连接进程内服务（见 quickgotest）
+	DialOptions func(serviceName string) []rpc.DialOption `json:"-" yaml:"-" toml:"-"`
+}
+
+// GrpcCanaryConfig 灰度路由配置
+// 请求携带 Header 时始终路由到灰度版本，否则按 Percent 随机路由；灰度实例不可用时回退到其他实例
+type GrpcCanaryConfig struct {
+	// 灰度版本（服务端 RegisterMetadata 中的 version）示例：2.0.0
+	Version string `json:"version" yaml:"version" toml:"version"`
+	// 路由到灰度版本的流量百分比（0-100）
+	Percent float64 `json:"percent" yaml:"percent" toml:"percent"`
+	// 请求 metadata 键（可选）示例：x-canary，网关可使用 grpcep.ForwardHeaders 透传同名请求头
+	Header string `json:"header" yaml:"header" toml:"header"`
+	// 请求 metadata 值（可选），为空时只要携带 Header 即路由到灰度版本
+	HeaderValue string `json:"headerValue" yaml:"headerValue" toml:"headerValue"`
+}
+
+// rule 转换为 gRPC 灰度路由规则
+func (c *GrpcCanaryConfig) rule() *grpc.CanaryRule {
+	if c == nil {
+		return nil
+	}
+	return &grpc.CanaryRule{
+		Version:     c.Version,
+		Percent:     c.Percent,
+		Header:      c.Header,
+		HeaderValue: c.HeaderValue,
+	}
+}
+
+// validateGrpcClientConfig 校验客户端配置
+func validateGrpcClientConfig(config *GrpcClientConfig) error {
+	if config.Lazy && config.HTTPFallback != nil && config.HTTPFallback.URL != "" {
+		return errors.New("grpc client lazy and httpFallback cannot be used together: lazy connections never detect a failed dial, so the HTTP tunnel would not be used")
+	}
+	return validateGrpcCanaryConfig(config)
+}
+
+// validateGrpcCanaryConfig 校验灰度路由配置
+func validateGrpcCanaryConfig(config *GrpcClientConfig) error {
+	if len(config.Canary) == 0 {
+		return nil
+	}
+	if config.Etcd == nil {
+		return errors.New("grpc client canary routing requires etcd discovery")
+	}
+	for service, canary := range config.Canary {
+		if canary == nil {
+			continue
+		}
+		if err := canary.rule().Validate(); err != nil {
+			return fmt.Errorf("invalid canary rule for service %s: %w", service, err)
+		}
+	}
+	return nil
+}
+
+// GrpcHTTPFallbackConfig gRPC over HTTP 隧道配置（网关侧使用 grpcep.BaseHandler.GRPCTunnel 挂载）
+type GrpcHTTPFallbackConfig struct {
+	// 网关隧道地址 示例：https://api.example.com/grpc-tunnel
+	URL string `json:"url" yaml:"url" toml:"url"`
+	// 单次请求超时 示例：30s（默认 30s）
+	Timeout Duration `json:"timeout" yaml:"timeout" toml:"timeout"`
+	// 附加请求头（如网关鉴权）
+	Headers map[string]string `json:"headers" yaml:"headers" toml:"headers"`
+	// 使用隧道期间重新尝试直连的间隔 示例：30s（默认 30s），直连恢复后自动切回
+	ProbeInterval Duration `json:"probeInterval" yaml:"probeInterval" toml:"probeInterval"`
 }
 
 // GrpcClientManager gRPC 客户端管理器
 // 用于管理多个 gRPC 服务客户端，适合网关场景
 type GrpcClientManager struct {
-	clientPools         map[string]*clientPool // 服务名称 -> 连接池
-	services            map[string]string      // 服务名称 -> 服务名称（用于记录已注册的服务）
-	globalConfig        *GrpcClientConfig      // 全局配置（所有服务共享）
-	etcdResolver        *grpc.EtcdResolver     // 共享的 etcd resolver
-	mu                  sync.RWMutex
-	healthCheckInterval time.Duration // 健康检查间隔
-	reconnectInterval   time.Duration // 重连间隔
-	healthCheckCtx      context.Context
-	healthCheckCancel   context.CancelFunc
-	healthCheckRunning  bool
+	clientPools          map[string]*clientPool // 服务名称 -> 连接池
+	services             map[string]string      // 服务名称 -> 服务名称（用于记录已注册的服务）
+	globalConfig         *GrpcClientConfig      // 全局配置（所有服务共享）
+	etcdResolver         *grpc.EtcdResolver     // 共享的 etcd resolver
+	mu                   sync.RWMutex
+	healthCheckInterval  time.Duration // 健康检查间隔
+	reconnectInterval    time.Duration // 重连间隔
+	reconnectMaxInterval time.Duration // 重连间隔上限
+	healthCheckCtx       context.Context
+	healthCheckCancel    context.CancelFunc
+	healthCheckRunning   bool
+	// 自定义客户端拦截器（通过 Use 注册），创建第一个连接后不可修改
+	customInterceptors []grpc.ClientInterceptorSpec
+	interceptorsFrozen bool
+	// 托管的流（OpenStream / WatchStream），CloseAll 时取消
+	streamsMu sync.Mutex
+	streams   map[*context.CancelCauseFunc]struct{}
 }
 
 // clientPool 连接池
@@ -66,6 +165,11 @@ type clientPool struct {
 	mu           sync.RWMutex
 	unhealthy    []int        // 不健康的连接索引
 	reconnecting map[int]bool // 正在重连的连接索引
+	// 懒连接池：连接由 gRPC 在后台建立与重连，未就绪的客户端同样可被选取
+	lazy bool
+	// 连续重连失败次数与下次允许重连的时间（指数退避）
+	failures    map[int]int
+	nextAttempt map[int]time.Time
 }
 
 // NewGrpcClientManager 创建 gRPC 客户端管理器
@@ -75,54 +179,42 @@ func NewGrpcClientManager(config *GrpcClientConfig) (*GrpcClientManager, error)
 		return nil, errors.New("config is nil")
 	}
 	config = cloneGrpcClientConfig(config)
+	if err := validateGrpcClientConfig(config); err != nil {
+		return nil, err
+	}
 
 	// 设置默认连接池大小
 	if config.PoolSize <= 0 {
 		config.PoolSize = 1
 	}
 
-	// 解析健康检查间隔（默认 30s）
-	var healthCheckInterval time.Duration
-	if config.HealthCheckInterval != "" {
-		var err error
-		healthCheckInterval, err = time.ParseDuration(config.HealthCheckInterval)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse health check interval: %w", err)
-		}
-	} else {
-		healthCheckInterval = 30 * time.Second // 默认 30 秒
+	// 健康检查间隔（默认 30s）、重连间隔（默认 5s）与重连间隔上限（默认 2m）
+	healthCheckInterval := config.HealthCheckInterval.Std()
+	if config.HealthCheckInterval == 0 {
+		healthCheckInterval = 30 * time.Second
 	}
-
-	// 解析重连间隔（默认 5s）
-	var reconnectInterval time.Duration
-	if config.ReconnectInterval != "" {
-		var err error
-		reconnectInterval, err = time.ParseDuration(config.ReconnectInterval)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse reconnect interval: %w", err)
-		}
-	} else {
-		reconnectInterval = 5 * time.Second // 默认 5 秒
+	reconnectInterval := config.ReconnectInterval.OrDefault(5 * time.Second)
+	reconnectMaxInterval := config.ReconnectMaxInterval.OrDefault(2 * time.Minute)
+	if reconnectMaxInterval < reconnectInterval {
+		reconnectMaxInterval = reconnectInterval
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
 	manager := &GrpcClientManager{
-		clientPools:         make(map[string]*clientPool),
-		services:            make(map[string]string),
-		globalConfig:        config,
-		healthCheckInterval: healthCheckInterval,
-		reconnectInterval:   reconnectInterval,
-		healthCheckCtx:      ctx,
-		healthCheckCancel:   cancel,
+		clientPools:          make(map[string]*clientPool),
+		services:             make(map[string]string),
+		globalConfig:         config,
+		healthCheckInterval:  healthCheckInterval,
+		reconnectInterval:    reconnectInterval,
+		reconnectMaxInterval: reconnectMaxInterval,
+		healthCheckCtx:       ctx,
+		healthCheckCancel:    cancel,
 	}
 
 	// 如果配置了 etcd，创建共享的 resolver
 	if config.Etcd != nil {
-		dialTimeout, err := parseDurationOrDefault(config.Etcd.DialTimeout, defaultEtcdDialTimeout)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse etcd dial timeout: %w", err)
-		}
+		dialTimeout := config.Etcd.DialTimeout.OrDefault(defaultEtcdDialTimeout)
 
 		etcdConfig := grpc.EtcdConfig{
 			Endpoints:   config.Etcd.Endpoints,
@@ -172,6 +264,53 @@ func (m *GrpcClientManager) RegisterService(serviceName string) error {
 	return nil
 }
 
+// Use 注册自定义客户端拦截器，必须在创建第一个服务连接之前调用
+// 配置了 InterceptorOrder 时，只有列出的拦截器会生效
+func (m *GrpcClientManager) Use(specs ...grpc.ClientInterceptorSpec) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.interceptorsFrozen {
+		return errors.New("grpc client interceptors must be registered before the first connection is created")
+	}
+	custom := append(append([]grpc.ClientInterceptorSpec(nil), m.customInterceptors...), specs...)
+	if _, err := grpc.BuildClientInterceptors(append(m.managerInterceptors(), custom...), nil); err != nil {
+		return err
+	}
+	m.customInterceptors = custom
+	return nil
+}
+
+// clientInterceptors 返回生效的客户端拦截器链，并禁止之后的 Use
+func (m *GrpcClientManager) clientInterceptors() ([]grpc.ClientInterceptorSpec, error) {
+	m.mu.Lock()
+	m.interceptorsFrozen = true
+	custom := m.customInterceptors
+	m.mu.Unlock()
+	return grpc.BuildClientInterceptors(append(m.managerInterceptors(), custom...), m.globalConfig.InterceptorOrder)
+}
+
+// managerInterceptors 管理器内置的客户端拦截器：deadline 将调用 context 的截止时间传递给下游并应用 CallTimeout
+func (m *GrpcClientManager) managerInterceptors() []grpc.ClientInterceptorSpec {
+	return []grpc.ClientInterceptorSpec{{
+		Name:   "deadline",
+		Unary:  grpc.ClientDeadlineInterceptor(m.globalConfig.CallTimeout.Std()),
+		Stream: grpc.ClientStreamDeadlineInterceptor(),
+	}}
+}
+
+// ValidateService 检查服务是否可解析：静态发现模式下必须配置地址，服务发现模式下仅检查名称非空
+func (m *GrpcClientManager) ValidateService(serviceName string) error {
+	if serviceName == "" {
+		return errors.New("serviceName is required")
+	}
+	if m.globalConfig.Discovery == "static" {
+		if address := m.globalConfig.StaticAddresses[serviceName]; address == "" {
+			return fmt.Errorf("static address is required for service %s", serviceName)
+		}
+	}
+	return nil
+}
+
 // GetClient 获取客户端连接（从连接池中轮询获取）
 // serviceName: 服务名称
 func (m *GrpcClientManager) GetClient(ctx context.Context, serviceName string) (*grpc.Client, error) {
@@ -182,7 +321,7 @@ func (m *GrpcClientManager) GetClient(ctx context.Context, serviceName string) (
 
 	if exists && pool != nil {
 		client := pool.getClient()
-		if client != nil && client.IsConnected() {
+		if client != nil && (pool.lazy || client.IsConnected()) {
 			return client, nil
 		}
 	}
@@ -191,7 +330,7 @@ func (m *GrpcClientManager) GetClient(ctx context.Context, serviceName string) (
 	}
 
 	// 创建连接池可能触发网络 I/O，避免持有 manager 写锁。
-	newPool, err := m.createClientPool(ctx, serviceName)
+	newPool, err := m.createClientPool(ctx, serviceName, m.globalConfig.Lazy)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create client pool for service %s: %w", serviceName, err)
 	}
@@ -200,7 +339,7 @@ func (m *GrpcClientManager) GetClient(ctx context.Context, serviceName string) (
 	defer m.mu.Unlock()
 	if pool, exists := m.clientPools[serviceName]; exists && pool != nil {
 		client := pool.getClient()
-		if client != nil && client.IsConnected() {
+		if client != nil && (pool.lazy || client.IsConnected()) {
 			_ = newPool.close()
 			return client, nil
 		}
@@ -224,6 +363,8 @@ func (m *GrpcClientManager) GetClient(ctx context.Context, serviceName string) (
 
 // GetConn 获取服务连接（便捷方法）
 // serviceName: 服务名称
+//
+// Deprecated: 通过 HTTP 隧道连接时没有底层 gRPC 连接，GetConn 返回错误；请使用 Conn，直连与隧道均可用于生成客户端存根
 func (m *GrpcClientManager) GetConn(ctx context.Context, serviceName string) (*rpc.ClientConn, error) {
 	client, err := m.GetClient(ctx, serviceName)
 	if err != nil {
@@ -232,42 +373,51 @@ func (m *GrpcClientManager) GetConn(ctx context.Context, serviceName string) (*r
 	if client == nil {
 		return nil, fmt.Errorf("grpc client is nil for service %s", serviceName)
 	}
-	return client.GetConn(), nil
-}
-
-// createClient 创建客户端（内部方法）
-func (m *GrpcClientManager) createClient(serviceName string) (*grpc.Client, error) {
-	config := m.globalConfig
-
-	// 解析超时时间
-	var (
-		timeout time.Duration
-		err     error
-	)
-	if config.Timeout != "" {
-		timeout, err = time.ParseDuration(config.Timeout)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse timeout: %w", err)
+	conn := client.GetConn()
+	if conn == nil {
+		if client.UsingHTTPFallback() {
+			return nil, fmt.Errorf("grpc client for service %s is using the http tunnel, use Conn instead of GetConn", serviceName)
 		}
+		return nil, fmt.Errorf("grpc client is not connected for service %s", serviceName)
 	}
+	return conn, nil
+}
 
-	// 解析 KeepAlive 时间
-	var keepAliveTime time.Duration
-	if config.KeepAliveTime != "" {
-		keepAliveTime, err = time.ParseDuration(config.KeepAliveTime)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse keepAliveTime: %w", err)
-		}
+// Conn 获取服务连接，直连不可用且配置了 HTTP 隧道时返回隧道连接
+// 生成的客户端存根（pb.NewXxxClient）接受 grpc.ClientConnInterface，可直接使用
+func (m *GrpcClientManager) Conn(ctx context.Context, serviceName string) (rpc.ClientConnInterface, error) {
+	client, err := m.GetClient(ctx, serviceName)
+	if err != nil {
+		return nil, err
 	}
+	if client == nil {
+		return nil, fmt.Errorf("grpc client is nil for service %s", serviceName)
+	}
+	conn := client.Conn()
+	if conn == nil {
+		return nil, fmt.Errorf("grpc client is not connected for service %s", serviceName)
+	}
+	return conn, nil
+}
 
-	// 解析 KeepAlive 超时时间
-	var keepAliveTimeout time.Duration
-	if config.KeepAliveTimeout != "" {
-		keepAliveTimeout, err = time.ParseDuration(config.KeepAliveTimeout)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse keepAliveTimeout: %w", err)
+// TunnelResolver 返回供 grpcep.BaseHandler.GRPCTunnel 使用的后端连接解析器
+// 仅允许转发到已注册的服务
+func (m *GrpcClientManager) TunnelResolver() grpcep.TunnelResolver {
+	return func(ctx context.Context, target string) (rpc.ClientConnInterface, error) {
+		if target == "" {
+			return nil, errors.New("tunnel target is required")
 		}
+		return m.Conn(ctx, target)
 	}
+}
+
+// createClient 创建客户端（内部方法）
+func (m *GrpcClientManager) createClient(serviceName string) (*grpc.Client, error) {
+	config := m.globalConfig
+
+	timeout := config.Timeout.Std()
+	keepAliveTime := config.KeepAliveTime.Std()
+	keepAliveTimeout := config.KeepAliveTimeout.Std()
 
 	// 确定连接地址
 	// 如果是静态模式，从 StaticAddresses 中获取地址
@@ -284,11 +434,24 @@ func (m *GrpcClientManager) createClient(serviceName string) (*grpc.Client, erro
 		logger.Info(context.Background(), "Using static address for service: service=%s, address=%s", serviceName, address)
 	}
 
+	interceptors, err := m.clientInterceptors()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build client interceptors: %w", err)
+	}
+
 	// 构建客户端配置
 	clientConfig := grpc.ClientConfig{
-		Address:  address, // 使用解析后的地址
-		Timeout:  timeout,
-		Insecure: config.Insecure,
+		Address:      address, // 使用解析后的地址
+		Interceptors: interceptors,
+		Timeout:      timeout,
+		Insecure:     config.Insecure,
+		// 记录对该服务的调用（启用 depmap 组件后生效）
+		Options:      config.dialOptions(serviceName),
+		WaitForReady: config.WaitForReady,
+		Backoff: &grpc.BackoffConfig{
+			BaseDelay: m.reconnectInterval,
+			MaxDelay:  m.reconnectMaxInterval,
+		},
 	}
 
 	// 设置 KeepAlive 配置
@@ -315,6 +478,21 @@ func (m *GrpcClientManager) createClient(serviceName string) (*grpc.Client, erro
 		clientConfig.ServiceDiscovery = m.etcdResolver
 	}
 
+	// 设置灰度路由规则
+	clientConfig.Canary = config.Canary[serviceName].rule()
+
+	// 设置 HTTP 隧道备用通道
+	if config.HTTPFallback != nil && config.HTTPFallback.URL != "" {
+		fallback := &grpc.HTTPFallbackConfig{
+			URL:     config.HTTPFallback.URL,
+			Target:  serviceName,
+			Headers: config.HTTPFallback.Headers,
+		}
+		fallback.Timeout = config.HTTPFallback.Timeout.Std()
+		fallback.ProbeInterval = config.HTTPFallback.ProbeInterval.Std()
+		clientConfig.HTTPFallback = fallback
+	}
+
 	// 创建客户端
 	client, err := grpc.NewClient(clientConfig)
 	if err != nil {
@@ -324,8 +502,17 @@ func (m *GrpcClientManager) createClient(serviceName string) (*grpc.Client, erro
 	return client, nil
 }
 
-// createClientPool 创建连接池（内部方法）
-func (m *GrpcClientManager) createClientPool(ctx context.Context, serviceName string) (*clientPool, error) {
+// dialOptions 返回服务的 DialOption：depmap 调用记录与配置追加的选项
+func (c *GrpcClientConfig) dialOptions(serviceName string) []rpc.DialOption {
+	options := depmap.DialOptions(serviceName)
+	if c.DialOptions != nil {
+		options = append(options, c.DialOptions(serviceName)...)
+	}
+	return options
+}
+
+// createClientPool 创建连接池（内部方法），lazy 为 true 时不等待连接建立
+func (m *GrpcClientManager) createClientPool(ctx context.Context, serviceName string, lazy bool) (*clientPool, error) {
 	poolSize := m.globalConfig.PoolSize
 	if poolSize <= 0 {
 		poolSize = 1
@@ -336,6 +523,9 @@ func (m *GrpcClientManager) createClientPool(ctx context.Context, serviceName st
 		clients:      make([]*grpc.Client, 0, poolSize),
 		unhealthy:    make([]int, 0),
 		reconnecting: make(map[int]bool),
+		lazy:         lazy,
+		failures:     make(map[int]int),
+		nextAttempt:  make(map[int]time.Time),
 	}
 
 	for i := 0; i < poolSize; i++ {
@@ -348,7 +538,11 @@ func (m *GrpcClientManager) createClientPool(ctx context.Context, serviceName st
 			return nil, fmt.Errorf("failed to create client %d: %w", i, err)
 		}
 
-		if err := client.Connect(ctx); err != nil {
+		connect := client.Connect
+		if lazy {
+			connect = client.ConnectLazy
+		}
+		if err := connect(ctx); err != nil {
 			// 关闭已创建的连接
 			client.Close()
 			for _, c := range pool.clients {
@@ -380,6 +574,14 @@ func (p *clientPool) getClient() *grpc.Client {
 			return client
 		}
 	}
+	// 懒连接池在连接未就绪时同样返回客户端，由 gRPC 等待就绪或返回 Unavailable
+	if p.lazy {
+		for offset := 0; offset < len(p.clients); offset++ {
+			if client := p.clients[int((start+uint64(offset))%uint64(len(p.clients)))]; client != nil {
+				return client
+			}
+		}
+	}
 	return nil
 }
 
@@ -407,10 +609,32 @@ func (p *clientPool) close() error {
 	return nil
 }
 
-func (p *clientPool) finishReconnect(idx int) {
+// recordFailure 记录一次重连失败并按指数退避（含 ±20% 抖动）计算下次重连时间，返回等待时长
+func (p *clientPool) recordFailure(idx int, base, max time.Duration) time.Duration {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	if p.failures == nil {
+		p.failures = make(map[int]int)
+		p.nextAttempt = make(map[int]time.Time)
+	}
+	p.failures[idx]++
+	delay := reconnectBackoff(p.failures[idx], base, max)
+	p.nextAttempt[idx] = time.Now().Add(delay)
 	p.finishReconnectLocked(idx)
+	return delay
+}
+
+// reconnectBackoff 第 failures 次失败后的等待时长：base * 2^(failures-1)，不超过 max
+func reconnectBackoff(failures int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < failures && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	jitter := time.Duration(float64(delay) * 0.2 * (rand.Float64()*2 - 1))
+	return delay + jitter
 }
 
 func (p *clientPool) finishReconnectLocked(idx int) {
@@ -438,7 +662,7 @@ func (m *GrpcClientManager) ConnectAll(ctx context.Context) error {
 			continue // 已经连接
 		}
 
-		pool, err := m.createClientPool(ctx, serviceName)
+		pool, err := m.createClientPool(ctx, serviceName, m.globalConfig.Lazy)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("service %s: %w", serviceName, err))
 			continue
@@ -468,6 +692,76 @@ func (m *GrpcClientManager) ConnectAll(ctx context.Context) error {
 	return nil
 }
 
+// WarmUp 预先建立所有已注册服务的连接（在 ctx 超时内等待连接就绪）
+// 连接失败的服务不会导致启动失败，而是降级为懒连接：后台按退避策略重连，调用时由 WaitForReady 决定等待或快速失败
+// 返回连接失败（已降级）的服务及原因
+func (m *GrpcClientManager) WarmUp(ctx context.Context) map[string]error {
+	m.mu.RLock()
+	services := make([]string, 0, len(m.services))
+	for serviceName := range m.services {
+		if _, exists := m.clientPools[serviceName]; !exists {
+			services = append(services, serviceName)
+		}
+	}
+	m.mu.RUnlock()
+
+	type result struct {
+		serviceName string
+		pool        *clientPool
+		err         error
+	}
+	results := make(chan result, len(services))
+	for _, serviceName := range services {
+		go func() {
+			pool, err := m.createClientPool(ctx, serviceName, false)
+			if err == nil {
+				results <- result{serviceName: serviceName, pool: pool}
+				return
+			}
+			// 配置了 HTTP 隧道时直连失败已回退到隧道，仍失败说明隧道也不可用，懒连接无法回退到隧道
+			if m.globalConfig.HTTPFallback != nil && m.globalConfig.HTTPFallback.URL != "" {
+				results <- result{serviceName: serviceName, err: err}
+				return
+			}
+			lazyPool, lazyErr := m.createClientPool(context.Background(), serviceName, true)
+			if lazyErr != nil {
+				err = errors.Join(err, lazyErr)
+			}
+			results <- result{serviceName: serviceName, pool: lazyPool, err: err}
+		}()
+	}
+
+	failed := make(map[string]error)
+	for range services {
+		r := <-results
+		if r.err != nil {
+			failed[r.serviceName] = r.err
+		}
+		if r.pool == nil {
+			logger.Error(ctx, "Failed to warm up gRPC client: service=%s, error=%v", r.serviceName, r.err)
+			continue
+		}
+
+		m.mu.Lock()
+		_, exists := m.clientPools[r.serviceName]
+		_, registered := m.services[r.serviceName]
+		if exists || !registered {
+			m.mu.Unlock()
+			_ = r.pool.close()
+			continue
+		}
+		m.clientPools[r.serviceName] = r.pool
+		m.mu.Unlock()
+
+		if r.err != nil {
+			logger.Warn(ctx, "gRPC dependency unavailable at startup, falling back to lazy connection: service=%s, error=%v", r.serviceName, r.err)
+		} else {
+			logger.Info(ctx, "Warmed up gRPC client pool: service=%s, poolSize=%d", r.serviceName, m.globalConfig.PoolSize)
+		}
+	}
+	return failed
+}
+
 // CloseClient 关闭指定服务的客户端
 func (m *GrpcClientManager) CloseClient(serviceName string) error {
 	m.mu.Lock()
@@ -490,8 +784,9 @@ func (m *GrpcClientManager) CloseClient(serviceName string) error {
 
 // CloseAll 关闭所有客户端
 func (m *GrpcClientManager) CloseAll() error {
-	// 先停止健康检查
+	// 先停止健康检查，并在关闭连接前取消托管的流
 	m.StopHealthCheck()
+	m.cancelStreams()
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -643,6 +938,14 @@ func (m *GrpcClientManager) checkPoolHealth(serviceName string, pool *clientPool
 			continue
 		}
 
+		// 懒连接由 gRPC 按退避策略自动重连，仅在连接已关闭时重建
+		if pool.lazy {
+			if client.Conn() == nil {
+				unhealthyIndices = append(unhealthyIndices, i)
+			}
+			continue
+		}
+
 		// 检查连接状态
 		if !client.IsConnected() {
 			logger.Warn(context.Background(), "Unhealthy connection detected: service=%s, index=%d", serviceName, i)
@@ -653,6 +956,7 @@ func (m *GrpcClientManager) checkPoolHealth(serviceName string, pool *clientPool
 	pool.unhealthy = unhealthyIndices
 
 	reconnectIndices := make([]int, 0, len(unhealthyIndices))
+	now := time.Now()
 	for _, idx := range unhealthyIndices {
 		if pool.reconnecting == nil {
 			pool.reconnecting = make(map[int]bool)
@@ -660,6 +964,10 @@ func (m *GrpcClientManager) checkPoolHealth(serviceName string, pool *clientPool
 		if pool.reconnecting[idx] {
 			continue
 		}
+		// 退避期内不重连
+		if next, ok := pool.nextAttempt[idx]; ok && now.Before(next) {
+			continue
+		}
 		pool.reconnecting[idx] = true
 		reconnectIndices = append(reconnectIndices, idx)
 	}
@@ -697,26 +1005,30 @@ func (m *GrpcClientManager) reconnectUnhealthyClients(serviceName string, pool *
 		// 创建新客户端
 		newClient, err := m.createClient(serviceName)
 		if err != nil {
-			logger.Error(context.Background(), "Failed to create new client for reconnection: service=%s, index=%d, error=%v", serviceName, idx, err)
-			pool.finishReconnect(idx)
-			time.Sleep(m.reconnectInterval)
+			delay := pool.recordFailure(idx, m.reconnectInterval, m.reconnectMaxInterval)
+			logger.Error(context.Background(), "Failed to create new client for reconnection: service=%s, index=%d, retry_in=%v, error=%v", serviceName, idx, delay, err)
 			continue
 		}
 
 		// 连接
+		connect := newClient.Connect
+		if pool.lazy {
+			connect = newClient.ConnectLazy
+		}
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		if err := newClient.Connect(ctx); err != nil {
+		if err := connect(ctx); err != nil {
 			cancel()
-			logger.Error(context.Background(), "Failed to reconnect client: service=%s, index=%d, error=%v", serviceName, idx, err)
 			newClient.Close()
-			pool.finishReconnect(idx)
-			time.Sleep(m.reconnectInterval)
+			delay := pool.recordFailure(idx, m.reconnectInterval, m.reconnectMaxInterval)
+			logger.Error(context.Background(), "Failed to reconnect client: service=%s, index=%d, retry_in=%v, error=%v", serviceName, idx, delay, err)
 			continue
 		}
 		cancel()
 
 		// 替换客户端
 		pool.mu.Lock()
+		delete(pool.failures, idx)
+		delete(pool.nextAttempt, idx)
 		if idx < len(pool.clients) {
 			pool.clients[idx] = newClient
 			// 从不健康列表中移除
@@ -795,37 +1107,13 @@ func NewGrpcClient(serviceName string, config *GrpcClientConfig) (*GrpcClient, e
 		return nil, errors.New("config is nil")
 	}
 	config = cloneGrpcClientConfig(config)
-
-	// 解析超时时间
-	var timeout time.Duration
-	var err error
-	if config.Timeout != "" {
-		timeout, err = time.ParseDuration(config.Timeout)
-		if err != nil {
-			logger.Error(context.Background(), "Failed to parse GrpcClientConfig.Timeout: %v", err)
-			return nil, err
-		}
-	}
-
-	// 解析 KeepAlive 时间
-	var keepAliveTime time.Duration
-	if config.KeepAliveTime != "" {
-		keepAliveTime, err = time.ParseDuration(config.KeepAliveTime)
-		if err != nil {
-			logger.Error(context.Background(), "Failed to parse GrpcClientConfig.KeepAliveTime: %v", err)
-			return nil, err
-		}
+	if err := validateGrpcClientConfig(config); err != nil {
+		return nil, err
 	}
 
-	// 解析 KeepAlive 超时时间
-	var keepAliveTimeout time.Duration
-	if config.KeepAliveTimeout != "" {
-		keepAliveTimeout, err = time.ParseDuration(config.KeepAliveTimeout)
-		if err != nil {
-			logger.Error(context.Background(), "Failed to parse GrpcClientConfig.KeepAliveTimeout: %v", err)
-			return nil, err
-		}
-	}
+	timeout := config.Timeout.Std()
+	keepAliveTime := config.KeepAliveTime.Std()
+	keepAliveTimeout := config.KeepAliveTimeout.Std()
 
 	// 构建客户端配置
 	address := serviceName
@@ -841,10 +1129,21 @@ func NewGrpcClient(serviceName string, config *GrpcClientConfig) (*GrpcClient, e
 		logger.Info(context.Background(), "Using static address for service: service=%s, address=%s", serviceName, address)
 	}
 
+	interceptors, err := grpc.BuildClientInterceptors(nil, config.InterceptorOrder)
+	if err != nil {
+		logger.Error(context.Background(), "Failed to build grpc client interceptors: %v", err)
+		return nil, err
+	}
+
 	clientConfig := grpc.ClientConfig{
-		Address:  address,
-		Timeout:  timeout,
-		Insecure: config.Insecure,
+		Address:      address,
+		Interceptors: interceptors,
+		Timeout:      timeout,
+		Insecure:     config.Insecure,
+		// 记录对该服务的调用（启用 depmap 组件后生效）
+		Options: config.dialOptions(serviceName),
+		// 单客户端模式下由 grpc.Client 维护连接池（管理器模式在服务级别维护连接池）
+		PoolSize: config.PoolSize,
 	}
 
 	// 设置 KeepAlive 配置
@@ -869,11 +1168,7 @@ func NewGrpcClient(serviceName string, config *GrpcClientConfig) (*GrpcClient, e
 	// 如果配置了 etcd，使用 etcd 服务发现
 	var etcdResolver *grpc.EtcdResolver
 	if config.Etcd != nil {
-		dialTimeout, err := parseDurationOrDefault(config.Etcd.DialTimeout, defaultEtcdDialTimeout)
-		if err != nil {
-			logger.Error(context.Background(), "Failed to parse GrpcClientConfig.Etcd.DialTimeout: %v", err)
-			return nil, err
-		}
+		dialTimeout := config.Etcd.DialTimeout.OrDefault(defaultEtcdDialTimeout)
 
 		// 创建 etcd resolver 配置
 		etcdConfig := grpc.EtcdConfig{
@@ -911,6 +1206,9 @@ func NewGrpcClient(serviceName string, config *GrpcClientConfig) (*GrpcClient, e
 		clientConfig.ServiceDiscovery = etcdResolver
 	}
 
+	// 设置灰度路由规则
+	clientConfig.Canary = config.Canary[serviceName].rule()
+
 	// 创建客户端
 	client, err := grpc.NewClient(clientConfig)
 	if err != nil {
@@ -945,6 +1243,27 @@ func cloneGrpcClientConfig(config *GrpcClientConfig) *GrpcClientConfig {
 		etcd.Endpoints = append([]string(nil), config.Etcd.Endpoints...)
 		cloned.Etcd = &etcd
 	}
+	cloned.InterceptorOrder = append([]string(nil), config.InterceptorOrder...)
+	if config.Canary != nil {
+		cloned.Canary = make(map[string]*GrpcCanaryConfig, len(config.Canary))
+		for service, canary := range config.Canary {
+			if canary != nil {
+				canaryConfig := *canary
+				canary = &canaryConfig
+			}
+			cloned.Canary[service] = canary
+		}
+	}
+	if config.HTTPFallback != nil {
+		fallback := *config.HTTPFallback
+		if config.HTTPFallback.Headers != nil {
+			fallback.Headers = make(map[string]string, len(config.HTTPFallback.Headers))
+			for key, value := range config.HTTPFallback.Headers {
+				fallback.Headers[key] = value
+			}
+		}
+		cloned.HTTPFallback = &fallback
+	}
 	return &cloned
 }
 
@@ -965,6 +1284,8 @@ func (c *GrpcClient) Connect(ctx context.Context) error {
 }
 
 // GetConn 获取 gRPC 连接（用于创建服务客户端）
+//
+// Deprecated: 通过 HTTP 隧道连接时返回 nil，请使用 Conn
 func (c *GrpcClient) GetConn() *rpc.ClientConn {
 	if c.client == nil {
 		return nil
@@ -972,6 +1293,14 @@ func (c *GrpcClient) GetConn() *rpc.ClientConn {
 	return c.client.GetConn()
 }
 
+// Conn 获取可用于生成客户端存根的连接（直连或 HTTP 隧道），未连接时返回 nil
+func (c *GrpcClient) Conn() rpc.ClientConnInterface {
+	if c.client == nil {
+		return nil
+	}
+	return c.client.Conn()
+}
+
 // IsConnected 检查是否已连接
 func (c *GrpcClient) IsConnected() bool {
 	if c.client == nil {
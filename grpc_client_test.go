@@ -1,10 +1,20 @@
 package quickgo
 
 import (
+	"context"
+	"fmt"
+	"net"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/team-dandelion/quickgo/grpc"
+	"github.com/team-dandelion/quickgo/rpcclient"
 )
 
+// 生成的类型化客户端直接使用 GrpcClientManager 作为连接来源
+var _ rpcclient.ConnProvider = (*GrpcClientManager)(nil)
+
 func TestNewGrpcClientStaticDiscoveryRequiresAddress(t *testing.T) {
 	_, err := NewGrpcClient("user-service", &GrpcClientConfig{
 		Discovery:       "static",
@@ -34,3 +44,192 @@ func TestGrpcClientManagerStaticDiscoveryRequiresAddress(t *testing.T) {
 		t.Fatalf("expected static address error, got %v", err)
 	}
 }
+
+func TestGrpcClientManagerValidatesCanaryConfig(t *testing.T) {
+	_, err := NewGrpcClientManager(&GrpcClientConfig{
+		Discovery: "static",
+		Canary:    map[string]*GrpcCanaryConfig{"user-service": {Version: "2.0.0", Percent: 10}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "requires etcd") {
+		t.Fatalf("expected canary to require etcd discovery, got %v", err)
+	}
+
+	_, err = NewGrpcClientManager(&GrpcClientConfig{
+		Etcd:   &EtcdConfig{Endpoints: []string{"127.0.0.1:2379"}},
+		Canary: map[string]*GrpcCanaryConfig{"user-service": {Version: "2.0.0", Percent: 150}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "user-service") {
+		t.Fatalf("expected invalid canary percent error, got %v", err)
+	}
+}
+
+func TestGrpcClientConfigRejectsLazyWithHTTPFallback(t *testing.T) {
+	config := &GrpcClientConfig{
+		Discovery:       "static",
+		StaticAddresses: map[string]string{"user-service": "127.0.0.1:9000"},
+		Lazy:            true,
+		HTTPFallback:    &GrpcHTTPFallbackConfig{URL: "http://127.0.0.1:8080/grpc-tunnel"},
+	}
+	if _, err := NewGrpcClientManager(config); err == nil || !strings.Contains(err.Error(), "httpFallback") {
+		t.Fatalf("expected lazy with httpFallback to be rejected, got %v", err)
+	}
+	if _, err := NewGrpcClient("user-service", config); err == nil || !strings.Contains(err.Error(), "httpFallback") {
+		t.Fatalf("expected lazy with httpFallback to be rejected, got %v", err)
+	}
+}
+
+func TestGrpcClientManagerUseInterceptors(t *testing.T) {
+	address := fmt.Sprintf("127.0.0.1:%d", reserveGrpcClientTestPort(t))
+	newManager := func(order ...string) *GrpcClientManager {
+		manager, err := NewGrpcClientManager(&GrpcClientConfig{
+			Discovery:        "static",
+			StaticAddresses:  map[string]string{"user-service": address},
+			Insecure:         true,
+			Lazy:             true,
+			InterceptorOrder: order,
+		})
+		if err != nil {
+			t.Fatalf("NewGrpcClientManager failed: %v", err)
+		}
+		if err := manager.RegisterService("user-service"); err != nil {
+			t.Fatalf("RegisterService failed: %v", err)
+		}
+		t.Cleanup(func() { _ = manager.CloseAll() })
+		return manager
+	}
+
+	manager := newManager("auth", "logging")
+	auth := grpc.ClientInterceptorSpec{Name: "auth", Unary: grpc.ClientAuthInterceptor("token")}
+	if err := manager.Use(auth); err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	if err := manager.Use(auth); err == nil {
+		t.Fatal("expected duplicate interceptor to be rejected")
+	}
+	if _, err := manager.GetClient(t.Context(), "user-service"); err != nil {
+		t.Fatalf("GetClient failed: %v", err)
+	}
+	if err := manager.Use(grpc.ClientInterceptorSpec{Name: "late", Unary: auth.Unary}); err == nil {
+		t.Fatal("expected Use after the first connection to be rejected")
+	}
+
+	// 配置引用了未注册的拦截器
+	manager = newManager("missing")
+	if _, err := manager.GetClient(t.Context(), "user-service"); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Fatalf("expected unknown interceptor error, got %v", err)
+	}
+}
+
+func reserveGrpcClientTestPort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to reserve port: %v", err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestGrpcClientManagerWarmUpDegradesToLazyConnection(t *testing.T) {
+	up := reserveGrpcClientTestPort(t)
+	down := reserveGrpcClientTestPort(t)
+	server, err := grpc.NewServer(grpc.Config{Address: "127.0.0.1", Port: up})
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	if err := server.StartAsync(); err != nil {
+		t.Fatalf("StartAsync failed: %v", err)
+	}
+	defer server.Stop()
+
+	manager, err := NewGrpcClientManager(&GrpcClientConfig{
+		Discovery: "static",
+		StaticAddresses: map[string]string{
+			"up-service":   fmt.Sprintf("127.0.0.1:%d", up),
+			"down-service": fmt.Sprintf("127.0.0.1:%d", down),
+		},
+		Insecure:          true,
+		WaitForReady:      true,
+		ReconnectInterval: Duration(50 * time.Millisecond),
+	})
+	if err != nil {
+		t.Fatalf("NewGrpcClientManager failed: %v", err)
+	}
+	defer manager.CloseAll()
+	for _, name := range []string{"up-service", "down-service"} {
+		if err := manager.RegisterService(name); err != nil {
+			t.Fatalf("RegisterService failed: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	failed := manager.WarmUp(ctx)
+	cancel()
+	if len(failed) != 1 || failed["down-service"] == nil {
+		t.Fatalf("expected only down-service to fail warm up, got %v", failed)
+	}
+	if !manager.IsConnected("up-service") {
+		t.Fatal("expected up-service to be connected after warm up")
+	}
+
+	// 降级为懒连接后 GetClient 不再失败，依赖恢复后调用自动成功
+	client, err := manager.GetClient(context.Background(), "down-service")
+	if err != nil || client == nil {
+		t.Fatalf("expected lazy client for unavailable dependency, got %v", err)
+	}
+	recovered, err := grpc.NewServer(grpc.Config{Address: "127.0.0.1", Port: down})
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	if err := recovered.StartAsync(); err != nil {
+		t.Fatalf("StartAsync failed: %v", err)
+	}
+	defer recovered.Stop()
+
+	callCtx, callCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer callCancel()
+	if err := manager.HealthCheck(callCtx, "down-service", ""); err != nil {
+		t.Fatalf("expected call to wait for the recovered dependency, got %v", err)
+	}
+}
+
+func TestGrpcClientManagerLazyGetClientDoesNotBlock(t *testing.T) {
+	manager, err := NewGrpcClientManager(&GrpcClientConfig{
+		Discovery:       "static",
+		StaticAddresses: map[string]string{"down-service": fmt.Sprintf("127.0.0.1:%d", reserveGrpcClientTestPort(t))},
+		Insecure:        true,
+		Timeout:         Duration(5 * time.Second),
+		Lazy:            true,
+	})
+	if err != nil {
+		t.Fatalf("NewGrpcClientManager failed: %v", err)
+	}
+	defer manager.CloseAll()
+	if err := manager.RegisterService("down-service"); err != nil {
+		t.Fatalf("RegisterService failed: %v", err)
+	}
+
+	start := time.Now()
+	if _, err := manager.GetClient(context.Background(), "down-service"); err != nil {
+		t.Fatalf("lazy GetClient failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("lazy GetClient should not wait for the connection, took %v", elapsed)
+	}
+	// 未启用 WaitForReady 时调用快速失败
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := manager.HealthCheck(ctx, "down-service", ""); err == nil {
+		t.Fatal("expected call to unavailable dependency to fail")
+	}
+}
+
+func TestReconnectBackoffGrowsAndCaps(t *testing.T) {
+	base, max := 100*time.Millisecond, time.Second
+	for failures, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 800 * time.Millisecond, 10: time.Second} {
+		got := reconnectBackoff(failures, base, max)
+		if got < want*8/10 || got > want*12/10 {
+			t.Fatalf("failures=%d: expected ~%v, got %v", failures, want, got)
+		}
+	}
+}
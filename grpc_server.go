@@ -4,14 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"net"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/team-dandelion/quickgo/callinfo"
 	"github.com/team-dandelion/quickgo/grpc"
+	"github.com/team-dandelion/quickgo/http"
+	"github.com/team-dandelion/quickgo/loadshed"
 	"github.com/team-dandelion/quickgo/logger"
 	"github.com/team-dandelion/quickgo/metrics"
 	"github.com/team-dandelion/quickgo/tracing"
+	"github.com/team-dandelion/quickgo/validation"
 
 	rpc "google.golang.org/grpc"
 
@@ -24,6 +33,8 @@ const (
 	defaultGrpcServerKeepAliveTime    = 10 * time.Second
 	defaultGrpcServerKeepAliveTimeout = 3 * time.Second
 	defaultEtcdDialTimeout            = 5 * time.Second
+	// etcdRegistryName etcd 注册中心在注册状态中的名称
+	etcdRegistryName = "etcd"
 )
 
 type GrpcServerConfig struct {
@@ -36,30 +47,70 @@ type GrpcServerConfig struct {
 	// 服务端口 示例：50051
 	Port int `json:"port" yaml:"port" toml:"port"`
 	// 最大连接空闲时间 示例：5s
-	MaxConnectionIdle string `json:"maxConnectionIdle" yaml:"maxConnectionIdle" toml:"maxConnectionIdle"`
+	MaxConnectionIdle Duration `json:"maxConnectionIdle" yaml:"maxConnectionIdle" toml:"maxConnectionIdle"`
 	// 最大连接年龄 示例：5s
-	MaxConnectionAge string `json:"maxConnectionAge" yaml:"maxConnectionAge" toml:"maxConnectionAge"`
+	MaxConnectionAge Duration `json:"maxConnectionAge" yaml:"maxConnectionAge" toml:"maxConnectionAge"`
 	// 最大连接年龄 grace time 示例：5s
-	MaxConnectionAgeGrace string `json:"maxConnectionAgeGrace" yaml:"maxConnectionAgeGrace" toml:"maxConnectionAgeGrace"`
+	MaxConnectionAgeGrace Duration `json:"maxConnectionAgeGrace" yaml:"maxConnectionAgeGrace" toml:"maxConnectionAgeGrace"`
 	// 心跳时间 示例：10s
-	KeepAliveTime string `json:"keepAliveTime" yaml:"keepAliveTime" toml:"keepAliveTime"`
+	KeepAliveTime Duration `json:"keepAliveTime" yaml:"keepAliveTime" toml:"keepAliveTime"`
 	// 心跳超时时间 示例：3s
-	KeepAliveTimeout string `json:"keepAliveTimeout" yaml:"keepAliveTimeout" toml:"keepAliveTimeout"`
+	KeepAliveTimeout Duration `json:"keepAliveTimeout" yaml:"keepAliveTimeout" toml:"keepAliveTimeout"`
 	// Etcd 配置（使用 etcd 服务发现时必需，全局共享）
 	Etcd *EtcdConfig `json:"etcd" yaml:"etcd" toml:"etcd"`
+	// 停止前的摘流时间 示例：5s（默认 0，不等待）
+	// 停止时先从 etcd 注销，等待 DrainDuration 让客户端 resolver 移除该地址，再将健康状态置为 NOT_SERVING 并优雅关闭
+	DrainDuration Duration `json:"drainDuration" yaml:"drainDuration" toml:"drainDuration"`
+	// 服务注册元数据（覆盖默认的 version、weight、region，可设置 zone 等自定义字段），客户端负载均衡器可读取
+	RegisterMetadata map[string]string `json:"registerMetadata" yaml:"registerMetadata" toml:"registerMetadata"`
 	// Metrics 配置（可选）
 	Metrics *metrics.Config `json:"metrics" yaml:"metrics" toml:"metrics"`
+	// AccessLog 访问日志（可选），默认每次调用完成时输出一行，包含方法、状态码、耗时、对端地址、请求/响应大小与截止时间；
+	// split 为 true 时保留调用开始与完成各一行的旧格式
+	AccessLog *grpc.AccessLogConfig `json:"accessLog" yaml:"accessLog" toml:"accessLog"`
+	// CallInfo 调用信息拦截器（可选），配置后注册内置的 callinfo 拦截器：
+	// 对端地址、客户端 IP、User-Agent、截止时间与选定的 metadata 可通过 callinfo.From(ctx) 读取并附加到日志字段
+	CallInfo *callinfo.Config `json:"callInfo" yaml:"callInfo" toml:"callInfo"`
+	// ConcurrencyLimit 并发限制（可选），配置后注册内置的 concurrency 拦截器：
+	// 按全局与方法限制同时处理的请求数，超出时有界排队，队列已满或排队超时返回 ResourceExhausted，避免单个重型接口耗尽服务资源
+	ConcurrencyLimit *grpc.ConcurrencyLimitConfig `json:"concurrencyLimit" yaml:"concurrencyLimit" toml:"concurrencyLimit"`
+	// LoadShed 自适应过载保护（可选），配置后注册内置的 loadshed 拦截器：
+	// CPU、GC 停顿、goroutine 数或 p99 延迟超过阈值时按比例拒绝请求并返回 ResourceExhausted，健康检查不受影响
+	LoadShed *loadshed.Config `json:"loadShed" yaml:"loadShed" toml:"loadShed"`
+	// 调试服务（建议仅在非生产环境开启）
+	// 是否注册 server reflection 服务（grpcurl 等工具无需 proto 文件即可调用）
+	Reflection bool `json:"reflection" yaml:"reflection" toml:"reflection"`
+	// 是否注册 channelz 服务（grpcdebug 等工具查看连接、子通道状态）
+	Channelz bool `json:"channelz" yaml:"channelz" toml:"channelz"`
+	// 是否注册 gRPC admin 服务（包含 channelz 以及引入 xds 时的 CSDS）
+	Admin bool `json:"admin" yaml:"admin" toml:"admin"`
+	// 独立调试监听地址 示例：127.0.0.1:6060，为空不启动
+	// 提供 /debug/pprof/、/debug/runtime（goroutine、内存、GC 停顿）以及配置 Metrics 时的 /metrics，适用于没有 HTTP Server 的纯 gRPC 服务
+	DebugAddress string `json:"debugAddress" yaml:"debugAddress" toml:"debugAddress"`
+	// 自定义拦截器（代码注册），与内置拦截器按优先级分类合并；也可在 Start 之前通过 GrpcServer.Use 注册
+	Interceptors *grpc.InterceptorChain `json:"-" yaml:"-" toml:"-"`
+	// 启用的拦截器及顺序（由外到内），示例：[tracing, logging, recovery, auth]
+	// 可选名称：内置的 tracing、callinfo、logging、recovery、metrics、loadshed、concurrency 以及自定义拦截器；为空时启用全部拦截器并按优先级分类排序
+	InterceptorOrder []string `json:"interceptors" yaml:"interceptors" toml:"interceptors"`
+	// 额外的注册中心（如迁移期间同时注册到 Consul），与 etcd 一起注册、注销；由服务器负责关闭
+	Registries []grpc.NamedRegistry `json:"-" yaml:"-" toml:"-"`
+	// 服务注册事件回调（租约丢失、重新注册等），用于观测注册状态
+	OnRegistrationEvent func(grpc.RegistrationEvent) `json:"-" yaml:"-" toml:"-"`
 
 	metrics *metrics.Metrics
 }
 
 type EtcdConfig struct {
 	Endpoints   []string `json:"endpoints" yaml:"endpoints" toml:"endpoints"`
-	DialTimeout string   `json:"dialTimeout" yaml:"dialTimeout" toml:"dialTimeout"`
+	DialTimeout Duration `json:"dialTimeout" yaml:"dialTimeout" toml:"dialTimeout"`
 	Prefix      string   `json:"prefix" yaml:"prefix" toml:"prefix"`
 	TTL         int64    `json:"ttl" yaml:"ttl" toml:"ttl"`
 	Username    string   `json:"username" yaml:"username" toml:"username"`
 	Password    string   `json:"password" yaml:"password" toml:"password"`
+	// 租约丢失后重新注册的初始退避间隔 示例：1s
+	ReregisterBackoff Duration `json:"reregisterBackoff" yaml:"reregisterBackoff" toml:"reregisterBackoff"`
+	// 重新注册的最大退避间隔 示例：30s
+	ReregisterMaxBackoff Duration `json:"reregisterMaxBackoff" yaml:"reregisterMaxBackoff" toml:"reregisterMaxBackoff"`
 }
 
 type GrpcServer struct {
@@ -67,6 +118,24 @@ type GrpcServer struct {
 	config    *GrpcServerConfig
 	registrar *grpc.ServiceRegistrar
 	metrics   *metrics.Metrics
+	// 过载保护器（配置 LoadShed 时创建）
+	shedder *loadshed.Shedder
+	// 自定义拦截器（配置 + Use 注册），Start 之后不可修改
+	customInterceptors *grpc.InterceptorChain
+	pipeline           atomic.Pointer[grpcServerPipeline]
+	interceptorsMu     sync.Mutex
+	started            bool
+	// 停止前的摘流时间
+	drainDuration time.Duration
+	// 独立调试监听（配置 DebugAddress 时创建）
+	debugServer *http.DebugServer
+}
+
+// grpcServerPipeline 生效的拦截器链及其组合后的拦截器
+type grpcServerPipeline struct {
+	chain  *grpc.InterceptorChain
+	unary  rpc.UnaryServerInterceptor
+	stream rpc.StreamServerInterceptor
 }
 
 type register func(s *rpc.Server)
@@ -82,56 +151,67 @@ func NewGrpcServer(config *GrpcServerConfig) (*GrpcServer, error) {
 	}
 
 	// etcd 配置是可选的；实际 registry 在 Start 阶段创建，避免构造期建立无用连接。
-	if config.Etcd != nil {
-		if _, err := parseDurationOrDefault(config.Etcd.DialTimeout, defaultEtcdDialTimeout); err != nil {
-			logger.Error(context.Background(), "Failed to parse GrpcServerConfig.Etcd.DialTimeout: %v", err)
-			return nil, err
-		}
-	} else {
+	if config.Etcd == nil {
 		logger.Info(context.Background(), "Etcd not configured, running in standalone mode (no service discovery)")
 	}
 
-	keepTime, err := parseDurationOrDefault(config.KeepAliveTime, defaultGrpcServerKeepAliveTime)
-	if err != nil {
-		logger.Error(context.Background(), "Failed to parse GrpcServerConfig.Time: %v", err)
-		return nil, err
-	}
+	drainDuration := config.DrainDuration.Std()
+	keepTime := config.KeepAliveTime.OrDefault(defaultGrpcServerKeepAliveTime)
+	timeout := config.KeepAliveTimeout.OrDefault(defaultGrpcServerKeepAliveTimeout)
 
-	timeout, err := parseDurationOrDefault(config.KeepAliveTimeout, defaultGrpcServerKeepAliveTimeout)
-	if err != nil {
-		logger.Error(context.Background(), "Failed to parse GrpcServerConfig.Timeout: %v", err)
-		return nil, err
-	}
-
-	// 构建拦截器链
-	unaryInterceptors := []rpc.UnaryServerInterceptor{
-		grpc.LoggingInterceptor(),
-		grpc.RecoveryInterceptor(),
-	}
-	streamInterceptors := []rpc.StreamServerInterceptor{
-		grpc.StreamLoggingInterceptor(),
-	}
+	// 构建拦截器链：内置拦截器 + 用户注册的拦截器，按优先级分类确定性组装
 	metricCollector := config.metrics
 	if metricCollector == nil && config.Metrics != nil {
 		metricCollector = metrics.New(*config.Metrics)
 	}
-	if metricCollector != nil {
-		unaryInterceptors = append(unaryInterceptors, metrics.UnaryServerInterceptor(metricCollector))
-		streamInterceptors = append(streamInterceptors, metrics.StreamServerInterceptor(metricCollector))
+	s := &GrpcServer{
+		config:             config,
+		metrics:            metricCollector,
+		customInterceptors: grpc.NewInterceptorChain(),
+		drainDuration:      drainDuration,
 	}
+	if config.LoadShed != nil {
+		shedder, err := loadshed.New("grpc", *config.LoadShed)
+		if err != nil {
+			return nil, err
+		}
+		if metricCollector != nil {
+			namespace := metrics.DefaultConfig().Namespace
+			if config.Metrics != nil && config.Metrics.Namespace != "" {
+				namespace = config.Metrics.Namespace
+			}
+			if err := shedder.RegisterMetrics(metricCollector.Registry(), namespace); err != nil {
+				return nil, err
+			}
+		}
+		s.shedder = shedder
+	}
+	if err := s.customInterceptors.Merge(config.Interceptors); err != nil {
+		logger.Error(context.Background(), "Failed to build grpc interceptor chain: %v", err)
+		return nil, err
+	}
+	// 配置中引用、但尚未注册的拦截器可在 Start 之前通过 Use 注册
+	if err := s.rebuildInterceptors(false); err != nil {
+		logger.Error(context.Background(), "Failed to build grpc interceptor chain: %v", err)
+		return nil, err
+	}
+	logger.Info(context.Background(), "gRPC server interceptor chain: %s", s.pipeline.Load().chain.String())
 
-	// 如果启用了 OpenTelemetry tracing，添加 tracing 拦截器
-	if tracing.IsEnabled() {
-		unaryInterceptors = append([]rpc.UnaryServerInterceptor{tracing.UnaryServerInterceptor()}, unaryInterceptors...)
-		streamInterceptors = append([]rpc.StreamServerInterceptor{tracing.StreamServerInterceptor()}, streamInterceptors...)
+	if config.Reflection || config.Channelz || config.Admin {
+		logger.Info(context.Background(), "gRPC debug services enabled: reflection=%v, channelz=%v, admin=%v",
+			config.Reflection, config.Channelz, config.Admin)
 	}
 
 	server, err := grpc.NewServer(grpc.Config{
-		Address: config.Address,
-		Port:    config.Port,
+		Address:    config.Address,
+		Port:       config.Port,
+		Reflection: config.Reflection,
+		Channelz:   config.Channelz,
+		Admin:      config.Admin,
 		Options: []rpc.ServerOption{
-			rpc.ChainUnaryInterceptor(unaryInterceptors...),
-			rpc.ChainStreamInterceptor(streamInterceptors...),
+			// 通过分发拦截器调用生效的拦截器链，使 Start 之前的 Use 同样生效
+			rpc.ChainUnaryInterceptor(s.dispatchUnary),
+			rpc.ChainStreamInterceptor(s.dispatchStream),
 			// 添加keepalive配置
 			rpc.KeepaliveParams(keepalive.ServerParameters{
 				Time:    keepTime,
@@ -145,11 +225,20 @@ func NewGrpcServer(config *GrpcServerConfig) (*GrpcServer, error) {
 		return nil, err
 	}
 
-	return &GrpcServer{
-		server:  server,
-		config:  config,
-		metrics: metricCollector,
-	}, nil
+	s.server = server
+
+	if config.DebugAddress != "" {
+		debugConfig := http.DebugServerConfig{Address: config.DebugAddress}
+		if metricCollector != nil {
+			debugConfig.MetricsHandler = metricCollector.Handler()
+		}
+		debugServer, err := http.NewDebugServer(debugConfig)
+		if err != nil {
+			return nil, err
+		}
+		s.debugServer = debugServer
+	}
+	return s, nil
 }
 
 func (s *GrpcServer) RegisterService(register register) error {
@@ -162,6 +251,16 @@ func (s *GrpcServer) Start() error {
 		return errors.New("grpc server is nil")
 	}
 
+	// 固化拦截器链：此后不再接受 Use
+	s.interceptorsMu.Lock()
+	if err := s.rebuildInterceptorsLocked(true); err != nil {
+		s.interceptorsMu.Unlock()
+		return err
+	}
+	s.started = true
+	s.interceptorsMu.Unlock()
+	logger.Info(context.Background(), "gRPC server interceptor chain: %s", s.pipeline.Load().chain.String())
+
 	serverAddress, err := s.registerAddress()
 	if err != nil {
 		return err
@@ -171,49 +270,84 @@ func (s *GrpcServer) Start() error {
 	if err := s.server.StartAsync(); err != nil {
 		return fmt.Errorf("failed to start grpc server: %w", err)
 	}
+	if s.debugServer != nil {
+		if err := s.debugServer.Start(); err != nil {
+			return s.rollbackStartedServer(err)
+		}
+	}
 
-	// 如果没有配置 etcd，跳过服务注册
-	if s.config.Etcd == nil {
+	// 没有配置任何注册中心时，跳过服务注册
+	if s.config.Etcd == nil && len(s.config.Registries) == 0 {
 		logger.Info(context.Background(), "Running in standalone mode, skipping service registration")
 		return nil
 	}
 
-	dialTimeout, err := parseDurationOrDefault(s.config.Etcd.DialTimeout, defaultEtcdDialTimeout)
-	if err != nil {
-		return fmt.Errorf("failed to parse etcd dial timeout: %w", err)
-	}
-
-	etcdConfig := grpc.EtcdConfig{
-		Endpoints:   s.config.Etcd.Endpoints,
-		DialTimeout: dialTimeout,
-		Prefix:      s.config.Etcd.Prefix,
-		TTL:         s.config.Etcd.TTL,
-		Username:    s.config.Etcd.Username,
-		Password:    s.config.Etcd.Password,
-	}
-
-	registry, err := grpc.NewEtcdRegistry(etcdConfig)
-	if err != nil {
-		return s.rollbackStartedServer(fmt.Errorf("failed to create etcd registry: %w", err))
+	registries := make([]grpc.NamedRegistry, 0, len(s.config.Registries)+1)
+	if s.config.Etcd != nil {
+		registry, err := s.newEtcdRegistry()
+		if err != nil {
+			return s.rollbackStartedServer(err)
+		}
+		registries = append(registries, grpc.NamedRegistry{Name: etcdRegistryName, Registry: registry})
 	}
+	registries = append(registries, s.config.Registries...)
 
 	metadata := map[string]string{
 		"version": "1.0.0",
 		"weight":  "10",
 		"region":  "default",
 	}
+	maps.Copy(metadata, s.config.RegisterMetadata)
 
 	// 使用包含端口的完整地址创建新的 registrar
-	s.registrar = grpc.NewServiceRegistrar(registry, s.config.ServiceName, serverAddress, metadata)
+	registrar, err := grpc.NewMultiServiceRegistrar(registries, s.config.ServiceName, serverAddress, metadata)
+	if err != nil {
+		for _, named := range registries {
+			_ = named.Registry.Close()
+		}
+		return s.rollbackStartedServer(fmt.Errorf("failed to create service registrar: %w", err))
+	}
+	s.registrar = registrar
 
 	if err := s.registrar.Register(context.Background()); err != nil {
-		return s.rollbackStartedServer(fmt.Errorf("failed to register service to etcd: %w", err))
+		return s.rollbackStartedServer(err)
 	}
-	logger.Info(context.Background(), "Service registered to etcd: service=%s, address=%s", s.config.ServiceName, serverAddress)
+	logger.Info(context.Background(), "Service registered: service=%s, address=%s, registries=%d", s.config.ServiceName, serverAddress, len(registries))
 
 	return nil
 }
 
+// newEtcdRegistry 根据配置创建 etcd 注册中心
+func (s *GrpcServer) newEtcdRegistry() (*grpc.EtcdRegistry, error) {
+	etcdConfig := grpc.EtcdConfig{
+		Endpoints:            s.config.Etcd.Endpoints,
+		DialTimeout:          s.config.Etcd.DialTimeout.OrDefault(defaultEtcdDialTimeout),
+		Prefix:               s.config.Etcd.Prefix,
+		TTL:                  s.config.Etcd.TTL,
+		Username:             s.config.Etcd.Username,
+		Password:             s.config.Etcd.Password,
+		ReregisterBackoff:    s.config.Etcd.ReregisterBackoff.Std(),
+		ReregisterMaxBackoff: s.config.Etcd.ReregisterMaxBackoff.Std(),
+	}
+
+	registry, err := grpc.NewEtcdRegistry(etcdConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create etcd registry: %w", err)
+	}
+	if s.config.OnRegistrationEvent != nil {
+		registry.OnEvent(s.config.OnRegistrationEvent)
+	}
+	return registry, nil
+}
+
+// RegistryStatus 返回各注册中心的注册状态（未注册到任何注册中心时返回 nil）
+func (s *GrpcServer) RegistryStatus() []grpc.RegistryStatus {
+	if s == nil || s.registrar == nil {
+		return nil
+	}
+	return s.registrar.Status()
+}
+
 func (s *GrpcServer) rollbackStartedServer(startErr error) error {
 	if s.registrar != nil {
 		if err := s.registrar.Close(); err != nil {
@@ -222,6 +356,9 @@ func (s *GrpcServer) rollbackStartedServer(startErr error) error {
 		}
 		s.registrar = nil
 	}
+	if err := s.stopDebugServer(); err != nil {
+		startErr = errors.Join(startErr, fmt.Errorf("stop debug server during rollback: %w", err))
+	}
 	if s.server != nil {
 		if err := s.server.Stop(); err != nil {
 			logger.Error(context.Background(), "Failed to stop grpc server during start rollback: %v", err)
@@ -231,6 +368,7 @@ func (s *GrpcServer) rollbackStartedServer(startErr error) error {
 	return startErr
 }
 
+// Stop 停止 gRPC 服务器：注销服务 -> 等待 DrainDuration -> 健康状态置为 NOT_SERVING -> 优雅关闭
 func (s *GrpcServer) Stop() error {
 	if s == nil || s.server == nil {
 		return nil
@@ -249,13 +387,45 @@ func (s *GrpcServer) Stop() error {
 		s.registrar = nil
 	}
 
+	// 摘流：注销后继续处理请求，等待客户端 resolver 移除该地址
+	if s.drainDuration > 0 && s.server.IsRunning() {
+		logger.Info(context.Background(), "Draining grpc server before stop: duration=%s", s.drainDuration)
+		time.Sleep(s.drainDuration)
+	}
+
+	// 将健康状态置为 NOT_SERVING 后优雅关闭
 	if err := s.server.Stop(); err != nil {
 		logger.Error(context.Background(), "Failed to stop server: %v", err)
 		return err
 	}
+	return s.stopDebugServer()
+}
+
+// stopDebugServer 关闭独立调试监听
+func (s *GrpcServer) stopDebugServer() error {
+	if s.debugServer == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.debugServer.Stop(ctx); err != nil {
+		logger.Error(context.Background(), "Failed to stop debug server: %v", err)
+		return err
+	}
 	return nil
 }
 
+// DebugAddr 返回独立调试监听的实际地址（未配置或未启动时返回空字符串）
+func (s *GrpcServer) DebugAddr() string {
+	if s == nil || s.debugServer == nil {
+		return ""
+	}
+	if addr := s.debugServer.Addr(); addr != nil {
+		return addr.String()
+	}
+	return ""
+}
+
 // Metrics 获取 gRPC 服务器使用的指标收集器。
 func (s *GrpcServer) Metrics() *metrics.Metrics {
 	if s == nil {
@@ -264,6 +434,83 @@ func (s *GrpcServer) Metrics() *metrics.Metrics {
 	return s.metrics
 }
 
+// Interceptors 返回生效的拦截器链（按执行顺序，由外到内）
+func (s *GrpcServer) Interceptors() []grpc.InterceptorInfo {
+	if s == nil {
+		return nil
+	}
+	pipeline := s.pipeline.Load()
+	if pipeline == nil {
+		return nil
+	}
+	return pipeline.chain.Describe()
+}
+
+// Use 注册自定义拦截器，必须在 Start 之前调用
+// 配置了 InterceptorOrder 时，只有列出的拦截器会生效
+func (s *GrpcServer) Use(specs ...grpc.InterceptorSpec) error {
+	if s == nil || s.server == nil {
+		return errors.New("grpc server is nil")
+	}
+	s.interceptorsMu.Lock()
+	defer s.interceptorsMu.Unlock()
+	if s.started {
+		return errors.New("grpc server interceptors must be registered before Start")
+	}
+
+	for i, spec := range specs {
+		if err := s.customInterceptors.Register(spec); err != nil {
+			for _, registered := range specs[:i] {
+				s.customInterceptors.Remove(registered.Name)
+			}
+			return err
+		}
+	}
+	if err := s.rebuildInterceptorsLocked(false); err != nil {
+		for _, spec := range specs {
+			s.customInterceptors.Remove(spec.Name)
+		}
+		return err
+	}
+	return nil
+}
+
+// rebuildInterceptors 重新组装生效的拦截器链，strict 为 true 时 InterceptorOrder 中的名称必须全部存在
+func (s *GrpcServer) rebuildInterceptors(strict bool) error {
+	s.interceptorsMu.Lock()
+	defer s.interceptorsMu.Unlock()
+	return s.rebuildInterceptorsLocked(strict)
+}
+
+func (s *GrpcServer) rebuildInterceptorsLocked(strict bool) error {
+	chain, err := buildGrpcServerInterceptors(s.customInterceptors, s.metrics, s.config.AccessLog, s.config.CallInfo, s.config.ConcurrencyLimit, s.shedder)
+	if err != nil {
+		return err
+	}
+	if order := s.config.InterceptorOrder; len(order) > 0 {
+		if missing := chain.Missing(order); strict && len(missing) > 0 {
+			return fmt.Errorf("unknown grpc server interceptors: %s", strings.Join(missing, ", "))
+		}
+		if chain, err = chain.Select(order); err != nil {
+			return err
+		}
+	}
+	s.pipeline.Store(&grpcServerPipeline{
+		chain:  chain,
+		unary:  chain.UnaryInterceptor(),
+		stream: chain.StreamInterceptor(),
+	})
+	return nil
+}
+
+func (s *GrpcServer) dispatchUnary(ctx context.Context, req any, info *rpc.UnaryServerInfo, handler rpc.UnaryHandler) (any, error) {
+	return s.pipeline.Load().unary(ctx, req, info, handler)
+}
+
+func (s *GrpcServer) dispatchStream(srv any, ss rpc.ServerStream, info *rpc.StreamServerInfo, handler rpc.StreamHandler) error {
+	return s.pipeline.Load().stream(srv, ss, info, handler)
+}
+
 func (s *GrpcServer) registerAddress() (string, error) {
 	if s.config.RegisterAddress != "" {
 		return s.config.RegisterAddress, nil
@@ -275,8 +522,8 @@ func (s *GrpcServer) registerAddress() (string, error) {
 	if serverIP == "0.0.0.0" {
 		serverIP = "127.0.0.1"
 	}
-	if s.config.Etcd != nil && serverIP == "127.0.0.1" {
-		logger.Warn(context.Background(), "Grpc server is registering loopback address to etcd; set registerAddress or SERVER_IP when other services need to connect: address=%s, port=%d", s.config.Address, s.config.Port)
+	if (s.config.Etcd != nil || len(s.config.Registries) > 0) && serverIP == "127.0.0.1" {
+		logger.Warn(context.Background(), "Grpc server is registering loopback address to service registry; set registerAddress or SERVER_IP when other services need to connect: address=%s, port=%d", s.config.Address, s.config.Port)
 	}
 	return fmt.Sprintf("%s:%d", serverIP, s.config.Port), nil
 }
@@ -294,6 +541,50 @@ func (s *GrpcServer) getLocalIP() string {
 	return localAddr.IP.String()
 }
 
+// buildGrpcServerInterceptors 组装内置拦截器与用户拦截器
+func buildGrpcServerInterceptors(custom *grpc.InterceptorChain, metricCollector *metrics.Metrics, accessLog *grpc.AccessLogConfig, callInfo *callinfo.Config, concurrencyLimit *grpc.ConcurrencyLimitConfig, shedder *loadshed.Shedder) (*grpc.InterceptorChain, error) {
+	chain := grpc.NewInterceptorChain()
+	var accessLogConfig grpc.AccessLogConfig
+	if accessLog != nil {
+		accessLogConfig = *accessLog
+	}
+	builtin := []grpc.InterceptorSpec{
+		{Name: "logging", Class: grpc.ClassObservability, Order: 10, Unary: grpc.LoggingInterceptorWithConfig(accessLogConfig), Stream: grpc.StreamLoggingInterceptorWithConfig(accessLogConfig)},
+		{Name: "recovery", Class: grpc.ClassObservability, Order: 20, Unary: grpc.RecoveryInterceptor()},
+	}
+	// 如果启用了 OpenTelemetry tracing，tracing 位于最外层
+	if tracing.IsEnabled() {
+		builtin = append(builtin, grpc.InterceptorSpec{Name: "tracing", Class: grpc.ClassObservability, Order: 0, Unary: tracing.UnaryServerInterceptor(), Stream: tracing.StreamServerInterceptor()})
+	}
+	// 调用信息位于日志拦截器之前，请求日志即携带调用方字段
+	if callInfo != nil {
+		builtin = append(builtin, grpc.InterceptorSpec{Name: "callinfo", Class: grpc.ClassObservability, Order: 5, Unary: callinfo.UnaryServerInterceptor(*callInfo), Stream: callinfo.StreamServerInterceptor(*callInfo)})
+	}
+	// 过载保护与并发限制位于流量治理分类，被拒绝的请求仍有日志与指标；先按过载比例拒绝，再占用并发名额
+	if shedder != nil {
+		builtin = append(builtin, grpc.InterceptorSpec{Name: "loadshed", Class: grpc.ClassTraffic, Order: 0, Unary: loadshed.UnaryServerInterceptor(shedder), Stream: loadshed.StreamServerInterceptor(shedder)})
+	}
+	if concurrencyLimit != nil {
+		limiter, err := grpc.NewConcurrencyLimiter(*concurrencyLimit)
+		if err != nil {
+			return nil, err
+		}
+		builtin = append(builtin, grpc.InterceptorSpec{Name: "concurrency", Class: grpc.ClassTraffic, Order: 10, Unary: limiter.UnaryInterceptor(), Stream: limiter.StreamInterceptor()})
+	}
+	if metricCollector != nil {
+		builtin = append(builtin, grpc.InterceptorSpec{Name: "metrics", Class: grpc.ClassObservability, Order: 30, Unary: metrics.UnaryServerInterceptor(metricCollector), Stream: metrics.StreamServerInterceptor(metricCollector)})
+	}
+	for _, spec := range builtin {
+		if err := chain.Register(spec); err != nil {
+			return nil, err
+		}
+	}
+	if err := chain.Merge(custom); err != nil {
+		return nil, err
+	}
+	return chain, nil
+}
+
 func applyGrpcServerDefaults(config *GrpcServerConfig) {
 	if config.Address == "" {
 		config.Address = defaultGrpcServerAddress
@@ -313,6 +604,8 @@ func cloneGrpcServerConfig(config *GrpcServerConfig) *GrpcServerConfig {
 		etcd.Endpoints = append([]string(nil), config.Etcd.Endpoints...)
 		cloned.Etcd = &etcd
 	}
+	cloned.RegisterMetadata = maps.Clone(config.RegisterMetadata)
+	cloned.Registries = append([]grpc.NamedRegistry(nil), config.Registries...)
 	if config.Metrics != nil {
 		metricsConfig := *config.Metrics
 		if config.Metrics.Buckets != nil {
@@ -320,24 +613,73 @@ func cloneGrpcServerConfig(config *GrpcServerConfig) *GrpcServerConfig {
 		}
 		cloned.Metrics = &metricsConfig
 	}
+	if config.AccessLog != nil {
+		accessLog := *config.AccessLog
+		cloned.AccessLog = &accessLog
+	}
+	if config.CallInfo != nil {
+		callInfo := *config.CallInfo
+		callInfo.Metadata = append([]string(nil), config.CallInfo.Metadata...)
+		callInfo.ClientIPKeys = append([]string(nil), config.CallInfo.ClientIPKeys...)
+		cloned.CallInfo = &callInfo
+	}
+	if config.ConcurrencyLimit != nil {
+		concurrencyLimit := *config.ConcurrencyLimit
+		concurrencyLimit.Methods = maps.Clone(config.ConcurrencyLimit.Methods)
+		cloned.ConcurrencyLimit = &concurrencyLimit
+	}
+	if config.LoadShed != nil {
+		loadShed := *config.LoadShed
+		loadShed.Exclude = append([]string(nil), config.LoadShed.Exclude...)
+		cloned.LoadShed = &loadShed
+	}
 	return &cloned
 }
 
 func validateGrpcServerConfig(config *GrpcServerConfig) error {
-	if config.Port < 0 || config.Port > 65535 {
-		return fmt.Errorf("invalid grpc server port: %d", config.Port)
+	return config.Validate()
+}
+
+// Validate 校验字段之间的约束（端口范围、服务注册所需字段），返回全部问题
+func (c *GrpcServerConfig) Validate() error {
+	var errs validation.ValidationErrors
+	add := func(field string, value interface{}, message string) {
+		errs = append(errs, &validation.ValidationError{Field: field, Tag: "grpcServer", Value: value, Message: message})
 	}
-	if config.Etcd == nil {
-		return nil
+	if c.Port < 0 || c.Port > 65535 {
+		add("port", c.Port, fmt.Sprintf("invalid grpc server port: %d", c.Port))
 	}
-	if config.ServiceName == "" {
-		return errors.New("grpc server serviceName is required when etcd is configured")
+	if weight, ok := c.RegisterMetadata[grpc.MetadataWeight]; ok {
+		if n, err := strconv.Atoi(weight); err != nil || n <= 0 {
+			add("registerMetadata."+grpc.MetadataWeight, weight, fmt.Sprintf("invalid grpc server register metadata weight: %q", weight))
+		}
+	}
+	if c.ServiceName == "" && len(c.Registries) > 0 {
+		add("serviceName", c.ServiceName, "grpc server serviceName is required when registries are configured")
 	}
-	if len(config.Etcd.Endpoints) == 0 {
-		return errors.New("grpc server etcd endpoints are required")
+	if c.Etcd != nil {
+		if c.ServiceName == "" && len(c.Registries) == 0 {
+			add("serviceName", c.ServiceName, "grpc server serviceName is required when etcd is configured")
+		}
+		if len(c.Etcd.Endpoints) == 0 {
+			add("etcd.endpoints", c.Etcd.Endpoints, "grpc server etcd endpoints are required")
+		}
+		if c.Etcd.TTL < 0 {
+			add("etcd.ttl", c.Etcd.TTL, fmt.Sprintf("grpc server etcd ttl must be non-negative: %d", c.Etcd.TTL))
+		}
+	}
+	if c.LoadShed != nil {
+		if err := c.LoadShed.Validate(); err != nil {
+			add("loadShed", c.LoadShed, fmt.Sprintf("invalid grpc server load shedding: %v", err))
+		}
+	}
+	if c.ConcurrencyLimit != nil {
+		if err := c.ConcurrencyLimit.Validate(); err != nil {
+			add("concurrencyLimit", c.ConcurrencyLimit, fmt.Sprintf("invalid grpc server concurrency limit: %v", err))
+		}
 	}
-	if config.Etcd.TTL < 0 {
-		return fmt.Errorf("grpc server etcd ttl must be non-negative: %d", config.Etcd.TTL)
+	if len(errs) > 0 {
+		return errs
 	}
 	return nil
 }
@@ -1,10 +1,23 @@
 package quickgo
 
 import (
+	"context"
+	"fmt"
+	"io"
+	nethttp "net/http"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/team-dandelion/quickgo/callinfo"
+	"github.com/team-dandelion/quickgo/grpc"
+	"github.com/team-dandelion/quickgo/json"
+	"github.com/team-dandelion/quickgo/loadshed"
 	"github.com/team-dandelion/quickgo/metrics"
+
+	rpc "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func TestNewGrpcServerAppliesDefaultsWithoutMutatingInput(t *testing.T) {
@@ -47,6 +60,13 @@ func TestNewGrpcServerValidatesEtcdConfig(t *testing.T) {
 	if err == nil || !strings.Contains(err.Error(), "endpoints") {
 		t.Fatalf("expected missing endpoints error, got %v", err)
 	}
+
+	_, err = NewGrpcServer(&GrpcServerConfig{
+		RegisterMetadata: map[string]string{"weight": "0"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "weight") {
+		t.Fatalf("expected invalid weight error, got %v", err)
+	}
 }
 
 func TestGrpcServerRegisterAddressPrefersExplicitValue(t *testing.T) {
@@ -106,3 +126,295 @@ func TestGrpcServerClonesMetricsConfig(t *testing.T) {
 		t.Fatal("expected metrics buckets to be cloned")
 	}
 }
+
+func TestGrpcServerMergesCustomInterceptors(t *testing.T) {
+	custom := grpc.NewInterceptorChain()
+	if err := custom.RegisterUnary("auth", grpc.ClassAuth, 0, grpc.AuthInterceptor("token")); err != nil {
+		t.Fatalf("RegisterUnary failed: %v", err)
+	}
+
+	server, err := NewGrpcServer(&GrpcServerConfig{Interceptors: custom})
+	if err != nil {
+		t.Fatalf("NewGrpcServer failed: %v", err)
+	}
+
+	var names []string
+	for _, info := range server.Interceptors() {
+		names = append(names, info.Name)
+	}
+	if got := strings.Join(names, ","); got != "logging,recovery,auth" {
+		t.Fatalf("unexpected interceptor chain: %s", got)
+	}
+
+	withCallInfo, err := NewGrpcServer(&GrpcServerConfig{Interceptors: custom, CallInfo: &callinfo.Config{}})
+	if err != nil {
+		t.Fatalf("NewGrpcServer with call info failed: %v", err)
+	}
+	names = names[:0]
+	for _, info := range withCallInfo.Interceptors() {
+		names = append(names, info.Name)
+	}
+	if got := strings.Join(names, ","); got != "callinfo,logging,recovery,auth" {
+		t.Fatalf("unexpected interceptor chain with call info: %s", got)
+	}
+
+	withLimit, err := NewGrpcServer(&GrpcServerConfig{Interceptors: custom, ConcurrencyLimit: &grpc.ConcurrencyLimitConfig{MaxInFlight: 10}})
+	if err != nil {
+		t.Fatalf("NewGrpcServer with concurrency limit failed: %v", err)
+	}
+	names = names[:0]
+	for _, info := range withLimit.Interceptors() {
+		names = append(names, info.Name)
+	}
+	if got := strings.Join(names, ","); got != "logging,recovery,auth,concurrency" {
+		t.Fatalf("unexpected interceptor chain with concurrency limit: %s", got)
+	}
+	withShedding, err := NewGrpcServer(&GrpcServerConfig{
+		Interceptors:     custom,
+		ConcurrencyLimit: &grpc.ConcurrencyLimitConfig{MaxInFlight: 10},
+		LoadShed:         &loadshed.Config{GoroutineThreshold: 10000},
+	})
+	if err != nil {
+		t.Fatalf("NewGrpcServer with load shedding failed: %v", err)
+	}
+	names = names[:0]
+	for _, info := range withShedding.Interceptors() {
+		names = append(names, info.Name)
+	}
+	if got := strings.Join(names, ","); got != "logging,recovery,auth,loadshed,concurrency" {
+		t.Fatalf("unexpected interceptor chain with load shedding: %s", got)
+	}
+	if _, err := NewGrpcServer(&GrpcServerConfig{LoadShed: &loadshed.Config{}}); err == nil {
+		t.Fatal("expected invalid load shedding error")
+	}
+	if _, err := NewGrpcServer(&GrpcServerConfig{ConcurrencyLimit: &grpc.ConcurrencyLimitConfig{MaxQueue: -1}}); err == nil {
+		t.Fatal("expected invalid concurrency limit error")
+	}
+
+	conflict := grpc.NewInterceptorChain()
+	_ = conflict.RegisterUnary("logging", grpc.ClassBusiness, 0, grpc.AuthInterceptor("token"))
+	if _, err := NewGrpcServer(&GrpcServerConfig{Interceptors: conflict}); err == nil {
+		t.Fatal("expected duplicate builtin interceptor name error")
+	}
+}
+
+func TestGrpcServerRegistersDebugServices(t *testing.T) {
+	services := func(config *GrpcServerConfig) map[string]bool {
+		t.Helper()
+		server, err := NewGrpcServer(config)
+		if err != nil {
+			t.Fatalf("NewGrpcServer failed: %v", err)
+		}
+		t.Cleanup(func() { _ = server.server.Stop() })
+		names := make(map[string]bool)
+		for name := range server.server.GetServer().GetServiceInfo() {
+			names[name] = true
+		}
+		return names
+	}
+
+	if names := services(&GrpcServerConfig{}); names["grpc.reflection.v1.ServerReflection"] || names["grpc.channelz.v1.Channelz"] {
+		t.Fatalf("debug services should be disabled by default, got %v", names)
+	}
+	names := services(&GrpcServerConfig{Reflection: true, Channelz: true})
+	if !names["grpc.reflection.v1.ServerReflection"] || !names["grpc.channelz.v1.Channelz"] {
+		t.Fatalf("expected reflection and channelz services, got %v", names)
+	}
+	// admin 已包含 channelz，同时开启时不应重复注册
+	if names := services(&GrpcServerConfig{Channelz: true, Admin: true}); !names["grpc.channelz.v1.Channelz"] {
+		t.Fatalf("expected channelz service via admin, got %v", names)
+	}
+}
+
+func TestGrpcServerServesStandaloneDebugListener(t *testing.T) {
+	server, err := NewGrpcServer(&GrpcServerConfig{
+		Address:      "127.0.0.1",
+		Port:         reserveGrpcClientTestPort(t),
+		DebugAddress: "127.0.0.1:0",
+		Metrics:      &metrics.Config{Namespace: "grpcdebug"},
+	})
+	if err != nil {
+		t.Fatalf("NewGrpcServer failed: %v", err)
+	}
+	if server.DebugAddr() != "" {
+		t.Fatal("expected no debug address before Start")
+	}
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	addr := server.DebugAddr()
+
+	get := func(path string) string {
+		t.Helper()
+		resp, err := nethttp.Get("http://" + addr + path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != nethttp.StatusOK {
+			t.Fatalf("GET %s returned %d: %s", path, resp.StatusCode, body)
+		}
+		return string(body)
+	}
+	if body := get("/debug/pprof/goroutine?debug=1"); !strings.Contains(body, "goroutine") {
+		t.Fatalf("unexpected goroutine profile: %.200s", body)
+	}
+	if body := get("/debug/runtime"); !strings.Contains(body, `"numGoroutine"`) {
+		t.Fatalf("unexpected runtime stats: %s", body)
+	}
+	if body := get("/metrics"); !strings.Contains(body, "go_goroutines") {
+		t.Fatalf("unexpected metrics: %.200s", body)
+	}
+
+	if err := server.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if _, err := nethttp.Get("http://" + addr + "/debug/runtime"); err == nil {
+		t.Fatal("expected debug listener to be closed after Stop")
+	}
+}
+
+func TestGrpcServerStopDrainsBeforeNotServing(t *testing.T) {
+	port := reserveGrpcClientTestPort(t)
+	server, err := NewGrpcServer(&GrpcServerConfig{
+		Address:       "127.0.0.1",
+		Port:          port,
+		DrainDuration: Duration(300 * time.Millisecond),
+	})
+	if err != nil {
+		t.Fatalf("NewGrpcServer failed: %v", err)
+	}
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	conn, err := rpc.NewClient(fmt.Sprintf("127.0.0.1:%d", port), rpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer conn.Close()
+	healthClient := grpc_health_v1.NewHealthClient(conn)
+
+	start := time.Now()
+	stopped := make(chan error, 1)
+	go func() { stopped <- server.Stop() }()
+
+	time.Sleep(100 * time.Millisecond)
+	resp, err := healthClient.Check(t.Context(), &grpc_health_v1.HealthCheckRequest{})
+	if err != nil || resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Fatalf("expected server to keep serving during drain, got %v, %v", resp, err)
+	}
+
+	if err := <-stopped; err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Fatalf("expected Stop to wait for drain duration, returned after %s", elapsed)
+	}
+
+	var invalid GrpcServerConfig
+	if err := json.Unmarshal([]byte(`{"drainDuration":"soon"}`), &invalid); err == nil {
+		t.Fatal("expected invalid drain duration to be rejected")
+	}
+}
+
+func TestGrpcServerRegistersIntoAdditionalRegistries(t *testing.T) {
+	registry := grpc.NewStaticRegistry()
+	server, err := NewGrpcServer(&GrpcServerConfig{
+		ServiceName:      "svc",
+		Address:          "127.0.0.1",
+		Port:             reserveGrpcClientTestPort(t),
+		RegisterAddress:  "10.0.0.1:9000",
+		RegisterMetadata: map[string]string{"version": "2.0.0"},
+		Registries:       []grpc.NamedRegistry{{Name: "consul", Registry: registry}},
+	})
+	if err != nil {
+		t.Fatalf("NewGrpcServer failed: %v", err)
+	}
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	services := registry.GetServices("svc")
+	if len(services) != 1 || services[0].Address != "10.0.0.1:9000" || services[0].Metadata["version"] != "2.0.0" {
+		t.Fatalf("unexpected registered services: %+v", services)
+	}
+	if status := server.RegistryStatus(); len(status) != 1 || status[0].Name != "consul" || !status[0].Registered {
+		t.Fatalf("unexpected registry status: %+v", status)
+	}
+
+	if err := server.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if services := registry.GetServices("svc"); len(services) != 0 {
+		t.Fatalf("expected deregistration on stop, got %+v", services)
+	}
+
+	if _, err := NewGrpcServer(&GrpcServerConfig{Registries: []grpc.NamedRegistry{{Name: "consul", Registry: registry}}}); err == nil {
+		t.Fatal("expected serviceName to be required with registries")
+	}
+}
+
+func TestGrpcServerUseWithConfiguredInterceptorOrder(t *testing.T) {
+	port := reserveGrpcClientTestPort(t)
+	server, err := NewGrpcServer(&GrpcServerConfig{
+		Address:          "127.0.0.1",
+		Port:             port,
+		InterceptorOrder: []string{"recovery", "audit", "logging"},
+	})
+	if err != nil {
+		t.Fatalf("NewGrpcServer failed: %v", err)
+	}
+
+	var called []string
+	audit := grpc.InterceptorSpec{Name: "audit", Class: grpc.ClassBusiness, Unary: func(ctx context.Context, req any, info *rpc.UnaryServerInfo, handler rpc.UnaryHandler) (any, error) {
+		called = append(called, info.FullMethod)
+		return handler(ctx, req)
+	}}
+	if err := server.Use(audit); err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	names := make([]string, 0)
+	for _, info := range server.Interceptors() {
+		names = append(names, info.Name)
+	}
+	if strings.Join(names, ",") != "recovery,audit,logging" {
+		t.Fatalf("unexpected interceptor chain: %v", names)
+	}
+
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer server.Stop()
+	if err := server.Use(grpc.InterceptorSpec{Name: "late", Unary: audit.Unary}); err == nil {
+		t.Fatal("expected Use after Start to be rejected")
+	}
+
+	conn, err := rpc.NewClient(fmt.Sprintf("127.0.0.1:%d", port), rpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer conn.Close()
+	if _, err := grpc_health_v1.NewHealthClient(conn).Check(t.Context(), &grpc_health_v1.HealthCheckRequest{}); err != nil {
+		t.Fatalf("health check failed: %v", err)
+	}
+	if len(called) != 1 || called[0] != grpc_health_v1.Health_Check_FullMethodName {
+		t.Fatalf("expected custom interceptor to run, got %v", called)
+	}
+}
+
+func TestGrpcServerStartRejectsUnknownInterceptors(t *testing.T) {
+	server, err := NewGrpcServer(&GrpcServerConfig{
+		Address:          "127.0.0.1",
+		Port:             reserveGrpcClientTestPort(t),
+		InterceptorOrder: []string{"logging", "auth"},
+	})
+	if err != nil {
+		t.Fatalf("NewGrpcServer failed: %v", err)
+	}
+	if err := server.Start(); err == nil || !strings.Contains(err.Error(), "auth") {
+		_ = server.Stop()
+		t.Fatalf("expected unknown interceptor error, got %v", err)
+	}
+}
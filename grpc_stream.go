@@ -0,0 +1,206 @@
+package quickgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	rpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// ErrStreamManagerClosed 客户端管理器已关闭（CloseAll），托管的流被取消
+var ErrStreamManagerClosed = errors.New("grpc client manager closed")
+
+// StreamOpener 在服务连接上打开流，通常包装生成的客户端存根：
+//
+//	func(ctx context.Context, conn grpc.ClientConnInterface) (pb.Config_WatchClient, error) {
+//		return pb.NewConfigClient(conn).Watch(ctx, &pb.WatchRequest{Revision: revision})
+//	}
+//
+// 连接上已配置客户端拦截器链（tracing、logging 等），打开的流自动经过这些拦截器
+type StreamOpener[S any] func(ctx context.Context, conn rpc.ClientConnInterface) (S, error)
+
+// ReceiveStream 可接收消息的流（服务端流与双向流的生成客户端均满足）
+type ReceiveStream[T any] interface {
+	Recv() (*T, error)
+}
+
+// StreamWatchOptions 长连接订阅流的重连配置
+type StreamWatchOptions struct {
+	// 首次重连等待时间（默认 ReconnectInterval）
+	InitialBackoff time.Duration
+	// 重连等待时间上限（默认 ReconnectMaxInterval）
+	MaxBackoff time.Duration
+	// 服务端正常结束流（io.EOF）时是否停止订阅（默认重新订阅）
+	StopOnEOF bool
+	// 判断错误是否可通过重连恢复（默认 Unavailable、Aborted、Internal、ResourceExhausted）
+	Retryable func(err error) bool
+	// 每次重连前回调（attempt 从 1 开始）
+	OnReconnect func(attempt int, err error)
+}
+
+// streamContext 返回与管理器关联的 context：CloseAll 时取消，release 在流结束后调用以解除关联
+func (m *GrpcClientManager) streamContext(ctx context.Context) (context.Context, func()) {
+	streamCtx, cancel := context.WithCancelCause(ctx)
+	m.streamsMu.Lock()
+	if m.streams == nil {
+		m.streams = make(map[*context.CancelCauseFunc]struct{})
+	}
+	key := &cancel
+	m.streams[key] = struct{}{}
+	m.streamsMu.Unlock()
+	return streamCtx, func() {
+		m.streamsMu.Lock()
+		delete(m.streams, key)
+		m.streamsMu.Unlock()
+		cancel(context.Canceled)
+	}
+}
+
+// cancelStreams 取消全部托管的流
+func (m *GrpcClientManager) cancelStreams() {
+	m.streamsMu.Lock()
+	streams := m.streams
+	m.streams = nil
+	m.streamsMu.Unlock()
+	for cancel := range streams {
+		(*cancel)(ErrStreamManagerClosed)
+	}
+	if len(streams) > 0 {
+		logger.Info(context.Background(), "Cancelled managed gRPC streams: count=%d", len(streams))
+	}
+}
+
+// NewStream 在服务连接上打开原始流（desc 决定服务端流、客户端流或双向流），流在 CloseAll 时被取消
+// 流结束后需调用返回的 release（可重复调用）
+func (m *GrpcClientManager) NewStream(ctx context.Context, serviceName string, desc *rpc.StreamDesc, method string, opts ...rpc.CallOption) (rpc.ClientStream, func(), error) {
+	return OpenStream(ctx, m, serviceName, func(ctx context.Context, conn rpc.ClientConnInterface) (rpc.ClientStream, error) {
+		return conn.NewStream(ctx, desc, method, opts...)
+	})
+}
+
+// OpenStream 打开托管的流：使用管理器的服务连接（含拦截器链），CloseAll 时流被取消
+// 流结束后需调用返回的 release（可重复调用），以释放 context 并解除与管理器的关联
+func OpenStream[S any](ctx context.Context, m *GrpcClientManager, serviceName string, open StreamOpener[S]) (S, func(), error) {
+	var zero S
+	conn, err := m.Conn(ctx, serviceName)
+	if err != nil {
+		return zero, nil, err
+	}
+	streamCtx, release := m.streamContext(ctx)
+	stream, err := open(streamCtx, conn)
+	if err != nil {
+		release()
+		return zero, nil, err
+	}
+	return stream, release, nil
+}
+
+// WatchStream 维持长连接订阅流（如配置、服务列表的 watch），阻塞直到 ctx 取消、管理器关闭、
+// 遇到不可恢复的错误或 handle 返回错误：连接中断等可恢复错误会按退避重新调用 open 重新订阅
+// （open 可使用已处理的最新版本号实现断点续订），成功收到消息后退避时间重置
+func WatchStream[T any](ctx context.Context, m *GrpcClientManager, serviceName string, open StreamOpener[ReceiveStream[T]], handle func(*T) error, opts *StreamWatchOptions) error {
+	var options StreamWatchOptions
+	if opts != nil {
+		options = *opts
+	}
+	if options.InitialBackoff <= 0 {
+		options.InitialBackoff = m.reconnectInterval
+	}
+	if options.MaxBackoff < options.InitialBackoff {
+		options.MaxBackoff = max(m.reconnectMaxInterval, options.InitialBackoff)
+	}
+	if options.Retryable == nil {
+		options.Retryable = retryableStreamError
+	}
+
+	// 整个订阅期间与管理器关联，CloseAll 时在退避等待中也能及时退出
+	watchCtx, release := m.streamContext(ctx)
+	defer release()
+	stopped := func() error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return context.Cause(watchCtx)
+	}
+
+	backoff := options.InitialBackoff
+	for attempt := 0; ; {
+		received, err := watchOnce(watchCtx, m, serviceName, open, handle)
+		if watchCtx.Err() != nil {
+			return stopped()
+		}
+		var handlerErr *streamHandlerError
+		if errors.As(err, &handlerErr) {
+			return handlerErr.err
+		}
+		if errors.Is(err, io.EOF) {
+			if options.StopOnEOF {
+				return nil
+			}
+		} else if !options.Retryable(err) {
+			return err
+		}
+
+		if received {
+			attempt, backoff = 0, options.InitialBackoff
+		}
+		attempt++
+		logger.Warn(ctx, "gRPC watch stream interrupted, resubscribing: service=%s, attempt=%d, backoff=%s, error=%v", serviceName, attempt, backoff, err)
+		if options.OnReconnect != nil {
+			options.OnReconnect(attempt, err)
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-watchCtx.Done():
+			timer.Stop()
+			return stopped()
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, options.MaxBackoff)
+	}
+}
+
+// streamHandlerError handle 返回的错误，不触发重连
+type streamHandlerError struct {
+	err error
+}
+
+func (e *streamHandlerError) Error() string {
+	return fmt.Sprintf("stream handler: %v", e.err)
+}
+
+// watchOnce 打开一次流并持续接收，返回是否收到过消息与结束原因
+func watchOnce[T any](ctx context.Context, m *GrpcClientManager, serviceName string, open StreamOpener[ReceiveStream[T]], handle func(*T) error) (bool, error) {
+	stream, release, err := OpenStream(ctx, m, serviceName, open)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+	received := false
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return received, err
+		}
+		received = true
+		if err := handle(msg); err != nil {
+			return received, &streamHandlerError{err: err}
+		}
+	}
+}
+
+// retryableStreamError 默认的可恢复错误：连接中断、服务端重启或过载
+func retryableStreamError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Aborted, codes.Internal, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package quickgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	rpc "google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/team-dandelion/quickgo/grpc"
+)
+
+func startGrpcStreamTestServer(t *testing.T, port int) *grpc.Server {
+	t.Helper()
+	server, err := grpc.NewServer(grpc.Config{Address: "127.0.0.1", Port: port})
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	if err := server.StartAsync(); err != nil {
+		t.Fatalf("StartAsync failed: %v", err)
+	}
+	return server
+}
+
+func TestWatchStreamResubscribesAndStopsOnCloseAll(t *testing.T) {
+	port := reserveGrpcClientTestPort(t)
+	server := startGrpcStreamTestServer(t, port)
+
+	manager, err := NewGrpcClientManager(&GrpcClientConfig{
+		Discovery:            "static",
+		StaticAddresses:      map[string]string{"health-service": fmt.Sprintf("127.0.0.1:%d", port)},
+		Insecure:             true,
+		ReconnectInterval:    Duration(20 * time.Millisecond),
+		ReconnectMaxInterval: Duration(100 * time.Millisecond),
+	})
+	if err != nil {
+		t.Fatalf("NewGrpcClientManager failed: %v", err)
+	}
+	if err := manager.RegisterService("health-service"); err != nil {
+		t.Fatalf("RegisterService failed: %v", err)
+	}
+
+	updates := make(chan healthpb.HealthCheckResponse_ServingStatus, 16)
+	var reconnects atomic.Int32
+	done := make(chan error, 1)
+	go func() {
+		done <- WatchStream(context.Background(), manager, "health-service",
+			func(ctx context.Context, conn rpc.ClientConnInterface) (ReceiveStream[healthpb.HealthCheckResponse], error) {
+				return healthpb.NewHealthClient(conn).Watch(ctx, &healthpb.HealthCheckRequest{})
+			},
+			func(resp *healthpb.HealthCheckResponse) error {
+				updates <- resp.GetStatus()
+				return nil
+			},
+			&StreamWatchOptions{OnReconnect: func(int, error) { reconnects.Add(1) }},
+		)
+	}()
+
+	expectServing := func() {
+		t.Helper()
+		select {
+		case status := <-updates:
+			if status != healthpb.HealthCheckResponse_SERVING {
+				t.Fatalf("expected SERVING, got %v", status)
+			}
+		case err := <-done:
+			t.Fatalf("watch stopped unexpectedly: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for health update")
+		}
+	}
+	expectServing()
+
+	// 服务端中断后重启，订阅自动恢复
+	server.GetServer().Stop()
+	restarted := startGrpcStreamTestServer(t, port)
+	defer restarted.Stop()
+	expectServing()
+	if reconnects.Load() == 0 {
+		t.Fatal("expected OnReconnect to be called")
+	}
+
+	manager.CloseAll()
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrStreamManagerClosed) {
+			t.Fatalf("expected ErrStreamManagerClosed, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch did not stop after CloseAll")
+	}
+}
+
+func TestWatchStreamReturnsHandlerError(t *testing.T) {
+	port := reserveGrpcClientTestPort(t)
+	server := startGrpcStreamTestServer(t, port)
+	defer server.Stop()
+
+	manager, err := NewGrpcClientManager(&GrpcClientConfig{
+		Discovery:       "static",
+		StaticAddresses: map[string]string{"health-service": fmt.Sprintf("127.0.0.1:%d", port)},
+		Insecure:        true,
+	})
+	if err != nil {
+		t.Fatalf("NewGrpcClientManager failed: %v", err)
+	}
+	defer manager.CloseAll()
+	if err := manager.RegisterService("health-service"); err != nil {
+		t.Fatalf("RegisterService failed: %v", err)
+	}
+
+	stop := errors.New("stop watching")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = WatchStream(ctx, manager, "health-service",
+		func(ctx context.Context, conn rpc.ClientConnInterface) (ReceiveStream[healthpb.HealthCheckResponse], error) {
+			return healthpb.NewHealthClient(conn).Watch(ctx, &healthpb.HealthCheckRequest{})
+		},
+		func(*healthpb.HealthCheckResponse) error { return stop },
+		nil,
+	)
+	if !errors.Is(err, stop) {
+		t.Fatalf("expected handler error, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package grpcep
+
+import (
+	jsoniter "github.com/json-iterator/go"
+)
+
+// rawField 响应 JSON 的顶层字段（值为原始字节）
+type rawField struct {
+	key   string
+	value []byte
+}
+
+// decorateJSON 将 rpc 响应 JSON 流式转换为统一响应 JSON（按 env 的字段名），结果与 decorateResponse 序列化后一致
+// 只遍历一次顶层字段，data 中的字段值按原始字节拷贝，不经过 map 反序列化与二次序列化（大整数也不会损失精度）
+// 响应不是合法的 JSON 对象时返回 ok = false，由调用方回退到 decorateResponse
+func decorateJSON(env *envelope, byteData []byte, traceID string) (result string, code int32, ok bool) {
+	iter := jsoniter.ConfigDefault.BorrowIterator(byteData)
+	defer jsoniter.ConfigDefault.ReturnIterator(iter)
+	if iter.WhatIsNext() != jsoniter.ObjectValue {
+		return "", 0, false
+	}
+
+	fields := make([]rawField, 0, 8)
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, key string) bool {
+		value := iter.SkipAndReturnBytes()
+		// 重复的键以最后一次为准（与解析为 map 时一致）
+		for i := range fields {
+			if fields[i].key == key {
+				fields[i].value = value
+				return true
+			}
+		}
+		fields = append(fields, rawField{key: key, value: value})
+		return true
+	})
+	if iter.Error != nil {
+		return "", 0, false
+	}
+	// 对象之后不允许出现其他内容
+	if iter.WhatIsNext() != jsoniter.InvalidValue {
+		return "", 0, false
+	}
+
+	code, msg := int32(SuccessCode), SuccessDesc
+	decorated := true
+	if index := rawFieldIndex(fields, CommonRespKey); index >= 0 {
+		code, msg = commonRespCodeAndMsg(fields[index].value, code, msg)
+		fields = removeRawField(fields, index)
+	} else if index := rawFieldIndex(fields, CommonRespKeyV2); index >= 0 {
+		code, msg = commonRespCodeAndMsg(fields[index].value, code, msg)
+		fields = removeRawField(fields, index)
+	} else {
+		// 没有 CommonResp 时，检查是否有 code 和 message 字段（proto 响应格式）
+		decorated = false
+		if index := rawFieldIndex(fields, "code"); index >= 0 {
+			if value := jsoniter.Get(fields[index].value); value.ValueType() == jsoniter.NumberValue {
+				code = int32(value.ToFloat64())
+				decorated = true
+			}
+		}
+		if index := rawFieldIndex(fields, "message"); index >= 0 {
+			if value := jsoniter.Get(fields[index].value); value.ValueType() == jsoniter.StringValue {
+				msg = value.ToString()
+				decorated = true
+			}
+		}
+		if decorated {
+			if index := rawFieldIndex(fields, "code"); index >= 0 {
+				fields = removeRawField(fields, index)
+			}
+			if index := rawFieldIndex(fields, "message"); index >= 0 {
+				fields = removeRawField(fields, index)
+			}
+		}
+	}
+
+	stream := jsoniter.ConfigDefault.BorrowStream(nil)
+	defer jsoniter.ConfigDefault.ReturnStream(stream)
+	stream.WriteObjectStart()
+	for key, value := range env.extraFields(code) {
+		stream.WriteObjectField(key)
+		stream.WriteVal(value)
+		stream.WriteMore()
+	}
+	stream.WriteObjectField(env.CodeField)
+	stream.WriteInt32(code)
+	stream.WriteMore()
+	stream.WriteObjectField(env.MsgField)
+	stream.WriteString(msg)
+	stream.WriteMore()
+	stream.WriteObjectField(env.DataField)
+	switch {
+	case !decorated:
+		// 既没有 CommonResp 也没有 code/message，原始响应整体作为 data
+		_, _ = stream.Write(byteData)
+	case len(fields) == 0:
+		stream.WriteNil()
+	default:
+		stream.WriteObjectStart()
+		for i, field := range fields {
+			if i > 0 {
+				stream.WriteMore()
+			}
+			stream.WriteObjectField(field.key)
+			_, _ = stream.Write(field.value)
+		}
+		stream.WriteObjectEnd()
+	}
+	if !env.OmitRequestID {
+		stream.WriteMore()
+		stream.WriteObjectField(env.RequestIDField)
+		stream.WriteString(traceID)
+	}
+	stream.WriteObjectEnd()
+	if stream.Error != nil {
+		return "", 0, false
+	}
+	return string(stream.Buffer()), code, true
+}
+
+// commonRespCodeAndMsg 读取 CommonResp 中的 code 与 msg，缺失或类型不符时保留默认值
+func commonRespCodeAndMsg(raw []byte, code int32, msg string) (int32, string) {
+	commonResp := jsoniter.Get(raw)
+	if commonResp.ValueType() != jsoniter.ObjectValue {
+		return code, msg
+	}
+	if value := commonResp.Get("code"); value.ValueType() == jsoniter.NumberValue {
+		code = int32(value.ToFloat64())
+	}
+	if value := commonResp.Get("msg"); value.ValueType() == jsoniter.StringValue {
+		msg = value.ToString()
+	}
+	return code, msg
+}
+
+func rawFieldIndex(fields []rawField, key string) int {
+	for i := range fields {
+		if fields[i].key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func removeRawField(fields []rawField, index int) []rawField {
+	return append(fields[:index], fields[index+1:]...)
+}
@@ -0,0 +1,84 @@
+package grpcep
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// legacyResponseDecorator 基于 map 的实现，作为流式转换的对照
+func legacyResponseDecorator(h *BaseHandler, byteData []byte, traceID string) string {
+	result, _ := jsoniter.Marshal(h.decorateResponse(byteData, traceID))
+	return string(result)
+}
+
+func TestDecorateJSONMatchesMapBasedDecorator(t *testing.T) {
+	h := &BaseHandler{}
+	cases := []string{
+		`{"CommonResp":{"code":1001,"msg":"bad"},"user":{"id":1,"name":"alice"}}`,
+		`{"common_resp":{"code":0,"msg":"ok"},"items":[1,2,3]}`,
+		`{"common_resp":{"code":200,"msg":"ok"}}`,
+		`{"common_resp":null,"value":true}`,
+		`{"code":3,"message":"failed","detail":"x"}`,
+		`{"code":"3","detail":"x"}`,
+		`{"message":"only message"}`,
+		`{"user":{"id":1},"tags":["a","b"]}`,
+		`{}`,
+		`[1,2,3]`,
+		`"text"`,
+		`null`,
+		`not json`,
+		`{"a":1}trailing`,
+		`{"a":1,"a":2}`,
+		`{"html":"<b>&</b>","unicode":"你好"}`,
+	}
+	for _, input := range cases {
+		got := h.ResponseDecorator([]byte(input), "trace-1")
+		want := legacyResponseDecorator(h, []byte(input), "trace-1")
+		var gotValue, wantValue interface{}
+		if err := jsoniter.UnmarshalFromString(want, &wantValue); err != nil {
+			// 非法 JSON 回退到原实现，输出应完全一致
+			if got != want {
+				t.Errorf("ResponseDecorator(%s)\n got  %s\n want %s", input, got, want)
+			}
+			continue
+		}
+		if err := jsoniter.UnmarshalFromString(got, &gotValue); err != nil {
+			t.Fatalf("ResponseDecorator(%s) produced invalid JSON %s: %v", input, got, err)
+		}
+		if !reflect.DeepEqual(gotValue, wantValue) {
+			t.Errorf("ResponseDecorator(%s)\n got  %s\n want %s", input, got, want)
+		}
+	}
+}
+
+func TestDecorateJSONPreservesLargeIntegers(t *testing.T) {
+	got := (&BaseHandler{}).ResponseDecorator([]byte(`{"common_resp":{"code":0,"msg":"ok"},"id":1234567890123456789}`), "")
+	if !strings.Contains(got, `"id":1234567890123456789`) {
+		t.Fatalf("expected large integer to be copied verbatim, got %s", got)
+	}
+}
+
+// benchmarkResponse 典型的网关响应：CommonResp + 列表数据
+var benchmarkResponse = []byte(`{"common_resp":{"code":0,"msg":"success"},"total":3,"items":[` +
+	`{"id":1001,"name":"alice","email":"alice@example.com","roles":["admin","user"],"profile":{"age":30,"city":"Shanghai"}},` +
+	`{"id":1002,"name":"bob","email":"bob@example.com","roles":["user"],"profile":{"age":25,"city":"Beijing"}},` +
+	`{"id":1003,"name":"carol","email":"carol@example.com","roles":["user"],"profile":{"age":41,"city":"Shenzhen"}}]}`)
+
+func BenchmarkResponseDecorator(b *testing.B) {
+	h := &BaseHandler{}
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = h.ResponseDecorator(benchmarkResponse, "trace-id")
+	}
+}
+
+func BenchmarkResponseDecoratorMapBased(b *testing.B) {
+	h := &BaseHandler{}
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = legacyResponseDecorator(h, benchmarkResponse, "trace-id")
+	}
+}
@@ -0,0 +1,163 @@
+package grpcep
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// FieldError 字段级错误（对应 google.rpc.BadRequest.FieldViolation）
+type FieldError struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// ErrorInfo 错误原因（对应 google.rpc.ErrorInfo）
+type ErrorInfo struct {
+	Reason   string            `json:"reason"`
+	Domain   string            `json:"domain,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// ErrorDetails 从 gRPC status 中解析出的结构化错误详情
+type ErrorDetails struct {
+	Code       codes.Code
+	Message    string
+	FieldErrs  []FieldError
+	Info       *ErrorInfo
+	RetryAfter time.Duration
+}
+
+// ==================== 服务端：附加错误详情 ====================
+
+// NewBadRequestError 创建带字段校验错误的 InvalidArgument 错误
+func NewBadRequestError(msg string, fieldErrs ...FieldError) error {
+	return withStatusDetails(status.New(codes.InvalidArgument, msg), newBadRequest(fieldErrs))
+}
+
+// WithFieldErrors 为错误附加字段校验错误（非 status 错误会被转换为 Unknown）
+func WithFieldErrors(err error, fieldErrs ...FieldError) error {
+	if err == nil || len(fieldErrs) == 0 {
+		return err
+	}
+	return withStatusDetails(status.Convert(err), newBadRequest(fieldErrs))
+}
+
+func newBadRequest(fieldErrs []FieldError) *errdetails.BadRequest {
+	br := &errdetails.BadRequest{}
+	for _, fe := range fieldErrs {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       fe.Field,
+			Description: fe.Description,
+		})
+	}
+	return br
+}
+
+// WithErrorInfo 为错误附加机器可读的错误原因
+func WithErrorInfo(err error, reason, domain string, metadata map[string]string) error {
+	if err == nil {
+		return nil
+	}
+	return withStatusDetails(status.Convert(err), &errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   domain,
+		Metadata: metadata,
+	})
+}
+
+// WithRetryInfo 为错误附加建议的重试间隔
+func WithRetryInfo(err error, delay time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return withStatusDetails(status.Convert(err), &errdetails.RetryInfo{
+		RetryDelay: durationpb.New(delay),
+	})
+}
+
+func withStatusDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		// 附加失败时退化为原始 status，不丢失错误本身
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// HasErrorDetails 判断错误是否携带 gRPC status 详情
+func HasErrorDetails(err error) bool {
+	if err == nil {
+		return false
+	}
+	st, ok := status.FromError(err)
+	return ok && len(st.Proto().GetDetails()) > 0
+}
+
+// ==================== 网关：解析错误详情 ====================
+
+// ParseErrorDetails 从 gRPC 错误中解析结构化错误详情，不携带详情时返回 nil
+func ParseErrorDetails(err error) *ErrorDetails {
+	if !HasErrorDetails(err) {
+		return nil
+	}
+	st, _ := status.FromError(err)
+
+	details := &ErrorDetails{
+		Code:    st.Code(),
+		Message: st.Message(),
+	}
+	for _, d := range st.Details() {
+		switch v := d.(type) {
+		case *errdetails.BadRequest:
+			for _, fv := range v.GetFieldViolations() {
+				details.FieldErrs = append(details.FieldErrs, FieldError{
+					Field:       fv.GetField(),
+					Description: fv.GetDescription(),
+				})
+			}
+		case *errdetails.ErrorInfo:
+			details.Info = &ErrorInfo{
+				Reason:   v.GetReason(),
+				Domain:   v.GetDomain(),
+				Metadata: v.GetMetadata(),
+			}
+		case *errdetails.RetryInfo:
+			if v.GetRetryDelay() != nil {
+				details.RetryAfter = v.GetRetryDelay().AsDuration()
+			}
+		}
+	}
+	return details
+}
+
+// errorDetailsResponse 将错误详情渲染为 JSON 响应，并设置 Retry-After 响应头
+func (h *BaseHandler) errorDetailsResponse(ctx *fiber.Ctx, details *ErrorDetails) error {
+	code := int32(InternalErrCode)
+	if details.Code == codes.InvalidArgument || len(details.FieldErrs) > 0 {
+		code = ParamsErrCode
+	}
+	msg := details.Message
+	if msg == "" {
+		msg = details.Code.String()
+	}
+
+	if details.RetryAfter > 0 {
+		seconds := int64(math.Ceil(details.RetryAfter.Seconds()))
+		ctx.Set(fiber.HeaderRetryAfter, strconv.FormatInt(seconds, 10))
+	}
+
+	return h.Response(ctx, JsonResponse{
+		Code:      code,
+		Msg:       msg,
+		Errors:    details.FieldErrs,
+		ErrorInfo: details.Info,
+	}, nil)
+}
@@ -0,0 +1,88 @@
+package grpcep
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	jsoniter "github.com/json-iterator/go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseErrorDetails(t *testing.T) {
+	err := NewBadRequestError("invalid request",
+		FieldError{Field: "name", Description: "required"},
+		FieldError{Field: "age", Description: "must be positive"},
+	)
+	err = WithErrorInfo(err, "NAME_REQUIRED", "user.quickgo", map[string]string{"field": "name"})
+	err = WithRetryInfo(err, 1500*time.Millisecond)
+
+	details := ParseErrorDetails(err)
+	if details == nil {
+		t.Fatal("expected error details")
+	}
+	if details.Code != codes.InvalidArgument || details.Message != "invalid request" {
+		t.Fatalf("unexpected status: code=%s, msg=%s", details.Code, details.Message)
+	}
+	if len(details.FieldErrs) != 2 || details.FieldErrs[1].Field != "age" {
+		t.Fatalf("unexpected field errors: %+v", details.FieldErrs)
+	}
+	if details.Info == nil || details.Info.Reason != "NAME_REQUIRED" || details.Info.Metadata["field"] != "name" {
+		t.Fatalf("unexpected error info: %+v", details.Info)
+	}
+	if details.RetryAfter != 1500*time.Millisecond {
+		t.Fatalf("unexpected retry after: %s", details.RetryAfter)
+	}
+}
+
+func TestParseErrorDetailsWithoutDetails(t *testing.T) {
+	if ParseErrorDetails(status.Error(codes.NotFound, "not found")) != nil {
+		t.Fatal("expected nil details for plain status error")
+	}
+	if ParseErrorDetails(errors.New("plain")) != nil {
+		t.Fatal("expected nil details for plain error")
+	}
+	if HasErrorDetails(nil) {
+		t.Fatal("expected nil error to have no details")
+	}
+}
+
+func TestGRPCCallRendersErrorDetails(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Post("/call", func(ctx *fiber.Ctx) error {
+		handler := func(context.Context, *testGRPCReq) (*testGRPCResp, error) {
+			err := NewBadRequestError("invalid request", FieldError{Field: "name", Description: "required"})
+			return nil, WithRetryInfo(err, 1500*time.Millisecond)
+		}
+		return (&BaseHandler{}).GRPCCall(ctx, &testGRPCReq{}, handler)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/call", nil))
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if got := resp.Header.Get(fiber.HeaderRetryAfter); got != "2" {
+		t.Fatalf("expected Retry-After 2, got %q", got)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	var out JsonResponse
+	if err := jsoniter.Unmarshal(body, &out); err != nil {
+		t.Fatalf("unmarshal response failed: %v", err)
+	}
+	if out.Code != ParamsErrCode || out.Msg != "invalid request" {
+		t.Fatalf("unexpected envelope: %s", body)
+	}
+	if len(out.Errors) != 1 || out.Errors[0].Field != "name" {
+		t.Fatalf("expected field errors in envelope, got %s", body)
+	}
+	if strings.Contains(string(body), "error_info") {
+		t.Fatalf("expected error_info to be omitted, got %s", body)
+	}
+}
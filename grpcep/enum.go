@@ -17,4 +17,7 @@ const (
 
 	InternalErrCode = 50000
 	InternalErrDesc = "internal error"
+
+	TimeoutErrCode = 50400
+	TimeoutErrDesc = "upstream timeout"
 )
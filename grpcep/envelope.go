@@ -0,0 +1,113 @@
+package grpcep
+
+import (
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// EnvelopeConfig 统一响应结构定制，应用启动时通过 SetEnvelope 配置一次，
+// BaseHandler.Response、GRPCCall、声明式路由与 ResponseDecorator 均按该配置输出
+type EnvelopeConfig struct {
+	// 字段名，为空时使用默认值 code、msg、data、request_id
+	CodeField      string
+	MsgField       string
+	DataField      string
+	RequestIDField string
+	// 是否省略 request_id 字段
+	OmitRequestID bool
+	// 按业务码映射 HTTP 状态码，返回 0 表示保持默认
+	// 优先级低于 JsonResponse.HttpStatus 与 gerr 注册表中声明的状态码
+	HTTPStatus func(code int32) int
+	// 按业务码追加字段（如服务器时间、版本号），与标准字段同名的字段被忽略
+	Fields func(code int32) map[string]interface{}
+}
+
+// envelope 生效的响应结构配置（字段名已补全默认值）
+type envelope struct {
+	EnvelopeConfig
+	// 与默认结构一致，可直接序列化 JsonResponse
+	standard bool
+}
+
+var currentEnvelope atomic.Pointer[envelope]
+
+func init() {
+	SetEnvelope(EnvelopeConfig{})
+}
+
+// SetEnvelope 设置统一响应结构（并发安全，通常在启动时调用一次），传入零值恢复默认结构
+func SetEnvelope(config EnvelopeConfig) {
+	if config.CodeField == "" {
+		config.CodeField = "code"
+	}
+	if config.MsgField == "" {
+		config.MsgField = "msg"
+	}
+	if config.DataField == "" {
+		config.DataField = "data"
+	}
+	if config.RequestIDField == "" {
+		config.RequestIDField = "request_id"
+	}
+	standard := config.CodeField == "code" && config.MsgField == "msg" && config.DataField == "data" &&
+		config.RequestIDField == "request_id" && !config.OmitRequestID && config.Fields == nil
+	currentEnvelope.Store(&envelope{EnvelopeConfig: config, standard: standard})
+}
+
+func loadEnvelope() *envelope {
+	return currentEnvelope.Load()
+}
+
+// value 返回响应的序列化结构：默认结构时直接返回 JsonResponse，否则按配置生成 map
+func (e *envelope) value(resp JsonResponse) interface{} {
+	if e.standard {
+		return resp
+	}
+	out := make(map[string]interface{}, 7)
+	for key, value := range e.extraFields(resp.Code) {
+		out[key] = value
+	}
+	out[e.CodeField] = resp.Code
+	out[e.MsgField] = resp.Msg
+	out[e.DataField] = resp.Data
+	if len(resp.Errors) > 0 {
+		out["errors"] = resp.Errors
+	}
+	if resp.ErrorInfo != nil {
+		out["error_info"] = resp.ErrorInfo
+	}
+	if !e.OmitRequestID {
+		out[e.RequestIDField] = resp.RequestId
+	}
+	return out
+}
+
+// extraFields 返回追加字段，排除与标准字段同名的字段
+func (e *envelope) extraFields(code int32) map[string]interface{} {
+	if e.Fields == nil {
+		return nil
+	}
+	fields := e.Fields(code)
+	for key := range fields {
+		if e.reserved(key) {
+			delete(fields, key)
+		}
+	}
+	return fields
+}
+
+func (e *envelope) reserved(key string) bool {
+	return key == e.CodeField || key == e.MsgField || key == e.DataField || key == e.RequestIDField ||
+		key == "errors" || key == "error_info"
+}
+
+// applyStatus 按业务码映射设置 HTTP 状态码（未配置映射或映射结果为 0 时不修改）
+func (e *envelope) applyStatus(c *fiber.Ctx, code int32) {
+	if e.HTTPStatus == nil {
+		return
+	}
+	if status := e.HTTPStatus(code); status > 0 {
+		c.Status(status)
+	}
+}
@@ -0,0 +1,86 @@
+package grpcep
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/team-dandelion/quickgo/gerr"
+)
+
+func TestSetEnvelopeCustomizesResponses(t *testing.T) {
+	SetEnvelope(EnvelopeConfig{
+		CodeField:     "status",
+		MsgField:      "message",
+		DataField:     "result",
+		OmitRequestID: true,
+		HTTPStatus: func(code int32) int {
+			if code == 40401 {
+				return fiber.StatusNotFound
+			}
+			return 0
+		},
+		Fields: func(int32) map[string]interface{} {
+			return map[string]interface{}{"server_time": 1700000000, "status": "ignored"}
+		},
+	})
+	t.Cleanup(func() { SetEnvelope(EnvelopeConfig{}) })
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Post("/ok", func(c *fiber.Ctx) error {
+		return Invoke(&BaseHandler{}, c, new(testGRPCReq), func(context.Context, *testGRPCReq) (*testGRPCResp, error) {
+			return &testGRPCResp{Data: "hello"}, nil
+		})
+	})
+	app.Post("/missing", func(c *fiber.Ctx) error {
+		return (&BaseHandler{}).Response(c, JsonResponse{}, gerr.NewGErr(40401, "not found"))
+	})
+
+	call := func(path string) (int, map[string]interface{}) {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest("POST", path, nil))
+		if err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return resp.StatusCode, body
+	}
+
+	status, body := call("/ok")
+	if status != fiber.StatusOK || body["status"] != float64(SuccessCode) || body["message"] != SuccessDesc ||
+		body["server_time"] != float64(1700000000) {
+		t.Fatalf("unexpected decorated response: %d %v", status, body)
+	}
+	if result, ok := body["result"].(map[string]interface{}); !ok || result["Data"] != "hello" {
+		t.Fatalf("expected rpc response under result, got %v", body)
+	}
+	if _, ok := body["request_id"]; ok {
+		t.Fatalf("expected request_id to be omitted, got %v", body)
+	}
+
+	status, body = call("/missing")
+	if status != fiber.StatusNotFound || body["status"] != float64(40401) || body["message"] != "not found" {
+		t.Fatalf("unexpected error response: %d %v", status, body)
+	}
+	if _, ok := body["code"]; ok {
+		t.Fatalf("expected default field names to be replaced, got %v", body)
+	}
+}
+
+func TestSetEnvelopeZeroValueRestoresDefault(t *testing.T) {
+	SetEnvelope(EnvelopeConfig{CodeField: "status"})
+	SetEnvelope(EnvelopeConfig{})
+	if !loadEnvelope().standard {
+		t.Fatal("expected zero config to restore the standard envelope")
+	}
+	got := (&BaseHandler{}).ResponseDecorator([]byte(`{"common_resp":{"code":0,"msg":"ok"},"id":1}`), "trace")
+	if got != `{"code":0,"msg":"ok","data":{"id":1},"request_id":"trace"}` {
+		t.Fatalf("unexpected default envelope: %s", got)
+	}
+}
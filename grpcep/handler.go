@@ -6,19 +6,23 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"reflect"
 	"strings"
 
 	"github.com/team-dandelion/quickgo/gerr"
 	"github.com/team-dandelion/quickgo/http"
 	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/pagination"
 	"github.com/team-dandelion/quickgo/tracing"
 
 	jsoniter "github.com/json-iterator/go"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/spf13/cast"
+	"github.com/gofiber/fiber/v2/utils"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type BaseHandler struct {
@@ -54,15 +58,8 @@ func (h *BaseHandler) GRPCCall(ctx *fiber.Ctx, param interface{}, handler interf
 		return errors.New("rpc_call param is not a pointer")
 	}
 
-	if len(ctx.Body()) > 0 {
-		err := h.ParseJson(ctx, param)
-		if err != nil {
-			logger.Error(c, "parse json error: %v", err)
-			return h.Response(ctx, JsonResponse{
-				Code: ParamsErrCode,
-				Msg:  err.Error(),
-			}, err)
-		}
+	if handled, err := h.bindRequest(ctx, param); handled {
+		return err
 	}
 
 	refHandler := reflect.ValueOf(handler)
@@ -77,16 +74,67 @@ func (h *BaseHandler) GRPCCall(ctx *fiber.Ctx, param interface{}, handler interf
 	rets = refHandler.Call(inParam)
 
 	if !rets[1].IsNil() {
-		err := rets[1].Interface().(error)
-		return h.Response(ctx, JsonResponse{}, gerr.NewGErr(InternalErrCode, err.Error()))
+		return h.rpcErrorResponse(ctx, rets[1].Interface().(error))
 	}
+	return h.rpcResultResponse(ctx, rets[0].Interface())
+}
 
-	// 对rpc响应内容进行处理
-	byteData, _ := jsoniter.Marshal(rets[0].Interface())
-	resp := h.ResponseDecorator(byteData, http.GetTraceID(ctx))
-	ctx.Response().Header.Add("Content-Type", fiber.MIMEApplicationJSON)
-	_, err := ctx.WriteString(resp)
-	return err
+// bindRequest 解析并校验请求体（请求体为空时跳过），失败时写入参数错误响应并返回 handled = true
+func (h *BaseHandler) bindRequest(ctx *fiber.Ctx, param interface{}) (bool, error) {
+	if len(ctx.Body()) == 0 {
+		return false, nil
+	}
+	if err := h.ParseJson(ctx, param); err != nil {
+		logger.Error(ctx.Context(), "parse json error: %v", err)
+		return true, h.Response(ctx, JsonResponse{
+			Code: ParamsErrCode,
+			Msg:  err.Error(),
+		}, err)
+	}
+	return false, nil
+}
+
+// rpcErrorResponse 将 rpc 调用错误转换为统一响应
+func (h *BaseHandler) rpcErrorResponse(ctx *fiber.Ctx, err error) error {
+	// 下游调用超过请求截止时间（X-Request-Timeout、路由超时或 CallTimeout）返回 504
+	if status.Code(err) == codes.DeadlineExceeded {
+		return h.timeoutResponse(ctx)
+	}
+	// 服务端返回的 GErr（经 gerr.ToGRPCStatus 编码）还原为原始错误码
+	if gErr := rpcGErr(err); gErr != nil {
+		return h.Response(ctx, JsonResponse{}, gErr)
+	}
+	// 携带 google.rpc.Status 详情的错误，透传字段错误、错误原因与重试间隔
+	if details := ParseErrorDetails(err); details != nil {
+		return h.errorDetailsResponse(ctx, details)
+	}
+	return h.Response(ctx, JsonResponse{}, gerr.NewGErr(InternalErrCode, err.Error()))
+}
+
+// timeoutResponse 下游调用超时响应
+func (h *BaseHandler) timeoutResponse(ctx *fiber.Ctx) error {
+	return h.Response(ctx, JsonResponse{HttpStatus: fiber.StatusGatewayTimeout, Code: TimeoutErrCode, Msg: TimeoutErrDesc}, nil)
+}
+
+// rpcResultResponse 对 rpc 响应内容进行处理，按 ResponseDecorator 输出统一响应
+func (h *BaseHandler) rpcResultResponse(ctx *fiber.Ctx, result interface{}) error {
+	byteData, _ := jsoniter.Marshal(result)
+	return h.writeDecoratedResponse(ctx, byteData)
+}
+
+// writeDecoratedResponse 将 rpc 响应 JSON 转换为统一响应结构，按协商的序列化方式写入并按业务码映射 HTTP 状态码
+func (h *BaseHandler) writeDecoratedResponse(ctx *fiber.Ctx, byteData []byte) error {
+	traceID := http.GetTraceID(ctx)
+	env := loadEnvelope()
+	if binaryResponseSerializer(ctx) != nil {
+		resp := h.decorateResponse(byteData, traceID)
+		env.applyStatus(ctx, resp.Code)
+		return h.writeResponse(ctx, resp)
+	}
+	resp, code := h.decorate(byteData, traceID)
+	env.applyStatus(ctx, code)
+	ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return ctx.SendString(resp)
 }
 
 func validateGRPCCallHandler(refParam reflect.Value, refHandler reflect.Value) error {
@@ -221,7 +269,39 @@ func (b *BaseHandler) RPCStream(ctx *fiber.Ctx, param interface{}, streamFunc fu
 	return nil
 }
 
+// ResponseDecorator 将 rpc 响应 JSON 转换为统一响应 JSON（字段名等见 SetEnvelope）
+// 响应为 JSON 对象时单次流式转换，不经过 map 反序列化与二次序列化；其他响应回退到 decorateResponse
 func (h *BaseHandler) ResponseDecorator(byteData []byte, traceID string) string {
+	resp, _ := h.decorate(byteData, traceID)
+	return resp
+}
+
+// decorate 返回统一响应 JSON 及其业务码
+func (h *BaseHandler) decorate(byteData []byte, traceID string) (string, int32) {
+	env := loadEnvelope()
+	if result, code, ok := decorateJSON(env, byteData, traceID); ok {
+		return result, code
+	}
+	jsonResp := h.decorateResponse(byteData, traceID)
+	// 序列化为 JSON 字符串
+	result, err := jsoniter.Marshal(env.value(jsonResp))
+	if err != nil {
+		// 如果序列化失败，返回错误响应
+		errorResp := JsonResponse{
+			Code:      InternalErrCode,
+			Msg:       InternalErrDesc,
+			Data:      nil,
+			RequestId: traceID,
+		}
+		result, _ = jsoniter.Marshal(env.value(errorResp))
+		return string(result), errorResp.Code
+	}
+
+	return string(result), jsonResp.Code
+}
+
+// decorateResponse 将 rpc 响应 JSON 转换为统一响应结构：提取 CommonResp（或 code/message）作为响应码与消息，其余字段作为 data
+func (h *BaseHandler) decorateResponse(byteData []byte, traceID string) JsonResponse {
 	// 先尝试解析为 map，检查是否包含 CommonResp 或 common_resp 字段
 	var dataMap map[string]interface{}
 	var code int32 = SuccessCode
@@ -307,55 +387,21 @@ func (h *BaseHandler) ResponseDecorator(byteData []byte, traceID string) string
 		}
 	}
 
-	// 序列化为 JSON 字符串
-	result, err := jsoniter.Marshal(jsonResp)
-	if err != nil {
-		// 如果序列化失败，返回错误响应
-		errorResp := JsonResponse{
-			Code:      InternalErrCode,
-			Msg:       InternalErrDesc,
-			Data:      nil,
-			RequestId: traceID,
-		}
-		result, _ = jsoniter.Marshal(errorResp)
-		return string(result)
-	}
-
-	return string(result)
+	return jsonResp
 }
 
 func (h *BaseHandler) RPCCtx(c *fiber.Ctx) context.Context {
-	// 1. 获取基础 context（优先级：trace_ctx > UserContext > Context）
-	var ctx context.Context
-
-	// 优先从 Locals 中获取 trace context（由 tracing middleware 设置）
-	if traceCtx, ok := c.Locals("trace_ctx").(context.Context); ok && traceCtx != nil {
-		ctx = traceCtx
-	} else {
-		// 从 UserContext 获取（Fiber 的标准方式）
-		ctx = c.UserContext()
-		if ctx == nil {
-			// 最后使用 fasthttp context
-			ctx = c.Context()
-		}
-	}
+	return h.rpcCtx(c, nil)
+}
 
-	// 确保 context 不为 nil
-	if ctx == nil {
-		ctx = context.Background()
-	}
+// rpcCtx 构建调用 context，extra 为请求携带的额外 metadata（如隧道的 Grpc-Metadata-* 请求头），与 UserValues 一同经过策略过滤
+func (h *BaseHandler) rpcCtx(c *fiber.Ctx, extra map[string]string) context.Context {
+	// 1. 获取请求 context（合并 UserContext、trace_ctx、trace ID、身份与截止时间）
+	ctx := http.RequestContext(c)
 
-	// 2. 收集 UserValues 并创建 gRPC metadata
-	userValues := make(map[string]string)
-	if fctx := c.Context(); fctx != nil {
-		fctx.VisitUserValues(func(key []byte, value interface{}) {
-			userValues[string(key)] = cast.ToString(value)
-		})
-	}
-
-	// 3. 将 UserValues 添加到 outgoing metadata
-	if len(userValues) > 0 {
-		md := metadata.New(userValues)
+	// 2. 按 metadata 策略（未配置时全部透传）收集 UserValues、请求头转换与客户端 IP
+	// 3. 添加到 outgoing metadata
+	if md := outgoingMetadata(c, loadMetadataPolicy(c), extra); len(md) > 0 {
 		ctx = metadata.NewOutgoingContext(ctx, md)
 	}
 
@@ -367,6 +413,25 @@ func (h *BaseHandler) RPCCtx(c *fiber.Ctx) context.Context {
 	return ctx
 }
 
+// ForwardHeaders 将指定的请求头写入 UserValues，经 RPCCtx 透传为 gRPC metadata（键为小写请求头名）
+// 常用于灰度路由：网关透传 x-canary 等请求头，客户端负载均衡器按 metadata 选择灰度实例
+func ForwardHeaders(headers ...string) fiber.Handler {
+	keys := make([]string, 0, len(headers))
+	for _, header := range headers {
+		if header = strings.ToLower(strings.TrimSpace(header)); header != "" {
+			keys = append(keys, header)
+		}
+	}
+	return func(c *fiber.Ctx) error {
+		for _, key := range keys {
+			if value := c.Get(key); value != "" {
+				c.Context().SetUserValue(key, utils.CopyString(value))
+			}
+		}
+		return c.Next()
+	}
+}
+
 func (h *BaseHandler) ParseJson(c *fiber.Ctx, param interface{}) error {
 	err := c.BodyParser(param)
 	if err != nil {
@@ -375,15 +440,52 @@ func (h *BaseHandler) ParseJson(c *fiber.Ctx, param interface{}) error {
 	return StructValidator(param)
 }
 
+// ParsePageRequest 解析请求中的 page/page_size/sort/filter 查询参数，参数不合法时返回参数错误
+// 查询结果可通过 pagination.NewPageResponse 构建后作为 JsonResponse.Data 返回
+func (h *BaseHandler) ParsePageRequest(c *fiber.Ctx, spec *pagination.QuerySpec) (*pagination.PageRequest, error) {
+	values, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		return nil, gerr.NewGErr(ParamsErrCode, ParamsErrDesc+"err:"+err.Error())
+	}
+	req, err := pagination.ParsePageRequest(values, spec)
+	if err != nil {
+		return nil, gerr.NewGErr(ParamsErrCode, ParamsErrDesc+"err:"+err.Error())
+	}
+	return req, nil
+}
+
 func (h *BaseHandler) Response(ctx *fiber.Ctx, respData JsonResponse, err error) error {
+	statusSet := true
 	if respData.HttpStatus > 0 {
 		ctx.Status(respData.HttpStatus)
+	} else if err != nil && gerr.IsRegistered(err) {
+		// 已注册的错误码使用声明的 HTTP 状态码，未注册的错误保持 200 + 业务码
+		ctx.Status(gerr.HTTPStatus(err))
+	} else {
+		statusSet = false
 	}
 
 	respData.Code, respData.Msg = h.msgAndCodeParser(respData.Code, respData.Msg, err)
 	respData.RequestId = http.GetTraceID(ctx)
+	if !statusSet {
+		loadEnvelope().applyStatus(ctx, respData.Code)
+	}
+
+	return h.writeResponse(ctx, respData)
+}
 
-	return ctx.JSON(respData)
+// rpcGErr 获取 gRPC 调用错误中的 GErr：直接返回的 GErr 或由 gerr.ToGRPCStatus 编码的 status，其他错误返回 nil
+func rpcGErr(err error) *gerr.GErr {
+	var gErr *gerr.GErr
+	if errors.As(err, &gErr) {
+		return gErr
+	}
+	if st, ok := status.FromError(err); ok {
+		if gErr = gerr.FromGRPCStatus(st); gErr != nil && gErr.Code != 0 {
+			return gErr
+		}
+	}
+	return nil
 }
 
 func (h *BaseHandler) msgAndCodeParser(code int32, msg string, err error) (int32, string) {
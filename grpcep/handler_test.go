@@ -2,6 +2,8 @@ package grpcep
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 	"net/http/httptest"
 	"reflect"
 	"strings"
@@ -9,6 +11,11 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/team-dandelion/quickgo/gerr"
+	"github.com/team-dandelion/quickgo/pagination"
+	"github.com/team-dandelion/quickgo/serializer"
 )
 
 type testGRPCReq struct {
@@ -84,3 +91,156 @@ func TestValidateGRPCCallHandlerRejectsWrongParam(t *testing.T) {
 		t.Fatalf("expected second arg error, got %v", err)
 	}
 }
+
+func TestInvokeMatchesGRPCCallResponses(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Post("/invoke", func(c *fiber.Ctx) error {
+		return Invoke(&BaseHandler{}, c, new(testGRPCReq), func(_ context.Context, req *testGRPCReq) (*testGRPCResp, error) {
+			if req.Name == "fail" {
+				return nil, gerr.NewGErr(40401, "not found")
+			}
+			return &testGRPCResp{Data: "hello " + req.Name}, nil
+		})
+	})
+
+	call := func(body string) JsonResponse {
+		t.Helper()
+		req := httptest.NewRequest("POST", "/invoke", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+		var decoded JsonResponse
+		if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return decoded
+	}
+
+	if got := call(`{"Name":"alice"}`); got.Code != SuccessCode || !reflect.DeepEqual(got.Data, map[string]interface{}{"Data": "hello alice"}) {
+		t.Fatalf("unexpected success response %+v", got)
+	}
+	if got := call(`{"Name":"fail"}`); got.Code != 40401 || got.Msg != "not found" {
+		t.Fatalf("unexpected error response %+v", got)
+	}
+	if got := call(`{"Name":`); got.Code != ParamsErrCode {
+		t.Fatalf("expected params error for invalid body, got %+v", got)
+	}
+}
+
+func TestGRPCCallRestoresRegisteredErrorFromStatus(t *testing.T) {
+	errQuota := gerr.MustRegister(gerr.ErrorCode{Code: 42901, Type: gerr.TypeBusiness, Msg: "quota exceeded", HTTPStatus: fiber.StatusTooManyRequests})
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Post("/call", func(c *fiber.Ctx) error {
+		return (&BaseHandler{}).GRPCCall(c, &testGRPCReq{}, func(context.Context, *testGRPCReq) (*testGRPCResp, error) {
+			// 模拟服务端拦截器编码后经 gRPC 传输的错误
+			return nil, gerr.ToGRPCStatus(errQuota.New()).Err()
+		})
+	})
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/call", nil))
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusTooManyRequests {
+		t.Fatalf("expected registered HTTP status, got %d", resp.StatusCode)
+	}
+	var body JsonResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Code != 42901 || body.Msg != "quota exceeded" {
+		t.Fatalf("unexpected response %+v", body)
+	}
+}
+
+func TestGRPCCallUsesNegotiatedSerializer(t *testing.T) {
+	middleware, err := SerializerMiddleware(serializer.JSON, serializer.Msgpack)
+	if err != nil {
+		t.Fatalf("SerializerMiddleware failed: %v", err)
+	}
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Post("/call", middleware, func(c *fiber.Ctx) error {
+		return (&BaseHandler{}).GRPCCall(c, &testGRPCReq{}, func(context.Context, *testGRPCReq) (*testGRPCResp, error) {
+			return &testGRPCResp{Data: "ok"}, nil
+		})
+	})
+
+	req := httptest.NewRequest("POST", "/call", nil)
+	req.Header.Set(fiber.HeaderAccept, "application/msgpack")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	raw, _ := io.ReadAll(resp.Body)
+	var body JsonResponse
+	if err := serializer.Must(serializer.Msgpack).Unmarshal(raw, &body); err != nil {
+		t.Fatalf("decode msgpack response: %v", err)
+	}
+	if body.Code != SuccessCode || body.Data.(map[string]interface{})["Data"] != "ok" {
+		t.Fatalf("unexpected response %+v", body)
+	}
+
+	if _, err := SerializerMiddleware("yaml"); err == nil {
+		t.Fatal("expected unknown serializer to be rejected")
+	}
+}
+
+func TestForwardHeadersPropagatesToRPCMetadata(t *testing.T) {
+	app := fiber.New()
+	h := &BaseHandler{}
+	app.Get("/", ForwardHeaders("X-Canary", " "), func(c *fiber.Ctx) error {
+		md, _ := metadata.FromOutgoingContext(h.RPCCtx(c))
+		return c.SendString(strings.Join(md.Get("x-canary"), ","))
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Canary", "2.0.0")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "2.0.0" {
+		t.Fatalf("expected forwarded canary header, got %q", body)
+	}
+}
+
+func TestParsePageRequestReturnsParamsError(t *testing.T) {
+	h := &BaseHandler{}
+	spec := &pagination.QuerySpec{Sortable: map[string]string{"name": "name"}}
+	app := fiber.New()
+	app.Get("/users", func(c *fiber.Ctx) error {
+		req, err := h.ParsePageRequest(c, spec)
+		if err != nil {
+			return h.Response(c, JsonResponse{}, err)
+		}
+		return h.Response(c, JsonResponse{Data: pagination.NewPageResponse(req, []string{"a"}, 1)}, nil)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users?page=2&sort=-name", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	var body struct {
+		Code int32                           `json:"code"`
+		Data pagination.PageResponse[string] `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if body.Code != SuccessCode || body.Data.Page != 2 || body.Data.Total != 1 {
+		t.Fatalf("unexpected response %+v", body)
+	}
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/users?sort=password", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body.Code = 0
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+	if body.Code != ParamsErrCode {
+		t.Fatalf("expected params error code, got %d", body.Code)
+	}
+}
@@ -0,0 +1,24 @@
+package grpcep
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Invoke GRPCCall 的类型化版本，供 protoc-gen-quickgo-gateway 生成的处理器使用
+// 请求绑定与校验、错误转换、ResponseDecorator 响应格式与 GRPCCall 一致，但直接调用 call，调用路径上没有反射
+//
+//	return grpcep.Invoke(&g.BaseHandler, c, new(auth.LoginRequest), func(ctx context.Context, req *auth.LoginRequest) (*auth.LoginResponse, error) {
+//		return auth.NewAuthServiceClient(conn).Login(ctx, req)
+//	})
+func Invoke[Req any, Resp any](h *BaseHandler, c *fiber.Ctx, req *Req, call func(ctx context.Context, req *Req) (*Resp, error)) error {
+	if handled, err := h.bindRequest(c, req); handled {
+		return err
+	}
+	resp, err := call(h.RPCCtx(c), req)
+	if err != nil {
+		return h.rpcErrorResponse(c, err)
+	}
+	return h.rpcResultResponse(c, resp)
+}
@@ -0,0 +1,192 @@
+package grpcep
+
+import (
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/cast"
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataConfig 网关透传到后端 gRPC 服务的 metadata 策略
+// 未配置策略时 RPCCtx 保持原行为：全部 UserValues 原样透传
+type MetadataConfig struct {
+	// Allow 允许透传的 UserValue 键与隧道 Grpc-Metadata-* 请求头键（不区分大小写，支持 x-* 前缀通配），为空表示全部允许
+	Allow []string `json:"allow" yaml:"allow"`
+	// Deny 禁止透传的 UserValue 键（语法同 Allow），优先于 Allow
+	Deny []string `json:"deny" yaml:"deny"`
+	// Headers 请求头转换规则（如 Authorization → x-user-token），不受 Allow/Deny 限制
+	Headers []MetadataHeader `json:"headers" yaml:"headers"`
+	// RealIPKey 写入客户端 IP 的 metadata 键（如 x-real-ip），为空不写入
+	RealIPKey string `json:"realIPKey" yaml:"realIPKey"`
+	// MaxValueBytes 单个值最大字节数，超过的键被丢弃，0 不限制
+	MaxValueBytes int `json:"maxValueBytes" yaml:"maxValueBytes"`
+	// MaxTotalBytes 全部键值总字节数上限，超过后丢弃后续键（转换规则优先，其余按键名排序），0 不限制
+	MaxTotalBytes int `json:"maxTotalBytes" yaml:"maxTotalBytes"`
+}
+
+// MetadataHeader 请求头到 metadata 的转换规则
+type MetadataHeader struct {
+	// Header 请求头名
+	Header string `json:"header" yaml:"header"`
+	// Key 目标 metadata 键，为空时使用小写请求头名
+	Key string `json:"key" yaml:"key"`
+}
+
+// metadataPolicy 预处理后的透传策略
+type metadataPolicy struct {
+	config MetadataConfig
+	allow  []string
+	deny   []string
+}
+
+// metadataPolicyKey Locals 中保存网关级策略的键（非字符串键，不会被 VisitUserValues 遍历）
+type metadataPolicyKey struct{}
+
+var currentMetadataPolicy atomic.Pointer[metadataPolicy]
+
+// SetMetadataPolicy 设置全局 metadata 透传策略（并发安全），传入 nil 恢复全部透传
+// 网关级策略（MetadataPolicy 中间件）优先于全局策略
+func SetMetadataPolicy(config *MetadataConfig) {
+	if config == nil {
+		currentMetadataPolicy.Store(nil)
+		return
+	}
+	currentMetadataPolicy.Store(newMetadataPolicy(*config))
+}
+
+// MetadataPolicy 返回为当前网关（或路由组）设置 metadata 透传策略的中间件
+func MetadataPolicy(config MetadataConfig) fiber.Handler {
+	policy := newMetadataPolicy(config)
+	return func(c *fiber.Ctx) error {
+		c.Locals(metadataPolicyKey{}, policy)
+		return c.Next()
+	}
+}
+
+func newMetadataPolicy(config MetadataConfig) *metadataPolicy {
+	config.RealIPKey = strings.ToLower(strings.TrimSpace(config.RealIPKey))
+	headers := make([]MetadataHeader, 0, len(config.Headers))
+	for _, rule := range config.Headers {
+		rule.Header = strings.TrimSpace(rule.Header)
+		if rule.Header == "" {
+			continue
+		}
+		rule.Key = strings.ToLower(strings.TrimSpace(rule.Key))
+		if rule.Key == "" {
+			rule.Key = strings.ToLower(rule.Header)
+		}
+		headers = append(headers, rule)
+	}
+	config.Headers = headers
+	return &metadataPolicy{
+		config: config,
+		allow:  normalizeMetadataPatterns(config.Allow),
+		deny:   normalizeMetadataPatterns(config.Deny),
+	}
+}
+
+func normalizeMetadataPatterns(patterns []string) []string {
+	out := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if pattern = strings.ToLower(strings.TrimSpace(pattern)); pattern != "" {
+			out = append(out, pattern)
+		}
+	}
+	return out
+}
+
+func matchMetadataPattern(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if pattern == key {
+			return true
+		}
+	}
+	return false
+}
+
+// loadMetadataPolicy 返回当前请求生效的策略：网关级策略优先，其次全局策略，均未配置时返回 nil
+func loadMetadataPolicy(c *fiber.Ctx) *metadataPolicy {
+	if policy, ok := c.Locals(metadataPolicyKey{}).(*metadataPolicy); ok && policy != nil {
+		return policy
+	}
+	return currentMetadataPolicy.Load()
+}
+
+// outgoingMetadata 按策略构建透传 metadata，policy 为 nil 时全部 UserValues 原样透传
+// extra 与 UserValues 一同过滤，键重名时以 UserValues（网关写入）为准
+func outgoingMetadata(c *fiber.Ctx, policy *metadataPolicy, extra map[string]string) metadata.MD {
+	userValues := make(map[string]string)
+	if fctx := c.Context(); fctx != nil {
+		fctx.VisitUserValues(func(key []byte, value interface{}) {
+			userValues[string(key)] = cast.ToString(value)
+		})
+	}
+	for key, value := range extra {
+		if _, exists := userValues[key]; !exists {
+			userValues[key] = value
+		}
+	}
+	if policy == nil {
+		if len(userValues) == 0 {
+			return nil
+		}
+		return metadata.New(userValues)
+	}
+	return policy.apply(c, userValues)
+}
+
+func (p *metadataPolicy) apply(c *fiber.Ctx, userValues map[string]string) metadata.MD {
+	md := metadata.MD{}
+	total := 0
+	add := func(key, value string) {
+		if value == "" || len(md[key]) > 0 {
+			return
+		}
+		if p.config.MaxValueBytes > 0 && len(value) > p.config.MaxValueBytes {
+			return
+		}
+		size := len(key) + len(value)
+		if p.config.MaxTotalBytes > 0 && total+size > p.config.MaxTotalBytes {
+			return
+		}
+		total += size
+		md[key] = []string{value}
+	}
+
+	// 1. 请求头转换规则
+	for _, rule := range p.config.Headers {
+		add(rule.Key, c.Get(rule.Header))
+	}
+	// 2. 客户端 IP
+	if p.config.RealIPKey != "" {
+		add(p.config.RealIPKey, c.IP())
+	}
+	// 3. 按 Allow/Deny 过滤的 UserValues（按键名排序，保证超过总大小时丢弃结果确定）
+	keys := make([]string, 0, len(userValues))
+	for key := range userValues {
+		lower := strings.ToLower(key)
+		if matchMetadataPattern(p.deny, lower) {
+			continue
+		}
+		if len(p.allow) > 0 && !matchMetadataPattern(p.allow, lower) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		add(strings.ToLower(key), userValues[key])
+	}
+
+	if len(md) == 0 {
+		return nil
+	}
+	return md
+}
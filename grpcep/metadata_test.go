@@ -0,0 +1,104 @@
+package grpcep
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc/metadata"
+)
+
+func rpcMetadata(t *testing.T, app *fiber.App, headers map[string]string) metadata.MD {
+	t.Helper()
+	var md metadata.MD
+	h := &BaseHandler{}
+	app.Get("/", func(c *fiber.Ctx) error {
+		c.Context().SetUserValue("x-user-id", "u1")
+		c.Context().SetUserValue("x-tenant-id", "t1")
+		c.Context().SetUserValue("x-secret", "s3cr3t")
+		c.Context().SetUserValue("x-blob", strings.Repeat("b", 64))
+		md, _ = metadata.FromOutgoingContext(h.RPCCtx(c))
+		return nil
+	})
+	req := httptest.NewRequest("GET", "/", nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	return md
+}
+
+func TestMetadataPolicyFiltersAndTransforms(t *testing.T) {
+	app := fiber.New()
+	app.Use(MetadataPolicy(MetadataConfig{
+		Allow:         []string{"x-*"},
+		Deny:          []string{"X-Secret"},
+		Headers:       []MetadataHeader{{Header: "Authorization", Key: "x-user-token"}, {Header: "X-Canary"}},
+		RealIPKey:     "x-real-ip",
+		MaxValueBytes: 32,
+	}))
+	md := rpcMetadata(t, app, map[string]string{"Authorization": "Bearer abc", "X-Canary": "v2"})
+
+	if got := md.Get("x-user-token"); len(got) != 1 || got[0] != "Bearer abc" {
+		t.Fatalf("expected authorization transformed to x-user-token, got %v", got)
+	}
+	if got := md.Get("x-canary"); len(got) != 1 || got[0] != "v2" {
+		t.Fatalf("expected x-canary forwarded, got %v", got)
+	}
+	if got := md.Get("x-real-ip"); len(got) != 1 || got[0] == "" {
+		t.Fatalf("expected x-real-ip, got %v", got)
+	}
+	if got := md.Get("x-user-id"); len(got) != 1 || got[0] != "u1" {
+		t.Fatalf("expected allowed user value, got %v", got)
+	}
+	if len(md.Get("x-secret")) != 0 {
+		t.Fatal("expected denied key to be dropped")
+	}
+	if len(md.Get("x-blob")) != 0 {
+		t.Fatal("expected oversized value to be dropped")
+	}
+	if len(md.Get("authorization")) != 0 {
+		t.Fatal("expected raw authorization header not to be forwarded")
+	}
+}
+
+func TestMetadataPolicyTotalLimit(t *testing.T) {
+	app := fiber.New()
+	app.Use(MetadataPolicy(MetadataConfig{
+		Allow:         []string{"x-tenant-id", "x-user-id"},
+		MaxTotalBytes: len("x-tenant-id") + len("t1"),
+	}))
+	md := rpcMetadata(t, app, nil)
+	if got := md.Get("x-tenant-id"); len(got) != 1 || got[0] != "t1" {
+		t.Fatalf("expected first sorted key to fit, got %v", got)
+	}
+	if len(md.Get("x-user-id")) != 0 || len(md) != 1 {
+		t.Fatalf("expected keys beyond total limit to be dropped, got %v", md)
+	}
+}
+
+func TestSetMetadataPolicyGlobalAndGatewayOverride(t *testing.T) {
+	SetMetadataPolicy(&MetadataConfig{Allow: []string{"x-user-id"}})
+	defer SetMetadataPolicy(nil)
+
+	md := rpcMetadata(t, fiber.New(), nil)
+	if len(md) != 1 || len(md.Get("x-user-id")) != 1 {
+		t.Fatalf("expected global allowlist, got %v", md)
+	}
+
+	app := fiber.New()
+	app.Use(MetadataPolicy(MetadataConfig{Allow: []string{"x-tenant-id"}}))
+	md = rpcMetadata(t, app, nil)
+	if len(md) != 1 || len(md.Get("x-tenant-id")) != 1 {
+		t.Fatalf("expected gateway policy to override global, got %v", md)
+	}
+
+	SetMetadataPolicy(nil)
+	md = rpcMetadata(t, fiber.New(), nil)
+	if len(md.Get("x-secret")) != 1 {
+		t.Fatalf("expected all user values forwarded without policy, got %v", md)
+	}
+}
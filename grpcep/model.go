@@ -1,9 +1,11 @@
 package grpcep
 
 type JsonResponse struct {
-	Code       int32       `json:"code"`
-	Msg        string      `json:"msg"`
-	Data       interface{} `json:"data"`
-	HttpStatus int         `json:"-"`
-	RequestId  string      `json:"request_id"`
+	Code       int32        `json:"code"`
+	Msg        string       `json:"msg"`
+	Data       interface{}  `json:"data"`
+	Errors     []FieldError `json:"errors,omitempty"`
+	ErrorInfo  *ErrorInfo   `json:"error_info,omitempty"`
+	HttpStatus int          `json:"-"`
+	RequestId  string       `json:"request_id"`
 }
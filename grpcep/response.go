@@ -126,3 +126,43 @@ func setCommonRespFields(rv reflect.Value, code int32, msg string) {
 		msgField.SetString(msg)
 	}
 }
+
+// ResponseError 读取响应中的 CommonResp，业务失败（Code 非 SuccessCode 且非 0）时返回对应的 gerr 业务错误
+// 响应不含 CommonResp 或 CommonResp 为空时返回 nil
+func ResponseError(resp interface{}) error {
+	if IsNilValue(resp) {
+		return nil
+	}
+	rv := reflect.ValueOf(resp)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	field := rv.FieldByName(CommonRespKey)
+	if !field.IsValid() {
+		field = rv.FieldByName(CommonRespKeyV2)
+	}
+	if !field.IsValid() || field.Kind() != reflect.Ptr || field.IsNil() {
+		return nil
+	}
+	commonResp := field.Elem()
+	codeField := commonResp.FieldByName("Code")
+	if !codeField.IsValid() || !codeField.CanInt() {
+		return nil
+	}
+	code := int32(codeField.Int())
+	if code == 0 || code == SuccessCode {
+		return nil
+	}
+	msg := ""
+	if msgField := commonResp.FieldByName("Msg"); msgField.IsValid() && msgField.Kind() == reflect.String {
+		msg = msgField.String()
+	}
+	return gerr.NewBusiness(code, msg)
+}
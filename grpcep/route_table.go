@@ -0,0 +1,132 @@
+package grpcep
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// routeMissKey 路由表未命中标记（fasthttp UserValue），由分发器读取后立即移除
+const routeMissKey = "__quickgo_route_miss"
+
+// RouteTable 可热更新的声明式路由表
+// 每次 Update 先完整校验（配置错误、路径冲突、未知服务），再编译为独立的 fiber.App 并原子替换，
+// 校验失败时保留旧路由表；进行中的请求继续使用替换前的路由表完成
+type RouteTable struct {
+	handler   *BaseHandler
+	opts      RouteOptions
+	appConfig fiber.Config
+	// existing 返回宿主 App 上已注册的静态路由（用于冲突检测）
+	existing func() []fiber.Route
+
+	mu     sync.Mutex // 串行化 Update
+	active atomic.Pointer[routeSet]
+}
+
+// routeSet 编译后的路由表快照
+type routeSet struct {
+	routes  []RouteConfig
+	serve   fasthttp.RequestHandler
+	version uint64
+}
+
+// NewRouteTable 创建绑定到 app 的路由表，需通过 app.Use(table.Handler()) 挂载后生效
+func (h *BaseHandler) NewRouteTable(app *fiber.App, opts RouteOptions) (*RouteTable, error) {
+	if app == nil {
+		return nil, errors.New("app is nil")
+	}
+	if opts.Resolver == nil {
+		return nil, errors.New("route resolver is nil")
+	}
+	config := app.Config()
+	config.DisableStartupMessage = true
+	return &RouteTable{
+		handler:   h,
+		opts:      opts,
+		appConfig: config,
+		existing: func() []fiber.Route {
+			return app.GetRoutes(true)
+		},
+	}, nil
+}
+
+// Handler 返回路由表分发中间件：命中路由时由当前路由表处理，否则交给后续处理器
+func (t *RouteTable) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		set := t.active.Load()
+		if set == nil {
+			return c.Next()
+		}
+		fctx := c.Context()
+		set.serve(fctx)
+		if fctx.UserValue(routeMissKey) != nil {
+			fctx.RemoveUserValue(routeMissKey)
+			return c.Next()
+		}
+		return nil
+	}
+}
+
+// Update 校验并原子替换路由表，任意路由无效时返回错误且不影响当前路由表
+func (t *RouteTable) Update(routes []RouteConfig) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	compiled, err := t.handler.compileRoutes(routes, t.opts, t.existing())
+	if err != nil {
+		return err
+	}
+
+	app := fiber.New(t.appConfig)
+	for _, route := range compiled {
+		app.Add(route.method, route.config.Path, route.handlers...)
+	}
+	// 未命中任何声明式路由时打标记，交还宿主 App 继续处理
+	app.Use(func(c *fiber.Ctx) error {
+		c.Context().SetUserValue(routeMissKey, true)
+		return nil
+	})
+
+	var version uint64 = 1
+	old := t.active.Load()
+	if old != nil {
+		version = old.version + 1
+	}
+	t.active.Store(&routeSet{
+		routes:  append([]RouteConfig(nil), routes...),
+		serve:   app.Handler(),
+		version: version,
+	})
+
+	ctx := context.Background()
+	for _, route := range compiled {
+		logger.Info(ctx, "Declarative route registered: %s %s -> %s%s, auth=%s",
+			route.method, route.config.Path, route.config.Service, normalizeGRPCMethod(route.config.GRPCMethod), route.config.Auth)
+	}
+	logger.Info(ctx, "Declarative route table activated: version=%d, routes=%d", version, len(compiled))
+	return nil
+}
+
+// Routes 返回当前生效的路由配置
+func (t *RouteTable) Routes() []RouteConfig {
+	set := t.active.Load()
+	if set == nil {
+		return nil
+	}
+	return append([]RouteConfig(nil), set.routes...)
+}
+
+// Version 返回当前路由表版本（每次成功 Update 加 1，未加载时为 0）
+func (t *RouteTable) Version() uint64 {
+	set := t.active.Load()
+	if set == nil {
+		return 0
+	}
+	return set.version
+}
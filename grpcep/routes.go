@@ -0,0 +1,346 @@
+package grpcep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/team-dandelion/quickgo/gerr"
+	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/serializer"
+)
+
+// RouteConfig 声明式网关路由：将 HTTP 请求直接转发为后端 gRPC 一元调用，无需编写 handler
+// 请求消息由 JSON 请求体、查询参数、路径参数依次合并而成（后者覆盖前者），参数名匹配消息顶层字段的 proto 名或 JSON 名
+// 消息类型通过全局 proto 注册表解析，网关进程需导入对应的生成代码
+type RouteConfig struct {
+	// HTTP 路径，支持路径参数（如 /api/v1/users/:user_id）
+	Path string `json:"path" yaml:"path" toml:"path"`
+	// HTTP 方法（GET、POST、PUT、DELETE 等，默认 POST）
+	Method string `json:"method" yaml:"method" toml:"method"`
+	// 后端服务名称（交给 TunnelResolver 解析连接）
+	Service string `json:"service" yaml:"service" toml:"service"`
+	// gRPC 方法全名（如 /user.UserService/GetUser）
+	GRPCMethod string `json:"grpcMethod" yaml:"grpcMethod" toml:"grpcMethod"`
+	// 鉴权策略名称（为空表示无需鉴权），策略通过 RouteOptions.AuthPolicies 注册
+	Auth string `json:"auth" yaml:"auth" toml:"auth"`
+	// 调用超时（如 3s，为空表示不限制）
+	Timeout string `json:"timeout" yaml:"timeout" toml:"timeout"`
+	// 响应序列化方式（按服务端偏好排序，按 Accept 请求头协商），如 [json, msgpack, cbor]，默认 json
+	// 请求体 Content-Type 为其中的二进制格式时同样按该格式解码
+	Serializers []string `json:"serializers" yaml:"serializers" toml:"serializers"`
+}
+
+// RouteOptions 声明式路由注册选项
+type RouteOptions struct {
+	// 后端连接解析器（必填）
+	Resolver TunnelResolver
+	// 鉴权策略：名称 -> fiber 中间件（鉴权失败时中间件直接返回响应，成功时调用 c.Next()）
+	AuthPolicies map[string]fiber.Handler
+	// 后端服务校验（可选），返回错误时拒绝引用该服务的路由
+	ValidateService func(service string) error
+}
+
+// compiledRoute 校验通过的路由
+type compiledRoute struct {
+	config   RouteConfig
+	method   string
+	handlers []fiber.Handler
+}
+
+// RegisterRoutes 校验并注册声明式路由，任意路由配置错误时返回错误且不注册任何路由
+// 需要运行时增删路由时使用 RouteTable
+func (h *BaseHandler) RegisterRoutes(router fiber.Router, routes []RouteConfig, opts RouteOptions) error {
+	compiled, err := h.compileRoutes(routes, opts, nil)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	for _, route := range compiled {
+		router.Add(route.method, route.config.Path, route.handlers...)
+		logger.Info(ctx, "Declarative route registered: %s %s -> %s%s, auth=%s",
+			route.method, route.config.Path, route.config.Service, normalizeGRPCMethod(route.config.GRPCMethod), route.config.Auth)
+	}
+	return nil
+}
+
+// compileRoutes 校验全部路由（含路径冲突、后端服务）并生成处理链
+// existing 为已注册的静态路由，声明式路由不允许与其冲突
+func (h *BaseHandler) compileRoutes(routes []RouteConfig, opts RouteOptions, existing []fiber.Route) ([]compiledRoute, error) {
+	if opts.Resolver == nil {
+		return nil, fmt.Errorf("route resolver is nil")
+	}
+
+	occupied := make(map[string]string, len(routes)+len(existing))
+	for _, route := range existing {
+		occupied[routeKey(route.Method, route.Path)] = route.Path
+	}
+
+	compiled := make([]compiledRoute, 0, len(routes))
+	for i, route := range routes {
+		method, handlers, err := h.compileRoute(route, opts)
+		if err != nil {
+			return nil, fmt.Errorf("route[%d] %s %s: %w", i, route.Method, route.Path, err)
+		}
+		key := routeKey(method, route.Path)
+		if other, ok := occupied[key]; ok {
+			return nil, fmt.Errorf("route[%d] %s %s: conflicts with %s %s", i, method, route.Path, method, other)
+		}
+		occupied[key] = route.Path
+		compiled = append(compiled, compiledRoute{config: route, method: method, handlers: handlers})
+	}
+	return compiled, nil
+}
+
+// routeKey 路由冲突检测键：路径参数名与末尾 / 不影响匹配，按 fiber 默认规则忽略大小写
+func routeKey(method, routePath string) string {
+	segments := strings.Split(strings.Trim(routePath, "/"), "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			segments[i] = ":"
+			if strings.HasSuffix(segment, "?") {
+				segments[i] = ":?"
+			}
+		case strings.HasPrefix(segment, "*"), strings.HasPrefix(segment, "+"):
+			segments[i] = segment[:1]
+		default:
+			segments[i] = strings.ToLower(segment)
+		}
+	}
+	return method + " /" + strings.Join(segments, "/")
+}
+
+// compileRoute 校验路由配置并生成处理链
+func (h *BaseHandler) compileRoute(route RouteConfig, opts RouteOptions) (string, []fiber.Handler, error) {
+	if route.Path == "" {
+		return "", nil, fmt.Errorf("path is required")
+	}
+	if route.Service == "" {
+		return "", nil, fmt.Errorf("service is required")
+	}
+	if opts.ValidateService != nil {
+		if err := opts.ValidateService(route.Service); err != nil {
+			return "", nil, fmt.Errorf("invalid service %s: %w", route.Service, err)
+		}
+	}
+
+	httpMethod := strings.ToUpper(route.Method)
+	if httpMethod == "" {
+		httpMethod = fiber.MethodPost
+	}
+	if !isHTTPMethod(httpMethod) {
+		return "", nil, fmt.Errorf("unsupported http method: %s", route.Method)
+	}
+
+	fullMethod := normalizeGRPCMethod(route.GRPCMethod)
+	method, err := findUnaryMethod(fullMethod)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid grpcMethod: %w", err)
+	}
+
+	var timeout time.Duration
+	if route.Timeout != "" {
+		timeout, err = time.ParseDuration(route.Timeout)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to parse timeout %s: %w", route.Timeout, err)
+		}
+	}
+
+	var handlers []fiber.Handler
+	if route.Auth != "" {
+		policy, ok := opts.AuthPolicies[route.Auth]
+		if !ok || policy == nil {
+			return "", nil, fmt.Errorf("unknown auth policy: %s", route.Auth)
+		}
+		handlers = append(handlers, policy)
+	}
+	serializers, err := lookupSerializers(route.Serializers)
+	if err != nil {
+		return "", nil, err
+	}
+	handlers = append(handlers, h.routeHandler(route.Service, fullMethod, method, timeout, serializers, opts.Resolver))
+	return httpMethod, handlers, nil
+}
+
+// routeHandler 将 HTTP 请求转为 gRPC 调用，响应格式与 GRPCCall 一致
+func (h *BaseHandler) routeHandler(service, fullMethod string, method protoreflect.MethodDescriptor, timeout time.Duration, serializers []serializer.Serializer, resolve TunnelResolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		negotiateSerializer(c, serializers)
+		body, err := requestBodyJSON(c, serializers)
+		if err != nil {
+			return h.Response(c, JsonResponse{Code: ParamsErrCode, Msg: err.Error()}, err)
+		}
+		in, err := buildRouteRequest(c, body, method.Input())
+		if err != nil {
+			return h.Response(c, JsonResponse{Code: ParamsErrCode, Msg: err.Error()}, err)
+		}
+
+		ctx := h.RPCCtx(c)
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		conn, err := resolve(ctx, service)
+		if err != nil {
+			logger.Error(ctx, "Declarative route resolve failed: service=%s, error=%v", service, err)
+			return h.Response(c, JsonResponse{}, gerr.NewGErr(InternalErrCode, err.Error()))
+		}
+
+		out := dynamicpb.NewMessage(method.Output())
+		if err := conn.Invoke(ctx, fullMethod, in, out); err != nil {
+			logger.Error(ctx, "Declarative route call failed: method=%s, error=%v", fullMethod, err)
+			if status.Code(err) == codes.DeadlineExceeded {
+				return h.timeoutResponse(c)
+			}
+			if gErr := rpcGErr(err); gErr != nil {
+				return h.Response(c, JsonResponse{}, gErr)
+			}
+			if details := ParseErrorDetails(err); details != nil {
+				return h.errorDetailsResponse(c, details)
+			}
+			return h.Response(c, JsonResponse{}, gerr.NewGErr(InternalErrCode, status.Convert(err).Message()))
+		}
+
+		// 使用 proto 字段名，与生成代码的 json tag 保持一致，便于 ResponseDecorator 提取 common_resp
+		data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(out)
+		if err != nil {
+			return h.Response(c, JsonResponse{}, gerr.NewGErr(InternalErrCode, err.Error()))
+		}
+		return h.writeDecoratedResponse(c, data)
+	}
+}
+
+// buildRouteRequest 合并请求体（JSON）、查询参数与路径参数生成请求消息
+func buildRouteRequest(c *fiber.Ctx, body []byte, desc protoreflect.MessageDescriptor) (*dynamicpb.Message, error) {
+	fields := make(map[string]json.RawMessage)
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return nil, fmt.Errorf("invalid request body: %w", err)
+		}
+	}
+
+	var paramErr error
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		if paramErr != nil {
+			return
+		}
+		name := string(key)
+		field := lookupField(desc, name)
+		if field == nil {
+			return
+		}
+		values := c.Context().QueryArgs().PeekMulti(name)
+		raw, err := paramJSON(field, values)
+		if err != nil {
+			paramErr = fmt.Errorf("invalid query parameter %s: %w", name, err)
+			return
+		}
+		fields[string(field.Name())] = raw
+	})
+	if paramErr != nil {
+		return nil, paramErr
+	}
+
+	for name, value := range c.AllParams() {
+		field := lookupField(desc, name)
+		if field == nil {
+			continue
+		}
+		raw, err := paramJSON(field, [][]byte{[]byte(value)})
+		if err != nil {
+			return nil, fmt.Errorf("invalid path parameter %s: %w", name, err)
+		}
+		fields[string(field.Name())] = raw
+	}
+
+	msg := dynamicpb.NewMessage(desc)
+	if len(fields) == 0 {
+		return msg, nil
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	return msg, nil
+}
+
+// lookupField 按 proto 字段名或 JSON 名查找顶层字段
+func lookupField(desc protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	fields := desc.Fields()
+	if field := fields.ByName(protoreflect.Name(name)); field != nil {
+		return field
+	}
+	return fields.ByJSONName(name)
+}
+
+// paramJSON 将字符串参数转换为字段对应的 JSON 值（protojson 接受字符串形式的数字）
+func paramJSON(field protoreflect.FieldDescriptor, values [][]byte) (json.RawMessage, error) {
+	if field.IsMap() || field.Kind() == protoreflect.MessageKind || field.Kind() == protoreflect.GroupKind {
+		return nil, fmt.Errorf("message fields cannot be set from parameters")
+	}
+	convert := func(value string) (interface{}, error) {
+		switch field.Kind() {
+		case protoreflect.BoolKind:
+			return strconv.ParseBool(value)
+		case protoreflect.EnumKind:
+			if n, err := strconv.ParseInt(value, 10, 32); err == nil {
+				return n, nil
+			}
+			return value, nil
+		default:
+			return value, nil
+		}
+	}
+
+	if !field.IsList() {
+		if len(values) == 0 {
+			return nil, fmt.Errorf("missing value")
+		}
+		v, err := convert(utils.CopyString(string(values[len(values)-1])))
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(v)
+	}
+	list := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		v, err := convert(utils.CopyString(string(value)))
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return json.Marshal(list)
+}
+
+// normalizeGRPCMethod 补全 gRPC 方法名的前导 /
+func normalizeGRPCMethod(method string) string {
+	return "/" + strings.TrimPrefix(method, "/")
+}
+
+func isHTTPMethod(method string) bool {
+	switch method {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch,
+		fiber.MethodDelete, fiber.MethodOptions:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,276 @@
+package grpcep
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/team-dandelion/quickgo/serializer"
+)
+
+// routeTestConn 模拟后端健康检查服务：service 为 slow 时阻塞到超时
+type routeTestConn struct {
+	lastService string
+}
+
+func (c *routeTestConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	raw, err := proto.Marshal(args.(proto.Message))
+	if err != nil {
+		return err
+	}
+	req := &grpc_health_v1.HealthCheckRequest{}
+	if err := proto.Unmarshal(raw, req); err != nil {
+		return err
+	}
+	c.lastService = req.GetService()
+
+	if req.GetService() == "slow" {
+		<-ctx.Done()
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	raw, _ = proto.Marshal(&grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING})
+	return proto.Unmarshal(raw, reply.(proto.Message))
+}
+
+func (c *routeTestConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, status.Error(codes.Unimplemented, "streams are not supported")
+}
+
+func newRouteTestApp(t *testing.T, conn *routeTestConn, routes []RouteConfig) *fiber.App {
+	t.Helper()
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	err := (&BaseHandler{}).RegisterRoutes(app, routes, RouteOptions{
+		Resolver: func(ctx context.Context, target string) (grpc.ClientConnInterface, error) {
+			return conn, nil
+		},
+		AuthPolicies: map[string]fiber.Handler{
+			"token": func(c *fiber.Ctx) error {
+				if c.Get(fiber.HeaderAuthorization) != "Bearer ok" {
+					return c.Status(fiber.StatusUnauthorized).SendString("unauthorized")
+				}
+				return c.Next()
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterRoutes failed: %v", err)
+	}
+	return app
+}
+
+func TestRegisterRoutesMapsParamsToRequest(t *testing.T) {
+	conn := &routeTestConn{}
+	app := newRouteTestApp(t, conn, []RouteConfig{
+		{Path: "/health/:service", Method: "get", Service: "user-service", GRPCMethod: "grpc.health.v1.Health/Check"},
+		{Path: "/health", Service: "user-service", GRPCMethod: "/grpc.health.v1.Health/Check", Auth: "token", Timeout: "20ms"},
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/health/orders?service=ignored", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	var out map[string]interface{}
+	if err := json.Unmarshal(body, &out); err != nil {
+		t.Fatalf("invalid response %s: %v", body, err)
+	}
+	if conn.lastService != "orders" {
+		t.Fatalf("expected path parameter to override query, got %q", conn.lastService)
+	}
+	if out["code"] != float64(SuccessCode) || !strings.Contains(string(body), "SERVING") {
+		t.Fatalf("unexpected response: %s", body)
+	}
+
+	req := httptest.NewRequest("POST", "/health", strings.NewReader(`{"service":"billing"}`))
+	resp, _ = app.Test(req)
+	if resp.StatusCode != fiber.StatusUnauthorized {
+		t.Fatalf("expected auth policy to reject request, got %d", resp.StatusCode)
+	}
+
+	req = httptest.NewRequest("POST", "/health", strings.NewReader(`{"service":"billing"}`))
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer ok")
+	if _, err := app.Test(req); err != nil || conn.lastService != "billing" {
+		t.Fatalf("expected body to populate request, got %q, %v", conn.lastService, err)
+	}
+
+	req = httptest.NewRequest("POST", "/health", strings.NewReader(`{"service":"slow"}`))
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer ok")
+	start := time.Now()
+	resp, _ = app.Test(req, int(time.Second/time.Millisecond))
+	body, _ = io.ReadAll(resp.Body)
+	if time.Since(start) > 500*time.Millisecond || resp.StatusCode != fiber.StatusGatewayTimeout || !strings.Contains(string(body), `"code":50400`) {
+		t.Fatalf("expected route timeout to fail the call, got %s", body)
+	}
+
+	req = httptest.NewRequest("POST", "/health", strings.NewReader(`not json`))
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer ok")
+	resp, _ = app.Test(req)
+	body, _ = io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), `"code":40001`) {
+		t.Fatalf("expected invalid body to be rejected, got %s", body)
+	}
+}
+
+func TestRegisterRoutesValidation(t *testing.T) {
+	resolver := func(ctx context.Context, target string) (grpc.ClientConnInterface, error) { return nil, nil }
+	valid := RouteConfig{Path: "/health", Service: "user-service", GRPCMethod: "/grpc.health.v1.Health/Check"}
+
+	cases := map[string]func(r *RouteConfig){
+		"unknown method":      func(r *RouteConfig) { r.GRPCMethod = "/grpc.health.v1.Health/Missing" },
+		"streaming method":    func(r *RouteConfig) { r.GRPCMethod = "/grpc.health.v1.Health/Watch" },
+		"invalid http method": func(r *RouteConfig) { r.Method = "FETCH" },
+		"unknown auth policy": func(r *RouteConfig) { r.Auth = "admin" },
+		"invalid timeout":     func(r *RouteConfig) { r.Timeout = "soon" },
+		"missing service":     func(r *RouteConfig) { r.Service = "" },
+	}
+	for name, mutate := range cases {
+		route := valid
+		mutate(&route)
+		app := fiber.New()
+		if err := (&BaseHandler{}).RegisterRoutes(app, []RouteConfig{valid, route}, RouteOptions{Resolver: resolver}); err == nil {
+			t.Fatalf("%s: expected RegisterRoutes to fail", name)
+		}
+		if len(app.GetRoutes()) != 0 {
+			t.Fatalf("%s: expected no routes to be registered on failure", name)
+		}
+	}
+}
+
+func TestRouteTableUpdateSwapsRoutes(t *testing.T) {
+	conn := &routeTestConn{}
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/static", func(c *fiber.Ctx) error { return c.SendString("static") })
+
+	table, err := (&BaseHandler{}).NewRouteTable(app, RouteOptions{
+		Resolver: func(ctx context.Context, target string) (grpc.ClientConnInterface, error) {
+			return conn, nil
+		},
+		ValidateService: func(service string) error {
+			if service != "user-service" {
+				return errors.New("not configured")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRouteTable failed: %v", err)
+	}
+	app.Use(table.Handler())
+	app.Get("/fallback", func(c *fiber.Ctx) error { return c.SendString("fallback") })
+
+	get := func(target string) (int, string) {
+		resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+		if err != nil {
+			t.Fatalf("request %s failed: %v", target, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	check := RouteConfig{Path: "/health/:service", Method: "GET", Service: "user-service", GRPCMethod: "/grpc.health.v1.Health/Check"}
+	if err := table.Update([]RouteConfig{check}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if _, body := get("/health/orders"); !strings.Contains(body, "SERVING") || conn.lastService != "orders" {
+		t.Fatalf("expected declarative route to serve request, got %s", body)
+	}
+	if _, body := get("/fallback"); body != "fallback" {
+		t.Fatalf("expected unmatched request to fall through, got %s", body)
+	}
+
+	invalid := [][]RouteConfig{
+		{check, {Path: "/health/:name", Method: "GET", Service: "user-service", GRPCMethod: "/grpc.health.v1.Health/Check"}},
+		{{Path: "/STATIC/", Method: "GET", Service: "user-service", GRPCMethod: "/grpc.health.v1.Health/Check"}},
+		{{Path: "/orders", Service: "order-service", GRPCMethod: "/grpc.health.v1.Health/Check"}},
+	}
+	for i, routes := range invalid {
+		if err := table.Update(routes); err == nil {
+			t.Fatalf("invalid[%d]: expected Update to fail", i)
+		}
+	}
+	if table.Version() != 1 || len(table.Routes()) != 1 {
+		t.Fatalf("expected failed updates to keep current table, got version=%d routes=%v", table.Version(), table.Routes())
+	}
+
+	moved := check
+	moved.Path = "/v2/health/:service"
+	if err := table.Update([]RouteConfig{moved}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if status, _ := get("/health/orders"); status != fiber.StatusNotFound {
+		t.Fatalf("expected removed route to return 404, got %d", status)
+	}
+	if _, body := get("/v2/health/billing"); !strings.Contains(body, "SERVING") || conn.lastService != "billing" {
+		t.Fatalf("expected new route to serve request, got %s", body)
+	}
+	if _, body := get("/static"); body != "static" || table.Version() != 2 {
+		t.Fatalf("expected static route to keep working, got %s (version %d)", body, table.Version())
+	}
+}
+
+func TestRegisterRoutesNegotiatesSerializer(t *testing.T) {
+	conn := &routeTestConn{}
+	app := newRouteTestApp(t, conn, []RouteConfig{
+		{Path: "/health", Service: "user-service", GRPCMethod: "/grpc.health.v1.Health/Check", Serializers: []string{serializer.JSON, serializer.Msgpack, serializer.CBOR}},
+	})
+	msgpack := serializer.Must(serializer.Msgpack)
+	cbor := serializer.Must(serializer.CBOR)
+
+	body, _ := cbor.Marshal(map[string]interface{}{"service": "billing"})
+	req := httptest.NewRequest("POST", "/health", strings.NewReader(string(body)))
+	req.Header.Set(fiber.HeaderContentType, "application/cbor")
+	req.Header.Set(fiber.HeaderAccept, "application/x-msgpack, application/json;q=0.5")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if conn.lastService != "billing" {
+		t.Fatalf("expected cbor body to populate request, got %q", conn.lastService)
+	}
+	if ct := resp.Header.Get(fiber.HeaderContentType); ct != "application/msgpack" || resp.Header.Get(fiber.HeaderVary) != fiber.HeaderAccept {
+		t.Fatalf("unexpected headers: content-type=%q vary=%q", ct, resp.Header.Get(fiber.HeaderVary))
+	}
+	raw, _ := io.ReadAll(resp.Body)
+	var out JsonResponse
+	if err := msgpack.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("invalid msgpack response: %v", err)
+	}
+	if out.Code != SuccessCode || out.Data.(map[string]interface{})["status"] != "SERVING" {
+		t.Fatalf("unexpected response: %+v", out)
+	}
+
+	// 错误响应同样使用协商出的格式
+	req = httptest.NewRequest("POST", "/health", strings.NewReader(`not cbor`))
+	req.Header.Set(fiber.HeaderContentType, "application/cbor")
+	req.Header.Set(fiber.HeaderAccept, "application/cbor")
+	resp, _ = app.Test(req)
+	raw, _ = io.ReadAll(resp.Body)
+	out = JsonResponse{}
+	if err := cbor.Unmarshal(raw, &out); err != nil || out.Code != ParamsErrCode {
+		t.Fatalf("expected cbor encoded params error, got %+v (%v)", out, err)
+	}
+
+	resp, _ = app.Test(httptest.NewRequest("POST", "/health", nil))
+	if ct := resp.Header.Get(fiber.HeaderContentType); ct != fiber.MIMEApplicationJSON {
+		t.Fatalf("expected JSON by default, got %q", ct)
+	}
+
+	err = (&BaseHandler{}).RegisterRoutes(fiber.New(), []RouteConfig{
+		{Path: "/x", Service: "user-service", GRPCMethod: "/grpc.health.v1.Health/Check", Serializers: []string{"yaml"}},
+	}, RouteOptions{Resolver: func(ctx context.Context, target string) (grpc.ClientConnInterface, error) { return conn, nil }})
+	if err == nil || !strings.Contains(err.Error(), "yaml") {
+		t.Fatalf("expected unknown serializer to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package grpcep
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/team-dandelion/quickgo/json"
+	"github.com/team-dandelion/quickgo/serializer"
+)
+
+// responseSerializerKey 协商出的响应序列化方式（fiber Locals）
+const responseSerializerKey = "__quickgo_response_serializer"
+
+// SerializerMiddleware 按 Accept 请求头从 names（按服务端偏好排序）中协商响应序列化方式，
+// 后续 GRPCCall / Response 使用协商结果编码响应；names 为空时使用 JSON
+func SerializerMiddleware(names ...string) (fiber.Handler, error) {
+	allowed, err := lookupSerializers(names)
+	if err != nil {
+		return nil, err
+	}
+	return func(c *fiber.Ctx) error {
+		negotiateSerializer(c, allowed)
+		return c.Next()
+	}, nil
+}
+
+// lookupSerializers 解析序列化方式名称，为空时使用 JSON
+func lookupSerializers(names []string) ([]serializer.Serializer, error) {
+	if len(names) == 0 {
+		names = []string{serializer.JSON}
+	}
+	allowed, err := serializer.Lookup(names)
+	if err != nil {
+		return nil, fmt.Errorf("invalid serializers: %w", err)
+	}
+	return allowed, nil
+}
+
+// negotiateSerializer 协商响应序列化方式并记录到请求上下文
+func negotiateSerializer(c *fiber.Ctx, allowed []serializer.Serializer) serializer.Serializer {
+	s, _ := serializer.Negotiate(c.Get(fiber.HeaderAccept), allowed)
+	if len(allowed) > 1 {
+		c.Vary(fiber.HeaderAccept)
+	}
+	c.Locals(responseSerializerKey, s)
+	return s
+}
+
+// binaryResponseSerializer 返回协商出的非 JSON 序列化方式，未协商或协商结果为 JSON 时返回 nil
+func binaryResponseSerializer(c *fiber.Ctx) serializer.Serializer {
+	s, ok := c.Locals(responseSerializerKey).(serializer.Serializer)
+	if !ok || s == nil || isJSONSerializer(s) {
+		return nil
+	}
+	return s
+}
+
+func isJSONSerializer(s serializer.Serializer) bool {
+	return s.ContentType() == fiber.MIMEApplicationJSON
+}
+
+// writeResponse 使用协商出的序列化方式写入响应（默认 JSON）
+func (h *BaseHandler) writeResponse(c *fiber.Ctx, resp JsonResponse) error {
+	env := loadEnvelope()
+	s := binaryResponseSerializer(c)
+	if s == nil {
+		return c.JSON(env.value(resp))
+	}
+	data, err := s.Marshal(env.value(resp))
+	if err != nil {
+		return c.JSON(env.value(JsonResponse{Code: InternalErrCode, Msg: InternalErrDesc, RequestId: resp.RequestId}))
+	}
+	c.Set(fiber.HeaderContentType, s.ContentType())
+	return c.Send(data)
+}
+
+// requestBodyJSON 返回 JSON 形式的请求体：Content-Type 为 allowed 中的二进制序列化方式（如 msgpack、cbor）时转换为 JSON
+func requestBodyJSON(c *fiber.Ctx, allowed []serializer.Serializer) ([]byte, error) {
+	body := c.Body()
+	if len(body) == 0 {
+		return body, nil
+	}
+	s, ok := serializer.ForContentType(c.Get(fiber.HeaderContentType), allowed)
+	if !ok || isJSONSerializer(s) {
+		return body, nil
+	}
+	var fields map[string]interface{}
+	if err := s.Unmarshal(body, &fields); err != nil {
+		return nil, fmt.Errorf("invalid %s request body: %w", s.Name(), err)
+	}
+	return json.Marshal(fields)
+}
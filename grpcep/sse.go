@@ -0,0 +1,79 @@
+package grpcep
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/team-dandelion/quickgo/http"
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+type streamCancelKey struct{}
+
+// SSEErrorEvent 流异常结束时发送的 error 事件数据
+type SSEErrorEvent struct {
+	Code      int32  `json:"code"`
+	Msg       string `json:"msg"`
+	RequestId string `json:"requestId"`
+}
+
+// RPCStreamCtx 创建用于发起 gRPC 服务端流的可取消 context（在 RPCCtx 基础上）
+// 配合 StreamToSSE 使用时，客户端断开后该 context 被取消，gRPC 流随之结束
+func (h *BaseHandler) RPCStreamCtx(c *fiber.Ctx) context.Context {
+	ctx, cancel := context.WithCancel(h.RPCCtx(c))
+	return context.WithValue(ctx, streamCancelKey{}, cancel)
+}
+
+// StreamToSSE 将 gRPC 服务端流以 SSE 事件转发给客户端
+// recv 通常为 stream.Recv：每条消息编码为 JSON 作为一个事件（id 自增）发送，recv 返回 io.EOF 时发送 close 事件，
+// 返回其他错误时发送 error 事件；按 config 发送心跳。ctx 为创建流时使用的 context，
+// 由 RPCStreamCtx 创建时客户端断开或转发结束后会取消该 context 以释放 gRPC 流
+func StreamToSSE(ctx context.Context, c *fiber.Ctx, recv func() (interface{}, error), config ...http.SSEConfig) error {
+	cancel, ok := ctx.Value(streamCancelKey{}).(context.CancelFunc)
+	if !ok {
+		cancel = func() {}
+	}
+	requestID := http.GetTraceID(c)
+
+	return http.SSE(c, func(w *http.SSEWriter) error {
+		defer cancel()
+		stop := context.AfterFunc(w.Context(), cancel)
+		defer stop()
+
+		for id := 1; ; id++ {
+			msg, err := recv()
+			if errors.Is(err, io.EOF) {
+				return w.Send(http.SSEEvent{Event: "close", Data: `{"close":true}`})
+			}
+			if err != nil {
+				if w.Context().Err() != nil {
+					// 客户端已断开，流因 context 取消而结束
+					return nil
+				}
+				logger.Error(ctx, "stream_to_sse receive error: %v", err)
+				return w.SendJSON("", "error", newSSEErrorEvent(err, requestID))
+			}
+			if err = w.SendJSON(strconv.Itoa(id), "", msg); err != nil {
+				return err
+			}
+		}
+	}, config...)
+}
+
+// newSSEErrorEvent 将 GErr 或 gRPC status 转换为 error 事件数据
+func newSSEErrorEvent(err error, requestID string) SSEErrorEvent {
+	if gErr := rpcGErr(err); gErr != nil {
+		return SSEErrorEvent{Code: gErr.GetCode(), Msg: gErr.GetMsg(), RequestId: requestID}
+	}
+	if st, ok := status.FromError(err); ok && st.Code() != codes.Unknown {
+		return SSEErrorEvent{Code: int32(st.Code()), Msg: st.Message(), RequestId: requestID}
+	}
+	code, msg := (&BaseHandler{}).msgAndCodeParser(0, "", err)
+	return SSEErrorEvent{Code: code, Msg: msg, RequestId: requestID}
+}
@@ -0,0 +1,88 @@
+package grpcep
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/team-dandelion/quickgo/gerr"
+)
+
+func TestStreamToSSEForwardsMessages(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/stream", func(c *fiber.Ctx) error {
+		messages := []interface{}{map[string]string{"content": "a"}, map[string]string{"content": "b"}}
+		return StreamToSSE(context.Background(), c, func() (interface{}, error) {
+			if len(messages) == 0 {
+				return nil, io.EOF
+			}
+			msg := messages[0]
+			messages = messages[1:]
+			return msg, nil
+		})
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/stream", nil), -1)
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	want := "id: 1\ndata: {\"content\":\"a\"}\n\nid: 2\ndata: {\"content\":\"b\"}\n\nevent: close\ndata: {\"close\":true}\n\n"
+	if string(body) != want {
+		t.Fatalf("unexpected body %q, want %q", body, want)
+	}
+}
+
+func TestStreamToSSESendsErrorEvent(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/stream", func(c *fiber.Ctx) error {
+		return StreamToSSE(context.Background(), c, func() (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "missing")
+		})
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/stream", nil), -1)
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.HasPrefix(string(body), "event: error\ndata: {\"code\":5,\"msg\":\"missing\"") {
+		t.Fatalf("unexpected body %q", body)
+	}
+}
+
+func TestNewSSEErrorEventUsesGErrCode(t *testing.T) {
+	event := newSSEErrorEvent(gerr.NewGErr(4001, "bad"), "trace")
+	if event.Code != 4001 || event.Msg != "bad" || event.RequestId != "trace" {
+		t.Fatalf("unexpected event %+v", event)
+	}
+}
+
+func TestRPCStreamCtxCancelledWhenForwardingEnds(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	streamCtx := make(chan context.Context, 1)
+	app.Get("/stream", func(c *fiber.Ctx) error {
+		ctx := (&BaseHandler{}).RPCStreamCtx(c)
+		streamCtx <- ctx
+		return StreamToSSE(ctx, c, func() (interface{}, error) {
+			return nil, errors.New("boom")
+		})
+	})
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/stream", nil), -1); err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	select {
+	case <-(<-streamCtx).Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected stream context to be canceled")
+	}
+}
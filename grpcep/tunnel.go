@@ -0,0 +1,193 @@
+package grpcep
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/team-dandelion/quickgo/http"
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// gRPC HTTP 隧道协议使用的请求/响应头
+const (
+	// TunnelTargetHeader 目标服务名称（网关据此选择后端连接）
+	TunnelTargetHeader = "X-Grpc-Target"
+	// TunnelMetadataPrefix gRPC metadata 以该前缀的请求头传递
+	TunnelMetadataPrefix = "Grpc-Metadata-"
+	// TunnelStatusHeader gRPC 状态码
+	TunnelStatusHeader = "Grpc-Status"
+	// TunnelMessageHeader gRPC 错误信息（URL 编码）
+	TunnelMessageHeader = "Grpc-Message"
+	// TunnelStatusDetailsHeader base64 编码的 google.rpc.Status（包含错误详情）
+	TunnelStatusDetailsHeader = "Grpc-Status-Details-Bin"
+	// TunnelTimeoutHeader 调用剩余时间（毫秒），网关经 http.RequestContext 还原为下游调用的截止时间
+	TunnelTimeoutHeader = http.RequestTimeoutHeader
+)
+
+// TunnelResolver 根据目标服务名称获取后端 gRPC 连接
+type TunnelResolver func(ctx context.Context, target string) (grpc.ClientConnInterface, error)
+
+// GRPCTunnel 返回 gRPC-over-HTTP 隧道处理器，供无法直连 gRPC 端口的客户端通过网关转发一元调用
+// 路由需以通配符注册，示例：app.Post("/grpc-tunnel/*", h.GRPCTunnel(resolver))
+// 请求路径为 /grpc-tunnel/{package.Service}/{Method}，请求体为 protojson；
+// 消息类型通过全局 proto 注册表解析，网关进程需导入对应的生成代码
+func (h *BaseHandler) GRPCTunnel(resolve TunnelResolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		fullMethod := "/" + strings.TrimPrefix(c.Params("*"), "/")
+		method, err := findUnaryMethod(fullMethod)
+		if err != nil {
+			return h.tunnelError(c, err)
+		}
+
+		in := dynamicpb.NewMessage(method.Input())
+		if body := c.Body(); len(body) > 0 {
+			if err := protojson.Unmarshal(body, in); err != nil {
+				return h.tunnelError(c, status.Errorf(codes.InvalidArgument, "invalid request body: %v", err))
+			}
+		}
+
+		// 隧道请求头与 UserValues 一同经过 metadata 策略（Allow/Deny 与大小限制）
+		ctx := h.rpcCtx(c, tunnelMetadata(c))
+
+		if resolve == nil {
+			return h.tunnelError(c, status.Error(codes.Unavailable, "tunnel resolver is nil"))
+		}
+		conn, err := resolve(ctx, c.Get(TunnelTargetHeader))
+		if err != nil {
+			return h.tunnelError(c, status.Errorf(codes.Unavailable, "resolve target %q: %v", c.Get(TunnelTargetHeader), err))
+		}
+
+		out := dynamicpb.NewMessage(method.Output())
+		if err := conn.Invoke(ctx, fullMethod, in, out); err != nil {
+			logger.Error(ctx, "gRPC tunnel call failed: method=%s, error=%v", fullMethod, err)
+			return h.tunnelError(c, err)
+		}
+
+		data, err := protojson.Marshal(out)
+		if err != nil {
+			return h.tunnelError(c, status.Errorf(codes.Internal, "marshal reply: %v", err))
+		}
+		c.Set(TunnelStatusHeader, "0")
+		return c.JSON(JsonResponse{
+			Code:      SuccessCode,
+			Msg:       SuccessDesc,
+			Data:      json.RawMessage(data),
+			RequestId: http.GetTraceID(c),
+		})
+	}
+}
+
+// findUnaryMethod 在全局 proto 注册表中查找一元方法
+func findUnaryMethod(fullMethod string) (protoreflect.MethodDescriptor, error) {
+	idx := strings.LastIndex(fullMethod, "/")
+	if idx <= 0 || idx == len(fullMethod)-1 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid method path: %s", fullMethod)
+	}
+	serviceName, methodName := fullMethod[1:idx], fullMethod[idx+1:]
+
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil, status.Errorf(codes.Unimplemented, "unknown service: %s", serviceName)
+	}
+	service, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "unknown service: %s", serviceName)
+	}
+	method := service.Methods().ByName(protoreflect.Name(methodName))
+	if method == nil {
+		return nil, status.Errorf(codes.Unimplemented, "unknown method: %s", fullMethod)
+	}
+	if method.IsStreamingClient() || method.IsStreamingServer() {
+		return nil, status.Errorf(codes.Unimplemented, "streaming method is not supported over http tunnel: %s", fullMethod)
+	}
+	return method, nil
+}
+
+// ReservedTunnelMetadata 隧道请求不得设置的 metadata 键（支持 x-* 前缀通配）：
+// 网关写入的身份（x-user-*）、客户端 IP 与 gRPC 保留键，客户端通过 Grpc-Metadata-* 请求头携带时被丢弃
+var ReservedTunnelMetadata = []string{"x-user-*", "x-real-ip", "grpc-*"}
+
+// tunnelMetadata 收集 Grpc-Metadata-* 请求头（同名请求头取第一个值），保留键被丢弃
+func tunnelMetadata(c *fiber.Ctx) map[string]string {
+	prefix := strings.ToLower(TunnelMetadataPrefix)
+	reserved := normalizeMetadataPatterns(ReservedTunnelMetadata)
+	values := make(map[string]string)
+	c.Request().Header.VisitAll(func(key, value []byte) {
+		name := strings.ToLower(string(key))
+		if !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+			return
+		}
+		name = name[len(prefix):]
+		if _, exists := values[name]; exists || matchMetadataPattern(reserved, name) {
+			return
+		}
+		values[name] = string(value)
+	})
+	return values
+}
+
+// tunnelError 输出隧道错误：状态码与详情写入响应头，响应体保持 grpcep JSON 格式
+func (h *BaseHandler) tunnelError(c *fiber.Ctx, err error) error {
+	EncodeTunnelStatus(err, func(key, value string) { c.Set(key, value) })
+
+	st := status.Convert(err)
+	code := int32(InternalErrCode)
+	if st.Code() == codes.InvalidArgument {
+		code = ParamsErrCode
+	}
+	return c.JSON(JsonResponse{
+		Code:      code,
+		Msg:       st.Message(),
+		RequestId: http.GetTraceID(c),
+	})
+}
+
+// EncodeTunnelStatus 将 gRPC 错误编码为隧道响应头
+func EncodeTunnelStatus(err error, setHeader func(key, value string)) {
+	st := status.Convert(err)
+	setHeader(TunnelStatusHeader, strconv.Itoa(int(st.Code())))
+	setHeader(TunnelMessageHeader, url.PathEscape(st.Message()))
+	if raw, marshalErr := proto.Marshal(st.Proto()); marshalErr == nil {
+		setHeader(TunnelStatusDetailsHeader, base64.StdEncoding.EncodeToString(raw))
+	}
+}
+
+// DecodeTunnelStatus 从隧道响应头还原 gRPC 状态错误，成功（无状态或状态为 0）时返回 nil
+func DecodeTunnelStatus(getHeader func(key string) string) error {
+	codeStr := getHeader(TunnelStatusHeader)
+	if codeStr == "" || codeStr == "0" {
+		return nil
+	}
+	if detailsBin := getHeader(TunnelStatusDetailsHeader); detailsBin != "" {
+		if raw, err := base64.StdEncoding.DecodeString(detailsBin); err == nil {
+			st := &spb.Status{}
+			if err := proto.Unmarshal(raw, st); err == nil {
+				return status.ErrorProto(st)
+			}
+		}
+	}
+	code, err := strconv.Atoi(codeStr)
+	if err != nil {
+		return status.Errorf(codes.Unknown, "invalid grpc status %q", codeStr)
+	}
+	message := getHeader(TunnelMessageHeader)
+	if unescaped, err := url.PathUnescape(message); err == nil {
+		message = unescaped
+	}
+	return status.Error(codes.Code(code), message)
+}
@@ -0,0 +1,251 @@
+package handover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// EnvListenFDs 父进程传递给新进程的监听地址列表（逗号分隔），按顺序对应从 3 开始的文件描述符
+const EnvListenFDs = "QUICKGO_LISTEN_FDS"
+
+// 继承的第一个文件描述符（0/1/2 为标准输入输出）
+const listenFDStart = 3
+
+// Config 监听套接字交接配置
+type Config struct {
+	// 新建监听时设置 SO_REUSEPORT，允许新旧进程同时绑定同一端口（仅 Linux/BSD/macOS）
+	ReusePort bool `json:"reusePort" yaml:"reusePort" toml:"reusePort"`
+	// 收到 SIGUSR2 时以当前参数启动新版本进程并传递监听套接字，随后当前进程优雅退出（仅 Unix）
+	UpgradeOnSignal bool `json:"upgradeOnSignal" yaml:"upgradeOnSignal" toml:"upgradeOnSignal"`
+}
+
+// Manager 管理继承的与新建的监听套接字
+type Manager struct {
+	mu        sync.Mutex
+	reusePort bool
+	// 继承自父进程、尚未被使用的监听（地址 -> 文件）
+	inherited map[string]*os.File
+	// 当前进程正在使用的监听（按创建顺序）
+	active []*trackedListener
+}
+
+var (
+	defaultManager     *Manager
+	defaultManagerOnce sync.Once
+)
+
+// Default 获取默认 Manager（首次调用时从环境变量读取继承的监听）
+func Default() *Manager {
+	defaultManagerOnce.Do(func() {
+		defaultManager = newManager(os.Getenv(EnvListenFDs), listenFDStart)
+		// 避免继续传递给本进程启动的其他子进程
+		_ = os.Unsetenv(EnvListenFDs)
+	})
+	return defaultManager
+}
+
+// Configure 应用配置到默认 Manager
+func Configure(config *Config) {
+	if config == nil {
+		return
+	}
+	Default().SetReusePort(config.ReusePort)
+}
+
+// Listen 使用默认 Manager 监听地址
+func Listen(network, address string) (net.Listener, error) {
+	return Default().Listen(network, address)
+}
+
+// newManager 按地址列表与起始文件描述符解析继承的监听
+func newManager(addresses string, fdStart int) *Manager {
+	m := &Manager{inherited: make(map[string]*os.File)}
+	if addresses == "" {
+		return m
+	}
+	for i, address := range strings.Split(addresses, ",") {
+		if address == "" {
+			continue
+		}
+		fd := fdStart + i
+		m.inherited[address] = os.NewFile(uintptr(fd), "listener:"+address)
+	}
+	logger.Info(context.Background(), "Inherited %d listener(s) from parent process: %s", len(m.inherited), addresses)
+	return m
+}
+
+// SetReusePort 设置新建监听时是否启用 SO_REUSEPORT
+func (m *Manager) SetReusePort(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reusePort = enabled
+}
+
+// Listen 监听地址，优先使用父进程传递的同地址监听，否则新建（按配置启用 SO_REUSEPORT）
+func (m *Manager) Listen(network, address string) (net.Listener, error) {
+	if !strings.HasPrefix(network, "tcp") {
+		return net.Listen(network, address)
+	}
+
+	m.mu.Lock()
+	file := m.inherited[address]
+	delete(m.inherited, address)
+	reusePort := m.reusePort
+	m.mu.Unlock()
+
+	var (
+		listener net.Listener
+		err      error
+	)
+	if file != nil {
+		listener, err = net.FileListener(file)
+		_ = file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to use inherited listener for %s: %w", address, err)
+		}
+		logger.Info(context.Background(), "Using inherited listener: %s", address)
+	} else {
+		lc := net.ListenConfig{}
+		if reusePort {
+			lc.Control = reusePortControl
+		}
+		listener, err = lc.Listen(context.Background(), network, address)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tcpListener, ok := listener.(*net.TCPListener)
+	if !ok {
+		return listener, nil
+	}
+	tracked := &trackedListener{TCPListener: tcpListener, address: address, manager: m}
+	m.mu.Lock()
+	m.active = append(m.active, tracked)
+	m.mu.Unlock()
+	return tracked, nil
+}
+
+// CloseUnused 关闭未被使用的继承监听（新版本不再监听的地址），返回关闭的地址
+func (m *Manager) CloseUnused() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	addresses := make([]string, 0, len(m.inherited))
+	for address, file := range m.inherited {
+		_ = file.Close()
+		addresses = append(addresses, address)
+	}
+	m.inherited = make(map[string]*os.File)
+	return addresses
+}
+
+// Files 复制当前使用中的监听文件描述符，返回文件与对应地址（调用方负责关闭文件）
+func (m *Manager) Files() ([]*os.File, []string, error) {
+	m.mu.Lock()
+	active := append([]*trackedListener(nil), m.active...)
+	m.mu.Unlock()
+
+	files := make([]*os.File, 0, len(active))
+	addresses := make([]string, 0, len(active))
+	for _, l := range active {
+		file, err := l.File()
+		if err != nil {
+			for _, f := range files {
+				_ = f.Close()
+			}
+			return nil, nil, fmt.Errorf("failed to duplicate listener %s: %w", l.address, err)
+		}
+		files = append(files, file)
+		addresses = append(addresses, l.address)
+	}
+	return files, addresses, nil
+}
+
+// StartProcess 启动新进程并传递当前使用中的监听，新进程通过 Listen 直接复用同一套接字
+// path 为空时使用当前可执行文件，args 为空时使用当前进程参数
+func (m *Manager) StartProcess(path string, args []string) (*os.Process, error) {
+	if path == "" {
+		executable, err := os.Executable()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve executable: %w", err)
+		}
+		path = executable
+	}
+	if args == nil {
+		args = os.Args[1:]
+	}
+
+	files, addresses, err := m.Files()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+	if len(files) == 0 {
+		return nil, errors.New("no active listeners to hand over")
+	}
+
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = files
+	cmd.Env = append(filterEnv(os.Environ(), EnvListenFDs), EnvListenFDs+"="+strings.Join(addresses, ","))
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start new process: %w", err)
+	}
+	logger.Info(context.Background(), "Started new process for listener handover: pid=%d, listeners=%s",
+		cmd.Process.Pid, strings.Join(addresses, ","))
+	return cmd.Process, nil
+}
+
+// StartProcess 使用默认 Manager 启动新进程并传递监听
+func StartProcess(path string, args []string) (*os.Process, error) {
+	return Default().StartProcess(path, args)
+}
+
+func (m *Manager) untrack(l *trackedListener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, active := range m.active {
+		if active == l {
+			m.active = append(m.active[:i:i], m.active[i+1:]...)
+			return
+		}
+	}
+}
+
+func filterEnv(env []string, key string) []string {
+	prefix := key + "="
+	filtered := make([]string, 0, len(env))
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, prefix) {
+			filtered = append(filtered, kv)
+		}
+	}
+	return filtered
+}
+
+// trackedListener 关闭时从 Manager 中移除，避免交接已关闭的监听
+type trackedListener struct {
+	*net.TCPListener
+	address string
+	manager *Manager
+	once    sync.Once
+}
+
+func (l *trackedListener) Close() error {
+	l.once.Do(func() { l.manager.untrack(l) })
+	return l.TCPListener.Close()
+}
@@ -0,0 +1,190 @@
+package handover
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManagerUsesInheritedListener(t *testing.T) {
+	parent := newManager("", listenFDStart)
+	listener, err := parent.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer listener.Close()
+
+	files, addresses, err := parent.Files()
+	if err != nil || len(files) != 1 || addresses[0] != "127.0.0.1:0" {
+		t.Fatalf("unexpected Files result: %v %v %v", files, addresses, err)
+	}
+
+	// 模拟子进程：从复制的文件描述符继承监听
+	child := newManager(addresses[0], int(files[0].Fd()))
+	inherited, err := child.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("inherited Listen failed: %v", err)
+	}
+	defer inherited.Close()
+	if inherited.Addr().String() != listener.Addr().String() {
+		t.Fatalf("expected inherited address %s, got %s", listener.Addr(), inherited.Addr())
+	}
+	if unused := child.CloseUnused(); len(unused) != 0 {
+		t.Fatalf("unexpected unused listeners %v", unused)
+	}
+
+	// 两个进程共享同一套接字，任一方都能接受连接
+	go func() {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if err == nil {
+			conn.Close()
+		}
+	}()
+	_ = inherited.(*trackedListener).SetDeadline(time.Now().Add(2 * time.Second))
+	conn, err := inherited.Accept()
+	if err != nil {
+		t.Fatalf("Accept on inherited listener failed: %v", err)
+	}
+	conn.Close()
+}
+
+func TestManagerCloseUnusedInheritedListeners(t *testing.T) {
+	parent := newManager("", listenFDStart)
+	listener, err := parent.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer listener.Close()
+	files, addresses, err := parent.Files()
+	if err != nil {
+		t.Fatalf("Files failed: %v", err)
+	}
+
+	child := newManager(addresses[0], int(files[0].Fd()))
+	if unused := child.CloseUnused(); len(unused) != 1 || unused[0] != addresses[0] {
+		t.Fatalf("unexpected unused listeners %v", unused)
+	}
+	// 关闭继承的副本不影响父进程的监听
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("parent listener should still accept connections: %v", err)
+	}
+	conn.Close()
+}
+
+func TestManagerUntracksClosedListener(t *testing.T) {
+	m := newManager("", listenFDStart)
+	listener, err := m.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	if err := listener.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	files, _, err := m.Files()
+	if err != nil || len(files) != 0 {
+		t.Fatalf("expected no active listeners, got %d (%v)", len(files), err)
+	}
+	if _, err := m.StartProcess("", nil); err == nil {
+		t.Fatal("expected StartProcess to fail without listeners")
+	}
+}
+
+func TestManagerReusePort(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("SO_REUSEPORT test only runs on linux/darwin")
+	}
+	m := newManager("", listenFDStart)
+	m.SetReusePort(true)
+	first, err := m.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer first.Close()
+	second, err := m.Listen("tcp", first.Addr().String())
+	if err != nil {
+		t.Fatalf("expected second bind with SO_REUSEPORT to succeed: %v", err)
+	}
+	second.Close()
+}
+
+func TestStartProcessPassesListeners(t *testing.T) {
+	if os.Getenv("QUICKGO_HANDOVER_HELPER") == "1" {
+		return
+	}
+	m := newManager("", listenFDStart)
+	listener, err := m.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer listener.Close()
+
+	t.Setenv("QUICKGO_HANDOVER_HELPER", "1")
+	files, addresses, err := m.Files()
+	if err != nil {
+		t.Fatalf("Files failed: %v", err)
+	}
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+	cmd := exec.Command(os.Args[0], "-test.run=TestHandoverHelperProcess")
+	cmd.ExtraFiles = files
+	cmd.Env = append(filterEnv(os.Environ(), EnvListenFDs), EnvListenFDs+"="+strings.Join(addresses, ","))
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("StdoutPipe failed: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer cmd.Wait()
+
+	// 父进程停止接受连接后，子进程在同一地址上继续服务
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	conn.Close()
+	listener.Close()
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "handover:") {
+			if line != "handover: accepted" {
+				t.Fatalf("helper failed: %s", line)
+			}
+			return
+		}
+	}
+	t.Fatal("helper process exited without result")
+}
+
+// TestHandoverHelperProcess 在子进程中运行，从环境变量继承监听并接受一个连接
+func TestHandoverHelperProcess(t *testing.T) {
+	if os.Getenv("QUICKGO_HANDOVER_HELPER") != "1" {
+		return
+	}
+	address := os.Getenv(EnvListenFDs)
+	listener, err := Default().Listen("tcp", address)
+	if err != nil {
+		fmt.Printf("handover: listen error %v\n", err)
+		return
+	}
+	defer listener.Close()
+	_ = listener.(*trackedListener).SetDeadline(time.Now().Add(5 * time.Second))
+	conn, err := listener.Accept()
+	if err != nil {
+		fmt.Printf("handover: accept error %v\n", err)
+		return
+	}
+	conn.Close()
+	fmt.Println("handover: accepted")
+}
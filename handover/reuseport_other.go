@@ -0,0 +1,17 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package handover
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// UpgradeSignal 当前平台不支持信号触发升级
+var UpgradeSignal os.Signal
+
+// reusePortControl 当前平台不支持 SO_REUSEPORT
+func reusePortControl(network, address string, c syscall.RawConn) error {
+	return errors.New("SO_REUSEPORT is not supported on this platform")
+}
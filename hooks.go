@@ -0,0 +1,130 @@
+package quickgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// defaultHookTimeout 生命周期钩子默认超时
+const defaultHookTimeout = 30 * time.Second
+
+// HookFunc 生命周期钩子函数
+type HookFunc func(ctx context.Context) error
+
+// HookOption 生命周期钩子选项
+type HookOption func(*lifecycleHook)
+
+// WithHookName 设置钩子名称（用于日志与停止报告）
+func WithHookName(name string) HookOption {
+	return func(h *lifecycleHook) {
+		h.name = name
+	}
+}
+
+// WithHookTimeout 设置钩子超时（默认 30s），超时后不再等待钩子返回
+func WithHookTimeout(timeout time.Duration) HookOption {
+	return func(h *lifecycleHook) {
+		if timeout > 0 {
+			h.timeout = timeout
+		}
+	}
+}
+
+type lifecycleHook struct {
+	name    string
+	fn      HookFunc
+	timeout time.Duration
+}
+
+// hookStage 钩子执行时机
+type hookStage string
+
+const (
+	hookBeforeStart hookStage = "before start"
+	hookAfterStart  hookStage = "after start"
+	hookBeforeStop  hookStage = "before stop"
+)
+
+// OnBeforeStart 注册启动前钩子：在 Start 启动服务与组件之前按注册顺序执行，
+// 任一钩子失败时 Start 返回所有钩子的错误且不启动任何服务
+func (f *Framework) OnBeforeStart(fn HookFunc, opts ...HookOption) {
+	f.addHook(hookBeforeStart, fn, opts)
+}
+
+// OnAfterStart 注册启动后钩子：在服务与组件全部启动后按注册顺序执行（如预热、打印启动信息），
+// 钩子失败时 Start 返回所有钩子的错误，框架保持运行状态，由调用方决定是否 Stop
+func (f *Framework) OnAfterStart(fn HookFunc, opts ...HookOption) {
+	f.addHook(hookAfterStart, fn, opts)
+}
+
+// OnBeforeStop 注册停止前钩子：在 Stop 停止服务与组件之前按注册的逆序执行（仅当框架已启动），
+// 钩子失败不影响后续停止，错误合并到 Stop 的返回值并记录在停止报告中
+func (f *Framework) OnBeforeStop(fn HookFunc, opts ...HookOption) {
+	f.addHook(hookBeforeStop, fn, opts)
+}
+
+func (f *Framework) addHook(stage hookStage, fn HookFunc, opts []HookOption) {
+	if fn == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.hooks == nil {
+		f.hooks = make(map[hookStage][]*lifecycleHook)
+	}
+	hook := &lifecycleHook{
+		name:    fmt.Sprintf("%s hook #%d", stage, len(f.hooks[stage])+1),
+		fn:      fn,
+		timeout: defaultHookTimeout,
+	}
+	for _, opt := range opts {
+		opt(hook)
+	}
+	f.hooks[stage] = append(f.hooks[stage], hook)
+}
+
+// hooksFor 返回指定时机的钩子快照
+func (f *Framework) hooksFor(stage hookStage) []*lifecycleHook {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return append([]*lifecycleHook(nil), f.hooks[stage]...)
+}
+
+// runHooks 依次执行钩子，汇总所有错误
+func runHooks(ctx context.Context, hooks []*lifecycleHook) error {
+	var errs []error
+	for _, hook := range hooks {
+		if err := hook.run(ctx); err != nil {
+			logger.Error(ctx, "Lifecycle hook %s failed: %v", hook.name, err)
+			errs = append(errs, fmt.Errorf("%s: %w", hook.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// run 在超时内执行钩子，钩子 panic 视为失败
+func (h *lifecycleHook) run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- h.fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out after %s: %w", h.timeout, ctx.Err())
+	}
+}
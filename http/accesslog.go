@@ -0,0 +1,380 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AccessLogFormat 访问日志格式
+type AccessLogFormat string
+
+const (
+	// AccessLogFormatJSON 每行一个 JSON 对象
+	AccessLogFormatJSON AccessLogFormat = "json"
+	// AccessLogFormatCombined Apache/Nginx combined 格式，额外字段以 key=value 追加在行尾
+	AccessLogFormatCombined AccessLogFormat = "combined"
+)
+
+// 访问日志字段
+const (
+	AccessLogFieldTime      = "time"
+	AccessLogFieldMethod    = "method"
+	AccessLogFieldPath      = "path"
+	AccessLogFieldRoute     = "route"
+	AccessLogFieldStatus    = "status"
+	AccessLogFieldLatency   = "latency"
+	AccessLogFieldBytes     = "bytes"
+	AccessLogFieldIP        = "ip"
+	AccessLogFieldUserAgent = "user_agent"
+	AccessLogFieldReferer   = "referer"
+	AccessLogFieldTraceID   = "trace_id"
+)
+
+// defaultAccessLogFields 默认输出的字段（按输出顺序）
+var defaultAccessLogFields = []string{
+	AccessLogFieldTime,
+	AccessLogFieldMethod,
+	AccessLogFieldPath,
+	AccessLogFieldRoute,
+	AccessLogFieldStatus,
+	AccessLogFieldLatency,
+	AccessLogFieldBytes,
+	AccessLogFieldIP,
+	AccessLogFieldUserAgent,
+	AccessLogFieldReferer,
+	AccessLogFieldTraceID,
+}
+
+// AccessLogConfig 访问日志配置
+// 访问日志与应用日志相互独立，输出到单独的文件或 stdout
+type AccessLogConfig struct {
+	// 日志格式（默认 json）
+	Format AccessLogFormat
+	// 自定义输出（优先于 Path）
+	Output io.Writer
+	// 输出文件路径，为空时输出到 stdout
+	Path string
+	// 单个文件最大字节数，超过后轮转为 Path.1、Path.2 ...（为 0 时不轮转）
+	MaxSize int64
+	// 保留的历史文件数量（默认 5）
+	MaxBackups int
+	// 输出字段（默认全部字段）；combined 格式固定输出标准字段，其余字段追加在行尾
+	Fields []string
+	// 采样率（0 或 >= 1 时全部记录），状态码 >= 500 的请求始终记录
+	SampleRate float64
+	// 按路由模式设置采样率（如 "/healthz": 0.01），优先于 SampleRate
+	RouteSampleRates map[string]float64
+}
+
+// AccessLogger HTTP 访问日志记录器
+type AccessLogger struct {
+	config AccessLogConfig
+	fields []string
+	mu     sync.Mutex
+	out    io.Writer
+	closer io.Closer
+}
+
+// NewAccessLogger 创建访问日志记录器
+func NewAccessLogger(config AccessLogConfig) (*AccessLogger, error) {
+	switch config.Format {
+	case "":
+		config.Format = AccessLogFormatJSON
+	case AccessLogFormatJSON, AccessLogFormatCombined:
+	default:
+		return nil, fmt.Errorf("unsupported access log format: %s", config.Format)
+	}
+	if config.MaxBackups <= 0 {
+		config.MaxBackups = 5
+	}
+
+	fields := config.Fields
+	if len(fields) == 0 {
+		fields = defaultAccessLogFields
+	}
+	for _, field := range fields {
+		if !isAccessLogField(field) {
+			return nil, fmt.Errorf("unsupported access log field: %s", field)
+		}
+	}
+
+	l := &AccessLogger{config: config, fields: append([]string(nil), fields...)}
+	switch {
+	case config.Output != nil:
+		l.out = config.Output
+	case config.Path != "":
+		file, err := openRotatingFile(config.Path, config.MaxSize, config.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		l.out = file
+		l.closer = file
+	default:
+		l.out = os.Stdout
+	}
+	return l, nil
+}
+
+func isAccessLogField(field string) bool {
+	for _, f := range defaultAccessLogFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Handler 返回访问日志中间件
+func (l *AccessLogger) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+		latency := time.Since(start)
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			// 错误由 ErrorHandler 在中间件链返回后写入响应，这里按错误推断最终状态码
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		route := c.Route().Path
+		if !l.sampled(route, status) {
+			return err
+		}
+
+		bytes := c.Response().Header.ContentLength()
+		if bytes < 0 {
+			bytes = 0
+		}
+		if body := len(c.Response().Body()); body > bytes {
+			bytes = body
+		}
+
+		entry := accessLogEntry{
+			time:      start,
+			method:    c.Method(),
+			path:      c.Path(),
+			route:     route,
+			status:    status,
+			latency:   latency,
+			bytes:     bytes,
+			ip:        c.IP(),
+			userAgent: c.Get(fiber.HeaderUserAgent),
+			referer:   c.Get(fiber.HeaderReferer),
+			traceID:   GetTraceID(c),
+		}
+		l.write(entry)
+		return err
+	}
+}
+
+// Close 关闭访问日志文件
+func (l *AccessLogger) Close() error {
+	if l == nil || l.closer == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closer.Close()
+}
+
+// sampled 判断请求是否需要记录
+func (l *AccessLogger) sampled(route string, status int) bool {
+	if status >= fiber.StatusInternalServerError {
+		return true
+	}
+	rate := l.config.SampleRate
+	if routeRate, ok := l.config.RouteSampleRates[route]; ok {
+		rate = routeRate
+	} else if rate <= 0 {
+		return true
+	}
+	if rate >= 1 {
+		return true
+	}
+	return rand.Float64() < rate
+}
+
+type accessLogEntry struct {
+	time      time.Time
+	method    string
+	path      string
+	route     string
+	status    int
+	latency   time.Duration
+	bytes     int
+	ip        string
+	userAgent string
+	referer   string
+	traceID   string
+}
+
+func (e accessLogEntry) value(field string) interface{} {
+	switch field {
+	case AccessLogFieldTime:
+		return e.time.Format(time.RFC3339Nano)
+	case AccessLogFieldMethod:
+		return e.method
+	case AccessLogFieldPath:
+		return e.path
+	case AccessLogFieldRoute:
+		return e.route
+	case AccessLogFieldStatus:
+		return e.status
+	case AccessLogFieldLatency:
+		return float64(e.latency.Microseconds()) / 1000
+	case AccessLogFieldBytes:
+		return e.bytes
+	case AccessLogFieldIP:
+		return e.ip
+	case AccessLogFieldUserAgent:
+		return e.userAgent
+	case AccessLogFieldReferer:
+		return e.referer
+	case AccessLogFieldTraceID:
+		return e.traceID
+	}
+	return nil
+}
+
+func (l *AccessLogger) write(entry accessLogEntry) {
+	var line []byte
+	if l.config.Format == AccessLogFormatCombined {
+		line = l.formatCombined(entry)
+	} else {
+		record := make(map[string]interface{}, len(l.fields))
+		for _, field := range l.fields {
+			record[field] = entry.value(field)
+		}
+		data, err := json.Marshal(record)
+		if err != nil {
+			return
+		}
+		line = append(data, '\n')
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.out.Write(line)
+}
+
+// formatCombined 输出 combined 格式：
+// ip - - [time] "method path" status bytes "referer" "user_agent" key=value...
+func (l *AccessLogger) formatCombined(entry accessLogEntry) []byte {
+	var b strings.Builder
+	b.WriteString(entry.ip)
+	b.WriteString(" - - [")
+	b.WriteString(entry.time.Format("02/Jan/2006:15:04:05 -0700"))
+	b.WriteString("] \"")
+	b.WriteString(entry.method)
+	b.WriteByte(' ')
+	b.WriteString(entry.path)
+	b.WriteString("\" ")
+	b.WriteString(strconv.Itoa(entry.status))
+	b.WriteByte(' ')
+	b.WriteString(strconv.Itoa(entry.bytes))
+	b.WriteString(" ")
+	b.WriteString(strconv.Quote(entry.referer))
+	b.WriteString(" ")
+	b.WriteString(strconv.Quote(entry.userAgent))
+	for _, field := range l.fields {
+		switch field {
+		case AccessLogFieldRoute, AccessLogFieldLatency, AccessLogFieldTraceID:
+			b.WriteByte(' ')
+			b.WriteString(field)
+			b.WriteByte('=')
+			b.WriteString(fmt.Sprint(entry.value(field)))
+		}
+	}
+	b.WriteByte('\n')
+	return []byte(b.String())
+}
+
+// rotatingFile 按大小轮转的日志文件
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func openRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open access log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat access log file: %w", err)
+	}
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// Write 写入数据，超过 maxSize 时先轮转（调用方负责加锁）
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate 将 path 重命名为 path.1，已有的历史文件依次后移，超出 maxBackups 的删除
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+	_ = os.Remove(r.path + "." + strconv.Itoa(r.maxBackups))
+	for i := r.maxBackups - 1; i >= 1; i-- {
+		_ = os.Rename(r.path+"."+strconv.Itoa(i), r.path+"."+strconv.Itoa(i+1))
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		if openErr := r.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("failed to rotate access log file: %w", err)
+	}
+	return r.open()
+}
+
+func (r *rotatingFile) Close() error {
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestAccessLoggerWritesSelectedJSONFields(t *testing.T) {
+	var buf bytes.Buffer
+	accessLog, err := NewAccessLogger(AccessLogConfig{
+		Output: &buf,
+		Fields: []string{AccessLogFieldRoute, AccessLogFieldStatus, AccessLogFieldBytes, AccessLogFieldTraceID},
+	})
+	if err != nil {
+		t.Fatalf("NewAccessLogger failed: %v", err)
+	}
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(TraceMiddleware(), accessLog.Handler())
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		return c.SendString("hello")
+	})
+
+	req := httptest.NewRequest("GET", "/users/42", nil)
+	req.Header.Set(TraceIDHeader, "trace-1")
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected JSON access log line, got %q: %v", buf.String(), err)
+	}
+	if len(record) != 4 {
+		t.Fatalf("expected only selected fields, got %v", record)
+	}
+	if record["route"] != "/users/:id" || record["status"] != float64(200) || record["bytes"] != float64(5) || record["trace_id"] != "trace-1" {
+		t.Fatalf("unexpected access log record: %v", record)
+	}
+}
+
+func TestAccessLoggerCombinedFormatAndSampling(t *testing.T) {
+	var buf bytes.Buffer
+	accessLog, err := NewAccessLogger(AccessLogConfig{
+		Format:           AccessLogFormatCombined,
+		Output:           &buf,
+		Fields:           []string{AccessLogFieldRoute},
+		RouteSampleRates: map[string]float64{"/healthz": 0},
+	})
+	if err != nil {
+		t.Fatalf("NewAccessLogger failed: %v", err)
+	}
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(accessLog.Handler())
+	app.Get("/healthz", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	app.Get("/boom", func(c *fiber.Ctx) error { return fiber.NewError(fiber.StatusBadGateway, "boom") })
+
+	for _, path := range []string{"/healthz", "/boom"} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set(fiber.HeaderUserAgent, "test-agent")
+		if _, err := app.Test(req); err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected sampled-out route to be skipped, got %q", buf.String())
+	}
+	if !strings.Contains(lines[0], `"GET /boom" 502 `) || !strings.Contains(lines[0], `"test-agent" route=/boom`) {
+		t.Fatalf("unexpected combined log line: %q", lines[0])
+	}
+}
+
+func TestAccessLoggerRotatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	accessLog, err := NewAccessLogger(AccessLogConfig{
+		Path:       path,
+		MaxSize:    100,
+		MaxBackups: 1,
+		Fields:     []string{AccessLogFieldPath, AccessLogFieldUserAgent},
+	})
+	if err != nil {
+		t.Fatalf("NewAccessLogger failed: %v", err)
+	}
+	defer accessLog.Close()
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(accessLog.Handler())
+	app.Get("/*", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	for i := 0; i < 4; i++ {
+		req := httptest.NewRequest("GET", "/rotate", nil)
+		req.Header.Set(fiber.HeaderUserAgent, strings.Repeat("a", 30))
+		if _, err := app.Test(req); err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+	}
+
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Fatalf("expected rotated backup file: %v", err)
+	}
+	if _, err := os.Stat(path + ".2"); !os.IsNotExist(err) {
+		t.Fatalf("expected backups beyond MaxBackups to be removed, got %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Size() > 100 {
+		t.Fatalf("expected current file within MaxSize, info=%v err=%v", info, err)
+	}
+}
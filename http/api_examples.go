@@ -0,0 +1,309 @@
+package http
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultExampleSampleRate      = 0.01
+	defaultExampleMaxBodyBytes    = 8 << 10
+	defaultExampleRefreshInterval = time.Hour
+	defaultExampleMask            = "***"
+)
+
+// defaultExampleRedactFields 默认脱敏字段（大小写不敏感，支持 * 通配符）
+var defaultExampleRedactFields = []string{
+	"*password*", "*passwd*", "*secret*", "*token*", "*apikey*", "*api_key*", "authorization", "cookie",
+	"*email*", "*phone*", "*mobile*", "*id_card*", "*idcard*", "*card_no*",
+}
+
+// APIExample 路由的一组请求/响应示例（已脱敏）
+type APIExample struct {
+	Method       string            `json:"method"`
+	Path         string            `json:"path"`
+	Status       int               `json:"status"`
+	Query        map[string]string `json:"query,omitempty"`
+	RequestBody  json.RawMessage   `json:"requestBody,omitempty"`
+	ResponseBody json.RawMessage   `json:"responseBody,omitempty"`
+	CapturedAt   time.Time         `json:"capturedAt"`
+}
+
+// ExampleStore 示例存储，每个 (Method, Path, Status) 保留一条示例
+type ExampleStore interface {
+	Get(method, path string, status int) (APIExample, bool)
+	Put(example APIExample)
+	List() []APIExample
+}
+
+// MemoryExampleStore 进程内示例存储
+type MemoryExampleStore struct {
+	mu       sync.RWMutex
+	examples map[string]APIExample
+}
+
+// NewMemoryExampleStore 创建进程内示例存储
+func NewMemoryExampleStore() *MemoryExampleStore {
+	return &MemoryExampleStore{examples: make(map[string]APIExample)}
+}
+
+func exampleKey(method, path string, status int) string {
+	return method + " " + path + " " + strconv.Itoa(status)
+}
+
+// Get 获取示例
+func (s *MemoryExampleStore) Get(method, path string, status int) (APIExample, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	example, ok := s.examples[exampleKey(method, path, status)]
+	return example, ok
+}
+
+// Put 保存示例（覆盖同一路由、同一状态码的旧示例）
+func (s *MemoryExampleStore) Put(example APIExample) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.examples[exampleKey(example.Method, example.Path, example.Status)] = example
+}
+
+// List 按路径、方法、状态码排序返回全部示例
+func (s *MemoryExampleStore) List() []APIExample {
+	s.mu.RLock()
+	examples := make([]APIExample, 0, len(s.examples))
+	for _, example := range s.examples {
+		examples = append(examples, example)
+	}
+	s.mu.RUnlock()
+	sort.Slice(examples, func(i, j int) bool {
+		a, b := examples[i], examples[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		return a.Status < b.Status
+	})
+	return examples
+}
+
+// ExampleCaptureConfig 请求/响应示例采集配置
+type ExampleCaptureConfig struct {
+	// 示例存储（默认进程内存储）
+	Store ExampleStore
+	// 已有示例过期后的采样率（0~1，默认 0.01）；路由还没有示例时总是采集
+	SampleRate float64
+	// 示例的有效期，超过后按采样率采集新示例替换（默认 1h），使示例随 API 演进自动刷新
+	RefreshInterval time.Duration
+	// 请求或响应体超过该字节数时不采集（默认 8KB）
+	MaxBodyBytes int
+	// 额外的脱敏字段（大小写不敏感，支持 * 通配符），与默认规则合并
+	RedactFields []string
+	// 脱敏替换值（默认 ***）
+	Mask string
+	// 跳过采集的路由（返回 true 时不采集）
+	Skip func(c *fiber.Ctx) bool
+}
+
+func normalizeExampleCaptureConfig(config ExampleCaptureConfig) ExampleCaptureConfig {
+	if config.Store == nil {
+		config.Store = NewMemoryExampleStore()
+	}
+	if config.SampleRate <= 0 {
+		config.SampleRate = defaultExampleSampleRate
+	}
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = defaultExampleRefreshInterval
+	}
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = defaultExampleMaxBodyBytes
+	}
+	if config.Mask == "" {
+		config.Mask = defaultExampleMask
+	}
+	redact := make([]string, 0, len(defaultExampleRedactFields)+len(config.RedactFields))
+	for _, field := range append(append([]string(nil), defaultExampleRedactFields...), config.RedactFields...) {
+		redact = append(redact, strings.ToLower(field))
+	}
+	config.RedactFields = redact
+	return config
+}
+
+// ExampleCaptureMiddleware 按路由采集脱敏后的 JSON 请求/响应示例，用于通过 EnrichOpenAPI 丰富 OpenAPI 文档
+// 仅采集 JSON（或空）请求体与未压缩的 JSON 响应，流式响应与超过 MaxBodyBytes 的请求不采集；
+// 请求体、响应体与查询参数中匹配脱敏规则的字段替换为 Mask
+func ExampleCaptureMiddleware(config ExampleCaptureConfig) fiber.Handler {
+	config = normalizeExampleCaptureConfig(config)
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+		if err != nil || (config.Skip != nil && config.Skip(c)) {
+			return err
+		}
+		route := c.Route()
+		if route == nil || route.Path == "" {
+			return nil
+		}
+		method, routePath, status := c.Method(), route.Path, c.Response().StatusCode()
+		if existing, ok := config.Store.Get(method, routePath, status); ok {
+			if time.Since(existing.CapturedAt) < config.RefreshInterval || rand.Float64() >= config.SampleRate {
+				return nil
+			}
+		}
+		if example, ok := captureExample(c, config); ok {
+			example.Method, example.Path, example.Status = method, routePath, status
+			config.Store.Put(example)
+		}
+		return nil
+	}
+}
+
+// captureExample 读取并脱敏当前请求/响应，不满足采集条件时返回 false
+func captureExample(c *fiber.Ctx, config ExampleCaptureConfig) (APIExample, bool) {
+	resp := c.Response()
+	if resp.IsBodyStream() || len(resp.Header.Peek(fiber.HeaderContentEncoding)) > 0 ||
+		!strings.HasPrefix(string(resp.Header.ContentType()), fiber.MIMEApplicationJSON) {
+		return APIExample{}, false
+	}
+	reqBody, respBody := c.Body(), resp.Body()
+	if len(reqBody) > config.MaxBodyBytes || len(respBody) > config.MaxBodyBytes {
+		return APIExample{}, false
+	}
+
+	example := APIExample{CapturedAt: time.Now()}
+	if len(reqBody) > 0 {
+		data, ok := redactJSON(reqBody, config)
+		if !ok {
+			return APIExample{}, false
+		}
+		example.RequestBody = data
+	}
+	if len(respBody) > 0 {
+		data, ok := redactJSON(respBody, config)
+		if !ok {
+			return APIExample{}, false
+		}
+		example.ResponseBody = data
+	}
+	for key, value := range c.Queries() {
+		if example.Query == nil {
+			example.Query = make(map[string]string)
+		}
+		if matchExampleField(config.RedactFields, key) {
+			value = config.Mask
+		}
+		// Queries 返回的字符串引用请求缓冲区，请求结束后会被复用
+		example.Query[strings.Clone(key)] = strings.Clone(value)
+	}
+	return example, true
+}
+
+func redactJSON(data []byte, config ExampleCaptureConfig) (json.RawMessage, bool) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, false
+	}
+	redacted, err := json.Marshal(redactExampleValue(value, config))
+	if err != nil {
+		return nil, false
+	}
+	return redacted, true
+}
+
+func redactExampleValue(value interface{}, config ExampleCaptureConfig) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if matchExampleField(config.RedactFields, key) {
+				v[key] = config.Mask
+				continue
+			}
+			v[key] = redactExampleValue(item, config)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactExampleValue(item, config)
+		}
+	}
+	return value
+}
+
+func matchExampleField(patterns []string, field string) bool {
+	field = strings.ToLower(field)
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, field); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// routeParamPattern 匹配 Fiber 路由参数（:id、:id? 及带约束的 :id<int>）
+var routeParamPattern = regexp.MustCompile(`:([A-Za-z0-9_]+)(<[^>]*>)?\??`)
+
+// openAPIPath 将 Fiber 路由路径转换为 OpenAPI 路径模板（/users/:id → /users/{id}）
+func openAPIPath(routePath string) string {
+	return routeParamPattern.ReplaceAllString(routePath, "{$1}")
+}
+
+// EnrichOpenAPI 将示例写入 OpenAPI 3 文档（JSON）：请求体示例写入 requestBody，响应示例写入对应状态码的响应，
+// 只填充文档中已声明的操作与状态码，不新增接口
+func EnrichOpenAPI(spec []byte, store ExampleStore) ([]byte, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, err
+	}
+	paths, _ := doc["paths"].(map[string]interface{})
+	for _, example := range store.List() {
+		item, _ := paths[openAPIPath(example.Path)].(map[string]interface{})
+		operation, _ := item[strings.ToLower(example.Method)].(map[string]interface{})
+		if operation == nil {
+			continue
+		}
+		if len(example.RequestBody) > 0 {
+			if body, ok := operation["requestBody"].(map[string]interface{}); ok {
+				setJSONExample(body, example.RequestBody)
+			}
+		}
+		responses, _ := operation["responses"].(map[string]interface{})
+		if response, ok := responses[strconv.Itoa(example.Status)].(map[string]interface{}); ok && len(example.ResponseBody) > 0 {
+			setJSONExample(response, example.ResponseBody)
+		}
+	}
+	return json.Marshal(doc)
+}
+
+// setJSONExample 设置 content["application/json"].example
+func setJSONExample(target map[string]interface{}, example json.RawMessage) {
+	content, ok := target["content"].(map[string]interface{})
+	if !ok {
+		content = make(map[string]interface{})
+		target["content"] = content
+	}
+	media, ok := content[fiber.MIMEApplicationJSON].(map[string]interface{})
+	if !ok {
+		media = make(map[string]interface{})
+		content[fiber.MIMEApplicationJSON] = media
+	}
+	media["example"] = example
+}
+
+// OpenAPIHandler 返回附带最新示例的 OpenAPI 文档，每次请求时重新合并示例
+func OpenAPIHandler(spec []byte, store ExampleStore) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		doc, err := EnrichOpenAPI(spec, store)
+		if err != nil {
+			return err
+		}
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.Send(doc)
+	}
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestExampleCaptureMiddlewareRecordsRedactedExample(t *testing.T) {
+	store := NewMemoryExampleStore()
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(ExampleCaptureMiddleware(ExampleCaptureConfig{Store: store, RedactFields: []string{"nickname"}}))
+	app.Post("/users/:id", func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": c.Params("id"), "email": "a@b.com", "nickname": "bob"})
+	})
+
+	req := httptest.NewRequest("POST", "/users/42?token=abc&lang=en", strings.NewReader(`{"name":"bob","password":"pw"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+
+	example, ok := store.Get("POST", "/users/:id", fiber.StatusCreated)
+	if !ok {
+		t.Fatalf("expected example to be captured, got %v", store.List())
+	}
+	if string(example.RequestBody) != `{"name":"bob","password":"***"}` {
+		t.Fatalf("unexpected request example %s", example.RequestBody)
+	}
+	if string(example.ResponseBody) != `{"email":"***","id":"42","nickname":"***"}` {
+		t.Fatalf("unexpected response example %s", example.ResponseBody)
+	}
+	if example.Query["token"] != "***" || example.Query["lang"] != "en" {
+		t.Fatalf("unexpected query example %v", example.Query)
+	}
+}
+
+func TestExampleCaptureMiddlewareKeepsFreshExample(t *testing.T) {
+	store := NewMemoryExampleStore()
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(ExampleCaptureMiddleware(ExampleCaptureConfig{Store: store, SampleRate: 1, RefreshInterval: time.Hour}))
+	count := 0
+	app.Get("/items", func(c *fiber.Ctx) error {
+		count++
+		return c.JSON(fiber.Map{"n": count})
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := app.Test(httptest.NewRequest("GET", "/items", nil)); err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+	}
+	example, _ := store.Get("GET", "/items", fiber.StatusOK)
+	if string(example.ResponseBody) != `{"n":1}` {
+		t.Fatalf("expected first example to be kept until refresh, got %s", example.ResponseBody)
+	}
+
+	example.CapturedAt = time.Now().Add(-2 * time.Hour)
+	store.Put(example)
+	if _, err := app.Test(httptest.NewRequest("GET", "/items", nil)); err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if example, _ = store.Get("GET", "/items", fiber.StatusOK); string(example.ResponseBody) != `{"n":3}` {
+		t.Fatalf("expected stale example to be refreshed, got %s", example.ResponseBody)
+	}
+}
+
+func TestOpenAPIHandlerEnrichesDeclaredOperations(t *testing.T) {
+	store := NewMemoryExampleStore()
+	store.Put(APIExample{Method: "POST", Path: "/users/:id<int>", Status: 201,
+		RequestBody: json.RawMessage(`{"name":"bob"}`), ResponseBody: json.RawMessage(`{"id":1}`)})
+	store.Put(APIExample{Method: "GET", Path: "/undocumented", Status: 200, ResponseBody: json.RawMessage(`{}`)})
+	spec := []byte(`{"openapi":"3.0.3","paths":{"/users/{id}":{"post":{"requestBody":{"content":{"application/json":{}}},"responses":{"201":{"description":"created"}}}}}}`)
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/openapi.json", OpenAPIHandler(spec, store))
+	resp, err := app.Test(httptest.NewRequest("GET", "/openapi.json", nil))
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+
+	var doc struct {
+		Paths map[string]map[string]struct {
+			RequestBody struct {
+				Content map[string]struct {
+					Example json.RawMessage `json:"example"`
+				} `json:"content"`
+			} `json:"requestBody"`
+			Responses map[string]struct {
+				Content map[string]struct {
+					Example json.RawMessage `json:"example"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("invalid document %s: %v", body, err)
+	}
+	op := doc.Paths["/users/{id}"]["post"]
+	if got := string(op.RequestBody.Content["application/json"].Example); got != `{"name":"bob"}` {
+		t.Fatalf("unexpected request example %q", got)
+	}
+	if got := string(op.Responses["201"].Content["application/json"].Example); got != `{"id":1}` {
+		t.Fatalf("unexpected response example %q", got)
+	}
+	if _, ok := doc.Paths["/undocumented"]; ok {
+		t.Fatal("expected undocumented routes not to be added")
+	}
+}
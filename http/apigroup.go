@@ -0,0 +1,158 @@
+package http
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// APIVersionHeader 响应中返回的 API 版本
+	APIVersionHeader = "API-Version"
+
+	defaultAPIPrefix   = "/api"
+	apiVersionLocalKey = "quickgo.apiVersion"
+)
+
+// APIGroupOptions 版本化路由组配置
+type APIGroupOptions struct {
+	// 路由前缀（默认 /api），版本号追加在前缀之后，如 /api/v1；设置为 "/" 时直接使用 /v1
+	Prefix string
+	// 版本级中间件，仅作用于该版本下的路由
+	Middlewares []fiber.Handler
+	// 是否已弃用，弃用后响应携带 Deprecation 头
+	Deprecated bool
+	// 弃用时间（可选），设置后 Deprecation 头为 @<unix 秒>，否则为 true；设置时隐含 Deprecated
+	DeprecatedAt time.Time
+	// 下线时间（可选），设置后响应携带 Sunset 头
+	Sunset time.Time
+	// 弃用说明或迁移文档地址（可选），以 Link: <url>; rel="deprecation" 返回
+	DeprecationLink string
+}
+
+// NewAPIGroup 创建版本化路由组，如 NewAPIGroup(app, "v1") 对应 /api/v1
+// 组内响应携带 API-Version 头，弃用版本附加 Deprecation / Sunset / Link 头；处理器可通过 APIVersion 获取当前版本
+func NewAPIGroup(router fiber.Router, version string, opts ...APIGroupOptions) fiber.Router {
+	var options APIGroupOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	version = normalizeAPIVersion(version)
+	prefix := strings.TrimRight(options.Prefix, "/")
+	if options.Prefix == "" {
+		prefix = defaultAPIPrefix
+	}
+
+	handlers := append([]fiber.Handler{apiVersionMiddleware(version, options)}, options.Middlewares...)
+	return router.Group(prefix+"/"+version, handlers...)
+}
+
+// APIVersion 返回请求所属的 API 版本（不在版本化路由组内时返回空字符串）
+func APIVersion(c *fiber.Ctx) string {
+	version, _ := c.Locals(apiVersionLocalKey).(string)
+	return version
+}
+
+// apiVersionMiddleware 记录版本并设置版本与弃用相关响应头
+func apiVersionMiddleware(version string, options APIGroupOptions) fiber.Handler {
+	var deprecation string
+	switch {
+	case !options.DeprecatedAt.IsZero():
+		deprecation = "@" + strconv.FormatInt(options.DeprecatedAt.Unix(), 10)
+	case options.Deprecated:
+		deprecation = "true"
+	}
+	var sunset string
+	if !options.Sunset.IsZero() {
+		sunset = options.Sunset.UTC().Format(http.TimeFormat)
+	}
+	var link string
+	if options.DeprecationLink != "" {
+		link = "<" + options.DeprecationLink + `>; rel="deprecation"`
+	}
+
+	return func(c *fiber.Ctx) error {
+		c.Locals(apiVersionLocalKey, version)
+		c.Set(APIVersionHeader, version)
+		if deprecation != "" {
+			c.Set("Deprecation", deprecation)
+		}
+		if sunset != "" {
+			c.Set("Sunset", sunset)
+		}
+		if link != "" {
+			c.Append(fiber.HeaderLink, link)
+		}
+		return c.Next()
+	}
+}
+
+// normalizeAPIVersion 规范化版本号：去除首尾斜杠，纯数字补充 v 前缀（"1" -> "v1"）
+func normalizeAPIVersion(version string) string {
+	version = strings.Trim(version, "/")
+	if version != "" && version[0] >= '0' && version[0] <= '9' {
+		version = "v" + version
+	}
+	return version
+}
+
+// RouteInfo 已注册路由的描述
+type RouteInfo struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// 路由名称（通过 fiber Name 设置，可为空）
+	Name string `json:"name,omitempty"`
+	// 最终处理器的函数名
+	Handler string `json:"handler"`
+}
+
+// ListRoutes 返回应用中注册的路由（不含 Use 注册的中间件，GET 路由自动生成的 HEAD 路由不重复列出），按路径与方法排序
+func ListRoutes(app *fiber.App) []RouteInfo {
+	routes := app.GetRoutes(true)
+	gets := make(map[string]struct{})
+	for _, route := range routes {
+		if route.Method == fiber.MethodGet {
+			gets[route.Path] = struct{}{}
+		}
+	}
+
+	result := make([]RouteInfo, 0, len(routes))
+	for _, route := range routes {
+		if route.Method == fiber.MethodHead {
+			if _, ok := gets[route.Path]; ok {
+				continue
+			}
+		}
+		info := RouteInfo{Method: route.Method, Path: route.Path, Name: route.Name}
+		if len(route.Handlers) > 0 {
+			info.Handler = handlerName(route.Handlers[len(route.Handlers)-1])
+		}
+		result = append(result, info)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Path != result[j].Path {
+			return result[i].Path < result[j].Path
+		}
+		return result[i].Method < result[j].Method
+	})
+	return result
+}
+
+// Routes 返回服务器注册的路由，用于生成文档与运维接口
+func (s *Server) Routes() []RouteInfo {
+	return ListRoutes(s.app)
+}
+
+// handlerName 返回处理器的函数名
+func handlerName(handler fiber.Handler) string {
+	if fn := runtime.FuncForPC(reflect.ValueOf(handler).Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return ""
+}
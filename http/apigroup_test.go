@@ -0,0 +1,62 @@
+package http
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func listUsers(c *fiber.Ctx) error {
+	return c.SendString(APIVersion(c))
+}
+
+func TestNewAPIGroup(t *testing.T) {
+	app := fiber.New()
+	var v1Middleware int
+	v1 := NewAPIGroup(app, "1", APIGroupOptions{
+		DeprecatedAt:    time.Unix(1700000000, 0),
+		Sunset:          time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		DeprecationLink: "https://docs.example.com/migrate-v2",
+		Middlewares: []fiber.Handler{func(c *fiber.Ctx) error {
+			v1Middleware++
+			return c.Next()
+		}},
+	})
+	v1.Get("/users", listUsers)
+	v2 := NewAPIGroup(app, "v2")
+	v2.Get("/users", listUsers).Name("users.list")
+	NewAPIGroup(app, "v3", APIGroupOptions{Prefix: "/"}).Post("/users", listUsers)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/users", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.Header.Get(APIVersionHeader) != "v1" || resp.Header.Get("Deprecation") != "@1700000000" ||
+		resp.Header.Get("Sunset") != "Wed, 01 Jan 2025 00:00:00 GMT" ||
+		resp.Header.Get("Link") != `<https://docs.example.com/migrate-v2>; rel="deprecation"` || v1Middleware != 1 {
+		t.Fatalf("unexpected v1 headers: %v middleware=%d", resp.Header, v1Middleware)
+	}
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/api/v2/users", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.Header.Get(APIVersionHeader) != "v2" || resp.Header.Get("Deprecation") != "" || v1Middleware != 1 {
+		t.Fatalf("unexpected v2 headers: %v middleware=%d", resp.Header, v1Middleware)
+	}
+	if resp, _ := app.Test(httptest.NewRequest("POST", "/v3/users", nil)); resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected /v3/users to be routed, got %d", resp.StatusCode)
+	}
+
+	routes := ListRoutes(app)
+	if len(routes) != 3 {
+		t.Fatalf("expected 3 routes, got %+v", routes)
+	}
+	if routes[1].Method != fiber.MethodGet || routes[1].Path != "/api/v2/users" || routes[1].Name != "users.list" ||
+		!strings.HasSuffix(routes[1].Handler, "http.listUsers") {
+		t.Fatalf("unexpected route info: %+v", routes[1])
+	}
+}
@@ -0,0 +1,27 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/team-dandelion/quickgo/authz"
+)
+
+// RequirePermission 要求请求身份拥有全部权限的中间件（使用 authz.SetDefault 设置的授权器）
+// 身份由 authz.FiberIdentity 或鉴权中间件写入 UserContext（见 RequestContext）；未携带身份返回 401，缺少权限返回 403
+func RequirePermission(permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authorizer := authz.Default()
+		if authorizer == nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "authz is not configured")
+		}
+		switch err := authorizer.Check(RequestContext(c), permissions...); {
+		case errors.Is(err, authz.ErrUnauthenticated):
+			return fiber.NewError(fiber.StatusUnauthorized, err.Error())
+		case err != nil:
+			return fiber.NewError(fiber.StatusForbidden, err.Error())
+		}
+		return c.Next()
+	}
+}
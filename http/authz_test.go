@@ -0,0 +1,53 @@
+package http
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/team-dandelion/quickgo/authz"
+)
+
+func TestRequirePermission(t *testing.T) {
+	authorizer, err := authz.New(authz.StaticSource(authz.Role{Name: "viewer", Permissions: []string{"user:read"}}), nil)
+	if err != nil {
+		t.Fatalf("authz.New failed: %v", err)
+	}
+	authz.SetDefault(authorizer)
+	defer authz.SetDefault(nil)
+
+	app := fiber.New()
+	app.Use(authz.FiberIdentity(func(c *fiber.Ctx) (authz.Identity, bool) {
+		user := c.Get("X-Test-User")
+		return authz.Identity{Subject: user, Roles: strings.Split(c.Get("X-Test-Roles"), ",")}, user != ""
+	}))
+	app.Get("/users", RequirePermission("user:read"), func(c *fiber.Ctx) error {
+		return c.SendString(c.Context().UserValue(authz.MetadataRoles).(string))
+	})
+	app.Delete("/users", RequirePermission("user:delete"), func(c *fiber.Ctx) error { return nil })
+
+	cases := []struct {
+		method, user string
+		want         int
+	}{
+		{"GET", "", fiber.StatusUnauthorized},
+		{"GET", "u1", fiber.StatusOK},
+		{"DELETE", "u1", fiber.StatusForbidden},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, "/users", nil)
+		if c.user != "" {
+			req.Header.Set("X-Test-User", c.user)
+			req.Header.Set("X-Test-Roles", "viewer")
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		if resp.StatusCode != c.want {
+			t.Errorf("%s as %q: got %d, want %d", c.method, c.user, resp.StatusCode, c.want)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+
+	"github.com/team-dandelion/quickgo/cache"
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+const (
+	// CacheStatusHeader 响应缓存状态响应头（HIT / MISS）
+	CacheStatusHeader = "X-Cache"
+
+	defaultCacheTTL         = time.Minute
+	defaultCacheMaxBodySize = 1 << 20
+)
+
+// CacheConfig 响应缓存中间件配置
+type CacheConfig struct {
+	// 缓存存储（必需），cache.NewRedisResponseStore 或 cache.NewMemoryResponseStore
+	Store *cache.ResponseStore
+	// 默认缓存时间（默认 1m），响应 Cache-Control 的 s-maxage / max-age 更短时使用后者
+	TTL time.Duration
+	// 按路由前缀设置缓存时间（最长前缀优先），如 "/api/products": 5 * time.Minute；负数表示该路由不缓存
+	RouteTTLs map[string]time.Duration
+	// 参与缓存 key 的请求头（如 Accept-Language、X-Tenant-ID）；包含 Cookie 时携带 Cookie 的请求按 Cookie 独立缓存
+	VaryHeaders []string
+	// 是否缓存携带 Authorization 的请求（默认不缓存；开启后 Authorization 参与缓存 key，各凭证独立缓存）
+	CacheAuthorized bool
+	// 可缓存的最大响应体字节数（默认 1MB）
+	MaxBodySize int
+	// 额外的缓存 key 片段（可选，如按用户或租户区分）；设置后携带 Cookie 的请求也会缓存，由 KeyFunc 负责区分用户
+	KeyFunc func(c *fiber.Ctx) string
+}
+
+// CacheMiddleware GET 响应缓存中间件，缓存 key 由 路由 + 路径 + 排序后的查询参数 + VaryHeaders 组成
+// 遵循 Cache-Control：请求 no-store 不读不写，no-cache / max-age=0 跳过读取并刷新缓存；
+// 响应 no-store / no-cache / private、Vary: *、携带 Set-Cookie 或非 200 时不缓存，响应 Vary 列出的请求头值不同视为未命中；
+// 携带 Authorization（未开启 CacheAuthorized）或 Cookie（VaryHeaders 未包含 Cookie 且未设置 KeyFunc）的请求不缓存
+// 路由取 c.Route().Path（如 /api/users/:id），通过 app.Use 注册时取请求路径；InvalidateRoute 使用同一路由失效缓存
+func CacheMiddleware(config CacheConfig) fiber.Handler {
+	if config.TTL <= 0 {
+		config.TTL = defaultCacheTTL
+	}
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = defaultCacheMaxBodySize
+	}
+
+	return func(c *fiber.Ctx) error {
+		if config.Store == nil || c.Method() != fiber.MethodGet {
+			return c.Next()
+		}
+		route := CacheRoute(c)
+		ttl := config.routeTTL(route)
+		if ttl < 0 {
+			return c.Next()
+		}
+		request := parseCacheControl(c.Get(fiber.HeaderCacheControl))
+		if request.noStore {
+			return c.Next()
+		}
+		if c.Get(fiber.HeaderAuthorization) != "" && !config.CacheAuthorized {
+			return c.Next()
+		}
+		// 以 Cookie 鉴权的请求可能返回用户私有的响应，缓存 key 未区分 Cookie 时不缓存
+		if c.Get(fiber.HeaderCookie) != "" && !config.keyCoversCookie() {
+			return c.Next()
+		}
+
+		ctx := c.UserContext()
+		key := cache.ResponseKey(route, config.digest(c))
+		if !request.noCache && !(request.hasMaxAge && request.maxAge == 0) {
+			entry, err := config.Store.Get(ctx, key)
+			if err == nil && varyMatches(c, entry) {
+				return writeCachedResponse(c, entry)
+			}
+			if err != nil && !errors.Is(err, cache.ErrCacheMiss) {
+				logger.Warn(ctx, "HTTP response cache read failed: route=%s, error=%v", route, err)
+			}
+		}
+
+		// 外层中间件已设置的响应头（CORS、链路 ID 等）按请求生成，不随缓存回放
+		outer := make(map[string]struct{})
+		c.Response().Header.VisitAll(func(key, _ []byte) {
+			outer[strings.ToLower(string(key))] = struct{}{}
+		})
+		// Content-Type 总是存在默认值，由处理器决定
+		delete(outer, "content-type")
+		if err := c.Next(); err != nil {
+			return err
+		}
+		c.Set(CacheStatusHeader, "MISS")
+
+		entry, ttl, ok := config.cacheableResponse(c, ttl, outer)
+		if !ok {
+			return nil
+		}
+		if err := config.Store.Set(context.WithoutCancel(ctx), key, entry, ttl); err != nil {
+			logger.Warn(ctx, "HTTP response cache write failed: route=%s, error=%v", route, err)
+		}
+		return nil
+	}
+}
+
+// CacheRoute 返回响应缓存使用的路由（与 InvalidateRoute 的参数一致）
+func CacheRoute(c *fiber.Ctx) string {
+	if route := c.Route(); route != nil && route.Method != "USE" {
+		return route.Path
+	}
+	return c.Path()
+}
+
+// routeTTL 返回路由的缓存时间（最长前缀优先）
+func (config CacheConfig) routeTTL(route string) time.Duration {
+	ttl := config.TTL
+	matched := -1
+	for prefix, t := range config.RouteTTLs {
+		if len(prefix) > matched && strings.HasPrefix(route, prefix) {
+			ttl, matched = t, len(prefix)
+		}
+	}
+	return ttl
+}
+
+// keyCoversCookie 缓存 key 是否区分 Cookie（VaryHeaders 包含 Cookie 或设置了 KeyFunc）
+func (config CacheConfig) keyCoversCookie() bool {
+	if config.KeyFunc != nil {
+		return true
+	}
+	for _, name := range config.VaryHeaders {
+		if strings.EqualFold(strings.TrimSpace(name), fiber.HeaderCookie) {
+			return true
+		}
+	}
+	return false
+}
+
+// digest 计算请求的缓存摘要
+func (config CacheConfig) digest(c *fiber.Ctx) string {
+	var args fasthttp.Args
+	c.Request().URI().QueryArgs().CopyTo(&args)
+	args.Sort(bytes.Compare)
+
+	h := sha256.New()
+	write := func(parts ...[]byte) {
+		for _, part := range parts {
+			h.Write(part)
+			h.Write([]byte{0})
+		}
+	}
+	write([]byte(c.Path()), args.QueryString())
+	for _, name := range config.VaryHeaders {
+		write([]byte(strings.ToLower(name)), c.Request().Header.Peek(name))
+	}
+	if config.CacheAuthorized {
+		write(c.Request().Header.Peek(fiber.HeaderAuthorization))
+	}
+	if config.KeyFunc != nil {
+		write([]byte(config.KeyFunc(c)))
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// cacheableResponse 判断响应是否可缓存，返回缓存条目与缓存时间
+func (config CacheConfig) cacheableResponse(c *fiber.Ctx, ttl time.Duration, outer map[string]struct{}) (*cache.ResponseEntry, time.Duration, bool) {
+	resp := c.Response()
+	if resp.StatusCode() != fiber.StatusOK || resp.IsBodyStream() || len(resp.Body()) > config.MaxBodySize {
+		return nil, 0, false
+	}
+	if len(resp.Header.Peek(fiber.HeaderSetCookie)) > 0 {
+		return nil, 0, false
+	}
+	directives := parseCacheControl(string(resp.Header.Peek(fiber.HeaderCacheControl)))
+	if directives.noStore || directives.noCache || directives.private {
+		return nil, 0, false
+	}
+	if directives.hasMaxAge {
+		ttl = min(ttl, time.Duration(directives.maxAge)*time.Second)
+	}
+	if ttl <= 0 {
+		return nil, 0, false
+	}
+	vary, ok := responseVary(c)
+	if !ok {
+		return nil, 0, false
+	}
+
+	entry := &cache.ResponseEntry{
+		Status:   resp.StatusCode(),
+		Body:     append([]byte(nil), resp.Body()...),
+		StoredAt: time.Now(),
+		Vary:     vary,
+	}
+	resp.Header.VisitAll(func(key, value []byte) {
+		name := string(key)
+		if _, ok := outer[strings.ToLower(name)]; !ok && !skipCachedHeader(name) {
+			entry.Header = append(entry.Header, [2]string{name, string(value)})
+		}
+	})
+	return entry, ttl, true
+}
+
+// responseVary 返回响应 Vary 列出的请求头及当前请求的值，Vary: * 时不可缓存
+func responseVary(c *fiber.Ctx) ([][2]string, bool) {
+	var vary [][2]string
+	for _, value := range c.Response().Header.PeekAll(fiber.HeaderVary) {
+		for _, name := range strings.Split(string(value), ",") {
+			name = strings.TrimSpace(name)
+			switch {
+			case name == "":
+				continue
+			case name == "*":
+				return nil, false
+			}
+			vary = append(vary, [2]string{name, c.Get(name)})
+		}
+	}
+	return vary, true
+}
+
+// varyMatches 判断当前请求的 Vary 请求头值是否与缓存条目写入时一致
+func varyMatches(c *fiber.Ctx, entry *cache.ResponseEntry) bool {
+	for _, vary := range entry.Vary {
+		if c.Get(vary[0]) != vary[1] {
+			return false
+		}
+	}
+	return true
+}
+
+// skipCachedHeader 不随缓存回放的响应头（逐跳头、长度、日期、缓存状态与请求级的链路 ID）
+func skipCachedHeader(name string) bool {
+	switch strings.ToLower(name) {
+	case "connection", "transfer-encoding", "content-length", "date", "set-cookie", "age",
+		strings.ToLower(CacheStatusHeader), strings.ToLower(TraceIDHeader):
+		return true
+	}
+	return false
+}
+
+// writeCachedResponse 回放缓存的响应
+func writeCachedResponse(c *fiber.Ctx, entry *cache.ResponseEntry) error {
+	for _, header := range entry.Header {
+		c.Response().Header.Add(header[0], header[1])
+	}
+	age := max(0, int(time.Since(entry.StoredAt)/time.Second))
+	c.Set(fiber.HeaderAge, strconv.Itoa(age))
+	c.Set(CacheStatusHeader, "HIT")
+	return c.Status(entry.Status).Send(entry.Body)
+}
+
+// cacheControl 解析后的 Cache-Control 指令
+type cacheControl struct {
+	noStore   bool
+	noCache   bool
+	private   bool
+	hasMaxAge bool
+	maxAge    int
+}
+
+// parseCacheControl 解析 Cache-Control，s-maxage 优先于 max-age
+func parseCacheControl(value string) cacheControl {
+	var cc cacheControl
+	sharedMaxAge := -1
+	for _, directive := range strings.Split(value, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-store":
+			cc.noStore = true
+		case "no-cache":
+			cc.noCache = true
+		case "private":
+			cc.private = true
+		case "max-age":
+			if n, err := strconv.Atoi(strings.Trim(arg, `"`)); err == nil && n >= 0 {
+				cc.hasMaxAge, cc.maxAge = true, n
+			}
+		case "s-maxage":
+			if n, err := strconv.Atoi(strings.Trim(arg, `"`)); err == nil && n >= 0 {
+				sharedMaxAge = n
+			}
+		}
+	}
+	if sharedMaxAge >= 0 {
+		cc.hasMaxAge, cc.maxAge = true, sharedMaxAge
+	}
+	return cc
+}
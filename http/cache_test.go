@@ -0,0 +1,193 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/team-dandelion/quickgo/cache"
+)
+
+func newCacheTestApp(t *testing.T, config CacheConfig) (*fiber.App, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Set("Access-Control-Allow-Origin", c.Get("Origin"))
+		return c.Next()
+	})
+	cached := CacheMiddleware(config)
+	app.Get("/products/:id", cached, func(c *fiber.Ctx) error {
+		calls.Add(1)
+		c.Set("ETag", "v1")
+		return c.JSON(fiber.Map{"id": c.Params("id"), "q": c.Query("q")})
+	})
+	app.Get("/private", cached, func(c *fiber.Ctx) error {
+		calls.Add(1)
+		c.Set(fiber.HeaderCacheControl, "private, max-age=60")
+		return c.SendString("secret")
+	})
+	return app, &calls
+}
+
+func cacheRequest(t *testing.T, app *fiber.App, target string, headers map[string]string) (*httptestResponse, string) {
+	t.Helper()
+	req := httptest.NewRequest("GET", target, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	return &httptestResponse{status: resp.StatusCode, header: resp.Header}, string(body)
+}
+
+type httptestResponse struct {
+	status int
+	header map[string][]string
+}
+
+func (r *httptestResponse) get(name string) string {
+	if values := r.header[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func TestCacheMiddlewareCachesAndInvalidates(t *testing.T) {
+	store, err := cache.NewMemoryResponseStore(100, 0)
+	if err != nil {
+		t.Fatalf("NewMemoryResponseStore failed: %v", err)
+	}
+	app, calls := newCacheTestApp(t, CacheConfig{Store: store})
+
+	first, body := cacheRequest(t, app, "/products/1?b=2&a=1", map[string]string{"Origin": "https://a.example.com"})
+	if first.get(CacheStatusHeader) != "MISS" || calls.Load() != 1 {
+		t.Fatalf("expected miss, got %q calls=%d", first.get(CacheStatusHeader), calls.Load())
+	}
+	// 查询参数顺序不影响缓存 key；外层中间件的响应头按请求生成
+	hit, hitBody := cacheRequest(t, app, "/products/1?a=1&b=2", map[string]string{"Origin": "https://b.example.com"})
+	if hit.get(CacheStatusHeader) != "HIT" || calls.Load() != 1 || hitBody != body {
+		t.Fatalf("expected hit with same body, got %q calls=%d body=%s", hit.get(CacheStatusHeader), calls.Load(), hitBody)
+	}
+	if hit.get("Content-Type") != fiber.MIMEApplicationJSON || hit.get("Etag") != "v1" || hit.get("Age") == "" {
+		t.Fatalf("cached headers not replayed: %v", hit.header)
+	}
+	if origins := hit.header["Access-Control-Allow-Origin"]; len(origins) != 1 || origins[0] != "https://b.example.com" {
+		t.Fatalf("outer middleware headers should not be replayed: %v", origins)
+	}
+
+	// 不同路径参数独立缓存
+	cacheRequest(t, app, "/products/2", nil)
+	if calls.Load() != 2 {
+		t.Fatalf("different path should miss, calls=%d", calls.Load())
+	}
+
+	// 请求 no-cache 跳过读取并刷新缓存；Authorization 默认不缓存
+	cacheRequest(t, app, "/products/1?a=1&b=2", map[string]string{"Cache-Control": "no-cache"})
+	cacheRequest(t, app, "/products/1?a=1&b=2", map[string]string{"Authorization": "Bearer x"})
+	if calls.Load() != 4 {
+		t.Fatalf("no-cache and authorized requests should reach handler, calls=%d", calls.Load())
+	}
+
+	if err := store.InvalidateRoute(context.Background(), "/products/:id"); err != nil {
+		t.Fatalf("InvalidateRoute failed: %v", err)
+	}
+	if resp, _ := cacheRequest(t, app, "/products/1?a=1&b=2", nil); resp.get(CacheStatusHeader) != "MISS" || calls.Load() != 5 {
+		t.Fatalf("expected miss after invalidation, calls=%d", calls.Load())
+	}
+
+	// 响应 private 不缓存
+	cacheRequest(t, app, "/private", nil)
+	cacheRequest(t, app, "/private", nil)
+	if calls.Load() != 7 {
+		t.Fatalf("private response should not be cached, calls=%d", calls.Load())
+	}
+}
+
+func TestCacheMiddlewareDoesNotShareCookieSessions(t *testing.T) {
+	store, _ := cache.NewMemoryResponseStore(100, 0)
+	app := fiber.New()
+	app.Get("/me", CacheMiddleware(CacheConfig{Store: store}), func(c *fiber.Ctx) error {
+		return c.SendString("user=" + c.Cookies("session"))
+	})
+
+	cacheRequest(t, app, "/me", map[string]string{"Cookie": "session=alice"})
+	resp, body := cacheRequest(t, app, "/me", map[string]string{"Cookie": "session=bob"})
+	if body != "user=bob" || resp.get(CacheStatusHeader) == "HIT" {
+		t.Fatalf("expected bob's own response, got %q (%s)", body, resp.get(CacheStatusHeader))
+	}
+
+	// VaryHeaders 包含 Cookie 时按 Cookie 独立缓存
+	app = fiber.New()
+	app.Get("/me", CacheMiddleware(CacheConfig{Store: store, VaryHeaders: []string{"Cookie"}}), func(c *fiber.Ctx) error {
+		return c.SendString("user=" + c.Cookies("session"))
+	})
+	cacheRequest(t, app, "/me", map[string]string{"Cookie": "session=alice"})
+	if resp, body := cacheRequest(t, app, "/me", map[string]string{"Cookie": "session=alice"}); resp.get(CacheStatusHeader) != "HIT" || body != "user=alice" {
+		t.Fatalf("expected cached alice response, got %q (%s)", body, resp.get(CacheStatusHeader))
+	}
+	if resp, body := cacheRequest(t, app, "/me", map[string]string{"Cookie": "session=bob"}); resp.get(CacheStatusHeader) == "HIT" || body != "user=bob" {
+		t.Fatalf("expected bob's own response, got %q (%s)", body, resp.get(CacheStatusHeader))
+	}
+}
+
+func TestCacheMiddlewareHonoursResponseVary(t *testing.T) {
+	store, _ := cache.NewMemoryResponseStore(100, 0)
+	var calls atomic.Int32
+	app := fiber.New()
+	cached := CacheMiddleware(CacheConfig{Store: store})
+	app.Get("/greeting", cached, func(c *fiber.Ctx) error {
+		calls.Add(1)
+		c.Vary(fiber.HeaderAcceptLanguage)
+		return c.SendString("lang=" + c.Get(fiber.HeaderAcceptLanguage))
+	})
+	app.Get("/any", cached, func(c *fiber.Ctx) error {
+		calls.Add(1)
+		c.Set(fiber.HeaderVary, "*")
+		return c.SendString("any")
+	})
+
+	cacheRequest(t, app, "/greeting", map[string]string{"Accept-Language": "en"})
+	if resp, body := cacheRequest(t, app, "/greeting", map[string]string{"Accept-Language": "fr"}); resp.get(CacheStatusHeader) == "HIT" || body != "lang=fr" {
+		t.Fatalf("expected vary mismatch to miss, got %q (%s)", body, resp.get(CacheStatusHeader))
+	}
+	if resp, body := cacheRequest(t, app, "/greeting", map[string]string{"Accept-Language": "fr"}); resp.get(CacheStatusHeader) != "HIT" || body != "lang=fr" {
+		t.Fatalf("expected matching vary to hit, got %q (%s)", body, resp.get(CacheStatusHeader))
+	}
+
+	calls.Store(0)
+	cacheRequest(t, app, "/any", nil)
+	cacheRequest(t, app, "/any", nil)
+	if calls.Load() != 2 {
+		t.Fatalf("Vary: * response should not be cached, calls=%d", calls.Load())
+	}
+}
+
+func TestCacheMiddlewareRouteTTLs(t *testing.T) {
+	store, _ := cache.NewMemoryResponseStore(100, 0)
+	app, calls := newCacheTestApp(t, CacheConfig{Store: store, RouteTTLs: map[string]time.Duration{"/products": -1}})
+
+	cacheRequest(t, app, "/products/1", nil)
+	cacheRequest(t, app, "/products/1", nil)
+	if calls.Load() != 2 {
+		t.Fatalf("route with negative ttl should not be cached, calls=%d", calls.Load())
+	}
+}
+
+func TestParseCacheControl(t *testing.T) {
+	cc := parseCacheControl(`public, max-age=60, s-maxage="30"`)
+	if !cc.hasMaxAge || cc.maxAge != 30 || cc.noStore || cc.private {
+		t.Fatalf("unexpected directives: %+v", cc)
+	}
+	if cc := parseCacheControl("No-Store"); !cc.noStore {
+		t.Fatalf("expected no-store, got %+v", cc)
+	}
+}
@@ -0,0 +1,198 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// 压缩级别
+const (
+	CompressLevelDefault         = "default"
+	CompressLevelBestSpeed       = "bestSpeed"
+	CompressLevelBestCompression = "bestCompression"
+)
+
+// 支持的压缩编码
+const (
+	EncodingGzip   = "gzip"
+	EncodingBrotli = "br"
+)
+
+const defaultCompressMinSize = 1024
+
+// defaultCompressContentTypes 默认压缩的 Content-Type 前缀
+var defaultCompressContentTypes = []string{
+	"text/",
+	fiber.MIMEApplicationJSON,
+	fiber.MIMEApplicationJavaScript,
+	fiber.MIMEApplicationXML,
+	"application/problem+json",
+	"image/svg+xml",
+}
+
+// CompressConfig 响应压缩配置
+type CompressConfig struct {
+	// 启用的编码，按优先级排列（默认 br、gzip），客户端均支持时使用靠前的编码
+	Encodings []string
+	// 压缩级别：default（默认）、bestSpeed、bestCompression
+	Level string
+	// 最小压缩字节数（默认 1024），更小的响应不压缩
+	MinSize int
+	// 压缩的 Content-Type 前缀（默认 text/、JSON、JavaScript、XML、SVG）
+	ContentTypes []string
+	// 不压缩的路径前缀（如 /metrics、/download）
+	ExcludePaths []string
+}
+
+// Validate 校验压缩配置
+func (c CompressConfig) Validate() error {
+	for _, encoding := range c.Encodings {
+		if encoding != EncodingGzip && encoding != EncodingBrotli {
+			return fmt.Errorf("unsupported compression encoding %q (gzip or br)", encoding)
+		}
+	}
+	switch c.Level {
+	case "", CompressLevelDefault, CompressLevelBestSpeed, CompressLevelBestCompression:
+	default:
+		return fmt.Errorf("unsupported compression level %q", c.Level)
+	}
+	if c.MinSize < 0 {
+		return fmt.Errorf("compression minSize must be non-negative: %d", c.MinSize)
+	}
+	return nil
+}
+
+// CompressMiddleware 响应压缩中间件，按 Accept-Encoding 协商 br / gzip 并设置 Vary: Accept-Encoding
+// 不压缩：HEAD 请求、流式响应（Stream / SSE / chunked）、已设置 Content-Encoding、非 2xx、204、
+// 小于 MinSize 或不在 ContentTypes 中的响应，以及 ExcludePaths 下的路径
+// 配置无效时 panic（可先调用 CompressConfig.Validate 校验）
+func CompressMiddleware(config CompressConfig) fiber.Handler {
+	if err := config.Validate(); err != nil {
+		panic(err)
+	}
+	if len(config.Encodings) == 0 {
+		config.Encodings = []string{EncodingBrotli, EncodingGzip}
+	}
+	if config.MinSize == 0 {
+		config.MinSize = defaultCompressMinSize
+	}
+	if len(config.ContentTypes) == 0 {
+		config.ContentTypes = defaultCompressContentTypes
+	}
+	gzipLevel, brotliLevel := compressLevels(config.Level)
+
+	return func(c *fiber.Ctx) error {
+		if c.Method() == fiber.MethodHead || config.excluded(c.Path()) {
+			return c.Next()
+		}
+		if err := c.Next(); err != nil {
+			return err
+		}
+		if !config.compressible(c) {
+			return nil
+		}
+		// 响应可压缩时无论是否协商成功都需 Vary，避免共享缓存混用编码
+		c.Vary(fiber.HeaderAcceptEncoding)
+		encoding := negotiateEncoding(c.Get(fiber.HeaderAcceptEncoding), config.Encodings)
+		if encoding == "" {
+			return nil
+		}
+
+		resp := c.Response()
+		var compressed []byte
+		if encoding == EncodingBrotli {
+			compressed = fasthttp.AppendBrotliBytesLevel(nil, resp.Body(), brotliLevel)
+		} else {
+			compressed = fasthttp.AppendGzipBytesLevel(nil, resp.Body(), gzipLevel)
+		}
+		if len(compressed) >= len(resp.Body()) {
+			return nil
+		}
+		resp.SetBodyRaw(compressed)
+		resp.Header.Set(fiber.HeaderContentEncoding, encoding)
+		// 强 ETag 对应未压缩的表示，压缩后降级为弱 ETag
+		if etag := resp.Header.Peek(fiber.HeaderETag); len(etag) > 0 && !strings.HasPrefix(string(etag), "W/") {
+			resp.Header.Set(fiber.HeaderETag, "W/"+string(etag))
+		}
+		return nil
+	}
+}
+
+// excluded 判断路径是否不压缩
+func (config CompressConfig) excluded(path string) bool {
+	for _, prefix := range config.ExcludePaths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// compressible 判断响应是否可压缩
+func (config CompressConfig) compressible(c *fiber.Ctx) bool {
+	resp := c.Response()
+	status := resp.StatusCode()
+	if status < fiber.StatusOK || status >= fiber.StatusMultipleChoices || status == fiber.StatusNoContent {
+		return false
+	}
+	if resp.IsBodyStream() || len(resp.Header.Peek(fiber.HeaderContentEncoding)) > 0 || len(resp.Body()) < config.MinSize {
+		return false
+	}
+	contentType := string(resp.Header.ContentType())
+	if strings.HasPrefix(contentType, "text/event-stream") {
+		return false
+	}
+	for _, prefix := range config.ContentTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// compressLevels 返回压缩级别对应的 gzip 与 brotli 级别
+func compressLevels(level string) (int, int) {
+	switch level {
+	case CompressLevelBestSpeed:
+		return fasthttp.CompressBestSpeed, fasthttp.CompressBrotliBestSpeed
+	case CompressLevelBestCompression:
+		return fasthttp.CompressBestCompression, fasthttp.CompressBrotliBestCompression
+	default:
+		return fasthttp.CompressDefaultCompression, fasthttp.CompressBrotliDefaultCompression
+	}
+}
+
+// negotiateEncoding 按 Accept-Encoding 选择编码（q=0 表示拒绝，* 匹配任意编码），返回空表示不压缩
+func negotiateEncoding(acceptEncoding string, encodings []string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		accepted[name] = quality > 0
+	}
+	for _, encoding := range encodings {
+		if ok, listed := accepted[encoding]; listed {
+			if ok {
+				return encoding
+			}
+			continue
+		}
+		if accepted["*"] {
+			return encoding
+		}
+	}
+	return ""
+}
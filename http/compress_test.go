@@ -0,0 +1,93 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCompressMiddleware(t *testing.T) {
+	payload := strings.Repeat(`{"name":"quickgo","tags":["a","b"]},`, 100)
+	app := fiber.New()
+	app.Use(CompressMiddleware(CompressConfig{ExcludePaths: []string{"/raw"}}))
+	app.Get("/json", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderETag, `"v1"`)
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.SendString(payload)
+	})
+	app.Get("/small", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"ok": true})
+	})
+	app.Get("/raw/json", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.SendString(payload)
+	})
+	app.Get("/binary", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEOctetStream)
+		return c.SendString(payload)
+	})
+	app.Get("/events", func(c *fiber.Ctx) error {
+		return SSE(c, func(w *SSEWriter) error {
+			return w.Send(SSEEvent{Data: payload})
+		}, SSEConfig{HeartbeatInterval: -1})
+	})
+
+	var etag string
+	do := func(target, acceptEncoding string) (string, string, []byte) {
+		req := httptest.NewRequest("GET", target, nil)
+		if acceptEncoding != "" {
+			req.Header.Set(fiber.HeaderAcceptEncoding, acceptEncoding)
+		}
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		etag = resp.Header.Get(fiber.HeaderETag)
+		return resp.Header.Get(fiber.HeaderContentEncoding), resp.Header.Get(fiber.HeaderVary), body
+	}
+
+	encoding, vary, body := do("/json", "gzip, deflate")
+	if encoding != EncodingGzip || vary != fiber.HeaderAcceptEncoding || etag != `W/"v1"` {
+		t.Fatalf("expected gzip with Vary and weak etag, got encoding=%q vary=%q etag=%q", encoding, vary, etag)
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("invalid gzip body: %v", err)
+	}
+	if plain, _ := io.ReadAll(reader); string(plain) != payload {
+		t.Fatal("gzip body does not match payload")
+	}
+
+	if encoding, _, _ := do("/json", "gzip;q=0.5, br"); encoding != EncodingBrotli {
+		t.Fatalf("expected br, got %q", encoding)
+	}
+	if encoding, _, _ := do("/json", "br;q=0, *"); encoding != EncodingGzip {
+		t.Fatalf("expected gzip when br is refused, got %q", encoding)
+	}
+	if encoding, vary, body := do("/json", ""); encoding != "" || vary != fiber.HeaderAcceptEncoding || string(body) != payload {
+		t.Fatalf("expected identity with Vary, got encoding=%q vary=%q", encoding, vary)
+	}
+	for _, target := range []string{"/small", "/raw/json", "/binary", "/events"} {
+		if encoding, _, _ := do(target, "gzip, br"); encoding != "" {
+			t.Fatalf("%s should not be compressed, got %q", target, encoding)
+		}
+	}
+	if _, _, body := do("/events", "gzip"); !strings.Contains(string(body), "data: "+payload[:20]) {
+		t.Fatalf("unexpected sse body: %.60s", body)
+	}
+}
+
+func TestCompressConfigValidate(t *testing.T) {
+	if err := (CompressConfig{Encodings: []string{"deflate"}}).Validate(); err == nil {
+		t.Fatal("expected unsupported encoding to be rejected")
+	}
+	if err := (CompressConfig{Level: "max"}).Validate(); err == nil {
+		t.Fatal("expected unsupported level to be rejected")
+	}
+}
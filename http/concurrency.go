@@ -0,0 +1,182 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// ConcurrencyLimitConfig 并发限制中间件配置
+// 与限流不同，并发限制约束的是同时处理中的请求数量，适合保护报表导出等耗时接口
+type ConcurrencyLimitConfig struct {
+	// 每个路由允许同时处理的最大请求数（默认 10）
+	MaxConcurrent int
+	// 排队等待的最长时间，超时返回 503（为 0 时不排队，直接返回 503）
+	MaxWait time.Duration
+	// 最大排队请求数（为 0 时不限制排队数量，仅受 MaxWait 约束）
+	MaxQueue int
+	// 自定义分组 key（默认使用 "方法 路由路径"）
+	// 注意：通过 app.Use 全局注册时路由路径为挂载前缀，建议直接注册在具体路由上
+	KeyFunc func(c *fiber.Ctx) string
+}
+
+// routeSemaphore 单个路由的并发信号量
+type routeSemaphore struct {
+	slots   chan struct{}
+	waiting atomic.Int32
+}
+
+// ConcurrencyLimitMiddleware 按路由限制并发请求数的中间件
+// 超过并发上限的请求最多排队 MaxWait，仍无法获取执行槽位时返回 503
+func ConcurrencyLimitMiddleware(config ConcurrencyLimitConfig) fiber.Handler {
+	if config.MaxConcurrent <= 0 {
+		config.MaxConcurrent = 10
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = func(c *fiber.Ctx) string {
+			return c.Method() + " " + c.Route().Path
+		}
+	}
+
+	var mu sync.Mutex
+	semaphores := make(map[string]*routeSemaphore)
+	getSemaphore := func(key string) *routeSemaphore {
+		mu.Lock()
+		defer mu.Unlock()
+		sem, ok := semaphores[key]
+		if !ok {
+			sem = &routeSemaphore{slots: make(chan struct{}, config.MaxConcurrent)}
+			semaphores[key] = sem
+		}
+		return sem
+	}
+
+	return func(c *fiber.Ctx) error {
+		key := config.KeyFunc(c)
+		sem := getSemaphore(key)
+
+		if !sem.acquire(c.UserContext(), config.MaxWait, config.MaxQueue) {
+			ctx := context.Background()
+			if traceID := GetTraceID(c); traceID != "" {
+				ctx = logger.WithTraceID(ctx, traceID)
+			}
+			logger.Warn(ctx, "HTTP concurrency limit exceeded: key=%s, max_concurrent=%d", key, config.MaxConcurrent)
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": "Service Unavailable",
+				"code":  fiber.StatusServiceUnavailable,
+			})
+		}
+		defer sem.release()
+
+		return c.Next()
+	}
+}
+
+// acquire 获取执行槽位，必要时排队等待
+func (s *routeSemaphore) acquire(ctx context.Context, maxWait time.Duration, maxQueue int) bool {
+	select {
+	case s.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if maxWait <= 0 {
+		return false
+	}
+	if maxQueue > 0 && int(s.waiting.Load()) >= maxQueue {
+		return false
+	}
+
+	s.waiting.Add(1)
+	defer s.waiting.Add(-1)
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timer := time.NewTimer(maxWait)
+	defer timer.Stop()
+
+	select {
+	case s.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// release 释放执行槽位
+func (s *routeSemaphore) release() {
+	<-s.slots
+}
+
+// InFlightLimitMiddleware 全局在途请求限制中间件
+// 同时处理中的请求超过 maxInFlight 时直接返回 503，避免请求堆积耗尽内存与连接
+func InFlightLimitMiddleware(maxInFlight int) fiber.Handler {
+	var inFlight atomic.Int64
+	return func(c *fiber.Ctx) error {
+		if maxInFlight > 0 && inFlight.Add(1) > int64(maxInFlight) {
+			inFlight.Add(-1)
+			ctx := context.Background()
+			if traceID := GetTraceID(c); traceID != "" {
+				ctx = logger.WithTraceID(ctx, traceID)
+			}
+			logger.Warn(ctx, "HTTP in-flight limit exceeded: max_in_flight=%d", maxInFlight)
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": "Service Unavailable",
+				"code":  fiber.StatusServiceUnavailable,
+			})
+		}
+		if maxInFlight > 0 {
+			defer inFlight.Add(-1)
+		}
+		return c.Next()
+	}
+}
+
+// RequestTimeoutConfig 请求处理超时配置
+type RequestTimeoutConfig struct {
+	// 默认处理超时（0 不限制）
+	Timeout time.Duration
+	// 按路径前缀设置超时（最长前缀优先），如 "/api/export": time.Minute
+	RouteTimeouts map[string]time.Duration
+}
+
+// RequestTimeoutMiddleware 请求处理超时中间件
+// 超时通过 c.UserContext() 的截止时间传递给处理器（协作式取消，处理器应将该 context 传给下游调用），
+// 处理结束时已超时则返回 408
+func RequestTimeoutMiddleware(config RequestTimeoutConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		timeout := config.Timeout
+		matched := -1
+		path := c.Path()
+		for prefix, t := range config.RouteTimeouts {
+			if len(prefix) > matched && strings.HasPrefix(path, prefix) {
+				timeout, matched = t, len(prefix)
+			}
+		}
+		if timeout <= 0 {
+			return c.Next()
+		}
+
+		parent := c.UserContext()
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		defer cancel()
+		c.SetUserContext(ctx)
+		err := c.Next()
+		c.SetUserContext(parent)
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && parent.Err() == nil {
+			return fiber.NewError(fiber.StatusRequestTimeout, "Request Timeout")
+		}
+		return err
+	}
+}
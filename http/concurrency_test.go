@@ -0,0 +1,137 @@
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestConcurrencyLimitMiddlewareRejectsBeyondMaxWait(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	entered := make(chan struct{}, 2)
+	release := make(chan struct{})
+	app.Get("/export", ConcurrencyLimitMiddleware(ConcurrencyLimitConfig{
+		MaxConcurrent: 1,
+		MaxWait:       20 * time.Millisecond,
+	}), func(c *fiber.Ctx) error {
+		entered <- struct{}{}
+		<-release
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	firstDone := make(chan int, 1)
+	go func() {
+		resp, err := app.Test(httptest.NewRequest("GET", "/export", nil), -1)
+		if err != nil {
+			firstDone <- 0
+			return
+		}
+		firstDone <- resp.StatusCode
+	}()
+	<-entered
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/export", nil), -1)
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusServiceUnavailable {
+		t.Fatalf("expected 503 beyond max wait, got %d", resp.StatusCode)
+	}
+
+	close(release)
+	if status := <-firstDone; status != fiber.StatusOK {
+		t.Fatalf("expected first request to succeed, got %d", status)
+	}
+}
+
+func TestInFlightLimitMiddlewareRejectsBeyondMax(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	entered := make(chan struct{}, 2)
+	release := make(chan struct{})
+	app.Use(InFlightLimitMiddleware(1))
+	app.Get("/slow", func(c *fiber.Ctx) error {
+		entered <- struct{}{}
+		<-release
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/fast", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	firstDone := make(chan int, 1)
+	go func() {
+		resp, err := app.Test(httptest.NewRequest("GET", "/slow", nil), -1)
+		if err != nil {
+			firstDone <- 0
+			return
+		}
+		firstDone <- resp.StatusCode
+	}()
+	<-entered
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/fast", nil), -1)
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusServiceUnavailable {
+		t.Fatalf("expected 503 beyond max in-flight, got %d", resp.StatusCode)
+	}
+
+	close(release)
+	if status := <-firstDone; status != fiber.StatusOK {
+		t.Fatalf("expected first request to succeed, got %d", status)
+	}
+	resp, err = app.Test(httptest.NewRequest("GET", "/fast", nil), -1)
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected in-flight slot to be released, got %d", resp.StatusCode)
+	}
+}
+
+func TestConcurrencyLimitMiddlewareQueuesWithinMaxWait(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	entered := make(chan struct{}, 2)
+	app.Get("/export", ConcurrencyLimitMiddleware(ConcurrencyLimitConfig{
+		MaxConcurrent: 1,
+		MaxWait:       time.Second,
+	}), func(c *fiber.Ctx) error {
+		entered <- struct{}{}
+		time.Sleep(20 * time.Millisecond)
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/other", ConcurrencyLimitMiddleware(ConcurrencyLimitConfig{MaxConcurrent: 1}), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	firstDone := make(chan int, 1)
+	go func() {
+		resp, err := app.Test(httptest.NewRequest("GET", "/export", nil), -1)
+		if err != nil {
+			firstDone <- 0
+			return
+		}
+		firstDone <- resp.StatusCode
+	}()
+	<-entered
+
+	// 其他路由不受影响
+	resp, err := app.Test(httptest.NewRequest("GET", "/other", nil), -1)
+	if err != nil || resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected other route to be unaffected, status=%v, err=%v", resp, err)
+	}
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/export", nil), -1)
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected queued request to succeed, got %d", resp.StatusCode)
+	}
+	if status := <-firstDone; status != fiber.StatusOK {
+		t.Fatalf("expected first request to succeed, got %d", status)
+	}
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/team-dandelion/quickgo/authz"
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// RequestTimeoutHeader 客户端声明的请求超时（Go duration 如 1500ms、2s，或整数毫秒），
+// 由 RequestContext 转换为 context 截止时间并随下游调用传递；只会缩短已有的截止时间
+const RequestTimeoutHeader = "X-Request-Timeout"
+
+// requestContextKey 缓存的请求 context
+type requestContextKey struct{}
+
+// requestContextCancelKey 请求截止时间的 cancel 函数
+type requestContextCancelKey struct{}
+
+// cachedRequestContext RequestContext 的构建结果，UserContext 变化后重新构建
+type cachedRequestContext struct {
+	user context.Context
+	ctx  context.Context
+}
+
+// RequestContext 返回处理器与下游调用使用的请求 context，合并了：
+//   - c.UserContext()（鉴权、租户、超时等中间件写入的值与截止时间）
+//   - tracing 中间件写入的 trace_ctx（OpenTelemetry span）
+//   - Locals 中的 trace_id / span_id（日志关联）
+//   - RequestTimeoutHeader 声明的截止时间
+//   - 已认证的身份（authz.Identity，未写入时通过全局 authz.Authorizer 的 IdentityResolver 解析）
+//
+// 结果缓存在请求内，UserContext 被后续中间件替换时重新构建
+func RequestContext(c *fiber.Ctx) context.Context {
+	user := c.UserContext()
+	if cached, ok := c.Locals(requestContextKey{}).(*cachedRequestContext); ok && cached.user == user {
+		return cached.ctx
+	}
+
+	ctx := user
+	if traceCtx, ok := c.Locals("trace_ctx").(context.Context); ok && traceCtx != nil && traceCtx != user {
+		ctx = mergeContext(ctx, traceCtx)
+	}
+	if logger.GetTraceID(ctx) == "" {
+		if traceID := GetTraceID(c); traceID != "" {
+			ctx = logger.WithTrace(ctx, traceID, GetSpanID(c))
+		}
+	}
+	if _, ok := authz.IdentityFromContext(ctx); !ok {
+		if authorizer := authz.Default(); authorizer != nil {
+			if identity, ok := authorizer.Identity(ctx); ok {
+				ctx = authz.WithIdentity(ctx, identity)
+			}
+		}
+	}
+	if timeout, ok := parseRequestTimeout(c.Get(RequestTimeoutHeader)); ok {
+		deadline := time.Now().Add(timeout)
+		if current, has := ctx.Deadline(); !has || deadline.Before(current) {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadline(ctx, deadline)
+			cancels, _ := c.Locals(requestContextCancelKey{}).([]context.CancelFunc)
+			c.Locals(requestContextCancelKey{}, append(cancels, cancel))
+		}
+	}
+
+	c.Locals(requestContextKey{}, &cachedRequestContext{user: user, ctx: ctx})
+	return ctx
+}
+
+// SetRequestContext 替换请求 context（中间件向 context 写入值时使用），
+// 同时同步 trace_ctx，使 RequestContext、grpcep.RPCCtx 与 c.UserContext() 看到一致的值
+func SetRequestContext(c *fiber.Ctx, ctx context.Context) {
+	c.SetUserContext(ctx)
+	if _, ok := c.Locals("trace_ctx").(context.Context); ok {
+		c.Locals("trace_ctx", ctx)
+	}
+}
+
+// RequestContextMiddleware 在请求开始时构建 RequestContext 并写入 c.UserContext()，
+// 使直接使用 c.UserContext() 的处理器同样获得 trace、身份与截止时间，请求结束时释放截止时间的定时器
+// 默认中间件在链路追踪中间件之后注册
+func RequestContextMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		SetRequestContext(c, RequestContext(c))
+		err := c.Next()
+		if cancels, ok := c.Locals(requestContextCancelKey{}).([]context.CancelFunc); ok {
+			for _, cancel := range cancels {
+				cancel()
+			}
+			c.Locals(requestContextCancelKey{}, nil)
+		}
+		return err
+	}
+}
+
+// parseRequestTimeout 解析 RequestTimeoutHeader，无效或非正数时忽略
+func parseRequestTimeout(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if ms <= 0 {
+			return 0, false
+		}
+		return time.Duration(ms) * time.Millisecond, true
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+// valuesContext 截止时间与取消来自主 context，值优先从主 context 查找，找不到时查找 values
+type valuesContext struct {
+	context.Context
+	values context.Context
+}
+
+func (v valuesContext) Value(key any) any {
+	if value := v.Context.Value(key); value != nil {
+		return value
+	}
+	return v.values.Value(key)
+}
+
+// mergeContext 合并两个 context 的值
+func mergeContext(primary, values context.Context) context.Context {
+	return valuesContext{Context: primary, values: values}
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/team-dandelion/quickgo/authz"
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+type contextTestKey string
+
+func TestRequestContextMergesRequestState(t *testing.T) {
+	authorizer, err := authz.New(authz.StaticSource(), &authz.Config{
+		IdentityResolver: func(ctx context.Context) (authz.Identity, bool) {
+			user, ok := ctx.Value(contextTestKey("user")).(string)
+			return authz.Identity{Subject: user}, ok
+		},
+	})
+	if err != nil {
+		t.Fatalf("authz.New failed: %v", err)
+	}
+	authz.SetDefault(authorizer)
+	defer authz.SetDefault(nil)
+
+	var handlerCtx context.Context
+	var deadlineLeft time.Duration
+	app := fiber.New()
+	app.Use(TraceMiddleware(), RequestContextMiddleware())
+	app.Use(func(c *fiber.Ctx) error {
+		// 模拟 tracing 中间件写入的 trace_ctx 与鉴权中间件写入的 UserContext
+		c.Locals("trace_ctx", context.WithValue(context.Background(), contextTestKey("span"), "s1"))
+		c.SetUserContext(context.WithValue(c.UserContext(), contextTestKey("user"), "u1"))
+		return c.Next()
+	})
+	app.Get("/", func(c *fiber.Ctx) error {
+		handlerCtx = RequestContext(c)
+		if RequestContext(c) != handlerCtx {
+			t.Error("expected request context to be cached")
+		}
+		if deadline, ok := handlerCtx.Deadline(); ok {
+			deadlineLeft = time.Until(deadline)
+		}
+		return nil
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(TraceIDHeader, "trace-1")
+	req.Header.Set(RequestTimeoutHeader, "1500")
+	if _, err := app.Test(req); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	if logger.GetTraceID(handlerCtx) != "trace-1" {
+		t.Fatalf("expected trace id, got %q", logger.GetTraceID(handlerCtx))
+	}
+	if handlerCtx.Value(contextTestKey("span")) != "s1" || handlerCtx.Value(contextTestKey("user")) != "u1" {
+		t.Fatal("expected values from trace_ctx and UserContext to be merged")
+	}
+	if identity, ok := authz.IdentityFromContext(handlerCtx); !ok || identity.Subject != "u1" {
+		t.Fatalf("expected resolved principal, got %+v", identity)
+	}
+	if deadlineLeft <= time.Second || deadlineLeft > 1500*time.Millisecond {
+		t.Fatalf("expected deadline from header, got %v", deadlineLeft)
+	}
+	// 请求结束后释放截止时间
+	if handlerCtx.Err() == nil {
+		t.Fatal("expected request context to be cancelled after the request")
+	}
+}
+
+func TestParseRequestTimeout(t *testing.T) {
+	cases := map[string]time.Duration{"250": 250 * time.Millisecond, "2s": 2 * time.Second, "": 0, "-1": 0, "abc": 0, "0s": 0}
+	for value, want := range cases {
+		got, ok := parseRequestTimeout(value)
+		if got != want || ok != (want > 0) {
+			t.Errorf("parseRequestTimeout(%q) = %v, %v; want %v", value, got, ok, want)
+		}
+	}
+}
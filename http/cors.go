@@ -0,0 +1,131 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+const (
+	defaultCORSAllowMethods = "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS"
+	defaultCORSAllowHeaders = "*"
+)
+
+// ErrCORSWildcardCredentials AllowOrigins 为 "*" 时不能同时允许凭证（浏览器会拒绝该响应）
+var ErrCORSWildcardCredentials = errors.New(`cors: AllowOrigins "*" cannot be combined with AllowCredentials`)
+
+// Validate 校验 CORS 配置
+// 拒绝 "*" 与 AllowCredentials 同时使用、格式错误的源（应为 scheme://host[:port]，支持 https://*.example.com）以及无法编译的源正则
+func (c CORSConfig) Validate() error {
+	origins := c.origins()
+	if c.AllowCredentials && len(origins) == 1 && origins[0] == "*" {
+		return ErrCORSWildcardCredentials
+	}
+	for _, origin := range origins {
+		if origin == "*" {
+			if len(origins) > 1 || len(c.AllowOriginPatterns) > 0 {
+				return errors.New(`cors: "*" cannot be combined with other origins or origin patterns`)
+			}
+			continue
+		}
+		if err := validateOrigin(origin); err != nil {
+			return err
+		}
+	}
+	for _, pattern := range c.AllowOriginPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("cors: invalid origin pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// origins 返回生效的静态源列表（未配置源与正则时默认 "*"）
+func (c CORSConfig) origins() []string {
+	var origins []string
+	for _, origin := range strings.Split(c.AllowOrigins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	if len(origins) == 0 && len(c.AllowOriginPatterns) == 0 {
+		origins = []string{"*"}
+	}
+	return origins
+}
+
+func validateOrigin(origin string) error {
+	u, err := url.Parse(strings.Replace(origin, "://*.", "://", 1))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+		(u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("cors: invalid origin %q, expected scheme://host[:port]", origin)
+	}
+	return nil
+}
+
+// newCORSMiddleware 校验配置并创建 CORS 中间件，返回生效的配置用于启动日志
+func newCORSMiddleware(config CORSConfig) (fiber.Handler, CORSConfig, error) {
+	if err := config.Validate(); err != nil {
+		return nil, config, err
+	}
+	if config.AllowMethods == "" {
+		config.AllowMethods = defaultCORSAllowMethods
+	}
+	if config.AllowHeaders == "" {
+		config.AllowHeaders = defaultCORSAllowHeaders
+	}
+	origins := config.origins()
+	config.AllowOrigins = strings.Join(origins, ",")
+
+	corsCfg := cors.Config{
+		AllowOrigins:     config.AllowOrigins,
+		AllowMethods:     config.AllowMethods,
+		AllowHeaders:     config.AllowHeaders,
+		AllowCredentials: config.AllowCredentials,
+		ExposeHeaders:    config.ExposeHeaders,
+		MaxAge:           config.MaxAge,
+	}
+	if len(config.AllowOriginPatterns) > 0 {
+		// 配置了正则时统一由 AllowOriginsFunc 匹配（静态源转换为正则），避免 Fiber 同时使用两种配置的告警
+		patterns := make([]*regexp.Regexp, 0, len(origins)+len(config.AllowOriginPatterns))
+		for _, origin := range origins {
+			patterns = append(patterns, originRegexp(origin))
+		}
+		for _, pattern := range config.AllowOriginPatterns {
+			patterns = append(patterns, regexp.MustCompile(pattern))
+		}
+		corsCfg.AllowOrigins = ""
+		corsCfg.AllowOriginsFunc = func(origin string) bool {
+			for _, pattern := range patterns {
+				if pattern.MatchString(origin) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return cors.New(corsCfg), config, nil
+}
+
+// originRegexp 将静态源（支持 scheme://*.domain 通配子域名）转换为不区分大小写的完整匹配正则
+func originRegexp(origin string) *regexp.Regexp {
+	origin = strings.TrimSuffix(strings.ToLower(origin), "/")
+	quoted := regexp.QuoteMeta(origin)
+	quoted = strings.Replace(quoted, `://\*\.`, `://([a-z0-9-]+\.)+`, 1)
+	return regexp.MustCompile("(?i)^" + quoted + "$")
+}
+
+// logCORSPolicy 启动时输出生效的 CORS 策略
+func logCORSPolicy(config CORSConfig) {
+	logger.Info(context.Background(), "HTTP CORS policy: allow_origins=%s, allow_origin_patterns=%v, allow_methods=%s, allow_headers=%s, allow_credentials=%t, expose_headers=%s, max_age=%d",
+		config.AllowOrigins, config.AllowOriginPatterns, config.AllowMethods, config.AllowHeaders,
+		config.AllowCredentials, config.ExposeHeaders, config.MaxAge)
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCORSConfigValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		config  CORSConfig
+		wantErr bool
+	}{
+		{name: "default wildcard", config: CORSConfig{}},
+		{name: "wildcard with credentials", config: CORSConfig{AllowOrigins: "*", AllowCredentials: true}, wantErr: true},
+		{name: "default wildcard with credentials", config: CORSConfig{AllowCredentials: true}, wantErr: true},
+		{name: "explicit origins with credentials", config: CORSConfig{AllowOrigins: "https://app.example.com, https://*.example.org", AllowCredentials: true}},
+		{name: "origin with path", config: CORSConfig{AllowOrigins: "https://app.example.com/login"}, wantErr: true},
+		{name: "origin without scheme", config: CORSConfig{AllowOrigins: "app.example.com"}, wantErr: true},
+		{name: "wildcard mixed with origins", config: CORSConfig{AllowOrigins: "*,https://app.example.com"}, wantErr: true},
+		{name: "invalid pattern", config: CORSConfig{AllowOriginPatterns: []string{"("}}, wantErr: true},
+		{name: "pattern with credentials", config: CORSConfig{AllowOriginPatterns: []string{`^https://[a-z]+\.example\.com$`}, AllowCredentials: true}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+	if err := (CORSConfig{AllowCredentials: true}).Validate(); !errors.Is(err, ErrCORSWildcardCredentials) {
+		t.Fatalf("expected ErrCORSWildcardCredentials, got %v", err)
+	}
+}
+
+func TestServerRejectsInsecureCORSConfig(t *testing.T) {
+	_, err := NewServer(Config{
+		FiberConfig: fiber.Config{DisableStartupMessage: true},
+		CORSConfig:  CORSConfig{AllowCredentials: true},
+	})
+	if !errors.Is(err, ErrCORSWildcardCredentials) {
+		t.Fatalf("expected wildcard credentials config to be rejected, got %v", err)
+	}
+}
+
+func TestServerCORSMatchesOriginPatterns(t *testing.T) {
+	server, err := NewServer(Config{
+		FiberConfig: fiber.Config{DisableStartupMessage: true},
+		CORSConfig: CORSConfig{
+			AllowOrigins:        "https://admin.example.com",
+			AllowOriginPatterns: []string{`^https://pr-[0-9]+\.preview\.example\.com$`},
+			AllowCredentials:    true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	server.GetApp().Get("/ok", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	for origin, allowed := range map[string]bool{
+		"https://admin.example.com":           true,
+		"https://pr-42.preview.example.com":   true,
+		"https://evil.example.com":            false,
+		"https://pr-42.preview.example.com.x": false,
+	} {
+		req := httptest.NewRequest("GET", "/ok", nil)
+		req.Header.Set(fiber.HeaderOrigin, origin)
+		resp, err := server.GetApp().Test(req)
+		if err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+		got := resp.Header.Get(fiber.HeaderAccessControlAllowOrigin)
+		if allowed && got != origin {
+			t.Fatalf("expected origin %s to be allowed, got %q", origin, got)
+		}
+		if !allowed && got != "" {
+			t.Fatalf("expected origin %s to be rejected, got %q", origin, got)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	nethttp "net/http"
+	"net/http/pprof"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// maxRecentGCPauses 运行时统计中返回的最近 GC 停顿次数上限
+const maxRecentGCPauses = 16
+
+// RuntimeStats Go 运行时统计
+type RuntimeStats struct {
+	GoVersion    string `json:"goVersion"`
+	NumCPU       int    `json:"numCPU"`
+	GOMAXPROCS   int    `json:"gomaxprocs"`
+	NumGoroutine int    `json:"numGoroutine"`
+	NumCgoCall   int64  `json:"numCgoCall"`
+	// 内存统计（字节）
+	Memory RuntimeMemoryStats `json:"memory"`
+	// GC 统计
+	GC RuntimeGCStats `json:"gc"`
+}
+
+// RuntimeMemoryStats 内存统计（runtime.MemStats 的常用字段）
+type RuntimeMemoryStats struct {
+	Alloc        uint64 `json:"alloc"`
+	TotalAlloc   uint64 `json:"totalAlloc"`
+	Sys          uint64 `json:"sys"`
+	Mallocs      uint64 `json:"mallocs"`
+	Frees        uint64 `json:"frees"`
+	HeapAlloc    uint64 `json:"heapAlloc"`
+	HeapSys      uint64 `json:"heapSys"`
+	HeapIdle     uint64 `json:"heapIdle"`
+	HeapInuse    uint64 `json:"heapInuse"`
+	HeapReleased uint64 `json:"heapReleased"`
+	HeapObjects  uint64 `json:"heapObjects"`
+	StackInuse   uint64 `json:"stackInuse"`
+}
+
+// RuntimeGCStats GC 统计
+type RuntimeGCStats struct {
+	NumGC         uint32    `json:"numGC"`
+	NumForcedGC   uint32    `json:"numForcedGC"`
+	NextGC        uint64    `json:"nextGC"`
+	LastGC        time.Time `json:"lastGC"`
+	PauseTotal    string    `json:"pauseTotal"`
+	CPUFraction   float64   `json:"cpuFraction"`
+	RecentPauses  []string  `json:"recentPauses"` // 最近的 GC 停顿（由新到旧）
+	RecentPauseNs []uint64  `json:"recentPauseNs"`
+}
+
+// ReadRuntimeStats 读取当前 Go 运行时统计（会短暂 stop-the-world，不宜高频调用）
+func ReadRuntimeStats() RuntimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	stats := RuntimeStats{
+		GoVersion:    runtime.Version(),
+		NumCPU:       runtime.NumCPU(),
+		GOMAXPROCS:   runtime.GOMAXPROCS(0),
+		NumGoroutine: runtime.NumGoroutine(),
+		NumCgoCall:   runtime.NumCgoCall(),
+		Memory: RuntimeMemoryStats{
+			Alloc:        mem.Alloc,
+			TotalAlloc:   mem.TotalAlloc,
+			Sys:          mem.Sys,
+			Mallocs:      mem.Mallocs,
+			Frees:        mem.Frees,
+			HeapAlloc:    mem.HeapAlloc,
+			HeapSys:      mem.HeapSys,
+			HeapIdle:     mem.HeapIdle,
+			HeapInuse:    mem.HeapInuse,
+			HeapReleased: mem.HeapReleased,
+			HeapObjects:  mem.HeapObjects,
+			StackInuse:   mem.StackInuse,
+		},
+		GC: RuntimeGCStats{
+			NumGC:       mem.NumGC,
+			NumForcedGC: mem.NumForcedGC,
+			NextGC:      mem.NextGC,
+			PauseTotal:  time.Duration(mem.PauseTotalNs).String(),
+			CPUFraction: mem.GCCPUFraction,
+		},
+	}
+	if mem.LastGC > 0 {
+		stats.GC.LastGC = time.Unix(0, int64(mem.LastGC))
+	}
+	// PauseNs 为环形缓冲区，最近一次 GC 位于 (NumGC+255)%256
+	recent := min(int(mem.NumGC), maxRecentGCPauses)
+	stats.GC.RecentPauses = make([]string, 0, recent)
+	stats.GC.RecentPauseNs = make([]uint64, 0, recent)
+	for i := 0; i < recent; i++ {
+		pause := mem.PauseNs[(int(mem.NumGC)-1-i+len(mem.PauseNs))%len(mem.PauseNs)]
+		stats.GC.RecentPauses = append(stats.GC.RecentPauses, time.Duration(pause).String())
+		stats.GC.RecentPauseNs = append(stats.GC.RecentPauseNs, pause)
+	}
+	return stats
+}
+
+// RuntimeStatsHandler 以 JSON 返回 Go 运行时统计（goroutine、内存、GC 停顿）
+func RuntimeStatsHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.JSON(ReadRuntimeStats())
+	}
+}
+
+// RegisterPprof 在路由组上挂载 net/http/pprof 处理器，如 RegisterPprof(app.Group("/debug/pprof"))
+// 提供 /（索引）、/cmdline、/profile、/symbol、/trace 以及 /goroutine、/heap 等命名 profile
+func RegisterPprof(router fiber.Router) {
+	router.Get("/", adaptor.HTTPHandlerFunc(pprof.Index))
+	router.Get("/cmdline", adaptor.HTTPHandlerFunc(pprof.Cmdline))
+	router.Get("/profile", adaptor.HTTPHandlerFunc(pprof.Profile))
+	router.Get("/symbol", adaptor.HTTPHandlerFunc(pprof.Symbol))
+	router.Post("/symbol", adaptor.HTTPHandlerFunc(pprof.Symbol))
+	router.Get("/trace", adaptor.HTTPHandlerFunc(pprof.Trace))
+	router.Get("/:name", func(c *fiber.Ctx) error {
+		return adaptor.HTTPHandler(pprof.Handler(c.Params("name")))(c)
+	})
+}
+
+// DebugServerConfig 独立调试监听配置
+type DebugServerConfig struct {
+	// 监听地址，如 127.0.0.1:6060（建议只监听内网地址）
+	Address string
+	// 指标处理器（可选，配置后挂载在 /metrics）
+	MetricsHandler nethttp.Handler
+}
+
+// DebugServer 独立的调试 HTTP 监听，用于没有 HTTP 服务器的服务（如纯 gRPC 服务）
+// 提供 /debug/pprof/、/debug/runtime 以及可选的 /metrics
+type DebugServer struct {
+	server   *nethttp.Server
+	listener net.Listener
+	mu       sync.Mutex
+	done     chan struct{}
+}
+
+// NewDebugServer 创建调试监听
+func NewDebugServer(config DebugServerConfig) (*DebugServer, error) {
+	if config.Address == "" {
+		return nil, errors.New("debug server address is required")
+	}
+	mux := nethttp.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/runtime", func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ReadRuntimeStats())
+	})
+	if config.MetricsHandler != nil {
+		mux.Handle("/metrics", config.MetricsHandler)
+	}
+	return &DebugServer{
+		server: &nethttp.Server{
+			Addr:              config.Address,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}, nil
+}
+
+// Start 开始监听（非阻塞），端口占用等错误同步返回；Stop 之后不可再次启动
+func (s *DebugServer) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener != nil {
+		return errors.New("debug server already running")
+	}
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen debug server on %s: %w", s.server.Addr, err)
+	}
+	s.listener = listener
+	s.done = make(chan struct{})
+	go func(done chan struct{}) {
+		defer close(done)
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, nethttp.ErrServerClosed) {
+			logger.Error(context.Background(), "Debug server stopped unexpectedly: %v", err)
+		}
+	}(s.done)
+	logger.Info(context.Background(), "Debug server listening on %s", listener.Addr())
+	return nil
+}
+
+// Addr 返回实际监听地址（未启动时返回 nil）
+func (s *DebugServer) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Stop 关闭调试监听，正在进行的请求（如 CPU profile）最多等待 ctx 结束
+func (s *DebugServer) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	listener, done := s.listener, s.done
+	s.listener = nil
+	s.mu.Unlock()
+	if listener == nil {
+		return nil
+	}
+	err := s.server.Shutdown(ctx)
+	if err != nil {
+		err = errors.Join(err, s.server.Close())
+	}
+	<-done
+	return err
+}
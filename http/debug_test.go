@@ -0,0 +1,22 @@
+package http
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestReadRuntimeStatsReportsRecentGCPauses(t *testing.T) {
+	runtime.GC()
+	runtime.GC()
+	stats := ReadRuntimeStats()
+	if stats.NumGoroutine <= 0 || stats.GOMAXPROCS <= 0 || stats.Memory.HeapAlloc == 0 {
+		t.Fatalf("unexpected runtime stats: %+v", stats)
+	}
+	if stats.GC.NumGC < 2 || stats.GC.LastGC.IsZero() {
+		t.Fatalf("expected forced GCs to be reported: %+v", stats.GC)
+	}
+	want := min(int(stats.GC.NumGC), maxRecentGCPauses)
+	if len(stats.GC.RecentPauses) != want || len(stats.GC.RecentPauseNs) != want {
+		t.Fatalf("expected %d recent pauses, got %v", want, stats.GC.RecentPauses)
+	}
+}
@@ -0,0 +1,135 @@
+package http
+
+import (
+	"hash/crc32"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const defaultETagMaxBodySize = 4 << 20
+
+// ETagConfig 条件请求中间件配置
+type ETagConfig struct {
+	// 自动计算弱 ETag 的响应 Content-Type 前缀（默认 application/json）；处理器已设置 ETag 时直接使用
+	ContentTypes []string
+	// 自动计算 ETag 的最大响应体字节数（默认 4MB），超出时不计算
+	MaxBodySize int
+}
+
+// ETagMiddleware 条件请求中间件：为 GET / HEAD 的 200 JSON 响应计算弱 ETag（处理器可通过 SetETag 自行设置），
+// 请求 If-None-Match 命中时返回 304；未携带 If-None-Match 时按 Last-Modified 与 If-Modified-Since 判断
+// 与 CacheMiddleware 同时使用时注册在其外层，缓存命中的响应同样可返回 304
+func ETagMiddleware(config ETagConfig) fiber.Handler {
+	if len(config.ContentTypes) == 0 {
+		config.ContentTypes = []string{fiber.MIMEApplicationJSON}
+	}
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = defaultETagMaxBodySize
+	}
+
+	return func(c *fiber.Ctx) error {
+		method := c.Method()
+		if method != fiber.MethodGet && method != fiber.MethodHead {
+			return c.Next()
+		}
+		if err := c.Next(); err != nil {
+			return err
+		}
+		resp := c.Response()
+		if resp.StatusCode() != fiber.StatusOK || resp.IsBodyStream() {
+			return nil
+		}
+		if len(resp.Header.Peek(fiber.HeaderETag)) == 0 && config.computable(c) {
+			c.Set(fiber.HeaderETag, weakETag(resp.Body()))
+		}
+		if NotModified(c) {
+			writeNotModified(c)
+		}
+		return nil
+	}
+}
+
+// computable 判断是否为响应自动计算 ETag
+func (config ETagConfig) computable(c *fiber.Ctx) bool {
+	body := c.Response().Body()
+	if len(body) == 0 || len(body) > config.MaxBodySize {
+		return false
+	}
+	contentType := string(c.Response().Header.ContentType())
+	for _, prefix := range config.ContentTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetETag 设置响应 ETag，value 未加引号时自动补充，weak 为 true 时生成弱 ETag（W/"..."）
+func SetETag(c *fiber.Ctx, value string, weak bool) {
+	if !strings.HasPrefix(value, `"`) {
+		value = strconv.Quote(value)
+	}
+	if weak {
+		value = "W/" + value
+	}
+	c.Set(fiber.HeaderETag, value)
+}
+
+// SetLastModified 设置响应 Last-Modified（精确到秒）
+func SetLastModified(c *fiber.Ctx, t time.Time) {
+	c.Set(fiber.HeaderLastModified, t.UTC().Format(http.TimeFormat))
+}
+
+// NotModified 按已设置的 ETag / Last-Modified 判断请求的缓存副本是否仍然有效
+// 处理器可在查询数据前设置 ETag 或 Last-Modified 并调用该函数，命中时直接返回 c.SendStatus(fiber.StatusNotModified)
+func NotModified(c *fiber.Ctx) bool {
+	if noneMatch := c.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		etag := string(c.Response().Header.Peek(fiber.HeaderETag))
+		return etag != "" && etagMatches(noneMatch, etag)
+	}
+	since := c.Get(fiber.HeaderIfModifiedSince)
+	lastModified := string(c.Response().Header.Peek(fiber.HeaderLastModified))
+	if since == "" || lastModified == "" {
+		return false
+	}
+	sinceTime, err := http.ParseTime(since)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	return !modified.After(sinceTime)
+}
+
+// etagMatches 按弱比较判断 If-None-Match 是否包含 etag
+func etagMatches(noneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(noneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// weakETag 按响应体长度与 CRC32 计算弱 ETag
+func weakETag(body []byte) string {
+	return `W/"` + strconv.FormatInt(int64(len(body)), 16) + "-" +
+		strconv.FormatUint(uint64(crc32.ChecksumIEEE(body)), 16) + `"`
+}
+
+// writeNotModified 将响应改写为 304，保留 ETag、Cache-Control 等校验相关响应头
+func writeNotModified(c *fiber.Ctx) {
+	resp := c.Response()
+	resp.SetStatusCode(fiber.StatusNotModified)
+	resp.ResetBody()
+	resp.Header.Del(fiber.HeaderContentType)
+	resp.Header.Del(fiber.HeaderContentLength)
+}
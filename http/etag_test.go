@@ -0,0 +1,72 @@
+package http
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestETagMiddleware(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	app := fiber.New()
+	app.Use(ETagMiddleware(ETagConfig{}))
+	app.Get("/items", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderCacheControl, "max-age=60")
+		return c.JSON(fiber.Map{"items": []int{1, 2, 3}})
+	})
+	app.Get("/text", func(c *fiber.Ctx) error {
+		return c.SendString("plain")
+	})
+	app.Get("/report", func(c *fiber.Ctx) error {
+		SetETag(c, "v42", false)
+		SetLastModified(c, modified)
+		if NotModified(c) {
+			return c.SendStatus(fiber.StatusNotModified)
+		}
+		return c.JSON(fiber.Map{"report": "full"})
+	})
+
+	do := func(target string, headers map[string]string) (int, string, string) {
+		req := httptest.NewRequest("GET", target, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, resp.Header.Get(fiber.HeaderETag), string(body)
+	}
+
+	status, etag, _ := do("/items", nil)
+	if status != fiber.StatusOK || len(etag) < 4 || etag[:3] != `W/"` {
+		t.Fatalf("expected weak etag, got status=%d etag=%q", status, etag)
+	}
+	status, again, body := do("/items", map[string]string{fiber.HeaderIfNoneMatch: `"other", ` + etag})
+	if status != fiber.StatusNotModified || again != etag || body != "" {
+		t.Fatalf("expected 304 with etag, got status=%d etag=%q body=%q", status, again, body)
+	}
+	if status, _, _ := do("/items", map[string]string{fiber.HeaderIfNoneMatch: `W/"stale"`}); status != fiber.StatusOK {
+		t.Fatalf("stale etag should return 200, got %d", status)
+	}
+	if _, etag, _ := do("/text", nil); etag != "" {
+		t.Fatalf("non-JSON response should not get etag, got %q", etag)
+	}
+
+	// 处理器设置的强 ETag 按弱比较匹配
+	if status, etag, _ := do("/report", map[string]string{fiber.HeaderIfNoneMatch: `W/"v42"`}); status != fiber.StatusNotModified || etag != `"v42"` {
+		t.Fatalf("expected 304 for handler etag, got status=%d etag=%q", status, etag)
+	}
+	since := modified.Add(time.Hour).Format("Mon, 02 Jan 2006 15:04:05 GMT")
+	if status, _, _ := do("/report", map[string]string{fiber.HeaderIfModifiedSince: since}); status != fiber.StatusNotModified {
+		t.Fatalf("expected 304 for If-Modified-Since, got %d", status)
+	}
+	earlier := modified.Add(-time.Hour).Format("Mon, 02 Jan 2006 15:04:05 GMT")
+	if status, _, _ := do("/report", map[string]string{fiber.HeaderIfModifiedSince: earlier}); status != fiber.StatusOK {
+		t.Fatalf("expected 200 for older If-Modified-Since, got %d", status)
+	}
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	fiberlog "github.com/gofiber/fiber/v2/log"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// InstallFiberLogger 将 fiber 内部日志（fiber/log）转发到框架 logger
+// 日志级别由 logger.SetLibraryLevel(logger.LibraryFiber, ...) 控制
+func InstallFiberLogger() {
+	fiberlog.SetLogger(&fiberLogAdapter{ctx: context.Background()})
+}
+
+// fiberLogAdapter 实现 fiberlog.AllLogger 接口
+type fiberLogAdapter struct {
+	ctx context.Context
+}
+
+func (a *fiberLogAdapter) write(level logger.Level, msg string, fields map[string]interface{}) {
+	logger.LogLibrary(a.ctx, logger.LibraryFiber, level, msg, fields)
+}
+
+func (a *fiberLogAdapter) writeArgs(level logger.Level, v ...interface{}) {
+	a.write(level, strings.TrimSuffix(fmt.Sprintln(v...), "\n"), nil)
+}
+
+func (a *fiberLogAdapter) writef(level logger.Level, format string, v ...interface{}) {
+	a.write(level, fmt.Sprintf(format, v...), nil)
+}
+
+func (a *fiberLogAdapter) writew(level logger.Level, msg string, keysAndValues ...interface{}) {
+	fields := make(map[string]interface{}, len(keysAndValues)/2)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		fields[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+	}
+	a.write(level, msg, fields)
+}
+
+func (a *fiberLogAdapter) Trace(v ...interface{}) { a.writeArgs(logger.LevelDebug, v...) }
+func (a *fiberLogAdapter) Debug(v ...interface{}) { a.writeArgs(logger.LevelDebug, v...) }
+func (a *fiberLogAdapter) Info(v ...interface{})  { a.writeArgs(logger.LevelInfo, v...) }
+func (a *fiberLogAdapter) Warn(v ...interface{})  { a.writeArgs(logger.LevelWarn, v...) }
+func (a *fiberLogAdapter) Error(v ...interface{}) { a.writeArgs(logger.LevelError, v...) }
+
+func (a *fiberLogAdapter) Fatal(v ...interface{}) {
+	a.writeArgs(logger.LevelError, v...)
+	os.Exit(1)
+}
+
+func (a *fiberLogAdapter) Panic(v ...interface{}) {
+	msg := fmt.Sprint(v...)
+	a.write(logger.LevelError, msg, nil)
+	panic(msg)
+}
+
+func (a *fiberLogAdapter) Tracef(format string, v ...interface{}) {
+	a.writef(logger.LevelDebug, format, v...)
+}
+func (a *fiberLogAdapter) Debugf(format string, v ...interface{}) {
+	a.writef(logger.LevelDebug, format, v...)
+}
+func (a *fiberLogAdapter) Infof(format string, v ...interface{}) {
+	a.writef(logger.LevelInfo, format, v...)
+}
+func (a *fiberLogAdapter) Warnf(format string, v ...interface{}) {
+	a.writef(logger.LevelWarn, format, v...)
+}
+func (a *fiberLogAdapter) Errorf(format string, v ...interface{}) {
+	a.writef(logger.LevelError, format, v...)
+}
+
+func (a *fiberLogAdapter) Fatalf(format string, v ...interface{}) {
+	a.writef(logger.LevelError, format, v...)
+	os.Exit(1)
+}
+
+func (a *fiberLogAdapter) Panicf(format string, v ...interface{}) {
+	msg := fmt.Sprintf(format, v...)
+	a.write(logger.LevelError, msg, nil)
+	panic(msg)
+}
+
+func (a *fiberLogAdapter) Tracew(msg string, keysAndValues ...interface{}) {
+	a.writew(logger.LevelDebug, msg, keysAndValues...)
+}
+func (a *fiberLogAdapter) Debugw(msg string, keysAndValues ...interface{}) {
+	a.writew(logger.LevelDebug, msg, keysAndValues...)
+}
+func (a *fiberLogAdapter) Infow(msg string, keysAndValues ...interface{}) {
+	a.writew(logger.LevelInfo, msg, keysAndValues...)
+}
+func (a *fiberLogAdapter) Warnw(msg string, keysAndValues ...interface{}) {
+	a.writew(logger.LevelWarn, msg, keysAndValues...)
+}
+func (a *fiberLogAdapter) Errorw(msg string, keysAndValues ...interface{}) {
+	a.writew(logger.LevelError, msg, keysAndValues...)
+}
+
+func (a *fiberLogAdapter) Fatalw(msg string, keysAndValues ...interface{}) {
+	a.writew(logger.LevelError, msg, keysAndValues...)
+	os.Exit(1)
+}
+
+func (a *fiberLogAdapter) Panicw(msg string, keysAndValues ...interface{}) {
+	a.writew(logger.LevelError, msg, keysAndValues...)
+	panic(msg)
+}
+
+// SetLevel 将 fiber 日志级别同步为 fiber 库级别
+func (a *fiberLogAdapter) SetLevel(level fiberlog.Level) {
+	switch {
+	case level <= fiberlog.LevelDebug:
+		logger.SetLibraryLevel(logger.LibraryFiber, logger.LevelDebug)
+	case level == fiberlog.LevelInfo:
+		logger.SetLibraryLevel(logger.LibraryFiber, logger.LevelInfo)
+	case level == fiberlog.LevelWarn:
+		logger.SetLibraryLevel(logger.LibraryFiber, logger.LevelWarn)
+	default:
+		logger.SetLibraryLevel(logger.LibraryFiber, logger.LevelError)
+	}
+}
+
+// SetOutput 输出由框架 logger 统一管理，忽略 fiber 的输出设置
+func (a *fiberLogAdapter) SetOutput(io.Writer) {}
+
+// WithContext 返回携带 context（用于提取 trace ID）的 logger
+func (a *fiberLogAdapter) WithContext(ctx context.Context) fiberlog.CommonLogger {
+	return &fiberLogAdapter{ctx: ctx}
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	defaultJSONArrayFlushItems    = 100
+	defaultJSONArrayFlushBytes    = 32 << 10
+	defaultJSONArrayFlushInterval = time.Second
+)
+
+// JSONIterator 逐条读取记录的迭代器
+// *mongo.Cursor 直接满足该接口，GORM 查询可通过 db/gorm 包的 NewRowsIterator 适配
+type JSONIterator interface {
+	Next(ctx context.Context) bool
+	Decode(v interface{}) error
+	Err() error
+	Close(ctx context.Context) error
+}
+
+// JSONArrayConfig 流式 JSON 数组发送配置，任一条件满足即将已编码的数据发送给客户端
+type JSONArrayConfig struct {
+	// 累计的记录数（默认 100）
+	FlushItems int
+	// 累计的字节数（默认 32KB）
+	FlushBytes int
+	// 距上次发送的时间（默认 1s），避免查询较慢时客户端长时间收不到数据
+	FlushInterval time.Duration
+}
+
+func normalizeJSONArrayConfig(config JSONArrayConfig) JSONArrayConfig {
+	if config.FlushItems <= 0 {
+		config.FlushItems = defaultJSONArrayFlushItems
+	}
+	if config.FlushBytes <= 0 {
+		config.FlushBytes = defaultJSONArrayFlushBytes
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaultJSONArrayFlushInterval
+	}
+	return config
+}
+
+// StreamJSONArray 将迭代器中的记录逐条编码为 JSON 数组流式发送，网关内存中只保留一个批次的数据
+// 每条记录解码为 T 后编码；客户端消费慢于读取速度时暂停读取（受 StreamLimitMiddleware 的 MaxLag 限制），
+// 客户端断开时停止读取。迭代器出错时数组不会闭合，客户端可据此识别数据不完整；迭代器在结束时关闭
+func StreamJSONArray[T any](c *fiber.Ctx, it JSONIterator, config ...JSONArrayConfig) error {
+	var cfg JSONArrayConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	cfg = normalizeJSONArrayConfig(cfg)
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return Stream(c, func(w *StreamWriter) error {
+		defer it.Close(context.Background())
+		return encodeJSONArray[T](w, it, cfg)
+	})
+}
+
+func encodeJSONArray[T any](w *StreamWriter, it JSONIterator, cfg JSONArrayConfig) error {
+	ctx := w.Context()
+	buf := make([]byte, 0, cfg.FlushBytes)
+	buf = append(buf, '[')
+	items := 0
+	lastFlush := time.Now()
+
+	flush := func() error {
+		// 等待客户端消费到能容纳本批数据后再写入，避免慢客户端导致数据堆积在内存中
+		if err := w.waitBuffered(w.config.MaxBufferedBytes - len(buf)); err != nil {
+			return err
+		}
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+		buf = buf[:0]
+		items = 0
+		lastFlush = time.Now()
+		return nil
+	}
+
+	first := true
+	for it.Next(ctx) {
+		var item T
+		if err := it.Decode(&item); err != nil {
+			return err
+		}
+		data, err := jsoniter.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if !first {
+			buf = append(buf, ',')
+		}
+		first = false
+		buf = append(buf, data...)
+		items++
+		if items >= cfg.FlushItems || len(buf) >= cfg.FlushBytes || time.Since(lastFlush) >= cfg.FlushInterval {
+			if err = flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return ErrStreamClosed
+	}
+	buf = append(buf, ']')
+	return flush()
+}
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type sliceIterator struct {
+	items  []int
+	pos    int
+	err    error
+	closed bool
+}
+
+func (it *sliceIterator) Next(ctx context.Context) bool {
+	if ctx.Err() != nil || it.pos >= len(it.items) {
+		return false
+	}
+	it.pos++
+	return true
+}
+
+func (it *sliceIterator) Decode(v interface{}) error {
+	*(v.(*map[string]int)) = map[string]int{"id": it.items[it.pos-1]}
+	return nil
+}
+
+func (it *sliceIterator) Err() error { return it.err }
+
+func (it *sliceIterator) Close(context.Context) error {
+	it.closed = true
+	return nil
+}
+
+func TestStreamJSONArrayEncodesAllItems(t *testing.T) {
+	for _, count := range []int{0, 1, 250} {
+		it := &sliceIterator{}
+		for i := 0; i < count; i++ {
+			it.items = append(it.items, i)
+		}
+		app := fiber.New(fiber.Config{DisableStartupMessage: true})
+		app.Get("/export", func(c *fiber.Ctx) error {
+			return StreamJSONArray[map[string]int](c, it, JSONArrayConfig{FlushItems: 7})
+		})
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/export", nil), -1)
+		if err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		var items []map[string]int
+		if err := json.Unmarshal(body, &items); err != nil {
+			t.Fatalf("invalid JSON array %q: %v", body, err)
+		}
+		if len(items) != count || (count > 0 && items[count-1]["id"] != count-1) {
+			t.Fatalf("unexpected items for count %d: %d", count, len(items))
+		}
+		if !it.closed {
+			t.Fatal("expected iterator to be closed")
+		}
+	}
+}
+
+func TestStreamJSONArrayLeavesArrayOpenOnIteratorError(t *testing.T) {
+	it := &sliceIterator{items: []int{1, 2}, err: errors.New("cursor failed")}
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/export", func(c *fiber.Ctx) error {
+		return StreamJSONArray[map[string]int](c, it)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/export", nil), -1)
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if json.Valid(body) {
+		t.Fatalf("expected truncated JSON, got %q", body)
+	}
+}
+
+func TestEncodeJSONArrayWaitsForSlowClient(t *testing.T) {
+	w := newStreamWriter(context.Background(), StreamLimitConfig{MaxBufferedBytes: 64, MaxLag: time.Minute}, nil)
+	defer w.close()
+	it := &sliceIterator{}
+	for i := 0; i < 50; i++ {
+		it.items = append(it.items, i)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- encodeJSONArray[map[string]int](w, it, normalizeJSONArrayConfig(JSONArrayConfig{FlushItems: 1}))
+		w.finish()
+	}()
+
+	var body []byte
+	for {
+		chunks, ok := w.next()
+		if !ok {
+			break
+		}
+		n := 0
+		for _, chunk := range chunks {
+			body = append(body, chunk.data...)
+			n += len(chunk.data)
+		}
+		time.Sleep(time.Millisecond)
+		w.sent(n)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	var items []map[string]int
+	if err := json.Unmarshal(body, &items); err != nil || len(items) != 50 {
+		t.Fatalf("unexpected body %q: %v", body, err)
+	}
+}
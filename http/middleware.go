@@ -2,6 +2,8 @@ package http
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -12,7 +14,7 @@ import (
 
 const (
 	// TraceIDHeader trace ID 请求头名称（统一使用此请求头，request_id 和 trace_id 使用同一个值）
-	TraceIDHeader = "X-Trace-ID"
+	TraceIDHeader = tracing.TraceIDHeader
 	// RequestIDHeader 请求 ID 请求头名称（已废弃，统一使用 TraceIDHeader）
 	// Deprecated: 使用 TraceIDHeader 代替
 	RequestIDHeader = TraceIDHeader
@@ -22,8 +24,14 @@ const (
 // 从请求头中提取 trace ID，如果没有则生成新的
 // 同时设置 request_id 和 trace_id 为同一个值（用于日志关联和追踪）
 // 统一使用 X-Trace-ID 请求头，避免混淆
+// 启用 OpenTelemetry 时创建真实 span（优先使用 traceparent，其次沿用 X-Trace-ID），trace ID 与 span 一致
 func TraceMiddleware() fiber.Handler {
+	otelMiddleware := tracing.Middleware()
 	return func(c *fiber.Ctx) error {
+		if tracing.IsEnabled() {
+			return otelMiddleware(c)
+		}
+
 		// 从请求头中获取 trace ID（统一使用 X-Trace-ID）
 		traceID := c.Get(TraceIDHeader)
 		if traceID == "" {
@@ -54,13 +62,9 @@ func LoggingMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		start := time.Now()
 
-		// 从 Locals 中获取 trace ID 和 span ID，创建 context
-		traceID := GetTraceID(c)
-		spanID := GetSpanID(c)
-		ctx := context.Background()
-		if traceID != "" {
-			ctx = logger.WithTrace(ctx, traceID, spanID)
-		} else {
+		// 使用请求 context 记录日志（包含 trace ID、span ID 与已认证身份）
+		ctx := RequestContext(c)
+		if logger.GetTraceID(ctx) == "" {
 			ctx = logger.StartSpan(ctx)
 		}
 
@@ -79,6 +83,18 @@ func LoggingMiddleware() fiber.Handler {
 		duration := time.Since(start)
 		statusCode := c.Response().StatusCode()
 
+		// 运行时开启了该路由的载荷日志时记录脱敏后的请求与响应体
+		route := c.Route().Path
+		if logger.PayloadLoggingEnabled(route, c.Method()+" "+route) {
+			logger.Info(logger.WithModule(ctx, route), "HTTP payload: method=%s, route=%s, status=%d, request=%s, response=%s",
+				c.Method(),
+				route,
+				statusCode,
+				formatBodyPayload(route, c.Body(), c.Get(fiber.HeaderContentType)),
+				formatResponsePayload(c, route),
+			)
+		}
+
 		// 记录响应信息
 		if err != nil {
 			logger.Error(ctx, "HTTP request failed: method=%s, path=%s, status=%d, duration=%v",
@@ -116,15 +132,8 @@ func RecoveryMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		defer func() {
 			if r := recover(); r != nil {
-				// 从 Locals 中获取 trace ID，创建 context
-				traceID := GetTraceID(c)
-				ctx := context.Background()
-				if traceID != "" {
-					ctx = logger.WithTraceID(ctx, traceID)
-				} else {
-					ctx = logger.StartSpan(ctx)
-				}
-				logger.Error(ctx, "HTTP panic recovered: %v", r)
+				// 记录堆栈并上报到 recovery.Reporter（已配置时）
+				reportPanic(c, r)
 
 				// 返回 500 错误
 				c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -220,3 +229,24 @@ func GetRequestID(c *fiber.Ctx) string {
 	// 如果没有 request_id，返回 trace_id（它们应该是同一个值）
 	return GetTraceID(c)
 }
+
+// formatBodyPayload 格式化请求/响应体：JSON 脱敏并截断，其他内容只记录类型与大小（无法脱敏）
+func formatBodyPayload(route string, body []byte, contentType string) string {
+	if len(body) == 0 {
+		return "<empty>"
+	}
+	if json.Valid(body) {
+		if payload, ok := tracing.FormatPayload(route, json.RawMessage(body)); ok {
+			return payload
+		}
+	}
+	return fmt.Sprintf("<%d bytes, %s>", len(body), contentType)
+}
+
+func formatResponsePayload(c *fiber.Ctx, route string) string {
+	resp := c.Response()
+	if resp.IsBodyStream() {
+		return "<stream>"
+	}
+	return formatBodyPayload(route, resp.Body(), string(resp.Header.ContentType()))
+}
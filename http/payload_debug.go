@@ -0,0 +1,75 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/types"
+)
+
+// PayloadLoggingRequest 运行时开启/关闭载荷日志的请求
+type PayloadLoggingRequest struct {
+	// gRPC 方法全名，如 /auth.AuthService/Login
+	Method string `json:"method"`
+	// HTTP 路由，如 /api/users/:id 或 POST /api/login（与 Method 二选一，均支持 * 通配符）
+	Route string `json:"route"`
+	// 持续时间，如 10m（默认 10m，最长 24h）
+	TTL types.Duration `json:"ttl"`
+}
+
+// target 返回请求的目标（Method 优先）
+func (r *PayloadLoggingRequest) target() string {
+	if method := strings.TrimSpace(r.Method); method != "" {
+		return method
+	}
+	return strings.TrimSpace(r.Route)
+}
+
+// PayloadLoggingHandler 运行时载荷日志管理接口，用于排查线上问题而无需重新部署：
+//
+//	GET    列出当前开启的目标
+//	POST   {"method": "/auth.AuthService/Login", "ttl": "10m"} 开启，到期自动关闭
+//	DELETE {"method": "/auth.AuthService/Login"}（或 ?target=）立即关闭
+//
+// 载荷会脱敏、截断后写入日志，接口本身不做鉴权，应只注册在内部运维端口或配合鉴权中间件使用
+func PayloadLoggingHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet:
+			return c.JSON(fiber.Map{"targets": logger.PayloadLoggingTargets()})
+		case fiber.MethodPost:
+			var req PayloadLoggingRequest
+			if err := c.BodyParser(&req); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body: " + err.Error()})
+			}
+			target := req.target()
+			if target == "" {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "method or route is required"})
+			}
+			if req.TTL < 0 {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ttl must be positive"})
+			}
+			return c.JSON(logger.EnablePayloadLogging(target, req.TTL.Std()))
+		case fiber.MethodDelete:
+			target := strings.TrimSpace(c.Query("target"))
+			if target == "" && len(c.Body()) > 0 {
+				var req PayloadLoggingRequest
+				if err := c.BodyParser(&req); err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body: " + err.Error()})
+				}
+				target = req.target()
+			}
+			if target == "" {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "method, route or target is required"})
+			}
+			if !logger.DisablePayloadLogging(target) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "payload logging is not enabled for " + target})
+			}
+			return c.SendStatus(fiber.StatusNoContent)
+		default:
+			return c.SendStatus(fiber.StatusMethodNotAllowed)
+		}
+	}
+}
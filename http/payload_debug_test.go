@@ -0,0 +1,95 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+func TestPayloadLoggingHandlerTogglesRoutePayloadLogs(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "app.log")
+	l, err := logger.NewLogger(logger.Config{Level: logger.LevelInfo, Output: output})
+	if err != nil {
+		t.Fatalf("NewLogger failed: %v", err)
+	}
+	previous := logger.GetDefault()
+	logger.SetDefault(l)
+	defer func() {
+		logger.SetDefault(previous)
+		_ = l.Close()
+	}()
+
+	app := fiber.New()
+	app.Use(LoggingMiddleware())
+	app.All("/admin/debug/log-level", PayloadLoggingHandler())
+	app.Post("/api/login", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"user": "alice", "token": "t0ken"})
+	})
+
+	login := func() {
+		req := httptest.NewRequest(nethttp.MethodPost, "/api/login", strings.NewReader(`{"user":"alice","password":"hunter2"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		if _, err := app.Test(req); err != nil {
+			t.Fatalf("login request failed: %v", err)
+		}
+	}
+	admin := func(method, body string) *nethttp.Response {
+		req := httptest.NewRequest(method, "/admin/debug/log-level", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("admin request failed: %v", err)
+		}
+		return resp
+	}
+
+	login()
+	if resp := admin(nethttp.MethodPost, `{"route":"POST /api/login","ttl":"5s"}`); resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected enable to succeed, got %d", resp.StatusCode)
+	}
+	login()
+
+	resp := admin(nethttp.MethodGet, "")
+	var listed struct {
+		Targets []logger.PayloadLoggingTarget `json:"targets"`
+	}
+	data, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(data, &listed); err != nil || len(listed.Targets) != 1 || listed.Targets[0].Target != "POST /api/login" {
+		t.Fatalf("unexpected targets %s: %v", data, err)
+	}
+
+	if resp := admin(nethttp.MethodDelete, `{"route":"POST /api/login"}`); resp.StatusCode != fiber.StatusNoContent {
+		t.Fatalf("expected disable to succeed, got %d", resp.StatusCode)
+	}
+	login()
+	if resp := admin(nethttp.MethodDelete, `{"route":"POST /api/login"}`); resp.StatusCode != fiber.StatusNotFound {
+		t.Fatalf("expected second disable to return 404, got %d", resp.StatusCode)
+	}
+	if resp := admin(nethttp.MethodPost, `{"ttl":"5s"}`); resp.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("expected missing target to be rejected, got %d", resp.StatusCode)
+	}
+
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	logs := string(content)
+	if n := strings.Count(logs, "HTTP payload:"); n != 1 {
+		t.Fatalf("expected exactly one payload log while enabled, got %d: %s", n, logs)
+	}
+	if strings.Contains(logs, "hunter2") || strings.Contains(logs, "t0ken") {
+		t.Fatalf("expected payload secrets to be redacted: %s", logs)
+	}
+	if !strings.Contains(logs, "alice") {
+		t.Fatalf("expected payload content in logs: %s", logs)
+	}
+}
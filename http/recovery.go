@@ -0,0 +1,70 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/recovery"
+)
+
+// requestContext 从 Locals 中的 trace 信息创建日志 context
+func requestContext(c *fiber.Ctx) context.Context {
+	ctx := context.Background()
+	if traceID := GetTraceID(c); traceID != "" {
+		return logger.WithTrace(ctx, traceID, GetSpanID(c))
+	}
+	return logger.StartSpan(ctx)
+}
+
+// httpReport 提取请求信息用于错误上报
+func httpReport(c *fiber.Ctx, status int) recovery.Report {
+	path := c.Path()
+	if route := c.Route(); route != nil && route.Path != "" && route.Path != "/" {
+		path = route.Path
+	}
+	return recovery.Report{
+		Transport: "http",
+		Method:    c.Method(),
+		Path:      strings.Clone(path),
+		Status:    status,
+		TraceID:   GetTraceID(c),
+		SpanID:    GetSpanID(c),
+		Metadata: map[string]string{
+			"client_ip":  strings.Clone(c.IP()),
+			"user_agent": strings.Clone(c.Get(fiber.HeaderUserAgent)),
+		},
+	}
+}
+
+// reportPanic 记录 panic 日志（含堆栈）并上报到 recovery.Reporter
+func reportPanic(c *fiber.Ctx, r interface{}) {
+	stack := debug.Stack()
+	ctx := requestContext(c)
+	logger.Error(ctx, "HTTP panic recovered: method=%s, path=%s, panic=%v\n%s", c.Method(), c.Path(), r, stack)
+	recovery.ReportPanic(ctx, r, stack, httpReport(c, fiber.StatusInternalServerError))
+}
+
+// ServerErrorReportMiddleware 统计 5xx 响应，短时间内大量出现时通过 recovery.Reporter 上报错误突增
+// 需注册在恢复中间件之前，使 panic 转换的 500 同样计入
+func ServerErrorReportMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+		if status >= fiber.StatusInternalServerError && recovery.GetReporter() != nil {
+			recovery.RecordServerError(requestContext(c), httpReport(c, status))
+		}
+		return err
+	}
+}
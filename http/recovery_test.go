@@ -0,0 +1,109 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+
+	"github.com/team-dandelion/quickgo/recovery"
+)
+
+type captureReporter struct {
+	mu      sync.Mutex
+	reports []*recovery.Report
+}
+
+func (r *captureReporter) Report(_ context.Context, report *recovery.Report) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports = append(r.reports, report)
+}
+
+func (r *captureReporter) snapshot() []*recovery.Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*recovery.Report(nil), r.reports...)
+}
+
+func TestRecoveryReportsPanicWithRequestMetadata(t *testing.T) {
+	reporter := &captureReporter{}
+	recovery.SetReporter(reporter)
+	t.Cleanup(func() { recovery.SetReporter(nil) })
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(ServerErrorReportMiddleware())
+	app.Use(recover.New(recover.Config{EnableStackTrace: true, StackTraceHandler: reportPanic}))
+	app.Get("/orders/:id", func(c *fiber.Ctx) error {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest("GET", "/orders/42", nil)
+	req.Header.Set(fiber.HeaderUserAgent, "quickgo-test")
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", resp.StatusCode)
+	}
+
+	reports := reporter.snapshot()
+	if len(reports) != 1 {
+		t.Fatalf("expected 1 panic report, got %d", len(reports))
+	}
+	report := reports[0]
+	if report.Kind != recovery.ReportKindPanic || report.Message != "boom" || report.Stack == "" {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if report.Transport != "http" || report.Method != "GET" || report.Path != "/orders/:id" || report.Status != 500 {
+		t.Fatalf("unexpected request info: %+v", report)
+	}
+	if report.Metadata["user_agent"] != "quickgo-test" || report.TraceID == "" {
+		t.Fatalf("unexpected metadata: %+v", report)
+	}
+}
+
+func TestServerErrorReportMiddlewareReportsBurst(t *testing.T) {
+	reporter := &captureReporter{}
+	recovery.SetReporter(reporter)
+	t.Cleanup(func() { recovery.SetReporter(nil) })
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(ServerErrorReportMiddleware())
+	app.Get("/fail", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusServiceUnavailable)
+	})
+	app.Get("/ok", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	for i := 0; i < 30; i++ {
+		path := "/fail"
+		if i%2 == 0 {
+			path = "/ok"
+		}
+		if _, err := app.Test(httptest.NewRequest("GET", path, nil), -1); err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+	}
+
+	if reports := reporter.snapshot(); len(reports) != 0 {
+		t.Fatalf("expected no burst below threshold, got %d", len(reports))
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := app.Test(httptest.NewRequest("GET", "/fail", nil), -1); err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+	}
+	reports := reporter.snapshot()
+	if len(reports) != 1 {
+		t.Fatalf("expected a single burst report, got %d", len(reports))
+	}
+	if report := reports[0]; report.Kind != recovery.ReportKindErrorBurst || report.Count != 20 || report.Status != 503 {
+		t.Fatalf("unexpected burst report: %+v", report)
+	}
+}
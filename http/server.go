@@ -2,17 +2,19 @@ package http
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 
+	"github.com/team-dandelion/quickgo/handover"
 	"github.com/team-dandelion/quickgo/logger"
 	"github.com/team-dandelion/quickgo/tracing"
 )
@@ -27,6 +29,9 @@ type Server struct {
 	mu       sync.RWMutex
 	running  bool
 	stopped  bool
+	inFlight atomic.Int64 // 处理中的请求数
+	// 活跃的 WebSocket 连接
+	websockets webSocketRegistry
 }
 
 // Config HTTP服务器配置
@@ -45,18 +50,40 @@ type Config struct {
 	DisableRecovery bool       // 显式禁用恢复中间件
 	DisableLogging  bool       // 显式禁用日志中间件
 	DisableTrace    bool       // 显式禁用链路追踪中间件
+	// 服务器加固配置
+	Limits LimitsConfig // 请求体、超时、并发等限制
+	// TLS 配置（非 nil 时以 HTTPS 提供服务）
+	TLSConfig *tls.Config
 	// 自定义中间件
 	Middlewares []fiber.Handler // 自定义中间件列表
 }
 
+// LimitsConfig 服务器加固配置，为 0 的项保持 Fiber 默认值（显式设置的值覆盖 FiberConfig 中的同名项）
+type LimitsConfig struct {
+	BodyLimit      int           // 请求体最大字节数（Fiber 默认 4MB），超过返回 413
+	ReadTimeout    time.Duration // 读取完整请求的超时，防止慢速发送请求头/请求体（slowloris）
+	WriteTimeout   time.Duration // 写响应超时
+	IdleTimeout    time.Duration // keep-alive 连接空闲超时（默认与 ReadTimeout 相同）
+	ReadBufferSize int           // 单连接读缓冲区大小，同时限制请求头大小（Fiber 默认 4096）
+	Concurrency    int           // 最大并发连接数（Fiber 默认 256 * 1024）
+	// MaxInFlight 全局最大处理中请求数，超过时直接返回 503（0 不限制）
+	MaxInFlight int
+	// RequestTimeout 请求处理超时，通过 UserContext 截止时间传递给处理器，超时返回 408（0 不限制）
+	RequestTimeout time.Duration
+	// RouteTimeouts 按路径前缀设置处理超时（最长前缀优先），覆盖 RequestTimeout
+	RouteTimeouts map[string]time.Duration
+}
+
 // CORSConfig CORS 配置
 type CORSConfig struct {
-	AllowOrigins     string // 允许的源，默认 "*"
-	AllowMethods     string // 允许的方法，默认 "GET,POST,HEAD,PUT,DELETE,PATCH"
-	AllowHeaders     string // 允许的请求头，默认 "*"
-	AllowCredentials bool   // 是否允许凭证，默认 false
-	ExposeHeaders    string // 暴露的响应头
-	MaxAge           int    // 预检请求缓存时间（秒），默认 0
+	AllowOrigins string // 允许的源（逗号分隔，支持 https://*.example.com），默认 "*"
+	// 允许的源正则（如 ^https://[a-z0-9-]+\.example\.com$），与 AllowOrigins 任一匹配即允许
+	AllowOriginPatterns []string
+	AllowMethods        string // 允许的方法，默认 "GET,POST,HEAD,PUT,DELETE,PATCH"
+	AllowHeaders        string // 允许的请求头，默认 "*"
+	AllowCredentials    bool   // 是否允许凭证，默认 false
+	ExposeHeaders       string // 暴露的响应头
+	MaxAge              int    // 预检请求缓存时间（秒），默认 0
 }
 
 // NewServer 创建新的 HTTP 服务器实例
@@ -75,6 +102,7 @@ func NewServer(config Config) (*Server, error) {
 	if fiberCfg.ErrorHandler == nil {
 		fiberCfg.ErrorHandler = defaultErrorHandler
 	}
+	config.Limits.applyTo(&fiberCfg)
 
 	// 创建 Fiber 应用
 	app := fiber.New(fiberCfg)
@@ -86,8 +114,13 @@ func NewServer(config Config) (*Server, error) {
 		config:  config,
 	}
 
+	// 统计处理中的请求（最先注册，覆盖所有中间件与路由）
+	app.Use(server.trackInFlight)
+
 	// 注册默认中间件
-	server.registerDefaultMiddlewares()
+	if err := server.registerDefaultMiddlewares(); err != nil {
+		return nil, err
+	}
 
 	// 注册自定义中间件
 	for _, middleware := range config.Middlewares {
@@ -117,7 +150,7 @@ func (c *Config) applyMiddlewareDefaults() {
 }
 
 // registerDefaultMiddlewares 注册默认中间件
-func (s *Server) registerDefaultMiddlewares() {
+func (s *Server) registerDefaultMiddlewares() error {
 	// 链路追踪中间件（应该最先执行，以便后续中间件可以使用 trace ID）
 	if s.config.EnableTrace {
 		// 如果 OpenTelemetry tracing 已启用，使用 OpenTelemetry 中间件
@@ -129,39 +162,67 @@ func (s *Server) registerDefaultMiddlewares() {
 		}
 	}
 
+	// 请求 context 中间件：合并 trace、身份与 RequestTimeoutHeader 截止时间，写入 UserContext
+	s.app.Use(RequestContextMiddleware())
+
 	// 日志中间件
 	if s.config.EnableLogging {
 		s.app.Use(LoggingMiddleware())
 	}
 
-	// 恢复中间件
+	// 恢复中间件（panic 记录堆栈并上报到 recovery.Reporter，5xx 突增同样上报）
 	if s.config.EnableRecovery {
+		s.app.Use(ServerErrorReportMiddleware())
 		s.app.Use(recover.New(recover.Config{
-			EnableStackTrace: true,
+			EnableStackTrace:  true,
+			StackTraceHandler: reportPanic,
 		}))
 	}
 
 	// CORS 中间件
 	if s.config.EnableCORS {
-		corsCfg := cors.Config{
-			AllowOrigins:     s.config.CORSConfig.AllowOrigins,
-			AllowMethods:     s.config.CORSConfig.AllowMethods,
-			AllowHeaders:     s.config.CORSConfig.AllowHeaders,
-			AllowCredentials: s.config.CORSConfig.AllowCredentials,
-			ExposeHeaders:    s.config.CORSConfig.ExposeHeaders,
-			MaxAge:           s.config.CORSConfig.MaxAge,
-		}
-		// 设置默认值
-		if corsCfg.AllowOrigins == "" {
-			corsCfg.AllowOrigins = "*"
-		}
-		if corsCfg.AllowMethods == "" {
-			corsCfg.AllowMethods = "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS"
-		}
-		if corsCfg.AllowHeaders == "" {
-			corsCfg.AllowHeaders = "*"
+		handler, effective, err := newCORSMiddleware(s.config.CORSConfig)
+		if err != nil {
+			return err
 		}
-		s.app.Use(cors.New(corsCfg))
+		logCORSPolicy(effective)
+		s.app.Use(handler)
+	}
+
+	// 全局在途请求限制
+	if s.config.Limits.MaxInFlight > 0 {
+		s.app.Use(InFlightLimitMiddleware(s.config.Limits.MaxInFlight))
+	}
+
+	// 请求处理超时
+	if s.config.Limits.RequestTimeout > 0 || len(s.config.Limits.RouteTimeouts) > 0 {
+		s.app.Use(RequestTimeoutMiddleware(RequestTimeoutConfig{
+			Timeout:       s.config.Limits.RequestTimeout,
+			RouteTimeouts: s.config.Limits.RouteTimeouts,
+		}))
+	}
+	return nil
+}
+
+// applyTo 将显式设置的限制写入 Fiber 配置
+func (l LimitsConfig) applyTo(cfg *fiber.Config) {
+	if l.BodyLimit > 0 {
+		cfg.BodyLimit = l.BodyLimit
+	}
+	if l.ReadTimeout > 0 {
+		cfg.ReadTimeout = l.ReadTimeout
+	}
+	if l.WriteTimeout > 0 {
+		cfg.WriteTimeout = l.WriteTimeout
+	}
+	if l.IdleTimeout > 0 {
+		cfg.IdleTimeout = l.IdleTimeout
+	}
+	if l.ReadBufferSize > 0 {
+		cfg.ReadBufferSize = l.ReadBufferSize
+	}
+	if l.Concurrency > 0 {
+		cfg.Concurrency = l.Concurrency
 	}
 }
 
@@ -170,6 +231,22 @@ func (s *Server) GetApp() *fiber.App {
 	return s.app
 }
 
+// InFlight 获取当前处理中的请求数
+func (s *Server) InFlight() int64 {
+	return s.inFlight.Load()
+}
+
+// OpenConnections 获取当前打开的客户端连接数
+func (s *Server) OpenConnections() int {
+	return int(s.app.Server().GetOpenConnectionsCount())
+}
+
+func (s *Server) trackInFlight(c *fiber.Ctx) error {
+	s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+	return c.Next()
+}
+
 // Start 启动 HTTP 服务器
 func (s *Server) Start() error {
 	if s.isStopped() {
@@ -211,10 +288,13 @@ func (s *Server) Listen() error {
 		return nil
 	}
 
-	listener, err := net.Listen("tcp", s.GetAddress())
+	listener, err := handover.Listen("tcp", s.GetAddress())
 	if err != nil {
 		return fmt.Errorf("failed to listen on %s: %w", s.GetAddress(), err)
 	}
+	if s.config.TLSConfig != nil {
+		listener = tls.NewListener(listener, s.config.TLSConfig)
+	}
 	s.listener = listener
 	return nil
 }
@@ -263,6 +343,10 @@ func (s *Server) Stop() error {
 		return nil
 	}
 	logger.Info(ctx, "HTTP server shutting down...")
+	// WebSocket 连接已被劫持，不受 Shutdown 管理，需先主动关闭
+	if closed := s.websockets.closeAll(); closed > 0 {
+		logger.Info(ctx, "Closed websocket connections: count=%d", closed)
+	}
 	err := errors.Join(s.app.Shutdown(), s.closeListener())
 	s.setStopped()
 	if isHTTPServerClosedError(err) {
@@ -1,7 +1,15 @@
 package http
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
 	"net"
+	nethttp "net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
@@ -77,6 +85,73 @@ func TestServerPreservesConfiguredReadWriteTimeouts(t *testing.T) {
 	}
 }
 
+func TestServerAppliesLimits(t *testing.T) {
+	server, err := NewServer(Config{
+		FiberConfig: fiber.Config{
+			DisableStartupMessage: true,
+			ReadTimeout:           3 * time.Second,
+		},
+		Limits: LimitsConfig{
+			BodyLimit:    16,
+			IdleTimeout:  5 * time.Second,
+			WriteTimeout: 4 * time.Second,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+
+	fasthttpServer := server.GetApp().Server()
+	if fasthttpServer.MaxRequestBodySize != 16 {
+		t.Fatalf("expected body limit 16, got %d", fasthttpServer.MaxRequestBodySize)
+	}
+	if fasthttpServer.ReadTimeout != 3*time.Second || fasthttpServer.WriteTimeout != 4*time.Second || fasthttpServer.IdleTimeout != 5*time.Second {
+		t.Fatalf("unexpected timeouts: read=%s write=%s idle=%s", fasthttpServer.ReadTimeout, fasthttpServer.WriteTimeout, fasthttpServer.IdleTimeout)
+	}
+}
+
+func TestServerRequestTimeoutUsesLongestRoutePrefix(t *testing.T) {
+	server, err := NewServer(Config{
+		FiberConfig: fiber.Config{DisableStartupMessage: true},
+		Limits: LimitsConfig{
+			RequestTimeout: 20 * time.Millisecond,
+			RouteTimeouts: map[string]time.Duration{
+				"/api":        20 * time.Millisecond,
+				"/api/export": time.Second,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	slow := func(c *fiber.Ctx) error {
+		select {
+		case <-c.UserContext().Done():
+			return c.UserContext().Err()
+		case <-time.After(100 * time.Millisecond):
+			return c.SendStatus(fiber.StatusOK)
+		}
+	}
+	server.GetApp().Get("/api/items", slow)
+	server.GetApp().Get("/api/export", slow)
+
+	resp, err := server.GetApp().Test(httptest.NewRequest("GET", "/api/items", nil), -1)
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusRequestTimeout {
+		t.Fatalf("expected 408 after request timeout, got %d", resp.StatusCode)
+	}
+
+	resp, err = server.GetApp().Test(httptest.NewRequest("GET", "/api/export", nil), -1)
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected route timeout to override default, got %d", resp.StatusCode)
+	}
+}
+
 func TestServerDefaultMiddlewaresCanBeDisabledIndividually(t *testing.T) {
 	server, err := NewServer(Config{
 		DisableCORS:  true,
@@ -196,6 +271,91 @@ func TestServerStopIsIdempotent(t *testing.T) {
 	}
 }
 
+func TestServerTracksInFlightRequests(t *testing.T) {
+	server, err := NewServer(Config{FiberConfig: fiber.Config{DisableStartupMessage: true}})
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	server.GetApp().Get("/slow", func(c *fiber.Ctx) error {
+		close(entered)
+		<-release
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = server.GetApp().Test(httptest.NewRequest("GET", "/slow", nil), -1)
+	}()
+	<-entered
+	if got := server.InFlight(); got != 1 {
+		t.Fatalf("expected 1 in-flight request, got %d", got)
+	}
+	close(release)
+	<-done
+	if got := server.InFlight(); got != 0 {
+		t.Fatalf("expected in-flight requests to drain, got %d", got)
+	}
+}
+
+func TestServerServesTLS(t *testing.T) {
+	cert := newTestCertificate(t)
+	port := reserveTCPPort(t)
+	server, err := NewServer(Config{
+		Address:     "127.0.0.1",
+		Port:        port,
+		FiberConfig: fiber.Config{DisableStartupMessage: true},
+		TLSConfig:   &tls.Config{Certificates: []tls.Certificate{cert}},
+	})
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	server.GetApp().Get("/ping", func(c *fiber.Ctx) error {
+		return c.SendString("pong")
+	})
+	if err := server.StartAsync(); err != nil {
+		t.Fatalf("StartAsync failed: %v", err)
+	}
+	defer server.Stop()
+
+	client := &nethttp.Client{Transport: &nethttp.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+	resp, err := client.Get("https://" + server.GetAddress() + "/ping")
+	if err != nil {
+		t.Fatalf("https request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != fiber.StatusOK || resp.TLS == nil {
+		t.Fatalf("expected successful TLS response, got status=%d tls=%v", resp.StatusCode, resp.TLS != nil)
+	}
+}
+
+func newTestCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed: %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
 func reserveTCPPort(t *testing.T) int {
 	t.Helper()
 
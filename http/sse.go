@@ -0,0 +1,123 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	jsoniter "github.com/json-iterator/go"
+)
+
+const defaultSSEHeartbeatInterval = 15 * time.Second
+
+// SSEConfig Server-Sent Events 配置
+type SSEConfig struct {
+	// 心跳间隔，定时发送注释行避免代理与负载均衡因空闲断开连接（默认 15s，< 0 时禁用）
+	HeartbeatInterval time.Duration
+	// 客户端断线重连间隔（0 时不下发 retry 字段）
+	Retry time.Duration
+}
+
+// SSEEvent 单个 SSE 事件
+type SSEEvent struct {
+	// 事件 ID，客户端重连时通过 Last-Event-ID 请求头带回
+	ID string
+	// 事件类型（为空时为默认的 message 事件）
+	Event string
+	// 事件数据，多行数据会拆分为多个 data 字段
+	Data string
+}
+
+// SSEWriter SSE 写入器，每个事件作为一次整体写入，与心跳并发写入时不会交错
+type SSEWriter struct {
+	*StreamWriter
+}
+
+// Send 发送一个事件
+func (w *SSEWriter) Send(event SSEEvent) error {
+	var b strings.Builder
+	if event.ID != "" {
+		b.WriteString("id: " + sanitizeSSEField(event.ID) + "\n")
+	}
+	if event.Event != "" {
+		b.WriteString("event: " + sanitizeSSEField(event.Event) + "\n")
+	}
+	for _, line := range strings.Split(event.Data, "\n") {
+		b.WriteString("data: " + strings.TrimSuffix(line, "\r") + "\n")
+	}
+	b.WriteString("\n")
+	_, err := w.WriteString(b.String())
+	return err
+}
+
+// SendJSON 将 v 编码为 JSON 作为事件数据发送
+func (w *SSEWriter) SendJSON(id, event string, v interface{}) error {
+	data, err := jsoniter.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return w.Send(SSEEvent{ID: id, Event: event, Data: string(data)})
+}
+
+// Comment 发送注释行（客户端会忽略），用于心跳
+func (w *SSEWriter) Comment(text string) error {
+	_, err := w.WriteString(": " + sanitizeSSEField(text) + "\n\n")
+	return err
+}
+
+// sanitizeSSEField 去除单行字段中的换行，避免注入额外字段
+func sanitizeSSEField(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
+
+// SSE 以 Server-Sent Events 方式发送响应
+// 设置 text/event-stream 相关响应头后基于 Stream 发送（同样受 StreamLimitMiddleware 背压限制），
+// fn 返回前按 HeartbeatInterval 发送心跳；客户端断开时 w.Context() 被取消，fn 应据此尽快返回
+func SSE(c *fiber.Ctx, fn func(w *SSEWriter) error, config ...SSEConfig) error {
+	var cfg SSEConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.HeartbeatInterval == 0 {
+		cfg.HeartbeatInterval = defaultSSEHeartbeatInterval
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	return Stream(c, func(sw *StreamWriter) error {
+		w := &SSEWriter{StreamWriter: sw}
+		if cfg.Retry > 0 {
+			if _, err := w.WriteString("retry: " + strconv.FormatInt(cfg.Retry.Milliseconds(), 10) + "\n\n"); err != nil {
+				return err
+			}
+		}
+		if cfg.HeartbeatInterval > 0 {
+			done := make(chan struct{})
+			defer close(done)
+			go w.heartbeat(cfg.HeartbeatInterval, done)
+		}
+		return fn(w)
+	})
+}
+
+// heartbeat 定时发送心跳，fn 返回或流结束时退出
+func (w *SSEWriter) heartbeat(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-w.Context().Done():
+			return
+		case <-ticker.C:
+			if err := w.Comment("ping"); err != nil {
+				return
+			}
+		}
+	}
+}
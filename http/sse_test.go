@@ -0,0 +1,57 @@
+package http
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestSSESendsEventsAndHeartbeat(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/events", func(c *fiber.Ctx) error {
+		return SSE(c, func(w *SSEWriter) error {
+			if err := w.Send(SSEEvent{ID: "1", Event: "tick", Data: "a\nb"}); err != nil {
+				return err
+			}
+			time.Sleep(50 * time.Millisecond)
+			return w.SendJSON("2", "", map[string]int{"n": 2})
+		}, SSEConfig{HeartbeatInterval: 10 * time.Millisecond, Retry: time.Second})
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/events", nil), -1)
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if ct := resp.Header.Get(fiber.HeaderContentType); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	got := string(body)
+	if !strings.HasPrefix(got, "retry: 1000\n\nid: 1\nevent: tick\ndata: a\ndata: b\n\n") {
+		t.Fatalf("unexpected body prefix %q", got)
+	}
+	if !strings.Contains(got, ": ping\n\n") {
+		t.Fatalf("expected heartbeat in body %q", got)
+	}
+	if !strings.HasSuffix(got, "id: 2\ndata: {\"n\":2}\n\n") {
+		t.Fatalf("unexpected body suffix %q", got)
+	}
+}
+
+func TestSSEWriterSanitizesSingleLineFields(t *testing.T) {
+	w := newStreamWriter(t.Context(), normalizeStreamLimitConfig(StreamLimitConfig{}), nil)
+	defer w.close()
+	sw := &SSEWriter{StreamWriter: w}
+	if err := sw.Send(SSEEvent{ID: "1\nevent: x", Data: "ok"}); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	w.finish()
+	chunks, _ := w.next()
+	if got := string(chunks[0].data); got != "id: 1event: x\ndata: ok\n\n" {
+		t.Fatalf("unexpected event %q", got)
+	}
+}
@@ -0,0 +1,425 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+const streamLimitLocalsKey = "stream_limit"
+
+var (
+	// ErrStreamBufferExceeded 客户端消费过慢，待发送数据超过 MaxBufferedBytes
+	ErrStreamBufferExceeded = errors.New("http stream buffer limit exceeded")
+	// ErrStreamLagExceeded 客户端消费过慢，最早一段待发送数据滞留超过 MaxLag
+	ErrStreamLagExceeded = errors.New("http stream lag limit exceeded")
+	// ErrStreamClosed 流已结束（客户端断开或已被策略断开）
+	ErrStreamClosed = errors.New("http stream closed")
+)
+
+// StreamLimitConfig 流式响应（chunked / SSE）背压限制配置
+// 慢客户端无法及时消费时数据会堆积在网关内存中，超过任一阈值即主动断开该连接
+type StreamLimitConfig struct {
+	// 单个连接允许堆积的最大待发送字节数（默认 1MB）
+	MaxBufferedBytes int
+	// 最早一段待发送数据允许滞留的最长时间（默认 30s）
+	MaxLag time.Duration
+}
+
+// StreamStats 流式响应统计（进程内累计）
+type StreamStats struct {
+	Active           int64 // 当前活跃的流
+	BufferedBytes    int64 // 当前所有流待发送的字节数
+	SentBytes        int64 // 已发送给客户端的字节数
+	BufferExceeded   int64 // 因待发送字节超限被断开的流数量
+	LagExceeded      int64 // 因滞留时间超限被断开的流数量
+	ClientDisconnect int64 // 客户端主动断开的流数量
+}
+
+type streamCounters struct {
+	active           atomic.Int64
+	buffered         atomic.Int64
+	sent             atomic.Int64
+	bufferExceeded   atomic.Int64
+	lagExceeded      atomic.Int64
+	clientDisconnect atomic.Int64
+}
+
+var globalStreamCounters streamCounters
+
+// GetStreamStats 获取流式响应统计
+func GetStreamStats() StreamStats {
+	return StreamStats{
+		Active:           globalStreamCounters.active.Load(),
+		BufferedBytes:    globalStreamCounters.buffered.Load(),
+		SentBytes:        globalStreamCounters.sent.Load(),
+		BufferExceeded:   globalStreamCounters.bufferExceeded.Load(),
+		LagExceeded:      globalStreamCounters.lagExceeded.Load(),
+		ClientDisconnect: globalStreamCounters.clientDisconnect.Load(),
+	}
+}
+
+// StreamLimitMiddleware 为后续通过 Stream 发送的流式响应设置背压限制
+// 可全局注册，也可注册在具体路由上覆盖全局配置
+func StreamLimitMiddleware(config StreamLimitConfig) fiber.Handler {
+	config = normalizeStreamLimitConfig(config)
+	return func(c *fiber.Ctx) error {
+		c.Locals(streamLimitLocalsKey, config)
+		return c.Next()
+	}
+}
+
+func normalizeStreamLimitConfig(config StreamLimitConfig) StreamLimitConfig {
+	if config.MaxBufferedBytes <= 0 {
+		config.MaxBufferedBytes = 1 << 20
+	}
+	if config.MaxLag <= 0 {
+		config.MaxLag = 30 * time.Second
+	}
+	return config
+}
+
+type streamChunk struct {
+	data []byte
+	at   time.Time
+}
+
+// StreamWriter 流式响应写入器
+// 写入的数据先进入有界缓冲区，由连接协程异步发送给客户端，生产者不会被慢客户端阻塞
+type StreamWriter struct {
+	config StreamLimitConfig
+	ctx    context.Context
+	cancel context.CancelFunc
+	closer func() error
+
+	mu            sync.Mutex
+	cond          *sync.Cond
+	chunks        []streamChunk
+	buffered      int
+	inflightSince time.Time
+	done          bool
+	err           error
+}
+
+func newStreamWriter(ctx context.Context, config StreamLimitConfig, closer func() error) *StreamWriter {
+	ctx, cancel := context.WithCancel(ctx)
+	w := &StreamWriter{
+		config: config,
+		ctx:    ctx,
+		cancel: cancel,
+		closer: closer,
+	}
+	w.cond = sync.NewCond(&w.mu)
+	return w
+}
+
+// Context 流的上下文，客户端断开或触发背压策略时取消
+func (w *StreamWriter) Context() context.Context {
+	return w.ctx
+}
+
+// Write 写入一段数据；客户端已断开或超过背压限制时返回错误
+func (w *StreamWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	w.mu.Lock()
+	if w.err != nil {
+		err := w.err
+		w.mu.Unlock()
+		return 0, err
+	}
+	if w.buffered+len(p) > w.config.MaxBufferedBytes {
+		w.mu.Unlock()
+		w.abort(ErrStreamBufferExceeded)
+		return 0, ErrStreamBufferExceeded
+	}
+	w.chunks = append(w.chunks, streamChunk{data: append([]byte(nil), p...), at: time.Now()})
+	w.buffered += len(p)
+	globalStreamCounters.buffered.Add(int64(len(p)))
+	w.cond.Broadcast()
+	w.mu.Unlock()
+	return len(p), nil
+}
+
+// WriteString 写入字符串
+func (w *StreamWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteEvent 以 SSE 格式写入一个事件（event 为空时省略 event 字段）
+func (w *StreamWriter) WriteEvent(event, data string) error {
+	msg := ""
+	if event != "" {
+		msg = "event: " + event + "\n"
+	}
+	start := 0
+	for i := 0; i <= len(data); i++ {
+		if i == len(data) || data[i] == '\n' {
+			msg += "data: " + data[start:i] + "\n"
+			start = i + 1
+		}
+	}
+	_, err := w.WriteString(msg + "\n")
+	return err
+}
+
+// waitBuffered 阻塞直到待发送字节数不超过 n 或流结束，供需要按客户端速度生产数据的调用方使用
+func (w *StreamWriter) waitBuffered(n int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for w.buffered > n && w.err == nil {
+		w.cond.Wait()
+	}
+	return w.err
+}
+
+// abort 终止流并关闭底层连接，使阻塞在慢客户端上的写操作尽快返回
+func (w *StreamWriter) abort(reason error) {
+	w.mu.Lock()
+	if w.err != nil {
+		w.mu.Unlock()
+		return
+	}
+	w.err = reason
+	w.cond.Broadcast()
+	w.mu.Unlock()
+	// 统计与关闭连接完成后再取消 ctx，监听 Context().Done() 的调用方可看到最终状态
+	defer w.cancel()
+
+	switch {
+	case errors.Is(reason, ErrStreamBufferExceeded):
+		globalStreamCounters.bufferExceeded.Add(1)
+	case errors.Is(reason, ErrStreamLagExceeded):
+		globalStreamCounters.lagExceeded.Add(1)
+	default:
+		globalStreamCounters.clientDisconnect.Add(1)
+	}
+	if errors.Is(reason, ErrStreamBufferExceeded) || errors.Is(reason, ErrStreamLagExceeded) {
+		logger.Warn(w.ctx, "HTTP stream client too slow, disconnecting: reason=%v, max_buffered_bytes=%d, max_lag=%s",
+			reason, w.config.MaxBufferedBytes, w.config.MaxLag)
+		if w.closer != nil {
+			_ = w.closer()
+		}
+	}
+}
+
+// finish 生产者结束写入
+func (w *StreamWriter) finish() {
+	w.mu.Lock()
+	w.done = true
+	w.cond.Broadcast()
+	w.mu.Unlock()
+}
+
+// next 取出全部待发送数据；流结束且无数据时返回 false
+func (w *StreamWriter) next() ([]streamChunk, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for len(w.chunks) == 0 && !w.done && w.err == nil {
+		w.cond.Wait()
+	}
+	if w.err != nil || len(w.chunks) == 0 {
+		return nil, false
+	}
+	chunks := w.chunks
+	w.chunks = nil
+	w.inflightSince = chunks[0].at
+	return chunks, true
+}
+
+// sent 标记一批数据已发送给客户端
+func (w *StreamWriter) sent(n int) {
+	w.mu.Lock()
+	w.buffered -= n
+	w.inflightSince = time.Time{}
+	w.cond.Broadcast()
+	w.mu.Unlock()
+	globalStreamCounters.buffered.Add(-int64(n))
+	globalStreamCounters.sent.Add(int64(n))
+}
+
+// lag 最早一段待发送数据已滞留的时间
+func (w *StreamWriter) lag(now time.Time) time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	oldest := w.inflightSince
+	if oldest.IsZero() && len(w.chunks) > 0 {
+		oldest = w.chunks[0].at
+	}
+	if oldest.IsZero() {
+		return 0
+	}
+	return now.Sub(oldest)
+}
+
+// close 结束流并归还仍未发送的字节计数
+func (w *StreamWriter) close() {
+	w.mu.Lock()
+	if w.err == nil {
+		w.err = ErrStreamClosed
+	}
+	remaining := w.buffered
+	w.buffered = 0
+	w.chunks = nil
+	w.cond.Broadcast()
+	w.mu.Unlock()
+	w.cancel()
+	globalStreamCounters.buffered.Add(-int64(remaining))
+}
+
+// watch 周期检查滞留时间，超过 MaxLag 时断开连接
+func (w *StreamWriter) watch() {
+	interval := w.config.MaxLag / 4
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case now := <-ticker.C:
+			if w.lag(now) > w.config.MaxLag {
+				w.abort(ErrStreamLagExceeded)
+				return
+			}
+		}
+	}
+}
+
+// drain 在连接协程中将缓冲数据发送给客户端
+func (w *StreamWriter) drain(bw *bufio.Writer) {
+	for {
+		chunks, ok := w.next()
+		if !ok {
+			return
+		}
+		n := 0
+		var err error
+		for _, chunk := range chunks {
+			if _, err = bw.Write(chunk.data); err != nil {
+				break
+			}
+			n += len(chunk.data)
+		}
+		if err == nil {
+			err = bw.Flush()
+		}
+		if err != nil {
+			w.abort(fmt.Errorf("%w: %v", ErrStreamClosed, err))
+			return
+		}
+		w.sent(n)
+	}
+}
+
+// Stream 以流式方式发送响应（适用于 chunked / SSE）
+// fn 在独立协程中执行，通过 StreamWriter 写入数据；客户端消费过慢时按 StreamLimitMiddleware
+// 配置（未配置时使用默认值）断开连接，此后 Write 返回错误且 w.Context() 被取消
+func Stream(c *fiber.Ctx, fn func(w *StreamWriter) error) error {
+	config, ok := c.Locals(streamLimitLocalsKey).(StreamLimitConfig)
+	if !ok {
+		config = normalizeStreamLimitConfig(StreamLimitConfig{})
+	}
+
+	ctx := context.Background()
+	if traceID := GetTraceID(c); traceID != "" {
+		ctx = logger.WithTraceID(ctx, traceID)
+	}
+	fctx := c.Context()
+	conn := fctx.Conn()
+	writer := newStreamWriter(ctx, config, conn.Close)
+
+	fctx.SetBodyStreamWriter(func(bw *bufio.Writer) {
+		globalStreamCounters.active.Add(1)
+		defer globalStreamCounters.active.Add(-1)
+
+		go writer.watch()
+		go func() {
+			defer writer.finish()
+			if err := fn(writer); err != nil && !errors.Is(err, ErrStreamClosed) &&
+				!errors.Is(err, ErrStreamBufferExceeded) && !errors.Is(err, ErrStreamLagExceeded) {
+				logger.Error(writer.ctx, "HTTP stream handler error: %v", err)
+			}
+		}()
+
+		writer.drain(bw)
+		writer.close()
+	})
+	return nil
+}
+
+var registerStreamMetricsMu sync.Mutex
+
+// RegisterStreamMetrics 将流式响应指标注册到 Prometheus（重复注册时忽略）
+// 指标：{namespace}_http_streams_active、_stream_buffered_bytes、_stream_sent_bytes_total、_stream_disconnects_total{reason}
+func RegisterStreamMetrics(registerer prometheus.Registerer, namespace string) error {
+	if registerer == nil {
+		return errors.New("registerer is nil")
+	}
+	registerStreamMetricsMu.Lock()
+	defer registerStreamMetricsMu.Unlock()
+
+	collectors := []prometheus.Collector{
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: "http", Name: "streams_active",
+			Help: "Number of HTTP streamed responses in progress",
+		}, func() float64 { return float64(globalStreamCounters.active.Load()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: "http", Name: "stream_buffered_bytes",
+			Help: "Bytes buffered for slow HTTP stream clients",
+		}, func() float64 { return float64(globalStreamCounters.buffered.Load()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "http", Name: "stream_sent_bytes_total",
+			Help: "Total bytes sent to HTTP stream clients",
+		}, func() float64 { return float64(globalStreamCounters.sent.Load()) }),
+		newStreamDisconnectCollector(namespace),
+	}
+	for _, collector := range collectors {
+		if err := registerer.Register(collector); err != nil {
+			var already prometheus.AlreadyRegisteredError
+			if errors.As(err, &already) {
+				continue
+			}
+			return fmt.Errorf("failed to register http stream metrics: %w", err)
+		}
+	}
+	return nil
+}
+
+// streamDisconnectCollector 按原因导出流断开次数
+type streamDisconnectCollector struct {
+	desc *prometheus.Desc
+}
+
+func newStreamDisconnectCollector(namespace string) *streamDisconnectCollector {
+	return &streamDisconnectCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "http", "stream_disconnects_total"),
+			"Total number of HTTP streams terminated before completion",
+			[]string{"reason"}, nil,
+		),
+	}
+}
+
+func (c *streamDisconnectCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *streamDisconnectCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := GetStreamStats()
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, float64(stats.BufferExceeded), "buffer_exceeded")
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, float64(stats.LagExceeded), "lag_exceeded")
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, float64(stats.ClientDisconnect), "client_disconnect")
+}
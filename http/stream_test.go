@@ -0,0 +1,90 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestStreamSendsServerSentEvents(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/events", StreamLimitMiddleware(StreamLimitConfig{MaxBufferedBytes: 1024}), func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+		return Stream(c, func(w *StreamWriter) error {
+			if err := w.WriteEvent("tick", "1"); err != nil {
+				return err
+			}
+			return w.WriteEvent("", "a\nb")
+		})
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/events", nil), -1)
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	want := "event: tick\ndata: 1\n\ndata: a\ndata: b\n\n"
+	if string(body) != want {
+		t.Fatalf("unexpected body %q, want %q", body, want)
+	}
+}
+
+func TestStreamWriterDisconnectsWhenBufferExceeded(t *testing.T) {
+	var closed atomic.Bool
+	w := newStreamWriter(context.Background(), normalizeStreamLimitConfig(StreamLimitConfig{MaxBufferedBytes: 8}), func() error {
+		closed.Store(true)
+		return nil
+	})
+	defer w.close()
+
+	if _, err := w.WriteString("12345"); err != nil {
+		t.Fatalf("first write failed: %v", err)
+	}
+	before := GetStreamStats().BufferExceeded
+	if _, err := w.WriteString("6789"); !errors.Is(err, ErrStreamBufferExceeded) {
+		t.Fatalf("expected ErrStreamBufferExceeded, got %v", err)
+	}
+	if !closed.Load() {
+		t.Fatal("expected connection to be closed")
+	}
+	if w.Context().Err() == nil {
+		t.Fatal("expected stream context to be canceled")
+	}
+	if got := GetStreamStats().BufferExceeded; got != before+1 {
+		t.Fatalf("expected buffer exceeded counter to increase, got %d -> %d", before, got)
+	}
+	if _, err := w.WriteString("x"); !errors.Is(err, ErrStreamBufferExceeded) {
+		t.Fatalf("expected writes after disconnect to fail, got %v", err)
+	}
+}
+
+func TestStreamWriterDisconnectsWhenLagExceeded(t *testing.T) {
+	var closed atomic.Bool
+	w := newStreamWriter(context.Background(), StreamLimitConfig{MaxBufferedBytes: 1024, MaxLag: 20 * time.Millisecond}, func() error {
+		closed.Store(true)
+		return nil
+	})
+	defer w.close()
+	go w.watch()
+
+	if _, err := w.WriteString("pending"); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	select {
+	case <-w.Context().Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected lagging stream to be disconnected")
+	}
+	if !closed.Load() {
+		t.Fatal("expected connection to be closed")
+	}
+	if _, err := w.WriteString("more"); !errors.Is(err, ErrStreamLagExceeded) {
+		t.Fatalf("expected ErrStreamLagExceeded, got %v", err)
+	}
+}
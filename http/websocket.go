@@ -0,0 +1,204 @@
+package http
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/recovery"
+)
+
+const (
+	// websocketContextKey 升级前保存请求 context 的 Locals key（升级时 Locals 会复制到连接上）
+	websocketContextKey = "websocket_context"
+
+	defaultWebSocketPingInterval = 30 * time.Second
+	websocketCloseTimeout        = time.Second
+)
+
+// WebSocketConfig WebSocket 配置
+type WebSocketConfig struct {
+	// 服务端发送 ping 的间隔（默认 30s，< 0 时禁用 keepalive）
+	PingInterval time.Duration
+	// 等待 pong（或任意消息）的最长时间，超时后读取返回错误并关闭连接（默认 PingInterval 的 2 倍）
+	PongWait time.Duration
+	// 握手超时
+	HandshakeTimeout time.Duration
+	// 允许的 Origin（为空时允许全部）
+	Origins []string
+	// 支持的子协议
+	Subprotocols []string
+	// 读写缓冲区大小（默认 1024）
+	ReadBufferSize  int
+	WriteBufferSize int
+	// 单条消息最大字节数（0 不限制）
+	ReadLimit int64
+	// 是否协商消息压缩
+	EnableCompression bool
+}
+
+// WebSocketConn WebSocket 连接，Context() 携带握手请求的链路信息（trace_id / span）
+type WebSocketConn struct {
+	*websocket.Conn
+	ctx context.Context
+}
+
+// Context 获取连接 context，服务器关闭或连接断开后被取消
+func (c *WebSocketConn) Context() context.Context {
+	return c.ctx
+}
+
+// WebSocketHandler WebSocket 连接处理函数，返回时连接关闭
+type WebSocketHandler func(conn *WebSocketConn)
+
+// webSocketRegistry 活跃连接表，服务器关闭时统一发送 close 帧并断开
+type webSocketRegistry struct {
+	mu     sync.Mutex
+	conns  map[*websocket.Conn]context.CancelFunc
+	closed bool
+}
+
+func (r *webSocketRegistry) add(conn *websocket.Conn, cancel context.CancelFunc) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return false
+	}
+	if r.conns == nil {
+		r.conns = make(map[*websocket.Conn]context.CancelFunc)
+	}
+	r.conns[conn] = cancel
+	return true
+}
+
+func (r *webSocketRegistry) remove(conn *websocket.Conn) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.conns, conn)
+}
+
+// closeAll 向所有连接发送 1001 (going away) 并关闭底层连接，之后建立的连接直接拒绝
+func (r *webSocketRegistry) closeAll() int {
+	r.mu.Lock()
+	r.closed = true
+	conns := r.conns
+	r.conns = nil
+	r.mu.Unlock()
+
+	deadline := time.Now().Add(websocketCloseTimeout)
+	message := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	for conn, cancel := range conns {
+		_ = conn.WriteControl(websocket.CloseMessage, message, deadline)
+		_ = conn.Close()
+		cancel()
+	}
+	return len(conns)
+}
+
+// WebSocket 注册 WebSocket 路由
+// 握手请求经过服务器的全部中间件（链路追踪、日志等），连接 context 继承握手请求的 trace_id 与 span；
+// 服务端按 PingInterval 发送 ping 保活，服务器 Stop 时向所有连接发送 close 帧后断开
+func (s *Server) WebSocket(path string, handler WebSocketHandler, config ...WebSocketConfig) fiber.Router {
+	var cfg WebSocketConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.PingInterval == 0 {
+		cfg.PingInterval = defaultWebSocketPingInterval
+	}
+	if cfg.PongWait <= 0 && cfg.PingInterval > 0 {
+		cfg.PongWait = 2 * cfg.PingInterval
+	}
+
+	upgrade := websocket.New(func(conn *websocket.Conn) {
+		s.serveWebSocket(conn, cfg, handler)
+	}, websocket.Config{
+		HandshakeTimeout:  cfg.HandshakeTimeout,
+		Origins:           cfg.Origins,
+		Subprotocols:      cfg.Subprotocols,
+		ReadBufferSize:    cfg.ReadBufferSize,
+		WriteBufferSize:   cfg.WriteBufferSize,
+		EnableCompression: cfg.EnableCompression,
+		RecoverHandler:    recoverWebSocket,
+	})
+
+	return s.app.Get(path, func(c *fiber.Ctx) error {
+		if !websocket.IsWebSocketUpgrade(c) {
+			return fiber.ErrUpgradeRequired
+		}
+		if s.isStopped() {
+			return fiber.ErrServiceUnavailable
+		}
+		// 连接生命周期长于请求，保留请求 context 的值但不继承其截止时间
+		c.Locals(websocketContextKey, context.WithoutCancel(RequestContext(c)))
+		return c.Next()
+	}, upgrade)
+}
+
+// serveWebSocket 登记连接、维持 keepalive 并调用业务处理函数
+func (s *Server) serveWebSocket(conn *websocket.Conn, cfg WebSocketConfig, handler WebSocketHandler) {
+	parent, ok := conn.Locals(websocketContextKey).(context.Context)
+	if !ok {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	if !s.websockets.add(conn, cancel) {
+		_ = conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"),
+			time.Now().Add(websocketCloseTimeout))
+		return
+	}
+	defer s.websockets.remove(conn)
+
+	if cfg.ReadLimit > 0 {
+		conn.SetReadLimit(cfg.ReadLimit)
+	}
+	if cfg.PingInterval > 0 {
+		_ = conn.SetReadDeadline(time.Now().Add(cfg.PongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(cfg.PongWait))
+		})
+		go keepWebSocketAlive(ctx, conn, cfg.PingInterval)
+	}
+
+	handler(&WebSocketConn{Conn: conn, ctx: ctx})
+}
+
+// keepWebSocketAlive 定时发送 ping，发送失败（连接已断开）时退出
+func keepWebSocketAlive(ctx context.Context, conn *websocket.Conn, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(websocketCloseTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// recoverWebSocket 处理函数 panic 时记录日志并以 1011 关闭连接
+func recoverWebSocket(conn *websocket.Conn) {
+	if r := recover(); r != nil {
+		ctx, ok := conn.Locals(websocketContextKey).(context.Context)
+		if !ok {
+			ctx = context.Background()
+		}
+		stack := debug.Stack()
+		logger.Error(ctx, "WebSocket handler panic: %v\n%s", r, stack)
+		recovery.ReportPanic(ctx, r, stack, recovery.Report{Transport: "websocket", Method: fiber.MethodGet})
+		_ = conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "internal server error"),
+			time.Now().Add(websocketCloseTimeout))
+	}
+}
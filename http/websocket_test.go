@@ -0,0 +1,112 @@
+package http
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	fastws "github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+func TestServerWebSocketEchoesWithTraceContext(t *testing.T) {
+	port := reserveTCPPort(t)
+	server, err := NewServer(Config{
+		Address:     "127.0.0.1",
+		Port:        port,
+		FiberConfig: fiber.Config{DisableStartupMessage: true},
+	})
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	server.WebSocket("/ws", func(conn *WebSocketConn) {
+		for {
+			messageType, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			reply := fmt.Sprintf("%s trace=%s", message, logger.GetTraceID(conn.Context()))
+			if err := conn.WriteMessage(messageType, []byte(reply)); err != nil {
+				return
+			}
+		}
+	}, WebSocketConfig{PingInterval: 20 * time.Millisecond})
+	if err := server.StartAsync(); err != nil {
+		t.Fatalf("StartAsync failed: %v", err)
+	}
+	defer server.Stop()
+
+	header := map[string][]string{TraceIDHeader: {"ws-trace-1"}}
+	client, _, err := fastws.DefaultDialer.Dial("ws://"+server.GetAddress()+"/ws", header)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer client.Close()
+
+	pings := make(chan struct{}, 1)
+	client.SetPingHandler(func(data string) error {
+		select {
+		case pings <- struct{}{}:
+		default:
+		}
+		return client.WriteControl(fastws.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+
+	if err := client.WriteMessage(fastws.TextMessage, []byte("hello")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	_, reply, err := client.ReadMessage()
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if string(reply) != "hello trace=ws-trace-1" {
+		t.Fatalf("unexpected reply: %s", reply)
+	}
+
+	// 读取期间处理 ping，直到服务器关闭时收到 close 帧
+	readErr := make(chan error, 1)
+	go func() {
+		for {
+			if _, _, err := client.ReadMessage(); err != nil {
+				readErr <- err
+				return
+			}
+		}
+	}()
+	select {
+	case <-pings:
+	case <-time.After(time.Second):
+		t.Fatal("expected server keepalive ping")
+	}
+
+	if err := server.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	select {
+	case err := <-readErr:
+		if !fastws.IsCloseError(err, fastws.CloseGoingAway) {
+			t.Fatalf("expected going away close, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected websocket to be closed on server stop")
+	}
+}
+
+func TestServerWebSocketRequiresUpgrade(t *testing.T) {
+	server, err := NewServer(Config{FiberConfig: fiber.Config{DisableStartupMessage: true}})
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	server.WebSocket("/ws", func(conn *WebSocketConn) {})
+
+	resp, err := server.GetApp().Test(httptest.NewRequest("GET", "/ws", nil))
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusUpgradeRequired {
+		t.Fatalf("expected 426 without upgrade headers, got %d", resp.StatusCode)
+	}
+}
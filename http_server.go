@@ -2,10 +2,17 @@ package quickgo
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/team-dandelion/quickgo/grpcep"
 	"github.com/team-dandelion/quickgo/http"
+	"github.com/team-dandelion/quickgo/loadshed"
 	"github.com/team-dandelion/quickgo/logger"
 	"github.com/team-dandelion/quickgo/metrics"
 
@@ -15,6 +22,9 @@ import (
 
 type AppRouteHandler func(app *fiber.App)
 
+// DefaultHTTPServerName 默认 HTTP 服务器名称（ConfigOptionWithHTTPServer 配置的服务器）
+const DefaultHTTPServerName = "default"
+
 // HTTPServerConfig HTTP 服务器配置
 type HTTPServerConfig struct {
 	// 是否启用
@@ -22,15 +32,15 @@ type HTTPServerConfig struct {
 	// 监听地址
 	Address string `json:"address" yaml:"address"`
 	// 监听端口
-	Port int `json:"port" yaml:"port"`
+	Port int `json:"port" yaml:"port" validate:"gte=0,lte=65535"`
 	// 是否启用 CORS
-	EnableCORS bool `json:"enableCORS" yaml:"enableCORS"`
+	EnableCORS bool `json:"enableCORS" yaml:"enableCORS" validate:"excluded_with=DisableCORS"`
 	// 是否启用恢复中间件
-	EnableRecovery bool `json:"enableRecovery" yaml:"enableRecovery"`
+	EnableRecovery bool `json:"enableRecovery" yaml:"enableRecovery" validate:"excluded_with=DisableRecovery"`
 	// 是否启用日志中间件
-	EnableLogging bool `json:"enableLogging" yaml:"enableLogging"`
+	EnableLogging bool `json:"enableLogging" yaml:"enableLogging" validate:"excluded_with=DisableLogging"`
 	// 是否启用链路追踪中间件
-	EnableTrace bool `json:"enableTrace" yaml:"enableTrace"`
+	EnableTrace bool `json:"enableTrace" yaml:"enableTrace" validate:"excluded_with=DisableTrace"`
 	// 显式禁用 CORS
 	DisableCORS bool `json:"disableCORS" yaml:"disableCORS"`
 	// 显式禁用恢复中间件
@@ -47,8 +57,37 @@ type HTTPServerConfig struct {
 	MetricsPath string `json:"metricsPath" yaml:"metricsPath"`
 	// DisableMetricsEndpoint 显式禁用 /metrics 路由
 	DisableMetricsEndpoint bool `json:"disableMetricsEndpoint" yaml:"disableMetricsEndpoint"`
+	// 是否挂载 pprof 与运行时统计接口（DebugPath/pprof/、DebugPath/runtime），建议只在内部运维端口开启
+	EnablePprof bool `json:"enablePprof" yaml:"enablePprof"`
+	// DebugPath pprof 与运行时统计接口前缀，默认 /debug
+	DebugPath string `json:"debugPath" yaml:"debugPath"`
+	// PayloadLoggingPath 运行时载荷日志管理接口路径（如 /admin/debug/log-level），为空不注册
+	// 接口不做鉴权，建议只在内部运维 HTTP Server（HTTPServers）上配置
+	PayloadLoggingPath string `json:"payloadLoggingPath" yaml:"payloadLoggingPath"`
+	// Admin 运维管理接口（日志级别、配置导出、pprof、构建信息、gRPC 连接与注册状态），由框架挂载
+	Admin *AdminConfig `json:"admin" yaml:"admin"`
+	// Routes 声明式网关路由（需配置 gRPC Client，启动时转换为 grpcep 处理器）
+	Routes []grpcep.RouteConfig `json:"routes" yaml:"routes"`
+	// StreamLimit 流式响应（chunked / SSE）背压限制（可选，配置后对所有路由生效）
+	StreamLimit *StreamLimitConfig `json:"streamLimit" yaml:"streamLimit"`
+	// AccessLog 访问日志（可选，与应用日志分开输出）
+	AccessLog *AccessLogConfig `json:"accessLog" yaml:"accessLog"`
+	// Limits 请求体大小、超时、并发等加固配置（可选，未设置的项保持 Fiber 默认值）
+	Limits *HTTPLimitsConfig `json:"limits" yaml:"limits"`
+	// TLS 证书配置（可选，配置后以 HTTPS 提供服务）
+	TLS *HTTPTLSConfig `json:"tls" yaml:"tls"`
+	// LoadShed 自适应过载保护（可选），CPU、GC 停顿、goroutine 数或 p99 延迟超过阈值时按比例拒绝请求并返回 503
+	LoadShed *loadshed.Config `json:"loadShed" yaml:"loadShed"`
+	// Compression 响应压缩（可选，gzip / br），流式响应（Stream / SSE）不压缩
+	Compression *HTTPCompressionConfig `json:"compression" yaml:"compression"`
+	// Metadata 网关透传到后端 gRPC 服务的 metadata 策略（可选，未配置时透传全部 UserValues）
+	Metadata *grpcep.MetadataConfig `json:"metadata" yaml:"metadata"`
+	// Middlewares 自定义中间件（在默认中间件之后注册，仅作用于当前服务器）
+	Middlewares []fiber.Handler `json:"-" yaml:"-"`
 
 	metrics *metrics.Metrics
+	// env 当前环境（由 Framework 传入，为空时使用 GetEnv()）
+	env string
 }
 
 // CORSConfig CORS 配置
@@ -59,13 +98,147 @@ type CORSConfig struct {
 	AllowCredentials bool   `json:"allowCredentials" yaml:"allowCredentials"` // 是否允许凭证
 	ExposeHeaders    string `json:"exposeHeaders" yaml:"exposeHeaders"`       // 暴露的响应头
 	MaxAge           int    `json:"maxAge" yaml:"maxAge"`                     // 预检请求缓存时间（秒）
+	// 允许的源正则（如 ^https://pr-[0-9]+\.preview\.example\.com$）
+	AllowOriginPatterns []string `json:"allowOriginPatterns" yaml:"allowOriginPatterns"`
+	// 按环境（local、develop、release、production）的预设，当前环境存在预设时整体替换上述配置
+	Environments map[string]CORSConfig `json:"environments" yaml:"environments"`
+}
+
+// resolve 返回当前环境生效的 CORS 配置
+func (c CORSConfig) resolve(env string) CORSConfig {
+	if preset, ok := c.Environments[env]; ok {
+		return preset
+	}
+	return c
+}
+
+// StreamLimitConfig 流式响应背压限制配置，慢客户端超过任一阈值即断开
+type StreamLimitConfig struct {
+	MaxBufferedBytes int    `json:"maxBufferedBytes" yaml:"maxBufferedBytes"` // 单连接最大待发送字节数，默认 1MB
+	MaxLag           string `json:"maxLag" yaml:"maxLag"`                     // 待发送数据最长滞留时间，默认 30s
+}
+
+// AccessLogConfig 访问日志配置
+type AccessLogConfig struct {
+	Enabled          bool               `json:"enabled" yaml:"enabled"`                   // 是否启用
+	Format           string             `json:"format" yaml:"format"`                     // json（默认）或 combined
+	Path             string             `json:"path" yaml:"path"`                         // 输出文件路径，为空时输出到 stdout
+	MaxSizeMB        int                `json:"maxSizeMB" yaml:"maxSizeMB"`               // 单文件最大大小（MB），超过后轮转，0 不轮转
+	MaxBackups       int                `json:"maxBackups" yaml:"maxBackups"`             // 保留的历史文件数，默认 5
+	Fields           []string           `json:"fields" yaml:"fields"`                     // 输出字段，默认全部
+	SampleRate       float64            `json:"sampleRate" yaml:"sampleRate"`             // 采样率，0 表示全部记录
+	RouteSampleRates map[string]float64 `json:"routeSampleRates" yaml:"routeSampleRates"` // 按路由模式的采样率
+}
+
+// HTTPTLSConfig HTTPS 证书配置
+type HTTPTLSConfig struct {
+	CertFile     string `json:"certFile" yaml:"certFile"`         // 证书文件路径（PEM）
+	KeyFile      string `json:"keyFile" yaml:"keyFile"`           // 私钥文件路径（PEM）
+	ClientCAFile string `json:"clientCAFile" yaml:"clientCAFile"` // 客户端 CA 证书（可选，配置后要求并校验客户端证书）
+}
+
+// load 加载证书，构建 tls.Config
+func (c *HTTPTLSConfig) load() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load http tls certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if c.ClientCAFile != "" {
+		pem, err := os.ReadFile(c.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read http tls client ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid certificates in http tls client ca %s", c.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// HTTPCompressionConfig 响应压缩配置
+type HTTPCompressionConfig struct {
+	Encodings    []string `json:"encodings" yaml:"encodings"`       // 启用的编码，按优先级排列，默认 [br, gzip]
+	Level        string   `json:"level" yaml:"level"`               // 压缩级别：default（默认）、bestSpeed、bestCompression
+	MinSize      int      `json:"minSize" yaml:"minSize"`           // 最小压缩字节数，默认 1024
+	ContentTypes []string `json:"contentTypes" yaml:"contentTypes"` // 压缩的 Content-Type 前缀，默认 text/、JSON、JavaScript、XML、SVG
+	ExcludePaths []string `json:"excludePaths" yaml:"excludePaths"` // 不压缩的路径前缀
+}
+
+// toHTTPCompress 转换为 http 包的压缩配置
+func (c *HTTPCompressionConfig) toHTTPCompress() http.CompressConfig {
+	return http.CompressConfig{
+		Encodings:    c.Encodings,
+		Level:        c.Level,
+		MinSize:      c.MinSize,
+		ContentTypes: c.ContentTypes,
+		ExcludePaths: c.ExcludePaths,
+	}
+}
+
+// HTTPLimitsConfig HTTP 服务器加固配置，时长使用 Go duration 格式（如 "10s"）
+type HTTPLimitsConfig struct {
+	BodyLimit      int               `json:"bodyLimit" yaml:"bodyLimit"`                               // 请求体最大字节数，默认 4MB，超过返回 413
+	ReadTimeout    string            `json:"readTimeout" yaml:"readTimeout" validate:"duration"`       // 读取完整请求超时（防止 slowloris），默认不限制
+	WriteTimeout   string            `json:"writeTimeout" yaml:"writeTimeout" validate:"duration"`     // 写响应超时，默认不限制
+	IdleTimeout    string            `json:"idleTimeout" yaml:"idleTimeout" validate:"duration"`       // keep-alive 空闲超时，默认与 readTimeout 相同
+	ReadBufferSize int               `json:"readBufferSize" yaml:"readBufferSize"`                     // 单连接读缓冲区大小（同时限制请求头大小），默认 4096
+	Concurrency    int               `json:"concurrency" yaml:"concurrency"`                           // 最大并发连接数，默认 256 * 1024
+	MaxInFlight    int               `json:"maxInFlight" yaml:"maxInFlight"`                           // 全局最大处理中请求数，超过返回 503，0 不限制
+	RequestTimeout string            `json:"requestTimeout" yaml:"requestTimeout" validate:"duration"` // 请求处理超时，超时返回 408，默认不限制
+	RouteTimeouts  map[string]string `json:"routeTimeouts" yaml:"routeTimeouts"`                       // 按路径前缀的处理超时（最长前缀优先）
+}
+
+// toHTTPLimits 解析为 http 包的加固配置
+func (c *HTTPLimitsConfig) toHTTPLimits() (http.LimitsConfig, error) {
+	limits := http.LimitsConfig{
+		BodyLimit:      c.BodyLimit,
+		ReadBufferSize: c.ReadBufferSize,
+		Concurrency:    c.Concurrency,
+		MaxInFlight:    c.MaxInFlight,
+	}
+	durations := []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"read timeout", c.ReadTimeout, &limits.ReadTimeout},
+		{"write timeout", c.WriteTimeout, &limits.WriteTimeout},
+		{"idle timeout", c.IdleTimeout, &limits.IdleTimeout},
+		{"request timeout", c.RequestTimeout, &limits.RequestTimeout},
+	}
+	for _, d := range durations {
+		parsed, err := parseDurationOrDefault(d.value, 0)
+		if err != nil {
+			return limits, fmt.Errorf("invalid http %s %q: %w", d.name, d.value, err)
+		}
+		*d.dst = parsed
+	}
+	if len(c.RouteTimeouts) > 0 {
+		limits.RouteTimeouts = make(map[string]time.Duration, len(c.RouteTimeouts))
+		for prefix, value := range c.RouteTimeouts {
+			parsed, err := parseDurationOrDefault(value, 0)
+			if err != nil {
+				return limits, fmt.Errorf("invalid http route timeout %q for %s: %w", value, prefix, err)
+			}
+			limits.RouteTimeouts[prefix] = parsed
+		}
+	}
+	return limits, nil
 }
 
 // HTTPServer HTTP 服务器封装
 type HTTPServer struct {
-	server  *http.Server
-	config  *HTTPServerConfig
-	metrics *metrics.Metrics
+	server    *http.Server
+	config    *HTTPServerConfig
+	metrics   *metrics.Metrics
+	accessLog *http.AccessLogger
 }
 
 // NewHTTPServer 创建 HTTP 服务器实例
@@ -96,35 +269,111 @@ func NewHTTPServer(config *HTTPServerConfig) (*HTTPServer, error) {
 		DisableLogging:  config.DisableLogging,
 		DisableTrace:    config.DisableTrace,
 	}
+	if config.Limits != nil {
+		limits, err := config.Limits.toHTTPLimits()
+		if err != nil {
+			return nil, err
+		}
+		httpConfig.Limits = limits
+	}
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.load()
+		if err != nil {
+			return nil, err
+		}
+		httpConfig.TLSConfig = tlsConfig
+	}
 	metricCollector := config.metrics
 	if metricCollector == nil && config.Metrics != nil {
 		metricCollector = metrics.New(*config.Metrics)
 	}
+	namespace := metrics.DefaultConfig().Namespace
+	if config.Metrics != nil && config.Metrics.Namespace != "" {
+		namespace = config.Metrics.Namespace
+	}
 	if metricCollector != nil {
 		httpConfig.Middlewares = append(httpConfig.Middlewares, metrics.FiberMiddleware(metricCollector))
+		if err := http.RegisterStreamMetrics(metricCollector.Registry(), namespace); err != nil {
+			return nil, err
+		}
+	}
+	if config.StreamLimit != nil {
+		maxLag, err := parseDurationOrDefault(config.StreamLimit.MaxLag, 0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid http stream max lag %q: %w", config.StreamLimit.MaxLag, err)
+		}
+		httpConfig.Middlewares = append(httpConfig.Middlewares, http.StreamLimitMiddleware(http.StreamLimitConfig{
+			MaxBufferedBytes: config.StreamLimit.MaxBufferedBytes,
+			MaxLag:           maxLag,
+		}))
 	}
 
-	// 设置 CORS 配置
-	if config.CORS.AllowOrigins != "" {
-		httpConfig.CORSConfig.AllowOrigins = config.CORS.AllowOrigins
+	var accessLogger *http.AccessLogger
+	if config.AccessLog != nil && config.AccessLog.Enabled {
+		var err error
+		accessLogger, err = http.NewAccessLogger(http.AccessLogConfig{
+			Format:           http.AccessLogFormat(config.AccessLog.Format),
+			Path:             config.AccessLog.Path,
+			MaxSize:          int64(config.AccessLog.MaxSizeMB) << 20,
+			MaxBackups:       config.AccessLog.MaxBackups,
+			Fields:           config.AccessLog.Fields,
+			SampleRate:       config.AccessLog.SampleRate,
+			RouteSampleRates: config.AccessLog.RouteSampleRates,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create access logger: %w", err)
+		}
+		httpConfig.Middlewares = append(httpConfig.Middlewares, accessLogger.Handler())
 	}
-	if config.CORS.AllowMethods != "" {
-		httpConfig.CORSConfig.AllowMethods = config.CORS.AllowMethods
+	// 过载保护位于访问日志之后，被拒绝的请求仍记录访问日志与指标
+	if config.LoadShed != nil {
+		shedder, err := loadshed.New("http", *config.LoadShed)
+		if err != nil {
+			_ = accessLogger.Close()
+			return nil, err
+		}
+		if metricCollector != nil {
+			if err := shedder.RegisterMetrics(metricCollector.Registry(), namespace); err != nil {
+				_ = accessLogger.Close()
+				return nil, err
+			}
+		}
+		httpConfig.Middlewares = append(httpConfig.Middlewares, loadshed.FiberMiddleware(shedder))
 	}
-	if config.CORS.AllowHeaders != "" {
-		httpConfig.CORSConfig.AllowHeaders = config.CORS.AllowHeaders
+	if config.Metadata != nil {
+		httpConfig.Middlewares = append(httpConfig.Middlewares, grpcep.MetadataPolicy(*config.Metadata))
 	}
-	httpConfig.CORSConfig.AllowCredentials = config.CORS.AllowCredentials
-	if config.CORS.ExposeHeaders != "" {
-		httpConfig.CORSConfig.ExposeHeaders = config.CORS.ExposeHeaders
+	// 压缩位于自定义中间件外层，响应缓存与 ETag 处理的是未压缩的响应体
+	if config.Compression != nil {
+		compress := config.Compression.toHTTPCompress()
+		if err := compress.Validate(); err != nil {
+			_ = accessLogger.Close()
+			return nil, err
+		}
+		httpConfig.Middlewares = append(httpConfig.Middlewares, http.CompressMiddleware(compress))
 	}
-	if config.CORS.MaxAge > 0 {
-		httpConfig.CORSConfig.MaxAge = config.CORS.MaxAge
+	httpConfig.Middlewares = append(httpConfig.Middlewares, config.Middlewares...)
+
+	// 设置 CORS 配置
+	env := config.env
+	if env == "" {
+		env = GetEnv()
+	}
+	corsConfig := config.CORS.resolve(env)
+	httpConfig.CORSConfig = http.CORSConfig{
+		AllowOrigins:        corsConfig.AllowOrigins,
+		AllowMethods:        corsConfig.AllowMethods,
+		AllowHeaders:        corsConfig.AllowHeaders,
+		AllowCredentials:    corsConfig.AllowCredentials,
+		ExposeHeaders:       corsConfig.ExposeHeaders,
+		MaxAge:              corsConfig.MaxAge,
+		AllowOriginPatterns: corsConfig.AllowOriginPatterns,
 	}
 
 	// 创建 HTTP 服务器
 	server, err := http.NewServer(httpConfig)
 	if err != nil {
+		_ = accessLogger.Close()
 		return nil, fmt.Errorf("failed to create http server: %w", err)
 	}
 
@@ -135,11 +384,23 @@ func NewHTTPServer(config *HTTPServerConfig) (*HTTPServer, error) {
 		}
 		server.GetApp().Get(metricsPath, adaptor.HTTPHandler(metricCollector.Handler()))
 	}
+	if config.EnablePprof {
+		debugPath := strings.TrimRight(config.DebugPath, "/")
+		if debugPath == "" {
+			debugPath = "/debug"
+		}
+		http.RegisterPprof(server.GetApp().Group(debugPath + "/pprof"))
+		server.GetApp().Get(debugPath+"/runtime", http.RuntimeStatsHandler())
+	}
+	if config.PayloadLoggingPath != "" {
+		server.GetApp().All(config.PayloadLoggingPath, http.PayloadLoggingHandler())
+	}
 
 	return &HTTPServer{
-		server:  server,
-		config:  config,
-		metrics: metricCollector,
+		server:    server,
+		config:    config,
+		metrics:   metricCollector,
+		accessLog: accessLogger,
 	}, nil
 }
 
@@ -148,6 +409,7 @@ func cloneHTTPServerConfig(config *HTTPServerConfig) *HTTPServerConfig {
 		return nil
 	}
 	cloned := *config
+	cloned.CORS = cloneCORSConfig(config.CORS)
 	if config.Metrics != nil {
 		metricsConfig := *config.Metrics
 		if config.Metrics.Buckets != nil {
@@ -155,9 +417,75 @@ func cloneHTTPServerConfig(config *HTTPServerConfig) *HTTPServerConfig {
 		}
 		cloned.Metrics = &metricsConfig
 	}
+	if config.Routes != nil {
+		cloned.Routes = append([]grpcep.RouteConfig(nil), config.Routes...)
+	}
+	if config.StreamLimit != nil {
+		streamLimit := *config.StreamLimit
+		cloned.StreamLimit = &streamLimit
+	}
+	if config.AccessLog != nil {
+		accessLog := *config.AccessLog
+		accessLog.Fields = append([]string(nil), config.AccessLog.Fields...)
+		if config.AccessLog.RouteSampleRates != nil {
+			accessLog.RouteSampleRates = make(map[string]float64, len(config.AccessLog.RouteSampleRates))
+			for route, rate := range config.AccessLog.RouteSampleRates {
+				accessLog.RouteSampleRates[route] = rate
+			}
+		}
+		cloned.AccessLog = &accessLog
+	}
+	if config.Limits != nil {
+		limits := *config.Limits
+		if config.Limits.RouteTimeouts != nil {
+			limits.RouteTimeouts = make(map[string]string, len(config.Limits.RouteTimeouts))
+			for prefix, timeout := range config.Limits.RouteTimeouts {
+				limits.RouteTimeouts[prefix] = timeout
+			}
+		}
+		cloned.Limits = &limits
+	}
+	if config.LoadShed != nil {
+		loadShed := *config.LoadShed
+		loadShed.Exclude = append([]string(nil), config.LoadShed.Exclude...)
+		cloned.LoadShed = &loadShed
+	}
+	if config.Compression != nil {
+		compression := *config.Compression
+		compression.Encodings = append([]string(nil), config.Compression.Encodings...)
+		compression.ContentTypes = append([]string(nil), config.Compression.ContentTypes...)
+		compression.ExcludePaths = append([]string(nil), config.Compression.ExcludePaths...)
+		cloned.Compression = &compression
+	}
+	if config.TLS != nil {
+		tlsConfig := *config.TLS
+		cloned.TLS = &tlsConfig
+	}
+	if config.Metadata != nil {
+		metadataConfig := *config.Metadata
+		metadataConfig.Allow = append([]string(nil), config.Metadata.Allow...)
+		metadataConfig.Deny = append([]string(nil), config.Metadata.Deny...)
+		metadataConfig.Headers = append([]grpcep.MetadataHeader(nil), config.Metadata.Headers...)
+		cloned.Metadata = &metadataConfig
+	}
+	if config.Middlewares != nil {
+		cloned.Middlewares = append([]fiber.Handler(nil), config.Middlewares...)
+	}
 	return &cloned
 }
 
+func cloneCORSConfig(config CORSConfig) CORSConfig {
+	cloned := config
+	cloned.AllowOriginPatterns = append([]string(nil), config.AllowOriginPatterns...)
+	if config.Environments != nil {
+		cloned.Environments = make(map[string]CORSConfig, len(config.Environments))
+		for env, preset := range config.Environments {
+			cloned.Environments[env] = cloneCORSConfig(preset)
+		}
+	}
+	return cloned
+}
+
 // Start 启动 HTTP 服务器
 func (s *HTTPServer) Start() error {
 	if s.server == nil {
@@ -186,7 +514,7 @@ func (s *HTTPServer) Stop() error {
 
 	ctx := context.Background()
 	logger.Info(ctx, "HTTP server shutting down...")
-	return s.server.Stop()
+	return errors.Join(s.server.Stop(), s.accessLog.Close())
 }
 
 // GetApp 获取 Fiber 应用实例（用于注册路由等）
@@ -204,6 +532,11 @@ func (s *HTTPServer) Metrics() *metrics.Metrics {
 	return s.metrics
 }
 
+// Routes 返回服务器注册的路由（方法、路径、名称与处理器函数名）
+func (s *HTTPServer) Routes() []http.RouteInfo {
+	return s.server.Routes()
+}
+
 func (s *HTTPServer) RegisterApp(handler AppRouteHandler) error {
 	if s.server == nil {
 		return errors.New("server is nil")
@@ -214,6 +547,15 @@ func (s *HTTPServer) RegisterApp(handler AppRouteHandler) error {
 	return nil
 }
 
+// WebSocket 注册 WebSocket 路由（便捷方法，见 http.Server.WebSocket）
+func (s *HTTPServer) WebSocket(path string, handler http.WebSocketHandler, config ...http.WebSocketConfig) error {
+	if s.server == nil {
+		return errors.New("server is nil")
+	}
+	s.server.WebSocket(path, handler, config...)
+	return nil
+}
+
 // RegisterRoute 注册路由（便捷方法）
 // method: HTTP 方法（GET, POST, PUT, DELETE 等）
 // path: 路由路径
@@ -1,12 +1,17 @@
 package quickgo
 
 import (
+	"context"
 	"io"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/team-dandelion/quickgo/grpcep"
 	"github.com/team-dandelion/quickgo/metrics"
 )
 
@@ -76,4 +81,259 @@ func TestNewHTTPServerExposesMetricsEndpoint(t *testing.T) {
 	if !strings.Contains(string(body), "httpserver_http_requests_total") {
 		t.Fatalf("expected metrics endpoint to expose shared collector, got %s", string(body))
 	}
+	if !strings.Contains(string(body), "httpserver_http_stream_disconnects_total") {
+		t.Fatalf("expected metrics endpoint to expose stream metrics, got %s", string(body))
+	}
+}
+
+func TestNewHTTPServerMountsPprofWhenEnabled(t *testing.T) {
+	disabled, err := NewHTTPServer(&HTTPServerConfig{})
+	if err != nil {
+		t.Fatalf("NewHTTPServer failed: %v", err)
+	}
+	resp, err := disabled.GetApp().Test(httptest.NewRequest("GET", "/debug/pprof/", nil))
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusNotFound {
+		t.Fatalf("expected pprof to be disabled by default, got %d", resp.StatusCode)
+	}
+
+	server, err := NewHTTPServer(&HTTPServerConfig{EnablePprof: true, DebugPath: "/ops/"})
+	if err != nil {
+		t.Fatalf("NewHTTPServer failed: %v", err)
+	}
+	for path, want := range map[string]string{
+		"/ops/pprof/heap?debug=1": "heap profile",
+		"/ops/runtime":            `"recentPauses"`,
+	} {
+		resp, err := server.GetApp().Test(httptest.NewRequest("GET", path, nil), -1)
+		if err != nil {
+			t.Fatalf("app.Test(%s) failed: %v", path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != fiber.StatusOK || !strings.Contains(string(body), want) {
+			t.Fatalf("unexpected response for %s: %d %.200s", path, resp.StatusCode, body)
+		}
+	}
+}
+
+func TestNewHTTPServerRejectsInvalidStreamMaxLag(t *testing.T) {
+	_, err := NewHTTPServer(&HTTPServerConfig{
+		StreamLimit: &StreamLimitConfig{MaxLag: "soon"},
+	})
+	if err == nil {
+		t.Fatal("expected invalid stream max lag to be rejected")
+	}
+}
+
+func TestNewHTTPServerAppliesLimits(t *testing.T) {
+	server, err := NewHTTPServer(&HTTPServerConfig{
+		Limits: &HTTPLimitsConfig{
+			BodyLimit:     1024,
+			ReadTimeout:   "5s",
+			IdleTimeout:   "30s",
+			RouteTimeouts: map[string]string{"/export": "1m"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPServer failed: %v", err)
+	}
+	fasthttpServer := server.GetApp().Server()
+	if fasthttpServer.MaxRequestBodySize != 1024 || fasthttpServer.ReadTimeout != 5*time.Second || fasthttpServer.IdleTimeout != 30*time.Second {
+		t.Fatalf("unexpected limits: body=%d read=%s idle=%s", fasthttpServer.MaxRequestBodySize, fasthttpServer.ReadTimeout, fasthttpServer.IdleTimeout)
+	}
+
+	_, err = NewHTTPServer(&HTTPServerConfig{
+		Limits: &HTTPLimitsConfig{RouteTimeouts: map[string]string{"/export": "later"}},
+	})
+	if err == nil {
+		t.Fatal("expected invalid route timeout to be rejected")
+	}
+}
+
+func TestNewHTTPServerCompressesResponses(t *testing.T) {
+	server, err := NewHTTPServer(&HTTPServerConfig{
+		Compression: &HTTPCompressionConfig{MinSize: 10, Encodings: []string{"gzip"}},
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPServer failed: %v", err)
+	}
+	server.GetApp().Get("/items", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"items": strings.Repeat("x", 100)})
+	})
+	req := httptest.NewRequest("GET", "/items", nil)
+	req.Header.Set("Accept-Encoding", "gzip, br")
+	resp, err := server.GetApp().Test(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if encoding := resp.Header.Get("Content-Encoding"); encoding != "gzip" {
+		t.Fatalf("expected gzip response, got %q", encoding)
+	}
+
+	if _, err := NewHTTPServer(&HTTPServerConfig{Compression: &HTTPCompressionConfig{Level: "max"}}); err == nil {
+		t.Fatal("expected invalid compression level to be rejected")
+	}
+}
+
+func TestNewHTTPServerRejectsMissingTLSCertificate(t *testing.T) {
+	dir := t.TempDir()
+	_, err := NewHTTPServer(&HTTPServerConfig{
+		TLS: &HTTPTLSConfig{
+			CertFile: filepath.Join(dir, "server.crt"),
+			KeyFile:  filepath.Join(dir, "server.key"),
+		},
+	})
+	if err == nil {
+		t.Fatal("expected missing tls certificate to be rejected")
+	}
+}
+
+func TestNewHTTPServerUsesEnvironmentCORSPreset(t *testing.T) {
+	config := &HTTPServerConfig{
+		CORS: CORSConfig{
+			AllowOrigins: "*",
+			Environments: map[string]CORSConfig{
+				"production": {AllowOrigins: "https://app.example.com", AllowCredentials: true},
+			},
+		},
+		env: "production",
+	}
+	server, err := NewHTTPServer(config)
+	if err != nil {
+		t.Fatalf("NewHTTPServer failed: %v", err)
+	}
+	server.GetApp().Get("/ok", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	req := httptest.NewRequest("GET", "/ok", nil)
+	req.Header.Set(fiber.HeaderOrigin, "https://other.example.com")
+	resp, err := server.GetApp().Test(req)
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if got := resp.Header.Get(fiber.HeaderAccessControlAllowOrigin); got != "" {
+		t.Fatalf("expected production preset to reject other origins, got %q", got)
+	}
+
+	config.env = "local"
+	config.CORS.AllowCredentials = true
+	if _, err := NewHTTPServer(config); err == nil {
+		t.Fatal("expected wildcard origins with credentials to be rejected")
+	}
+}
+
+func TestFrameworkDeclarativeRoutesRequireGrpcClient(t *testing.T) {
+	f, err := NewFramework(
+		ConfigOptionWithLogger(LoggerConfig{Enabled: false}),
+		ConfigOptionWithHTTPServer(&HTTPServerConfig{
+			Enabled: true,
+			Routes: []grpcep.RouteConfig{
+				{Path: "/health", Service: "user-service", GRPCMethod: "/grpc.health.v1.Health/Check"},
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewFramework failed: %v", err)
+	}
+	if err := f.Init(); err == nil || !strings.Contains(err.Error(), "grpc client") {
+		t.Fatalf("expected Init to require grpc client, got %v", err)
+	}
+	_ = f.Stop()
+}
+
+// chanRouteSource 测试用路由来源，从 channel 读取路由变化
+type chanRouteSource chan []grpcep.RouteConfig
+
+func (s chanRouteSource) Watch(ctx context.Context, onChange func(routes []grpcep.RouteConfig)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case routes := <-s:
+			onChange(routes)
+		}
+	}
+}
+
+func TestFrameworkReloadsDeclarativeRoutesFromSource(t *testing.T) {
+	source := make(chanRouteSource)
+	health := grpcep.RouteConfig{Path: "/health", Method: "GET", Service: "user-service", GRPCMethod: "/grpc.health.v1.Health/Check", Timeout: "20ms"}
+	f, err := NewFramework(
+		ConfigOptionWithLogger(LoggerConfig{Enabled: false}),
+		ConfigOptionWithGrpcClient(&GrpcClientConfig{
+			Discovery:       "static",
+			StaticAddresses: map[string]string{"user-service": "127.0.0.1:1", "order-service": "127.0.0.1:2"},
+			Timeout:         Duration(20 * time.Millisecond),
+			Insecure:        true,
+		}),
+		ConfigOptionWithHTTPServer(&HTTPServerConfig{Enabled: true, Routes: []grpcep.RouteConfig{health}}),
+		ConfigOptionWithRouteSource(source),
+	)
+	if err != nil {
+		t.Fatalf("NewFramework failed: %v", err)
+	}
+	if err := f.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer f.Stop()
+	f.startRouteWatch(context.Background())
+
+	app := f.HTTPServer().GetApp()
+	status := func(target string) int {
+		resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+		if err != nil {
+			t.Fatalf("request %s failed: %v", target, err)
+		}
+		return resp.StatusCode
+	}
+	if status("/health") == fiber.StatusNotFound {
+		t.Fatal("expected configured route to be registered")
+	}
+
+	// 未知服务的路由表被拒绝，保留当前路由
+	source <- []grpcep.RouteConfig{{Path: "/billing", Method: "GET", Service: "billing-service", GRPCMethod: "/grpc.health.v1.Health/Check"}}
+	orders := grpcep.RouteConfig{Path: "/orders/health", Method: "GET", Service: "order-service", GRPCMethod: "/grpc.health.v1.Health/Check", Timeout: "20ms"}
+	source <- []grpcep.RouteConfig{orders}
+	deadline := time.Now().Add(time.Second)
+	for f.RouteTable().Version() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected route table to be reloaded, version=%d", f.RouteTable().Version())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if status("/health") != fiber.StatusNotFound || status("/billing") != fiber.StatusNotFound {
+		t.Fatal("expected old and rejected routes to be absent")
+	}
+	if status("/orders/health") == fiber.StatusNotFound {
+		t.Fatal("expected reloaded route to be registered")
+	}
+}
+
+func TestNewHTTPServerWritesAccessLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	server, err := NewHTTPServer(&HTTPServerConfig{
+		DisableLogging: true,
+		AccessLog:      &AccessLogConfig{Enabled: true, Path: path, Fields: []string{"route", "status"}},
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPServer failed: %v", err)
+	}
+	server.GetApp().Get("/ping", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusNoContent) })
+	if _, err := server.GetApp().Test(httptest.NewRequest("GET", "/ping", nil)); err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if err := server.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read access log failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != `{"route":"/ping","status":204}` {
+		t.Fatalf("unexpected access log %q", got)
+	}
 }
@@ -0,0 +1,103 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// UnaryServerInterceptor gRPC 一元调用幂等拦截器
+// 幂等键取自 metadata（Config.Header 的小写形式，如 idempotency-key）；成功的响应被保存并在重复调用时重放，
+// 重放时设置响应头 idempotent-replayed: true；调用失败时释放幂等键，允许客户端重试
+func (g *Guard) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	header := strings.ToLower(g.config.Header)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var key string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(header); len(values) > 0 {
+				key = values[0]
+			}
+		}
+		if err := g.validateKey(key); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		msg, ok := req.(proto.Message)
+		if key == "" || !ok {
+			return handler(ctx, req)
+		}
+
+		body, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal request: %v", err)
+		}
+		storeKey := g.storeKey(ctx, info.FullMethod, key)
+		requestFingerprint := fingerprint([]byte(info.FullMethod), body)
+		record, token, err := g.begin(ctx, storeKey, requestFingerprint)
+		switch {
+		case errors.Is(err, ErrInProgress):
+			return nil, status.Error(codes.Aborted, err.Error())
+		case errors.Is(err, ErrKeyReused):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case err != nil:
+			logger.Error(ctx, "Idempotency store unavailable: key=%s, error=%v", storeKey, err)
+			if g.config.FailOpen {
+				return handler(ctx, req)
+			}
+			return nil, status.Error(codes.Unavailable, "idempotency store unavailable")
+		case record != nil:
+			resp, err := replayMessage(record)
+			if err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+			_ = grpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(ReplayedHeader), "true"))
+			return resp, nil
+		}
+
+		storeCtx := context.WithoutCancel(ctx)
+		resp, handlerErr := handler(ctx, req)
+		respMsg, ok := resp.(proto.Message)
+		if handlerErr != nil || !ok {
+			if err := g.store.Release(storeCtx, storeKey, token); err != nil {
+				logger.Error(storeCtx, "Failed to release idempotency key: key=%s, error=%v", storeKey, err)
+			}
+			return resp, handlerErr
+		}
+
+		data, err := proto.Marshal(respMsg)
+		if err == nil {
+			err = g.complete(storeCtx, storeKey, token, &Record{
+				Fingerprint: requestFingerprint,
+				Body:        data,
+				MessageType: string(proto.MessageName(respMsg)),
+			})
+		}
+		if err != nil {
+			logger.Error(storeCtx, "Failed to store idempotent response: key=%s, error=%v", storeKey, err)
+		}
+		return resp, nil
+	}
+}
+
+// replayMessage 根据保存的记录还原响应消息
+func replayMessage(record *Record) (proto.Message, error) {
+	messageType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(record.MessageType))
+	if err != nil {
+		return nil, fmt.Errorf("unknown idempotent response type %s: %w", record.MessageType, err)
+	}
+	msg := messageType.New().Interface()
+	if err := proto.Unmarshal(record.Body, msg); err != nil {
+		return nil, fmt.Errorf("failed to decode idempotent response: %w", err)
+	}
+	return msg, nil
+}
@@ -0,0 +1,75 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/team-dandelion/quickgo/http"
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// FiberMiddleware HTTP 幂等中间件
+// 对 Config.Methods 中的请求按幂等键去重：首次请求的响应（状态码 < 500）被保存并在重复请求时重放，
+// 重放的响应带有 Idempotent-Replayed: true；5xx 响应或处理错误会释放幂等键，允许客户端重试
+func (g *Guard) FiberMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !g.methods[c.Method()] {
+			return c.Next()
+		}
+		key := c.Get(g.config.Header)
+		if err := g.validateKey(key); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		if key == "" {
+			return c.Next()
+		}
+
+		ctx := http.RequestContext(c)
+		operation := c.Method() + " " + c.Path()
+		storeKey := g.storeKey(ctx, operation, key)
+		requestFingerprint := fingerprint([]byte(operation), c.Body())
+		record, token, err := g.begin(ctx, storeKey, requestFingerprint)
+		switch {
+		case errors.Is(err, ErrInProgress):
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		case errors.Is(err, ErrKeyReused):
+			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+		case err != nil:
+			logger.Error(ctx, "Idempotency store unavailable: key=%s, error=%v", storeKey, err)
+			if g.config.FailOpen {
+				return c.Next()
+			}
+			return fiber.NewError(fiber.StatusServiceUnavailable, "idempotency store unavailable")
+		case record != nil:
+			c.Set(ReplayedHeader, "true")
+			if record.ContentType != "" {
+				c.Set(fiber.HeaderContentType, record.ContentType)
+			}
+			return c.Status(record.Status).Send(record.Body)
+		}
+
+		// 请求结束后写入存储，不受请求 context 取消影响
+		storeCtx := context.WithoutCancel(ctx)
+		handlerErr := c.Next()
+		status := c.Response().StatusCode()
+		if handlerErr != nil || status >= fiber.StatusInternalServerError {
+			if err := g.store.Release(storeCtx, storeKey, token); err != nil {
+				logger.Error(storeCtx, "Failed to release idempotency key: key=%s, error=%v", storeKey, err)
+			}
+			return handlerErr
+		}
+
+		record = &Record{
+			Fingerprint: requestFingerprint,
+			Status:      status,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+		}
+		if err := g.complete(storeCtx, storeKey, token, record); err != nil {
+			logger.Error(storeCtx, "Failed to store idempotent response: key=%s, error=%v", storeKey, err)
+		}
+		return nil
+	}
+}
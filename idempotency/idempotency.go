@@ -0,0 +1,215 @@
+// Package idempotency 提供基于 Idempotency-Key 的请求去重
+//
+// 客户端为有副作用的请求（如支付）携带 Idempotency-Key，首次请求的响应会在存储中保留 TTL，
+// 重试的重复请求直接重放已保存的响应而不会再次执行业务逻辑；同一 key 正在处理时重复请求返回冲突，
+// 同一 key 携带不同的请求内容时返回错误。HTTP 使用 FiberMiddleware，gRPC 一元调用使用 UnaryServerInterceptor：
+//
+//	guard, _ := idempotency.New(idempotency.NewRedisStore(redisClient), &idempotency.Config{TTL: "24h"})
+//	app.Post("/payments", guard.FiberMiddleware(), handler)
+package idempotency
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultHeader 默认的幂等键请求头（gRPC metadata 使用小写形式）
+	DefaultHeader = "Idempotency-Key"
+	// ReplayedHeader 重放响应时附加的响应头
+	ReplayedHeader = "Idempotent-Replayed"
+	// DefaultPrefix 默认存储 key 前缀
+	DefaultPrefix = "quickgo:idempotency:"
+
+	defaultTTL     = 24 * time.Hour
+	defaultLockTTL = time.Minute
+	maxKeyLength   = 255
+)
+
+var (
+	// ErrInProgress 相同幂等键的请求正在处理
+	ErrInProgress = errors.New("idempotency: request with the same key is in progress")
+	// ErrKeyReused 相同幂等键携带了不同的请求内容
+	ErrKeyReused = errors.New("idempotency: key reused with a different request")
+	// ErrKeyRequired 请求缺少幂等键（Config.Required 为 true 时）
+	ErrKeyRequired = errors.New("idempotency: key is required")
+	// ErrInvalidKey 幂等键过长
+	ErrInvalidKey = errors.New("idempotency: key is too long")
+)
+
+// Config 幂等配置
+type Config struct {
+	// 幂等键请求头 示例：Idempotency-Key（默认）
+	Header string `json:"header" yaml:"header" toml:"header"`
+	// 存储 key 前缀（默认 quickgo:idempotency:）
+	Prefix string `json:"prefix" yaml:"prefix" toml:"prefix"`
+	// 响应保留时间 示例：24h（默认 24h）
+	TTL string `json:"ttl" yaml:"ttl" toml:"ttl"`
+	// 处理中占用时间 示例：1m（默认 1m），处理超过该时间（如进程崩溃）后允许重试
+	LockTTL string `json:"lockTTL" yaml:"lockTTL" toml:"lockTTL"`
+	// 需要去重的 HTTP 方法（默认 POST）
+	Methods []string `json:"methods" yaml:"methods" toml:"methods"`
+	// 是否要求请求必须携带幂等键
+	Required bool `json:"required" yaml:"required" toml:"required"`
+	// 存储不可用时是否放行请求（默认拒绝，避免重复扣款等风险）
+	FailOpen bool `json:"failOpen" yaml:"failOpen" toml:"failOpen"`
+	// 幂等键作用域（如当前用户 ID），避免不同调用方使用相同的 key 互相干扰（可选）
+	Scope func(ctx context.Context) string `json:"-" yaml:"-" toml:"-"`
+}
+
+// Record 存储的幂等记录
+type Record struct {
+	// 处理中的占用标识（处理完成后为空）
+	Token string `json:"token,omitempty"`
+	// 请求指纹（方法、路径与请求体的摘要）
+	Fingerprint string `json:"fingerprint"`
+	// 是否已处理完成
+	Completed bool `json:"completed"`
+	// HTTP 状态码
+	Status int `json:"status,omitempty"`
+	// HTTP 响应内容类型
+	ContentType string `json:"contentType,omitempty"`
+	// 响应内容（gRPC 为 protobuf 编码）
+	Body []byte `json:"body,omitempty"`
+	// gRPC 响应消息类型（protobuf 全名）
+	MessageType string `json:"messageType,omitempty"`
+	// 创建时间
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Store 幂等记录存储
+type Store interface {
+	// Reserve 占用 key 并写入处理中记录；key 已存在时返回已有记录与 false
+	Reserve(ctx context.Context, key string, record *Record, ttl time.Duration) (*Record, bool, error)
+	// Complete 保存处理结果（仅当 key 仍由 token 占用时）
+	Complete(ctx context.Context, key, token string, record *Record, ttl time.Duration) error
+	// Release 释放占用，允许重试（仅当 key 仍由 token 占用时）
+	Release(ctx context.Context, key, token string) error
+}
+
+// Guard 幂等请求去重器
+type Guard struct {
+	store   Store
+	config  Config
+	ttl     time.Duration
+	lockTTL time.Duration
+	methods map[string]bool
+}
+
+// New 创建幂等请求去重器
+func New(store Store, config *Config) (*Guard, error) {
+	if store == nil {
+		return nil, errors.New("idempotency store is nil")
+	}
+	g := &Guard{store: store, ttl: defaultTTL, lockTTL: defaultLockTTL, methods: make(map[string]bool)}
+	if config != nil {
+		g.config = *config
+	}
+	if g.config.Header == "" {
+		g.config.Header = DefaultHeader
+	}
+	if g.config.Prefix == "" {
+		g.config.Prefix = DefaultPrefix
+	}
+	if g.config.TTL != "" {
+		ttl, err := time.ParseDuration(g.config.TTL)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("invalid idempotency ttl %q", g.config.TTL)
+		}
+		g.ttl = ttl
+	}
+	if g.config.LockTTL != "" {
+		lockTTL, err := time.ParseDuration(g.config.LockTTL)
+		if err != nil || lockTTL <= 0 {
+			return nil, fmt.Errorf("invalid idempotency lockTTL %q", g.config.LockTTL)
+		}
+		g.lockTTL = lockTTL
+	}
+	methods := g.config.Methods
+	if len(methods) == 0 {
+		methods = []string{"POST"}
+	}
+	for _, method := range methods {
+		g.methods[strings.ToUpper(method)] = true
+	}
+	return g, nil
+}
+
+// Header 返回幂等键请求头
+func (g *Guard) Header() string {
+	return g.config.Header
+}
+
+// begin 开始处理幂等请求：首次请求返回占用标识，重复请求返回已保存的记录
+func (g *Guard) begin(ctx context.Context, key, fingerprint string) (*Record, string, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, "", err
+	}
+	record := &Record{Token: token, Fingerprint: fingerprint, CreatedAt: time.Now()}
+	existing, reserved, err := g.store.Reserve(ctx, key, record, g.lockTTL)
+	if err != nil {
+		return nil, "", err
+	}
+	if reserved {
+		return nil, token, nil
+	}
+	if existing.Fingerprint != fingerprint {
+		return nil, "", ErrKeyReused
+	}
+	if !existing.Completed {
+		return nil, "", ErrInProgress
+	}
+	return existing, "", nil
+}
+
+// complete 保存处理结果
+func (g *Guard) complete(ctx context.Context, key, token string, record *Record) error {
+	record.Completed = true
+	record.CreatedAt = time.Now()
+	return g.store.Complete(ctx, key, token, record, g.ttl)
+}
+
+// storeKey 组装存储 key：前缀 + 作用域 + 操作（路由或 gRPC 方法）+ 幂等键
+func (g *Guard) storeKey(ctx context.Context, operation, key string) string {
+	scope := ""
+	if g.config.Scope != nil {
+		scope = g.config.Scope(ctx)
+	}
+	return g.config.Prefix + scope + ":" + operation + ":" + key
+}
+
+// validateKey 校验幂等键（为空时按 Required 决定是否报错）
+func (g *Guard) validateKey(key string) error {
+	if key == "" && g.config.Required {
+		return ErrKeyRequired
+	}
+	if len(key) > maxKeyLength {
+		return ErrInvalidKey
+	}
+	return nil
+}
+
+// fingerprint 计算请求指纹
+func fingerprint(parts ...[]byte) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write(part)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func newToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate idempotency token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
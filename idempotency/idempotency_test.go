@@ -0,0 +1,201 @@
+package idempotency
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v2"
+	redisClient "github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func newTestGuard(t *testing.T, config *Config) (*miniredis.Miniredis, *Guard) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redisClient.NewClient(&redisClient.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	guard, err := New(NewRedisStore(client), config)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return server, guard
+}
+
+func doRequest(t *testing.T, app *fiber.App, key, body string) (*httptest.ResponseRecorder, string) {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/payments", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(DefaultHeader, key)
+	}
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	recorder := httptest.NewRecorder()
+	recorder.Code = resp.StatusCode
+	for k, v := range resp.Header {
+		recorder.Header()[k] = v
+	}
+	return recorder, string(data)
+}
+
+func TestFiberMiddlewareReplaysDuplicateRequests(t *testing.T) {
+	_, guard := newTestGuard(t, nil)
+	var charges atomic.Int32
+	app := fiber.New()
+	app.Post("/payments", guard.FiberMiddleware(), func(c *fiber.Ctx) error {
+		n := charges.Add(1)
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"charge": n})
+	})
+
+	first, body := doRequest(t, app, "key-1", `{"amount":100}`)
+	if first.Code != fiber.StatusCreated || body != `{"charge":1}` {
+		t.Fatalf("unexpected first response: %d %s", first.Code, body)
+	}
+	replayed, body := doRequest(t, app, "key-1", `{"amount":100}`)
+	if replayed.Code != fiber.StatusCreated || body != `{"charge":1}` || replayed.Header().Get(ReplayedHeader) != "true" {
+		t.Fatalf("expected replayed response, got %d %s %v", replayed.Code, body, replayed.Header())
+	}
+	if !strings.HasPrefix(replayed.Header().Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) {
+		t.Fatalf("expected replayed content type, got %q", replayed.Header().Get(fiber.HeaderContentType))
+	}
+	if charges.Load() != 1 {
+		t.Fatalf("expected handler to run once, ran %d times", charges.Load())
+	}
+
+	// 相同的 key 携带不同的请求内容
+	if reused, _ := doRequest(t, app, "key-1", `{"amount":200}`); reused.Code != fiber.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for reused key, got %d", reused.Code)
+	}
+	// 没有幂等键的请求直接处理
+	if plain, _ := doRequest(t, app, "", `{"amount":100}`); plain.Code != fiber.StatusCreated || charges.Load() != 2 {
+		t.Fatalf("expected request without key to be processed, got %d", plain.Code)
+	}
+}
+
+func TestFiberMiddlewareRejectsConcurrentDuplicate(t *testing.T) {
+	_, guard := newTestGuard(t, nil)
+	started, release := make(chan struct{}), make(chan struct{})
+	app := fiber.New()
+	app.Post("/payments", guard.FiberMiddleware(), func(c *fiber.Ctx) error {
+		close(started)
+		<-release
+		return c.SendString("ok")
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		doRequest(t, app, "key-2", "{}")
+	}()
+	<-started
+	if dup, _ := doRequest(t, app, "key-2", "{}"); dup.Code != fiber.StatusConflict {
+		t.Fatalf("expected 409 while first request is in progress, got %d", dup.Code)
+	}
+	close(release)
+	<-done
+}
+
+func TestFiberMiddlewareReleasesKeyOnServerError(t *testing.T) {
+	server, guard := newTestGuard(t, &Config{Required: true})
+	var calls atomic.Int32
+	app := fiber.New()
+	app.Post("/payments", guard.FiberMiddleware(), func(c *fiber.Ctx) error {
+		if calls.Add(1) == 1 {
+			return c.Status(fiber.StatusBadGateway).SendString("upstream failed")
+		}
+		return c.SendString("ok")
+	})
+
+	if first, _ := doRequest(t, app, "key-3", "{}"); first.Code != fiber.StatusBadGateway {
+		t.Fatalf("unexpected first response: %d", first.Code)
+	}
+	if len(server.Keys()) != 0 {
+		t.Fatalf("expected key to be released after 5xx, got %v", server.Keys())
+	}
+	if retry, body := doRequest(t, app, "key-3", "{}"); retry.Code != fiber.StatusOK || body != "ok" {
+		t.Fatalf("expected retry to be processed, got %d %s", retry.Code, body)
+	}
+	if missing, _ := doRequest(t, app, "", "{}"); missing.Code != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 when key is required, got %d", missing.Code)
+	}
+
+	// 存储不可用时默认拒绝请求
+	server.Close()
+	if unavailable, _ := doRequest(t, app, "key-4", "{}"); unavailable.Code != fiber.StatusServiceUnavailable {
+		t.Fatalf("expected 503 when store is unavailable, got %d", unavailable.Code)
+	}
+}
+
+func TestUnaryServerInterceptorReplaysResponse(t *testing.T) {
+	_, guard := newTestGuard(t, &Config{TTL: "1h", LockTTL: "10s"})
+	interceptor := guard.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/payment.Payment/Charge"}
+	var calls atomic.Int32
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return wrapperspb.String("charge-" + string(rune('0'+calls.Add(1)))), nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("idempotency-key", "key-1"))
+	for i := 0; i < 2; i++ {
+		resp, err := interceptor(ctx, wrapperspb.Int64(100), info, handler)
+		if err != nil {
+			t.Fatalf("call %d failed: %v", i, err)
+		}
+		if got := resp.(*wrapperspb.StringValue).GetValue(); got != "charge-1" {
+			t.Fatalf("call %d: expected replayed charge-1, got %s", i, got)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected handler to run once, ran %d times", calls.Load())
+	}
+
+	_, err := interceptor(ctx, wrapperspb.Int64(200), info, handler)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for reused key, got %v", err)
+	}
+
+	failing := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Unavailable, "down")
+	}
+	retryCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("idempotency-key", "key-2"))
+	if _, err := interceptor(retryCtx, wrapperspb.Int64(100), info, failing); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected handler error, got %v", err)
+	}
+	if _, err := interceptor(retryCtx, wrapperspb.Int64(100), info, handler); err != nil {
+		t.Fatalf("expected retry after failure to be processed: %v", err)
+	}
+}
+
+func TestRedisStoreCompleteRequiresToken(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redisClient.NewClient(&redisClient.Options{Addr: server.Addr()})
+	defer client.Close()
+	store := NewRedisStore(client)
+	ctx := context.Background()
+
+	if _, reserved, err := store.Reserve(ctx, "k", &Record{Token: "owner", Fingerprint: "fp"}, time.Minute); err != nil || !reserved {
+		t.Fatalf("Reserve failed: %v, %v", reserved, err)
+	}
+	if err := store.Complete(ctx, "k", "other", &Record{Fingerprint: "fp", Completed: true}, time.Hour); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	existing, reserved, err := store.Reserve(ctx, "k", &Record{Token: "next", Fingerprint: "fp"}, time.Minute)
+	if err != nil || reserved || existing.Completed || existing.Token != "owner" {
+		t.Fatalf("expected record to stay owned by the first token, got %+v, %v, %v", existing, reserved, err)
+	}
+	if err := store.Release(ctx, "k", "owner"); err != nil || server.Exists("k") {
+		t.Fatalf("expected owner to release key, err=%v", err)
+	}
+}
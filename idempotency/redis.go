@@ -0,0 +1,95 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	redisClient "github.com/redis/go-redis/v9"
+
+	"github.com/team-dandelion/quickgo/json"
+)
+
+// completeScript 仅当 key 仍由 token 占用时写入处理结果
+var completeScript = redisClient.NewScript(`
+local value = redis.call("GET", KEYS[1])
+if not value then
+	return 0
+end
+if cjson.decode(value)["token"] ~= ARGV[1] then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+return 1
+`)
+
+// releaseScript 仅当 key 仍由 token 占用时删除
+var releaseScript = redisClient.NewScript(`
+local value = redis.call("GET", KEYS[1])
+if value and cjson.decode(value)["token"] == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// RedisStore 基于 Redis 的幂等记录存储
+type RedisStore struct {
+	client redisClient.Cmdable
+}
+
+// NewRedisStore 创建 Redis 幂等记录存储
+func NewRedisStore(client redisClient.Cmdable) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Reserve 占用 key（SET NX），key 已存在时返回已有记录
+func (s *RedisStore) Reserve(ctx context.Context, key string, record *Record, ttl time.Duration) (*Record, bool, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode idempotency record: %w", err)
+	}
+	// 已有记录可能在读取前过期，此时重试占用
+	for attempt := 0; attempt < 2; attempt++ {
+		ok, err := s.client.SetNX(ctx, key, data, ttl).Result()
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+		if ok {
+			return nil, true, nil
+		}
+		existing, err := s.client.Get(ctx, key).Bytes()
+		if errors.Is(err, redisClient.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to load idempotency record: %w", err)
+		}
+		var stored Record
+		if err := json.Unmarshal(existing, &stored); err != nil {
+			return nil, false, fmt.Errorf("failed to decode idempotency record: %w", err)
+		}
+		return &stored, false, nil
+	}
+	return nil, false, ErrInProgress
+}
+
+// Complete 保存处理结果
+func (s *RedisStore) Complete(ctx context.Context, key, token string, record *Record, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode idempotency record: %w", err)
+	}
+	if err := completeScript.Run(ctx, s.client, []string{key}, token, data, ttl.Milliseconds()).Err(); err != nil {
+		return fmt.Errorf("failed to store idempotency record: %w", err)
+	}
+	return nil
+}
+
+// Release 释放占用
+func (s *RedisStore) Release(ctx context.Context, key, token string) error {
+	if err := releaseScript.Run(ctx, s.client, []string{key}, token).Err(); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,327 @@
+package loadshed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"path"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/types"
+)
+
+const (
+	defaultSampleInterval = time.Second
+	defaultLatencyWindow  = 1000
+	defaultRecoverRatio   = 0.8
+	defaultStep           = 0.1
+	defaultMaxDropRatio   = 0.9
+	// minLatencySamples 计算 p99 所需的最少样本数，样本不足时忽略延迟信号
+	minLatencySamples = 20
+)
+
+// Config 自适应过载保护配置
+// 按采样间隔检测 CPU 使用率、GC 停顿、goroutine 数与请求 p99 延迟，任一信号超过阈值时按 Step 提高拒绝比例，
+// 全部信号回落到 阈值 × RecoverRatio 以下时按 Step 降低拒绝比例（滞回，避免在阈值附近反复切换）
+// 阈值为 0 的信号不参与检测
+type Config struct {
+	// CPU 使用率阈值（0-1，按 GOMAXPROCS 计算，如 0.85），仅 Unix 系统支持
+	CPUThreshold float64 `json:"cpuThreshold" yaml:"cpuThreshold" toml:"cpuThreshold"`
+	// 采样间隔内最大 GC 停顿阈值 示例：50ms
+	GCPauseThreshold types.Duration `json:"gcPauseThreshold" yaml:"gcPauseThreshold" toml:"gcPauseThreshold"`
+	// goroutine 数阈值
+	GoroutineThreshold int `json:"goroutineThreshold" yaml:"goroutineThreshold" toml:"goroutineThreshold"`
+	// 请求 p99 延迟阈值（按采样间隔内完成的请求计算） 示例：500ms
+	LatencyThreshold types.Duration `json:"latencyThreshold" yaml:"latencyThreshold" toml:"latencyThreshold"`
+	// 每个采样间隔保留的最大延迟样本数，默认 1000
+	LatencyWindow int `json:"latencyWindow" yaml:"latencyWindow" toml:"latencyWindow"`
+	// 采样间隔，默认 1s
+	SampleInterval types.Duration `json:"sampleInterval" yaml:"sampleInterval" toml:"sampleInterval"`
+	// 恢复比例（0-1），全部信号低于 阈值 × RecoverRatio 时降低拒绝比例，默认 0.8
+	RecoverRatio float64 `json:"recoverRatio" yaml:"recoverRatio" toml:"recoverRatio"`
+	// 每次调整的拒绝比例步长，默认 0.1
+	Step float64 `json:"step" yaml:"step" toml:"step"`
+	// 最大拒绝比例（0-1），默认 0.9，保证过载时仍有部分请求通过以观测恢复情况
+	MaxDropRatio float64 `json:"maxDropRatio" yaml:"maxDropRatio" toml:"maxDropRatio"`
+	// 不参与过载保护的 HTTP 路径或 gRPC 方法（支持 * 通配符，如 /healthz、/grpc.health.v1.Health/*）
+	Exclude []string `json:"exclude" yaml:"exclude" toml:"exclude"`
+}
+
+// Validate 校验配置
+func (c Config) Validate() error {
+	if c.CPUThreshold < 0 || c.CPUThreshold > 1 {
+		return fmt.Errorf("cpuThreshold must be between 0 and 1, got %v", c.CPUThreshold)
+	}
+	if c.GCPauseThreshold < 0 || c.LatencyThreshold < 0 || c.SampleInterval < 0 {
+		return errors.New("gcPauseThreshold, latencyThreshold and sampleInterval must be non-negative")
+	}
+	if c.GoroutineThreshold < 0 || c.LatencyWindow < 0 {
+		return errors.New("goroutineThreshold and latencyWindow must be non-negative")
+	}
+	for name, ratio := range map[string]float64{"recoverRatio": c.RecoverRatio, "step": c.Step, "maxDropRatio": c.MaxDropRatio} {
+		if ratio < 0 || ratio > 1 {
+			return fmt.Errorf("%s must be between 0 and 1, got %v", name, ratio)
+		}
+	}
+	if c.CPUThreshold == 0 && c.GCPauseThreshold == 0 && c.GoroutineThreshold == 0 && c.LatencyThreshold == 0 {
+		return errors.New("at least one of cpuThreshold, gcPauseThreshold, goroutineThreshold and latencyThreshold is required")
+	}
+	for _, pattern := range c.Exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Signals 运行时信号采样结果
+type Signals struct {
+	// CPU 使用率（0-1，不支持的平台为 0）
+	CPU float64
+	// 采样间隔内的最大 GC 停顿
+	GCPause time.Duration
+	// goroutine 数
+	Goroutines int
+	// 采样间隔内请求的 p99 延迟（样本不足时为 0）
+	LatencyP99 time.Duration
+}
+
+// Stats 过载保护状态
+type Stats struct {
+	// 最近一次采样的信号
+	Signals Signals
+	// 当前拒绝比例
+	DropRatio float64
+	// 累计拒绝的请求数
+	Rejected int64
+}
+
+// Shedder 自适应过载保护器，HTTP 中间件与 gRPC 拦截器通过 Allow 判断是否处理请求
+// 采样在请求路径上按 SampleInterval 惰性执行，无需后台 goroutine
+type Shedder struct {
+	name   string
+	config Config
+
+	interval   time.Duration
+	nextSample atomic.Int64
+	dropRatio  atomic.Uint64 // math.Float64bits
+	rejected   atomic.Int64
+
+	mu        sync.Mutex
+	signals   Signals
+	lastWall  time.Time
+	lastCPU   time.Duration
+	cpuOK     bool
+	lastNumGC uint32
+	latencies []time.Duration
+	latencyAt int
+	// readSignals 读取运行时信号（测试可替换）
+	readSignals func(now time.Time) Signals
+}
+
+// New 创建过载保护器，name 用于日志与指标标签（如 http、grpc）
+func New(name string, config Config) (*Shedder, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid load shedding config: %w", err)
+	}
+	if config.LatencyWindow == 0 {
+		config.LatencyWindow = defaultLatencyWindow
+	}
+	if config.RecoverRatio == 0 {
+		config.RecoverRatio = defaultRecoverRatio
+	}
+	if config.Step == 0 {
+		config.Step = defaultStep
+	}
+	if config.MaxDropRatio == 0 {
+		config.MaxDropRatio = defaultMaxDropRatio
+	}
+	s := &Shedder{
+		name:      name,
+		config:    config,
+		interval:  config.SampleInterval.OrDefault(defaultSampleInterval),
+		latencies: make([]time.Duration, 0, config.LatencyWindow),
+	}
+	s.readSignals = s.readRuntimeSignals
+
+	now := time.Now()
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	s.lastWall, s.lastNumGC = now, mem.NumGC
+	s.lastCPU, s.cpuOK = processCPUTime()
+	s.nextSample.Store(now.Add(s.interval).UnixNano())
+	return s, nil
+}
+
+// Excluded 判断 HTTP 路径或 gRPC 方法是否不参与过载保护
+func (s *Shedder) Excluded(name string) bool {
+	for _, pattern := range s.config.Exclude {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Allow 判断是否处理请求，拒绝时累计拒绝数
+func (s *Shedder) Allow() bool {
+	now := time.Now()
+	if next := s.nextSample.Load(); now.UnixNano() >= next && s.nextSample.CompareAndSwap(next, now.Add(s.interval).UnixNano()) {
+		s.sample(now)
+	}
+	ratio := s.DropRatio()
+	if ratio <= 0 || rand.Float64() >= ratio {
+		return true
+	}
+	s.rejected.Add(1)
+	return false
+}
+
+// Observe 记录已处理请求的耗时，用于计算 p99 延迟
+func (s *Shedder) Observe(latency time.Duration) {
+	if s.config.LatencyThreshold == 0 {
+		return
+	}
+	s.mu.Lock()
+	if len(s.latencies) < cap(s.latencies) {
+		s.latencies = append(s.latencies, latency)
+	} else {
+		s.latencies[s.latencyAt] = latency
+		s.latencyAt = (s.latencyAt + 1) % len(s.latencies)
+	}
+	s.mu.Unlock()
+}
+
+// DropRatio 返回当前拒绝比例
+func (s *Shedder) DropRatio() float64 {
+	return math.Float64frombits(s.dropRatio.Load())
+}
+
+// Stats 返回过载保护状态
+func (s *Shedder) Stats() Stats {
+	s.mu.Lock()
+	signals := s.signals
+	s.mu.Unlock()
+	return Stats{Signals: signals, DropRatio: s.DropRatio(), Rejected: s.rejected.Load()}
+}
+
+// sample 采样运行时信号并调整拒绝比例
+func (s *Shedder) sample(now time.Time) {
+	s.mu.Lock()
+	signals := s.readSignals(now)
+	s.signals = signals
+	s.mu.Unlock()
+	s.evaluate(signals)
+}
+
+// readRuntimeSignals 读取运行时信号，调用方持有 s.mu
+func (s *Shedder) readRuntimeSignals(now time.Time) Signals {
+	signals := Signals{Goroutines: runtime.NumGoroutine()}
+
+	if s.config.CPUThreshold > 0 && s.cpuOK {
+		if cpu, ok := processCPUTime(); ok {
+			if wall := now.Sub(s.lastWall); wall > 0 {
+				signals.CPU = float64(cpu-s.lastCPU) / (float64(wall) * float64(runtime.GOMAXPROCS(0)))
+			}
+			s.lastCPU = cpu
+		}
+	}
+	s.lastWall = now
+
+	if s.config.GCPauseThreshold > 0 {
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+		// PauseNs 为环形缓冲区，最近一次 GC 位于 (NumGC+255)%256
+		for n := min(mem.NumGC-s.lastNumGC, uint32(len(mem.PauseNs))); n > 0; n-- {
+			pause := time.Duration(mem.PauseNs[(mem.NumGC-n)%uint32(len(mem.PauseNs))])
+			signals.GCPause = max(signals.GCPause, pause)
+		}
+		s.lastNumGC = mem.NumGC
+	}
+
+	if len(s.latencies) >= minLatencySamples {
+		sorted := slices.Clone(s.latencies)
+		slices.Sort(sorted)
+		signals.LatencyP99 = sorted[int(math.Ceil(float64(len(sorted))*0.99))-1]
+	}
+	s.latencies, s.latencyAt = s.latencies[:0], 0
+	return signals
+}
+
+// evaluate 按信号调整拒绝比例：超过阈值时提高，全部回落到恢复线以下时降低，介于两者之间时保持
+func (s *Shedder) evaluate(signals Signals) {
+	current := s.DropRatio()
+	next := current
+	switch {
+	case s.exceeds(signals, 1):
+		next = math.Min(s.config.MaxDropRatio, current+s.config.Step)
+	case !s.exceeds(signals, s.config.RecoverRatio):
+		next = math.Max(0, current-s.config.Step)
+	}
+	if next == current {
+		return
+	}
+	s.dropRatio.Store(math.Float64bits(next))
+
+	ctx := context.Background()
+	switch {
+	case current == 0:
+		logger.Warn(ctx, "Load shedding started: name=%s, drop_ratio=%.2f, cpu=%.2f, gc_pause=%v, goroutines=%d, p99=%v",
+			s.name, next, signals.CPU, signals.GCPause, signals.Goroutines, signals.LatencyP99)
+	case next == 0:
+		logger.Info(ctx, "Load shedding stopped: name=%s, rejected=%d", s.name, s.rejected.Load())
+	}
+}
+
+// exceeds 判断是否有信号超过 阈值 × factor
+func (s *Shedder) exceeds(signals Signals, factor float64) bool {
+	c := s.config
+	return (c.CPUThreshold > 0 && signals.CPU > c.CPUThreshold*factor) ||
+		(c.GCPauseThreshold > 0 && float64(signals.GCPause) > float64(c.GCPauseThreshold)*factor) ||
+		(c.GoroutineThreshold > 0 && float64(signals.Goroutines) > float64(c.GoroutineThreshold)*factor) ||
+		(c.LatencyThreshold > 0 && float64(signals.LatencyP99) > float64(c.LatencyThreshold)*factor)
+}
+
+// RegisterMetrics 将过载保护指标注册到 Prometheus（重复注册时忽略），指标带 server 标签（取 New 的 name）
+// 指标：{namespace}_loadshed_drop_ratio、_rejected_total、_cpu_utilization、_gc_pause_seconds、_goroutines、_latency_p99_seconds
+func (s *Shedder) RegisterMetrics(registerer prometheus.Registerer, namespace string) error {
+	if registerer == nil {
+		return errors.New("registerer is nil")
+	}
+	labels := prometheus.Labels{"server": s.name}
+	gauge := func(name, help string, value func(Stats) float64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: "loadshed", Name: name, Help: help, ConstLabels: labels,
+		}, func() float64 { return value(s.Stats()) })
+	}
+	collectors := []prometheus.Collector{
+		gauge("drop_ratio", "Current fraction of requests rejected by load shedding", func(st Stats) float64 { return st.DropRatio }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "loadshed", Name: "rejected_total",
+			Help: "Total number of requests rejected by load shedding", ConstLabels: labels,
+		}, func() float64 { return float64(s.rejected.Load()) }),
+		gauge("cpu_utilization", "Process CPU utilization at the last sample", func(st Stats) float64 { return st.Signals.CPU }),
+		gauge("gc_pause_seconds", "Longest GC pause during the last sample interval", func(st Stats) float64 { return st.Signals.GCPause.Seconds() }),
+		gauge("goroutines", "Number of goroutines at the last sample", func(st Stats) float64 { return float64(st.Signals.Goroutines) }),
+		gauge("latency_p99_seconds", "Request p99 latency during the last sample interval", func(st Stats) float64 { return st.Signals.LatencyP99.Seconds() }),
+	}
+	for _, collector := range collectors {
+		if err := registerer.Register(collector); err != nil {
+			var already prometheus.AlreadyRegisteredError
+			if errors.As(err, &already) {
+				continue
+			}
+			return fmt.Errorf("failed to register load shedding metrics: %w", err)
+		}
+	}
+	return nil
+}
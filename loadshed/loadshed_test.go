@@ -0,0 +1,132 @@
+package loadshed
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/team-dandelion/quickgo/types"
+)
+
+func newTestShedder(t *testing.T, config Config) *Shedder {
+	t.Helper()
+	s, err := New("test", config)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return s
+}
+
+func TestShedderEvaluateWithHysteresis(t *testing.T) {
+	s := newTestShedder(t, Config{GoroutineThreshold: 100, Step: 0.5, MaxDropRatio: 0.9})
+
+	s.evaluate(Signals{Goroutines: 150})
+	if ratio := s.DropRatio(); ratio != 0.5 {
+		t.Fatalf("expected drop ratio 0.5 after overload, got %v", ratio)
+	}
+	s.evaluate(Signals{Goroutines: 150})
+	if ratio := s.DropRatio(); ratio != 0.9 {
+		t.Fatalf("drop ratio should be capped at maxDropRatio, got %v", ratio)
+	}
+	// 低于阈值但高于恢复线（100 × 0.8）时保持
+	s.evaluate(Signals{Goroutines: 90})
+	if ratio := s.DropRatio(); ratio != 0.9 {
+		t.Fatalf("drop ratio should hold inside hysteresis band, got %v", ratio)
+	}
+	s.evaluate(Signals{Goroutines: 10})
+	s.evaluate(Signals{Goroutines: 10})
+	if ratio := s.DropRatio(); ratio != 0 {
+		t.Fatalf("drop ratio should recover to 0, got %v", ratio)
+	}
+}
+
+func TestShedderLatencyP99PerInterval(t *testing.T) {
+	s := newTestShedder(t, Config{LatencyThreshold: types.Duration(100 * time.Millisecond)})
+
+	for i := 0; i < 10; i++ {
+		s.Observe(time.Second)
+	}
+	s.sample(time.Now())
+	if p99 := s.Stats().Signals.LatencyP99; p99 != 0 {
+		t.Fatalf("latency should be ignored with too few samples, got %v", p99)
+	}
+
+	for i := 1; i <= 100; i++ {
+		s.Observe(time.Duration(i) * time.Millisecond)
+	}
+	s.sample(time.Now())
+	if p99 := s.Stats().Signals.LatencyP99; p99 != 99*time.Millisecond {
+		t.Fatalf("unexpected p99: %v", p99)
+	}
+	if ratio := s.DropRatio(); ratio != 0 {
+		t.Fatalf("p99 below threshold should not shed, got %v", ratio)
+	}
+}
+
+func TestMiddlewaresRejectWhenShedding(t *testing.T) {
+	s := newTestShedder(t, Config{GoroutineThreshold: 1, Step: 1, MaxDropRatio: 1, Exclude: []string{"/healthz"}})
+	s.evaluate(Signals{Goroutines: 2})
+
+	app := fiber.New()
+	app.Use(FiberMiddleware(s))
+	app.Get("/*", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusServiceUnavailable || resp.Header.Get(fiber.HeaderRetryAfter) != "1" {
+		t.Fatalf("expected 503 with Retry-After, got %d %q", resp.StatusCode, resp.Header.Get(fiber.HeaderRetryAfter))
+	}
+	if resp, _ := app.Test(httptest.NewRequest("GET", "/healthz", nil)); resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("excluded path should pass, got %d", resp.StatusCode)
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	interceptor := UnaryServerInterceptor(s)
+	if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/svc.Svc/Call"}, handler); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+	if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler); err != nil {
+		t.Fatalf("health check should never be shed: %v", err)
+	}
+	if rejected := s.Stats().Rejected; rejected != 2 {
+		t.Fatalf("expected 2 rejected requests, got %d", rejected)
+	}
+}
+
+func TestRegisterMetricsAndValidate(t *testing.T) {
+	s := newTestShedder(t, Config{CPUThreshold: 0.9})
+	registry := prometheus.NewRegistry()
+	if err := s.RegisterMetrics(registry, "quickgo"); err != nil {
+		t.Fatalf("RegisterMetrics failed: %v", err)
+	}
+	if err := s.RegisterMetrics(registry, "quickgo"); err != nil {
+		t.Fatalf("duplicate RegisterMetrics should be ignored: %v", err)
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	if len(families) != 6 {
+		t.Fatalf("expected 6 load shedding metrics, got %d", len(families))
+	}
+
+	for _, config := range []Config{
+		{},
+		{CPUThreshold: 1.5},
+		{GoroutineThreshold: 10, Step: 2},
+		{GoroutineThreshold: 10, Exclude: []string{"["}},
+	} {
+		if err := config.Validate(); err == nil {
+			t.Fatalf("expected validation error for %+v", config)
+		}
+	}
+}
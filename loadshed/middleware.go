@@ -0,0 +1,67 @@
+package loadshed
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// healthServicePrefix gRPC 健康检查服务，始终不参与过载保护
+const healthServicePrefix = "/grpc.health.v1.Health/"
+
+// FiberMiddleware HTTP 过载保护中间件，拒绝时返回 503 与 Retry-After
+func FiberMiddleware(s *Shedder) fiber.Handler {
+	retryAfter := strconv.Itoa(max(1, int(s.interval.Round(time.Second)/time.Second)))
+	return func(c *fiber.Ctx) error {
+		if s.Excluded(c.Path()) {
+			return c.Next()
+		}
+		if !s.Allow() {
+			c.Set(fiber.HeaderRetryAfter, retryAfter)
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": "Service Unavailable",
+				"code":  fiber.StatusServiceUnavailable,
+			})
+		}
+		start := time.Now()
+		err := c.Next()
+		s.Observe(time.Since(start))
+		return err
+	}
+}
+
+// UnaryServerInterceptor gRPC 一元过载保护拦截器，拒绝时返回 ResourceExhausted
+func UnaryServerInterceptor(s *Shedder) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if s.excludedMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		if !s.Allow() {
+			return nil, status.Error(codes.ResourceExhausted, "server overloaded, request shed")
+		}
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		s.Observe(time.Since(start))
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor gRPC 流式过载保护拦截器，仅在建立流时判断，流的持续时间不计入延迟
+func StreamServerInterceptor(s *Shedder) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !s.excludedMethod(info.FullMethod) && !s.Allow() {
+			return status.Error(codes.ResourceExhausted, "server overloaded, request shed")
+		}
+		return handler(srv, ss)
+	}
+}
+
+func (s *Shedder) excludedMethod(method string) bool {
+	return strings.HasPrefix(method, healthServicePrefix) || s.Excluded(method)
+}
@@ -0,0 +1,221 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BoostConfig 错误率触发的日志级别自动提升配置
+type BoostConfig struct {
+	// 是否启用
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled"`
+	// 统计窗口，示例：1m（默认 1m）
+	Window string `json:"window" yaml:"window" toml:"window" validate:"duration"`
+	// 错误率阈值（0~1），超过后提升日志级别（默认 0.5）
+	Threshold float64 `json:"threshold" yaml:"threshold" toml:"threshold"`
+	// 窗口内最少请求数，低于该值不触发（默认 20）
+	MinRequests int `json:"minRequests" yaml:"minRequests" toml:"minRequests"`
+	// 提升后的日志级别（默认 debug）
+	Level string `json:"level" yaml:"level" toml:"level"`
+	// 冷却时间，提升后持续该时间无新触发则恢复，示例：5m（默认 5m）
+	Cooldown string `json:"cooldown" yaml:"cooldown" toml:"cooldown" validate:"duration"`
+}
+
+// moduleWindow 模块在当前统计窗口内的计数
+type moduleWindow struct {
+	start    time.Time
+	total    int
+	errors   int
+	boosted  bool
+	until    time.Time
+	previous *Level
+	timer    *time.Timer
+}
+
+// ErrorRateBooster 按模块统计错误率，超过阈值时临时将模块日志级别提升（如 debug），冷却后自动恢复
+type ErrorRateBooster struct {
+	window      time.Duration
+	threshold   float64
+	minRequests int
+	level       Level
+	cooldown    time.Duration
+	now         func() time.Time
+
+	mu      sync.Mutex
+	modules map[string]*moduleWindow
+}
+
+var (
+	defaultBooster   *ErrorRateBooster
+	defaultBoosterMu sync.RWMutex
+)
+
+// NewErrorRateBooster 创建错误率日志级别提升控制器
+func NewErrorRateBooster(config *BoostConfig) (*ErrorRateBooster, error) {
+	if config == nil {
+		return nil, errors.New("config is nil")
+	}
+
+	b := &ErrorRateBooster{
+		window:      time.Minute,
+		threshold:   0.5,
+		minRequests: 20,
+		level:       LevelDebug,
+		cooldown:    5 * time.Minute,
+		now:         time.Now,
+		modules:     make(map[string]*moduleWindow),
+	}
+	if config.Window != "" {
+		window, err := time.ParseDuration(config.Window)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("invalid boost window: %s", config.Window)
+		}
+		b.window = window
+	}
+	if config.Cooldown != "" {
+		cooldown, err := time.ParseDuration(config.Cooldown)
+		if err != nil || cooldown <= 0 {
+			return nil, fmt.Errorf("invalid boost cooldown: %s", config.Cooldown)
+		}
+		b.cooldown = cooldown
+	}
+	if config.Threshold != 0 {
+		if config.Threshold < 0 || config.Threshold > 1 {
+			return nil, fmt.Errorf("invalid boost threshold: %v", config.Threshold)
+		}
+		b.threshold = config.Threshold
+	}
+	if config.MinRequests > 0 {
+		b.minRequests = config.MinRequests
+	}
+	if config.Level != "" {
+		level, err := ParseLevel(config.Level)
+		if err != nil {
+			return nil, err
+		}
+		b.level = level
+	}
+	return b, nil
+}
+
+// SetDefaultBooster 设置全局错误率提升控制器（nil 表示关闭）
+func SetDefaultBooster(b *ErrorRateBooster) {
+	defaultBoosterMu.Lock()
+	defer defaultBoosterMu.Unlock()
+	defaultBooster = b
+}
+
+// DefaultBooster 获取全局错误率提升控制器，未配置时返回 nil
+func DefaultBooster() *ErrorRateBooster {
+	defaultBoosterMu.RLock()
+	defer defaultBoosterMu.RUnlock()
+	return defaultBooster
+}
+
+// Record 记录模块的一次请求结果，错误率超过阈值时提升该模块日志级别
+func (b *ErrorRateBooster) Record(module string, failed bool) {
+	if b == nil || module == "" {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	w, ok := b.modules[module]
+	if !ok {
+		w = &moduleWindow{start: now}
+		b.modules[module] = w
+	}
+	if now.Sub(w.start) >= b.window {
+		w.start, w.total, w.errors = now, 0, 0
+	}
+	w.total++
+	if failed {
+		w.errors++
+	}
+
+	if w.total < b.minRequests || float64(w.errors)/float64(w.total) < b.threshold {
+		return
+	}
+
+	if !w.boosted {
+		w.boosted = true
+		if previous, exists := GetModuleLevel(module); exists {
+			w.previous = &previous
+		} else {
+			w.previous = nil
+		}
+		SetModuleLevel(module, b.level)
+		Warn(context.Background(), "Log level boosted due to error rate: module=%s, errors=%d, total=%d, level=%s, cooldown=%s",
+			module, w.errors, w.total, levelNames[b.level], b.cooldown)
+		w.until = now.Add(b.cooldown)
+		w.timer = time.AfterFunc(b.cooldown, func() { b.revert(module, false) })
+		return
+	}
+	// 仍在高错误率，顺延冷却时间（由 revert 检查 until 并重新计时）
+	w.until = now.Add(b.cooldown)
+}
+
+// Boosted 返回当前处于提升状态的模块列表
+func (b *ErrorRateBooster) Boosted() []string {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var modules []string
+	for module, w := range b.modules {
+		if w.boosted {
+			modules = append(modules, module)
+		}
+	}
+	return modules
+}
+
+// Close 停止所有冷却计时器并立即恢复被提升的模块日志级别
+func (b *ErrorRateBooster) Close() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	modules := make([]string, 0, len(b.modules))
+	for module, w := range b.modules {
+		if w.boosted {
+			w.timer.Stop()
+			modules = append(modules, module)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, module := range modules {
+		b.revert(module, true)
+	}
+}
+
+// revert 恢复模块提升前的日志级别，冷却期被顺延时重新计时（force 为 true 时立即恢复）
+func (b *ErrorRateBooster) revert(module string, force bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	w, ok := b.modules[module]
+	if !ok || !w.boosted {
+		return
+	}
+	now := b.now()
+	if !force && now.Before(w.until) {
+		w.timer = time.AfterFunc(w.until.Sub(now), func() { b.revert(module, false) })
+		return
+	}
+	w.boosted = false
+	w.start, w.total, w.errors = now, 0, 0
+	if w.previous != nil {
+		SetModuleLevel(module, *w.previous)
+	} else {
+		ClearModuleLevel(module)
+	}
+	Info(context.Background(), "Log level boost reverted after cooldown: module=%s", module)
+}
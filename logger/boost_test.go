@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestModuleLevelOverridesLoggerLevel(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "logger_module_*.log")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	l, err := NewLogger(Config{Level: LevelInfo, Output: tmpFile.Name()})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer l.Close()
+
+	ctx := WithModule(context.Background(), "/user.UserService/Get")
+	l.Debug(ctx, "hidden debug")
+
+	SetModuleLevel("/user.UserService/Get", LevelDebug)
+	defer ClearModuleLevel("/user.UserService/Get")
+	l.Debug(ctx, "visible debug")
+	l.Debug(context.Background(), "other module debug")
+
+	content, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	output := string(content)
+	if strings.Contains(output, "hidden debug") || strings.Contains(output, "other module debug") {
+		t.Fatalf("unexpected debug output: %s", output)
+	}
+	if !strings.Contains(output, "visible debug") || !strings.Contains(output, `"module":"/user.UserService/Get"`) {
+		t.Fatalf("expected boosted module debug output, got: %s", output)
+	}
+}
+
+func TestErrorRateBoosterBoostsAndReverts(t *testing.T) {
+	booster, err := NewErrorRateBooster(&BoostConfig{
+		Window:      "1m",
+		Threshold:   0.5,
+		MinRequests: 4,
+		Level:       "debug",
+		Cooldown:    "50ms",
+	})
+	if err != nil {
+		t.Fatalf("NewErrorRateBooster failed: %v", err)
+	}
+	defer booster.Close()
+
+	const module = "/order.OrderService/Create"
+	SetModuleLevel(module, LevelWarn)
+	defer ClearModuleLevel(module)
+
+	booster.Record(module, true)
+	booster.Record(module, false)
+	booster.Record(module, true)
+	if len(booster.Boosted()) != 0 {
+		t.Fatal("expected no boost below min requests")
+	}
+	booster.Record(module, true)
+
+	if level, ok := GetModuleLevel(module); !ok || level != LevelDebug {
+		t.Fatalf("expected module boosted to debug, got %v, %v", level, ok)
+	}
+	if got := booster.Boosted(); len(got) != 1 || got[0] != module {
+		t.Fatalf("unexpected boosted modules: %v", got)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(booster.Boosted()) > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(booster.Boosted()) != 0 {
+		t.Fatal("expected boost to revert after cooldown")
+	}
+	if level, ok := GetModuleLevel(module); !ok || level != LevelWarn {
+		t.Fatalf("expected previous module level restored, got %v, %v", level, ok)
+	}
+}
+
+func TestErrorRateBoosterValidatesConfig(t *testing.T) {
+	if _, err := NewErrorRateBooster(nil); err == nil {
+		t.Fatal("expected nil config error")
+	}
+	if _, err := NewErrorRateBooster(&BoostConfig{Threshold: 2}); err == nil {
+		t.Fatal("expected invalid threshold error")
+	}
+	if _, err := NewErrorRateBooster(&BoostConfig{Cooldown: "soon"}); err == nil {
+		t.Fatal("expected invalid cooldown error")
+	}
+
+	// nil booster 可安全调用
+	var booster *ErrorRateBooster
+	booster.Record("module", true)
+	booster.Close()
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"sync/atomic"
 )
 
 type contextKey string
@@ -12,8 +13,34 @@ type contextKey string
 const (
 	traceIDKey contextKey = "trace_id"
 	spanIDKey  contextKey = "span_id"
+	fieldsKey  contextKey = "fields"
 )
 
+// TraceResolver 从 context 中解析外部链路系统（如 OpenTelemetry）的 trace ID 和 span ID
+// 未能解析时返回空字符串
+type TraceResolver func(ctx context.Context) (traceID, spanID string)
+
+var traceResolver atomic.Pointer[TraceResolver]
+
+// SetTraceResolver 设置链路解析器，设置后 GetTraceID / GetSpanID 优先返回解析结果
+// 传入 nil 时恢复为仅使用 context 中由 WithTraceID / WithSpanID 设置的值
+func SetTraceResolver(resolver TraceResolver) {
+	if resolver == nil {
+		traceResolver.Store(nil)
+		return
+	}
+	traceResolver.Store(&resolver)
+}
+
+// resolveTrace 通过链路解析器获取 trace ID 和 span ID
+func resolveTrace(ctx context.Context) (string, string) {
+	resolver := traceResolver.Load()
+	if resolver == nil {
+		return "", ""
+	}
+	return (*resolver)(ctx)
+}
+
 // WithTraceID 在 context 中设置 trace ID
 func WithTraceID(ctx context.Context, traceID string) context.Context {
 	return context.WithValue(ctx, traceIDKey, traceID)
@@ -31,11 +58,40 @@ func WithTrace(ctx context.Context, traceID, spanID string) context.Context {
 	return ctx
 }
 
+// WithContextFields 在 context 中附加结构化日志字段，经该 context 输出的日志自动携带
+// 与已附加的字段合并（同名覆盖），日志调用时显式传入的字段优先
+func WithContextFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	parent := GetContextFields(ctx)
+	merged := make(map[string]interface{}, len(parent)+len(fields))
+	for k, v := range parent {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, fieldsKey, merged)
+}
+
+// GetContextFields 从 context 中获取 WithContextFields 附加的日志字段（只读）
+func GetContextFields(ctx context.Context) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey).(map[string]interface{})
+	return fields
+}
+
 // GetTraceID 从 context 中获取 trace ID
 func GetTraceID(ctx context.Context) string {
 	if ctx == nil {
 		return ""
 	}
+	if traceID, _ := resolveTrace(ctx); traceID != "" {
+		return traceID
+	}
 	if traceID, ok := ctx.Value(traceIDKey).(string); ok {
 		return traceID
 	}
@@ -47,6 +103,9 @@ func GetSpanID(ctx context.Context) string {
 	if ctx == nil {
 		return ""
 	}
+	if _, spanID := resolveTrace(ctx); spanID != "" {
+		return spanID
+	}
 	if spanID, ok := ctx.Value(spanIDKey).(string); ok {
 		return spanID
 	}
@@ -20,6 +20,13 @@ const (
 	FieldUserAgent     = "user_agent"     // User Agent
 	FieldClientIP      = "client_ip"      // 客户端 IP
 	FieldRemoteAddr    = "remote_addr"    // 远程地址
+	FieldDeadlineMs    = "deadline_ms"    // 请求开始时距截止时间的剩余时间（毫秒）
+	FieldRequestSize   = "request_size"   // 请求大小（字节）
+	FieldResponseSize  = "response_size"  // 响应大小（字节）
+
+	// 载荷相关（脱敏后）
+	FieldRequestPayload  = "request"  // 请求载荷
+	FieldResponsePayload = "response" // 响应载荷
 
 	// gRPC 相关
 	FieldGRPCCode    = "grpc_code"    // gRPC 状态码
@@ -34,6 +41,7 @@ const (
 	FieldInstanceID  = "instance_id" // 实例 ID
 	FieldPodName     = "pod_name"    // Kubernetes Pod 名称
 	FieldNamespace   = "namespace"   // Kubernetes 命名空间
+	FieldPID         = "pid"         // 进程 ID
 
 	// 错误相关
 	FieldError      = "error"       // 错误信息
@@ -71,6 +79,7 @@ const (
 	FieldResource  = "resource"  // 资源类型
 	FieldModule    = "module"    // 模块名
 	FieldComponent = "component" // 组件名
+	FieldLibrary   = "library"   // 第三方库名
 )
 
 // Fields 日志字段类型
@@ -0,0 +1,49 @@
+package logger
+
+import "sync"
+
+// EntryHook 日志条目钩子，在条目写出前同步调用（用于测试捕获、转发到其他系统）
+type EntryHook func(entry LogEntry)
+
+var (
+	entryHooksMu  sync.RWMutex
+	entryHooks    = make(map[int]EntryHook)
+	nextEntryHook int
+)
+
+// AddEntryHook 注册全局日志钩子，对所有 Logger 生效（包括之后通过 Init 重新创建的默认 Logger），
+// 只接收通过级别过滤的条目，返回注销函数（可重复调用）
+func AddEntryHook(hook EntryHook) func() {
+	if hook == nil {
+		return func() {}
+	}
+	entryHooksMu.Lock()
+	nextEntryHook++
+	id := nextEntryHook
+	entryHooks[id] = hook
+	entryHooksMu.Unlock()
+
+	return func() {
+		entryHooksMu.Lock()
+		defer entryHooksMu.Unlock()
+		delete(entryHooks, id)
+	}
+}
+
+// runEntryHooks 依次调用已注册的钩子
+func runEntryHooks(entry LogEntry) {
+	entryHooksMu.RLock()
+	if len(entryHooks) == 0 {
+		entryHooksMu.RUnlock()
+		return
+	}
+	hooks := make([]EntryHook, 0, len(entryHooks))
+	for _, hook := range entryHooks {
+		hooks = append(hooks, hook)
+	}
+	entryHooksMu.RUnlock()
+
+	for _, hook := range hooks {
+		hook(entry)
+	}
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// 第三方库名称（用于按库配置日志级别）
+const (
+	LibraryEtcd  = "etcd"
+	LibraryMongo = "mongo"
+	LibraryFiber = "fiber"
+)
+
+// DefaultLibraryLevel 第三方库默认日志级别（未单独配置时使用）
+const DefaultLibraryLevel = LevelWarn
+
+var (
+	libraryLevels   = make(map[string]Level)
+	libraryLevelsMu sync.RWMutex
+)
+
+// ParseLevel 解析日志级别字符串（debug、info、warn、error、fatal）
+func ParseLevel(level string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	case "fatal":
+		return LevelFatal, nil
+	default:
+		return LevelInfo, fmt.Errorf("invalid log level: %s", level)
+	}
+}
+
+// SetLibraryLevel 设置第三方库的日志级别
+func SetLibraryLevel(library string, level Level) {
+	libraryLevelsMu.Lock()
+	defer libraryLevelsMu.Unlock()
+	libraryLevels[library] = level
+}
+
+// GetLibraryLevel 获取第三方库的日志级别
+func GetLibraryLevel(library string) Level {
+	libraryLevelsMu.RLock()
+	defer libraryLevelsMu.RUnlock()
+	if level, ok := libraryLevels[library]; ok {
+		return level
+	}
+	return DefaultLibraryLevel
+}
+
+// LibraryEnabled 判断第三方库指定级别的日志是否需要输出
+func LibraryEnabled(library string, level Level) bool {
+	return level >= GetLibraryLevel(library)
+}
+
+// LogLibrary 通过默认日志记录器输出第三方库日志，并附加 library 字段
+func LogLibrary(ctx context.Context, library string, level Level, msg string, fields map[string]interface{}) {
+	if !LibraryEnabled(library, level) {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	allFields := make(map[string]interface{}, len(fields)+1)
+	for k, v := range fields {
+		allFields[k] = v
+	}
+	allFields[FieldLibrary] = library
+
+	// 第三方库日志最高按 Error 级别输出
+	if level > LevelError {
+		level = LevelError
+	}
+	// 第三方库消息可能包含 %，不作为格式化字符串处理
+	GetDefault().WithFields(allFields).log(ctx, level, msg, nil, nil)
+}
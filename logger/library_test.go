@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	cases := map[string]Level{"debug": LevelDebug, "INFO": LevelInfo, "warning": LevelWarn, "error": LevelError}
+	for input, want := range cases {
+		got, err := ParseLevel(input)
+		if err != nil || got != want {
+			t.Fatalf("ParseLevel(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Fatal("expected invalid level error")
+	}
+}
+
+func TestLogLibraryRespectsLibraryLevel(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "logger_library_*.log")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	previous := GetDefault()
+	l, err := NewLogger(Config{Level: LevelDebug, Output: tmpFile.Name()})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	SetDefault(l)
+	defer func() {
+		SetDefault(previous)
+		l.Close()
+	}()
+
+	SetLibraryLevel("test-lib", LevelWarn)
+	defer func() {
+		libraryLevelsMu.Lock()
+		delete(libraryLevels, "test-lib")
+		libraryLevelsMu.Unlock()
+	}()
+
+	if GetLibraryLevel("unconfigured-lib") != DefaultLibraryLevel {
+		t.Fatalf("expected default library level %v", DefaultLibraryLevel)
+	}
+
+	LogLibrary(context.Background(), "test-lib", LevelInfo, "suppressed info", nil)
+	LogLibrary(context.Background(), "test-lib", LevelWarn, "progress 100%", map[string]interface{}{"endpoint": "127.0.0.1:2379"})
+
+	content, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected exactly one log line, got %d: %s", len(lines), content)
+	}
+
+	var entry LogEntry
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if entry.Message != "progress 100%" || entry.Level != "WARN" {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+	if entry.Fields[FieldLibrary] != "test-lib" || entry.Fields["endpoint"] != "127.0.0.1:2379" {
+		t.Fatalf("expected library fields, got %+v", entry.Fields)
+	}
+}
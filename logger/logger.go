@@ -41,6 +41,14 @@ var levelNames = map[Level]string{
 	LevelFatal: "FATAL",
 }
 
+// String 返回小写的级别名称（与 ParseLevel 对应）
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return strings.ToLower(name)
+	}
+	return fmt.Sprintf("level(%d)", int(l))
+}
+
 // Logger 日志记录器
 type Logger struct {
 	level      Level
@@ -49,6 +57,8 @@ type Logger struct {
 	version    string
 	fields     map[string]interface{}
 	callerSkip int
+	// 内存接收器（NewTestLogger 创建时设置），设置后条目只写入接收器
+	sink *TestSink
 }
 
 // Config 日志配置
@@ -58,6 +68,8 @@ type Config struct {
 	Service    string // 服务名称
 	Version    string // 服务版本
 	CallerSkip int    // 调用栈跳过层数，0表示使用动态检测
+	// 附加到每条日志的固定字段（如 environment、hostname、pid）
+	Fields map[string]interface{}
 }
 
 // LogEntry 日志条目
@@ -85,6 +97,9 @@ func NewLogger(config Config) (*Logger, error) {
 		fields:     make(map[string]interface{}),
 		callerSkip: config.CallerSkip,
 	}
+	for k, v := range config.Fields {
+		logger.fields[k] = v
+	}
 
 	// 设置输出
 	if config.Output == "" {
@@ -132,7 +147,7 @@ func (l *Logger) WithContext(ctx context.Context) *Logger {
 
 // log 内部日志方法
 func (l *Logger) log(ctx context.Context, level Level, msg string, err error, fields map[string]interface{}) {
-	if level < l.level {
+	if level < l.effectiveLevel(ctx) {
 		return
 	}
 
@@ -144,6 +159,16 @@ func (l *Logger) log(ctx context.Context, level Level, msg string, err error, fi
 	for k, v := range fields {
 		allFields[k] = v
 	}
+	if module := GetModule(ctx); module != "" {
+		if _, exists := allFields[FieldModule]; !exists {
+			allFields[FieldModule] = module
+		}
+	}
+	for k, v := range GetContextFields(ctx) {
+		if _, exists := allFields[k]; !exists {
+			allFields[k] = v
+		}
+	}
 
 	// 获取调用者信息（从项目根目录开始的完整路径）
 	// 调用链分析：
@@ -206,6 +231,26 @@ func (l *Logger) log(ctx context.Context, level Level, msg string, err error, fi
 	traceID := GetTraceID(ctx)
 	spanID := GetSpanID(ctx)
 
+	entry := LogEntry{
+		Timestamp: time.Now().Format(time.RFC3339Nano),
+		Level:     levelNames[level],
+		Service:   l.service,
+		Version:   l.version,
+		TraceID:   traceID,
+		SpanID:    spanID,
+		Caller:    caller,
+		Message:   msg,
+		Fields:    allFields,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	runEntryHooks(entry)
+	if l.sink != nil {
+		l.sink.Record(entry)
+		return
+	}
+
 	// 判断是否是控制台输出
 	isConsole := l.output == os.Stdout || l.output == os.Stderr
 
@@ -236,23 +281,6 @@ func (l *Logger) log(ctx context.Context, level Level, msg string, err error, fi
 		fmt.Fprintf(l.output, "%s\n", strings.Join(parts, " "))
 	} else {
 		// 文件输出：使用 JSON 格式
-		entry := LogEntry{
-			Timestamp: time.Now().Format(time.RFC3339Nano),
-			Level:     levelNames[level],
-			Service:   l.service,
-			Version:   l.version,
-			TraceID:   traceID,
-			SpanID:    spanID,
-			Caller:    caller,
-			Message:   msg,
-			Fields:    allFields,
-		}
-
-		if err != nil {
-			entry.Error = err.Error()
-		}
-
-		// 序列化为 JSON
 		data, jsonErr := json.Marshal(entry)
 		if jsonErr != nil {
 			// 如果 JSON 序列化失败，使用简单格式
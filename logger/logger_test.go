@@ -219,6 +219,34 @@ func TestWithFields(t *testing.T) {
 	}
 }
 
+// TestWithContextFields 测试 context 附加字段
+func TestWithContextFields(t *testing.T) {
+	tmpFile, _ := os.CreateTemp("", "logger_test_*.log")
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	logger, _ := NewLogger(Config{
+		Level:  LevelInfo,
+		Output: tmpFile.Name(),
+	})
+	defer logger.Close()
+
+	ctx := WithContextFields(context.Background(), map[string]interface{}{"client_ip": "10.0.0.1", "env": "ctx"})
+	ctx = WithContextFields(ctx, map[string]interface{}{"user_agent": "grpc-go"})
+	logger.WithField("env", "test").Info(ctx, "hello")
+
+	content, _ := os.ReadFile(tmpFile.Name())
+	var entry LogEntry
+	json.Unmarshal(content, &entry)
+
+	if entry.Fields["client_ip"] != "10.0.0.1" || entry.Fields["user_agent"] != "grpc-go" {
+		t.Errorf("Expected context fields, got %v", entry.Fields)
+	}
+	if entry.Fields["env"] != "test" {
+		t.Errorf("Expected explicit field to win, got '%v'", entry.Fields["env"])
+	}
+}
+
 // TestWithField 测试单个字段添加
 func TestWithField(t *testing.T) {
 	tmpFile, _ := os.CreateTemp("", "logger_test_*.log")
@@ -370,3 +398,31 @@ func TestCallerInfo(t *testing.T) {
 		t.Errorf("Expected caller to contain 'logger_test.go', got '%s'", entry.Caller)
 	}
 }
+
+func TestEntryHookReceivesFilteredEntries(t *testing.T) {
+	var entries []LogEntry
+	remove := AddEntryHook(func(entry LogEntry) {
+		entries = append(entries, entry)
+	})
+	logger, err := NewLogger(Config{Level: LevelInfo, Output: t.TempDir() + "/hook.log", Service: "hook-service"})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	ctx := WithTraceID(context.Background(), "trace-1")
+	logger.Debug(ctx, "filtered")
+	logger.WithField("user", "u1").Error(ctx, "failed: %v", errors.New("boom"))
+	remove()
+	logger.Info(ctx, "after remove")
+	remove()
+
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d: %+v", len(entries), entries)
+	}
+	entry := entries[0]
+	if entry.Level != "ERROR" || entry.Message != "failed: boom" || entry.TraceID != "trace-1" ||
+		entry.Service != "hook-service" || entry.Fields["user"] != "u1" {
+		t.Fatalf("Unexpected entry: %+v", entry)
+	}
+}
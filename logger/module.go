@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+const moduleKey contextKey = "module"
+
+var (
+	moduleLevels   = make(map[string]Level)
+	moduleLevelsMu sync.RWMutex
+	// moduleLevelCount 当前模块级别覆盖的数量，为 0 时跳过查表
+	moduleLevelCount atomic.Int32
+)
+
+// WithModule 在 context 中设置模块名（如 gRPC 方法、HTTP 路由），用于按模块控制日志级别
+func WithModule(ctx context.Context, module string) context.Context {
+	return context.WithValue(ctx, moduleKey, module)
+}
+
+// GetModule 从 context 中获取模块名
+func GetModule(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if module, ok := ctx.Value(moduleKey).(string); ok {
+		return module
+	}
+	return ""
+}
+
+// SetModuleLevel 设置模块的日志级别，覆盖日志记录器自身的级别
+func SetModuleLevel(module string, level Level) {
+	moduleLevelsMu.Lock()
+	defer moduleLevelsMu.Unlock()
+	moduleLevels[module] = level
+	moduleLevelCount.Store(int32(len(moduleLevels)))
+}
+
+// ClearModuleLevel 清除模块的日志级别覆盖
+func ClearModuleLevel(module string) {
+	moduleLevelsMu.Lock()
+	defer moduleLevelsMu.Unlock()
+	delete(moduleLevels, module)
+	moduleLevelCount.Store(int32(len(moduleLevels)))
+}
+
+// GetModuleLevel 获取模块的日志级别覆盖
+func GetModuleLevel(module string) (Level, bool) {
+	moduleLevelsMu.RLock()
+	defer moduleLevelsMu.RUnlock()
+	level, ok := moduleLevels[module]
+	return level, ok
+}
+
+// ModuleLevels 返回当前所有模块级别覆盖的快照
+func ModuleLevels() map[string]Level {
+	moduleLevelsMu.RLock()
+	defer moduleLevelsMu.RUnlock()
+	result := make(map[string]Level, len(moduleLevels))
+	for module, level := range moduleLevels {
+		result[module] = level
+	}
+	return result
+}
+
+// effectiveLevel 返回本次日志生效的级别（模块覆盖优先）
+func (l *Logger) effectiveLevel(ctx context.Context) Level {
+	if moduleLevelCount.Load() == 0 {
+		return l.level
+	}
+	if module := GetModule(ctx); module != "" {
+		if level, ok := GetModuleLevel(module); ok {
+			return level
+		}
+	}
+	return l.level
+}
@@ -0,0 +1,151 @@
+package logger
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LevelOverride 运行时临时调整的日志级别，到期后自动恢复调整前的级别
+type LevelOverride struct {
+	// 模块名，为空表示全局（默认日志记录器）级别
+	Module string
+	// 当前级别
+	Level Level
+	// 调整前的级别（模块调整前没有覆盖时 HasPrevious 为 false，恢复时清除覆盖）
+	Previous    Level
+	HasPrevious bool
+	// 到期时间
+	ExpiresAt time.Time
+}
+
+// levelOverride 进行中的临时调整
+type levelOverride struct {
+	LevelOverride
+	timer *time.Timer
+}
+
+var (
+	levelOverrides   = make(map[string]*levelOverride)
+	levelOverridesMu sync.Mutex
+)
+
+// SetLevelWithTTL 设置默认日志记录器的级别，ttl 后恢复调整前的级别（ttl <= 0 时永久生效并取消进行中的恢复）
+func SetLevelWithTTL(level Level, ttl time.Duration) {
+	levelOverridesMu.Lock()
+	defer levelOverridesMu.Unlock()
+	current := GetDefault()
+	previous, hasPrevious := current.GetLevel(), true
+	if o, ok := levelOverrides[""]; ok {
+		previous = o.Previous
+	}
+	current.SetLevel(level)
+	scheduleRevertLocked("", level, previous, hasPrevious, ttl)
+}
+
+// SetModuleLevelWithTTL 设置模块的日志级别，ttl 后恢复调整前的级别（ttl <= 0 时永久生效并取消进行中的恢复）
+func SetModuleLevelWithTTL(module string, level Level, ttl time.Duration) {
+	levelOverridesMu.Lock()
+	defer levelOverridesMu.Unlock()
+	previous, hasPrevious := GetModuleLevel(module)
+	if o, ok := levelOverrides[module]; ok {
+		previous, hasPrevious = o.Previous, o.HasPrevious
+	}
+	SetModuleLevel(module, level)
+	scheduleRevertLocked(module, level, previous, hasPrevious, ttl)
+}
+
+// scheduleRevertLocked 记录临时调整并计时恢复，同一目标重复调整时保留最初的级别
+func scheduleRevertLocked(module string, level, previous Level, hasPrevious bool, ttl time.Duration) {
+	if o, ok := levelOverrides[module]; ok {
+		o.timer.Stop()
+		delete(levelOverrides, module)
+	}
+	if ttl <= 0 {
+		return
+	}
+	o := &levelOverride{LevelOverride: LevelOverride{
+		Module:      module,
+		Level:       level,
+		Previous:    previous,
+		HasPrevious: hasPrevious,
+		ExpiresAt:   time.Now().Add(ttl),
+	}}
+	o.timer = time.AfterFunc(ttl, func() { revertLevelOverride(module, o) })
+	levelOverrides[module] = o
+}
+
+// revertLevelOverride 到期恢复（o 已被新的调整替换时忽略）
+func revertLevelOverride(module string, o *levelOverride) {
+	levelOverridesMu.Lock()
+	defer levelOverridesMu.Unlock()
+	if levelOverrides[module] != o {
+		return
+	}
+	revertLocked(o)
+	Info(context.Background(), "Temporary log level reverted: module=%s, level=%s", module, levelDescription(o.Previous, o.HasPrevious))
+}
+
+func revertLocked(o *levelOverride) {
+	o.timer.Stop()
+	delete(levelOverrides, o.Module)
+	switch {
+	case o.Module == "":
+		GetDefault().SetLevel(o.Previous)
+	case o.HasPrevious:
+		SetModuleLevel(o.Module, o.Previous)
+	default:
+		ClearModuleLevel(o.Module)
+	}
+}
+
+// CancelLevelOverride 取消模块（为空表示全局）进行中的临时调整，保留当前级别不再自动恢复
+func CancelLevelOverride(module string) {
+	levelOverridesMu.Lock()
+	defer levelOverridesMu.Unlock()
+	if o, ok := levelOverrides[module]; ok {
+		o.timer.Stop()
+		delete(levelOverrides, module)
+	}
+}
+
+// RevertLevelOverrides 立即恢复所有临时调整，返回恢复的数量
+func RevertLevelOverrides() int {
+	levelOverridesMu.Lock()
+	defer levelOverridesMu.Unlock()
+	count := len(levelOverrides)
+	for _, o := range levelOverrides {
+		revertLocked(o)
+	}
+	return count
+}
+
+// LevelOverrides 返回进行中的临时调整（全局在前，模块按名称排序）
+func LevelOverrides() []LevelOverride {
+	levelOverridesMu.Lock()
+	defer levelOverridesMu.Unlock()
+	result := make([]LevelOverride, 0, len(levelOverrides))
+	for _, o := range levelOverrides {
+		result = append(result, o.LevelOverride)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Module < result[j].Module })
+	return result
+}
+
+// GetLevelOverride 获取模块（为空表示全局）进行中的临时调整
+func GetLevelOverride(module string) (LevelOverride, bool) {
+	levelOverridesMu.Lock()
+	defer levelOverridesMu.Unlock()
+	if o, ok := levelOverrides[module]; ok {
+		return o.LevelOverride, true
+	}
+	return LevelOverride{}, false
+}
+
+func levelDescription(level Level, ok bool) string {
+	if !ok {
+		return "unset"
+	}
+	return level.String()
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetModuleLevelWithTTLRevertsAfterTTL(t *testing.T) {
+	defer ClearModuleLevel("override-test")
+	SetModuleLevel("override-test", LevelWarn)
+
+	SetModuleLevelWithTTL("override-test", LevelDebug, 50*time.Millisecond)
+	SetModuleLevelWithTTL("override-test", LevelInfo, 50*time.Millisecond)
+	override, ok := GetLevelOverride("override-test")
+	if !ok || override.Level != LevelInfo || override.Previous != LevelWarn || !override.HasPrevious {
+		t.Fatalf("unexpected override: %+v, %v", override, ok)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if level, _ := GetModuleLevel("override-test"); level == LevelWarn {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected module level to revert to warn")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, ok := GetLevelOverride("override-test"); ok {
+		t.Fatal("expected override to be removed after revert")
+	}
+}
+
+func TestSetModuleLevelWithoutTTLCancelsRevert(t *testing.T) {
+	defer ClearModuleLevel("override-permanent")
+
+	SetModuleLevelWithTTL("override-permanent", LevelDebug, time.Hour)
+	SetModuleLevelWithTTL("override-permanent", LevelError, 0)
+	if len(LevelOverrides()) != 0 {
+		t.Fatalf("expected no pending overrides, got %+v", LevelOverrides())
+	}
+	if level, ok := GetModuleLevel("override-permanent"); !ok || level != LevelError {
+		t.Fatalf("expected permanent error level, got %v, %v", level, ok)
+	}
+}
+
+func TestCycleLevelAndRevertOverrides(t *testing.T) {
+	log, _ := NewTestLogger()
+	log.SetLevel(LevelWarn)
+	defer ReplaceDefault(log)()
+	defer ClearModuleLevel("override-cycle")
+
+	if got := CycleLevel(time.Hour); got != LevelInfo {
+		t.Fatalf("expected info after first cycle, got %s", got)
+	}
+	if got := CycleLevel(time.Hour); got != LevelDebug {
+		t.Fatalf("expected debug after second cycle, got %s", got)
+	}
+	if override, ok := GetLevelOverride(""); !ok || override.Previous != LevelWarn {
+		t.Fatalf("expected global override from warn, got %+v, %v", override, ok)
+	}
+	if got := CycleLevel(time.Hour); got != LevelWarn || log.GetLevel() != LevelWarn {
+		t.Fatalf("expected cycle to wrap back to warn, got %s", got)
+	}
+	if _, ok := GetLevelOverride(""); ok {
+		t.Fatal("expected global override to be cleared after wrap")
+	}
+
+	CycleLevel(time.Hour)
+	SetModuleLevelWithTTL("override-cycle", LevelDebug, time.Hour)
+	if count := RevertLevelOverrides(); count != 2 {
+		t.Fatalf("expected 2 reverted overrides, got %d", count)
+	}
+	if _, ok := GetModuleLevel("override-cycle"); ok || log.GetLevel() != LevelWarn {
+		t.Fatalf("expected levels restored, global=%s", log.GetLevel())
+	}
+}
@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultPayloadLoggingTTL 运行时开启载荷日志的默认持续时间
+	DefaultPayloadLoggingTTL = 10 * time.Minute
+	// MaxPayloadLoggingTTL 运行时开启载荷日志的最长持续时间，避免遗忘关闭导致生产环境长期输出载荷
+	MaxPayloadLoggingTTL = 24 * time.Hour
+)
+
+// PayloadLoggingTarget 运行时开启的载荷日志目标
+type PayloadLoggingTarget struct {
+	// gRPC 方法全名（如 /auth.AuthService/Login）或 HTTP 路由（如 /api/users/:id、POST /api/login），支持 * 通配符
+	Target string `json:"target"`
+	// 到期时间
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// payloadTarget 已开启的目标
+type payloadTarget struct {
+	expiresAt time.Time
+	timer     *time.Timer
+	// 开启前的模块日志级别（仅精确目标会临时将模块级别调整为 debug）
+	boosted  bool
+	previous *Level
+}
+
+var (
+	payloadTargets   = make(map[string]*payloadTarget)
+	payloadTargetsMu sync.RWMutex
+	// payloadTargetCount 当前开启的目标数量，为 0 时跳过查表
+	payloadTargetCount atomic.Int32
+	payloadNow         = time.Now
+)
+
+// EnablePayloadLogging 在 ttl 内为 gRPC 方法或 HTTP 路由输出请求/响应载荷日志（脱敏、截断），到期自动关闭
+// ttl <= 0 时使用 DefaultPayloadLoggingTTL，超过 MaxPayloadLoggingTTL 时按上限处理；重复开启时重新计时
+// 精确目标（不含通配符）同时临时将该模块日志级别调整为 debug，到期后恢复
+func EnablePayloadLogging(target string, ttl time.Duration) PayloadLoggingTarget {
+	target = strings.TrimSpace(target)
+	if ttl <= 0 {
+		ttl = DefaultPayloadLoggingTTL
+	}
+	if ttl > MaxPayloadLoggingTTL {
+		ttl = MaxPayloadLoggingTTL
+	}
+
+	payloadTargetsMu.Lock()
+	defer payloadTargetsMu.Unlock()
+
+	t, ok := payloadTargets[target]
+	if ok {
+		t.timer.Stop()
+	} else {
+		t = &payloadTarget{}
+		if !strings.ContainsAny(target, "*?[") {
+			if previous, exists := GetModuleLevel(target); exists {
+				t.previous = &previous
+			}
+			t.boosted = true
+			SetModuleLevel(target, LevelDebug)
+		}
+		payloadTargets[target] = t
+		payloadTargetCount.Store(int32(len(payloadTargets)))
+	}
+	expiresAt := payloadNow().Add(ttl)
+	t.expiresAt = expiresAt
+	t.timer = time.AfterFunc(ttl, func() { expirePayloadLogging(target, t, expiresAt) })
+
+	Warn(context.Background(), "Payload logging enabled: target=%s, ttl=%s", target, ttl)
+	return PayloadLoggingTarget{Target: target, ExpiresAt: t.expiresAt}
+}
+
+// DisablePayloadLogging 立即关闭目标的载荷日志，返回目标此前是否已开启
+func DisablePayloadLogging(target string) bool {
+	target = strings.TrimSpace(target)
+	payloadTargetsMu.Lock()
+	defer payloadTargetsMu.Unlock()
+	t, ok := payloadTargets[target]
+	if !ok {
+		return false
+	}
+	t.timer.Stop()
+	removePayloadTargetLocked(target, t)
+	return true
+}
+
+// expirePayloadLogging 到期关闭目标（目标已被重新开启并顺延时忽略旧的计时器）
+func expirePayloadLogging(target string, t *payloadTarget, expiresAt time.Time) {
+	payloadTargetsMu.Lock()
+	defer payloadTargetsMu.Unlock()
+	if payloadTargets[target] != t || !t.expiresAt.Equal(expiresAt) {
+		return
+	}
+	removePayloadTargetLocked(target, t)
+}
+
+// removePayloadTargetLocked 移除目标并恢复模块日志级别
+func removePayloadTargetLocked(target string, t *payloadTarget) {
+	delete(payloadTargets, target)
+	payloadTargetCount.Store(int32(len(payloadTargets)))
+	if t.boosted {
+		if t.previous != nil {
+			SetModuleLevel(target, *t.previous)
+		} else {
+			ClearModuleLevel(target)
+		}
+	}
+	Info(context.Background(), "Payload logging disabled: target=%s", target)
+}
+
+// PayloadLoggingEnabled 判断任一名称（如 HTTP 路由与 "方法 路由"）是否开启了载荷日志，精确匹配优先于通配符匹配
+func PayloadLoggingEnabled(names ...string) bool {
+	if payloadTargetCount.Load() == 0 {
+		return false
+	}
+	payloadTargetsMu.RLock()
+	defer payloadTargetsMu.RUnlock()
+	for _, name := range names {
+		if _, ok := payloadTargets[name]; ok {
+			return true
+		}
+	}
+	for pattern := range payloadTargets {
+		for _, name := range names {
+			if matched, _ := path.Match(pattern, name); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PayloadLoggingTargets 返回当前开启的载荷日志目标（按目标名称排序）
+func PayloadLoggingTargets() []PayloadLoggingTarget {
+	payloadTargetsMu.RLock()
+	defer payloadTargetsMu.RUnlock()
+	targets := make([]PayloadLoggingTarget, 0, len(payloadTargets))
+	for target, t := range payloadTargets {
+		targets = append(targets, PayloadLoggingTarget{Target: target, ExpiresAt: t.expiresAt})
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Target < targets[j].Target })
+	return targets
+}
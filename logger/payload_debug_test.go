@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPayloadLoggingEnableExpireAndRestoreLevel(t *testing.T) {
+	const method = "/auth.AuthService/Login"
+	SetModuleLevel(method, LevelWarn)
+	defer ClearModuleLevel(method)
+
+	target := EnablePayloadLogging(method, 50*time.Millisecond)
+	if target.Target != method || target.ExpiresAt.IsZero() {
+		t.Fatalf("unexpected target: %+v", target)
+	}
+	if !PayloadLoggingEnabled(method) || PayloadLoggingEnabled("/auth.AuthService/Logout") {
+		t.Fatal("expected payload logging only for the enabled method")
+	}
+	if level, _ := GetModuleLevel(method); level != LevelDebug {
+		t.Fatalf("expected module level debug while enabled, got %v", level)
+	}
+	if targets := PayloadLoggingTargets(); len(targets) != 1 || targets[0].Target != method {
+		t.Fatalf("unexpected targets: %+v", targets)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for PayloadLoggingEnabled(method) {
+		if time.Now().After(deadline) {
+			t.Fatal("payload logging did not expire")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if level, ok := GetModuleLevel(method); !ok || level != LevelWarn {
+		t.Fatalf("expected previous module level restored, got %v (%v)", level, ok)
+	}
+}
+
+func TestPayloadLoggingWildcardAndDisable(t *testing.T) {
+	EnablePayloadLogging("/user.UserService/*", time.Minute)
+	EnablePayloadLogging("POST /api/login", time.Minute)
+	defer DisablePayloadLogging("POST /api/login")
+
+	if !PayloadLoggingEnabled("/user.UserService/Get") {
+		t.Fatal("expected wildcard target to match")
+	}
+	if _, ok := GetModuleLevel("/user.UserService/*"); ok {
+		t.Fatal("wildcard targets must not set a module level")
+	}
+	if !PayloadLoggingEnabled("/api/login", "POST /api/login") || PayloadLoggingEnabled("/api/login", "GET /api/login") {
+		t.Fatal("expected route target to match method and route")
+	}
+
+	if !DisablePayloadLogging("/user.UserService/*") || DisablePayloadLogging("/user.UserService/*") {
+		t.Fatal("expected disable to report whether the target was enabled")
+	}
+	if PayloadLoggingEnabled("/user.UserService/Get") {
+		t.Fatal("expected disabled target to stop matching")
+	}
+}
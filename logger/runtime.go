@@ -0,0 +1,35 @@
+package logger
+
+import "os"
+
+// Kubernetes Pod 信息的环境变量（通常通过 Downward API 注入），按顺序取第一个非空值
+var (
+	PodNameEnvVars      = []string{"POD_NAME", "K8S_POD_NAME"}
+	PodNamespaceEnvVars = []string{"POD_NAMESPACE", "K8S_NAMESPACE"}
+)
+
+// RuntimeFields 返回当前进程的运行环境字段：hostname、pid，
+// 以及设置了对应环境变量时的 pod_name、namespace
+func RuntimeFields() Fields {
+	fields := NewFields()
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		fields[FieldHostname] = hostname
+	}
+	fields[FieldPID] = os.Getpid()
+	if pod := lookupEnv(PodNameEnvVars); pod != "" {
+		fields[FieldPodName] = pod
+	}
+	if namespace := lookupEnv(PodNamespaceEnvVars); namespace != "" {
+		fields[FieldNamespace] = namespace
+	}
+	return fields
+}
+
+func lookupEnv(names []string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
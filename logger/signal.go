@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/team-dandelion/quickgo/types"
+)
+
+// DynamicLevelConfig 运行时日志级别调整配置（管理接口与信号）
+type DynamicLevelConfig struct {
+	// 临时调整的有效期，到期自动恢复调整前的级别，示例：15m（默认 15m；管理接口请求可通过 ttl 覆盖）
+	TTL types.Duration `json:"ttl" yaml:"ttl" toml:"ttl"`
+	// 是否监听 SIGUSR1（全局级别降低一级）与 SIGHUP（恢复所有临时调整），仅 Unix
+	Signals bool `json:"signals" yaml:"signals" toml:"signals"`
+}
+
+// DefaultDynamicLevelTTL 临时日志级别调整的默认有效期
+const DefaultDynamicLevelTTL = 15 * time.Minute
+
+// CycleLevel 将全局级别降低一级（如 info → debug）并在 ttl 后恢复；已是 debug 时恢复调整前的级别，返回调整后的级别
+func CycleLevel(ttl time.Duration) Level {
+	current := GetDefault().GetLevel()
+	if current > LevelDebug {
+		next := current - 1
+		SetLevelWithTTL(next, ttl)
+		Warn(context.Background(), "Log level lowered by signal: level=%s, ttl=%s", next, ttl)
+		return next
+	}
+	override, ok := GetLevelOverride("")
+	if !ok {
+		return current
+	}
+	SetLevelWithTTL(override.Previous, 0)
+	Warn(context.Background(), "Log level restored by signal: level=%s", override.Previous)
+	return override.Previous
+}
+
+// HandleLevelSignals 监听日志级别信号：CycleLevelSignal 调用 CycleLevel，ReloadLevelSignal 立即恢复所有临时调整。
+// 返回停止监听的函数；当前平台不支持时不监听
+func HandleLevelSignals(ttl time.Duration) (stop func()) {
+	signals := make([]os.Signal, 0, 2)
+	for _, sig := range []os.Signal{CycleLevelSignal, ReloadLevelSignal} {
+		if sig != nil {
+			signals = append(signals, sig)
+		}
+	}
+	if len(signals) == 0 {
+		return func() {}
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigChan, signals...)
+	go func() {
+		for {
+			select {
+			case sig := <-sigChan:
+				if sig == CycleLevelSignal {
+					CycleLevel(ttl)
+					continue
+				}
+				count := RevertLevelOverrides()
+				Warn(context.Background(), "Log level overrides reverted by signal: count=%d, level=%s", count, GetDefault().GetLevel())
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(sigChan)
+		close(done)
+	}
+}
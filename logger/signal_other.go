@@ -0,0 +1,11 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package logger
+
+import "os"
+
+// 当前平台不支持信号调整日志级别
+var (
+	CycleLevelSignal  os.Signal
+	ReloadLevelSignal os.Signal
+)
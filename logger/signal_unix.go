@@ -0,0 +1,15 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+var (
+	// CycleLevelSignal 将全局日志级别降低一级的信号
+	CycleLevelSignal os.Signal = syscall.SIGUSR1
+	// ReloadLevelSignal 恢复所有临时日志级别调整的信号
+	ReloadLevelSignal os.Signal = syscall.SIGHUP
+)
@@ -0,0 +1,178 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// TestSink 内存日志接收器，记录 Logger 输出的条目，供测试按级别、消息、字段与 trace_id 查询
+type TestSink struct {
+	mu      sync.Mutex
+	entries []LogEntry
+}
+
+// NewTestLogger 创建写入内存的日志记录器（debug 级别，不输出到控制台或文件），返回记录器与接收器：
+//
+//	log, sink := logger.NewTestLogger()
+//	defer logger.ReplaceDefault(log)()
+//	... 调用被测代码 ...
+//	sink.AssertLogged(t, logger.MatchLevel(logger.LevelError), logger.MatchMessage("payment failed"))
+func NewTestLogger() (*Logger, *TestSink) {
+	sink := &TestSink{}
+	return &Logger{
+		level:  LevelDebug,
+		fields: make(map[string]interface{}),
+		sink:   sink,
+	}, sink
+}
+
+// ReplaceDefault 替换默认日志记录器，返回恢复原记录器的函数（用于测试：defer logger.ReplaceDefault(log)()）
+func ReplaceDefault(l *Logger) func() {
+	defaultMu.Lock()
+	previous := defaultLogger
+	defaultLogger = l
+	defaultMu.Unlock()
+
+	return func() {
+		defaultMu.Lock()
+		defer defaultMu.Unlock()
+		defaultLogger = previous
+	}
+}
+
+// Record 记录一个条目（NewTestLogger 创建的记录器自动调用，也可作为 AddEntryHook 的钩子捕获所有 Logger）
+func (s *TestSink) Record(entry LogEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+}
+
+// Entries 返回已记录的条目
+func (s *TestSink) Entries() []LogEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]LogEntry(nil), s.entries...)
+}
+
+// Reset 清除已记录的条目
+func (s *TestSink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = nil
+}
+
+// Find 返回满足全部匹配条件的条目（不指定条件时返回全部）
+func (s *TestSink) Find(matchers ...EntryMatcher) []LogEntry {
+	var found []LogEntry
+	for _, entry := range s.Entries() {
+		if matchEntry(entry, matchers) {
+			found = append(found, entry)
+		}
+	}
+	return found
+}
+
+// Count 返回满足全部匹配条件的条目数
+func (s *TestSink) Count(matchers ...EntryMatcher) int {
+	return len(s.Find(matchers...))
+}
+
+// TestingT 断言所需的 testing.TB 子集
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertLogged 断言至少有一个条目满足全部匹配条件
+func (s *TestSink) AssertLogged(t TestingT, matchers ...EntryMatcher) {
+	t.Helper()
+	if s.Count(matchers...) == 0 {
+		t.Errorf("logger: expected entry matching %s, got none in %d entries:\n%s", describeMatchers(matchers), len(s.Entries()), s.dump())
+	}
+}
+
+// AssertNotLogged 断言没有条目满足全部匹配条件
+func (s *TestSink) AssertNotLogged(t TestingT, matchers ...EntryMatcher) {
+	t.Helper()
+	if found := s.Find(matchers...); len(found) > 0 {
+		t.Errorf("logger: expected no entry matching %s, got %d: %+v", describeMatchers(matchers), len(found), found)
+	}
+}
+
+// dump 返回便于阅读的条目列表（断言失败时输出）
+func (s *TestSink) dump() string {
+	var b strings.Builder
+	for _, entry := range s.Entries() {
+		fmt.Fprintf(&b, "  [%s] %s", entry.Level, entry.Message)
+		if len(entry.Fields) > 0 {
+			fmt.Fprintf(&b, " %v", entry.Fields)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// EntryMatcher 条目匹配条件
+type EntryMatcher struct {
+	desc  string
+	match func(entry LogEntry) bool
+}
+
+// MatchLevel 匹配日志级别
+func MatchLevel(level Level) EntryMatcher {
+	name := levelNames[level]
+	return EntryMatcher{desc: "level=" + level.String(), match: func(entry LogEntry) bool {
+		return entry.Level == name
+	}}
+}
+
+// MatchMessage 匹配消息中包含 substr 的条目
+func MatchMessage(substr string) EntryMatcher {
+	return EntryMatcher{desc: fmt.Sprintf("message~%q", substr), match: func(entry LogEntry) bool {
+		return strings.Contains(entry.Message, substr)
+	}}
+}
+
+// MatchField 匹配字段值（按 fmt.Sprint 结果比较，int 与 int64 等数值视为相等）
+func MatchField(key string, value interface{}) EntryMatcher {
+	want := fmt.Sprint(value)
+	return EntryMatcher{desc: fmt.Sprintf("%s=%v", key, value), match: func(entry LogEntry) bool {
+		got, ok := entry.Fields[key]
+		return ok && fmt.Sprint(got) == want
+	}}
+}
+
+// MatchTraceID 匹配 trace_id
+func MatchTraceID(traceID string) EntryMatcher {
+	return EntryMatcher{desc: "trace_id=" + traceID, match: func(entry LogEntry) bool {
+		return entry.TraceID == traceID
+	}}
+}
+
+// MatchError 匹配错误信息中包含 substr 的条目
+func MatchError(substr string) EntryMatcher {
+	return EntryMatcher{desc: fmt.Sprintf("error~%q", substr), match: func(entry LogEntry) bool {
+		return entry.Error != "" && strings.Contains(entry.Error, substr)
+	}}
+}
+
+func matchEntry(entry LogEntry, matchers []EntryMatcher) bool {
+	for _, matcher := range matchers {
+		if matcher.match != nil && !matcher.match(entry) {
+			return false
+		}
+	}
+	return true
+}
+
+func describeMatchers(matchers []EntryMatcher) string {
+	if len(matchers) == 0 {
+		return "{any}"
+	}
+	descs := make([]string, 0, len(matchers))
+	for _, matcher := range matchers {
+		descs = append(descs, matcher.desc)
+	}
+	return "{" + strings.Join(descs, ", ") + "}"
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// recordingT 记录断言失败信息
+type recordingT struct {
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestTestLoggerRecordsEntries(t *testing.T) {
+	log, sink := NewTestLogger()
+	ctx := WithTrace(context.Background(), "trace-1", "span-1")
+
+	log.Debug(ctx, "loading order %d", 42)
+	log.WithFields(map[string]interface{}{"order_id": int64(42), "user": "u1"}).Error(ctx, "payment failed: %v", errors.New("card declined"))
+	log.Info(context.Background(), "unrelated")
+
+	if got := len(sink.Entries()); got != 3 {
+		t.Fatalf("expected 3 entries, got %d", got)
+	}
+	found := sink.Find(MatchLevel(LevelError), MatchField("order_id", 42), MatchTraceID("trace-1"))
+	if len(found) != 1 || found[0].Message != "payment failed: card declined" || found[0].SpanID != "span-1" {
+		t.Fatalf("unexpected entries: %+v", found)
+	}
+	if sink.Count(MatchMessage("loading order 42"), MatchLevel(LevelDebug)) != 1 {
+		t.Fatal("expected debug entry")
+	}
+	if sink.Count(MatchTraceID("trace-1")) != 2 {
+		t.Fatal("expected 2 entries with trace-1")
+	}
+
+	sink.AssertLogged(t, MatchLevel(LevelError), MatchError("card declined"), MatchField("user", "u1"))
+	sink.AssertNotLogged(t, MatchLevel(LevelWarn))
+
+	rt := &recordingT{}
+	sink.AssertLogged(rt, MatchLevel(LevelWarn), MatchMessage("missing"))
+	sink.AssertNotLogged(rt, MatchLevel(LevelInfo))
+	if len(rt.errors) != 2 || !strings.Contains(rt.errors[0], `level=warn, message~"missing"`) || !strings.Contains(rt.errors[0], "[ERROR] payment failed") {
+		t.Fatalf("unexpected assertion failures: %q", rt.errors)
+	}
+
+	sink.Reset()
+	if len(sink.Entries()) != 0 {
+		t.Fatal("expected Reset to clear entries")
+	}
+}
+
+func TestReplaceDefaultRestoresPrevious(t *testing.T) {
+	previous := GetDefault()
+	log, sink := NewTestLogger()
+	restore := ReplaceDefault(log)
+
+	Warn(context.Background(), "through global %s", "logger")
+	if GetDefault() != log {
+		t.Fatal("expected test logger to be the default")
+	}
+	restore()
+
+	if GetDefault() != previous {
+		t.Fatal("expected previous default to be restored")
+	}
+	sink.AssertLogged(t, MatchLevel(LevelWarn), MatchMessage("through global logger"))
+}
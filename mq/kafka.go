@@ -0,0 +1,253 @@
+package mq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// KafkaDriver Kafka 驱动
+type KafkaDriver struct {
+	config *KafkaConfig
+	writer *kafka.Writer
+	dialer *kafka.Dialer
+
+	mu      sync.Mutex
+	readers []*kafka.Reader
+
+	// deadLetter 转发死信消息（默认通过 Publish 发送）
+	deadLetter func(ctx context.Context, msg *Message) error
+}
+
+const (
+	// HeaderDeadLetterTopic 死信消息的原始 topic
+	HeaderDeadLetterTopic = "x-dead-letter-topic"
+	// HeaderDeadLetterError 死信消息最后一次处理失败的错误
+	HeaderDeadLetterError = "x-dead-letter-error"
+
+	defaultKafkaMaxRetries   = 3
+	defaultKafkaRetryBackoff = time.Second
+)
+
+// NewKafkaDriver 创建 Kafka 驱动
+func NewKafkaDriver(config *KafkaConfig) (*KafkaDriver, error) {
+	if config == nil {
+		return nil, errors.New("kafka config is nil")
+	}
+	if len(config.Brokers) == 0 {
+		return nil, errors.New("kafka brokers are required")
+	}
+
+	requiredAcks := kafka.RequireAll
+	switch config.RequiredAcks {
+	case "", "all":
+	case "none":
+		requiredAcks = kafka.RequireNone
+	case "leader":
+		requiredAcks = kafka.RequireOne
+	default:
+		return nil, fmt.Errorf("invalid kafka requiredAcks: %s", config.RequiredAcks)
+	}
+
+	switch config.StartOffset {
+	case "", "latest", "earliest":
+	default:
+		return nil, fmt.Errorf("invalid kafka startOffset: %s", config.StartOffset)
+	}
+
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(config.Brokers...),
+		Balancer:     &kafka.Hash{},
+		BatchTimeout: config.BatchTimeout.OrDefault(10 * time.Millisecond),
+		RequiredAcks: requiredAcks,
+	}
+	dialer := &kafka.Dialer{
+		Timeout:   10 * time.Second,
+		DualStack: true,
+	}
+	if config.ClientID != "" {
+		writer.Transport = &kafka.Transport{ClientID: config.ClientID}
+		dialer.ClientID = config.ClientID
+	}
+
+	d := &KafkaDriver{
+		config: config,
+		writer: writer,
+		dialer: dialer,
+	}
+	d.deadLetter = d.Publish
+	return d, nil
+}
+
+// Name 驱动名称
+func (d *KafkaDriver) Name() string {
+	return DriverKafka
+}
+
+// Publish 发送消息
+func (d *KafkaDriver) Publish(ctx context.Context, msg *Message) error {
+	headers := make([]kafka.Header, 0, len(msg.Headers))
+	for k, v := range msg.Headers {
+		headers = append(headers, kafka.Header{Key: k, Value: []byte(v)})
+	}
+	return d.writer.WriteMessages(ctx, kafka.Message{
+		Topic:   msg.Topic,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+		Time:    msg.Timestamp,
+	})
+}
+
+// Consume 消费消息
+// 并发数对应同一消费组内的 reader 数量，每个 reader 顺序处理并在处理成功（或转入死信 topic）后提交 offset，保证分区内有序
+func (d *KafkaDriver) Consume(ctx context.Context, sub Subscription, handler Handler) error {
+	if sub.Group == "" {
+		return fmt.Errorf("kafka consumer group is required for topic %s", sub.Topic)
+	}
+
+	startOffset := kafka.LastOffset
+	if d.config.StartOffset == "earliest" {
+		startOffset = kafka.FirstOffset
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < sub.Concurrency; i++ {
+		reader := kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     d.config.Brokers,
+			GroupID:     sub.Group,
+			Topic:       sub.Topic,
+			StartOffset: startOffset,
+			Dialer:      d.dialer,
+		})
+		d.mu.Lock()
+		d.readers = append(d.readers, reader)
+		d.mu.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer reader.Close()
+			d.consumeLoop(ctx, reader, handler)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+func (d *KafkaDriver) consumeLoop(ctx context.Context, reader *kafka.Reader, handler Handler) {
+	for {
+		m, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Error(ctx, "Kafka fetch message failed: topic=%s, error=%v", reader.Config().Topic, err)
+			}
+			return
+		}
+
+		headers := make(map[string]string, len(m.Headers))
+		for _, h := range m.Headers {
+			headers[h.Key] = string(h.Value)
+		}
+
+		msg := &Message{
+			Topic:     m.Topic,
+			Key:       m.Key,
+			Value:     m.Value,
+			Headers:   headers,
+			Timestamp: m.Time,
+		}
+		// 未处理成功的消息不提交 offset，重启或重平衡后重新投递
+		if !d.handleMessage(ctx, msg, handler) {
+			return
+		}
+
+		commitCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := reader.CommitMessages(commitCtx, m); err != nil {
+			logger.Error(commitCtx, "Kafka commit message failed: topic=%s, partition=%d, offset=%d, error=%v",
+				m.Topic, m.Partition, m.Offset, err)
+		}
+		cancel()
+	}
+}
+
+// handleMessage 处理消息，成功或已转入死信 topic 时返回 true
+// 失败后按 RetryBackoff 间隔重试，超过 MaxRetries 且配置了死信 topic 时转发；未配置时持续重试直到成功或收到停止信号
+func (d *KafkaDriver) handleMessage(ctx context.Context, msg *Message, handler Handler) bool {
+	maxRetries := d.config.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultKafkaMaxRetries
+	}
+	backoff := d.config.RetryBackoff.OrDefault(defaultKafkaRetryBackoff)
+
+	for attempt := 1; ; attempt++ {
+		// 处理中的消息不受停止信号影响，保证优雅关闭时能够处理完成
+		err := handler(context.Background(), msg)
+		if err == nil {
+			return true
+		}
+		logger.Error(ctx, "Kafka handle message failed: topic=%s, attempt=%d, error=%v", msg.Topic, attempt, err)
+
+		if attempt > maxRetries && d.config.DeadLetterTopic != "" {
+			if dlqErr := d.sendDeadLetter(msg, err); dlqErr != nil {
+				logger.Error(ctx, "Kafka send dead letter failed: topic=%s, deadLetterTopic=%s, error=%v",
+					msg.Topic, d.config.DeadLetterTopic, dlqErr)
+			} else {
+				logger.Warn(ctx, "Kafka message moved to dead letter topic: topic=%s, deadLetterTopic=%s, attempts=%d",
+					msg.Topic, d.config.DeadLetterTopic, attempt)
+				return true
+			}
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+	}
+}
+
+// sendDeadLetter 转发消息到死信 topic，保留原始 key 与 header 并附带原始 topic 和错误
+func (d *KafkaDriver) sendDeadLetter(msg *Message, cause error) error {
+	headers := make(map[string]string, len(msg.Headers)+2)
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	headers[HeaderDeadLetterTopic] = msg.Topic
+	headers[HeaderDeadLetterError] = cause.Error()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return d.deadLetter(ctx, &Message{
+		Topic:     d.config.DeadLetterTopic,
+		Key:       msg.Key,
+		Value:     msg.Value,
+		Headers:   headers,
+		Timestamp: msg.Timestamp,
+	})
+}
+
+// Close 关闭驱动
+func (d *KafkaDriver) Close() error {
+	d.mu.Lock()
+	readers := d.readers
+	d.readers = nil
+	d.mu.Unlock()
+
+	var errs []error
+	for _, reader := range readers {
+		_ = reader.Close()
+	}
+	if err := d.writer.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("close kafka writer: %w", err))
+	}
+	return errors.Join(errs...)
+}
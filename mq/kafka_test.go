@@ -0,0 +1,102 @@
+package mq
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/team-dandelion/quickgo/types"
+)
+
+func newTestKafkaDriver(config *KafkaConfig, deadLetters chan *Message) *KafkaDriver {
+	return &KafkaDriver{
+		config: config,
+		deadLetter: func(ctx context.Context, msg *Message) error {
+			deadLetters <- msg
+			return nil
+		},
+	}
+}
+
+func TestKafkaHandleMessageRetriesUntilSuccess(t *testing.T) {
+	deadLetters := make(chan *Message, 1)
+	d := newTestKafkaDriver(&KafkaConfig{MaxRetries: 3, RetryBackoff: types.Duration(time.Millisecond), DeadLetterTopic: "orders.dlq"}, deadLetters)
+
+	attempts := 0
+	ok := d.handleMessage(context.Background(), &Message{Topic: "orders"}, func(ctx context.Context, msg *Message) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("temporary")
+		}
+		return nil
+	})
+	if !ok {
+		t.Fatal("expected message to be handled")
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+	if len(deadLetters) != 0 {
+		t.Fatal("expected no dead letter for a message that eventually succeeded")
+	}
+}
+
+func TestKafkaHandleMessageSendsDeadLetterAfterRetries(t *testing.T) {
+	deadLetters := make(chan *Message, 1)
+	d := newTestKafkaDriver(&KafkaConfig{MaxRetries: 2, RetryBackoff: types.Duration(time.Millisecond), DeadLetterTopic: "orders.dlq"}, deadLetters)
+
+	attempts := 0
+	ok := d.handleMessage(context.Background(), &Message{Topic: "orders", Key: []byte("k1"), Value: []byte("v1"), Headers: map[string]string{"a": "b"}},
+		func(ctx context.Context, msg *Message) error {
+			attempts++
+			return errors.New("boom")
+		})
+	if !ok {
+		t.Fatal("expected message to be committed after moving to the dead letter topic")
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 1 attempt plus 2 retries, got %d", attempts)
+	}
+
+	msg := <-deadLetters
+	if msg.Topic != "orders.dlq" || string(msg.Key) != "k1" || string(msg.Value) != "v1" {
+		t.Fatalf("unexpected dead letter: %+v", msg)
+	}
+	if msg.Headers["a"] != "b" || msg.Headers[HeaderDeadLetterTopic] != "orders" || msg.Headers[HeaderDeadLetterError] != "boom" {
+		t.Fatalf("unexpected dead letter headers: %v", msg.Headers)
+	}
+}
+
+func TestKafkaHandleMessageWithoutDeadLetterDoesNotCommit(t *testing.T) {
+	deadLetters := make(chan *Message, 1)
+	d := newTestKafkaDriver(&KafkaConfig{MaxRetries: 1, RetryBackoff: types.Duration(time.Millisecond)}, deadLetters)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	ok := d.handleMessage(ctx, &Message{Topic: "orders"}, func(context.Context, *Message) error {
+		attempts++
+		if attempts == 5 {
+			cancel()
+		}
+		return errors.New("boom")
+	})
+	if ok {
+		t.Fatal("expected failed message not to be committed")
+	}
+	if attempts != 5 {
+		t.Fatalf("expected retries to continue until stopped, got %d attempts", attempts)
+	}
+	if len(deadLetters) != 0 {
+		t.Fatal("expected no dead letter without a dead letter topic")
+	}
+}
+
+func TestRabbitMQRejectsUnroutableQueueOnDefaultExchange(t *testing.T) {
+	d := &RabbitMQDriver{config: &RabbitMQConfig{}}
+	err := d.Consume(context.Background(), Subscription{Topic: "orders", Group: "billing", Concurrency: 1}, func(context.Context, *Message) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "default exchange") {
+		t.Fatalf("expected default exchange error, got %v", err)
+	}
+}
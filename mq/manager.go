@@ -0,0 +1,232 @@
+package mq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// subscription 已注册的订阅
+type subscription struct {
+	sub     Subscription
+	handler Handler
+}
+
+// Manager 消息队列管理器，统一管理生产与消费
+type Manager struct {
+	config       *Config
+	driver       Driver
+	drainTimeout time.Duration
+
+	mu            sync.Mutex
+	subscriptions []subscription
+	started       bool
+	closed        bool
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+// NewManager 根据配置创建消息队列管理器
+func NewManager(config *Config) (*Manager, error) {
+	if config == nil {
+		return nil, errors.New("config is nil")
+	}
+
+	var (
+		driver Driver
+		err    error
+	)
+	switch config.Driver {
+	case DriverKafka:
+		driver, err = NewKafkaDriver(config.Kafka)
+	case DriverRabbitMQ:
+		driver, err = NewRabbitMQDriver(config.RabbitMQ)
+	default:
+		return nil, fmt.Errorf("unsupported mq driver: %s", config.Driver)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s driver: %w", config.Driver, err)
+	}
+
+	manager, err := NewManagerWithDriver(config, driver)
+	if err != nil {
+		_ = driver.Close()
+		return nil, err
+	}
+	return manager, nil
+}
+
+// NewManagerWithDriver 使用自定义驱动创建消息队列管理器
+func NewManagerWithDriver(config *Config, driver Driver) (*Manager, error) {
+	if config == nil {
+		return nil, errors.New("config is nil")
+	}
+	if driver == nil {
+		return nil, errors.New("mq driver is nil")
+	}
+
+	return &Manager{
+		config:       config,
+		driver:       driver,
+		drainTimeout: config.DrainTimeout.OrDefault(30 * time.Second),
+	}, nil
+}
+
+// Driver 获取底层驱动
+func (m *Manager) Driver() Driver {
+	return m.driver
+}
+
+// Publish 发送消息，自动将 context 中的 trace 信息写入消息头
+func (m *Manager) Publish(ctx context.Context, msg *Message) error {
+	if msg == nil {
+		return errors.New("message is nil")
+	}
+	if msg.Topic == "" {
+		return errors.New("message topic is required")
+	}
+
+	m.mu.Lock()
+	closed := m.closed
+	m.mu.Unlock()
+	if closed {
+		return errors.New("mq manager is closed")
+	}
+
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+	injectTrace(ctx, msg)
+
+	if err := m.driver.Publish(ctx, msg); err != nil {
+		logger.Error(ctx, "MQ publish failed: driver=%s, topic=%s, error=%v", m.driver.Name(), msg.Topic, err)
+		return fmt.Errorf("failed to publish message to %s: %w", msg.Topic, err)
+	}
+	return nil
+}
+
+// Subscribe 注册订阅（需在 Start 之前调用）
+func (m *Manager) Subscribe(sub Subscription, handler Handler) error {
+	if sub.Topic == "" {
+		return errors.New("subscription topic is required")
+	}
+	if handler == nil {
+		return fmt.Errorf("handler for topic %s is nil", sub.Topic)
+	}
+	if sub.Group == "" {
+		sub.Group = m.config.Group
+	}
+	if sub.Concurrency <= 0 {
+		sub.Concurrency = m.config.Concurrency
+	}
+	if sub.Concurrency <= 0 {
+		sub.Concurrency = 1
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.started {
+		return errors.New("cannot subscribe after mq manager started")
+	}
+	m.subscriptions = append(m.subscriptions, subscription{sub: sub, handler: handler})
+	return nil
+}
+
+// Start 启动所有订阅的消费
+func (m *Manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return errors.New("mq manager is closed")
+	}
+	if m.started {
+		return errors.New("mq manager already started")
+	}
+
+	consumeCtx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.started = true
+
+	for _, s := range m.subscriptions {
+		s := s
+		handler := m.wrapHandler(s.sub, s.handler)
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			if err := m.driver.Consume(consumeCtx, s.sub, handler); err != nil {
+				logger.Error(ctx, "MQ consumer stopped with error: driver=%s, topic=%s, group=%s, error=%v",
+					m.driver.Name(), s.sub.Topic, s.sub.Group, err)
+			}
+		}()
+		logger.Info(ctx, "MQ consumer started: driver=%s, topic=%s, group=%s, concurrency=%d",
+			m.driver.Name(), s.sub.Topic, s.sub.Group, s.sub.Concurrency)
+	}
+	return nil
+}
+
+// wrapHandler 为处理函数添加 trace 恢复、panic 恢复与日志
+func (m *Manager) wrapHandler(sub Subscription, handler Handler) Handler {
+	return func(ctx context.Context, msg *Message) (err error) {
+		ctx = extractTrace(ctx, msg)
+		start := time.Now()
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("mq handler panic: %v", r)
+				logger.Error(ctx, "MQ handler panic recovered: topic=%s, group=%s, panic=%v\n%s", sub.Topic, sub.Group, r, debug.Stack())
+			}
+		}()
+
+		err = handler(ctx, msg)
+		if err != nil {
+			logger.Error(ctx, "MQ message handle failed: topic=%s, group=%s, duration=%v, error=%v", sub.Topic, sub.Group, time.Since(start), err)
+		}
+		return err
+	}
+}
+
+// Close 停止消费并等待处理中的消息完成（最长 DrainTimeout），然后关闭连接
+func (m *Manager) Close(ctx context.Context) error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	cancel := m.cancel
+	m.mu.Unlock()
+
+	var errs []error
+	if cancel != nil {
+		cancel()
+
+		drainCtx, drainCancel := context.WithTimeout(ctx, m.drainTimeout)
+		defer drainCancel()
+
+		done := make(chan struct{})
+		go func() {
+			m.wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+			logger.Info(ctx, "MQ consumers drained: driver=%s", m.driver.Name())
+		case <-drainCtx.Done():
+			logger.Warn(ctx, "MQ consumers drain timed out: driver=%s, timeout=%v", m.driver.Name(), m.drainTimeout)
+			errs = append(errs, fmt.Errorf("drain consumers: %w", drainCtx.Err()))
+		}
+	}
+
+	if err := m.driver.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("close driver: %w", err))
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
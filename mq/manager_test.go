@@ -0,0 +1,165 @@
+package mq
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/types"
+)
+
+// memoryDriver 内存驱动，用于测试
+type memoryDriver struct {
+	mu     sync.Mutex
+	topics map[string]chan *Message
+	closed bool
+}
+
+func newMemoryDriver() *memoryDriver {
+	return &memoryDriver{topics: make(map[string]chan *Message)}
+}
+
+func (d *memoryDriver) topic(name string) chan *Message {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	ch, ok := d.topics[name]
+	if !ok {
+		ch = make(chan *Message, 100)
+		d.topics[name] = ch
+	}
+	return ch
+}
+
+func (d *memoryDriver) Name() string { return "memory" }
+
+func (d *memoryDriver) Publish(ctx context.Context, msg *Message) error {
+	d.topic(msg.Topic) <- msg
+	return nil
+}
+
+func (d *memoryDriver) Consume(ctx context.Context, sub Subscription, handler Handler) error {
+	ch := d.topic(sub.Topic)
+	var wg sync.WaitGroup
+	for i := 0; i < sub.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case msg := <-ch:
+					_ = handler(context.Background(), msg)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+func (d *memoryDriver) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closed = true
+	return nil
+}
+
+func TestManagerPropagatesTraceID(t *testing.T) {
+	manager, err := NewManagerWithDriver(&Config{Group: "test"}, newMemoryDriver())
+	if err != nil {
+		t.Fatalf("NewManagerWithDriver failed: %v", err)
+	}
+
+	received := make(chan string, 1)
+	if err := manager.Subscribe(Subscription{Topic: "orders"}, func(ctx context.Context, msg *Message) error {
+		received <- logger.GetTraceID(ctx)
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer manager.Close(context.Background())
+
+	ctx := logger.WithTraceID(context.Background(), "trace-123")
+	if err := manager.Publish(ctx, &Message{Topic: "orders", Value: []byte("hello")}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	select {
+	case traceID := <-received:
+		if traceID != "trace-123" {
+			t.Fatalf("expected trace id to be propagated, got %q", traceID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message not consumed")
+	}
+}
+
+func TestManagerConcurrencyAndDrain(t *testing.T) {
+	driver := newMemoryDriver()
+	manager, err := NewManagerWithDriver(&Config{DrainTimeout: types.Duration(time.Second)}, driver)
+	if err != nil {
+		t.Fatalf("NewManagerWithDriver failed: %v", err)
+	}
+
+	var inFlight, maxInFlight, handled atomic.Int32
+	started := make(chan struct{}, 10)
+	if err := manager.Subscribe(Subscription{Topic: "jobs", Concurrency: 2}, func(ctx context.Context, msg *Message) error {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			current := maxInFlight.Load()
+			if n <= current || maxInFlight.CompareAndSwap(current, n) {
+				break
+			}
+		}
+		started <- struct{}{}
+		time.Sleep(50 * time.Millisecond)
+		handled.Add(1)
+		return nil
+	}); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if err := manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := manager.Publish(context.Background(), &Message{Topic: "jobs"}); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+	<-started
+	<-started
+
+	if err := manager.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if handled.Load() != 2 {
+		t.Fatalf("expected in-flight messages to drain before close, handled=%d", handled.Load())
+	}
+	if maxInFlight.Load() != 2 {
+		t.Fatalf("expected concurrency 2, got %d", maxInFlight.Load())
+	}
+	if !driver.closed {
+		t.Fatal("expected driver to be closed")
+	}
+	if err := manager.Publish(context.Background(), &Message{Topic: "jobs"}); err == nil {
+		t.Fatal("expected publish after close to fail")
+	}
+}
+
+func TestNewManagerRejectsUnknownDriver(t *testing.T) {
+	if _, err := NewManager(&Config{Driver: "nats"}); err == nil {
+		t.Fatal("expected unsupported driver error")
+	}
+	if _, err := NewManager(&Config{Driver: DriverKafka}); err == nil {
+		t.Fatal("expected missing kafka config error")
+	}
+}
@@ -0,0 +1,109 @@
+package mq
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/tracing"
+)
+
+const (
+	// TraceIDHeader 消息头中的 trace ID（与 gRPC metadata 保持一致）
+	TraceIDHeader = "x-trace-id"
+	// SpanIDHeader 消息头中的 span ID
+	SpanIDHeader = "x-span-id"
+)
+
+// Message 消息
+type Message struct {
+	// 主题（Kafka topic / RabbitMQ routing key）
+	Topic string
+	// 消息 key（Kafka 用于分区，RabbitMQ 作为 MessageId）
+	Key []byte
+	// 消息体
+	Value []byte
+	// 消息头
+	Headers map[string]string
+	// 消息时间
+	Timestamp time.Time
+}
+
+// Handler 消息处理函数，返回 nil 表示消费成功
+type Handler func(ctx context.Context, msg *Message) error
+
+// Subscription 订阅信息
+type Subscription struct {
+	// 订阅主题
+	Topic string
+	// 消费组（Kafka group id / RabbitMQ 队列名）
+	Group string
+	// 并发消费数
+	Concurrency int
+}
+
+// Driver 消息队列驱动
+type Driver interface {
+	// Name 驱动名称
+	Name() string
+	// Publish 发送消息
+	Publish(ctx context.Context, msg *Message) error
+	// Consume 消费消息，阻塞直到 ctx 取消且所有处理中的消息完成
+	Consume(ctx context.Context, sub Subscription, handler Handler) error
+	// Close 关闭驱动连接
+	Close() error
+}
+
+// injectTrace 将 trace 信息写入消息头
+func injectTrace(ctx context.Context, msg *Message) {
+	if msg.Headers == nil {
+		msg.Headers = make(map[string]string)
+	}
+	if traceID := logger.GetTraceID(ctx); traceID != "" {
+		if _, exists := msg.Headers[TraceIDHeader]; !exists {
+			msg.Headers[TraceIDHeader] = traceID
+		}
+	}
+	if spanID := logger.GetSpanID(ctx); spanID != "" {
+		if _, exists := msg.Headers[SpanIDHeader]; !exists {
+			msg.Headers[SpanIDHeader] = spanID
+		}
+	}
+	if tracing.IsEnabled() {
+		otel.GetTextMapPropagator().Inject(ctx, headerCarrier(msg.Headers))
+	}
+}
+
+// extractTrace 从消息头恢复 trace 信息，不存在时创建新的 trace
+func extractTrace(ctx context.Context, msg *Message) context.Context {
+	if msg.Headers != nil {
+		if tracing.IsEnabled() {
+			ctx = otel.GetTextMapPropagator().Extract(ctx, headerCarrier(msg.Headers))
+		}
+		if traceID := msg.Headers[TraceIDHeader]; traceID != "" {
+			ctx = logger.WithTraceID(ctx, traceID)
+		}
+	}
+	return logger.StartSpan(ctx)
+}
+
+// headerCarrier 实现 propagation.TextMapCarrier 接口，用于在消息头中传递 trace context
+type headerCarrier map[string]string
+
+func (h headerCarrier) Get(key string) string {
+	return h[key]
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h[key] = value
+}
+
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,192 @@
+package mq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// RabbitMQDriver RabbitMQ 驱动
+type RabbitMQDriver struct {
+	config *RabbitMQConfig
+	conn   *amqp.Connection
+
+	// amqp.Channel 不支持并发发布，使用互斥锁保护
+	publishMu sync.Mutex
+	publishCh *amqp.Channel
+}
+
+// NewRabbitMQDriver 创建 RabbitMQ 驱动
+func NewRabbitMQDriver(config *RabbitMQConfig) (*RabbitMQDriver, error) {
+	if config == nil {
+		return nil, errors.New("rabbitmq config is nil")
+	}
+	if config.URL == "" {
+		return nil, errors.New("rabbitmq url is required")
+	}
+
+	conn, err := amqp.Dial(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial rabbitmq: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open rabbitmq channel: %w", err)
+	}
+
+	if config.Exchange != "" {
+		exchangeType := config.ExchangeType
+		if exchangeType == "" {
+			exchangeType = amqp.ExchangeTopic
+		}
+		if err := ch.ExchangeDeclare(config.Exchange, exchangeType, true, false, false, false, nil); err != nil {
+			ch.Close()
+			conn.Close()
+			return nil, fmt.Errorf("failed to declare exchange %s: %w", config.Exchange, err)
+		}
+	}
+
+	return &RabbitMQDriver{
+		config:    config,
+		conn:      conn,
+		publishCh: ch,
+	}, nil
+}
+
+// Name 驱动名称
+func (d *RabbitMQDriver) Name() string {
+	return DriverRabbitMQ
+}
+
+// Publish 发送消息（topic 作为 routing key）
+func (d *RabbitMQDriver) Publish(ctx context.Context, msg *Message) error {
+	headers := make(amqp.Table, len(msg.Headers))
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+
+	d.publishMu.Lock()
+	defer d.publishMu.Unlock()
+	return d.publishCh.PublishWithContext(ctx, d.config.Exchange, msg.Topic, false, false, amqp.Publishing{
+		Headers:      headers,
+		MessageId:    string(msg.Key),
+		Timestamp:    msg.Timestamp,
+		DeliveryMode: amqp.Persistent,
+		Body:         msg.Value,
+	})
+}
+
+// Consume 消费消息
+// 消费组对应队列名（未指定时使用 topic），并发数对应同一 channel 上的处理协程数
+func (d *RabbitMQDriver) Consume(ctx context.Context, sub Subscription, handler Handler) error {
+	queue := sub.Group
+	if queue == "" {
+		queue = sub.Topic
+	}
+	// 默认交换机按队列名路由且不能绑定，队列名与 topic 不一致时消息永远无法到达
+	if d.config.Exchange == "" && queue != sub.Topic {
+		return fmt.Errorf("rabbitmq queue %s cannot receive topic %s from the default exchange: configure an exchange or use the topic as group", queue, sub.Topic)
+	}
+
+	ch, err := d.conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open rabbitmq channel: %w", err)
+	}
+	defer ch.Close()
+
+	prefetch := d.config.Prefetch
+	if prefetch <= 0 {
+		prefetch = sub.Concurrency
+	}
+	if err := ch.Qos(prefetch, 0, false); err != nil {
+		return fmt.Errorf("failed to set qos: %w", err)
+	}
+
+	if _, err := ch.QueueDeclare(queue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", queue, err)
+	}
+	if d.config.Exchange != "" {
+		if err := ch.QueueBind(queue, sub.Topic, d.config.Exchange, false, nil); err != nil {
+			return fmt.Errorf("failed to bind queue %s to %s: %w", queue, d.config.Exchange, err)
+		}
+	}
+
+	consumerTag := fmt.Sprintf("%s-%s", queue, logger.GenerateSpanID())
+	deliveries, err := ch.Consume(queue, consumerTag, false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to consume queue %s: %w", queue, err)
+	}
+
+	// 收到停止信号后取消订阅，已投递的消息会继续被处理完毕
+	go func() {
+		<-ctx.Done()
+		if err := ch.Cancel(consumerTag, false); err != nil {
+			logger.Warn(context.Background(), "RabbitMQ cancel consumer failed: queue=%s, error=%v", queue, err)
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < sub.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for delivery := range deliveries {
+				d.handleDelivery(delivery, sub.Topic, handler)
+			}
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+func (d *RabbitMQDriver) handleDelivery(delivery amqp.Delivery, topic string, handler Handler) {
+	headers := make(map[string]string, len(delivery.Headers))
+	for k, v := range delivery.Headers {
+		headers[k] = fmt.Sprint(v)
+	}
+	if delivery.RoutingKey != "" {
+		topic = delivery.RoutingKey
+	}
+
+	err := handler(context.Background(), &Message{
+		Topic:     topic,
+		Key:       []byte(delivery.MessageId),
+		Value:     delivery.Body,
+		Headers:   headers,
+		Timestamp: delivery.Timestamp,
+	})
+	if err != nil {
+		if nackErr := delivery.Nack(false, d.config.RequeueOnError); nackErr != nil {
+			logger.Error(context.Background(), "RabbitMQ nack failed: topic=%s, error=%v", topic, nackErr)
+		}
+		return
+	}
+	if ackErr := delivery.Ack(false); ackErr != nil {
+		logger.Error(context.Background(), "RabbitMQ ack failed: topic=%s, error=%v", topic, ackErr)
+	}
+}
+
+// Close 关闭驱动
+func (d *RabbitMQDriver) Close() error {
+	var errs []error
+	d.publishMu.Lock()
+	if d.publishCh != nil {
+		if err := d.publishCh.Close(); err != nil && !errors.Is(err, amqp.ErrClosed) {
+			errs = append(errs, fmt.Errorf("close rabbitmq channel: %w", err))
+		}
+	}
+	d.publishMu.Unlock()
+	if d.conn != nil {
+		if err := d.conn.Close(); err != nil && !errors.Is(err, amqp.ErrClosed) {
+			errs = append(errs, fmt.Errorf("close rabbitmq connection: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,178 @@
+package outbox
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	gormDB "gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/team-dandelion/quickgo/db/gorm"
+	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/mq"
+)
+
+const (
+	// DefaultTable 默认 outbox 表名
+	DefaultTable = "outbox_messages"
+	// DedupKeyHeader 消息头中的去重键，消费方据此对至少一次投递的重复消息去重
+	DedupKeyHeader = "x-outbox-id"
+)
+
+// 消息状态
+const (
+	StatusPending = "pending"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+)
+
+// ErrNotInTransaction Enqueue 传入的 DB 不在事务中
+var ErrNotInTransaction = errors.New("outbox: enqueue requires a transaction")
+
+// Message outbox 表结构
+type Message struct {
+	ID            uint64    `gorm:"primaryKey;autoIncrement"`
+	Topic         string    `gorm:"size:255;not null"`
+	Key           string    `gorm:"size:255"`
+	DedupKey      string    `gorm:"size:128;not null;uniqueIndex"`
+	Payload       []byte    `gorm:"not null"`
+	Headers       string    `gorm:"type:text"`
+	Status        string    `gorm:"size:16;not null;index:idx_outbox_poll,priority:1"`
+	NextAttemptAt time.Time `gorm:"not null;index:idx_outbox_poll,priority:2"`
+	Attempts      int       `gorm:"not null;default:0"`
+	LastError     string    `gorm:"type:text"`
+	CreatedAt     time.Time
+	SentAt        *time.Time
+}
+
+// Option Enqueue 选项
+type Option func(*enqueueOptions)
+
+type enqueueOptions struct {
+	key      string
+	dedupKey string
+	headers  map[string]string
+	table    string
+}
+
+// WithKey 设置消息 key（Kafka 分区键），同一 key 的消息按写入顺序投递；默认使用去重键
+func WithKey(key string) Option {
+	return func(o *enqueueOptions) {
+		o.key = key
+	}
+}
+
+// WithDedupKey 设置去重键（如业务事件 ID），重复写入同一去重键时忽略；默认随机生成
+func WithDedupKey(dedupKey string) Option {
+	return func(o *enqueueOptions) {
+		o.dedupKey = dedupKey
+	}
+}
+
+// WithHeaders 设置消息头
+func WithHeaders(headers map[string]string) Option {
+	return func(o *enqueueOptions) {
+		o.headers = headers
+	}
+}
+
+// WithTable 写入指定的 outbox 表（需与 Relay 配置的 Table 一致）
+func WithTable(table string) Option {
+	return func(o *enqueueOptions) {
+		o.table = table
+	}
+}
+
+// Enqueue 在业务事务 tx 中写入待发送消息，随事务一起提交或回滚，由 Relay 异步投递到消息队列
+// payload 为 []byte 或 string 时原样发送，其余类型编码为 JSON；当前 trace ID 写入消息头以便链路延续
+func Enqueue(tx *gormDB.DB, topic string, payload any, opts ...Option) error {
+	if tx == nil {
+		return errors.New("outbox: tx is nil")
+	}
+	if _, ok := tx.Statement.ConnPool.(gormDB.TxCommitter); !ok {
+		return ErrNotInTransaction
+	}
+	if topic == "" {
+		return errors.New("outbox: topic is required")
+	}
+	options := enqueueOptions{table: DefaultTable}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	value, err := encodePayload(payload)
+	if err != nil {
+		return err
+	}
+	if options.dedupKey == "" {
+		if options.dedupKey, err = newDedupKey(); err != nil {
+			return err
+		}
+	}
+	headers := make(map[string]string, len(options.headers)+1)
+	for k, v := range options.headers {
+		headers[k] = v
+	}
+	ctx := tx.Statement.Context
+	if traceID := logger.GetTraceID(ctx); traceID != "" {
+		if _, exists := headers[mq.TraceIDHeader]; !exists {
+			headers[mq.TraceIDHeader] = traceID
+		}
+	}
+	message := &Message{
+		Topic:         topic,
+		Key:           options.key,
+		DedupKey:      options.dedupKey,
+		Payload:       value,
+		Status:        StatusPending,
+		NextAttemptAt: time.Now(),
+	}
+	if len(headers) > 0 {
+		data, err := json.Marshal(headers)
+		if err != nil {
+			return fmt.Errorf("outbox: failed to encode headers: %w", err)
+		}
+		message.Headers = string(data)
+	}
+	return tx.WithContext(gorm.WithoutTenantScope(ctx)).Table(options.table).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "dedup_key"}}, DoNothing: true}).
+		Create(message).Error
+}
+
+// DedupKey 返回消息的去重键（消费方据此实现幂等处理），非 outbox 投递的消息返回空
+func DedupKey(msg *mq.Message) string {
+	if msg == nil || msg.Headers == nil {
+		return ""
+	}
+	return msg.Headers[DedupKeyHeader]
+}
+
+// encodePayload 编码消息体
+func encodePayload(payload any) ([]byte, error) {
+	switch v := payload.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	case nil:
+		return nil, errors.New("outbox: payload is nil")
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("outbox: failed to encode payload: %w", err)
+	}
+	return data, nil
+}
+
+// newDedupKey 生成随机去重键
+func newDedupKey() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("outbox: failed to generate dedup key: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,221 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gormDB "gorm.io/gorm"
+
+	"github.com/team-dandelion/quickgo/db/gorm"
+	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/mq"
+	"github.com/team-dandelion/quickgo/types"
+)
+
+type memoryDriver struct {
+	mu       sync.Mutex
+	fail     map[string]bool
+	messages []*mq.Message
+}
+
+func (d *memoryDriver) Name() string { return "memory" }
+
+func (d *memoryDriver) Publish(ctx context.Context, msg *mq.Message) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.fail[string(msg.Value)] {
+		return errors.New("broker unavailable")
+	}
+	d.messages = append(d.messages, msg)
+	return nil
+}
+
+func (d *memoryDriver) Consume(ctx context.Context, sub mq.Subscription, handler mq.Handler) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (d *memoryDriver) Close() error { return nil }
+
+func (d *memoryDriver) values() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	values := make([]string, 0, len(d.messages))
+	for _, msg := range d.messages {
+		values = append(values, string(msg.Value))
+	}
+	return values
+}
+
+func newTestRelay(t *testing.T, config Config) (*Relay, *gorm.Manager, *memoryDriver) {
+	t.Helper()
+	manager, err := gorm.NewManager(&gorm.GormManagerConfig{Databases: []gorm.GormConfig{{
+		Name:   "main",
+		Master: gorm.MasterConfig{Type: gorm.DatabaseTypeSQLite, Database: filepath.Join(t.TempDir(), "outbox.db")},
+	}}})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	t.Cleanup(func() { _ = manager.Close() })
+	driver := &memoryDriver{fail: map[string]bool{}}
+	producer, err := mq.NewManagerWithDriver(&mq.Config{}, driver)
+	if err != nil {
+		t.Fatalf("NewManagerWithDriver failed: %v", err)
+	}
+	config.Database = "main"
+	config.AutoMigrate = true
+	relay, err := NewRelay(manager, producer, &config)
+	if err != nil {
+		t.Fatalf("NewRelay failed: %v", err)
+	}
+	if err := relay.Init(context.Background()); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	return relay, manager, driver
+}
+
+func enqueue(t *testing.T, manager *gorm.Manager, ctx context.Context, fn func(tx *gormDB.DB) error) error {
+	t.Helper()
+	return manager.Transaction(ctx, "main", fn)
+}
+
+func TestEnqueueFollowsTransaction(t *testing.T) {
+	relay, manager, driver := newTestRelay(t, Config{})
+	ctx := logger.WithTraceID(context.Background(), "trace-1")
+
+	if err := enqueue(t, manager, ctx, func(tx *gormDB.DB) error {
+		if err := Enqueue(tx, "orders", map[string]int{"id": 1}, WithKey("order-1"), WithDedupKey("order-1-created")); err != nil {
+			return err
+		}
+		// 重复的去重键被忽略
+		return Enqueue(tx, "orders", map[string]int{"id": 1}, WithDedupKey("order-1-created"))
+	}); err != nil {
+		t.Fatalf("transaction failed: %v", err)
+	}
+	rollback := errors.New("rollback")
+	if err := enqueue(t, manager, ctx, func(tx *gormDB.DB) error {
+		if err := Enqueue(tx, "orders", "discarded"); err != nil {
+			return err
+		}
+		return rollback
+	}); !errors.Is(err, rollback) {
+		t.Fatalf("expected rollback, got %v", err)
+	}
+	db, _ := manager.GetDB("main")
+	if err := Enqueue(db, "orders", "outside"); !errors.Is(err, ErrNotInTransaction) {
+		t.Fatalf("expected ErrNotInTransaction, got %v", err)
+	}
+
+	sent, err := relay.Flush(context.Background())
+	if err != nil || sent != 1 {
+		t.Fatalf("expected 1 sent message, got %d (%v)", sent, err)
+	}
+	msg := driver.messages[0]
+	if msg.Topic != "orders" || string(msg.Key) != "order-1" || string(msg.Value) != `{"id":1}` ||
+		DedupKey(msg) != "order-1-created" || msg.Headers[mq.TraceIDHeader] != "trace-1" {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+	if sent, err := relay.Flush(context.Background()); err != nil || sent != 0 {
+		t.Fatalf("expected sent messages not to be redelivered, got %d (%v)", sent, err)
+	}
+}
+
+func TestFlushRetriesFailedMessagesInKeyOrder(t *testing.T) {
+	relay, manager, driver := newTestRelay(t, Config{RetryBackoff: types.Duration(time.Millisecond), MaxAttempts: 3})
+	if err := enqueue(t, manager, context.Background(), func(tx *gormDB.DB) error {
+		for _, value := range []string{"a1", "a2", "b1"} {
+			if err := Enqueue(tx, "events", value, WithKey(value[:1])); err != nil {
+				return err
+			}
+		}
+		return Enqueue(tx, "events", "poison")
+	}); err != nil {
+		t.Fatalf("transaction failed: %v", err)
+	}
+
+	driver.fail["a1"], driver.fail["poison"] = true, true
+	if sent, err := relay.Flush(context.Background()); err != nil || sent != 1 {
+		t.Fatalf("expected only b1 to be sent, got %d (%v)", sent, err)
+	}
+	// a1 等待重试期间 a2 不能越过 a1 投递
+	if sent, err := relay.Flush(context.Background()); err != nil || sent != 0 {
+		t.Fatalf("expected a2 to wait for a1 within the backoff window, got %d (%v)", sent, err)
+	}
+	// a1 恢复后 a2 随之按序发送
+	delete(driver.fail, "a1")
+	time.Sleep(5 * time.Millisecond)
+	if sent, err := relay.Flush(context.Background()); err != nil || sent != 2 {
+		t.Fatalf("expected a1 and a2 to be sent, got %d (%v)", sent, err)
+	}
+	if got := driver.values(); len(got) != 3 || got[0] != "b1" || got[1] != "a1" || got[2] != "a2" {
+		t.Fatalf("unexpected delivery order: %v", got)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	_, _ = relay.Flush(context.Background())
+	db, _ := manager.GetDB("main")
+	var poison Message
+	if err := db.Table(DefaultTable).Where("payload = ?", []byte("poison")).First(&poison).Error; err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if poison.Status != StatusFailed || poison.Attempts != 3 || !strings.Contains(poison.LastError, "broker unavailable") {
+		t.Fatalf("expected poison message to be marked failed, got %+v", poison)
+	}
+}
+
+func TestFlushKeepsKeyOrderWhileLeaseHeld(t *testing.T) {
+	relay, manager, driver := newTestRelay(t, Config{})
+	if err := enqueue(t, manager, context.Background(), func(tx *gormDB.DB) error {
+		for _, value := range []string{"a1", "a2"} {
+			if err := Enqueue(tx, "events", value, WithKey("a")); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("transaction failed: %v", err)
+	}
+
+	// 模拟其他实例已认领 a1 且尚未完成投递
+	db, _ := manager.GetDB("main")
+	if err := db.Table(DefaultTable).Where("payload = ?", []byte("a1")).
+		Update("next_attempt_at", time.Now().Add(time.Minute)).Error; err != nil {
+		t.Fatalf("claim failed: %v", err)
+	}
+	if sent, err := relay.Flush(context.Background()); err != nil || sent != 0 {
+		t.Fatalf("expected a2 to wait while a1 is leased, got %d (%v)", sent, err)
+	}
+	if got := driver.values(); len(got) != 0 {
+		t.Fatalf("expected nothing delivered, got %v", got)
+	}
+}
+
+func TestRelayComponentDeliversInBackground(t *testing.T) {
+	relay, manager, driver := newTestRelay(t, Config{PollInterval: types.Duration(10 * time.Millisecond)})
+	if err := relay.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := enqueue(t, manager, context.Background(), func(tx *gormDB.DB) error {
+		return Enqueue(tx, "events", "hello")
+	}); err != nil {
+		t.Fatalf("transaction failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(driver.values()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := driver.values(); len(got) != 1 || got[0] != "hello" {
+		t.Fatalf("expected background delivery, got %v", got)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := relay.Stop(ctx); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+}
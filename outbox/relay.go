@@ -0,0 +1,361 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	gormDB "gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/team-dandelion/quickgo/db/gorm"
+	"github.com/team-dandelion/quickgo/logger"
+	"github.com/team-dandelion/quickgo/mq"
+	"github.com/team-dandelion/quickgo/types"
+)
+
+// Config Relay 配置
+type Config struct {
+	// 是否启用
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled"`
+	// 组件名称（默认 outbox）
+	Name string `json:"name" yaml:"name" toml:"name"`
+	// GormManager 中的数据库名称
+	Database string `json:"database" yaml:"database" toml:"database"`
+	// outbox 表名（默认 outbox_messages）
+	Table string `json:"table" yaml:"table" toml:"table"`
+	// 启动时自动创建或更新 outbox 表
+	AutoMigrate bool `json:"autoMigrate" yaml:"autoMigrate" toml:"autoMigrate"`
+	// 轮询间隔（默认 1s）
+	PollInterval types.Duration `json:"pollInterval" yaml:"pollInterval" toml:"pollInterval"`
+	// 每次轮询的最大消息数（默认 100）
+	BatchSize int `json:"batchSize" yaml:"batchSize" toml:"batchSize"`
+	// 消息被认领后的租约时间，进程在租约内崩溃时由其他实例重新投递（默认 30s）
+	Lease types.Duration `json:"lease" yaml:"lease" toml:"lease"`
+	// 投递失败后的首次重试间隔，之后按指数退避（默认 1s）
+	RetryBackoff types.Duration `json:"retryBackoff" yaml:"retryBackoff" toml:"retryBackoff"`
+	// 重试间隔上限（默认 5m）
+	MaxBackoff types.Duration `json:"maxBackoff" yaml:"maxBackoff" toml:"maxBackoff"`
+	// 最大投递次数，超过后标记为 failed 不再重试（0 表示不限制）
+	MaxAttempts int `json:"maxAttempts" yaml:"maxAttempts" toml:"maxAttempts"`
+	// 已发送消息的保留时间（如 72h），未设置表示不清理
+	Retention types.Duration `json:"retention" yaml:"retention" toml:"retention"`
+}
+
+// Relay 轮询 outbox 表并将消息投递到消息队列，实现 quickgo.Component 接口
+// 投递语义为至少一次：发送成功但标记失败（如进程崩溃）时消息会在租约到期后重发，消费方按 DedupKey 去重
+// 同一 key 的消息按写入顺序投递，前序消息失败时后续消息等待重试；多实例可同时运行，通过租约避免并发重复投递
+type Relay struct {
+	name         string
+	enabled      bool
+	manager      *gorm.Manager
+	producer     *mq.Manager
+	database     string
+	table        string
+	autoMigrate  bool
+	pollInterval time.Duration
+	batchSize    int
+	lease        time.Duration
+	retryBackoff time.Duration
+	maxBackoff   time.Duration
+	maxAttempts  int
+	retention    time.Duration
+
+	mu          sync.Mutex
+	cancel      context.CancelFunc
+	done        chan struct{}
+	lastCleanup time.Time
+}
+
+// NewRelay 创建 outbox 投递组件
+func NewRelay(manager *gorm.Manager, producer *mq.Manager, config *Config) (*Relay, error) {
+	if manager == nil {
+		return nil, errors.New("gorm manager is nil")
+	}
+	if producer == nil {
+		return nil, errors.New("mq manager is nil")
+	}
+	if config == nil {
+		return nil, errors.New("config is nil")
+	}
+
+	r := &Relay{
+		name:        config.Name,
+		enabled:     config.Enabled,
+		manager:     manager,
+		producer:    producer,
+		database:    config.Database,
+		table:       config.Table,
+		autoMigrate: config.AutoMigrate,
+		batchSize:   config.BatchSize,
+		maxAttempts: config.MaxAttempts,
+	}
+	if r.name == "" {
+		r.name = "outbox"
+	}
+	if r.table == "" {
+		r.table = DefaultTable
+	}
+	if r.batchSize <= 0 {
+		r.batchSize = 100
+	}
+	r.pollInterval = config.PollInterval.OrDefault(time.Second)
+	r.lease = config.Lease.OrDefault(30 * time.Second)
+	r.retryBackoff = config.RetryBackoff.OrDefault(time.Second)
+	r.maxBackoff = config.MaxBackoff.OrDefault(5 * time.Minute)
+	r.retention = config.Retention.OrDefault(0)
+	if r.maxBackoff < r.retryBackoff {
+		r.maxBackoff = r.retryBackoff
+	}
+	return r, nil
+}
+
+// Migrate 创建或更新 outbox 表
+func (r *Relay) Migrate(ctx context.Context) error {
+	db, err := r.db(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Table(r.table).AutoMigrate(&Message{})
+}
+
+// Flush 执行一轮投递，返回成功发送的消息数
+func (r *Relay) Flush(ctx context.Context) (int, error) {
+	db, err := r.db(ctx)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	var messages []Message
+	// 同一 key 存在等待重试或被其他实例认领（next_attempt_at 未到）的前序消息时，后续消息不越过它投递；
+	// 已到期的前序消息 id 更小，必然在同一批中先处理
+	keyColumn := clause.Column{Table: r.table, Name: "key"}
+	if err := db.Table(r.table).Where("status = ? AND next_attempt_at <= ?", StatusPending, now).
+		Where("? = '' OR NOT EXISTS (SELECT 1 FROM ? AS prior WHERE ? = ? AND prior.status = ? AND prior.next_attempt_at > ? AND prior.id < ?)",
+			keyColumn, clause.Table{Name: r.table}, clause.Column{Table: "prior", Name: "key"}, keyColumn,
+			StatusPending, now, clause.Column{Table: r.table, Name: "id"}).
+		Order("id").Limit(r.batchSize).Find(&messages).Error; err != nil {
+		return 0, fmt.Errorf("outbox: failed to load pending messages: %w", err)
+	}
+
+	sent := 0
+	// 失败或被其他实例认领的 key，本轮跳过其后续消息以保持顺序
+	blocked := make(map[string]bool)
+	for i := range messages {
+		message := &messages[i]
+		if message.Key != "" && blocked[message.Key] {
+			continue
+		}
+		claimed, err := r.claim(db, message, now)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			blocked[message.Key] = true
+			continue
+		}
+		if err := r.publish(ctx, message); err != nil {
+			blocked[message.Key] = true
+			if markErr := r.markFailed(db, message, err); markErr != nil {
+				return sent, markErr
+			}
+			continue
+		}
+		if err := db.Table(r.table).Where("id = ?", message.ID).Updates(map[string]any{
+			"status":     StatusSent,
+			"attempts":   message.Attempts + 1,
+			"last_error": "",
+			"sent_at":    time.Now(),
+		}).Error; err != nil {
+			// 消息已发送，租约到期后会重发，由消费方去重
+			return sent, fmt.Errorf("outbox: failed to mark message %d sent: %w", message.ID, err)
+		}
+		sent++
+	}
+
+	r.cleanup(ctx, db, now)
+	return sent, nil
+}
+
+// claim 通过租约认领消息，返回 false 表示已被其他实例认领
+func (r *Relay) claim(db *gormDB.DB, message *Message, now time.Time) (bool, error) {
+	result := db.Table(r.table).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", message.ID, StatusPending, now).
+		Update("next_attempt_at", now.Add(r.lease))
+	if result.Error != nil {
+		return false, fmt.Errorf("outbox: failed to claim message %d: %w", message.ID, result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// publish 发送消息到消息队列
+func (r *Relay) publish(ctx context.Context, message *Message) error {
+	headers := make(map[string]string)
+	if message.Headers != "" {
+		if err := json.Unmarshal([]byte(message.Headers), &headers); err != nil {
+			return fmt.Errorf("failed to decode headers: %w", err)
+		}
+	}
+	headers[DedupKeyHeader] = message.DedupKey
+	if traceID := headers[mq.TraceIDHeader]; traceID != "" {
+		ctx = logger.WithTraceID(ctx, traceID)
+	}
+	key := message.Key
+	if key == "" {
+		key = message.DedupKey
+	}
+	return r.producer.Publish(ctx, &mq.Message{
+		Topic:     message.Topic,
+		Key:       []byte(key),
+		Value:     message.Payload,
+		Headers:   headers,
+		Timestamp: message.CreatedAt,
+	})
+}
+
+// markFailed 记录投递失败并安排重试，超过最大次数时标记为 failed
+func (r *Relay) markFailed(db *gormDB.DB, message *Message, cause error) error {
+	attempts := message.Attempts + 1
+	updates := map[string]any{
+		"attempts":   attempts,
+		"last_error": cause.Error(),
+	}
+	if r.maxAttempts > 0 && attempts >= r.maxAttempts {
+		updates["status"] = StatusFailed
+		logger.Error(db.Statement.Context, "Outbox message delivery failed permanently: id=%d, topic=%s, attempts=%d, error=%v",
+			message.ID, message.Topic, attempts, cause)
+	} else {
+		backoff := r.backoff(attempts)
+		updates["next_attempt_at"] = time.Now().Add(backoff)
+		logger.Warn(db.Statement.Context, "Outbox message delivery failed, will retry: id=%d, topic=%s, attempts=%d, backoff=%s, error=%v",
+			message.ID, message.Topic, attempts, backoff, cause)
+	}
+	if err := db.Table(r.table).Where("id = ?", message.ID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("outbox: failed to record delivery failure of message %d: %w", message.ID, err)
+	}
+	return nil
+}
+
+// backoff 第 attempts 次失败后的重试间隔
+func (r *Relay) backoff(attempts int) time.Duration {
+	backoff := r.retryBackoff
+	for i := 1; i < attempts && backoff < r.maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, r.maxBackoff)
+}
+
+// cleanup 清理超过保留时间的已发送消息（每分钟最多一次）
+func (r *Relay) cleanup(ctx context.Context, db *gormDB.DB, now time.Time) {
+	if r.retention <= 0 {
+		return
+	}
+	r.mu.Lock()
+	if now.Sub(r.lastCleanup) < time.Minute {
+		r.mu.Unlock()
+		return
+	}
+	r.lastCleanup = now
+	r.mu.Unlock()
+
+	result := db.Table(r.table).Where("status = ? AND sent_at < ?", StatusSent, now.Add(-r.retention)).Delete(&Message{})
+	if result.Error != nil {
+		logger.Warn(ctx, "Outbox cleanup failed: table=%s, error=%v", r.table, result.Error)
+	} else if result.RowsAffected > 0 {
+		logger.Debug(ctx, "Outbox cleanup removed sent messages: table=%s, count=%d", r.table, result.RowsAffected)
+	}
+}
+
+// db 获取 outbox 表所在的连接，不加入 context 中的事务，也不受行级租户隔离影响
+func (r *Relay) db(ctx context.Context) (*gormDB.DB, error) {
+	db, err := r.manager.GetDB(r.database)
+	if err != nil {
+		return nil, err
+	}
+	return db.WithContext(gorm.WithoutTenantScope(ctx)), nil
+}
+
+// ==================== Component 接口实现 ====================
+
+// Name 返回组件名称
+func (r *Relay) Name() string {
+	return r.name
+}
+
+// IsEnabled 是否启用
+func (r *Relay) IsEnabled() bool {
+	return r.enabled
+}
+
+// Init 初始化组件
+func (r *Relay) Init(ctx context.Context) error {
+	if _, err := r.manager.GetDB(r.database); err != nil {
+		return err
+	}
+	if r.autoMigrate {
+		return r.Migrate(ctx)
+	}
+	return nil
+}
+
+// Start 启动轮询投递
+func (r *Relay) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cancel != nil {
+		return errors.New("outbox relay already started")
+	}
+	loopCtx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	go r.loop(loopCtx, r.done)
+
+	logger.Info(ctx, "Outbox relay started: name=%s, table=%s, pollInterval=%s", r.name, r.table, r.pollInterval)
+	return nil
+}
+
+// Stop 停止轮询，并等待当前一轮投递结束（受 ctx 超时控制）
+func (r *Relay) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel, r.done = nil, nil
+	r.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+		logger.Info(ctx, "Outbox relay stopped: name=%s", r.name)
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("outbox relay %s stop timed out: %w", r.name, ctx.Err())
+	}
+}
+
+// loop 轮询循环，一轮发送满批次时立即继续
+func (r *Relay) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		sent, err := r.Flush(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Warn(ctx, "Outbox relay poll failed: name=%s, error=%v", r.name, err)
+		}
+		if err == nil && sent >= r.batchSize {
+			timer.Reset(0)
+		} else {
+			timer.Reset(r.pollInterval)
+		}
+	}
+}
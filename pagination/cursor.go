@@ -0,0 +1,124 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidCursor 游标格式错误、签名不匹配或与排序键不一致
+	ErrInvalidCursor = errors.New("pagination: invalid cursor")
+	// ErrCursorExpired 游标已过期
+	ErrCursorExpired = errors.New("pagination: cursor expired")
+)
+
+// Config 游标签名配置
+type Config struct {
+	// HMAC 签名密钥（必填，多实例需一致）
+	Secret string `json:"secret" yaml:"secret" toml:"secret"`
+	// 游标有效期，示例：24h（默认不过期）
+	TTL string `json:"ttl" yaml:"ttl" toml:"ttl"`
+}
+
+// Codec 生成与校验不透明的签名游标
+// 游标格式：base64url(payload).base64url(hmac-sha256(payload))，客户端无法解析或篡改排序键
+type Codec struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// cursorPayload 游标内容
+type cursorPayload struct {
+	// 查询标识（如列表接口名），防止游标在不同列表间混用
+	Scope string `json:"s,omitempty"`
+	// 排序键（上一页最后一条记录）
+	Key json.RawMessage `json:"k"`
+	// 过期时间（Unix 秒，0 表示不过期）
+	Exp int64 `json:"e,omitempty"`
+}
+
+// NewCodec 创建游标编解码器
+func NewCodec(config *Config) (*Codec, error) {
+	if config == nil || config.Secret == "" {
+		return nil, errors.New("pagination cursor secret is required")
+	}
+	c := &Codec{secret: []byte(config.Secret), now: time.Now}
+	if config.TTL != "" {
+		ttl, err := time.ParseDuration(config.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse TTL %s: %w", config.TTL, err)
+		}
+		c.ttl = ttl
+	}
+	return c, nil
+}
+
+// Encode 将排序键编码为签名游标，scope 用于区分不同的列表查询
+func (c *Codec) Encode(scope string, key interface{}) (string, error) {
+	raw, err := json.Marshal(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor key: %w", err)
+	}
+	payload := cursorPayload{Scope: scope, Key: raw}
+	if c.ttl > 0 {
+		payload.Exp = c.now().Add(c.ttl).Unix()
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data) + "." + base64.RawURLEncoding.EncodeToString(c.sign(data)), nil
+}
+
+// Decode 校验签名游标并将排序键解码到 dst
+func (c *Codec) Decode(scope, cursor string, dst interface{}) error {
+	encoded, signature, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return ErrInvalidCursor
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, c.sign(data)) {
+		return ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(data, &payload); err != nil || payload.Scope != scope {
+		return ErrInvalidCursor
+	}
+	if payload.Exp > 0 && c.now().Unix() > payload.Exp {
+		return ErrCursorExpired
+	}
+	if err := json.Unmarshal(payload.Key, dst); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+func (c *Codec) sign(data []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// EncodeKey 将类型化排序键编码为签名游标
+func EncodeKey[K any](c *Codec, scope string, key K) (string, error) {
+	return c.Encode(scope, key)
+}
+
+// DecodeKey 校验签名游标并解码为类型化排序键
+func DecodeKey[K any](c *Codec, scope, cursor string) (K, error) {
+	var key K
+	err := c.Decode(scope, cursor, &key)
+	return key, err
+}
@@ -0,0 +1,106 @@
+package pagination
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type orderKey struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+func TestCodecRoundTripAndTamperDetection(t *testing.T) {
+	codec, err := NewCodec(&Config{Secret: "s3cret"})
+	if err != nil {
+		t.Fatalf("NewCodec failed: %v", err)
+	}
+	key := orderKey{CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), ID: 42}
+	cursor, err := EncodeKey(codec, "orders", key)
+	if err != nil {
+		t.Fatalf("EncodeKey failed: %v", err)
+	}
+	if strings.Contains(cursor, "42") {
+		t.Fatalf("cursor should be opaque, got %q", cursor)
+	}
+
+	decoded, err := DecodeKey[orderKey](codec, "orders", cursor)
+	if err != nil || !decoded.CreatedAt.Equal(key.CreatedAt) || decoded.ID != 42 {
+		t.Fatalf("unexpected decoded key %+v, err=%v", decoded, err)
+	}
+
+	if _, err := DecodeKey[orderKey](codec, "users", cursor); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected scope mismatch to be rejected, got %v", err)
+	}
+	tampered := "x" + cursor[1:]
+	if _, err := DecodeKey[orderKey](codec, "orders", tampered); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected tampered cursor to be rejected, got %v", err)
+	}
+	other, _ := NewCodec(&Config{Secret: "other"})
+	if _, err := DecodeKey[orderKey](other, "orders", cursor); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected cursor signed with another secret to be rejected, got %v", err)
+	}
+	if _, err := DecodeKey[orderKey](codec, "orders", "garbage"); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected garbage cursor to be rejected, got %v", err)
+	}
+}
+
+func TestCodecExpiry(t *testing.T) {
+	codec, err := NewCodec(&Config{Secret: "s3cret", TTL: "1h"})
+	if err != nil {
+		t.Fatalf("NewCodec failed: %v", err)
+	}
+	now := time.Now()
+	codec.now = func() time.Time { return now }
+	cursor, err := codec.Encode("", 7)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	var id int
+	if err := codec.Decode("", cursor, &id); err != nil || id != 7 {
+		t.Fatalf("unexpected decode id=%d err=%v", id, err)
+	}
+	codec.now = func() time.Time { return now.Add(2 * time.Hour) }
+	if err := codec.Decode("", cursor, &id); !errors.Is(err, ErrCursorExpired) {
+		t.Fatalf("expected ErrCursorExpired, got %v", err)
+	}
+
+	if _, err := NewCodec(&Config{}); err == nil {
+		t.Fatal("expected error without secret")
+	}
+}
+
+func TestConditionsAndKeyValues(t *testing.T) {
+	key := orderKey{CreatedAt: time.Unix(100, 0), ID: 9}
+	values := KeyValues(key)
+	if len(values) != 2 || values[1] != int64(9) {
+		t.Fatalf("unexpected key values %v", values)
+	}
+	if values := KeyValues(time.Unix(1, 0)); len(values) != 1 {
+		t.Fatalf("time.Time should be a single value, got %v", values)
+	}
+
+	groups, err := Conditions([]SortKey{{Column: "created_at", Desc: true}, {Column: "id", Desc: true}}, values)
+	if err != nil {
+		t.Fatalf("Conditions failed: %v", err)
+	}
+	if len(groups) != 2 || len(groups[0]) != 1 || groups[0][0].Op != "<" {
+		t.Fatalf("unexpected first group %+v", groups)
+	}
+	if second := groups[1]; len(second) != 2 || second[0].Op != "=" || second[1].Column != "id" || second[1].Op != "<" {
+		t.Fatalf("unexpected second group %+v", second)
+	}
+	if _, err := Conditions([]SortKey{{Column: "id"}}, values); err == nil {
+		t.Fatal("expected mismatch error")
+	}
+}
+
+func TestRequestPageLimit(t *testing.T) {
+	for limit, want := range map[int]int{0: DefaultLimit, -1: DefaultLimit, 5: 5, 1000: MaxLimit} {
+		if got := (Request{Limit: limit}).PageLimit(); got != want {
+			t.Fatalf("limit %d: expected %d, got %d", limit, want, got)
+		}
+	}
+}
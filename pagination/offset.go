@@ -0,0 +1,188 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidQuery 分页、排序或过滤参数不合法（页码非数字、排序或过滤字段不在白名单中等）
+var ErrInvalidQuery = errors.New("pagination: invalid query")
+
+// 查询参数名
+const (
+	PageParam     = "page"
+	PageSizeParam = "page_size"
+	SortParam     = "sort"
+	FilterParam   = "filter"
+)
+
+// FilterOp 过滤操作符
+type FilterOp string
+
+const (
+	OpEq   FilterOp = "eq"
+	OpNe   FilterOp = "ne"
+	OpGt   FilterOp = "gt"
+	OpGte  FilterOp = "gte"
+	OpLt   FilterOp = "lt"
+	OpLte  FilterOp = "lte"
+	OpLike FilterOp = "like"
+	OpIn   FilterOp = "in"
+)
+
+var filterOps = map[FilterOp]bool{
+	OpEq: true, OpNe: true, OpGt: true, OpGte: true, OpLt: true, OpLte: true, OpLike: true, OpIn: true,
+}
+
+// Filter 过滤条件（Column 为白名单映射后的列名）
+type Filter struct {
+	Column string
+	Op     FilterOp
+	// 过滤值；OpIn 时为多个值
+	Values []string
+}
+
+// QuerySpec 查询参数白名单，只有声明的字段可以排序或过滤，防止按任意列排序或注入列名
+type QuerySpec struct {
+	// 可排序字段：查询参数中的字段名 -> 列名
+	Sortable map[string]string
+	// 可过滤字段：查询参数中的字段名 -> 列名
+	Filterable map[string]string
+	// 未指定 sort 时的默认排序
+	DefaultSort []SortKey
+	// 默认每页条数（默认 DefaultLimit）
+	DefaultPageSize int
+	// 每页最大条数（默认 MaxLimit）
+	MaxPageSize int
+}
+
+// PageRequest 页码分页请求
+type PageRequest struct {
+	// 页码，从 1 开始
+	Page     int
+	PageSize int
+	Sort     []SortKey
+	Filters  []Filter
+}
+
+// Offset 返回查询偏移量
+func (r *PageRequest) Offset() int {
+	return (r.Page - 1) * r.PageSize
+}
+
+// ParsePageRequest 解析查询参数为页码分页请求
+// 参数格式：page=2&page_size=20&sort=-created_at,id&filter=status:eq:paid&filter=amount:gte:100
+// sort 以 - 开头表示降序；filter 格式为 字段:操作符:值，in 操作符的多个值以 | 分隔（如 status:in:paid|refunded）
+func ParsePageRequest(values url.Values, spec *QuerySpec) (*PageRequest, error) {
+	if spec == nil {
+		spec = &QuerySpec{}
+	}
+	defaultSize, maxSize := spec.DefaultPageSize, spec.MaxPageSize
+	if defaultSize <= 0 {
+		defaultSize = DefaultLimit
+	}
+	if maxSize <= 0 {
+		maxSize = MaxLimit
+	}
+
+	req := &PageRequest{Page: 1, PageSize: defaultSize}
+	if raw := values.Get(PageParam); raw != "" {
+		page, err := strconv.Atoi(raw)
+		if err != nil || page < 1 {
+			return nil, fmt.Errorf("%w: page must be a positive integer", ErrInvalidQuery)
+		}
+		req.Page = page
+	}
+	if raw := values.Get(PageSizeParam); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil || size < 1 {
+			return nil, fmt.Errorf("%w: page_size must be a positive integer", ErrInvalidQuery)
+		}
+		req.PageSize = min(size, maxSize)
+	}
+
+	sort, err := parseSort(values.Get(SortParam), spec.Sortable)
+	if err != nil {
+		return nil, err
+	}
+	if len(sort) == 0 {
+		sort = append([]SortKey(nil), spec.DefaultSort...)
+	}
+	req.Sort = sort
+
+	// 多个过滤条件使用重复的 filter 参数，值中可以包含逗号
+	for _, raw := range values[FilterParam] {
+		if raw == "" {
+			continue
+		}
+		filter, err := parseFilter(raw, spec.Filterable)
+		if err != nil {
+			return nil, err
+		}
+		req.Filters = append(req.Filters, filter)
+	}
+	return req, nil
+}
+
+// parseSort 解析排序参数，字段必须在白名单中
+func parseSort(raw string, sortable map[string]string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, field := range strings.Split(raw, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		desc := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(strings.TrimPrefix(field, "-"), "+")
+		column, ok := sortable[field]
+		if !ok {
+			return nil, fmt.Errorf("%w: field %q is not sortable", ErrInvalidQuery, field)
+		}
+		keys = append(keys, SortKey{Column: column, Desc: desc})
+	}
+	return keys, nil
+}
+
+// parseFilter 解析单个过滤条件，字段必须在白名单中
+func parseFilter(raw string, filterable map[string]string) (Filter, error) {
+	parts := strings.SplitN(raw, ":", 3)
+	if len(parts) != 3 {
+		return Filter{}, fmt.Errorf("%w: filter %q must be field:op:value", ErrInvalidQuery, raw)
+	}
+	column, ok := filterable[parts[0]]
+	if !ok {
+		return Filter{}, fmt.Errorf("%w: field %q is not filterable", ErrInvalidQuery, parts[0])
+	}
+	op := FilterOp(strings.ToLower(parts[1]))
+	if !filterOps[op] {
+		return Filter{}, fmt.Errorf("%w: unsupported filter operator %q", ErrInvalidQuery, parts[1])
+	}
+	values := []string{parts[2]}
+	if op == OpIn {
+		values = strings.Split(parts[2], "|")
+	}
+	return Filter{Column: column, Op: op, Values: values}, nil
+}
+
+// PageResponse 页码分页结果，作为 grpcep.JsonResponse 的 Data 返回
+type PageResponse[T any] struct {
+	Items      []T   `json:"items"`
+	Page       int   `json:"page"`
+	PageSize   int   `json:"pageSize"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"totalPages"`
+}
+
+// NewPageResponse 根据分页请求与总条数构建分页结果
+func NewPageResponse[T any](req *PageRequest, items []T, total int64) *PageResponse[T] {
+	if items == nil {
+		items = []T{}
+	}
+	resp := &PageResponse[T]{Items: items, Page: req.Page, PageSize: req.PageSize, Total: total}
+	if req.PageSize > 0 {
+		resp.TotalPages = int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+	}
+	return resp
+}
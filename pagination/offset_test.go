@@ -0,0 +1,65 @@
+package pagination
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+var orderSpec = &QuerySpec{
+	Sortable:    map[string]string{"created": "created_at", "id": "id"},
+	Filterable:  map[string]string{"status": "status", "amount": "amount_cents"},
+	DefaultSort: []SortKey{{Column: "id", Desc: true}},
+	MaxPageSize: 50,
+}
+
+func TestParsePageRequest(t *testing.T) {
+	values, _ := url.ParseQuery("page=3&page_size=500&sort=-created,id&filter=status:in:paid|refunded&filter=amount:gte:100")
+	req, err := ParsePageRequest(values, orderSpec)
+	if err != nil {
+		t.Fatalf("ParsePageRequest failed: %v", err)
+	}
+	if req.Page != 3 || req.PageSize != 50 || req.Offset() != 100 {
+		t.Fatalf("unexpected paging %+v", req)
+	}
+	wantSort := []SortKey{{Column: "created_at", Desc: true}, {Column: "id"}}
+	if !reflect.DeepEqual(req.Sort, wantSort) {
+		t.Fatalf("unexpected sort %+v", req.Sort)
+	}
+	wantFilters := []Filter{
+		{Column: "status", Op: OpIn, Values: []string{"paid", "refunded"}},
+		{Column: "amount_cents", Op: OpGte, Values: []string{"100"}},
+	}
+	if !reflect.DeepEqual(req.Filters, wantFilters) {
+		t.Fatalf("unexpected filters %+v", req.Filters)
+	}
+
+	defaults, err := ParsePageRequest(url.Values{}, orderSpec)
+	if err != nil || defaults.Page != 1 || defaults.PageSize != DefaultLimit || !reflect.DeepEqual(defaults.Sort, orderSpec.DefaultSort) {
+		t.Fatalf("unexpected defaults %+v, err=%v", defaults, err)
+	}
+}
+
+func TestParsePageRequestRejectsUnlistedFields(t *testing.T) {
+	for _, query := range []string{
+		"page=0",
+		"page_size=abc",
+		"sort=password",
+		"filter=password:eq:x",
+		"filter=status:regex:x",
+		"filter=status",
+	} {
+		values, _ := url.ParseQuery(query)
+		if _, err := ParsePageRequest(values, orderSpec); !errors.Is(err, ErrInvalidQuery) {
+			t.Fatalf("%s: expected ErrInvalidQuery, got %v", query, err)
+		}
+	}
+}
+
+func TestNewPageResponse(t *testing.T) {
+	resp := NewPageResponse[int](&PageRequest{Page: 2, PageSize: 20}, nil, 41)
+	if resp.TotalPages != 3 || resp.Items == nil || resp.Total != 41 {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+}
@@ -0,0 +1,160 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+const (
+	// DefaultLimit 默认每页条数
+	DefaultLimit = 20
+	// MaxLimit 每页最大条数
+	MaxLimit = 100
+)
+
+// Request 游标分页请求（cursor 为空表示第一页）
+type Request struct {
+	Cursor string `json:"cursor" query:"cursor" form:"cursor"`
+	Limit  int    `json:"limit" query:"limit" form:"limit"`
+}
+
+// PageLimit 返回规范化后的每页条数（默认 DefaultLimit，最大 MaxLimit）
+func (r Request) PageLimit() int {
+	switch {
+	case r.Limit <= 0:
+		return DefaultLimit
+	case r.Limit > MaxLimit:
+		return MaxLimit
+	default:
+		return r.Limit
+	}
+}
+
+// Page 游标分页结果
+type Page[T any] struct {
+	Items []T `json:"items"`
+	// 下一页游标（没有更多数据时为空）
+	NextCursor string `json:"nextCursor,omitempty"`
+	HasMore    bool   `json:"hasMore"`
+}
+
+// SortKey 排序列（SQL 列名或 MongoDB 字段名）
+type SortKey struct {
+	Column string
+	Desc   bool
+}
+
+// Keyset 基于排序键的游标分页定义
+// 排序列需组成唯一键（通常以主键结尾），K 为排序键类型：
+// 单列时为该列的值类型，多列时为按 Sort 顺序声明导出字段的结构体
+type Keyset[T any, K any] struct {
+	// 查询标识，游标只能用于相同 Scope 的查询
+	Scope string
+	Codec *Codec
+	Sort  []SortKey
+	// 从记录中提取排序键
+	Key func(item T) K
+}
+
+// Validate 校验分页定义
+func (k *Keyset[T, K]) Validate() error {
+	if k.Codec == nil {
+		return errors.New("pagination keyset codec is nil")
+	}
+	if len(k.Sort) == 0 {
+		return errors.New("pagination keyset requires at least one sort key")
+	}
+	if k.Key == nil {
+		return errors.New("pagination keyset key func is nil")
+	}
+	return nil
+}
+
+// After 解码请求游标，返回上一页最后一条记录的排序键值（按 Sort 顺序），第一页返回 nil
+func (k *Keyset[T, K]) After(req Request) ([]interface{}, error) {
+	if req.Cursor == "" {
+		return nil, nil
+	}
+	key, err := DecodeKey[K](k.Codec, k.Scope, req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	values := KeyValues(key)
+	if len(values) != len(k.Sort) {
+		return nil, ErrInvalidCursor
+	}
+	return values, nil
+}
+
+// BuildPage 根据多查询一条（limit+1）的结果构建分页结果与下一页游标
+func (k *Keyset[T, K]) BuildPage(items []T, limit int) (*Page[T], error) {
+	page := &Page[T]{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.HasMore = true
+		cursor, err := EncodeKey(k.Codec, k.Scope, k.Key(page.Items[limit-1]))
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = cursor
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	return page, nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// KeyValues 将排序键展开为值列表：结构体按导出字段声明顺序展开，其他类型（含 time.Time）视为单列
+func KeyValues(key interface{}) []interface{} {
+	v := reflect.ValueOf(key)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || v.Type() == timeType {
+		return []interface{}{v.Interface()}
+	}
+	values := make([]interface{}, 0, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).IsExported() {
+			values = append(values, v.Field(i).Interface())
+		}
+	}
+	return values
+}
+
+// Comparison 游标条件中的一个比较项
+type Comparison struct {
+	Column string
+	// Op 为 "=", ">" 或 "<"
+	Op    string
+	Value interface{}
+}
+
+// Conditions 构建"排在游标之后"的条件：多组比较项之间为 OR，组内为 AND
+// 例如 (a, b) 升序：(a > va) OR (a = va AND b > vb)
+func Conditions(sort []SortKey, after []interface{}) ([][]Comparison, error) {
+	if len(after) != len(sort) {
+		return nil, fmt.Errorf("cursor has %d values, expected %d", len(after), len(sort))
+	}
+	groups := make([][]Comparison, 0, len(sort))
+	for i, key := range sort {
+		group := make([]Comparison, 0, i+1)
+		for j := 0; j < i; j++ {
+			group = append(group, Comparison{Column: sort[j].Column, Op: "=", Value: after[j]})
+		}
+		op := ">"
+		if key.Desc {
+			op = "<"
+		}
+		group = append(group, Comparison{Column: key.Column, Op: op, Value: after[i]})
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
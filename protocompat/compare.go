@@ -0,0 +1,316 @@
+// Package protocompat 检查 protobuf 描述符的向后兼容性
+// 用于滚动发布前比较当前描述符与已发布基线，阻止字段重编号、类型变更等破坏性修改
+package protocompat
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Severity 变更级别
+type Severity string
+
+const (
+	// SeverityBreaking 破坏性变更（旧客户端/服务端无法正确通信）
+	SeverityBreaking Severity = "breaking"
+	// SeverityWarning 兼容但需关注的变更
+	SeverityWarning Severity = "warning"
+)
+
+// Change 单个变更
+type Change struct {
+	Severity Severity `json:"severity"`
+	// 变更元素的全名，如 auth.LoginRequest.username
+	Element string `json:"element"`
+	Message string `json:"message"`
+}
+
+// String 返回变更描述
+func (c Change) String() string {
+	return fmt.Sprintf("[%s] %s: %s", c.Severity, c.Element, c.Message)
+}
+
+// Report 兼容性检查结果
+type Report struct {
+	Changes []Change `json:"changes"`
+}
+
+// Breaking 是否存在破坏性变更
+func (r *Report) Breaking() bool {
+	for _, change := range r.Changes {
+		if change.Severity == SeverityBreaking {
+			return true
+		}
+	}
+	return false
+}
+
+// BreakingChanges 返回所有破坏性变更
+func (r *Report) BreakingChanges() []Change {
+	var changes []Change
+	for _, change := range r.Changes {
+		if change.Severity == SeverityBreaking {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// String 返回多行报告
+func (r *Report) String() string {
+	if len(r.Changes) == 0 {
+		return "no changes"
+	}
+	lines := make([]string, 0, len(r.Changes))
+	for _, change := range r.Changes {
+		lines = append(lines, change.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r *Report) add(severity Severity, element, format string, args ...interface{}) {
+	r.Changes = append(r.Changes, Change{Severity: severity, Element: element, Message: fmt.Sprintf(format, args...)})
+}
+
+// Compare 比较基线描述符集与当前描述符集
+// 仅比较基线中存在的元素；新增消息、字段、方法均视为兼容
+// 由于网关使用 protojson 转发，字段改名同样视为破坏性变更
+func Compare(baseline, current *descriptorpb.FileDescriptorSet) *Report {
+	report := &Report{}
+	oldIndex := indexSet(baseline)
+	newIndex := indexSet(current)
+
+	for _, name := range sortedKeys(oldIndex.messages) {
+		oldMsg := oldIndex.messages[name]
+		newMsg, ok := newIndex.messages[name]
+		if !ok {
+			report.add(SeverityBreaking, name, "message removed")
+			continue
+		}
+		compareMessage(report, name, oldMsg, newMsg)
+	}
+	for _, name := range sortedKeys(oldIndex.enums) {
+		oldEnum := oldIndex.enums[name]
+		newEnum, ok := newIndex.enums[name]
+		if !ok {
+			report.add(SeverityBreaking, name, "enum removed")
+			continue
+		}
+		compareEnum(report, name, oldEnum, newEnum)
+	}
+	for _, name := range sortedKeys(oldIndex.services) {
+		oldSvc := oldIndex.services[name]
+		newSvc, ok := newIndex.services[name]
+		if !ok {
+			report.add(SeverityBreaking, name, "service removed")
+			continue
+		}
+		compareService(report, name, oldSvc, newSvc)
+	}
+	return report
+}
+
+func compareMessage(report *Report, name string, oldMsg, newMsg *descriptorpb.DescriptorProto) {
+	newByNumber := make(map[int32]*descriptorpb.FieldDescriptorProto, len(newMsg.GetField()))
+	newByName := make(map[string]*descriptorpb.FieldDescriptorProto, len(newMsg.GetField()))
+	for _, field := range newMsg.GetField() {
+		newByNumber[field.GetNumber()] = field
+		newByName[field.GetName()] = field
+	}
+
+	for _, oldField := range oldMsg.GetField() {
+		element := name + "." + oldField.GetName()
+		newField, ok := newByNumber[oldField.GetNumber()]
+		if !ok {
+			if renamed, exists := newByName[oldField.GetName()]; exists {
+				report.add(SeverityBreaking, element, "field renumbered from %d to %d", oldField.GetNumber(), renamed.GetNumber())
+				continue
+			}
+			if isReservedNumber(newMsg, oldField.GetNumber()) {
+				report.add(SeverityWarning, element, "field %d removed and reserved", oldField.GetNumber())
+				continue
+			}
+			report.add(SeverityBreaking, element, "field %d removed without reserving its number", oldField.GetNumber())
+			continue
+		}
+
+		if newField.GetName() != oldField.GetName() {
+			report.add(SeverityBreaking, element, "field %d renamed to %s (breaks JSON transcoding)", oldField.GetNumber(), newField.GetName())
+		}
+		if oldType, newType := fieldTypeName(oldField), fieldTypeName(newField); oldType != newType {
+			report.add(SeverityBreaking, element, "field type changed from %s to %s", oldType, newType)
+		}
+		if oldField.GetLabel() != newField.GetLabel() {
+			oldRepeated := oldField.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+			newRepeated := newField.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+			if oldRepeated != newRepeated {
+				report.add(SeverityBreaking, element, "field cardinality changed from %s to %s", labelName(oldField), labelName(newField))
+			} else {
+				report.add(SeverityWarning, element, "field label changed from %s to %s", labelName(oldField), labelName(newField))
+			}
+		}
+		if oldField.OneofIndex != nil && newField.OneofIndex == nil || oldField.OneofIndex == nil && newField.OneofIndex != nil {
+			report.add(SeverityBreaking, element, "field moved into or out of a oneof")
+		}
+	}
+}
+
+func compareEnum(report *Report, name string, oldEnum, newEnum *descriptorpb.EnumDescriptorProto) {
+	newByName := make(map[string]int32, len(newEnum.GetValue()))
+	for _, value := range newEnum.GetValue() {
+		newByName[value.GetName()] = value.GetNumber()
+	}
+	for _, oldValue := range oldEnum.GetValue() {
+		element := name + "." + oldValue.GetName()
+		number, ok := newByName[oldValue.GetName()]
+		if !ok {
+			report.add(SeverityBreaking, element, "enum value %d removed", oldValue.GetNumber())
+			continue
+		}
+		if number != oldValue.GetNumber() {
+			report.add(SeverityBreaking, element, "enum value renumbered from %d to %d", oldValue.GetNumber(), number)
+		}
+	}
+}
+
+func compareService(report *Report, name string, oldSvc, newSvc *descriptorpb.ServiceDescriptorProto) {
+	newMethods := make(map[string]*descriptorpb.MethodDescriptorProto, len(newSvc.GetMethod()))
+	for _, method := range newSvc.GetMethod() {
+		newMethods[method.GetName()] = method
+	}
+	for _, oldMethod := range oldSvc.GetMethod() {
+		element := name + "." + oldMethod.GetName()
+		newMethod, ok := newMethods[oldMethod.GetName()]
+		if !ok {
+			report.add(SeverityBreaking, element, "method removed")
+			continue
+		}
+		if oldMethod.GetInputType() != newMethod.GetInputType() {
+			report.add(SeverityBreaking, element, "request type changed from %s to %s", trimDot(oldMethod.GetInputType()), trimDot(newMethod.GetInputType()))
+		}
+		if oldMethod.GetOutputType() != newMethod.GetOutputType() {
+			report.add(SeverityBreaking, element, "response type changed from %s to %s", trimDot(oldMethod.GetOutputType()), trimDot(newMethod.GetOutputType()))
+		}
+		if oldMethod.GetClientStreaming() != newMethod.GetClientStreaming() || oldMethod.GetServerStreaming() != newMethod.GetServerStreaming() {
+			report.add(SeverityBreaking, element, "streaming mode changed")
+		}
+	}
+}
+
+func isReservedNumber(msg *descriptorpb.DescriptorProto, number int32) bool {
+	for _, reserved := range msg.GetReservedRange() {
+		// ReservedRange 的 End 为开区间
+		if number >= reserved.GetStart() && number < reserved.GetEnd() {
+			return true
+		}
+	}
+	return false
+}
+
+func fieldTypeName(field *descriptorpb.FieldDescriptorProto) string {
+	switch field.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, descriptorpb.FieldDescriptorProto_TYPE_ENUM, descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		return trimDot(field.GetTypeName())
+	default:
+		return strings.ToLower(strings.TrimPrefix(field.GetType().String(), "TYPE_"))
+	}
+}
+
+func labelName(field *descriptorpb.FieldDescriptorProto) string {
+	return strings.ToLower(strings.TrimPrefix(field.GetLabel().String(), "LABEL_"))
+}
+
+func trimDot(name string) string {
+	return strings.TrimPrefix(name, ".")
+}
+
+// descriptorIndex 按全名索引的消息、枚举与服务
+type descriptorIndex struct {
+	messages map[string]*descriptorpb.DescriptorProto
+	enums    map[string]*descriptorpb.EnumDescriptorProto
+	services map[string]*descriptorpb.ServiceDescriptorProto
+}
+
+func indexSet(set *descriptorpb.FileDescriptorSet) *descriptorIndex {
+	index := &descriptorIndex{
+		messages: make(map[string]*descriptorpb.DescriptorProto),
+		enums:    make(map[string]*descriptorpb.EnumDescriptorProto),
+		services: make(map[string]*descriptorpb.ServiceDescriptorProto),
+	}
+	for _, file := range set.GetFile() {
+		prefix := file.GetPackage()
+		for _, msg := range file.GetMessageType() {
+			index.addMessage(joinName(prefix, msg.GetName()), msg)
+		}
+		for _, enum := range file.GetEnumType() {
+			index.enums[joinName(prefix, enum.GetName())] = enum
+		}
+		for _, svc := range file.GetService() {
+			index.services[joinName(prefix, svc.GetName())] = svc
+		}
+	}
+	return index
+}
+
+func (i *descriptorIndex) addMessage(name string, msg *descriptorpb.DescriptorProto) {
+	i.messages[name] = msg
+	for _, nested := range msg.GetNestedType() {
+		i.addMessage(name+"."+nested.GetName(), nested)
+	}
+	for _, enum := range msg.GetEnumType() {
+		i.enums[name+"."+enum.GetName()] = enum
+	}
+}
+
+func joinName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FromRegistry 从全局 proto 注册表构建描述符集（包含导入的依赖文件）
+// packages 为需要检查的 proto 包名前缀（如 "auth"），为空时包含所有非 google.* 的文件
+func FromRegistry(packages ...string) *descriptorpb.FileDescriptorSet {
+	return FromFiles(protoregistry.GlobalFiles, packages...)
+}
+
+// FromFiles 从指定注册表构建描述符集
+func FromFiles(files *protoregistry.Files, packages ...string) *descriptorpb.FileDescriptorSet {
+	set := &descriptorpb.FileDescriptorSet{}
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		if matchPackage(string(fd.Package()), packages) {
+			set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+		}
+		return true
+	})
+	sort.Slice(set.File, func(i, j int) bool { return set.File[i].GetName() < set.File[j].GetName() })
+	return set
+}
+
+func matchPackage(pkg string, packages []string) bool {
+	if len(packages) == 0 {
+		return !strings.HasPrefix(pkg, "google.")
+	}
+	for _, prefix := range packages {
+		if pkg == prefix || strings.HasPrefix(pkg, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
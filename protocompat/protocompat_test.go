@@ -0,0 +1,198 @@
+package protocompat
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	// 注册 grpc.health.v1 描述符
+	_ "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func field(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		Number:   proto.Int32(number),
+		Type:     typ.Enum(),
+		Label:    label.Enum(),
+		JsonName: proto.String(name),
+	}
+}
+
+var (
+	optional = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	tString  = descriptorpb.FieldDescriptorProto_TYPE_STRING
+	tInt64   = descriptorpb.FieldDescriptorProto_TYPE_INT64
+)
+
+func baselineSet() *descriptorpb.FileDescriptorSet {
+	return &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:    proto.String("auth.proto"),
+		Package: proto.String("auth"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("LoginRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("username", 1, tString, optional),
+					field("password", 2, tString, optional),
+					field("captcha", 3, tString, optional),
+				},
+			},
+			{
+				Name: proto.String("LoginResponse"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("token", 1, tString, optional),
+					field("expires_at", 2, tInt64, optional),
+					field("roles", 3, tString, repeated),
+				},
+			},
+		},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Status"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("STATUS_UNKNOWN"), Number: proto.Int32(0)},
+				{Name: proto.String("STATUS_ACTIVE"), Number: proto.Int32(1)},
+			},
+		}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("AuthService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("Login"), InputType: proto.String(".auth.LoginRequest"), OutputType: proto.String(".auth.LoginResponse")},
+				{Name: proto.String("Logout"), InputType: proto.String(".auth.LoginRequest"), OutputType: proto.String(".auth.LoginResponse")},
+			},
+		}},
+	}}}
+}
+
+func TestCompareCompatibleChanges(t *testing.T) {
+	current := baselineSet()
+	file := current.File[0]
+	// 新增字段、消息、方法、枚举值均兼容
+	file.MessageType[0].Field = append(file.MessageType[0].Field, field("device", 4, tString, optional))
+	file.MessageType = append(file.MessageType, &descriptorpb.DescriptorProto{Name: proto.String("Empty")})
+	file.EnumType[0].Value = append(file.EnumType[0].Value, &descriptorpb.EnumValueDescriptorProto{Name: proto.String("STATUS_LOCKED"), Number: proto.Int32(2)})
+	file.Service[0].Method = append(file.Service[0].Method, &descriptorpb.MethodDescriptorProto{
+		Name: proto.String("Refresh"), InputType: proto.String(".auth.Empty"), OutputType: proto.String(".auth.LoginResponse"),
+	})
+	// 删除字段但保留编号
+	file.MessageType[0].Field = removeField(file.MessageType[0].Field, "captcha")
+	file.MessageType[0].ReservedRange = []*descriptorpb.DescriptorProto_ReservedRange{{Start: proto.Int32(3), End: proto.Int32(4)}}
+
+	report := Compare(baselineSet(), current)
+	if report.Breaking() {
+		t.Fatalf("expected compatible changes, got:\n%s", report)
+	}
+	if len(report.Changes) != 1 || report.Changes[0].Severity != SeverityWarning {
+		t.Fatalf("expected reserved field warning, got:\n%s", report)
+	}
+}
+
+func TestCompareBreakingChanges(t *testing.T) {
+	current := baselineSet()
+	file := current.File[0]
+	req, resp := file.MessageType[0], file.MessageType[1]
+	req.Field[0].Number = proto.Int32(10)                        // username 重编号
+	req.Field[1].Type = tInt64.Enum()                            // password 类型变更
+	req.Field = removeField(req.Field, "captcha")                // captcha 删除且未保留
+	resp.Field[0].Name = proto.String("access_token")            // token 改名
+	resp.Field[2].Label = optional.Enum()                        // roles repeated -> singular
+	file.EnumType[0].Value[1].Number = proto.Int32(5)            // 枚举值重编号
+	file.Service[0].Method[0].ServerStreaming = proto.Bool(true) // 流模式变更
+	file.Service[0].Method = file.Service[0].Method[:1]          // Logout 删除
+
+	report := Compare(baselineSet(), current)
+	if !report.Breaking() {
+		t.Fatalf("expected breaking changes")
+	}
+	expected := []string{
+		"auth.LoginRequest.username: field renumbered from 1 to 10",
+		"auth.LoginRequest.password: field type changed from string to int64",
+		"auth.LoginRequest.captcha: field 3 removed without reserving its number",
+		"auth.LoginResponse.token: field 1 renamed to access_token",
+		"auth.LoginResponse.roles: field cardinality changed from repeated to optional",
+		"auth.Status.STATUS_ACTIVE: enum value renumbered from 1 to 5",
+		"auth.AuthService.Login: streaming mode changed",
+		"auth.AuthService.Logout: method removed",
+	}
+	output := report.String()
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in report:\n%s", want, output)
+		}
+	}
+	if len(report.BreakingChanges()) != len(expected) {
+		t.Fatalf("expected %d breaking changes, got:\n%s", len(expected), output)
+	}
+}
+
+func TestCompareRemovedMessage(t *testing.T) {
+	current := baselineSet()
+	current.File[0].MessageType = current.File[0].MessageType[:1]
+	report := Compare(baselineSet(), current)
+	if !strings.Contains(report.String(), "auth.LoginResponse: message removed") {
+		t.Fatalf("expected removed message, got:\n%s", report)
+	}
+}
+
+func TestStoresRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileStore(filepath.Join(t.TempDir(), "baselines"))
+
+	report, err := Check(ctx, store, "auth", baselineSet())
+	if err != nil || len(report.Changes) != 0 {
+		t.Fatalf("expected empty report without baseline, got %v, %v", report, err)
+	}
+	if _, err := store.Load(ctx, "auth"); !errors.Is(err, ErrBaselineNotFound) {
+		t.Fatalf("expected ErrBaselineNotFound, got %v", err)
+	}
+
+	if err := store.Save(ctx, "auth", baselineSet()); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := store.Load(ctx, "auth")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !proto.Equal(loaded, baselineSet()) {
+		t.Fatalf("loaded baseline differs from saved one")
+	}
+
+	current := baselineSet()
+	current.File[0].MessageType = current.File[0].MessageType[1:]
+	report, err = Check(ctx, store, "auth", current)
+	if err != nil || !report.Breaking() {
+		t.Fatalf("expected breaking report, got %v, %v", report, err)
+	}
+}
+
+func TestFromRegistry(t *testing.T) {
+	set := FromRegistry("grpc.health")
+	if len(set.File) == 0 {
+		t.Fatalf("expected grpc.health descriptors from registry")
+	}
+	for _, file := range set.File {
+		if !strings.HasPrefix(file.GetPackage(), "grpc.health") {
+			t.Fatalf("unexpected file %s in package %s", file.GetName(), file.GetPackage())
+		}
+	}
+	if report := Compare(set, FromRegistry("grpc.health")); len(report.Changes) != 0 {
+		t.Fatalf("expected identical registry snapshots, got:\n%s", report)
+	}
+}
+
+func removeField(fields []*descriptorpb.FieldDescriptorProto, name string) []*descriptorpb.FieldDescriptorProto {
+	out := fields[:0:0]
+	for _, f := range fields {
+		if f.GetName() != name {
+			out = append(out, f)
+		}
+	}
+	return out
+}
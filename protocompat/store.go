@@ -0,0 +1,201 @@
+package protocompat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// DefaultEtcdPrefix 默认描述符基线 etcd 前缀
+const DefaultEtcdPrefix = "/quickgo/protocompat"
+
+// ErrBaselineNotFound 基线不存在（首次发布）
+var ErrBaselineNotFound = errors.New("protocompat: baseline not found")
+
+// Store 描述符基线存储
+type Store interface {
+	// Load 读取基线，不存在时返回 ErrBaselineNotFound
+	Load(ctx context.Context, name string) (*descriptorpb.FileDescriptorSet, error)
+	// Save 保存基线（发布成功后调用）
+	Save(ctx context.Context, name string, set *descriptorpb.FileDescriptorSet) error
+}
+
+// Check 从 Store 读取基线并与当前描述符集比较
+// 基线不存在时返回空报告，便于首次发布时直接通过
+func Check(ctx context.Context, store Store, name string, current *descriptorpb.FileDescriptorSet) (*Report, error) {
+	baseline, err := store.Load(ctx, name)
+	if err != nil {
+		if errors.Is(err, ErrBaselineNotFound) {
+			return &Report{}, nil
+		}
+		return nil, err
+	}
+	return Compare(baseline, current), nil
+}
+
+// LoadFile 读取二进制 FileDescriptorSet 文件（protoc --descriptor_set_out 或 buf build -o 生成）
+func LoadFile(path string) (*descriptorpb.FileDescriptorSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrBaselineNotFound, path)
+		}
+		return nil, fmt.Errorf("failed to read descriptor set %s: %w", path, err)
+	}
+	return unmarshalSet(data)
+}
+
+// SaveFile 将描述符集写入二进制文件
+func SaveFile(path string, set *descriptorpb.FileDescriptorSet) error {
+	data, err := marshalSet(set)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write descriptor set %s: %w", path, err)
+	}
+	return nil
+}
+
+func marshalSet(set *descriptorpb.FileDescriptorSet) ([]byte, error) {
+	if set == nil {
+		return nil, errors.New("descriptor set is nil")
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(set)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal descriptor set: %w", err)
+	}
+	return data, nil
+}
+
+func unmarshalSet(data []byte) (*descriptorpb.FileDescriptorSet, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal descriptor set: %w", err)
+	}
+	return set, nil
+}
+
+// FileStore 基于本地目录的基线存储，文件名为 {name}.binpb
+type FileStore struct {
+	Dir string
+}
+
+// NewFileStore 创建文件存储
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{Dir: dir}
+}
+
+func (s *FileStore) path(name string) string {
+	return filepath.Join(s.Dir, name+".binpb")
+}
+
+// Load 实现 Store
+func (s *FileStore) Load(ctx context.Context, name string) (*descriptorpb.FileDescriptorSet, error) {
+	return LoadFile(s.path(name))
+}
+
+// Save 实现 Store
+func (s *FileStore) Save(ctx context.Context, name string, set *descriptorpb.FileDescriptorSet) error {
+	return SaveFile(s.path(name), set)
+}
+
+// EtcdConfig etcd 基线存储配置
+type EtcdConfig struct {
+	Endpoints   []string      // etcd 端点列表
+	DialTimeout time.Duration // 连接超时，默认为 5s
+	Prefix      string        // 键前缀，默认为 /quickgo/protocompat
+	Username    string        // 用户名（可选）
+	Password    string        // 密码（可选）
+}
+
+// EtcdStore 基于 etcd 的基线存储，键为 {prefix}/{name}
+type EtcdStore struct {
+	kv     clientv3.KV
+	client *clientv3.Client
+	prefix string
+}
+
+// NewEtcdStore 创建 etcd 基线存储
+func NewEtcdStore(config EtcdConfig) (*EtcdStore, error) {
+	if len(config.Endpoints) == 0 {
+		return nil, fmt.Errorf("etcd endpoints are required")
+	}
+	if config.DialTimeout == 0 {
+		config.DialTimeout = 5 * time.Second
+	}
+
+	etcdConfig := clientv3.Config{
+		Endpoints:   config.Endpoints,
+		DialTimeout: config.DialTimeout,
+	}
+	if config.Username != "" && config.Password != "" {
+		etcdConfig.Username = config.Username
+		etcdConfig.Password = config.Password
+	}
+
+	client, err := clientv3.New(etcdConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create etcd client: %w", err)
+	}
+	store := NewEtcdStoreWithKV(client, config.Prefix)
+	store.client = client
+	return store, nil
+}
+
+// NewEtcdStoreWithKV 使用已有的 etcd KV（如共享的 clientv3.Client）创建基线存储
+func NewEtcdStoreWithKV(kv clientv3.KV, prefix string) *EtcdStore {
+	if prefix == "" {
+		prefix = DefaultEtcdPrefix
+	}
+	return &EtcdStore{kv: kv, prefix: strings.TrimSuffix(prefix, "/")}
+}
+
+func (s *EtcdStore) key(name string) string {
+	return s.prefix + "/" + name
+}
+
+// Load 实现 Store
+func (s *EtcdStore) Load(ctx context.Context, name string) (*descriptorpb.FileDescriptorSet, error) {
+	resp, err := s.kv.Get(ctx, s.key(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get baseline from etcd: %w", err)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrBaselineNotFound, s.key(name))
+	}
+	return unmarshalSet(resp.Kvs[0].Value)
+}
+
+// Save 实现 Store
+func (s *EtcdStore) Save(ctx context.Context, name string, set *descriptorpb.FileDescriptorSet) error {
+	data, err := marshalSet(set)
+	if err != nil {
+		return err
+	}
+	if _, err := s.kv.Put(ctx, s.key(name), string(data)); err != nil {
+		return fmt.Errorf("failed to put baseline to etcd: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭由 NewEtcdStore 创建的 etcd 客户端
+func (s *EtcdStore) Close() error {
+	if s.client == nil {
+		return nil
+	}
+	return s.client.Close()
+}
@@ -0,0 +1,198 @@
+package quickgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+var (
+	frameworkType = reflect.TypeOf((*Framework)(nil))
+	contextType   = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType     = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// provider 已注册的依赖提供者
+type provider struct {
+	name        string
+	constructor reflect.Value
+	// 构造中标记，用于检测提供者之间的循环依赖
+	building bool
+	built    bool
+	instance any
+}
+
+// Provide 注册依赖提供者，实例在 Init 时（内置组件之后、自定义组件之前）按注册顺序构造，
+// Stop 时按构造的逆序关闭实现了 io.Closer 的实例
+// constructor 支持以下形式（T 为任意类型）：
+//
+//	func() T
+//	func() (T, error)
+//	func(*Framework) T
+//	func(*Framework) (T, error)
+//	func(context.Context, *Framework) T
+//	func(context.Context, *Framework) (T, error)
+//
+// 构造函数中可以通过 Resolve 获取其他提供者的实例，被依赖的提供者会先构造
+func (f *Framework) Provide(name string, constructor any) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("provider name is empty")
+	}
+	fn := reflect.ValueOf(constructor)
+	if err := validateConstructor(fn); err != nil {
+		return fmt.Errorf("invalid provider %s: %w", name, err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.initializing || f.initialized || f.started || f.stopping {
+		return errors.New("cannot register provider after framework initialization has started")
+	}
+	if _, exists := f.providers[name]; exists {
+		return fmt.Errorf("provider %s already registered", name)
+	}
+	if f.providers == nil {
+		f.providers = make(map[string]*provider)
+	}
+	f.providers[name] = &provider{name: name, constructor: fn}
+	f.providerOrder = append(f.providerOrder, name)
+	return nil
+}
+
+// validateConstructor 校验构造函数签名
+func validateConstructor(fn reflect.Value) error {
+	if fn.Kind() != reflect.Func || fn.IsNil() {
+		return errors.New("constructor must be a non-nil function")
+	}
+	t := fn.Type()
+	switch {
+	case t.NumIn() == 0:
+	case t.NumIn() == 1 && t.In(0) == frameworkType:
+	case t.NumIn() == 2 && t.In(0) == contextType && t.In(1) == frameworkType:
+	default:
+		return fmt.Errorf("unsupported constructor parameters %s", t)
+	}
+	switch {
+	case t.NumOut() == 1 && t.Out(0) != errorType:
+	case t.NumOut() == 2 && t.Out(1) == errorType:
+	default:
+		return fmt.Errorf("constructor must return T or (T, error), got %s", t)
+	}
+	return nil
+}
+
+// initProviders 按注册顺序构造所有提供者
+func (f *Framework) initProviders(ctx context.Context) error {
+	f.mu.RLock()
+	names := append([]string(nil), f.providerOrder...)
+	f.mu.RUnlock()
+	for _, name := range names {
+		if _, err := f.resolveProvider(ctx, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveProvider 返回提供者实例，初始化期间尚未构造时立即构造
+func (f *Framework) resolveProvider(ctx context.Context, name string) (any, error) {
+	f.mu.Lock()
+	p, ok := f.providers[name]
+	if !ok {
+		f.mu.Unlock()
+		return nil, fmt.Errorf("provider %s not registered", name)
+	}
+	if p.built {
+		instance := p.instance
+		f.mu.Unlock()
+		return instance, nil
+	}
+	if !f.initializing {
+		f.mu.Unlock()
+		return nil, fmt.Errorf("provider %s is not constructed, call Init() first", name)
+	}
+	if p.building {
+		f.mu.Unlock()
+		return nil, fmt.Errorf("provider dependency cycle detected at %s", name)
+	}
+	p.building = true
+	f.mu.Unlock()
+
+	instance, err := p.construct(ctx, f)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p.building = false
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct provider %s: %w", name, err)
+	}
+	p.instance = instance
+	p.built = true
+	f.builtProviders = append(f.builtProviders, name)
+	return instance, nil
+}
+
+// construct 调用构造函数
+func (p *provider) construct(ctx context.Context, f *Framework) (any, error) {
+	var args []reflect.Value
+	switch p.constructor.Type().NumIn() {
+	case 1:
+		args = []reflect.Value{reflect.ValueOf(f)}
+	case 2:
+		args = []reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(f)}
+	}
+	out := p.constructor.Call(args)
+	if len(out) == 2 && !out[1].IsNil() {
+		return nil, out[1].Interface().(error)
+	}
+	return out[0].Interface(), nil
+}
+
+// closeProvidersLocked 按构造的逆序收集实现了 io.Closer 的实例，并清空已构造的实例
+func (f *Framework) closeProvidersLocked() []namedCloser {
+	closers := make([]namedCloser, 0, len(f.builtProviders))
+	for i := len(f.builtProviders) - 1; i >= 0; i-- {
+		p := f.providers[f.builtProviders[i]]
+		if p == nil {
+			continue
+		}
+		if closer, ok := p.instance.(io.Closer); ok {
+			closers = append(closers, namedCloser{name: p.name, closer: closer})
+		}
+		p.instance, p.built = nil, false
+	}
+	f.builtProviders = nil
+	return closers
+}
+
+type namedCloser struct {
+	name   string
+	closer io.Closer
+}
+
+// Resolve 获取提供者实例并转换为类型 T（T 可以是实例实现的接口）
+func Resolve[T any](f *Framework, name string) (T, error) {
+	var zero T
+	instance, err := f.resolveProvider(context.Background(), name)
+	if err != nil {
+		return zero, err
+	}
+	value, ok := instance.(T)
+	if !ok {
+		return zero, fmt.Errorf("provider %s is %T, not %s", name, instance, reflect.TypeOf((*T)(nil)).Elem())
+	}
+	return value, nil
+}
+
+// MustResolve 获取提供者实例，失败时 panic（用于启动阶段的装配代码）
+func MustResolve[T any](f *Framework, name string) T {
+	value, err := Resolve[T](f, name)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
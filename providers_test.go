@@ -0,0 +1,101 @@
+package quickgo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type greeter interface {
+	Greet() string
+}
+
+type testService struct {
+	prefix string
+	closed *[]string
+}
+
+func (s *testService) Greet() string { return s.prefix + "hello" }
+
+func (s *testService) Close() error {
+	*s.closed = append(*s.closed, s.prefix)
+	return nil
+}
+
+func TestFrameworkProvidersConstructOnInitAndCloseOnStop(t *testing.T) {
+	var closed []string
+	f, err := NewFramework(ConfigOptionWithLogger(LoggerConfig{Enabled: false}))
+	if err != nil {
+		t.Fatalf("NewFramework failed: %v", err)
+	}
+	// authService 依赖后注册的 store，store 先构造
+	if err := f.Provide("authService", func(f *Framework) (*testService, error) {
+		store, err := Resolve[*testService](f, "store")
+		if err != nil {
+			return nil, err
+		}
+		return &testService{prefix: store.prefix + "auth:", closed: &closed}, nil
+	}); err != nil {
+		t.Fatalf("Provide(authService) failed: %v", err)
+	}
+	if err := f.Provide("store", func() *testService { return &testService{prefix: "store:", closed: &closed} }); err != nil {
+		t.Fatalf("Provide(store) failed: %v", err)
+	}
+	if err := f.Provide("store", func() int { return 1 }); err == nil {
+		t.Fatal("expected duplicate provider to be rejected")
+	}
+	if err := f.Provide("bad", func(int) string { return "" }); err == nil {
+		t.Fatal("expected unsupported constructor to be rejected")
+	}
+	if _, err := Resolve[greeter](f, "authService"); err == nil {
+		t.Fatal("expected Resolve before Init to fail")
+	}
+
+	if err := f.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	auth, err := Resolve[greeter](f, "authService")
+	if err != nil || auth.Greet() != "store:auth:hello" {
+		t.Fatalf("unexpected resolved service %v, err=%v", auth, err)
+	}
+	if MustResolve[*testService](f, "authService") != auth {
+		t.Fatal("expected providers to be singletons")
+	}
+	if _, err := Resolve[string](f, "authService"); err == nil || !strings.Contains(err.Error(), "not string") {
+		t.Fatalf("expected type mismatch error, got %v", err)
+	}
+	if _, err := Resolve[greeter](f, "missing"); err == nil {
+		t.Fatal("expected missing provider error")
+	}
+
+	if err := f.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if strings.Join(closed, ",") != "store:auth:,store:" {
+		t.Fatalf("expected providers closed in reverse construction order, got %v", closed)
+	}
+}
+
+func TestFrameworkProviderFailuresAbortInit(t *testing.T) {
+	f, err := NewFramework(ConfigOptionWithLogger(LoggerConfig{Enabled: false}))
+	if err != nil {
+		t.Fatalf("NewFramework failed: %v", err)
+	}
+	_ = f.Provide("a", func(ctx context.Context, f *Framework) (int, error) { return Resolve[int](f, "b") })
+	_ = f.Provide("b", func(f *Framework) (int, error) { return Resolve[int](f, "a") })
+	if err := f.Init(); err == nil || !strings.Contains(err.Error(), "provider dependency cycle detected at a") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+
+	var closed []string
+	f, _ = NewFramework(ConfigOptionWithLogger(LoggerConfig{Enabled: false}))
+	_ = f.Provide("store", func() *testService { return &testService{prefix: "store", closed: &closed} })
+	_ = f.Provide("broken", func() (*testService, error) { return nil, errors.New("boom") })
+	if err := f.Init(); err == nil || !strings.Contains(err.Error(), "failed to construct provider broken: boom") {
+		t.Fatalf("expected construction error, got %v", err)
+	}
+	if strings.Join(closed, ",") != "store" {
+		t.Fatalf("expected constructed providers to be closed on init failure, got %v", closed)
+	}
+}
@@ -0,0 +1,276 @@
+package quickgotest
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/team-dandelion/quickgo"
+)
+
+const loopbackAddress = "127.0.0.1"
+
+// App 测试中运行的框架实例，嵌入 *quickgo.Framework，可直接获取组件
+type App struct {
+	*quickgo.Framework
+	// 框架运行期间捕获的日志
+	Logs *LogRecorder
+
+	httpAddrs map[string]string
+	grpcAddr  string
+}
+
+// AppOption RunApp 选项
+type AppOption func(*appOptions)
+
+type appOptions struct {
+	frameworkOptions []quickgo.FrameworkOption
+	configFormat     string
+	configContent    string
+	beforeInit       []func(app *quickgo.Framework) error
+	setup            []func(app *quickgo.Framework) error
+}
+
+// WithFrameworkOptions 追加框架配置选项（在 WithConfig 之后应用，可覆盖配置内容）
+func WithFrameworkOptions(opts ...quickgo.FrameworkOption) AppOption {
+	return func(o *appOptions) {
+		o.frameworkOptions = append(o.frameworkOptions, opts...)
+	}
+}
+
+// WithConfig 使用内存中的配置内容（format 为 yaml、json、toml）：内容按框架配置解析（app、logger、grpcServer、httpServer 等键），
+// 同时设置为全局配置，业务代码中的 quickgo.LoadCustomConfig / LoadCustomConfigKey 读取同一内容
+func WithConfig(format, content string) AppOption {
+	return func(o *appOptions) {
+		o.configFormat = format
+		o.configContent = content
+	}
+}
+
+// WithBeforeInit 在 Init 之前执行（注册自定义组件、依赖提供者、生命周期钩子）
+func WithBeforeInit(fn func(app *quickgo.Framework) error) AppOption {
+	return func(o *appOptions) {
+		o.beforeInit = append(o.beforeInit, fn)
+	}
+}
+
+// WithSetup 在 Init 之后、Start 之前执行（注册 gRPC 服务、HTTP 路由）
+func WithSetup(fn func(app *quickgo.Framework) error) AppOption {
+	return func(o *appOptions) {
+		o.setup = append(o.setup, fn)
+	}
+}
+
+// RunApp 创建、初始化并启动框架，测试结束时自动 Stop：
+//   - gRPC 与 HTTP 服务器（含具名服务器）监听 127.0.0.1 上随机空闲端口，通过 HTTPAddr / GrpcAddr 获取
+//   - 未配置 logger 时使用 debug 级别并写入临时文件，日志条目由 App.Logs 捕获用于断言
+//
+// 框架使用全局 logger 与配置，使用 RunApp 的测试不能并行执行
+func RunApp(t testing.TB, opts ...AppOption) *App {
+	t.Helper()
+	var options appOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	frameworkOptions := []quickgo.FrameworkOption{
+		quickgo.ConfigOptionWithApp(quickgo.AppConfig{Name: "quickgotest-app", Version: "test", Env: quickgo.EnvLocal}),
+		quickgo.ConfigOptionWithLogger(quickgo.LoggerConfig{
+			Enabled: true,
+			Level:   "debug",
+			Output:  "file",
+			File:    filepath.Join(t.TempDir(), "app.log"),
+		}),
+	}
+	if options.configContent != "" {
+		configOptions, err := loadConfigOptions(options.configFormat, options.configContent)
+		if err != nil {
+			t.Fatalf("quickgotest: %v", err)
+		}
+		frameworkOptions = append(frameworkOptions, configOptions...)
+	}
+	frameworkOptions = append(frameworkOptions, options.frameworkOptions...)
+
+	app := &App{Logs: NewLogRecorder(t), httpAddrs: make(map[string]string)}
+	frameworkOptions = append(frameworkOptions, app.listenOnFreePorts(t))
+
+	framework, err := quickgo.NewFramework(frameworkOptions...)
+	if err != nil {
+		t.Fatalf("quickgotest: failed to create framework: %v", err)
+	}
+	app.Framework = framework
+	t.Cleanup(func() {
+		if err := framework.Stop(); err != nil {
+			t.Errorf("quickgotest: failed to stop framework: %v", err)
+		}
+	})
+
+	for _, fn := range options.beforeInit {
+		if err := fn(framework); err != nil {
+			t.Fatalf("quickgotest: before init failed: %v", err)
+		}
+	}
+	if err := framework.Init(); err != nil {
+		t.Fatalf("quickgotest: failed to init framework: %v", err)
+	}
+	for _, fn := range options.setup {
+		if err := fn(framework); err != nil {
+			t.Fatalf("quickgotest: setup failed: %v", err)
+		}
+	}
+	if err := framework.Start(); err != nil {
+		t.Fatalf("quickgotest: failed to start framework: %v", err)
+	}
+	return app
+}
+
+// HTTPAddr 返回 HTTP 服务器的监听地址（host:port），name 为空时返回默认服务器；未配置时返回空字符串
+func (a *App) HTTPAddr(name ...string) string {
+	server := quickgo.DefaultHTTPServerName
+	if len(name) > 0 && name[0] != "" {
+		server = name[0]
+	}
+	return a.httpAddrs[server]
+}
+
+// HTTPURL 返回默认 HTTP 服务器上 path 的完整 URL（如 http://127.0.0.1:41234/api/v1/users）
+func (a *App) HTTPURL(path string) string {
+	return "http://" + a.HTTPAddr() + path
+}
+
+// GrpcAddr 返回 gRPC 服务器的监听地址（host:port），未配置时返回空字符串
+func (a *App) GrpcAddr() string {
+	return a.grpcAddr
+}
+
+// GrpcConn 创建连接到 gRPC 服务器的非加密客户端连接，测试结束时关闭
+func (a *App) GrpcConn(t testing.TB) *grpc.ClientConn {
+	t.Helper()
+	if a.grpcAddr == "" {
+		t.Fatal("quickgotest: grpc server is not configured")
+	}
+	conn, err := grpc.NewClient(a.grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("quickgotest: failed to dial grpc server: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// listenOnFreePorts 返回最后应用的框架选项：将各服务器改为监听 127.0.0.1 上的空闲端口（复制配置，不修改调用方的结构体）
+func (a *App) listenOnFreePorts(t testing.TB) quickgo.FrameworkOption {
+	return func(c *quickgo.FrameworkConfig) {
+		if c.GrpcServer != nil {
+			config := *c.GrpcServer
+			config.Address = loopbackAddress
+			config.Port = freePort(t)
+			if config.DebugAddress != "" {
+				config.DebugAddress = net.JoinHostPort(loopbackAddress, "0")
+			}
+			c.GrpcServer = &config
+			a.grpcAddr = net.JoinHostPort(loopbackAddress, fmt.Sprint(config.Port))
+		}
+		if c.HTTPServer != nil && c.HTTPServer.Enabled {
+			c.HTTPServer = a.listenHTTP(t, quickgo.DefaultHTTPServerName, c.HTTPServer)
+		}
+		if len(c.HTTPServers) > 0 {
+			servers := make(map[string]*quickgo.HTTPServerConfig, len(c.HTTPServers))
+			names := make([]string, 0, len(c.HTTPServers))
+			for name := range c.HTTPServers {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				server := c.HTTPServers[name]
+				if server != nil && server.Enabled {
+					server = a.listenHTTP(t, name, server)
+				}
+				servers[name] = server
+			}
+			c.HTTPServers = servers
+		}
+	}
+}
+
+func (a *App) listenHTTP(t testing.TB, name string, server *quickgo.HTTPServerConfig) *quickgo.HTTPServerConfig {
+	config := *server
+	config.Address = loopbackAddress
+	config.Port = freePort(t)
+	a.httpAddrs[name] = net.JoinHostPort(loopbackAddress, fmt.Sprint(config.Port))
+	return &config
+}
+
+// freePort 返回 127.0.0.1 上当前空闲的端口
+func freePort(t testing.TB) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", net.JoinHostPort(loopbackAddress, "0"))
+	if err != nil {
+		t.Fatalf("quickgotest: failed to find free port: %v", err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// loadConfigOptions 解析内存配置并设置为全局配置，返回对应的框架选项
+func loadConfigOptions(format, content string) ([]quickgo.FrameworkOption, error) {
+	if err := quickgo.InitConfigFromBytes(quickgo.EnvLocal, format, []byte(content)); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	var config quickgo.FrameworkConfig
+	if err := quickgo.LoadCustomConfigE(&config); err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var opts []quickgo.FrameworkOption
+	if config.App.Name != "" {
+		opts = append(opts, quickgo.ConfigOptionWithApp(config.App))
+	}
+	if config.Logger != nil {
+		opts = append(opts, quickgo.ConfigOptionWithLogger(*config.Logger))
+	}
+	if config.GrpcServer != nil {
+		opts = append(opts, quickgo.ConfigOptionWithGrpcServer(config.GrpcServer))
+	}
+	if config.GrpcClient != nil {
+		opts = append(opts, quickgo.ConfigOptionWithGrpcClient(config.GrpcClient))
+	}
+	if config.HTTPServer != nil {
+		opts = append(opts, quickgo.ConfigOptionWithHTTPServer(config.HTTPServer))
+	}
+	for name, server := range config.HTTPServers {
+		opts = append(opts, quickgo.ConfigOptionWithNamedHTTPServer(name, server))
+	}
+	if config.Gorm != nil {
+		opts = append(opts, quickgo.ConfigOptionWithGorm(config.Gorm))
+	}
+	if config.Migrate != nil {
+		opts = append(opts, quickgo.ConfigOptionWithMigrate(config.Migrate))
+	}
+	if config.MongoDB != nil {
+		opts = append(opts, quickgo.ConfigOptionWithMongoDB(config.MongoDB))
+	}
+	if config.Redis != nil {
+		opts = append(opts, quickgo.ConfigOptionWithRedis(config.Redis))
+	}
+	if config.MQ != nil {
+		opts = append(opts, quickgo.ConfigOptionWithMQ(config.MQ))
+	}
+	if config.Tracing != nil {
+		opts = append(opts, quickgo.ConfigOptionWithTracing(config.Tracing))
+	}
+	if config.Metrics != nil {
+		opts = append(opts, quickgo.ConfigOptionWithMetrics(config.Metrics))
+	}
+	if config.Recovery != nil {
+		opts = append(opts, quickgo.ConfigOptionWithRecovery(config.Recovery))
+	}
+	if config.Handover != nil {
+		opts = append(opts, quickgo.ConfigOptionWithHandover(config.Handover))
+	}
+	return opts, nil
+}
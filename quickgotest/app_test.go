@@ -0,0 +1,94 @@
+package quickgotest
+
+import (
+	"context"
+	"io"
+	nethttp "net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	rpc "google.golang.org/grpc"
+	testgrpc "google.golang.org/grpc/interop/grpc_testing"
+
+	"github.com/team-dandelion/quickgo"
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+const appConfig = `
+app:
+  name: orders
+greeting: hello from config
+grpcServer:
+  address: 0.0.0.0
+  port: 50051
+httpServer:
+  enabled: true
+  port: 8080
+`
+
+func TestRunAppServesHTTPAndGrpc(t *testing.T) {
+	app := RunApp(t,
+		WithConfig(quickgo.ConfigFormatYAML, appConfig),
+		WithSetup(func(app *quickgo.Framework) error {
+			if err := app.GrpcServer().RegisterService(func(s *rpc.Server) {
+				testgrpc.RegisterTestServiceServer(s, echoService{})
+			}); err != nil {
+				return err
+			}
+			return app.HTTPServer().RegisterApp(func(a *fiber.App) {
+				a.Get("/greeting", func(c *fiber.Ctx) error {
+					var greeting string
+					if err := quickgo.LoadCustomConfigKeyE("greeting", &greeting); err != nil {
+						return err
+					}
+					logger.Info(c.UserContext(), "greeting served")
+					return c.SendString(greeting)
+				})
+			})
+		}),
+	)
+
+	if app.HTTPAddr() == "127.0.0.1:8080" || app.GrpcAddr() == "127.0.0.1:50051" {
+		t.Fatalf("expected ephemeral ports, got http=%s grpc=%s", app.HTTPAddr(), app.GrpcAddr())
+	}
+
+	resp, err := nethttp.Get(app.HTTPURL("/greeting"))
+	if err != nil {
+		t.Fatalf("GET /greeting failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusOK || string(body) != "hello from config" {
+		t.Fatalf("unexpected response: %d %q", resp.StatusCode, body)
+	}
+
+	client := testgrpc.NewTestServiceClient(app.GrpcConn(t))
+	req := &testgrpc.SimpleRequest{Payload: &testgrpc.Payload{Body: []byte("ping")}}
+	reply, err := client.UnaryCall(context.Background(), req)
+	if err != nil || string(reply.GetPayload().GetBody()) != "ping" {
+		t.Fatalf("unexpected grpc reply: %v, %v", reply, err)
+	}
+
+	app.Logs.AssertLogged(t, logger.LevelInfo, "Framework started successfully")
+	app.Logs.AssertNotLogged(t, logger.LevelError, "")
+	app.Logs.Sink.AssertLogged(t, logger.MatchLevel(logger.LevelInfo), logger.MatchMessage("greeting served"))
+}
+
+func TestRunAppStopsOnCleanup(t *testing.T) {
+	server := &quickgo.HTTPServerConfig{Enabled: true, Port: 8080}
+	var httpAddr string
+	t.Run("app", func(t *testing.T) {
+		app := RunApp(t, WithFrameworkOptions(quickgo.ConfigOptionWithHTTPServer(server)))
+		httpAddr = app.HTTPAddr()
+		if _, err := nethttp.Get("http://" + httpAddr + "/"); err != nil {
+			t.Fatalf("expected http server to be running: %v", err)
+		}
+	})
+
+	if server.Port != 8080 {
+		t.Fatalf("RunApp must not modify the caller's config, port=%d", server.Port)
+	}
+	if _, err := nethttp.Get("http://" + httpAddr + "/"); err == nil {
+		t.Fatal("expected http server to be stopped after subtest cleanup")
+	}
+}
@@ -0,0 +1,269 @@
+// Package quickgotest 提供 gRPC 依赖的进程内替身，用于处理器与客户端的单元测试
+//
+// FakeService 在 bufconn 内存连接上运行真实的 gRPC 服务（无需 etcd 与网络），记录每次调用并支持注入错误与延迟；
+// NewClientManager 创建连接到这些替身的 GrpcClientManager，业务代码按服务名调用，与生产环境一致：
+//
+//	auth := quickgotest.NewFakeService("auth-service", &pb.AuthService_ServiceDesc, &fakeAuth{})
+//	defer auth.Close()
+//	manager := quickgotest.NewClientManager(t, auth)
+//	auth.FailNext("/auth.AuthService/Login", status.Error(codes.Unavailable, "down"))
+//	... 调用被测处理器 ...
+//	auth.AssertCalled(t, "/auth.AuthService/Login", 2)
+//
+// RunApp 在随机空闲端口上启动完整的 Framework（内存配置、日志捕获、测试结束自动 Stop），用于服务的黑盒测试：
+//
+//	app := quickgotest.RunApp(t, quickgotest.WithConfig("yaml", cfg), quickgotest.WithSetup(registerRoutes))
+//	resp, _ := http.Get(app.HTTPURL("/api/v1/users"))
+//	app.Logs.AssertLogged(t, logger.LevelInfo, "user created")
+package quickgotest
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// AnyMethod 匹配全部方法的错误与延迟注入 key
+const AnyMethod = "*"
+
+const bufSize = 1 << 20
+
+// Call 替身服务收到的一次调用
+type Call struct {
+	// gRPC 完整方法名（如 /auth.AuthService/Login）
+	Method string
+	// 请求消息（流式调用为 nil）
+	Request interface{}
+	// 请求携带的 metadata
+	Metadata metadata.MD
+	// 返回给客户端的错误（注入的错误或服务实现返回的错误）
+	Err error
+	// 是否为注入的错误（未执行服务实现）
+	Injected bool
+}
+
+// FakeService 进程内 gRPC 服务替身
+type FakeService struct {
+	name     string
+	listener *bufconn.Listener
+	server   *grpc.Server
+	health   *health.Server
+
+	mu       sync.Mutex
+	calls    []Call
+	failNext map[string][]error
+	errors   map[string]error
+	delays   map[string]time.Duration
+
+	startOnce sync.Once
+	closeOnce sync.Once
+}
+
+// NewFakeService 创建服务替身并注册服务实现，name 为 GrpcClientManager 中使用的服务名
+// desc 与 impl 对应 protoc-gen-go-grpc 生成的 XXX_ServiceDesc 与服务实现；可继续通过 Register 注册其他服务
+// 服务在第一次连接（Dialer）时启动，同时提供标准健康检查服务
+func NewFakeService(name string, desc *grpc.ServiceDesc, impl interface{}) *FakeService {
+	s := &FakeService{
+		name:     name,
+		listener: bufconn.Listen(bufSize),
+		health:   health.NewServer(),
+		failNext: make(map[string][]error),
+		errors:   make(map[string]error),
+		delays:   make(map[string]time.Duration),
+	}
+	s.server = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.unaryInterceptor),
+		grpc.ChainStreamInterceptor(s.streamInterceptor),
+	)
+	healthpb.RegisterHealthServer(s.server, s.health)
+	if desc != nil {
+		s.Register(desc, impl)
+	}
+	return s
+}
+
+// Register 注册服务实现，需在第一次连接前调用
+func (s *FakeService) Register(desc *grpc.ServiceDesc, impl interface{}) {
+	s.server.RegisterService(desc, impl)
+}
+
+// Name 返回服务名
+func (s *FakeService) Name() string {
+	return s.name
+}
+
+// Dialer 返回连接到替身的拨号函数（用于 grpc.WithContextDialer），第一次调用时启动服务
+func (s *FakeService) Dialer() func(ctx context.Context, address string) (net.Conn, error) {
+	s.startOnce.Do(func() {
+		go func() { _ = s.server.Serve(s.listener) }()
+	})
+	return func(ctx context.Context, _ string) (net.Conn, error) {
+		return s.listener.DialContext(ctx)
+	}
+}
+
+// Close 停止服务并关闭内存连接
+func (s *FakeService) Close() {
+	s.closeOnce.Do(func() {
+		s.server.Stop()
+		_ = s.listener.Close()
+	})
+}
+
+// FailNext 使方法的下一次调用返回 err（多次调用按顺序依次生效），method 为 AnyMethod 时匹配任意方法
+func (s *FakeService) FailNext(method string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failNext[method] = append(s.failNext[method], err)
+}
+
+// SetError 使方法的每次调用都返回 err（nil 清除），method 为 AnyMethod 时匹配任意方法
+func (s *FakeService) SetError(method string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		delete(s.errors, method)
+		return
+	}
+	s.errors[method] = err
+}
+
+// SetDelay 为方法的每次调用增加延迟（0 清除），延迟期间请求 context 结束时返回 context 错误
+func (s *FakeService) SetDelay(method string, delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if delay <= 0 {
+		delete(s.delays, method)
+		return
+	}
+	s.delays[method] = delay
+}
+
+// SetServing 设置健康检查状态（service 为空表示整体状态）
+func (s *FakeService) SetServing(service string, serving bool) {
+	status := healthpb.HealthCheckResponse_SERVING
+	if !serving {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	s.health.SetServingStatus(service, status)
+}
+
+// Reset 清除调用记录与注入的错误、延迟
+func (s *FakeService) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+	s.failNext = make(map[string][]error)
+	s.errors = make(map[string]error)
+	s.delays = make(map[string]time.Duration)
+}
+
+// Calls 返回调用记录，指定 method 时只返回该方法的调用
+func (s *FakeService) Calls(method ...string) []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	calls := make([]Call, 0, len(s.calls))
+	for _, call := range s.calls {
+		if len(method) == 0 || call.Method == method[0] {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// CallCount 返回方法的调用次数
+func (s *FakeService) CallCount(method string) int {
+	return len(s.Calls(method))
+}
+
+// LastRequest 返回方法最近一次调用的请求（未调用时返回 nil）
+func (s *FakeService) LastRequest(method string) interface{} {
+	calls := s.Calls(method)
+	if len(calls) == 0 {
+		return nil
+	}
+	return calls[len(calls)-1].Request
+}
+
+// inject 返回方法本次调用需要注入的延迟与错误
+func (s *FakeService) inject(method string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delay, ok := s.delays[method]
+	if !ok {
+		delay = s.delays[AnyMethod]
+	}
+	for _, key := range []string{method, AnyMethod} {
+		if queued := s.failNext[key]; len(queued) > 0 {
+			s.failNext[key] = queued[1:]
+			return delay, queued[0]
+		}
+	}
+	if err, ok := s.errors[method]; ok {
+		return delay, err
+	}
+	return delay, s.errors[AnyMethod]
+}
+
+// record 记录一次调用
+func (s *FakeService) record(call Call) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, call)
+}
+
+// before 执行延迟与错误注入，返回注入的错误
+func (s *FakeService) before(ctx context.Context, method string) error {
+	delay, err := s.inject(method)
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return err
+}
+
+func (s *FakeService) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if isHealthMethod(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if err := s.before(ctx, info.FullMethod); err != nil {
+		s.record(Call{Method: info.FullMethod, Request: req, Metadata: md, Err: err, Injected: true})
+		return nil, err
+	}
+	resp, err := handler(ctx, req)
+	s.record(Call{Method: info.FullMethod, Request: req, Metadata: md, Err: err})
+	return resp, err
+}
+
+func (s *FakeService) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if isHealthMethod(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	md, _ := metadata.FromIncomingContext(ss.Context())
+	if err := s.before(ss.Context(), info.FullMethod); err != nil {
+		s.record(Call{Method: info.FullMethod, Metadata: md, Err: err, Injected: true})
+		return err
+	}
+	err := handler(srv, ss)
+	s.record(Call{Method: info.FullMethod, Metadata: md, Err: err})
+	return err
+}
+
+// isHealthMethod 健康检查调用不记录、不注入
+func isHealthMethod(method string) bool {
+	return strings.HasPrefix(method, "/grpc.health.v1.Health/")
+}
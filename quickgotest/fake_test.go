@@ -0,0 +1,86 @@
+package quickgotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	testgrpc "google.golang.org/grpc/interop/grpc_testing"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const unaryCall = "/grpc.testing.TestService/UnaryCall"
+
+type echoService struct {
+	testgrpc.UnimplementedTestServiceServer
+}
+
+func (echoService) UnaryCall(ctx context.Context, req *testgrpc.SimpleRequest) (*testgrpc.SimpleResponse, error) {
+	return &testgrpc.SimpleResponse{Payload: req.GetPayload()}, nil
+}
+
+func TestFakeServiceThroughClientManager(t *testing.T) {
+	fake := NewFakeService("echo-service", &testgrpc.TestService_ServiceDesc, echoService{})
+	manager := NewClientManager(t, fake)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-tenant-id", "t1")
+	conn, err := manager.Conn(ctx, "echo-service")
+	if err != nil {
+		t.Fatalf("Conn failed: %v", err)
+	}
+	client := testgrpc.NewTestServiceClient(conn)
+	req := &testgrpc.SimpleRequest{Payload: &testgrpc.Payload{Body: []byte("hello")}}
+
+	resp, err := client.UnaryCall(ctx, req)
+	if err != nil || string(resp.GetPayload().GetBody()) != "hello" {
+		t.Fatalf("unexpected response: %v, %v", resp, err)
+	}
+	fake.AssertCalled(t, unaryCall, 1)
+	calls := fake.Calls(unaryCall)
+	if got := calls[0].Metadata.Get("x-tenant-id"); len(got) != 1 || got[0] != "t1" {
+		t.Fatalf("expected metadata to be recorded, got %v", calls[0].Metadata)
+	}
+	if last, ok := fake.LastRequest(unaryCall).(*testgrpc.SimpleRequest); !ok || string(last.GetPayload().GetBody()) != "hello" {
+		t.Fatalf("unexpected last request: %v", fake.LastRequest(unaryCall))
+	}
+
+	// 一次性错误按顺序生效，随后恢复正常
+	fake.FailNext(unaryCall, status.Error(codes.Unavailable, "down"))
+	fake.FailNext(AnyMethod, status.Error(codes.Internal, "boom"))
+	for _, want := range []codes.Code{codes.Unavailable, codes.Internal, codes.OK} {
+		if _, err := client.UnaryCall(ctx, req); status.Code(err) != want {
+			t.Fatalf("expected %s, got %v", want, err)
+		}
+	}
+	if calls := fake.Calls(unaryCall); !calls[1].Injected || calls[3].Injected {
+		t.Fatalf("unexpected injected flags: %+v", calls)
+	}
+
+	fake.SetError(unaryCall, status.Error(codes.PermissionDenied, "denied"))
+	if _, err := client.UnaryCall(ctx, req); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", err)
+	}
+	fake.SetError(unaryCall, nil)
+
+	fake.SetDelay(unaryCall, time.Second)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.UnaryCall(timeoutCtx, req); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded with delay, got %v", err)
+	}
+
+	fake.Reset()
+	fake.AssertNotCalled(t, unaryCall)
+	if err := manager.HealthCheck(ctx, "echo-service", ""); err != nil {
+		t.Fatalf("health check failed: %v", err)
+	}
+}
+
+func TestClientManagerRejectsUnknownService(t *testing.T) {
+	manager := NewClientManager(t, NewFakeService("echo-service", &testgrpc.TestService_ServiceDesc, echoService{}))
+	if _, err := manager.Conn(context.Background(), "missing-service"); err == nil {
+		t.Fatal("expected unknown service to fail")
+	}
+}
@@ -0,0 +1,53 @@
+package quickgotest
+
+import (
+	"testing"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// LogRecorder 捕获测试期间输出的日志条目（所有 Logger，包括框架 Init 创建的默认 Logger）
+// 按字段或 trace_id 查询时使用 Sink 与 logger.Match* 条件
+type LogRecorder struct {
+	// 捕获的条目
+	Sink *logger.TestSink
+}
+
+// NewLogRecorder 开始捕获日志，测试结束时停止
+func NewLogRecorder(t testing.TB) *LogRecorder {
+	r := &LogRecorder{Sink: &logger.TestSink{}}
+	remove := logger.AddEntryHook(r.Sink.Record)
+	t.Cleanup(remove)
+	return r
+}
+
+// Entries 返回已捕获的日志条目
+func (r *LogRecorder) Entries() []logger.LogEntry {
+	return r.Sink.Entries()
+}
+
+// Find 返回级别为 level 且消息包含 substr 的条目
+func (r *LogRecorder) Find(level logger.Level, substr string) []logger.LogEntry {
+	return r.Sink.Find(logger.MatchLevel(level), logger.MatchMessage(substr))
+}
+
+// Reset 清除已捕获的条目
+func (r *LogRecorder) Reset() {
+	r.Sink.Reset()
+}
+
+// AssertLogged 断言输出过级别为 level 且消息包含 substr 的日志
+func (r *LogRecorder) AssertLogged(t testing.TB, level logger.Level, substr string) {
+	t.Helper()
+	if len(r.Find(level, substr)) == 0 {
+		t.Errorf("quickgotest: expected %s log containing %q, got none", level, substr)
+	}
+}
+
+// AssertNotLogged 断言没有输出级别为 level 且消息包含 substr 的日志
+func (r *LogRecorder) AssertNotLogged(t testing.TB, level logger.Level, substr string) {
+	t.Helper()
+	if found := r.Find(level, substr); len(found) > 0 {
+		t.Errorf("quickgotest: expected no %s log containing %q, got %d", level, substr, len(found))
+	}
+}
@@ -0,0 +1,74 @@
+package quickgotest
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/team-dandelion/quickgo"
+)
+
+// ClientConfig 返回连接到替身服务的客户端配置（静态发现、非加密、关闭后台健康检查），
+// 可直接传给 quickgo.ConfigOptionWithGrpcClient 让框架使用替身；未注册替身的服务名创建连接时报错
+func ClientConfig(services ...*FakeService) *quickgo.GrpcClientConfig {
+	addresses := make(map[string]string, len(services))
+	dialers := make(map[string]*FakeService, len(services))
+	for _, service := range services {
+		addresses[service.name] = "quickgotest-" + service.name
+		dialers[service.name] = service
+	}
+	return &quickgo.GrpcClientConfig{
+		Discovery:           "static",
+		StaticAddresses:     addresses,
+		Insecure:            true,
+		HealthCheckInterval: quickgo.Duration(-time.Second),
+		DialOptions: func(serviceName string) []grpc.DialOption {
+			service, ok := dialers[serviceName]
+			if !ok {
+				return nil
+			}
+			return []grpc.DialOption{grpc.WithContextDialer(service.Dialer())}
+		},
+	}
+}
+
+// NewClientManager 创建连接到替身服务的 GrpcClientManager 并注册各服务，测试结束时关闭管理器与替身
+func NewClientManager(t testing.TB, services ...*FakeService) *quickgo.GrpcClientManager {
+	t.Helper()
+	manager, err := quickgo.NewGrpcClientManager(ClientConfig(services...))
+	if err != nil {
+		t.Fatalf("quickgotest: failed to create grpc client manager: %v", err)
+	}
+	for _, service := range services {
+		if err := manager.RegisterService(service.name); err != nil {
+			t.Fatalf("quickgotest: failed to register service %s: %v", service.name, err)
+		}
+	}
+	t.Cleanup(func() {
+		_ = manager.CloseAll()
+		for _, service := range services {
+			service.Close()
+		}
+	})
+	return manager
+}
+
+// AssertCalled 断言方法被调用了 times 次（times < 0 时只要求至少调用一次）
+func (s *FakeService) AssertCalled(t testing.TB, method string, times int) {
+	t.Helper()
+	count := s.CallCount(method)
+	if times < 0 && count == 0 {
+		t.Errorf("quickgotest: expected %s on %s to be called, got no calls", method, s.name)
+	} else if times >= 0 && count != times {
+		t.Errorf("quickgotest: expected %s on %s to be called %d times, got %d", method, s.name, times, count)
+	}
+}
+
+// AssertNotCalled 断言方法未被调用
+func (s *FakeService) AssertNotCalled(t testing.TB, method string) {
+	t.Helper()
+	if count := s.CallCount(method); count != 0 {
+		t.Errorf("quickgotest: expected %s on %s not to be called, got %d calls", method, s.name, count)
+	}
+}
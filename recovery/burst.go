@@ -0,0 +1,65 @@
+package recovery
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBurstThreshold = 20
+	defaultBurstWindow    = time.Minute
+)
+
+// burstDetector 固定窗口内统计服务端错误数，达到阈值时每个窗口触发一次
+type burstDetector struct {
+	threshold int
+	window    time.Duration
+
+	mu      sync.Mutex
+	start   time.Time
+	count   int
+	emitted bool
+}
+
+// newBurstDetector 创建错误突增检测器，禁用时返回 nil
+func newBurstDetector(config *ErrorBurstConfig) (*burstDetector, error) {
+	detector := &burstDetector{threshold: defaultBurstThreshold, window: defaultBurstWindow}
+	if config == nil {
+		return detector, nil
+	}
+	if config.Disabled {
+		return nil, nil
+	}
+	if config.Threshold < 0 {
+		return nil, fmt.Errorf("invalid errorBurst.threshold %d", config.Threshold)
+	}
+	if config.Threshold > 0 {
+		detector.threshold = config.Threshold
+	}
+	if config.Window != "" {
+		window, err := time.ParseDuration(config.Window)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("invalid errorBurst.window %q", config.Window)
+		}
+		detector.window = window
+	}
+	return detector, nil
+}
+
+// record 记录一次错误，返回窗口内错误数、窗口长度以及是否应上报
+func (d *burstDetector) record(now time.Time) (int, time.Duration, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.start.IsZero() || now.Sub(d.start) >= d.window {
+		d.start = now
+		d.count = 0
+		d.emitted = false
+	}
+	d.count++
+	if d.count >= d.threshold && !d.emitted {
+		d.emitted = true
+		return d.count, d.window, true
+	}
+	return d.count, d.window, false
+}
@@ -0,0 +1,39 @@
+package recovery
+
+// Config panic 与错误突增上报配置
+type Config struct {
+	// 是否启用上报
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled"`
+	// Sentry 配置（启用时必填）
+	Sentry *SentryConfig `json:"sentry" yaml:"sentry" toml:"sentry"`
+	// 错误突增检测配置（可选，使用默认阈值）
+	ErrorBurst *ErrorBurstConfig `json:"errorBurst" yaml:"errorBurst" toml:"errorBurst"`
+}
+
+// SentryConfig Sentry 上报配置
+type SentryConfig struct {
+	// 项目 DSN（如：https://<key>@o0.ingest.sentry.io/<project>）
+	DSN string `json:"dsn" yaml:"dsn" toml:"dsn"`
+	// 环境名称，默认使用应用环境
+	Environment string `json:"environment" yaml:"environment" toml:"environment"`
+	// 版本，默认使用 应用名称@应用版本
+	Release string `json:"release" yaml:"release" toml:"release"`
+	// 服务器名称，默认使用主机名
+	ServerName string `json:"serverName" yaml:"serverName" toml:"serverName"`
+	// 采样率（0.0-1.0），默认 1.0
+	SampleRate float64 `json:"sampleRate" yaml:"sampleRate" toml:"sampleRate"`
+	// 单次发送超时（如：5s），默认 5s
+	Timeout string `json:"timeout" yaml:"timeout" toml:"timeout" validate:"duration"`
+	// 待发送队列长度，默认 100，队列满时丢弃事件
+	QueueSize int `json:"queueSize" yaml:"queueSize" toml:"queueSize"`
+}
+
+// ErrorBurstConfig 错误突增检测配置
+type ErrorBurstConfig struct {
+	// 是否禁用错误突增上报（只上报 panic）
+	Disabled bool `json:"disabled" yaml:"disabled" toml:"disabled"`
+	// 窗口内服务端错误数达到该值时上报，默认 20
+	Threshold int `json:"threshold" yaml:"threshold" toml:"threshold"`
+	// 统计窗口（如：1m），默认 1m，每个窗口最多上报一次
+	Window string `json:"window" yaml:"window" toml:"window" validate:"duration"`
+}
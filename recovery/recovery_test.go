@@ -0,0 +1,213 @@
+package recovery
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseSentryDSN(t *testing.T) {
+	endpoint, key, err := parseSentryDSN("https://abc@o1.ingest.sentry.io/sub/42")
+	if err != nil {
+		t.Fatalf("parseSentryDSN failed: %v", err)
+	}
+	if endpoint != "https://o1.ingest.sentry.io/sub/api/42/envelope/" || key != "abc" {
+		t.Fatalf("unexpected endpoint=%q key=%q", endpoint, key)
+	}
+	for _, dsn := range []string{"", "https://o1.ingest.sentry.io/42", "ftp://abc@host/42", "https://abc@host/"} {
+		if _, _, err := parseSentryDSN(dsn); err == nil {
+			t.Fatalf("expected error for dsn %q", dsn)
+		}
+	}
+}
+
+func TestBurstDetectorFiresOncePerWindow(t *testing.T) {
+	detector, err := newBurstDetector(&ErrorBurstConfig{Threshold: 3, Window: "1m"})
+	if err != nil {
+		t.Fatalf("newBurstDetector failed: %v", err)
+	}
+	now := time.Now()
+	var fired int
+	for i := 0; i < 10; i++ {
+		if _, _, fire := detector.record(now); fire {
+			fired++
+		}
+	}
+	if fired != 1 {
+		t.Fatalf("expected 1 burst within window, got %d", fired)
+	}
+	for i := 0; i < 3; i++ {
+		if count, _, fire := detector.record(now.Add(time.Minute)); fire && count != 3 {
+			t.Fatalf("unexpected burst count %d", count)
+		} else if fire {
+			fired++
+		}
+	}
+	if fired != 2 {
+		t.Fatalf("expected burst in next window, got %d total", fired)
+	}
+
+	if detector, err := newBurstDetector(&ErrorBurstConfig{Disabled: true}); err != nil || detector != nil {
+		t.Fatalf("expected nil detector when disabled, got %v, %v", detector, err)
+	}
+	if _, err := newBurstDetector(&ErrorBurstConfig{Window: "soon"}); err == nil {
+		t.Fatal("expected error for invalid window")
+	}
+}
+
+func TestParseStack(t *testing.T) {
+	frames := parseStack(string(debug.Stack()))
+	if len(frames) == 0 {
+		t.Fatal("expected frames")
+	}
+	last := frames[len(frames)-1]
+	if !strings.HasSuffix(last["function"].(string), "debug.Stack") {
+		t.Fatalf("expected innermost frame last, got %v", last["function"])
+	}
+	var found bool
+	for _, frame := range frames {
+		if strings.HasSuffix(frame["function"].(string), "TestParseStack") {
+			found = true
+			if !strings.HasSuffix(frame["filename"].(string), "recovery_test.go") || frame["lineno"].(int) == 0 {
+				t.Fatalf("unexpected frame %v", frame)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("test function missing from frames: %v", frames)
+	}
+}
+
+type sentryEnvelope struct {
+	header map[string]interface{}
+	event  map[string]interface{}
+	auth   string
+	path   string
+}
+
+func newSentryServer(t *testing.T) (*httptest.Server, func() []sentryEnvelope) {
+	t.Helper()
+	var (
+		mu        sync.Mutex
+		envelopes []sentryEnvelope
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		scanner := bufio.NewScanner(strings.NewReader(string(body)))
+		scanner.Buffer(make([]byte, 1<<20), 1<<20)
+		var lines []string
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		if len(lines) != 3 {
+			t.Errorf("expected 3 envelope lines, got %d", len(lines))
+			return
+		}
+		env := sentryEnvelope{auth: r.Header.Get("X-Sentry-Auth"), path: r.URL.Path}
+		_ = json.Unmarshal([]byte(lines[0]), &env.header)
+		_ = json.Unmarshal([]byte(lines[2]), &env.event)
+		mu.Lock()
+		envelopes = append(envelopes, env)
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []sentryEnvelope {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]sentryEnvelope(nil), envelopes...)
+	}
+}
+
+func TestSentryReporterSendsPanicAndBurst(t *testing.T) {
+	server, envelopes := newSentryServer(t)
+	dsn := strings.Replace(server.URL, "http://", "http://key@", 1) + "/7"
+
+	err := Init(&Config{
+		Enabled:    true,
+		Sentry:     &SentryConfig{DSN: dsn},
+		ErrorBurst: &ErrorBurstConfig{Threshold: 2, Window: "1m"},
+	}, "orders", "1.2.3", "production")
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	ctx := context.Background()
+	ReportPanic(ctx, "boom", debug.Stack(), Report{
+		Transport: "http",
+		Method:    "GET",
+		Path:      "/orders/:id",
+		Status:    500,
+		TraceID:   "0123456789abcdef0123456789abcdef",
+		SpanID:    "0123456789abcdef",
+		Metadata:  map[string]string{"client_ip": "10.0.0.1"},
+	})
+	for i := 0; i < 3; i++ {
+		RecordServerError(ctx, Report{Transport: "grpc", Method: "/orders.Orders/Get", Status: 13})
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := Shutdown(shutdownCtx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if GetReporter() != nil {
+		t.Fatal("expected reporter cleared after shutdown")
+	}
+
+	got := envelopes()
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events (panic + burst), got %d", len(got))
+	}
+	var panicEvent, burstEvent map[string]interface{}
+	for _, env := range got {
+		if env.path != "/api/7/envelope/" || !strings.Contains(env.auth, "sentry_key=key") {
+			t.Fatalf("unexpected request path=%q auth=%q", env.path, env.auth)
+		}
+		if env.header["event_id"] != env.event["event_id"] {
+			t.Fatalf("envelope event_id mismatch: %v vs %v", env.header["event_id"], env.event["event_id"])
+		}
+		switch env.event["tags"].(map[string]interface{})["kind"] {
+		case string(ReportKindPanic):
+			panicEvent = env.event
+		case string(ReportKindErrorBurst):
+			burstEvent = env.event
+		}
+	}
+	if panicEvent == nil || burstEvent == nil {
+		t.Fatalf("missing events: panic=%v burst=%v", panicEvent, burstEvent)
+	}
+	if panicEvent["release"] != "orders@1.2.3" || panicEvent["environment"] != "production" || panicEvent["level"] != "fatal" {
+		t.Fatalf("unexpected panic event metadata: %v", panicEvent)
+	}
+	exception := panicEvent["exception"].(map[string]interface{})["values"].([]interface{})[0].(map[string]interface{})
+	if exception["value"] != "boom" || exception["stacktrace"] == nil {
+		t.Fatalf("unexpected exception: %v", exception)
+	}
+	trace := panicEvent["contexts"].(map[string]interface{})["trace"].(map[string]interface{})
+	if trace["trace_id"] != "0123456789abcdef0123456789abcdef" {
+		t.Fatalf("unexpected trace context: %v", trace)
+	}
+	if request := panicEvent["request"].(map[string]interface{}); request["url"] != "/orders/:id" {
+		t.Fatalf("unexpected request: %v", request)
+	}
+	if extra := burstEvent["extra"].(map[string]interface{}); extra["count"] != float64(2) {
+		t.Fatalf("unexpected burst extra: %v", extra)
+	}
+}
+
+func TestInitRequiresDSN(t *testing.T) {
+	if err := Init(&Config{Enabled: true}, "svc", "1.0.0", "local"); err == nil {
+		t.Fatal("expected error without sentry dsn")
+	}
+	if err := Init(&Config{Enabled: false}, "svc", "1.0.0", "local"); err != nil || GetReporter() != nil {
+		t.Fatalf("expected reporting disabled, got reporter=%v err=%v", GetReporter(), err)
+	}
+}
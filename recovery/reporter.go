@@ -0,0 +1,173 @@
+package recovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// ReportKind 上报类型
+type ReportKind string
+
+const (
+	// ReportKindPanic 请求处理 panic
+	ReportKindPanic ReportKind = "panic"
+	// ReportKindErrorBurst 短时间内大量 5xx（gRPC Internal/Unknown/DataLoss）
+	ReportKindErrorBurst ReportKind = "error_burst"
+)
+
+// Report 上报到外部错误追踪系统的事件
+type Report struct {
+	Kind ReportKind
+	// 事件描述（panic 值或错误突增说明）
+	Message string
+	// panic 时的堆栈（debug.Stack() 格式）
+	Stack string
+	// 传输协议：http / grpc / grpc-client / websocket
+	Transport string
+	// HTTP 方法或 gRPC 方法全名
+	Method string
+	// HTTP 路由路径
+	Path string
+	// HTTP 状态码或 gRPC code
+	Status int
+	// 链路信息
+	TraceID string
+	SpanID  string
+	// 请求元数据（如客户端 IP、User-Agent）
+	Metadata map[string]string
+	// 错误突增时窗口内的错误数
+	Count int
+	Time  time.Time
+}
+
+// Reporter 错误上报接口，Report 不应阻塞请求处理（实现方自行异步发送）
+type Reporter interface {
+	Report(ctx context.Context, report *Report)
+}
+
+// Flusher 支持在关闭前发送剩余事件的 Reporter
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+type reporterHolder struct {
+	reporter Reporter
+}
+
+var (
+	globalReporter atomic.Pointer[reporterHolder]
+	globalBurst    atomic.Pointer[burstDetector]
+)
+
+// SetReporter 设置全局 Reporter（nil 表示关闭上报并重置错误突增检测），未配置错误突增检测时使用默认阈值
+func SetReporter(reporter Reporter) {
+	if reporter == nil {
+		globalReporter.Store(nil)
+		globalBurst.Store(nil)
+		return
+	}
+	if globalBurst.Load() == nil {
+		detector, _ := newBurstDetector(nil)
+		globalBurst.Store(detector)
+	}
+	globalReporter.Store(&reporterHolder{reporter: reporter})
+}
+
+// GetReporter 获取全局 Reporter，未设置时返回 nil
+func GetReporter() Reporter {
+	if holder := globalReporter.Load(); holder != nil {
+		return holder.reporter
+	}
+	return nil
+}
+
+// Init 按配置创建 Reporter 并设置为全局 Reporter，未启用时关闭上报
+// service / version / environment 用于填充未配置的 Sentry release、environment
+func Init(config *Config, service, version, environment string) error {
+	if config == nil || !config.Enabled {
+		SetReporter(nil)
+		return nil
+	}
+	if config.Sentry == nil || config.Sentry.DSN == "" {
+		return errors.New("recovery: sentry.dsn is required when recovery reporting is enabled")
+	}
+	sentryConfig := *config.Sentry
+	if sentryConfig.Environment == "" {
+		sentryConfig.Environment = environment
+	}
+	if sentryConfig.Release == "" && service != "" {
+		sentryConfig.Release = service + "@" + version
+	}
+	reporter, err := NewSentryReporter(sentryConfig)
+	if err != nil {
+		return err
+	}
+	detector, err := newBurstDetector(config.ErrorBurst)
+	if err != nil {
+		return err
+	}
+	SetReporter(reporter)
+	globalBurst.Store(detector)
+	return nil
+}
+
+// Shutdown 发送剩余事件并关闭上报（Reporter 实现 Close 时一并关闭）
+func Shutdown(ctx context.Context) error {
+	reporter := GetReporter()
+	SetReporter(nil)
+	switch r := reporter.(type) {
+	case interface{ Close(context.Context) error }:
+		return r.Close(ctx)
+	case Flusher:
+		return r.Flush(ctx)
+	}
+	return nil
+}
+
+// ReportPanic 上报 panic，recovered 为 recover() 的返回值，report 中的请求信息由调用方填充
+func ReportPanic(ctx context.Context, recovered interface{}, stack []byte, report Report) {
+	reporter := GetReporter()
+	if reporter == nil {
+		return
+	}
+	report.Kind = ReportKindPanic
+	report.Message = fmt.Sprint(recovered)
+	report.Stack = string(stack)
+	send(ctx, reporter, &report)
+}
+
+// RecordServerError 记录一次服务端错误（HTTP 5xx 或 gRPC 服务端错误），窗口内达到阈值时上报一次错误突增
+func RecordServerError(ctx context.Context, report Report) {
+	reporter := GetReporter()
+	detector := globalBurst.Load()
+	if reporter == nil || detector == nil {
+		return
+	}
+	count, window, fire := detector.record(time.Now())
+	if !fire {
+		return
+	}
+	report.Kind = ReportKindErrorBurst
+	report.Count = count
+	report.Message = fmt.Sprintf("%d server errors within %s, last: %s %s %s status=%d",
+		count, window, report.Transport, report.Method, report.Path, report.Status)
+	send(ctx, reporter, &report)
+}
+
+func send(ctx context.Context, reporter Reporter, report *Report) {
+	if report.Time.IsZero() {
+		report.Time = time.Now()
+	}
+	if report.TraceID == "" {
+		report.TraceID = logger.GetTraceID(ctx)
+	}
+	if report.SpanID == "" {
+		report.SpanID = logger.GetSpanID(ctx)
+	}
+	reporter.Report(ctx, report)
+}
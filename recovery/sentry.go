@@ -0,0 +1,308 @@
+package recovery
+
+import (
+	"bytes"
+	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+const (
+	defaultSentryTimeout   = 5 * time.Second
+	defaultSentryQueueSize = 100
+	sentryClientName       = "quickgo-recovery/1.0"
+)
+
+// SentryReporter 通过 Sentry envelope 接口异步上报事件
+type SentryReporter struct {
+	config      SentryConfig
+	endpoint    string
+	auth        string
+	dsn         string
+	client      *http.Client
+	queue       chan *Report
+	pending     sync.WaitGroup
+	dropped     atomic.Uint64
+	closeOnce   sync.Once
+	closed      chan struct{}
+	workersDone chan struct{}
+}
+
+// NewSentryReporter 创建 Sentry Reporter 并启动发送协程
+func NewSentryReporter(config SentryConfig) (*SentryReporter, error) {
+	endpoint, publicKey, err := parseSentryDSN(config.DSN)
+	if err != nil {
+		return nil, err
+	}
+	if config.SampleRate < 0 || config.SampleRate > 1 {
+		return nil, fmt.Errorf("invalid sentry.sampleRate %v", config.SampleRate)
+	}
+	if config.SampleRate == 0 {
+		config.SampleRate = 1
+	}
+	timeout := defaultSentryTimeout
+	if config.Timeout != "" {
+		timeout, err = time.ParseDuration(config.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid sentry.timeout %q", config.Timeout)
+		}
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultSentryQueueSize
+	}
+	if config.ServerName == "" {
+		config.ServerName, _ = os.Hostname()
+	}
+
+	r := &SentryReporter{
+		config:      config,
+		endpoint:    endpoint,
+		auth:        fmt.Sprintf("Sentry sentry_version=7, sentry_client=%s, sentry_key=%s", sentryClientName, publicKey),
+		dsn:         config.DSN,
+		client:      &http.Client{Timeout: timeout},
+		queue:       make(chan *Report, config.QueueSize),
+		closed:      make(chan struct{}),
+		workersDone: make(chan struct{}),
+	}
+	go r.run()
+	return r, nil
+}
+
+// parseSentryDSN 解析 DSN，返回 envelope 接口地址与公钥
+func parseSentryDSN(dsn string) (string, string, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid sentry dsn: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" || u.User == nil || u.User.Username() == "" {
+		return "", "", fmt.Errorf("invalid sentry dsn %q, expected scheme://<key>@host/<project>", dsn)
+	}
+	path := strings.TrimSuffix(u.Path, "/")
+	idx := strings.LastIndex(path, "/")
+	projectID := path[idx+1:]
+	if projectID == "" {
+		return "", "", fmt.Errorf("invalid sentry dsn %q, missing project id", dsn)
+	}
+	endpoint := fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, path[:idx], projectID)
+	return endpoint, u.User.Username(), nil
+}
+
+// Report 将事件放入发送队列，按采样率丢弃，队列满时丢弃并计数
+func (r *SentryReporter) Report(ctx context.Context, report *Report) {
+	if r.config.SampleRate < 1 && rand.Float64() >= r.config.SampleRate {
+		return
+	}
+	select {
+	case <-r.closed:
+		return
+	default:
+	}
+	r.pending.Add(1)
+	select {
+	case r.queue <- report:
+	default:
+		r.pending.Done()
+		r.dropped.Add(1)
+		logger.Warn(ctx, "Sentry report queue full, dropping event: kind=%s", report.Kind)
+	}
+}
+
+// Dropped 因队列已满丢弃的事件数
+func (r *SentryReporter) Dropped() uint64 {
+	return r.dropped.Load()
+}
+
+// Flush 等待队列中的事件发送完成（或 ctx 结束）
+func (r *SentryReporter) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		r.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close 发送剩余事件后停止发送协程
+func (r *SentryReporter) Close(ctx context.Context) error {
+	err := r.Flush(ctx)
+	r.closeOnce.Do(func() { close(r.closed) })
+	<-r.workersDone
+	return err
+}
+
+func (r *SentryReporter) run() {
+	defer close(r.workersDone)
+	for {
+		select {
+		case <-r.closed:
+			return
+		case report := <-r.queue:
+			if err := r.send(report); err != nil {
+				logger.Warn(context.Background(), "Failed to send report to Sentry: %v", err)
+			}
+			r.pending.Done()
+		}
+	}
+}
+
+// send 以 envelope 格式发送单个事件
+func (r *SentryReporter) send(report *Report) error {
+	event := r.buildEvent(report)
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	header, _ := json.Marshal(map[string]string{
+		"event_id": event["event_id"].(string),
+		"sent_at":  time.Now().UTC().Format(time.RFC3339Nano),
+		"dsn":      r.dsn,
+	})
+	itemHeader, _ := json.Marshal(map[string]interface{}{"type": "event", "length": len(eventJSON)})
+
+	var body bytes.Buffer
+	body.Write(header)
+	body.WriteByte('\n')
+	body.Write(itemHeader)
+	body.WriteByte('\n')
+	body.Write(eventJSON)
+	body.WriteByte('\n')
+
+	req, err := http.NewRequest(http.MethodPost, r.endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", r.auth)
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sentry responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// buildEvent 将 Report 转换为 Sentry 事件
+func (r *SentryReporter) buildEvent(report *Report) map[string]interface{} {
+	level := "error"
+	exceptionType := "ErrorBurst"
+	if report.Kind == ReportKindPanic {
+		level = "fatal"
+		exceptionType = "panic"
+	}
+
+	tags := map[string]string{"kind": string(report.Kind)}
+	if report.Transport != "" {
+		tags["transport"] = report.Transport
+	}
+	if report.Method != "" {
+		tags["method"] = report.Method
+	}
+	if report.Status != 0 {
+		tags["status"] = strconv.Itoa(report.Status)
+	}
+	if report.TraceID != "" {
+		tags["trace_id"] = report.TraceID
+	}
+
+	exception := map[string]interface{}{"type": exceptionType, "value": report.Message}
+	if frames := parseStack(report.Stack); len(frames) > 0 {
+		exception["stacktrace"] = map[string]interface{}{"frames": frames}
+	}
+
+	event := map[string]interface{}{
+		"event_id":    newEventID(),
+		"timestamp":   report.Time.UTC().Format(time.RFC3339Nano),
+		"platform":    "go",
+		"level":       level,
+		"logger":      "quickgo.recovery",
+		"server_name": r.config.ServerName,
+		"environment": r.config.Environment,
+		"release":     r.config.Release,
+		"tags":        tags,
+		"exception":   map[string]interface{}{"values": []interface{}{exception}},
+	}
+	extra := map[string]interface{}{}
+	if report.Kind == ReportKindErrorBurst {
+		extra["count"] = report.Count
+	}
+	if report.Transport == "http" || report.Transport == "websocket" {
+		request := map[string]interface{}{"method": report.Method, "url": report.Path}
+		if len(report.Metadata) > 0 {
+			request["headers"] = report.Metadata
+		}
+		event["request"] = request
+	} else if len(report.Metadata) > 0 {
+		extra["metadata"] = report.Metadata
+	}
+	if len(extra) > 0 {
+		event["extra"] = extra
+	}
+	if len(report.TraceID) == 32 && len(report.SpanID) == 16 {
+		event["contexts"] = map[string]interface{}{
+			"trace": map[string]string{"trace_id": report.TraceID, "span_id": report.SpanID},
+		}
+	}
+	return event
+}
+
+func newEventID() string {
+	var b [16]byte
+	_, _ = crand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// parseStack 解析 debug.Stack() 输出为 Sentry 栈帧（按调用顺序，最内层在最后）
+func parseStack(stack string) []map[string]interface{} {
+	lines := strings.Split(strings.TrimSpace(stack), "\n")
+	var frames []map[string]interface{}
+	// 第一行为 goroutine 头，之后每两行为一帧：函数名、\t文件:行号 +偏移
+	for i := 1; i+1 < len(lines); i += 2 {
+		function := strings.TrimSpace(lines[i])
+		if idx := strings.LastIndex(function, "("); idx > 0 {
+			function = function[:idx]
+		}
+		location := strings.TrimSpace(lines[i+1])
+		if idx := strings.LastIndex(location, " +0x"); idx > 0 {
+			location = location[:idx]
+		}
+		file, lineNo := location, 0
+		if idx := strings.LastIndex(location, ":"); idx > 0 {
+			file = location[:idx]
+			lineNo, _ = strconv.Atoi(location[idx+1:])
+		}
+		frames = append(frames, map[string]interface{}{
+			"function": function,
+			"filename": file,
+			"abs_path": file,
+			"lineno":   lineNo,
+			"in_app":   !strings.Contains(file, "/go/pkg/mod/") && !strings.HasPrefix(function, "runtime."),
+		})
+	}
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+	return frames
+}
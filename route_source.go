@@ -0,0 +1,171 @@
+package quickgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"gopkg.in/yaml.v3"
+
+	"github.com/team-dandelion/quickgo/grpcep"
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// RouteSource 声明式路由的动态来源（配置文件、etcd 等）
+// Watch 阻塞直到 ctx 结束，路由变化时调用 onChange（传入完整路由列表）
+type RouteSource interface {
+	Watch(ctx context.Context, onChange func(routes []grpcep.RouteConfig)) error
+}
+
+// ConfigRouteSource 从全局配置文件读取路由，文件变化时重新加载
+type ConfigRouteSource struct {
+	key string
+}
+
+// NewConfigRouteSource 创建配置文件路由来源
+// key: 路由列表在配置文件中的键（默认 httpServer.routes）
+func NewConfigRouteSource(key string) *ConfigRouteSource {
+	if key == "" {
+		key = "httpServer.routes"
+	}
+	return &ConfigRouteSource{key: key}
+}
+
+// Watch 监听配置文件变化
+func (s *ConfigRouteSource) Watch(ctx context.Context, onChange func(routes []grpcep.RouteConfig)) error {
+	changes := make(chan struct{}, 1)
+	if err := WatchConfig(func() {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changes:
+			var routes []grpcep.RouteConfig
+			if err := LoadCustomConfigKeyE(s.key, &routes); err != nil {
+				logger.Error(ctx, "Failed to load declarative routes: key=%s, error=%v", s.key, err)
+				continue
+			}
+			onChange(routes)
+		}
+	}
+}
+
+// EtcdRouteSourceConfig etcd 路由来源配置
+type EtcdRouteSourceConfig struct {
+	// etcd 端点列表
+	Endpoints []string `json:"endpoints" yaml:"endpoints" toml:"endpoints"`
+	// 连接超时 示例：5s（默认 5s）
+	DialTimeout string `json:"dialTimeout" yaml:"dialTimeout" toml:"dialTimeout"`
+	// 路由列表所在的 key，值为 YAML 或 JSON 格式的路由数组
+	Key      string `json:"key" yaml:"key" toml:"key"`
+	Username string `json:"username" yaml:"username" toml:"username"`
+	Password string `json:"password" yaml:"password" toml:"password"`
+}
+
+// EtcdRouteSource 从 etcd key 读取路由，key 变化时重新加载
+type EtcdRouteSource struct {
+	client *clientv3.Client
+	key    string
+}
+
+// NewEtcdRouteSource 创建 etcd 路由来源
+func NewEtcdRouteSource(config *EtcdRouteSourceConfig) (*EtcdRouteSource, error) {
+	if config == nil {
+		return nil, errors.New("config is nil")
+	}
+	if len(config.Endpoints) == 0 {
+		return nil, errors.New("etcd endpoints are required")
+	}
+	if config.Key == "" {
+		return nil, errors.New("etcd route key is required")
+	}
+	dialTimeout, err := parseDurationOrDefault(config.DialTimeout, defaultEtcdDialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse dialTimeout %s: %w", config.DialTimeout, err)
+	}
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   config.Endpoints,
+		DialTimeout: dialTimeout,
+		Username:    config.Username,
+		Password:    config.Password,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create etcd client: %w", err)
+	}
+	return NewEtcdRouteSourceWithClient(client, config.Key), nil
+}
+
+// NewEtcdRouteSourceWithClient 使用已有的 etcd 客户端创建路由来源（客户端由调用方负责关闭）
+func NewEtcdRouteSourceWithClient(client *clientv3.Client, key string) *EtcdRouteSource {
+	return &EtcdRouteSource{client: client, key: key}
+}
+
+// Watch 读取当前路由并监听 key 变化；连接中断时按 5s 间隔重试
+func (s *EtcdRouteSource) Watch(ctx context.Context, onChange func(routes []grpcep.RouteConfig)) error {
+	for {
+		err := s.watchOnce(ctx, onChange)
+		if ctx.Err() != nil {
+			return nil
+		}
+		logger.Warn(ctx, "Etcd route watch interrupted, retrying: key=%s, error=%v", s.key, err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+func (s *EtcdRouteSource) watchOnce(ctx context.Context, onChange func(routes []grpcep.RouteConfig)) error {
+	resp, err := s.client.Get(ctx, s.key)
+	if err != nil {
+		return err
+	}
+	if len(resp.Kvs) > 0 {
+		s.apply(ctx, resp.Kvs[0].Value, onChange)
+	}
+
+	watchCtx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+	defer cancel()
+	for watchResp := range s.client.Watch(watchCtx, s.key, clientv3.WithRev(resp.Header.Revision+1)) {
+		if err := watchResp.Err(); err != nil {
+			return err
+		}
+		for _, event := range watchResp.Events {
+			if event.Type == clientv3.EventTypeDelete {
+				s.apply(ctx, nil, onChange)
+				continue
+			}
+			s.apply(ctx, event.Kv.Value, onChange)
+		}
+	}
+	return errors.New("watch channel closed")
+}
+
+// apply 解析路由列表（YAML 或 JSON），解析失败时保留当前路由
+func (s *EtcdRouteSource) apply(ctx context.Context, value []byte, onChange func(routes []grpcep.RouteConfig)) {
+	var routes []grpcep.RouteConfig
+	if len(value) > 0 {
+		if err := yaml.Unmarshal(value, &routes); err != nil {
+			logger.Error(ctx, "Failed to parse declarative routes from etcd: key=%s, error=%v", s.key, err)
+			return
+		}
+	}
+	onChange(routes)
+}
+
+// Close 关闭 etcd 客户端
+func (s *EtcdRouteSource) Close() error {
+	return s.client.Close()
+}
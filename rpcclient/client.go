@@ -0,0 +1,234 @@
+// Package rpcclient 为 protoc-gen-quickgo-client 生成的类型化客户端提供运行时支持
+//
+// 生成的客户端内置服务连接查找、默认超时、重试与熔断策略，并将 gRPC status 错误及
+// CommonResp 业务失败统一转换为 gerr，业务代码无需再处理 GetConn + 原始存根 + status：
+//
+//	authcli := auth.NewAuthServiceQuickClient(app.GrpcClientManager(), "auth-server")
+//	resp, err := authcli.Login(ctx, &auth.LoginRequest{Username: "alice"})
+package rpcclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/team-dandelion/quickgo/grpcep"
+	"github.com/team-dandelion/quickgo/resilience"
+)
+
+// ConnProvider 按服务名获取连接（*quickgo.GrpcClientManager 已实现）
+type ConnProvider interface {
+	Conn(ctx context.Context, serviceName string) (grpc.ClientConnInterface, error)
+}
+
+// Options 客户端调用策略
+type Options struct {
+	// 单次调用总超时（ctx 已有截止时间时不覆盖，<= 0 表示不设置）
+	Timeout time.Duration
+	// 重试配置（nil 表示不重试），RetryIf 为空时仅重试 Unavailable
+	// 仅幂等或无副作用的方法会自动重试，分类来自 Idempotency 或 proto 的 idempotency_level 选项
+	Retry *resilience.RetryConfig
+	// 方法幂等性分类（key 为 gRPC 完整方法名），优先于 proto 选项
+	Idempotency map[string]Idempotency
+	// 允许重试未分类的方法（默认 false）
+	RetryUnclassified bool
+	// 客户端包含的方法，用于启动时输出调用策略报告
+	Methods []string
+	// 熔断配置（nil 表示不熔断），按方法独立熔断；IsFailure 为空时仅统计服务端/网络故障
+	CircuitBreaker *resilience.CircuitConfig
+	// 覆盖调用的服务名（生成的 SDK 客户端内置默认服务名）
+	ServiceName string
+	// 每次调用（含重试）写入的 outgoing metadata，如认证令牌
+	Metadata []MetadataFunc
+}
+
+// Option 调用策略选项
+type Option func(*Options)
+
+// DefaultOptions 默认策略：5s 超时，Unavailable 时最多尝试 3 次，按方法熔断
+func DefaultOptions() Options {
+	retry := resilience.DefaultRetryConfig()
+	circuit := resilience.DefaultCircuitConfig()
+	return Options{
+		Timeout:        5 * time.Second,
+		Retry:          &retry,
+		CircuitBreaker: &circuit,
+	}
+}
+
+// WithTimeout 设置调用超时
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = timeout
+	}
+}
+
+// WithRetry 设置重试配置
+func WithRetry(config resilience.RetryConfig) Option {
+	return func(o *Options) {
+		o.Retry = &config
+	}
+}
+
+// WithoutRetry 禁用重试
+func WithoutRetry() Option {
+	return func(o *Options) {
+		o.Retry = nil
+	}
+}
+
+// WithCircuitBreaker 设置熔断配置
+func WithCircuitBreaker(config resilience.CircuitConfig) Option {
+	return func(o *Options) {
+		o.CircuitBreaker = &config
+	}
+}
+
+// WithoutCircuitBreaker 禁用熔断
+func WithoutCircuitBreaker() Option {
+	return func(o *Options) {
+		o.CircuitBreaker = nil
+	}
+}
+
+// WithServiceName 覆盖调用的服务名（GrpcClientManager 中注册的名称）
+func WithServiceName(serviceName string) Option {
+	return func(o *Options) {
+		o.ServiceName = serviceName
+	}
+}
+
+// Client 单个服务的调用器，由生成的类型化客户端持有
+type Client struct {
+	provider ConnProvider
+	service  string
+	timeout  time.Duration
+	retryer  *resilience.Retryer
+	breakers *resilience.CircuitBreakerManager
+	metadata []MetadataFunc
+
+	idempotency       map[string]Idempotency
+	retryUnclassified bool
+	methods           []string
+	// 方法调用策略缓存（method -> MethodPolicy）
+	policies sync.Map
+}
+
+// New 创建服务调用器（WithServiceName 优先于 serviceName）
+func New(provider ConnProvider, serviceName string, opts ...Option) *Client {
+	options := DefaultOptions()
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+
+	if options.ServiceName != "" {
+		serviceName = options.ServiceName
+	}
+	c := &Client{
+		provider:          provider,
+		service:           serviceName,
+		timeout:           options.Timeout,
+		metadata:          options.Metadata,
+		idempotency:       options.Idempotency,
+		retryUnclassified: options.RetryUnclassified,
+		methods:           options.Methods,
+	}
+	if options.Retry != nil {
+		config := *options.Retry
+		if config.RetryIf == nil {
+			config.RetryIf = isRetryable
+		}
+		c.retryer = resilience.NewRetryer(config)
+	}
+	if options.CircuitBreaker != nil {
+		config := *options.CircuitBreaker
+		if config.IsFailure == nil {
+			config.IsFailure = isFailure
+		}
+		c.breakers = resilience.NewCircuitBreakerManager(config)
+	}
+	c.logPolicyReport()
+	return c
+}
+
+// ServiceName 返回调用的服务名
+func (c *Client) ServiceName() string {
+	return c.service
+}
+
+// Call 通过服务连接执行一次类型化调用
+// method 为 gRPC 完整方法名（如 /auth.AuthService/Login），用于熔断分组与重试安全检查（未分类的方法不重试）；
+// 返回的错误均为 *gerr.GErr：status 错误按 code 转换，CommonResp 业务失败转换为业务错误（此时同时返回响应）
+func Call[Resp any](ctx context.Context, c *Client, method string, call func(ctx context.Context, conn grpc.ClientConnInterface) (Resp, error)) (Resp, error) {
+	var zero Resp
+	if c == nil || c.provider == nil {
+		return zero, ToGErr(status.Error(codes.FailedPrecondition, "rpc client is not initialized"))
+	}
+	if c.timeout > 0 {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+		}
+	}
+
+	var resp Resp
+	invoke := func(ctx context.Context) error {
+		ctx, err := c.withMetadata(ctx)
+		if err != nil {
+			return err
+		}
+		conn, err := c.provider.Conn(ctx, c.service)
+		if err != nil {
+			return status.Errorf(codes.Unavailable, "service %s unavailable: %v", c.service, err)
+		}
+		result, err := call(ctx, conn)
+		if err != nil {
+			return err
+		}
+		resp = result
+		return nil
+	}
+	if c.breakers != nil {
+		breaker := c.breakers.Get(c.service + method)
+		guarded := invoke
+		invoke = func(ctx context.Context) error {
+			return breaker.Execute(ctx, guarded)
+		}
+	}
+
+	var err error
+	if c.retryer != nil && c.Policy(method).Retry {
+		err = c.retryer.Do(ctx, invoke)
+	} else {
+		err = invoke(ctx)
+	}
+	if err != nil {
+		return zero, ToGErr(err)
+	}
+	if err := grpcep.ResponseError(resp); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
+
+// isRetryable 默认仅重试 Unavailable（请求未被服务端处理，重试是安全的）
+func isRetryable(err error) bool {
+	return status.Code(err) == codes.Unavailable
+}
+
+// isFailure 默认仅统计服务端/网络故障，业务错误与参数错误不触发熔断
+func isFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown, codes.ResourceExhausted, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
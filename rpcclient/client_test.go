@@ -0,0 +1,172 @@
+package rpcclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/team-dandelion/quickgo/gerr"
+	"github.com/team-dandelion/quickgo/grpcep"
+	"github.com/team-dandelion/quickgo/resilience"
+)
+
+type connProviderFunc func(ctx context.Context, serviceName string) (grpc.ClientConnInterface, error)
+
+func (f connProviderFunc) Conn(ctx context.Context, serviceName string) (grpc.ClientConnInterface, error) {
+	return f(ctx, serviceName)
+}
+
+type testResp struct {
+	CommonResp *grpcep.CommonResp
+}
+
+func staticProvider() ConnProvider {
+	return connProviderFunc(func(ctx context.Context, serviceName string) (grpc.ClientConnInterface, error) {
+		return nil, nil
+	})
+}
+
+func fastRetry() Option {
+	return WithRetry(resilience.RetryConfig{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond})
+}
+
+func TestCallRetriesUnavailable(t *testing.T) {
+	client := New(staticProvider(), "svc", fastRetry(), WithIdempotency(Idempotent, "/svc.S/M"))
+	attempts := 0
+	resp, err := Call(context.Background(), client, "/svc.S/M", func(ctx context.Context, conn grpc.ClientConnInterface) (*testResp, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, status.Error(codes.Unavailable, "down")
+		}
+		return &testResp{}, nil
+	})
+	if err != nil || resp == nil {
+		t.Fatalf("expected success after retries, resp=%v err=%v", resp, err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestCallConvertsStatusErrorWithoutRetry(t *testing.T) {
+	client := New(staticProvider(), "svc", fastRetry(), WithIdempotency(Idempotent, "/svc.S/M"))
+	attempts := 0
+	_, err := Call(context.Background(), client, "/svc.S/M", func(ctx context.Context, conn grpc.ClientConnInterface) (*testResp, error) {
+		attempts++
+		return nil, status.Error(codes.InvalidArgument, "bad name")
+	})
+	if attempts != 1 {
+		t.Fatalf("expected InvalidArgument not to be retried, got %d attempts", attempts)
+	}
+	var gErr *gerr.GErr
+	if !errors.As(err, &gErr) {
+		t.Fatalf("expected *gerr.GErr, got %T", err)
+	}
+	if gErr.Type != gerr.TypeValidation || gErr.Code != grpcep.ParamsErrCode || gErr.Msg != "bad name" {
+		t.Fatalf("unexpected converted error: %+v", gErr)
+	}
+	if gErr.GetMetadataValue(GRPCCodeMetadataKey) != codes.InvalidArgument.String() {
+		t.Fatalf("expected grpc code metadata, got %v", gErr.Metadata)
+	}
+}
+
+func TestCallReturnsBusinessErrorFromCommonResp(t *testing.T) {
+	client := New(staticProvider(), "svc")
+	resp, err := Call(context.Background(), client, "/svc.S/M", func(ctx context.Context, conn grpc.ClientConnInterface) (*testResp, error) {
+		return &testResp{CommonResp: &grpcep.CommonResp{Code: 40010, Msg: "wrong password"}}, nil
+	})
+	if resp == nil {
+		t.Fatal("expected response to be returned alongside business error")
+	}
+	if !gerr.IsType(err, gerr.TypeBusiness) || gerr.GetCode(err) != 40010 {
+		t.Fatalf("expected business error 40010, got %v", err)
+	}
+}
+
+func TestCallOpensCircuitOnServerFailures(t *testing.T) {
+	client := New(staticProvider(), "svc", WithoutRetry(), WithCircuitBreaker(resilience.CircuitConfig{
+		FailureThreshold: 2,
+		OpenDuration:     time.Minute,
+	}))
+	calls := 0
+	call := func(ctx context.Context, conn grpc.ClientConnInterface) (*testResp, error) {
+		calls++
+		return nil, status.Error(codes.Internal, "boom")
+	}
+	for i := 0; i < 3; i++ {
+		_, _ = Call(context.Background(), client, "/svc.S/M", call)
+	}
+	if calls != 2 {
+		t.Fatalf("expected circuit to open after 2 failures, got %d calls", calls)
+	}
+	if _, err := Call(context.Background(), client, "/svc.S/Other", call); err == nil || calls != 3 {
+		t.Fatalf("expected other methods to use their own breaker, calls=%d err=%v", calls, err)
+	}
+}
+
+func TestCallAppliesDefaultTimeout(t *testing.T) {
+	client := New(staticProvider(), "svc", WithoutRetry(), WithTimeout(10*time.Millisecond))
+	_, err := Call(context.Background(), client, "/svc.S/M", func(ctx context.Context, conn grpc.ClientConnInterface) (*testResp, error) {
+		<-ctx.Done()
+		return nil, status.FromContextError(ctx.Err()).Err()
+	})
+	if !gerr.IsType(err, gerr.TypeTimeout) {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+}
+
+func TestCallRestoresGErrEncodedInStatus(t *testing.T) {
+	client := New(staticProvider(), "svc", fastRetry())
+	_, err := Call(context.Background(), client, "/svc.S/M", func(ctx context.Context, conn grpc.ClientConnInterface) (*testResp, error) {
+		return nil, gerr.ToGRPCStatus(gerr.NewForbidden(40301, "no access")).Err()
+	})
+	var gErr *gerr.GErr
+	if !errors.As(err, &gErr) {
+		t.Fatalf("expected *gerr.GErr, got %T", err)
+	}
+	if gErr.Code != 40301 || gErr.Type != gerr.TypeForbidden || gErr.Msg != "no access" {
+		t.Fatalf("unexpected restored error: %+v", gErr)
+	}
+	if gErr.GetMetadataValue(GRPCCodeMetadataKey) != codes.PermissionDenied.String() {
+		t.Fatalf("expected grpc code metadata, got %v", gErr.Metadata)
+	}
+}
+
+func TestCallInjectsMetadataAndServiceName(t *testing.T) {
+	var service string
+	provider := connProviderFunc(func(ctx context.Context, serviceName string) (grpc.ClientConnInterface, error) {
+		service = serviceName
+		return nil, nil
+	})
+	client := New(provider, "auth", WithServiceName("auth-server"), WithBearerToken(func(ctx context.Context) (string, error) {
+		return "t0k3n", nil
+	}))
+	_, err := Call(context.Background(), client, "/auth.AuthService/Login", func(ctx context.Context, conn grpc.ClientConnInterface) (*testResp, error) {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		if got := md.Get("authorization"); len(got) != 1 || got[0] != "Bearer t0k3n" {
+			t.Fatalf("unexpected authorization metadata: %v", got)
+		}
+		return &testResp{}, nil
+	})
+	if err != nil || service != "auth-server" {
+		t.Fatalf("unexpected result: service=%s err=%v", service, err)
+	}
+
+	failing := New(staticProvider(), "auth", WithBearerToken(func(ctx context.Context) (string, error) {
+		return "", errors.New("token expired")
+	}))
+	_, err = Call(context.Background(), failing, "/auth.AuthService/Login", func(ctx context.Context, conn grpc.ClientConnInterface) (*testResp, error) {
+		t.Fatal("call should not run without metadata")
+		return nil, nil
+	})
+	var gErr *gerr.GErr
+	if !errors.As(err, &gErr) || gErr.GetMetadataValue(GRPCCodeMetadataKey) != codes.Unauthenticated.String() {
+		t.Fatalf("expected Unauthenticated error, got %v", err)
+	}
+}
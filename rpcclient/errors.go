@@ -0,0 +1,64 @@
+package rpcclient
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/team-dandelion/quickgo/gerr"
+	"github.com/team-dandelion/quickgo/grpcep"
+)
+
+// GRPCCodeMetadataKey 转换后的 gerr 中记录原始 gRPC code 的元数据 key
+const GRPCCodeMetadataKey = "grpc_code"
+
+// ToGErr 将调用错误转换为 gerr：已是 gerr 时原样返回，携带 gerr 错误码的 status 还原为原始错误，其他 gRPC status 按 code 映射错误类型
+func ToGErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	var gErr *gerr.GErr
+	if errors.As(err, &gErr) {
+		return gErr
+	}
+
+	// 服务端通过 gerr.ToGRPCStatus 返回的错误还原错误码与类型
+	if st, ok := status.FromError(err); ok {
+		if gErr = gerr.FromGRPCStatus(st); gErr != nil && gErr.Code != 0 {
+			return gErr.WithMetadata(GRPCCodeMetadataKey, st.Code().String())
+		}
+	}
+
+	code := status.Code(err)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	}
+	msg := err.Error()
+	if st, ok := status.FromError(err); ok && st.Message() != "" {
+		msg = st.Message()
+	}
+
+	errCode, errType := int32(grpcep.InternalErrCode), gerr.TypeInternal
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange:
+		errCode, errType = grpcep.ParamsErrCode, gerr.TypeValidation
+	case codes.NotFound:
+		errCode, errType = grpcep.FailCode, gerr.TypeNotFound
+	case codes.Unauthenticated:
+		errCode, errType = grpcep.FailCode, gerr.TypeUnauthorized
+	case codes.PermissionDenied:
+		errCode, errType = grpcep.FailCode, gerr.TypeForbidden
+	case codes.AlreadyExists, codes.FailedPrecondition, codes.Aborted:
+		errCode, errType = grpcep.FailCode, gerr.TypeBusiness
+	case codes.DeadlineExceeded, codes.Canceled:
+		errType = gerr.TypeTimeout
+	case codes.Unavailable, codes.ResourceExhausted:
+		errType = gerr.TypeNetwork
+	}
+	return gerr.New(errCode, errType, msg).WithCause(err).WithMetadata(GRPCCodeMetadataKey, code.String())
+}
@@ -0,0 +1,176 @@
+package rpcclient
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// Idempotency 方法的幂等性分类，决定是否允许自动重试
+type Idempotency int
+
+const (
+	// IdempotencyUnknown 未分类，按可能修改数据处理，不自动重试
+	IdempotencyUnknown Idempotency = iota
+	// NoSideEffects 无副作用（只读查询），可安全重试
+	NoSideEffects
+	// Idempotent 幂等（重复执行结果一致），可安全重试
+	Idempotent
+	// NonIdempotent 非幂等（如创建订单、扣款），不自动重试
+	NonIdempotent
+)
+
+// String 返回分类名称
+func (i Idempotency) String() string {
+	switch i {
+	case NoSideEffects:
+		return "no_side_effects"
+	case Idempotent:
+		return "idempotent"
+	case NonIdempotent:
+		return "non_idempotent"
+	default:
+		return "unknown"
+	}
+}
+
+// RetrySafe 是否可安全重试
+func (i Idempotency) RetrySafe() bool {
+	return i == NoSideEffects || i == Idempotent
+}
+
+// MethodPolicy 单个方法生效的调用策略
+type MethodPolicy struct {
+	// gRPC 完整方法名
+	Method      string
+	Idempotency Idempotency
+	// 分类来源："config"（WithIdempotency）、"proto"（idempotency_level 选项）或空（未分类）
+	Source string
+	// 是否启用自动重试
+	Retry bool
+	// 未启用重试的原因（重试已启用或未配置重试时为空）
+	RetryRefused string
+}
+
+// WithIdempotency 显式声明方法的幂等性分类（优先于 proto 选项），methods 为 gRPC 完整方法名
+func WithIdempotency(level Idempotency, methods ...string) Option {
+	return func(o *Options) {
+		if o.Idempotency == nil {
+			o.Idempotency = make(map[string]Idempotency, len(methods))
+		}
+		for _, method := range methods {
+			o.Idempotency[method] = level
+		}
+	}
+}
+
+// WithUnclassifiedRetry 允许重试未分类的方法（关闭重试安全检查，仅用于迁移期）
+func WithUnclassifiedRetry() Option {
+	return func(o *Options) {
+		o.RetryUnclassified = true
+	}
+}
+
+// WithMethods 声明客户端包含的方法（生成的客户端自动设置），用于启动时输出调用策略报告
+func WithMethods(methods ...string) Option {
+	return func(o *Options) {
+		o.Methods = append(o.Methods, methods...)
+	}
+}
+
+// classify 获取方法的幂等性分类：优先使用显式配置，其次读取已注册 proto 描述中的 idempotency_level 选项
+func (c *Client) classify(method string) (Idempotency, string) {
+	if level, ok := c.idempotency[method]; ok {
+		return level, "config"
+	}
+	if level, ok := protoIdempotency(method); ok {
+		return level, "proto"
+	}
+	return IdempotencyUnknown, ""
+}
+
+// protoIdempotency 从全局 proto 注册表读取方法的 idempotency_level 选项
+func protoIdempotency(method string) (Idempotency, bool) {
+	service, name, ok := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	if !ok {
+		return IdempotencyUnknown, false
+	}
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service + "." + name))
+	if err != nil {
+		return IdempotencyUnknown, false
+	}
+	md, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok {
+		return IdempotencyUnknown, false
+	}
+	options, ok := md.Options().(*descriptorpb.MethodOptions)
+	if !ok {
+		return IdempotencyUnknown, false
+	}
+	switch options.GetIdempotencyLevel() {
+	case descriptorpb.MethodOptions_NO_SIDE_EFFECTS:
+		return NoSideEffects, true
+	case descriptorpb.MethodOptions_IDEMPOTENT:
+		return Idempotent, true
+	default:
+		return IdempotencyUnknown, false
+	}
+}
+
+// Policy 返回方法生效的调用策略
+func (c *Client) Policy(method string) MethodPolicy {
+	if cached, ok := c.policies.Load(method); ok {
+		return cached.(MethodPolicy)
+	}
+	level, source := c.classify(method)
+	policy := MethodPolicy{Method: method, Idempotency: level, Source: source}
+	if c.retryer != nil {
+		switch {
+		case level.RetrySafe() || (level == IdempotencyUnknown && c.retryUnclassified):
+			policy.Retry = true
+		case level == NonIdempotent:
+			policy.RetryRefused = "method is non-idempotent"
+		default:
+			policy.RetryRefused = "method idempotency is not classified"
+		}
+	}
+	actual, loaded := c.policies.LoadOrStore(method, policy)
+	if !loaded && policy.RetryRefused != "" && level == IdempotencyUnknown {
+		logger.Warn(context.Background(), "Retry disabled for unclassified gRPC method: service=%s, method=%s (declare idempotency_level in proto or use rpcclient.WithIdempotency)", c.service, method)
+	}
+	return actual.(MethodPolicy)
+}
+
+// Policies 返回已声明方法（WithMethods）的生效调用策略
+func (c *Client) Policies() []MethodPolicy {
+	policies := make([]MethodPolicy, 0, len(c.methods))
+	for _, method := range c.methods {
+		policies = append(policies, c.Policy(method))
+	}
+	return policies
+}
+
+// logPolicyReport 启动时输出调用策略报告（仅在配置了重试时输出）
+func (c *Client) logPolicyReport() {
+	if c.retryer == nil || len(c.methods) == 0 {
+		return
+	}
+	ctx := context.Background()
+	refused := 0
+	for _, policy := range c.Policies() {
+		if policy.Retry {
+			logger.Info(ctx, "gRPC client policy: service=%s, method=%s, idempotency=%s, source=%s, retry=enabled", c.service, policy.Method, policy.Idempotency, policy.Source)
+			continue
+		}
+		refused++
+		if policy.Idempotency != IdempotencyUnknown {
+			logger.Info(ctx, "gRPC client policy: service=%s, method=%s, idempotency=%s, source=%s, retry=disabled (%s)", c.service, policy.Method, policy.Idempotency, policy.Source, policy.RetryRefused)
+		}
+	}
+	logger.Info(ctx, "gRPC client policy report: service=%s, methods=%d, retry_enabled=%d, retry_disabled=%d", c.service, len(c.methods), len(c.methods)-refused, refused)
+}
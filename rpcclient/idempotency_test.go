@@ -0,0 +1,98 @@
+package rpcclient
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func init() {
+	// 注册带 idempotency_level 选项的测试服务
+	file := &descriptorpb.FileDescriptorProto{
+		Name:        proto.String("rpcclient_idempotency_test.proto"),
+		Package:     proto.String("rpcclienttest"),
+		Syntax:      proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("Msg")}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Orders"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("Get"), InputType: proto.String(".rpcclienttest.Msg"), OutputType: proto.String(".rpcclienttest.Msg"),
+					Options: &descriptorpb.MethodOptions{IdempotencyLevel: descriptorpb.MethodOptions_NO_SIDE_EFFECTS.Enum()}},
+				{Name: proto.String("Create"), InputType: proto.String(".rpcclienttest.Msg"), OutputType: proto.String(".rpcclienttest.Msg")},
+			},
+		}},
+	}
+	fd, err := protodesc.NewFile(file, protoregistry.GlobalFiles)
+	if err != nil {
+		panic(err)
+	}
+	if err := protoregistry.GlobalFiles.RegisterFile(fd); err != nil {
+		panic(err)
+	}
+}
+
+func countAttempts(t *testing.T, client *Client, method string) int {
+	t.Helper()
+	attempts := 0
+	_, _ = Call(context.Background(), client, method, func(ctx context.Context, conn grpc.ClientConnInterface) (*testResp, error) {
+		attempts++
+		return nil, status.Error(codes.Unavailable, "down")
+	})
+	return attempts
+}
+
+func TestCallRetriesOnlyClassifiedMethods(t *testing.T) {
+	client := New(staticProvider(), "orders", fastRetry(), WithoutCircuitBreaker(),
+		WithIdempotency(NonIdempotent, "/rpcclienttest.Orders/Get"),
+		WithIdempotency(Idempotent, "/rpcclienttest.Orders/Cancel"))
+
+	cases := map[string]int{
+		// 显式配置优先于 proto 选项
+		"/rpcclienttest.Orders/Get":    1,
+		"/rpcclienttest.Orders/Cancel": 3,
+		"/rpcclienttest.Orders/Create": 1,
+		"/unknown.Service/Method":      1,
+	}
+	for method, want := range cases {
+		if got := countAttempts(t, client, method); got != want {
+			t.Fatalf("%s: expected %d attempts, got %d", method, want, got)
+		}
+	}
+}
+
+func TestPolicyReadsProtoIdempotencyLevel(t *testing.T) {
+	client := New(staticProvider(), "orders", fastRetry(),
+		WithMethods("/rpcclienttest.Orders/Get", "/rpcclienttest.Orders/Create"))
+
+	policies := client.Policies()
+	if len(policies) != 2 {
+		t.Fatalf("expected 2 policies, got %+v", policies)
+	}
+	get, create := policies[0], policies[1]
+	if get.Idempotency != NoSideEffects || get.Source != "proto" || !get.Retry {
+		t.Fatalf("unexpected policy for Get: %+v", get)
+	}
+	if create.Idempotency != IdempotencyUnknown || create.Retry || create.RetryRefused == "" {
+		t.Fatalf("expected retry to be refused for unclassified Create: %+v", create)
+	}
+	if got := countAttempts(t, client, "/rpcclienttest.Orders/Get"); got != 3 {
+		t.Fatalf("expected proto-classified method to be retried, got %d attempts", got)
+	}
+}
+
+func TestUnclassifiedRetryOptOut(t *testing.T) {
+	client := New(staticProvider(), "orders", fastRetry(), WithoutCircuitBreaker(), WithUnclassifiedRetry())
+	if got := countAttempts(t, client, "/rpcclienttest.Orders/Create"); got != 3 {
+		t.Fatalf("expected unclassified method to be retried when opted out, got %d attempts", got)
+	}
+	if got := countAttempts(t, New(staticProvider(), "orders", WithoutRetry()), "/rpcclienttest.Orders/Get"); got != 1 {
+		t.Fatalf("expected no retries without retry config, got %d attempts", got)
+	}
+}
@@ -0,0 +1,51 @@
+package rpcclient
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataFunc 返回写入 outgoing context 的调用元数据，返回错误时调用以 Unauthenticated 失败且不重试
+type MetadataFunc func(ctx context.Context) (metadata.MD, error)
+
+// TokenSource 返回调用使用的访问令牌（可在内部缓存并按需刷新）
+type TokenSource func(ctx context.Context) (string, error)
+
+// WithMetadata 为每次调用注入 metadata，多次设置时按顺序追加
+func WithMetadata(fn MetadataFunc) Option {
+	return func(o *Options) {
+		if fn != nil {
+			o.Metadata = append(o.Metadata, fn)
+		}
+	}
+}
+
+// WithBearerToken 为每次调用注入 authorization: Bearer <token>，每次尝试（含重试）重新获取令牌
+func WithBearerToken(source TokenSource) Option {
+	return WithMetadata(func(ctx context.Context) (metadata.MD, error) {
+		token, err := source(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return metadata.Pairs("authorization", "Bearer "+token), nil
+	})
+}
+
+// withMetadata 将调用元数据追加到 outgoing context
+func (c *Client) withMetadata(ctx context.Context) (context.Context, error) {
+	for _, fn := range c.metadata {
+		md, err := fn(ctx)
+		if err != nil {
+			return ctx, status.Errorf(codes.Unauthenticated, "failed to build call metadata for %s: %v", c.service, err)
+		}
+		for key, values := range md {
+			for _, value := range values {
+				ctx = metadata.AppendToOutgoingContext(ctx, key, value)
+			}
+		}
+	}
+	return ctx, nil
+}
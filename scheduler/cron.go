@@ -0,0 +1,227 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule 调度计划，返回给定时间之后的下一次执行时间
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+// intervalSchedule 固定间隔调度
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+// Next 返回下一次执行时间（对齐到间隔的整数倍，集群中各实例的触发时间一致）
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	return t.Truncate(s.interval).Add(s.interval)
+}
+
+// cronSchedule cron 表达式调度（位图表示各字段允许的取值）
+type cronSchedule struct {
+	second, minute, hour, dom, month, dow uint64
+	domStar, dowStar                      bool
+}
+
+type fieldBounds struct {
+	min, max uint
+}
+
+var (
+	secondBounds = fieldBounds{0, 59}
+	minuteBounds = fieldBounds{0, 59}
+	hourBounds   = fieldBounds{0, 23}
+	domBounds    = fieldBounds{1, 31}
+	monthBounds  = fieldBounds{1, 12}
+	dowBounds    = fieldBounds{0, 6}
+)
+
+// cronDescriptors 预定义的 cron 描述符
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// ParseCron 解析 cron 表达式
+// 支持格式：
+// - 标准 5 段：分 时 日 月 周（如 "*/5 * * * *"）
+// - 带秒 6 段：秒 分 时 日 月 周（如 "0 */5 * * * *"）
+// - 描述符：@yearly、@monthly、@weekly、@daily、@hourly、@every 1m30s
+func ParseCron(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("empty cron spec")
+	}
+
+	if strings.HasPrefix(spec, "@every ") {
+		interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid @every duration %q: %w", spec, err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("@every duration must be positive: %s", spec)
+		}
+		return intervalSchedule{interval: interval}, nil
+	}
+	if expanded, ok := cronDescriptors[spec]; ok {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("invalid cron spec %q: expected 5 or 6 fields, got %d", spec, len(fields))
+	}
+
+	schedule := &cronSchedule{
+		domStar: fields[3] == "*" || fields[3] == "?",
+		dowStar: fields[5] == "*" || fields[5] == "?",
+	}
+	parsers := []struct {
+		field  string
+		bounds fieldBounds
+		target *uint64
+		name   string
+	}{
+		{fields[0], secondBounds, &schedule.second, "second"},
+		{fields[1], minuteBounds, &schedule.minute, "minute"},
+		{fields[2], hourBounds, &schedule.hour, "hour"},
+		{fields[3], domBounds, &schedule.dom, "day of month"},
+		{fields[4], monthBounds, &schedule.month, "month"},
+		{fields[5], dowBounds, &schedule.dow, "day of week"},
+	}
+	for _, p := range parsers {
+		bits, err := parseCronField(p.field, p.bounds)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron %s field %q: %w", p.name, p.field, err)
+		}
+		*p.target = bits
+	}
+
+	return schedule, nil
+}
+
+// parseCronField 解析单个 cron 字段，支持 *、?、a-b、a,b、*/n、a-b/n
+func parseCronField(field string, bounds fieldBounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		if part == "" {
+			return 0, fmt.Errorf("empty list item")
+		}
+
+		rangePart, step := part, uint(1)
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			rangePart = part[:idx]
+			n, err := strconv.ParseUint(part[idx+1:], 10, 8)
+			if err != nil || n == 0 {
+				return 0, fmt.Errorf("invalid step %q", part[idx+1:])
+			}
+			step = uint(n)
+		}
+
+		var start, end uint
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			start, end = bounds.min, bounds.max
+		case strings.Contains(rangePart, "-"):
+			pieces := strings.SplitN(rangePart, "-", 2)
+			lo, err := parseCronValue(pieces[0], bounds)
+			if err != nil {
+				return 0, err
+			}
+			hi, err := parseCronValue(pieces[1], bounds)
+			if err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("range start %d beyond end %d", lo, hi)
+			}
+			start, end = lo, hi
+		default:
+			v, err := parseCronValue(rangePart, bounds)
+			if err != nil {
+				return 0, err
+			}
+			start, end = v, v
+			// "5/10" 表示从 5 开始每 10 个单位执行一次
+			if step > 1 {
+				end = bounds.max
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(s string, bounds fieldBounds) (uint, error) {
+	n, err := strconv.ParseUint(s, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	v := uint(n)
+	// 周字段允许使用 7 表示周日
+	if bounds == dowBounds && v == 7 {
+		v = 0
+	}
+	if v < bounds.min || v > bounds.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", v, bounds.min, bounds.max)
+	}
+	return v, nil
+}
+
+// Next 返回给定时间之后的下一次执行时间，五年内无匹配时返回零值
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.Add(time.Second - time.Duration(t.Nanosecond()))
+	yearLimit := t.Year() + 5
+
+	for t.Year() <= yearLimit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Truncate(time.Minute).Add(time.Minute)
+			continue
+		}
+		if s.second&(1<<uint(t.Second())) == 0 {
+			t = t.Add(time.Second)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches 日与周同时受限时满足其一即可（与标准 cron 语义一致）
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
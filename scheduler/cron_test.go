@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronNext(t *testing.T) {
+	base := time.Date(2024, 3, 15, 10, 7, 30, 0, time.UTC)
+
+	cases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"*/5 * * * *", time.Date(2024, 3, 15, 10, 10, 0, 0, time.UTC)},
+		{"0 12 * * *", time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)},
+		{"30 * * * * *", time.Date(2024, 3, 15, 10, 8, 30, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2024, 3, 18, 9, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 3, 15, 11, 0, 0, 0, time.UTC)},
+		{"@every 90s", base.Add(90 * time.Second)},
+	}
+
+	for _, tc := range cases {
+		schedule, err := ParseCron(tc.spec)
+		if err != nil {
+			t.Fatalf("ParseCron(%q) failed: %v", tc.spec, err)
+		}
+		if got := schedule.Next(base); !got.Equal(tc.want) {
+			t.Fatalf("ParseCron(%q).Next = %s, want %s", tc.spec, got, tc.want)
+		}
+	}
+}
+
+func TestParseCronRejectsInvalidSpec(t *testing.T) {
+	for _, spec := range []string{"", "* * *", "61 * * * *", "* 25 * * *", "*/0 * * * *", "5-1 * * * *", "@every -1s"} {
+		if _, err := ParseCron(spec); err == nil {
+			t.Fatalf("expected ParseCron(%q) to fail", spec)
+		}
+	}
+}
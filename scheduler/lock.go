@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	redisClient "github.com/redis/go-redis/v9"
+
+	"github.com/team-dandelion/quickgo/db/redis"
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// Locker 分布式锁接口，保证集群中同一任务同一时刻只有一个实例执行
+type Locker interface {
+	// TryLock 尝试获取锁，获取成功返回 true 和解锁函数
+	TryLock(ctx context.Context, key string, ttl time.Duration) (bool, func(), error)
+}
+
+// RedisLocker 基于 redis.Lock 的分布式锁，持有期间自动续期
+type RedisLocker struct {
+	client redisClient.Cmdable
+}
+
+// NewRedisLocker 创建 Redis 分布式锁（key 前缀由调度器配置 LockPrefix 决定）
+func NewRedisLocker(client redisClient.Cmdable) (*RedisLocker, error) {
+	if client == nil {
+		return nil, fmt.Errorf("redis client is nil")
+	}
+	return &RedisLocker{client: client}, nil
+}
+
+// TryLock 尝试获取锁
+func (l *RedisLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, func(), error) {
+	lock, err := redis.NewLock(l.client, key, &redis.LockOptions{TTL: ttl, AutoRenew: true})
+	if err != nil {
+		return false, nil, err
+	}
+
+	ok, err := lock.TryLock(ctx)
+	if err != nil || !ok {
+		return false, nil, err
+	}
+
+	unlock := func() {
+		// 使用独立的 context，避免任务 context 超时导致锁无法释放
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if err := lock.Unlock(releaseCtx); err != nil && !errors.Is(err, redis.ErrLockNotHeld) {
+			logger.Warn(releaseCtx, "Failed to release scheduler lock: key=%s, error=%v", key, err)
+		}
+	}
+	return true, unlock, nil
+}
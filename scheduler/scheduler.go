@@ -0,0 +1,459 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// JobFunc 任务执行函数
+type JobFunc func(ctx context.Context) error
+
+// Config 调度器配置
+type Config struct {
+	// 是否启用
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled"`
+	// 组件名称（默认 scheduler）
+	Name string `json:"name" yaml:"name" toml:"name"`
+	// 任务默认超时时间（如 5m，为空表示不限制）
+	DefaultTimeout string `json:"defaultTimeout" yaml:"defaultTimeout" toml:"defaultTimeout"`
+	// 分布式锁 key 前缀（锁 key 为前缀 + 任务名 + 计划执行时间）
+	LockPrefix string `json:"lockPrefix" yaml:"lockPrefix" toml:"lockPrefix"`
+	// 分布式锁默认过期时间（任务未设置超时时使用，默认 1m），任务提前结束时仍持有到过期，避免其他实例重复执行同一次调度
+	LockTTL string `json:"lockTTL" yaml:"lockTTL" toml:"lockTTL"`
+}
+
+// Job 任务定义
+type Job struct {
+	// 任务名称（唯一）
+	Name string
+	// cron 表达式（与 Interval 二选一）
+	Spec string
+	// 固定执行间隔（与 Spec 二选一）
+	Interval time.Duration
+	// 单次执行超时时间（为 0 时使用调度器默认超时）
+	Timeout time.Duration
+	// 是否启用分布式锁（需要调度器配置 Locker）
+	DistributedLock bool
+	// 执行函数
+	Func JobFunc
+}
+
+// JobStatus 任务运行状态
+type JobStatus struct {
+	Name      string    `json:"name"`
+	Running   bool      `json:"running"`
+	LastRun   time.Time `json:"lastRun"`
+	NextRun   time.Time `json:"nextRun"`
+	LastError string    `json:"lastError,omitempty"`
+	Runs      int64     `json:"runs"`
+	Skipped   int64     `json:"skipped"`
+	Failures  int64     `json:"failures"`
+}
+
+// jobEntry 已注册的任务
+type jobEntry struct {
+	job      Job
+	schedule Schedule
+	running  atomic.Bool
+
+	mu        sync.Mutex
+	lastRun   time.Time
+	nextRun   time.Time
+	lastError string
+	runs      int64
+	skipped   int64
+	failures  int64
+}
+
+// Scheduler 任务调度器，实现 quickgo.Component 接口
+type Scheduler struct {
+	name           string
+	enabled        bool
+	defaultTimeout time.Duration
+	lockTTL        time.Duration
+	lockPrefix     string
+	locker         Locker
+
+	mu      sync.RWMutex
+	jobs    map[string]*jobEntry
+	order   []string
+	started bool
+	loopCtx context.Context
+	cancel  context.CancelFunc
+	loopWg  sync.WaitGroup
+	runWg   sync.WaitGroup
+	// 任务结束后仍在持有的分布式锁（计时器到期时释放），Stop 时立即释放
+	holds map[*time.Timer]func()
+}
+
+// New 创建调度器
+func New(config *Config) (*Scheduler, error) {
+	if config == nil {
+		return nil, errors.New("config is nil")
+	}
+
+	name := config.Name
+	if name == "" {
+		name = "scheduler"
+	}
+
+	var defaultTimeout time.Duration
+	if config.DefaultTimeout != "" {
+		d, err := time.ParseDuration(config.DefaultTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse DefaultTimeout %s: %w", config.DefaultTimeout, err)
+		}
+		defaultTimeout = d
+	}
+
+	lockTTL := time.Minute
+	if config.LockTTL != "" {
+		d, err := time.ParseDuration(config.LockTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse LockTTL %s: %w", config.LockTTL, err)
+		}
+		if d > 0 {
+			lockTTL = d
+		}
+	}
+
+	lockPrefix := config.LockPrefix
+	if lockPrefix == "" {
+		lockPrefix = "quickgo:scheduler:lock:"
+	}
+
+	return &Scheduler{
+		name:           name,
+		lockPrefix:     lockPrefix,
+		enabled:        config.Enabled,
+		defaultTimeout: defaultTimeout,
+		lockTTL:        lockTTL,
+		jobs:           make(map[string]*jobEntry),
+		holds:          make(map[*time.Timer]func()),
+	}, nil
+}
+
+// SetLocker 设置分布式锁实现（如 NewRedisLocker）
+func (s *Scheduler) SetLocker(locker Locker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.locker = locker
+}
+
+// AddJob 注册任务
+func (s *Scheduler) AddJob(job Job) error {
+	if job.Name == "" {
+		return errors.New("job name is required")
+	}
+	if job.Func == nil {
+		return fmt.Errorf("job %s func is nil", job.Name)
+	}
+
+	var schedule Schedule
+	switch {
+	case job.Spec != "" && job.Interval > 0:
+		return fmt.Errorf("job %s: spec and interval are mutually exclusive", job.Name)
+	case job.Spec != "":
+		parsed, err := ParseCron(job.Spec)
+		if err != nil {
+			return fmt.Errorf("job %s: %w", job.Name, err)
+		}
+		schedule = parsed
+	case job.Interval > 0:
+		schedule = intervalSchedule{interval: job.Interval}
+	default:
+		return fmt.Errorf("job %s: spec or interval is required", job.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("job %s already registered", job.Name)
+	}
+	if job.DistributedLock && s.locker == nil {
+		return fmt.Errorf("job %s requires distributed lock but no locker configured", job.Name)
+	}
+
+	entry := &jobEntry{job: job, schedule: schedule}
+	s.jobs[job.Name] = entry
+	s.order = append(s.order, job.Name)
+
+	// 调度器已启动时，新任务立即进入调度
+	if s.started {
+		s.startLoop(entry)
+	}
+	return nil
+}
+
+// AddCron 注册 cron 任务
+func (s *Scheduler) AddCron(name, spec string, fn JobFunc) error {
+	return s.AddJob(Job{Name: name, Spec: spec, Func: fn})
+}
+
+// AddInterval 注册固定间隔任务
+func (s *Scheduler) AddInterval(name string, interval time.Duration, fn JobFunc) error {
+	return s.AddJob(Job{Name: name, Interval: interval, Func: fn})
+}
+
+// Jobs 返回所有任务的运行状态
+func (s *Scheduler) Jobs() []JobStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := make([]JobStatus, 0, len(s.order))
+	for _, name := range s.order {
+		entry := s.jobs[name]
+		entry.mu.Lock()
+		statuses = append(statuses, JobStatus{
+			Name:      name,
+			Running:   entry.running.Load(),
+			LastRun:   entry.lastRun,
+			NextRun:   entry.nextRun,
+			LastError: entry.lastError,
+			Runs:      entry.runs,
+			Skipped:   entry.skipped,
+			Failures:  entry.failures,
+		})
+		entry.mu.Unlock()
+	}
+	return statuses
+}
+
+// ==================== Component 接口实现 ====================
+
+// Name 返回组件名称
+func (s *Scheduler) Name() string {
+	return s.name
+}
+
+// IsEnabled 是否启用
+func (s *Scheduler) IsEnabled() bool {
+	return s.enabled
+}
+
+// Init 初始化组件
+func (s *Scheduler) Init(ctx context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, entry := range s.jobs {
+		if entry.job.DistributedLock && s.locker == nil {
+			return fmt.Errorf("job %s requires distributed lock but no locker configured", entry.job.Name)
+		}
+	}
+	return nil
+}
+
+// Start 启动所有任务的调度循环
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return errors.New("scheduler already started")
+	}
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	s.loopCtx = loopCtx
+	s.cancel = cancel
+	s.started = true
+	for _, name := range s.order {
+		s.startLoop(s.jobs[name])
+	}
+
+	logger.Info(ctx, "Scheduler started: name=%s, jobs=%d", s.name, len(s.jobs))
+	return nil
+}
+
+// Stop 停止调度，并等待正在执行的任务结束（受 ctx 超时控制）
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		return nil
+	}
+	s.started = false
+	cancel := s.cancel
+	s.cancel = nil
+	s.loopCtx = nil
+	holds := s.holds
+	s.holds = make(map[*time.Timer]func())
+	s.mu.Unlock()
+
+	cancel()
+	s.loopWg.Wait()
+	// 停止持有计时器并立即释放锁，避免计时器与锁续期在调度器停止后继续运行
+	for timer, unlock := range holds {
+		if timer.Stop() {
+			unlock()
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.runWg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logger.Info(ctx, "Scheduler stopped: name=%s", s.name)
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("scheduler %s stop timed out waiting for running jobs: %w", s.name, ctx.Err())
+	}
+}
+
+// ==================== 调度与执行 ====================
+
+// startLoop 启动单个任务的调度循环（调用方需持有 s.mu）
+func (s *Scheduler) startLoop(entry *jobEntry) {
+	ctx := s.loopCtx
+	s.loopWg.Add(1)
+	go func() {
+		defer s.loopWg.Done()
+		for {
+			next := entry.schedule.Next(time.Now())
+			if next.IsZero() {
+				logger.Warn(ctx, "Scheduler job has no next run time: job=%s", entry.job.Name)
+				return
+			}
+			entry.mu.Lock()
+			entry.nextRun = next
+			entry.mu.Unlock()
+
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			// 上一次执行尚未结束时跳过本次，防止任务重叠执行
+			if !entry.running.CompareAndSwap(false, true) {
+				entry.mu.Lock()
+				entry.skipped++
+				entry.mu.Unlock()
+				logger.Warn(ctx, "Scheduler job still running, skip this run: job=%s", entry.job.Name)
+				continue
+			}
+
+			s.runWg.Add(1)
+			go func(tick time.Time) {
+				defer s.runWg.Done()
+				defer entry.running.Store(false)
+				s.runJob(ctx, entry, tick)
+			}(next)
+		}
+	}()
+}
+
+// runJob 执行 tick 对应的一次任务：生成 trace ID、获取分布式锁、超时控制与 panic 恢复
+func (s *Scheduler) runJob(parent context.Context, entry *jobEntry, tick time.Time) {
+	job := entry.job
+	ctx := logger.StartSpan(logger.WithTraceID(parent, logger.GenerateTraceID()))
+
+	timeout := job.Timeout
+	if timeout <= 0 {
+		timeout = s.defaultTimeout
+	}
+
+	if job.DistributedLock {
+		s.mu.RLock()
+		locker := s.locker
+		s.mu.RUnlock()
+
+		ttl := s.lockTTL
+		if timeout > 0 {
+			ttl = timeout
+		}
+		// 锁按计划执行时间区分，各实例对同一次调度竞争同一把锁，不影响下一次调度
+		key := fmt.Sprintf("%s%s:%d", s.lockPrefix, job.Name, tick.UnixMilli())
+		acquired, unlock, err := locker.TryLock(ctx, key, ttl)
+		if err != nil {
+			logger.Error(ctx, "Scheduler job lock failed: job=%s, error=%v", job.Name, err)
+			entry.recordResult(time.Now(), err)
+			return
+		}
+		if !acquired {
+			logger.Debug(ctx, "Scheduler job locked by another instance, skip: job=%s", job.Name)
+			entry.mu.Lock()
+			entry.skipped++
+			entry.mu.Unlock()
+			return
+		}
+		defer s.holdLock(tick.Add(ttl), unlock)
+	}
+
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	start := time.Now()
+	logger.Info(ctx, "Scheduler job started: job=%s", job.Name)
+	err := safeRun(ctx, job.Func)
+	entry.recordResult(start, err)
+
+	if err != nil {
+		logger.Error(ctx, "Scheduler job failed: job=%s, duration=%v, error=%v", job.Name, time.Since(start), err)
+		return
+	}
+	logger.Info(ctx, "Scheduler job finished: job=%s, duration=%v", job.Name, time.Since(start))
+}
+
+// holdLock 任务结束后继续持有锁直到 until，防止时钟稍慢的实例在锁释放后再次执行同一次调度；
+// 调度器已停止时立即释放
+func (s *Scheduler) holdLock(until time.Time, unlock func()) {
+	wait := time.Until(until)
+	s.mu.Lock()
+	if wait <= 0 || !s.started {
+		s.mu.Unlock()
+		unlock()
+		return
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(wait, func() {
+		// 回调需等待 holdLock 释放 s.mu，此时 timer 已赋值
+		s.mu.Lock()
+		delete(s.holds, timer)
+		s.mu.Unlock()
+		unlock()
+	})
+	s.holds[timer] = unlock
+	s.mu.Unlock()
+}
+
+// safeRun 执行任务函数并将 panic 转换为错误
+func safeRun(ctx context.Context, fn JobFunc) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return fn(ctx)
+}
+
+func (e *jobEntry) recordResult(start time.Time, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lastRun = start
+	e.runs++
+	if err != nil {
+		e.failures++
+		e.lastError = err.Error()
+	} else {
+		e.lastError = ""
+	}
+}
@@ -0,0 +1,265 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+type fakeLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+	keys []string
+}
+
+func (l *fakeLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, func(), error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held == nil {
+		l.held = make(map[string]bool)
+	}
+	l.keys = append(l.keys, key)
+	if l.held[key] {
+		return false, nil, nil
+	}
+	l.held[key] = true
+	return true, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.held, key)
+	}, nil
+}
+
+func TestSchedulerRunsIntervalJobWithTraceID(t *testing.T) {
+	s, err := New(&Config{Enabled: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	var runs atomic.Int32
+	traceIDs := make(chan string, 10)
+	if err := s.AddInterval("tick", 10*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		traceIDs <- logger.GetTraceID(ctx)
+		return nil
+	}); err != nil {
+		t.Fatalf("AddInterval failed: %v", err)
+	}
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	if runs.Load() < 2 {
+		t.Fatalf("expected job to run at least twice, got %d", runs.Load())
+	}
+	first, second := <-traceIDs, <-traceIDs
+	if first == "" || first == second {
+		t.Fatalf("expected distinct trace id per run, got %q and %q", first, second)
+	}
+}
+
+func TestSchedulerPreventsOverlapAndRecoversPanic(t *testing.T) {
+	s, err := New(&Config{Enabled: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	var concurrent, maxConcurrent atomic.Int32
+	if err := s.AddInterval("slow", 5*time.Millisecond, func(ctx context.Context) error {
+		n := concurrent.Add(1)
+		defer concurrent.Add(-1)
+		if n > maxConcurrent.Load() {
+			maxConcurrent.Store(n)
+		}
+		time.Sleep(30 * time.Millisecond)
+		return nil
+	}); err != nil {
+		t.Fatalf("AddInterval failed: %v", err)
+	}
+	if err := s.AddInterval("panic", 10*time.Millisecond, func(ctx context.Context) error {
+		panic("boom")
+	}); err != nil {
+		t.Fatalf("AddInterval failed: %v", err)
+	}
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	time.Sleep(80 * time.Millisecond)
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	if maxConcurrent.Load() != 1 {
+		t.Fatalf("expected no overlapping runs, got max concurrency %d", maxConcurrent.Load())
+	}
+	for _, status := range s.Jobs() {
+		switch status.Name {
+		case "slow":
+			if status.Skipped == 0 {
+				t.Fatalf("expected overlapping runs to be skipped: %+v", status)
+			}
+		case "panic":
+			if status.Failures == 0 || !strings.Contains(status.LastError, "boom") {
+				t.Fatalf("expected panic to be recorded as failure: %+v", status)
+			}
+		}
+	}
+}
+
+func TestSchedulerJobTimeout(t *testing.T) {
+	s, err := New(&Config{Enabled: true, DefaultTimeout: "10ms"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	timedOut := make(chan struct{}, 1)
+	if err := s.AddInterval("timeout", 5*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		select {
+		case timedOut <- struct{}{}:
+		default:
+		}
+		return ctx.Err()
+	}); err != nil {
+		t.Fatalf("AddInterval failed: %v", err)
+	}
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer s.Stop(context.Background())
+
+	select {
+	case <-timedOut:
+	case <-time.After(time.Second):
+		t.Fatal("expected job context to time out")
+	}
+}
+
+func TestSchedulerDistributedLock(t *testing.T) {
+	s, err := New(&Config{Enabled: true, LockPrefix: "test:"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	job := Job{Name: "locked", Interval: 5 * time.Millisecond, DistributedLock: true, Func: func(ctx context.Context) error { return nil }}
+	if err := s.AddJob(job); err == nil {
+		t.Fatal("expected error when locker is not configured")
+	}
+
+	locker := &fakeLocker{}
+	s.SetLocker(&heldLocker{fakeLocker: locker, prefix: "test:locked:"})
+	if err := s.AddJob(job); err != nil {
+		t.Fatalf("AddJob failed: %v", err)
+	}
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	status := s.Jobs()[0]
+	if status.Runs != 0 || status.Skipped == 0 {
+		t.Fatalf("expected job to be skipped while lock held elsewhere: %+v", status)
+	}
+	if len(locker.keys) == 0 || !strings.HasPrefix(locker.keys[0], "test:locked:") {
+		t.Fatalf("expected lock key with prefix, got %v", locker.keys)
+	}
+}
+
+// heldLocker 模拟其他实例持有所有以 prefix 开头的锁
+type heldLocker struct {
+	*fakeLocker
+	prefix string
+}
+
+func (l *heldLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, func(), error) {
+	if strings.HasPrefix(key, l.prefix) {
+		l.mu.Lock()
+		l.keys = append(l.keys, key)
+		l.mu.Unlock()
+		return false, nil, nil
+	}
+	return l.fakeLocker.TryLock(ctx, key, ttl)
+}
+
+func TestSchedulerDistributedLockRunsFastJobOncePerTick(t *testing.T) {
+	locker := &fakeLocker{}
+	var runs atomic.Int32
+	schedulers := make([]*Scheduler, 2)
+	for i := range schedulers {
+		s, err := New(&Config{Enabled: true, LockPrefix: "test:", LockTTL: "1s"})
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		s.SetLocker(locker)
+		// 任务立即结束，锁仍需持有到过期，另一个实例不能再执行同一次调度
+		if err := s.AddJob(Job{Name: "fast", Interval: 20 * time.Millisecond, DistributedLock: true, Func: func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		}}); err != nil {
+			t.Fatalf("AddJob failed: %v", err)
+		}
+		schedulers[i] = s
+	}
+
+	for _, s := range schedulers {
+		if err := s.Start(context.Background()); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+	}
+	time.Sleep(110 * time.Millisecond)
+	for _, s := range schedulers {
+		if err := s.Stop(context.Background()); err != nil {
+			t.Fatalf("Stop failed: %v", err)
+		}
+	}
+
+	locker.mu.Lock()
+	ticks := make(map[string]bool)
+	for _, key := range locker.keys {
+		ticks[key] = true
+	}
+	locker.mu.Unlock()
+
+	if runs.Load() == 0 || int(runs.Load()) != len(ticks) {
+		t.Fatalf("expected exactly one run per tick, got runs=%d ticks=%d", runs.Load(), len(ticks))
+	}
+	var skipped int64
+	for _, s := range schedulers {
+		skipped += s.Jobs()[0].Skipped
+	}
+	if skipped == 0 {
+		t.Fatal("expected the second scheduler to skip ticks locked by the first")
+	}
+
+	// Stop 停止持有计时器并立即释放锁
+	locker.mu.Lock()
+	held := len(locker.held)
+	locker.mu.Unlock()
+	if held != 0 {
+		t.Fatalf("expected Stop to release held locks, %d still held", held)
+	}
+	for _, s := range schedulers {
+		s.mu.RLock()
+		pending := len(s.holds)
+		s.mu.RUnlock()
+		if pending != 0 {
+			t.Fatalf("expected Stop to clear hold timers, %d pending", pending)
+		}
+	}
+}
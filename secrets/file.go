@@ -0,0 +1,41 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileProvider 从目录中的文件读取密钥（如 Kubernetes Secret 挂载目录 /var/run/secrets/app）
+// secret://db-password 读取 <Dir>/db-password；带 #key 时文件内容按 JSON 对象解析并选择字段
+type FileProvider struct {
+	// 密钥文件目录
+	Dir string
+}
+
+// NewFileProvider 创建文件密钥提供者
+func NewFileProvider(dir string) *FileProvider {
+	return &FileProvider{Dir: dir}
+}
+
+// GetSecret 读取密钥文件，去除末尾换行
+func (p *FileProvider) GetSecret(ctx context.Context, ref Ref) (string, error) {
+	if !filepath.IsLocal(ref.Path) {
+		return "", fmt.Errorf("secret path %q escapes the secrets directory", ref.Path)
+	}
+	data, err := os.ReadFile(filepath.Join(p.Dir, filepath.FromSlash(ref.Path)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, ref.Path)
+	}
+	if err != nil {
+		return "", err
+	}
+	if ref.Key == "" {
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+	return selectField(data, ref.Key)
+}
@@ -0,0 +1,121 @@
+// Package secrets 提供配置中的密钥引用解析
+//
+// 配置值写为 secret://path 或 secret://path#key 时，由 Provider 读取真实值，密钥不再明文写入配置文件：
+//
+//	redis:
+//	  password: secret://redis#password      # Vault KV 中 redis 的 password 字段
+//	  # 或 secret://redis-password           # FileProvider 读取挂载目录下的 redis-password 文件
+//
+// 内置 FileProvider（Kubernetes Secret 挂载目录等）与 VaultProvider（Vault KV v2），
+// 其他存储（如 AWS Secrets Manager）实现 Provider 接口即可接入。
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/team-dandelion/quickgo/json"
+)
+
+// Scheme 密钥引用前缀
+const Scheme = "secret://"
+
+// ErrNotFound 密钥不存在
+var ErrNotFound = errors.New("secrets: secret not found")
+
+// Ref 密钥引用
+type Ref struct {
+	// 密钥路径
+	Path string
+	// 密钥中的字段（可选，密钥为 JSON 对象或 Vault KV 时选择字段）
+	Key string
+}
+
+// String 返回引用的 URI 形式
+func (r Ref) String() string {
+	if r.Key == "" {
+		return Scheme + r.Path
+	}
+	return Scheme + r.Path + "#" + r.Key
+}
+
+// IsRef 判断配置值是否为密钥引用
+func IsRef(value string) bool {
+	return strings.HasPrefix(value, Scheme)
+}
+
+// ParseRef 解析 secret://path#key 形式的密钥引用
+func ParseRef(value string) (Ref, error) {
+	if !IsRef(value) {
+		return Ref{}, fmt.Errorf("secrets: %q is not a secret reference", value)
+	}
+	path, key, _ := strings.Cut(strings.TrimPrefix(value, Scheme), "#")
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return Ref{}, fmt.Errorf("secrets: empty path in %q", value)
+	}
+	return Ref{Path: path, Key: key}, nil
+}
+
+// Provider 密钥提供者
+type Provider interface {
+	// GetSecret 读取密钥值，不存在时返回 ErrNotFound
+	GetSecret(ctx context.Context, ref Ref) (string, error)
+}
+
+// ProviderFunc 函数形式的密钥提供者
+type ProviderFunc func(ctx context.Context, ref Ref) (string, error)
+
+// GetSecret 实现 Provider
+func (f ProviderFunc) GetSecret(ctx context.Context, ref Ref) (string, error) {
+	return f(ctx, ref)
+}
+
+// Resolve 解析配置值：密钥引用返回密钥值，其他值原样返回
+func Resolve(ctx context.Context, provider Provider, value string) (string, error) {
+	if !IsRef(value) {
+		return value, nil
+	}
+	ref, err := ParseRef(value)
+	if err != nil {
+		return "", err
+	}
+	if provider == nil {
+		return "", fmt.Errorf("secrets: no provider configured for %s", ref)
+	}
+	secret, err := provider.GetSecret(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("secrets: failed to resolve %s: %w", ref, err)
+	}
+	return secret, nil
+}
+
+// selectField 从 JSON 对象中选择字段，key 为空时返回原值
+func selectField(data []byte, key string) (string, error) {
+	if key == "" {
+		return string(data), nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object: %w", err)
+	}
+	return fieldValue(fields, key)
+}
+
+// fieldValue 返回字段值（非字符串字段按 JSON 编码）
+func fieldValue(fields map[string]interface{}, key string) (string, error) {
+	value, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("%w: field %s", ErrNotFound, key)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
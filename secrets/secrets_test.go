@@ -0,0 +1,92 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseRef(t *testing.T) {
+	ref, err := ParseRef("secret://db/main#password")
+	if err != nil || ref.Path != "db/main" || ref.Key != "password" || ref.String() != "secret://db/main#password" {
+		t.Fatalf("unexpected ref %+v, err=%v", ref, err)
+	}
+	if _, err := ParseRef("secret://"); err == nil {
+		t.Fatal("expected empty path to be rejected")
+	}
+	if value, err := Resolve(context.Background(), nil, "plain"); err != nil || value != "plain" {
+		t.Fatalf("expected plain value to pass through, got %q, %v", value, err)
+	}
+}
+
+func TestFileProvider(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "redis-password"), []byte("s3cret\n"), 0o600)
+	_ = os.WriteFile(filepath.Join(dir, "db.json"), []byte(`{"user":"app","password":"p@ss","port":5432}`), 0o600)
+	provider := NewFileProvider(dir)
+	ctx := context.Background()
+
+	cases := map[string]string{
+		"secret://redis-password":   "s3cret",
+		"secret://db.json#password": "p@ss",
+		"secret://db.json#port":     "5432",
+	}
+	for ref, want := range cases {
+		if got, err := Resolve(ctx, provider, ref); err != nil || got != want {
+			t.Fatalf("%s: got %q, err=%v", ref, got, err)
+		}
+	}
+	if _, err := Resolve(ctx, provider, "secret://missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := Resolve(ctx, provider, "secret://../etc/passwd"); err == nil {
+		t.Fatal("expected path traversal to be rejected")
+	}
+}
+
+func TestVaultProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/kv/data/db":
+			_, _ = w.Write([]byte(`{"data":{"data":{"user":"app","password":"p@ss"}}}`))
+		case "/v1/kv/data/api-key":
+			_, _ = w.Write([]byte(`{"data":{"data":{"value":"k-123"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewVaultProvider(&VaultConfig{Address: server.URL, Token: "token", Mount: "kv"})
+	if err != nil {
+		t.Fatalf("NewVaultProvider failed: %v", err)
+	}
+	ctx := context.Background()
+	if got, err := Resolve(ctx, provider, "secret://db#password"); err != nil || got != "p@ss" {
+		t.Fatalf("unexpected secret %q, err=%v", got, err)
+	}
+	if got, err := Resolve(ctx, provider, "secret://api-key"); err != nil || got != "k-123" {
+		t.Fatalf("expected single field secret, got %q, err=%v", got, err)
+	}
+	if _, err := Resolve(ctx, provider, "secret://db"); err == nil {
+		t.Fatal("expected multi-field secret without key to be rejected")
+	}
+	if _, err := Resolve(ctx, provider, "secret://missing#x"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	denied, _ := NewVaultProvider(&VaultConfig{Address: server.URL, Token: "wrong", Mount: "kv"})
+	if _, err := Resolve(ctx, denied, "secret://db#password"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected permission error, got %v", err)
+	}
+}
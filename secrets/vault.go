@@ -0,0 +1,143 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/team-dandelion/quickgo/json"
+)
+
+const defaultVaultTimeout = 5 * time.Second
+
+// VaultConfig Vault KV v2 配置
+type VaultConfig struct {
+	// Vault 地址 示例：https://vault.internal:8200（默认读取 VAULT_ADDR）
+	Address string `json:"address" yaml:"address" toml:"address"`
+	// 访问令牌（默认读取 VAULT_TOKEN）
+	Token string `json:"token" yaml:"token" toml:"token"`
+	// KV v2 挂载路径（默认 secret）
+	Mount string `json:"mount" yaml:"mount" toml:"mount"`
+	// 命名空间（Vault Enterprise，可选）
+	Namespace string `json:"namespace" yaml:"namespace" toml:"namespace"`
+	// 请求超时 示例：5s（默认 5s）
+	Timeout string `json:"timeout" yaml:"timeout" toml:"timeout"`
+	// 自定义 HTTP 客户端（可选，如配置 TLS）
+	HTTPClient *http.Client `json:"-" yaml:"-" toml:"-"`
+}
+
+// VaultProvider 从 Vault KV v2 读取密钥
+// secret://db#password 读取 <Mount>/data/db 最新版本中的 password 字段；未指定字段且只有一个字段时返回该字段
+type VaultProvider struct {
+	address   string
+	token     string
+	mount     string
+	namespace string
+	client    *http.Client
+}
+
+// NewVaultProvider 创建 Vault 密钥提供者
+func NewVaultProvider(config *VaultConfig) (*VaultProvider, error) {
+	if config == nil {
+		config = &VaultConfig{}
+	}
+	p := &VaultProvider{
+		address:   strings.TrimRight(config.Address, "/"),
+		token:     config.Token,
+		mount:     strings.Trim(config.Mount, "/"),
+		namespace: config.Namespace,
+		client:    config.HTTPClient,
+	}
+	if p.address == "" {
+		p.address = strings.TrimRight(os.Getenv("VAULT_ADDR"), "/")
+	}
+	if p.token == "" {
+		p.token = os.Getenv("VAULT_TOKEN")
+	}
+	if p.address == "" {
+		return nil, errors.New("vault address is required")
+	}
+	if p.token == "" {
+		return nil, errors.New("vault token is required")
+	}
+	if p.mount == "" {
+		p.mount = "secret"
+	}
+	if p.client == nil {
+		timeout := defaultVaultTimeout
+		if config.Timeout != "" {
+			d, err := time.ParseDuration(config.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse vault timeout %s: %w", config.Timeout, err)
+			}
+			timeout = d
+		}
+		p.client = &http.Client{Timeout: timeout}
+	}
+	return p, nil
+}
+
+// vaultKVResponse KV v2 读取响应
+type vaultKVResponse struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+// GetSecret 读取 KV v2 密钥字段
+func (p *VaultProvider) GetSecret(ctx context.Context, ref Ref) (string, error) {
+	endpoint := fmt.Sprintf("%s/v1/%s/data/%s", p.address, p.mount, escapePath(ref.Path))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read vault response: %w", err)
+	}
+
+	var kv vaultKVResponse
+	_ = json.Unmarshal(body, &kv)
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", fmt.Errorf("%w: %s", ErrNotFound, ref.Path)
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("vault returned status %d: %s", resp.StatusCode, strings.Join(kv.Errors, "; "))
+	}
+
+	fields := kv.Data.Data
+	if ref.Key == "" {
+		if len(fields) != 1 {
+			return "", fmt.Errorf("secret %s has %d fields, specify one with #key", ref.Path, len(fields))
+		}
+		for key := range fields {
+			return fieldValue(fields, key)
+		}
+	}
+	return fieldValue(fields, ref.Key)
+}
+
+// escapePath 逐段转义密钥路径
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
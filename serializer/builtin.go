@@ -0,0 +1,79 @@
+package serializer
+
+import (
+	"bytes"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/team-dandelion/quickgo/json"
+)
+
+// jsonSerializer 基于 json-iterator 的 JSON（与标准库兼容）
+type jsonSerializer struct{}
+
+func (jsonSerializer) Name() string                               { return JSON }
+func (jsonSerializer) ContentType() string                        { return "application/json" }
+func (jsonSerializer) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonSerializer) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// protoJSONSerializer proto 消息使用 protojson（proto 字段名、int64 编码为字符串、支持 Well-Known Types），
+// 非 proto 值回退为 JSON
+type protoJSONSerializer struct{}
+
+func (protoJSONSerializer) Name() string        { return ProtoJSON }
+func (protoJSONSerializer) ContentType() string { return "application/json" }
+
+func (protoJSONSerializer) Marshal(v interface{}) ([]byte, error) {
+	if msg, ok := v.(proto.Message); ok {
+		return protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	}
+	return json.Marshal(v)
+}
+
+func (protoJSONSerializer) Unmarshal(data []byte, v interface{}) error {
+	if msg, ok := v.(proto.Message); ok {
+		return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, msg)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// msgpackSerializer msgpack，结构体字段优先使用 msgpack tag，缺省时使用 json tag
+type msgpackSerializer struct{}
+
+func (msgpackSerializer) Name() string        { return Msgpack }
+func (msgpackSerializer) ContentType() string { return "application/msgpack" }
+
+func (msgpackSerializer) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (msgpackSerializer) Unmarshal(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+// cborEncMode / cborDecMode cbor 编解码模式：map 解码为 map[string]interface{}，便于与 JSON 数据互通
+var (
+	cborEncMode, _ = cbor.CoreDetEncOptions().EncMode()
+	cborDecMode, _ = cbor.DecOptions{DefaultMapType: mapStringInterfaceType}.DecMode()
+)
+
+// cborSerializer cbor（RFC 8949），结构体字段优先使用 cbor tag，缺省时使用 json tag
+type cborSerializer struct{}
+
+func (cborSerializer) Name() string                          { return CBOR }
+func (cborSerializer) ContentType() string                   { return "application/cbor" }
+func (cborSerializer) Marshal(v interface{}) ([]byte, error) { return cborEncMode.Marshal(v) }
+func (cborSerializer) Unmarshal(data []byte, v interface{}) error {
+	return cborDecMode.Unmarshal(data, v)
+}
@@ -0,0 +1,110 @@
+package serializer
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var mapStringInterfaceType = reflect.TypeOf(map[string]interface{}(nil))
+
+// mediaAliases 常见的非标准内容类型别名
+var mediaAliases = map[string]string{
+	"application/x-msgpack":   "application/msgpack",
+	"application/vnd.msgpack": "application/msgpack",
+	"text/json":               "application/json",
+}
+
+// mediaType 返回规范化的媒体类型（去掉参数并转为小写）
+func mediaType(value string) string {
+	value, _, _ = strings.Cut(value, ";")
+	value = strings.ToLower(strings.TrimSpace(value))
+	if alias, ok := mediaAliases[value]; ok {
+		return alias
+	}
+	return value
+}
+
+// Negotiate 根据 Accept 请求头从 allowed（按服务端偏好排序）中选择序列化方式
+// 按 q 值选择客户端最偏好的类型，q 值相同时按 allowed 顺序；Accept 为空或 */* 时返回 allowed 中的第一个
+// 没有可接受的类型时返回 allowed 中的第一个与 false（调用方可选择返回 406）
+func Negotiate(accept string, allowed []Serializer) (Serializer, bool) {
+	if len(allowed) == 0 {
+		return nil, false
+	}
+	if strings.TrimSpace(accept) == "" {
+		return allowed[0], true
+	}
+
+	parts := strings.Split(accept, ",")
+	// q=0 明确拒绝的类型不会被通配符重新选中
+	rejected := make(map[string]bool)
+	for _, part := range parts {
+		if acceptQuality(part) <= 0 {
+			rejected[mediaType(part)] = true
+		}
+	}
+
+	best, bestQ := -1, 0.0
+	for _, part := range parts {
+		media := mediaType(part)
+		q := acceptQuality(part)
+		if media == "" || q <= 0 {
+			continue
+		}
+		for i, s := range allowed {
+			if rejected[mediaType(s.ContentType())] || !mediaMatches(media, s.ContentType()) {
+				continue
+			}
+			if q > bestQ || (q == bestQ && i < best) {
+				best, bestQ = i, q
+			}
+			break
+		}
+	}
+	if best < 0 {
+		return allowed[0], false
+	}
+	return allowed[best], true
+}
+
+// ForContentType 根据请求的 Content-Type 从 allowed 中选择序列化方式（用于解码请求体）
+func ForContentType(contentType string, allowed []Serializer) (Serializer, bool) {
+	media := mediaType(contentType)
+	for _, s := range allowed {
+		if media == mediaType(s.ContentType()) {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// mediaMatches 判断 Accept 中的媒体范围（支持 */* 与 type/*）是否匹配内容类型
+func mediaMatches(accept, contentType string) bool {
+	contentType = mediaType(contentType)
+	switch {
+	case accept == "*/*" || accept == contentType:
+		return true
+	case strings.HasSuffix(accept, "/*"):
+		return strings.HasPrefix(contentType, strings.TrimSuffix(accept, "*"))
+	default:
+		return false
+	}
+}
+
+// acceptQuality 解析媒体范围的 q 参数（默认 1）
+func acceptQuality(part string) float64 {
+	_, params, _ := strings.Cut(part, ";")
+	for _, param := range strings.Split(params, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || strings.ToLower(strings.TrimSpace(key)) != "q" {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0
+		}
+		return q
+	}
+	return 1
+}
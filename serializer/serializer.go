@@ -0,0 +1,100 @@
+// Package serializer 提供可插拔的序列化方式注册表（JSON、protojson、msgpack、cbor）
+//
+// 网关路由按 Accept 请求头协商响应格式，缓存按命名空间选择序列化方式，
+// 内部的二进制友好客户端可使用 msgpack / cbor 跳过 JSON 编解码开销：
+//
+//	s, _ := serializer.Negotiate(c.Get("Accept"), []string{serializer.JSON, serializer.Msgpack})
+//	data, err := s.Marshal(resp)
+package serializer
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// 内置序列化方式名称
+const (
+	JSON      = "json"
+	ProtoJSON = "protojson"
+	Msgpack   = "msgpack"
+	CBOR      = "cbor"
+)
+
+// Serializer 序列化方式
+type Serializer interface {
+	// Name 返回序列化方式名称（注册表中的唯一标识）
+	Name() string
+	// ContentType 返回 HTTP 内容类型（如 application/json）
+	ContentType() string
+	// Marshal 序列化
+	Marshal(v interface{}) ([]byte, error)
+	// Unmarshal 反序列化
+	Unmarshal(data []byte, v interface{}) error
+}
+
+var (
+	registry   = make(map[string]Serializer)
+	registryMu sync.RWMutex
+)
+
+func init() {
+	for _, s := range []Serializer{jsonSerializer{}, protoJSONSerializer{}, msgpackSerializer{}, cborSerializer{}} {
+		registry[s.Name()] = s
+	}
+}
+
+// Register 注册序列化方式，同名时覆盖（可用于替换内置实现）
+func Register(s Serializer) {
+	if s == nil || s.Name() == "" {
+		panic("serializer: Register called with nil or unnamed serializer")
+	}
+	registryMu.Lock()
+	registry[s.Name()] = s
+	registryMu.Unlock()
+}
+
+// Get 按名称获取序列化方式
+func Get(name string) (Serializer, error) {
+	registryMu.RLock()
+	s, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown serializer: %s", name)
+	}
+	return s, nil
+}
+
+// Must 按名称获取序列化方式，未注册时 panic（用于初始化阶段）
+func Must(name string) Serializer {
+	s, err := Get(name)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Names 返回已注册的序列化方式名称（按名称排序）
+func Names() []string {
+	registryMu.RLock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	registryMu.RUnlock()
+	sort.Strings(names)
+	return names
+}
+
+// Lookup 按名称列表获取序列化方式，任一名称未注册时返回错误
+func Lookup(names []string) ([]Serializer, error) {
+	serializers := make([]Serializer, 0, len(names))
+	for _, name := range names {
+		s, err := Get(name)
+		if err != nil {
+			return nil, err
+		}
+		serializers = append(serializers, s)
+	}
+	return serializers, nil
+}
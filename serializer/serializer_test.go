@@ -0,0 +1,123 @@
+package serializer
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type item struct {
+	ID    string            `json:"id"`
+	Count int64             `json:"count"`
+	Tags  []string          `json:"tags"`
+	Attrs map[string]string `json:"attrs"`
+}
+
+func mustLookup(t *testing.T, names ...string) []Serializer {
+	t.Helper()
+	serializers, err := Lookup(names)
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	return serializers
+}
+
+func TestBuiltinSerializersRoundTrip(t *testing.T) {
+	in := item{ID: "a1", Count: 42, Tags: []string{"x", "y"}, Attrs: map[string]string{"zone": "sh"}}
+	for _, name := range []string{JSON, ProtoJSON, Msgpack, CBOR} {
+		s, err := Get(name)
+		if err != nil {
+			t.Fatalf("Get(%s) failed: %v", name, err)
+		}
+		data, err := s.Marshal(in)
+		if err != nil {
+			t.Fatalf("%s marshal failed: %v", name, err)
+		}
+		var out item
+		if err := s.Unmarshal(data, &out); err != nil {
+			t.Fatalf("%s unmarshal failed: %v", name, err)
+		}
+		if out.ID != in.ID || out.Count != in.Count || len(out.Tags) != 2 || out.Attrs["zone"] != "sh" {
+			t.Fatalf("%s round trip mismatch: %+v", name, out)
+		}
+	}
+}
+
+func TestBinarySerializersUseJSONTagsAndStringMaps(t *testing.T) {
+	for _, name := range []string{Msgpack, CBOR} {
+		s, _ := Get(name)
+		data, err := s.Marshal(item{ID: "a1", Attrs: map[string]string{"zone": "sh"}})
+		if err != nil {
+			t.Fatalf("%s marshal failed: %v", name, err)
+		}
+		var generic map[string]interface{}
+		if err := s.Unmarshal(data, &generic); err != nil {
+			t.Fatalf("%s unmarshal failed: %v", name, err)
+		}
+		if generic["id"] != "a1" {
+			t.Fatalf("%s expected json tag names, got %v", name, generic)
+		}
+		if _, ok := generic["attrs"].(map[string]interface{}); !ok {
+			t.Fatalf("%s expected nested maps to decode as map[string]interface{}, got %T", name, generic["attrs"])
+		}
+	}
+}
+
+func TestProtoJSONUsesProtojsonForMessages(t *testing.T) {
+	s, _ := Get(ProtoJSON)
+	msg, _ := structpb.NewStruct(map[string]interface{}{"name": "alice"})
+	data, err := s.Marshal(msg)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	out := &structpb.Struct{}
+	if err := s.Unmarshal(data, out); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if !proto.Equal(msg, out) {
+		t.Fatalf("expected %v, got %v", msg, out)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	allowed := mustLookup(t, JSON, Msgpack, CBOR)
+	cases := []struct {
+		accept string
+		want   string
+		ok     bool
+	}{
+		{"", JSON, true},
+		{"*/*", JSON, true},
+		{"application/msgpack", Msgpack, true},
+		{"application/x-msgpack", Msgpack, true},
+		{"application/json;q=0.5, application/cbor", CBOR, true},
+		{"application/cbor;q=0.8, application/msgpack;q=0.8", Msgpack, true},
+		{"application/json;q=0, */*;q=0.1", Msgpack, true},
+		{"text/html", JSON, false},
+	}
+	for _, tc := range cases {
+		s, ok := Negotiate(tc.accept, allowed)
+		if s.Name() != tc.want || ok != tc.ok {
+			t.Fatalf("Negotiate(%q) = %s, %v; want %s, %v", tc.accept, s.Name(), ok, tc.want, tc.ok)
+		}
+	}
+	if s, ok := ForContentType("application/cbor; charset=binary", allowed); !ok || s.Name() != CBOR {
+		t.Fatalf("expected cbor for request content type, got %v", s)
+	}
+}
+
+type upperJSON struct{ Serializer }
+
+func (upperJSON) Name() string { return "custom-json" }
+
+func TestRegisterCustomSerializer(t *testing.T) {
+	base, _ := Get(JSON)
+	Register(upperJSON{base})
+	if _, err := Get("custom-json"); err != nil {
+		t.Fatalf("expected custom serializer to be registered: %v", err)
+	}
+	if _, err := Lookup([]string{JSON, "missing"}); err == nil {
+		t.Fatal("expected Lookup to reject unknown serializer")
+	}
+}
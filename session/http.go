@@ -0,0 +1,123 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"github.com/team-dandelion/quickgo/http"
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// LocalsKey fiber Locals 中的会话键
+const LocalsKey = "quickgo_session"
+
+// FiberMiddleware HTTP 会话中间件
+// 读取签名 Cookie 加载会话（签名无效或已过期时视为新会话），请求结束后保存修改并在需要时续期、设置 Cookie；
+// 开启 CSRF 时 POST / PUT / PATCH / DELETE 请求的令牌（请求头或表单字段）与会话不一致返回 403，存储不可用返回 503
+func (m *Manager) FiberMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := http.RequestContext(c)
+		s, err := m.load(ctx, utils.CopyString(c.Cookies(m.config.CookieName)))
+		if err != nil {
+			logger.Error(ctx, "Session store unavailable: error=%v", err)
+			return fiber.NewError(fiber.StatusServiceUnavailable, "session store unavailable")
+		}
+		c.Locals(LocalsKey, s)
+
+		if m.config.CSRF && isUnsafeMethod(c.Method()) &&
+			(m.config.CSRFSkip == nil || !m.config.CSRFSkip(ctx, c.Method(), c.Path())) {
+			token := c.Get(m.config.CSRFHeader)
+			if token == "" {
+				token = c.FormValue(m.config.CSRFForm)
+			}
+			if !s.verifyCSRF(token) {
+				return fiber.NewError(fiber.StatusForbidden, ErrInvalidCSRFToken.Error())
+			}
+		}
+
+		handlerErr := c.Next()
+
+		// 请求结束后写入存储，不受请求 context 取消影响
+		value, err := m.save(context.WithoutCancel(ctx), s)
+		if err != nil {
+			logger.Error(ctx, "Failed to save session: error=%v", err)
+			if handlerErr == nil {
+				return fiber.NewError(fiber.StatusServiceUnavailable, "session store unavailable")
+			}
+			return handlerErr
+		}
+		if value != nil {
+			m.setCookie(c, *value, s.expiresAt)
+		}
+		return handlerErr
+	}
+}
+
+// setCookie 设置会话 Cookie，value 为空时清除
+func (m *Manager) setCookie(c *fiber.Ctx, value string, expiresAt time.Time) {
+	cookie := &fiber.Cookie{
+		Name:     m.config.CookieName,
+		Value:    value,
+		Path:     m.config.CookiePath,
+		Domain:   m.config.CookieDomain,
+		Secure:   m.config.CookieSecure,
+		HTTPOnly: true,
+		SameSite: m.config.CookieSameSite,
+		Expires:  expiresAt,
+	}
+	if value == "" {
+		cookie.Expires = time.Unix(0, 0)
+		cookie.MaxAge = -1
+	}
+	c.Cookie(cookie)
+}
+
+func isUnsafeMethod(method string) bool {
+	switch method {
+	case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// FromCtx 获取请求的会话，未安装会话中间件时返回 nil
+func FromCtx(c *fiber.Ctx) *Session {
+	s, _ := c.Locals(LocalsKey).(*Session)
+	return s
+}
+
+// Get 读取会话中的值，键不存在、解码失败或未安装会话中间件时返回零值与 false
+func Get[T any](c *fiber.Ctx, key string) (T, bool) {
+	var value T
+	s := FromCtx(c)
+	if s == nil {
+		return value, false
+	}
+	ok, err := s.Get(key, &value)
+	if err != nil {
+		logger.Warn(c.UserContext(), "Invalid session value: key=%s, error=%v", key, err)
+		return value, false
+	}
+	return value, ok
+}
+
+// Set 写入会话值
+func Set(c *fiber.Ctx, key string, value interface{}) error {
+	s := FromCtx(c)
+	if s == nil {
+		return ErrNoSession
+	}
+	return s.Set(key, value)
+}
+
+// CSRFToken 返回当前会话的 CSRF 令牌（用于渲染表单或通过接口下发给前端）
+func CSRFToken(c *fiber.Ctx) (string, error) {
+	s := FromCtx(c)
+	if s == nil {
+		return "", ErrNoSession
+	}
+	return s.CSRFToken()
+}
@@ -0,0 +1,81 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	redisClient "github.com/redis/go-redis/v9"
+
+	"github.com/team-dandelion/quickgo/db/redis"
+)
+
+// RedisStore 基于 Redis 的会话存储
+type RedisStore struct {
+	client redisClient.Cmdable
+}
+
+// NewRedisStore 创建 Redis 会话存储
+func NewRedisStore(client redisClient.Cmdable) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// NewRedisStoreFromManager 使用 redis.Manager 中指定名称的客户端创建会话存储
+func NewRedisStoreFromManager(manager *redis.Manager, name string) (*RedisStore, error) {
+	if manager == nil {
+		return nil, errors.New("redis manager is nil")
+	}
+	client, err := manager.GetRedisClient(name)
+	if err != nil {
+		return nil, err
+	}
+	return NewRedisStore(client), nil
+}
+
+// Load 读取会话数据与剩余过期时间（GET 与 PTTL 在同一个 pipeline 中执行）
+func (s *RedisStore) Load(ctx context.Context, key string) ([]byte, time.Duration, bool, error) {
+	var get *redisClient.StringCmd
+	var ttl *redisClient.DurationCmd
+	_, err := s.client.Pipelined(ctx, func(pipe redisClient.Pipeliner) error {
+		get = pipe.Get(ctx, key)
+		ttl = pipe.PTTL(ctx, key)
+		return nil
+	})
+	if errors.Is(err, redisClient.Nil) {
+		return nil, 0, false, nil
+	}
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("failed to load session: %w", err)
+	}
+	data, err := get.Bytes()
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("failed to load session: %w", err)
+	}
+	return data, ttl.Val(), true, nil
+}
+
+// Save 写入会话数据
+func (s *RedisStore) Save(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	if err := s.client.Set(ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// Touch 使用 PEXPIRE 重置会话过期时间
+func (s *RedisStore) Touch(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	ok, err := s.client.PExpire(ctx, key, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to renew session: %w", err)
+	}
+	return ok, nil
+}
+
+// Delete 删除会话
+func (s *RedisStore) Delete(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,368 @@
+// Package session 提供基于 Redis 存储的 HTTP 会话
+//
+// 会话 ID 保存在签名的 Cookie 中（HttpOnly），会话数据保存在存储中并在访问时续期（TTL 为空闲超时）；
+// 会话在首次写入数据时才创建，未登录的匿名请求不会产生存储与 Cookie。开启 CSRF 后，
+// POST / PUT / PATCH / DELETE 请求需携带与会话中一致的 CSRF 令牌：
+//
+//	store, _ := session.NewRedisStoreFromManager(redisManager, "default")
+//	sessions, _ := session.New(store, &session.Config{Secret: secret, CSRF: true})
+//	app.Use(sessions.FiberMiddleware())
+//	_ = session.Set(c, "user_id", userID)
+//	userID, ok := session.Get[int64](c, "user_id")
+package session
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/team-dandelion/quickgo/types"
+)
+
+const (
+	// DefaultCookieName 默认的会话 Cookie 名称
+	DefaultCookieName = "quickgo_session"
+	// DefaultPrefix 默认存储 key 前缀
+	DefaultPrefix = "quickgo:session:"
+	// DefaultCSRFHeader 默认的 CSRF 令牌请求头
+	DefaultCSRFHeader = "X-CSRF-Token"
+	// DefaultCSRFForm 默认的 CSRF 令牌表单字段
+	DefaultCSRFForm = "_csrf"
+
+	defaultTTL     = 24 * time.Hour
+	minSecretBytes = 32
+	idBytes        = 32
+	csrfKey        = "_csrf"
+)
+
+var (
+	// ErrInvalidCSRFToken CSRF 令牌缺失或与会话不一致
+	ErrInvalidCSRFToken = errors.New("session: invalid csrf token")
+	// ErrNoSession 请求未经过会话中间件
+	ErrNoSession = errors.New("session: session middleware is not installed")
+)
+
+// Config 会话配置
+type Config struct {
+	// Cookie 签名密钥（至少 32 字节），多实例部署需保持一致
+	Secret string `json:"secret" yaml:"secret" toml:"secret"`
+	// Cookie 名称 示例：quickgo_session（默认）
+	CookieName string `json:"cookieName" yaml:"cookieName" toml:"cookieName"`
+	// Cookie 路径（默认 /）
+	CookiePath string `json:"cookiePath" yaml:"cookiePath" toml:"cookiePath"`
+	// Cookie 域名（可选）
+	CookieDomain string `json:"cookieDomain" yaml:"cookieDomain" toml:"cookieDomain"`
+	// 是否仅通过 HTTPS 发送 Cookie（生产环境应开启）
+	CookieSecure bool `json:"cookieSecure" yaml:"cookieSecure" toml:"cookieSecure"`
+	// Cookie SameSite 策略 示例：Lax（默认）、Strict、None
+	CookieSameSite string `json:"cookieSameSite" yaml:"cookieSameSite" toml:"cookieSameSite"`
+	// 存储 key 前缀（默认 quickgo:session:）
+	Prefix string `json:"prefix" yaml:"prefix" toml:"prefix"`
+	// 空闲超时 示例：24h（默认 24h），剩余时间不足一半时访问会自动续期
+	TTL types.Duration `json:"ttl" yaml:"ttl" toml:"ttl"`
+	// 是否开启 CSRF 校验
+	CSRF bool `json:"csrf" yaml:"csrf" toml:"csrf"`
+	// CSRF 令牌请求头 示例：X-CSRF-Token（默认）
+	CSRFHeader string `json:"csrfHeader" yaml:"csrfHeader" toml:"csrfHeader"`
+	// CSRF 令牌表单字段 示例：_csrf（默认）
+	CSRFForm string `json:"csrfForm" yaml:"csrfForm" toml:"csrfForm"`
+	// 跳过 CSRF 校验的请求（如使用 Bearer 令牌的 API 请求）
+	CSRFSkip func(ctx context.Context, method, path string) bool `json:"-" yaml:"-" toml:"-"`
+}
+
+// Store 会话数据存储
+type Store interface {
+	// Load 读取会话数据与剩余过期时间（未设置过期时间时为负数），不存在或已过期时返回 false
+	Load(ctx context.Context, key string) ([]byte, time.Duration, bool, error)
+	// Save 写入会话数据并设置过期时间
+	Save(ctx context.Context, key string, data []byte, ttl time.Duration) error
+	// Touch 仅重置过期时间而不改写数据，会话不存在时返回 false
+	Touch(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Delete 删除会话
+	Delete(ctx context.Context, key string) error
+}
+
+// Manager 会话管理器
+type Manager struct {
+	store  Store
+	config Config
+	secret []byte
+	ttl    time.Duration
+}
+
+// New 创建会话管理器
+func New(store Store, config *Config) (*Manager, error) {
+	if store == nil {
+		return nil, errors.New("session store is nil")
+	}
+	m := &Manager{store: store}
+	if config != nil {
+		m.config = *config
+	}
+	if len(m.config.Secret) < minSecretBytes {
+		return nil, fmt.Errorf("session secret must be at least %d bytes", minSecretBytes)
+	}
+	m.secret = []byte(m.config.Secret)
+	if m.config.CookieName == "" {
+		m.config.CookieName = DefaultCookieName
+	}
+	if m.config.CookiePath == "" {
+		m.config.CookiePath = "/"
+	}
+	switch strings.ToLower(m.config.CookieSameSite) {
+	case "":
+		m.config.CookieSameSite = "Lax"
+	case "lax", "strict", "none":
+	default:
+		return nil, fmt.Errorf("invalid session cookie sameSite %q", m.config.CookieSameSite)
+	}
+	if m.config.Prefix == "" {
+		m.config.Prefix = DefaultPrefix
+	}
+	if m.config.TTL < 0 {
+		return nil, fmt.Errorf("invalid session ttl %s", m.config.TTL)
+	}
+	m.ttl = m.config.TTL.OrDefault(defaultTTL)
+	if m.config.CSRFHeader == "" {
+		m.config.CSRFHeader = DefaultCSRFHeader
+	}
+	if m.config.CSRFForm == "" {
+		m.config.CSRFForm = DefaultCSRFForm
+	}
+	return m, nil
+}
+
+// record 存储中的会话数据，过期时间由存储的 TTL 维护
+type record struct {
+	Values map[string]json.RawMessage `json:"values"`
+}
+
+// Session 单次请求的会话，非并发安全（与 fiber.Ctx 一样仅在请求处理期间使用）
+type Session struct {
+	manager   *Manager
+	id        string
+	values    map[string]json.RawMessage
+	expiresAt time.Time
+	// 会话是否已存在于存储中
+	stored bool
+	// 数据是否被修改
+	modified bool
+	// 是否需要续期
+	renew bool
+	// 是否已销毁
+	destroyed bool
+	// 重新生成 ID 前的旧 ID（需要从存储删除）
+	previousID string
+}
+
+// ID 返回会话 ID（新会话在首次写入前为空）
+func (s *Session) ID() string {
+	return s.id
+}
+
+// IsNew 会话是否尚未保存到存储
+func (s *Session) IsNew() bool {
+	return !s.stored
+}
+
+// Get 读取值并解码到 out，键不存在时返回 false
+func (s *Session) Get(key string, out interface{}) (bool, error) {
+	raw, ok := s.values[key]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return true, fmt.Errorf("failed to decode session value %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// Has 判断键是否存在
+func (s *Session) Has(key string) bool {
+	_, ok := s.values[key]
+	return ok
+}
+
+// Set 写入值（JSON 编码），请求结束时保存
+func (s *Session) Set(key string, value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode session value %s: %w", key, err)
+	}
+	if s.values == nil {
+		s.values = make(map[string]json.RawMessage)
+	}
+	s.values[key] = raw
+	s.modified = true
+	s.destroyed = false
+	return nil
+}
+
+// Delete 删除值
+func (s *Session) Delete(key string) {
+	if _, ok := s.values[key]; ok {
+		delete(s.values, key)
+		s.modified = true
+	}
+}
+
+// Regenerate 更换会话 ID 并保留数据，登录、提权后调用以防止会话固定攻击；CSRF 令牌同时更换
+func (s *Session) Regenerate() {
+	if s.stored && s.previousID == "" {
+		s.previousID = s.id
+	}
+	s.id = ""
+	s.stored = false
+	delete(s.values, csrfKey)
+	s.modified = true
+}
+
+// Destroy 销毁会话（如退出登录），请求结束时删除存储并清除 Cookie
+func (s *Session) Destroy() {
+	s.values = nil
+	s.modified = false
+	s.destroyed = true
+}
+
+// CSRFToken 返回会话的 CSRF 令牌，不存在时生成（会创建会话）
+func (s *Session) CSRFToken() (string, error) {
+	var token string
+	if ok, err := s.Get(csrfKey, &token); err == nil && ok && token != "" {
+		return token, nil
+	}
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	if err := s.Set(csrfKey, token); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// verifyCSRF 校验 CSRF 令牌
+func (s *Session) verifyCSRF(token string) bool {
+	var expected string
+	if ok, err := s.Get(csrfKey, &expected); err != nil || !ok || expected == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(token)) == 1
+}
+
+// load 根据 Cookie 读取会话，Cookie 无效或会话已过期时返回新会话
+func (m *Manager) load(ctx context.Context, cookie string) (*Session, error) {
+	s := &Session{manager: m}
+	id, ok := m.verify(cookie)
+	if !ok {
+		return s, nil
+	}
+	data, remaining, found, err := m.store.Load(ctx, m.config.Prefix+id)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return s, nil
+	}
+	var stored record
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode session: %w", err)
+	}
+	s.id, s.values, s.expiresAt, s.stored = id, stored.Values, time.Now().Add(remaining), true
+	s.renew = remaining < m.ttl/2
+	return s, nil
+}
+
+// save 将会话变更写入存储，返回需要设置的 Cookie 值（空字符串表示清除，nil 表示无需变更）
+func (m *Manager) save(ctx context.Context, s *Session) (*string, error) {
+	if s.previousID != "" {
+		if err := m.store.Delete(ctx, m.config.Prefix+s.previousID); err != nil {
+			return nil, err
+		}
+		s.previousID = ""
+	}
+	if s.destroyed {
+		if s.stored {
+			if err := m.store.Delete(ctx, m.config.Prefix+s.id); err != nil {
+				return nil, err
+			}
+		}
+		empty := ""
+		return &empty, nil
+	}
+	if !s.modified && !s.renew {
+		return nil, nil
+	}
+	if !s.modified {
+		return m.touch(ctx, s)
+	}
+	if s.id == "" {
+		id, err := randomToken()
+		if err != nil {
+			return nil, err
+		}
+		s.id = id
+	}
+	s.expiresAt = time.Now().Add(m.ttl)
+	data, err := json.Marshal(record{Values: s.values})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode session: %w", err)
+	}
+	if err := m.store.Save(ctx, m.config.Prefix+s.id, data, m.ttl); err != nil {
+		return nil, err
+	}
+	s.stored, s.modified, s.renew = true, false, false
+	value := m.sign(s.id)
+	return &value, nil
+}
+
+// touch 续期未修改的会话：只重置存储的过期时间，避免用本请求读到的旧数据覆盖并发请求的写入；
+// 会话已被并发删除（如退出登录）时不再重建，并清除 Cookie
+func (m *Manager) touch(ctx context.Context, s *Session) (*string, error) {
+	expiresAt := time.Now().Add(m.ttl)
+	found, err := m.store.Touch(ctx, m.config.Prefix+s.id, m.ttl)
+	if err != nil {
+		return nil, err
+	}
+	s.renew = false
+	if !found {
+		s.stored = false
+		empty := ""
+		return &empty, nil
+	}
+	s.expiresAt = expiresAt
+	value := m.sign(s.id)
+	return &value, nil
+}
+
+// sign 生成 Cookie 值：会话 ID + "." + HMAC-SHA256 签名
+func (m *Manager) sign(id string) string {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write([]byte(id))
+	return id + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify 校验 Cookie 签名并返回会话 ID
+func (m *Manager) verify(cookie string) (string, bool) {
+	id, _, ok := strings.Cut(cookie, ".")
+	if !ok || id == "" {
+		return "", false
+	}
+	return id, hmac.Equal([]byte(cookie), []byte(m.sign(id)))
+}
+
+// randomToken 生成随机令牌（会话 ID、CSRF 令牌）
+func randomToken() (string, error) {
+	buf := make([]byte, idBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate session token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
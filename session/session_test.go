@@ -0,0 +1,220 @@
+package session
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v2"
+	redisClient "github.com/redis/go-redis/v9"
+
+	"github.com/team-dandelion/quickgo/types"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func newTestApp(t *testing.T, config *Config) (*miniredis.Miniredis, *fiber.App) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redisClient.NewClient(&redisClient.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	if config == nil {
+		config = &Config{}
+	}
+	config.Secret = testSecret
+	manager, err := New(NewRedisStore(client), config)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	app := fiber.New()
+	app.Use(manager.FiberMiddleware())
+	app.Get("/whoami", func(c *fiber.Ctx) error {
+		userID, ok := Get[int64](c, "user_id")
+		if !ok {
+			return c.SendString("anonymous")
+		}
+		return c.JSON(fiber.Map{"user_id": userID})
+	})
+	app.Post("/login", func(c *fiber.Ctx) error {
+		FromCtx(c).Regenerate()
+		return Set(c, "user_id", int64(42))
+	})
+	app.Post("/logout", func(c *fiber.Ctx) error {
+		FromCtx(c).Destroy()
+		return nil
+	})
+	app.Get("/csrf", func(c *fiber.Ctx) error {
+		token, err := CSRFToken(c)
+		if err != nil {
+			return err
+		}
+		return c.SendString(token)
+	})
+	return server, app
+}
+
+func send(t *testing.T, app *fiber.App, method, path string, cookie *http.Cookie, header map[string]string) (*http.Response, string) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, path, err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func sessionCookie(resp *http.Response) *http.Cookie {
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == DefaultCookieName {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	server, app := newTestApp(t, nil)
+
+	resp, body := send(t, app, "GET", "/whoami", nil, nil)
+	if body != "anonymous" || sessionCookie(resp) != nil || len(server.Keys()) != 0 {
+		t.Fatalf("anonymous request should not create a session: %s %v", body, server.Keys())
+	}
+
+	resp, _ = send(t, app, "POST", "/login", nil, nil)
+	cookie := sessionCookie(resp)
+	if cookie == nil || !cookie.HttpOnly || cookie.Value == "" {
+		t.Fatalf("expected signed HttpOnly session cookie, got %+v", cookie)
+	}
+	if _, body = send(t, app, "GET", "/whoami", cookie, nil); body != `{"user_id":42}` {
+		t.Fatalf("expected session value, got %s", body)
+	}
+
+	// 篡改签名的 Cookie 视为新会话
+	forged := &http.Cookie{Name: DefaultCookieName, Value: strings.Split(cookie.Value, ".")[0] + ".forged"}
+	if _, body = send(t, app, "GET", "/whoami", forged, nil); body != "anonymous" {
+		t.Fatalf("forged cookie must be rejected, got %s", body)
+	}
+
+	// 登录时更换会话 ID，旧会话失效
+	resp, _ = send(t, app, "POST", "/login", cookie, nil)
+	renewed := sessionCookie(resp)
+	if renewed == nil || renewed.Value == cookie.Value {
+		t.Fatalf("expected regenerated session id, got %+v", renewed)
+	}
+	if _, body = send(t, app, "GET", "/whoami", cookie, nil); body != "anonymous" {
+		t.Fatalf("old session must be deleted after regenerate, got %s", body)
+	}
+
+	resp, _ = send(t, app, "POST", "/logout", renewed, nil)
+	if cleared := sessionCookie(resp); cleared == nil || cleared.Value != "" {
+		t.Fatalf("expected session cookie to be cleared, got %+v", cleared)
+	}
+	if len(server.Keys()) != 0 {
+		t.Fatalf("expected session to be deleted, got %v", server.Keys())
+	}
+}
+
+func TestSessionRenewal(t *testing.T) {
+	server, app := newTestApp(t, &Config{TTL: types.Duration(time.Hour)})
+	resp, _ := send(t, app, "POST", "/login", nil, nil)
+	cookie := sessionCookie(resp)
+
+	// 剩余时间超过一半时不续期
+	if resp, _ = send(t, app, "GET", "/whoami", cookie, nil); sessionCookie(resp) != nil {
+		t.Fatal("fresh session should not be renewed")
+	}
+
+	// 模拟存储中的会话即将过期
+	key := server.Keys()[0]
+	server.SetTTL(key, 10*time.Minute)
+
+	resp, body := send(t, app, "GET", "/whoami", cookie, nil)
+	if body != `{"user_id":42}` || sessionCookie(resp) == nil {
+		t.Fatalf("expected session to be renewed: %s", body)
+	}
+	if ttl := server.TTL(key); ttl != time.Hour {
+		t.Fatalf("expected store ttl to be reset, got %s", ttl)
+	}
+}
+
+func TestSessionRenewalKeepsConcurrentWrites(t *testing.T) {
+	server, app := newTestApp(t, &Config{TTL: types.Duration(time.Hour)})
+	resp, _ := send(t, app, "POST", "/login", nil, nil)
+	cookie := sessionCookie(resp)
+	key := server.Keys()[0]
+
+	// 续期请求处理期间，并发请求写入了新数据
+	concurrent, _ := json.Marshal(record{Values: map[string]json.RawMessage{"user_id": json.RawMessage("7")}})
+	app.Get("/concurrent", func(c *fiber.Ctx) error {
+		return server.Set(key, string(concurrent))
+	})
+	server.SetTTL(key, 10*time.Minute)
+	if resp, _ = send(t, app, "GET", "/concurrent", cookie, nil); sessionCookie(resp) == nil {
+		t.Fatal("expected session to be renewed")
+	}
+	if ttl := server.TTL(key); ttl != time.Hour {
+		t.Fatalf("expected store ttl to be reset, got %s", ttl)
+	}
+	if _, body := send(t, app, "GET", "/whoami", cookie, nil); body != `{"user_id":7}` {
+		t.Fatalf("renewal must not overwrite concurrent writes, got %s", body)
+	}
+
+	// 续期时会话已被并发删除，不再重建并清除 Cookie
+	app.Get("/deleted", func(c *fiber.Ctx) error {
+		server.Del(key)
+		return nil
+	})
+	server.SetTTL(key, 10*time.Minute)
+	resp, _ = send(t, app, "GET", "/deleted", cookie, nil)
+	if cleared := sessionCookie(resp); cleared == nil || cleared.Value != "" {
+		t.Fatalf("expected session cookie to be cleared, got %+v", cleared)
+	}
+	if len(server.Keys()) != 0 {
+		t.Fatalf("expected deleted session not to be recreated, got %v", server.Keys())
+	}
+}
+
+func TestCSRFProtection(t *testing.T) {
+	_, app := newTestApp(t, &Config{CSRF: true})
+
+	resp, token := send(t, app, "GET", "/csrf", nil, nil)
+	cookie := sessionCookie(resp)
+	if cookie == nil || token == "" {
+		t.Fatalf("expected csrf token and session cookie")
+	}
+	if resp, _ = send(t, app, "POST", "/logout", cookie, nil); resp.StatusCode != fiber.StatusForbidden {
+		t.Fatalf("expected 403 without csrf token, got %d", resp.StatusCode)
+	}
+	if resp, _ = send(t, app, "POST", "/logout", cookie, map[string]string{DefaultCSRFHeader: "wrong"}); resp.StatusCode != fiber.StatusForbidden {
+		t.Fatalf("expected 403 with wrong csrf token, got %d", resp.StatusCode)
+	}
+	if resp, _ = send(t, app, "POST", "/logout", cookie, map[string]string{DefaultCSRFHeader: token}); resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected csrf token to be accepted, got %d", resp.StatusCode)
+	}
+}
+
+func TestNewValidatesConfig(t *testing.T) {
+	store := NewRedisStore(redisClient.NewClient(&redisClient.Options{Addr: "127.0.0.1:0"}))
+	if _, err := New(store, &Config{Secret: "short"}); err == nil {
+		t.Fatal("expected short secret to be rejected")
+	}
+	if _, err := New(store, &Config{Secret: testSecret, CookieSameSite: "Loose"}); err == nil {
+		t.Fatal("expected invalid sameSite to be rejected")
+	}
+	if _, err := New(store, &Config{Secret: testSecret, TTL: types.Duration(-time.Second)}); err == nil {
+		t.Fatal("expected invalid ttl to be rejected")
+	}
+}
@@ -0,0 +1,114 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	redisClient "github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// DefaultTable GORM 存储默认表名
+	DefaultTable = "quickgo_settings"
+	// DefaultRedisKey Redis 存储默认 hash key
+	DefaultRedisKey = "quickgo:settings"
+)
+
+// Setting GORM 存储的配置项记录
+type Setting struct {
+	Key       string `gorm:"primaryKey;size:191"`
+	Value     string `gorm:"type:text;not null"`
+	UpdatedAt time.Time
+}
+
+// GormBackend 基于数据库表的配置项存储
+type GormBackend struct {
+	db    *gorm.DB
+	table string
+}
+
+// NewGormBackend 创建 GORM 存储，table 为空时使用 DefaultTable
+func NewGormBackend(db *gorm.DB, table string) (*GormBackend, error) {
+	if db == nil {
+		return nil, errors.New("gorm db is nil")
+	}
+	if table == "" {
+		table = DefaultTable
+	}
+	return &GormBackend{db: db, table: table}, nil
+}
+
+// AutoMigrate 创建或更新配置项表
+func (b *GormBackend) AutoMigrate(ctx context.Context) error {
+	return b.db.WithContext(ctx).Table(b.table).AutoMigrate(&Setting{})
+}
+
+// LoadAll 读取全部配置项
+func (b *GormBackend) LoadAll(ctx context.Context) (map[string][]byte, error) {
+	var rows []Setting
+	if err := b.db.WithContext(ctx).Table(b.table).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	values := make(map[string][]byte, len(rows))
+	for _, row := range rows {
+		values[row.Key] = []byte(row.Value)
+	}
+	return values, nil
+}
+
+// Store 写入配置项（存在时覆盖）
+func (b *GormBackend) Store(ctx context.Context, key string, value []byte) error {
+	row := Setting{Key: key, Value: string(value), UpdatedAt: time.Now()}
+	return b.db.WithContext(ctx).Table(b.table).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(&row).Error
+}
+
+// Delete 删除配置项
+func (b *GormBackend) Delete(ctx context.Context, key string) error {
+	return b.db.WithContext(ctx).Table(b.table).Where(clause.Eq{Column: clause.Column{Name: "key"}, Value: key}).Delete(&Setting{}).Error
+}
+
+// RedisBackend 基于 Redis hash 的配置项存储
+type RedisBackend struct {
+	client redisClient.Cmdable
+	key    string
+}
+
+// NewRedisBackend 创建 Redis 存储，key 为空时使用 DefaultRedisKey
+func NewRedisBackend(client redisClient.Cmdable, key string) (*RedisBackend, error) {
+	if client == nil {
+		return nil, errors.New("redis client is nil")
+	}
+	if key == "" {
+		key = DefaultRedisKey
+	}
+	return &RedisBackend{client: client, key: key}, nil
+}
+
+// LoadAll 读取全部配置项
+func (b *RedisBackend) LoadAll(ctx context.Context) (map[string][]byte, error) {
+	fields, err := b.client.HGetAll(ctx, b.key).Result()
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string][]byte, len(fields))
+	for field, value := range fields {
+		values[field] = []byte(value)
+	}
+	return values, nil
+}
+
+// Store 写入配置项
+func (b *RedisBackend) Store(ctx context.Context, key string, value []byte) error {
+	return b.client.HSet(ctx, b.key, key, value).Err()
+}
+
+// Delete 删除配置项
+func (b *RedisBackend) Delete(ctx context.Context, key string) error {
+	return b.client.HDel(ctx, b.key, key).Err()
+}
@@ -0,0 +1,310 @@
+package settings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// ErrNotFound 配置项不存在
+var ErrNotFound = errors.New("settings: not found")
+
+const defaultRefreshInterval = 30 * time.Second
+
+// Config 配置中心配置
+type Config struct {
+	// 从存储刷新全部配置项的间隔，用于感知其他实例的修改，示例：30s（默认 30s，负数表示关闭）
+	RefreshInterval string `json:"refreshInterval" yaml:"refreshInterval" toml:"refreshInterval"`
+}
+
+// Backend 配置项存储，值为 JSON 编码后的字节
+type Backend interface {
+	// LoadAll 读取全部配置项
+	LoadAll(ctx context.Context) (map[string][]byte, error)
+	// Store 写入（新增或覆盖）配置项
+	Store(ctx context.Context, key string, value []byte) error
+	// Delete 删除配置项，不存在时不报错
+	Delete(ctx context.Context, key string) error
+}
+
+// Change 配置项变更
+type Change struct {
+	Key string
+	// 变更前的值（新增时为 nil）
+	Old []byte
+	// 变更后的值（删除时为 nil）
+	New     []byte
+	Deleted bool
+}
+
+// Decode 将变更后的值解码到 dst
+func (c Change) Decode(dst interface{}) error {
+	if c.Deleted {
+		return ErrNotFound
+	}
+	return json.Unmarshal(c.New, dst)
+}
+
+type watcher struct {
+	id  uint64
+	key string // 空表示监听全部
+	fn  func(Change)
+}
+
+// Store 运行时可调整的业务配置，全部配置项缓存在进程内，读取不访问存储
+// 本实例的 Set/Delete 立即生效并通知监听者，其他实例的修改在下次刷新时生效
+type Store struct {
+	backend  Backend
+	interval time.Duration
+
+	mu       sync.RWMutex
+	values   map[string][]byte
+	watchers []watcher
+	nextID   uint64
+
+	// refreshMu 串行化刷新与写入，避免刷新结果覆盖并发写入
+	refreshMu sync.Mutex
+	closeOnce sync.Once
+	closed    chan struct{}
+	done      chan struct{}
+}
+
+// New 创建配置中心并加载全部配置项，RefreshInterval 大于 0 时启动后台刷新
+func New(ctx context.Context, backend Backend, config *Config) (*Store, error) {
+	if backend == nil {
+		return nil, errors.New("settings backend is nil")
+	}
+	if config == nil {
+		config = &Config{}
+	}
+	s := &Store{
+		backend:  backend,
+		interval: defaultRefreshInterval,
+		values:   make(map[string][]byte),
+		closed:   make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if config.RefreshInterval != "" {
+		interval, err := time.ParseDuration(config.RefreshInterval)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RefreshInterval %s: %w", config.RefreshInterval, err)
+		}
+		s.interval = interval
+	}
+	if err := s.Refresh(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+	if s.interval > 0 {
+		go s.run()
+	} else {
+		close(s.done)
+	}
+	return s, nil
+}
+
+// Close 停止后台刷新
+func (s *Store) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+	<-s.done
+}
+
+func (s *Store) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closed:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), s.interval)
+			if err := s.Refresh(ctx); err != nil {
+				logger.Warn(ctx, "Failed to refresh settings: %v", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// Refresh 从存储重新加载全部配置项，并通知发生变化的配置项
+func (s *Store) Refresh(ctx context.Context) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	values, err := s.backend.LoadAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	var changes []Change
+	for key, value := range values {
+		if old, ok := s.values[key]; !ok || !bytes.Equal(old, value) {
+			changes = append(changes, Change{Key: key, Old: old, New: value})
+		}
+	}
+	for key, old := range s.values {
+		if _, ok := values[key]; !ok {
+			changes = append(changes, Change{Key: key, Old: old, Deleted: true})
+		}
+	}
+	s.values = values
+	s.mu.Unlock()
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	for _, change := range changes {
+		s.notify(change)
+	}
+	return nil
+}
+
+// Get 读取配置项并解码到 dst，不存在时返回 ErrNotFound
+func (s *Store) Get(key string, dst interface{}) error {
+	s.mu.RLock()
+	data, ok := s.values[key]
+	s.mu.RUnlock()
+	if !ok {
+		return ErrNotFound
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("failed to decode setting %s: %w", key, err)
+	}
+	return nil
+}
+
+// Has 判断配置项是否存在
+func (s *Store) Has(key string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.values[key]
+	return ok
+}
+
+// Keys 返回全部配置项 key（已排序）
+func (s *Store) Keys() []string {
+	s.mu.RLock()
+	keys := make([]string, 0, len(s.values))
+	for key := range s.values {
+		keys = append(keys, key)
+	}
+	s.mu.RUnlock()
+	sort.Strings(keys)
+	return keys
+}
+
+// Set 写入配置项（JSON 编码）并通知监听者
+func (s *Store) Set(ctx context.Context, key string, value interface{}) error {
+	if key == "" {
+		return errors.New("settings key is empty")
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode setting %s: %w", key, err)
+	}
+
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	if err := s.backend.Store(ctx, key, data); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	old, existed := s.values[key]
+	s.values[key] = data
+	s.mu.Unlock()
+	if !existed || !bytes.Equal(old, data) {
+		s.notify(Change{Key: key, Old: old, New: data})
+	}
+	return nil
+}
+
+// Delete 删除配置项并通知监听者
+func (s *Store) Delete(ctx context.Context, key string) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	if err := s.backend.Delete(ctx, key); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	old, existed := s.values[key]
+	delete(s.values, key)
+	s.mu.Unlock()
+	if existed {
+		s.notify(Change{Key: key, Old: old, Deleted: true})
+	}
+	return nil
+}
+
+// Watch 监听指定配置项变更（key 为空时监听全部），返回取消监听函数
+// 回调在触发变更的 goroutine（Set/Delete 调用方或后台刷新）中同步执行，不应阻塞
+func (s *Store) Watch(key string, fn func(Change)) func() {
+	s.mu.Lock()
+	s.nextID++
+	id := s.nextID
+	s.watchers = append(s.watchers, watcher{id: id, key: key, fn: fn})
+	s.mu.Unlock()
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, w := range s.watchers {
+			if w.id == id {
+				s.watchers = append(s.watchers[:i:i], s.watchers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (s *Store) notify(change Change) {
+	s.mu.RLock()
+	watchers := s.watchers
+	s.mu.RUnlock()
+	for _, w := range watchers {
+		if w.key == "" || w.key == change.Key {
+			w.fn(change)
+		}
+	}
+}
+
+// Value 读取指定类型的配置项，不存在时返回 ErrNotFound
+func Value[T any](s *Store, key string) (T, error) {
+	var value T
+	err := s.Get(key, &value)
+	return value, err
+}
+
+// GetOr 读取指定类型的配置项，不存在或解码失败时返回默认值
+func GetOr[T any](s *Store, key string, def T) T {
+	value, err := Value[T](s, key)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			logger.Warn(context.Background(), "Invalid setting, using default: key=%s, error=%v", key, err)
+		}
+		return def
+	}
+	return value
+}
+
+// OnChange 监听指定配置项的类型化变更，删除或解码失败时回调 def
+func OnChange[T any](s *Store, key string, def T, fn func(T)) func() {
+	return s.Watch(key, func(change Change) {
+		var value T
+		if err := change.Decode(&value); err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				logger.Warn(context.Background(), "Invalid setting, using default: key=%s, error=%v", key, err)
+			}
+			fn(def)
+			return
+		}
+		fn(value)
+	})
+}
@@ -0,0 +1,169 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	redisClient "github.com/redis/go-redis/v9"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type featureFlags struct {
+	Checkout bool `json:"checkout"`
+	MaxItems int  `json:"maxItems"`
+}
+
+func newRedisBackend(t *testing.T) (*miniredis.Miniredis, *RedisBackend) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redisClient.NewClient(&redisClient.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	backend, err := NewRedisBackend(client, "")
+	if err != nil {
+		t.Fatalf("NewRedisBackend failed: %v", err)
+	}
+	return server, backend
+}
+
+func newGormBackend(t *testing.T) *GormBackend {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "settings.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open failed: %v", err)
+	}
+	backend, err := NewGormBackend(db, "")
+	if err != nil {
+		t.Fatalf("NewGormBackend failed: %v", err)
+	}
+	if err := backend.AutoMigrate(context.Background()); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	return backend
+}
+
+func TestStoreTypedGetSetAndNotify(t *testing.T) {
+	_, redisBackend := newRedisBackend(t)
+	backends := map[string]Backend{"redis": redisBackend, "gorm": newGormBackend(t)}
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store, err := New(ctx, backend, &Config{RefreshInterval: "-1s"})
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			defer store.Close()
+
+			if got := GetOr(store, "flags", featureFlags{MaxItems: 10}); got.MaxItems != 10 {
+				t.Fatalf("expected default, got %+v", got)
+			}
+
+			var changes []featureFlags
+			unwatch := OnChange(store, "flags", featureFlags{}, func(v featureFlags) { changes = append(changes, v) })
+			var all []string
+			store.Watch("", func(c Change) { all = append(all, c.Key) })
+
+			if err := store.Set(ctx, "flags", featureFlags{Checkout: true, MaxItems: 50}); err != nil {
+				t.Fatalf("Set failed: %v", err)
+			}
+			// 相同值不重复通知
+			if err := store.Set(ctx, "flags", featureFlags{Checkout: true, MaxItems: 50}); err != nil {
+				t.Fatalf("Set failed: %v", err)
+			}
+			if err := store.Set(ctx, "banner", "hello"); err != nil {
+				t.Fatalf("Set failed: %v", err)
+			}
+
+			flags, err := Value[featureFlags](store, "flags")
+			if err != nil || !flags.Checkout || flags.MaxItems != 50 {
+				t.Fatalf("unexpected flags %+v, err=%v", flags, err)
+			}
+			if got := GetOr(store, "banner", ""); got != "hello" {
+				t.Fatalf("unexpected banner %q", got)
+			}
+			if got := GetOr(store, "banner", 7); got != 7 {
+				t.Fatalf("expected default for mismatched type, got %d", got)
+			}
+
+			// 重新加载后从存储读取到相同的值
+			reloaded, err := New(ctx, backend, &Config{RefreshInterval: "-1s"})
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			defer reloaded.Close()
+			if keys := reloaded.Keys(); len(keys) != 2 || keys[0] != "banner" || keys[1] != "flags" {
+				t.Fatalf("unexpected keys %v", keys)
+			}
+
+			if err := store.Delete(ctx, "flags"); err != nil {
+				t.Fatalf("Delete failed: %v", err)
+			}
+			if _, err := Value[featureFlags](store, "flags"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("expected ErrNotFound, got %v", err)
+			}
+			unwatch()
+			if err := store.Set(ctx, "flags", featureFlags{}); err != nil {
+				t.Fatalf("Set failed: %v", err)
+			}
+
+			if len(changes) != 2 || changes[0].MaxItems != 50 || changes[1] != (featureFlags{}) {
+				t.Fatalf("unexpected typed changes %+v", changes)
+			}
+			if len(all) != 4 {
+				t.Fatalf("unexpected changes %v", all)
+			}
+		})
+	}
+}
+
+func TestStoreRefreshPicksUpExternalChanges(t *testing.T) {
+	server, backend := newRedisBackend(t)
+	server.HSet(DefaultRedisKey, "limit", "5")
+
+	store, err := New(context.Background(), backend, &Config{RefreshInterval: "10ms"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer store.Close()
+	if got := GetOr(store, "limit", 0); got != 5 {
+		t.Fatalf("expected initial value 5, got %d", got)
+	}
+
+	var (
+		mu      sync.Mutex
+		changes []Change
+	)
+	store.Watch("limit", func(c Change) {
+		mu.Lock()
+		changes = append(changes, c)
+		mu.Unlock()
+	})
+
+	server.HSet(DefaultRedisKey, "limit", "8")
+	deadline := time.Now().Add(2 * time.Second)
+	for GetOr(store, "limit", 0) != 8 {
+		if time.Now().After(deadline) {
+			t.Fatal("refresh did not pick up external change")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	server.HDel(DefaultRedisKey, "limit")
+	for store.Has("limit") {
+		if time.Now().After(deadline) {
+			t.Fatal("refresh did not pick up external delete")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(changes) != 2 || string(changes[0].Old) != "5" || string(changes[0].New) != "8" || !changes[1].Deleted {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+}
@@ -0,0 +1,74 @@
+package quickgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// ShutdownReport 框架停止报告，Stop 结束时以单条结构化日志输出，便于发布后核对与事故时间线分析
+type ShutdownReport struct {
+	// 开始停止的时间
+	StartedAt time.Time `json:"startedAt"`
+	// 停止总耗时（毫秒）
+	DurationMs float64 `json:"durationMs"`
+	// 各组件停止情况（按停止顺序）
+	Components []ComponentShutdown `json:"components"`
+	// 停止时仍在处理、等待完成的 HTTP 请求数
+	InFlightDrained int64 `json:"inFlightDrained"`
+	// 关闭的连接数（HTTP 客户端连接与 gRPC 客户端连接）
+	ConnectionsClosed int `json:"connectionsClosed"`
+	// 停止期间导出的 span 数
+	SpansFlushed uint64 `json:"spansFlushed"`
+	// 停止期间丢弃的 span 数
+	SpansDropped uint64 `json:"spansDropped"`
+	// 停止过程中的错误
+	Errors []string `json:"errors,omitempty"`
+}
+
+// ComponentShutdown 单个组件的停止情况
+type ComponentShutdown struct {
+	Name       string  `json:"name"`
+	DurationMs float64 `json:"durationMs"`
+	Error      string  `json:"error,omitempty"`
+}
+
+func newShutdownReport() *ShutdownReport {
+	return &ShutdownReport{StartedAt: time.Now()}
+}
+
+// record 记录组件停止耗时与错误
+func (r *ShutdownReport) record(name string, duration time.Duration, err error) {
+	component := ComponentShutdown{Name: name, DurationMs: durationMs(duration)}
+	if err != nil {
+		component.Error = err.Error()
+		r.Errors = append(r.Errors, name+": "+err.Error())
+	}
+	r.Components = append(r.Components, component)
+}
+
+// finish 计算总耗时并输出报告
+func (r *ShutdownReport) finish(ctx context.Context) {
+	r.DurationMs = durationMs(time.Since(r.StartedAt))
+	fields := map[string]interface{}{
+		"duration_ms":        r.DurationMs,
+		"components":         r.Components,
+		"in_flight_drained":  r.InFlightDrained,
+		"connections_closed": r.ConnectionsClosed,
+		"spans_flushed":      r.SpansFlushed,
+		"spans_dropped":      r.SpansDropped,
+	}
+	if len(r.Errors) > 0 {
+		fields["errors"] = r.Errors
+		logger.WithFields(fields).Warn(ctx, "Shutdown report: duration=%.1fms, components=%d, errors=%d",
+			r.DurationMs, len(r.Components), len(r.Errors))
+		return
+	}
+	logger.WithFields(fields).Info(ctx, "Shutdown report: duration=%.1fms, components=%d, in_flight_drained=%d, connections_closed=%d, spans_flushed=%d",
+		r.DurationMs, len(r.Components), r.InFlightDrained, r.ConnectionsClosed, r.SpansFlushed)
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,90 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor gRPC 一元调用租户拦截器，租户 ID 取自 metadata（Config.Header 的小写形式，如 x-tenant-id）
+// 缺少租户返回 InvalidArgument，租户不一致或不合法返回 PermissionDenied
+func (r *Resolver) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := r.serverContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor gRPC 流式调用租户拦截器
+func (r *Resolver) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := r.serverContext(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &tenantServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// UnaryClientInterceptor 将 context 中的租户 ID 写入下游调用的 metadata
+func (r *Resolver) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(r.outgoingContext(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor 将 context 中的租户 ID 写入下游流式调用的 metadata
+func (r *Resolver) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(r.outgoingContext(ctx), desc, cc, method, opts...)
+	}
+}
+
+// serverContext 从 metadata 提取租户 ID 并写入 context
+func (r *Resolver) serverContext(ctx context.Context) (context.Context, error) {
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(r.metadataKey); len(values) > 0 {
+			header = values[0]
+		}
+	}
+	id, err := r.resolve(ctx, header)
+	switch {
+	case errors.Is(err, ErrTenantRequired):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case id == "":
+		return ctx, nil
+	}
+	return WithTenant(ctx, id), nil
+}
+
+// outgoingContext 将租户 ID 写入 outgoing metadata（已存在时不覆盖）
+func (r *Resolver) outgoingContext(ctx context.Context) context.Context {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	if md, exists := metadata.FromOutgoingContext(ctx); exists && len(md.Get(r.metadataKey)) > 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, r.metadataKey, id)
+}
+
+// tenantServerStream 替换 context 的 ServerStream
+type tenantServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tenantServerStream) Context() context.Context {
+	return s.ctx
+}
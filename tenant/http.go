@@ -0,0 +1,31 @@
+package tenant
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LocalsKey fiber Locals 中的租户 ID 键
+const LocalsKey = "tenant_id"
+
+// FiberMiddleware HTTP 租户中间件
+// 提取租户 ID 写入 UserContext 与 Locals，并写入 UserValues（键为请求头的小写形式），
+// 经 grpcep.BaseHandler.RPCCtx 透传为下游 gRPC metadata；缺少租户返回 400，租户不一致或不合法返回 403
+func (r *Resolver) FiberMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := r.resolve(c.UserContext(), c.Get(r.config.Header))
+		switch {
+		case errors.Is(err, ErrTenantRequired):
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		case err != nil:
+			return fiber.NewError(fiber.StatusForbidden, err.Error())
+		case id == "":
+			return c.Next()
+		}
+		c.SetUserContext(WithTenant(c.UserContext(), id))
+		c.Locals(LocalsKey, id)
+		c.Context().SetUserValue(r.metadataKey, id)
+		return c.Next()
+	}
+}
@@ -0,0 +1,150 @@
+// Package tenant 提供多租户的租户标识提取与传递
+//
+// HTTP 使用 FiberMiddleware，gRPC 使用 UnaryServerInterceptor / StreamServerInterceptor，
+// 从请求头 / metadata 或已验证的 JWT claim 中提取租户 ID 写入 context，业务代码与 db/gorm 的租户隔离通过 FromContext 读取；
+// 调用下游 gRPC 服务时 UnaryClientInterceptor 将租户 ID 写入 metadata：
+//
+//	resolver, _ := tenant.New(&tenant.Config{Required: true})
+//	app.Use(resolver.FiberMiddleware())
+//	id, _ := tenant.FromContext(ctx)
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultHeader 默认的租户请求头（gRPC metadata 使用小写形式）
+	DefaultHeader = "X-Tenant-ID"
+	// DefaultClaim 默认的租户 claim 名称
+	DefaultClaim = "tenant_id"
+
+	maxIDLength = 128
+)
+
+var (
+	// ErrTenantRequired 请求缺少租户 ID（Config.Required 为 true 时）
+	ErrTenantRequired = errors.New("tenant: tenant id is required")
+	// ErrTenantMismatch 请求头中的租户 ID 与 claim 中的租户 ID 不一致
+	ErrTenantMismatch = errors.New("tenant: tenant id does not match the authenticated tenant")
+	// ErrInvalidTenant 租户 ID 不合法（过长、包含控制字符或未通过 Config.Validate）
+	ErrInvalidTenant = errors.New("tenant: invalid tenant id")
+)
+
+// Config 租户提取配置
+type Config struct {
+	// 租户请求头 示例：X-Tenant-ID（默认）
+	Header string `json:"header" yaml:"header" toml:"header"`
+	// JWT claim 名称 示例：tenant_id（默认），配置 Claims 时生效
+	Claim string `json:"claim" yaml:"claim" toml:"claim"`
+	// 是否要求请求必须携带租户 ID
+	Required bool `json:"required" yaml:"required" toml:"required"`
+	// 是否忽略请求头（仅信任 claim），避免客户端伪造租户
+	IgnoreHeader bool `json:"ignoreHeader" yaml:"ignoreHeader" toml:"ignoreHeader"`
+	// 读取已验证的 JWT claims（由鉴权中间件/拦截器写入 context），claim 中的租户优先于请求头，两者不一致时拒绝请求
+	Claims func(ctx context.Context) map[string]interface{} `json:"-" yaml:"-" toml:"-"`
+	// 租户 ID 校验（如检查租户是否存在、是否停用），返回错误时拒绝请求
+	Validate func(ctx context.Context, id string) error `json:"-" yaml:"-" toml:"-"`
+}
+
+// Resolver 租户提取器
+type Resolver struct {
+	config Config
+	// gRPC metadata 键（请求头的小写形式）
+	metadataKey string
+}
+
+// New 创建租户提取器
+func New(config *Config) (*Resolver, error) {
+	r := &Resolver{}
+	if config != nil {
+		r.config = *config
+	}
+	if r.config.Header == "" {
+		r.config.Header = DefaultHeader
+	}
+	if r.config.Claim == "" {
+		r.config.Claim = DefaultClaim
+	}
+	if r.config.IgnoreHeader && r.config.Claims == nil {
+		return nil, errors.New("tenant claims func is required when ignoreHeader is set")
+	}
+	r.metadataKey = strings.ToLower(r.config.Header)
+	return r, nil
+}
+
+// Header 返回租户请求头
+func (r *Resolver) Header() string {
+	return r.config.Header
+}
+
+// resolve 按 claim、请求头的顺序确定租户 ID，未携带租户时返回空字符串
+func (r *Resolver) resolve(ctx context.Context, header string) (string, error) {
+	header = strings.TrimSpace(header)
+	if r.config.IgnoreHeader {
+		header = ""
+	}
+	var claimed string
+	if r.config.Claims != nil {
+		if claims := r.config.Claims(ctx); claims != nil {
+			if value, ok := claims[r.config.Claim]; ok && value != nil {
+				claimed = strings.TrimSpace(fmt.Sprint(value))
+			}
+		}
+	}
+
+	id := header
+	if claimed != "" {
+		if header != "" && header != claimed {
+			return "", ErrTenantMismatch
+		}
+		id = claimed
+	}
+	if id == "" {
+		if r.config.Required {
+			return "", ErrTenantRequired
+		}
+		return "", nil
+	}
+	if err := validateID(id); err != nil {
+		return "", err
+	}
+	if r.config.Validate != nil {
+		if err := r.config.Validate(ctx, id); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidTenant, err)
+		}
+	}
+	return id, nil
+}
+
+// validateID 校验租户 ID 的长度与字符
+func validateID(id string) error {
+	if len(id) > maxIDLength {
+		return fmt.Errorf("%w: too long", ErrInvalidTenant)
+	}
+	for _, r := range id {
+		if r < 0x20 || r == 0x7f {
+			return fmt.Errorf("%w: contains control characters", ErrInvalidTenant)
+		}
+	}
+	return nil
+}
+
+type contextKey struct{}
+
+// WithTenant 返回携带租户 ID 的 context
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext 读取 context 中的租户 ID
+func FromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok && id != ""
+}
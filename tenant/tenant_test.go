@@ -0,0 +1,127 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type claimsKey struct{}
+
+func claimsFromContext(ctx context.Context) map[string]interface{} {
+	claims, _ := ctx.Value(claimsKey{}).(map[string]interface{})
+	return claims
+}
+
+func TestResolverResolve(t *testing.T) {
+	r, err := New(&Config{Claims: claimsFromContext})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	authed := context.WithValue(context.Background(), claimsKey{}, map[string]interface{}{"tenant_id": "acme"})
+
+	if id, err := r.resolve(context.Background(), " globex "); err != nil || id != "globex" {
+		t.Fatalf("expected header tenant, got %q (%v)", id, err)
+	}
+	if id, err := r.resolve(authed, ""); err != nil || id != "acme" {
+		t.Fatalf("expected claim tenant, got %q (%v)", id, err)
+	}
+	if _, err := r.resolve(authed, "globex"); !errors.Is(err, ErrTenantMismatch) {
+		t.Fatalf("expected mismatch error, got %v", err)
+	}
+	if _, err := r.resolve(context.Background(), "bad\nid"); !errors.Is(err, ErrInvalidTenant) {
+		t.Fatalf("expected invalid tenant error, got %v", err)
+	}
+	if id, err := r.resolve(context.Background(), ""); err != nil || id != "" {
+		t.Fatalf("expected no tenant, got %q (%v)", id, err)
+	}
+
+	if _, err := New(&Config{IgnoreHeader: true}); err == nil {
+		t.Fatal("expected ignoreHeader without claims to be rejected")
+	}
+	strict, _ := New(&Config{Required: true, IgnoreHeader: true, Claims: claimsFromContext,
+		Validate: func(ctx context.Context, id string) error {
+			if id == "suspended" {
+				return errors.New("tenant suspended")
+			}
+			return nil
+		}})
+	if _, err := strict.resolve(context.Background(), "acme"); !errors.Is(err, ErrTenantRequired) {
+		t.Fatalf("expected header to be ignored, got %v", err)
+	}
+	suspended := context.WithValue(context.Background(), claimsKey{}, map[string]interface{}{"tenant_id": "suspended"})
+	if _, err := strict.resolve(suspended, ""); !errors.Is(err, ErrInvalidTenant) {
+		t.Fatalf("expected validate error, got %v", err)
+	}
+}
+
+func TestFiberMiddleware(t *testing.T) {
+	r, _ := New(&Config{Required: true})
+	app := fiber.New()
+	app.Use(r.FiberMiddleware())
+	app.Get("/", func(c *fiber.Ctx) error {
+		id, _ := FromContext(c.UserContext())
+		forwarded, _ := c.Context().UserValue("x-tenant-id").(string)
+		return c.SendString(id + "|" + c.Locals(LocalsKey).(string) + "|" + forwarded)
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(DefaultHeader, "acme")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 || string(body) != "acme|acme|acme" {
+		t.Fatalf("unexpected response: %d %s", resp.StatusCode, body)
+	}
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 without tenant, got %d", resp.StatusCode)
+	}
+}
+
+func TestGrpcInterceptors(t *testing.T) {
+	r, _ := New(&Config{Required: true})
+	server := r.UnaryServerInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		id, _ := FromContext(ctx)
+		return id, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant-id", "acme"))
+	resp, err := server(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Call"}, handler)
+	if err != nil || resp != "acme" {
+		t.Fatalf("expected tenant from metadata, got %v (%v)", resp, err)
+	}
+	_, err = server(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Call"}, handler)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument without tenant, got %v", err)
+	}
+
+	var forwarded []string
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		forwarded = md.Get("x-tenant-id")
+		return nil
+	}
+	client := r.UnaryClientInterceptor()
+	if err := client(WithTenant(context.Background(), "acme"), "/test.Service/Call", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("client interceptor failed: %v", err)
+	}
+	if len(forwarded) != 1 || forwarded[0] != "acme" {
+		t.Fatalf("expected tenant to be forwarded, got %v", forwarded)
+	}
+}
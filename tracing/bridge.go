@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+const (
+	// TraceIDHeader 自定义 trace ID 请求/响应头（兼容未接入 W3C Trace Context 的调用方）
+	TraceIDHeader = "X-Trace-ID"
+	// TraceparentHeader W3C Trace Context 请求头
+	TraceparentHeader = "traceparent"
+)
+
+// resolveOTelTrace 从 OpenTelemetry span context 中解析 trace ID 和 span ID
+// 注册为 logger 的链路解析器后，logger.GetTraceID 与 span 使用同一个 trace ID
+func resolveOTelTrace(ctx context.Context) (string, string) {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.IsValid() {
+		return "", ""
+	}
+	return spanCtx.TraceID().String(), spanCtx.SpanID().String()
+}
+
+// ContextWithRemoteTraceID 以 trace ID（32 位十六进制）构造远程父 span context
+// 用于兼容仅携带 X-Trace-ID 的调用方：新建的 span 沿用该 trace ID；ctx 已有有效 span context 或 ID 非法时原样返回
+func ContextWithRemoteTraceID(ctx context.Context, traceID string) context.Context {
+	if traceID == "" || trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	tid, err := trace.TraceIDFromHex(traceID)
+	if err != nil {
+		return ctx
+	}
+	var sid trace.SpanID
+	if _, err := rand.Read(sid[:]); err != nil || !sid.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: tid,
+		SpanID:  sid,
+		Remote:  true,
+	}))
+}
+
+// FormatTraceparent 由 trace ID 和 span ID 生成 W3C traceparent 值，ID 非法时返回 false
+func FormatTraceparent(traceID, spanID string, sampled bool) (string, bool) {
+	tid, err := trace.TraceIDFromHex(traceID)
+	if err != nil {
+		return "", false
+	}
+	sid, err := trace.SpanIDFromHex(spanID)
+	if err != nil {
+		return "", false
+	}
+	var flags trace.TraceFlags
+	if sampled {
+		flags = trace.FlagsSampled
+	}
+	carrier := propagation.MapCarrier{}
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: flags,
+	}))
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	value := carrier.Get(TraceparentHeader)
+	return value, value != ""
+}
+
+// ParseTraceparent 解析 W3C traceparent 值，返回 trace ID 和父 span ID
+func ParseTraceparent(value string) (traceID, spanID string, ok bool) {
+	if value == "" {
+		return "", "", false
+	}
+	ctx := propagation.TraceContext{}.Extract(context.Background(), propagation.MapCarrier{TraceparentHeader: value})
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.IsValid() {
+		return "", "", false
+	}
+	return spanCtx.TraceID().String(), spanCtx.SpanID().String(), true
+}
+
+// installTraceResolver 启用/关闭 logger 与 OpenTelemetry 的 trace ID 统一
+func installTraceResolver(enabled bool) {
+	if enabled {
+		logger.SetTraceResolver(resolveOTelTrace)
+		return
+	}
+	logger.SetTraceResolver(nil)
+}
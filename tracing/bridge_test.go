@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+func TestLoggerTraceIDFollowsOTelSpan(t *testing.T) {
+	config := DefaultConfig()
+	config.Enabled = true
+	if err := Init(&config); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	ctx, span := StartSpan(logger.WithTraceID(context.Background(), "legacy"), "bridge")
+	defer span.End()
+	if got, want := logger.GetTraceID(ctx), span.SpanContext().TraceID().String(); got != want {
+		t.Fatalf("expected logger trace ID %q to match span, got %q", want, got)
+	}
+	if got, want := logger.GetSpanID(ctx), span.SpanContext().SpanID().String(); got != want {
+		t.Fatalf("expected logger span ID %q to match span, got %q", want, got)
+	}
+
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if got := logger.GetTraceID(ctx); got != "legacy" {
+		t.Fatalf("expected logger trace ID to fall back after shutdown, got %q", got)
+	}
+}
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	spanID := "00f067aa0ba902b7"
+	value, ok := FormatTraceparent(traceID, spanID, true)
+	if !ok || value != "00-"+traceID+"-"+spanID+"-01" {
+		t.Fatalf("unexpected traceparent %q (ok=%v)", value, ok)
+	}
+	gotTrace, gotSpan, ok := ParseTraceparent(value)
+	if !ok || gotTrace != traceID || gotSpan != spanID {
+		t.Fatalf("unexpected parse result %q %q %v", gotTrace, gotSpan, ok)
+	}
+	if _, ok := FormatTraceparent("not-hex", spanID, false); ok {
+		t.Fatal("expected invalid trace ID to be rejected")
+	}
+}
+
+func TestMiddlewareContinuesLegacyTraceIDHeader(t *testing.T) {
+	config := DefaultConfig()
+	config.Enabled = true
+	if err := Init(&config); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Shutdown(context.Background())
+
+	traceID := logger.GenerateTraceID()
+	var handlerTraceID string
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(Middleware())
+	app.Get("/", func(c *fiber.Ctx) error {
+		handlerTraceID = logger.GetTraceID(c.UserContext())
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(TraceIDHeader, traceID)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if handlerTraceID != traceID {
+		t.Fatalf("expected span to continue trace %q, got %q", traceID, handlerTraceID)
+	}
+	if got := resp.Header.Get(TraceIDHeader); got != traceID {
+		t.Fatalf("expected response %s header %q, got %q", TraceIDHeader, traceID, got)
+	}
+	if got := resp.Header.Get(TraceparentHeader); got == "" {
+		t.Fatal("expected traceparent response header")
+	}
+}
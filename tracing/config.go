@@ -10,6 +10,8 @@ type Config struct {
 	ServiceVersion string `json:"serviceVersion" yaml:"serviceVersion" toml:"serviceVersion"`
 	// 环境名称（如：dev、staging、prod）
 	Environment string `json:"environment" yaml:"environment" toml:"environment"`
+	// 禁用资源自动探测（默认探测主机名、操作系统、容器 ID、Kubernetes Pod/Namespace/Node 及 OTEL_RESOURCE_ATTRIBUTES）
+	DisableResourceDetection bool `json:"disableResourceDetection" yaml:"disableResourceDetection" toml:"disableResourceDetection"`
 	// Jaeger 配置（已废弃，建议使用 OTLP）
 	// Deprecated: 建议使用 OTLP 配置
 	Jaeger JaegerConfig `json:"jaeger" yaml:"jaeger" toml:"jaeger"`
@@ -17,6 +19,52 @@ type Config struct {
 	OTLP OTLPConfig `json:"otlp" yaml:"otlp" toml:"otlp"`
 	// 采样率（0.0-1.0，0.0 表示不采样，1.0 表示采样所有请求）
 	SamplingRate float64 `json:"samplingRate" yaml:"samplingRate" toml:"samplingRate"`
+	// 批量导出配置（队列满时丢弃 span，不阻塞业务请求）
+	Batcher BatcherConfig `json:"batcher" yaml:"batcher" toml:"batcher"`
+	// 关闭时等待剩余 span 导出的最长时间（如：5s），默认 5s；与 Shutdown 传入 context 的截止时间取较早者
+	ShutdownTimeout string `json:"shutdownTimeout" yaml:"shutdownTimeout" toml:"shutdownTimeout" validate:"duration"`
+	// OTel 指标导出配置（可选，通过 Meter() 获取 Meter）
+	Metrics *MetricsConfig `json:"metrics" yaml:"metrics" toml:"metrics"`
+	// OTel 日志导出配置（可选，通过 OTelLogger() 获取 Logger）
+	Logs *LogsConfig `json:"logs" yaml:"logs" toml:"logs"`
+	// gRPC 请求/响应载荷采集配置（可选，可按环境开启）
+	Payload *PayloadConfig `json:"payload" yaml:"payload" toml:"payload"`
+	// span 属性过滤配置（可选，导出前丢弃或脱敏属性）
+	AttributeFilter *AttributeFilterConfig `json:"attributeFilter" yaml:"attributeFilter" toml:"attributeFilter"`
+}
+
+// MetricsConfig OTel 指标导出配置
+type MetricsConfig struct {
+	// 是否启用 OTLP 指标导出
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled"`
+	// OTLP 配置（endpoint 为空时复用链路追踪的 OTLP 配置）
+	OTLP OTLPConfig `json:"otlp" yaml:"otlp" toml:"otlp"`
+	// 导出间隔（如：30s），默认 60s
+	Interval string `json:"interval" yaml:"interval" toml:"interval" validate:"duration"`
+}
+
+// LogsConfig OTel 日志导出配置
+type LogsConfig struct {
+	// 是否启用 OTLP 日志导出
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled"`
+	// OTLP 配置（endpoint 为空时复用链路追踪的 OTLP 配置）
+	OTLP OTLPConfig `json:"otlp" yaml:"otlp" toml:"otlp"`
+	// 批量导出间隔（如：1s），默认 1s
+	ExportInterval string `json:"exportInterval" yaml:"exportInterval" toml:"exportInterval" validate:"duration"`
+	// 队列最大长度，默认 2048
+	MaxQueueSize int `json:"maxQueueSize" yaml:"maxQueueSize" toml:"maxQueueSize"`
+}
+
+// BatcherConfig 批量导出配置
+type BatcherConfig struct {
+	// 队列最大长度，默认 2048，队列满时新 span 直接丢弃并计入 dropped 指标
+	MaxQueueSize int `json:"maxQueueSize" yaml:"maxQueueSize" toml:"maxQueueSize"`
+	// 单批最大 span 数，默认 512
+	MaxExportBatchSize int `json:"maxExportBatchSize" yaml:"maxExportBatchSize" toml:"maxExportBatchSize"`
+	// 批量导出间隔（如：5s），默认 5s
+	BatchTimeout string `json:"batchTimeout" yaml:"batchTimeout" toml:"batchTimeout" validate:"duration"`
+	// 单次导出超时（如：30s），默认 30s
+	ExportTimeout string `json:"exportTimeout" yaml:"exportTimeout" toml:"exportTimeout" validate:"duration"`
 }
 
 // DefaultConfig 返回推荐默认配置。
@@ -5,6 +5,7 @@ import (
 
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
@@ -14,8 +15,16 @@ import (
 func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
 	otelInterceptor := otelgrpc.UnaryServerInterceptor()
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		// 先调用 otelgrpc 拦截器（它会创建 span）
-		resp, err := otelInterceptor(ctx, req, info, handler)
+		// 先调用 otelgrpc 拦截器（它会创建 span），载荷写入 otelgrpc 创建的 span
+		resp, err := otelInterceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			span := trace.SpanFromContext(ctx)
+			recordPayload(span, RequestPayloadKey, CaptureRequestPayload, info.FullMethod, req)
+			resp, err := handler(ctx, req)
+			if err == nil {
+				recordPayload(span, ResponsePayloadKey, CaptureResponsePayload, info.FullMethod, resp)
+			}
+			return resp, err
+		})
 
 		// 获取 span 并添加 trace_id
 		span := trace.SpanFromContext(ctx)
@@ -50,8 +59,16 @@ func StreamServerInterceptor() grpc.StreamServerInterceptor {
 func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
 	otelInterceptor := otelgrpc.UnaryClientInterceptor()
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
-		// 先调用 otelgrpc 拦截器（它会创建 span）
-		err := otelInterceptor(ctx, method, req, reply, cc, invoker, opts...)
+		// 先调用 otelgrpc 拦截器（它会创建 span），载荷写入 otelgrpc 创建的 span
+		err := otelInterceptor(ctx, method, req, reply, cc, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			span := trace.SpanFromContext(ctx)
+			recordPayload(span, RequestPayloadKey, CaptureRequestPayload, method, req)
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil {
+				recordPayload(span, ResponsePayloadKey, CaptureResponsePayload, method, reply)
+			}
+			return err
+		}, opts...)
 
 		// 获取 span 并添加 trace_id
 		span := trace.SpanFromContext(ctx)
@@ -80,6 +97,16 @@ func StreamClientInterceptor() grpc.StreamClientInterceptor {
 	}
 }
 
+// recordPayload 采集载荷并写入 span 属性（span 未采样或未启用采集时跳过序列化）
+func recordPayload(span trace.Span, key attribute.Key, capture func(string, interface{}) (string, bool), method string, msg interface{}) {
+	if span == nil || !span.IsRecording() {
+		return
+	}
+	if payload, ok := capture(method, msg); ok {
+		span.SetAttributes(key.String(payload))
+	}
+}
+
 // ExtractTraceContext 从 gRPC metadata 中提取 trace context
 func ExtractTraceContext(ctx context.Context) context.Context {
 	md, ok := metadata.FromIncomingContext(ctx)
@@ -94,17 +121,17 @@ func ExtractTraceContext(ctx context.Context) context.Context {
 
 // InjectTraceContext 将 trace context 注入到 gRPC metadata 中
 func InjectTraceContext(ctx context.Context) context.Context {
+	// FromOutgoingContext 返回的是副本，注入后需重新写回 context
 	md, ok := metadata.FromOutgoingContext(ctx)
 	if !ok {
 		md = metadata.New(nil)
-		ctx = metadata.NewOutgoingContext(ctx, md)
 	}
 
 	// 使用 OpenTelemetry 的 propagator 注入 trace context
 	propagator := otel.GetTextMapPropagator()
 	propagator.Inject(ctx, &metadataCarrier{md: md})
 
-	return ctx
+	return metadata.NewOutgoingContext(ctx, md)
 }
 
 // metadataCarrier 实现 propagation.TextMapCarrier 接口，用于在 gRPC metadata 中传递 trace context
@@ -28,6 +28,8 @@ func Middleware() fiber.Handler {
 			headers[string(key)] = string(value)
 		})
 		ctx = propagator.Extract(ctx, &headerCarrier{headers: headers})
+		// 未携带 traceparent 时沿用 X-Trace-ID，保证跨跳 trace ID 一致
+		ctx = ContextWithRemoteTraceID(ctx, c.Get(TraceIDHeader))
 
 		// 创建 span
 		tracer := GetTracer()
@@ -61,6 +63,8 @@ func Middleware() fiber.Handler {
 		if traceID != "" {
 			c.Locals("trace_id", traceID)
 			c.Locals("request_id", traceID) // request_id 和 trace_id 使用同一个值
+			c.Locals("span_id", span.SpanContext().SpanID().String())
+			c.Set(TraceIDHeader, traceID)
 		}
 
 		// 处理请求
@@ -0,0 +1,114 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// removeLogBridge 注销日志桥接钩子（未启用日志导出时为 nil），调用方需持有 mu
+var removeLogBridge func()
+
+// logSeverities logger 级别名称到 OTel 日志级别的映射
+var logSeverities = map[string]otellog.Severity{
+	"DEBUG": otellog.SeverityDebug,
+	"INFO":  otellog.SeverityInfo,
+	"WARN":  otellog.SeverityWarn,
+	"ERROR": otellog.SeverityError,
+	"FATAL": otellog.SeverityFatal,
+}
+
+// installLogBridge 启用日志导出时注册 logger 钩子，将日志条目转发到 OTelLogger；未启用时注销，调用方需持有 mu
+func installLogBridge(enabled bool) {
+	switch {
+	case enabled && removeLogBridge == nil:
+		removeLogBridge = logger.AddEntryHook(emitLogEntry)
+	case !enabled && removeLogBridge != nil:
+		removeLogBridge()
+		removeLogBridge = nil
+	}
+}
+
+// emitLogEntry 将 logger 条目转换为 OTel 日志记录并发送，trace ID 与 span ID 通过 context 关联
+func emitLogEntry(entry logger.LogEntry) {
+	if !LogsEnabled() {
+		return
+	}
+
+	now := time.Now()
+	var record otellog.Record
+	record.SetObservedTimestamp(now)
+	if ts, err := time.Parse(time.RFC3339Nano, entry.Timestamp); err == nil {
+		record.SetTimestamp(ts)
+	} else {
+		record.SetTimestamp(now)
+	}
+	record.SetSeverity(logSeverities[entry.Level])
+	record.SetSeverityText(entry.Level)
+	record.SetBody(otellog.StringValue(entry.Message))
+
+	attrs := make([]otellog.KeyValue, 0, len(entry.Fields)+2)
+	if entry.Caller != "" {
+		attrs = append(attrs, otellog.String("code.caller", entry.Caller))
+	}
+	if entry.Error != "" {
+		attrs = append(attrs, otellog.String("exception.message", entry.Error))
+	}
+	for k, v := range entry.Fields {
+		attrs = append(attrs, otellog.KeyValue{Key: k, Value: logValue(v)})
+	}
+	record.AddAttributes(attrs...)
+
+	OTelLogger().Emit(entryContext(entry), record)
+}
+
+// entryContext 由条目中的 trace ID 和 span ID 构造 span context，ID 非法时返回空 context
+func entryContext(entry logger.LogEntry) context.Context {
+	ctx := context.Background()
+	tid, err := trace.TraceIDFromHex(entry.TraceID)
+	if err != nil {
+		return ctx
+	}
+	sid, err := trace.SpanIDFromHex(entry.SpanID)
+	if err != nil {
+		return ctx
+	}
+	return trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: trace.FlagsSampled,
+	}))
+}
+
+// logValue 将字段值转换为 OTel 日志属性值，不支持的类型按字符串记录
+func logValue(v interface{}) otellog.Value {
+	switch val := v.(type) {
+	case string:
+		return otellog.StringValue(val)
+	case bool:
+		return otellog.BoolValue(val)
+	case int:
+		return otellog.IntValue(val)
+	case int32:
+		return otellog.Int64Value(int64(val))
+	case int64:
+		return otellog.Int64Value(val)
+	case uint32:
+		return otellog.Int64Value(int64(val))
+	case float32:
+		return otellog.Float64Value(float64(val))
+	case float64:
+		return otellog.Float64Value(val)
+	case time.Duration:
+		return otellog.StringValue(val.String())
+	case error:
+		return otellog.StringValue(val.Error())
+	default:
+		return otellog.StringValue(fmt.Sprint(val))
+	}
+}
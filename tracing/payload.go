@@ -0,0 +1,300 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// RequestPayloadKey 请求载荷 span 属性名
+	RequestPayloadKey = attribute.Key("rpc.request.payload")
+	// ResponsePayloadKey 响应载荷 span 属性名
+	ResponsePayloadKey = attribute.Key("rpc.response.payload")
+
+	defaultPayloadMaxSize = 4096
+	defaultRedactMask     = "***"
+)
+
+// defaultRedactFields 默认脱敏字段（大小写不敏感，支持 * 通配符）
+var defaultRedactFields = []string{"*password*", "*passwd*", "*secret*", "*token*", "*apikey*", "*api_key*", "authorization", "cookie"}
+
+// PayloadConfig gRPC 请求/响应载荷采集配置（仅一元调用）
+// 载荷脱敏、截断后写入 span 属性（rpc.request.payload / rpc.response.payload）及 gRPC 日志拦截器的日志
+type PayloadConfig struct {
+	// 是否启用载荷采集
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled"`
+	// 仅在指定环境启用（与 Config.Environment 比较，如 ["local", "develop"]），为空表示所有环境
+	Environments []string `json:"environments" yaml:"environments" toml:"environments"`
+	// 是否采集请求载荷（默认 true）
+	Request *bool `json:"request" yaml:"request" toml:"request"`
+	// 是否采集响应载荷（默认 true）
+	Response *bool `json:"response" yaml:"response" toml:"response"`
+	// 单个载荷最大字节数，默认 4096，超出部分截断
+	MaxSize int `json:"maxSize" yaml:"maxSize" toml:"maxSize"`
+	// 按方法覆盖最大字节数（key 为 gRPC 方法全名，支持 * 通配符如 /user.UserService/*；值为 0 表示不采集该方法）
+	MethodMaxSize map[string]int `json:"methodMaxSize" yaml:"methodMaxSize" toml:"methodMaxSize"`
+	// 额外的脱敏字段（大小写不敏感，支持 * 通配符，如 *card_no*），与默认规则合并
+	RedactFields []string `json:"redactFields" yaml:"redactFields" toml:"redactFields"`
+	// 脱敏替换值，默认 ***
+	Mask string `json:"mask" yaml:"mask" toml:"mask"`
+}
+
+// AttributeFilterConfig span 属性过滤配置（导出前生效）
+type AttributeFilterConfig struct {
+	// 丢弃的属性名（支持 * 通配符，如 http.request.header.*）
+	Drop []string `json:"drop" yaml:"drop" toml:"drop"`
+	// 值需要脱敏的属性名（支持 * 通配符）
+	Redact []string `json:"redact" yaml:"redact" toml:"redact"`
+	// 脱敏替换值，默认 ***
+	Mask string `json:"mask" yaml:"mask" toml:"mask"`
+}
+
+// PayloadCapturer 载荷采集器：序列化、字段脱敏并按方法限制大小
+type PayloadCapturer struct {
+	request       bool
+	response      bool
+	maxSize       int
+	methodMaxSize map[string]int
+	redact        []string
+	mask          string
+}
+
+// globalPayload 全局载荷采集器（未启用时为 nil）
+var globalPayload atomic.Pointer[PayloadCapturer]
+
+// NewPayloadCapturer 创建载荷采集器，未启用或当前环境不在 Environments 中时返回 nil
+func NewPayloadCapturer(config *PayloadConfig, environment string) (*PayloadCapturer, error) {
+	if config == nil || !config.Enabled {
+		return nil, nil
+	}
+	if len(config.Environments) > 0 && !containsFold(config.Environments, environment) {
+		return nil, nil
+	}
+
+	if config.MaxSize < 0 {
+		return nil, fmt.Errorf("invalid payload.maxSize %d", config.MaxSize)
+	}
+	maxSize := config.MaxSize
+	if maxSize == 0 {
+		maxSize = defaultPayloadMaxSize
+	}
+
+	redact := make([]string, 0, len(defaultRedactFields)+len(config.RedactFields))
+	for _, field := range append(append([]string(nil), defaultRedactFields...), config.RedactFields...) {
+		field = strings.ToLower(field)
+		if _, err := path.Match(field, ""); err != nil {
+			return nil, fmt.Errorf("invalid payload redact field %q: %w", field, err)
+		}
+		redact = append(redact, field)
+	}
+
+	methodMaxSize := make(map[string]int, len(config.MethodMaxSize))
+	for method, size := range config.MethodMaxSize {
+		if _, err := path.Match(method, ""); err != nil {
+			return nil, fmt.Errorf("invalid payload method pattern %q: %w", method, err)
+		}
+		methodMaxSize[method] = size
+	}
+
+	mask := config.Mask
+	if mask == "" {
+		mask = defaultRedactMask
+	}
+	return &PayloadCapturer{
+		request:       config.Request == nil || *config.Request,
+		response:      config.Response == nil || *config.Response,
+		maxSize:       maxSize,
+		methodMaxSize: methodMaxSize,
+		redact:        redact,
+		mask:          mask,
+	}, nil
+}
+
+// SetPayloadCapturer 设置全局载荷采集器（nil 表示关闭采集）
+func SetPayloadCapturer(capturer *PayloadCapturer) {
+	globalPayload.Store(capturer)
+}
+
+// CaptureRequestPayload 使用全局采集器采集请求载荷，未启用时返回 false
+func CaptureRequestPayload(method string, msg interface{}) (string, bool) {
+	capturer := globalPayload.Load()
+	if capturer == nil || !capturer.request {
+		return "", false
+	}
+	return capturer.Capture(method, msg)
+}
+
+// CaptureResponsePayload 使用全局采集器采集响应载荷，未启用时返回 false
+func CaptureResponsePayload(method string, msg interface{}) (string, bool) {
+	capturer := globalPayload.Load()
+	if capturer == nil || !capturer.response {
+		return "", false
+	}
+	return capturer.Capture(method, msg)
+}
+
+// defaultPayloadCapturer 运行时载荷调试日志使用的默认采集器（默认脱敏规则与大小限制）
+var defaultPayloadCapturer = sync.OnceValue(func() *PayloadCapturer {
+	capturer, _ := NewPayloadCapturer(&PayloadConfig{Enabled: true}, "")
+	return capturer
+})
+
+// FormatPayload 序列化、脱敏并截断载荷，用于运行时临时开启的载荷日志
+// 优先使用全局采集器的规则，未配置或该方法未采集时使用默认规则
+func FormatPayload(method string, msg interface{}) (string, bool) {
+	if capturer := globalPayload.Load(); capturer != nil {
+		if payload, ok := capturer.Capture(method, msg); ok {
+			return payload, true
+		}
+	}
+	return defaultPayloadCapturer().Capture(method, msg)
+}
+
+// Capture 序列化并脱敏 msg，超出方法大小限制时截断；方法限制为 0 或 msg 为 nil 时返回 false
+func (p *PayloadCapturer) Capture(method string, msg interface{}) (string, bool) {
+	limit := p.limit(method)
+	if limit <= 0 || msg == nil {
+		return "", false
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if m, ok := msg.(proto.Message); ok {
+		data, err = protojson.MarshalOptions{UseProtoNames: true}.Marshal(m)
+	} else {
+		data, err = json.Marshal(msg)
+	}
+	if err != nil {
+		return fmt.Sprintf("<unserializable: %v>", err), true
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err == nil {
+		if redacted, err := json.Marshal(p.redactValue(value)); err == nil {
+			data = redacted
+		}
+	}
+	if len(data) > limit {
+		return fmt.Sprintf("%s...(truncated, %d bytes)", data[:limit], len(data)), true
+	}
+	return string(data), true
+}
+
+// limit 返回方法的载荷大小限制，精确匹配优先于通配符匹配
+func (p *PayloadCapturer) limit(method string) int {
+	if size, ok := p.methodMaxSize[method]; ok {
+		return size
+	}
+	for pattern, size := range p.methodMaxSize {
+		if matched, _ := path.Match(pattern, method); matched {
+			return size
+		}
+	}
+	return p.maxSize
+}
+
+func (p *PayloadCapturer) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if matchAny(p.redact, strings.ToLower(key)) {
+				v[key] = p.mask
+				continue
+			}
+			v[key] = p.redactValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = p.redactValue(item)
+		}
+	}
+	return value
+}
+
+// attributeFilterExporter 导出前按规则丢弃或脱敏 span 属性
+type attributeFilterExporter struct {
+	tracesdk.SpanExporter
+	drop   []string
+	redact []string
+	mask   string
+}
+
+// newAttributeFilterExporter 包装 exporter，未配置规则时原样返回
+func newAttributeFilterExporter(exporter tracesdk.SpanExporter, config *AttributeFilterConfig) (tracesdk.SpanExporter, error) {
+	if config == nil || (len(config.Drop) == 0 && len(config.Redact) == 0) {
+		return exporter, nil
+	}
+	for _, pattern := range append(append([]string(nil), config.Drop...), config.Redact...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid attribute filter pattern %q: %w", pattern, err)
+		}
+	}
+	mask := config.Mask
+	if mask == "" {
+		mask = defaultRedactMask
+	}
+	return &attributeFilterExporter{SpanExporter: exporter, drop: config.Drop, redact: config.Redact, mask: mask}, nil
+}
+
+func (e *attributeFilterExporter) ExportSpans(ctx context.Context, spans []tracesdk.ReadOnlySpan) error {
+	filtered := make([]tracesdk.ReadOnlySpan, len(spans))
+	for i, span := range spans {
+		filtered[i] = &filteredSpan{ReadOnlySpan: span, attrs: e.filter(span.Attributes())}
+	}
+	return e.SpanExporter.ExportSpans(ctx, filtered)
+}
+
+func (e *attributeFilterExporter) filter(attrs []attribute.KeyValue) []attribute.KeyValue {
+	out := make([]attribute.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		key := string(kv.Key)
+		if matchAny(e.drop, key) {
+			continue
+		}
+		if matchAny(e.redact, key) {
+			kv = kv.Key.String(e.mask)
+		}
+		out = append(out, kv)
+	}
+	return out
+}
+
+// filteredSpan 替换属性后的只读 span
+type filteredSpan struct {
+	tracesdk.ReadOnlySpan
+	attrs []attribute.KeyValue
+}
+
+func (s *filteredSpan) Attributes() []attribute.KeyValue {
+	return s.attrs
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
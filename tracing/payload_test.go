@@ -0,0 +1,133 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestPayloadCapturerRedactsAndTruncates(t *testing.T) {
+	capturer, err := NewPayloadCapturer(&PayloadConfig{
+		Enabled:       true,
+		MaxSize:       256,
+		MethodMaxSize: map[string]int{"/auth.AuthService/*": 0, "/user.UserService/Big": 16},
+		RedactFields:  []string{"*card_no*"},
+	}, "develop")
+	if err != nil || capturer == nil {
+		t.Fatalf("NewPayloadCapturer failed: %v", err)
+	}
+
+	req := map[string]interface{}{
+		"name":        "alice",
+		"Password":    "p@ss",
+		"accessToken": "abc",
+		"cards":       []interface{}{map[string]interface{}{"card_no": "6222"}},
+	}
+	payload, ok := capturer.Capture("/user.UserService/Create", req)
+	if !ok {
+		t.Fatal("expected payload to be captured")
+	}
+	for _, secret := range []string{"p@ss", "abc", "6222"} {
+		if strings.Contains(payload, secret) {
+			t.Fatalf("expected %q to be redacted, got %s", secret, payload)
+		}
+	}
+	if !strings.Contains(payload, `"name":"alice"`) {
+		t.Fatalf("expected non-sensitive fields to be kept, got %s", payload)
+	}
+
+	if _, ok := capturer.Capture("/auth.AuthService/Login", req); ok {
+		t.Fatal("expected method limit 0 to disable capture")
+	}
+	payload, _ = capturer.Capture("/user.UserService/Big", &grpc_health_v1.HealthCheckRequest{Service: strings.Repeat("x", 64)})
+	if !strings.HasPrefix(payload, `{"service":"xxxx`) || !strings.Contains(payload, "truncated") {
+		t.Fatalf("expected truncated proto payload, got %s", payload)
+	}
+}
+
+func TestPayloadCapturerEnvironmentToggle(t *testing.T) {
+	config := &PayloadConfig{Enabled: true, Environments: []string{"local", "develop"}}
+	if capturer, err := NewPayloadCapturer(config, "production"); err != nil || capturer != nil {
+		t.Fatalf("expected capture to be disabled in production, got %v, %v", capturer, err)
+	}
+	if capturer, err := NewPayloadCapturer(config, "Develop"); err != nil || capturer == nil {
+		t.Fatalf("expected capture to be enabled in develop, got %v, %v", capturer, err)
+	}
+	if _, err := NewPayloadCapturer(&PayloadConfig{Enabled: true, RedactFields: []string{"["}}, ""); err == nil {
+		t.Fatal("expected invalid redact pattern to be rejected")
+	}
+}
+
+func TestUnaryServerInterceptorRecordsPayload(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	capturer, _ := NewPayloadCapturer(&PayloadConfig{Enabled: true}, "")
+	SetPayloadCapturer(capturer)
+	defer SetPayloadCapturer(nil)
+
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+	_, err := interceptor(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "user"}, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+	})
+	if err != nil {
+		t.Fatalf("interceptor failed: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected one span, got %d", len(spans))
+	}
+	attrs := make(map[attribute.Key]string)
+	for _, kv := range spans[0].Attributes() {
+		attrs[kv.Key] = kv.Value.Emit()
+	}
+	if attrs[RequestPayloadKey] != `{"service":"user"}` || attrs[ResponsePayloadKey] != `{"status":"SERVING"}` {
+		t.Fatalf("unexpected payload attributes: %v", attrs)
+	}
+}
+
+func TestAttributeFilterExporter(t *testing.T) {
+	memory := tracetest.NewInMemoryExporter()
+	exporter, err := newAttributeFilterExporter(memory, &AttributeFilterConfig{
+		Drop:   []string{"http.request.header.*"},
+		Redact: []string{"user.email"},
+	})
+	if err != nil {
+		t.Fatalf("newAttributeFilterExporter failed: %v", err)
+	}
+	provider := tracesdk.NewTracerProvider(tracesdk.WithSyncer(exporter))
+	_, span := provider.Tracer("test").Start(context.Background(), "filtered")
+	span.SetAttributes(
+		attribute.String("http.request.header.cookie", "session"),
+		attribute.String("user.email", "a@b.c"),
+		attribute.String("user.id", "42"),
+	)
+	span.End()
+
+	spans := memory.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected one exported span, got %d", len(spans))
+	}
+	attrs := make(map[attribute.Key]string)
+	for _, kv := range spans[0].Attributes {
+		attrs[kv.Key] = kv.Value.Emit()
+	}
+	if _, ok := attrs["http.request.header.cookie"]; ok {
+		t.Fatalf("expected header attribute to be dropped: %v", attrs)
+	}
+	if attrs["user.email"] != "***" || attrs["user.id"] != "42" {
+		t.Fatalf("unexpected filtered attributes: %v", attrs)
+	}
+}
@@ -0,0 +1,390 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+const (
+	defaultMaxQueueSize       = 2048
+	defaultMaxExportBatchSize = 512
+	defaultBatchTimeout       = 5 * time.Second
+	defaultExportTimeout      = 30 * time.Second
+	defaultShutdownTimeout    = 5 * time.Second
+)
+
+// ExporterStats span 导出统计（进程内累计值）
+type ExporterStats struct {
+	// 当前等待导出的 span 数
+	Queued int `json:"queued"`
+	// 累计入队 span 数
+	Enqueued uint64 `json:"enqueued"`
+	// 累计成功导出 span 数
+	Exported uint64 `json:"exported"`
+	// 累计丢弃 span 数（队列满或关闭超时未导出）
+	Dropped uint64 `json:"dropped"`
+	// 累计导出失败 span 数
+	Failed uint64 `json:"failed"`
+}
+
+type exporterCounters struct {
+	enqueued atomic.Uint64
+	exported atomic.Uint64
+	dropped  atomic.Uint64
+	failed   atomic.Uint64
+}
+
+// globalCounters 全局 TracerProvider 的导出统计，重新 Init 时不清零以保证计数器单调递增
+var globalCounters exporterCounters
+
+// batchOptions 批量导出参数
+type batchOptions struct {
+	maxQueueSize       int
+	maxExportBatchSize int
+	batchTimeout       time.Duration
+	exportTimeout      time.Duration
+}
+
+func parseBatchOptions(config BatcherConfig) (batchOptions, error) {
+	opts := batchOptions{
+		maxQueueSize:       config.MaxQueueSize,
+		maxExportBatchSize: config.MaxExportBatchSize,
+		batchTimeout:       defaultBatchTimeout,
+		exportTimeout:      defaultExportTimeout,
+	}
+	if opts.maxQueueSize <= 0 {
+		opts.maxQueueSize = defaultMaxQueueSize
+	}
+	if opts.maxExportBatchSize <= 0 {
+		opts.maxExportBatchSize = defaultMaxExportBatchSize
+	}
+	if opts.maxExportBatchSize > opts.maxQueueSize {
+		opts.maxExportBatchSize = opts.maxQueueSize
+	}
+	if config.BatchTimeout != "" {
+		d, err := time.ParseDuration(config.BatchTimeout)
+		if err != nil {
+			return opts, fmt.Errorf("invalid batchTimeout %q: %w", config.BatchTimeout, err)
+		}
+		if d > 0 {
+			opts.batchTimeout = d
+		}
+	}
+	if config.ExportTimeout != "" {
+		d, err := time.ParseDuration(config.ExportTimeout)
+		if err != nil {
+			return opts, fmt.Errorf("invalid exportTimeout %q: %w", config.ExportTimeout, err)
+		}
+		if d > 0 {
+			opts.exportTimeout = d
+		}
+	}
+	return opts, nil
+}
+
+type flushRequest struct {
+	ctx  context.Context
+	done chan error
+}
+
+// batchProcessor 非阻塞批量 span 处理器
+// 与 SDK 的 BatchSpanProcessor 类似，但队列满时立即丢弃并计数，关闭时严格遵守截止时间并输出汇总日志
+type batchProcessor struct {
+	exporter tracesdk.SpanExporter
+	opts     batchOptions
+	// 本处理器的统计与（可选的）全局统计
+	local  exporterCounters
+	global *exporterCounters
+
+	// 后台导出使用的 context，关闭超时后取消以中断进行中的导出
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	queue   chan tracesdk.ReadOnlySpan
+	flushCh chan flushRequest
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+	stopped atomic.Bool
+
+	// 上次输出丢弃告警时的丢弃计数
+	reportedDropped atomic.Uint64
+	// 关闭时未能导出的 span 数
+	abandoned atomic.Int64
+	// 已入队但尚未导出的 span 数（含工作协程中正在组批的 span）
+	pending atomic.Int64
+}
+
+func newBatchProcessor(exporter tracesdk.SpanExporter, opts batchOptions, global *exporterCounters) *batchProcessor {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &batchProcessor{
+		ctx:      ctx,
+		cancel:   cancel,
+		exporter: exporter,
+		opts:     opts,
+		global:   global,
+		queue:    make(chan tracesdk.ReadOnlySpan, opts.maxQueueSize),
+		flushCh:  make(chan flushRequest),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// OnStart 实现 SpanProcessor
+func (p *batchProcessor) OnStart(parent context.Context, s tracesdk.ReadWriteSpan) {}
+
+// OnEnd 实现 SpanProcessor，队列满时丢弃 span，不阻塞调用方
+func (p *batchProcessor) OnEnd(s tracesdk.ReadOnlySpan) {
+	if p.stopped.Load() || !s.SpanContext().IsSampled() {
+		return
+	}
+	select {
+	case p.queue <- s:
+		p.pending.Add(1)
+		p.record(1, 0, 0, 0)
+	default:
+		p.record(0, 0, 1, 0)
+	}
+}
+
+// ForceFlush 实现 SpanProcessor，导出当前队列中的所有 span
+func (p *batchProcessor) ForceFlush(ctx context.Context) error {
+	req := flushRequest{ctx: ctx, done: make(chan error, 1)}
+	select {
+	case p.flushCh <- req:
+	case <-p.doneCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-req.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown 实现 SpanProcessor
+// 在 ctx 截止前尽量导出剩余 span，超时后放弃并计入丢弃数，最后输出导出汇总
+func (p *batchProcessor) Shutdown(ctx context.Context) error {
+	if !p.stopped.CompareAndSwap(false, true) {
+		return nil
+	}
+	defer p.cancel()
+	flushErr := p.ForceFlush(ctx)
+	close(p.stopCh)
+	select {
+	case <-p.doneCh:
+	case <-ctx.Done():
+		p.cancel()
+	}
+
+	// 工作协程退出时会将未导出的 span 计入丢弃；若其仍在导出中（ctx 已截止），以队列长度估算
+	abandoned := int(p.abandoned.Load())
+	select {
+	case <-p.doneCh:
+	default:
+		abandoned = len(p.queue)
+	}
+	exporterErr := p.exporter.Shutdown(ctx)
+
+	stats := p.stats()
+	if flushErr != nil || abandoned > 0 || stats.Dropped > 0 || stats.Failed > 0 {
+		logger.Warn(ctx, "Tracing exporter shutdown: exported=%d, dropped=%d, failed=%d, abandoned=%d, error=%v",
+			stats.Exported, stats.Dropped, stats.Failed, abandoned, flushErr)
+	} else {
+		logger.Info(ctx, "Tracing exporter shutdown: exported=%d, dropped=%d, failed=%d",
+			stats.Exported, stats.Dropped, stats.Failed)
+	}
+	return errors.Join(flushErr, exporterErr)
+}
+
+func (p *batchProcessor) record(enqueued, exported, dropped, failed uint64) {
+	for _, c := range [2]*exporterCounters{&p.local, p.global} {
+		if c == nil {
+			continue
+		}
+		c.enqueued.Add(enqueued)
+		c.exported.Add(exported)
+		c.dropped.Add(dropped)
+		c.failed.Add(failed)
+	}
+}
+
+// stats 返回本处理器的统计
+func (p *batchProcessor) stats() ExporterStats {
+	return p.local.snapshot(int(p.pending.Load()))
+}
+
+func (c *exporterCounters) snapshot(queued int) ExporterStats {
+	return ExporterStats{
+		Queued:   queued,
+		Enqueued: c.enqueued.Load(),
+		Exported: c.exported.Load(),
+		Dropped:  c.dropped.Load(),
+		Failed:   c.failed.Load(),
+	}
+}
+
+func (p *batchProcessor) run() {
+	defer close(p.doneCh)
+	ticker := time.NewTicker(p.opts.batchTimeout)
+	defer ticker.Stop()
+
+	batch := make([]tracesdk.ReadOnlySpan, 0, p.opts.maxExportBatchSize)
+	for {
+		select {
+		case s := <-p.queue:
+			batch = append(batch, s)
+			if len(batch) >= p.opts.maxExportBatchSize {
+				batch = p.export(p.ctx, batch)
+			}
+		case <-ticker.C:
+			batch = p.export(p.ctx, batch)
+			p.reportDropped()
+		case req := <-p.flushCh:
+			var err error
+			batch, err = p.drain(req.ctx, batch)
+			req.done <- err
+		case <-p.stopCh:
+			// 剩余未导出的 span 计入丢弃
+			abandoned := len(batch)
+			for len(p.queue) > 0 {
+				<-p.queue
+				abandoned++
+			}
+			p.abandoned.Store(int64(abandoned))
+			p.pending.Add(-int64(abandoned))
+			p.record(0, 0, uint64(abandoned), 0)
+			return
+		}
+	}
+}
+
+// drain 导出队列中的所有 span，ctx 截止时停止
+func (p *batchProcessor) drain(ctx context.Context, batch []tracesdk.ReadOnlySpan) ([]tracesdk.ReadOnlySpan, error) {
+	var firstErr error
+	for {
+		if err := ctx.Err(); err != nil {
+			return batch, err
+		}
+	fill:
+		for len(batch) < p.opts.maxExportBatchSize {
+			select {
+			case s := <-p.queue:
+				batch = append(batch, s)
+			default:
+				break fill
+			}
+		}
+		if len(batch) == 0 {
+			return batch, firstErr
+		}
+		var err error
+		if batch, err = p.exportErr(ctx, batch); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+}
+
+func (p *batchProcessor) export(ctx context.Context, batch []tracesdk.ReadOnlySpan) []tracesdk.ReadOnlySpan {
+	batch, _ = p.exportErr(ctx, batch)
+	return batch
+}
+
+// exportErr 导出一批 span，返回清空后的 batch（复用底层数组）
+func (p *batchProcessor) exportErr(ctx context.Context, batch []tracesdk.ReadOnlySpan) ([]tracesdk.ReadOnlySpan, error) {
+	if len(batch) == 0 {
+		return batch, nil
+	}
+	exportCtx, cancel := context.WithTimeout(ctx, p.opts.exportTimeout)
+	err := p.exporter.ExportSpans(exportCtx, batch)
+	cancel()
+	p.pending.Add(-int64(len(batch)))
+	if err != nil {
+		p.record(0, 0, 0, uint64(len(batch)))
+		logger.Warn(ctx, "Failed to export spans: count=%d, error=%v", len(batch), err)
+	} else {
+		p.record(0, uint64(len(batch)), 0, 0)
+	}
+	clear(batch)
+	return batch[:0], err
+}
+
+// reportDropped 队列满丢弃 span 时输出告警（每个导出周期最多一次）
+func (p *batchProcessor) reportDropped() {
+	dropped := p.local.dropped.Load()
+	last := p.reportedDropped.Swap(dropped)
+	if dropped > last {
+		logger.Warn(context.Background(), "Tracing queue full, spans dropped: dropped=%d, total_dropped=%d, max_queue_size=%d",
+			dropped-last, dropped, p.opts.maxQueueSize)
+	}
+}
+
+// Stats 获取全局 span 导出统计（进程内累计）；未启用导出时 Queued 为 0
+func Stats() ExporterStats {
+	mu.RLock()
+	current := processor
+	mu.RUnlock()
+	queued := 0
+	if current != nil {
+		queued = int(current.pending.Load())
+	}
+	return globalCounters.snapshot(queued)
+}
+
+var registerMetricsMu sync.Mutex
+
+// RegisterMetrics 将 span 导出指标注册到 Prometheus（重复注册时忽略）
+// 指标：{namespace}_tracing_spans_queued、_enqueued_total、_exported_total、_dropped_total、_export_failed_total
+func RegisterMetrics(registerer prometheus.Registerer, namespace string) error {
+	if registerer == nil {
+		return errors.New("registerer is nil")
+	}
+	registerMetricsMu.Lock()
+	defer registerMetricsMu.Unlock()
+
+	collectors := []prometheus.Collector{
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace, Subsystem: "tracing", Name: "spans_queued",
+			Help: "Number of spans waiting in the export queue",
+		}, func() float64 { return float64(Stats().Queued) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "tracing", Name: "spans_enqueued_total",
+			Help: "Total number of spans enqueued for export",
+		}, func() float64 { return float64(globalCounters.enqueued.Load()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "tracing", Name: "spans_exported_total",
+			Help: "Total number of spans exported successfully",
+		}, func() float64 { return float64(globalCounters.exported.Load()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "tracing", Name: "spans_dropped_total",
+			Help: "Total number of spans dropped because the queue was full or shutdown timed out",
+		}, func() float64 { return float64(globalCounters.dropped.Load()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: "tracing", Name: "spans_export_failed_total",
+			Help: "Total number of spans that failed to export",
+		}, func() float64 { return float64(globalCounters.failed.Load()) }),
+	}
+	for _, collector := range collectors {
+		if err := registerer.Register(collector); err != nil {
+			var already prometheus.AlreadyRegisteredError
+			if errors.As(err, &already) {
+				continue
+			}
+			return fmt.Errorf("failed to register tracing metrics: %w", err)
+		}
+	}
+	return nil
+}
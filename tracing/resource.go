@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+// k8sEnvAttributes Kubernetes downward API 环境变量与资源属性的对应关系，
+// 每个属性按顺序读取第一个非空的环境变量，示例 Deployment 配置：
+//
+//	env:
+//	  - name: K8S_POD_NAME
+//	    valueFrom: {fieldRef: {fieldPath: metadata.name}}
+//	  - name: K8S_POD_NAMESPACE
+//	    valueFrom: {fieldRef: {fieldPath: metadata.namespace}}
+//	  - name: K8S_NODE_NAME
+//	    valueFrom: {fieldRef: {fieldPath: spec.nodeName}}
+var k8sEnvAttributes = []struct {
+	key  attribute.Key
+	envs []string
+}{
+	{semconv.K8SPodNameKey, []string{"K8S_POD_NAME", "POD_NAME"}},
+	{semconv.K8SNamespaceNameKey, []string{"K8S_POD_NAMESPACE", "K8S_NAMESPACE", "POD_NAMESPACE"}},
+	{semconv.K8SNodeNameKey, []string{"K8S_NODE_NAME", "NODE_NAME"}},
+	{semconv.K8SPodUIDKey, []string{"K8S_POD_UID", "POD_UID"}},
+}
+
+// k8sDetector 从 downward API 注入的环境变量中读取 Pod、Namespace、Node 属性
+type k8sDetector struct{}
+
+// Detect 实现 resource.Detector，未运行在 Kubernetes 中时返回空资源
+func (k8sDetector) Detect(ctx context.Context) (*resource.Resource, error) {
+	var attrs []attribute.KeyValue
+	for _, item := range k8sEnvAttributes {
+		for _, env := range item.envs {
+			if value := os.Getenv(env); value != "" {
+				attrs = append(attrs, item.key.String(value))
+				break
+			}
+		}
+	}
+	if len(attrs) == 0 {
+		return resource.Empty(), nil
+	}
+	return resource.NewSchemaless(attrs...), nil
+}
+
+// newResource 创建服务资源
+// 未禁用资源探测时依次合并主机、操作系统、容器、Kubernetes 与 OTEL_RESOURCE_ATTRIBUTES 属性，
+// 最后写入服务名称、版本与环境（同名属性以后者为准）
+func newResource(config *Config, serviceName, serviceVersion, environment string) (*resource.Resource, error) {
+	var opts []resource.Option
+	if !config.DisableResourceDetection {
+		opts = append(opts,
+			resource.WithHost(),
+			resource.WithOS(),
+			resource.WithContainer(),
+			resource.WithDetectors(k8sDetector{}),
+			resource.WithFromEnv(),
+		)
+	}
+	opts = append(opts, resource.WithAttributes(
+		semconv.ServiceNameKey.String(serviceName),
+		semconv.ServiceVersionKey.String(serviceVersion),
+		semconv.DeploymentEnvironmentKey.String(environment),
+	))
+
+	res, err := resource.New(context.Background(), opts...)
+	if err != nil && errors.Is(err, resource.ErrPartialResource) && res != nil {
+		// 部分探测器失败（如无法读取 cgroup）时保留已探测到的属性
+		logger.Warn(context.Background(), "Tracing resource detection incomplete: %v", err)
+		return res, nil
+	}
+	return res, err
+}
@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+)
+
+func resourceValue(res *resource.Resource, key attribute.Key) (string, bool) {
+	value, ok := res.Set().Value(key)
+	return value.AsString(), ok
+}
+
+func TestNewResourceDetectsHostAndKubernetesAttributes(t *testing.T) {
+	t.Setenv("K8S_POD_NAME", "api-7d9f")
+	t.Setenv("POD_NAMESPACE", "payments")
+	t.Setenv("K8S_NODE_NAME", "node-1")
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "cloud.region=eu-west-1,service.name=from-env")
+
+	res, err := newResource(&Config{}, "orders", "2.0.0", "prod")
+	if err != nil {
+		t.Fatalf("newResource failed: %v", err)
+	}
+
+	want := map[attribute.Key]string{
+		semconv.K8SPodNameKey:       "api-7d9f",
+		semconv.K8SNamespaceNameKey: "payments",
+		semconv.K8SNodeNameKey:      "node-1",
+		"cloud.region":              "eu-west-1",
+		semconv.ServiceNameKey:      "orders",
+		semconv.ServiceVersionKey:   "2.0.0",
+	}
+	for key, expected := range want {
+		if got, ok := resourceValue(res, key); !ok || got != expected {
+			t.Fatalf("expected %s=%q, got %q (present=%v)", key, expected, got, ok)
+		}
+	}
+	if host, ok := resourceValue(res, semconv.HostNameKey); !ok || host == "" {
+		t.Fatal("expected host.name to be detected")
+	}
+}
+
+func TestNewResourceSkipsDetectionWhenDisabled(t *testing.T) {
+	t.Setenv("K8S_POD_NAME", "api-7d9f")
+
+	res, err := newResource(&Config{DisableResourceDetection: true}, "orders", "2.0.0", "prod")
+	if err != nil {
+		t.Fatalf("newResource failed: %v", err)
+	}
+	if _, ok := resourceValue(res, semconv.K8SPodNameKey); ok {
+		t.Fatal("expected kubernetes attributes to be skipped")
+	}
+	if _, ok := resourceValue(res, semconv.HostNameKey); ok {
+		t.Fatal("expected host attributes to be skipped")
+	}
+	if got, _ := resourceValue(res, semconv.ServiceNameKey); got != "orders" {
+		t.Fatalf("expected service name to be kept, got %q", got)
+	}
+}
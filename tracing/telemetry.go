@@ -0,0 +1,260 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	otellog "go.opentelemetry.io/otel/log"
+	logglobal "go.opentelemetry.io/otel/log/global"
+	lognoop "go.opentelemetry.io/otel/log/noop"
+	"go.opentelemetry.io/otel/metric"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+const (
+	defaultMetricsInterval    = 60 * time.Second
+	defaultLogsExportInterval = time.Second
+)
+
+var (
+	// mp 全局 MeterProvider（未启用指标导出时为 nil）
+	mp *sdkmetric.MeterProvider
+	// lp 全局 LoggerProvider（未启用日志导出时为 nil）
+	lp *sdklog.LoggerProvider
+	// instrumentationName Meter / Logger 默认的 instrumentation 名称（服务名）
+	instrumentationName = "quickgo-service"
+)
+
+// telemetryProviders Init 过程中创建的指标、日志 Provider
+type telemetryProviders struct {
+	meter  *sdkmetric.MeterProvider
+	logger *sdklog.LoggerProvider
+}
+
+// shutdown 关闭已创建的 Provider（Init 失败回滚或替换旧 Provider 时使用）
+func (p telemetryProviders) shutdown(ctx context.Context) error {
+	var errs []error
+	if p.meter != nil {
+		if err := p.meter.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown meter provider: %w", err))
+		}
+	}
+	if p.logger != nil {
+		if err := p.logger.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown logger provider: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// newTelemetryProviders 根据配置创建指标、日志 Provider，出错时关闭已创建的 Provider
+func newTelemetryProviders(config *Config, res *resource.Resource) (telemetryProviders, error) {
+	var providers telemetryProviders
+	if config.Metrics != nil && config.Metrics.Enabled {
+		provider, err := newMeterProvider(config.Metrics, config.OTLP, res)
+		if err != nil {
+			return providers, err
+		}
+		providers.meter = provider
+	}
+	if config.Logs != nil && config.Logs.Enabled {
+		provider, err := newLoggerProvider(config.Logs, config.OTLP, res)
+		if err != nil {
+			_ = providers.shutdown(context.Background())
+			return telemetryProviders{}, err
+		}
+		providers.logger = provider
+	}
+	return providers, nil
+}
+
+// resolveOTLP 信号级 OTLP 配置未设置 endpoint 时复用链路追踪的 OTLP 配置
+func resolveOTLP(signal, fallback OTLPConfig) (OTLPConfig, error) {
+	if signal.Endpoint == "" {
+		signal = fallback
+	}
+	if signal.Endpoint == "" {
+		return signal, errors.New("otlp endpoint is required")
+	}
+	return signal, nil
+}
+
+// newMeterProvider 创建 OTLP 指标导出的 MeterProvider
+func newMeterProvider(config *MetricsConfig, fallback OTLPConfig, res *resource.Resource) (*sdkmetric.MeterProvider, error) {
+	interval := defaultMetricsInterval
+	if config.Interval != "" {
+		d, err := time.ParseDuration(config.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metrics.interval %q: %w", config.Interval, err)
+		}
+		if d > 0 {
+			interval = d
+		}
+	}
+
+	otlp, err := resolveOTLP(config.OTLP, fallback)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP metric exporter: %w", err)
+	}
+	endpoint := parseOTLPEndpoint(otlp.Endpoint)
+
+	var exporter sdkmetric.Exporter
+	if otlp.UseGRPC {
+		opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(endpoint)}
+		if otlp.Insecure {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+		if len(otlp.Headers) > 0 {
+			opts = append(opts, otlpmetricgrpc.WithHeaders(otlp.Headers))
+		}
+		exporter, err = otlpmetricgrpc.New(context.Background(), opts...)
+	} else {
+		opts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(endpoint)}
+		if otlp.Insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		if len(otlp.Headers) > 0 {
+			opts = append(opts, otlpmetrichttp.WithHeaders(otlp.Headers))
+		}
+		exporter, err = otlpmetrichttp.New(context.Background(), opts...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP metric exporter (endpoint=%s, parsed=%s): %w", otlp.Endpoint, endpoint, err)
+	}
+
+	return sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(interval))),
+	), nil
+}
+
+// newLoggerProvider 创建 OTLP 日志导出的 LoggerProvider
+func newLoggerProvider(config *LogsConfig, fallback OTLPConfig, res *resource.Resource) (*sdklog.LoggerProvider, error) {
+	interval := defaultLogsExportInterval
+	if config.ExportInterval != "" {
+		d, err := time.ParseDuration(config.ExportInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid logs.exportInterval %q: %w", config.ExportInterval, err)
+		}
+		if d > 0 {
+			interval = d
+		}
+	}
+
+	otlp, err := resolveOTLP(config.OTLP, fallback)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP log exporter: %w", err)
+	}
+	endpoint := parseOTLPEndpoint(otlp.Endpoint)
+
+	var exporter sdklog.Exporter
+	if otlp.UseGRPC {
+		opts := []otlploggrpc.Option{otlploggrpc.WithEndpoint(endpoint)}
+		if otlp.Insecure {
+			opts = append(opts, otlploggrpc.WithInsecure())
+		}
+		if len(otlp.Headers) > 0 {
+			opts = append(opts, otlploggrpc.WithHeaders(otlp.Headers))
+		}
+		exporter, err = otlploggrpc.New(context.Background(), opts...)
+	} else {
+		opts := []otlploghttp.Option{otlploghttp.WithEndpoint(endpoint)}
+		if otlp.Insecure {
+			opts = append(opts, otlploghttp.WithInsecure())
+		}
+		if len(otlp.Headers) > 0 {
+			opts = append(opts, otlploghttp.WithHeaders(otlp.Headers))
+		}
+		exporter, err = otlploghttp.New(context.Background(), opts...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP log exporter (endpoint=%s, parsed=%s): %w", otlp.Endpoint, endpoint, err)
+	}
+
+	batchOpts := []sdklog.BatchProcessorOption{sdklog.WithExportInterval(interval)}
+	if config.MaxQueueSize > 0 {
+		batchOpts = append(batchOpts, sdklog.WithMaxQueueSize(config.MaxQueueSize))
+	}
+	return sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter, batchOpts...)),
+	), nil
+}
+
+// installTelemetryProviders 设置全局指标、日志 Provider 并按需桥接 logger，返回被替换的旧 Provider
+// 未启用的信号保持 OTel 全局默认（noop），调用方需持有 mu
+func installTelemetryProviders(providers telemetryProviders, serviceName string) telemetryProviders {
+	old := telemetryProviders{meter: mp, logger: lp}
+	mp = providers.meter
+	lp = providers.logger
+	instrumentationName = serviceName
+	if providers.meter != nil {
+		otel.SetMeterProvider(providers.meter)
+	}
+	if providers.logger != nil {
+		logglobal.SetLoggerProvider(providers.logger)
+	}
+	installLogBridge(providers.logger != nil)
+	return old
+}
+
+// MeterProvider 获取 MeterProvider，未启用指标导出时返回 OTel 全局 MeterProvider
+func MeterProvider() metric.MeterProvider {
+	mu.RLock()
+	current := mp
+	mu.RUnlock()
+	if current == nil {
+		return otel.GetMeterProvider()
+	}
+	return current
+}
+
+// Meter 获取以服务名命名的 Meter，未启用指标导出时记录的指标不会上报
+func Meter(opts ...metric.MeterOption) metric.Meter {
+	mu.RLock()
+	name := instrumentationName
+	mu.RUnlock()
+	return MeterProvider().Meter(name, opts...)
+}
+
+// LoggerProvider 获取 OTel LoggerProvider，未启用日志导出时返回 Noop LoggerProvider
+func LoggerProvider() otellog.LoggerProvider {
+	mu.RLock()
+	current := lp
+	mu.RUnlock()
+	if current == nil {
+		return lognoop.NewLoggerProvider()
+	}
+	return current
+}
+
+// OTelLogger 获取以服务名命名的 OTel Logger（用于日志桥接），未启用日志导出时返回 Noop Logger
+func OTelLogger(opts ...otellog.LoggerOption) otellog.Logger {
+	mu.RLock()
+	name := instrumentationName
+	mu.RUnlock()
+	return LoggerProvider().Logger(name, opts...)
+}
+
+// MetricsEnabled 检查 OTel 指标导出是否已启用
+func MetricsEnabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return mp != nil
+}
+
+// LogsEnabled 检查 OTel 日志导出是否已启用
+func LogsEnabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return lp != nil
+}
@@ -0,0 +1,159 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+
+	"github.com/team-dandelion/quickgo/logger"
+)
+
+func TestInitExportsMetricsAndLogs(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths = make(map[string]int)
+	)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths[r.URL.Path]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	err := Init(&Config{
+		Enabled:     true,
+		ServiceName: "telemetry-test",
+		OTLP:        OTLPConfig{Endpoint: collector.URL, Insecure: true},
+		Metrics:     &MetricsConfig{Enabled: true, Interval: "1h"},
+		Logs:        &LogsConfig{Enabled: true, ExportInterval: "1h"},
+	})
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if !MetricsEnabled() || !LogsEnabled() {
+		t.Fatal("expected metrics and logs to be enabled")
+	}
+
+	counter, err := Meter().Int64Counter("requests_total")
+	if err != nil {
+		t.Fatalf("create counter failed: %v", err)
+	}
+	counter.Add(context.Background(), 3)
+
+	var record otellog.Record
+	record.SetBody(otellog.StringValue("hello"))
+	record.SetSeverity(otellog.SeverityInfo)
+	OTelLogger().Emit(context.Background(), record)
+
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if MetricsEnabled() || LogsEnabled() {
+		t.Fatal("expected Shutdown to disable metrics and logs")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if paths["/v1/metrics"] == 0 || paths["/v1/logs"] == 0 {
+		t.Fatalf("expected metrics and logs to be flushed on shutdown, got %v", paths)
+	}
+}
+
+func TestInitTelemetryRequiresEndpoint(t *testing.T) {
+	if err := Init(&Config{Enabled: true, Metrics: &MetricsConfig{Enabled: true}}); err == nil {
+		t.Fatal("expected metrics without OTLP endpoint to be rejected")
+	}
+	if err := Init(&Config{Enabled: true, Logs: &LogsConfig{Enabled: true, OTLP: OTLPConfig{Endpoint: "localhost:4318"}, ExportInterval: "soon"}}); err == nil {
+		t.Fatal("expected invalid export interval to be rejected")
+	}
+	if IsEnabled() || MetricsEnabled() || LogsEnabled() {
+		t.Fatal("expected failed Init not to install providers")
+	}
+
+	// 未启用时返回 Noop 实现，调用不报错
+	counter, err := Meter().Int64Counter("noop_total")
+	if err != nil {
+		t.Fatalf("noop meter failed: %v", err)
+	}
+	counter.Add(context.Background(), 1)
+	OTelLogger().Emit(context.Background(), otellog.Record{})
+}
+
+// memoryLogExporter 内存日志导出器，记录导出的日志
+type memoryLogExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *memoryLogExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *memoryLogExporter) Shutdown(ctx context.Context) error   { return nil }
+func (e *memoryLogExporter) ForceFlush(ctx context.Context) error { return nil }
+
+func TestLoggerEntriesBridgedToOTelLogs(t *testing.T) {
+	exporter := &memoryLogExporter{}
+	mu.Lock()
+	installTelemetryProviders(telemetryProviders{
+		logger: sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter))),
+	}, "bridge-test")
+	mu.Unlock()
+	defer Shutdown(context.Background())
+
+	log, err := logger.NewLogger(logger.Config{Level: logger.LevelInfo, Output: t.TempDir() + "/bridge.log"})
+	if err != nil {
+		t.Fatalf("NewLogger failed: %v", err)
+	}
+	defer log.Close()
+
+	traceID, spanID := "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"
+	ctx := logger.WithSpanID(logger.WithTraceID(context.Background(), traceID), spanID)
+	log.Debug(ctx, "filtered")
+	log.WithField("order_id", "o-1").Warn(ctx, "order delayed: %d", 3)
+
+	exporter.mu.Lock()
+	records := append([]sdklog.Record(nil), exporter.records...)
+	exporter.mu.Unlock()
+	if len(records) != 1 {
+		t.Fatalf("expected one bridged record, got %d", len(records))
+	}
+	record := records[0]
+	if record.Body().AsString() != "order delayed: 3" || record.Severity() != otellog.SeverityWarn {
+		t.Fatalf("unexpected record: body=%q severity=%v", record.Body().AsString(), record.Severity())
+	}
+	if record.TraceID().String() != traceID || record.SpanID().String() != spanID {
+		t.Fatalf("expected record to carry trace context, got trace=%s span=%s", record.TraceID(), record.SpanID())
+	}
+	var orderID string
+	record.WalkAttributes(func(kv otellog.KeyValue) bool {
+		if kv.Key == "order_id" {
+			orderID = kv.Value.AsString()
+		}
+		return true
+	})
+	if orderID != "o-1" {
+		t.Fatalf("expected order_id attribute, got %q", orderID)
+	}
+
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	log.Info(ctx, "after shutdown")
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	if len(exporter.records) != 1 {
+		t.Fatalf("expected no records after Shutdown, got %d", len(exporter.records))
+	}
+}
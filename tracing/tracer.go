@@ -2,10 +2,12 @@ package tracing
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -13,9 +15,7 @@ import (
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/propagation"
-	"go.opentelemetry.io/otel/sdk/resource"
 	tracesdk "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -25,7 +25,11 @@ var (
 	globalTracer trace.Tracer
 	// tp 全局 TracerProvider
 	tp *tracesdk.TracerProvider
-	mu sync.RWMutex
+	// processor 全局批量导出处理器（未配置 exporter 时为 nil）
+	processor *batchProcessor
+	// shutdownTimeout 关闭时等待导出的最长时间
+	shutdownTimeout = defaultShutdownTimeout
+	mu              sync.RWMutex
 )
 
 // Init 初始化链路追踪
@@ -52,15 +56,23 @@ func Init(config *Config) error {
 		environment = "development"
 	}
 
+	batchOpts, err := parseBatchOptions(config.Batcher)
+	if err != nil {
+		return err
+	}
+	newShutdownTimeout := defaultShutdownTimeout
+	if config.ShutdownTimeout != "" {
+		d, err := time.ParseDuration(config.ShutdownTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid shutdownTimeout %q: %w", config.ShutdownTimeout, err)
+		}
+		if d > 0 {
+			newShutdownTimeout = d
+		}
+	}
+
 	// 创建资源
-	res, err := resource.New(
-		context.Background(),
-		resource.WithAttributes(
-			semconv.ServiceNameKey.String(serviceName),
-			semconv.ServiceVersionKey.String(serviceVersion),
-			semconv.DeploymentEnvironmentKey.String(environment),
-		),
-	)
+	res, err := newResource(config, serviceName, serviceVersion, environment)
 	if err != nil {
 		return fmt.Errorf("failed to create resource: %w", err)
 	}
@@ -141,6 +153,30 @@ func Init(config *Config) error {
 		exporter = nil
 	}
 
+	// 创建载荷采集器与 span 属性过滤（可选）
+	payloadCapturer, err := NewPayloadCapturer(config.Payload, environment)
+	if err == nil && exporter != nil {
+		var filtered tracesdk.SpanExporter
+		if filtered, err = newAttributeFilterExporter(exporter, config.AttributeFilter); err == nil {
+			exporter = filtered
+		}
+	}
+	if err != nil {
+		if exporter != nil {
+			_ = exporter.Shutdown(context.Background())
+		}
+		return err
+	}
+
+	// 创建 OTel 指标、日志 Provider（可选）
+	providers, err := newTelemetryProviders(config, res)
+	if err != nil {
+		if exporter != nil {
+			_ = exporter.Shutdown(context.Background())
+		}
+		return err
+	}
+
 	// 设置采样率
 	samplingRate := config.SamplingRate
 	if samplingRate < 0 {
@@ -151,7 +187,10 @@ func Init(config *Config) error {
 	}
 
 	// 创建 TracerProvider
-	var newProvider *tracesdk.TracerProvider
+	var (
+		newProvider  *tracesdk.TracerProvider
+		newProcessor *batchProcessor
+	)
 	if exporter == nil {
 		// 如果没有 exporter，使用 Noop TracerProvider（仅本地追踪，不上传）
 		newProvider = tracesdk.NewTracerProvider(
@@ -159,9 +198,10 @@ func Init(config *Config) error {
 			tracesdk.WithSampler(tracesdk.TraceIDRatioBased(samplingRate)),
 		)
 	} else {
-		// 创建 TracerProvider（带 exporter，会上传到 Jaeger）
+		// 创建 TracerProvider（带 exporter，队列满时丢弃 span，不阻塞业务请求）
+		newProcessor = newBatchProcessor(exporter, batchOpts, &globalCounters)
 		newProvider = tracesdk.NewTracerProvider(
-			tracesdk.WithBatcher(exporter),
+			tracesdk.WithSpanProcessor(newProcessor),
 			tracesdk.WithResource(res),
 			tracesdk.WithSampler(tracesdk.TraceIDRatioBased(samplingRate)),
 		)
@@ -179,27 +219,84 @@ func Init(config *Config) error {
 	// 创建全局 Tracer
 	mu.Lock()
 	oldProvider := tp
+	oldTimeout := shutdownTimeout
 	tp = newProvider
+	processor = newProcessor
+	shutdownTimeout = newShutdownTimeout
 	globalTracer = otel.Tracer(serviceName)
+	oldProviders := installTelemetryProviders(providers, serviceName)
+	SetPayloadCapturer(payloadCapturer)
 	mu.Unlock()
+	installTraceResolver(true)
 	if oldProvider != nil && oldProvider != newProvider {
-		_ = oldProvider.Shutdown(context.Background())
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), oldTimeout)
+		_ = oldProvider.Shutdown(shutdownCtx)
+		cancel()
+	}
+	if oldProviders.meter != nil || oldProviders.logger != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), oldTimeout)
+		_ = oldProviders.shutdown(shutdownCtx)
+		cancel()
 	}
 
 	return nil
 }
 
-// Shutdown 关闭链路追踪
+// Shutdown 关闭链路追踪及 OTel 指标、日志导出
+// 最多等待 Config.ShutdownTimeout（与 ctx 截止时间取较早者）导出剩余数据，超时未导出的 span 计入丢弃并输出汇总日志
 func Shutdown(ctx context.Context) error {
 	mu.Lock()
 	current := tp
+	timeout := shutdownTimeout
+	providers := telemetryProviders{meter: mp, logger: lp}
 	tp = nil
+	processor = nil
 	globalTracer = nil
+	mp = nil
+	lp = nil
+	installLogBridge(false)
+	SetPayloadCapturer(nil)
 	mu.Unlock()
+	installTraceResolver(false)
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var errs []error
 	if current != nil {
-		return current.Shutdown(ctx)
+		if err := current.Shutdown(shutdownCtx); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	return nil
+	if err := providers.shutdown(shutdownCtx); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// ForceFlush 立即导出队列中的 span、指标与日志
+func ForceFlush(ctx context.Context) error {
+	mu.RLock()
+	current := tp
+	meterProvider, loggerProvider := mp, lp
+	mu.RUnlock()
+
+	var errs []error
+	if current != nil {
+		if err := current.ForceFlush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if meterProvider != nil {
+		if err := meterProvider.ForceFlush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if loggerProvider != nil {
+		if err := loggerProvider.ForceFlush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // GetTracer 获取 Tracer 实例
@@ -2,7 +2,13 @@ package tracing
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
 )
 
 func TestSamplingRateZeroMeansDropAll(t *testing.T) {
@@ -32,3 +38,146 @@ func TestDefaultConfigSamplesAll(t *testing.T) {
 		t.Fatal("expected DefaultConfig to create recording spans")
 	}
 }
+
+// blockingExporter 在 release 关闭前阻塞导出（遵守 ctx 截止）
+type blockingExporter struct {
+	release  chan struct{}
+	mu       sync.Mutex
+	exported int
+}
+
+func (e *blockingExporter) ExportSpans(ctx context.Context, spans []tracesdk.ReadOnlySpan) error {
+	select {
+	case <-e.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	e.mu.Lock()
+	e.exported += len(spans)
+	e.mu.Unlock()
+	return nil
+}
+
+func (e *blockingExporter) Shutdown(ctx context.Context) error { return nil }
+
+func TestBatchProcessorDropsWhenQueueFull(t *testing.T) {
+	exporter := &blockingExporter{release: make(chan struct{})}
+	proc := newBatchProcessor(exporter, batchOptions{
+		maxQueueSize:       4,
+		maxExportBatchSize: 1,
+		batchTimeout:       time.Hour,
+		exportTimeout:      time.Minute,
+	}, nil)
+	provider := tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(proc))
+
+	start := time.Now()
+	for i := 0; i < 50; i++ {
+		_, span := provider.Tracer("test").Start(context.Background(), "span")
+		span.End()
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected span.End not to block on a stalled exporter, took %s", elapsed)
+	}
+	stats := proc.stats()
+	if stats.Dropped == 0 || stats.Enqueued+stats.Dropped != 50 {
+		t.Fatalf("expected spans to be dropped when queue is full, got %+v", stats)
+	}
+
+	close(exporter.release)
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	stats = proc.stats()
+	if stats.Exported != stats.Enqueued || stats.Exported != uint64(exporter.exported) {
+		t.Fatalf("expected all enqueued spans to be exported on shutdown, got %+v (exporter saw %d)", stats, exporter.exported)
+	}
+}
+
+func TestBatchProcessorShutdownHonorsDeadline(t *testing.T) {
+	exporter := &blockingExporter{release: make(chan struct{})}
+	proc := newBatchProcessor(exporter, batchOptions{
+		maxQueueSize:       16,
+		maxExportBatchSize: 4,
+		batchTimeout:       time.Hour,
+		exportTimeout:      time.Minute,
+	}, nil)
+	provider := tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(proc))
+	for i := 0; i < 10; i++ {
+		_, span := provider.Tracer("test").Start(context.Background(), "span")
+		span.End()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := provider.Shutdown(ctx)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected shutdown to return near its deadline, took %s", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	<-proc.doneCh
+	if stats := proc.stats(); stats.Exported != 0 || stats.Dropped+stats.Failed != 10 {
+		t.Fatalf("expected unexported spans to be accounted as dropped or failed, got %+v", stats)
+	}
+}
+
+func TestInitWithUnreachableExporterShutsDownWithinTimeout(t *testing.T) {
+	if err := Init(&Config{
+		Enabled:         true,
+		SamplingRate:    1,
+		ShutdownTimeout: "200ms",
+		OTLP:            OTLPConfig{Enabled: true, Endpoint: "http://127.0.0.1:1", Insecure: true},
+		Batcher:         BatcherConfig{MaxQueueSize: 8, BatchTimeout: "1h"},
+	}); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	before := Stats()
+	for i := 0; i < 3; i++ {
+		_, span := StartSpan(context.Background(), "unreachable")
+		span.End()
+	}
+	if stats := Stats(); stats.Queued != 3 || stats.Enqueued-before.Enqueued != 3 {
+		t.Fatalf("expected 3 queued spans, got %+v", stats)
+	}
+
+	registry := prometheus.NewRegistry()
+	if err := RegisterMetrics(registry, "quickgo"); err != nil {
+		t.Fatalf("RegisterMetrics failed: %v", err)
+	}
+	if err := RegisterMetrics(registry, "quickgo"); err != nil {
+		t.Fatalf("expected duplicate registration to be ignored, got %v", err)
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	var queued float64 = -1
+	for _, family := range families {
+		if family.GetName() == "quickgo_tracing_spans_queued" {
+			queued = family.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+	if queued != 3 {
+		t.Fatalf("expected queued gauge 3, got %v", queued)
+	}
+
+	start := time.Now()
+	_ = Shutdown(context.Background())
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected shutdown to honor shutdownTimeout, took %s", elapsed)
+	}
+	if IsEnabled() {
+		t.Fatal("expected tracing to be disabled after shutdown")
+	}
+}
+
+func TestInitRejectsInvalidBatcherConfig(t *testing.T) {
+	if err := Init(&Config{Enabled: true, Batcher: BatcherConfig{BatchTimeout: "soon"}}); err == nil {
+		t.Fatal("expected invalid batchTimeout to be rejected")
+	}
+	if err := Init(&Config{Enabled: true, ShutdownTimeout: "later"}); err == nil {
+		t.Fatal("expected invalid shutdownTimeout to be rejected")
+	}
+}
@@ -0,0 +1,87 @@
+// Package types 提供配置结构体共用的值类型
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/team-dandelion/quickgo/json"
+)
+
+// Duration 配置中的时长，使用时长字符串（如 "5s"、"1m30s"）书写，
+// 支持 JSON / YAML / TOML 与 mapstructure（TextUnmarshaler）解析；
+// 为了兼容 time.Duration 的 JSON 编码，JSON 数字按纳秒解析
+type Duration time.Duration
+
+// ParseDuration 解析时长字符串，空字符串为 0
+func ParseDuration(s string) (Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	return Duration(d), nil
+}
+
+// Std 返回 time.Duration
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+// OrDefault 未设置（<= 0）时返回 fallback
+func (d Duration) OrDefault(fallback time.Duration) time.Duration {
+	if d <= 0 {
+		return fallback
+	}
+	return time.Duration(d)
+}
+
+// String 返回时长字符串
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalText 实现 encoding.TextMarshaler
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := ParseDuration(string(bytes.TrimSpace(text)))
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", text, err)
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON 编码为时长字符串
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON 接受时长字符串或纳秒数
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return d.UnmarshalText([]byte(s))
+	}
+	n, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid duration %s: must be a string such as \"5s\" or nanoseconds", data)
+	}
+	*d = Duration(n)
+	return nil
+}
@@ -0,0 +1,62 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/team-dandelion/quickgo/json"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDurationUnmarshal(t *testing.T) {
+	var v struct {
+		Timeout Duration `json:"timeout" yaml:"timeout"`
+	}
+	for _, tc := range []struct {
+		name  string
+		input string
+		json  bool
+		want  time.Duration
+	}{
+		{"json string", `{"timeout":"1m30s"}`, true, 90 * time.Second},
+		{"json nanoseconds", `{"timeout":1000000}`, true, time.Millisecond},
+		{"json empty", `{"timeout":""}`, true, 0},
+		{"yaml string", "timeout: 250ms", false, 250 * time.Millisecond},
+	} {
+		v.Timeout = -1
+		var err error
+		if tc.json {
+			err = json.Unmarshal([]byte(tc.input), &v)
+		} else {
+			err = yaml.Unmarshal([]byte(tc.input), &v)
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if v.Timeout.Std() != tc.want {
+			t.Fatalf("%s: expected %s, got %s", tc.name, tc.want, v.Timeout)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`{"timeout":"soon"}`), &v); err == nil {
+		t.Fatal("expected invalid json duration to be rejected")
+	}
+	if err := yaml.Unmarshal([]byte("timeout: 5"), &v); err == nil {
+		t.Fatal("expected yaml duration without unit to be rejected")
+	}
+}
+
+func TestDurationMarshalAndDefault(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Timeout Duration `json:"timeout"`
+	}{Duration(5 * time.Second)})
+	if err != nil || string(data) != `{"timeout":"5s"}` {
+		t.Fatalf("unexpected marshal result %s: %v", data, err)
+	}
+	if got := Duration(0).OrDefault(time.Second); got != time.Second {
+		t.Fatalf("expected default, got %s", got)
+	}
+	if got := Duration(2 * time.Second).OrDefault(time.Second); got != 2*time.Second {
+		t.Fatalf("expected configured value, got %s", got)
+	}
+}
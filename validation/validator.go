@@ -1,11 +1,13 @@
 package validation
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"net/url"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -43,33 +45,74 @@ func (e ValidationErrors) Error() string {
 // Validator 配置验证器
 type Validator struct {
 	tagName string
+	// 字段路径使用的标签（如 json），为空或字段没有该标签时使用字段名
+	nameTag string
+}
+
+// Option 验证器选项
+type Option func(*Validator)
+
+// WithNameTag 使用指定标签（如 json、yaml）的名称作为错误中的字段路径，与配置文件中的键一致
+func WithNameTag(tag string) Option {
+	return func(v *Validator) {
+		v.nameTag = tag
+	}
+}
+
+// Validatable 可选接口：结构体实现 Validate 进行跨字段校验（如依赖关系、互斥字段），
+// 验证器在字段校验之后调用；返回 ValidationErrors 时其中的 Field 为相对于该结构体的路径，
+// Message 只需描述问题（验证器会加上完整路径）
+type Validatable interface {
+	Validate() error
 }
 
 // NewValidator 创建验证器
-func NewValidator() *Validator {
-	return &Validator{
+func NewValidator(opts ...Option) *Validator {
+	v := &Validator{
 		tagName: "validate",
 	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
 }
 
-// Validate 验证结构体
+// Validate 验证结构体，返回所有字段的错误（ValidationErrors）
+// 支持嵌套结构体、结构体切片与 map，以及实现了 Validatable 的结构体
 func (v *Validator) Validate(cfg interface{}) error {
-	return v.validateValue(reflect.ValueOf(cfg), "")
+	errs := v.validateValue(reflect.ValueOf(cfg), "")
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
 }
 
-func (v *Validator) validateValue(val reflect.Value, prefix string) error {
+func (v *Validator) validateValue(val reflect.Value, prefix string) ValidationErrors {
 	var errs ValidationErrors
 
-	// 处理指针
-	if val.Kind() == reflect.Ptr {
+	// 处理指针与接口
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
 		if val.IsNil() {
 			return nil
 		}
 		val = val.Elem()
 	}
 
-	// 只处理结构体
-	if val.Kind() != reflect.Struct {
+	switch val.Kind() {
+	case reflect.Struct:
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			errs = append(errs, v.validateValue(val.Index(i), fmt.Sprintf("%s[%d]", prefix, i))...)
+		}
+		return errs
+	case reflect.Map:
+		keys := val.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface()) })
+		for _, key := range keys {
+			errs = append(errs, v.validateValue(val.MapIndex(key), joinPath(prefix, fmt.Sprint(key.Interface())))...)
+		}
+		return errs
+	default:
 		return nil
 	}
 
@@ -82,24 +125,18 @@ func (v *Validator) validateValue(val reflect.Value, prefix string) error {
 		if !field.IsExported() {
 			continue
 		}
-
-		fieldName := field.Name
-		if prefix != "" {
-			fieldName = prefix + "." + fieldName
+		name, skip := v.fieldName(field)
+		if skip {
+			continue
 		}
+		fieldName := joinPath(prefix, name)
 
 		// 获取验证标签
 		tag := field.Tag.Get(v.tagName)
 		if tag == "" {
-			// 递归验证嵌套结构体
-			if fieldVal.Kind() == reflect.Struct || (fieldVal.Kind() == reflect.Ptr && fieldVal.Type().Elem().Kind() == reflect.Struct) {
-				if err := v.validateValue(fieldVal, fieldName); err != nil {
-					if verrs, ok := err.(ValidationErrors); ok {
-						errs = append(errs, verrs...)
-					} else {
-						return err
-					}
-				}
+			// 递归验证嵌套结构体、切片与 map
+			if containsStruct(fieldVal.Type()) {
+				errs = append(errs, v.validateValue(fieldVal, fieldName)...)
 			}
 			continue
 		}
@@ -112,16 +149,122 @@ func (v *Validator) validateValue(val reflect.Value, prefix string) error {
 				continue
 			}
 
+			if param, ok := strings.CutPrefix(rule, "excluded_with="); ok {
+				if err := v.validateExcludedWith(val, fieldName, fieldVal, param); err != nil {
+					errs = append(errs, err)
+				}
+				continue
+			}
 			if err := v.validateRule(fieldName, fieldVal, rule); err != nil {
 				errs = append(errs, err)
 			}
 		}
 	}
 
-	if len(errs) > 0 {
+	errs = append(errs, v.validateSelf(val, prefix)...)
+	return errs
+}
+
+// fieldName 返回字段在错误路径中的名称，名称标签为 "-" 时跳过该字段
+func (v *Validator) fieldName(field reflect.StructField) (string, bool) {
+	if v.nameTag == "" {
+		return field.Name, false
+	}
+	name, _, _ := strings.Cut(field.Tag.Get(v.nameTag), ",")
+	switch name {
+	case "-":
+		return "", true
+	case "":
+		return field.Name, false
+	}
+	return name, false
+}
+
+// validateExcludedWith 互斥字段校验：当前字段与 param 指定的同级字段（Go 字段名）不能同时设置
+func (v *Validator) validateExcludedWith(parent reflect.Value, fieldName string, fieldVal reflect.Value, param string) *ValidationError {
+	other := parent.FieldByName(param)
+	if !other.IsValid() || !isSet(fieldVal) || !isSet(other) {
+		return nil
+	}
+	otherName := param
+	if field, ok := parent.Type().FieldByName(param); ok {
+		if name, skip := v.fieldName(field); !skip {
+			otherName = name
+		}
+	}
+	return &ValidationError{
+		Field:   fieldName,
+		Tag:     "excluded_with",
+		Value:   fieldVal.Interface(),
+		Message: fmt.Sprintf("field '%s' cannot be set together with '%s'", fieldName, otherName),
+	}
+}
+
+// validateSelf 调用结构体的 Validate 方法（值或指针接收者）
+func (v *Validator) validateSelf(val reflect.Value, prefix string) ValidationErrors {
+	var target interface{}
+	if val.CanAddr() {
+		target = val.Addr().Interface()
+	} else {
+		target = val.Interface()
+	}
+	validatable, ok := target.(Validatable)
+	if !ok {
+		return nil
+	}
+	err := validatable.Validate()
+	if err == nil {
+		return nil
+	}
+
+	var nested ValidationErrors
+	if errors.As(err, &nested) {
+		errs := make(ValidationErrors, 0, len(nested))
+		for _, e := range nested {
+			field := joinPath(prefix, e.Field)
+			errs = append(errs, &ValidationError{Field: field, Tag: e.Tag, Value: e.Value, Message: fmt.Sprintf("field '%s': %s", field, e.Message)})
+		}
 		return errs
 	}
-	return nil
+	field := prefix
+	message := err.Error()
+	if field != "" {
+		message = fmt.Sprintf("'%s': %s", field, message)
+	}
+	return ValidationErrors{{Field: field, Tag: "validate", Message: message}}
+}
+
+// containsStruct 判断类型是否为（指向）结构体，或元素为结构体的切片、数组与 map
+func containsStruct(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Struct:
+		return true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		elem := typ.Elem()
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		return elem.Kind() == reflect.Struct
+	}
+	return false
+}
+
+// isSet 判断字段是否已设置（bool 为 true 才视为设置）
+func isSet(val reflect.Value) bool {
+	if val.Kind() == reflect.Bool {
+		return val.Bool()
+	}
+	return hasValue(val)
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
 }
 
 func (v *Validator) validateRule(fieldName string, val reflect.Value, rule string) *ValidationError {
@@ -0,0 +1,75 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+)
+
+type testServer struct {
+	Port        int    `json:"port" validate:"gte=0,lte=65535"`
+	Timeout     string `json:"timeout" validate:"duration"`
+	EnableCORS  bool   `json:"enableCORS" validate:"excluded_with=DisableCORS"`
+	DisableCORS bool   `json:"disableCORS"`
+}
+
+type testRoute struct {
+	Path string `json:"path" validate:"required"`
+}
+
+type testConfig struct {
+	Server  *testServer           `json:"server"`
+	Named   map[string]testServer `json:"named"`
+	Routes  []testRoute           `json:"routes"`
+	Ignored *testServer           `json:"-"`
+	Mode    string                `json:"mode"`
+	Name    string                `json:"name"`
+}
+
+func (c *testConfig) Validate() error {
+	if c.Mode == "cluster" && c.Name == "" {
+		return ValidationErrors{{Field: "name", Tag: "cluster", Message: "required in cluster mode"}}
+	}
+	return nil
+}
+
+func TestValidateCollectsAllErrorsWithKeyPaths(t *testing.T) {
+	cfg := &testConfig{
+		Server:  &testServer{Port: 70000, Timeout: "5 seconds", EnableCORS: true, DisableCORS: true},
+		Named:   map[string]testServer{"admin": {Timeout: "abc"}},
+		Routes:  []testRoute{{Path: "/a"}, {}},
+		Ignored: &testServer{Port: -1},
+		Mode:    "cluster",
+	}
+
+	err := NewValidator(WithNameTag("json")).Validate(cfg)
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	want := []string{"server.port", "server.timeout", "server.enableCORS", "named.admin.timeout", "routes[1].path", "name"}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d errors, got %d: %v", len(want), len(errs), err)
+	}
+	for i, field := range want {
+		if errs[i].Field != field {
+			t.Errorf("error %d: expected field %s, got %s (%s)", i, field, errs[i].Field, errs[i].Message)
+		}
+	}
+	if errs[2].Message != "field 'server.enableCORS' cannot be set together with 'disableCORS'" {
+		t.Errorf("unexpected excluded_with message: %s", errs[2].Message)
+	}
+	if errs[5].Message != "field 'name': required in cluster mode" {
+		t.Errorf("unexpected Validate message: %s", errs[5].Message)
+	}
+}
+
+func TestValidateUsesFieldNamesByDefault(t *testing.T) {
+	err := ValidateConfig(&testConfig{Server: &testServer{Timeout: "x"}})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "Server.Timeout" {
+		t.Fatalf("expected Server.Timeout error, got %v", err)
+	}
+	if err := ValidateConfig(&testConfig{Server: &testServer{Port: 8080, Timeout: "1s", EnableCORS: true}}); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+}